@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guyghost/constantine/internal/audit"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/statefile"
+)
+
+func main() {
+	path := flag.String("path", os.Getenv("AUDIT_LOG_PATH"), "Audit log file (defaults to AUDIT_LOG_PATH)")
+	symbol := flag.String("symbol", "", "Only decisions on this symbol")
+	strategyName := flag.String("strategy", "", "Only decisions on signals of this strategy")
+	outcome := flag.String("outcome", "", "Only decisions with this outcome: executed, sized_down, rejected or skipped")
+	since := flag.Duration("since", 0, "Only decisions made within this duration, e.g. 2h")
+	summary := flag.Bool("summary", false, "Print counts by outcome and rejection instead of the decisions")
+	flag.Parse()
+
+	if *path == "" {
+		log.Fatal("No audit log: set -path or AUDIT_LOG_PATH")
+	}
+
+	// Logs written with STATE_PASSPHRASE set are encrypted
+	if _, err := statefile.SetPassphraseFromEnv(); err != nil {
+		log.Fatalf("Failed to load the state passphrase: %v", err)
+	}
+	decisions, err := audit.Load(*path)
+	if err != nil {
+		log.Fatalf("Failed to load audit log: %v", err)
+	}
+
+	query := audit.Query{
+		Symbol:   *symbol,
+		Strategy: *strategyName,
+		Outcome:  execution.DecisionOutcome(*outcome),
+	}
+	if *since > 0 {
+		query.Since = time.Now().Add(-*since)
+	}
+	decisions = audit.Filter(decisions, query)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	if *summary {
+		s := audit.Summarize(decisions)
+		fmt.Fprintf(w, "Decisions\t%d\n", s.Total)
+		for _, count := range s.ByOutcome {
+			fmt.Fprintf(w, "  %s\t%d\n", count.Key, count.Count)
+		}
+		if len(s.Rejections) > 0 {
+			fmt.Fprintln(w, "Rejections")
+			for _, count := range s.Rejections {
+				fmt.Fprintf(w, "  %s\t%d\n", count.Key, count.Count)
+			}
+		}
+		return
+	}
+
+	fmt.Fprintln(w, "TIME\tSYMBOL\tTYPE\tSIDE\tSTRENGTH\tOUTCOME\tSIZE\tREASON")
+	for _, d := range decisions {
+		size := ""
+		if d.Amount.IsPositive() {
+			size = d.Amount.String()
+			if d.Outcome == execution.DecisionSizedDown {
+				size = d.RequestedAmount.String() + " -> " + size
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\t%s\t%s\t%s\n",
+			d.Time.Local().Format(time.DateTime), d.Symbol, d.Type, d.Side, d.Strength, d.Outcome, size, d.Reason)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+
+	"github.com/guyghost/constantine/internal/audit"
+	"github.com/guyghost/constantine/internal/execution"
+)
+
+// auditLog is set when AUDIT_LOG_PATH is configured
+var auditLog *audit.Log
+
+// setupAuditLog records the decision made on every signal the execution
+// agent handles, executed or not, when AUDIT_LOG_PATH is set
+func setupAuditLog(executionAgent *execution.ExecutionAgent) error {
+	path := os.Getenv("AUDIT_LOG_PATH")
+	if path == "" {
+		return nil
+	}
+
+	l, err := audit.NewFile(path)
+	if err != nil {
+		return err
+	}
+	auditLog = l
+
+	executionAgent.SetDecisionCallback(func(decision execution.Decision) {
+		if err := l.Record(decision); err != nil {
+			botLogger().Warn("failed to record signal decision", "symbol", decision.Symbol, "error", err)
+		}
+	})
+	botLogger().Info("signal audit log enabled", "path", path)
+	return nil
+}
+
+// closeAuditLog closes the audit log if enabled
+func closeAuditLog() {
+	if auditLog == nil {
+		return
+	}
+	if err := auditLog.Close(); err != nil {
+		botLogger().Error("failed to close audit log", "error", err)
+	}
+}
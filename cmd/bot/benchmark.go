@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/benchmark"
+	"github.com/guyghost/constantine/internal/equity"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	// sessionStart is when the equity history was opened; the session's
+	// equity is compared with buy and hold from then
+	sessionStart time.Time
+	// benchmarkSymbols are the BENCHMARK_SYMBOLS compared on their own
+	// besides the traded symbols, e.g. BTC-USD
+	benchmarkSymbols []string
+
+	benchmarksMu      sync.RWMutex
+	sessionBenchmarks []benchmark.Comparison
+)
+
+// parseSymbols splits a comma separated list of symbols
+func parseSymbols(value string) []string {
+	var symbols []string
+	for _, symbol := range strings.Split(value, ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+// symbolPrices returns the last prices of the traded and benchmark symbols,
+// leaving out the symbols no exchange quotes
+func symbolPrices(ctx context.Context, multiplexer *exchanges.ExchangeMultiplexer) map[string]decimal.Decimal {
+	symbols := append([]string(nil), benchmarkSymbols...)
+	for symbol := range multiplexer.GetSymbolMap() {
+		symbols = append(symbols, symbol)
+	}
+
+	prices := make(map[string]decimal.Decimal)
+	for _, symbol := range symbols {
+		if _, done := prices[symbol]; done {
+			continue
+		}
+		// Benchmark symbols may not be traded on any exchange
+		var candidates []exchanges.Exchange
+		if exchange, err := multiplexer.GetExchangeForSymbol(symbol); err == nil {
+			candidates = append(candidates, exchange)
+		} else {
+			for _, exchange := range multiplexer.GetExchanges() {
+				candidates = append(candidates, exchange)
+			}
+		}
+		for _, exchange := range candidates {
+			ticker, err := exchange.GetTicker(ctx, symbol)
+			if err == nil && ticker != nil && ticker.Last.IsPositive() {
+				prices[symbol] = ticker.Last
+				break
+			}
+		}
+	}
+	return prices
+}
+
+// updateSessionBenchmarks compares the total equity of the session with buy
+// and hold of the traded and benchmark symbols
+func updateSessionBenchmarks(symbolMap map[string]string) {
+	traded := make([]string, 0, len(symbolMap))
+	for symbol := range symbolMap {
+		traded = append(traded, symbol)
+	}
+	sort.Strings(traded)
+
+	comparisons := compareSession(equityHistory.Snapshots(equity.Total, sessionStart), traded, benchmarkSymbols)
+	benchmarksMu.Lock()
+	sessionBenchmarks = comparisons
+	benchmarksMu.Unlock()
+}
+
+// currentBenchmarks returns the latest comparisons of the session
+func currentBenchmarks() []benchmark.Comparison {
+	benchmarksMu.RLock()
+	defer benchmarksMu.RUnlock()
+	return sessionBenchmarks
+}
+
+// compareSession compares the total equity snapshots, net of the deposits
+// made since the first one, with an equal weight buy and hold of the traded
+// symbols and with each benchmark symbol
+func compareSession(snapshots []equity.Snapshot, traded, benchmarks []string) []benchmark.Comparison {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	points := make([]benchmark.Point, len(snapshots))
+	times := make([]time.Time, len(snapshots))
+	for i, snapshot := range snapshots {
+		deposited := snapshot.NetDeposits.Sub(snapshots[0].NetDeposits)
+		points[i] = benchmark.Point{Time: snapshot.Time, Value: snapshot.Equity.Sub(deposited).InexactFloat64()}
+		times[i] = snapshot.Time
+	}
+
+	var comparisons []benchmark.Comparison
+	compare := func(name string, symbols []string) {
+		series := make([][]benchmark.Point, 0, len(symbols))
+		for _, symbol := range symbols {
+			series = append(series, priceSeries(snapshots, symbol))
+		}
+		held := benchmark.BuyAndHold(series, times)
+		if held == nil {
+			return
+		}
+		if comparison, err := benchmark.Compare(name, points, held); err == nil {
+			comparisons = append(comparisons, comparison)
+		}
+	}
+	if len(traded) > 0 {
+		compare("Buy & hold traded symbols", traded)
+	}
+	for _, symbol := range benchmarks {
+		compare("Buy & hold "+symbol, []string{symbol})
+	}
+	return comparisons
+}
+
+// priceSeries returns the prices of symbol recorded in snapshots
+func priceSeries(snapshots []equity.Snapshot, symbol string) []benchmark.Point {
+	var series []benchmark.Point
+	for _, snapshot := range snapshots {
+		if price, ok := snapshot.Prices[symbol]; ok && price.IsPositive() {
+			series = append(series, benchmark.Point{Time: snapshot.Time, Value: price.InexactFloat64()})
+		}
+	}
+	return series
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/guyghost/constantine/internal/blocklist"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// marketBlocklist holds the markets the execution agent never enters
+var marketBlocklist = blocklist.New()
+
+// setupBlocklist refuses entries on the blocked markets, persisted to
+// BLOCKLIST_PATH when set. The comma-separated SYMBOL or EXCHANGE:SYMBOL
+// markets of BLOCKLIST are blocked at startup.
+func setupBlocklist(multiplexer *exchanges.ExchangeMultiplexer, executionAgent *execution.ExecutionAgent) error {
+	if path := os.Getenv("BLOCKLIST_PATH"); path != "" {
+		list, err := blocklist.Load(path)
+		if err != nil {
+			return err
+		}
+		marketBlocklist = list
+	}
+	for _, value := range strings.Split(os.Getenv("BLOCKLIST"), ",") {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		entry, err := blocklist.ParseEntry(value)
+		if err != nil {
+			return err
+		}
+		entry.Reason = "configured"
+		if err := marketBlocklist.Block(entry); err != nil {
+			return err
+		}
+	}
+
+	executionAgent.SetExchangeResolver(func(symbol string) string {
+		return multiplexer.GetSymbolMap()[symbol]
+	})
+	executionAgent.SetBlocklist(marketBlocklist)
+	if entries := marketBlocklist.Entries(); len(entries) > 0 {
+		botLogger().Info("market blocklist loaded", "markets", len(entries))
+	}
+	return nil
+}
+
+// registerBlocklistHandlers serves the blocked markets on GET /blocklist and
+// blocks or unblocks one on POST /blocklist/block?market=&reason= and
+// POST /blocklist/unblock?market=, the market written SYMBOL or
+// EXCHANGE:SYMBOL
+func registerBlocklistHandlers(server *telemetry.Server) {
+	server.HandleFunc("/blocklist", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, marketBlocklist.Entries())
+	})
+	update := func(apply func(entry blocklist.Entry) (bool, error)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			entry, err := blocklist.ParseEntry(r.URL.Query().Get("market"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			entry.Reason = r.URL.Query().Get("reason")
+			found, err := apply(entry)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if !found {
+				http.Error(w, "market not blocked", http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	server.HandleFunc("/blocklist/block", update(func(entry blocklist.Entry) (bool, error) {
+		if err := marketBlocklist.Block(entry); err != nil {
+			return false, err
+		}
+		botLogger().Warn("market blocked", "market", entry.String(), "reason", entry.Reason)
+		return true, nil
+	}))
+	server.HandleFunc("/blocklist/unblock", update(func(entry blocklist.Entry) (bool, error) {
+		removed, err := marketBlocklist.Unblock(entry)
+		if removed {
+			botLogger().Info("market unblocked", "market", entry.String())
+		}
+		return removed, err
+	}))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/replay"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+const (
+	defaultClockSyncInterval = 10 * time.Minute
+	clockSyncTimeout         = 10 * time.Second
+)
+
+// syncExchangeClocks measures the offset of the local clock to the server
+// time of every exchange signing requests with timestamps, and corrects the
+// timestamps of their later requests by it
+func syncExchangeClocks(ctx context.Context, exchangesMap map[string]exchanges.Exchange) {
+	for name, exchange := range exchangesMap {
+		if recording, ok := exchange.(*replay.RecordingExchange); ok {
+			exchange = recording.Exchange
+		}
+		syncer, ok := exchange.(exchanges.ClockSyncer)
+		if !ok {
+			continue
+		}
+
+		syncCtx, cancel := context.WithTimeout(ctx, clockSyncTimeout)
+		offset, err := syncer.SyncClock(syncCtx)
+		cancel()
+		if err != nil {
+			botLogger().Warn("failed to check clock against exchange time", "exchange", name, "error", err)
+			continue
+		}
+
+		telemetry.RecordClockOffset(name, offset)
+		if offset.Abs() > exchanges.MaxClockSkew {
+			telemetry.RecordClockSkewWarning(name)
+			botLogger().Warn("system clock is skewed from exchange time, correcting request timestamps",
+				"exchange", name,
+				"offset", offset.Round(time.Millisecond),
+				"hint", "check that NTP time synchronization is enabled")
+			continue
+		}
+		botLogger().Debug("clock checked against exchange time", "exchange", name, "offset", offset.Round(time.Millisecond))
+	}
+}
+
+// runClockSync re-checks the clock against the exchanges every
+// CLOCK_SYNC_MINUTES until ctx is canceled
+func runClockSync(ctx context.Context, exchangesMap map[string]exchanges.Exchange) {
+	interval := defaultClockSyncInterval
+	if value := os.Getenv("CLOCK_SYNC_MINUTES"); value != "" {
+		if minutes, err := strconv.Atoi(value); err == nil && minutes > 0 {
+			interval = time.Duration(minutes) * time.Minute
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			syncExchangeClocks(ctx, exchangesMap)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// confirmations holds the entries awaiting approval when
+// EXECUTION_CONFIRM_ENTRIES is enabled
+var confirmations *execution.ConfirmationQueue
+
+// setupConfirmations makes the execution agent propose every entry and place
+// it once an operator approves it, from the TUI or the telemetry server's
+// /proposals endpoints, when config.ConfirmEntries is set. Signals keep
+// being handled while entries await approval.
+func setupConfirmations(executionAgent *execution.ExecutionAgent, config execution.Config) {
+	if !config.ConfirmEntries {
+		return
+	}
+
+	confirmations = execution.NewConfirmationQueue(config.ConfirmTimeout)
+	confirmations.SetProposalCallback(func(proposal execution.Proposal) {
+		botLogger().Warn("entry awaiting approval",
+			"id", proposal.ID,
+			"symbol", proposal.Symbol,
+			"side", proposal.Side,
+			"amount", proposal.Amount.String(),
+			"price", proposal.Price.StringFixed(2),
+			"expires_at", proposal.ExpiresAt.Format("15:04:05"))
+	})
+	executionAgent.SetApprover(confirmations)
+	botLogger().Info("entry confirmation enabled", "timeout", config.ConfirmTimeout)
+}
+
+// executeSignal hands signal to the execution agent
+func executeSignal(ctx context.Context, executionAgent *execution.ExecutionAgent, signal *strategy.Signal, args ...any) {
+	if err := executionAgent.HandleSignal(ctx, signal); err != nil {
+		logExecutionError(err, append(args, "symbol", signal.Symbol)...)
+	}
+}
+
+// registerConfirmationHandlers serves the pending proposals on
+// GET /proposals and decides them on POST /proposals/approve?id= and
+// POST /proposals/reject?id=
+func registerConfirmationHandlers(server *telemetry.Server) {
+	if confirmations == nil {
+		return
+	}
+
+	server.HandleFunc("/proposals", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(confirmations.Pending()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	decide := func(decision func(id string) error) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			if err := decision(r.URL.Query().Get("id")); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	server.HandleFunc("/proposals/approve", decide(confirmations.Approve))
+	server.HandleFunc("/proposals/reject", decide(confirmations.Reject))
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"os"
+
+	"github.com/guyghost/constantine/internal/apiauth"
+	"github.com/guyghost/constantine/internal/audit"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// controlAuditLog is set when CONTROL_AUDIT_LOG_PATH is configured
+var controlAuditLog *audit.Log
+
+// setupControlAuth guards the control API served by the telemetry server.
+// CONTROL_API_KEYS grants roles to API keys (name:role:key, comma separated)
+// and CONTROL_API_CLIENTS to client certificates (commonName:role), with
+// read, trade or admin roles. CONTROL_API_TLS_CERT and CONTROL_API_TLS_KEY
+// serve over TLS, and CONTROL_API_CLIENT_CA verifies client certificates.
+// Without keys nor clients the API only serves reads and refuses every control
+// action. Every control action is logged, and appended to
+// CONTROL_AUDIT_LOG_PATH when set, refused ones included.
+func setupControlAuth(server *telemetry.Server) error {
+	if server == nil {
+		return nil
+	}
+
+	certFile := os.Getenv("CONTROL_API_TLS_CERT")
+	keyFile := os.Getenv("CONTROL_API_TLS_KEY")
+	clientCA := os.Getenv("CONTROL_API_CLIENT_CA")
+	if certFile != "" || keyFile != "" {
+		config, err := apiauth.LoadTLSConfig(certFile, keyFile, clientCA)
+		if err != nil {
+			return err
+		}
+		server.SetTLSConfig(config)
+	} else if clientCA != "" {
+		return errors.New("CONTROL_API_CLIENT_CA requires CONTROL_API_TLS_CERT and CONTROL_API_TLS_KEY")
+	}
+
+	auth := apiauth.New()
+	if err := auth.AddKeys(os.Getenv("CONTROL_API_KEYS")); err != nil {
+		return err
+	}
+	if clients := os.Getenv("CONTROL_API_CLIENTS"); clients != "" {
+		if clientCA == "" {
+			return errors.New("CONTROL_API_CLIENTS requires CONTROL_API_CLIENT_CA")
+		}
+		if err := auth.AddClients(clients); err != nil {
+			return err
+		}
+	}
+
+	if path := os.Getenv("CONTROL_AUDIT_LOG_PATH"); path != "" {
+		l, err := audit.NewFile(path)
+		if err != nil {
+			return err
+		}
+		controlAuditLog = l
+	}
+
+	// Probes run without credentials
+	auth.SetPublicPaths("/healthz", "/readyz")
+	// Changes to the safety configuration
+	auth.SetAdminPaths("/blocklist/block", "/blocklist/unblock", "/degradation/reset")
+	auth.SetAuditCallback(recordControlAction)
+	server.SetMiddleware(auth.Middleware)
+	if !auth.HasClients() {
+		botLogger().Warn("control API has no keys, control actions are refused until CONTROL_API_KEYS is set")
+		return nil
+	}
+	botLogger().Info("control API authentication enabled", "tls", certFile != "", "client_certificates", clientCA != "")
+	return nil
+}
+
+// recordControlAction logs a control API action and appends it to the
+// control audit log if enabled
+func recordControlAction(action apiauth.Action) {
+	entry := botLogger().With(
+		"principal", action.Principal,
+		"role", action.Role,
+		"method", action.Method,
+		"path", action.Path,
+		"query", action.Query,
+		"remote_addr", action.RemoteAddr,
+		"status", action.Status,
+	)
+	if action.Allowed {
+		entry.Info("control action")
+	} else {
+		entry.Warn("control action denied")
+	}
+
+	if controlAuditLog == nil {
+		return
+	}
+	if err := controlAuditLog.RecordAction(action); err != nil {
+		botLogger().Warn("failed to record control action", "path", action.Path, "error", err)
+	}
+}
+
+// closeControlAuditLog closes the control audit log if enabled
+func closeControlAuditLog() {
+	if controlAuditLog == nil {
+		return
+	}
+	if err := controlAuditLog.Close(); err != nil {
+		botLogger().Error("failed to close control audit log", "error", err)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/deadman"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// deadmanCheckInterval is how often the deadman switch is checked
+const deadmanCheckInterval = time.Minute
+
+// deadmanSwitch is set when DEADMAN_TIMEOUT_HOURS is configured
+var deadmanSwitch *deadman.Switch
+
+// setupDeadman arms a deadman switch when DEADMAN_TIMEOUT_HOURS is set: without
+// an operator heartbeat (POST /heartbeat, or a key pressed in the TUI) for that
+// long, running algorithms are canceled, positions flattened within
+// flattenTimeout, and entries refused until the next heartbeat
+func setupDeadman(executionAgent *execution.ExecutionAgent, orderManager *order.Manager, flattenTimeout time.Duration) {
+	value := os.Getenv("DEADMAN_TIMEOUT_HOURS")
+	if value == "" {
+		return
+	}
+	hours, err := strconv.ParseFloat(value, 64)
+	if err != nil || hours <= 0 {
+		botLogger().Warn("invalid DEADMAN_TIMEOUT_HOURS, deadman switch disabled", "value", value)
+		return
+	}
+
+	deadmanSwitch = deadman.New(time.Duration(hours * float64(time.Hour)))
+	deadmanSwitch.SetChangeCallback(func(status deadman.Status) {
+		if !status.Tripped {
+			botLogger().Info("deadman switch re-armed", "source", status.Source, "deadline", status.Deadline)
+			return
+		}
+		telemetry.RecordError("deadman_tripped")
+		botLogger().Error("deadman switch tripped: flattening positions and refusing entries",
+			"last_heartbeat", status.LastHeartbeat, "source", status.Source)
+		go func() {
+			executionAgent.CancelAlgos()
+			executeShutdownPolicy(orderManager, order.ShutdownPolicyFlattenAll, flattenTimeout)
+		}()
+	})
+	executionAgent.SetOperatorPresence(deadmanSwitch)
+	botLogger().Info("deadman switch armed", "timeout", deadmanSwitch.Timeout())
+}
+
+// registerDeadmanHandlers serves the state of the deadman switch on
+// GET /deadman and records an operator heartbeat on POST /heartbeat
+func registerDeadmanHandlers(server *telemetry.Server) {
+	server.HandleFunc("/deadman", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, deadmanSwitch.Status())
+	})
+	server.HandleFunc("/heartbeat", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		deadmanSwitch.Heartbeat("api")
+		writeJSON(w, deadmanSwitch.Status())
+	})
+}
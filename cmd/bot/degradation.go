@@ -0,0 +1,129 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/guyghost/constantine/internal/accounting"
+	"github.com/guyghost/constantine/internal/degradation"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/risk"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+var (
+	// degradationMonitor is set when STRATEGY_EXPECTATIONS_FILE is configured
+	degradationMonitor *degradation.Monitor
+	// accountBalance sizes the trades recorded by the monitor when the
+	// balance is not split across strategies
+	accountBalance func() float64
+)
+
+// setupDegradationMonitor compares the live trades of each strategy and
+// symbol with the backtest expectations of STRATEGY_EXPECTATIONS_FILE, and
+// throttles or disables the entries of those that degrade beyond the
+// DEGRADATION_* bands
+func setupDegradationMonitor(executionAgent *execution.ExecutionAgent, riskManager *risk.Manager) error {
+	path := os.Getenv("STRATEGY_EXPECTATIONS_FILE")
+	if path == "" {
+		return nil
+	}
+	expectations, err := degradation.LoadExpectations(path)
+	if err != nil {
+		return err
+	}
+
+	cfg := degradation.DefaultConfig()
+	for key, target := range map[string]*int{
+		"DEGRADATION_WINDOW_TRADES": &cfg.Window,
+		"DEGRADATION_MIN_TRADES":    &cfg.MinTrades,
+	} {
+		if value := os.Getenv(key); value != "" {
+			if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+				*target = parsed
+			}
+		}
+	}
+	for key, target := range map[string]*float64{
+		"DEGRADATION_WIN_RATE_THROTTLE_BAND":   &cfg.WinRateThrottleBand,
+		"DEGRADATION_WIN_RATE_DISABLE_BAND":    &cfg.WinRateDisableBand,
+		"DEGRADATION_EXPECTANCY_THROTTLE_BAND": &cfg.ExpectancyThrottleBand,
+		"DEGRADATION_EXPECTANCY_DISABLE_BAND":  &cfg.ExpectancyDisableBand,
+		"DEGRADATION_DRAWDOWN_THROTTLE_RATIO":  &cfg.DrawdownThrottleRatio,
+		"DEGRADATION_DRAWDOWN_DISABLE_RATIO":   &cfg.DrawdownDisableRatio,
+	} {
+		if value := os.Getenv(key); value != "" {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed >= 0 {
+				*target = parsed
+			}
+		}
+	}
+	if value := os.Getenv("DEGRADATION_THROTTLE_FACTOR"); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed > 0 && parsed <= 1 {
+			cfg.ThrottleFactor = parsed
+		}
+	}
+
+	monitor := degradation.NewMonitor(cfg, expectations)
+	monitor.SetChangeCallback(func(change degradation.Change) {
+		if change.To != degradation.StateHealthy {
+			telemetry.RecordError("strategy_" + string(change.To))
+		}
+	})
+	executionAgent.SetPerformanceGuard(monitor)
+	degradationMonitor = monitor
+	accountBalance = func() float64 { return riskManager.GetCurrentBalance().InexactFloat64() }
+
+	botLogger().Info("strategy degradation monitor enabled",
+		"path", path,
+		"expectations", len(expectations),
+		"window", cfg.Window,
+		"min_trades", cfg.MinTrades,
+		"throttle_factor", cfg.ThrottleFactor)
+	return nil
+}
+
+// recordDegradation passes a closed trade to the degradation monitor, its
+// PnL in percent of the capital of its strategy
+func recordDegradation(trade accounting.Trade) {
+	if degradationMonitor == nil {
+		return
+	}
+	capital := accountBalance()
+	if capitalAllocator != nil {
+		capital = capitalAllocator.Capital(trade.Strategy).InexactFloat64()
+	}
+	if capital <= 0 {
+		return
+	}
+	degradationMonitor.Record(trade.Strategy, trade.Symbol, trade.PnL.InexactFloat64()/capital*100)
+}
+
+// registerDegradationHandlers serves the state of each strategy and symbol
+// and the latest changes on GET /degradation, and re-enables a strategy, or
+// one of its symbols, on POST /degradation/reset?strategy=&symbol=
+func registerDegradationHandlers(server *telemetry.Server) {
+	server.HandleFunc("/degradation", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, struct {
+			Statuses []degradation.Status `json:"statuses"`
+			Changes  []degradation.Change `json:"changes"`
+		}{degradationMonitor.Statuses(), degradationMonitor.Changes()})
+	})
+	server.HandleFunc("/degradation/reset", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		strategyName := r.URL.Query().Get("strategy")
+		if strategyName == "" {
+			http.Error(w, "missing strategy", http.StatusBadRequest)
+			return
+		}
+		if !degradationMonitor.Reset(strategyName, r.URL.Query().Get("symbol")) {
+			http.Error(w, "strategy not tracked", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
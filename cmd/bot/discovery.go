@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/replay"
+	"github.com/guyghost/constantine/internal/risk"
+	"github.com/guyghost/constantine/internal/rotation"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/symbolmanager"
+	"github.com/guyghost/constantine/internal/watchdog"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultDiscoveryMaxSymbols = 10
+	defaultDiscoveryMinVolume  = 1000000 // USD over 24h
+)
+
+// symbolDiscovery is whether the traded symbols follow the exchange listings
+var symbolDiscovery bool
+
+// setupSymbolDiscovery makes the integrated engine rebuild its symbols from
+// the markets listed by every connected exchange when SYMBOL_DISCOVERY is
+// set: the SYMBOL_DISCOVERY_MAX_SYMBOLS (default 10) most traded ones quoted
+// in SYMBOL_DISCOVERY_QUOTE (default USD) with at least
+// SYMBOL_DISCOVERY_MIN_VOLUME (default 1M) traded over 24h. Symbols set in
+// TRADING_SYMBOLS are always kept, and symbols listed on the primary exchange
+// are traded there. It returns whether discovery is enabled.
+func setupSymbolDiscovery(
+	integratedEngine *strategy.IntegratedStrategyEngine,
+	multiplexer *exchanges.ExchangeMultiplexer,
+	primaryExchangeName string,
+	appConfig *config.AppConfig,
+) bool {
+	if !getEnvBool("SYMBOL_DISCOVERY", false) || replayPlayer != nil {
+		return false
+	}
+
+	cfg := strategy.DiscoveryConfig{
+		MaxSymbols:   defaultDiscoveryMaxSymbols,
+		MinVolume24h: decimal.NewFromInt(defaultDiscoveryMinVolume),
+		Quote:        "USD",
+		Preferred:    primaryExchangeName,
+	}
+	if value := os.Getenv("SYMBOL_DISCOVERY_MAX_SYMBOLS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			cfg.MaxSymbols = parsed
+		}
+	}
+	if value := os.Getenv("SYMBOL_DISCOVERY_MIN_VOLUME"); value != "" {
+		if parsed, err := decimal.NewFromString(value); err == nil {
+			cfg.MinVolume24h = parsed
+		}
+	}
+	if value, set := os.LookupEnv("SYMBOL_DISCOVERY_QUOTE"); set {
+		cfg.Quote = strings.ToUpper(value)
+	}
+	if os.Getenv("TRADING_SYMBOLS") != "" {
+		cfg.Pinned = appConfig.TradingSymbols
+	}
+
+	integratedEngine.EnableDiscovery(func() map[string]exchanges.Exchange {
+		sources := multiplexer.GetExchanges()
+		for name, exchange := range sources {
+			if recording, ok := exchange.(*replay.RecordingExchange); ok {
+				sources[name] = recording.Exchange
+			}
+		}
+		return sources
+	}, cfg)
+	botLogger().Info("symbol discovery enabled",
+		"max_symbols", cfg.MaxSymbols,
+		"min_volume", cfg.MinVolume24h.String(),
+		"quote", cfg.Quote,
+		"pinned", cfg.Pinned)
+	symbolDiscovery = true
+	return true
+}
+
+// applyUniverseChanges starts a strategy for every symbol discovery adds,
+// mapped to the exchange it was found on, and hands every symbol it removes
+// to rotator. Strategies run until ctx is done.
+func applyUniverseChanges(
+	ctx context.Context,
+	integratedEngine *strategy.IntegratedStrategyEngine,
+	orchestrator *strategy.StrategyOrchestrator,
+	multiplexer *exchanges.ExchangeMultiplexer,
+	executionAgent *execution.ExecutionAgent,
+	supervisor *watchdog.Watchdog,
+	rotator *rotation.Rotator,
+) {
+	riskConfig := risk.LoadConfig()
+	baseConfig := integratedEngine.GetConfig()
+
+	integratedEngine.SetUniverseCallback(func(change strategy.UniverseChange) {
+		for _, symbol := range change.Removed {
+			rotator.Drop(ctx, symbol)
+		}
+
+		for _, market := range change.Added {
+			// A symbol selected again before leaving may still be trading
+			if rotator.Keep(market.Symbol) {
+				continue
+			}
+			if _, err := orchestrator.GetSymbolStrategy(market.Symbol); err == nil {
+				continue
+			}
+			if market.Exchange != "" {
+				if err := multiplexer.MapSymbol(market.Symbol, market.Exchange); err != nil {
+					botLogger().Warn("failed to map discovered symbol", "symbol", market.Symbol, "error", err)
+					continue
+				}
+			}
+
+			strategyInstance, err := orchestrator.AddSymbol(ctx, symbolmanager.SymbolConfig{
+				Symbol:         market.Symbol,
+				StrategyConfig: baseConfig.ForSymbol(market.Symbol),
+				Enabled:        true,
+			})
+			if err != nil {
+				botLogger().Warn("failed to add discovered symbol", "symbol", market.Symbol, "error", err)
+				continue
+			}
+			if exchange, err := multiplexer.GetExchangeForSymbol(market.Symbol); err == nil {
+				leverageCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				if err := risk.ApplyLeverage(leverageCtx, exchange, riskConfig, []string{market.Symbol}); err != nil {
+					botLogger().Warn("failed to apply leverage", "symbol", market.Symbol, "error", err)
+				}
+				cancel()
+			}
+			setupStrategyCallbacks(market.Symbol, strategyInstance, executionAgent)
+			if err := strategyInstance.Start(ctx); err != nil {
+				botLogger().Error("failed to start discovered strategy", "symbol", market.Symbol, "error", err)
+				orchestrator.RemoveSymbol(market.Symbol)
+				continue
+			}
+			if supervisor != nil {
+				watchStrategy(ctx, supervisor, market.Symbol, strategyInstance)
+			}
+			botLogger().Info("strategy started",
+				"symbol", market.Symbol,
+				"exchange", market.Exchange,
+				"volume_24h", market.Volume24h.StringFixed(0),
+				"reason", "discovery")
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/strategy"
+)
+
+// signalEnsemble combines the entries of the strategy instances when
+// STRATEGY_ENSEMBLE is set
+var signalEnsemble *strategy.Ensemble
+
+// setupSignalEnsemble votes on the entries the strategies signal on a symbol
+// within STRATEGY_ENSEMBLE_WINDOW before executing them, one vote per strategy
+// (STRATEGY_ENSEMBLE=majority) or weighted by its recent accuracy
+// (STRATEGY_ENSEMBLE=weighted)
+func setupSignalEnsemble(ctx context.Context, executionAgent *execution.ExecutionAgent) error {
+	mode := strategy.EnsembleMode(os.Getenv("STRATEGY_ENSEMBLE"))
+	switch mode {
+	case "":
+		return nil
+	case strategy.EnsembleMajority, strategy.EnsembleWeighted:
+	default:
+		return fmt.Errorf("unknown ensemble mode %q", mode)
+	}
+
+	cfg := strategy.DefaultEnsembleConfig()
+	cfg.Mode = mode
+	if value := os.Getenv("STRATEGY_ENSEMBLE_WINDOW"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			cfg.Window = parsed
+		}
+	}
+	for key, target := range map[string]*int{
+		"STRATEGY_ENSEMBLE_MIN_VOTES":       &cfg.MinVotes,
+		"STRATEGY_ENSEMBLE_ACCURACY_TRADES": &cfg.AccuracyWindow,
+	} {
+		if value := os.Getenv(key); value != "" {
+			if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+				*target = parsed
+			}
+		}
+	}
+	if value := os.Getenv("STRATEGY_ENSEMBLE_QUORUM"); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed >= 0 && parsed < 1 {
+			cfg.Quorum = parsed
+		}
+	}
+	for _, name := range strings.Split(os.Getenv("STRATEGY_ENSEMBLE_VETOES"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			cfg.Vetoes = append(cfg.Vetoes, name)
+		}
+	}
+
+	signalEnsemble = strategy.NewEnsemble(cfg, func(signal *strategy.Signal) {
+		executeSignal(ctx, executionAgent, signal, "strategy", signal.Strategy)
+	})
+	botLogger().Info("strategy ensemble enabled",
+		"mode", cfg.Mode,
+		"window", cfg.Window,
+		"min_votes", cfg.MinVotes,
+		"quorum", cfg.Quorum,
+		"vetoes", cfg.Vetoes)
+	return nil
+}
+
+// submitSignal executes signal, through the ensemble when it is enabled
+func submitSignal(ctx context.Context, executionAgent *execution.ExecutionAgent, signal *strategy.Signal, args ...any) {
+	if signalEnsemble != nil {
+		signalEnsemble.Submit(signal)
+		return
+	}
+	executeSignal(ctx, executionAgent, signal, args...)
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/equity"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/risk"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+const defaultEquitySnapshotInterval = 15 * time.Minute
+
+// equityHistory is set when EQUITY_HISTORY_PATH is configured
+var equityHistory *equity.Store
+
+// setupEquityHistory opens the balance and equity history when
+// EQUITY_HISTORY_PATH is set
+func setupEquityHistory() error {
+	path := os.Getenv("EQUITY_HISTORY_PATH")
+	if path == "" {
+		return nil
+	}
+
+	store, err := equity.Open(path)
+	if err != nil {
+		return err
+	}
+	equityHistory = store
+	sessionStart = time.Now()
+	benchmarkSymbols = parseSymbols(os.Getenv("BENCHMARK_SYMBOLS"))
+	botLogger().Info("equity history enabled", "path", path,
+		"snapshots", len(store.Snapshots("", time.Time{})))
+	return nil
+}
+
+// closeEquityHistory closes the equity history if enabled
+func closeEquityHistory() {
+	if equityHistory == nil {
+		return
+	}
+	if err := equityHistory.Close(); err != nil {
+		botLogger().Error("failed to close equity history", "error", err)
+	}
+}
+
+// runEquitySnapshots records the equity of every exchange every
+// EQUITY_SNAPSHOT_MINUTES until ctx is canceled
+func runEquitySnapshots(ctx context.Context, multiplexer *exchanges.ExchangeMultiplexer, riskManager *risk.Manager) {
+	interval := defaultEquitySnapshotInterval
+	if value := os.Getenv("EQUITY_SNAPSHOT_MINUTES"); value != "" {
+		if minutes, err := strconv.Atoi(value); err == nil && minutes > 0 {
+			interval = time.Duration(minutes) * time.Minute
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			// The data is refreshed by the headless loop or the TUI; a
+			// snapshot older than the interval is not recorded again
+			data := multiplexer.GetAggregatedData()
+			if data == nil || now.Sub(time.Unix(data.LastUpdate, 0)) > interval {
+				botLogger().Debug("no fresh exchange data to snapshot equity")
+				continue
+			}
+			prices := symbolPrices(ctx, multiplexer)
+			snapshots := equitySnapshots(data, riskManager.GetStats().NetDeposits, prices, now)
+			if err := equityHistory.Record(snapshots...); err != nil {
+				botLogger().Warn("failed to record equity snapshot", "error", err)
+				continue
+			}
+			updateSessionBenchmarks(multiplexer.GetSymbolMap())
+		}
+	}
+}
+
+// equitySnapshots builds the snapshots of the exchanges that reported their
+// margin, and of the total with the symbol prices when every exchange
+// reported
+func equitySnapshots(data *exchanges.AggregatedData, netDeposits decimal.Decimal, prices map[string]decimal.Decimal, at time.Time) []equity.Snapshot {
+	var snapshots []equity.Snapshot
+	complete := len(data.Exchanges) > 0
+	for name, exchangeData := range data.Exchanges {
+		if exchangeData.Error != nil || exchangeData.Margin.UpdatedAt.IsZero() {
+			complete = false
+			continue
+		}
+		unrealized := decimal.Zero
+		for _, position := range exchangeData.Positions {
+			unrealized = unrealized.Add(position.UnrealizedPnL)
+		}
+		// Margin is held in USD collateral
+		snapshots = append(snapshots, equity.Snapshot{
+			Time:          at,
+			Exchange:      name,
+			Currency:      "USD",
+			Equity:        exchangeData.Margin.Equity,
+			UnrealizedPnL: unrealized,
+		})
+	}
+
+	// Transfers are detected on the total balance only
+	if complete {
+		snapshots = append(snapshots, equity.Snapshot{
+			Time:          at,
+			Exchange:      equity.Total,
+			Currency:      data.Currency,
+			Equity:        data.TotalBalance,
+			UnrealizedPnL: data.TotalUnrealizedPnL,
+			NetDeposits:   netDeposits,
+			Prices:        prices,
+		})
+	}
+	return snapshots
+}
+
+// registerEquityHandlers serves the PnL rollups on
+// GET /pnl?period=daily|weekly|monthly&exchange= and the equity snapshots on
+// GET /pnl/snapshots?exchange=&since=RFC3339. Every exchange is included
+// when none is named.
+func registerEquityHandlers(server *telemetry.Server) {
+	server.HandleFunc("/pnl", func(w http.ResponseWriter, r *http.Request) {
+		period := equity.Daily
+		if value := r.URL.Query().Get("period"); value != "" {
+			parsed, err := equity.ParsePeriod(value)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			period = parsed
+		}
+		writeJSON(w, equityHistory.Rollups(r.URL.Query().Get("exchange"), period))
+	})
+	server.HandleFunc("/pnl/snapshots", func(w http.ResponseWriter, r *http.Request) {
+		var since time.Time
+		if value := r.URL.Query().Get("since"); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				http.Error(w, "since must be an RFC 3339 time", http.StatusBadRequest)
+				return
+			}
+			since = parsed
+		}
+		writeJSON(w, equityHistory.Snapshots(r.URL.Query().Get("exchange"), since))
+	})
+}
+
+// writeJSON encodes value as the JSON response
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
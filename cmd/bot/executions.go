@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/journal"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// maxRecentExecutions bounds the executions kept for the /executions report
+const maxRecentExecutions = 1000
+
+var (
+	executionsMu     sync.Mutex
+	recentExecutions []order.Execution
+)
+
+// setupExecutionReports logs the slippage and liquidity of every filled
+// order, keeps the latest ones for the /executions report and the cost
+// estimates of the execution agent, and journals them when the trade journal
+// is enabled. It must run after setupTradeJournal.
+func setupExecutionReports(orderManager *order.Manager, executionAgent *execution.ExecutionAgent) {
+	orderManager.SetExecutionCallback(func(execution order.Execution) {
+		botLogger().Info("order executed",
+			"exchange", execution.Exchange,
+			"symbol", execution.Symbol,
+			"side", execution.Side,
+			"reference_price", execution.ReferencePrice,
+			"fill_price", execution.FillPrice,
+			"slippage_bps", execution.SlippageBps,
+			"liquidity", execution.Liquidity)
+
+		executionsMu.Lock()
+		if len(recentExecutions) >= maxRecentExecutions {
+			recentExecutions = append(recentExecutions[:0], recentExecutions[1:]...)
+		}
+		recentExecutions = append(recentExecutions, execution)
+		executionsMu.Unlock()
+		executionAgent.RecordExecution(execution)
+
+		if tradeJournal != nil {
+			if err := tradeJournal.Record(journal.NewFill(execution)); err != nil {
+				botLogger().Warn("failed to journal fill", "symbol", execution.Symbol, "error", err)
+			}
+		}
+	})
+}
+
+// registerExecutionHandlers serves the execution quality of the latest fills
+// per exchange and symbol on GET /executions
+func registerExecutionHandlers(server *telemetry.Server) {
+	server.HandleFunc("/executions", func(w http.ResponseWriter, _ *http.Request) {
+		executionsMu.Lock()
+		summaries := order.SummarizeExecutions(recentExecutions)
+		executionsMu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(summaries); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/risk"
+)
+
+// fxPrimeTimeout bounds fetching the conversion rates of the traded symbols
+// at startup
+const fxPrimeTimeout = 10 * time.Second
+
+// setupCurrencyConversion normalizes balances, PnL and risk limits into
+// REPORTING_CURRENCY using the primary exchange's tickers, and fetches the
+// rates of the quote currencies of the traded symbols
+func setupCurrencyConversion(
+	primaryExchange exchanges.Exchange,
+	multiplexer *exchanges.ExchangeMultiplexer,
+	riskManager *risk.Manager,
+	appConfig *config.AppConfig,
+) {
+	fx := exchanges.NewFXConverter(primaryExchange, appConfig.ReportingCurrency)
+
+	var quotes []string
+	for _, symbol := range appConfig.TradingSymbols {
+		quotes = append(quotes, exchanges.QuoteCurrency(symbol))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), fxPrimeTimeout)
+	fx.Refresh(ctx, quotes...)
+	cancel()
+
+	multiplexer.SetFXConverter(fx)
+	riskManager.SetCurrencyConverter(fx)
+	botLogger().Info("reporting currency set", "currency", fx.Currency())
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/halt"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/replay"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// defaultHaltCheckInterval is how often market status is polled
+const defaultHaltCheckInterval = time.Minute
+
+// halts holds the symbols halted by their exchange or by an operator
+var halts = halt.NewRegistry()
+
+// setupHalts stops strategies from signaling entries, and the order manager
+// from placing them and monitoring, on halted symbols. It must run before
+// strategy instances are created so they share the halt check.
+func setupHalts(orchestrator *strategy.StrategyOrchestrator, orderManager *order.Manager, integratedEngine *strategy.IntegratedStrategyEngine) {
+	halts.SetChangeCallback(func(h halt.Halt, halted bool) {
+		if halted {
+			botLogger().Warn("trading halted", "symbol", h.Symbol, "source", h.Source, "reason", h.Reason)
+			return
+		}
+		botLogger().Info("trading resumed", "symbol", h.Symbol, "source", h.Source)
+	})
+	orchestrator.SetHaltCheck(halts.IsHalted)
+	integratedEngine.GetScalpingStrategy().SetHaltCheck(halts.IsHalted)
+	orderManager.SetHaltCheck(halts.IsHalted)
+}
+
+// runHaltDetection polls the market status of the traded symbols on the
+// exchanges that report it, every HALT_CHECK_INTERVAL_SECONDS (default 60),
+// until ctx is done
+func runHaltDetection(ctx context.Context, multiplexer *exchanges.ExchangeMultiplexer) {
+	interval := defaultHaltCheckInterval
+	if value := os.Getenv("HALT_CHECK_INTERVAL_SECONDS"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			interval = time.Duration(seconds) * time.Second
+		}
+	}
+
+	reporters := make(map[string]exchanges.MarketStatusReporter)
+	for name, exchange := range multiplexer.GetExchanges() {
+		if recording, ok := exchange.(*replay.RecordingExchange); ok {
+			exchange = recording.Exchange
+		}
+		if reporter, ok := exchange.(exchanges.MarketStatusReporter); ok {
+			reporters[name] = reporter
+		}
+	}
+	if len(reporters) == 0 {
+		return
+	}
+
+	refresh := func() {
+		// Symbol discovery maps symbols while running
+		symbolsByExchange := make(map[string][]string)
+		for symbol, name := range multiplexer.GetSymbolMap() {
+			symbolsByExchange[name] = append(symbolsByExchange[name], symbol)
+		}
+		for name, reporter := range reporters {
+			if len(symbolsByExchange[name]) == 0 {
+				continue
+			}
+			callCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+			if err := halts.Refresh(callCtx, reporter, symbolsByExchange[name]); err != nil {
+				botLogger().Warn("failed to check market status", "exchange", name, "error", err)
+			}
+			cancel()
+		}
+	}
+
+	refresh()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
+
+// registerHaltHandlers serves the halts on GET /halts and halts or resumes a
+// symbol on POST /halts/halt?symbol=&reason= and POST /halts/resume?symbol=.
+// Only manual halts can be resumed; exchange halts follow the market status.
+func registerHaltHandlers(server *telemetry.Server) {
+	server.HandleFunc("/halts", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(halts.Halts()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	update := func(apply func(symbol, reason string)) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			symbol := r.URL.Query().Get("symbol")
+			if symbol == "" {
+				http.Error(w, "missing symbol", http.StatusBadRequest)
+				return
+			}
+			apply(symbol, r.URL.Query().Get("reason"))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+	server.HandleFunc("/halts/halt", update(func(symbol, reason string) {
+		halts.Halt(symbol, halt.SourceManual, reason)
+	}))
+	server.HandleFunc("/halts/resume", update(func(symbol, _ string) {
+		halts.Resume(symbol, halt.SourceManual)
+	}))
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/instancelock"
+	"github.com/guyghost/constantine/internal/lifecycle"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+var (
+	// instanceLock is held while the bot trades, unless locking is disabled
+	instanceLock instancelock.Lock
+	// instanceLockTTL is how long the lock lasts without renewal
+	instanceLockTTL time.Duration
+	// observeOnly is set while another instance trades the account: orders
+	// are neither placed nor canceled
+	observeOnly atomic.Bool
+)
+
+// acquireInstanceLock takes the lock of every account traded, so two instances
+// never trade the same account. INSTANCE_LOCK selects a file lock in
+// INSTANCE_LOCK_DIR (file, the default), a lease on INSTANCE_LOCK_REDIS_URL
+// expiring after INSTANCE_LOCK_TTL_SECONDS (redis), or none (off). When
+// another instance holds the lock the bot refuses to start, or starts in
+// observe-only mode with INSTANCE_LOCK_CONFLICT=observe.
+func acquireInstanceLock(ctx context.Context, appConfig *config.AppConfig) error {
+	identities := accountIdentities(appConfig)
+	if len(identities) == 0 {
+		return nil
+	}
+	owner := instancelock.Owner()
+
+	instanceLockTTL = 30 * time.Second
+	if val := os.Getenv("INSTANCE_LOCK_TTL_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 3 {
+			instanceLockTTL = time.Duration(parsed) * time.Second
+		}
+	}
+
+	// Each account takes its own lock, so instances trading different sets
+	// of exchanges still exclude each other on the accounts they share
+	keys := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		keys = append(keys, instancelock.AccountKey(identity...))
+	}
+	sort.Strings(keys)
+
+	var locks instancelock.Locks
+	switch backend := strings.ToLower(os.Getenv("INSTANCE_LOCK")); backend {
+	case "", "file":
+		dir := os.Getenv("INSTANCE_LOCK_DIR")
+		if dir == "" {
+			dir = os.TempDir()
+		}
+		for _, key := range keys {
+			locks = append(locks, instancelock.NewFileLock(dir, key, owner))
+		}
+	case "redis":
+		for _, key := range keys {
+			lock, err := instancelock.NewRedisLock(os.Getenv("INSTANCE_LOCK_REDIS_URL"), key, owner, instanceLockTTL)
+			if err != nil {
+				return err
+			}
+			locks = append(locks, lock)
+		}
+	case "off", "none":
+		botLogger().Warn("instance lock disabled, make sure no other instance trades the account")
+		return nil
+	default:
+		return fmt.Errorf("unknown INSTANCE_LOCK %q, expected file, redis or off", backend)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	err := locks.Acquire(callCtx)
+	switch {
+	case err == nil:
+		instanceLock = locks
+		botLogger().Info("instance lock acquired", "keys", strings.Join(keys, ","), "owner", owner)
+		return nil
+	case !errors.Is(err, instancelock.ErrHeld):
+		return err
+	case strings.EqualFold(os.Getenv("INSTANCE_LOCK_CONFLICT"), "observe"):
+		observeOnly.Store(true)
+		telemetry.RecordError("instance_lock_held")
+		botLogger().Warn("another instance trades the account, starting in observe-only mode", "error", err)
+		return nil
+	default:
+		return fmt.Errorf("another instance trades the account, stop it or set INSTANCE_LOCK_CONFLICT=observe: %w", err)
+	}
+}
+
+// accountIdentities returns the values identifying the account of each
+// enabled exchange
+func accountIdentities(appConfig *config.AppConfig) [][]string {
+	var identities [][]string
+	for name, cfg := range appConfig.Exchanges {
+		if !cfg.Enabled {
+			continue
+		}
+		identities = append(identities, []string{
+			name, cfg.APIKey, cfg.PortfolioID, cfg.Mnemonic, strconv.Itoa(cfg.SubAccountNumber),
+		})
+	}
+	return identities
+}
+
+// watchInstanceLock puts orderManager in observe-only mode when another
+// instance trades the account, at startup or once the lock is lost while
+// running
+func watchInstanceLock(components *lifecycle.Manager, orderManager *order.Manager) {
+	if observeOnly.Load() {
+		orderManager.SetObserveOnly(true)
+		return
+	}
+	if instanceLock == nil {
+		return
+	}
+
+	components.Register(lifecycle.NewService("instance_lock", func(ctx context.Context) {
+		instancelock.Keep(ctx, instanceLock, instanceLockTTL/3, instanceLockTTL, func(err error) {
+			observeOnly.Store(true)
+			orderManager.SetObserveOnly(true)
+			telemetry.RecordError("instance_lock_lost")
+			botLogger().Error("instance lock lost, switching to observe-only mode", "error", err)
+		})
+	}))
+}
+
+// releaseInstanceLock lets another instance trade the account
+func releaseInstanceLock() {
+	if instanceLock == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := instanceLock.Release(ctx); err != nil {
+		botLogger().Warn("failed to release instance lock", "error", err)
+	}
+}
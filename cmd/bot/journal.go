@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/journal"
+	"github.com/guyghost/constantine/internal/strategy"
+)
+
+// tradeJournal is set when TRADE_JOURNAL_PATH is configured
+var tradeJournal *journal.Journal
+
+// setupTradeJournal records every entry placed by the execution agent, with
+// the indicator snapshot of its signal, and every stop it moves when
+// TRADE_JOURNAL_PATH is set
+func setupTradeJournal(executionAgent *execution.ExecutionAgent) error {
+	path := os.Getenv("TRADE_JOURNAL_PATH")
+	if path == "" {
+		return nil
+	}
+
+	j, err := journal.NewFile(path)
+	if err != nil {
+		return err
+	}
+	tradeJournal = j
+
+	executionAgent.SetEntryCallback(func(signal *strategy.Signal, placed *exchanges.Order, decision *execution.Decision) {
+		entry := journal.NewEntry(signal, placed)
+		entry.SizeMultiplier = decision.SizeMultiplier
+		if err := j.Record(entry); err != nil {
+			botLogger().Warn("failed to journal trade", "symbol", signal.Symbol, "error", err)
+		}
+	})
+	executionAgent.SetStopMoveCallback(func(move execution.StopMove) {
+		entry := journal.NewStopMove(move.Symbol, move.Side, move.Strategy, move.Reason, journal.StopMove{
+			From: move.From,
+			To:   move.To,
+			Mark: move.Mark,
+		})
+		entry.Time = move.Time
+		if err := j.Record(entry); err != nil {
+			botLogger().Warn("failed to journal stop move", "symbol", move.Symbol, "error", err)
+		}
+	})
+	botLogger().Info("trade journal enabled", "path", path)
+	return nil
+}
+
+// closeTradeJournal closes the trade journal if enabled
+func closeTradeJournal() {
+	if tradeJournal == nil {
+		return
+	}
+	if err := tradeJournal.Close(); err != nil {
+		botLogger().Error("failed to close trade journal", "error", err)
+	}
+}
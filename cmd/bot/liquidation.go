@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/risk"
+)
+
+// liquidationCheckInterval is how often positions are checked against their
+// liquidation price
+const liquidationCheckInterval = 5 * time.Second
+
+// runLiquidationDefense partially closes positions nearing liquidation until
+// ctx is canceled
+func runLiquidationDefense(ctx context.Context, riskManager *risk.Manager, orderManager *order.Manager) {
+	ticker := time.NewTicker(liquidationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, at := range riskManager.DefendLiquidations(ctx, orderManager) {
+				if at.Deleverage {
+					botLogger().Warn("position deleveraged near liquidation",
+						"symbol", at.Symbol,
+						"side", at.Side,
+						"liquidation_price", at.LiquidationPrice.String(),
+						"distance_pct", at.Distance.StringFixed(2),
+						"buffer_used", at.BufferUsed.StringFixed(2))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// setupLiquidationAlerts raises a critical alert whenever the venue
+// liquidates or auto-deleverages a position of the account. The position is
+// reconciled by the order manager before the callback runs, and its trade is
+// recorded as liquidated once it is closed.
+func setupLiquidationAlerts(orderManager *order.Manager) {
+	orderManager.SetLiquidationCallback(func(event *order.LiquidationEvent) {
+		telemetry.RecordError("position_" + string(event.Kind))
+		remaining := "untracked"
+		if event.Position != nil {
+			remaining = event.Position.Amount.String()
+		}
+		botLogger().Error("position closed by the exchange",
+			"kind", event.Kind,
+			"symbol", event.Symbol,
+			"side", event.Side,
+			"price", event.Price,
+			"amount", event.Amount,
+			"fee", event.Fee,
+			"remaining", remaining)
+	})
+}
@@ -2,26 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
-	"sync"
 	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/guyghost/constantine/internal/cli"
 	"github.com/guyghost/constantine/internal/config"
 	"github.com/guyghost/constantine/internal/exchanges"
-	"github.com/guyghost/constantine/internal/exchanges/coinbase"
 	"github.com/guyghost/constantine/internal/exchanges/dydx"
-	"github.com/guyghost/constantine/internal/exchanges/hyperliquid"
 	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/lifecycle"
 	"github.com/guyghost/constantine/internal/logger"
 	"github.com/guyghost/constantine/internal/order"
 	"github.com/guyghost/constantine/internal/risk"
+	"github.com/guyghost/constantine/internal/rotation"
 	"github.com/guyghost/constantine/internal/strategy"
 	"github.com/guyghost/constantine/internal/symbolmanager"
 	"github.com/guyghost/constantine/internal/telemetry"
@@ -34,6 +37,18 @@ var (
 	headless = flag.Bool("headless", false, "Run in headless mode without TUI")
 )
 
+// flatByCheckInterval is how often positions are checked against the flat-by
+// times of the trading schedule
+const flatByCheckInterval = 15 * time.Second
+
+// breakEvenCheckInterval is how often open positions are checked for a stop
+// to move to break-even
+const breakEvenCheckInterval = time.Second
+
+// maxHoldingCheckInterval is how often open positions are checked against
+// the maximum holding time of their strategy
+const maxHoldingCheckInterval = 5 * time.Second
+
 // getEnvBool gets a boolean environment variable with default value
 func getEnvBool(key string, defaultValue bool) bool {
 	value := os.Getenv(key)
@@ -65,6 +80,7 @@ func loadLoggerConfig() *logger.Config {
 	}
 
 	cfg.AddSource = getEnvBool("LOG_ADD_SOURCE", false)
+	cfg.Redact = !getEnvBool("LOG_SENSITIVE_DATA", false)
 	if output := os.Getenv("LOG_OUTPUT_PATH"); output != "" {
 		cfg.OutputPath = output
 	}
@@ -76,21 +92,61 @@ func main() {
 	// Load .env file if it exists
 	godotenv.Load()
 
-	flag.Parse()
-
 	logger.SetDefault(logger.New(loadLoggerConfig()))
 
-	if err := run(); err != nil {
-		logger.Default().Error("bot exited with error", "error", err)
+	root := &cli.Command{
+		Name:  "constantine",
+		Short: "Constantine multi-exchange trading bot",
+		Commands: []*cli.Command{
+			runCommand(),
+			cli.BacktestCommand(),
+			cli.SymbolsCommand(),
+			cli.ExchangeCommand(),
+			cli.OrderCommand(),
+			cli.PositionsCommand(),
+			cli.DoctorCommand(),
+			cli.TaxCommand(),
+		},
+	}
+	// Flags alone still start the bot
+	root.Default = root.Commands[0]
+
+	if err := root.Execute(context.Background(), os.Args[1:]); err != nil {
+		if errors.Is(err, cli.ErrUsage) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
 
+// runCommand starts the bot, taking the flags declared on the default flag
+// set
+func runCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "run",
+		Usage: "[flags]",
+		Short: "Run the trading bot (default)",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			flag.CommandLine.VisitAll(func(f *flag.Flag) {
+				fs.Var(f.Value, f.Name, f.Usage)
+			})
+			return func(context.Context, []string) error {
+				if err := run(); err != nil {
+					logger.Default().Error("bot exited with error", "error", err)
+					os.Exit(1)
+				}
+				return nil
+			}
+		},
+	}
+}
+
 func run() error {
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	var wg sync.WaitGroup
+	components := lifecycle.NewManager(lifecycle.DefaultStopTimeout)
 
 	appConfig, err := config.Load()
 	if err != nil {
@@ -98,10 +154,45 @@ func run() error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	shutdownPolicy, err := order.ParseShutdownPolicy(appConfig.ShutdownPolicy)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if err := setupStateEncryption(); err != nil {
+		return fmt.Errorf("failed to set up state encryption: %w", err)
+	}
+
+	if err := setupMarketDataMode(appConfig); err != nil {
+		return fmt.Errorf("failed to set up market data mode: %w", err)
+	}
+	defer closeMarketDataRecorder()
+
+	// A replay trades no account
+	if replayPlayer == nil {
+		if err := acquireInstanceLock(ctx, appConfig); err != nil {
+			return fmt.Errorf("failed to acquire instance lock: %w", err)
+		}
+		defer releaseInstanceLock()
+	}
+
+	if replayPlayer == nil {
+		if err := setupOrderBookRecording(); err != nil {
+			return fmt.Errorf("failed to set up order book recording: %w", err)
+		}
+		defer closeOrderBookRecorder()
+	}
+
 	// Auto-select trading symbols if not configured
-	appConfig.TradingSymbols = autoSelectTradingSymbols(ctx, appConfig)
+	if replayPlayer == nil {
+		appConfig.TradingSymbols = autoSelectTradingSymbols(ctx, appConfig)
+	}
 
 	metricsServer := telemetry.NewServer(appConfig.TelemetryAddr)
+	if err := setupControlAuth(metricsServer); err != nil {
+		return fmt.Errorf("failed to set up control API authentication: %w", err)
+	}
+	defer closeControlAuditLog()
 	if metricsServer != nil {
 		if err := metricsServer.Start(); err != nil {
 			return fmt.Errorf("failed to start telemetry server: %w", err)
@@ -116,7 +207,7 @@ func run() error {
 
 	defer func() {
 		cancel()
-		wg.Wait()
+		components.StopAll()
 	}()
 
 	// Setup signal handling
@@ -133,45 +224,209 @@ func run() error {
 		return fmt.Errorf("failed to initialize bot: %w", err)
 	}
 
+	setupHalts(strategyOrchestrator, orderManager, integratedEngine)
+	watchInstanceLock(components, orderManager)
+
+	if err := setupBlocklist(multiplexer, executionAgent); err != nil {
+		return fmt.Errorf("failed to set up blocklist: %w", err)
+	}
+
+	if err := setupTradeJournal(executionAgent); err != nil {
+		return fmt.Errorf("failed to set up trade journal: %w", err)
+	}
+	defer closeTradeJournal()
+	setupExecutionReports(orderManager, executionAgent)
+	setupLiquidationAlerts(orderManager)
+
+	if err := setupAuditLog(executionAgent); err != nil {
+		return fmt.Errorf("failed to set up audit log: %w", err)
+	}
+	defer closeAuditLog()
+
+	if err := setupEquityHistory(); err != nil {
+		return fmt.Errorf("failed to set up equity history: %w", err)
+	}
+	defer closeEquityHistory()
+
+	if err := setupSignalQueues(); err != nil {
+		return fmt.Errorf("failed to set up signal queues: %w", err)
+	}
+
+	if err := setupSignalEnsemble(ctx, executionAgent); err != nil {
+		return fmt.Errorf("failed to set up strategy ensemble: %w", err)
+	}
+
+	if err := setupStrategyInstances(ctx, integratedEngine, executionAgent, riskManager); err != nil {
+		return fmt.Errorf("failed to set up strategy instances: %w", err)
+	}
+
+	if err := setupDegradationMonitor(executionAgent, riskManager); err != nil {
+		return fmt.Errorf("failed to set up degradation monitor: %w", err)
+	}
+
+	setupDeadman(executionAgent, orderManager, appConfig.ShutdownTimeout)
+
 	// Connect to all exchanges
 	if err := multiplexer.ConnectAll(ctx); err != nil {
 		return fmt.Errorf("failed to connect to exchanges: %w", err)
 	}
-	defer multiplexer.DisconnectAll()
+	defer func() {
+		// Stop components and execution algorithms first so nothing new is
+		// placed while the policy runs
+		cancel()
+		components.StopAll()
+		if n := executionAgent.CancelAlgos(); n > 0 {
+			botLogger().Info("execution algorithms canceled", "count", n)
+		}
+		executeShutdownPolicy(orderManager, shutdownPolicy, appConfig.ShutdownTimeout)
+		multiplexer.DisconnectAll()
+	}()
+
+	// Correct signed request timestamps for clock drift
+	syncExchangeClocks(ctx, multiplexer.GetExchanges())
+	components.Register(lifecycle.NewService("clock_sync", func(ctx context.Context) {
+		runClockSync(ctx, multiplexer.GetExchanges())
+	}))
 
 	// Setup callbacks
 	setupCallbacks(strategyOrchestrator, orderManager, riskManager, executionAgent)
 
+	// A replay starts from an empty account
+	if replayPlayer == nil {
+		if err := recoverSession(ctx, orderManager); err != nil {
+			return fmt.Errorf("failed to recover session (SESSION_RECOVERY=false starts without it): %w", err)
+		}
+	}
+
 	// Setup integrated strategy engine callbacks
-	integratedEngine.SetSignalCallback(func(signal *strategy.Signal) {
+	integratedEngine.SetSignalCallback(queueSignals("integrated", func(signal *strategy.Signal) {
 		botLogger().Info("integrated strategy signal",
 			"type", signal.Type,
 			"side", signal.Side,
 			"symbol", signal.Symbol,
 			"price", signal.Price.StringFixed(2),
 			"strength", signal.Strength,
+			"components", signal.Components,
 		)
 
 		// Handle signal with execution agent
-		if err := executionAgent.HandleSignal(ctx, signal); err != nil {
-			botLogger().Error("execution error", "error", err)
-		}
-	})
+		submitSignal(ctx, executionAgent, signal)
+	}))
 
 	integratedEngine.SetErrorCallback(func(err error) {
 		botLogger().Error("integrated strategy error", "error", err)
 	})
 
-	// Start bot components in background
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		if err := startBotComponents(ctx, strategyOrchestrator, orderManager, integratedEngine); err != nil {
-			botLogger().Error("failed to start bot components", "error", err)
+	// Register bot components, started in order and stopped in reverse
+	if scheduler := setupStrategyScheduler(strategyOrchestrator); scheduler != nil {
+		components.Register(lifecycle.NewComponent("strategy_scheduler", scheduler.Start, scheduler.Stop))
+	}
+	activeStrategies := registerBotComponents(components, strategyOrchestrator, orderManager, integratedEngine)
+
+	if capitalAllocator != nil {
+		components.Register(lifecycle.NewService("strategy_instances", func(ctx context.Context) {
+			runStrategyInstances(ctx, riskManager)
+		}))
+	}
+
+	if tuner := setupParameterTuning(strategyOrchestrator); tuner != nil {
+		components.Register(lifecycle.NewService("parameter_tuning", tuner.Run))
+	}
+
+	components.Register(lifecycle.NewService("liquidation_defense", func(ctx context.Context) {
+		runLiquidationDefense(ctx, riskManager, orderManager)
+	}))
+
+	if replayPlayer == nil {
+		components.Register(lifecycle.NewService("halt_detection", func(ctx context.Context) {
+			runHaltDetection(ctx, multiplexer)
+		}))
+		if equityHistory != nil {
+			components.Register(lifecycle.NewService("equity_snapshots", func(ctx context.Context) {
+				runEquitySnapshots(ctx, multiplexer, riskManager)
+			}))
 		}
-	}()
+		if orderBookRecorder != nil {
+			symbols := orderBookSymbols(appConfig.TradingSymbols)
+			components.Register(lifecycle.NewService("orderbook_recorder", func(ctx context.Context) {
+				runOrderBookRecording(ctx, multiplexer, symbols)
+			}))
+		}
+	}
+
+	if calendar := riskManager.EventCalendar(); calendar != nil {
+		components.Register(lifecycle.NewService("event_calendar", calendar.Run))
+	}
+
+	if deadmanSwitch != nil {
+		components.Register(lifecycle.NewService("deadman_switch", func(ctx context.Context) {
+			deadmanSwitch.Run(ctx, deadmanCheckInterval)
+		}))
+	}
+
+	if executionAgent.HasFlatBy() {
+		components.Register(lifecycle.NewService("flat_by", func(ctx context.Context) {
+			executionAgent.RunFlatBy(ctx, flatByCheckInterval)
+		}))
+	}
+
+	if executionAgent.HasBreakEven() {
+		components.Register(lifecycle.NewService("break_even", func(ctx context.Context) {
+			executionAgent.RunBreakEven(ctx, breakEvenCheckInterval)
+		}))
+	}
+
+	if executionAgent.HasMaxHolding() {
+		components.Register(lifecycle.NewService("max_holding", func(ctx context.Context) {
+			executionAgent.RunMaxHolding(ctx, maxHoldingCheckInterval)
+		}))
+	}
+	setupSignalParking(components, multiplexer, executionAgent)
+
+	// A replay goes quiet once the recording is exhausted, which is not a
+	// stuck component
+	var rotator *rotation.Rotator
+	if replayPlayer == nil {
+		supervisor := newWatchdog(ctx, multiplexer.GetExchanges(), strategyOrchestrator, orderManager)
+		components.Register(lifecycle.NewService("watchdog", func(ctx context.Context) {
+			runWatchdog(ctx, supervisor)
+		}))
+		if symbolDiscovery {
+			rotator, err = setupSymbolRotation(strategyOrchestrator, orderManager, supervisor)
+			if err != nil {
+				return fmt.Errorf("failed to set up symbol rotation: %w", err)
+			}
+			components.Register(lifecycle.NewService("symbol_rotation", func(ctx context.Context) {
+				rotator.Run(ctx, rotationCheckInterval)
+			}))
+			applyUniverseChanges(ctx, integratedEngine, strategyOrchestrator, multiplexer, executionAgent, supervisor, rotator)
+		}
+	}
+
+	if replayPlayer != nil {
+		components.Register(lifecycle.NewService("replay", runReplay))
+	}
+
+	if err := components.StartAll(ctx); err != nil {
+		return fmt.Errorf("failed to start bot components: %w", err)
+	}
+	botLogger().Info("bot components started", "active_strategies", activeStrategies)
 
 	if metricsServer != nil {
+		metricsServer.SetStatusSource(currentStatus)
+		registerConfirmationHandlers(metricsServer)
+		registerHaltHandlers(metricsServer)
+		registerBlocklistHandlers(metricsServer)
+		registerExecutionHandlers(metricsServer)
+		if equityHistory != nil {
+			registerEquityHandlers(metricsServer)
+		}
+		if degradationMonitor != nil {
+			registerDegradationHandlers(metricsServer)
+		}
+		if deadmanSwitch != nil {
+			registerDeadmanHandlers(metricsServer)
+		}
 		metricsServer.SetReady(true)
 	}
 
@@ -182,6 +437,16 @@ func run() error {
 
 	// Create TUI model
 	model := tui.NewModel(multiplexer, strategyOrchestrator, orderManager, riskManager, integratedEngine, appConfig.TradingSymbols)
+	model.SetLedger(pnlLedger)
+	model.SetConfirmations(confirmations)
+	model.SetHalts(halts)
+	model.SetBlocklist(marketBlocklist)
+	model.SetRotation(rotator)
+	model.SetEquityHistory(equityHistory)
+	model.SetDegradation(degradationMonitor)
+	model.SetDeadman(deadmanSwitch)
+	model.SetObserveOnly(observeOnly.Load)
+	model.SetFlatBy(executionAgent.FlatBy)
 
 	// Start the TUI
 	p := tea.NewProgram(model, tea.WithAltScreen())
@@ -198,6 +463,25 @@ func botLogger() *logger.Logger {
 	return logger.Default().Component("bot")
 }
 
+// logExecutionError logs a signal the execution agent failed to execute by
+// how the failure should be handled: failures needing an operator, such as
+// rejected credentials or missing funds, are logged as errors and counted
+func logExecutionError(err error, args ...any) {
+	action := execution.ErrorActionAbort
+	var execErr *execution.ExecutionError
+	if errors.As(err, &execErr) {
+		action = execErr.Action()
+	}
+	args = append(args, "action", action, "error", err)
+
+	if action == execution.ErrorActionAlert {
+		telemetry.RecordError("execution_alert")
+		botLogger().Error("execution error requires attention", args...)
+		return
+	}
+	botLogger().Warn("execution error", args...)
+}
+
 // autoSelectTradingSymbols automatically selects the best trading symbols for dYdX
 func autoSelectTradingSymbols(ctx context.Context, appConfig *config.AppConfig) []string {
 	// If symbols are explicitly configured (via env var), use them
@@ -224,19 +508,16 @@ func autoSelectTradingSymbols(ctx context.Context, appConfig *config.AppConfig)
 	botLogger().Info("auto-selecting best trading symbols...")
 
 	// Create dYdX client for symbol selection
-	var dydxClient *dydx.Client
-	var err error
-
-	if dydxCfg.Mnemonic != "" {
-		dydxClient, err = dydx.NewClientWithMnemonic(dydxCfg.Mnemonic, 0)
-	} else {
-		dydxClient, err = dydx.NewClient(dydxCfg.APIKey, dydxCfg.APISecret)
-	}
-
+	exchange, err := cli.NewExchange("dydx", dydxCfg)
 	if err != nil {
 		botLogger().Error("failed to create dYdX client for symbol selection", "error", err)
 		return []string{"BTC-USD", "ETH-USD"} // Fallback symbols
 	}
+	dydxClient, ok := exchange.(*dydx.Client)
+	if !ok {
+		botLogger().Error("dYdX connector does not support symbol selection")
+		return []string{"BTC-USD", "ETH-USD"} // Fallback symbols
+	}
 
 	// Get auto-selection parameters from environment
 	maxSymbols := 10
@@ -244,17 +525,15 @@ func autoSelectTradingSymbols(ctx context.Context, appConfig *config.AppConfig)
 		fmt.Sscanf(val, "%d", &maxSymbols)
 	}
 
-	minQuality := 0.70
-	if val := os.Getenv("AUTO_SELECT_MIN_QUALITY"); val != "" {
-		fmt.Sscanf(val, "%f", &minQuality)
-	}
+	quality := appConfig.Scoring.Quality
+	dydxClient.SetQualityConfig(cli.QualityConfig(quality))
 
 	// Create a context with timeout for symbol selection
 	selectCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	// Select best markets
-	markets, err := dydxClient.SelectBestMarkets(selectCtx, maxSymbols, minQuality)
+	markets, err := dydxClient.SelectBestMarkets(selectCtx, maxSymbols, quality.MinQuality)
 	if err != nil {
 		botLogger().Error("failed to select markets", "error", err)
 		return []string{"BTC-USD", "ETH-USD"} // Fallback symbols
@@ -281,7 +560,7 @@ func autoSelectTradingSymbols(ctx context.Context, appConfig *config.AppConfig)
 	botLogger().Info("auto-selection complete",
 		"symbols", selectedSymbols,
 		"count", len(selectedSymbols),
-		"min_quality", fmt.Sprintf("%.0f%%", minQuality*100),
+		"min_quality", fmt.Sprintf("%.0f%%", quality.MinQuality*100),
 	)
 
 	return selectedSymbols
@@ -316,70 +595,17 @@ func initializeBot(appConfig *config.AppConfig) (
 	// Create all exchange clients based on configuration
 	exchangesMap := make(map[string]exchanges.Exchange)
 
-	// Hyperliquid exchange
-	if hyperCfg, ok := appConfig.Exchanges["hyperliquid"]; ok && hyperCfg.Enabled {
-		hyperliquidExchange := hyperliquid.NewClient(
-			hyperCfg.APIKey,
-			hyperCfg.APISecret,
-		)
-		exchangesMap["hyperliquid"] = hyperliquidExchange
-		botLogger().Info("exchange enabled", "exchange", "hyperliquid")
-	}
-
-	// Coinbase exchange
-	if coinbaseCfg, ok := appConfig.Exchanges["coinbase"]; ok && coinbaseCfg.Enabled {
-		var coinbaseExchange *coinbase.Client
-
-		if coinbaseCfg.PortfolioID != "" {
-			coinbaseExchange = coinbase.NewClientWithPortfolio(
-				coinbaseCfg.APIKey,
-				coinbaseCfg.APISecret, // Now expects private key PEM
-				coinbaseCfg.PortfolioID,
-			)
-		} else {
-			coinbaseExchange = coinbase.NewClient(
-				coinbaseCfg.APIKey,
-				coinbaseCfg.APISecret, // Now expects private key PEM
-			)
+	for _, name := range cli.EnabledExchanges(appConfig) {
+		exchange, err := cli.NewExchange(name, appConfig.Exchanges[name])
+		if err != nil {
+			return nil, nil, nil, nil, nil, nil, err
 		}
-		exchangesMap["coinbase"] = coinbaseExchange
-		botLogger().Info("exchange enabled", "exchange", "coinbase")
-	}
-
-	// dYdX exchange
-	if dydxCfg, ok := appConfig.Exchanges["dydx"]; ok && dydxCfg.Enabled {
-		var dydxExchange exchanges.Exchange
-		var err error
-
-		// Check if mnemonic is set (preferred method)
-		if dydxCfg.Mnemonic != "" {
-			// Use mnemonic-based authentication
-			dydxExchange, err = dydx.NewClientWithMnemonic(
-				dydxCfg.Mnemonic,
-				dydxCfg.SubAccountNumber,
-			)
-			if err != nil {
-				return nil, nil, nil, nil, nil, nil, fmt.Errorf("failed to create dYdX client with mnemonic: %w", err)
-			}
-			botLogger().Info("exchange enabled", "exchange", "dydx", "auth", "mnemonic")
-		} else if dydxCfg.APISecret != "" {
-			// Use traditional API key authentication
-			client, err := dydx.NewClient(
-				dydxCfg.APIKey,
-				dydxCfg.APISecret,
-			)
-			if err != nil {
-				return nil, nil, nil, nil, nil, nil, fmt.Errorf("failed to create dYdX client: %w", err)
-			}
-			dydxExchange = client
-			botLogger().Info("exchange enabled", "exchange", "dydx", "auth", "api_key")
-		} else {
-			return nil, nil, nil, nil, nil, nil, fmt.Errorf("dYdX enabled but no authentication method provided - set DYDX_MNEMONIC or DYDX_API_KEY/DYDX_API_SECRET")
-		}
-
-		exchangesMap["dydx"] = dydxExchange
+		exchangesMap[name] = exchange
+		botLogger().Info("exchange enabled", "exchange", name)
 	}
 
+	exchangesMap = applyMarketDataMode(exchangesMap)
+
 	if len(exchangesMap) == 0 {
 		return nil, nil, nil, nil, nil, nil, fmt.Errorf("no exchanges enabled - check ENABLE_* environment variables")
 	}
@@ -387,9 +613,15 @@ func initializeBot(appConfig *config.AppConfig) (
 	// Create aggregator
 	multiplexer := exchanges.NewExchangeMultiplexer()
 
-	// Add exchanges to multiplexer
+	// Add exchanges to multiplexer, with the symbols each may trade
 	for name, exchange := range exchangesMap {
+		cfg := appConfig.Exchanges[name]
+		filter, err := exchanges.NewSymbolFilter(cfg.AllowSymbols, cfg.DenySymbols)
+		if err != nil {
+			return nil, nil, nil, nil, nil, nil, fmt.Errorf("invalid %s symbols: %w", name, err)
+		}
 		multiplexer.AddExchange(name, exchange)
+		multiplexer.Configure(name, exchanges.ExchangeSettings{BaseCurrency: cfg.BaseCurrency, Symbols: filter})
 	}
 
 	// Map symbols to primary exchange (for now, use the first one for all)
@@ -399,13 +631,23 @@ func initializeBot(appConfig *config.AppConfig) (
 		break
 	}
 
-	// Map all trading symbols to the primary exchange
+	// Map all trading symbols to the primary exchange, leaving out those it
+	// may not trade
+	var allowedSymbols []string
 	for _, symbol := range appConfig.TradingSymbols {
-		if err := multiplexer.MapSymbol(symbol, primaryExchangeName); err != nil {
+		if err := multiplexer.MapSymbol(symbol, primaryExchangeName); errors.Is(err, exchanges.ErrSymbolNotAllowed) {
+			botLogger().Warn("symbol not allowed, skipping", "symbol", symbol, "exchange", primaryExchangeName)
+			continue
+		} else if err != nil {
 			return nil, nil, nil, nil, nil, nil, fmt.Errorf("failed to map symbol %s: %w", symbol, err)
 		}
+		allowedSymbols = append(allowedSymbols, symbol)
 		botLogger().Info("symbol mapped", "symbol", symbol, "exchange", primaryExchangeName)
 	}
+	if len(allowedSymbols) == 0 {
+		return nil, nil, nil, nil, nil, nil, fmt.Errorf("none of the trading symbols %v is allowed on %s", appConfig.TradingSymbols, primaryExchangeName)
+	}
+	appConfig.TradingSymbols = allowedSymbols
 
 	// Create strategy configuration for primary symbol
 	strategyConfig := config.DefaultConfig()
@@ -413,18 +655,23 @@ func initializeBot(appConfig *config.AppConfig) (
 
 	// Initialize multi-symbol components
 	symbolManager := symbolmanager.NewSymbolManager()
+	symbolManager.SetSymbolFilter(multiplexer.AllowsSymbol)
 
 	// Create strategy configuration (shared defaults)
 	baseStrategyConfig := config.DefaultConfig()
+	baseStrategyConfig.Selection = appConfig.Scoring.Selection
+	if err := strategy.LoadScorers(baseStrategyConfig); err != nil {
+		return nil, nil, nil, nil, nil, nil, fmt.Errorf("failed to load signal scoring model: %w", err)
+	}
 
 	// Add all trading symbols to symbol manager
 	for _, symbol := range appConfig.TradingSymbols {
-		strategyConfig := *baseStrategyConfig // Copy base config
-		strategyConfig.Symbol = symbol
+		// Copy of the base config with the symbol's own intervals and history
+		strategyConfig := baseStrategyConfig.ForSymbol(symbol)
 
 		symbolConfig := symbolmanager.SymbolConfig{
 			Symbol:         symbol,
-			StrategyConfig: &strategyConfig,
+			StrategyConfig: strategyConfig,
 			Enabled:        true,
 		}
 		if err := symbolManager.AddSymbol(symbol, symbolConfig); err != nil {
@@ -455,14 +702,83 @@ func initializeBot(appConfig *config.AppConfig) (
 
 	// Create order manager
 	orderManager := order.NewManager(primaryExchange)
+	if appConfig.HedgeMode {
+		if err := orderManager.SetHedgeMode(true); err != nil {
+			return nil, nil, nil, nil, nil, nil, fmt.Errorf("HEDGE_MODE: %w", err)
+		}
+		botLogger().Info("hedge mode enabled: long and short positions are tracked independently")
+	}
+	setupOrderGuard(orderManager)
+	setupOrderSweeper(orderManager)
+	setupExitPriceCheck(orderManager, multiplexer)
+	setupVenueLatency(orderManager, multiplexer)
+	if err := setupSyntheticStops(orderManager); err != nil {
+		return nil, nil, nil, nil, nil, nil, fmt.Errorf("failed to restore synthetic stops: %w", err)
+	}
 
 	// Create risk manager
 	riskConfig := risk.LoadConfig()
 	riskManager := risk.NewManager(riskConfig, appConfig.InitialBalance)
+	riskManager.SetOpenOrderSource(orderManager)
+	if path := riskSessionStatePath(); path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return nil, nil, nil, nil, nil, nil, fmt.Errorf("failed to create risk session directory: %w", err)
+		}
+		if err := riskManager.SetSessionStore(risk.NewFileSessionStore(path)); err != nil {
+			return nil, nil, nil, nil, nil, nil, fmt.Errorf("failed to restore risk session: %w", err)
+		}
+		botLogger().Info("risk session persisted",
+			"path", path,
+			"timezone", riskConfig.SessionTimezone.String(),
+			"reset_time", riskConfig.SessionResetTime.String(),
+			"daily_pnl", riskManager.GetDailyPnL().StringFixed(2),
+			"weekly_pnl", riskManager.GetWeeklyPnL().StringFixed(2))
+	}
+	if calendar := risk.NewEventCalendarFromConfig(riskConfig); calendar != nil {
+		if err := calendar.Refresh(context.Background()); err != nil {
+			return nil, nil, nil, nil, nil, nil, fmt.Errorf("failed to load event calendar: %w", err)
+		}
+		riskManager.SetEventCalendar(calendar)
+		botLogger().Info("event calendar enabled",
+			"upcoming", len(calendar.Upcoming()),
+			"action", riskConfig.EventAction,
+			"min_impact", riskConfig.EventMinImpact)
+	}
+	setupCurrencyConversion(primaryExchange, multiplexer, riskManager, appConfig)
+	if err := risk.ApplyLeverage(context.Background(), primaryExchange, riskConfig, appConfig.TradingSymbols); err != nil {
+		return nil, nil, nil, nil, nil, nil, fmt.Errorf("failed to apply leverage: %w", err)
+	}
+	if riskConfig.MarginMode != "" && primaryExchange.Capabilities().Margin {
+		botLogger().Info("leverage applied",
+			"margin_mode", riskConfig.MarginMode,
+			"leverage", riskConfig.MaxLeverage)
+	}
 
 	// Create execution agent
-	executionConfig := execution.DefaultConfig()
+	executionConfig := execution.LoadConfig()
 	executionAgent := execution.NewExecutionAgent(orderManager, riskManager, executionConfig)
+	setupConfirmations(executionAgent, executionConfig)
+	schedule, err := execution.LoadSchedule()
+	if err != nil {
+		return nil, nil, nil, nil, nil, nil, fmt.Errorf("failed to load trading schedule: %w", err)
+	}
+	if schedule != nil {
+		executionAgent.SetSchedule(schedule)
+		botLogger().Info("trading schedule enabled", "blackouts", len(schedule.Blackouts))
+	}
+	executionAgent.SetOrderBookSource(func(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error) {
+		exchange, err := multiplexer.GetExchangeForSymbol(symbol)
+		if err != nil {
+			return nil, err
+		}
+		return exchange.GetOrderBook(ctx, symbol, depth)
+	})
+	executionAgent.SetExchangeResolver(func(symbol string) string {
+		if exchange, err := multiplexer.GetExchangeForSymbol(symbol); err == nil {
+			return exchange.Name()
+		}
+		return primaryExchangeName
+	})
 
 	// Create integrated strategy engine with dynamic weights and symbol selection
 	// Use primary exchange for market data queries
@@ -473,6 +789,14 @@ func initializeBot(appConfig *config.AppConfig) (
 		primaryExchange,
 		symbolRefreshInterval,
 	)
+	if len(exchangesMap) > 1 {
+		// Check price spikes against the median price across exchanges
+		integratedEngine.GetScalpingStrategy().SetPriceReference(multiplexer.CrossExchangePrice)
+	}
+	if setupSymbolDiscovery(integratedEngine, multiplexer, primaryExchangeName, appConfig) {
+		// Discovered symbols stream from the exchange they were found on
+		strategyOrchestrator.SetExchangeResolver(multiplexer.GetExchangeForSymbol)
+	}
 
 	return multiplexer, strategyOrchestrator, orderManager, riskManager, executionAgent, integratedEngine, nil
 }
@@ -489,29 +813,7 @@ func setupCallbacks(
 	// Set up callbacks for each active strategy
 	activeStrategies := strategyOrchestrator.GetActiveStrategies()
 	for symbol, strategyInstance := range activeStrategies {
-		// Strategy signal callback
-		strategyInstance.SetSignalCallback(func(signal *strategy.Signal) {
-			log.Info("strategy signal",
-				"type", signal.Type,
-				"side", signal.Side,
-				"symbol", signal.Symbol,
-				"price", signal.Price.StringFixed(2),
-				"strength", signal.Strength,
-			)
-
-			// Handle signal with execution agent
-			ctx := context.Background()
-			if err := executionAgent.HandleSignal(ctx, signal); err != nil {
-				log.Error("execution error", "error", err)
-			}
-		})
-
-		// Strategy error callback
-		strategyInstance.SetErrorCallback(func(err error) {
-			log.Error("strategy error", "symbol", symbol, "error", err)
-		})
-
-		log.Info("callbacks set up", "symbol", symbol)
+		setupStrategyCallbacks(symbol, strategyInstance, executionAgent)
 	}
 
 	// Order manager callbacks
@@ -522,6 +824,8 @@ func setupCallbacks(
 			"unrealized_pnl", position.UnrealizedPnL.StringFixed(2),
 			"realized_pnl", position.RealizedPnL.StringFixed(2),
 		)
+		recordClosedPosition(position)
+		recordRiskTrade(position, riskManager)
 	})
 
 	orderManager.SetErrorCallback(func(err error) {
@@ -529,52 +833,83 @@ func setupCallbacks(
 	})
 }
 
-// startBotComponents starts the bot components
-func startBotComponents(
-	ctx context.Context,
+// setupStrategyCallbacks executes the signals of the strategy of symbol,
+// queued off its loop, and logs its errors
+func setupStrategyCallbacks(symbol string, strategyInstance *strategy.ScalpingStrategy, executionAgent *execution.ExecutionAgent) {
+	log := botLogger()
+
+	// Strategy signal callback
+	strategyInstance.SetSignalCallback(queueSignals(symbol, func(signal *strategy.Signal) {
+		log.Info("strategy signal",
+			"type", signal.Type,
+			"side", signal.Side,
+			"symbol", signal.Symbol,
+			"price", signal.Price.StringFixed(2),
+			"strength", signal.Strength,
+			"components", signal.Components,
+		)
+
+		// Handle signal with execution agent
+		executeSignal(context.Background(), executionAgent, signal)
+	}))
+
+	// Strategy error callback
+	strategyInstance.SetErrorCallback(func(err error) {
+		log.Error("strategy error", "symbol", symbol, "error", err)
+	})
+
+	log.Info("callbacks set up", "symbol", symbol)
+}
+
+// registerBotComponents registers the order manager, the integrated
+// strategy engine and the strategies of the orchestrator, and returns the
+// number of strategies
+func registerBotComponents(
+	components *lifecycle.Manager,
 	strategyOrchestrator *strategy.StrategyOrchestrator,
 	orderManager *order.Manager,
 	integratedEngine *strategy.IntegratedStrategyEngine,
-) error {
-	// Start order manager
-	if err := orderManager.Start(ctx); err != nil {
-		return fmt.Errorf("failed to start order manager: %w", err)
-	}
-
-	// Start integrated strategy engine (with dynamic weights and symbol selection)
-	if err := integratedEngine.Start(ctx); err != nil {
-		return fmt.Errorf("failed to start integrated strategy engine: %w", err)
-	}
-	botLogger().Info("integrated strategy engine started", "refresh_interval", "30s")
+) int {
+	components.Register(
+		lifecycle.NewComponent("order_manager", orderManager.Start, orderManager.Stop),
+		lifecycle.NewComponent("integrated_engine", integratedEngine.Start, integratedEngine.Stop),
+	)
 
-	// Start all strategies in the orchestrator
 	activeStrategies := strategyOrchestrator.GetActiveStrategies()
-	for symbol, strategyInstance := range activeStrategies {
-		if err := strategyInstance.Start(ctx); err != nil {
-			return fmt.Errorf("failed to start strategy for %s: %w", symbol, err)
-		}
+	symbols := make([]string, 0, len(activeStrategies))
+	for symbol := range activeStrategies {
+		symbols = append(symbols, symbol)
 	}
-
-	botLogger().Info("bot components started", "active_strategies", len(activeStrategies))
-
-	// Wait for context cancellation
-	<-ctx.Done()
-
-	// Stop integrated strategy engine
-	if err := integratedEngine.Stop(); err != nil {
-		botLogger().Error("failed to stop integrated strategy engine", "error", err)
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		strategyInstance := activeStrategies[symbol]
+		components.Register(lifecycle.NewComponent("strategy:"+symbol, strategyInstance.Start, strategyInstance.Stop))
 	}
+	return len(symbols)
+}
 
-	// Stop all strategies
-	for symbol, strategyInstance := range activeStrategies {
-		strategyInstance.Stop()
-		botLogger().Info("strategy stopped", "symbol", symbol)
+// executeShutdownPolicy applies the shutdown policy within the configured timeout
+func executeShutdownPolicy(orderManager *order.Manager, policy order.ShutdownPolicy, timeout time.Duration) {
+	log := botLogger()
+	if orderManager.ObserveOnly() {
+		log.Info("shutdown policy skipped, another instance trades the account", "policy", policy)
+		return
 	}
-	orderManager.Stop()
+	log.Info("executing shutdown policy",
+		"policy", policy,
+		"open_orders", len(orderManager.GetOpenOrders()),
+		"positions", len(orderManager.GetPositions()),
+		"timeout", timeout,
+	)
 
-	botLogger().Info("bot components stopped")
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	return nil
+	if err := orderManager.Shutdown(ctx, policy); err != nil {
+		log.Error("shutdown policy incomplete", "policy", policy, "error", err)
+		return
+	}
+	log.Info("shutdown policy complete", "policy", policy)
 }
 
 // runHeadless runs the bot in headless mode with periodic status updates
@@ -625,12 +960,44 @@ func logAggregatedStatus(
 	// Check if sensitive data logging is enabled
 	logSensitive := getEnvBool("LOG_SENSITIVE_DATA", false)
 
-	// Update risk manager with current total balance from exchanges
-	riskManager.UpdateBalance(data.TotalBalance)
+	// Update risk manager with current total balance from exchanges. Only
+	// complete snapshots are reconciled, so an exchange failing to report or
+	// a balance without a conversion rate is not mistaken for a withdrawal.
+	complete := len(data.Exchanges) > 0 && len(data.Unconverted) == 0
+	for _, exchangeData := range data.Exchanges {
+		if exchangeData.Error != nil {
+			complete = false
+		}
+	}
+	if !complete {
+		riskManager.UpdateBalance(data.TotalBalance)
+	} else if flow := riskManager.ReconcileBalance(data.TotalBalance, data.TotalUnrealizedPnL, time.Now()); flow != nil {
+		if flow.Kind == risk.FlowFunding {
+			log.Debug("balance adjusted by funding or fees", "amount", flow.Amount.StringFixed(2))
+		} else {
+			log.Info("balance transfer detected",
+				"kind", flow.Kind,
+				"amount", flow.Amount.StringFixed(2))
+		}
+	}
+
+	// Buying power is only enforced once every exchange reported its margin
+	marginKnown := len(data.Exchanges) > 0
+	for _, exchangeData := range data.Exchanges {
+		if exchangeData.Margin.UpdatedAt.IsZero() {
+			marginKnown = false
+		}
+	}
+	if marginKnown {
+		riskManager.UpdateFreeCollateral(data.TotalFreeCollateral)
+	}
 
 	log.Info("portfolio status",
 		"total_balance", data.TotalBalance.StringFixed(2),
 		"total_pnl", data.TotalPnL.StringFixed(2),
+		"margin_used", data.TotalMarginUsed.StringFixed(2),
+		"free_collateral", data.TotalFreeCollateral.StringFixed(2),
+		"reserved_capital", riskManager.ReservedCapital().StringFixed(2),
 	)
 
 	// Log each exchange status
@@ -686,62 +1053,77 @@ func logAggregatedStatus(
 	if !canTrade {
 		fields = append(fields, "blocked_reason", reason)
 	}
+	if orderStats := orderManager.GetStats(); orderStats.StaleCanceled+orderStats.StaleRepriced > 0 {
+		fields = append(fields,
+			"stale_canceled", orderStats.StaleCanceled,
+			"stale_repriced", orderStats.StaleRepriced)
+	}
 	log.Info("risk status", fields...)
-}
-
-// calculateAndRecordPnL calculates PnL for filled orders and records trades
-func calculateAndRecordPnL(update *order.OrderUpdate, orderManager *order.Manager, riskManager *risk.Manager) {
-	filledOrder := update.Order
 
-	// Get current positions to determine if this closes a position
-	positions := orderManager.GetPositions()
-
-	// Find if this order closes an existing position
-	for _, pos := range positions {
-		if pos.Symbol == filledOrder.Symbol {
-			// Check if order side closes the position
-			orderClosesPosition := false
-			if (filledOrder.Side == exchanges.OrderSideSell && pos.Side == order.PositionSideLong) ||
-				(filledOrder.Side == exchanges.OrderSideBuy && pos.Side == order.PositionSideShort) {
-				orderClosesPosition = true
-			}
-
-			if orderClosesPosition {
-				// Calculate PnL for closed position
-				var pnl decimal.Decimal
-				if pos.Side == order.PositionSideLong {
-					// Long position closed by sell order
-					pnl = filledOrder.Price.Sub(pos.EntryPrice).Mul(pos.Amount)
-				} else {
-					// Short position closed by buy order
-					pnl = pos.EntryPrice.Sub(filledOrder.Price).Mul(pos.Amount)
-				}
+	// Realized PnL per strategy
+	for _, attribution := range pnlLedger.ByStrategy() {
+		log.Info("strategy performance",
+			"strategy", attribution.Key,
+			"trades", attribution.Trades,
+			"win_rate", attribution.WinRate(),
+			"pnl", attribution.PnL.StringFixed(2),
+		)
+	}
+}
 
-				// Record the trade
-				tradeResult := risk.TradeResult{
-					Timestamp:  update.Timestamp,
-					Symbol:     filledOrder.Symbol,
-					Side:       filledOrder.Side,
-					EntryPrice: pos.EntryPrice,
-					ExitPrice:  filledOrder.Price,
-					Amount:     pos.Amount,
-					PnL:        pnl,
-					IsWin:      pnl.GreaterThan(decimal.Zero),
-				}
+// recordRiskTrade records a closed position with the risk manager, at the
+// PnL realized on its fills net of their fees
+func recordRiskTrade(position *order.ManagedPosition, riskManager *risk.Manager) {
+	if position.Status != order.PositionStatusClosed || position.Amount.IsPositive() {
+		return
+	}
 
-				riskManager.RecordTrade(tradeResult)
+	// The exit is the opposite side of the position
+	side := exchanges.OrderSideSell
+	if position.Side == order.PositionSideShort {
+		side = exchanges.OrderSideBuy
+	}
+	timestamp := time.Now()
+	if position.ExitTime != nil {
+		timestamp = *position.ExitTime
+	}
+	tradeResult := risk.TradeResult{
+		Timestamp:   timestamp,
+		Symbol:      position.Symbol,
+		Side:        side,
+		EntryPrice:  position.EntryPrice,
+		PnL:         position.RealizedPnL,
+		IsWin:       position.RealizedPnL.IsPositive(),
+		StopOut:     position.StopOut,
+		Strategy:    position.Strategy,
+		Liquidation: position.Liquidation,
+	}
+	riskManager.RecordTrade(tradeResult)
+
+	botLogger().Info("trade recorded",
+		"symbol", position.Symbol,
+		"side", position.Side,
+		"entry_price", position.EntryPrice.StringFixed(2),
+		"pnl", position.RealizedPnL.StringFixed(2),
+		"fees", position.Fees.StringFixed(2),
+		"is_win", tradeResult.IsWin,
+	)
+}
 
-				botLogger().Info("trade recorded",
-					"symbol", filledOrder.Symbol,
-					"side", filledOrder.Side,
-					"entry_price", pos.EntryPrice.StringFixed(2),
-					"exit_price", filledOrder.Price.StringFixed(2),
-					"amount", pos.Amount.StringFixed(4),
-					"pnl", pnl.StringFixed(2),
-					"is_win", tradeResult.IsWin,
-				)
-				break
-			}
-		}
+// defaultRiskSessionStatePath is where the risk session counters are kept
+// unless RISK_SESSION_STATE_PATH says otherwise
+const defaultRiskSessionStatePath = "./data/risk_session.json"
+
+// riskSessionStatePath returns the file the risk session counters persist
+// to, empty when RISK_SESSION_STATE_PATH is off
+func riskSessionStatePath() string {
+	path, ok := os.LookupEnv("RISK_SESSION_STATE_PATH")
+	switch {
+	case !ok || path == "":
+		return defaultRiskSessionStatePath
+	case path == "off":
+		return ""
+	default:
+		return path
 	}
 }
@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"math"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/guyghost/constantine/internal/accounting"
 	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/degradation"
+	"github.com/guyghost/constantine/internal/equity"
+	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/guyghost/constantine/internal/testutils"
 	"github.com/shopspring/decimal"
 	"log/slog"
@@ -137,3 +145,101 @@ func TestInitializeBot_WithMockExchange(t *testing.T) {
 
 	t.Log("Successfully initialized bot with dYdX exchange")
 }
+
+func TestEquitySnapshots(t *testing.T) {
+	now := time.Now()
+	data := &exchanges.AggregatedData{
+		Exchanges: map[string]*exchanges.ExchangeData{
+			"dydx": {
+				Name:      "dydx",
+				Positions: []exchanges.Position{{Symbol: "BTC-USD", UnrealizedPnL: decimal.NewFromInt(25)}},
+				Margin:    exchanges.AccountMargin{Equity: decimal.NewFromInt(1025), UpdatedAt: now},
+			},
+		},
+		TotalBalance:       decimal.NewFromInt(1025),
+		TotalUnrealizedPnL: decimal.NewFromInt(25),
+		Currency:           "USD",
+	}
+
+	snapshots := equitySnapshots(data, decimal.NewFromInt(100), nil, now)
+	testutils.AssertEqual(t, 2, len(snapshots), "one snapshot per exchange and the total")
+	testutils.AssertTrue(t, snapshots[0].UnrealizedPnL.Equal(decimal.NewFromInt(25)), "exchange unrealized PnL from its positions")
+	testutils.AssertEqual(t, equity.Total, snapshots[1].Exchange, "total snapshot last")
+	testutils.AssertTrue(t, snapshots[1].NetDeposits.Equal(decimal.NewFromInt(100)), "deposits recorded on the total")
+
+	// An exchange failing to report leaves the total out
+	data.Exchanges["coinbase"] = &exchanges.ExchangeData{Name: "coinbase", Error: errors.New("timeout")}
+	snapshots = equitySnapshots(data, decimal.Zero, nil, now)
+	testutils.AssertEqual(t, 1, len(snapshots), "no total from incomplete data")
+	testutils.AssertEqual(t, "dydx", snapshots[0].Exchange, "reporting exchange still recorded")
+}
+
+func TestCompareSession(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	snapshot := func(hour int, equityValue, deposits, btc, eth int64) equity.Snapshot {
+		return equity.Snapshot{
+			Time:        start.Add(time.Duration(hour) * time.Hour),
+			Exchange:    equity.Total,
+			Equity:      decimal.NewFromInt(equityValue),
+			NetDeposits: decimal.NewFromInt(deposits),
+			Prices: map[string]decimal.Decimal{
+				"BTC-USD": decimal.NewFromInt(btc),
+				"ETH-USD": decimal.NewFromInt(eth),
+			},
+		}
+	}
+	snapshots := []equity.Snapshot{
+		snapshot(0, 1000, 0, 100, 10),
+		snapshot(1, 1010, 0, 101, 10),
+		// A deposit of 500 is not a return
+		snapshot(2, 1520, 500, 103, 11),
+		snapshot(3, 1550, 500, 105, 12),
+	}
+
+	comparisons := compareSession(snapshots, []string{"ETH-USD"}, []string{"BTC-USD"})
+	testutils.AssertEqual(t, 2, len(comparisons), "traded symbols and the benchmark symbol")
+	testutils.AssertEqual(t, "Buy & hold traded symbols", comparisons[0].Benchmark, "traded symbols first")
+	testutils.AssertEqual(t, "Buy & hold BTC-USD", comparisons[1].Benchmark, "benchmark symbol compared on its own")
+	testutils.AssertTrue(t, math.Abs(comparisons[0].StrategyReturnPct-5) < 1e-9, "strategy return net of the deposit")
+	testutils.AssertTrue(t, math.Abs(comparisons[0].BenchmarkReturnPct-20) < 1e-9, "ETH buy and hold return")
+	testutils.AssertTrue(t, math.Abs(comparisons[1].BenchmarkReturnPct-5) < 1e-9, "BTC buy and hold return")
+
+	testutils.AssertEqual(t, 0, len(compareSession(snapshots[:2], []string{"ETH-USD"}, nil)), "too few snapshots to compare")
+}
+
+func TestRecordDegradation(t *testing.T) {
+	degradationMonitor = degradation.NewMonitor(degradation.DefaultConfig(), nil)
+	accountBalance = func() float64 { return 2000 }
+	defer func() { degradationMonitor, accountBalance = nil, nil }()
+
+	recordDegradation(accounting.Trade{Strategy: "main", Symbol: "BTC-USD", PnL: decimal.NewFromInt(10)})
+
+	statuses := degradationMonitor.Statuses()
+	testutils.AssertEqual(t, 2, len(statuses), "strategy and symbol tracked")
+	testutils.AssertEqual(t, 0.5, statuses[1].Metrics.ExpectancyPct, "PnL in percent of the balance")
+}
+
+func TestAcquireInstanceLock(t *testing.T) {
+	t.Setenv("INSTANCE_LOCK_DIR", t.TempDir())
+	defer func() {
+		releaseInstanceLock()
+		instanceLock = nil
+		observeOnly.Store(false)
+	}()
+	appConfig := &config.AppConfig{Exchanges: map[string]config.ExchangeConfig{
+		"hyperliquid": {Enabled: true, APIKey: "0xabc"},
+	}}
+	ctx := context.Background()
+
+	testutils.AssertNoError(t, acquireInstanceLock(ctx, appConfig), "first instance should take the lock")
+	held := instanceLock
+	testutils.AssertNotNil(t, held, "lock should be held")
+
+	// A second instance against the same account
+	instanceLock = nil
+	testutils.AssertError(t, acquireInstanceLock(ctx, appConfig), "second instance should refuse to start")
+	t.Setenv("INSTANCE_LOCK_CONFLICT", "observe")
+	testutils.AssertNoError(t, acquireInstanceLock(ctx, appConfig), "second instance may observe")
+	testutils.AssertTrue(t, observeOnly.Load(), "second instance should observe")
+	instanceLock = held
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// setupOrderGuard sets the hard limits the order manager checks right before
+// each order is submitted, a last line of defense behind the risk manager
+func setupOrderGuard(orderManager *order.Manager) {
+	var limits order.SanityLimits
+	if val := os.Getenv("ORDER_MAX_NOTIONAL"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && parsed.IsPositive() {
+			limits.MaxNotional = parsed
+		}
+	}
+	if val := os.Getenv("ORDER_MAX_AMOUNT"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && parsed.IsPositive() {
+			limits.MaxAmount = parsed
+		}
+	}
+	if val := os.Getenv("ORDER_MAX_PRICE_DEVIATION_PERCENT"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && parsed.IsPositive() {
+			limits.MaxPriceDeviation = parsed
+		}
+	}
+	if !limits.Enabled() {
+		return
+	}
+
+	orderManager.SetSanityLimits(limits)
+	botLogger().Info("order sanity guard enabled",
+		"max_notional", limits.MaxNotional,
+		"max_amount", limits.MaxAmount,
+		"max_price_deviation_percent", limits.MaxPriceDeviation)
+}
+
+// setupExitPriceCheck holds back synthetic stops and exit signals until the
+// other exchanges confirm the price that triggered them, within
+// EXIT_CONFIRM_DEVIATION_PERCENT (default 1%, 0 disables). It needs at least
+// two exchanges.
+func setupExitPriceCheck(orderManager *order.Manager, multiplexer *exchanges.ExchangeMultiplexer) {
+	if len(multiplexer.GetExchanges()) < 2 {
+		return
+	}
+	tolerance := decimal.NewFromInt(1)
+	if val := os.Getenv("EXIT_CONFIRM_DEVIATION_PERCENT"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && !parsed.IsNegative() {
+			tolerance = parsed
+		}
+	}
+	if tolerance.IsZero() {
+		return
+	}
+
+	orderManager.SetExitPriceCheck(multiplexer.AlternatePrice, tolerance)
+	botLogger().Info("exit price confirmation enabled", "tolerance_percent", tolerance)
+}
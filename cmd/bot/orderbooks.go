@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/bookrecord"
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// orderBookFlushInterval is how often recorded books are flushed to disk
+const orderBookFlushInterval = 10 * time.Second
+
+// orderBookRecorder is set when ORDERBOOK_RECORD_DIR is configured
+var orderBookRecorder *bookrecord.Recorder
+
+// setupOrderBookRecording creates the L2 order book recorder when
+// ORDERBOOK_RECORD_DIR is set
+func setupOrderBookRecording() error {
+	dir := os.Getenv("ORDERBOOK_RECORD_DIR")
+	if dir == "" {
+		return nil
+	}
+
+	config := bookrecord.DefaultConfig()
+	config.Dir = dir
+	if val := os.Getenv("ORDERBOOK_RECORD_DEPTH"); val != "" {
+		if depth, err := strconv.Atoi(val); err == nil && depth >= 0 {
+			config.Depth = depth
+		}
+	}
+	if val := os.Getenv("ORDERBOOK_SNAPSHOT_INTERVAL_SECONDS"); val != "" {
+		if seconds, err := strconv.Atoi(val); err == nil && seconds >= 0 {
+			config.SnapshotInterval = time.Duration(seconds) * time.Second
+		}
+	}
+	if val := os.Getenv("ORDERBOOK_ROTATE_MINUTES"); val != "" {
+		if minutes, err := strconv.Atoi(val); err == nil && minutes >= 0 {
+			config.RotateInterval = time.Duration(minutes) * time.Minute
+		}
+	}
+	if val := os.Getenv("ORDERBOOK_ROTATE_MB"); val != "" {
+		if mb, err := strconv.ParseInt(val, 10, 64); err == nil && mb >= 0 {
+			config.MaxFileBytes = mb << 20
+		}
+	}
+
+	recorder, err := bookrecord.NewRecorder(config)
+	if err != nil {
+		return err
+	}
+	orderBookRecorder = recorder
+	botLogger().Info("order book recording enabled",
+		"dir", config.Dir,
+		"depth", config.Depth,
+		"snapshot_interval", config.SnapshotInterval,
+		"rotate_interval", config.RotateInterval,
+	)
+	return nil
+}
+
+// orderBookSymbols returns the symbols whose books are recorded:
+// ORDERBOOK_RECORD_SYMBOLS, or the trading symbols
+func orderBookSymbols(tradingSymbols []string) []string {
+	var symbols []string
+	for _, symbol := range strings.Split(os.Getenv("ORDERBOOK_RECORD_SYMBOLS"), ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return tradingSymbols
+	}
+	return symbols
+}
+
+// runOrderBookRecording records the books of symbols on the exchange each is
+// traded on, flushing them periodically until ctx is canceled
+func runOrderBookRecording(ctx context.Context, multiplexer *exchanges.ExchangeMultiplexer, symbols []string) {
+	if orderBookRecorder == nil {
+		return
+	}
+
+	for _, symbol := range symbols {
+		exchange, err := multiplexer.GetExchangeForSymbol(symbol)
+		if err != nil {
+			botLogger().Warn("cannot record order book", "symbol", symbol, "error", err)
+			continue
+		}
+		name := exchange.Name()
+		err = exchange.SubscribeOrderBook(ctx, symbol, func(book *exchanges.OrderBook) {
+			if err := orderBookRecorder.Record(name, book); err != nil {
+				botLogger().Warn("failed to record order book", "exchange", name, "symbol", symbol, "error", err)
+			}
+		})
+		if err != nil {
+			botLogger().Warn("failed to subscribe to order book", "exchange", name, "symbol", symbol, "error", err)
+		}
+	}
+
+	ticker := time.NewTicker(orderBookFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := orderBookRecorder.Flush(); err != nil {
+				botLogger().Warn("failed to flush order book recording", "error", err)
+			}
+		}
+	}
+}
+
+// closeOrderBookRecorder finishes the current recording file if enabled
+func closeOrderBookRecorder() {
+	if orderBookRecorder == nil {
+		return
+	}
+	if err := orderBookRecorder.Close(); err != nil {
+		botLogger().Error("failed to close order book recording", "error", err)
+	}
+}
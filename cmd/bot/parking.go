@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/lifecycle"
+	"github.com/guyghost/constantine/internal/replay"
+)
+
+const (
+	// parkingCheckInterval is how often parked signals are re-validated
+	parkingCheckInterval = time.Second
+	// streamSilenceLimit is how long the websocket of an exchange may stay
+	// silent before its symbols are considered unreachable
+	streamSilenceLimit = 15 * time.Second
+)
+
+// setupSignalParking parks the signals on symbols whose exchange is
+// disconnected or whose stream went silent when EXECUTION_PARK_SIGNALS_SECONDS
+// is set, and executes those still valid once the exchange is back
+func setupSignalParking(components *lifecycle.Manager, multiplexer *exchanges.ExchangeMultiplexer, executionAgent *execution.ExecutionAgent) {
+	if !executionAgent.HasParking() {
+		return
+	}
+
+	executionAgent.SetConnectivityCheck(func(symbol string) bool {
+		exchange, err := multiplexer.GetExchangeForSymbol(symbol)
+		if err != nil {
+			// Signals on unknown symbols are rejected as they are
+			return true
+		}
+		return exchangeReachable(exchange)
+	})
+	components.Register(lifecycle.NewService("signal_parking", func(ctx context.Context) {
+		executionAgent.RunParking(ctx, parkingCheckInterval)
+	}))
+}
+
+// exchangeReachable reports whether exchange is connected and, when it
+// streams market data, has heard from its stream recently
+func exchangeReachable(exchange exchanges.Exchange) bool {
+	if !exchange.IsConnected() {
+		return false
+	}
+	if recording, ok := exchange.(*replay.RecordingExchange); ok {
+		exchange = recording.Exchange
+	}
+	monitor, ok := exchange.(exchanges.StreamMonitor)
+	if !ok || !monitor.Streaming() {
+		return true
+	}
+	last := monitor.LastMessageAt()
+	return last.IsZero() || time.Since(last) < streamSilenceLimit
+}
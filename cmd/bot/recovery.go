@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+
+	"github.com/guyghost/constantine/internal/order"
+)
+
+// recoverSession adopts the positions and open orders left on the exchange,
+// by a previous session or by hand, so they are managed rather than stacked
+// upon. SESSION_RECOVERY=false starts without them.
+func recoverSession(ctx context.Context, orderManager *order.Manager) error {
+	if !getEnvBool("SESSION_RECOVERY", true) {
+		return nil
+	}
+	report, err := orderManager.Recover(ctx)
+	if err != nil {
+		return err
+	}
+	if len(report.Positions) == 0 && report.Orders == 0 {
+		return nil
+	}
+	botLogger().Info("session recovered",
+		"positions", report.Positions,
+		"orders", report.Orders,
+		"protected", report.Protected,
+		"entry_price_from_exchange", report.Uninferred,
+	)
+	return nil
+}
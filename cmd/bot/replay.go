@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"sort"
+
+	"github.com/guyghost/constantine/internal/bookrecord"
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/replay"
+)
+
+var (
+	replayFile  = flag.String("replay", "", "Replay recorded market data from file instead of connecting to exchanges")
+	replaySpeed = flag.Float64("replay-speed", 1, "Replay speed multiplier (0 = as fast as possible)")
+	replayBooks = flag.String("replay-books", "", "Merge the order books recorded in this directory into the replay")
+)
+
+var (
+	// replayPlayer is set when the bot is driven from a recording
+	replayPlayer *replay.Player
+	// marketDataRecorder is set when MARKET_DATA_RECORD_PATH is configured
+	marketDataRecorder *replay.Recorder
+)
+
+// setupMarketDataMode prepares replay or recording based on flags and environment
+func setupMarketDataMode(appConfig *config.AppConfig) error {
+	if *replayFile != "" {
+		events, err := replay.LoadFile(*replayFile)
+		if err != nil {
+			return err
+		}
+		if *replayBooks != "" {
+			records, err := bookrecord.LoadDir(*replayBooks)
+			if err != nil {
+				return err
+			}
+			events = append(events, bookrecord.Events(records)...)
+			sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+		}
+		replayConfig := replay.DefaultConfig()
+		replayConfig.Speed = *replaySpeed
+		replayConfig.InitialBalance = appConfig.InitialBalance
+		replayPlayer = replay.NewPlayer(events, replayConfig)
+
+		if os.Getenv("TRADING_SYMBOLS") == "" {
+			if symbols := replayPlayer.SupportedSymbols(); len(symbols) > 0 {
+				appConfig.TradingSymbols = symbols
+				appConfig.StrategySymbol = symbols[0]
+			}
+		}
+		botLogger().Info("replay mode enabled",
+			"file", *replayFile,
+			"events", len(events),
+			"speed", *replaySpeed,
+			"symbols", appConfig.TradingSymbols,
+		)
+		return nil
+	}
+
+	if path := os.Getenv("MARKET_DATA_RECORD_PATH"); path != "" {
+		recorder, err := replay.NewFileRecorder(path)
+		if err != nil {
+			return err
+		}
+		marketDataRecorder = recorder
+		botLogger().Info("market data recording enabled", "path", path)
+	}
+	return nil
+}
+
+// applyMarketDataMode swaps in the replay player or wraps exchanges with the recorder
+func applyMarketDataMode(exchangesMap map[string]exchanges.Exchange) map[string]exchanges.Exchange {
+	if replayPlayer != nil {
+		return map[string]exchanges.Exchange{replayPlayer.Name(): replayPlayer}
+	}
+	if marketDataRecorder == nil {
+		return exchangesMap
+	}
+
+	wrapped := make(map[string]exchanges.Exchange, len(exchangesMap))
+	for name, exchange := range exchangesMap {
+		recording := replay.NewRecordingExchange(exchange, marketDataRecorder)
+		recording.SetErrorCallback(func(err error) {
+			botLogger().Warn("failed to record market data", "exchange", name, "error", err)
+		})
+		wrapped[name] = recording
+	}
+	return wrapped
+}
+
+// runReplay drives the replay player until the recording is exhausted
+func runReplay(ctx context.Context) {
+	if replayPlayer == nil {
+		return
+	}
+	if err := replayPlayer.Run(ctx); err != nil && ctx.Err() == nil {
+		botLogger().Error("replay failed", "error", err)
+		return
+	}
+	botLogger().Info("replay finished", "events", replayPlayer.Replayed())
+}
+
+// closeMarketDataRecorder flushes and closes the recorder if enabled
+func closeMarketDataRecorder() {
+	if marketDataRecorder == nil {
+		return
+	}
+	if err := marketDataRecorder.Close(); err != nil {
+		botLogger().Error("failed to close market data recording", "error", err)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/rotation"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/watchdog"
+)
+
+const (
+	defaultRotationCloseMinutes = 15
+	rotationCheckInterval       = 30 * time.Second
+)
+
+// setupSymbolRotation creates the rotator taking the symbols discovery drops
+// out of trading: with ROTATION_POLICY=block (default) a symbol holding a
+// position keeps trading until it is flat, with ROTATION_POLICY=close its
+// strategy stops and the position is closed gradually over
+// ROTATION_CLOSE_MINUTES (default 15)
+func setupSymbolRotation(
+	orchestrator *strategy.StrategyOrchestrator,
+	orderManager *order.Manager,
+	supervisor *watchdog.Watchdog,
+) (*rotation.Rotator, error) {
+	policy, err := rotation.ParsePolicy(os.Getenv("ROTATION_POLICY"))
+	if err != nil {
+		return nil, err
+	}
+	closeOver := defaultRotationCloseMinutes * time.Minute
+	if value := os.Getenv("ROTATION_CLOSE_MINUTES"); value != "" {
+		if minutes, err := strconv.Atoi(value); err == nil && minutes > 0 {
+			closeOver = time.Duration(minutes) * time.Minute
+		}
+	}
+
+	rotator := rotation.NewRotator(policy, closeOver, orderManager, func(symbol string) error {
+		if err := orchestrator.RemoveSymbol(symbol); err != nil {
+			return err
+		}
+		if supervisor != nil {
+			supervisor.Unregister("strategy:" + symbol)
+		}
+		botLogger().Info("strategy stopped", "symbol", symbol, "reason", "discovery")
+		return nil
+	})
+	botLogger().Info("symbol rotation enabled", "policy", policy, "close_over", closeOver)
+	return rotator, nil
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+
+	"github.com/guyghost/constantine/internal/strategy"
+)
+
+// setupStrategyScheduler runs the updates of the orchestrator's strategies
+// on a shared pool of STRATEGY_WORKERS workers, the number of CPUs by
+// default, rather than a loop per symbol. STRATEGY_WORKERS=0 keeps a loop per
+// symbol and returns nil.
+func setupStrategyScheduler(orchestrator *strategy.StrategyOrchestrator) *strategy.Scheduler {
+	workers := runtime.NumCPU()
+	if value := os.Getenv("STRATEGY_WORKERS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			workers = parsed
+		}
+	}
+	if workers == 0 {
+		return nil
+	}
+
+	scheduler := strategy.NewScheduler(workers)
+	orchestrator.SetScheduler(scheduler)
+	return scheduler
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/guyghost/constantine/internal/dispatch"
+	"github.com/guyghost/constantine/internal/strategy"
+)
+
+// defaultSignalQueueSize is how many signals of a strategy may wait for the
+// execution path
+const defaultSignalQueueSize = 32
+
+var (
+	signalQueueSize   = defaultSignalQueueSize
+	signalQueuePolicy = dispatch.DropOldest
+)
+
+// setupSignalQueues sizes the queues signals wait in for the execution path
+// with SIGNAL_QUEUE_SIZE (0 executes them on the strategy loop) and picks
+// what a full queue drops with SIGNAL_QUEUE_POLICY (drop_oldest, the
+// default, or drop_newest)
+func setupSignalQueues() error {
+	if value := os.Getenv("SIGNAL_QUEUE_SIZE"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			signalQueueSize = parsed
+		}
+	}
+	if value := os.Getenv("SIGNAL_QUEUE_POLICY"); value != "" {
+		policy, err := dispatch.ParsePolicy(value)
+		if err != nil {
+			return err
+		}
+		signalQueuePolicy = policy
+	}
+	return nil
+}
+
+// queueSignals returns a signal callback handing signals to handle through
+// the bounded queue named name, so a slow execution path does not hold up
+// the strategy loop generating them. Exits are never dropped: a full queue
+// drops an entry to make room for them.
+func queueSignals(name string, handle func(*strategy.Signal)) func(*strategy.Signal) {
+	if signalQueueSize == 0 {
+		return handle
+	}
+
+	queue := dispatch.New("signals:"+name, signalQueueSize, signalQueuePolicy, handle)
+	queue.SetKeep(func(signal *strategy.Signal) bool {
+		return signal.Type == strategy.SignalTypeExit
+	})
+	return func(signal *strategy.Signal) {
+		if !queue.Push(signal) {
+			botLogger().Warn("signal queue full, a signal was dropped",
+				"queue", name,
+				"policy", signalQueuePolicy,
+				"dropped", queue.Dropped())
+		}
+	}
+}
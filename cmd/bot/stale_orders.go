@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/order"
+)
+
+// setupOrderSweeper sets how long limit entries may rest before the order
+// manager cancels or reprices them: ORDER_MAX_AGE_SECONDS (0 lets them rest)
+// with ORDER_STALE_ACTION, and ORDER_MAX_AGE_STRATEGIES by strategy
+func setupOrderSweeper(orderManager *order.Manager) {
+	limits := order.OrderAgeLimits{
+		Default: order.OrderAgeLimit{Action: order.StaleActionCancel},
+	}
+	if val := os.Getenv("ORDER_MAX_AGE_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+			limits.Default.MaxAge = time.Duration(parsed) * time.Second
+		}
+	}
+	if val := os.Getenv("ORDER_STALE_ACTION"); val != "" {
+		if action, ok := order.ParseStaleAction(val); ok {
+			limits.Default.Action = action
+		}
+	}
+	if val := os.Getenv("ORDER_MAX_AGE_STRATEGIES"); val != "" {
+		limits.Strategies = order.ParseOrderAgeLimits(val)
+	}
+	if !limits.Enabled() {
+		return
+	}
+
+	orderManager.SetOrderAgeLimits(limits)
+	botLogger().Info("stale order sweeper enabled",
+		"max_age", limits.Default.MaxAge,
+		"action", limits.Default.Action,
+		"strategies", len(limits.Strategies))
+}
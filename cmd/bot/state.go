@@ -0,0 +1,19 @@
+package main
+
+import (
+	"github.com/guyghost/constantine/internal/statefile"
+)
+
+// setupStateEncryption encrypts the persisted state files (risk session,
+// synthetic stops, blocklist) and the lines appended to the logs (trade
+// journal, equity history, audit logs) at rest with STATE_PASSPHRASE, or the
+// content of STATE_KEY_FILE. Plain files left from before are read; state
+// files are encrypted on their next save, logs from their next line.
+func setupStateEncryption() error {
+	enabled, err := statefile.SetPassphraseFromEnv()
+	if err != nil || !enabled {
+		return err
+	}
+	botLogger().Info("state files encrypted at rest")
+	return nil
+}
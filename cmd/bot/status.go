@@ -0,0 +1,69 @@
+package main
+
+import (
+	"github.com/guyghost/constantine/internal/accounting"
+	"github.com/guyghost/constantine/internal/benchmark"
+	"github.com/guyghost/constantine/internal/degradation"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/portfolio"
+)
+
+// pnlLedger attributes the realized PnL of closed positions to strategies
+// and symbols
+var pnlLedger = accounting.NewLedger()
+
+// statusReport is served on the /status endpoint of the telemetry server
+type statusReport struct {
+	Attribution accounting.Snapshot            `json:"attribution"`
+	Allocations []portfolio.StrategyAllocation `json:"allocations,omitempty"`
+	// Benchmarks compares the session's equity with buy and hold, when the
+	// equity history is enabled
+	Benchmarks []benchmark.Comparison `json:"benchmarks,omitempty"`
+	// Degradation is the state of the strategies and symbols that traded,
+	// when the degradation monitor is enabled
+	Degradation []degradation.Status `json:"degradation,omitempty"`
+	// EnsembleAccuracy is the recent accuracy of each strategy voting in the
+	// ensemble, when it is enabled
+	EnsembleAccuracy map[string]float64 `json:"ensemble_accuracy,omitempty"`
+	// ObserveOnly is set while another instance trades the account
+	ObserveOnly bool `json:"observe_only,omitempty"`
+}
+
+// currentStatus builds the status report
+func currentStatus() any {
+	report := statusReport{
+		Attribution: pnlLedger.Snapshot(),
+		Benchmarks:  currentBenchmarks(),
+		ObserveOnly: observeOnly.Load(),
+	}
+	if capitalAllocator != nil {
+		report.Allocations = capitalAllocator.Allocations()
+	}
+	if degradationMonitor != nil {
+		report.Degradation = degradationMonitor.Statuses()
+	}
+	if signalEnsemble != nil {
+		report.EnsembleAccuracy = signalEnsemble.Accuracies()
+	}
+	return report
+}
+
+// recordClosedPosition attributes the PnL of a position to its strategy and
+// symbol once its exit has filled
+func recordClosedPosition(position *order.ManagedPosition) {
+	trade, closed := accounting.TradeFromPosition(position)
+	if !closed {
+		return
+	}
+	pnlLedger.Record(trade)
+	recordDegradation(trade)
+	if capitalAllocator != nil {
+		capitalAllocator.RecordTrade(trade.Symbol, trade.PnL)
+	}
+	if parameterTuner != nil {
+		parameterTuner.RecordTrade(trade.Symbol, trade.PnL)
+	}
+	if signalEnsemble != nil {
+		signalEnsemble.RecordTrade(trade.Symbol, trade.PnL)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/portfolio"
+	"github.com/guyghost/constantine/internal/risk"
+	"github.com/guyghost/constantine/internal/strategy"
+)
+
+const defaultRebalanceInterval = time.Hour
+
+var (
+	// strategyInstances are the engines run alongside the main engine when
+	// STRATEGY_INSTANCES_FILE is configured
+	strategyInstances = map[string]*strategy.IntegratedStrategyEngine{}
+	// capitalAllocator splits the balance across the main engine and the
+	// strategy instances
+	capitalAllocator *portfolio.Allocator
+)
+
+// setupStrategyInstances creates the strategy instances listed in
+// STRATEGY_INSTANCES_FILE and the allocator sharing the balance between them
+// and the main engine
+func setupStrategyInstances(
+	ctx context.Context,
+	mainEngine *strategy.IntegratedStrategyEngine,
+	executionAgent *execution.ExecutionAgent,
+	riskManager *risk.Manager,
+) error {
+	path := os.Getenv("STRATEGY_INSTANCES_FILE")
+	if path == "" {
+		return nil
+	}
+
+	instances, err := strategy.LoadInstances(path, mainEngine.GetConfig())
+	if err != nil {
+		return err
+	}
+
+	weights := make(map[string]float64, len(instances))
+	for _, instance := range instances {
+		weights[instance.Name] = instance.Weight
+	}
+	allocator, err := portfolio.NewAllocator(portfolio.AllocationMode(os.Getenv("ALLOCATION_MODE")), weights)
+	if err != nil {
+		return fmt.Errorf("failed to create capital allocator: %w", err)
+	}
+	allocator.Rebalance(riskManager.GetCurrentBalance())
+	capitalAllocator = allocator
+	executionAgent.SetCapitalAllocator(allocator)
+
+	for _, instance := range instances[1:] {
+		name := instance.Name
+		engine := mainEngine.NewInstanceEngine(instance.Config)
+		engine.SetSignalCallback(queueSignals("strategy:"+name, func(signal *strategy.Signal) {
+			signal.Strategy = name
+			botLogger().Info("integrated strategy signal",
+				"strategy", name,
+				"type", signal.Type,
+				"side", signal.Side,
+				"symbol", signal.Symbol,
+				"price", signal.Price.StringFixed(2),
+				"strength", signal.Strength,
+				"components", signal.Components,
+			)
+			submitSignal(ctx, executionAgent, signal, "strategy", name)
+		}))
+		engine.SetErrorCallback(func(err error) {
+			botLogger().Error("integrated strategy error", "strategy", name, "error", err)
+		})
+		strategyInstances[name] = engine
+	}
+
+	botLogger().Info("multi-strategy trading enabled",
+		"path", path,
+		"strategies", len(instances),
+		"allocation_mode", os.Getenv("ALLOCATION_MODE"))
+	return nil
+}
+
+// runStrategyInstances runs the strategy instances and rebalances their
+// capital every ALLOCATION_REBALANCE_MINUTES until ctx is canceled
+func runStrategyInstances(ctx context.Context, riskManager *risk.Manager) {
+	if capitalAllocator == nil {
+		return
+	}
+
+	for name, engine := range strategyInstances {
+		if err := engine.Start(ctx); err != nil {
+			botLogger().Error("failed to start strategy instance", "strategy", name, "error", err)
+			continue
+		}
+		botLogger().Info("strategy instance started", "strategy", name)
+	}
+
+	interval := defaultRebalanceInterval
+	if value := os.Getenv("ALLOCATION_REBALANCE_MINUTES"); value != "" {
+		if minutes, err := strconv.Atoi(value); err == nil && minutes > 0 {
+			interval = time.Duration(minutes) * time.Minute
+		}
+	}
+	capitalAllocator.Run(ctx, interval, riskManager.GetCurrentBalance)
+
+	for name, engine := range strategyInstances {
+		if err := engine.Stop(); err != nil {
+			botLogger().Error("failed to stop strategy instance", "strategy", name, "error", err)
+		}
+	}
+}
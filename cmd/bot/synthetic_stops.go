@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os"
+
+	"github.com/guyghost/constantine/internal/order"
+)
+
+// setupSyntheticStops persists the stops the order manager fires itself on
+// venues without native stop orders to SYNTHETIC_STOPS_PATH, so they survive
+// restarts
+func setupSyntheticStops(orderManager *order.Manager) error {
+	if orderManager.Capabilities().StopOrders {
+		return nil
+	}
+	path := os.Getenv("SYNTHETIC_STOPS_PATH")
+	if path == "" {
+		botLogger().Warn("exchange has no native stop orders: stop losses are fired by the bot and lost on restart unless SYNTHETIC_STOPS_PATH is set")
+		return nil
+	}
+
+	if err := orderManager.SetSyntheticStopStore(order.NewFileSyntheticStopStore(path)); err != nil {
+		return err
+	}
+	botLogger().Info("synthetic stops enabled",
+		"path", path,
+		"restored", len(orderManager.SyntheticStops()))
+	return nil
+}
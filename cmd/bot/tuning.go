@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/strategy"
+)
+
+// parameterTuner re-fits the strategy parameters of each symbol when
+// STRATEGY_TUNING is set
+var parameterTuner *strategy.Tuner
+
+// setupParameterTuning tunes the stop loss, take profit and EMA periods of
+// the orchestrator's strategies online when STRATEGY_TUNING is set, every
+// STRATEGY_TUNING_INTERVAL (default 15m) with at least
+// STRATEGY_TUNING_COOLDOWN (default 1h) between two changes of a symbol.
+// Each parameter stays within STRATEGY_TUNING_<PARAMETER>_MIN and _MAX.
+func setupParameterTuning(orchestrator *strategy.StrategyOrchestrator) *strategy.Tuner {
+	if !getEnvBool("STRATEGY_TUNING", false) {
+		return nil
+	}
+
+	cfg := strategy.DefaultTuningConfig()
+	if value := os.Getenv("STRATEGY_TUNING_INTERVAL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			cfg.Interval = parsed
+		}
+	}
+	if value := os.Getenv("STRATEGY_TUNING_COOLDOWN"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed >= 0 {
+			cfg.Cooldown = parsed
+		}
+	}
+	loadParameterRange("STRATEGY_TUNING_STOP_LOSS", &cfg.StopLoss)
+	loadParameterRange("STRATEGY_TUNING_TAKE_PROFIT", &cfg.TakeProfit)
+	loadParameterRange("STRATEGY_TUNING_SHORT_EMA", &cfg.ShortEMA)
+	loadParameterRange("STRATEGY_TUNING_LONG_EMA", &cfg.LongEMA)
+
+	parameterTuner = strategy.NewTuner(cfg, orchestrator.GetActiveStrategies)
+	botLogger().Info("strategy parameter tuning enabled",
+		"interval", cfg.Interval,
+		"cooldown", cfg.Cooldown,
+		"stop_loss", cfg.StopLoss,
+		"take_profit", cfg.TakeProfit,
+		"short_ema", cfg.ShortEMA,
+		"long_ema", cfg.LongEMA)
+	return parameterTuner
+}
+
+// loadParameterRange overrides the bounds of r from the _MIN and _MAX
+// variables of prefix, keeping them when the result would be empty
+func loadParameterRange(prefix string, r *strategy.ParameterRange) {
+	bounds := *r
+	if value := os.Getenv(prefix + "_MIN"); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed > 0 {
+			bounds.Min = parsed
+		}
+	}
+	if value := os.Getenv(prefix + "_MAX"); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil && parsed > 0 {
+			bounds.Max = parsed
+		}
+	}
+	if bounds.Min <= bounds.Max {
+		*r = bounds
+	}
+}
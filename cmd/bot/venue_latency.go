@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+)
+
+// setupVenueLatency tracks the round-trip latency of orders by exchange and
+// avoids the exchanges whose p95 over ORDER_LATENCY_WINDOW_MINUTES (default 5)
+// exceeds ORDER_MAX_P95_LATENCY_MS (0 disables): the order manager refuses new
+// entries on them and the multiplexer routes entries to another exchange
+func setupVenueLatency(orderManager *order.Manager, multiplexer *exchanges.ExchangeMultiplexer) {
+	var maxP95 time.Duration
+	if val := os.Getenv("ORDER_MAX_P95_LATENCY_MS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+			maxP95 = time.Duration(parsed) * time.Millisecond
+		}
+	}
+	if maxP95 == 0 {
+		return
+	}
+	window := 5 * time.Minute
+	if val := os.Getenv("ORDER_LATENCY_WINDOW_MINUTES"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed > 0 {
+			window = time.Duration(parsed) * time.Minute
+		}
+	}
+
+	tracker := exchanges.NewLatencyTracker(maxP95, window)
+	orderManager.SetLatencyTracker(tracker)
+	multiplexer.SetLatencyTracker(tracker)
+	botLogger().Info("venue latency limit enabled", "max_p95", maxP95, "window", window)
+}
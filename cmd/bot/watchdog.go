@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/replay"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/watchdog"
+)
+
+const (
+	defaultWatchdogInterval = 30 * time.Second
+	// orderLoopTimeout is how long the order manager's 1s monitor loop may
+	// go without completing a pass
+	orderLoopTimeout = time.Minute
+	// candleTimeout is how long a strategy may go without a 1m candle
+	candleTimeout = 3 * time.Minute
+	// defaultStreamTimeout is how long a websocket may stay silent; streams
+	// carrying tickers and trades are rarely quiet for more than seconds
+	defaultStreamTimeout = 2 * time.Minute
+)
+
+// newWatchdog supervises the order manager loop, the candles processed by
+// every strategy and the websocket of every exchange that reports its
+// message activity. Restarted components run until ctx is canceled.
+func newWatchdog(
+	ctx context.Context,
+	exchangesMap map[string]exchanges.Exchange,
+	strategyOrchestrator *strategy.StrategyOrchestrator,
+	orderManager *order.Manager,
+) *watchdog.Watchdog {
+	w := watchdog.New()
+
+	w.Register(watchdog.Component{
+		Name:     "order_manager",
+		Timeout:  orderLoopTimeout,
+		LastBeat: orderManager.LastLoopAt,
+		Restart: func(context.Context) error {
+			if err := orderManager.Stop(); err != nil {
+				return err
+			}
+			return orderManager.Start(ctx)
+		},
+	})
+
+	for symbol, strategyInstance := range strategyOrchestrator.GetActiveStrategies() {
+		watchStrategy(ctx, w, symbol, strategyInstance)
+	}
+
+	streamTimeout := defaultStreamTimeout
+	if value := os.Getenv("WATCHDOG_STREAM_TIMEOUT_SECONDS"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			streamTimeout = time.Duration(seconds) * time.Second
+		}
+	}
+	for name, exchange := range exchangesMap {
+		if recording, ok := exchange.(*replay.RecordingExchange); ok {
+			exchange = recording.Exchange
+		}
+		monitor, ok := exchange.(exchanges.StreamMonitor)
+		if !ok {
+			continue
+		}
+		w.Register(watchdog.Component{
+			Name:     "stream:" + name,
+			Timeout:  streamTimeout,
+			LastBeat: monitor.LastMessageAt,
+			Active: func() bool {
+				return exchange.IsConnected() && monitor.Streaming()
+			},
+			Restart: monitor.ReconnectStream,
+		})
+	}
+
+	w.SetInterventionCallback(func(intervention watchdog.Intervention) {
+		if intervention.Err != nil {
+			botLogger().Error("watchdog failed to restart stuck component",
+				"component", intervention.Component,
+				"silence", intervention.Silence.Round(time.Second),
+				"error", intervention.Err)
+			return
+		}
+		botLogger().Warn("watchdog restarted stuck component",
+			"component", intervention.Component,
+			"silence", intervention.Silence.Round(time.Second))
+	})
+	return w
+}
+
+// watchStrategy supervises the candles processed by the strategy of symbol,
+// restarting it to run until ctx is canceled
+func watchStrategy(ctx context.Context, w *watchdog.Watchdog, symbol string, strategyInstance *strategy.ScalpingStrategy) {
+	w.Register(watchdog.Component{
+		Name:     "strategy:" + symbol,
+		Timeout:  candleTimeout,
+		LastBeat: strategyInstance.LastCandleAt,
+		Active:   strategyInstance.IsRunning,
+		Restart: func(context.Context) error {
+			if err := strategyInstance.Stop(); err != nil {
+				return err
+			}
+			return strategyInstance.Start(ctx)
+		},
+	})
+}
+
+// runWatchdog checks component liveness every WATCHDOG_INTERVAL_SECONDS
+// until ctx is canceled
+func runWatchdog(ctx context.Context, w *watchdog.Watchdog) {
+	interval := defaultWatchdogInterval
+	if value := os.Getenv("WATCHDOG_INTERVAL_SECONDS"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+			interval = time.Duration(seconds) * time.Second
+		}
+	}
+	w.Run(ctx, interval)
+}
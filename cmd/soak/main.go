@@ -0,0 +1,244 @@
+// Command soak runs the trading pipeline against simulated high frequency
+// market data for hours, sampling memory, goroutines and callback latencies,
+// and fails when they grow without bound or exceed their budgets.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/exchanges/chaos"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/risk"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+)
+
+// soakChaos are the fault rates injected with -chaos, low enough for the
+// pipeline to keep trading
+var soakChaos = chaos.Config{
+	TimeoutRate:     0.01,
+	ServerErrorRate: 0.01,
+	DuplicateRate:   0.01,
+	OutOfOrderRate:  0.05,
+	StalePriceRate:  0.01,
+}
+
+func main() {
+	duration := flag.Duration("duration", 4*time.Hour, "How long to run")
+	symbolList := flag.String("symbols", "BTC-USD,ETH-USD,SOL-USD", "Comma-separated symbols to simulate")
+	rate := flag.Int("rate", 50, "Market data updates per second and symbol")
+	volatility := flag.Float64("volatility", 0.0005, "Standard deviation of the log return per update")
+	seed := flag.Int64("seed", 1, "Seed of the simulated market and injected faults")
+	injectChaos := flag.Bool("chaos", false, "Inject timeouts, server errors, duplicates, out of order updates and stale prices")
+	sampleInterval := flag.Duration("sample-interval", 30*time.Second, "How often memory, goroutines and latencies are sampled")
+	warmup := flag.Duration("warmup", 5*time.Minute, "Time left out of the growth analysis while histories fill")
+	maxHeapGrowth := flag.Int("max-heap-growth-mb", 64, "Largest live heap growth allowed, in MiB")
+	maxGoroutineGrowth := flag.Int("max-goroutine-growth", 20, "Largest goroutine count growth allowed")
+	maxCallbackP99 := flag.Duration("max-callback-p99", 50*time.Millisecond, "Highest 99th percentile callback latency allowed")
+	logLevel := flag.String("log-level", "warn", "Pipeline log level: debug, info, warn or error")
+	flag.Parse()
+
+	var symbols []string
+	for _, symbol := range strings.Split(*symbolList, ",") {
+		if symbol = strings.TrimSpace(symbol); symbol != "" {
+			symbols = append(symbols, symbol)
+		}
+	}
+	if len(symbols) == 0 || *rate <= 0 {
+		log.Fatal("Need at least one symbol and a positive -rate")
+	}
+	if *sampleInterval <= 0 {
+		log.Fatal("-sample-interval must be positive")
+	}
+
+	level := slog.LevelWarn
+	if err := level.UnmarshalText([]byte(*logLevel)); err != nil {
+		log.Fatalf("Invalid -log-level: %v", err)
+	}
+	loggerConfig := logger.DefaultConfig()
+	loggerConfig.Level = level
+	loggerConfig.Format = "text"
+	logger.SetDefault(logger.New(loggerConfig))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+
+	mkt := newMarket(symbols, 100, *volatility, *seed)
+	var exchange exchanges.Exchange = mkt
+	var chaotic *chaos.Exchange
+	if *injectChaos {
+		config := soakChaos
+		config.Seed = *seed
+		chaotic = chaos.New(mkt, config)
+		exchange = chaotic
+	}
+	if err := exchange.Connect(ctx); err != nil {
+		log.Fatalf("Failed to connect the simulated market: %v", err)
+	}
+
+	timings := newLatencies()
+	var signals, orderUpdates, trades atomic.Int64
+
+	orderManager := order.NewManager(exchange)
+	riskManager := risk.NewManager(risk.LoadConfig(), decimal.NewFromInt(100000))
+	agent := execution.NewExecutionAgent(orderManager, riskManager, execution.LoadConfig())
+
+	orderManager.SetOrderUpdateCallback(func(update *order.OrderUpdate) {
+		orderUpdates.Add(1)
+	})
+	orderManager.SetPositionUpdateCallback(func(position *order.ManagedPosition) {
+		timings.Time("position", func() {
+			if position.Status != order.PositionStatusClosed {
+				return
+			}
+			trades.Add(1)
+			riskManager.RecordTrade(risk.TradeResult{
+				Timestamp:  time.Now(),
+				Symbol:     position.Symbol,
+				EntryPrice: position.EntryPrice,
+				ExitPrice:  position.CurrentPrice,
+				PnL:        position.RealizedPnL,
+				IsWin:      position.RealizedPnL.IsPositive(),
+				Strategy:   position.Strategy,
+			})
+		})
+	})
+	if err := orderManager.Start(ctx); err != nil {
+		log.Fatalf("Failed to start the order manager: %v", err)
+	}
+
+	strategies := make([]*strategy.ScalpingStrategy, 0, len(symbols))
+	for _, symbol := range symbols {
+		s := strategy.NewScalpingStrategy(strategy.DefaultConfig().ForSymbol(symbol), exchange)
+		s.SetSignalCallback(func(signal *strategy.Signal) {
+			signals.Add(1)
+			timings.Time("signal", func() {
+				_ = agent.HandleSignal(ctx, signal)
+			})
+		})
+		if err := s.Start(ctx); err != nil {
+			log.Fatalf("Failed to start the strategy for %s: %v", symbol, err)
+		}
+		strategies = append(strategies, s)
+	}
+
+	log.Printf("Soak running for %s: %d symbols at %d updates/s each, chaos %t", *duration, len(symbols), *rate, *injectChaos)
+
+	go feed(ctx, mkt, symbols, *rate, timings)
+
+	start := time.Now()
+	samples := []sample{takeSample()}
+	sampler := time.NewTicker(*sampleInterval)
+	for running := true; running; {
+		select {
+		case <-ctx.Done():
+			running = false
+		case <-sampler.C:
+			timings.CloseWindow()
+			s := takeSample()
+			samples = append(samples, s)
+			log.Printf("%s heap=%s goroutines=%d signals=%d order_updates=%d trades=%d open_orders=%d positions=%d",
+				time.Since(start).Round(time.Second), formatBytes(int64(s.HeapAlloc)), s.Goroutines,
+				signals.Load(), orderUpdates.Load(), trades.Load(), mkt.OpenOrders(), len(orderManager.GetPositions()))
+		}
+	}
+	sampler.Stop()
+	timings.CloseWindow()
+
+	for _, s := range strategies {
+		_ = s.Stop()
+	}
+	_ = orderManager.Stop()
+
+	lim := limits{
+		Warmup:             *warmup,
+		MaxHeapGrowth:      uint64(*maxHeapGrowth) << 20,
+		MaxGoroutineGrowth: *maxGoroutineGrowth,
+		MaxCallbackP99:     *maxCallbackP99,
+	}
+	stats := timings.Stats()
+	report(samples, stats, lim, chaotic)
+
+	violations := evaluate(samples, stats, lim)
+	if len(violations) > 0 {
+		fmt.Println("\nFAIL")
+		for _, violation := range violations {
+			fmt.Println("  " + violation)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("\nPASS")
+}
+
+// feed publishes a market data update per symbol rate times a second until
+// ctx is done, timing the subscriber callbacks
+func feed(ctx context.Context, mkt *market, symbols []string, rate int, timings *latencies) {
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, symbol := range symbols {
+				timings.Time("market_data", func() { mkt.Tick(symbol, now) })
+			}
+		}
+	}
+}
+
+// report prints the growth and latencies measured
+func report(samples []sample, stats map[string]latencyStats, lim limits, chaotic *chaos.Exchange) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	first, last := samples[0], samples[len(samples)-1]
+	fmt.Fprintf(w, "\nDuration\t%s\n", last.At.Sub(first.At).Round(time.Second))
+	fmt.Fprintf(w, "Samples\t%d\n", len(samples))
+	fmt.Fprintf(w, "Live heap\t%s -> %s\n", formatBytes(int64(first.HeapAlloc)), formatBytes(int64(last.HeapAlloc)))
+	fmt.Fprintf(w, "Goroutines\t%d -> %d\n", first.Goroutines, last.Goroutines)
+	if heap, goroutines, ok := growth(samples, lim.Warmup); ok {
+		fmt.Fprintf(w, "Growth after warmup\t%s, %d goroutines\n", formatBytes(heap), goroutines)
+	}
+
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "\nCALLBACK\tCOUNT\tLATEST P99\tWORST P99\tMAX")
+	for _, name := range names {
+		s := stats[name]
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", name, s.Count, s.LatestP99, s.WorstP99, s.Max)
+	}
+
+	if chaotic != nil {
+		faults := chaotic.Faults()
+		kinds := make([]string, 0, len(faults))
+		for fault := range faults {
+			kinds = append(kinds, string(fault))
+		}
+		sort.Strings(kinds)
+		fmt.Fprintln(w, "\nFAULT\tCOUNT")
+		for _, kind := range kinds {
+			fmt.Fprintf(w, "%s\t%d\n", kind, faults[chaos.Fault(kind)])
+		}
+	}
+}
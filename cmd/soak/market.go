@@ -0,0 +1,366 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/replay"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// bookLevels is the number of levels generated per side
+	bookLevels = 10
+	// keptOrders is the number of finished orders kept for lookups, so the
+	// simulation itself does not grow over a long run
+	keptOrders = 1000
+)
+
+// market simulates a venue: a random walk per symbol streamed through a
+// replay player, and orders filled against it. Market data goes through the
+// player's subscriptions, orders are matched here.
+type market struct {
+	*replay.Player
+
+	mu         sync.Mutex
+	rng        *rand.Rand
+	volatility float64 // Standard deviation of the log return per tick
+	prices     map[string]float64
+	candles    map[string]*exchanges.Candle // Current one minute candle
+	open       map[string]*exchanges.Order
+	finished   map[string]*exchanges.Order
+	fifo       []string // Finished order IDs, oldest first
+	positions  map[string]*netPosition
+	nextID     int
+}
+
+// netPosition is the signed filled amount of a symbol, positive when long,
+// and its average entry price
+type netPosition struct {
+	amount decimal.Decimal
+	entry  decimal.Decimal
+}
+
+// add applies a signed fill of qty at price
+func (p *netPosition) add(qty, price decimal.Decimal) {
+	total := p.amount.Add(qty)
+	switch {
+	case total.IsZero():
+		p.entry = decimal.Zero
+	case p.amount.IsZero() || total.Sign() != p.amount.Sign():
+		// Opened or flipped at price
+		p.entry = price
+	case qty.Sign() == p.amount.Sign():
+		p.entry = p.entry.Mul(p.amount).Add(price.Mul(qty)).Div(total)
+	}
+	p.amount = total
+}
+
+func newMarket(symbols []string, basePrice, volatility float64, seed int64) *market {
+	m := &market{
+		Player: replay.NewPlayer(nil, replay.Config{
+			InitialBalance: decimal.NewFromInt(100000),
+			Name:           "soak",
+		}),
+		rng:        rand.New(rand.NewSource(seed)),
+		volatility: volatility,
+		prices:     make(map[string]float64, len(symbols)),
+		candles:    make(map[string]*exchanges.Candle, len(symbols)),
+		open:       make(map[string]*exchanges.Order),
+		finished:   make(map[string]*exchanges.Order),
+		positions:  make(map[string]*netPosition),
+	}
+	for _, symbol := range symbols {
+		m.prices[symbol] = basePrice
+	}
+	return m
+}
+
+// Tick moves the price of symbol, publishes its ticker, book and candle and
+// fills the orders the new price reaches
+func (m *market) Tick(symbol string, now time.Time) {
+	m.mu.Lock()
+	price := m.prices[symbol] * math.Exp(m.volatility*m.rng.NormFloat64())
+	m.prices[symbol] = price
+	last := decimal.NewFromFloat(price).Round(2)
+	spread := decimal.NewFromFloat(price * 0.0001).Round(2)
+	if !spread.IsPositive() {
+		spread = decimal.NewFromFloat(0.01)
+	}
+	volume := decimal.NewFromFloat(m.rng.Float64()).Round(4)
+	candle := m.updateCandle(symbol, now, last, volume)
+	m.match(symbol, last)
+	m.mu.Unlock()
+
+	bid, ask := last.Sub(spread), last.Add(spread)
+	m.Publish(replay.Event{
+		Time: now, Kind: replay.EventTicker, Symbol: symbol,
+		Ticker: &exchanges.Ticker{Symbol: symbol, Bid: bid, Ask: ask, Last: last, Timestamp: now},
+	})
+	m.Publish(replay.Event{
+		Time: now, Kind: replay.EventOrderBook, Symbol: symbol,
+		OrderBook: m.book(symbol, bid, ask, spread, now),
+	})
+	m.Publish(replay.Event{
+		Time: now, Kind: replay.EventCandle, Symbol: symbol, Interval: "1m",
+		Candle: &candle,
+	})
+}
+
+// updateCandle adds a trade to the current one minute candle of symbol,
+// starting a new one each minute. Must be called with the lock held.
+func (m *market) updateCandle(symbol string, now time.Time, price, volume decimal.Decimal) exchanges.Candle {
+	start := now.Truncate(time.Minute)
+	candle, exists := m.candles[symbol]
+	if !exists || !candle.Timestamp.Equal(start) {
+		candle = &exchanges.Candle{
+			Symbol: symbol, Timestamp: start,
+			Open: price, High: price, Low: price, Close: price,
+		}
+		m.candles[symbol] = candle
+	}
+	candle.High = decimal.Max(candle.High, price)
+	candle.Low = decimal.Min(candle.Low, price)
+	candle.Close = price
+	candle.Volume = candle.Volume.Add(volume)
+	return *candle
+}
+
+// book builds a book around the spread with random amounts
+func (m *market) book(symbol string, bid, ask, step decimal.Decimal, now time.Time) *exchanges.OrderBook {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	book := &exchanges.OrderBook{
+		Symbol:    symbol,
+		Bids:      make([]exchanges.Level, bookLevels),
+		Asks:      make([]exchanges.Level, bookLevels),
+		Timestamp: now,
+	}
+	for i := 0; i < bookLevels; i++ {
+		offset := step.Mul(decimal.NewFromInt(int64(i)))
+		book.Bids[i] = exchanges.Level{Price: bid.Sub(offset), Amount: decimal.NewFromFloat(0.1 + 2*m.rng.Float64()).Round(4)}
+		book.Asks[i] = exchanges.Level{Price: ask.Add(offset), Amount: decimal.NewFromFloat(0.1 + 2*m.rng.Float64()).Round(4)}
+	}
+	return book
+}
+
+// match fills the open orders of symbol reached by price. Must be called
+// with the lock held.
+func (m *market) match(symbol string, price decimal.Decimal) {
+	for id, order := range m.open {
+		if order.Symbol != symbol {
+			continue
+		}
+		if reached(order, price) {
+			m.fill(order, price)
+			m.finish(id)
+		}
+	}
+}
+
+// reached reports whether price triggers a stop order or reaches a limit
+func reached(order *exchanges.Order, price decimal.Decimal) bool {
+	buy := order.Side == exchanges.OrderSideBuy
+	switch order.Type {
+	case exchanges.OrderTypeMarket:
+		return true
+	case exchanges.OrderTypeStopLimit:
+		return (buy && price.GreaterThanOrEqual(order.StopPrice)) || (!buy && price.LessThanOrEqual(order.StopPrice))
+	default:
+		return (buy && price.LessThanOrEqual(order.Price)) || (!buy && price.GreaterThanOrEqual(order.Price))
+	}
+}
+
+// fill fills the remaining amount of order at price. Must be called with the
+// lock held.
+func (m *market) fill(order *exchanges.Order, price decimal.Decimal) {
+	qty := order.Amount.Sub(order.Filled)
+	order.Filled = order.Amount
+	order.FilledAmount = order.Amount
+	order.Remaining = decimal.Zero
+	order.AveragePrice = price
+	order.Status = exchanges.OrderStatusFilled
+	order.UpdatedAt = time.Now()
+	position, exists := m.positions[order.Symbol]
+	if !exists {
+		position = &netPosition{}
+		m.positions[order.Symbol] = position
+	}
+	if order.Side == exchanges.OrderSideSell {
+		qty = qty.Neg()
+	}
+	position.add(qty, price)
+}
+
+// finish moves an order from the open orders to the bounded finished ones.
+// Must be called with the lock held.
+func (m *market) finish(id string) {
+	order, exists := m.open[id]
+	if !exists {
+		return
+	}
+	delete(m.open, id)
+	m.finished[id] = order
+	m.fifo = append(m.fifo, id)
+	if len(m.fifo) > keptOrders {
+		delete(m.finished, m.fifo[0])
+		m.fifo = m.fifo[1:]
+	}
+}
+
+// OpenOrders returns the number of resting orders
+func (m *market) OpenOrders() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.open)
+}
+
+func (m *market) PlaceOrder(ctx context.Context, order *exchanges.Order) (*exchanges.Order, error) {
+	if order == nil {
+		return nil, exchanges.ErrInvalidOrder
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	price, exists := m.prices[order.Symbol]
+	if !exists {
+		return nil, fmt.Errorf("%w: unknown symbol %s", exchanges.ErrInvalidOrder, order.Symbol)
+	}
+	last := decimal.NewFromFloat(price).Round(2)
+	if order.PostOnly && order.Type == exchanges.OrderTypeLimit && reached(order, last) {
+		return nil, exchanges.NewError("soak", exchanges.ErrorCodeInvalidOrder, "post only order would take liquidity", nil)
+	}
+
+	m.nextID++
+	placed := *order
+	placed.ID = fmt.Sprintf("soak-%d", m.nextID)
+	placed.Status = exchanges.OrderStatusOpen
+	placed.Remaining = placed.Amount
+	placed.CreatedAt = time.Now()
+	placed.UpdatedAt = placed.CreatedAt
+	m.open[placed.ID] = &placed
+	if reached(&placed, last) && placed.Type != exchanges.OrderTypeStopLimit {
+		m.fill(&placed, last)
+		m.finish(placed.ID)
+	}
+	result := placed
+	return &result, nil
+}
+
+func (m *market) CancelOrder(ctx context.Context, orderID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	order, exists := m.open[orderID]
+	if !exists {
+		if _, done := m.finished[orderID]; done {
+			return fmt.Errorf("order %s is no longer open", orderID)
+		}
+		return exchanges.ErrOrderNotFound
+	}
+	order.Status = exchanges.OrderStatusCanceled
+	order.UpdatedAt = time.Now()
+	m.finish(orderID)
+	return nil
+}
+
+func (m *market) GetOrder(ctx context.Context, orderID string) (*exchanges.Order, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	order, exists := m.open[orderID]
+	if !exists {
+		order, exists = m.finished[orderID]
+	}
+	if !exists {
+		return nil, exchanges.ErrOrderNotFound
+	}
+	copied := *order
+	return &copied, nil
+}
+
+func (m *market) GetOpenOrders(ctx context.Context, symbol string) ([]exchanges.Order, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var orders []exchanges.Order
+	for _, order := range m.open {
+		if symbol == "" || order.Symbol == symbol {
+			orders = append(orders, *order)
+		}
+	}
+	return orders, nil
+}
+
+func (m *market) GetOrderHistory(ctx context.Context, symbol string, limit int) ([]exchanges.Order, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var orders []exchanges.Order
+	for _, id := range m.fifo {
+		if order := m.finished[id]; symbol == "" || order.Symbol == symbol {
+			orders = append(orders, *order)
+		}
+	}
+	if limit > 0 && len(orders) > limit {
+		orders = orders[len(orders)-limit:]
+	}
+	return orders, nil
+}
+
+func (m *market) GetPositions(ctx context.Context) ([]exchanges.Position, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var positions []exchanges.Position
+	for symbol, net := range m.positions {
+		if net.amount.IsZero() {
+			continue
+		}
+		side := exchanges.OrderSideBuy
+		if net.amount.IsNegative() {
+			side = exchanges.OrderSideSell
+		}
+		mark := decimal.NewFromFloat(m.prices[symbol]).Round(2)
+		pnl := mark.Sub(net.entry).Mul(net.amount)
+		positions = append(positions, exchanges.Position{
+			Symbol:        symbol,
+			Side:          side,
+			Size:          net.amount.Abs(),
+			EntryPrice:    net.entry,
+			MarkPrice:     mark,
+			UnrealizedPnL: pnl,
+		})
+	}
+	return positions, nil
+}
+
+func (m *market) GetPosition(ctx context.Context, symbol string) (*exchanges.Position, error) {
+	positions, _ := m.GetPositions(ctx)
+	for _, position := range positions {
+		if position.Symbol == symbol {
+			return &position, nil
+		}
+	}
+	return nil, exchanges.ErrPositionNotFound
+}
+
+func (m *market) SupportedSymbols() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	symbols := make([]string, 0, len(m.prices))
+	for symbol := range m.prices {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+func (m *market) Capabilities() exchanges.Capabilities {
+	return exchanges.Capabilities{
+		Shorts:     true,
+		StopOrders: true,
+		PostOnly:   true,
+	}
+}
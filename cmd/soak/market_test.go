@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func TestMarket_FillsAndBoundsHistory(t *testing.T) {
+	ctx := context.Background()
+	m := newMarket([]string{"BTC-USD"}, 100, 0.001, 1)
+
+	for i := 0; i < keptOrders+10; i++ {
+		placed, err := m.PlaceOrder(ctx, &exchanges.Order{
+			Symbol: "BTC-USD",
+			Side:   exchanges.OrderSideBuy,
+			Type:   exchanges.OrderTypeMarket,
+			Amount: decimal.NewFromInt(1),
+		})
+		if err != nil {
+			t.Fatalf("PlaceOrder: %v", err)
+		}
+		if placed.Status != exchanges.OrderStatusFilled {
+			t.Fatalf("expected market order to fill, got %s", placed.Status)
+		}
+	}
+	if len(m.finished) != keptOrders || len(m.fifo) != keptOrders {
+		t.Fatalf("expected %d finished orders kept, got %d", keptOrders, len(m.finished))
+	}
+
+	position, err := m.GetPosition(ctx, "BTC-USD")
+	if err != nil {
+		t.Fatalf("GetPosition: %v", err)
+	}
+	if !position.Size.Equal(decimal.NewFromInt(keptOrders+10)) || !position.EntryPrice.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("unexpected position %s at %s", position.Size, position.EntryPrice)
+	}
+}
+
+func TestMarket_RestingLimitFillsOnTick(t *testing.T) {
+	ctx := context.Background()
+	m := newMarket([]string{"BTC-USD"}, 100, 0.01, 1)
+
+	placed, err := m.PlaceOrder(ctx, &exchanges.Order{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeLimit,
+		Price:  decimal.NewFromInt(99),
+		Amount: decimal.NewFromInt(1),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	if placed.Status != exchanges.OrderStatusOpen {
+		t.Fatalf("expected the limit order to rest, got %s", placed.Status)
+	}
+
+	for i := 0; i < 10000 && m.OpenOrders() > 0; i++ {
+		m.Tick("BTC-USD", time.Now())
+	}
+	order, err := m.GetOrder(ctx, placed.ID)
+	if err != nil {
+		t.Fatalf("GetOrder: %v", err)
+	}
+	if order.Status != exchanges.OrderStatusFilled || !order.AveragePrice.LessThanOrEqual(decimal.NewFromInt(99)) {
+		t.Fatalf("expected a fill at 99 or below, got %s at %s", order.Status, order.AveragePrice)
+	}
+}
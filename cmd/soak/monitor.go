@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// sample is a snapshot of the process taken during the soak
+type sample struct {
+	At         time.Time
+	HeapAlloc  uint64 // Live heap after a garbage collection
+	Goroutines int
+}
+
+// takeSample collects garbage first so the heap holds only live objects
+func takeSample() sample {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return sample{
+		At:         time.Now(),
+		HeapAlloc:  stats.HeapAlloc,
+		Goroutines: runtime.NumGoroutine(),
+	}
+}
+
+// maxLatencySamples bounds the latencies kept per callback and window; more
+// are sampled uniformly
+const maxLatencySamples = 10000
+
+// latencyStats summarizes the latencies of a callback
+type latencyStats struct {
+	Count     int64
+	Max       time.Duration
+	WorstP99  time.Duration // Highest 99th percentile of a sampling window
+	LatestP99 time.Duration
+}
+
+// latencies records how long callbacks take, per callback name, in windows
+// closed at every sample
+type latencies struct {
+	mu     sync.Mutex
+	rng    *rand.Rand
+	window map[string]*latencyWindow
+	stats  map[string]*latencyStats
+}
+
+type latencyWindow struct {
+	seen      int
+	durations []time.Duration
+}
+
+func newLatencies() *latencies {
+	return &latencies{
+		rng:    rand.New(rand.NewSource(1)),
+		window: make(map[string]*latencyWindow),
+		stats:  make(map[string]*latencyStats),
+	}
+}
+
+// Time runs fn and records its duration under name
+func (l *latencies) Time(name string, fn func()) {
+	start := time.Now()
+	fn()
+	l.Record(name, time.Since(start))
+}
+
+// Record adds a duration of callback name
+func (l *latencies) Record(name string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats, exists := l.stats[name]
+	if !exists {
+		stats = &latencyStats{}
+		l.stats[name] = stats
+	}
+	stats.Count++
+	if d > stats.Max {
+		stats.Max = d
+	}
+
+	window, exists := l.window[name]
+	if !exists {
+		window = &latencyWindow{}
+		l.window[name] = window
+	}
+	window.seen++
+	if len(window.durations) < maxLatencySamples {
+		window.durations = append(window.durations, d)
+	} else if i := l.rng.Intn(window.seen); i < maxLatencySamples {
+		// Reservoir sampling keeps a uniform sample of the window
+		window.durations[i] = d
+	}
+}
+
+// CloseWindow computes the 99th percentiles of the current window and starts
+// a new one
+func (l *latencies) CloseWindow() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for name, window := range l.window {
+		if len(window.durations) == 0 {
+			continue
+		}
+		p99 := percentile(window.durations, 0.99)
+		stats := l.stats[name]
+		stats.LatestP99 = p99
+		if p99 > stats.WorstP99 {
+			stats.WorstP99 = p99
+		}
+		window.seen = 0
+		window.durations = window.durations[:0]
+	}
+}
+
+// Stats returns the latency statistics by callback name
+func (l *latencies) Stats() map[string]latencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := make(map[string]latencyStats, len(l.stats))
+	for name, s := range l.stats {
+		stats[name] = *s
+	}
+	return stats
+}
+
+// percentile returns the p quantile of durations, sorting them
+func percentile(durations []time.Duration, p float64) time.Duration {
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	i := int(float64(len(durations)-1) * p)
+	return durations[i]
+}
+
+// limits are the thresholds a soak run must stay within
+type limits struct {
+	// Warmup is left out of the analysis while caches and histories fill
+	Warmup time.Duration
+	// MaxHeapGrowth is the largest increase of the live heap, in bytes,
+	// between the start and the end of the run
+	MaxHeapGrowth uint64
+	// MaxGoroutineGrowth is the largest increase of the goroutine count
+	MaxGoroutineGrowth int
+	// MaxCallbackP99 is the highest 99th percentile latency allowed for any
+	// callback window
+	MaxCallbackP99 time.Duration
+}
+
+// growth compares the first and last quarters of the samples taken after the
+// warmup, using medians so a single spike is not mistaken for a trend. It
+// returns false when there are too few samples.
+func growth(samples []sample, warmup time.Duration) (heap int64, goroutines int, ok bool) {
+	if len(samples) == 0 {
+		return 0, 0, false
+	}
+	start := samples[0].At.Add(warmup)
+	var steady []sample
+	for _, s := range samples {
+		if !s.At.Before(start) {
+			steady = append(steady, s)
+		}
+	}
+	quarter := len(steady) / 4
+	if quarter < 1 {
+		return 0, 0, false
+	}
+	first, last := steady[:quarter], steady[len(steady)-quarter:]
+
+	heapMedian := func(samples []sample) int64 {
+		values := make([]int64, len(samples))
+		for i, s := range samples {
+			values[i] = int64(s.HeapAlloc)
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+		return values[len(values)/2]
+	}
+	goroutineMedian := func(samples []sample) int {
+		values := make([]int, len(samples))
+		for i, s := range samples {
+			values[i] = s.Goroutines
+		}
+		sort.Ints(values)
+		return values[len(values)/2]
+	}
+	return heapMedian(last) - heapMedian(first), goroutineMedian(last) - goroutineMedian(first), true
+}
+
+// evaluate returns the limits the run exceeded
+func evaluate(samples []sample, stats map[string]latencyStats, limits limits) []string {
+	var violations []string
+	heap, goroutines, ok := growth(samples, limits.Warmup)
+	if !ok {
+		violations = append(violations, fmt.Sprintf("too few samples after the %s warmup to detect growth; run longer", limits.Warmup))
+	}
+	if ok && heap > int64(limits.MaxHeapGrowth) {
+		violations = append(violations, fmt.Sprintf("live heap grew by %s, above %s", formatBytes(heap), formatBytes(int64(limits.MaxHeapGrowth))))
+	}
+	if ok && goroutines > limits.MaxGoroutineGrowth {
+		violations = append(violations, fmt.Sprintf("goroutines grew by %d, above %d", goroutines, limits.MaxGoroutineGrowth))
+	}
+
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if p99 := stats[name].WorstP99; limits.MaxCallbackP99 > 0 && p99 > limits.MaxCallbackP99 {
+			violations = append(violations, fmt.Sprintf("%s callbacks took %s at the 99th percentile, above %s", name, p99, limits.MaxCallbackP99))
+		}
+	}
+	return violations
+}
+
+// formatBytes formats n bytes in MiB
+func formatBytes(n int64) string {
+	return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+}
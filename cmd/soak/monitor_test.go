@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func samplesWithHeap(heaps ...uint64) []sample {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := make([]sample, len(heaps))
+	for i, heap := range heaps {
+		samples[i] = sample{At: start.Add(time.Duration(i) * time.Minute), HeapAlloc: heap << 20, Goroutines: 10}
+	}
+	return samples
+}
+
+func TestEvaluate_SteadyRunPasses(t *testing.T) {
+	samples := samplesWithHeap(50, 10, 11, 10, 12, 11, 10, 11, 10)
+	lim := limits{Warmup: time.Minute, MaxHeapGrowth: 8 << 20, MaxGoroutineGrowth: 5, MaxCallbackP99: 10 * time.Millisecond}
+	stats := map[string]latencyStats{"market_data": {Count: 100, WorstP99: time.Millisecond}}
+
+	if violations := evaluate(samples, stats, lim); len(violations) != 0 {
+		t.Fatalf("expected no violations, got %v", violations)
+	}
+}
+
+func TestEvaluate_DetectsGrowth(t *testing.T) {
+	samples := samplesWithHeap(10, 10, 20, 30, 40, 50, 60, 70, 80)
+	for i := range samples {
+		samples[i].Goroutines = 10 + 5*i
+	}
+	lim := limits{Warmup: time.Minute, MaxHeapGrowth: 8 << 20, MaxGoroutineGrowth: 5, MaxCallbackP99: 10 * time.Millisecond}
+	stats := map[string]latencyStats{"signal": {Count: 10, WorstP99: 20 * time.Millisecond}}
+
+	violations := strings.Join(evaluate(samples, stats, lim), "\n")
+	for _, want := range []string{"live heap grew", "goroutines grew", "signal callbacks took"} {
+		if !strings.Contains(violations, want) {
+			t.Errorf("expected a violation containing %q, got:\n%s", want, violations)
+		}
+	}
+}
+
+func TestEvaluate_TooShort(t *testing.T) {
+	violations := evaluate(samplesWithHeap(10, 10), nil, limits{Warmup: time.Hour})
+	if len(violations) != 1 || !strings.Contains(violations[0], "too few samples") {
+		t.Fatalf("expected a single too few samples violation, got %v", violations)
+	}
+}
+
+func TestLatencies_WindowPercentiles(t *testing.T) {
+	l := newLatencies()
+	for i := 1; i <= 100; i++ {
+		l.Record("market_data", time.Duration(i)*time.Millisecond)
+	}
+	l.CloseWindow()
+	l.Record("market_data", time.Millisecond)
+	l.CloseWindow()
+
+	stats := l.Stats()["market_data"]
+	if stats.Count != 101 || stats.Max != 100*time.Millisecond {
+		t.Fatalf("unexpected count %d or max %s", stats.Count, stats.Max)
+	}
+	if stats.WorstP99 != 99*time.Millisecond || stats.LatestP99 != time.Millisecond {
+		t.Fatalf("expected worst p99 99ms and latest 1ms, got %s and %s", stats.WorstP99, stats.LatestP99)
+	}
+}
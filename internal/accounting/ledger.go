@@ -0,0 +1,198 @@
+// Package accounting attributes realized PnL to the strategies and symbols
+// that produced it.
+package accounting
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+)
+
+// maxTrades bounds the trades kept in memory for inspection
+const maxTrades = 1000
+
+// Trade is a closed position attributed to a strategy
+type Trade struct {
+	Strategy string             `json:"strategy"`
+	Symbol   string             `json:"symbol"`
+	Side     order.PositionSide `json:"side"`
+	PnL      decimal.Decimal    `json:"pnl"`
+	OpenedAt time.Time          `json:"opened_at"`
+	ClosedAt time.Time          `json:"closed_at"`
+	// Liquidation is set when the venue liquidated or auto-deleveraged the
+	// position
+	Liquidation exchanges.LiquidationKind `json:"liquidation,omitempty"`
+}
+
+// TradeFromPosition builds the trade of a position once its exit has filled.
+// Positions opened without a strategy are attributed to the main instance.
+func TradeFromPosition(position *order.ManagedPosition) (Trade, bool) {
+	if position.Status != order.PositionStatusClosed || position.Amount.IsPositive() {
+		return Trade{}, false
+	}
+	trade := Trade{
+		Strategy:    position.Strategy,
+		Symbol:      position.Symbol,
+		Side:        position.Side,
+		PnL:         position.RealizedPnL,
+		OpenedAt:    position.EntryTime,
+		ClosedAt:    time.Now(),
+		Liquidation: position.Liquidation,
+	}
+	if trade.Strategy == "" {
+		trade.Strategy = strategy.MainInstance
+	}
+	if position.ExitTime != nil {
+		trade.ClosedAt = *position.ExitTime
+	}
+	return trade, true
+}
+
+// Attribution is the realized performance of a strategy, a symbol or the
+// whole account
+type Attribution struct {
+	Key         string          `json:"key"`
+	Trades      int             `json:"trades"`
+	Wins        int             `json:"wins"`
+	Losses      int             `json:"losses"`
+	PnL         decimal.Decimal `json:"pnl"`
+	GrossProfit decimal.Decimal `json:"gross_profit"`
+	GrossLoss   decimal.Decimal `json:"gross_loss"` // Positive sum of the losses
+}
+
+// WinRate returns the percentage of winning trades
+func (a Attribution) WinRate() float64 {
+	if a.Trades == 0 {
+		return 0
+	}
+	return float64(a.Wins) / float64(a.Trades) * 100
+}
+
+// ProfitFactor returns the gross profit over the gross loss, or 0 without
+// losses
+func (a Attribution) ProfitFactor() float64 {
+	if !a.GrossLoss.IsPositive() {
+		return 0
+	}
+	return a.GrossProfit.Div(a.GrossLoss).InexactFloat64()
+}
+
+func (a *Attribution) add(pnl decimal.Decimal) {
+	a.Trades++
+	a.PnL = a.PnL.Add(pnl)
+	switch {
+	case pnl.IsPositive():
+		a.Wins++
+		a.GrossProfit = a.GrossProfit.Add(pnl)
+	case pnl.IsNegative():
+		a.Losses++
+		a.GrossLoss = a.GrossLoss.Sub(pnl)
+	}
+}
+
+// Snapshot is the attribution of every strategy and symbol at a point in time
+type Snapshot struct {
+	Total      Attribution   `json:"total"`
+	Strategies []Attribution `json:"strategies"`
+	Symbols    []Attribution `json:"symbols"`
+}
+
+// Ledger keeps the realized PnL attribution of closed trades
+type Ledger struct {
+	mu         sync.RWMutex
+	total      Attribution
+	strategies map[string]*Attribution
+	symbols    map[string]*Attribution
+	trades     []Trade
+}
+
+// NewLedger creates an empty ledger
+func NewLedger() *Ledger {
+	return &Ledger{
+		total:      Attribution{Key: "total"},
+		strategies: make(map[string]*Attribution),
+		symbols:    make(map[string]*Attribution),
+	}
+}
+
+// Record attributes a closed trade to its strategy and symbol
+func (l *Ledger) Record(trade Trade) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.total.add(trade.PnL)
+	attribution(l.strategies, trade.Strategy).add(trade.PnL)
+	attribution(l.symbols, trade.Symbol).add(trade.PnL)
+
+	l.trades = append(l.trades, trade)
+	if len(l.trades) > maxTrades {
+		l.trades = l.trades[len(l.trades)-maxTrades:]
+	}
+}
+
+func attribution(ledger map[string]*Attribution, key string) *Attribution {
+	a, ok := ledger[key]
+	if !ok {
+		a = &Attribution{Key: key}
+		ledger[key] = a
+	}
+	return a
+}
+
+// Total returns the attribution of every trade
+func (l *Ledger) Total() Attribution {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.total
+}
+
+// ByStrategy returns the attribution of each strategy, best PnL first
+func (l *Ledger) ByStrategy() []Attribution {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return sorted(l.strategies)
+}
+
+// BySymbol returns the attribution of each symbol, best PnL first
+func (l *Ledger) BySymbol() []Attribution {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return sorted(l.symbols)
+}
+
+// Trades returns the most recent trades, oldest first
+func (l *Ledger) Trades() []Trade {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]Trade(nil), l.trades...)
+}
+
+// Snapshot returns the current attribution
+func (l *Ledger) Snapshot() Snapshot {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return Snapshot{
+		Total:      l.total,
+		Strategies: sorted(l.strategies),
+		Symbols:    sorted(l.symbols),
+	}
+}
+
+func sorted(ledger map[string]*Attribution) []Attribution {
+	attributions := make([]Attribution, 0, len(ledger))
+	for _, a := range ledger {
+		attributions = append(attributions, *a)
+	}
+	sort.Slice(attributions, func(i, j int) bool {
+		if !attributions[i].PnL.Equal(attributions[j].PnL) {
+			return attributions[i].PnL.GreaterThan(attributions[j].PnL)
+		}
+		return attributions[i].Key < attributions[j].Key
+	})
+	return attributions
+}
@@ -0,0 +1,85 @@
+package accounting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+)
+
+func TestTradeFromPosition(t *testing.T) {
+	exit := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	position := &order.ManagedPosition{
+		Symbol:      "BTC-USD",
+		Side:        order.PositionSideLong,
+		Amount:      decimal.NewFromInt(1),
+		RealizedPnL: decimal.NewFromInt(25),
+		Status:      order.PositionStatusClosed,
+		ExitTime:    &exit,
+	}
+
+	if _, ok := TradeFromPosition(position); ok {
+		t.Error("a position closing but not yet filled is not a trade")
+	}
+
+	position.Amount = decimal.Zero
+	trade, ok := TradeFromPosition(position)
+	if !ok {
+		t.Fatal("expected a trade for a filled exit")
+	}
+	if trade.Strategy != strategy.MainInstance {
+		t.Errorf("positions without a strategy belong to the main instance, got %q", trade.Strategy)
+	}
+	if !trade.PnL.Equal(decimal.NewFromInt(25)) || !trade.ClosedAt.Equal(exit) {
+		t.Errorf("unexpected trade: %+v", trade)
+	}
+	if trade.Liquidation != "" {
+		t.Errorf("a position closed by the bot is not liquidated, got %q", trade.Liquidation)
+	}
+
+	position.Liquidation = exchanges.LiquidationKindDeleveraged
+	if trade, _ = TradeFromPosition(position); trade.Liquidation != exchanges.LiquidationKindDeleveraged {
+		t.Errorf("expected the trade to be marked deleveraged, got %q", trade.Liquidation)
+	}
+}
+
+func TestLedger_Attribution(t *testing.T) {
+	ledger := NewLedger()
+	ledger.Record(Trade{Strategy: "main", Symbol: "BTC-USD", PnL: decimal.NewFromInt(30)})
+	ledger.Record(Trade{Strategy: "main", Symbol: "ETH-USD", PnL: decimal.NewFromInt(-10)})
+	ledger.Record(Trade{Strategy: "fast", Symbol: "BTC-USD", PnL: decimal.NewFromInt(50)})
+	ledger.Record(Trade{Strategy: "fast", Symbol: "BTC-USD", PnL: decimal.Zero})
+
+	total := ledger.Total()
+	if total.Trades != 4 || !total.PnL.Equal(decimal.NewFromInt(70)) {
+		t.Errorf("unexpected total: %+v", total)
+	}
+
+	strategies := ledger.ByStrategy()
+	if len(strategies) != 2 || strategies[0].Key != "fast" {
+		t.Fatalf("strategies should be sorted by PnL: %+v", strategies)
+	}
+	fast, main := strategies[0], strategies[1]
+	if fast.Trades != 2 || fast.Wins != 1 || fast.Losses != 0 || fast.WinRate() != 50 {
+		t.Errorf("unexpected fast attribution: %+v", fast)
+	}
+	if !main.GrossProfit.Equal(decimal.NewFromInt(30)) || !main.GrossLoss.Equal(decimal.NewFromInt(10)) || main.ProfitFactor() != 3 {
+		t.Errorf("unexpected main attribution: %+v", main)
+	}
+
+	symbols := ledger.BySymbol()
+	if len(symbols) != 2 || symbols[0].Key != "BTC-USD" || !symbols[0].PnL.Equal(decimal.NewFromInt(80)) {
+		t.Errorf("unexpected symbol attribution: %+v", symbols)
+	}
+
+	snapshot := ledger.Snapshot()
+	if len(snapshot.Strategies) != 2 || len(snapshot.Symbols) != 2 || snapshot.Total.Trades != 4 {
+		t.Errorf("unexpected snapshot: %+v", snapshot)
+	}
+	if len(ledger.Trades()) != 4 {
+		t.Errorf("expected 4 trades, got %d", len(ledger.Trades()))
+	}
+}
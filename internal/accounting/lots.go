@@ -0,0 +1,288 @@
+package accounting
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// longTermHolding is the holding period beyond which a gain is long-term
+const longTermHolding = 365 * 24 * time.Hour
+
+// LotMethod selects the open lots a disposal is matched against
+type LotMethod string
+
+const (
+	FIFO LotMethod = "fifo" // Oldest lot first
+	LIFO LotMethod = "lifo" // Newest lot first
+	// HIFO matches the lot realizing the smallest gain first: the highest
+	// cost for long lots, the lowest proceeds for short ones
+	HIFO LotMethod = "hifo"
+)
+
+// ParseLotMethod parses a lot matching method name
+func ParseLotMethod(name string) (LotMethod, error) {
+	switch method := LotMethod(strings.ToLower(name)); method {
+	case FIFO, LIFO, HIFO:
+		return method, nil
+	}
+	return "", fmt.Errorf("unknown lot method %q, expected fifo, lifo or hifo", name)
+}
+
+// Fill is a buy or sell of an asset on an exchange
+type Fill struct {
+	Time     time.Time
+	Exchange string
+	Symbol   string
+	Side     exchanges.OrderSide
+	Price    decimal.Decimal
+	Amount   decimal.Decimal
+	Fee      decimal.Decimal // In the quote currency
+}
+
+// FillFromExecution builds the fill of an executed order
+func FillFromExecution(execution order.Execution) Fill {
+	return Fill{
+		Time:     execution.Time,
+		Exchange: execution.Exchange,
+		Symbol:   execution.Symbol,
+		Side:     execution.Side,
+		Price:    execution.FillPrice,
+		Amount:   execution.Amount,
+		Fee:      execution.Fee,
+	}
+}
+
+// unitValue is the price of the fill net of its fee: the cost of a unit
+// bought, the proceeds of a unit sold
+func (f Fill) unitValue() decimal.Decimal {
+	fee := f.Fee.Div(f.Amount)
+	if f.Side == exchanges.OrderSideBuy {
+		return f.Price.Add(fee)
+	}
+	return f.Price.Sub(fee)
+}
+
+// Lot is an open quantity of an asset, bought for a long lot or sold short
+// for a short one
+type Lot struct {
+	Asset    string          `json:"asset"`
+	Exchange string          `json:"exchange"`
+	Short    bool            `json:"short"`
+	Opened   time.Time       `json:"opened"`
+	Amount   decimal.Decimal `json:"amount"`
+	Price    decimal.Decimal `json:"price"` // Unit cost, or unit proceeds of a short lot
+}
+
+// Gain is the realized gain of a lot, or part of one, closed by a fill
+type Gain struct {
+	Asset     string          `json:"asset"`
+	Short     bool            `json:"short"`
+	Amount    decimal.Decimal `json:"amount"`
+	Acquired  time.Time       `json:"acquired"` // When the lot was opened
+	Disposed  time.Time       `json:"disposed"` // When the lot was closed
+	Proceeds  decimal.Decimal `json:"proceeds"`
+	CostBasis decimal.Decimal `json:"cost_basis"`
+	Gain      decimal.Decimal `json:"gain"`
+	// LongTerm is set for long lots held more than a year; short sales are
+	// short-term
+	LongTerm bool `json:"long_term"`
+	// Exchanges the lot was opened and closed on
+	OpenedOn string `json:"opened_on"`
+	ClosedOn string `json:"closed_on"`
+}
+
+// LotTracker matches the fills of each asset across exchanges into lots and
+// realized gains. Prices of an asset are taken to be in one currency, whatever
+// the quote of its symbols.
+type LotTracker struct {
+	method LotMethod
+	lots   map[string][]*Lot
+	gains  []Gain
+}
+
+// NewLotTracker creates a tracker matching disposals with method
+func NewLotTracker(method LotMethod) *LotTracker {
+	return &LotTracker{
+		method: method,
+		lots:   make(map[string][]*Lot),
+	}
+}
+
+// Add closes the open lots of the opposite side with fill, per the lot
+// method, and opens a lot with the rest. Fills must be added in time order.
+func (t *LotTracker) Add(fill Fill) {
+	if !fill.Amount.IsPositive() {
+		return
+	}
+	asset := BaseAsset(fill.Symbol)
+	short := fill.Side == exchanges.OrderSideSell
+	value := fill.unitValue()
+	remaining := fill.Amount
+
+	for remaining.IsPositive() {
+		lot := t.match(asset, !short)
+		if lot == nil {
+			break
+		}
+		closed := decimal.Min(remaining, lot.Amount)
+		t.gains = append(t.gains, newGain(lot, fill, closed, value))
+		lot.Amount = lot.Amount.Sub(closed)
+		remaining = remaining.Sub(closed)
+		if !lot.Amount.IsPositive() {
+			t.remove(asset, lot)
+		}
+	}
+
+	if remaining.IsPositive() {
+		t.lots[asset] = append(t.lots[asset], &Lot{
+			Asset:    asset,
+			Exchange: fill.Exchange,
+			Short:    short,
+			Opened:   fill.Time,
+			Amount:   remaining,
+			Price:    value,
+		})
+	}
+}
+
+// match returns the open lot of asset on the short or long side to close
+// next, nil when there is none
+func (t *LotTracker) match(asset string, short bool) *Lot {
+	var best *Lot
+	for _, lot := range t.lots[asset] {
+		if lot.Short != short {
+			continue
+		}
+		if best == nil {
+			best = lot
+			continue
+		}
+		switch t.method {
+		case LIFO:
+			if !lot.Opened.Before(best.Opened) {
+				best = lot
+			}
+		case HIFO:
+			if (!short && lot.Price.GreaterThan(best.Price)) || (short && lot.Price.LessThan(best.Price)) {
+				best = lot
+			}
+		}
+	}
+	return best
+}
+
+func (t *LotTracker) remove(asset string, closed *Lot) {
+	lots := t.lots[asset]
+	for i, lot := range lots {
+		if lot == closed {
+			t.lots[asset] = append(lots[:i], lots[i+1:]...)
+			return
+		}
+	}
+}
+
+// newGain realizes amount of lot closed by fill at the unit value
+func newGain(lot *Lot, fill Fill, amount, value decimal.Decimal) Gain {
+	gain := Gain{
+		Asset:    lot.Asset,
+		Short:    lot.Short,
+		Amount:   amount,
+		Acquired: lot.Opened,
+		Disposed: fill.Time,
+		OpenedOn: lot.Exchange,
+		ClosedOn: fill.Exchange,
+	}
+	if lot.Short {
+		gain.Proceeds = lot.Price.Mul(amount)
+		gain.CostBasis = value.Mul(amount)
+	} else {
+		gain.Proceeds = value.Mul(amount)
+		gain.CostBasis = lot.Price.Mul(amount)
+		gain.LongTerm = fill.Time.Sub(lot.Opened) > longTermHolding
+	}
+	gain.Gain = gain.Proceeds.Sub(gain.CostBasis)
+	return gain
+}
+
+// Gains returns the gains realized so far, in the order they were realized
+func (t *LotTracker) Gains() []Gain {
+	return append([]Gain(nil), t.gains...)
+}
+
+// OpenLots returns the lots still open, ordered by asset then opening time
+func (t *LotTracker) OpenLots() []Lot {
+	var lots []Lot
+	for _, open := range t.lots {
+		for _, lot := range open {
+			lots = append(lots, *lot)
+		}
+	}
+	sort.SliceStable(lots, func(i, j int) bool {
+		if lots[i].Asset != lots[j].Asset {
+			return lots[i].Asset < lots[j].Asset
+		}
+		return lots[i].Opened.Before(lots[j].Opened)
+	})
+	return lots
+}
+
+// GainsInYear returns the gains disposed of during year, in UTC
+func GainsInYear(gains []Gain, year int) []Gain {
+	var inYear []Gain
+	for _, gain := range gains {
+		if gain.Disposed.UTC().Year() == year {
+			inYear = append(inYear, gain)
+		}
+	}
+	return inYear
+}
+
+// BaseAsset returns the base asset of a symbol such as BTC-USD or ETH/EUR
+func BaseAsset(symbol string) string {
+	if i := strings.IndexAny(symbol, "-/"); i > 0 {
+		return strings.ToUpper(symbol[:i])
+	}
+	return strings.ToUpper(symbol)
+}
+
+// WriteGainsCSV writes gains in the layout of Form 8949, which tax tools
+// import as a generic capital gains CSV: description, dates acquired and
+// sold (MM/DD/YYYY), proceeds, cost basis, gain and term
+func WriteGainsCSV(w io.Writer, gains []Gain) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"Description", "Date Acquired", "Date Sold", "Proceeds", "Cost Basis", "Gain or Loss", "Term"}); err != nil {
+		return err
+	}
+	for _, gain := range gains {
+		description := gain.Amount.String() + " " + gain.Asset
+		if gain.Short {
+			description += " (short sale)"
+		}
+		term := "Short"
+		if gain.LongTerm {
+			term = "Long"
+		}
+		record := []string{
+			description,
+			gain.Acquired.UTC().Format("01/02/2006"),
+			gain.Disposed.UTC().Format("01/02/2006"),
+			gain.Proceeds.StringFixed(2),
+			gain.CostBasis.StringFixed(2),
+			gain.Gain.StringFixed(2),
+			term,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
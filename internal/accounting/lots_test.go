@@ -0,0 +1,126 @@
+package accounting
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func fill(day int, exchange string, side exchanges.OrderSide, price, amount float64) Fill {
+	return Fill{
+		Time:     time.Date(2024, 1, day, 12, 0, 0, 0, time.UTC),
+		Exchange: exchange,
+		Symbol:   "BTC-USD",
+		Side:     side,
+		Price:    decimal.NewFromFloat(price),
+		Amount:   decimal.NewFromFloat(amount),
+	}
+}
+
+func TestLotTracker_Methods(t *testing.T) {
+	fills := []Fill{
+		fill(1, "coinbase", exchanges.OrderSideBuy, 100, 1),
+		fill(2, "dydx", exchanges.OrderSideBuy, 300, 1),
+		fill(3, "dydx", exchanges.OrderSideBuy, 200, 1),
+		// Sold on another exchange than the one the lots were bought on
+		fill(4, "coinbase", exchanges.OrderSideSell, 250, 1.5),
+	}
+
+	for method, want := range map[LotMethod][]string{
+		FIFO: {"150", "-25"}, // 100 then half of 300
+		LIFO: {"50", "-25"},  // 200 then half of 300
+		HIFO: {"-50", "25"},  // 300 then half of 200
+	} {
+		tracker := NewLotTracker(method)
+		for _, f := range fills {
+			tracker.Add(f)
+		}
+		gains := tracker.Gains()
+		if len(gains) != 2 {
+			t.Fatalf("%s: expected 2 gains, got %d", method, len(gains))
+		}
+		for i, gain := range gains {
+			if gain.Gain.String() != want[i] {
+				t.Errorf("%s: gain %d: expected %s, got %s", method, i, want[i], gain.Gain)
+			}
+		}
+		if gains[0].ClosedOn != "coinbase" {
+			t.Errorf("%s: expected the lot closed on coinbase, got %s", method, gains[0].ClosedOn)
+		}
+
+		open := tracker.OpenLots()
+		remaining := decimal.Zero
+		for _, lot := range open {
+			remaining = remaining.Add(lot.Amount)
+		}
+		if !remaining.Equal(decimal.NewFromFloat(1.5)) {
+			t.Errorf("%s: expected 1.5 BTC left open, got %s", method, remaining)
+		}
+	}
+}
+
+func TestLotTracker_ShortsAndFees(t *testing.T) {
+	tracker := NewLotTracker(FIFO)
+	// A sale without a long lot opens a short one, the buy covers it and
+	// opens a long lot with the rest
+	short := fill(1, "dydx", exchanges.OrderSideSell, 200, 1)
+	short.Fee = decimal.NewFromInt(2)
+	cover := fill(2, "dydx", exchanges.OrderSideBuy, 150, 2)
+	cover.Fee = decimal.NewFromInt(4)
+	tracker.Add(short)
+	tracker.Add(cover)
+
+	gains := tracker.Gains()
+	if len(gains) != 1 || !gains[0].Short || gains[0].LongTerm {
+		t.Fatalf("expected one short-term short sale, got %+v", gains)
+	}
+	// Proceeds 200 - 2, cost 150 + 2 per unit
+	if !gains[0].Proceeds.Equal(decimal.NewFromInt(198)) || !gains[0].CostBasis.Equal(decimal.NewFromInt(152)) {
+		t.Errorf("expected fees in the proceeds and cost basis, got %s and %s", gains[0].Proceeds, gains[0].CostBasis)
+	}
+	open := tracker.OpenLots()
+	if len(open) != 1 || open[0].Short || !open[0].Amount.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("expected a long lot of 1 left, got %+v", open)
+	}
+
+	// Held more than a year
+	sale := fill(1, "dydx", exchanges.OrderSideSell, 400, 1)
+	sale.Time = sale.Time.AddDate(1, 1, 0)
+	tracker.Add(sale)
+	gains = tracker.Gains()
+	if !gains[1].LongTerm {
+		t.Error("expected a long-term gain after a year")
+	}
+	if len(GainsInYear(gains, 2024)) != 1 || len(GainsInYear(gains, 2025)) != 1 {
+		t.Error("expected gains split by the year they were disposed of")
+	}
+}
+
+func TestWriteGainsCSV(t *testing.T) {
+	tracker := NewLotTracker(FIFO)
+	tracker.Add(fill(1, "coinbase", exchanges.OrderSideBuy, 100, 0.5))
+	tracker.Add(fill(2, "coinbase", exchanges.OrderSideSell, 120, 0.5))
+
+	var buf bytes.Buffer
+	if err := WriteGainsCSV(&buf, tracker.Gains()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a header and a row, got %q", buf.String())
+	}
+	if want := "0.5 BTC,01/01/2024,01/02/2024,60.00,50.00,10.00,Short"; lines[1] != want {
+		t.Errorf("expected %q, got %q", want, lines[1])
+	}
+
+	if _, err := ParseLotMethod("HIFO"); err != nil {
+		t.Errorf("expected method names to be case insensitive, got %v", err)
+	}
+	if _, err := ParseLotMethod("average"); err == nil {
+		t.Error("expected unknown methods to be rejected")
+	}
+}
@@ -0,0 +1,351 @@
+// Package apiauth authenticates the clients of the control API with API keys
+// or TLS client certificates, authorizes their requests by role and reports
+// every control action for auditing.
+package apiauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Role is what a client may do with the control API. Each role grants the
+// ones below it.
+type Role int
+
+const (
+	// RoleNone grants nothing
+	RoleNone Role = iota
+	// RoleRead reads the state of the bot
+	RoleRead
+	// RoleTrade also controls trading: halts, proposals, heartbeats
+	RoleTrade
+	// RoleAdmin also changes the safety configuration: blocklist, degradation
+	RoleAdmin
+)
+
+// String returns the name of the role
+func (r Role) String() string {
+	switch r {
+	case RoleRead:
+		return "read"
+	case RoleTrade:
+		return "trade"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// ParseRole parses a role name: read, trade or admin
+func ParseRole(value string) (Role, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "read", "read-only", "readonly":
+		return RoleRead, nil
+	case "trade", "trade-control":
+		return RoleTrade, nil
+	case "admin":
+		return RoleAdmin, nil
+	default:
+		return RoleNone, fmt.Errorf("unknown role %q", value)
+	}
+}
+
+// Principal is an authenticated client
+type Principal struct {
+	Name string
+	Role Role
+}
+
+// Action is a control request, allowed or not
+type Action struct {
+	Time       time.Time `json:"time"`
+	Principal  string    `json:"principal,omitempty"`
+	Role       string    `json:"role"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Query      string    `json:"query,omitempty"`
+	RemoteAddr string    `json:"remote_addr"`
+	Status     int       `json:"status"`
+	Allowed    bool      `json:"allowed"`
+}
+
+type apiKey struct {
+	name string
+	role Role
+	hash [sha256.Size]byte
+}
+
+// Authenticator guards the control API. Reads (GET and HEAD) need the read
+// role, other methods the trade role and admin paths the admin role. Public
+// paths, such as health probes, are served to anyone.
+type Authenticator struct {
+	mu      sync.RWMutex
+	keys    []apiKey
+	clients map[string]Role // By client certificate common name
+	public  map[string]bool
+	admin   map[string]bool
+	audit   func(Action)
+	now     func() time.Time
+}
+
+// New creates an authenticator without clients, which denies every request
+// but those to public paths
+func New() *Authenticator {
+	return &Authenticator{
+		clients: make(map[string]Role),
+		public:  make(map[string]bool),
+		admin:   make(map[string]bool),
+		now:     time.Now,
+	}
+}
+
+// AddKey grants role to the clients presenting key, identified as name in the
+// audit. Only the hash of the key is kept.
+func (a *Authenticator) AddKey(name string, role Role, key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys = append(a.keys, apiKey{name: name, role: role, hash: sha256.Sum256([]byte(key))})
+}
+
+// AddKeys adds the keys of a comma separated list of name:role:key
+func (a *Authenticator) AddKeys(spec string) error {
+	for i, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			// Never echo the entry, it may hold a key
+			return fmt.Errorf("invalid API key entry %d, expected name:role:key", i+1)
+		}
+		role, err := ParseRole(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid API key %s: %w", parts[0], err)
+		}
+		a.AddKey(parts[0], role, parts[2])
+	}
+	return nil
+}
+
+// AddClient grants role to the clients presenting a verified certificate
+// issued to commonName
+func (a *Authenticator) AddClient(commonName string, role Role) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.clients[commonName] = role
+}
+
+// AddClients adds the clients of a comma separated list of commonName:role
+func (a *Authenticator) AddClients(spec string) error {
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		commonName, roleName, ok := strings.Cut(entry, ":")
+		if !ok || commonName == "" {
+			return fmt.Errorf("invalid client %q, expected commonName:role", entry)
+		}
+		role, err := ParseRole(roleName)
+		if err != nil {
+			return fmt.Errorf("invalid client %s: %w", commonName, err)
+		}
+		a.AddClient(commonName, role)
+	}
+	return nil
+}
+
+// HasClients reports whether any key or client certificate is granted a role
+func (a *Authenticator) HasClients() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.keys) > 0 || len(a.clients) > 0
+}
+
+// SetPublicPaths serves paths without authentication
+func (a *Authenticator) SetPublicPaths(paths ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, path := range paths {
+		a.public[path] = true
+	}
+}
+
+// SetAdminPaths requires the admin role for paths, whatever the method
+func (a *Authenticator) SetAdminPaths(paths ...string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, path := range paths {
+		a.admin[path] = true
+	}
+}
+
+// SetAuditCallback sets the function called with every control action, that
+// is every request needing more than the read role
+func (a *Authenticator) SetAuditCallback(callback func(Action)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.audit = callback
+}
+
+// RequiredRole returns the role needed to serve r
+func (a *Authenticator) RequiredRole(r *http.Request) Role {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	switch {
+	case a.public[r.URL.Path]:
+		return RoleNone
+	case a.admin[r.URL.Path]:
+		return RoleAdmin
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return RoleRead
+	default:
+		return RoleTrade
+	}
+}
+
+// Authenticate returns the client of r, identified by the API key given as a
+// bearer token or X-API-Key header, or else by its verified client certificate
+func (a *Authenticator) Authenticate(r *http.Request) (Principal, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if key := requestKey(r); key != "" {
+		hash := sha256.Sum256([]byte(key))
+		found := -1
+		// Compare with every key so the time taken tells nothing
+		for i, k := range a.keys {
+			if subtle.ConstantTimeCompare(hash[:], k.hash[:]) == 1 {
+				found = i
+			}
+		}
+		if found < 0 {
+			return Principal{}, false
+		}
+		return Principal{Name: a.keys[found].name, Role: a.keys[found].role}, true
+	}
+
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.PeerCertificates) > 0 {
+		commonName := r.TLS.PeerCertificates[0].Subject.CommonName
+		if role, ok := a.clients[commonName]; ok {
+			return Principal{Name: "cert:" + commonName, Role: role}, true
+		}
+	}
+	return Principal{}, false
+}
+
+// requestKey returns the API key given with r, if any
+func requestKey(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+// Middleware serves the requests next is allowed to, failing the others with
+// 401 when the client is unknown and 403 when its role is insufficient.
+// Without keys nor clients the API fails closed: reads are served to an
+// anonymous client, control actions are refused with 403 and still reported
+// to the audit callback.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		required := a.RequiredRole(r)
+		if required == RoleNone {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, ok := a.Authenticate(r)
+		if !a.HasClients() {
+			principal, ok = Principal{Name: "anonymous", Role: RoleRead}, true
+		}
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		switch {
+		case !ok:
+			w.Header().Set("WWW-Authenticate", `Bearer realm="constantine"`)
+			http.Error(recorder, "unauthorized", http.StatusUnauthorized)
+		case principal.Role < required:
+			http.Error(recorder, fmt.Sprintf("%s role required", required), http.StatusForbidden)
+		default:
+			next.ServeHTTP(recorder, r)
+		}
+
+		if required > RoleRead {
+			a.record(r, principal, ok && principal.Role >= required, recorder.status)
+		}
+	})
+}
+
+// record reports a control action to the audit callback
+func (a *Authenticator) record(r *http.Request, principal Principal, allowed bool, status int) {
+	a.mu.RLock()
+	audit := a.audit
+	a.mu.RUnlock()
+	if audit == nil {
+		return
+	}
+	audit(Action{
+		Time:       a.now(),
+		Principal:  principal.Name,
+		Role:       principal.Role.String(),
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Query:      r.URL.RawQuery,
+		RemoteAddr: r.RemoteAddr,
+		Status:     status,
+		Allowed:    allowed,
+	})
+}
+
+// statusRecorder remembers the status code written to a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// LoadTLSConfig returns the server TLS configuration serving the certificate
+// and key at certFile and keyFile. With clientCAFile, client certificates
+// issued by its authorities are verified when presented, so clients may
+// authenticate with them instead of an API key.
+func LoadTLSConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if clientCAFile == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificate found in client CA %s", clientCAFile)
+	}
+	config.ClientCAs = pool
+	// Health probes and API key clients present no certificate
+	config.ClientAuth = tls.VerifyClientCertIfGiven
+	return config, nil
+}
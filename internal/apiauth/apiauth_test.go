@@ -0,0 +1,122 @@
+package apiauth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/testutils"
+)
+
+func newTestAuthenticator(t *testing.T) (*Authenticator, *[]Action) {
+	auth := New()
+	testutils.AssertNoError(t, auth.AddKeys("grafana:read:read-key, desk:trade:trade-key,ops:admin:admin-key"), "AddKeys should not return error")
+	auth.SetPublicPaths("/healthz")
+	auth.SetAdminPaths("/blocklist/block")
+	var actions []Action
+	auth.SetAuditCallback(func(action Action) {
+		actions = append(actions, action)
+	})
+	return auth, &actions
+}
+
+func serve(auth *Authenticator, method, path, key string) int {
+	handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	req := httptest.NewRequest(method, path, nil)
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestAuthenticator_Roles(t *testing.T) {
+	auth, actions := newTestAuthenticator(t)
+
+	testutils.AssertEqual(t, http.StatusNoContent, serve(auth, http.MethodGet, "/healthz", ""), "public paths need no key")
+	testutils.AssertEqual(t, http.StatusUnauthorized, serve(auth, http.MethodGet, "/status", ""), "other paths need a key")
+	testutils.AssertEqual(t, http.StatusUnauthorized, serve(auth, http.MethodGet, "/status", "unknown"), "unknown keys are refused")
+
+	testutils.AssertEqual(t, http.StatusNoContent, serve(auth, http.MethodGet, "/status", "read-key"), "read role reads")
+	testutils.AssertEqual(t, http.StatusForbidden, serve(auth, http.MethodPost, "/halts/halt", "read-key"), "read role cannot control trading")
+	testutils.AssertEqual(t, http.StatusNoContent, serve(auth, http.MethodPost, "/halts/halt", "trade-key"), "trade role controls trading")
+	testutils.AssertEqual(t, http.StatusForbidden, serve(auth, http.MethodPost, "/blocklist/block", "trade-key"), "trade role cannot administer")
+	testutils.AssertEqual(t, http.StatusNoContent, serve(auth, http.MethodPost, "/blocklist/block", "admin-key"), "admin role administers")
+	testutils.AssertEqual(t, http.StatusNoContent, serve(auth, http.MethodGet, "/status", "admin-key"), "admin role reads")
+
+	// Every control request is audited, reads are not
+	testutils.AssertEqual(t, 4, len(*actions), "control actions should be audited")
+	denied := (*actions)[0]
+	testutils.AssertEqual(t, "grafana", denied.Principal, "principal should be audited")
+	testutils.AssertEqual(t, "/halts/halt", denied.Path, "path should be audited")
+	testutils.AssertEqual(t, http.StatusForbidden, denied.Status, "status should be audited")
+	testutils.AssertFalse(t, denied.Allowed, "denied actions should be audited")
+	allowed := (*actions)[3]
+	testutils.AssertEqual(t, "ops", allowed.Principal, "principal should be audited")
+	testutils.AssertEqual(t, "admin", allowed.Role, "role should be audited")
+	testutils.AssertTrue(t, allowed.Allowed, "allowed actions should be audited")
+}
+
+func TestAuthenticator_WithoutClientsRefusesControlActions(t *testing.T) {
+	auth := New()
+	auth.SetAdminPaths("/blocklist/unblock")
+	var actions []Action
+	auth.SetAuditCallback(func(action Action) {
+		actions = append(actions, action)
+	})
+
+	testutils.AssertEqual(t, http.StatusNoContent, serve(auth, http.MethodGet, "/status", ""), "reads should be served without keys")
+	testutils.AssertEqual(t, http.StatusForbidden, serve(auth, http.MethodPost, "/halts/halt", ""), "trade actions should be refused without keys")
+	testutils.AssertEqual(t, http.StatusForbidden, serve(auth, http.MethodPost, "/blocklist/unblock", ""), "admin actions should be refused without keys")
+	testutils.AssertEqual(t, 2, len(actions), "refused control actions should be audited")
+	testutils.AssertEqual(t, "anonymous", actions[0].Principal, "clients without keys are anonymous")
+	testutils.AssertFalse(t, actions[0].Allowed, "control actions should not be allowed without keys")
+}
+
+func TestAuthenticator_APIKeyHeader(t *testing.T) {
+	auth, _ := newTestAuthenticator(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Header.Set("X-API-Key", "trade-key")
+	principal, ok := auth.Authenticate(req)
+	testutils.AssertTrue(t, ok, "X-API-Key should authenticate")
+	testutils.AssertEqual(t, RoleTrade, principal.Role, "key role should be granted")
+}
+
+func TestAuthenticator_ClientCertificate(t *testing.T) {
+	auth := New()
+	testutils.AssertNoError(t, auth.AddClients("desk:trade"), "AddClients should not return error")
+
+	certificate := &x509.Certificate{Subject: pkix.Name{CommonName: "desk"}}
+	req := httptest.NewRequest(http.MethodPost, "/halts/halt", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}}
+	_, ok := auth.Authenticate(req)
+	testutils.AssertFalse(t, ok, "unverified certificates should not authenticate")
+
+	req.TLS.VerifiedChains = [][]*x509.Certificate{{certificate}}
+	principal, ok := auth.Authenticate(req)
+	testutils.AssertTrue(t, ok, "verified certificates should authenticate")
+	testutils.AssertEqual(t, "cert:desk", principal.Name, "certificate common name should identify the client")
+	testutils.AssertEqual(t, RoleTrade, principal.Role, "certificate role should be granted")
+
+	certificate.Subject.CommonName = "intruder"
+	_, ok = auth.Authenticate(req)
+	testutils.AssertFalse(t, ok, "unknown common names should not authenticate")
+}
+
+func TestAuthenticator_InvalidSpecs(t *testing.T) {
+	auth := New()
+	err := auth.AddKeys("secret-without-name")
+	testutils.AssertError(t, err, "keys without name and role should be refused")
+	testutils.AssertFalse(t, strings.Contains(err.Error(), "secret"), "errors should not echo keys")
+	testutils.AssertError(t, auth.AddKeys("ops:root:key"), "unknown roles should be refused")
+	testutils.AssertError(t, auth.AddClients("desk"), "clients without role should be refused")
+	testutils.AssertFalse(t, auth.HasClients(), "invalid entries should grant nothing")
+}
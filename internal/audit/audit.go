@@ -0,0 +1,205 @@
+// Package audit keeps an append-only record of every signal decision made by
+// the execution agent, so a session can be reviewed for why trades were or
+// were not taken. A log may instead record the actions taken on the control
+// API.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/apiauth"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/statefile"
+)
+
+// Log writes decisions to a JSON lines stream. Every decision is flushed as
+// it is written so the log survives a crash.
+type Log struct {
+	mu      sync.Mutex
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	closer  io.Closer
+}
+
+// New creates a log that writes to w
+func New(w io.Writer) *Log {
+	buffered := bufio.NewWriter(w)
+	l := &Log{
+		writer:  buffered,
+		encoder: json.NewEncoder(statefile.NewLineWriter(buffered)),
+	}
+	if c, ok := w.(io.Closer); ok {
+		l.closer = c
+	}
+	return l
+}
+
+// NewFile creates a log appending to the file at path
+func NewFile(path string) (*Log, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return New(file), nil
+}
+
+// Record writes a single decision
+func (l *Log) Record(decision execution.Decision) error {
+	return l.write(&decision)
+}
+
+// RecordAction writes a single control API action
+func (l *Log) RecordAction(action apiauth.Action) error {
+	return l.write(&action)
+}
+
+// write encodes record as a line and flushes it
+func (l *Log) write(record any) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.encoder == nil {
+		return fmt.Errorf("audit log closed")
+	}
+	if err := l.encoder.Encode(record); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return l.writer.Flush()
+}
+
+// Close flushes and closes the underlying writer if possible
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.writer == nil {
+		return nil
+	}
+	err := l.writer.Flush()
+	l.writer = nil
+	l.encoder = nil
+	if l.closer != nil {
+		if cerr := l.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Load reads every decision from an audit log file
+func Load(path string) ([]execution.Decision, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var decisions []execution.Decision
+	decoder := json.NewDecoder(statefile.NewLineReader(file))
+	for decoder.More() {
+		var decision execution.Decision
+		if err := decoder.Decode(&decision); err != nil {
+			return nil, fmt.Errorf("failed to read audit record %d: %w", len(decisions)+1, err)
+		}
+		decisions = append(decisions, decision)
+	}
+	return decisions, nil
+}
+
+// Query selects decisions. Zero fields match everything.
+type Query struct {
+	Symbol   string
+	Strategy string
+	Outcome  execution.DecisionOutcome
+	Since    time.Time // Inclusive
+	Until    time.Time // Exclusive
+}
+
+// Match reports whether decision is selected by the query
+func (q Query) Match(decision execution.Decision) bool {
+	if q.Symbol != "" && !strings.EqualFold(q.Symbol, decision.Symbol) {
+		return false
+	}
+	if q.Strategy != "" && q.Strategy != decision.Strategy {
+		return false
+	}
+	if q.Outcome != "" && q.Outcome != decision.Outcome {
+		return false
+	}
+	if !q.Since.IsZero() && decision.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !decision.Time.Before(q.Until) {
+		return false
+	}
+	return true
+}
+
+// Filter returns the decisions selected by query, in their original order
+func Filter(decisions []execution.Decision, query Query) []execution.Decision {
+	var selected []execution.Decision
+	for _, decision := range decisions {
+		if query.Match(decision) {
+			selected = append(selected, decision)
+		}
+	}
+	return selected
+}
+
+// Count is the number of decisions sharing a key
+type Count struct {
+	Key   string
+	Count int
+}
+
+// Summary counts decisions by outcome and rejected signals by the check
+// that refused them
+type Summary struct {
+	Total      int
+	ByOutcome  []Count
+	Rejections []Count // By execution error type, most frequent first
+}
+
+// Summarize counts decisions
+func Summarize(decisions []execution.Decision) Summary {
+	outcomes := make(map[string]int)
+	rejections := make(map[string]int)
+	for _, decision := range decisions {
+		outcomes[string(decision.Outcome)]++
+		if decision.Outcome == execution.DecisionRejected {
+			key := decision.Error
+			if key == "" {
+				key = "unknown"
+			}
+			rejections[key]++
+		}
+	}
+	return Summary{
+		Total:      len(decisions),
+		ByOutcome:  sortedCounts(outcomes),
+		Rejections: sortedCounts(rejections),
+	}
+}
+
+// sortedCounts returns counts by decreasing count, then key
+func sortedCounts(counts map[string]int) []Count {
+	sorted := make([]Count, 0, len(counts))
+	for key, count := range counts {
+		sorted = append(sorted, Count{Key: key, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Key < sorted[j].Key
+	})
+	return sorted
+}
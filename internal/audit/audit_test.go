@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/apiauth"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestLog_RoundTripAndQuery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := NewFile(path)
+	testutils.AssertNoError(t, err, "NewFile should not return error")
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	decisions := []execution.Decision{
+		{Time: start, Symbol: "BTC-USD", Type: strategy.SignalTypeEntry, Outcome: execution.DecisionExecuted,
+			RequestedAmount: decimal.NewFromFloat(0.1), Amount: decimal.NewFromFloat(0.1), OrderID: "order-1"},
+		{Time: start.Add(time.Minute), Symbol: "ETH-USD", Type: strategy.SignalTypeEntry, Outcome: execution.DecisionRejected,
+			Reason: "max open positions reached", Error: "risk_validation_failed"},
+		{Time: start.Add(2 * time.Minute), Symbol: "BTC-USD", Type: strategy.SignalTypeEntry, Outcome: execution.DecisionSizedDown,
+			Reason: "reduced by event policy", RequestedAmount: decimal.NewFromFloat(0.2), Amount: decimal.NewFromFloat(0.1)},
+		{Time: start.Add(3 * time.Minute), Symbol: "BTC-USD", Type: strategy.SignalTypeEntry, Outcome: execution.DecisionRejected,
+			Reason: "spread too wide", Error: "insufficient_liquidity"},
+		{Time: start.Add(4 * time.Minute), Symbol: "ETH-USD", Type: strategy.SignalTypeEntry, Outcome: execution.DecisionRejected,
+			Reason: "daily loss limit reached", Error: "risk_check_failed"},
+	}
+	for _, decision := range decisions {
+		testutils.AssertNoError(t, l.Record(decision), "Record should not return error")
+	}
+
+	// Decisions are flushed as they are written
+	loaded, err := Load(path)
+	testutils.AssertNoError(t, err, "Load should not return error")
+	testutils.AssertEqual(t, len(decisions), len(loaded), "log should hold every decision")
+	testutils.AssertNoError(t, l.Close(), "Close should not return error")
+	testutils.AssertError(t, l.Record(decisions[0]), "Record after Close should fail")
+
+	sized := loaded[2]
+	testutils.AssertTrue(t, sized.RequestedAmount.Equal(decimal.NewFromFloat(0.2)), "requested amount should round trip")
+	testutils.AssertTrue(t, sized.Amount.Equal(decimal.NewFromFloat(0.1)), "amount should round trip")
+
+	btc := Filter(loaded, Query{Symbol: "btc-usd"})
+	testutils.AssertEqual(t, 3, len(btc), "symbol query should be case insensitive")
+
+	rejected := Filter(loaded, Query{Outcome: execution.DecisionRejected, Since: start.Add(2 * time.Minute)})
+	testutils.AssertEqual(t, 2, len(rejected), "outcome and time queries should combine")
+
+	window := Filter(loaded, Query{Since: start.Add(time.Minute), Until: start.Add(3 * time.Minute)})
+	testutils.AssertEqual(t, 2, len(window), "until should be exclusive")
+
+	summary := Summarize(loaded)
+	testutils.AssertEqual(t, 5, summary.Total, "summary should count every decision")
+	testutils.AssertEqual(t, Count{Key: "rejected", Count: 3}, summary.ByOutcome[0], "rejections should be the most frequent outcome")
+	testutils.AssertEqual(t, 3, len(summary.Rejections), "rejections should be counted by error type")
+}
+
+func TestLog_RecordAction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "control.jsonl")
+	l, err := NewFile(path)
+	testutils.AssertNoError(t, err, "NewFile should not return error")
+
+	action := apiauth.Action{Time: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), Principal: "ops", Role: "admin",
+		Method: "POST", Path: "/blocklist/block", Query: "symbol=BTC-USD", Status: 204, Allowed: true}
+	testutils.AssertNoError(t, l.RecordAction(action), "RecordAction should not return error")
+	testutils.AssertNoError(t, l.Close(), "Close should not return error")
+
+	data, err := os.ReadFile(path)
+	testutils.AssertNoError(t, err, "control log should be readable")
+	var loaded apiauth.Action
+	testutils.AssertNoError(t, json.Unmarshal(data, &loaded), "control actions should be JSON lines")
+	testutils.AssertEqual(t, action, loaded, "control action should round trip")
+}
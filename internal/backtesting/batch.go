@@ -0,0 +1,147 @@
+package backtesting
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+)
+
+// BatchResult is the outcome of the backtest of one symbol in a batch
+type BatchResult struct {
+	Symbol  string
+	Metrics *PerformanceMetrics
+	Err     error
+
+	Rank int // Position in the leaderboard, 1 being the best
+	// SelectorScore is the opportunity score the live symbol selector gives
+	// the symbol's data, and SelectorRank its position by that score
+	SelectorScore float64
+	SelectorRank  int
+}
+
+// LoadDirectory loads every CSV, Parquet and SQLite file in dir, naming each
+// symbol after its file, e.g. BTC-USD.csv holds BTC-USD
+func (dl *DataLoader) LoadDirectory(dir string) ([]*HistoricalData, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data directory: %w", err)
+	}
+
+	var datasets []*HistoricalData
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if _, err := FormatForFile(entry.Name()); err != nil {
+			continue
+		}
+		symbol := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		data, err := dl.Load(filepath.Join(dir, entry.Name()), symbol)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		datasets = append(datasets, data)
+	}
+	if len(datasets) == 0 {
+		return nil, fmt.Errorf("no data files in %s", dir)
+	}
+	return datasets, nil
+}
+
+// RunBatch backtests each dataset with its own copy of btConfig and
+// strategyConfig, running up to workers backtests in parallel. The results
+// are ranked by total return, best first, with the backtests that failed
+// last.
+func RunBatch(datasets []*HistoricalData, btConfig *BacktestConfig, strategyConfig *config.Config, workers int) []BatchResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]BatchResult, len(datasets))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = runBatchItem(datasets[i], btConfig, strategyConfig)
+			}
+		}()
+	}
+	for i := range datasets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	rankSelectorScores(results, datasets, strategyConfig)
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if (a.Err == nil) != (b.Err == nil) {
+			return a.Err == nil
+		}
+		if a.Err != nil {
+			return a.Symbol < b.Symbol
+		}
+		return a.Metrics.TotalReturnPct.GreaterThan(b.Metrics.TotalReturnPct)
+	})
+	for i := range results {
+		results[i].Rank = i + 1
+	}
+	return results
+}
+
+func runBatchItem(data *HistoricalData, btConfig *BacktestConfig, strategyConfig *config.Config) BatchResult {
+	result := BatchResult{Symbol: data.Symbol}
+	if len(data.Candles) == 0 {
+		result.Err = fmt.Errorf("no data loaded")
+		return result
+	}
+
+	cfg := *btConfig
+	cfg.StartTime = data.Candles[0].Timestamp
+	cfg.EndTime = data.Candles[len(data.Candles)-1].Timestamp
+	stratCfg := *strategyConfig
+	stratCfg.Symbol = data.Symbol
+
+	result.Metrics, result.Err = NewEngine(&cfg, data).Run(&stratCfg)
+	return result
+}
+
+// rankSelectorScores scores the data of each symbol with the symbol selector
+// so the leaderboard shows whether the selector would have picked the
+// symbols that backtested best
+func rankSelectorScores(results []BatchResult, datasets []*HistoricalData, strategyConfig *config.Config) {
+	symbols := make([]string, len(datasets))
+	symbolData := make(map[string]strategy.SymbolData, len(datasets))
+	for i, data := range datasets {
+		prices := make([]decimal.Decimal, len(data.Candles))
+		volumes := make([]decimal.Decimal, len(data.Candles))
+		for j, candle := range data.Candles {
+			prices[j] = candle.Close
+			volumes[j] = candle.Volume
+		}
+		symbols[i] = data.Symbol
+		symbolData[data.Symbol] = strategy.SymbolData{Prices: prices, Volumes: volumes}
+	}
+
+	ranked := strategy.NewSymbolSelector(strategyConfig).RankSymbols(symbols, symbolData)
+	positions := make(map[string]int, len(ranked))
+	scores := make(map[string]float64, len(ranked))
+	for i, r := range ranked {
+		positions[r.Symbol] = i + 1
+		scores[r.Symbol] = r.Score
+	}
+	for i := range results {
+		results[i].SelectorScore = scores[results[i].Symbol]
+		results[i].SelectorRank = positions[results[i].Symbol]
+	}
+}
@@ -0,0 +1,69 @@
+package backtesting
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/testutils"
+)
+
+func TestDataLoader_LoadDirectory(t *testing.T) {
+	dir := t.TempDir()
+	csvContent := `timestamp,open,high,low,close,volume
+1640995200,50000,51000,49000,50500,100
+1640995260,50500,51500,49500,51000,150`
+	for _, name := range []string{"BTC-USD.csv", "ETH-USD.csv"} {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(csvContent), 0644)
+		testutils.AssertNoError(t, err, "Failed to create test CSV file")
+	}
+	err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0644)
+	testutils.AssertNoError(t, err, "Failed to create test file")
+
+	datasets, err := NewDataLoader().LoadDirectory(dir)
+	testutils.AssertNoError(t, err, "LoadDirectory should not return error")
+	testutils.AssertEqual(t, 2, len(datasets), "should load only the CSV files")
+	testutils.AssertEqual(t, "BTC-USD", datasets[0].Symbol, "symbol should be named after the file")
+	testutils.AssertEqual(t, "ETH-USD", datasets[1].Symbol, "symbol should be named after the file")
+	testutils.AssertEqual(t, "ETH-USD", datasets[1].Candles[0].Symbol, "candles should carry the symbol")
+
+	_, err = NewDataLoader().LoadDirectory(t.TempDir())
+	testutils.AssertError(t, err, "an empty directory should be an error")
+}
+
+func TestRunBatch_RanksSymbolsByReturn(t *testing.T) {
+	loader := NewDataLoader()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	datasets := []*HistoricalData{
+		loader.GenerateSampleData("AAA-USD", start, 80, 100),
+		loader.GenerateSampleData("BBB-USD", start, 80, 50000),
+		{Symbol: "EMPTY-USD"},
+		loader.GenerateSampleData("CCC-USD", start, 80, 2000),
+	}
+
+	config := DefaultBacktestConfig()
+	config.AllowShort = true
+	results := RunBatch(datasets, config, strategy.DefaultConfig(), 2)
+
+	testutils.AssertEqual(t, 4, len(results), "every symbol should have a result")
+	for i, result := range results[:3] {
+		testutils.AssertEqual(t, i+1, result.Rank, "ranks should follow the order")
+		testutils.AssertNoError(t, result.Err, "backtest should succeed")
+		testutils.AssertTrue(t, result.SelectorRank > 0, "symbol should be ranked by the selector")
+		if i > 0 {
+			testutils.AssertTrue(t, !result.Metrics.TotalReturnPct.GreaterThan(results[i-1].Metrics.TotalReturnPct),
+				"results should be sorted by return")
+		}
+	}
+	testutils.AssertEqual(t, "EMPTY-USD", results[3].Symbol, "failed backtests should rank last")
+	testutils.AssertError(t, results[3].Err, "backtest without data should fail")
+	testutils.AssertTrue(t, config.StartTime.IsZero(), "the shared config should not be modified")
+
+	leaderboard := NewReporter().GenerateLeaderboard(results)
+	for _, symbol := range []string{"AAA-USD", "BBB-USD", "CCC-USD", "EMPTY-USD"} {
+		testutils.AssertTrue(t, strings.Contains(leaderboard, symbol), "leaderboard should list "+symbol)
+	}
+}
@@ -0,0 +1,91 @@
+package backtesting
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// csvHeader is the header written to CSV data files
+var csvHeader = []string{"timestamp", "open", "high", "low", "close", "volume"}
+
+// AppendToCSV appends the candles newer than the last one of the CSV file at
+// filename, creating it with a header if needed, so a file can be extended
+// with overlapping candles without duplicating any. It returns the number of
+// candles written. Timestamps are written in RFC3339 UTC.
+func (dl *DataLoader) AppendToCSV(filename string, candles []exchanges.Candle) (int, error) {
+	var last time.Time
+	exists := false
+	if info, err := os.Stat(filename); err == nil && info.Size() > 0 {
+		existing, err := dl.LoadFromCSV(filename, "")
+		if err != nil {
+			return 0, err
+		}
+		exists = true
+		if n := len(existing.Candles); n > 0 {
+			last = existing.Candles[n-1].Timestamp
+		}
+	}
+
+	if err := ValidateCandles(candles); err != nil {
+		return 0, err
+	}
+
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if !exists {
+		if err := writer.Write(csvHeader); err != nil {
+			return 0, fmt.Errorf("failed to write header: %w", err)
+		}
+	}
+
+	written := 0
+	for _, candle := range candles {
+		if !candle.Timestamp.After(last) {
+			continue
+		}
+		record := []string{
+			candle.Timestamp.UTC().Format(time.RFC3339),
+			candle.Open.String(),
+			candle.High.String(),
+			candle.Low.String(),
+			candle.Close.String(),
+			candle.Volume.String(),
+		}
+		if err := writer.Write(record); err != nil {
+			return written, fmt.Errorf("failed to write CSV record: %w", err)
+		}
+		written++
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return written, fmt.Errorf("failed to write CSV file: %w", err)
+	}
+	return written, file.Close()
+}
+
+// ValidateCandles checks that candles are in strictly increasing time order
+// and that their prices and volumes are consistent
+func ValidateCandles(candles []exchanges.Candle) error {
+	for i, candle := range candles {
+		if i > 0 && !candle.Timestamp.After(candles[i-1].Timestamp) {
+			return fmt.Errorf("candle %d at %s is not after the previous one", i, candle.Timestamp.Format(time.RFC3339))
+		}
+		if !candle.Low.IsPositive() || candle.Volume.IsNegative() {
+			return fmt.Errorf("candle %d at %s has a non-positive price or negative volume", i, candle.Timestamp.Format(time.RFC3339))
+		}
+		if candle.High.LessThan(candle.Open) || candle.High.LessThan(candle.Close) ||
+			candle.Low.GreaterThan(candle.Open) || candle.Low.GreaterThan(candle.Close) {
+			return fmt.Errorf("candle %d at %s has open or close outside its high-low range", i, candle.Timestamp.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
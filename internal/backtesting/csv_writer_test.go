@@ -0,0 +1,51 @@
+package backtesting
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/testutils"
+)
+
+func TestDataLoader_AppendToCSV(t *testing.T) {
+	loader := NewDataLoader()
+	file := filepath.Join(t.TempDir(), "BTC-USD.csv")
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := loader.GenerateSampleData("BTC-USD", start, 10, 50000)
+
+	written, err := loader.AppendToCSV(file, sample.Candles[:6])
+	testutils.AssertNoError(t, err, "AppendToCSV should create the file")
+	testutils.AssertEqual(t, 6, written, "every candle should be written to a new file")
+
+	// Candles overlapping the file only append the new ones
+	written, err = loader.AppendToCSV(file, sample.Candles[4:])
+	testutils.AssertNoError(t, err, "AppendToCSV should append to the file")
+	testutils.AssertEqual(t, 4, written, "candles already in the file should be skipped")
+
+	data, err := loader.LoadFromCSV(file, "BTC-USD")
+	testutils.AssertNoError(t, err, "LoadFromCSV should read the CSV file")
+	testutils.AssertEqual(t, 10, len(data.Candles), "file should hold every candle once")
+	testutils.AssertTrue(t, data.Candles[9].Timestamp.Equal(sample.Candles[9].Timestamp), "timestamps should round trip")
+	testutils.AssertTrue(t, data.Candles[9].Close.Equal(sample.Candles[9].Close), "prices should round trip")
+
+	_, err = loader.AppendToCSV(file, append(sample.Candles[9:], sample.Candles[0]))
+	testutils.AssertError(t, err, "out of order candles should be rejected")
+}
+
+func TestDataLoader_ParseTimestamp_Location(t *testing.T) {
+	loader := NewDataLoader()
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	loader.SetLocation(newYork)
+
+	timestamp, err := loader.parseTimestamp("2024-01-02 09:30:00")
+	testutils.AssertNoError(t, err, "Should parse a timestamp without offset")
+	testutils.AssertEqual(t, time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC).Unix(), timestamp.Unix(), "timestamp should be read in the location")
+
+	timestamp, err = loader.parseTimestamp("2024-01-02T09:30:00Z")
+	testutils.AssertNoError(t, err, "Should parse RFC3339")
+	testutils.AssertEqual(t, time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC).Unix(), timestamp.Unix(), "explicit offsets should win")
+}
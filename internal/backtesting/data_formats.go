@@ -0,0 +1,66 @@
+package backtesting
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// DataFormat is the storage format of a historical data file
+type DataFormat string
+
+const (
+	FormatCSV     DataFormat = "csv"
+	FormatParquet DataFormat = "parquet"
+	FormatSQLite  DataFormat = "sqlite"
+)
+
+// FormatForFile returns the data format of filename from its extension
+func FormatForFile(filename string) (DataFormat, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".parquet":
+		return FormatParquet, nil
+	case ".sqlite", ".sqlite3", ".db":
+		return FormatSQLite, nil
+	default:
+		return "", fmt.Errorf("unknown data format for %s, expected .csv, .parquet or .sqlite", filename)
+	}
+}
+
+// Load loads historical candle data from filename in the format of its
+// extension
+func (dl *DataLoader) Load(filename string, symbol string) (*HistoricalData, error) {
+	format, err := FormatForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case FormatParquet:
+		return dl.LoadFromParquet(filename, symbol)
+	case FormatSQLite:
+		return dl.LoadFromSQLite(filename, symbol)
+	default:
+		return dl.LoadFromCSV(filename, symbol)
+	}
+}
+
+// Append appends the candles newer than the last one of filename, in the
+// format of its extension, and returns the number of candles written
+func (dl *DataLoader) Append(filename string, candles []exchanges.Candle) (int, error) {
+	format, err := FormatForFile(filename)
+	if err != nil {
+		return 0, err
+	}
+	switch format {
+	case FormatParquet:
+		return dl.AppendToParquet(filename, candles)
+	case FormatSQLite:
+		return dl.AppendToSQLite(filename, candles)
+	default:
+		return dl.AppendToCSV(filename, candles)
+	}
+}
@@ -0,0 +1,158 @@
+package backtesting
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/parquet-go/parquet-go"
+	"github.com/shopspring/decimal"
+)
+
+func TestDataLoader_AppendAndLoad_Formats(t *testing.T) {
+	// Parquet and SQLite store prices as floating point numbers
+	epsilon := decimal.NewFromFloat(1e-6)
+	loader := NewDataLoader()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := loader.GenerateSampleData("BTC-USD", start, 10, 50000)
+
+	for _, name := range []string{"BTC-USD.csv", "BTC-USD.parquet", "BTC-USD.sqlite"} {
+		t.Run(name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), name)
+
+			written, err := loader.Append(file, sample.Candles[:6])
+			testutils.AssertNoError(t, err, "Append should create the file")
+			testutils.AssertEqual(t, 6, written, "every candle should be written to a new file")
+
+			// Candles overlapping the file only append the new ones
+			written, err = loader.Append(file, sample.Candles[4:])
+			testutils.AssertNoError(t, err, "Append should extend the file")
+			testutils.AssertEqual(t, 4, written, "candles already in the file should be skipped")
+
+			data, err := loader.Load(file, "BTC-USD")
+			testutils.AssertNoError(t, err, "Load should read the file")
+			testutils.AssertEqual(t, 10, len(data.Candles), "file should hold every candle once")
+			testutils.AssertEqual(t, "BTC-USD", data.Candles[0].Symbol, "candles should carry the symbol")
+			testutils.AssertTrue(t, data.Candles[9].Timestamp.Equal(sample.Candles[9].Timestamp), "timestamps should round trip")
+			testutils.AssertTrue(t, data.Candles[9].Close.Sub(sample.Candles[9].Close).Abs().LessThan(epsilon), "prices should round trip")
+
+			_, err = loader.Append(file, append(sample.Candles[9:], sample.Candles[0]))
+			testutils.AssertError(t, err, "out of order candles should be rejected")
+		})
+	}
+
+	_, err := loader.Load("BTC-USD.json", "BTC-USD")
+	testutils.AssertError(t, err, "unknown extensions should be rejected")
+}
+
+func TestDataLoader_LoadFromParquet_Schema(t *testing.T) {
+	dir := t.TempDir()
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	loader := NewDataLoader()
+	loader.SetLocation(newYork)
+
+	// Wall clock timestamps in microseconds with integer prices
+	type localRow struct {
+		Timestamp int64 `parquet:"timestamp,timestamp(microsecond:local)"`
+		Open      int32 `parquet:"open"`
+		High      int32 `parquet:"high"`
+		Low       int32 `parquet:"low"`
+		Close     int32 `parquet:"close"`
+		Volume    int64 `parquet:"volume"`
+	}
+	wall := time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC)
+	local := filepath.Join(dir, "local.parquet")
+	err = parquet.WriteFile(local, []localRow{{Timestamp: wall.UnixMicro(), Open: 100, High: 110, Low: 90, Close: 105, Volume: 7}})
+	testutils.AssertNoError(t, err, "Failed to create test Parquet file")
+
+	data, err := loader.LoadFromParquet(local, "BTC-USD")
+	testutils.AssertNoError(t, err, "LoadFromParquet should read local timestamps")
+	testutils.AssertEqual(t, time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC).Unix(), data.Candles[0].Timestamp.Unix(), "local timestamps should be read in the location")
+	testutils.AssertEqual(t, "105", data.Candles[0].Close.String(), "integer prices should be read")
+
+	// Plain integer timestamps in seconds
+	type unixRow struct {
+		Timestamp int64   `parquet:"timestamp"`
+		Open      float32 `parquet:"open"`
+		High      float32 `parquet:"high"`
+		Low       float32 `parquet:"low"`
+		Close     float32 `parquet:"close"`
+		Volume    float32 `parquet:"volume"`
+	}
+	unix := filepath.Join(dir, "unix.parquet")
+	err = parquet.WriteFile(unix, []unixRow{{Timestamp: 1704067200, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 3}})
+	testutils.AssertNoError(t, err, "Failed to create test Parquet file")
+
+	data, err = loader.LoadFromParquet(unix, "BTC-USD")
+	testutils.AssertNoError(t, err, "LoadFromParquet should read Unix timestamps")
+	testutils.AssertEqual(t, int64(1704067200), data.Candles[0].Timestamp.Unix(), "Unix timestamps should be read in UTC")
+
+	// A missing column is an error rather than zero prices
+	type partialRow struct {
+		Timestamp int64   `parquet:"timestamp"`
+		Close     float64 `parquet:"close"`
+	}
+	partial := filepath.Join(dir, "partial.parquet")
+	err = parquet.WriteFile(partial, []partialRow{{Timestamp: 1704067200, Close: 1}})
+	testutils.AssertNoError(t, err, "Failed to create test Parquet file")
+	_, err = loader.LoadFromParquet(partial, "BTC-USD")
+	testutils.AssertError(t, err, "a file without every candle column should be rejected")
+
+	// Prices must be numeric
+	type textRow struct {
+		Timestamp int64  `parquet:"timestamp"`
+		Open      string `parquet:"open"`
+		High      string `parquet:"high"`
+		Low       string `parquet:"low"`
+		Close     string `parquet:"close"`
+		Volume    string `parquet:"volume"`
+	}
+	text := filepath.Join(dir, "text.parquet")
+	err = parquet.WriteFile(text, []textRow{{Timestamp: 1704067200, Open: "1", High: "1", Low: "1", Close: "1", Volume: "1"}})
+	testutils.AssertNoError(t, err, "Failed to create test Parquet file")
+	_, err = loader.LoadFromParquet(text, "BTC-USD")
+	testutils.AssertError(t, err, "text prices should be rejected")
+}
+
+func TestDataLoader_LoadFromSQLite_Schema(t *testing.T) {
+	dir := t.TempDir()
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	loader := NewDataLoader()
+	loader.SetLocation(newYork)
+
+	file := filepath.Join(dir, "text.sqlite")
+	db, err := sql.Open("sqlite", file)
+	testutils.AssertNoError(t, err, "Failed to create test database")
+	_, err = db.Exec(`CREATE TABLE candles (timestamp TEXT, open TEXT, high TEXT, low TEXT, close TEXT, volume TEXT);
+		INSERT INTO candles VALUES ('2024-01-02 09:31:00', '100.5', '101', '100', '100.75', '2');
+		INSERT INTO candles VALUES ('2024-01-02 09:30:00', '100', '101', '99', '100.5', '3');
+		CREATE TABLE partial (timestamp INTEGER, close REAL);`)
+	testutils.AssertNoError(t, err, "Failed to fill test database")
+	testutils.AssertNoError(t, db.Close(), "Failed to close test database")
+
+	data, err := loader.LoadFromSQLite(file, "BTC-USD")
+	testutils.AssertNoError(t, err, "LoadFromSQLite should read text columns")
+	testutils.AssertEqual(t, 2, len(data.Candles), "every row should be read")
+	testutils.AssertEqual(t, time.Date(2024, 1, 2, 14, 30, 0, 0, time.UTC).Unix(), data.Candles[0].Timestamp.Unix(), "timestamps without offset should be read in the location and sorted")
+	testutils.AssertEqual(t, "100.75", data.Candles[1].Close.String(), "text prices should be read exactly")
+
+	db, err = sql.Open("sqlite", file)
+	testutils.AssertNoError(t, err, "Failed to open test database")
+	_, err = db.Exec(`DROP TABLE candles; ALTER TABLE partial RENAME TO candles`)
+	testutils.AssertNoError(t, err, "Failed to change test database")
+	testutils.AssertNoError(t, db.Close(), "Failed to close test database")
+
+	_, err = loader.LoadFromSQLite(file, "BTC-USD")
+	testutils.AssertError(t, err, "a table without every candle column should be rejected")
+
+	_, err = loader.LoadFromSQLite(filepath.Join(dir, "missing.sqlite"), "BTC-USD")
+	testutils.AssertError(t, err, "a missing database should not be created")
+}
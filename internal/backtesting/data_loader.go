@@ -14,13 +14,22 @@ import (
 )
 
 // DataLoader loads historical data for backtesting
-type DataLoader struct{}
+type DataLoader struct {
+	// location is the time zone of timestamps without an offset, UTC if nil
+	location *time.Location
+}
 
 // NewDataLoader creates a new data loader
 func NewDataLoader() *DataLoader {
 	return &DataLoader{}
 }
 
+// SetLocation sets the time zone of timestamps without an offset, such as
+// "2024-01-02 15:04:05". Unix and RFC3339 timestamps are unaffected.
+func (dl *DataLoader) SetLocation(location *time.Location) {
+	dl.location = location
+}
+
 // LoadFromCSV loads historical candle data from CSV file
 // Expected CSV format: timestamp,open,high,low,close,volume
 // timestamp can be in Unix timestamp (seconds or milliseconds) or RFC3339 format
@@ -153,8 +162,12 @@ func (dl *DataLoader) parseTimestamp(s string) (time.Time, error) {
 		"2006-01-02",
 	}
 
+	location := dl.location
+	if location == nil {
+		location = time.UTC
+	}
 	for _, format := range formats {
-		if t, err := time.Parse(format, s); err == nil {
+		if t, err := time.ParseInLocation(format, s, location); err == nil {
 			return t, nil
 		}
 	}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/guyghost/constantine/internal/benchmark"
 	"github.com/guyghost/constantine/internal/config"
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/guyghost/constantine/internal/logger"
@@ -24,6 +25,7 @@ type Engine struct {
 	currentIndex int
 	capital      decimal.Decimal
 	position     *Position
+	pending      []pendingOrder // Orders in flight under the order latency
 	trades       []Trade
 	equityCurve  []EquityPoint
 
@@ -32,6 +34,12 @@ type Engine struct {
 	onEquityUpdate func(decimal.Decimal)
 }
 
+// pendingOrder is a signal sent to the exchange that has not arrived yet
+type pendingOrder struct {
+	signal *strategy.Signal
+	sentAt time.Time
+}
+
 // NewEngine creates a new backtesting engine
 func NewEngine(config *BacktestConfig, data *HistoricalData) *Engine {
 	return &Engine{
@@ -80,6 +88,12 @@ func (e *Engine) Run(strategyConfig *config.Config) (*PerformanceMetrics, error)
 		// Update simulated exchange state
 		e.exchange.SetCurrentCandle(e.currentIndex)
 
+		// Charge carrying costs on the open position
+		e.accrueCarryCost(candle)
+
+		// Fill orders that reached the exchange since the previous candle
+		e.processPendingOrders(candle)
+
 		// Check if position should be closed (stop loss / take profit)
 		e.checkPositionExit(candle)
 
@@ -118,20 +132,23 @@ func (e *Engine) feedCandleToStrategy(ctx context.Context, candle exchanges.Cand
 	// In a real implementation, we'd need to adapt the strategy to accept candles directly
 	// For now, we'll simulate by calling the signal generator
 
+	// The strategy only sees candles that reached it under the data latency
+	visible := e.exchange.visibleIndex()
+
 	// Need enough historical data for indicators
 	minDataPoints := 25 // Need at least LongEMAPeriod + some buffer
-	if e.currentIndex < minDataPoints {
+	if visible < minDataPoints {
 		return // Not enough data yet
 	}
 
 	// Get current candles window for analysis
 	windowSize := 50 // Should be at least max(LongEMAPeriod, RSIPeriod, BB Period)
-	start := e.currentIndex - windowSize + 1
+	start := visible - windowSize + 1
 	if start < 0 {
 		start = 0
 	}
 
-	candles := e.data.Candles[start : e.currentIndex+1]
+	candles := e.data.Candles[start : visible+1]
 
 	// Extract prices and volumes
 	prices := make([]decimal.Decimal, len(candles))
@@ -153,23 +170,77 @@ func (e *Engine) feedCandleToStrategy(ctx context.Context, candle exchanges.Cand
 func (e *Engine) handleSignal(signal *strategy.Signal) {
 	candle := e.data.Candles[e.currentIndex]
 
+	// Orders take OrderLatency to reach the exchange
+	if e.config.OrderLatency > 0 {
+		if signal.Type == strategy.SignalTypeEntry && (e.position != nil || e.entryPending() || signal.Strength <= 0.1) {
+			return
+		}
+		e.pending = append(e.pending, pendingOrder{signal: signal, sentAt: candle.Timestamp})
+		return
+	}
+
+	e.executeSignal(signal, candle, signal.Price, candle.Close)
+}
+
+// executeSignal opens or closes the position for signal, entering at
+// entryPrice or exiting at exitPrice
+func (e *Engine) executeSignal(signal *strategy.Signal, candle exchanges.Candle, entryPrice, exitPrice decimal.Decimal) {
 	// Entry signals
 	if signal.Type == strategy.SignalTypeEntry {
 		if e.position == nil && signal.Strength > 0.1 {
-			e.openPosition(signal, candle)
+			e.openPositionAt(signal, candle, entryPrice)
 		}
 	}
 
 	// Exit signals
 	if signal.Type == strategy.SignalTypeExit {
 		if e.position != nil && e.position.Side == signal.Side {
-			e.closePosition(candle, "signal")
+			e.closePositionAt(candle, exitPrice, "signal")
+		}
+	}
+}
+
+// entryPending reports whether an entry order is in flight
+func (e *Engine) entryPending() bool {
+	for _, p := range e.pending {
+		if p.signal.Type == strategy.SignalTypeEntry {
+			return true
+		}
+	}
+	return false
+}
+
+// processPendingOrders fills the orders that have reached the exchange at
+// the open of candle, the first price they can trade at
+func (e *Engine) processPendingOrders(candle exchanges.Candle) {
+	if len(e.pending) == 0 {
+		return
+	}
+
+	price := candle.Open
+	if !price.IsPositive() {
+		price = candle.Close
+	}
+
+	remaining := e.pending[:0]
+	for _, p := range e.pending {
+		if !e.exchange.orderArrived(p.sentAt) {
+			remaining = append(remaining, p)
+			continue
 		}
+		e.executeSignal(p.signal, candle, price, price)
 	}
+	e.pending = remaining
 }
 
-// openPosition opens a new position
+// openPosition opens a new position at the signal price
 func (e *Engine) openPosition(signal *strategy.Signal, candle exchanges.Candle) {
+	e.openPositionAt(signal, candle, signal.Price)
+}
+
+// openPositionAt opens a new position filled at price, with stop loss and
+// take profit placed from the signal price
+func (e *Engine) openPositionAt(signal *strategy.Signal, candle exchanges.Candle, price decimal.Decimal) {
 	// Check if we can open a position
 	if e.config.MaxPositions > 0 && e.position != nil {
 		return // Already have a position
@@ -195,9 +266,15 @@ func (e *Engine) openPosition(signal *strategy.Signal, candle exchanges.Candle)
 
 	// Calculate position size
 	var amount decimal.Decimal
-	if e.config.UseFixedAmount {
+	switch {
+	case e.config.UseFixedAmount:
 		amount = e.config.FixedAmount
-	} else {
+	case e.config.NotionalPerTrade.IsPositive():
+		if !price.IsPositive() {
+			return
+		}
+		amount = e.capital.Mul(e.config.NotionalPerTrade).Div(price)
+	default:
 		// Risk-based position sizing
 		riskAmount := e.capital.Mul(e.config.RiskPerTrade)
 		stopDistance := signal.Price.Sub(stopLoss).Abs()
@@ -208,7 +285,7 @@ func (e *Engine) openPosition(signal *strategy.Signal, candle exchanges.Candle)
 	}
 
 	// Apply slippage to entry price
-	entryPrice := signal.Price
+	entryPrice := price
 	if signal.Side == exchanges.OrderSideBuy {
 		entryPrice = entryPrice.Mul(decimal.NewFromInt(1).Add(e.config.Slippage))
 	} else {
@@ -233,20 +310,27 @@ func (e *Engine) openPosition(signal *strategy.Signal, candle exchanges.Candle)
 		EntryTime:  candle.Timestamp,
 		StopLoss:   stopLoss,
 		TakeProfit: takeProfit,
+
+		LastAccrual: candle.Timestamp,
 	}
 
 	// Deduct capital
 	e.capital = e.capital.Sub(commission)
 }
 
-// closePosition closes the current position
+// closePosition closes the current position at the candle close
 func (e *Engine) closePosition(candle exchanges.Candle, reason string) {
+	e.closePositionAt(candle, candle.Close, reason)
+}
+
+// closePositionAt closes the current position filled at price
+func (e *Engine) closePositionAt(candle exchanges.Candle, price decimal.Decimal, reason string) {
 	if e.position == nil {
 		return
 	}
 
 	// Apply slippage to exit price
-	exitPrice := candle.Close
+	exitPrice := price
 	if e.position.Side == exchanges.OrderSideBuy {
 		exitPrice = exitPrice.Mul(decimal.NewFromInt(1).Sub(e.config.Slippage))
 	} else {
@@ -261,9 +345,9 @@ func (e *Engine) closePosition(candle exchanges.Candle, reason string) {
 		pnl = e.position.EntryPrice.Sub(exitPrice).Mul(e.position.Amount)
 	}
 
-	// Calculate commission
+	// Calculate commission and carrying costs
 	commission := exitPrice.Mul(e.position.Amount).Mul(e.config.CommissionRate)
-	pnl = pnl.Sub(commission)
+	pnl = pnl.Sub(commission).Sub(e.position.CarryCost)
 
 	// Calculate P&L percentage
 	pnlPercent := pnl.Div(e.position.EntryPrice.Mul(e.position.Amount)).Mul(decimal.NewFromInt(100))
@@ -284,6 +368,7 @@ func (e *Engine) closePosition(candle exchanges.Candle, reason string) {
 		StopLoss:   e.position.StopLoss,
 		TakeProfit: e.position.TakeProfit,
 		ExitReason: reason,
+		CarryCost:  e.position.CarryCost,
 	}
 
 	e.trades = append(e.trades, trade)
@@ -300,6 +385,21 @@ func (e *Engine) closePosition(candle exchanges.Candle, reason string) {
 	e.position = nil
 }
 
+// accrueCarryCost charges the carrying costs of the open position since
+// they were last accrued, at the candle open
+func (e *Engine) accrueCarryCost(candle exchanges.Candle) {
+	if e.position == nil || e.exchange == nil {
+		return
+	}
+	price := candle.Open
+	if !price.IsPositive() {
+		price = candle.Close
+	}
+	cost := e.exchange.carryCost(e.position, price, e.capital, candle.Timestamp.Sub(e.position.LastAccrual))
+	e.position.CarryCost = e.position.CarryCost.Add(cost)
+	e.position.LastAccrual = candle.Timestamp
+}
+
 // checkPositionExit checks if position should be exited due to stop loss or take profit
 func (e *Engine) checkPositionExit(candle exchanges.Candle) {
 	if e.position == nil {
@@ -341,7 +441,7 @@ func (e *Engine) recordEquity(timestamp time.Time) {
 		} else {
 			unrealizedPnL = e.position.EntryPrice.Sub(candle.Close).Mul(e.position.Amount)
 		}
-		equity = equity.Add(unrealizedPnL)
+		equity = equity.Add(unrealizedPnL).Sub(e.position.CarryCost)
 	}
 
 	e.equityCurve = append(e.equityCurve, EquityPoint{
@@ -360,6 +460,7 @@ func (e *Engine) calculateMetrics() *PerformanceMetrics {
 		Trades:      e.trades,
 		EquityCurve: e.equityCurve,
 		TotalTrades: len(e.trades),
+		Benchmarks:  e.compareBenchmarks(),
 	}
 
 	if len(e.trades) == 0 {
@@ -380,6 +481,7 @@ func (e *Engine) calculateMetrics() *PerformanceMetrics {
 	for _, trade := range e.trades {
 		duration := trade.ExitTime.Sub(trade.EntryTime)
 		totalDuration += duration
+		metrics.TotalCarryCost = metrics.TotalCarryCost.Add(trade.CarryCost)
 
 		if trade.PnL.GreaterThan(decimal.Zero) {
 			metrics.WinningTrades++
@@ -435,6 +537,40 @@ func (e *Engine) calculateMetrics() *PerformanceMetrics {
 	return metrics
 }
 
+// compareBenchmarks compares the equity curve with buy and hold of the
+// traded symbol and of the configured benchmark data
+func (e *Engine) compareBenchmarks() []benchmark.Comparison {
+	equity := make([]benchmark.Point, len(e.equityCurve))
+	times := make([]time.Time, len(e.equityCurve))
+	for i, point := range e.equityCurve {
+		equity[i] = benchmark.Point{Time: point.Time, Value: point.Equity.InexactFloat64()}
+		times[i] = point.Time
+	}
+
+	datasets := []*HistoricalData{e.data}
+	if e.config.BenchmarkData != nil && e.config.BenchmarkData.Symbol != e.data.Symbol {
+		datasets = append(datasets, e.config.BenchmarkData)
+	}
+
+	var comparisons []benchmark.Comparison
+	for _, data := range datasets {
+		prices := make([]benchmark.Point, len(data.Candles))
+		for i, candle := range data.Candles {
+			prices[i] = benchmark.Point{Time: candle.Timestamp, Value: candle.Close.InexactFloat64()}
+		}
+		held := benchmark.BuyAndHold([][]benchmark.Point{prices}, times)
+		if held == nil {
+			continue
+		}
+		comparison, err := benchmark.Compare("Buy & hold "+data.Symbol, equity, held)
+		if err != nil {
+			continue
+		}
+		comparisons = append(comparisons, comparison)
+	}
+	return comparisons
+}
+
 // calculateMaxDrawdown calculates the maximum drawdown
 func (e *Engine) calculateMaxDrawdown() (decimal.Decimal, decimal.Decimal) {
 	var maxDrawdown, maxDrawdownPct decimal.Decimal
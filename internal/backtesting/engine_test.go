@@ -154,6 +154,31 @@ func TestEngine_OpenPosition(t *testing.T) {
 	testutils.AssertTrue(t, engine.position.EntryPrice.Equal(expectedEntryPrice), "Entry price should include slippage")
 }
 
+func TestEngine_OpenPosition_NotionalPerTrade(t *testing.T) {
+	// Symbols of very different prices commit the same share of capital
+	for _, price := range []float64{50000, 0.5} {
+		config := DefaultBacktestConfig()
+		config.Slippage = decimal.Zero
+		config.NotionalPerTrade = decimal.NewFromFloat(0.1)
+
+		data := &HistoricalData{Symbol: "X-USD", Candles: testutils.SampleCandles()[:5]}
+		engine := NewEngine(config, data)
+		engine.strategy = strategy.NewScalpingStrategy(strategy.DefaultConfig(), nil)
+
+		signal := &strategy.Signal{
+			Type:   strategy.SignalTypeEntry,
+			Side:   exchanges.OrderSideBuy,
+			Symbol: "X-USD",
+			Price:  decimal.NewFromFloat(price),
+		}
+		engine.openPosition(signal, data.Candles[0])
+
+		testutils.AssertNotNil(t, engine.position, "Position should be opened")
+		notional := engine.position.EntryPrice.Mul(engine.position.Amount)
+		testutils.AssertTrue(t, notional.Equal(decimal.NewFromInt(1000)), "entry should commit 10% of capital at any price")
+	}
+}
+
 func TestEngine_ClosePosition(t *testing.T) {
 	config := DefaultBacktestConfig()
 	config.InitialCapital = decimal.NewFromFloat(100000) // Same as open position test
@@ -525,3 +550,150 @@ func TestEngine_WithArtificialSignalData(t *testing.T) {
 		}
 	}
 }
+
+func newLatencyEngine(dataLatency, orderLatency time.Duration) *Engine {
+	config := DefaultBacktestConfig()
+	config.InitialCapital = decimal.NewFromFloat(100000)
+	config.UseFixedAmount = true
+	config.FixedAmount = decimal.NewFromFloat(0.1)
+	config.Slippage = decimal.Zero
+	config.DataLatency = dataLatency
+	config.OrderLatency = orderLatency
+
+	data := &HistoricalData{Symbol: "BTC-USD", Candles: testutils.SampleCandles()[:10]}
+	engine := NewEngine(config, data)
+	engine.exchange = NewSimulatedExchange(data, config)
+	strategyConfig := strategy.DefaultConfig()
+	strategyConfig.Symbol = "BTC-USD"
+	engine.strategy = strategy.NewScalpingStrategy(strategyConfig, engine.exchange)
+	return engine
+}
+
+func TestSimulatedExchange_DataLatency(t *testing.T) {
+	engine := newLatencyEngine(90*time.Minute, 0)
+	engine.exchange.SetCurrentCandle(5)
+
+	// Hourly candles: 90 minutes of latency hides the current and previous candle
+	testutils.AssertEqual(t, 3, engine.exchange.visibleIndex(), "strategy should see the candle from two hours ago")
+
+	ticker, err := engine.exchange.GetTicker(context.Background(), "BTC-USD")
+	testutils.AssertNoError(t, err, "GetTicker should not return error")
+	testutils.AssertTrue(t, ticker.Last.Equal(engine.data.Candles[3].Close), "ticker should be stale")
+
+	candles, err := engine.exchange.GetCandles(context.Background(), "BTC-USD", "1h", 2)
+	testutils.AssertNoError(t, err, "GetCandles should not return error")
+	testutils.AssertEqual(t, 2, len(candles), "should return the requested number of candles")
+	testutils.AssertTrue(t, candles[1].Timestamp.Equal(engine.data.Candles[3].Timestamp), "latest candle should be the last visible one")
+
+	engine.exchange.SetCurrentCandle(1)
+	_, err = engine.exchange.GetTicker(context.Background(), "BTC-USD")
+	testutils.AssertError(t, err, "no candle is visible yet")
+}
+
+func TestEngine_OrderLatencyDelaysFills(t *testing.T) {
+	engine := newLatencyEngine(0, 90*time.Minute)
+	candles := engine.data.Candles
+
+	engine.currentIndex = 2
+	engine.exchange.SetCurrentCandle(2)
+	engine.handleSignal(&strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Side:     exchanges.OrderSideBuy,
+		Symbol:   "BTC-USD",
+		Price:    candles[2].Close,
+		Strength: 0.8,
+	})
+	if engine.position != nil {
+		t.Fatal("entry should not fill before the order latency has elapsed")
+	}
+
+	// One hour later the order is still in flight
+	engine.exchange.SetCurrentCandle(3)
+	engine.processPendingOrders(candles[3])
+	if engine.position != nil {
+		t.Fatal("entry should still be in flight")
+	}
+
+	// Two hours later it fills at the open
+	engine.exchange.SetCurrentCandle(4)
+	engine.processPendingOrders(candles[4])
+	testutils.AssertNotNil(t, engine.position, "entry should fill once it reaches the exchange")
+	testutils.AssertTrue(t, engine.position.EntryPrice.Equal(candles[4].Open), "entry should fill at the open of the arrival candle")
+	testutils.AssertEqual(t, 0, len(engine.pending), "filled orders should leave the queue")
+}
+
+func TestSimulatedExchange_CarryCost(t *testing.T) {
+	config := DefaultBacktestConfig()
+	config.FundingRate = decimal.NewFromFloat(0.0001)
+	config.BorrowRate = decimal.NewFromFloat(0.0876)
+	config.MarginInterestRate = decimal.NewFromFloat(0.0876)
+	exchange := NewSimulatedExchange(&HistoricalData{Symbol: "BTC-USD"}, config)
+
+	price := decimal.NewFromInt(100)
+	long := &Position{Side: exchanges.OrderSideBuy, Amount: decimal.NewFromInt(2)}
+	short := &Position{Side: exchanges.OrderSideSell, Amount: decimal.NewFromInt(2)}
+
+	// 8 hours of funding on 200 of notional is 0.02; margin interest only
+	// applies to the notional above capital
+	cost := exchange.carryCost(long, price, decimal.NewFromInt(1000), 8*time.Hour)
+	testutils.AssertTrue(t, cost.Round(8).Equal(decimal.NewFromFloat(0.02)), "long should pay funding, got "+cost.String())
+
+	// 100 of the 200 notional is borrowed at 0.001% per hour
+	cost = exchange.carryCost(long, price, decimal.NewFromInt(100), 8*time.Hour)
+	testutils.AssertTrue(t, cost.Round(8).Equal(decimal.NewFromFloat(0.028)), "long should pay margin interest, got "+cost.String())
+
+	// Shorts receive the funding and pay to borrow the whole notional
+	cost = exchange.carryCost(short, price, decimal.NewFromInt(1000), 8*time.Hour)
+	testutils.AssertTrue(t, cost.Round(8).Equal(decimal.NewFromFloat(-0.004)), "short should receive funding and pay borrow, got "+cost.String())
+
+	testutils.AssertTrue(t, exchange.carryCost(long, price, decimal.Zero, 0).IsZero(), "no time held costs nothing")
+}
+
+func TestEngine_CarryCostReducesPnL(t *testing.T) {
+	engine := newLatencyEngine(0, 0)
+	engine.config.FundingRate = decimal.NewFromFloat(0.001)
+
+	signal := &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Side:     exchanges.OrderSideBuy,
+		Symbol:   "BTC-USD",
+		Price:    engine.data.Candles[0].Close,
+		Strength: 0.8,
+	}
+	engine.openPositionAt(signal, engine.data.Candles[0], engine.data.Candles[0].Close)
+	testutils.AssertNotNil(t, engine.position, "position should be opened")
+
+	for _, candle := range engine.data.Candles[1:9] {
+		engine.accrueCarryCost(candle)
+	}
+	carry := engine.position.CarryCost
+	testutils.AssertTrue(t, carry.IsPositive(), "long should pay positive funding")
+
+	entry := engine.position.EntryPrice
+	exit := engine.data.Candles[8].Close
+	engine.closePositionAt(engine.data.Candles[8], exit, "test")
+
+	trade := engine.trades[0]
+	commission := exit.Mul(trade.Amount).Mul(engine.config.CommissionRate)
+	expected := exit.Sub(entry).Mul(trade.Amount).Sub(commission).Sub(carry)
+	testutils.AssertTrue(t, trade.CarryCost.Equal(carry), "trade should record the carry cost")
+	testutils.AssertTrue(t, trade.PnL.Equal(expected), "carry cost should be deducted from the trade PnL")
+}
+
+func TestEngine_Benchmarks(t *testing.T) {
+	config := DefaultBacktestConfig()
+	candles := testutils.SampleCandles()[:50]
+	config.BenchmarkData = &HistoricalData{Symbol: "ETH-USD", Candles: candles}
+	engine := NewEngine(config, &HistoricalData{Symbol: "BTC-USD", Candles: candles})
+
+	metrics, err := engine.Run(strategy.DefaultConfig())
+	testutils.AssertNoError(t, err, "Run should not return error")
+	testutils.AssertEqual(t, 2, len(metrics.Benchmarks), "Should compare with the traded symbol and the benchmark data")
+	testutils.AssertEqual(t, "Buy & hold BTC-USD", metrics.Benchmarks[0].Benchmark, "Should compare with the traded symbol first")
+	testutils.AssertEqual(t, "Buy & hold ETH-USD", metrics.Benchmarks[1].Benchmark, "Should compare with the benchmark data")
+
+	first, last := candles[0].Close, candles[len(candles)-1].Close
+	want := last.Div(first).Sub(decimal.NewFromInt(1)).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	got := metrics.Benchmarks[0].BenchmarkReturnPct
+	testutils.AssertTrue(t, got-want < 1e-6 && want-got < 1e-6, "Benchmark return should be the change of the close")
+}
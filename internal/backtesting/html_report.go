@@ -0,0 +1,244 @@
+package backtesting
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"time"
+)
+
+// histogramBins is the number of buckets of the trade distribution charts
+const histogramBins = 20
+
+// ReportParameter is a setting the backtest ran with, listed in the HTML
+// report
+type ReportParameter struct {
+	Name  string
+	Value string
+}
+
+// chartData is the data the report charts are drawn from
+type chartData struct {
+	Times          []string  `json:"times"`
+	Equity         []float64 `json:"equity"`
+	Drawdown       []float64 `json:"drawdown"` // Percent below the running peak
+	PnLBins        []string  `json:"pnl_bins"`
+	PnLCounts      []int     `json:"pnl_counts"`
+	DurationBins   []string  `json:"duration_bins"`
+	DurationCounts []int     `json:"duration_counts"`
+}
+
+type htmlReport struct {
+	Generated  string
+	Metrics    *PerformanceMetrics
+	Parameters []ReportParameter
+	Trades     []Trade
+	Charts     chartData
+}
+
+// GenerateHTML writes a single page HTML report with an interactive equity
+// curve, a drawdown chart, histograms of the trade returns and durations, and
+// the parameters of the run. The charts are drawn with Chart.js, loaded from
+// a CDN when the report is opened.
+func (r *Reporter) GenerateHTML(w io.Writer, metrics *PerformanceMetrics, params []ReportParameter) error {
+	report := htmlReport{
+		Generated:  time.Now().UTC().Format("2006-01-02 15:04 UTC"),
+		Metrics:    metrics,
+		Parameters: params,
+		Trades:     metrics.Trades,
+		Charts:     buildChartData(metrics),
+	}
+	if err := htmlReportTemplate.Execute(w, report); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return nil
+}
+
+// WriteHTMLFile writes the HTML report to path
+func (r *Reporter) WriteHTMLFile(path string, metrics *PerformanceMetrics, params []ReportParameter) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	if err := r.GenerateHTML(file, metrics, params); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func buildChartData(metrics *PerformanceMetrics) chartData {
+	var data chartData
+
+	peak := 0.0
+	for _, point := range metrics.EquityCurve {
+		equity := point.Equity.InexactFloat64()
+		peak = math.Max(peak, equity)
+		drawdown := 0.0
+		if peak > 0 {
+			drawdown = (equity - peak) / peak * 100
+		}
+		data.Times = append(data.Times, point.Time.UTC().Format("2006-01-02 15:04"))
+		data.Equity = append(data.Equity, equity)
+		data.Drawdown = append(data.Drawdown, drawdown)
+	}
+
+	returns := make([]float64, len(metrics.Trades))
+	durations := make([]float64, len(metrics.Trades))
+	for i, trade := range metrics.Trades {
+		returns[i] = trade.PnLPercent.InexactFloat64()
+		durations[i] = trade.ExitTime.Sub(trade.EntryTime).Minutes()
+	}
+	data.PnLBins, data.PnLCounts = histogram(returns, histogramBins, "%.2f%%")
+	data.DurationBins, data.DurationCounts = histogram(durations, histogramBins, "%.0fm")
+	return data
+}
+
+// histogram counts values into equal width bins between their minimum and
+// maximum, labelling each bin by its lower bound
+func histogram(values []float64, bins int, labelFormat string) ([]string, []int) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if hi == lo {
+		return []string{fmt.Sprintf(labelFormat, lo)}, []int{len(values)}
+	}
+
+	width := (hi - lo) / float64(bins)
+	labels := make([]string, bins)
+	counts := make([]int, bins)
+	for i := range labels {
+		labels[i] = fmt.Sprintf(labelFormat, lo+float64(i)*width)
+	}
+	for _, v := range values {
+		i := int((v - lo) / width)
+		if i >= bins {
+			i = bins - 1
+		}
+		counts[i]++
+	}
+	return labels, counts
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"duration": formatDuration,
+	"inc":      func(i int) int { return i + 1 },
+	"datetime": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Constantine backtest report</title>
+<script src="https://cdn.jsdelivr.net/npm/chart.js@4"></script>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; margin: 2em auto; max-width: 1100px; color: #222; }
+h1 { margin-bottom: 0; }
+.muted { color: #888; }
+.grid { display: grid; grid-template-columns: repeat(auto-fill, minmax(200px, 1fr)); gap: 1em; margin: 1.5em 0; }
+.card { border: 1px solid #ddd; border-radius: 6px; padding: 0.8em 1em; }
+.card .label { color: #888; font-size: 0.85em; }
+.card .value { font-size: 1.4em; font-weight: 600; }
+.chart { margin: 2em 0; }
+.charts { display: grid; grid-template-columns: 1fr 1fr; gap: 2em; }
+table { border-collapse: collapse; width: 100%; font-size: 0.9em; }
+th, td { border-bottom: 1px solid #eee; padding: 0.3em 0.6em; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+.win { color: #1a7f37; }
+.loss { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Backtest report</h1>
+<p class="muted">Generated {{.Generated}} &middot; {{duration .Metrics.TotalDuration}} of data</p>
+
+{{with .Metrics}}
+<div class="grid">
+  <div class="card"><div class="label">Total return</div><div class="value {{if .TotalReturn.IsNegative}}loss{{else}}win{{end}}">${{.TotalReturn.StringFixed 2}} ({{.TotalReturnPct.StringFixed 2}}%)</div></div>
+  <div class="card"><div class="label">Annualized return</div><div class="value">{{.AnnualizedReturn.StringFixed 2}}%</div></div>
+  <div class="card"><div class="label">Max drawdown</div><div class="value loss">${{.MaxDrawdown.StringFixed 2}} ({{.MaxDrawdownPct.StringFixed 2}}%)</div></div>
+  <div class="card"><div class="label">Sharpe ratio</div><div class="value">{{.SharpeRatio.StringFixed 2}}</div></div>
+  <div class="card"><div class="label">Trades</div><div class="value">{{.TotalTrades}} ({{.WinningTrades}}W / {{.LosingTrades}}L)</div></div>
+  <div class="card"><div class="label">Win rate</div><div class="value">{{.WinRate.StringFixed 2}}%</div></div>
+  <div class="card"><div class="label">Profit factor</div><div class="value">{{.ProfitFactor.StringFixed 2}}</div></div>
+  <div class="card"><div class="label">Avg trade duration</div><div class="value">{{duration .AvgTradeDuration}}</div></div>
+  <div class="card"><div class="label">Largest win / loss</div><div class="value">${{.LargestWin.StringFixed 2}} / ${{.LargestLoss.StringFixed 2}}</div></div>
+  <div class="card"><div class="label">Carry costs</div><div class="value">${{.TotalCarryCost.StringFixed 2}}</div></div>
+</div>
+{{end}}
+
+<div class="chart"><h2>Equity curve</h2><canvas id="equity" height="90"></canvas></div>
+<div class="chart"><h2>Drawdown</h2><canvas id="drawdown" height="60"></canvas></div>
+<div class="charts">
+  <div class="chart"><h2>Trade returns</h2><canvas id="returns"></canvas></div>
+  <div class="chart"><h2>Trade durations</h2><canvas id="durations"></canvas></div>
+</div>
+
+{{if .Metrics.Benchmarks}}
+<h2>Benchmarks</h2>
+<table>
+<tr><th>Benchmark</th><th>Return</th><th>Excess return</th><th>Alpha (annual)</th><th>Beta</th><th>Correlation</th></tr>
+{{range .Metrics.Benchmarks}}<tr>
+<td>{{.Benchmark}}</td><td>{{printf "%.2f" .BenchmarkReturnPct}}%</td>
+<td class="{{if lt .ExcessReturnPct 0.0}}loss{{else}}win{{end}}">{{printf "%+.2f" .ExcessReturnPct}}%</td>
+<td>{{printf "%.2f" .Alpha}}%</td><td>{{printf "%.2f" .Beta}}</td><td>{{printf "%.2f" .Correlation}}</td>
+</tr>
+{{end}}</table>
+{{end}}
+
+{{if .Parameters}}
+<h2>Parameters</h2>
+<table>
+{{range .Parameters}}<tr><td>{{.Name}}</td><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{end}}
+
+{{if .Trades}}
+<h2>Trades</h2>
+<table>
+<tr><th>#</th><th>Side</th><th>Entry time</th><th>Exit time</th><th>Entry</th><th>Exit</th><th>Amount</th><th>P&amp;L</th><th>P&amp;L %</th><th>Exit reason</th></tr>
+{{range $i, $t := .Trades}}<tr>
+<td>{{inc $i}}</td><td>{{$t.Side}}</td><td>{{datetime $t.EntryTime}}</td><td>{{datetime $t.ExitTime}}</td>
+<td>{{$t.EntryPrice.StringFixed 2}}</td><td>{{$t.ExitPrice.StringFixed 2}}</td><td>{{$t.Amount.StringFixed 4}}</td>
+<td class="{{if $t.PnL.IsNegative}}loss{{else}}win{{end}}">{{$t.PnL.StringFixed 2}}</td><td>{{$t.PnLPercent.StringFixed 2}}%</td><td>{{$t.ExitReason}}</td>
+</tr>
+{{end}}</table>
+{{end}}
+
+<script>
+const data = {{.Charts}};
+if (window.Chart) {
+  const options = { interaction: { mode: "index", intersect: false }, plugins: { legend: { display: false } } };
+  new Chart(document.getElementById("equity"), {
+    type: "line",
+    data: { labels: data.times, datasets: [{ label: "Equity", data: data.equity, borderColor: "#0969da", pointRadius: 0, borderWidth: 1.5 }] },
+    options,
+  });
+  new Chart(document.getElementById("drawdown"), {
+    type: "line",
+    data: { labels: data.times, datasets: [{ label: "Drawdown %", data: data.drawdown, borderColor: "#cf222e", backgroundColor: "rgba(207,34,46,0.15)", fill: true, pointRadius: 0, borderWidth: 1 }] },
+    options,
+  });
+  new Chart(document.getElementById("returns"), {
+    type: "bar",
+    data: { labels: data.pnl_bins, datasets: [{ label: "Trades", data: data.pnl_counts, backgroundColor: "#0969da" }] },
+    options,
+  });
+  new Chart(document.getElementById("durations"), {
+    type: "bar",
+    data: { labels: data.duration_bins, datasets: [{ label: "Trades", data: data.duration_counts, backgroundColor: "#8250df" }] },
+    options,
+  });
+} else {
+  document.querySelectorAll("canvas").forEach(c => c.replaceWith("Charts need network access to load Chart.js."));
+}
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,109 @@
+package backtesting
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/benchmark"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func reportMetrics() *PerformanceMetrics {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &PerformanceMetrics{
+		TotalReturn:    decimal.NewFromInt(-50),
+		TotalReturnPct: decimal.NewFromFloat(-0.5),
+		TotalTrades:    2,
+		WinningTrades:  1,
+		LosingTrades:   1,
+		TotalDuration:  2 * time.Hour,
+		Trades: []Trade{
+			{Side: exchanges.OrderSideBuy, EntryTime: start, ExitTime: start.Add(10 * time.Minute), PnL: decimal.NewFromInt(100), PnLPercent: decimal.NewFromInt(1), ExitReason: "take_profit"},
+			{Side: exchanges.OrderSideSell, EntryTime: start.Add(time.Hour), ExitTime: start.Add(90 * time.Minute), PnL: decimal.NewFromInt(-150), PnLPercent: decimal.NewFromFloat(-1.5), ExitReason: "stop_loss<script>"},
+		},
+		EquityCurve: []EquityPoint{
+			{Time: start, Equity: decimal.NewFromInt(10000)},
+			{Time: start.Add(time.Hour), Equity: decimal.NewFromInt(10100)},
+			{Time: start.Add(2 * time.Hour), Equity: decimal.NewFromInt(9950)},
+		},
+		Benchmarks: []benchmark.Comparison{
+			{Benchmark: "Buy & hold BTC-USD", BenchmarkReturnPct: 1.2, ExcessReturnPct: -1.7, Beta: 0.4},
+		},
+	}
+}
+
+func TestGenerateHTML(t *testing.T) {
+	var buf bytes.Buffer
+	params := []ReportParameter{{Name: "Short EMA", Value: "9"}}
+
+	if err := NewReporter().GenerateHTML(&buf, reportMetrics(), params); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	html := buf.String()
+
+	for _, want := range []string{
+		"<canvas id=\"equity\"", "<canvas id=\"drawdown\"", "<canvas id=\"returns\"", "<canvas id=\"durations\"",
+		"Short EMA", "$-50.00", "take_profit",
+		`"equity":[10000,10100,9950]`,
+		"Buy &amp; hold BTC-USD", "-1.70%",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report should contain %q", want)
+		}
+	}
+	if strings.Contains(html, "stop_loss<script>") {
+		t.Error("trade fields must be escaped")
+	}
+}
+
+func TestBuildChartData_Drawdown(t *testing.T) {
+	data := buildChartData(reportMetrics())
+
+	if len(data.Drawdown) != 3 || data.Drawdown[0] != 0 || data.Drawdown[1] != 0 {
+		t.Fatalf("unexpected drawdown: %v", data.Drawdown)
+	}
+	want := (9950.0 - 10100.0) / 10100.0 * 100
+	if diff := data.Drawdown[2] - want; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("drawdown = %v, want %v", data.Drawdown[2], want)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	labels, counts := histogram([]float64{0, 1, 2, 3, 10}, 5, "%.0f")
+	if len(labels) != 5 || labels[0] != "0" || labels[4] != "8" {
+		t.Errorf("unexpected labels: %v", labels)
+	}
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	if total != 5 || counts[0] != 2 || counts[4] != 1 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+
+	if labels, counts := histogram([]float64{2, 2}, 5, "%.0f"); len(labels) != 1 || counts[0] != 2 {
+		t.Errorf("identical values should fall in one bin, got %v %v", labels, counts)
+	}
+	if labels, _ := histogram(nil, 5, "%.0f"); labels != nil {
+		t.Error("no values should give no bins")
+	}
+}
+
+func TestWriteHTMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := NewReporter().WriteHTMLFile(path, reportMetrics(), nil); err != nil {
+		t.Fatalf("WriteHTMLFile failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("<!DOCTYPE html>")) {
+		t.Error("report file should be an HTML document")
+	}
+}
@@ -0,0 +1,234 @@
+package backtesting
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/parquet-go/parquet-go"
+	"github.com/shopspring/decimal"
+)
+
+// parquetColumns are the columns a Parquet data file must hold
+var parquetColumns = []string{"timestamp", "open", "high", "low", "close", "volume"}
+
+// parquetCandle is a row of the Parquet data files written by
+// AppendToParquet, with timestamps in milliseconds since the epoch in UTC
+type parquetCandle struct {
+	Timestamp int64   `parquet:"timestamp,timestamp(millisecond)"`
+	Open      float64 `parquet:"open"`
+	High      float64 `parquet:"high"`
+	Low       float64 `parquet:"low"`
+	Close     float64 `parquet:"close"`
+	Volume    float64 `parquet:"volume"`
+}
+
+// parquetRow is a row read from a Parquet data file. The timestamp is read
+// raw and converted with the unit of the file's column.
+type parquetRow struct {
+	Timestamp int64   `parquet:"timestamp"`
+	Open      float64 `parquet:"open"`
+	High      float64 `parquet:"high"`
+	Low       float64 `parquet:"low"`
+	Close     float64 `parquet:"close"`
+	Volume    float64 `parquet:"volume"`
+}
+
+// parquetTimestamp converts the raw values of a Parquet timestamp column
+type parquetTimestamp struct {
+	unit time.Duration // Zero for plain integers, read as Unix seconds or milliseconds
+	utc  bool          // False for local wall times, read in the loader's location
+}
+
+// LoadFromParquet loads historical candle data from a Parquet file with
+// timestamp, open, high, low, close and volume columns. Prices and volumes
+// may be any integer or floating point type. Timestamps are either Parquet
+// timestamps of any unit, those not adjusted to UTC being read in the
+// loader's location, or plain integers of Unix seconds or milliseconds.
+func (dl *DataLoader) LoadFromParquet(filename string, symbol string) (*HistoricalData, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	pf, err := parquet.OpenFile(file, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Parquet file: %w", err)
+	}
+	timestamps, err := validateParquetSchema(pf.Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]parquetRow, pf.NumRows())
+	reader := parquet.NewGenericReader[parquetRow](pf)
+	defer reader.Close()
+	n, err := reader.Read(rows)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read Parquet rows: %w", err)
+	}
+
+	candles := make([]exchanges.Candle, 0, n)
+	for _, row := range rows[:n] {
+		candles = append(candles, exchanges.Candle{
+			Symbol:    symbol,
+			Timestamp: dl.parquetTime(row.Timestamp, timestamps),
+			Open:      decimal.NewFromFloat(row.Open),
+			High:      decimal.NewFromFloat(row.High),
+			Low:       decimal.NewFromFloat(row.Low),
+			Close:     decimal.NewFromFloat(row.Close),
+			Volume:    decimal.NewFromFloat(row.Volume),
+		})
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Timestamp.Before(candles[j].Timestamp)
+	})
+
+	return &HistoricalData{
+		Symbol:  symbol,
+		Candles: candles,
+	}, nil
+}
+
+// validateParquetSchema checks that schema holds the candle columns with
+// numeric types and returns how to read its timestamps
+func validateParquetSchema(schema *parquet.Schema) (parquetTimestamp, error) {
+	for _, name := range parquetColumns {
+		column, ok := schema.Lookup(name)
+		if !ok {
+			return parquetTimestamp{}, fmt.Errorf("missing %s column in Parquet file", name)
+		}
+		switch column.Node.Type().Kind() {
+		case parquet.Int32, parquet.Int64:
+		case parquet.Float, parquet.Double:
+			if name == "timestamp" {
+				return parquetTimestamp{}, fmt.Errorf("timestamp column must be an integer or a timestamp, not %s", column.Node.Type())
+			}
+		default:
+			return parquetTimestamp{}, fmt.Errorf("%s column must be numeric, not %s", name, column.Node.Type())
+		}
+	}
+
+	column, _ := schema.Lookup("timestamp")
+	logical := column.Node.Type().LogicalType()
+	if logical == nil || logical.Timestamp == nil {
+		return parquetTimestamp{utc: true}, nil
+	}
+	timestamps := parquetTimestamp{utc: logical.Timestamp.IsAdjustedToUTC}
+	switch unit := logical.Timestamp.Unit; {
+	case unit.Millis != nil:
+		timestamps.unit = time.Millisecond
+	case unit.Micros != nil:
+		timestamps.unit = time.Microsecond
+	default:
+		timestamps.unit = time.Nanosecond
+	}
+	return timestamps, nil
+}
+
+// parquetTime converts a raw Parquet timestamp value
+func (dl *DataLoader) parquetTime(value int64, timestamps parquetTimestamp) time.Time {
+	var t time.Time
+	switch {
+	case timestamps.unit == 0 && value > 10000000000:
+		t = time.UnixMilli(value)
+	case timestamps.unit == 0:
+		t = time.Unix(value, 0)
+	default:
+		t = time.Unix(0, value*int64(timestamps.unit))
+	}
+	if timestamps.utc {
+		return t
+	}
+
+	// Local timestamps hold the wall time as if it were UTC
+	location := dl.location
+	if location == nil {
+		location = time.UTC
+	}
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location)
+}
+
+// AppendToParquet appends the candles newer than the last one of the Parquet
+// file at filename, creating it if needed, and returns the number of candles
+// written. Parquet files cannot be appended to in place, so the file is
+// rewritten with the new candles and replaced atomically. Timestamps are
+// written in milliseconds in UTC.
+func (dl *DataLoader) AppendToParquet(filename string, candles []exchanges.Candle) (int, error) {
+	var existing []exchanges.Candle
+	if info, err := os.Stat(filename); err == nil && info.Size() > 0 {
+		data, err := dl.LoadFromParquet(filename, "")
+		if err != nil {
+			return 0, err
+		}
+		existing = data.Candles
+	}
+
+	if err := ValidateCandles(candles); err != nil {
+		return 0, err
+	}
+
+	var last time.Time
+	if n := len(existing); n > 0 {
+		last = existing[n-1].Timestamp
+	}
+	rows := make([]parquetCandle, 0, len(existing)+len(candles))
+	for _, candle := range existing {
+		rows = append(rows, newParquetCandle(candle))
+	}
+	written := 0
+	for _, candle := range candles {
+		if !candle.Timestamp.After(last) {
+			continue
+		}
+		rows = append(rows, newParquetCandle(candle))
+		written++
+	}
+	if written == 0 && existing != nil {
+		return 0, nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := tmp.Chmod(0o644); err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	if err := parquet.Write(tmp, rows); err != nil {
+		return 0, fmt.Errorf("failed to write Parquet file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write Parquet file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return 0, fmt.Errorf("failed to replace Parquet file: %w", err)
+	}
+	return written, nil
+}
+
+// newParquetCandle converts candle to a Parquet row
+func newParquetCandle(candle exchanges.Candle) parquetCandle {
+	return parquetCandle{
+		Timestamp: candle.Timestamp.UnixMilli(),
+		Open:      candle.Open.InexactFloat64(),
+		High:      candle.High.InexactFloat64(),
+		Low:       candle.Low.InexactFloat64(),
+		Close:     candle.Close.InexactFloat64(),
+		Volume:    candle.Volume.InexactFloat64(),
+	}
+}
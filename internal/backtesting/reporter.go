@@ -42,6 +42,21 @@ func (r *Reporter) GenerateReport(metrics *PerformanceMetrics) string {
 	sb.WriteString(fmt.Sprintf("Total Duration:       %s\n\n",
 		formatDuration(metrics.TotalDuration)))
 
+	// Benchmarks
+	if len(metrics.Benchmarks) > 0 {
+		sb.WriteString("📐 BENCHMARKS\n")
+		sb.WriteString("───────────────────────────────────────────────────────\n")
+		for _, comparison := range metrics.Benchmarks {
+			sb.WriteString(fmt.Sprintf("%s\n", comparison.Benchmark))
+			sb.WriteString(fmt.Sprintf("  Return:             %.2f%% (excess %+.2f%%)\n",
+				comparison.BenchmarkReturnPct, comparison.ExcessReturnPct))
+			sb.WriteString(fmt.Sprintf("  Alpha (annual):     %.2f%%\n", comparison.Alpha))
+			sb.WriteString(fmt.Sprintf("  Beta:               %.2f\n", comparison.Beta))
+			sb.WriteString(fmt.Sprintf("  Correlation:        %.2f\n", comparison.Correlation))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Trade Statistics
 	sb.WriteString("📈 TRADE STATISTICS\n")
 	sb.WriteString("───────────────────────────────────────────────────────\n")
@@ -68,8 +83,10 @@ func (r *Reporter) GenerateReport(metrics *PerformanceMetrics) string {
 		metrics.AverageLossLose.StringFixed(2)))
 	sb.WriteString(fmt.Sprintf("Largest Win:          $%s\n",
 		metrics.LargestWin.StringFixed(2)))
-	sb.WriteString(fmt.Sprintf("Largest Loss:         $%s\n\n",
+	sb.WriteString(fmt.Sprintf("Largest Loss:         $%s\n",
 		metrics.LargestLoss.StringFixed(2)))
+	sb.WriteString(fmt.Sprintf("Carry Costs:          $%s\n\n",
+		metrics.TotalCarryCost.StringFixed(2)))
 
 	// Recent Trades
 	if len(metrics.Trades) > 0 {
@@ -118,6 +135,43 @@ func (r *Reporter) GenerateSummary(metrics *PerformanceMetrics) string {
 	)
 }
 
+// GenerateLeaderboard generates a table ranking the symbols of a batch
+// backtest, with the rank the symbol selector gives each one for comparison
+func (r *Reporter) GenerateLeaderboard(results []BatchResult) string {
+	var sb strings.Builder
+
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n")
+	sb.WriteString("                             SYMBOL LEADERBOARD\n")
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n\n")
+	sb.WriteString(fmt.Sprintf("%-4s %-14s %9s %7s %8s %8s %7s %7s %11s\n",
+		"#", "Symbol", "Return", "Trades", "Win Rate", "Max DD", "PF", "Sharpe", "Selector"))
+	sb.WriteString("───────────────────────────────────────────────────────────────────────────────\n")
+
+	for _, result := range results {
+		if result.Err != nil {
+			sb.WriteString(fmt.Sprintf("%-4d %-14s failed: %v\n", result.Rank, result.Symbol, result.Err))
+			continue
+		}
+		m := result.Metrics
+		sb.WriteString(fmt.Sprintf("%-4d %-14s %8.2f%% %7d %7.2f%% %7.2f%% %7.2f %7.2f %5.2f (#%d)\n",
+			result.Rank,
+			result.Symbol,
+			m.TotalReturnPct.InexactFloat64(),
+			m.TotalTrades,
+			m.WinRate.InexactFloat64(),
+			m.MaxDrawdownPct.InexactFloat64(),
+			m.ProfitFactor.InexactFloat64(),
+			m.SharpeRatio.InexactFloat64(),
+			result.SelectorScore,
+			result.SelectorRank,
+		))
+	}
+
+	sb.WriteString("═══════════════════════════════════════════════════════════════════════════════\n")
+
+	return sb.String()
+}
+
 // GenerateTradeLog generates a detailed trade log
 func (r *Reporter) GenerateTradeLog(metrics *PerformanceMetrics) string {
 	var sb strings.Builder
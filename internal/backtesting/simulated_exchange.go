@@ -3,6 +3,7 @@ package backtesting
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/shopspring/decimal"
@@ -13,6 +14,7 @@ type SimulatedExchange struct {
 	data         *HistoricalData
 	config       *BacktestConfig
 	currentIndex int
+	leverage     map[string]exchanges.LeverageSettings
 }
 
 // NewSimulatedExchange creates a new simulated exchange
@@ -28,6 +30,32 @@ func (s *SimulatedExchange) SetCurrentCandle(index int) {
 	s.currentIndex = index
 }
 
+// visibleIndex returns the index of the latest candle that has reached the
+// strategy at the current candle given the data latency, or -1 if none has
+func (s *SimulatedExchange) visibleIndex() int {
+	if s.currentIndex >= len(s.data.Candles) {
+		return len(s.data.Candles) - 1
+	}
+	if s.config.DataLatency <= 0 {
+		return s.currentIndex
+	}
+	cutoff := s.data.Candles[s.currentIndex].Timestamp.Add(-s.config.DataLatency)
+	index := s.currentIndex
+	for index >= 0 && s.data.Candles[index].Timestamp.After(cutoff) {
+		index--
+	}
+	return index
+}
+
+// orderArrived reports whether an order sent at sentAt has reached the
+// exchange by the current candle given the order latency
+func (s *SimulatedExchange) orderArrived(sentAt time.Time) bool {
+	if s.currentIndex >= len(s.data.Candles) {
+		return false
+	}
+	return !s.data.Candles[s.currentIndex].Timestamp.Before(sentAt.Add(s.config.OrderLatency))
+}
+
 // Connect simulates connecting to exchange
 func (s *SimulatedExchange) Connect(ctx context.Context) error {
 	return nil
@@ -43,13 +71,42 @@ func (s *SimulatedExchange) IsConnected() bool {
 	return true
 }
 
-// GetTicker returns the current ticker based on current candle
+const (
+	fundingPeriod = 8 * time.Hour
+	year          = 365 * 24 * time.Hour
+)
+
+// carryCost returns the funding, borrow and margin interest due on position
+// held for elapsed at price, when the account has capital. Negative costs
+// are funding received.
+func (s *SimulatedExchange) carryCost(position *Position, price, capital decimal.Decimal, elapsed time.Duration) decimal.Decimal {
+	if elapsed <= 0 {
+		return decimal.Zero
+	}
+	notional := position.Amount.Mul(price)
+	periods := decimal.NewFromFloat(elapsed.Hours() / fundingPeriod.Hours())
+	years := decimal.NewFromFloat(elapsed.Hours() / year.Hours())
+
+	funding := notional.Mul(s.config.FundingRate).Mul(periods)
+	if position.Side == exchanges.OrderSideBuy {
+		borrowed := decimal.Max(notional.Sub(capital), decimal.Zero)
+		return funding.Add(borrowed.Mul(s.config.MarginInterestRate).Mul(years))
+	}
+	return funding.Neg().Add(notional.Mul(s.config.BorrowRate).Mul(years))
+}
+
+// GetTicker returns the ticker of the latest candle visible given the data
+// latency
 func (s *SimulatedExchange) GetTicker(ctx context.Context, symbol string) (*exchanges.Ticker, error) {
 	if s.currentIndex >= len(s.data.Candles) {
 		return nil, fmt.Errorf("no more data")
 	}
+	visible := s.visibleIndex()
+	if visible < 0 {
+		return nil, fmt.Errorf("no data yet")
+	}
 
-	candle := s.data.Candles[s.currentIndex]
+	candle := s.data.Candles[visible]
 
 	return &exchanges.Ticker{
 		Symbol:    symbol,
@@ -66,8 +123,12 @@ func (s *SimulatedExchange) GetOrderBook(ctx context.Context, symbol string, dep
 	if s.currentIndex >= len(s.data.Candles) {
 		return nil, fmt.Errorf("no more data")
 	}
+	visible := s.visibleIndex()
+	if visible < 0 {
+		return nil, fmt.Errorf("no data yet")
+	}
 
-	candle := s.data.Candles[s.currentIndex]
+	candle := s.data.Candles[visible]
 	spread := candle.Close.Mul(decimal.NewFromFloat(0.0001)) // 0.01% spread
 
 	// Create simple order book with current price
@@ -83,18 +144,19 @@ func (s *SimulatedExchange) GetOrderBook(ctx context.Context, symbol string, dep
 	}, nil
 }
 
-// GetCandles returns historical candles up to current index
+// GetCandles returns historical candles up to the latest visible candle
 func (s *SimulatedExchange) GetCandles(ctx context.Context, symbol string, interval string, limit int) ([]exchanges.Candle, error) {
 	if s.currentIndex >= len(s.data.Candles) {
 		return nil, fmt.Errorf("no more data")
 	}
 
-	start := s.currentIndex - limit + 1
+	visible := s.visibleIndex()
+	start := visible - limit + 1
 	if start < 0 {
 		start = 0
 	}
 
-	return s.data.Candles[start : s.currentIndex+1], nil
+	return s.data.Candles[start : visible+1], nil
 }
 
 // SubscribeTicker not implemented for simulated exchange
@@ -173,3 +235,50 @@ func (s *SimulatedExchange) Name() string {
 func (s *SimulatedExchange) SupportedSymbols() []string {
 	return []string{s.data.Symbol}
 }
+
+// Capabilities returns the features of the simulation; shorts follow the
+// backtest configuration
+func (s *SimulatedExchange) Capabilities() exchanges.Capabilities {
+	return exchanges.Capabilities{
+		Shorts:     s.config.AllowShort,
+		StopOrders: true,
+		PostOnly:   true,
+		Margin:     true,
+	}
+}
+
+// SetLeverage records the leverage of symbol
+func (s *SimulatedExchange) SetLeverage(ctx context.Context, symbol string, leverage decimal.Decimal) error {
+	settings := s.leverageSettings(symbol)
+	settings.Leverage = leverage
+	s.leverage[symbol] = settings
+	return nil
+}
+
+// SetMarginMode records the margin mode of symbol
+func (s *SimulatedExchange) SetMarginMode(ctx context.Context, symbol string, mode exchanges.MarginMode) error {
+	settings := s.leverageSettings(symbol)
+	settings.MarginMode = mode
+	s.leverage[symbol] = settings
+	return nil
+}
+
+// GetLeverage returns the recorded leverage of symbol, 1x cross by default
+func (s *SimulatedExchange) GetLeverage(ctx context.Context, symbol string) (*exchanges.LeverageSettings, error) {
+	settings := s.leverageSettings(symbol)
+	return &settings, nil
+}
+
+func (s *SimulatedExchange) leverageSettings(symbol string) exchanges.LeverageSettings {
+	if s.leverage == nil {
+		s.leverage = make(map[string]exchanges.LeverageSettings)
+	}
+	if settings, ok := s.leverage[symbol]; ok {
+		return settings
+	}
+	return exchanges.LeverageSettings{
+		Symbol:     symbol,
+		Leverage:   decimal.NewFromInt(1),
+		MarginMode: exchanges.MarginModeCross,
+	}
+}
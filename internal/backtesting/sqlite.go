@@ -0,0 +1,227 @@
+package backtesting
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema creates the candles table of the SQLite data files written by
+// AppendToSQLite, with timestamps in milliseconds since the epoch in UTC
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS candles (
+	timestamp INTEGER PRIMARY KEY,
+	open REAL NOT NULL,
+	high REAL NOT NULL,
+	low REAL NOT NULL,
+	close REAL NOT NULL,
+	volume REAL NOT NULL
+)`
+
+// LoadFromSQLite loads historical candle data from the candles table of a
+// SQLite database, with timestamp, open, high, low, close and volume
+// columns. Timestamps are either integers of Unix seconds or milliseconds or
+// text in any format LoadFromCSV reads, those without an offset being read
+// in the loader's location.
+func (dl *DataLoader) LoadFromSQLite(filename string, symbol string) (*HistoricalData, error) {
+	// Opening a missing database would create it
+	if _, err := os.Stat(filename); err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+filename+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	candles, err := dl.readSQLiteCandles(db, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &HistoricalData{
+		Symbol:  symbol,
+		Candles: candles,
+	}, nil
+}
+
+// readSQLiteCandles validates the candles table of db and reads its rows in
+// time order
+func (dl *DataLoader) readSQLiteCandles(db *sql.DB, symbol string) ([]exchanges.Candle, error) {
+	if err := validateSQLiteSchema(db); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query(`SELECT timestamp, open, high, low, close, volume FROM candles`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query candles: %w", err)
+	}
+	defer rows.Close()
+
+	candles := make([]exchanges.Candle, 0)
+	for rows.Next() {
+		var values [6]any
+		if err := rows.Scan(&values[0], &values[1], &values[2], &values[3], &values[4], &values[5]); err != nil {
+			return nil, fmt.Errorf("failed to read candle: %w", err)
+		}
+		candle, err := dl.parseSQLiteRow(values, symbol)
+		if err != nil {
+			return nil, err
+		}
+		candles = append(candles, candle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read candles: %w", err)
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Timestamp.Before(candles[j].Timestamp)
+	})
+	return candles, nil
+}
+
+// validateSQLiteSchema checks that db has a candles table with the candle
+// columns
+func validateSQLiteSchema(db *sql.DB) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info('candles')`)
+	if err != nil {
+		return fmt.Errorf("failed to read database schema: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return fmt.Errorf("failed to read database schema: %w", err)
+		}
+		columns[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read database schema: %w", err)
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("missing candles table in database")
+	}
+	for _, name := range csvHeader {
+		if !columns[name] {
+			return fmt.Errorf("missing %s column in candles table", name)
+		}
+	}
+	return nil
+}
+
+// parseSQLiteRow converts the timestamp, open, high, low, close and volume
+// values of a candles row
+func (dl *DataLoader) parseSQLiteRow(values [6]any, symbol string) (exchanges.Candle, error) {
+	var raw string
+	switch v := values[0].(type) {
+	case int64:
+		raw = strconv.FormatInt(v, 10)
+	case float64:
+		raw = strconv.FormatInt(int64(v), 10)
+	case string:
+		raw = v
+	default:
+		return exchanges.Candle{}, fmt.Errorf("invalid timestamp %v", v)
+	}
+	timestamp, err := dl.parseTimestamp(raw)
+	if err != nil {
+		return exchanges.Candle{}, err
+	}
+
+	var prices [5]decimal.Decimal
+	for i, value := range values[1:] {
+		switch v := value.(type) {
+		case int64:
+			prices[i] = decimal.NewFromInt(v)
+		case float64:
+			prices[i] = decimal.NewFromFloat(v)
+		case string:
+			price, err := decimal.NewFromString(v)
+			if err != nil {
+				return exchanges.Candle{}, fmt.Errorf("invalid %s at %s: %w", csvHeader[i+1], timestamp.Format(time.RFC3339), err)
+			}
+			prices[i] = price
+		default:
+			return exchanges.Candle{}, fmt.Errorf("invalid %s at %s: %v", csvHeader[i+1], timestamp.Format(time.RFC3339), v)
+		}
+	}
+
+	return exchanges.Candle{
+		Symbol:    symbol,
+		Timestamp: timestamp,
+		Open:      prices[0],
+		High:      prices[1],
+		Low:       prices[2],
+		Close:     prices[3],
+		Volume:    prices[4],
+	}, nil
+}
+
+// AppendToSQLite appends the candles newer than the last one of the SQLite
+// database at filename, creating it and its candles table if needed, and
+// returns the number of candles written. The candles are inserted in a
+// single transaction with timestamps in milliseconds in UTC.
+func (dl *DataLoader) AppendToSQLite(filename string, candles []exchanges.Candle) (int, error) {
+	if err := ValidateCandles(candles); err != nil {
+		return 0, err
+	}
+
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return 0, fmt.Errorf("failed to create candles table: %w", err)
+	}
+	existing, err := dl.readSQLiteCandles(db, "")
+	if err != nil {
+		return 0, err
+	}
+	var last time.Time
+	if n := len(existing); n > 0 {
+		last = existing[n-1].Timestamp
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO candles (timestamp, open, high, low, close, volume) VALUES (?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	written := 0
+	for _, candle := range candles {
+		if !candle.Timestamp.After(last) {
+			continue
+		}
+		if _, err := stmt.Exec(
+			candle.Timestamp.UnixMilli(),
+			candle.Open.InexactFloat64(),
+			candle.High.InexactFloat64(),
+			candle.Low.InexactFloat64(),
+			candle.Close.InexactFloat64(),
+			candle.Volume.InexactFloat64(),
+		); err != nil {
+			return 0, fmt.Errorf("failed to insert candle at %s: %w", candle.Timestamp.Format(time.RFC3339), err)
+		}
+		written++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit candles: %w", err)
+	}
+	return written, nil
+}
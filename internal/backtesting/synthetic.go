@@ -0,0 +1,216 @@
+package backtesting
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// Regime parameterizes the price process of synthetic data. Returns are
+// per candle and follow a GARCH(1,1) process, so large moves cluster, with
+// occasional jumps on top.
+type Regime struct {
+	Name string
+	// Drift is the mean log return per candle: the trend strength, negative
+	// for downtrends
+	Drift float64
+	// Volatility is the long-run standard deviation of returns per candle
+	Volatility float64
+	// Alpha and Beta weigh the last squared return and the last variance in
+	// the next variance; volatility clusters more as their sum nears 1.
+	// Their sum must be below 1.
+	Alpha float64
+	Beta  float64
+	// JumpProbability is the chance of a jump per candle, and JumpSize the
+	// standard deviation of its log return
+	JumpProbability float64
+	JumpSize        float64
+	// Spread is the bid-ask spread relative to the price. Trades print at
+	// the bid or the ask, widening wicks and bouncing closes around the mid.
+	Spread float64
+}
+
+// Validate checks that the regime describes a stationary process
+func (r Regime) Validate() error {
+	switch {
+	case r.Volatility < 0, r.Alpha < 0, r.Beta < 0, r.JumpSize < 0, r.Spread < 0:
+		return fmt.Errorf("regime %s: parameters must not be negative", r.Name)
+	case r.Alpha+r.Beta >= 1:
+		return fmt.Errorf("regime %s: alpha + beta must be below 1", r.Name)
+	case r.JumpProbability > 1:
+		return fmt.Errorf("regime %s: jump probability must be at most 1", r.Name)
+	case r.Spread >= 1:
+		return fmt.Errorf("regime %s: spread must be below 1", r.Name)
+	}
+	return nil
+}
+
+// Predefined regimes, per one minute candle
+var (
+	RegimeBull = Regime{
+		Name: "bull", Drift: 0.0004, Volatility: 0.002, Alpha: 0.05, Beta: 0.9,
+		JumpProbability: 0.001, JumpSize: 0.01, Spread: 0.0002,
+	}
+	RegimeBear = Regime{
+		Name: "bear", Drift: -0.0004, Volatility: 0.0025, Alpha: 0.08, Beta: 0.88,
+		JumpProbability: 0.002, JumpSize: 0.015, Spread: 0.0003,
+	}
+	RegimeRange = Regime{
+		Name: "range", Drift: 0, Volatility: 0.0015, Alpha: 0.03, Beta: 0.9,
+		Spread: 0.0002,
+	}
+	RegimeVolatile = Regime{
+		Name: "volatile", Drift: 0, Volatility: 0.006, Alpha: 0.12, Beta: 0.85,
+		JumpProbability: 0.01, JumpSize: 0.02, Spread: 0.001,
+	}
+	RegimeCrash = Regime{
+		Name: "crash", Drift: -0.002, Volatility: 0.008, Alpha: 0.15, Beta: 0.83,
+		JumpProbability: 0.03, JumpSize: 0.04, Spread: 0.003,
+	}
+)
+
+// Regimes returns the predefined regimes by name
+func Regimes() map[string]Regime {
+	return map[string]Regime{
+		RegimeBull.Name:     RegimeBull,
+		RegimeBear.Name:     RegimeBear,
+		RegimeRange.Name:    RegimeRange,
+		RegimeVolatile.Name: RegimeVolatile,
+		RegimeCrash.Name:    RegimeCrash,
+	}
+}
+
+// RegimePhase is a number of candles generated under one regime
+type RegimePhase struct {
+	Regime  Regime
+	Candles int
+}
+
+// ParseRegimePhases parses phases written as name:candles separated by
+// commas, e.g. "bull:500,crash:50,range:300", using the predefined regimes
+func ParseRegimePhases(spec string) ([]RegimePhase, error) {
+	regimes := Regimes()
+	var phases []RegimePhase
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, count, found := strings.Cut(part, ":")
+		regime, exists := regimes[name]
+		if !exists {
+			names := make([]string, 0, len(regimes))
+			for n := range regimes {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown regime %q, expected one of %s", name, strings.Join(names, ", "))
+		}
+		if !found {
+			return nil, fmt.Errorf("regime %s: missing candle count, e.g. %s:500", name, name)
+		}
+		candles, err := strconv.Atoi(count)
+		if err != nil || candles <= 0 {
+			return nil, fmt.Errorf("regime %s: invalid candle count %q", name, count)
+		}
+		phases = append(phases, RegimePhase{Regime: regime, Candles: candles})
+	}
+	if len(phases) == 0 {
+		return nil, fmt.Errorf("no regime phases in %q", spec)
+	}
+	return phases, nil
+}
+
+// regimeSubsteps is the number of steps simulated within each candle to draw
+// its high and low
+const regimeSubsteps = 8
+
+// GenerateRegimeData generates one minute candles going through phases in
+// order, starting at basePrice. The same seed always generates the same data.
+func (dl *DataLoader) GenerateRegimeData(symbol string, startTime time.Time, basePrice float64, seed int64, phases []RegimePhase) (*HistoricalData, error) {
+	total := 0
+	for _, phase := range phases {
+		if err := phase.Regime.Validate(); err != nil {
+			return nil, err
+		}
+		total += phase.Candles
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	data := &HistoricalData{
+		Symbol:  symbol,
+		Candles: make([]exchanges.Candle, 0, total),
+	}
+
+	mid := basePrice
+	timestamp := startTime
+	lastReturn := 0.0
+	variance := -1.0
+	for _, phase := range phases {
+		regime := phase.Regime
+		longRun := regime.Volatility * regime.Volatility
+		omega := longRun * (1 - regime.Alpha - regime.Beta)
+		if variance < 0 {
+			variance = longRun
+		}
+
+		for i := 0; i < phase.Candles; i++ {
+			variance = omega + regime.Alpha*lastReturn*lastReturn + regime.Beta*variance
+			sigma := math.Sqrt(variance)
+
+			logReturn := regime.Drift + sigma*rng.NormFloat64()
+			if regime.JumpProbability > 0 && rng.Float64() < regime.JumpProbability {
+				logReturn += regime.JumpSize * rng.NormFloat64()
+			}
+			lastReturn = logReturn - regime.Drift
+
+			// Walk the mid through the candle to its close
+			halfSpread := regime.Spread / 2
+			open := mid * (1 + halfSpread*bidAskSign(rng))
+			high, low := open, open
+			step := logReturn / regimeSubsteps
+			stepSigma := sigma / math.Sqrt(regimeSubsteps)
+			path := math.Log(mid)
+			target := path + logReturn
+			for s := 1; s < regimeSubsteps; s++ {
+				path += step + stepSigma*rng.NormFloat64()
+				price := math.Exp(path)
+				high = math.Max(high, price*(1+halfSpread))
+				low = math.Min(low, price*(1-halfSpread))
+			}
+			mid = math.Exp(target)
+			close := mid * (1 + halfSpread*bidAskSign(rng))
+			high = math.Max(high, math.Max(open, close))
+			low = math.Min(low, math.Min(open, close))
+
+			volume := 1000 * (1 + math.Abs(logReturn)/math.Max(regime.Volatility, 1e-9)) * (0.5 + rng.Float64())
+
+			data.Candles = append(data.Candles, exchanges.Candle{
+				Symbol:    symbol,
+				Timestamp: timestamp,
+				Open:      decimal.NewFromFloat(open).Round(8),
+				High:      decimal.NewFromFloat(high).Round(8),
+				Low:       decimal.NewFromFloat(low).Round(8),
+				Close:     decimal.NewFromFloat(close).Round(8),
+				Volume:    decimal.NewFromFloat(volume).Round(4),
+			})
+			timestamp = timestamp.Add(time.Minute)
+		}
+	}
+	return data, nil
+}
+
+// bidAskSign returns -1 for a print at the bid and 1 for one at the ask
+func bidAskSign(rng *rand.Rand) float64 {
+	if rng.Intn(2) == 0 {
+		return -1
+	}
+	return 1
+}
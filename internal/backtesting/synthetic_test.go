@@ -0,0 +1,66 @@
+package backtesting
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/testutils"
+)
+
+func realizedVolatility(data *HistoricalData) float64 {
+	var sum, sumSquares float64
+	n := 0
+	for i := 1; i < len(data.Candles); i++ {
+		r := math.Log(data.Candles[i].Close.InexactFloat64() / data.Candles[i-1].Close.InexactFloat64())
+		sum += r
+		sumSquares += r * r
+		n++
+	}
+	mean := sum / float64(n)
+	return math.Sqrt(sumSquares/float64(n) - mean*mean)
+}
+
+func TestDataLoader_GenerateRegimeData(t *testing.T) {
+	loader := NewDataLoader()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	generate := func(seed int64, phases ...RegimePhase) *HistoricalData {
+		data, err := loader.GenerateRegimeData("BTC-USD", start, 50000, seed, phases)
+		testutils.AssertNoError(t, err, "GenerateRegimeData should not return error")
+		testutils.AssertNoError(t, ValidateCandles(data.Candles), "generated candles should be consistent")
+		return data
+	}
+
+	bull := generate(1, RegimePhase{Regime: RegimeBull, Candles: 2000})
+	testutils.AssertEqual(t, 2000, len(bull.Candles), "every phase candle should be generated")
+	testutils.AssertTrue(t, bull.Candles[1999].Close.InexactFloat64() > 50000, "bull regime should trend up")
+
+	bear := generate(1, RegimePhase{Regime: RegimeBear, Candles: 2000})
+	testutils.AssertTrue(t, bear.Candles[1999].Close.InexactFloat64() < 50000, "bear regime should trend down")
+
+	calm := generate(2, RegimePhase{Regime: RegimeRange, Candles: 2000})
+	wild := generate(2, RegimePhase{Regime: RegimeVolatile, Candles: 2000})
+	testutils.AssertTrue(t, realizedVolatility(wild) > 2*realizedVolatility(calm), "volatile regime should move more than a range")
+
+	again := generate(1, RegimePhase{Regime: RegimeBull, Candles: 2000})
+	testutils.AssertTrue(t, again.Candles[1999].Close.Equal(bull.Candles[1999].Close), "same seed should generate the same data")
+
+	phased := generate(3, RegimePhase{Regime: RegimeBull, Candles: 100}, RegimePhase{Regime: RegimeCrash, Candles: 50})
+	testutils.AssertEqual(t, 150, len(phased.Candles), "phases should follow each other")
+
+	_, err := loader.GenerateRegimeData("BTC-USD", start, 50000, 1, []RegimePhase{{Regime: Regime{Name: "explosive", Alpha: 0.5, Beta: 0.6}, Candles: 10}})
+	testutils.AssertError(t, err, "non-stationary regime should be rejected")
+}
+
+func TestParseRegimePhases(t *testing.T) {
+	phases, err := ParseRegimePhases("bull:500, crash:50")
+	testutils.AssertNoError(t, err, "ParseRegimePhases should not return error")
+	testutils.AssertEqual(t, 2, len(phases), "every phase should be parsed")
+	testutils.AssertEqual(t, "crash", phases[1].Regime.Name, "phase should use the named regime")
+	testutils.AssertEqual(t, 50, phases[1].Candles, "phase should have its candle count")
+
+	for _, spec := range []string{"", "sideways:100", "bull", "bull:0"} {
+		_, err := ParseRegimePhases(spec)
+		testutils.AssertError(t, err, "invalid spec "+spec+" should be rejected")
+	}
+}
@@ -3,6 +3,7 @@ package backtesting
 import (
 	"time"
 
+	"github.com/guyghost/constantine/internal/benchmark"
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/shopspring/decimal"
 )
@@ -28,7 +29,8 @@ type Trade struct {
 	Commission decimal.Decimal
 	StopLoss   decimal.Decimal
 	TakeProfit decimal.Decimal
-	ExitReason string // "stop_loss", "take_profit", "signal", "end_of_data"
+	ExitReason string          // "stop_loss", "take_profit", "signal", "end_of_data"
+	CarryCost  decimal.Decimal // Funding, borrow and margin interest paid while open
 }
 
 // Position represents an open position during backtesting
@@ -40,6 +42,9 @@ type Position struct {
 	EntryTime  time.Time
 	StopLoss   decimal.Decimal
 	TakeProfit decimal.Decimal
+	// CarryCost accrued since entry, and the time it was last accrued
+	CarryCost   decimal.Decimal
+	LastAccrual time.Time
 }
 
 // BacktestConfig holds configuration for backtesting
@@ -53,14 +58,30 @@ type BacktestConfig struct {
 	UseFixedAmount bool
 	FixedAmount    decimal.Decimal
 	RiskPerTrade   decimal.Decimal // e.g., 0.01 for 1% of capital
+	// NotionalPerTrade sizes entries at a share of capital instead of by
+	// risk, e.g. 0.1 for 10%, so symbols of any price trade alike
+	NotionalPerTrade decimal.Decimal
 
 	// Constraints
 	MaxPositions int
 	AllowShort   bool
 
+	// Latency
+	DataLatency  time.Duration // Delay before a candle is visible to the strategy
+	OrderLatency time.Duration // Delay before an order reaches the exchange and fills
+
+	// Carrying costs, accrued per bar on the notional of open positions
+	FundingRate        decimal.Decimal // Per 8 hours; longs pay shorts when positive
+	BorrowRate         decimal.Decimal // Annual rate shorts pay to borrow the asset
+	MarginInterestRate decimal.Decimal // Annual rate longs pay on notional above capital
+
 	// Time range
 	StartTime time.Time
 	EndTime   time.Time
+
+	// BenchmarkData is an optional symbol, such as BTC-USD, the results are
+	// compared with besides buy and hold of the traded symbol
+	BenchmarkData *HistoricalData
 }
 
 // DefaultBacktestConfig returns default backtesting configuration
@@ -97,6 +118,7 @@ type PerformanceMetrics struct {
 	LargestWin       decimal.Decimal
 	LargestLoss      decimal.Decimal
 	ProfitFactor     decimal.Decimal
+	TotalCarryCost   decimal.Decimal
 
 	// Risk metrics
 	MaxDrawdown    decimal.Decimal
@@ -107,6 +129,10 @@ type PerformanceMetrics struct {
 	AvgTradeDuration time.Duration
 	TotalDuration    time.Duration
 
+	// Benchmarks compares the equity curve with buy and hold of the traded
+	// symbol, and of the benchmark data when configured
+	Benchmarks []benchmark.Comparison
+
 	// Detailed records
 	Trades      []Trade
 	EquityCurve []EquityPoint
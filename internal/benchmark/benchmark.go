@@ -0,0 +1,144 @@
+// Package benchmark compares the returns of a strategy with a buy and hold
+// benchmark: the excess return, Jensen's alpha, the beta and the correlation
+// of the strategy's returns with the benchmark's.
+package benchmark
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const year = 365.25 * 24 * time.Hour
+
+// Point is a value of an equity curve or a price series at a point in time
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Comparison is the performance of a strategy relative to a benchmark over
+// the same period
+type Comparison struct {
+	Benchmark          string  `json:"benchmark"`
+	StrategyReturnPct  float64 `json:"strategy_return_pct"`
+	BenchmarkReturnPct float64 `json:"benchmark_return_pct"`
+	ExcessReturnPct    float64 `json:"excess_return_pct"`
+	// Alpha is Jensen's alpha with no risk-free rate, annualized, in percent:
+	// the return of the strategy not explained by its exposure to the
+	// benchmark
+	Alpha       float64 `json:"alpha"`
+	Beta        float64 `json:"beta"`
+	Correlation float64 `json:"correlation"`
+	Periods     int     `json:"periods"` // Number of returns compared
+}
+
+// BuyAndHold returns the value at each of times of an equal weight portfolio
+// of the price series bought at the first time, starting at 1. Each series
+// must be in time order; a series is valued at its last price at or before
+// the time, or its first price before it starts. Series without a positive
+// price are left out.
+func BuyAndHold(series [][]Point, times []time.Time) []Point {
+	if len(times) == 0 {
+		return nil
+	}
+	var held [][]Point
+	for _, prices := range series {
+		if len(prices) > 0 && priceAt(prices, times[0]) > 0 {
+			held = append(held, prices)
+		}
+	}
+	if len(held) == 0 {
+		return nil
+	}
+
+	points := make([]Point, len(times))
+	for i, t := range times {
+		value := 0.0
+		for _, prices := range held {
+			value += priceAt(prices, t) / priceAt(prices, times[0])
+		}
+		points[i] = Point{Time: t, Value: value / float64(len(held))}
+	}
+	return points
+}
+
+// priceAt returns the last price at or before t
+func priceAt(prices []Point, t time.Time) float64 {
+	i := sort.Search(len(prices), func(i int) bool { return prices[i].Time.After(t) })
+	if i == 0 {
+		return prices[0].Value
+	}
+	return prices[i-1].Value
+}
+
+// Compare compares the equity curve of a strategy with the values of the
+// benchmark named name at the same times. Alpha is annualized from the
+// average spacing of the equity curve.
+func Compare(name string, equity, benchmark []Point) (Comparison, error) {
+	if len(equity) != len(benchmark) {
+		return Comparison{}, fmt.Errorf("%d equity points for %d benchmark points", len(equity), len(benchmark))
+	}
+	if len(equity) < 3 {
+		return Comparison{}, fmt.Errorf("need at least 3 points to compare with %s, got %d", name, len(equity))
+	}
+	if equity[0].Value <= 0 || benchmark[0].Value <= 0 {
+		return Comparison{}, fmt.Errorf("the equity and %s must start positive", name)
+	}
+
+	comparison := Comparison{
+		Benchmark:          name,
+		StrategyReturnPct:  percentChange(equity[0].Value, equity[len(equity)-1].Value),
+		BenchmarkReturnPct: percentChange(benchmark[0].Value, benchmark[len(benchmark)-1].Value),
+	}
+	comparison.ExcessReturnPct = comparison.StrategyReturnPct - comparison.BenchmarkReturnPct
+
+	var strategyReturns, benchmarkReturns []float64
+	for i := 1; i < len(equity); i++ {
+		if equity[i-1].Value <= 0 || benchmark[i-1].Value <= 0 {
+			continue
+		}
+		strategyReturns = append(strategyReturns, equity[i].Value/equity[i-1].Value-1)
+		benchmarkReturns = append(benchmarkReturns, benchmark[i].Value/benchmark[i-1].Value-1)
+	}
+	comparison.Periods = len(strategyReturns)
+	if comparison.Periods < 2 {
+		return comparison, nil
+	}
+
+	meanStrategy, meanBenchmark := mean(strategyReturns), mean(benchmarkReturns)
+	var covariance, strategyVariance, benchmarkVariance float64
+	for i := range strategyReturns {
+		ds := strategyReturns[i] - meanStrategy
+		db := benchmarkReturns[i] - meanBenchmark
+		covariance += ds * db
+		strategyVariance += ds * ds
+		benchmarkVariance += db * db
+	}
+	if benchmarkVariance > 0 {
+		comparison.Beta = covariance / benchmarkVariance
+	}
+	if strategyVariance > 0 && benchmarkVariance > 0 {
+		comparison.Correlation = covariance / math.Sqrt(strategyVariance*benchmarkVariance)
+	}
+
+	span := equity[len(equity)-1].Time.Sub(equity[0].Time)
+	if span > 0 {
+		periodsPerYear := float64(year) / (float64(span) / float64(len(equity)-1))
+		comparison.Alpha = (meanStrategy - comparison.Beta*meanBenchmark) * periodsPerYear * 100
+	}
+	return comparison, nil
+}
+
+func percentChange(from, to float64) float64 {
+	return (to/from - 1) * 100
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
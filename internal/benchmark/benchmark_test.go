@@ -0,0 +1,83 @@
+package benchmark
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func day(i int) time.Time {
+	return start.AddDate(0, 0, i)
+}
+
+func TestBuyAndHold(t *testing.T) {
+	doubling := []Point{{day(0), 100}, {day(2), 200}}
+	// Starts late and is valued at its first price until then
+	flat := []Point{{day(1), 50}, {day(2), 50}}
+	empty := []Point{}
+
+	times := []time.Time{day(0), day(1), day(2), day(3)}
+	points := BuyAndHold([][]Point{doubling, flat, empty}, times)
+	want := []float64{1, 1, 1.5, 1.5}
+	if len(points) != len(want) {
+		t.Fatalf("expected %d points, got %d", len(want), len(points))
+	}
+	for i, point := range points {
+		if !point.Time.Equal(times[i]) || math.Abs(point.Value-want[i]) > 1e-9 {
+			t.Errorf("point %d: expected %v at %s, got %+v", i, want[i], times[i], point)
+		}
+	}
+
+	if BuyAndHold([][]Point{empty}, times) != nil {
+		t.Error("expected no benchmark without prices")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	// The strategy is the benchmark with twice the exposure
+	benchmarkReturns := []float64{0.01, -0.02, 0.03, 0.005, -0.01}
+	equity := []Point{{day(0), 1000}}
+	benchmark := []Point{{day(0), 1}}
+	for i, r := range benchmarkReturns {
+		equity = append(equity, Point{day(i + 1), equity[i].Value * (1 + 2*r)})
+		benchmark = append(benchmark, Point{day(i + 1), benchmark[i].Value * (1 + r)})
+	}
+
+	comparison, err := Compare("BTC-USD", equity, benchmark)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comparison.Periods != len(benchmarkReturns) {
+		t.Errorf("expected %d periods, got %d", len(benchmarkReturns), comparison.Periods)
+	}
+	if math.Abs(comparison.Beta-2) > 1e-9 || math.Abs(comparison.Correlation-1) > 1e-9 {
+		t.Errorf("expected a beta of 2 and a correlation of 1, got %v and %v", comparison.Beta, comparison.Correlation)
+	}
+	if math.Abs(comparison.Alpha) > 1e-9 {
+		t.Errorf("expected no alpha from leverage alone, got %v", comparison.Alpha)
+	}
+	if math.Abs(comparison.ExcessReturnPct-(comparison.StrategyReturnPct-comparison.BenchmarkReturnPct)) > 1e-9 {
+		t.Errorf("expected the excess return to be the difference of the returns, got %+v", comparison)
+	}
+
+	// A constant daily gain on top of the benchmark is alpha
+	for i := range equity {
+		equity[i].Value = benchmark[i].Value * math.Pow(1.001, float64(i))
+	}
+	comparison, err = Compare("BTC-USD", equity, benchmark)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comparison.Alpha < 30 || comparison.Alpha > 40 {
+		t.Errorf("expected about 36.5%% of annual alpha, got %v", comparison.Alpha)
+	}
+
+	if _, err := Compare("BTC-USD", equity[:2], benchmark[:2]); err == nil {
+		t.Error("expected an error with too few points")
+	}
+	if _, err := Compare("BTC-USD", equity, benchmark[1:]); err == nil {
+		t.Error("expected an error for misaligned series")
+	}
+}
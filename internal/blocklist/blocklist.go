@@ -0,0 +1,193 @@
+// Package blocklist holds the markets that must never be traded, such as
+// delisted or problematic ones, persisted across restarts
+package blocklist
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/statefile"
+)
+
+// Entry blocks a symbol on one exchange, or on every exchange when Exchange
+// is empty
+type Entry struct {
+	Exchange string    `json:"exchange,omitempty"`
+	Symbol   string    `json:"symbol"`
+	Reason   string    `json:"reason,omitempty"`
+	Since    time.Time `json:"since"`
+}
+
+// String returns the entry as parsed by ParseEntry
+func (e Entry) String() string {
+	if e.Exchange == "" {
+		return e.Symbol
+	}
+	return e.Exchange + ":" + e.Symbol
+}
+
+// ParseEntry parses a blocked market written as SYMBOL or EXCHANGE:SYMBOL
+func ParseEntry(value string) (Entry, error) {
+	value = strings.TrimSpace(value)
+	exchange, symbol, ok := strings.Cut(value, ":")
+	if !ok {
+		exchange, symbol = "", value
+	}
+	exchange = strings.ToLower(strings.TrimSpace(exchange))
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" || (ok && exchange == "") {
+		return Entry{}, fmt.Errorf("invalid blocked market %q: expected SYMBOL or EXCHANGE:SYMBOL", value)
+	}
+	return Entry{Exchange: exchange, Symbol: symbol}, nil
+}
+
+// List holds the blocked markets. Changes are saved to its file, when it
+// has one, before they are applied.
+type List struct {
+	mu      sync.RWMutex
+	entries map[string]Entry // Keyed by the entry's string
+	path    string
+	now     func() time.Time
+}
+
+// New creates an empty list kept in memory only
+func New() *List {
+	return &List{
+		entries: make(map[string]Entry),
+		now:     time.Now,
+	}
+}
+
+// Load creates a list persisted to the JSON file at path, starting from the
+// entries it holds, if it exists
+func Load(path string) (*List, error) {
+	l := New()
+	l.path = path
+
+	data, err := statefile.Read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blocklist: %w", err)
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse blocklist: %w", err)
+	}
+	for _, entry := range entries {
+		l.entries[entry.String()] = entry
+	}
+	return l, nil
+}
+
+// Block blocks entry, keeping the original reason of a market already
+// blocked
+func (l *List) Block(entry Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := entry.String()
+	if _, exists := l.entries[key]; exists {
+		return nil
+	}
+	if entry.Since.IsZero() {
+		entry.Since = l.now()
+	}
+	entries := l.copyEntries()
+	entries[key] = entry
+	return l.replace(entries)
+}
+
+// Unblock lifts the block of entry, reporting whether it was blocked. It does
+// not lift a block of the symbol on every exchange for an exchange entry.
+func (l *List) Unblock(entry Entry) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := entry.String()
+	if _, exists := l.entries[key]; !exists {
+		return false, nil
+	}
+	entries := l.copyEntries()
+	delete(entries, key)
+	return true, l.replace(entries)
+}
+
+// Blocked reports whether symbol is blocked on exchange, and why
+func (l *List) Blocked(exchange, symbol string) (string, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	symbol = strings.ToUpper(symbol)
+	if entry, ok := l.entries[symbol]; ok {
+		return entry.Reason, true
+	}
+	if exchange == "" {
+		return "", false
+	}
+	entry, ok := l.entries[Entry{Exchange: strings.ToLower(exchange), Symbol: symbol}.String()]
+	return entry.Reason, ok
+}
+
+// Entries returns the blocked markets sorted by symbol and exchange
+func (l *List) Entries() []Entry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.sorted(l.entries)
+}
+
+func (l *List) sorted(set map[string]Entry) []Entry {
+	entries := make([]Entry, 0, len(set))
+	for _, entry := range set {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Symbol != entries[j].Symbol {
+			return entries[i].Symbol < entries[j].Symbol
+		}
+		return entries[i].Exchange < entries[j].Exchange
+	})
+	return entries
+}
+
+// copyEntries returns a copy of the entries. Must be called with the lock
+// held.
+func (l *List) copyEntries() map[string]Entry {
+	entries := make(map[string]Entry, len(l.entries)+1)
+	for key, entry := range l.entries {
+		entries[key] = entry
+	}
+	return entries
+}
+
+// replace saves entries and makes them the list's. Must be called with the
+// lock held.
+func (l *List) replace(entries map[string]Entry) error {
+	if l.path != "" {
+		if err := l.save(l.sorted(entries)); err != nil {
+			return err
+		}
+	}
+	l.entries = entries
+	return nil
+}
+
+// save replaces the file of the list atomically
+func (l *List) save(entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode blocklist: %w", err)
+	}
+
+	if err := statefile.Write(l.path, data); err != nil {
+		return fmt.Errorf("failed to save blocklist: %w", err)
+	}
+	return nil
+}
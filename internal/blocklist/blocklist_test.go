@@ -0,0 +1,90 @@
+package blocklist
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestParseEntry(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Entry
+	}{
+		{"luna-usd", Entry{Symbol: "LUNA-USD"}},
+		{" Hyperliquid : ftt-usd ", Entry{Exchange: "hyperliquid", Symbol: "FTT-USD"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseEntry(tt.value)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseEntry(%q) = %+v, %v; expected %+v", tt.value, got, err, tt.want)
+		}
+	}
+	for _, value := range []string{"", "dydx:", ":BTC-USD"} {
+		if _, err := ParseEntry(value); err == nil {
+			t.Errorf("expected an error for %q", value)
+		}
+	}
+}
+
+func TestList_Blocked(t *testing.T) {
+	list := New()
+	if err := list.Block(Entry{Symbol: "LUNA-USD", Reason: "delisted"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Block(Entry{Exchange: "dydx", Symbol: "ETH-USD", Reason: "bad fills"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		exchange string
+		symbol   string
+		reason   string
+		blocked  bool
+	}{
+		{"hyperliquid", "LUNA-USD", "delisted", true},
+		{"", "luna-usd", "delisted", true},
+		{"dydx", "ETH-USD", "bad fills", true},
+		{"hyperliquid", "ETH-USD", "", false},
+		{"", "ETH-USD", "", false},
+	}
+	for _, tt := range tests {
+		reason, blocked := list.Blocked(tt.exchange, tt.symbol)
+		if blocked != tt.blocked || reason != tt.reason {
+			t.Errorf("Blocked(%q, %q) = %q, %v; expected %q, %v", tt.exchange, tt.symbol, reason, blocked, tt.reason, tt.blocked)
+		}
+	}
+
+	// An exchange entry does not lift the block on every exchange
+	if removed, _ := list.Unblock(Entry{Exchange: "dydx", Symbol: "LUNA-USD"}); removed {
+		t.Error("expected nothing to unblock")
+	}
+	if _, blocked := list.Blocked("dydx", "LUNA-USD"); !blocked {
+		t.Error("expected LUNA-USD still blocked")
+	}
+}
+
+func TestList_Persisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.json")
+	list, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Block(Entry{Symbol: "LUNA-USD", Reason: "delisted"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Block(Entry{Exchange: "dydx", Symbol: "ETH-USD"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := list.Unblock(Entry{Symbol: "LUNA-USD"}); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := restarted.Entries()
+	if len(entries) != 1 || entries[0].String() != "dydx:ETH-USD" {
+		t.Errorf("expected only dydx:ETH-USD restored, got %+v", entries)
+	}
+}
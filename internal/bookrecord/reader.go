@@ -0,0 +1,144 @@
+package bookrecord
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/replay"
+)
+
+// Load reads every record from a recording file. A file still being written
+// is read up to its last complete record.
+func Load(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open order book recording: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order book recording %s: %w", path, err)
+	}
+	defer gz.Close()
+
+	var records []Record
+	decoder := json.NewDecoder(gz)
+	for {
+		var record Record
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read order book record %d of %s: %w", len(records)+1, path, err)
+		}
+		records = append(records, record)
+	}
+}
+
+// LoadDir reads the records of every recording file in dir, oldest first
+func LoadDir(dir string) ([]Record, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "orderbook-*.jsonl.gz"))
+	if err != nil {
+		return nil, err
+	}
+	// File names start with their creation time
+	sort.Strings(paths)
+
+	var records []Record
+	for _, path := range paths {
+		fileRecords, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+	return records, nil
+}
+
+// Rebuild applies records in order and calls fn with the resulting book of
+// each record. Deltas of a book whose snapshot is missing are skipped.
+func Rebuild(records []Record, fn func(exchange string, book *exchanges.OrderBook)) {
+	type side map[string]Level
+	type book struct{ bids, asks side }
+	books := make(map[string]*book)
+
+	apply := func(s side, levels []Level) {
+		for _, level := range levels {
+			if level[1].IsZero() {
+				delete(s, level[0].String())
+			} else {
+				s[level[0].String()] = level
+			}
+		}
+	}
+
+	for _, record := range records {
+		key := record.Exchange + "/" + record.Symbol
+		b, exists := books[key]
+		if record.Type == RecordSnapshot {
+			b = &book{bids: make(side), asks: make(side)}
+			books[key] = b
+		} else if !exists {
+			continue
+		}
+		apply(b.bids, record.Bids)
+		apply(b.asks, record.Asks)
+
+		ob := &exchanges.OrderBook{
+			Symbol:    record.Symbol,
+			Bids:      sortedSide(b.bids, true),
+			Asks:      sortedSide(b.asks, false),
+			Timestamp: record.Time,
+		}
+		fn(record.Exchange, ob)
+	}
+}
+
+// Events rebuilds the books of records as replay events, so recordings can
+// drive the replay player
+func Events(records []Record) []replay.Event {
+	events := make([]replay.Event, 0, len(records))
+	Rebuild(records, func(exchange string, book *exchanges.OrderBook) {
+		events = append(events, replay.Event{
+			Time:      book.Timestamp,
+			Kind:      replay.EventOrderBook,
+			Exchange:  exchange,
+			Symbol:    book.Symbol,
+			OrderBook: book,
+		})
+	})
+	return events
+}
+
+// sortedSide returns the levels of a side best first
+func sortedSide(s map[string]Level, descending bool) []exchanges.Level {
+	levels := make([]Level, 0, len(s))
+	for _, level := range s {
+		levels = append(levels, level)
+	}
+	sortLevels(levels)
+	result := make([]exchanges.Level, len(levels))
+	for i, level := range levels {
+		if descending {
+			i = len(levels) - 1 - i
+		}
+		result[i] = exchanges.Level{Price: level[0], Amount: level[1]}
+	}
+	return result
+}
+
+// sortLevels sorts levels by ascending price
+func sortLevels(levels []Level) {
+	sort.Slice(levels, func(i, j int) bool {
+		return levels[i][0].LessThan(levels[j][0])
+	})
+}
@@ -0,0 +1,279 @@
+// Package bookrecord records L2 order books to compressed files for
+// microstructure research and tick-level replay. Each book is written as a
+// snapshot followed by deltas holding only the levels that changed.
+package bookrecord
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// RecordType tells whether a record holds a full book or changes to it
+type RecordType string
+
+const (
+	RecordSnapshot RecordType = "snapshot"
+	RecordDelta    RecordType = "delta"
+)
+
+// Level is a price level encoded as [price, amount]. In deltas a zero amount
+// removes the level.
+type Level [2]decimal.Decimal
+
+// Record is a single order book snapshot or delta
+type Record struct {
+	Time     time.Time  `json:"time"`
+	Exchange string     `json:"exchange"`
+	Symbol   string     `json:"symbol"`
+	Type     RecordType `json:"type"`
+	// Seq numbers the records of a book within a file, starting at 0 with
+	// its first snapshot
+	Seq  uint64  `json:"seq"`
+	Bids []Level `json:"bids,omitempty"`
+	Asks []Level `json:"asks,omitempty"`
+}
+
+// Config holds recorder configuration
+type Config struct {
+	Dir string // Directory the files are written to
+	// Depth is the number of levels recorded per side; 0 records them all
+	Depth int
+	// SnapshotInterval is how often a full book is written between deltas
+	SnapshotInterval time.Duration
+	// A new file is started after RotateInterval or once MaxFileBytes of
+	// uncompressed records were written to the current one; 0 disables
+	RotateInterval time.Duration
+	MaxFileBytes   int64
+}
+
+// DefaultConfig returns the default recorder configuration
+func DefaultConfig() Config {
+	return Config{
+		Dir:              "./recordings/orderbooks",
+		Depth:            20,
+		SnapshotInterval: time.Minute,
+		RotateInterval:   time.Hour,
+		MaxFileBytes:     100 << 20,
+	}
+}
+
+// bookState is the last recorded state of a book
+type bookState struct {
+	bids         map[string]decimal.Decimal // price -> amount
+	asks         map[string]decimal.Decimal
+	seq          uint64
+	lastSnapshot time.Time
+}
+
+// Recorder writes order books to gzip compressed JSON lines files, rotated
+// by age and size. Every file starts each book with a snapshot so it can be
+// read on its own.
+type Recorder struct {
+	mu       sync.Mutex
+	config   Config
+	file     *os.File
+	gz       *gzip.Writer
+	encoder  *json.Encoder
+	counter  *countingWriter
+	openedAt time.Time
+	files    int
+	books    map[string]*bookState
+	closed   bool
+	now      func() time.Time
+}
+
+// NewRecorder creates a recorder writing to config.Dir, creating it if needed
+func NewRecorder(config Config) (*Recorder, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("order book recording directory not set")
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create order book recording directory: %w", err)
+	}
+	return &Recorder{
+		config: config,
+		books:  make(map[string]*bookState),
+		now:    time.Now,
+	}, nil
+}
+
+// Record writes book of exchange as a delta against the previously recorded
+// book, or as a snapshot when none was recorded in the current file or the
+// snapshot interval elapsed. Books are taken as the full view of the top of
+// the book; levels missing from book are recorded as removed.
+func (r *Recorder) Record(exchange string, book *exchanges.OrderBook) error {
+	if book == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return fmt.Errorf("recorder closed")
+	}
+	now := r.now()
+	if err := r.rotateIfNeeded(now); err != nil {
+		return err
+	}
+
+	bids := levelMap(book.Bids, r.config.Depth)
+	asks := levelMap(book.Asks, r.config.Depth)
+	key := exchange + "/" + book.Symbol
+	state, exists := r.books[key]
+
+	record := Record{Time: now, Exchange: exchange, Symbol: book.Symbol}
+	if !exists || (r.config.SnapshotInterval > 0 && now.Sub(state.lastSnapshot) >= r.config.SnapshotInterval) {
+		record.Type = RecordSnapshot
+		record.Bids = toLevels(book.Bids, r.config.Depth)
+		record.Asks = toLevels(book.Asks, r.config.Depth)
+		if exists {
+			record.Seq = state.seq + 1
+		}
+		state = &bookState{seq: record.Seq, lastSnapshot: now}
+		r.books[key] = state
+	} else {
+		record.Type = RecordDelta
+		record.Bids = diffLevels(state.bids, bids)
+		record.Asks = diffLevels(state.asks, asks)
+		if len(record.Bids) == 0 && len(record.Asks) == 0 {
+			return nil
+		}
+		state.seq++
+		record.Seq = state.seq
+	}
+	state.bids = bids
+	state.asks = asks
+
+	if err := r.encoder.Encode(&record); err != nil {
+		return fmt.Errorf("failed to write order book record: %w", err)
+	}
+	return nil
+}
+
+// rotateIfNeeded opens the first file, or a new one once the current file is
+// too old or too large. Must be called with the lock held.
+func (r *Recorder) rotateIfNeeded(now time.Time) error {
+	if r.file != nil {
+		expired := r.config.RotateInterval > 0 && now.Sub(r.openedAt) >= r.config.RotateInterval
+		full := r.config.MaxFileBytes > 0 && r.counter.n >= r.config.MaxFileBytes
+		if !expired && !full {
+			return nil
+		}
+		if err := r.closeFile(); err != nil {
+			return err
+		}
+	}
+
+	r.files++
+	path := filepath.Join(r.config.Dir, fmt.Sprintf("orderbook-%s-%03d.jsonl.gz", now.UTC().Format("20060102T150405"), r.files))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open order book recording: %w", err)
+	}
+	r.file = file
+	r.gz = gzip.NewWriter(file)
+	r.counter = &countingWriter{w: r.gz}
+	r.encoder = json.NewEncoder(r.counter)
+	r.openedAt = now
+	// Every book starts over with a snapshot in the new file
+	r.books = make(map[string]*bookState)
+	return nil
+}
+
+// closeFile finishes the current file. Must be called with the lock held.
+func (r *Recorder) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.gz.Close()
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	r.file = nil
+	r.gz = nil
+	r.encoder = nil
+	r.counter = nil
+	return err
+}
+
+// Flush writes buffered records to the current file. Readers can only
+// decode a file up to the last flush until it is closed.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.gz == nil {
+		return nil
+	}
+	return r.gz.Flush()
+}
+
+// Close finishes the current file
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	return r.closeFile()
+}
+
+// levelMap indexes the first depth levels by price
+func levelMap(levels []exchanges.Level, depth int) map[string]decimal.Decimal {
+	if depth > 0 && len(levels) > depth {
+		levels = levels[:depth]
+	}
+	m := make(map[string]decimal.Decimal, len(levels))
+	for _, level := range levels {
+		m[level.Price.String()] = level.Amount
+	}
+	return m
+}
+
+// toLevels converts the first depth levels
+func toLevels(levels []exchanges.Level, depth int) []Level {
+	if depth > 0 && len(levels) > depth {
+		levels = levels[:depth]
+	}
+	result := make([]Level, len(levels))
+	for i, level := range levels {
+		result[i] = Level{level.Price, level.Amount}
+	}
+	return result
+}
+
+// diffLevels returns the levels of current that differ from previous, and
+// the levels of previous missing from current with a zero amount
+func diffLevels(previous, current map[string]decimal.Decimal) []Level {
+	var changes []Level
+	for price, amount := range current {
+		if old, exists := previous[price]; !exists || !old.Equal(amount) {
+			changes = append(changes, Level{decimal.RequireFromString(price), amount})
+		}
+	}
+	for price := range previous {
+		if _, exists := current[price]; !exists {
+			changes = append(changes, Level{decimal.RequireFromString(price), decimal.Zero})
+		}
+	}
+	sortLevels(changes)
+	return changes
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,128 @@
+package bookrecord
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/replay"
+	"github.com/shopspring/decimal"
+)
+
+func book(bids, asks [][2]float64) *exchanges.OrderBook {
+	levels := func(raw [][2]float64) []exchanges.Level {
+		result := make([]exchanges.Level, len(raw))
+		for i, level := range raw {
+			result[i] = exchanges.Level{Price: decimal.NewFromFloat(level[0]), Amount: decimal.NewFromFloat(level[1])}
+		}
+		return result
+	}
+	return &exchanges.OrderBook{Symbol: "BTC-USD", Bids: levels(bids), Asks: levels(asks)}
+}
+
+func TestRecorder_SnapshotsAndDeltas(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.Dir = dir
+	config.Depth = 2
+	config.SnapshotInterval = time.Minute
+	recorder, err := NewRecorder(config)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder.now = func() time.Time { return now }
+
+	books := []*exchanges.OrderBook{
+		book([][2]float64{{100, 1}, {99, 2}, {98, 5}}, [][2]float64{{101, 1}, {102, 3}}),
+		// Level 99 resized, 101 removed and 103 enters the recorded depth
+		book([][2]float64{{100, 1}, {99, 4}}, [][2]float64{{102, 3}, {103, 1}}),
+		// Unchanged within the recorded depth: nothing written
+		book([][2]float64{{100, 1}, {99, 4}, {97, 1}}, [][2]float64{{102, 3}, {103, 1}}),
+	}
+	for _, b := range books {
+		if err := recorder.Record("dydx", b); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+		now = now.Add(time.Second)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	records, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected a snapshot and a delta, got %d records", len(records))
+	}
+	if records[0].Type != RecordSnapshot || len(records[0].Bids) != 2 {
+		t.Errorf("first record should be a snapshot of the recorded depth, got %+v", records[0])
+	}
+	delta := records[1]
+	if delta.Type != RecordDelta || delta.Seq != 1 {
+		t.Fatalf("second record should be delta 1, got %s %d", delta.Type, delta.Seq)
+	}
+	if len(delta.Bids) != 1 || !delta.Bids[0][1].Equal(decimal.NewFromInt(4)) {
+		t.Errorf("delta should only hold the resized bid, got %v", delta.Bids)
+	}
+	if len(delta.Asks) != 2 || !delta.Asks[0][1].IsZero() || !delta.Asks[1][0].Equal(decimal.NewFromInt(103)) {
+		t.Errorf("delta should remove 101 and add 103, got %v", delta.Asks)
+	}
+
+	events := Events(records)
+	if len(events) != 2 || events[1].Kind != replay.EventOrderBook {
+		t.Fatalf("expected an order book event per record, got %d", len(events))
+	}
+	rebuilt := events[1].OrderBook
+	if len(rebuilt.Bids) != 2 || !rebuilt.Bids[0].Price.Equal(decimal.NewFromInt(100)) || !rebuilt.Bids[1].Amount.Equal(decimal.NewFromInt(4)) {
+		t.Errorf("bids should be rebuilt best first, got %v", rebuilt.Bids)
+	}
+	if len(rebuilt.Asks) != 2 || !rebuilt.Asks[0].Price.Equal(decimal.NewFromInt(102)) {
+		t.Errorf("asks should be rebuilt best first, got %v", rebuilt.Asks)
+	}
+}
+
+func TestRecorder_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.Dir = dir
+	config.RotateInterval = time.Hour
+	recorder, err := NewRecorder(config)
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder.now = func() time.Time { return now }
+
+	if err := recorder.Record("dydx", book([][2]float64{{100, 1}}, [][2]float64{{101, 1}})); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	now = now.Add(time.Hour)
+	if err := recorder.Record("dydx", book([][2]float64{{100, 2}}, [][2]float64{{101, 1}})); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.jsonl.gz"))
+	if len(paths) != 2 {
+		t.Fatalf("expected a file per hour, got %v", paths)
+	}
+	for _, path := range paths {
+		records, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if len(records) != 1 || records[0].Type != RecordSnapshot {
+			t.Errorf("every file should start the book with a snapshot, got %+v", records)
+		}
+	}
+	if info, err := os.Stat(paths[0]); err != nil || info.Size() == 0 {
+		t.Errorf("rotated file should be complete: %v", err)
+	}
+}
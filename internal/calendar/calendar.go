@@ -0,0 +1,80 @@
+// Package calendar reads the CSV files scheduling market events, such as
+// economic releases. The event policy of the risk manager and the blackouts
+// of the execution agent both read this format, so one file can feed both.
+package calendar
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Entry is an event scheduled at Start, or over Start to End
+type Entry struct {
+	Line    int // Line of the file, for error messages
+	Start   time.Time
+	End     time.Time // Zero when the file gives no end
+	Name    string
+	Symbols []string // Empty applies to every symbol
+	Impact  string   // Lower case, empty when the file gives none
+}
+
+// LoadFile reads the entries of a CSV file with the columns
+// start,end,name[,symbols[,impact]]. Times are RFC 3339 and end may be
+// left empty, symbols are separated by spaces, and lines starting with #
+// are ignored, as is a header line.
+func LoadFile(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open calendar: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read calendar %s: %w", path, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(entries) == 0 && strings.EqualFold(record[0], "start") {
+			continue
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("calendar %s line %d: expected start,end,name[,symbols[,impact]]", path, line)
+		}
+
+		entry := Entry{Line: line, Name: record[2]}
+		if entry.Start, err = time.Parse(time.RFC3339, record[0]); err != nil {
+			return nil, fmt.Errorf("calendar %s line %d: invalid start: %w", path, line, err)
+		}
+		if record[1] != "" {
+			if entry.End, err = time.Parse(time.RFC3339, record[1]); err != nil {
+				return nil, fmt.Errorf("calendar %s line %d: invalid end: %w", path, line, err)
+			}
+			if !entry.End.After(entry.Start) {
+				return nil, fmt.Errorf("calendar %s line %d: end must be after start", path, line)
+			}
+		}
+		if len(record) > 3 {
+			entry.Symbols = strings.Fields(record[3])
+		}
+		if len(record) > 4 {
+			entry.Impact = strings.ToLower(strings.TrimSpace(record[4]))
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
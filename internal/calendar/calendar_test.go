@@ -0,0 +1,58 @@
+package calendar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeCalendar(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "calendar.csv")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeCalendar(t, "start,end,name,symbols,impact\n"+
+		"# US releases\n"+
+		"2024-01-05T13:30:00Z,2024-01-05T14:00:00Z,NFP,,HIGH\n"+
+		"2024-01-31T19:00:00Z,,FOMC,BTC-USD USD\n")
+
+	entries, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	nfp := entries[0]
+	if nfp.Name != "NFP" || nfp.Impact != "high" || len(nfp.Symbols) != 0 || nfp.Line != 3 {
+		t.Errorf("Unexpected first entry: %+v", nfp)
+	}
+	if !nfp.End.Equal(time.Date(2024, 1, 5, 14, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the end of the window, got %s", nfp.End)
+	}
+	fomc := entries[1]
+	if !fomc.End.IsZero() || fomc.Impact != "" || len(fomc.Symbols) != 2 {
+		t.Errorf("Unexpected second entry: %+v", fomc)
+	}
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	for name, content := range map[string]string{
+		"missing name": "2024-01-05T13:30:00Z,2024-01-05T14:00:00Z\n",
+		"invalid time": "13:30,,NFP\n",
+		"end first":    "2024-01-05T14:00:00Z,2024-01-05T13:30:00Z,NFP\n",
+	} {
+		if _, err := LoadFile(writeCalendar(t, content)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := LoadFile(filepath.Join(t.TempDir(), "missing.csv")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
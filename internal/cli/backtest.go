@@ -0,0 +1,449 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/guyghost/constantine/internal/backtesting"
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/degradation"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+)
+
+// backtestOptions are the flags of the backtest command
+type backtestOptions struct {
+	dataFile       *string
+	dataTimezone   *string
+	dataDir        *string
+	workers        *int
+	symbol         *string
+	initialCapital *float64
+	commission     *float64
+	slippage       *float64
+	riskPerTrade   *float64
+	notional       *float64
+	maxPositions   *int
+	dataLatency    *time.Duration
+	orderLatency   *time.Duration
+	fundingRate    *float64
+	borrowRate     *float64
+	marginRate     *float64
+
+	// Benchmark besides buy and hold of the traded symbol
+	benchmarkFile   *string
+	benchmarkSymbol *string
+
+	// Strategy parameters
+	shortEMA      *int
+	longEMA       *int
+	rsiPeriod     *int
+	rsiOversold   *float64
+	rsiOverbought *float64
+	takeProfit    *float64
+	stopLoss      *float64
+
+	// Output options
+	verbose        *bool
+	reportFile     *string
+	expectations   *string
+	generateSample *bool
+	sampleCandles  *int
+	sampleRegimes  *string
+	sampleSeed     *int64
+}
+
+// BacktestCommand backtests the strategy on historical or generated data
+func BacktestCommand() *Command {
+	return &Command{
+		Name:  "backtest",
+		Usage: "[flags]",
+		Short: "Backtest the strategy on historical or generated data",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			o := newBacktestOptions(fs)
+			return func(context.Context, []string) error { return o.run() }
+		},
+	}
+}
+
+// newBacktestOptions declares the backtest flags on fs
+func newBacktestOptions(fs *flag.FlagSet) *backtestOptions {
+	return &backtestOptions{
+		dataFile:       fs.String("data", "", "Path to a CSV, Parquet or SQLite file with historical data (required)"),
+		dataTimezone:   fs.String("data-timezone", "UTC", "Time zone of data timestamps without an offset (e.g., America/New_York)"),
+		dataDir:        fs.String("data-dir", "", "Directory of CSV, Parquet or SQLite files, one per symbol named after it, to backtest and rank"),
+		workers:        fs.Int("workers", runtime.NumCPU(), "Number of symbols backtested in parallel with -data-dir"),
+		symbol:         fs.String("symbol", "BTC-USD", "Trading symbol"),
+		initialCapital: fs.Float64("capital", 10000, "Initial capital for backtesting"),
+		commission:     fs.Float64("commission", 0.001, "Commission rate (e.g., 0.001 for 0.1%)"),
+		slippage:       fs.Float64("slippage", 0.0005, "Slippage rate (e.g., 0.0005 for 0.05%)"),
+		riskPerTrade:   fs.Float64("risk", 0.01, "Risk per trade as fraction of capital (e.g., 0.01 for 1%), sizing entries when -notional is 0"),
+		notional:       fs.Float64("notional", 0.1, "Entry notional as fraction of capital (e.g., 0.1 for 10%), 0 to size entries by -risk"),
+		maxPositions:   fs.Int("max-positions", 1, "Maximum number of concurrent positions"),
+		dataLatency:    fs.Duration("data-latency", 0, "Delay before market data reaches the strategy (e.g., 500ms)"),
+		orderLatency:   fs.Duration("order-latency", 0, "Delay before orders reach the exchange and fill (e.g., 200ms)"),
+		fundingRate:    fs.Float64("funding-rate", 0, "Funding rate per 8 hours paid by longs to shorts (e.g., 0.0001 for 0.01%)"),
+		borrowRate:     fs.Float64("borrow-rate", 0, "Annual borrow rate paid on short positions (e.g., 0.05 for 5%)"),
+		marginRate:     fs.Float64("margin-rate", 0, "Annual interest paid on long notional above capital (e.g., 0.08 for 8%)"),
+
+		benchmarkFile:   fs.String("benchmark", "", "Data file of a symbol to compare the results with, besides buy and hold of the traded symbol"),
+		benchmarkSymbol: fs.String("benchmark-symbol", "BTC-USD", "Symbol of the -benchmark data"),
+
+		// Strategy parameters
+		shortEMA:      fs.Int("short-ema", 9, "Short EMA period"),
+		longEMA:       fs.Int("long-ema", 21, "Long EMA period"),
+		rsiPeriod:     fs.Int("rsi-period", 14, "RSI period"),
+		rsiOversold:   fs.Float64("rsi-oversold", 30.0, "RSI oversold threshold"),
+		rsiOverbought: fs.Float64("rsi-overbought", 70.0, "RSI overbought threshold"),
+		takeProfit:    fs.Float64("take-profit", 2.0, "Take profit percentage"),
+		stopLoss:      fs.Float64("stop-loss", 1.0, "Stop loss percentage"),
+
+		// Output options
+		verbose:        fs.Bool("verbose", false, "Show detailed trade log"),
+		reportFile:     fs.String("report", "", "Write an HTML report with charts to this file"),
+		expectations:   fs.String("expectations", "", "Write the win rate, expectancy and drawdown to this file for the live degradation monitor"),
+		generateSample: fs.Bool("generate-sample", false, "Generate sample data instead of loading from file"),
+		sampleCandles:  fs.Int("sample-candles", 1000, "Number of candles to generate for sample data"),
+		sampleRegimes:  fs.String("sample-regimes", "", "Generate sample data through market regimes, e.g. bull:500,crash:50,range:300 (bull, bear, range, volatile, crash)"),
+		sampleSeed:     fs.Int64("sample-seed", 1, "Random seed of regime sample data"),
+	}
+}
+
+func (o *backtestOptions) run() error {
+	// Print banner
+	printBacktestBanner()
+
+	if *o.dataDir != "" {
+		return o.runBatch()
+	}
+
+	// Load or generate data
+	var data *backtesting.HistoricalData
+	var err error
+
+	loader, err := o.newDataLoader()
+	if err != nil {
+		return err
+	}
+
+	if *o.generateSample {
+		log.Println("📊 Generating sample data...")
+		if *o.sampleRegimes != "" {
+			phases, err := backtesting.ParseRegimePhases(*o.sampleRegimes)
+			if err != nil {
+				return fmt.Errorf("invalid -sample-regimes: %w", err)
+			}
+			data, err = loader.GenerateRegimeData(*o.symbol, time.Now().Add(-24*time.Hour*30), 50000, *o.sampleSeed, phases)
+			if err != nil {
+				return fmt.Errorf("failed to generate sample data: %w", err)
+			}
+		} else {
+			data = loader.GenerateSampleData(*o.symbol, time.Now().Add(-24*time.Hour*30), *o.sampleCandles, 50000)
+		}
+		log.Printf("✓ Generated %d candles\n", len(data.Candles))
+	} else {
+		if *o.dataFile == "" {
+			return fmt.Errorf("one of the -data, -data-dir or -generate-sample flags is required")
+		}
+
+		log.Printf("📂 Loading data from %s...\n", *o.dataFile)
+		data, err = loader.Load(*o.dataFile, *o.symbol)
+		if err != nil {
+			return fmt.Errorf("failed to load data: %w", err)
+		}
+		log.Printf("✓ Loaded %d candles\n", len(data.Candles))
+	}
+
+	if len(data.Candles) == 0 {
+		return fmt.Errorf("no data loaded")
+	}
+
+	// Print data info
+	startTime := data.Candles[0].Timestamp
+	endTime := data.Candles[len(data.Candles)-1].Timestamp
+	log.Printf("📅 Period: %s to %s (%s)\n",
+		startTime.Format("2006-01-02"),
+		endTime.Format("2006-01-02"),
+		endTime.Sub(startTime).Round(time.Hour))
+
+	btConfig := o.backtestConfig(startTime, endTime)
+	if btConfig.BenchmarkData, err = o.loadBenchmark(loader); err != nil {
+		return err
+	}
+	stratConfig := o.strategyConfig(*o.symbol)
+
+	log.Println("\n⚙️  Backtest Configuration:")
+	log.Printf("   Initial Capital:  $%.2f\n", *o.initialCapital)
+	log.Printf("   Commission:       %.2f%%\n", *o.commission*100)
+	log.Printf("   Slippage:         %.2f%%\n", *o.slippage*100)
+	log.Printf("   Position Sizing:  %s\n", o.sizing())
+	log.Printf("   Max Positions:    %d\n", *o.maxPositions)
+	log.Printf("   Data Latency:     %s\n", *o.dataLatency)
+	log.Printf("   Order Latency:    %s\n", *o.orderLatency)
+	log.Printf("   Funding Rate:     %.4f%% / 8h\n", *o.fundingRate*100)
+	log.Printf("   Borrow Rate:      %.2f%% / year\n", *o.borrowRate*100)
+	log.Printf("   Margin Rate:      %.2f%% / year\n", *o.marginRate*100)
+
+	log.Println("\n📊 Strategy Parameters:")
+	log.Printf("   Short EMA:        %d\n", *o.shortEMA)
+	log.Printf("   Long EMA:         %d\n", *o.longEMA)
+	log.Printf("   RSI Period:       %d\n", *o.rsiPeriod)
+	log.Printf("   RSI Oversold:     %.0f\n", *o.rsiOversold)
+	log.Printf("   RSI Overbought:   %.0f\n", *o.rsiOverbought)
+	log.Printf("   Take Profit:      %.2f%%\n", *o.takeProfit)
+	log.Printf("   Stop Loss:        %.2f%%\n", *o.stopLoss)
+
+	// Create engine
+	engine := backtesting.NewEngine(btConfig, data)
+
+	// Set callbacks for progress
+	tradeCount := 0
+	engine.SetOnTrade(func(trade *backtesting.Trade) {
+		tradeCount++
+		if *o.verbose {
+			symbol := "✓"
+			if trade.PnL.LessThan(decimal.Zero) {
+				symbol = "✗"
+			}
+			log.Printf("[Trade #%d] %s %s: $%s → $%s = $%s (%.2f%%) [%s]\n",
+				tradeCount,
+				symbol,
+				trade.Side,
+				trade.EntryPrice.StringFixed(2),
+				trade.ExitPrice.StringFixed(2),
+				trade.PnL.StringFixed(2),
+				trade.PnLPercent.InexactFloat64(),
+				trade.ExitReason,
+			)
+		}
+	})
+
+	// Run backtest
+	log.Println("🚀 Running backtest...")
+	startRun := time.Now()
+
+	metrics, err := engine.Run(stratConfig)
+	if err != nil {
+		return fmt.Errorf("backtest failed: %w", err)
+	}
+
+	duration := time.Since(startRun)
+	log.Printf("✓ Backtest completed in %s\n\n", duration.Round(time.Millisecond))
+
+	// Generate report
+	reporter := backtesting.NewReporter()
+	report := reporter.GenerateReport(metrics)
+	fmt.Println(report)
+
+	// Generate detailed trade log if verbose
+	if *o.verbose && len(metrics.Trades) > 0 {
+		tradeLog := reporter.GenerateTradeLog(metrics)
+		fmt.Println(tradeLog)
+	}
+
+	if *o.reportFile != "" {
+		if err := reporter.WriteHTMLFile(*o.reportFile, metrics, o.reportParameters(startTime, endTime)); err != nil {
+			return err
+		}
+		log.Printf("📄 HTML report written to %s\n", *o.reportFile)
+	}
+
+	if *o.expectations != "" {
+		if err := o.writeExpectations(map[string]*backtesting.PerformanceMetrics{*o.symbol: metrics}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newDataLoader creates a data loader reading timestamps without an offset in
+// -data-timezone
+func (o *backtestOptions) newDataLoader() (*backtesting.DataLoader, error) {
+	location, err := time.LoadLocation(*o.dataTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -data-timezone: %w", err)
+	}
+	loader := backtesting.NewDataLoader()
+	loader.SetLocation(location)
+	return loader, nil
+}
+
+// runBatch backtests every data file of -data-dir in parallel and prints a
+// leaderboard ranking the symbols
+func (o *backtestOptions) runBatch() error {
+	loader, err := o.newDataLoader()
+	if err != nil {
+		return err
+	}
+	log.Printf("📂 Loading data from %s...\n", *o.dataDir)
+	datasets, err := loader.LoadDirectory(*o.dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to load data: %w", err)
+	}
+	log.Printf("✓ Loaded %d symbols\n", len(datasets))
+
+	btConfig := o.backtestConfig(time.Time{}, time.Time{})
+	if btConfig.BenchmarkData, err = o.loadBenchmark(loader); err != nil {
+		return err
+	}
+
+	log.Printf("🚀 Running %d backtests (%d workers)...\n", len(datasets), *o.workers)
+	startRun := time.Now()
+	results := backtesting.RunBatch(datasets, btConfig, o.strategyConfig(""), *o.workers)
+	log.Printf("✓ Backtests completed in %s\n\n", time.Since(startRun).Round(time.Millisecond))
+
+	fmt.Println(backtesting.NewReporter().GenerateLeaderboard(results))
+
+	if *o.expectations != "" {
+		bySymbol := make(map[string]*backtesting.PerformanceMetrics, len(results))
+		for _, result := range results {
+			if result.Err == nil {
+				bySymbol[result.Symbol] = result.Metrics
+			}
+		}
+		return o.writeExpectations(bySymbol)
+	}
+	return nil
+}
+
+// writeExpectations writes the performance of the main strategy on each
+// symbol to -expectations, leaving out the symbols without trades
+func (o *backtestOptions) writeExpectations(bySymbol map[string]*backtesting.PerformanceMetrics) error {
+	symbols := make([]string, 0, len(bySymbol))
+	for symbol := range bySymbol {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var expectations []degradation.Expectation
+	for _, symbol := range symbols {
+		metrics := bySymbol[symbol]
+		if metrics.TotalTrades == 0 {
+			continue
+		}
+		expectations = append(expectations, degradation.Expectation{
+			Strategy:       strategy.MainInstance,
+			Symbol:         symbol,
+			WinRate:        metrics.WinRate.InexactFloat64(),
+			ExpectancyPct:  metrics.TotalReturnPct.InexactFloat64() / float64(metrics.TotalTrades),
+			MaxDrawdownPct: metrics.MaxDrawdownPct.InexactFloat64(),
+		})
+	}
+	if err := degradation.WriteExpectations(*o.expectations, expectations); err != nil {
+		return fmt.Errorf("failed to write expectations: %w", err)
+	}
+	log.Printf("📐 Expectations of %d symbol(s) written to %s\n", len(expectations), *o.expectations)
+	return nil
+}
+
+// loadBenchmark loads the -benchmark data, nil when the flag is not set
+func (o *backtestOptions) loadBenchmark(loader *backtesting.DataLoader) (*backtesting.HistoricalData, error) {
+	if *o.benchmarkFile == "" {
+		return nil, nil
+	}
+	data, err := loader.Load(*o.benchmarkFile, *o.benchmarkSymbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load benchmark data: %w", err)
+	}
+	log.Printf("✓ Loaded %d %s benchmark candles\n", len(data.Candles), *o.benchmarkSymbol)
+	return data, nil
+}
+
+// backtestConfig builds the backtest configuration from the flags
+func (o *backtestOptions) backtestConfig(startTime, endTime time.Time) *backtesting.BacktestConfig {
+	return &backtesting.BacktestConfig{
+		InitialCapital: decimal.NewFromFloat(*o.initialCapital),
+		CommissionRate: decimal.NewFromFloat(*o.commission),
+		Slippage:       decimal.NewFromFloat(*o.slippage),
+		RiskPerTrade:   decimal.NewFromFloat(*o.riskPerTrade),
+		MaxPositions:   *o.maxPositions,
+		DataLatency:    *o.dataLatency,
+		OrderLatency:   *o.orderLatency,
+
+		FundingRate:        decimal.NewFromFloat(*o.fundingRate),
+		BorrowRate:         decimal.NewFromFloat(*o.borrowRate),
+		MarginInterestRate: decimal.NewFromFloat(*o.marginRate),
+
+		// Entries are sized by notional or risk, never by a quantity of
+		// the asset, so symbols of different prices compare fairly
+		NotionalPerTrade: decimal.NewFromFloat(*o.notional),
+
+		AllowShort: true, // Enable short selling for testing
+		StartTime:  startTime,
+		EndTime:    endTime,
+	}
+}
+
+// sizing describes how entries are sized
+func (o *backtestOptions) sizing() string {
+	if *o.notional > 0 {
+		return fmt.Sprintf("%.2f%% of capital per trade", *o.notional*100)
+	}
+	return fmt.Sprintf("%.2f%% of capital at risk per trade", *o.riskPerTrade*100)
+}
+
+// strategyConfig builds the strategy configuration for symbol from the flags
+func (o *backtestOptions) strategyConfig(symbol string) *config.Config {
+	stratConfig := config.DefaultConfig()
+	stratConfig.Symbol = symbol
+	stratConfig.ShortEMAPeriod = *o.shortEMA
+	stratConfig.LongEMAPeriod = *o.longEMA
+	stratConfig.RSIPeriod = *o.rsiPeriod
+	stratConfig.RSIOversold = *o.rsiOversold
+	stratConfig.RSIOverbought = *o.rsiOverbought
+	stratConfig.TakeProfitPercent = *o.takeProfit
+	stratConfig.StopLossPercent = *o.stopLoss
+	return stratConfig
+}
+
+// reportParameters lists the settings of the run for the HTML report
+func (o *backtestOptions) reportParameters(startTime, endTime time.Time) []backtesting.ReportParameter {
+	return []backtesting.ReportParameter{
+		{Name: "Symbol", Value: *o.symbol},
+		{Name: "Period", Value: startTime.Format("2006-01-02") + " to " + endTime.Format("2006-01-02")},
+		{Name: "Initial Capital", Value: fmt.Sprintf("$%.2f", *o.initialCapital)},
+		{Name: "Commission", Value: fmt.Sprintf("%.2f%%", *o.commission*100)},
+		{Name: "Slippage", Value: fmt.Sprintf("%.2f%%", *o.slippage*100)},
+		{Name: "Position Sizing", Value: o.sizing()},
+		{Name: "Max Positions", Value: fmt.Sprintf("%d", *o.maxPositions)},
+		{Name: "Data Latency", Value: o.dataLatency.String()},
+		{Name: "Order Latency", Value: o.orderLatency.String()},
+		{Name: "Funding Rate", Value: fmt.Sprintf("%.4f%% / 8h", *o.fundingRate*100)},
+		{Name: "Borrow Rate", Value: fmt.Sprintf("%.2f%% / year", *o.borrowRate*100)},
+		{Name: "Margin Rate", Value: fmt.Sprintf("%.2f%% / year", *o.marginRate*100)},
+		{Name: "Benchmark", Value: o.benchmarkName()},
+		{Name: "Short EMA", Value: fmt.Sprintf("%d", *o.shortEMA)},
+		{Name: "Long EMA", Value: fmt.Sprintf("%d", *o.longEMA)},
+		{Name: "RSI Period", Value: fmt.Sprintf("%d", *o.rsiPeriod)},
+		{Name: "RSI Oversold", Value: fmt.Sprintf("%.0f", *o.rsiOversold)},
+		{Name: "RSI Overbought", Value: fmt.Sprintf("%.0f", *o.rsiOverbought)},
+		{Name: "Take Profit", Value: fmt.Sprintf("%.2f%%", *o.takeProfit)},
+		{Name: "Stop Loss", Value: fmt.Sprintf("%.2f%%", *o.stopLoss)},
+	}
+}
+
+// benchmarkName names the symbols the results are compared with
+func (o *backtestOptions) benchmarkName() string {
+	if *o.benchmarkFile == "" || *o.benchmarkSymbol == *o.symbol {
+		return "Buy & hold " + *o.symbol
+	}
+	return "Buy & hold " + *o.symbol + ", " + *o.benchmarkSymbol
+}
+
+func printBacktestBanner() {
+	banner := `
+╔═══════════════════════════════════════════════════════╗
+║                                                       ║
+║        CONSTANTINE BACKTESTING FRAMEWORK              ║
+║        Multi-Agent Trading System                     ║
+║                                                       ║
+╚═══════════════════════════════════════════════════════╝
+`
+	fmt.Println(banner)
+}
@@ -0,0 +1,153 @@
+// Package cli implements the commands of the constantine command line: a
+// tree of subcommands each parsing its own flags, sharing the configuration
+// loading and exchange construction of the bot.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// ErrUsage is returned when a command is called with invalid arguments, after
+// its usage has been printed
+var ErrUsage = errors.New("invalid usage")
+
+// Command is a command of the command line, either running itself or
+// dispatching to one of its subcommands
+type Command struct {
+	Name  string
+	Usage string // Arguments, after the command path
+	Short string // One line description
+
+	// Flags declares the flags of the command on fs, returning the function
+	// running it with the arguments left once the flags are parsed
+	Flags func(fs *flag.FlagSet) func(ctx context.Context, args []string) error
+
+	// Commands are the subcommands, dispatched by name
+	Commands []*Command
+	// Default runs when no subcommand is named, or when the first argument is
+	// a flag
+	Default *Command
+
+	parent *Command
+	output io.Writer
+}
+
+// Execute runs the command with args, the command line without the program
+// name
+func (c *Command) Execute(ctx context.Context, args []string) error {
+	for _, sub := range c.Commands {
+		sub.parent = c
+	}
+	if c.Default != nil {
+		c.Default.parent = c
+	}
+
+	if len(c.Commands) > 0 {
+		if len(args) > 0 {
+			switch args[0] {
+			case "help", "-h", "-help", "--help":
+				c.printUsage()
+				return nil
+			}
+			if sub := c.find(args[0]); sub != nil {
+				return sub.Execute(ctx, args[1:])
+			}
+			if !strings.HasPrefix(args[0], "-") || c.Default == nil {
+				fmt.Fprintf(c.writer(), "unknown command %q for %s\n\n", args[0], c.path())
+				c.printUsage()
+				return ErrUsage
+			}
+		}
+		if c.Default == nil {
+			c.printUsage()
+			return ErrUsage
+		}
+		return c.Default.Execute(ctx, args)
+	}
+
+	fs := flag.NewFlagSet(c.path(), flag.ContinueOnError)
+	fs.SetOutput(c.writer())
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s\n\n%s\n", c.path(), c.Usage, c.Short)
+		if hasFlags(fs) {
+			fmt.Fprintln(fs.Output(), "\nFlags:")
+			fs.PrintDefaults()
+		}
+	}
+	run := func(context.Context, []string) error { return nil }
+	if c.Flags != nil {
+		run = c.Flags(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return ErrUsage
+	}
+	return run(ctx, fs.Args())
+}
+
+// SetOutput sets where usage and errors are printed, stderr by default
+func (c *Command) SetOutput(w io.Writer) {
+	c.output = w
+}
+
+// find returns the subcommand called name
+func (c *Command) find(name string) *Command {
+	for _, sub := range c.Commands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// path returns the names of the command and its parents
+func (c *Command) path() string {
+	switch {
+	case c.parent == nil:
+		return c.Name
+	case c.parent.Default == c:
+		return c.parent.path()
+	}
+	return c.parent.path() + " " + c.Name
+}
+
+// writer returns the output of the command or the closest parent setting one
+func (c *Command) writer() io.Writer {
+	for cmd := c; cmd != nil; cmd = cmd.parent {
+		if cmd.output != nil {
+			return cmd.output
+		}
+	}
+	return os.Stderr
+}
+
+// printUsage lists the subcommands
+func (c *Command) printUsage() {
+	w := c.writer()
+	if c.Short != "" {
+		fmt.Fprintf(w, "%s\n\n", c.Short)
+	}
+	fmt.Fprintf(w, "Usage: %s <command> [flags]\n\nCommands:\n", c.path())
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, sub := range c.Commands {
+		fmt.Fprintf(tw, "  %s\t%s\n", sub.Name, sub.Short)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for the flags of a command.\n", c.path())
+}
+
+// hasFlags reports whether fs declares any flag
+func hasFlags(fs *flag.FlagSet) bool {
+	found := false
+	fs.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
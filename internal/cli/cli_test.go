@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+)
+
+// recordCommand returns a command recording the value of its -n flag and its
+// arguments
+func recordCommand(name string, got *[]string) *Command {
+	return &Command{
+		Name:  name,
+		Short: name + " command",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			n := fs.String("n", "", "a flag")
+			return func(ctx context.Context, args []string) error {
+				*got = append([]string{name, *n}, args...)
+				return nil
+			}
+		},
+	}
+}
+
+func TestCommand_Execute(t *testing.T) {
+	var got []string
+	run := recordCommand("run", &got)
+	root := &Command{
+		Name: "constantine",
+		Commands: []*Command{
+			run,
+			{Name: "order", Commands: []*Command{recordCommand("place", &got), recordCommand("cancel", &got)}},
+		},
+		Default: run,
+	}
+	var output bytes.Buffer
+	root.SetOutput(&output)
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"order", "cancel", "-n", "x", "42"}, "cancel x 42"},
+		{[]string{"run", "a"}, "run  a"},
+		{[]string{"-n", "y"}, "run y"},
+		{nil, "run "},
+	} {
+		got = nil
+		if err := root.Execute(ctx, tt.args); err != nil {
+			t.Fatalf("Execute(%v) failed: %v", tt.args, err)
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("Execute(%v) ran %q, want %q", tt.args, strings.Join(got, " "), tt.want)
+		}
+	}
+
+	for _, args := range [][]string{{"nope"}, {"order"}, {"order", "place", "-unknown"}} {
+		output.Reset()
+		if err := root.Execute(ctx, args); !errors.Is(err, ErrUsage) {
+			t.Errorf("Execute(%v) = %v, want a usage error", args, err)
+		}
+		if output.Len() == 0 {
+			t.Errorf("Execute(%v) printed no usage", args)
+		}
+	}
+
+	output.Reset()
+	if err := root.Execute(ctx, []string{"help"}); err != nil {
+		t.Fatalf("help failed: %v", err)
+	}
+	if !strings.Contains(output.String(), "order") || !strings.Contains(output.String(), "run command") {
+		t.Errorf("Expected the commands listed, got %q", output.String())
+	}
+	output.Reset()
+	root.Execute(ctx, []string{"order", "place", "-h"})
+	if !strings.Contains(output.String(), "Usage: constantine order place") {
+		t.Errorf("Expected the usage of the command path, got %q", output.String())
+	}
+}
+
+func TestBuildOrder(t *testing.T) {
+	order, err := buildOrder("BTC-USD", "BUY", "limit", "0.01", "50000", true)
+	if err != nil {
+		t.Fatalf("buildOrder failed: %v", err)
+	}
+	if order.Side != exchanges.OrderSideBuy || order.Type != exchanges.OrderTypeLimit || order.Price.String() != "50000" || !order.PostOnly {
+		t.Errorf("Unexpected order %+v", order)
+	}
+	if _, err := buildOrder("BTC-USD", "sell", "market", "1", "", false); err != nil {
+		t.Errorf("Expected a market order without price, got %v", err)
+	}
+
+	for name, args := range map[string][]string{
+		"side":           {"BTC-USD", "long", "limit", "1", "100"},
+		"amount":         {"BTC-USD", "buy", "limit", "-1", "100"},
+		"limit price":    {"BTC-USD", "buy", "limit", "1", ""},
+		"market price":   {"BTC-USD", "buy", "market", "1", "100"},
+		"type":           {"BTC-USD", "buy", "stop", "1", "100"},
+		"missing symbol": {"", "buy", "limit", "1", "100"},
+	} {
+		if _, err := buildOrder(args[0], args[1], args[2], args[3], args[4], false); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	var out bytes.Buffer
+	for answer, want := range map[string]bool{"y\n": true, "oui\n": true, "\n": false, "no\n": false, "": false} {
+		if got := confirm(strings.NewReader(answer), &out, "Place?"); got != want {
+			t.Errorf("confirm(%q) = %v, want %v", answer, got, want)
+		}
+	}
+}
+
+// streamingExchange delivers a ticker on subscription and reports a fixed
+// clock offset
+type streamingExchange struct {
+	*testutils.TestExchange
+	offset time.Duration
+}
+
+func (e *streamingExchange) SubscribeTicker(ctx context.Context, symbol string, callback func(*exchanges.Ticker)) error {
+	go callback(e.TickerValue)
+	return nil
+}
+
+func (e *streamingExchange) SyncClock(ctx context.Context) (time.Duration, error) {
+	return e.offset, nil
+}
+
+func TestCheckExchange(t *testing.T) {
+	statuses := func(report *doctorReport) map[string]checkStatus {
+		got := make(map[string]checkStatus)
+		for _, result := range report.results {
+			got[strings.TrimPrefix(result.Name, "test: ")] = result.Status
+		}
+		return got
+	}
+
+	report := &doctorReport{}
+	checkExchange(context.Background(), report, "test", &streamingExchange{TestExchange: testutils.NewTestExchange("test")}, "BTC-USD", time.Second)
+	for check, status := range statuses(report) {
+		if status != checkPass {
+			t.Errorf("%s: expected %s, got %s", check, checkPass, status)
+		}
+	}
+
+	// A skewed clock is corrected, a missing websocket ticker fails
+	report = &doctorReport{}
+	skewed := &streamingExchange{TestExchange: testutils.NewTestExchange("test"), offset: 3 * time.Second}
+	skewed.BalanceError = errors.New("unauthorized")
+	checkExchange(context.Background(), report, "test", skewed, "BTC-USD", time.Second)
+	got := statuses(report)
+	if got["authentication"] != checkFail || got["clock"] != checkWarn {
+		t.Errorf("expected failed authentication and warned clock, got %v", got)
+	}
+
+	report = &doctorReport{}
+	silent := testutils.NewTestExchange("test")
+	checkExchange(context.Background(), report, "test", silent, "BTC-USD", 10*time.Millisecond)
+	got = statuses(report)
+	if got["clock"] != checkSkip || got["websocket"] != checkFail {
+		t.Errorf("expected skipped clock and failed websocket, got %v", got)
+	}
+	if report.failures() != 1 {
+		t.Errorf("expected 1 failure, got %d", report.failures())
+	}
+
+	report = &doctorReport{}
+	unreachable := testutils.NewTestExchange("test")
+	unreachable.ConnectError = errors.New("connection refused")
+	checkExchange(context.Background(), report, "test", unreachable, "BTC-USD", time.Second)
+	got = statuses(report)
+	if got["connectivity"] != checkFail || got["authentication"] != checkSkip || got["websocket"] != checkSkip {
+		t.Errorf("expected the checks after a failed connection to be skipped, got %v", got)
+	}
+}
+
+func TestExchangeRegistry(t *testing.T) {
+	got := RegisteredExchanges()
+	if strings.Join(got, ",") != "coinbase,dydx,hyperliquid" {
+		t.Errorf("expected the built-in connectors, got %v", got)
+	}
+
+	if _, err := exchanges.NewTestnetExchange("coinbase", config.ExchangeConfig{}); err == nil {
+		t.Error("expected an error for a connector without testnet")
+	}
+}
@@ -0,0 +1,213 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// checkStatus is the outcome of a doctor check
+type checkStatus string
+
+const (
+	checkPass checkStatus = "PASS"
+	checkWarn checkStatus = "WARN" // Works, but needs attention
+	checkFail checkStatus = "FAIL"
+	checkSkip checkStatus = "SKIP" // Not applicable, or blocked by an earlier failure
+)
+
+// checkResult is the outcome of a doctor check with its explanation
+type checkResult struct {
+	Name   string
+	Status checkStatus
+	Detail string
+}
+
+// doctorReport collects the results of the doctor checks in order
+type doctorReport struct {
+	results []checkResult
+}
+
+func (r *doctorReport) add(name string, status checkStatus, format string, args ...any) {
+	r.results = append(r.results, checkResult{Name: name, Status: status, Detail: fmt.Sprintf(format, args...)})
+}
+
+// failures returns the number of failed checks
+func (r *doctorReport) failures() int {
+	failed := 0
+	for _, result := range r.results {
+		if result.Status == checkFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// print writes the report as a table
+func (r *doctorReport) print(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tCHECK\tDETAIL")
+	for _, result := range r.results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Status, result.Name, result.Detail)
+	}
+	tw.Flush()
+}
+
+// DoctorCommand checks the configuration and every enabled exchange, printing
+// a pass/fail report
+func DoctorCommand() *Command {
+	return &Command{
+		Name:  "doctor",
+		Usage: "[flags]",
+		Short: "Check the configuration, exchange access, clock and websockets before trading",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			only := fs.String("exchange", "", "Check only this exchange (default: every enabled exchange)")
+			symbol := fs.String("symbol", "", "Symbol to read market data for (default: STRATEGY_SYMBOL)")
+			timeout := fs.Duration("timeout", 30*time.Second, "Time allowed to check each exchange")
+			streamTimeout := fs.Duration("stream-timeout", 15*time.Second, "Time allowed for the first websocket ticker")
+
+			return func(ctx context.Context, args []string) error {
+				report := &doctorReport{}
+				appConfig := checkConfig(report)
+				if appConfig != nil {
+					names := EnabledExchanges(appConfig)
+					if *only != "" {
+						names = []string{*only}
+					}
+					if *symbol == "" {
+						*symbol = appConfig.StrategySymbol
+					}
+					for _, name := range names {
+						checkExchangeConfig(ctx, report, name, appConfig, *symbol, *timeout, *streamTimeout)
+					}
+				}
+
+				fmt.Println()
+				report.print(os.Stdout)
+				fmt.Println()
+				if failed := report.failures(); failed > 0 {
+					return fmt.Errorf("%d check(s) failed", failed)
+				}
+				fmt.Println("✅ All checks passed")
+				return nil
+			}
+		},
+	}
+}
+
+// checkConfig loads and validates the configuration, returning nil when the
+// exchanges cannot be checked
+func checkConfig(report *doctorReport) *config.AppConfig {
+	appConfig, err := LoadConfig()
+	if err != nil {
+		report.add("config", checkFail, "%v", err)
+		return nil
+	}
+	enabled := EnabledExchanges(appConfig)
+	if len(enabled) == 0 {
+		report.add("config", checkFail, "no exchanges enabled - check ENABLE_* environment variables")
+		return nil
+	}
+	report.add("config", checkPass, "exchanges %s, symbols %s", strings.Join(enabled, ", "), strings.Join(appConfig.TradingSymbols, ", "))
+
+	if err := config.DefaultConfig().Validate(); err != nil {
+		report.add("config: strategy", checkFail, "%s", strings.ReplaceAll(err.Error(), "\n", "; "))
+	} else {
+		report.add("config: strategy", checkPass, "valid")
+	}
+	return appConfig
+}
+
+// checkExchangeConfig creates the client of exchange name and checks it
+func checkExchangeConfig(ctx context.Context, report *doctorReport, name string, appConfig *config.AppConfig, symbol string, timeout, streamTimeout time.Duration) {
+	cfg, ok := appConfig.Exchanges[name]
+	if !ok {
+		report.add(name, checkFail, "unknown exchange")
+		return
+	}
+	exchange, err := NewExchange(name, cfg)
+	if err != nil {
+		report.add(name, checkFail, "%v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	fmt.Printf("Checking %s...\n", name)
+	checkExchange(ctx, report, name, exchange, symbol, streamTimeout)
+	exchange.Disconnect()
+}
+
+// checkExchange connects to exchange and checks its connectivity,
+// authentication, clock and websocket, skipping the checks a failure to
+// connect makes meaningless
+func checkExchange(ctx context.Context, report *doctorReport, name string, exchange exchanges.Exchange, symbol string, streamTimeout time.Duration) {
+	if err := exchange.Connect(ctx); err != nil {
+		report.add(name+": connectivity", checkFail, "%v", err)
+		for _, check := range []string{"authentication", "clock", "websocket"} {
+			report.add(name+": "+check, checkSkip, "not connected")
+		}
+		return
+	}
+	start := time.Now()
+	ticker, err := exchange.GetTicker(ctx, symbol)
+	if err != nil {
+		report.add(name+": connectivity", checkFail, "connected, but failed to get %s ticker: %v", symbol, err)
+	} else {
+		report.add(name+": connectivity", checkPass, "%s last %s in %s", symbol, ticker.Last, time.Since(start).Round(time.Millisecond))
+	}
+
+	if balances, err := exchange.GetBalance(ctx); err != nil {
+		report.add(name+": authentication", checkFail, "failed to get balance: %v", err)
+	} else {
+		report.add(name+": authentication", checkPass, "%d balance(s) readable", len(balances))
+	}
+
+	if syncer, ok := exchange.(exchanges.ClockSyncer); !ok {
+		report.add(name+": clock", checkSkip, "requests are not timestamped")
+	} else if offset, err := syncer.SyncClock(ctx); err != nil {
+		report.add(name+": clock", checkFail, "failed to get server time: %v", err)
+	} else if offset.Abs() > exchanges.MaxClockSkew {
+		report.add(name+": clock", checkWarn, "offset %s beyond %s, corrected by the bot - enable NTP time synchronization",
+			offset.Round(time.Millisecond), exchanges.MaxClockSkew)
+	} else {
+		report.add(name+": clock", checkPass, "offset %s", offset.Round(time.Millisecond))
+	}
+
+	status, detail := checkStream(ctx, exchange, symbol, streamTimeout)
+	report.add(name+": websocket", status, "%s", detail)
+}
+
+// checkStream subscribes to the tickers of symbol and waits for the first one
+func checkStream(ctx context.Context, exchange exchanges.Exchange, symbol string, timeout time.Duration) (checkStatus, string) {
+	received := make(chan struct{}, 1)
+	start := time.Now()
+	err := exchange.SubscribeTicker(ctx, symbol, func(*exchanges.Ticker) {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		return checkFail, fmt.Sprintf("failed to subscribe to %s tickers: %v", symbol, err)
+	}
+
+	wait := time.NewTimer(timeout)
+	defer wait.Stop()
+	select {
+	case <-received:
+		return checkPass, fmt.Sprintf("first %s ticker after %s", symbol, time.Since(start).Round(time.Millisecond))
+	case <-wait.C:
+		return checkFail, fmt.Sprintf("subscribed, but no %s ticker within %s", symbol, timeout)
+	case <-ctx.Done():
+		return checkFail, fmt.Sprintf("no %s ticker before the exchange timeout", symbol)
+	}
+}
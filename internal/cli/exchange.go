@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// ExchangeCommand groups the commands checking an exchange
+func ExchangeCommand() *Command {
+	return &Command{
+		Name:     "exchange",
+		Short:    "Check the connection to an exchange",
+		Commands: []*Command{exchangeTestCommand()},
+	}
+}
+
+// exchangeTestCommand connects to an exchange and reads the balance, the
+// market data of a symbol and the open positions, without trading
+func exchangeTestCommand() *Command {
+	return &Command{
+		Name:  "test",
+		Usage: "[flags]",
+		Short: "Connect and read balances, market data and positions without trading",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			selection := addExchangeFlags(fs)
+			symbol := fs.String("symbol", "BTC-USD", "Symbol to read market data for")
+			timeout := fs.Duration("timeout", 2*time.Minute, "Time allowed for the whole test")
+
+			return func(ctx context.Context, args []string) error {
+				ctx, cancel := context.WithTimeout(ctx, *timeout)
+				defer cancel()
+				name, exchange, err := connect(ctx, selection)
+				if err != nil {
+					return err
+				}
+				defer exchange.Disconnect()
+				return testExchange(ctx, os.Stdout, name, exchange, *symbol)
+			}
+		},
+	}
+}
+
+// connect creates and connects the selected exchange
+func connect(ctx context.Context, selection exchangeFlags) (string, exchanges.Exchange, error) {
+	name, exchange, err := selection.open()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := exchange.Connect(ctx); err != nil {
+		return "", nil, fmt.Errorf("failed to connect to %s: %w", name, err)
+	}
+	return name, exchange, nil
+}
+
+// testExchange runs the read-only checks on exchange, stopping at the first
+// failure
+func testExchange(ctx context.Context, w io.Writer, name string, exchange exchanges.Exchange, symbol string) error {
+	fmt.Fprintf(w, "✅ Connected to %s\n", name)
+
+	balances, err := exchange.GetBalance(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get balance: %w", err)
+	}
+	fmt.Fprintf(w, "✅ %d balance(s)\n", len(balances))
+	for _, balance := range balances {
+		fmt.Fprintf(w, "   %s: %s free, %s locked\n", balance.Asset, balance.Free, balance.Locked)
+	}
+
+	ticker, err := exchange.GetTicker(ctx, symbol)
+	if err != nil {
+		return fmt.Errorf("failed to get %s ticker: %w", symbol, err)
+	}
+	fmt.Fprintf(w, "✅ %s: bid %s, ask %s, last %s, volume %s\n", symbol, ticker.Bid, ticker.Ask, ticker.Last, ticker.Volume24h)
+
+	book, err := exchange.GetOrderBook(ctx, symbol, 5)
+	if err != nil {
+		return fmt.Errorf("failed to get %s order book: %w", symbol, err)
+	}
+	fmt.Fprintf(w, "✅ Order book: %d bid and %d ask levels\n", len(book.Bids), len(book.Asks))
+	if len(book.Bids) > 0 && len(book.Asks) > 0 {
+		fmt.Fprintf(w, "   Best bid %s @ %s, best ask %s @ %s\n",
+			book.Bids[0].Amount, book.Bids[0].Price, book.Asks[0].Amount, book.Asks[0].Price)
+	}
+
+	positions, err := exchange.GetPositions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get positions: %w", err)
+	}
+	fmt.Fprintf(w, "✅ %d open position(s)\n", len(positions))
+	printPositions(w, positions)
+	return nil
+}
+
+// PositionsCommand lists the open positions of an exchange
+func PositionsCommand() *Command {
+	return &Command{
+		Name:  "positions",
+		Usage: "[flags]",
+		Short: "List the open positions of an exchange",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			selection := addExchangeFlags(fs)
+
+			return func(ctx context.Context, args []string) error {
+				ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				defer cancel()
+				_, exchange, err := connect(ctx, selection)
+				if err != nil {
+					return err
+				}
+				defer exchange.Disconnect()
+
+				positions, err := exchange.GetPositions(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to get positions: %w", err)
+				}
+				if len(positions) == 0 {
+					fmt.Println("No open positions")
+					return nil
+				}
+				printPositions(os.Stdout, positions)
+				return nil
+			}
+		},
+	}
+}
+
+// printPositions prints positions as a table
+func printPositions(w io.Writer, positions []exchanges.Position) {
+	if len(positions) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SYMBOL\tSIDE\tSIZE\tENTRY\tMARK\tUNREALIZED PNL\tLEVERAGE")
+	for _, position := range positions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			position.Symbol, strings.ToUpper(string(position.Side)), position.Size,
+			position.EntryPrice, position.MarkPrice, position.UnrealizedPnL.StringFixed(2), position.Leverage)
+	}
+	tw.Flush()
+}
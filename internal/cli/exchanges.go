@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+
+	// Connectors register themselves with the exchanges package
+	_ "github.com/guyghost/constantine/internal/exchanges/coinbase"
+	_ "github.com/guyghost/constantine/internal/exchanges/dydx"
+	_ "github.com/guyghost/constantine/internal/exchanges/hyperliquid"
+)
+
+// LoadConfig loads the application configuration from the environment
+func LoadConfig() (*config.AppConfig, error) {
+	appConfig, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return appConfig, nil
+}
+
+// EnabledExchanges returns the names of the exchanges enabled in appConfig,
+// sorted
+func EnabledExchanges(appConfig *config.AppConfig) []string {
+	names := make([]string, 0, len(appConfig.Exchanges))
+	for name, cfg := range appConfig.Exchanges {
+		if cfg.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisteredExchanges returns the names of the connectors the bot can load,
+// sorted
+func RegisteredExchanges() []string {
+	return exchanges.Connectors()
+}
+
+// NewExchange creates the client of exchange name from its configuration
+func NewExchange(name string, cfg config.ExchangeConfig) (exchanges.Exchange, error) {
+	return exchanges.NewExchange(name, cfg)
+}
+
+// exchangeFlags are the flags of the commands talking to a single exchange
+type exchangeFlags struct {
+	name    *string
+	testnet *bool
+}
+
+// addExchangeFlags declares the exchange selection flags on fs
+func addExchangeFlags(fs *flag.FlagSet) exchangeFlags {
+	return exchangeFlags{
+		name:    fs.String("exchange", "", "Exchange to use, required when several are enabled ("+strings.Join(RegisteredExchanges(), ", ")+")"),
+		testnet: fs.Bool("testnet", false, "Use the testnet of the exchange, on connectors having one"),
+	}
+}
+
+// open loads the configuration and creates the client of the selected
+// exchange, the only enabled one when none is named
+func (f exchangeFlags) open() (string, exchanges.Exchange, error) {
+	appConfig, err := LoadConfig()
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := *f.name
+	if name == "" {
+		enabled := EnabledExchanges(appConfig)
+		switch len(enabled) {
+		case 0:
+			return "", nil, fmt.Errorf("no exchanges enabled - check ENABLE_* environment variables")
+		case 1:
+			name = enabled[0]
+		default:
+			return "", nil, fmt.Errorf("several exchanges enabled (%v), select one with -exchange", enabled)
+		}
+	}
+	cfg, ok := appConfig.Exchanges[name]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown exchange %q", name)
+	}
+
+	if *f.testnet {
+		exchange, err := exchanges.NewTestnetExchange(name, cfg)
+		return name, exchange, err
+	}
+	exchange, err := NewExchange(name, cfg)
+	return name, exchange, err
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// OrderCommand groups the commands placing and canceling orders by hand, and
+// dry-running the execution of a signal
+func OrderCommand() *Command {
+	return &Command{
+		Name:     "order",
+		Short:    "Place or cancel an order by hand, or dry-run a signal",
+		Commands: []*Command{orderPlaceCommand(), orderCancelCommand(), orderSignalCommand()},
+	}
+}
+
+// orderPlaceCommand places a single order after confirmation
+func orderPlaceCommand() *Command {
+	return &Command{
+		Name:  "place",
+		Usage: "-symbol SYMBOL -side buy|sell -amount AMOUNT [-type limit -price PRICE] [flags]",
+		Short: "Place an order, asking for confirmation unless -yes is set",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			selection := addExchangeFlags(fs)
+			symbol := fs.String("symbol", "BTC-USD", "Symbol to trade")
+			side := fs.String("side", "", "Order side: buy or sell")
+			orderType := fs.String("type", "limit", "Order type: limit or market")
+			amount := fs.String("amount", "", "Amount in base currency")
+			price := fs.String("price", "", "Limit price")
+			postOnly := fs.Bool("post-only", false, "Reject the order instead of taking liquidity")
+			dryRun := fs.Bool("dry-run", false, "Print the order without placing it")
+			yes := fs.Bool("yes", false, "Place the order without asking for confirmation")
+
+			return func(ctx context.Context, args []string) error {
+				order, err := buildOrder(*symbol, *side, *orderType, *amount, *price, *postOnly)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s %s %s %s", strings.ToUpper(string(order.Type)), strings.ToUpper(string(order.Side)), order.Amount, order.Symbol)
+				if order.Type == exchanges.OrderTypeLimit {
+					fmt.Printf(" @ %s", order.Price)
+				}
+				fmt.Println()
+				if *dryRun {
+					return nil
+				}
+
+				ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				defer cancel()
+				name, exchange, err := connect(ctx, selection)
+				if err != nil {
+					return err
+				}
+				defer exchange.Disconnect()
+
+				if !*yes && !confirm(os.Stdin, os.Stdout, fmt.Sprintf("Place this order on %s?", name)) {
+					fmt.Println("Order not placed")
+					return nil
+				}
+				placed, err := exchange.PlaceOrder(ctx, order)
+				if err != nil {
+					return fmt.Errorf("failed to place order: %w", err)
+				}
+				fmt.Printf("✅ Order %s placed, status %s\n", placed.ID, placed.Status)
+				return nil
+			}
+		},
+	}
+}
+
+// orderCancelCommand cancels an order by id
+func orderCancelCommand() *Command {
+	return &Command{
+		Name:  "cancel",
+		Usage: "[flags] ORDER_ID...",
+		Short: "Cancel orders by id",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			selection := addExchangeFlags(fs)
+
+			return func(ctx context.Context, args []string) error {
+				if len(args) == 0 {
+					fs.Usage()
+					return ErrUsage
+				}
+				ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				defer cancel()
+				_, exchange, err := connect(ctx, selection)
+				if err != nil {
+					return err
+				}
+				defer exchange.Disconnect()
+
+				for _, id := range args {
+					if err := exchange.CancelOrder(ctx, id); err != nil {
+						return fmt.Errorf("failed to cancel order %s: %w", id, err)
+					}
+					fmt.Printf("✅ Order %s canceled\n", id)
+				}
+				return nil
+			}
+		},
+	}
+}
+
+// buildOrder validates the order flags
+func buildOrder(symbol, side, orderType, amount, price string, postOnly bool) (*exchanges.Order, error) {
+	order := &exchanges.Order{
+		Symbol:   symbol,
+		Side:     exchanges.OrderSide(strings.ToLower(side)),
+		Type:     exchanges.OrderType(strings.ToLower(orderType)),
+		PostOnly: postOnly,
+	}
+	if symbol == "" {
+		return nil, fmt.Errorf("-symbol is required")
+	}
+	if order.Side != exchanges.OrderSideBuy && order.Side != exchanges.OrderSideSell {
+		return nil, fmt.Errorf("-side must be buy or sell, got %q", side)
+	}
+
+	parsed, err := decimal.NewFromString(amount)
+	if err != nil || !parsed.IsPositive() {
+		return nil, fmt.Errorf("-amount must be a positive number, got %q", amount)
+	}
+	order.Amount = parsed
+
+	switch order.Type {
+	case exchanges.OrderTypeLimit:
+		parsed, err := decimal.NewFromString(price)
+		if err != nil || !parsed.IsPositive() {
+			return nil, fmt.Errorf("-price must be a positive number for a limit order, got %q", price)
+		}
+		order.Price = parsed
+	case exchanges.OrderTypeMarket:
+		if price != "" {
+			return nil, fmt.Errorf("-price is not used by market orders")
+		}
+		if postOnly {
+			return nil, fmt.Errorf("-post-only is only valid for limit orders")
+		}
+	default:
+		return nil, fmt.Errorf("-type must be limit or market, got %q", orderType)
+	}
+	return order, nil
+}
+
+// confirm asks question on out and reports whether the answer read from in
+// is yes
+func confirm(in io.Reader, out io.Writer, question string) bool {
+	fmt.Fprintf(out, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "o", "oui":
+		return true
+	}
+	return false
+}
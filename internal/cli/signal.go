@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/risk"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+)
+
+// orderSignalCommand runs an artificial entry signal at the current price
+// through the risk manager and the execution agent of the bot, recording
+// the orders they would place instead of placing them
+func orderSignalCommand() *Command {
+	return &Command{
+		Name:  "signal",
+		Usage: "[flags]",
+		Short: "Run an artificial entry signal through risk and execution without placing orders",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			selection := addExchangeFlags(fs)
+			symbol := fs.String("symbol", "BTC-USD", "Symbol of the signal")
+			side := fs.String("side", "buy", "Side of the signal: buy or sell")
+			strength := fs.Float64("strength", 0.75, "Strength of the signal [0, 1]")
+			balance := fs.Float64("balance", 0, "Account balance to size the order with (default: the USD or USDC balance of the exchange)")
+
+			return func(ctx context.Context, args []string) error {
+				orderSide := exchanges.OrderSide(strings.ToLower(*side))
+				if orderSide != exchanges.OrderSideBuy && orderSide != exchanges.OrderSideSell {
+					return fmt.Errorf("-side must be buy or sell, got %q", *side)
+				}
+
+				ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				defer cancel()
+				_, exchange, err := connect(ctx, selection)
+				if err != nil {
+					return err
+				}
+				defer exchange.Disconnect()
+
+				ticker, err := exchange.GetTicker(ctx, *symbol)
+				if err != nil {
+					return fmt.Errorf("failed to get %s ticker: %w", *symbol, err)
+				}
+				accountBalance := decimal.NewFromFloat(*balance)
+				if *balance <= 0 {
+					if accountBalance, err = quoteBalance(ctx, exchange); err != nil {
+						return err
+					}
+				}
+				fmt.Printf("%s at %s, balance %s\n", *symbol, ticker.Last, accountBalance)
+
+				signal := &strategy.Signal{
+					Type:      strategy.SignalTypeEntry,
+					Side:      orderSide,
+					Symbol:    *symbol,
+					Price:     ticker.Last,
+					Strength:  *strength,
+					Reason:    "artificial signal",
+					Timestamp: time.Now().Unix(),
+				}
+				orders := &recordingOrders{}
+				executionConfig := execution.LoadConfig()
+				executionConfig.AutoExecute = true
+				executionConfig.ConfirmEntries = false
+				agent := execution.NewExecutionAgent(orders, risk.NewManager(risk.LoadConfig(), accountBalance), executionConfig)
+				if err := agent.HandleSignal(ctx, signal); err != nil {
+					return fmt.Errorf("signal rejected: %w", err)
+				}
+
+				if len(orders.requests) == 0 {
+					fmt.Println("⚠️  No order would be placed")
+					return nil
+				}
+				for _, req := range orders.requests {
+					fmt.Printf("✅ Would place %s %s %s %s @ %s, value %s, stop loss %s, take profit %s\n",
+						strings.ToUpper(string(req.Type)), strings.ToUpper(string(req.Side)), req.Amount, req.Symbol,
+						req.Price, req.Amount.Mul(req.Price).StringFixed(2), req.StopLoss, req.TakeProfit)
+				}
+				return nil
+			}
+		},
+	}
+}
+
+// quoteBalance returns the free USD or USDC balance of exchange
+func quoteBalance(ctx context.Context, exchange exchanges.Exchange) (decimal.Decimal, error) {
+	balances, err := exchange.GetBalance(ctx)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("failed to get balance, set -balance: %w", err)
+	}
+	for _, balance := range balances {
+		if balance.Asset == "USD" || balance.Asset == "USDC" {
+			return balance.Free, nil
+		}
+	}
+	return decimal.Zero, fmt.Errorf("no USD or USDC balance, set -balance")
+}
+
+// recordingOrders stands in for the order manager, recording the orders
+// instead of placing them
+type recordingOrders struct {
+	mu       sync.Mutex
+	requests []*order.OrderRequest
+}
+
+func (r *recordingOrders) GetPositions() []*order.ManagedPosition { return nil }
+
+func (r *recordingOrders) GetOpenOrders() []*exchanges.Order { return nil }
+
+func (r *recordingOrders) PlaceOrder(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	return &exchanges.Order{
+		ID:     fmt.Sprintf("dry-run-%d", len(r.requests)),
+		Symbol: req.Symbol,
+		Side:   req.Side,
+		Type:   req.Type,
+		Amount: req.Amount,
+		Price:  req.Price,
+		Status: exchanges.OrderStatusOpen,
+	}, nil
+}
+
+func (r *recordingOrders) ClosePosition(ctx context.Context, symbol string) error { return nil }
+
+func (r *recordingOrders) ReducePosition(ctx context.Context, symbol string, fraction decimal.Decimal) (*exchanges.Order, error) {
+	return &exchanges.Order{Symbol: symbol}, nil
+}
@@ -0,0 +1,123 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges/dydx"
+	"github.com/shopspring/decimal"
+)
+
+// SymbolsCommand ranks the dYdX markets by quality score
+func SymbolsCommand() *Command {
+	return &Command{
+		Name:  "symbols",
+		Usage: "[flags]",
+		Short: "Rank the dYdX markets by quality score",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			maxSymbols := fs.Int("max", 10, "Maximum number of symbols to select")
+			minQuality := fs.Float64("min-quality", -1, "Minimum quality score [0, 1] (default: AUTO_SELECT_MIN_QUALITY)")
+			verbose := fs.Bool("verbose", false, "Show the details of each market")
+			timeout := fs.Duration("timeout", 30*time.Second, "Time allowed to fetch and score the markets")
+
+			return func(ctx context.Context, args []string) error {
+				scoring, err := config.LoadScoring()
+				if err != nil {
+					return err
+				}
+				if *minQuality >= 0 {
+					scoring.Quality.MinQuality = *minQuality
+				}
+				ctx, cancel := context.WithTimeout(ctx, *timeout)
+				defer cancel()
+				return selectSymbols(ctx, scoring.Quality, *maxSymbols, *verbose)
+			}
+		},
+	}
+}
+
+// selectSymbols prints the best dYdX markets scored with quality
+func selectSymbols(ctx context.Context, quality config.QualityScoring, maxSymbols int, verbose bool) error {
+	client, err := dydx.NewClient("", "")
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+	client.SetQualityConfig(QualityConfig(quality))
+
+	fmt.Printf("Scoring dYdX markets: volume %.0f%%, liquidity %.0f%%, volatility %.0f%%, minimum quality %.0f%%\n\n",
+		quality.VolumeWeight*100, quality.LiquidityWeight*100, quality.VolatilityWeight*100, quality.MinQuality*100)
+
+	start := time.Now()
+	markets, err := client.SelectBestMarkets(ctx, maxSymbols, quality.MinQuality)
+	if err != nil {
+		return fmt.Errorf("failed to select markets: %w", err)
+	}
+	if len(markets) == 0 {
+		fmt.Println("⚠️  No markets met the selection criteria")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "RANK\tSYMBOL\tQUALITY\tVOLUME(USD)\tLIQUIDITY\tVOLATILITY")
+	for i, market := range markets {
+		fmt.Fprintf(w, "%d\t%s\t%.1f%%\t%s\t%.1f%%\t%.1f%%\n",
+			i+1, market.Symbol, market.QualityScore*100, formatVolume(market.Volume24h),
+			market.Liquidity*100, market.Volatility*100)
+	}
+	w.Flush()
+	fmt.Printf("\n✓ Selected %d markets in %.2fs\n", len(markets), time.Since(start).Seconds())
+
+	if verbose {
+		for i, market := range markets {
+			fmt.Printf("\n%d. %s: %s\n", i+1, market.Symbol, qualityStatus(market.QualityScore))
+			fmt.Printf("   Quality Score: %.2f%%\n", market.QualityScore*100)
+			fmt.Printf("   Volume (24h):  %s\n", formatVolume(market.Volume24h))
+			fmt.Printf("   Liquidity:     %.2f%%\n", market.Liquidity*100)
+			fmt.Printf("   Volatility:    %.2f%%\n", market.Volatility*100)
+		}
+	}
+	return nil
+}
+
+// QualityConfig converts the configured quality scoring to the weights of
+// the dYdX client
+func QualityConfig(quality config.QualityScoring) dydx.QualityConfig {
+	return dydx.QualityConfig{
+		VolumeWeight:     quality.VolumeWeight,
+		LiquidityWeight:  quality.LiquidityWeight,
+		VolatilityWeight: quality.VolatilityWeight,
+		MinVolumeUSD:     quality.MinVolumeUSD,
+	}
+}
+
+// qualityStatus describes a quality score
+func qualityStatus(score float64) string {
+	switch {
+	case score >= 0.7:
+		return "✅ EXCELLENT - Very high quality trading pair"
+	case score >= 0.5:
+		return "🟢 GOOD - Good quality trading pair"
+	case score >= 0.3:
+		return "🟡 FAIR - Moderate quality trading pair"
+	}
+	return "🔴 POOR - Lower quality trading pair"
+}
+
+// formatVolume formats a USD volume with a B, M or K suffix
+func formatVolume(volume decimal.Decimal) string {
+	volumeUSD := volume.InexactFloat64()
+	switch {
+	case volumeUSD >= 1_000_000_000:
+		return fmt.Sprintf("$%.1fB", volumeUSD/1_000_000_000)
+	case volumeUSD >= 1_000_000:
+		return fmt.Sprintf("$%.0fM", volumeUSD/1_000_000)
+	case volumeUSD >= 1_000:
+		return fmt.Sprintf("$%.0fK", volumeUSD/1_000)
+	}
+	return fmt.Sprintf("$%.0f", volumeUSD)
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/guyghost/constantine/internal/accounting"
+	"github.com/guyghost/constantine/internal/journal"
+	"github.com/guyghost/constantine/internal/statefile"
+	"github.com/shopspring/decimal"
+)
+
+// TaxCommand exports the gains realized during a year from the fills of the
+// trade journals
+func TaxCommand() *Command {
+	return &Command{
+		Name:  "tax",
+		Usage: "[flags] [JOURNAL...]",
+		Short: "Export the gains realized in a year as a capital gains CSV",
+		Flags: func(fs *flag.FlagSet) func(context.Context, []string) error {
+			year := fs.Int("year", time.Now().Year()-1, "Tax year of the disposals")
+			method := fs.String("method", string(accounting.FIFO), "Lot matching method: fifo, lifo or hifo")
+			output := fs.String("output", "", "CSV file to write (default: standard output)")
+
+			return func(ctx context.Context, args []string) error {
+				lotMethod, err := accounting.ParseLotMethod(*method)
+				if err != nil {
+					return err
+				}
+				paths := args
+				if len(paths) == 0 {
+					path := os.Getenv("TRADE_JOURNAL_PATH")
+					if path == "" {
+						return fmt.Errorf("no trade journal given and TRADE_JOURNAL_PATH is not set")
+					}
+					paths = []string{path}
+				}
+				fills, err := loadFills(paths)
+				if err != nil {
+					return err
+				}
+
+				// Lots opened in earlier years are matched by the disposals
+				// of this one
+				tracker := accounting.NewLotTracker(lotMethod)
+				for _, fill := range fills {
+					tracker.Add(fill)
+				}
+				gains := accounting.GainsInYear(tracker.Gains(), *year)
+
+				var w io.Writer = os.Stdout
+				if *output != "" {
+					file, err := os.Create(*output)
+					if err != nil {
+						return fmt.Errorf("failed to create %s: %w", *output, err)
+					}
+					defer file.Close()
+					w = file
+				}
+				if err := accounting.WriteGainsCSV(w, gains); err != nil {
+					return fmt.Errorf("failed to write gains: %w", err)
+				}
+				printGainsSummary(os.Stderr, *year, lotMethod, gains, tracker.OpenLots())
+				return nil
+			}
+		},
+	}
+}
+
+// loadFills reads the fills of the journals at paths, in time order.
+// Journals written with STATE_PASSPHRASE set are decrypted with it.
+func loadFills(paths []string) ([]accounting.Fill, error) {
+	if _, err := statefile.SetPassphraseFromEnv(); err != nil {
+		return nil, err
+	}
+	var fills []accounting.Fill
+	for _, path := range paths {
+		entries, err := journal.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, execution := range journal.Executions(entries) {
+			fills = append(fills, accounting.FillFromExecution(execution))
+		}
+	}
+	sort.SliceStable(fills, func(i, j int) bool { return fills[i].Time.Before(fills[j].Time) })
+	return fills, nil
+}
+
+// printGainsSummary prints the totals of the gains and the lots left open
+func printGainsSummary(w io.Writer, year int, method accounting.LotMethod, gains []accounting.Gain, open []accounting.Lot) {
+	proceeds, cost, shortTerm, longTerm := decimal.Zero, decimal.Zero, decimal.Zero, decimal.Zero
+	for _, gain := range gains {
+		proceeds = proceeds.Add(gain.Proceeds)
+		cost = cost.Add(gain.CostBasis)
+		if gain.LongTerm {
+			longTerm = longTerm.Add(gain.Gain)
+		} else {
+			shortTerm = shortTerm.Add(gain.Gain)
+		}
+	}
+	fmt.Fprintf(w, "%d: %d disposal(s) matched %s, proceeds %s, cost basis %s, short-term gain %s, long-term gain %s\n",
+		year, len(gains), method, proceeds.StringFixed(2), cost.StringFixed(2), shortTerm.StringFixed(2), longTerm.StringFixed(2))
+	if len(open) > 0 {
+		fmt.Fprintf(w, "%d lot(s) still open\n", len(open))
+	}
+}
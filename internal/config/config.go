@@ -1,12 +1,15 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/shopspring/decimal"
 )
 
@@ -27,17 +30,65 @@ type Config struct {
 	MaxPriceChangePercent float64 // Maximum allowed price change between updates (default: 5%)
 	MinPrice              decimal.Decimal
 	MaxPrice              decimal.Decimal
+	// Ticks moving more than MaxPriceChangePercent are quarantined until
+	// SpikeConfirmations ticks confirm the new level (default: 3), or accepted
+	// at once within PriceDeviationPercent of the median price across
+	// exchanges (default: 1%)
+	SpikeConfirmations    int
+	PriceDeviationPercent float64
+	// With PriceBoundsPercent set, each symbol also rejects prices further
+	// than that from a reference calibrated on its first price, which follows
+	// accepted prices with a half-life of PriceBoundsHalfLife (default: 24h)
+	PriceBoundsPercent  float64
+	PriceBoundsHalfLife time.Duration
+	// Order book microstructure features
+	OrderBookLevels       int     // Levels used for the volume imbalance (default: 5)
+	OrderBookDepthBps     float64 // Band around the mid used to measure depth (default: 10 bps)
+	OrderBookMaxSpreadBps float64 // Spread at which the order book stops weighing in signals (default: 25 bps)
+	// Optional model scoring of entry signals
+	ScorerModelPath     string            // Model used for symbols without their own
+	ScorerModels        map[string]string // Model path per symbol
+	ScorerWeight        float64           // Share of the model probability in the signal strength (default: 0.5)
+	ScorerVetoThreshold float64           // Entries scored below this probability are dropped (default: 0, never)
+	// Market regime classification
+	RegimeADXPeriod         int     // Period of the ADX and ATR (default: 14)
+	RegimeTrendADX          float64 // ADX above which the market is trending (default: 25)
+	RegimeVolWindow         int     // Window of the short-term realized volatility (default: 10)
+	RegimeHighVolRatio      float64 // Short over long realized volatility flagging high volatility (default: 1.5)
+	RegimeHighVolATRPercent float64 // ATR as a percentage of price flagging high volatility (default: 2%)
+	RegimeBlockCounterTrend bool    // Block entries against the trend in a trending regime (default: true)
+	// Symbol selection diversity: scores are lowered by the penalty times the
+	// highest return correlation with the symbols already selected
+	SelectionCorrelationPenalty float64 // Default: 0.5, 0 to select by score alone
+	SelectionCorrelationWindow  int     // Returns the correlation is measured over (default: 60)
+	// Symbol selection scoring, loaded with the AppConfig
+	Selection SelectionScoring
+	// Signal debounce: a signal repeating the type and side of the last one
+	// is emitted again only once the price moved SignalMinPriceDeltaPercent
+	// or the strength SignalMinStrengthDelta (0: never), and no signal but
+	// an exit follows another within SignalMinInterval (default: 0)
+	SignalMinInterval          time.Duration
+	SignalMinPriceDeltaPercent float64
+	SignalMinStrengthDelta     float64
+	// Market data: interval of the candles the indicators are computed on
+	// (1m, 5m, 15m, 1h or 1d; default: 1m) and number of prices and volumes
+	// kept (default: 100)
+	CandleInterval time.Duration
+	HistorySize    int
+	// Per-symbol UpdateInterval, CandleInterval and HistorySize, applied by
+	// ForSymbol
+	SymbolUpdateIntervals map[string]time.Duration
+	SymbolCandleIntervals map[string]time.Duration
+	SymbolHistorySizes    map[string]int
+	// Long history: HistoryTiers tiers of HistorySize values each keep the
+	// prices and volumes evicted from the previous tier, downsampled by
+	// HistoryDownsample (default: 0 tiers, downsampled by 5)
+	HistoryTiers      int
+	HistoryDownsample int
 }
 
 // ExchangeConfig holds configuration for an exchange
-type ExchangeConfig struct {
-	Enabled          bool
-	APIKey           string
-	APISecret        string
-	PortfolioID      string // For Coinbase
-	Mnemonic         string // For dYdX
-	SubAccountNumber int    // For dYdX
-}
+type ExchangeConfig = exchanges.Config
 
 // AppConfig holds application-wide configuration
 type AppConfig struct {
@@ -46,25 +97,60 @@ type AppConfig struct {
 	TradingSymbols []string // Multi-symbol support
 	InitialBalance decimal.Decimal
 	Exchanges      map[string]ExchangeConfig
+	// Shutdown behaviour
+	ShutdownPolicy  string        // cancel-all (entries only), flatten-all or leave
+	ShutdownTimeout time.Duration // Upper bound for executing the shutdown policy
+	// HedgeMode tracks long and short positions on the same symbol
+	// independently; the bot refuses to start with it on venues without
+	// hedged positions
+	HedgeMode bool
+	// ReportingCurrency is the currency balances, PnL and risk limits are
+	// expressed in
+	ReportingCurrency string
+	// Scoring weighs the market quality and symbol selection scores
+	Scoring ScoringConfig
 }
 
 // DefaultConfig returns default strategy configuration
 func DefaultConfig() *Config {
 	cfg := &Config{
-		Symbol:                "BTC-USD",
-		ShortEMAPeriod:        9,
-		LongEMAPeriod:         21,
-		RSIPeriod:             14,
-		RSIOversold:           30.0,
-		RSIOverbought:         70.0,
-		TakeProfitPercent:     2.0, // Updated to 2%
-		StopLossPercent:       1.0, // Updated to 1%
-		MaxPositionSize:       decimal.NewFromFloat(0.1),
-		MinPriceMove:          decimal.NewFromFloat(0.01),
-		UpdateInterval:        5 * time.Second,               // Reduced from 1s to 5s (less CPU usage, aligned with data updates)
-		MaxPriceChangePercent: 5.0,                           // 5% max price change
-		MinPrice:              decimal.NewFromFloat(0.01),    // Minimum valid price
-		MaxPrice:              decimal.NewFromFloat(1000000), // Maximum valid price
+		Symbol:                  "BTC-USD",
+		ShortEMAPeriod:          9,
+		LongEMAPeriod:           21,
+		RSIPeriod:               14,
+		RSIOversold:             30.0,
+		RSIOverbought:           70.0,
+		TakeProfitPercent:       2.0, // Updated to 2%
+		StopLossPercent:         1.0, // Updated to 1%
+		MaxPositionSize:         decimal.NewFromFloat(0.1),
+		MinPriceMove:            decimal.NewFromFloat(0.01),
+		UpdateInterval:          5 * time.Second,               // Reduced from 1s to 5s (less CPU usage, aligned with data updates)
+		MaxPriceChangePercent:   5.0,                           // 5% max price change
+		MinPrice:                decimal.NewFromFloat(0.01),    // Minimum valid price
+		MaxPrice:                decimal.NewFromFloat(1000000), // Maximum valid price
+		SpikeConfirmations:      3,
+		PriceDeviationPercent:   1.0,
+		OrderBookLevels:         5,
+		OrderBookDepthBps:       10,
+		OrderBookMaxSpreadBps:   25,
+		ScorerWeight:            0.5,
+		RegimeADXPeriod:         14,
+		RegimeTrendADX:          25,
+		RegimeVolWindow:         10,
+		RegimeHighVolRatio:      1.5,
+		RegimeHighVolATRPercent: 2.0,
+		RegimeBlockCounterTrend: true,
+
+		PriceBoundsHalfLife: 24 * time.Hour,
+
+		CandleInterval: DefaultCandleInterval,
+		HistorySize:    DefaultHistorySize,
+
+		HistoryDownsample: DefaultHistoryDownsample,
+
+		SelectionCorrelationPenalty: 0.5,
+		SelectionCorrelationWindow:  60,
+		Selection:                   DefaultScoring().Selection,
 	}
 
 	if symbol := os.Getenv("STRATEGY_SYMBOL"); symbol != "" {
@@ -109,6 +195,20 @@ func DefaultConfig() *Config {
 	if val := parseFloatEnv("STRATEGY_MAX_PRICE_CHANGE_PERCENT", cfg.MaxPriceChangePercent); val > 0 {
 		cfg.MaxPriceChangePercent = val
 	}
+	if val := parseIntEnv("STRATEGY_SPIKE_CONFIRMATIONS", cfg.SpikeConfirmations); val > 0 {
+		cfg.SpikeConfirmations = val
+	}
+	if val := parseFloatEnv("STRATEGY_PRICE_DEVIATION_PERCENT", cfg.PriceDeviationPercent); val > 0 {
+		cfg.PriceDeviationPercent = val
+	}
+	if val := parseFloatEnv("STRATEGY_PRICE_BOUNDS_PERCENT", cfg.PriceBoundsPercent); val > 0 {
+		cfg.PriceBoundsPercent = val
+	}
+	if value := os.Getenv("STRATEGY_PRICE_BOUNDS_HALF_LIFE"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed >= 0 {
+			cfg.PriceBoundsHalfLife = parsed
+		}
+	}
 	if value := os.Getenv("STRATEGY_MIN_PRICE"); value != "" {
 		if parsed, err := decimal.NewFromString(value); err == nil && parsed.GreaterThan(decimal.Zero) {
 			cfg.MinPrice = parsed
@@ -119,18 +219,113 @@ func DefaultConfig() *Config {
 			cfg.MaxPrice = parsed
 		}
 	}
+	if val := parseIntEnv("STRATEGY_ORDERBOOK_LEVELS", cfg.OrderBookLevels); val > 0 {
+		cfg.OrderBookLevels = val
+	}
+	if val := parseFloatEnv("STRATEGY_ORDERBOOK_DEPTH_BPS", cfg.OrderBookDepthBps); val > 0 {
+		cfg.OrderBookDepthBps = val
+	}
+	if val := parseFloatEnv("STRATEGY_ORDERBOOK_MAX_SPREAD_BPS", cfg.OrderBookMaxSpreadBps); val > 0 {
+		cfg.OrderBookMaxSpreadBps = val
+	}
+	cfg.ScorerModelPath = os.Getenv("STRATEGY_SCORER_MODEL")
+	if models := os.Getenv("STRATEGY_SCORER_MODELS"); models != "" {
+		cfg.ScorerModels = parseSymbolMap(models)
+	}
+	if val := parseFloatEnv("STRATEGY_SCORER_WEIGHT", cfg.ScorerWeight); val >= 0 && val <= 1 {
+		cfg.ScorerWeight = val
+	}
+	if val := parseFloatEnv("STRATEGY_SCORER_VETO_THRESHOLD", cfg.ScorerVetoThreshold); val >= 0 && val <= 1 {
+		cfg.ScorerVetoThreshold = val
+	}
+	if val := parseIntEnv("STRATEGY_REGIME_ADX_PERIOD", cfg.RegimeADXPeriod); val > 0 {
+		cfg.RegimeADXPeriod = val
+	}
+	if val := parseFloatEnv("STRATEGY_REGIME_TREND_ADX", cfg.RegimeTrendADX); val > 0 {
+		cfg.RegimeTrendADX = val
+	}
+	if val := parseIntEnv("STRATEGY_REGIME_VOL_WINDOW", cfg.RegimeVolWindow); val > 1 {
+		cfg.RegimeVolWindow = val
+	}
+	if val := parseFloatEnv("STRATEGY_REGIME_HIGH_VOL_RATIO", cfg.RegimeHighVolRatio); val > 0 {
+		cfg.RegimeHighVolRatio = val
+	}
+	if val := parseFloatEnv("STRATEGY_REGIME_HIGH_VOL_ATR_PERCENT", cfg.RegimeHighVolATRPercent); val > 0 {
+		cfg.RegimeHighVolATRPercent = val
+	}
+	if value := os.Getenv("STRATEGY_REGIME_BLOCK_COUNTER_TREND"); value != "" {
+		cfg.RegimeBlockCounterTrend = value == "true"
+	}
+	if val := parseFloatEnv("STRATEGY_SELECTION_CORRELATION_PENALTY", cfg.SelectionCorrelationPenalty); val >= 0 {
+		cfg.SelectionCorrelationPenalty = val
+	}
+	if val := parseIntEnv("STRATEGY_SELECTION_CORRELATION_WINDOW", cfg.SelectionCorrelationWindow); val > 1 {
+		cfg.SelectionCorrelationWindow = val
+	}
+	if value := os.Getenv("STRATEGY_SIGNAL_MIN_INTERVAL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed >= 0 {
+			cfg.SignalMinInterval = parsed
+		}
+	}
+	if val := parseFloatEnv("STRATEGY_SIGNAL_MIN_PRICE_DELTA_PERCENT", cfg.SignalMinPriceDeltaPercent); val >= 0 {
+		cfg.SignalMinPriceDeltaPercent = val
+	}
+	if val := parseFloatEnv("STRATEGY_SIGNAL_MIN_STRENGTH_DELTA", cfg.SignalMinStrengthDelta); val >= 0 {
+		cfg.SignalMinStrengthDelta = val
+	}
+	loadMarketDataEnv(cfg)
 
 	return cfg
 }
 
+// Validate checks that the indicator periods, thresholds and price bounds of
+// the strategy are consistent, reporting every problem found
+func (c *Config) Validate() error {
+	var errs []error
+	if c.ShortEMAPeriod < 1 || c.LongEMAPeriod <= c.ShortEMAPeriod {
+		errs = append(errs, fmt.Errorf("EMAs must satisfy 1 <= short < long, got %d and %d", c.ShortEMAPeriod, c.LongEMAPeriod))
+	}
+	if c.RSIPeriod < 1 {
+		errs = append(errs, fmt.Errorf("RSI period must be positive, got %d", c.RSIPeriod))
+	}
+	if c.RSIOversold < 0 || c.RSIOverbought > 100 || c.RSIOversold >= c.RSIOverbought {
+		errs = append(errs, fmt.Errorf("RSI thresholds must satisfy 0 <= oversold < overbought <= 100, got %g and %g", c.RSIOversold, c.RSIOverbought))
+	}
+	if c.TakeProfitPercent <= 0 || c.StopLossPercent <= 0 {
+		errs = append(errs, fmt.Errorf("take profit and stop loss must be positive, got %g%% and %g%%", c.TakeProfitPercent, c.StopLossPercent))
+	}
+	if !c.MaxPositionSize.IsPositive() {
+		errs = append(errs, fmt.Errorf("max position size must be positive, got %s", c.MaxPositionSize))
+	}
+	if c.MinPrice.IsNegative() || !c.MaxPrice.GreaterThan(c.MinPrice) {
+		errs = append(errs, fmt.Errorf("price bounds must satisfy 0 <= min < max, got %s and %s", c.MinPrice, c.MaxPrice))
+	}
+	errs = append(errs, c.validateMarketData()...)
+	if c.ScorerModelPath != "" {
+		if _, err := os.Stat(c.ScorerModelPath); err != nil {
+			errs = append(errs, fmt.Errorf("scorer model: %w", err))
+		}
+	}
+	for symbol, path := range c.ScorerModels {
+		if _, err := os.Stat(path); err != nil {
+			errs = append(errs, fmt.Errorf("scorer model of %s: %w", symbol, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Load loads application configuration from environment variables
 func Load() (*AppConfig, error) {
 	cfg := &AppConfig{
-		TelemetryAddr:  ":9090", // Default telemetry address
-		StrategySymbol: "BTC-USD",
-		TradingSymbols: []string{"BTC-USD"},         // Default single symbol
-		InitialBalance: decimal.NewFromFloat(10000), // Default $10,000
-		Exchanges:      make(map[string]ExchangeConfig),
+		TelemetryAddr:   ":9090", // Default telemetry address
+		StrategySymbol:  "BTC-USD",
+		TradingSymbols:  []string{"BTC-USD"},         // Default single symbol
+		InitialBalance:  decimal.NewFromFloat(10000), // Default $10,000
+		Exchanges:       make(map[string]ExchangeConfig),
+		ShutdownPolicy:  "cancel-all",
+		ShutdownTimeout: 10 * time.Second,
+
+		ReportingCurrency: "USD",
 	}
 
 	// Load telemetry address
@@ -171,48 +366,77 @@ func Load() (*AppConfig, error) {
 		}
 	}
 
-	// Load exchange configurations
-	cfg.Exchanges["hyperliquid"] = ExchangeConfig{
-		Enabled:   os.Getenv("ENABLE_HYPERLIQUID") == "true",
-		APIKey:    os.Getenv("HYPERLIQUID_API_KEY"),
-		APISecret: os.Getenv("HYPERLIQUID_API_SECRET"),
+	// Load shutdown policy
+	if policy := os.Getenv("SHUTDOWN_POLICY"); policy != "" {
+		cfg.ShutdownPolicy = strings.ToLower(strings.TrimSpace(policy))
+	}
+	if timeout := os.Getenv("SHUTDOWN_TIMEOUT"); timeout != "" {
+		if parsed, err := time.ParseDuration(timeout); err == nil && parsed > 0 {
+			cfg.ShutdownTimeout = parsed
+		}
 	}
 
-	cfg.Exchanges["coinbase"] = ExchangeConfig{
-		Enabled:     os.Getenv("ENABLE_COINBASE") == "true",
-		APIKey:      os.Getenv("COINBASE_API_KEY"),
-		APISecret:   os.Getenv("COINBASE_API_SECRET"),
-		PortfolioID: os.Getenv("COINBASE_PORTFOLIO_ID"),
+	// Load hedge mode
+	cfg.HedgeMode = os.Getenv("HEDGE_MODE") == "true"
+
+	if currency := os.Getenv("REPORTING_CURRENCY"); currency != "" {
+		cfg.ReportingCurrency = strings.ToUpper(strings.TrimSpace(currency))
 	}
 
-	cfg.Exchanges["dydx"] = ExchangeConfig{
-		Enabled:          os.Getenv("ENABLE_DYDX") == "true",
-		APIKey:           os.Getenv("DYDX_API_KEY"),
-		APISecret:        os.Getenv("DYDX_API_SECRET"),
-		Mnemonic:         os.Getenv("DYDX_MNEMONIC"),
-		SubAccountNumber: parseIntEnv("DYDX_SUB_ACCOUNT_NUMBER", 0),
+	// Load exchange configurations, each connector reading and validating
+	// its own settings
+	exchangeConfigs, err := exchanges.LoadConfigs(os.Getenv)
+	if err != nil {
+		return nil, err
 	}
+	cfg.Exchanges = exchangeConfigs
 
-	// Validate exchange configurations
-	if cfg.Exchanges["hyperliquid"].Enabled {
-		if cfg.Exchanges["hyperliquid"].APIKey == "" || cfg.Exchanges["hyperliquid"].APISecret == "" {
-			return nil, fmt.Errorf("hyperliquid enabled but API key or secret is missing")
+	// Load the base currency and tradable symbols of each exchange
+	for name, exchange := range cfg.Exchanges {
+		prefix := strings.ToUpper(name)
+		exchange.BaseCurrency = strings.ToUpper(strings.TrimSpace(os.Getenv(prefix + "_BASE_CURRENCY")))
+		exchange.AllowSymbols = parseList(os.Getenv(prefix + "_ALLOW_SYMBOLS"))
+		exchange.DenySymbols = parseList(os.Getenv(prefix + "_DENY_SYMBOLS"))
+		for _, pattern := range append(append([]string(nil), exchange.AllowSymbols...), exchange.DenySymbols...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid %s symbol pattern %q: %w", name, pattern, err)
+			}
 		}
+		cfg.Exchanges[name] = exchange
 	}
 
-	if cfg.Exchanges["coinbase"].Enabled {
-		if cfg.Exchanges["coinbase"].APIKey == "" || cfg.Exchanges["coinbase"].APISecret == "" {
-			return nil, fmt.Errorf("coinbase enabled but API key or secret is missing")
-		}
+	scoring, err := LoadScoring()
+	if err != nil {
+		return nil, err
 	}
+	cfg.Scoring = scoring
 
-	if cfg.Exchanges["dydx"].Enabled {
-		if cfg.Exchanges["dydx"].Mnemonic == "" && cfg.Exchanges["dydx"].APISecret == "" {
-			return nil, fmt.Errorf("dYdX enabled but no authentication method provided - set DYDX_MNEMONIC or DYDX_API_KEY/DYDX_API_SECRET")
+	return cfg, nil
+}
+
+// parseList parses a comma-separated list, leaving out empty entries
+func parseList(value string) []string {
+	var result []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
 		}
 	}
+	return result
+}
 
-	return cfg, nil
+// parseSymbolMap parses a comma-separated list of SYMBOL=value pairs
+func parseSymbolMap(value string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		symbol, entry, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		symbol = strings.TrimSpace(symbol)
+		entry = strings.TrimSpace(entry)
+		if ok && symbol != "" && entry != "" {
+			result[symbol] = entry
+		}
+	}
+	return result
 }
 
 // parseIntEnv parses an integer environment variable
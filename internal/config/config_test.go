@@ -1,9 +1,17 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/shopspring/decimal"
+
+	// The connectors read their own configuration
+	_ "github.com/guyghost/constantine/internal/exchanges/coinbase"
+	_ "github.com/guyghost/constantine/internal/exchanges/dydx"
+	_ "github.com/guyghost/constantine/internal/exchanges/hyperliquid"
 )
 
 func TestLoad_SucceedsWithRequiredSecrets(t *testing.T) {
@@ -46,6 +54,30 @@ func TestLoad_FailsWhenCoinbaseSecretMissing(t *testing.T) {
 	}
 }
 
+func TestLoad_FailsWhenCoinbasePerpetualsHaveNoPortfolio(t *testing.T) {
+	t.Setenv("HYPERLIQUID_API_KEY", "test-key")
+	t.Setenv("HYPERLIQUID_API_SECRET", "test-secret")
+	t.Setenv("ENABLE_COINBASE", "true")
+	t.Setenv("COINBASE_API_KEY", "coinbase-key")
+	t.Setenv("COINBASE_API_SECRET", "coinbase-secret")
+	t.Setenv("COINBASE_PERPETUALS", "true")
+	t.Setenv("COINBASE_PORTFOLIO_ID", "")
+	t.Setenv("ENABLE_DYDX", "false")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error with coinbase perpetuals and no portfolio")
+	}
+
+	t.Setenv("COINBASE_PORTFOLIO_ID", "portfolio-1")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !cfg.Exchanges["coinbase"].Perpetuals {
+		t.Error("expected coinbase perpetuals to be enabled")
+	}
+}
+
 func TestLoad_FailsWhenDydxMissingAuth(t *testing.T) {
 	t.Setenv("HYPERLIQUID_API_KEY", "test-key")
 	t.Setenv("HYPERLIQUID_API_SECRET", "test-secret")
@@ -81,3 +113,169 @@ func TestLoad_AllowsTelemetryAndStrategyOverrides(t *testing.T) {
 		t.Fatalf("expected initial balance override, got %s", cfg.InitialBalance)
 	}
 }
+
+func TestLoad_ShutdownPolicy(t *testing.T) {
+	t.Setenv("ENABLE_HYPERLIQUID", "false")
+	t.Setenv("ENABLE_COINBASE", "false")
+	t.Setenv("ENABLE_DYDX", "false")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected config to load, got error: %v", err)
+	}
+	if cfg.ShutdownPolicy != "cancel-all" || cfg.ShutdownTimeout != 10*time.Second {
+		t.Fatalf("unexpected shutdown defaults: %s %s", cfg.ShutdownPolicy, cfg.ShutdownTimeout)
+	}
+
+	t.Setenv("SHUTDOWN_POLICY", "Flatten-All")
+	t.Setenv("SHUTDOWN_TIMEOUT", "30s")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("expected config to load, got error: %v", err)
+	}
+	if cfg.ShutdownPolicy != "flatten-all" {
+		t.Fatalf("expected flatten-all policy, got %s", cfg.ShutdownPolicy)
+	}
+	if cfg.ShutdownTimeout != 30*time.Second {
+		t.Fatalf("expected 30s timeout, got %s", cfg.ShutdownTimeout)
+	}
+}
+
+func TestLoad_HedgeMode(t *testing.T) {
+	t.Setenv("ENABLE_HYPERLIQUID", "false")
+	t.Setenv("ENABLE_COINBASE", "false")
+	t.Setenv("ENABLE_DYDX", "false")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected config to load, got error: %v", err)
+	}
+	if cfg.HedgeMode {
+		t.Fatal("hedge mode should be disabled by default")
+	}
+
+	t.Setenv("HEDGE_MODE", "true")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("expected config to load, got error: %v", err)
+	}
+	if !cfg.HedgeMode {
+		t.Fatal("expected hedge mode to be enabled")
+	}
+}
+
+func TestLoad_ExchangeSymbols(t *testing.T) {
+	t.Setenv("ENABLE_HYPERLIQUID", "false")
+	t.Setenv("ENABLE_COINBASE", "false")
+	t.Setenv("ENABLE_DYDX", "false")
+	t.Setenv("COINBASE_BASE_CURRENCY", "eur")
+	t.Setenv("COINBASE_ALLOW_SYMBOLS", "*-EUR, *-USD,")
+	t.Setenv("COINBASE_DENY_SYMBOLS", "PEPE-*")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected config to load, got error: %v", err)
+	}
+	coinbase := cfg.Exchanges["coinbase"]
+	if coinbase.BaseCurrency != "EUR" || len(coinbase.AllowSymbols) != 2 || coinbase.AllowSymbols[1] != "*-USD" ||
+		len(coinbase.DenySymbols) != 1 {
+		t.Errorf("unexpected coinbase symbols: %+v", coinbase)
+	}
+	if dydx := cfg.Exchanges["dydx"]; dydx.BaseCurrency != "" || dydx.AllowSymbols != nil {
+		t.Errorf("expected dYdX to trade every symbol, got %+v", dydx)
+	}
+
+	t.Setenv("DYDX_DENY_SYMBOLS", "[BTC")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected an error for a malformed symbol pattern")
+	}
+}
+
+func TestDefaultConfig_ScorerModels(t *testing.T) {
+	t.Setenv("STRATEGY_SCORER_MODEL", "models/default.json")
+	t.Setenv("STRATEGY_SCORER_MODELS", "BTC-USD=models/btc.json, ETH-USD = models/eth.json,invalid")
+	t.Setenv("STRATEGY_SCORER_VETO_THRESHOLD", "0.4")
+
+	cfg := DefaultConfig()
+	if cfg.ScorerModelPath != "models/default.json" {
+		t.Errorf("unexpected default model %q", cfg.ScorerModelPath)
+	}
+	if len(cfg.ScorerModels) != 2 || cfg.ScorerModels["ETH-USD"] != "models/eth.json" {
+		t.Errorf("unexpected per-symbol models %v", cfg.ScorerModels)
+	}
+	if cfg.ScorerVetoThreshold != 0.4 || cfg.ScorerWeight != 0.5 {
+		t.Errorf("unexpected scorer settings: weight %f, veto %f", cfg.ScorerWeight, cfg.ScorerVetoThreshold)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Fatalf("expected the defaults to be valid, got %v", err)
+	}
+
+	for name, mutate := range map[string]func(*Config){
+		"inverted EMAs":         func(c *Config) { c.ShortEMAPeriod = 30 },
+		"inverted RSI":          func(c *Config) { c.RSIOversold, c.RSIOverbought = 70, 30 },
+		"zero stop loss":        func(c *Config) { c.StopLossPercent = 0 },
+		"zero position size":    func(c *Config) { c.MaxPositionSize = decimal.Zero },
+		"inverted price bounds": func(c *Config) { c.MinPrice = decimal.NewFromInt(2000000) },
+		"missing scorer model":  func(c *Config) { c.ScorerModelPath = filepath.Join(t.TempDir(), "missing.json") },
+		"missing symbol scorer": func(c *Config) { c.ScorerModels = map[string]string{"BTC-USD": filepath.Join(t.TempDir(), "btc.json")} },
+	} {
+		cfg := DefaultConfig()
+		mutate(cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestLoadScoring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scoring.json")
+	data := `{"quality": {"volume_weight": 0.5, "liquidity_weight": 0.3, "volatility_weight": 0.2},
+		"selection": {"threshold": 0.5, "lookback": 60}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SCORING_CONFIG_FILE", path)
+	t.Setenv("AUTO_SELECT_MIN_QUALITY", "0.4")
+	t.Setenv("SCORING_SELECTION_LOOKBACK", "90")
+
+	cfg, err := LoadScoring()
+	if err != nil {
+		t.Fatalf("expected scoring config to load, got error: %v", err)
+	}
+	if cfg.Quality.VolumeWeight != 0.5 || cfg.Quality.MinQuality != 0.4 || cfg.Quality.MinVolumeUSD != 100000 {
+		t.Errorf("expected file and env to override the defaults, got %+v", cfg.Quality)
+	}
+	if cfg.Selection.Threshold != 0.5 || cfg.Selection.Lookback != 90 || cfg.Selection.GainWeight != 0.4 {
+		t.Errorf("expected env to override the file, got %+v", cfg.Selection)
+	}
+
+	t.Setenv("SCORING_SELECTION_RISK_WEIGHT", "0.3")
+	if _, err := LoadScoring(); err == nil {
+		t.Fatal("expected selection weights not summing to 1 to be rejected")
+	}
+}
+
+func TestScoringConfig_Validate(t *testing.T) {
+	if err := DefaultScoring().Validate(); err != nil {
+		t.Fatalf("expected the defaults to be valid, got %v", err)
+	}
+
+	for name, mutate := range map[string]func(*ScoringConfig){
+		"negative weight":        func(c *ScoringConfig) { c.Quality.VolumeWeight, c.Quality.LiquidityWeight = -0.1, 0.8 },
+		"quality weight sum":     func(c *ScoringConfig) { c.Quality.VolatilityWeight = 0.5 },
+		"min quality above 1":    func(c *ScoringConfig) { c.Quality.MinQuality = 1.5 },
+		"risk weight sum":        func(c *ScoringConfig) { c.Selection.RiskVolumeWeight = 0 },
+		"inverted volume bounds": func(c *ScoringConfig) { c.Selection.VolumeLow = 2000 },
+		"inverted EMAs":          func(c *ScoringConfig) { c.Selection.ShortEMA = 30 },
+		"lookback too short":     func(c *ScoringConfig) { c.Selection.Lookback = 10 },
+	} {
+		cfg := DefaultScoring()
+		mutate(&cfg)
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
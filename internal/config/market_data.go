@@ -0,0 +1,190 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCandleInterval, DefaultHistorySize and DefaultHistoryDownsample
+// apply to configs that set none
+const (
+	DefaultCandleInterval    = time.Minute
+	DefaultHistorySize       = 100
+	DefaultHistoryDownsample = 5
+)
+
+// candleIntervals are the candle intervals every exchange serves, by name
+var candleIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// ParseCandleInterval parses a candle interval name: 1m, 5m, 15m, 1h or 1d
+func ParseCandleInterval(value string) (time.Duration, bool) {
+	interval, ok := candleIntervals[strings.ToLower(strings.TrimSpace(value))]
+	return interval, ok
+}
+
+// candleIntervalName returns the name exchanges know the candle interval by,
+// empty when they do not all serve it
+func candleIntervalName(interval time.Duration) string {
+	for name, d := range candleIntervals {
+		if d == interval {
+			return name
+		}
+	}
+	return ""
+}
+
+// Candles returns the interval of the candles the indicators are computed on
+func (c *Config) Candles() time.Duration {
+	if c.CandleInterval > 0 {
+		return c.CandleInterval
+	}
+	return DefaultCandleInterval
+}
+
+// CandleIntervalName returns the name exchanges know the interval of the
+// candles by, that of the default interval when they do not serve it
+func (c *Config) CandleIntervalName() string {
+	if name := candleIntervalName(c.Candles()); name != "" {
+		return name
+	}
+	return candleIntervalName(DefaultCandleInterval)
+}
+
+// History returns the number of prices and volumes kept
+func (c *Config) History() int {
+	if c.HistorySize > 0 {
+		return c.HistorySize
+	}
+	return DefaultHistorySize
+}
+
+// Downsample returns by how much each history tier downsamples the previous
+// one
+func (c *Config) Downsample() int {
+	if c.HistoryDownsample > 1 {
+		return c.HistoryDownsample
+	}
+	return DefaultHistoryDownsample
+}
+
+// HistoryLength returns the number of prices kept once the history is full,
+// across its tiers
+func (c *Config) HistoryLength() int {
+	return c.History() * (1 + max(c.HistoryTiers, 0))
+}
+
+// HistorySpan returns the number of candles the full history covers
+func (c *Config) HistorySpan() int {
+	span, resolution := 0, 1
+	for range 1 + max(c.HistoryTiers, 0) {
+		span += c.History() * resolution
+		resolution *= c.Downsample()
+	}
+	return span
+}
+
+// ForSymbol returns a copy of the config for symbol, with the update
+// interval, candle interval and history size set for it, if any
+func (c *Config) ForSymbol(symbol string) *Config {
+	cfg := *c
+	cfg.Symbol = symbol
+	if interval, ok := c.SymbolUpdateIntervals[symbol]; ok {
+		cfg.UpdateInterval = interval
+	}
+	if interval, ok := c.SymbolCandleIntervals[symbol]; ok {
+		cfg.CandleInterval = interval
+	}
+	if size, ok := c.SymbolHistorySizes[symbol]; ok {
+		cfg.HistorySize = size
+	}
+	return &cfg
+}
+
+// loadMarketDataEnv loads the update cadence, candle interval and history
+// size, overall and per symbol
+func loadMarketDataEnv(cfg *Config) {
+	if value := os.Getenv("STRATEGY_CANDLE_INTERVAL"); value != "" {
+		if interval, ok := ParseCandleInterval(value); ok {
+			cfg.CandleInterval = interval
+		}
+	}
+	if val := parseIntEnv("STRATEGY_HISTORY_SIZE", cfg.HistorySize); val > 0 {
+		cfg.HistorySize = val
+	}
+	cfg.HistoryTiers = parseIntEnv("STRATEGY_HISTORY_TIERS", cfg.HistoryTiers)
+	cfg.HistoryDownsample = parseIntEnv("STRATEGY_HISTORY_DOWNSAMPLE", cfg.HistoryDownsample)
+	if value := os.Getenv("STRATEGY_SYMBOL_UPDATE_INTERVALS"); value != "" {
+		cfg.SymbolUpdateIntervals = make(map[string]time.Duration)
+		for symbol, entry := range parseSymbolMap(value) {
+			if interval, err := time.ParseDuration(entry); err == nil && interval > 0 {
+				cfg.SymbolUpdateIntervals[symbol] = interval
+			}
+		}
+	}
+	if value := os.Getenv("STRATEGY_SYMBOL_CANDLE_INTERVALS"); value != "" {
+		cfg.SymbolCandleIntervals = make(map[string]time.Duration)
+		for symbol, entry := range parseSymbolMap(value) {
+			if interval, ok := ParseCandleInterval(entry); ok {
+				cfg.SymbolCandleIntervals[symbol] = interval
+			}
+		}
+	}
+	if value := os.Getenv("STRATEGY_SYMBOL_HISTORY_SIZES"); value != "" {
+		cfg.SymbolHistorySizes = make(map[string]int)
+		for symbol, entry := range parseSymbolMap(value) {
+			if size, err := strconv.Atoi(entry); err == nil && size > 0 {
+				cfg.SymbolHistorySizes[symbol] = size
+			}
+		}
+	}
+}
+
+// validateMarketData checks that the update interval is positive, that the
+// candle interval is served by every exchange and that the history holds
+// the long EMA, for the config and each symbol overriding it, and that the
+// history tiers downsample
+func (c *Config) validateMarketData() []error {
+	var errs []error
+	check := func(cfg *Config, label string) {
+		if cfg.UpdateInterval <= 0 {
+			errs = append(errs, fmt.Errorf("%supdate interval must be positive, got %s", label, cfg.UpdateInterval))
+		}
+		if candleIntervalName(cfg.Candles()) == "" {
+			errs = append(errs, fmt.Errorf("%scandle interval must be 1m, 5m, 15m, 1h or 1d, got %s", label, cfg.Candles()))
+		}
+		if cfg.HistoryLength() < cfg.LongEMAPeriod {
+			errs = append(errs, fmt.Errorf("%shistory of %d prices cannot hold the long EMA of %d", label, cfg.HistoryLength(), cfg.LongEMAPeriod))
+		}
+	}
+
+	check(c, "")
+	if c.HistoryTiers < 0 {
+		errs = append(errs, fmt.Errorf("history tiers must not be negative, got %d", c.HistoryTiers))
+	}
+	if c.HistoryTiers > 0 && c.HistoryDownsample < 2 {
+		errs = append(errs, fmt.Errorf("history downsample must be at least 2, got %d", c.HistoryDownsample))
+	}
+	symbols := make(map[string]bool)
+	for symbol := range c.SymbolUpdateIntervals {
+		symbols[symbol] = true
+	}
+	for symbol := range c.SymbolCandleIntervals {
+		symbols[symbol] = true
+	}
+	for symbol := range c.SymbolHistorySizes {
+		symbols[symbol] = true
+	}
+	for symbol := range symbols {
+		check(c.ForSymbol(symbol), symbol+": ")
+	}
+	return errs
+}
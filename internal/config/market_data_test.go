@@ -0,0 +1,72 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultConfig_PerSymbolMarketData(t *testing.T) {
+	t.Setenv("STRATEGY_CANDLE_INTERVAL", "5m")
+	t.Setenv("STRATEGY_HISTORY_SIZE", "150")
+	t.Setenv("STRATEGY_SYMBOL_UPDATE_INTERVALS", "PEPE-USD=30s,BAD-USD=soon")
+	t.Setenv("STRATEGY_SYMBOL_CANDLE_INTERVALS", "PEPE-USD=15m,BAD-USD=7m")
+	t.Setenv("STRATEGY_SYMBOL_HISTORY_SIZES", "PEPE-USD=300")
+
+	cfg := DefaultConfig()
+	if cfg.Candles() != 5*time.Minute || cfg.History() != 150 {
+		t.Fatalf("expected 5m candles and 150 prices, got %s and %d", cfg.Candles(), cfg.History())
+	}
+
+	btc := cfg.ForSymbol("BTC-USD")
+	if btc.Symbol != "BTC-USD" || btc.UpdateInterval != cfg.UpdateInterval || btc.CandleIntervalName() != "5m" || btc.History() != 150 {
+		t.Errorf("symbols without overrides should keep the defaults: %+v", btc)
+	}
+	pepe := cfg.ForSymbol("PEPE-USD")
+	if pepe.UpdateInterval != 30*time.Second || pepe.CandleIntervalName() != "15m" || pepe.History() != 300 {
+		t.Errorf("overrides should apply: %s %s %d", pepe.UpdateInterval, pepe.CandleIntervalName(), pepe.History())
+	}
+	if _, ok := cfg.SymbolCandleIntervals["BAD-USD"]; ok {
+		t.Error("unsupported candle intervals should be skipped")
+	}
+	if cfg.Symbol == "PEPE-USD" || cfg.HistorySize != 150 {
+		t.Error("ForSymbol should not modify the base config")
+	}
+}
+
+func TestValidate_MarketData(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CandleInterval = 2 * time.Minute
+	cfg.SymbolHistorySizes = map[string]int{"ETH-USD": 10}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected an unsupported interval and a short history to be rejected")
+	}
+	for _, want := range []string{"candle interval must be", "ETH-USD: history of 10 prices"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+}
+
+func TestDefaultConfig_DownsampledHistory(t *testing.T) {
+	t.Setenv("STRATEGY_HISTORY_TIERS", "2")
+	t.Setenv("STRATEGY_HISTORY_DOWNSAMPLE", "4")
+
+	cfg := DefaultConfig()
+	if cfg.HistoryLength() != 300 || cfg.HistorySpan() != 100+400+1600 {
+		t.Errorf("expected 300 prices covering 2100 candles, got %d covering %d", cfg.HistoryLength(), cfg.HistorySpan())
+	}
+
+	// A long EMA beyond the full resolution tier fits in the downsampled ones
+	cfg.LongEMAPeriod = 250
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected the long EMA to fit the tiers, got %v", err)
+	}
+
+	cfg.HistoryDownsample = 1
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "history downsample") {
+		t.Errorf("expected a downsample below 2 to be rejected, got %v", err)
+	}
+}
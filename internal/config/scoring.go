@@ -0,0 +1,189 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// weightTolerance is how far from 1 a set of weights may sum
+const weightTolerance = 1e-6
+
+// QualityScoring weighs the market quality score used to pick symbols from
+// the dYdX markets
+type QualityScoring struct {
+	VolumeWeight     float64 `json:"volume_weight"`     // Default: 0.35
+	LiquidityWeight  float64 `json:"liquidity_weight"`  // Default: 0.35
+	VolatilityWeight float64 `json:"volatility_weight"` // Default: 0.30, volatility is penalized
+	MinQuality       float64 `json:"min_quality"`       // Markets scoring below are skipped (default: 0.70)
+	// MinVolumeUSD is the 24h volume below which a market gets the lowest
+	// volume score (default: 100K)
+	MinVolumeUSD float64 `json:"min_volume_usd"`
+}
+
+// SelectionScoring weighs the opportunity score the symbol selector ranks
+// symbols by
+type SelectionScoring struct {
+	GainWeight   float64 `json:"gain_weight"`   // Default: 0.40
+	SharpeWeight float64 `json:"sharpe_weight"` // Default: 0.35
+	VolumeWeight float64 `json:"volume_weight"` // Default: 0.15
+	RiskWeight   float64 `json:"risk_weight"`   // Subtracted from the score (default: 0.10)
+	// The risk assessment blends volatility, drawdown and thin volume
+	RiskVolatilityWeight float64 `json:"risk_volatility_weight"` // Default: 0.4
+	RiskDrawdownWeight   float64 `json:"risk_drawdown_weight"`   // Default: 0.4
+	RiskVolumeWeight     float64 `json:"risk_volume_weight"`     // Default: 0.2
+	// Average candle volumes at or below VolumeLow score 0, at or above
+	// VolumeHigh score 1 (defaults: 100 and 1000)
+	VolumeLow  float64 `json:"volume_low"`
+	VolumeHigh float64 `json:"volume_high"`
+	// Threshold is the score above which every symbol is selected when all
+	// of them reach it (default: 0.6)
+	Threshold    float64 `json:"threshold"`
+	RiskFreeRate float64 `json:"risk_free_rate"` // Default: 0.01
+	// EMAs the gain potential is measured with (defaults: 9 and 21)
+	ShortEMA int `json:"short_ema"`
+	LongEMA  int `json:"long_ema"`
+	// Lookback is the number of candles fetched per symbol (default: 30), and
+	// MinHistory the number below which a symbol is not scored (default: 20)
+	Lookback   int `json:"lookback"`
+	MinHistory int `json:"min_history"`
+}
+
+// ScoringConfig holds the market quality and symbol selection scoring
+type ScoringConfig struct {
+	Quality   QualityScoring   `json:"quality"`
+	Selection SelectionScoring `json:"selection"`
+}
+
+// DefaultScoring returns the default scoring configuration
+func DefaultScoring() ScoringConfig {
+	return ScoringConfig{
+		Quality: QualityScoring{
+			VolumeWeight:     0.35,
+			LiquidityWeight:  0.35,
+			VolatilityWeight: 0.30,
+			MinQuality:       0.70,
+			MinVolumeUSD:     100000,
+		},
+		Selection: SelectionScoring{
+			GainWeight:           0.40,
+			SharpeWeight:         0.35,
+			VolumeWeight:         0.15,
+			RiskWeight:           0.10,
+			RiskVolatilityWeight: 0.4,
+			RiskDrawdownWeight:   0.4,
+			RiskVolumeWeight:     0.2,
+			VolumeLow:            100,
+			VolumeHigh:           1000,
+			Threshold:            0.6,
+			RiskFreeRate:         0.01,
+			ShortEMA:             9,
+			LongEMA:              21,
+			Lookback:             30,
+			MinHistory:           20,
+		},
+	}
+}
+
+// LoadScoring loads the scoring configuration: the defaults, overridden by
+// the JSON file in SCORING_CONFIG_FILE when set, then by environment
+// variables
+func LoadScoring() (ScoringConfig, error) {
+	cfg := DefaultScoring()
+
+	if path := os.Getenv("SCORING_CONFIG_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("failed to read scoring config: %w", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("failed to parse scoring config %s: %w", path, err)
+		}
+	}
+
+	q := &cfg.Quality
+	q.VolumeWeight = parseFloatEnv("SCORING_QUALITY_VOLUME_WEIGHT", q.VolumeWeight)
+	q.LiquidityWeight = parseFloatEnv("SCORING_QUALITY_LIQUIDITY_WEIGHT", q.LiquidityWeight)
+	q.VolatilityWeight = parseFloatEnv("SCORING_QUALITY_VOLATILITY_WEIGHT", q.VolatilityWeight)
+	q.MinQuality = parseFloatEnv("AUTO_SELECT_MIN_QUALITY", q.MinQuality)
+	q.MinVolumeUSD = parseFloatEnv("SCORING_QUALITY_MIN_VOLUME", q.MinVolumeUSD)
+
+	s := &cfg.Selection
+	s.GainWeight = parseFloatEnv("SCORING_SELECTION_GAIN_WEIGHT", s.GainWeight)
+	s.SharpeWeight = parseFloatEnv("SCORING_SELECTION_SHARPE_WEIGHT", s.SharpeWeight)
+	s.VolumeWeight = parseFloatEnv("SCORING_SELECTION_VOLUME_WEIGHT", s.VolumeWeight)
+	s.RiskWeight = parseFloatEnv("SCORING_SELECTION_RISK_WEIGHT", s.RiskWeight)
+	s.RiskVolatilityWeight = parseFloatEnv("SCORING_RISK_VOLATILITY_WEIGHT", s.RiskVolatilityWeight)
+	s.RiskDrawdownWeight = parseFloatEnv("SCORING_RISK_DRAWDOWN_WEIGHT", s.RiskDrawdownWeight)
+	s.RiskVolumeWeight = parseFloatEnv("SCORING_RISK_VOLUME_WEIGHT", s.RiskVolumeWeight)
+	s.VolumeLow = parseFloatEnv("SCORING_SELECTION_VOLUME_LOW", s.VolumeLow)
+	s.VolumeHigh = parseFloatEnv("SCORING_SELECTION_VOLUME_HIGH", s.VolumeHigh)
+	s.Threshold = parseFloatEnv("SCORING_SELECTION_THRESHOLD", s.Threshold)
+	s.RiskFreeRate = parseFloatEnv("SCORING_SELECTION_RISK_FREE_RATE", s.RiskFreeRate)
+	s.ShortEMA = parseIntEnv("SCORING_SELECTION_SHORT_EMA", s.ShortEMA)
+	s.LongEMA = parseIntEnv("SCORING_SELECTION_LONG_EMA", s.LongEMA)
+	s.Lookback = parseIntEnv("SCORING_SELECTION_LOOKBACK", s.Lookback)
+	s.MinHistory = parseIntEnv("SCORING_SELECTION_MIN_HISTORY", s.MinHistory)
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid scoring config: %w", err)
+	}
+	return cfg, nil
+}
+
+// Validate checks the quality and selection scoring
+func (c ScoringConfig) Validate() error {
+	return errors.Join(c.Quality.Validate(), c.Selection.Validate())
+}
+
+// Validate checks that the weights are not negative and sum to 1 and that the
+// thresholds are within range
+func (q QualityScoring) Validate() error {
+	switch {
+	case q.VolumeWeight < 0, q.LiquidityWeight < 0, q.VolatilityWeight < 0:
+		return fmt.Errorf("quality: weights must not be negative")
+	case !sumsToOne(q.VolumeWeight, q.LiquidityWeight, q.VolatilityWeight):
+		return fmt.Errorf("quality: weights must sum to 1, got %g", q.VolumeWeight+q.LiquidityWeight+q.VolatilityWeight)
+	case q.MinQuality < 0 || q.MinQuality > 1:
+		return fmt.Errorf("quality: minimum quality must be within [0, 1], got %g", q.MinQuality)
+	case q.MinVolumeUSD <= 0:
+		return fmt.Errorf("quality: minimum volume must be positive")
+	}
+	return nil
+}
+
+// Validate checks that the weights are not negative and sum to 1, and that
+// the thresholds and windows are consistent
+func (s SelectionScoring) Validate() error {
+	switch {
+	case s.GainWeight < 0, s.SharpeWeight < 0, s.VolumeWeight < 0, s.RiskWeight < 0,
+		s.RiskVolatilityWeight < 0, s.RiskDrawdownWeight < 0, s.RiskVolumeWeight < 0:
+		return fmt.Errorf("selection: weights must not be negative")
+	case !sumsToOne(s.GainWeight, s.SharpeWeight, s.VolumeWeight, s.RiskWeight):
+		return fmt.Errorf("selection: score weights must sum to 1, got %g", s.GainWeight+s.SharpeWeight+s.VolumeWeight+s.RiskWeight)
+	case !sumsToOne(s.RiskVolatilityWeight, s.RiskDrawdownWeight, s.RiskVolumeWeight):
+		return fmt.Errorf("selection: risk weights must sum to 1, got %g", s.RiskVolatilityWeight+s.RiskDrawdownWeight+s.RiskVolumeWeight)
+	case s.VolumeLow < 0 || s.VolumeHigh <= s.VolumeLow:
+		return fmt.Errorf("selection: volume bounds must satisfy 0 <= low < high, got %g and %g", s.VolumeLow, s.VolumeHigh)
+	case s.Threshold < 0 || s.Threshold > 1:
+		return fmt.Errorf("selection: threshold must be within [0, 1], got %g", s.Threshold)
+	case s.ShortEMA < 1 || s.LongEMA <= s.ShortEMA:
+		return fmt.Errorf("selection: EMAs must satisfy 1 <= short < long, got %d and %d", s.ShortEMA, s.LongEMA)
+	case s.MinHistory < 2:
+		return fmt.Errorf("selection: minimum history must be at least 2 candles, got %d", s.MinHistory)
+	case s.Lookback < s.MinHistory || s.Lookback < s.LongEMA:
+		return fmt.Errorf("selection: lookback of %d candles is shorter than the minimum history or the long EMA", s.Lookback)
+	}
+	return nil
+}
+
+// sumsToOne reports whether weights sum to 1
+func sumsToOne(weights ...float64) bool {
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	return math.Abs(sum-1) <= weightTolerance
+}
@@ -0,0 +1,138 @@
+// Package deadman implements a deadman switch: unless an operator shows a
+// sign of life within the timeout, the bot flattens its positions and stops
+// entering new ones, so a failure nobody is watching cannot keep trading.
+package deadman
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SourceStart is the heartbeat the switch is armed with when created
+const SourceStart = "start"
+
+// Status is the state of the switch
+type Status struct {
+	LastHeartbeat time.Time `json:"last_heartbeat"`
+	Source        string    `json:"source"` // Where the last heartbeat came from
+	Deadline      time.Time `json:"deadline"`
+	Tripped       bool      `json:"tripped"`
+	TrippedAt     time.Time `json:"tripped_at"` // Zero while armed
+}
+
+// Switch trips when no heartbeat is received within its timeout, and is
+// re-armed by the next heartbeat
+type Switch struct {
+	timeout time.Duration
+
+	mu        sync.Mutex
+	last      time.Time
+	source    string
+	trippedAt time.Time
+	onChange  func(Status)
+	now       func() time.Time
+}
+
+// New creates a switch armed now, tripping after timeout without heartbeat
+func New(timeout time.Duration) *Switch {
+	s := &Switch{timeout: timeout, source: SourceStart, now: time.Now}
+	s.last = s.now()
+	return s
+}
+
+// Timeout returns how long the switch waits for a heartbeat
+func (s *Switch) Timeout() time.Duration {
+	return s.timeout
+}
+
+// SetChangeCallback sets the callback notified when the switch trips and
+// when a heartbeat re-arms it
+func (s *Switch) SetChangeCallback(callback func(Status)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = callback
+}
+
+// Heartbeat records a sign of life from the operator through source, such as
+// "api" or "tui", re-arming the switch if it had tripped
+func (s *Switch) Heartbeat(source string) {
+	s.mu.Lock()
+	s.last, s.source = s.now(), source
+	rearmed := !s.trippedAt.IsZero()
+	s.trippedAt = time.Time{}
+	status, callback := s.status(), s.onChange
+	s.mu.Unlock()
+
+	if rearmed && callback != nil {
+		callback(status)
+	}
+}
+
+// Check trips the switch once its deadline has passed and reports whether
+// it is tripped
+func (s *Switch) Check() bool {
+	s.mu.Lock()
+	if !s.trippedAt.IsZero() {
+		s.mu.Unlock()
+		return true
+	}
+	now := s.now()
+	if now.Before(s.last.Add(s.timeout)) {
+		s.mu.Unlock()
+		return false
+	}
+	s.trippedAt = now
+	status, callback := s.status(), s.onChange
+	s.mu.Unlock()
+
+	if callback != nil {
+		callback(status)
+	}
+	return true
+}
+
+// Absent reports whether the operator has been away past the timeout, and
+// why, so new entries can be refused
+func (s *Switch) Absent() (string, bool) {
+	if !s.Check() {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("deadman switch tripped: no operator heartbeat since %s", s.last.Format(time.RFC3339)), true
+}
+
+// Status returns the state of the switch
+func (s *Switch) Status() Status {
+	s.Check()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status()
+}
+
+// status returns the state of the switch. Must be called with the lock held.
+func (s *Switch) status() Status {
+	return Status{
+		LastHeartbeat: s.last,
+		Source:        s.source,
+		Deadline:      s.last.Add(s.timeout),
+		Tripped:       !s.trippedAt.IsZero(),
+		TrippedAt:     s.trippedAt,
+	}
+}
+
+// Run checks the switch every interval until ctx is done
+func (s *Switch) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Check()
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package deadman
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSwitch(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	s := New(2 * time.Hour)
+	s.now = func() time.Time { return now }
+	s.last = now
+
+	var changes []Status
+	s.SetChangeCallback(func(status Status) { changes = append(changes, status) })
+
+	now = now.Add(90 * time.Minute)
+	if _, absent := s.Absent(); absent {
+		t.Fatal("expected the switch armed before the deadline")
+	}
+	s.Heartbeat("tui")
+
+	now = now.Add(119 * time.Minute)
+	if s.Check() {
+		t.Fatal("expected a heartbeat to push the deadline back")
+	}
+	now = now.Add(time.Minute)
+	reason, absent := s.Absent()
+	if !absent || !strings.Contains(reason, "deadman") {
+		t.Fatalf("expected the switch to trip at the deadline, got %q", reason)
+	}
+	s.Check()
+	if len(changes) != 1 || !changes[0].Tripped || changes[0].Source != "tui" {
+		t.Fatalf("expected a single trip notification, got %+v", changes)
+	}
+
+	s.Heartbeat("api")
+	status := s.Status()
+	if status.Tripped || !status.TrippedAt.IsZero() || !status.Deadline.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("expected a heartbeat to re-arm the switch, got %+v", status)
+	}
+	if len(changes) != 2 || changes[1].Tripped {
+		t.Errorf("expected the re-arm to be notified, got %+v", changes)
+	}
+}
@@ -0,0 +1,421 @@
+// Package degradation watches the live performance of each strategy and
+// symbol against what its backtest led to expect, and throttles or disables
+// the entries of those that degrade beyond configurable bands.
+package degradation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/ringbuf"
+)
+
+// keptChanges is the number of recent state changes kept for display
+const keptChanges = 50
+
+// State is how a strategy or symbol is allowed to trade
+type State string
+
+const (
+	StateHealthy   State = "healthy"
+	StateThrottled State = "throttled" // Entries sized down
+	StateDisabled  State = "disabled"  // No new entries until reset
+)
+
+// Expectation is the performance a backtest measured for a strategy, on one
+// symbol or on all of them when Symbol is empty
+type Expectation struct {
+	Strategy string `json:"strategy"`
+	Symbol   string `json:"symbol,omitempty"`
+	// WinRate is the percentage of winning trades
+	WinRate float64 `json:"win_rate"`
+	// ExpectancyPct is the average PnL per trade in percent of the capital
+	ExpectancyPct float64 `json:"expectancy_pct"`
+	// MaxDrawdownPct is the deepest drop of the equity in percent
+	MaxDrawdownPct float64 `json:"max_drawdown_pct"`
+}
+
+// LoadExpectations reads a JSON array of expectations from path
+func LoadExpectations(path string) ([]Expectation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expectations: %w", err)
+	}
+	var expectations []Expectation
+	if err := json.Unmarshal(data, &expectations); err != nil {
+		return nil, fmt.Errorf("failed to parse expectations %s: %w", path, err)
+	}
+	for i, expectation := range expectations {
+		if expectation.Strategy == "" {
+			return nil, fmt.Errorf("expectation %d has no strategy", i+1)
+		}
+	}
+	return expectations, nil
+}
+
+// WriteExpectations writes expectations to path as a JSON array
+func WriteExpectations(path string, expectations []Expectation) error {
+	data, err := json.MarshalIndent(expectations, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Config holds the rolling window and the bands beyond which a strategy is
+// throttled or disabled. A band of 0 disables its check.
+type Config struct {
+	Window    int // Latest trades the metrics are computed over
+	MinTrades int // Trades needed before a strategy or symbol is judged
+
+	// Percentage points the win rate may fall below the expectation
+	WinRateThrottleBand float64
+	WinRateDisableBand  float64
+	// Percentage points of capital the expectancy may fall below the
+	// expectation
+	ExpectancyThrottleBand float64
+	ExpectancyDisableBand  float64
+	// Multiples of the expected maximum drawdown the rolling drawdown may
+	// reach
+	DrawdownThrottleRatio float64
+	DrawdownDisableRatio  float64
+
+	// ThrottleFactor scales the size of the entries of throttled strategies
+	ThrottleFactor float64
+}
+
+// DefaultConfig returns the default degradation bands
+func DefaultConfig() Config {
+	return Config{
+		Window:                 30,
+		MinTrades:              10,
+		WinRateThrottleBand:    10,
+		WinRateDisableBand:     20,
+		ExpectancyThrottleBand: 0.1,
+		ExpectancyDisableBand:  0.25,
+		DrawdownThrottleRatio:  1.5,
+		DrawdownDisableRatio:   2,
+		ThrottleFactor:         0.5,
+	}
+}
+
+// Metrics is the rolling live performance of a strategy or symbol
+type Metrics struct {
+	Trades        int     `json:"trades"`
+	WinRate       float64 `json:"win_rate"`
+	ExpectancyPct float64 `json:"expectancy_pct"`
+	DrawdownPct   float64 `json:"drawdown_pct"`
+}
+
+// Status is the state of a strategy, or of one of its symbols, and the
+// metrics it was judged on
+type Status struct {
+	Strategy    string      `json:"strategy"`
+	Symbol      string      `json:"symbol,omitempty"`
+	State       State       `json:"state"`
+	Reason      string      `json:"reason,omitempty"`
+	Metrics     Metrics     `json:"metrics"`
+	Expectation Expectation `json:"expectation"`
+	Since       time.Time   `json:"since"`
+}
+
+// Change is a transition of a strategy or symbol between states
+type Change struct {
+	Time     time.Time `json:"time"`
+	Strategy string    `json:"strategy"`
+	Symbol   string    `json:"symbol,omitempty"`
+	From     State     `json:"from"`
+	To       State     `json:"to"`
+	Reason   string    `json:"reason"`
+}
+
+// scope is the trades and state of a strategy, or of one of its symbols
+type scope struct {
+	strategy string
+	symbol   string
+	returns  *ringbuf.Buffer[float64] // PnL of the latest trades in percent of capital
+	state    State
+	reason   string
+	metrics  Metrics
+	since    time.Time
+}
+
+// Monitor tracks the trades of each strategy and of each of its symbols over
+// a rolling window, and compares them with the expectations of the strategy.
+// A scope without an expectation is tracked but never throttled.
+type Monitor struct {
+	config       Config
+	expectations map[string]Expectation // By strategy, or strategy and symbol
+
+	mu       sync.Mutex
+	scopes   map[string]*scope
+	changes  *ringbuf.Buffer[Change]
+	onChange func(Change)
+	now      func() time.Time
+}
+
+// NewMonitor creates a monitor comparing live trades with expectations
+func NewMonitor(config Config, expectations []Expectation) *Monitor {
+	if config.Window < 1 {
+		config.Window = 1
+	}
+	byKey := make(map[string]Expectation, len(expectations))
+	for _, expectation := range expectations {
+		byKey[scopeKey(expectation.Strategy, expectation.Symbol)] = expectation
+	}
+	return &Monitor{
+		config:       config,
+		expectations: byKey,
+		scopes:       make(map[string]*scope),
+		changes:      ringbuf.New[Change](keptChanges),
+		now:          time.Now,
+	}
+}
+
+func scopeKey(strategy, symbol string) string {
+	return strategy + "|" + symbol
+}
+
+// SetChangeCallback sets the callback notified of every state change
+func (m *Monitor) SetChangeCallback(callback func(Change)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onChange = callback
+}
+
+// expectation returns the expectation of a scope: the symbol's own, or the
+// strategy's for every symbol
+func (m *Monitor) expectation(strategy, symbol string) (Expectation, bool) {
+	if expectation, ok := m.expectations[scopeKey(strategy, symbol)]; ok {
+		return expectation, true
+	}
+	expectation, ok := m.expectations[scopeKey(strategy, "")]
+	return expectation, ok
+}
+
+// Record adds a closed trade of strategy on symbol, its PnL in percent of
+// the capital it was sized from, and re-evaluates the strategy and symbol
+func (m *Monitor) Record(strategy, symbol string, returnPct float64) {
+	var changes []Change
+	m.mu.Lock()
+	for _, scopeSymbol := range []string{"", symbol} {
+		key := scopeKey(strategy, scopeSymbol)
+		s, ok := m.scopes[key]
+		if !ok {
+			s = &scope{
+				strategy: strategy,
+				symbol:   scopeSymbol,
+				returns:  ringbuf.New[float64](m.config.Window),
+				state:    StateHealthy,
+				since:    m.now(),
+			}
+			m.scopes[key] = s
+		}
+		s.returns.Push(returnPct)
+		if change, ok := m.evaluate(s); ok {
+			changes = append(changes, change)
+		}
+	}
+	callback := m.onChange
+	m.mu.Unlock()
+
+	for _, change := range changes {
+		m.notify(change, callback)
+	}
+}
+
+// evaluate updates the metrics and state of s, returning the change made.
+// A disabled scope stays disabled until reset. Must be called with the lock
+// held.
+func (m *Monitor) evaluate(s *scope) (Change, bool) {
+	s.metrics = rollingMetrics(s.returns.Values())
+	expectation, ok := m.expectation(s.strategy, s.symbol)
+	if !ok || s.metrics.Trades < m.config.MinTrades || s.state == StateDisabled {
+		return Change{}, false
+	}
+
+	state, reason := m.judge(s.metrics, expectation)
+	if state == s.state {
+		s.reason = reason
+		return Change{}, false
+	}
+	change := Change{
+		Time:     m.now(),
+		Strategy: s.strategy,
+		Symbol:   s.symbol,
+		From:     s.state,
+		To:       state,
+		Reason:   reason,
+	}
+	s.state, s.reason, s.since = state, reason, change.Time
+	m.changes.Push(change)
+	return change, true
+}
+
+// judge returns the state metrics call for against the expectation, and why
+func (m *Monitor) judge(metrics Metrics, expectation Expectation) (State, string) {
+	c := m.config
+	shortfall := expectation.WinRate - metrics.WinRate
+	expectancyShortfall := expectation.ExpectancyPct - metrics.ExpectancyPct
+	drawdownRatio := 0.0
+	if expectation.MaxDrawdownPct > 0 {
+		drawdownRatio = metrics.DrawdownPct / expectation.MaxDrawdownPct
+	}
+
+	switch {
+	case c.WinRateDisableBand > 0 && shortfall > c.WinRateDisableBand:
+		return StateDisabled, fmt.Sprintf("win rate %.1f%% vs %.1f%% expected", metrics.WinRate, expectation.WinRate)
+	case c.ExpectancyDisableBand > 0 && expectancyShortfall > c.ExpectancyDisableBand:
+		return StateDisabled, fmt.Sprintf("expectancy %.3f%% vs %.3f%% expected", metrics.ExpectancyPct, expectation.ExpectancyPct)
+	case c.DrawdownDisableRatio > 0 && drawdownRatio > c.DrawdownDisableRatio:
+		return StateDisabled, fmt.Sprintf("drawdown %.2f%% vs %.2f%% expected", metrics.DrawdownPct, expectation.MaxDrawdownPct)
+	case c.WinRateThrottleBand > 0 && shortfall > c.WinRateThrottleBand:
+		return StateThrottled, fmt.Sprintf("win rate %.1f%% vs %.1f%% expected", metrics.WinRate, expectation.WinRate)
+	case c.ExpectancyThrottleBand > 0 && expectancyShortfall > c.ExpectancyThrottleBand:
+		return StateThrottled, fmt.Sprintf("expectancy %.3f%% vs %.3f%% expected", metrics.ExpectancyPct, expectation.ExpectancyPct)
+	case c.DrawdownThrottleRatio > 0 && drawdownRatio > c.DrawdownThrottleRatio:
+		return StateThrottled, fmt.Sprintf("drawdown %.2f%% vs %.2f%% expected", metrics.DrawdownPct, expectation.MaxDrawdownPct)
+	}
+	return StateHealthy, ""
+}
+
+// rollingMetrics computes the metrics of trade returns in time order
+func rollingMetrics(returns []float64) Metrics {
+	metrics := Metrics{Trades: len(returns)}
+	if len(returns) == 0 {
+		return metrics
+	}
+	wins, total, cumulative, peak := 0, 0.0, 0.0, 0.0
+	for _, r := range returns {
+		if r > 0 {
+			wins++
+		}
+		total += r
+		cumulative += r
+		peak = math.Max(peak, cumulative)
+		metrics.DrawdownPct = math.Max(metrics.DrawdownPct, peak-cumulative)
+	}
+	metrics.WinRate = float64(wins) / float64(len(returns)) * 100
+	metrics.ExpectancyPct = total / float64(len(returns))
+	return metrics
+}
+
+// notify logs a change and passes it to the callback
+func (m *Monitor) notify(change Change, callback func(Change)) {
+	log := logger.Component("degradation")
+	switch change.To {
+	case StateHealthy:
+		log.Info("strategy performance recovered", "strategy", change.Strategy, "symbol", change.Symbol)
+	case StateThrottled:
+		log.Warn("strategy performance degraded, entries throttled",
+			"strategy", change.Strategy, "symbol", change.Symbol,
+			"reason", change.Reason, "size_factor", m.config.ThrottleFactor)
+	case StateDisabled:
+		log.Error("strategy performance degraded, entries disabled",
+			"strategy", change.Strategy, "symbol", change.Symbol, "reason", change.Reason)
+	}
+	if callback != nil {
+		callback(change)
+	}
+}
+
+// SizeFactor returns the factor entries of strategy on symbol are sized by:
+// 1 when healthy, the throttle factor when the strategy or the symbol is
+// throttled and 0 when either is disabled, with the reason
+func (m *Monitor) SizeFactor(strategy, symbol string) (float64, string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	factor, reason := 1.0, ""
+	for _, key := range []string{scopeKey(strategy, ""), scopeKey(strategy, symbol)} {
+		s, ok := m.scopes[key]
+		if !ok {
+			continue
+		}
+		switch s.state {
+		case StateDisabled:
+			return 0, describe(s) + " disabled: " + s.reason
+		case StateThrottled:
+			if m.config.ThrottleFactor < factor {
+				factor, reason = m.config.ThrottleFactor, describe(s)+" throttled: "+s.reason
+			}
+		}
+	}
+	return factor, reason
+}
+
+// describe names the strategy and symbol of a scope
+func describe(s *scope) string {
+	if s.symbol == "" {
+		return "strategy " + s.strategy
+	}
+	return "strategy " + s.strategy + " on " + s.symbol
+}
+
+// Reset re-enables a strategy, or one of its symbols, and forgets its
+// trades so it is judged afresh. It returns false when nothing was tracked.
+func (m *Monitor) Reset(strategy, symbol string) bool {
+	m.mu.Lock()
+	s, ok := m.scopes[scopeKey(strategy, symbol)]
+	if !ok {
+		m.mu.Unlock()
+		return false
+	}
+	var change Change
+	changed := s.state != StateHealthy
+	if changed {
+		change = Change{Time: m.now(), Strategy: strategy, Symbol: symbol, From: s.state, To: StateHealthy, Reason: "reset"}
+		m.changes.Push(change)
+	}
+	s.returns.Reset()
+	s.metrics = Metrics{}
+	s.state, s.reason, s.since = StateHealthy, "", m.now()
+	callback := m.onChange
+	m.mu.Unlock()
+
+	if changed {
+		m.notify(change, callback)
+	}
+	return true
+}
+
+// Statuses returns the status of every tracked strategy and symbol, ordered
+// by strategy then symbol
+func (m *Monitor) Statuses() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]Status, 0, len(m.scopes))
+	for _, s := range m.scopes {
+		expectation, _ := m.expectation(s.strategy, s.symbol)
+		statuses = append(statuses, Status{
+			Strategy:    s.strategy,
+			Symbol:      s.symbol,
+			State:       s.state,
+			Reason:      s.reason,
+			Metrics:     s.metrics,
+			Expectation: expectation,
+			Since:       s.since,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Strategy != statuses[j].Strategy {
+			return statuses[i].Strategy < statuses[j].Strategy
+		}
+		return statuses[i].Symbol < statuses[j].Symbol
+	})
+	return statuses
+}
+
+// Changes returns the latest state changes, oldest first
+func (m *Monitor) Changes() []Change {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.changes.Values()
+}
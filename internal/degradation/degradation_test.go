@@ -0,0 +1,132 @@
+package degradation
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.Window = 10
+	config.MinTrades = 4
+	return config
+}
+
+var expected = Expectation{Strategy: "main", WinRate: 60, ExpectancyPct: 0.2, MaxDrawdownPct: 1}
+
+func TestMonitor_Throttle(t *testing.T) {
+	monitor := NewMonitor(testConfig(), []Expectation{expected})
+	var changes []Change
+	monitor.SetChangeCallback(func(change Change) { changes = append(changes, change) })
+
+	// Half the trades win with the expected expectancy: the win rate is 10
+	// points short, within the throttle band only after enough trades
+	for _, r := range []float64{0.6, -0.2, 0.6, -0.2} {
+		if factor, _ := monitor.SizeFactor("main", "BTC-USD"); factor != 1 {
+			t.Fatalf("expected full size before %d trades, got %v", testConfig().MinTrades, factor)
+		}
+		monitor.Record("main", "BTC-USD", r)
+	}
+	monitor.Record("main", "BTC-USD", -0.1)
+
+	factor, reason := monitor.SizeFactor("main", "BTC-USD")
+	if factor != 0.5 || !strings.Contains(reason, "win rate") {
+		t.Fatalf("expected entries throttled on the win rate, got %v (%s)", factor, reason)
+	}
+	// The strategy scope and the symbol scope both changed
+	if len(changes) != 2 || changes[0].To != StateThrottled || changes[1].Symbol != "BTC-USD" {
+		t.Fatalf("expected the strategy and symbol throttled, got %+v", changes)
+	}
+	if factor, _ := monitor.SizeFactor("other", "BTC-USD"); factor != 1 {
+		t.Error("strategies without trades should trade at full size")
+	}
+
+	// Winning trades bring the metrics back within the bands
+	for i := 0; i < 6; i++ {
+		monitor.Record("main", "BTC-USD", 0.5)
+	}
+	if factor, _ := monitor.SizeFactor("main", "BTC-USD"); factor != 1 {
+		t.Errorf("expected a recovered strategy at full size, got %v", factor)
+	}
+	if last := changes[len(changes)-1]; last.To != StateHealthy {
+		t.Errorf("expected a recovery change, got %+v", last)
+	}
+}
+
+func TestMonitor_DisableAndReset(t *testing.T) {
+	monitor := NewMonitor(testConfig(), []Expectation{expected})
+	// A run of losses: the drawdown is three times the expected one
+	for i := 0; i < 4; i++ {
+		monitor.Record("main", "ETH-USD", -0.75)
+	}
+
+	factor, reason := monitor.SizeFactor("main", "ETH-USD")
+	if factor != 0 || !strings.Contains(reason, "disabled") {
+		t.Fatalf("expected entries disabled, got %v (%s)", factor, reason)
+	}
+	// Disabling the strategy stops it on every symbol
+	if factor, _ := monitor.SizeFactor("main", "SOL-USD"); factor != 0 {
+		t.Error("expected the strategy disabled on other symbols")
+	}
+
+	// Winning trades do not re-enable a disabled strategy
+	for i := 0; i < 10; i++ {
+		monitor.Record("main", "ETH-USD", 1)
+	}
+	if factor, _ := monitor.SizeFactor("main", "ETH-USD"); factor != 0 {
+		t.Error("expected the strategy to stay disabled until reset")
+	}
+
+	if !monitor.Reset("main", "") || !monitor.Reset("main", "ETH-USD") {
+		t.Fatal("expected the strategy and symbol to be reset")
+	}
+	if factor, _ := monitor.SizeFactor("main", "ETH-USD"); factor != 1 {
+		t.Errorf("expected full size after a reset, got %v", factor)
+	}
+	if monitor.Reset("main", "DOGE-USD") {
+		t.Error("expected untracked symbols not to be reset")
+	}
+
+	statuses := monitor.Statuses()
+	if len(statuses) != 2 || statuses[0].Symbol != "" || statuses[1].Symbol != "ETH-USD" {
+		t.Fatalf("expected the strategy then its symbol, got %+v", statuses)
+	}
+	if statuses[1].Metrics.Trades != 0 || statuses[1].State != StateHealthy {
+		t.Errorf("expected a reset symbol judged afresh, got %+v", statuses[1])
+	}
+	if len(monitor.Changes()) != 4 {
+		t.Errorf("expected 2 disables and 2 resets, got %+v", monitor.Changes())
+	}
+}
+
+func TestRollingMetrics(t *testing.T) {
+	metrics := rollingMetrics([]float64{1, -0.5, -1, 2})
+	if metrics.Trades != 4 || metrics.WinRate != 50 || metrics.ExpectancyPct != 0.375 || metrics.DrawdownPct != 1.5 {
+		t.Errorf("unexpected metrics %+v", metrics)
+	}
+}
+
+func TestExpectations_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expectations.json")
+	symbol := Expectation{Strategy: "main", Symbol: "BTC-USD", WinRate: 55}
+	if err := WriteExpectations(path, []Expectation{expected, symbol}); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadExpectations(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 2 || loaded[0] != expected || loaded[1] != symbol {
+		t.Errorf("expected the expectations back, got %+v", loaded)
+	}
+
+	// A symbol's own expectation takes precedence over the strategy's
+	monitor := NewMonitor(testConfig(), loaded)
+	if got, _ := monitor.expectation("main", "BTC-USD"); got.WinRate != 55 {
+		t.Errorf("expected the symbol expectation, got %+v", got)
+	}
+	if got, _ := monitor.expectation("main", "ETH-USD"); got.WinRate != 60 {
+		t.Errorf("expected the strategy expectation, got %+v", got)
+	}
+}
@@ -0,0 +1,173 @@
+// Package dispatch decouples the producers of callbacks from their
+// consumers: values are delivered through a bounded queue from a goroutine of
+// their own, so a slow consumer never holds up the loop producing them.
+package dispatch
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// Policy is what a full queue drops to make room for a value
+type Policy string
+
+const (
+	DropOldest Policy = "drop_oldest" // The oldest value waiting, keeping the latest
+	DropNewest Policy = "drop_newest" // The value pushed, keeping those waiting
+)
+
+// ParsePolicy returns the policy named value
+func ParsePolicy(value string) (Policy, error) {
+	switch policy := Policy(value); policy {
+	case DropOldest, DropNewest:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown drop policy %q, expected %s or %s", value, DropOldest, DropNewest)
+	}
+}
+
+// Queue delivers the values pushed to its consumer one at a time, in the
+// order they were pushed. Delivery runs on a goroutine started when values
+// are waiting and ending once the queue is empty, so an idle queue holds no
+// goroutine. The depth and drops of the queue are reported to telemetry
+// under its name.
+type Queue[T any] struct {
+	name     string
+	capacity int
+	policy   Policy
+	deliver  func(T)
+
+	mu         sync.Mutex
+	keep       func(T) bool
+	items      []T
+	delivering bool
+	dropped    uint64
+}
+
+// New creates a queue named name delivering to deliver, holding up to
+// capacity values waiting
+func New[T any](name string, capacity int, policy Policy, deliver func(T)) *Queue[T] {
+	return &Queue[T]{
+		name:     name,
+		capacity: capacity,
+		policy:   policy,
+		deliver:  deliver,
+	}
+}
+
+// SetKeep marks the values keep reports true for as never dropped. A full
+// queue drops another value waiting to make room for them, following its
+// policy, and only grows past its capacity when every value waiting is kept.
+func (q *Queue[T]) SetKeep(keep func(T) bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.keep = keep
+}
+
+// Push queues v for delivery without waiting for the consumer, and reports
+// false when the queue was full and a value was dropped
+func (q *Queue[T]) Push(v T) bool {
+	q.mu.Lock()
+	queued, dropped := true, false
+	if len(q.items) >= q.capacity {
+		switch {
+		case q.policy == DropNewest && !q.kept(v):
+			queued, dropped = false, true
+		case q.evict():
+			dropped = true
+		case !q.kept(v):
+			// Every value waiting is kept
+			queued, dropped = false, true
+		}
+	}
+	if queued {
+		q.items = append(q.items, v)
+	}
+	if dropped {
+		q.dropped++
+	}
+	depth := len(q.items)
+	start := !q.delivering
+	q.delivering = true
+	q.mu.Unlock()
+
+	if dropped {
+		telemetry.RecordCallbackDropped(q.name)
+	}
+	telemetry.RecordCallbackQueueDepth(q.name, depth)
+	if start {
+		go q.run()
+	}
+	return !dropped
+}
+
+// kept reports whether v must never be dropped
+func (q *Queue[T]) kept(v T) bool {
+	return q.keep != nil && q.keep(v)
+}
+
+// evict drops the oldest value waiting, or the newest with DropNewest, that
+// is not kept, and reports whether there was one
+func (q *Queue[T]) evict() bool {
+	index := -1
+	for i, v := range q.items {
+		if !q.kept(v) {
+			index = i
+			if q.policy == DropOldest {
+				break
+			}
+		}
+	}
+	if index < 0 {
+		return false
+	}
+	q.items = slices.Delete(q.items, index, index+1)
+	return true
+}
+
+// run delivers the values waiting until the queue is empty
+func (q *Queue[T]) run() {
+	for {
+		q.mu.Lock()
+		if len(q.items) == 0 {
+			q.delivering = false
+			q.mu.Unlock()
+			return
+		}
+		v := q.items[0]
+		q.items = slices.Delete(q.items, 0, 1)
+		depth := len(q.items)
+		q.mu.Unlock()
+
+		telemetry.RecordCallbackQueueDepth(q.name, depth)
+		q.invoke(v)
+	}
+}
+
+// invoke delivers v, recovering from a panic of the consumer so the values
+// behind it are still delivered
+func (q *Queue[T]) invoke(v T) {
+	defer func() {
+		if r := recover(); r != nil {
+			telemetry.RecordCallbackPanic()
+		}
+	}()
+	q.deliver(v)
+}
+
+// Len returns the number of values waiting for delivery
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Dropped returns the number of values dropped from the full queue
+func (q *Queue[T]) Dropped() uint64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
@@ -0,0 +1,157 @@
+package dispatch
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// blockingConsumer delivers to a queue and holds each value until released
+type blockingConsumer struct {
+	started   chan int
+	release   chan struct{}
+	delivered chan int
+}
+
+func newBlockingConsumer() *blockingConsumer {
+	return &blockingConsumer{
+		started:   make(chan int, 10),
+		release:   make(chan struct{}),
+		delivered: make(chan int, 10),
+	}
+}
+
+func (c *blockingConsumer) deliver(v int) {
+	c.started <- v
+	<-c.release
+	c.delivered <- v
+}
+
+func (c *blockingConsumer) collect(t *testing.T, n int) []int {
+	t.Helper()
+	var got []int
+	for range n {
+		select {
+		case v := <-c.delivered:
+			got = append(got, v)
+		case <-time.After(time.Second):
+			t.Fatalf("Expected %d deliveries, got %v", n, got)
+		}
+	}
+	return got
+}
+
+func TestQueue_DropOldest(t *testing.T) {
+	consumer := newBlockingConsumer()
+	queue := New("test", 2, DropOldest, consumer.deliver)
+
+	if !queue.Push(1) {
+		t.Fatal("Push should not drop while the queue has room")
+	}
+	// The first value is being delivered, so it no longer waits
+	<-consumer.started
+	queue.Push(2)
+	queue.Push(3)
+	if queue.Push(4) {
+		t.Error("Push should report the value dropped from a full queue")
+	}
+	if queue.Len() != 2 || queue.Dropped() != 1 {
+		t.Errorf("Expected 2 waiting and 1 dropped, got %d and %d", queue.Len(), queue.Dropped())
+	}
+
+	close(consumer.release)
+	if got := consumer.collect(t, 3); !slices.Equal(got, []int{1, 3, 4}) {
+		t.Errorf("Expected the oldest waiting value to be dropped, got %v", got)
+	}
+}
+
+func TestQueue_DropNewest(t *testing.T) {
+	consumer := newBlockingConsumer()
+	queue := New("test", 2, DropNewest, consumer.deliver)
+
+	queue.Push(1)
+	<-consumer.started
+	queue.Push(2)
+	queue.Push(3)
+	queue.Push(4)
+
+	close(consumer.release)
+	if got := consumer.collect(t, 3); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("Expected the value pushed to be dropped, got %v", got)
+	}
+	if queue.Dropped() != 1 {
+		t.Errorf("Expected 1 dropped, got %d", queue.Dropped())
+	}
+}
+
+func TestQueue_NeverDropsKeptValues(t *testing.T) {
+	consumer := newBlockingConsumer()
+	queue := New("test", 2, DropOldest, consumer.deliver)
+	// Negative values stand for exits
+	queue.SetKeep(func(v int) bool { return v < 0 })
+
+	queue.Push(1)
+	<-consumer.started
+	queue.Push(-2)
+	queue.Push(3)
+	// The oldest value not kept makes room
+	queue.Push(4)
+	queue.Push(-5)
+	// Nothing waiting can be dropped, so the value pushed is
+	if queue.Push(6) {
+		t.Error("Push should drop a value not kept when every value waiting is kept")
+	}
+	// A kept value is queued past the capacity
+	if !queue.Push(-7) {
+		t.Error("Push should never drop a kept value")
+	}
+	if queue.Len() != 3 || queue.Dropped() != 3 {
+		t.Errorf("Expected 3 waiting and 3 dropped, got %d and %d", queue.Len(), queue.Dropped())
+	}
+
+	close(consumer.release)
+	if got := consumer.collect(t, 4); !slices.Equal(got, []int{1, -2, -5, -7}) {
+		t.Errorf("Expected every kept value delivered in order, got %v", got)
+	}
+}
+
+func TestQueue_RecoversFromPanics(t *testing.T) {
+	delivered := make(chan int, 2)
+	queue := New("test", 4, DropOldest, func(v int) {
+		if v == 1 {
+			panic("consumer failed")
+		}
+		delivered <- v
+	})
+
+	queue.Push(1)
+	queue.Push(2)
+	select {
+	case v := <-delivered:
+		if v != 2 {
+			t.Errorf("Expected 2, got %d", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Values behind a panicking delivery should still be delivered")
+	}
+
+	// The queue keeps delivering once it drained
+	queue.Push(3)
+	select {
+	case v := <-delivered:
+		if v != 3 {
+			t.Errorf("Expected 3, got %d", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("An idle queue should restart delivery")
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	if policy, err := ParsePolicy("drop_newest"); err != nil || policy != DropNewest {
+		t.Errorf("Expected drop_newest, got %q (%v)", policy, err)
+	}
+	if _, err := ParsePolicy("block"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}
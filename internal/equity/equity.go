@@ -0,0 +1,145 @@
+// Package equity persists periodic balance and equity snapshots per exchange
+// and rolls them up into daily, weekly and monthly PnL, so performance can be
+// tracked across restarts of the bot.
+package equity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Total is the exchange name of the snapshots summed over every exchange
+const Total = "total"
+
+// Snapshot is the equity of an exchange account at a point in time
+type Snapshot struct {
+	Time     time.Time `json:"time"`
+	Exchange string    `json:"exchange"`
+	Currency string    `json:"currency,omitempty"`
+	// Equity is the account value including the unrealized PnL of the open
+	// positions
+	Equity        decimal.Decimal `json:"equity"`
+	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl"`
+	// NetDeposits is the sum of the deposits less the withdrawals detected,
+	// left out of the PnL
+	NetDeposits decimal.Decimal `json:"net_deposits"`
+	// Prices are the last prices of the traded and benchmark symbols,
+	// recorded on the total to compare the PnL with buy and hold
+	Prices map[string]decimal.Decimal `json:"prices,omitempty"`
+}
+
+// realized is the equity excluding open positions and transfers
+func (s Snapshot) realized() decimal.Decimal {
+	return s.Equity.Sub(s.UnrealizedPnL).Sub(s.NetDeposits)
+}
+
+// Period is the length of a rollup
+type Period string
+
+const (
+	Daily   Period = "daily"
+	Weekly  Period = "weekly"
+	Monthly Period = "monthly"
+)
+
+// ParsePeriod parses a rollup period name
+func ParsePeriod(name string) (Period, error) {
+	switch period := Period(name); period {
+	case Daily, Weekly, Monthly:
+		return period, nil
+	}
+	return "", fmt.Errorf("unknown period %q, expected daily, weekly or monthly", name)
+}
+
+// Start returns the start of the period containing t, in UTC. Weeks start on
+// Monday.
+func (p Period) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch p {
+	case Weekly:
+		// Days since Monday, Sunday being the last day of the week
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case Monthly:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// Rollup is the PnL of an exchange account over a period
+type Rollup struct {
+	Exchange string    `json:"exchange"`
+	Period   Period    `json:"period"`
+	Start    time.Time `json:"start"`
+	// Equity at the end of the previous period, or at the first snapshot of
+	// this one, and at the last snapshot of this one
+	OpeningEquity decimal.Decimal `json:"opening_equity"`
+	ClosingEquity decimal.Decimal `json:"closing_equity"`
+	// RealizedPnL is the change of the equity excluding open positions and
+	// transfers, UnrealizedPnL the change of the open positions' PnL
+	RealizedPnL   decimal.Decimal `json:"realized_pnl"`
+	UnrealizedPnL decimal.Decimal `json:"unrealized_pnl"`
+	PnL           decimal.Decimal `json:"pnl"`
+	ReturnPercent float64         `json:"return_percent"`
+	Snapshots     int             `json:"snapshots"`
+}
+
+// Rollups computes the PnL of each exchange over every period covered by
+// snapshots, ordered by exchange then period. Each period opens at the last
+// snapshot of the previous one, so no PnL falls between two periods.
+func Rollups(snapshots []Snapshot, period Period) []Rollup {
+	byExchange := make(map[string][]Snapshot)
+	for _, snapshot := range snapshots {
+		byExchange[snapshot.Exchange] = append(byExchange[snapshot.Exchange], snapshot)
+	}
+	names := make([]string, 0, len(byExchange))
+	for name := range byExchange {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rollups []Rollup
+	for _, name := range names {
+		history := byExchange[name]
+		sort.SliceStable(history, func(i, j int) bool { return history[i].Time.Before(history[j].Time) })
+
+		var opening *Snapshot
+		for i := 0; i < len(history); {
+			start := period.Start(history[i].Time)
+			j := i
+			for j < len(history) && period.Start(history[j].Time).Equal(start) {
+				j++
+			}
+			closing := history[j-1]
+			if opening == nil {
+				opening = &history[i]
+			}
+			rollups = append(rollups, newRollup(name, period, start, *opening, closing, j-i))
+			opening = &history[j-1]
+			i = j
+		}
+	}
+	return rollups
+}
+
+// newRollup computes the PnL between the opening and closing snapshots
+func newRollup(exchange string, period Period, start time.Time, opening, closing Snapshot, snapshots int) Rollup {
+	rollup := Rollup{
+		Exchange:      exchange,
+		Period:        period,
+		Start:         start,
+		OpeningEquity: opening.Equity,
+		ClosingEquity: closing.Equity,
+		RealizedPnL:   closing.realized().Sub(opening.realized()),
+		UnrealizedPnL: closing.UnrealizedPnL.Sub(opening.UnrealizedPnL),
+		Snapshots:     snapshots,
+	}
+	rollup.PnL = rollup.RealizedPnL.Add(rollup.UnrealizedPnL)
+	if opening.Equity.IsPositive() {
+		rollup.ReturnPercent = rollup.PnL.Div(opening.Equity).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	}
+	return rollup
+}
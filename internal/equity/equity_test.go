@@ -0,0 +1,110 @@
+package equity
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func snapshot(at string, exchange string, equity, unrealized, deposits float64) Snapshot {
+	t, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		panic(err)
+	}
+	return Snapshot{
+		Time:          t,
+		Exchange:      exchange,
+		Equity:        decimal.NewFromFloat(equity),
+		UnrealizedPnL: decimal.NewFromFloat(unrealized),
+		NetDeposits:   decimal.NewFromFloat(deposits),
+	}
+}
+
+func TestPeriod_Start(t *testing.T) {
+	// Sunday evening in UTC+2
+	at := time.Date(2024, 3, 17, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	testutils.AssertEqual(t, time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC), Daily.Start(at), "days start at midnight UTC")
+	testutils.AssertEqual(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), Weekly.Start(at), "weeks start on Monday")
+	testutils.AssertEqual(t, time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC), Weekly.Start(at.AddDate(0, 0, 1)), "Monday starts a week")
+	testutils.AssertEqual(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Monthly.Start(at), "months start on the 1st")
+
+	_, err := ParsePeriod("hourly")
+	testutils.AssertError(t, err, "unknown periods should be rejected")
+}
+
+func TestRollups(t *testing.T) {
+	snapshots := []Snapshot{
+		snapshot("2024-03-01T10:00:00Z", Total, 1000, 0, 0),
+		snapshot("2024-03-01T18:00:00Z", Total, 1030, 20, 0),
+		// A deposit of 500 overnight and a closed position
+		snapshot("2024-03-02T10:00:00Z", Total, 1540, 0, 500),
+		snapshot("2024-03-02T18:00:00Z", Total, 1490, -10, 500),
+		snapshot("2024-03-01T18:00:00Z", "dydx", 600, 20, 0),
+	}
+
+	daily := Rollups(snapshots, Daily)
+	testutils.AssertEqual(t, 3, len(daily), "one rollup per exchange and day")
+	testutils.AssertEqual(t, "dydx", daily[0].Exchange, "rollups are ordered by exchange")
+
+	first := daily[1]
+	testutils.AssertTrue(t, first.RealizedPnL.Equal(decimal.NewFromInt(10)), "realized PnL excludes the open positions, got "+first.RealizedPnL.String())
+	testutils.AssertTrue(t, first.UnrealizedPnL.Equal(decimal.NewFromInt(20)), "unrealized PnL is the change of the open positions")
+	testutils.AssertEqual(t, 3.0, first.ReturnPercent, "return on the opening equity")
+	testutils.AssertEqual(t, 2, first.Snapshots, "snapshots of the day")
+
+	// The second day opens at the close of the first and leaves out the deposit
+	second := daily[2]
+	testutils.AssertTrue(t, second.OpeningEquity.Equal(decimal.NewFromInt(1030)), "periods open at the previous close")
+	testutils.AssertTrue(t, second.RealizedPnL.Equal(decimal.NewFromInt(-10)), "deposits are not PnL, got "+second.RealizedPnL.String())
+	testutils.AssertTrue(t, second.UnrealizedPnL.Equal(decimal.NewFromInt(-30)), "unrealized PnL change")
+	testutils.AssertTrue(t, second.PnL.Equal(decimal.NewFromInt(-40)), "PnL is realized plus unrealized")
+
+	monthly := Rollups(snapshots[:4], Monthly)
+	testutils.AssertEqual(t, 1, len(monthly), "one month")
+	testutils.AssertTrue(t, monthly[0].PnL.Equal(decimal.NewFromInt(-10)), "monthly PnL spans the month, got "+monthly[0].PnL.String())
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "equity.jsonl")
+	store, err := Open(path)
+	testutils.AssertNoError(t, err, "Open should create the history")
+
+	testutils.AssertNoError(t, store.Record(
+		snapshot("2024-03-01T10:00:00Z", Total, 1000, 0, 0),
+		snapshot("2024-03-01T10:00:00Z", "dydx", 1000, 0, 0),
+	), "Record should not return error")
+	testutils.AssertNoError(t, store.Close(), "Close should not return error")
+	testutils.AssertError(t, store.Record(snapshot("2024-03-01T11:00:00Z", Total, 1000, 0, 0)), "Record after Close should fail")
+
+	// Reopening keeps the history and appends to it
+	store, err = Open(path)
+	testutils.AssertNoError(t, err, "Open should load the history")
+	defer store.Close()
+	testutils.AssertNoError(t, store.Record(snapshot("2024-03-02T10:00:00Z", Total, 1100, 0, 0)), "Record should not return error")
+	testutils.AssertNoError(t, store.Record(snapshot("2024-03-02T11:00:00Z", Total, 1700, 0, 500)), "Record should not return error")
+
+	testutils.AssertEqual(t, 3, len(store.Snapshots(Total, time.Time{})), "total snapshots across restarts")
+	since := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	testutils.AssertEqual(t, 2, len(store.Snapshots("", since)), "snapshots since a time")
+
+	rollups := store.Rollups(Total, Daily)
+	testutils.AssertEqual(t, 2, len(rollups), "daily rollups across restarts")
+	testutils.AssertTrue(t, rollups[1].PnL.Equal(decimal.NewFromInt(200)), "PnL across restarts, got "+rollups[1].PnL.String())
+	testutils.AssertNoError(t, store.Close(), "Close should not return error")
+
+	// The deposits of a previous run are carried over to the next
+	store, err = Open(path)
+	testutils.AssertNoError(t, err, "Open should load the history")
+	testutils.AssertNoError(t, store.Record(snapshot("2024-03-02T12:00:00Z", Total, 1700, 0, 0)), "Record should not return error")
+	latest := store.Snapshots(Total, time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC))
+	testutils.AssertTrue(t, latest[0].NetDeposits.Equal(decimal.NewFromInt(500)), "deposits carried over, got "+latest[0].NetDeposits.String())
+	testutils.AssertTrue(t, store.Rollups(Total, Daily)[1].PnL.Equal(decimal.NewFromInt(200)), "a restart is not PnL")
+
+	loaded, err := Load(path)
+	testutils.AssertNoError(t, err, "Load should not return error")
+	testutils.AssertEqual(t, 5, len(loaded), "every snapshot is persisted")
+}
@@ -0,0 +1,130 @@
+package equity
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/statefile"
+	"github.com/shopspring/decimal"
+)
+
+// Store appends snapshots to a JSON lines file and keeps them in memory to be
+// queried. Every snapshot is flushed as it is written so the history survives
+// a crash.
+type Store struct {
+	mu        sync.RWMutex
+	file      *os.File
+	writer    *bufio.Writer
+	encoder   *json.Encoder
+	snapshots []Snapshot
+	// Net deposits of each exchange at the end of the history loaded, added
+	// to the deposits of this run
+	deposits map[string]decimal.Decimal
+}
+
+// Open loads the snapshots already recorded at path, creating the file if
+// needed, and appends the next ones to it
+func Open(path string) (*Store, error) {
+	snapshots, err := Load(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open equity history: %w", err)
+	}
+	deposits := make(map[string]decimal.Decimal)
+	for _, snapshot := range snapshots {
+		deposits[snapshot.Exchange] = snapshot.NetDeposits
+	}
+	writer := bufio.NewWriter(file)
+	return &Store{
+		file:      file,
+		writer:    writer,
+		encoder:   json.NewEncoder(statefile.NewLineWriter(writer)),
+		snapshots: snapshots,
+		deposits:  deposits,
+	}, nil
+}
+
+// Record appends snapshots to the history. Their net deposits are counted
+// since the bot started: the deposits of the previous runs are added to them.
+func (s *Store) Record(snapshots ...Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.encoder == nil {
+		return fmt.Errorf("equity history closed")
+	}
+	for _, snapshot := range snapshots {
+		snapshot.NetDeposits = snapshot.NetDeposits.Add(s.deposits[snapshot.Exchange])
+		if err := s.encoder.Encode(&snapshot); err != nil {
+			return fmt.Errorf("failed to write equity snapshot: %w", err)
+		}
+		s.snapshots = append(s.snapshots, snapshot)
+	}
+	return s.writer.Flush()
+}
+
+// Snapshots returns the snapshots of exchange taken at or after since, every
+// exchange's when exchange is empty
+func (s *Store) Snapshots(exchange string, since time.Time) []Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var snapshots []Snapshot
+	for _, snapshot := range s.snapshots {
+		if (exchange == "" || snapshot.Exchange == exchange) && !snapshot.Time.Before(since) {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+	return snapshots
+}
+
+// Rollups returns the PnL of exchange over every period of the history,
+// every exchange's when exchange is empty
+func (s *Store) Rollups(exchange string, period Period) []Rollup {
+	return Rollups(s.Snapshots(exchange, time.Time{}), period)
+}
+
+// Close flushes and closes the history file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.writer == nil {
+		return nil
+	}
+	err := s.writer.Flush()
+	s.writer = nil
+	s.encoder = nil
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Load reads every snapshot from a history file
+func Load(path string) ([]Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open equity history: %w", err)
+	}
+	defer file.Close()
+
+	var snapshots []Snapshot
+	decoder := json.NewDecoder(statefile.NewLineReader(file))
+	for decoder.More() {
+		var snapshot Snapshot
+		if err := decoder.Decode(&snapshot); err != nil {
+			return nil, fmt.Errorf("failed to read equity snapshot %d: %w", len(snapshots)+1, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
@@ -17,6 +17,7 @@ type ExchangeData struct {
 	Balances  []Balance
 	Positions []Position
 	Orders    []Order
+	Margin    AccountMargin
 	Error     error
 }
 
@@ -26,6 +27,20 @@ type AggregatedData struct {
 	TotalBalance decimal.Decimal
 	TotalPnL     decimal.Decimal
 	LastUpdate   int64
+	Currency     string // Reporting currency of the totals, empty when not normalized
+	// Unconverted lists the currencies of the amounts left out of the totals
+	// for lack of a rate into Currency; the totals are incomplete when set
+	Unconverted []string
+
+	// TotalUnrealizedPnL is the part of TotalPnL from open positions, included
+	// in the balance of margin accounts
+	TotalUnrealizedPnL decimal.Decimal
+
+	// Margin usage summed over the exchanges, the buying power left being
+	// TotalFreeCollateral rather than TotalBalance
+	TotalEquity         decimal.Decimal
+	TotalMarginUsed     decimal.Decimal
+	TotalFreeCollateral decimal.Decimal
 }
 
 // MultiExchangeAggregator manages multiple exchange clients and aggregates their data
@@ -93,6 +108,7 @@ func (a *MultiExchangeAggregator) RefreshData(ctx context.Context) error {
 
 	totalBalance := decimal.Zero
 	totalPnL := decimal.Zero
+	var totals marginTotals
 	results := make(map[string]*ExchangeData, len(exchanges))
 
 	for name, exchange := range exchanges {
@@ -135,6 +151,13 @@ func (a *MultiExchangeAggregator) RefreshData(ctx context.Context) error {
 			totalPnL = totalPnL.Add(position.UnrealizedPnL)
 		}
 
+		if margin, err := accountMargin(ctx, exchange, balances, positions); err != nil {
+			telemetry.RecordError(fmt.Sprintf("%s_get_margin", name))
+		} else {
+			exchangeData.Margin = margin
+			totals.add(margin)
+		}
+
 		if exchangeWithOrders, ok := exchange.(interface {
 			GetOrders(context.Context) ([]Order, error)
 		}); ok {
@@ -159,6 +182,7 @@ func (a *MultiExchangeAggregator) RefreshData(ctx context.Context) error {
 	}
 	a.data.TotalBalance = totalBalance
 	a.data.TotalPnL = totalPnL
+	totals.apply(a.data)
 	a.data.LastUpdate = time.Now().Unix()
 	if totalPnLFloat, ok := totalPnL.Float64(); ok {
 		telemetry.RecordPnLUpdate("total", totalPnLFloat)
@@ -178,6 +202,10 @@ func (a *MultiExchangeAggregator) GetAggregatedData() *AggregatedData {
 		TotalBalance: a.data.TotalBalance,
 		TotalPnL:     a.data.TotalPnL,
 		LastUpdate:   a.data.LastUpdate,
+
+		TotalEquity:         a.data.TotalEquity,
+		TotalMarginUsed:     a.data.TotalMarginUsed,
+		TotalFreeCollateral: a.data.TotalFreeCollateral,
 	}
 
 	for name, exchangeData := range a.data.Exchanges {
@@ -187,6 +215,7 @@ func (a *MultiExchangeAggregator) GetAggregatedData() *AggregatedData {
 			Balances:  append([]Balance(nil), exchangeData.Balances...),
 			Positions: append([]Position(nil), exchangeData.Positions...),
 			Orders:    append([]Order(nil), exchangeData.Orders...),
+			Margin:    exchangeData.Margin,
 			Error:     exchangeData.Error,
 		}
 	}
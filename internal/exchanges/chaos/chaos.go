@@ -0,0 +1,312 @@
+// Package chaos wraps an exchange to inject the failures real venues produce:
+// timeouts after the request went through, server errors, duplicate and out
+// of order order updates, and stale prices. It is meant for stress tests and
+// soak runs against a simulated exchange.
+package chaos
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// Config holds the probability of each fault, between 0 and 1
+type Config struct {
+	// Seed makes the faults reproducible
+	Seed int64
+	// TimeoutRate is the share of orders and cancels that report a timeout
+	// after reaching the exchange
+	TimeoutRate float64
+	// ServerErrorRate is the share of requests that fail with a server error
+	// without reaching the exchange
+	ServerErrorRate float64
+	// DuplicateRate is the share of open order listings and stream messages
+	// that repeat an entry
+	DuplicateRate float64
+	// OutOfOrderRate is the share of order queries answered with an earlier
+	// state of the order
+	OutOfOrderRate float64
+	// StalePriceRate is the share of tickers replaced by an earlier one
+	StalePriceRate float64
+}
+
+// Fault is a kind of injected failure
+type Fault string
+
+const (
+	FaultTimeout     Fault = "timeout"
+	FaultServerError Fault = "server_error"
+	FaultDuplicate   Fault = "duplicate"
+	FaultOutOfOrder  Fault = "out_of_order"
+	FaultStalePrice  Fault = "stale_price"
+)
+
+// maxOrderHistory is the number of states kept per order to answer out of
+// order queries
+const maxOrderHistory = 8
+
+// Exchange injects faults around the exchange it wraps. Calls it does not
+// override go straight to the wrapped exchange.
+type Exchange struct {
+	exchanges.Exchange
+
+	mu      sync.Mutex
+	config  Config
+	rng     *rand.Rand
+	history map[string][]exchanges.Order // order ID -> states seen, oldest first
+	tickers map[string]exchanges.Ticker  // symbol -> previous ticker
+	faults  map[Fault]int
+}
+
+// New wraps inner with the faults of config
+func New(inner exchanges.Exchange, config Config) *Exchange {
+	return &Exchange{
+		Exchange: inner,
+		config:   config,
+		rng:      rand.New(rand.NewSource(config.Seed)),
+		history:  make(map[string][]exchanges.Order),
+		tickers:  make(map[string]exchanges.Ticker),
+		faults:   make(map[Fault]int),
+	}
+}
+
+// SetConfig changes the fault rates, keeping the random sequence. A zero
+// config turns the wrapper into a pass-through.
+func (e *Exchange) SetConfig(config Config) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	config.Seed = e.config.Seed
+	e.config = config
+}
+
+// Faults returns the number of faults injected by kind
+func (e *Exchange) Faults() map[Fault]int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	faults := make(map[Fault]int, len(e.faults))
+	for fault, count := range e.faults {
+		faults[fault] = count
+	}
+	return faults
+}
+
+// inject draws whether to inject fault at rate and counts it
+func (e *Exchange) inject(fault Fault, rate func(Config) float64) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if p := rate(e.config); p <= 0 || e.rng.Float64() >= p {
+		return false
+	}
+	e.faults[fault]++
+	return true
+}
+
+func timeoutRate(c Config) float64     { return c.TimeoutRate }
+func serverErrorRate(c Config) float64 { return c.ServerErrorRate }
+func duplicateRate(c Config) float64   { return c.DuplicateRate }
+func outOfOrderRate(c Config) float64  { return c.OutOfOrderRate }
+func stalePriceRate(c Config) float64  { return c.StalePriceRate }
+
+func (e *Exchange) timeoutError() error {
+	return exchanges.NewError(e.Name(), exchanges.ErrorCodeNetwork, "chaos: request timed out", context.DeadlineExceeded)
+}
+
+func (e *Exchange) serverError() error {
+	return exchanges.NewError(e.Name(), exchanges.ErrorCodeNetwork, "chaos: 500 internal server error", nil)
+}
+
+// record keeps order as the latest state seen of its order
+func (e *Exchange) record(order *exchanges.Order) {
+	if order == nil || order.ID == "" {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	states := e.history[order.ID]
+	if n := len(states); n > 0 && states[n-1].Status == order.Status && states[n-1].Filled.Equal(order.Filled) {
+		return
+	}
+	states = append(states, *order)
+	if len(states) > maxOrderHistory {
+		states = states[1:]
+	}
+	e.history[order.ID] = states
+}
+
+// earlierState returns a random state of the order seen before the latest,
+// or nil if there is none
+func (e *Exchange) earlierState(orderID string) *exchanges.Order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	states := e.history[orderID]
+	if len(states) < 2 {
+		return nil
+	}
+	state := states[e.rng.Intn(len(states)-1)]
+	return &state
+}
+
+// PlaceOrder fails with a server error before the order reaches the
+// exchange, or with a timeout after it was placed
+func (e *Exchange) PlaceOrder(ctx context.Context, order *exchanges.Order) (*exchanges.Order, error) {
+	if e.inject(FaultServerError, serverErrorRate) {
+		return nil, e.serverError()
+	}
+	placed, err := e.Exchange.PlaceOrder(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+	e.record(placed)
+	if e.inject(FaultTimeout, timeoutRate) {
+		return nil, e.timeoutError()
+	}
+	return placed, nil
+}
+
+// CancelOrder fails with a server error before the cancel reaches the
+// exchange, or with a timeout after the order was canceled
+func (e *Exchange) CancelOrder(ctx context.Context, orderID string) error {
+	if e.inject(FaultServerError, serverErrorRate) {
+		return e.serverError()
+	}
+	if err := e.Exchange.CancelOrder(ctx, orderID); err != nil {
+		return err
+	}
+	if e.inject(FaultTimeout, timeoutRate) {
+		return e.timeoutError()
+	}
+	return nil
+}
+
+// GetOrder may fail or return an earlier state of the order
+func (e *Exchange) GetOrder(ctx context.Context, orderID string) (*exchanges.Order, error) {
+	if e.inject(FaultServerError, serverErrorRate) {
+		return nil, e.serverError()
+	}
+	order, err := e.Exchange.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	e.record(order)
+	if e.inject(FaultOutOfOrder, outOfOrderRate) {
+		if earlier := e.earlierState(orderID); earlier != nil {
+			return earlier, nil
+		}
+	}
+	return order, nil
+}
+
+// GetOrderByClientID looks the order up on the wrapped exchange when it
+// supports client order IDs
+func (e *Exchange) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*exchanges.Order, error) {
+	lookup, ok := e.Exchange.(exchanges.ClientOrderLookup)
+	if !ok {
+		return nil, exchanges.NewError(e.Name(), exchanges.ErrorCodeNotSupported, "client order lookup not supported", exchanges.ErrNotSupported)
+	}
+	if e.inject(FaultServerError, serverErrorRate) {
+		return nil, e.serverError()
+	}
+	order, err := lookup.GetOrderByClientID(ctx, symbol, clientOrderID)
+	if err != nil {
+		return nil, err
+	}
+	e.record(order)
+	return order, nil
+}
+
+// GetOpenOrders may fail, list earlier states of orders or list an order
+// twice
+func (e *Exchange) GetOpenOrders(ctx context.Context, symbol string) ([]exchanges.Order, error) {
+	if e.inject(FaultServerError, serverErrorRate) {
+		return nil, e.serverError()
+	}
+	orders, err := e.Exchange.GetOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]exchanges.Order, 0, len(orders))
+	for i := range orders {
+		e.record(&orders[i])
+		order := orders[i]
+		if e.inject(FaultOutOfOrder, outOfOrderRate) {
+			if earlier := e.earlierState(order.ID); earlier != nil {
+				order = *earlier
+			}
+		}
+		result = append(result, order)
+		if e.inject(FaultDuplicate, duplicateRate) {
+			result = append(result, order)
+		}
+	}
+	return result, nil
+}
+
+// GetTicker may fail or return the previous ticker of the symbol
+func (e *Exchange) GetTicker(ctx context.Context, symbol string) (*exchanges.Ticker, error) {
+	if e.inject(FaultServerError, serverErrorRate) {
+		return nil, e.serverError()
+	}
+	ticker, err := e.Exchange.GetTicker(ctx, symbol)
+	if err != nil || ticker == nil {
+		return ticker, err
+	}
+	return e.staleTicker(ticker), nil
+}
+
+// staleTicker returns the previous ticker of the symbol in place of ticker
+// when a stale price is injected, and remembers ticker otherwise
+func (e *Exchange) staleTicker(ticker *exchanges.Ticker) *exchanges.Ticker {
+	e.mu.Lock()
+	previous, seen := e.tickers[ticker.Symbol]
+	e.mu.Unlock()
+	if seen && e.inject(FaultStalePrice, stalePriceRate) {
+		return &previous
+	}
+	e.mu.Lock()
+	e.tickers[ticker.Symbol] = *ticker
+	e.mu.Unlock()
+	return ticker
+}
+
+// GetPositions may fail with a server error
+func (e *Exchange) GetPositions(ctx context.Context) ([]exchanges.Position, error) {
+	if e.inject(FaultServerError, serverErrorRate) {
+		return nil, e.serverError()
+	}
+	return e.Exchange.GetPositions(ctx)
+}
+
+// GetBalance may fail with a server error
+func (e *Exchange) GetBalance(ctx context.Context) ([]exchanges.Balance, error) {
+	if e.inject(FaultServerError, serverErrorRate) {
+		return nil, e.serverError()
+	}
+	return e.Exchange.GetBalance(ctx)
+}
+
+// SubscribeTicker may deliver the previous ticker again or a ticker twice
+func (e *Exchange) SubscribeTicker(ctx context.Context, symbol string, callback func(*exchanges.Ticker)) error {
+	return e.Exchange.SubscribeTicker(ctx, symbol, func(ticker *exchanges.Ticker) {
+		if ticker == nil {
+			callback(ticker)
+			return
+		}
+		delivered := e.staleTicker(ticker)
+		callback(delivered)
+		if e.inject(FaultDuplicate, duplicateRate) {
+			callback(delivered)
+		}
+	})
+}
+
+// SubscribeOrderBook may deliver a book twice
+func (e *Exchange) SubscribeOrderBook(ctx context.Context, symbol string, callback func(*exchanges.OrderBook)) error {
+	return e.Exchange.SubscribeOrderBook(ctx, symbol, func(book *exchanges.OrderBook) {
+		callback(book)
+		if e.inject(FaultDuplicate, duplicateRate) {
+			callback(book)
+		}
+	})
+}
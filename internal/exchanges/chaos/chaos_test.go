@@ -0,0 +1,119 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func newOrder(clientOrderID string) *exchanges.Order {
+	return &exchanges.Order{
+		ClientOrderID: clientOrderID,
+		Symbol:        "BTC-USD",
+		Side:          exchanges.OrderSideBuy,
+		Type:          exchanges.OrderTypeMarket,
+		Amount:        decimal.NewFromInt(1),
+	}
+}
+
+func TestPlaceOrder_TimeoutAfterPlacement(t *testing.T) {
+	market := testutils.NewMatchingExchange("test", decimal.NewFromInt(100))
+	exchange := New(market, Config{TimeoutRate: 1})
+
+	_, err := exchange.PlaceOrder(context.Background(), newOrder("a"))
+	if !errors.Is(err, context.DeadlineExceeded) || !exchanges.CodeOf(err).Retryable() {
+		t.Fatalf("expected a retryable timeout, got %v", err)
+	}
+	if orders := market.Orders(); len(orders) != 1 {
+		t.Fatalf("expected the order to reach the exchange, got %d orders", len(orders))
+	}
+	if _, err := exchange.GetOrderByClientID(context.Background(), "BTC-USD", "a"); err != nil {
+		t.Fatalf("expected the order to be found by client order ID: %v", err)
+	}
+	if got := exchange.Faults()[FaultTimeout]; got != 1 {
+		t.Fatalf("expected 1 timeout, got %d", got)
+	}
+}
+
+func TestPlaceOrder_ServerErrorBeforePlacement(t *testing.T) {
+	market := testutils.NewMatchingExchange("test", decimal.NewFromInt(100))
+	exchange := New(market, Config{ServerErrorRate: 1})
+
+	if _, err := exchange.PlaceOrder(context.Background(), newOrder("a")); exchanges.CodeOf(err) != exchanges.ErrorCodeNetwork {
+		t.Fatalf("expected a network error, got %v", err)
+	}
+	if orders := market.Orders(); len(orders) != 0 {
+		t.Fatalf("expected no order on the exchange, got %d", len(orders))
+	}
+}
+
+func TestGetOrder_OutOfOrder(t *testing.T) {
+	ctx := context.Background()
+	market := testutils.NewMatchingExchange("test", decimal.NewFromInt(100))
+	exchange := New(market, Config{})
+
+	order := newOrder("a")
+	order.Type = exchanges.OrderTypeLimit
+	order.Price = decimal.NewFromInt(99)
+	placed, err := exchange.PlaceOrder(ctx, order)
+	if err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	market.SetPrice(decimal.NewFromInt(98))
+	if latest, _ := exchange.GetOrder(ctx, placed.ID); latest.Status != exchanges.OrderStatusFilled {
+		t.Fatalf("expected the order to be filled, got %s", latest.Status)
+	}
+
+	exchange.SetConfig(Config{OutOfOrderRate: 1})
+	earlier, err := exchange.GetOrder(ctx, placed.ID)
+	if err != nil {
+		t.Fatalf("GetOrder: %v", err)
+	}
+	if earlier.Status != exchanges.OrderStatusOpen {
+		t.Fatalf("expected the earlier open state, got %s", earlier.Status)
+	}
+}
+
+func TestGetTicker_Stale(t *testing.T) {
+	ctx := context.Background()
+	market := testutils.NewMatchingExchange("test", decimal.NewFromInt(100))
+	exchange := New(market, Config{})
+
+	if _, err := exchange.GetTicker(ctx, "BTC-USD"); err != nil {
+		t.Fatalf("GetTicker: %v", err)
+	}
+	market.SetPrice(decimal.NewFromInt(110))
+	exchange.SetConfig(Config{StalePriceRate: 1})
+
+	ticker, err := exchange.GetTicker(ctx, "BTC-USD")
+	if err != nil {
+		t.Fatalf("GetTicker: %v", err)
+	}
+	if !ticker.Last.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("expected the stale price 100, got %s", ticker.Last)
+	}
+}
+
+func TestGetOpenOrders_Duplicate(t *testing.T) {
+	ctx := context.Background()
+	market := testutils.NewMatchingExchange("test", decimal.NewFromInt(100))
+	exchange := New(market, Config{DuplicateRate: 1})
+
+	order := newOrder("a")
+	order.Type = exchanges.OrderTypeLimit
+	order.Price = decimal.NewFromInt(90)
+	if _, err := exchange.PlaceOrder(ctx, order); err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	orders, err := exchange.GetOpenOrders(ctx, "BTC-USD")
+	if err != nil {
+		t.Fatalf("GetOpenOrders: %v", err)
+	}
+	if len(orders) != 2 || orders[0].ID != orders[1].ID {
+		t.Fatalf("expected the open order listed twice, got %d orders", len(orders))
+	}
+}
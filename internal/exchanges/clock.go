@@ -0,0 +1,45 @@
+package exchanges
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// MaxClockSkew is the offset to an exchange's server time beyond which
+// signed requests risk being rejected
+const MaxClockSkew = time.Second
+
+// ClockSyncer is implemented by exchanges that timestamp signed requests and
+// can correct those timestamps to the exchange's server time
+type ClockSyncer interface {
+	// SyncClock measures the offset of the server time from the local time,
+	// corrects the timestamps of later requests by it and returns it
+	SyncClock(ctx context.Context) (time.Duration, error)
+}
+
+// Clock is the local time corrected by the offset measured to an exchange's
+// server time. The zero value has no offset.
+type Clock struct {
+	offset atomic.Int64
+}
+
+// Now returns the current time on the exchange's clock
+func (c *Clock) Now() time.Time {
+	return time.Now().Add(c.Offset())
+}
+
+// Offset returns how far the exchange's clock is ahead of the local one
+func (c *Clock) Offset() time.Duration {
+	return time.Duration(c.offset.Load())
+}
+
+// Measure sets the offset from a request sent at sent and answered at
+// received with the server time serverTime, assuming the server stamped its
+// response halfway through the round trip
+func (c *Clock) Measure(sent, received, serverTime time.Time) time.Duration {
+	local := sent.Add(received.Sub(sent) / 2)
+	offset := serverTime.Sub(local)
+	c.offset.Store(int64(offset))
+	return offset
+}
@@ -0,0 +1,31 @@
+package exchanges
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock_Measure(t *testing.T) {
+	var clock Clock
+	if clock.Offset() != 0 {
+		t.Fatalf("expected no offset on the zero clock, got %s", clock.Offset())
+	}
+
+	sent := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	received := sent.Add(200 * time.Millisecond)
+	serverTime := sent.Add(3 * time.Second)
+
+	// The server stamped its response 100ms into the round trip
+	offset := clock.Measure(sent, received, serverTime)
+	if offset != 2900*time.Millisecond {
+		t.Errorf("expected an offset of 2.9s, got %s", offset)
+	}
+	if clock.Offset() != offset {
+		t.Errorf("expected the offset to be kept, got %s", clock.Offset())
+	}
+
+	drift := clock.Now().Sub(time.Now())
+	if drift < 2800*time.Millisecond || drift > 3*time.Second {
+		t.Errorf("expected the clock to run ahead by the offset, got %s", drift)
+	}
+}
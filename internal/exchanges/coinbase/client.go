@@ -1,21 +1,20 @@
 package coinbase
 
 import (
-	"bytes"
 	"context"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
-	"io"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/guyghost/constantine/internal/exchanges"
-	"github.com/guyghost/constantine/internal/ratelimit"
+	"github.com/guyghost/constantine/internal/httpclient"
 	"github.com/guyghost/constantine/internal/telemetry"
 	"github.com/shopspring/decimal"
 )
@@ -33,28 +32,20 @@ const (
 
 // HTTPClient handles REST API requests to Coinbase
 type HTTPClient struct {
-	baseURL       string
 	apiKey        string
 	privateKeyPEM string
 	portfolioID   string
-	httpClient    *http.Client
-	rateLimiter   ratelimit.Limiter
+	client        *httpclient.Client
+	clock         exchanges.Clock // Server time JWTs are issued at
 }
 
 // NewHTTPClient creates a new HTTP client for Coinbase
 func NewHTTPClient(baseURL, apiKey, privateKeyPEM string) *HTTPClient {
-	// Create rate limiter with burst capability
 	// Using private rate limit as it's more restrictive
-	limiter := ratelimit.NewTokenBucket(coinbasePrivateRateLimit, int(coinbasePrivateRateLimit*2))
-
 	return &HTTPClient{
-		baseURL:       baseURL,
 		apiKey:        apiKey,
 		privateKeyPEM: privateKeyPEM,
-		rateLimiter:   limiter,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client:        httpclient.New(httpclient.DefaultConfig("coinbase", baseURL, coinbasePrivateRateLimit)),
 	}
 }
 
@@ -77,7 +68,7 @@ func (c *HTTPClient) createJWT(method, path, host string) (string, error) {
 	}
 
 	// Create JWT claims - using full URI as in official example
-	now := time.Now()
+	now := c.clock.Now()
 
 	// Construct URI in the format: "GET api.coinbase.com/api/v3/brokerage/accounts"
 	uri := fmt.Sprintf("%s %s%s", method, host, path)
@@ -104,76 +95,112 @@ func (c *HTTPClient) createJWT(method, path, host string) (string, error) {
 	return signedToken, nil
 }
 
-// doRequest performs an HTTP request
+// doRequest performs an HTTP request. GET requests are retried on
+// transient failures.
 func (c *HTTPClient) doRequest(ctx context.Context, method, path string, body any, result any) error {
-	// Apply rate limiting before making the request
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limit wait failed: %w", err)
-	}
+	return c.doRouteRequest(ctx, method, "", path, body, result)
+}
 
-	start := time.Now()
+// doRouteRequest performs an HTTP request to path, keying its circuit
+// breaker on route, like /brokerage/orders/historical/{id}, so paths
+// carrying identifiers share one breaker
+func (c *HTTPClient) doRouteRequest(ctx context.Context, method, route, path string, body any, result any) error {
+	endpoint := ""
+	if route != "" {
+		endpoint = method + " " + route
+	}
 
-	var reqBody io.Reader
+	var reqBody []byte
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
+		reqBody = jsonData
+	}
+
+	resp, err := c.client.Do(ctx, &httpclient.Request{
+		Method:     method,
+		Path:       path,
+		Endpoint:   endpoint,
+		Body:       reqBody,
+		Header:     requestHeader(),
+		Idempotent: method == http.MethodGet,
+		Sign: func(req *http.Request) error {
+			return c.authenticate(req, path)
+		},
+	})
+	if err != nil {
+		return apiError(err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	if result != nil {
+		if err := json.Unmarshal(resp.Body, result); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
 	}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "Constantine-Trading-Bot/1.0")
+	return nil
+}
 
-	// Add JWT authentication if API key is available
-	if c.apiKey != "" && c.privateKeyPEM != "" {
-		// Extract host from baseURL (e.g., "https://api.coinbase.com/api/v3" -> "api.coinbase.com")
-		// and construct full path including /api/v3 prefix
-		fullPath := "/api/v3" + path
-		jwt, err := c.createJWT(method, fullPath, "api.coinbase.com")
-		if err != nil {
-			return fmt.Errorf("failed to create JWT: %w", err)
-		}
+// requestHeader returns the headers of every request
+func requestHeader() http.Header {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("User-Agent", "Constantine-Trading-Bot/1.0")
+	return header
+}
 
-		req.Header.Set("Authorization", "Bearer "+jwt)
+// authenticate adds JWT authentication to req if an API key is available
+func (c *HTTPClient) authenticate(req *http.Request, path string) error {
+	if c.apiKey == "" || c.privateKeyPEM == "" {
+		return nil
 	}
 
-	resp, err := c.httpClient.Do(req)
+	// Extract host from baseURL (e.g., "https://api.coinbase.com/api/v3" -> "api.coinbase.com")
+	// and construct full path including /api/v3 prefix
+	fullPath := "/api/v3" + path
+	jwt, err := c.createJWT(req.Method, fullPath, "api.coinbase.com")
 	if err != nil {
-		telemetry.RecordAPIRequest("coinbase", path, time.Since(start))
-		return fmt.Errorf("request failed: %w", err)
+		return fmt.Errorf("failed to create JWT: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Read response body for error details
-	respBody, err := io.ReadAll(resp.Body)
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	return nil
+}
+
+// serverTimeResponse is the response of the server time endpoint
+type serverTimeResponse struct {
+	EpochMillis string `json:"epochMillis"`
+}
+
+// syncClock measures the offset to the Coinbase server time with an
+// unauthenticated request, which a skewed JWT cannot fail
+func (c *HTTPClient) syncClock(ctx context.Context) (time.Duration, error) {
+	resp, err := c.client.Do(ctx, &httpclient.Request{
+		Method:     http.MethodGet,
+		Path:       "/brokerage/time",
+		Header:     requestHeader(),
+		Idempotent: true,
+	})
 	if err != nil {
-		telemetry.RecordAPIRequest("coinbase", path, time.Since(start))
-		return fmt.Errorf("failed to read response body: %w", err)
+		return 0, err
 	}
 
-	if resp.StatusCode >= 400 {
-		telemetry.RecordAPIRequest("coinbase", path, time.Since(start))
-		return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
+	var result serverTimeResponse
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
 	}
-
-	if result != nil {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			telemetry.RecordAPIRequest("coinbase", path, time.Since(start))
-			return fmt.Errorf("failed to decode response: %w", err)
-		}
+	millis, err := strconv.ParseInt(result.EpochMillis, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid server time %q: %w", result.EpochMillis, err)
 	}
+	return c.clock.Measure(resp.Sent, resp.Received, time.UnixMilli(millis)), nil
+}
 
-	// Record successful request
-	telemetry.RecordAPIRequest("coinbase", path, time.Since(start))
-
-	return nil
+// SyncClock corrects the JWT timestamps to the Coinbase server time
+func (c *Client) SyncClock(ctx context.Context) (time.Duration, error) {
+	return c.httpClient.syncClock(ctx)
 }
 
 // Client implements the exchanges.Exchange interface for Coinbase
@@ -188,6 +215,11 @@ type Client struct {
 	ws            *WebSocketClient
 	mu            sync.RWMutex
 	httpClient    *HTTPClient
+
+	// Perpetual futures of the INTX portfolio traded instead of spot
+	perpetuals  bool
+	leverage    map[string]decimal.Decimal
+	marginModes map[string]exchanges.MarginMode
 }
 
 // NewClient creates a new Coinbase client
@@ -286,7 +318,7 @@ type CoinbaseTickerResponse struct {
 // GetTicker retrieves ticker data
 func (c *Client) GetTicker(ctx context.Context, symbol string) (*exchanges.Ticker, error) {
 	var response CoinbaseTickerResponse
-	err := c.httpClient.doRequest(ctx, "GET", "/brokerage/products/"+symbol+"/ticker", nil, &response)
+	err := c.httpClient.doRequest(ctx, "GET", "/brokerage/products/"+c.productID(symbol)+"/ticker", nil, &response)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ticker: %w", err)
 	}
@@ -330,7 +362,7 @@ type CoinbaseOrderBookResponse struct {
 // GetOrderBook retrieves order book data
 func (c *Client) GetOrderBook(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error) {
 	var response CoinbaseOrderBookResponse
-	path := fmt.Sprintf("/brokerage/product_book?product_id=%s", symbol)
+	path := fmt.Sprintf("/brokerage/product_book?product_id=%s", c.productID(symbol))
 	err := c.httpClient.doRequest(ctx, "GET", path, nil, &response)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get order book: %w", err)
@@ -423,7 +455,7 @@ func intervalToGranularity(interval string) string {
 // GetCandles retrieves OHLCV data
 func (c *Client) GetCandles(ctx context.Context, symbol string, interval string, limit int) ([]exchanges.Candle, error) {
 	granularity := intervalToGranularity(interval)
-	path := fmt.Sprintf("/brokerage/products/%s/candles?granularity=%s&limit=%d", symbol, granularity, limit)
+	path := fmt.Sprintf("/brokerage/products/%s/candles?granularity=%s&limit=%d", c.productID(symbol), granularity, limit)
 
 	var response CoinbaseCandlesResponse
 	err := c.httpClient.doRequest(ctx, "GET", path, nil, &response)
@@ -483,7 +515,10 @@ func (c *Client) SubscribeTicker(ctx context.Context, symbol string, callback fu
 	if c.ws == nil {
 		return fmt.Errorf("websocket not connected")
 	}
-	return c.ws.SubscribeTicker(ctx, symbol, callback)
+	return c.ws.SubscribeTicker(ctx, c.productID(symbol), func(ticker *exchanges.Ticker) {
+		ticker.Symbol = symbol
+		callback(ticker)
+	})
 }
 
 // SubscribeOrderBook subscribes to order book updates
@@ -491,7 +526,10 @@ func (c *Client) SubscribeOrderBook(ctx context.Context, symbol string, callback
 	if c.ws == nil {
 		return fmt.Errorf("websocket not connected")
 	}
-	return c.ws.SubscribeOrderBook(ctx, symbol, callback)
+	return c.ws.SubscribeOrderBook(ctx, c.productID(symbol), func(book *exchanges.OrderBook) {
+		book.Symbol = symbol
+		callback(book)
+	})
 }
 
 // SubscribeTrades subscribes to trade updates
@@ -499,7 +537,32 @@ func (c *Client) SubscribeTrades(ctx context.Context, symbol string, callback fu
 	if c.ws == nil {
 		return fmt.Errorf("websocket not connected")
 	}
-	return c.ws.SubscribeTrades(ctx, symbol, callback)
+	return c.ws.SubscribeTrades(ctx, c.productID(symbol), func(trade *exchanges.Trade) {
+		trade.Symbol = symbol
+		callback(trade)
+	})
+}
+
+// Streaming reports whether the websocket carries any subscription
+func (c *Client) Streaming() bool {
+	return c.ws != nil && c.ws.HasSubscriptions()
+}
+
+// LastMessageAt returns when the websocket last received a message, zero when
+// it is not connected or has received nothing yet
+func (c *Client) LastMessageAt() time.Time {
+	if c.ws == nil {
+		return time.Time{}
+	}
+	return c.ws.LastMessageAt()
+}
+
+// ReconnectStream reconnects the websocket and renews its subscriptions
+func (c *Client) ReconnectStream(ctx context.Context) error {
+	if c.ws == nil {
+		return fmt.Errorf("websocket not connected")
+	}
+	return c.ws.Reconnect(ctx)
 }
 
 // CoinbaseOrderRequest represents the request body for placing orders
@@ -507,6 +570,8 @@ type CoinbaseOrderRequest struct {
 	ClientOrderID string `json:"client_order_id"`
 	ProductID     string `json:"product_id"`
 	Side          string `json:"side"`
+	Leverage      string `json:"leverage,omitempty"`    // Perpetuals only
+	MarginType    string `json:"margin_type,omitempty"` // Perpetuals only
 	OrderConfig   struct {
 		MarketMarketIOC *struct {
 			QuoteSize string `json:"quote_size,omitempty"`
@@ -517,6 +582,9 @@ type CoinbaseOrderRequest struct {
 			LimitPrice string `json:"limit_price"`
 			PostOnly   bool   `json:"post_only,omitempty"`
 		} `json:"limit_limit_gtc,omitempty"`
+		LimitLimitGTD         *CoinbaseLimitGTD `json:"limit_limit_gtd,omitempty"`
+		SorLimitIOC           *CoinbaseLimitIOC `json:"sor_limit_ioc,omitempty"`
+		LimitLimitFOK         *CoinbaseLimitIOC `json:"limit_limit_fok,omitempty"`
 		StopLimitStopLimitGTC *struct {
 			BaseSize      string `json:"base_size"`
 			LimitPrice    string `json:"limit_price"`
@@ -526,6 +594,21 @@ type CoinbaseOrderRequest struct {
 	} `json:"order_configuration"`
 }
 
+// CoinbaseLimitGTD is the configuration of good till date limit orders
+type CoinbaseLimitGTD struct {
+	BaseSize   string `json:"base_size"`
+	LimitPrice string `json:"limit_price"`
+	EndTime    string `json:"end_time"`
+	PostOnly   bool   `json:"post_only,omitempty"`
+}
+
+// CoinbaseLimitIOC is the configuration of immediate or cancel and fill or
+// kill limit orders
+type CoinbaseLimitIOC struct {
+	BaseSize   string `json:"base_size"`
+	LimitPrice string `json:"limit_price"`
+}
+
 // CoinbaseOrderResponse represents the API response for order operations
 type CoinbaseOrderResponse struct {
 	Success      bool   `json:"success"`
@@ -558,6 +641,7 @@ type CoinbaseOrderResponse struct {
 			} `json:"limit_limit_gtc,omitempty"`
 		} `json:"order_configuration"`
 	} `json:"order"`
+	Failure errorResponse `json:"error_response"` // Why the order failed
 }
 
 // CoinbaseListOrdersResponse represents response for listing orders
@@ -604,12 +688,26 @@ type CoinbaseListOrdersResponse struct {
 
 // PlaceOrder places a new order
 func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*exchanges.Order, error) {
+	// Reuse the caller's client order ID so resubmissions are deduplicated
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = uuid.New().String()
+	}
+
 	// Build request
 	req := CoinbaseOrderRequest{
-		ClientOrderID: uuid.New().String(),
-		ProductID:     order.Symbol,
+		ClientOrderID: order.ClientOrderID,
+		ProductID:     c.productID(order.Symbol),
 		Side:          mapOrderSideToString(order.Side),
 	}
+	if c.perpetuals {
+		c.mu.RLock()
+		leverage, ok := c.leverage[order.Symbol]
+		req.MarginType = marginType(c.marginModes[order.Symbol])
+		c.mu.RUnlock()
+		if ok {
+			req.Leverage = leverage.String()
+		}
+	}
 
 	// Configure order type
 	switch order.Type {
@@ -621,13 +719,37 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 			BaseSize: order.Amount.String(),
 		}
 	case exchanges.OrderTypeLimit:
-		req.OrderConfig.LimitLimitGTC = &struct {
-			BaseSize   string `json:"base_size"`
-			LimitPrice string `json:"limit_price"`
-			PostOnly   bool   `json:"post_only,omitempty"`
-		}{
-			BaseSize:   order.Amount.String(),
-			LimitPrice: order.Price.String(),
+		if err := exchanges.ValidateTimeInForce(order.TimeInForce, order.PostOnly, order.ExpiresAt); err != nil {
+			return nil, err
+		}
+		switch order.TimeInForce {
+		case exchanges.TimeInForceGTD:
+			req.OrderConfig.LimitLimitGTD = &CoinbaseLimitGTD{
+				BaseSize:   order.Amount.String(),
+				LimitPrice: order.Price.String(),
+				EndTime:    order.ExpiresAt.UTC().Format(time.RFC3339),
+				PostOnly:   order.PostOnly,
+			}
+		case exchanges.TimeInForceIOC:
+			req.OrderConfig.SorLimitIOC = &CoinbaseLimitIOC{
+				BaseSize:   order.Amount.String(),
+				LimitPrice: order.Price.String(),
+			}
+		case exchanges.TimeInForceFOK:
+			req.OrderConfig.LimitLimitFOK = &CoinbaseLimitIOC{
+				BaseSize:   order.Amount.String(),
+				LimitPrice: order.Price.String(),
+			}
+		default:
+			req.OrderConfig.LimitLimitGTC = &struct {
+				BaseSize   string `json:"base_size"`
+				LimitPrice string `json:"limit_price"`
+				PostOnly   bool   `json:"post_only,omitempty"`
+			}{
+				BaseSize:   order.Amount.String(),
+				LimitPrice: order.Price.String(),
+				PostOnly:   order.PostOnly,
+			}
 		}
 	case exchanges.OrderTypeStopLimit:
 		if order.StopPrice.IsZero() {
@@ -648,8 +770,10 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 			StopPrice:     order.StopPrice.String(),
 			StopDirection: stopDirection,
 		}
+	case exchanges.OrderTypeStopMarket:
+		return nil, fmt.Errorf("%w: coinbase only has stop limit orders", exchanges.ErrNotSupported)
 	default:
-		return nil, fmt.Errorf("unsupported order type: %s", order.Type)
+		return nil, fmt.Errorf("%w: order type %s", exchanges.ErrNotSupported, order.Type)
 	}
 
 	// Make API request
@@ -660,7 +784,11 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 	}
 
 	if !response.Success {
-		return nil, fmt.Errorf("order placement failed: %s", response.ErrorMessage)
+		message := response.ErrorMessage
+		if message == "" {
+			message = response.Failure.Message
+		}
+		return nil, exchanges.NewError("coinbase", response.Failure.code(), "order placement failed: "+message, nil)
 	}
 
 	// Parse response
@@ -740,7 +868,8 @@ func (c *Client) CancelOrder(ctx context.Context, orderID string) error {
 	}
 
 	if !response.Success {
-		return fmt.Errorf("cancel order failed: %s", response.Message)
+		code := exchanges.ClassifyMessage(response.Message, exchanges.ErrorCodeRejected)
+		return exchanges.NewError("coinbase", code, "cancel order failed: "+response.Message, nil)
 	}
 
 	return nil
@@ -781,18 +910,19 @@ func (c *Client) GetOrder(ctx context.Context, orderID string) (*exchanges.Order
 
 	var response GetOrderResponse
 	path := fmt.Sprintf("/brokerage/orders/historical/%s", orderID)
-	err := c.httpClient.doRequest(ctx, "GET", path, nil, &response)
+	err := c.httpClient.doRouteRequest(ctx, "GET", "/brokerage/orders/historical/{id}", path, nil, &response)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
 
 	// Parse order
 	order := &exchanges.Order{
-		ID:        response.Order.OrderID,
-		Symbol:    response.Order.ProductID,
-		Status:    mapCoinbaseStatus(response.Order.Status),
-		CreatedAt: parseTimeString(response.Order.CreatedTime),
-		UpdatedAt: time.Now(),
+		ID:            response.Order.OrderID,
+		ClientOrderID: response.Order.ClientOrderID,
+		Symbol:        c.symbolOf(response.Order.ProductID),
+		Status:        mapCoinbaseStatus(response.Order.Status),
+		CreatedAt:     parseTimeString(response.Order.CreatedTime),
+		UpdatedAt:     time.Now(),
 	}
 
 	// Parse side
@@ -838,7 +968,7 @@ func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]exchanges.
 	// Add query parameters for open orders
 	queryParams := "?order_status=OPEN"
 	if symbol != "" {
-		queryParams += "&product_id=" + symbol
+		queryParams += "&product_id=" + c.productID(symbol)
 	}
 
 	err := c.httpClient.doRequest(ctx, "GET", path+queryParams, nil, &response)
@@ -849,11 +979,12 @@ func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]exchanges.
 	orders := make([]exchanges.Order, 0, len(response.Orders))
 	for _, cbOrder := range response.Orders {
 		order := exchanges.Order{
-			ID:        cbOrder.OrderID,
-			Symbol:    cbOrder.ProductID,
-			Status:    mapCoinbaseStatus(cbOrder.Status),
-			CreatedAt: parseTimeString(cbOrder.CreatedTime),
-			UpdatedAt: time.Now(),
+			ID:            cbOrder.OrderID,
+			ClientOrderID: cbOrder.ClientOrderID,
+			Symbol:        c.symbolOf(cbOrder.ProductID),
+			Status:        mapCoinbaseStatus(cbOrder.Status),
+			CreatedAt:     parseTimeString(cbOrder.CreatedTime),
+			UpdatedAt:     time.Now(),
 		}
 
 		// Parse side
@@ -897,7 +1028,7 @@ func (c *Client) GetOrderHistory(ctx context.Context, symbol string, limit int)
 	// Add query parameters for historical orders (all statuses except OPEN)
 	queryParams := fmt.Sprintf("?limit=%d", limit)
 	if symbol != "" {
-		queryParams += "&product_id=" + symbol
+		queryParams += "&product_id=" + c.productID(symbol)
 	}
 
 	// Get all orders (FILLED, CANCELLED, EXPIRED, etc.) - exclude OPEN which is handled by GetOpenOrders
@@ -916,11 +1047,12 @@ func (c *Client) GetOrderHistory(ctx context.Context, symbol string, limit int)
 		}
 
 		order := exchanges.Order{
-			ID:        cbOrder.OrderID,
-			Symbol:    cbOrder.ProductID,
-			Status:    mapCoinbaseStatus(cbOrder.Status),
-			CreatedAt: parseTimeString(cbOrder.CreatedTime),
-			UpdatedAt: time.Now(),
+			ID:            cbOrder.OrderID,
+			ClientOrderID: cbOrder.ClientOrderID,
+			Symbol:        c.symbolOf(cbOrder.ProductID),
+			Status:        mapCoinbaseStatus(cbOrder.Status),
+			CreatedAt:     parseTimeString(cbOrder.CreatedTime),
+			UpdatedAt:     time.Now(),
 		}
 
 		// Parse side
@@ -961,6 +1093,31 @@ func (c *Client) GetOrderHistory(ctx context.Context, symbol string, limit int)
 	return orders, nil
 }
 
+// GetOrderByClientID finds an order by the client order ID it was placed with
+func (c *Client) GetOrderByClientID(ctx context.Context, symbol string, clientOrderID string) (*exchanges.Order, error) {
+	openOrders, err := c.GetOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	for i := range openOrders {
+		if openOrders[i].ClientOrderID == clientOrderID {
+			return &openOrders[i], nil
+		}
+	}
+
+	history, err := c.GetOrderHistory(ctx, symbol, 100)
+	if err != nil {
+		return nil, err
+	}
+	for i := range history {
+		if history[i].ClientOrderID == clientOrderID {
+			return &history[i], nil
+		}
+	}
+
+	return nil, exchanges.ErrOrderNotFound
+}
+
 // CoinbaseAccountsResponse represents the response from Coinbase accounts API
 type CoinbaseAccountsResponse struct {
 	Accounts []struct {
@@ -1025,7 +1182,29 @@ func (c *Client) GetBalance(ctx context.Context) ([]exchanges.Balance, error) {
 
 // GetPositions retrieves all open positions
 func (c *Client) GetPositions(ctx context.Context) ([]exchanges.Position, error) {
-	// For Coinbase spot trading, positions are just non-zero balances
+	getPositions := c.spotPositions
+	if c.perpetuals {
+		getPositions = c.getPerpetualPositions
+	}
+	positions, err := getPositions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Record position metrics
+	for _, position := range positions {
+		telemetry.RecordPositionUpdate(position.Symbol, "size", position.Size.InexactFloat64())
+		telemetry.RecordPositionUpdate(position.Symbol, "unrealized_pnl", position.UnrealizedPnL.InexactFloat64())
+		telemetry.RecordPositionUpdate(position.Symbol, "entry_price", position.EntryPrice.InexactFloat64())
+		telemetry.RecordPositionUpdate(position.Symbol, "mark_price", position.MarkPrice.InexactFloat64())
+		telemetry.RecordPnLUpdate(position.Symbol, position.UnrealizedPnL.InexactFloat64())
+	}
+
+	return positions, nil
+}
+
+// spotPositions derives positions from the non-zero balances of a spot account
+func (c *Client) spotPositions(ctx context.Context) ([]exchanges.Position, error) {
 	balances, err := c.GetBalance(ctx)
 	if err != nil {
 		return nil, err
@@ -1047,16 +1226,6 @@ func (c *Client) GetPositions(ctx context.Context) ([]exchanges.Position, error)
 			})
 		}
 	}
-
-	// Record position metrics
-	for _, position := range positions {
-		telemetry.RecordPositionUpdate(position.Symbol, "size", position.Size.InexactFloat64())
-		telemetry.RecordPositionUpdate(position.Symbol, "unrealized_pnl", position.UnrealizedPnL.InexactFloat64())
-		telemetry.RecordPositionUpdate(position.Symbol, "entry_price", position.EntryPrice.InexactFloat64())
-		telemetry.RecordPositionUpdate(position.Symbol, "mark_price", position.MarkPrice.InexactFloat64())
-		telemetry.RecordPnLUpdate(position.Symbol, position.UnrealizedPnL.InexactFloat64())
-	}
-
 	return positions, nil
 }
 
@@ -1085,6 +1254,101 @@ func (c *Client) SupportedSymbols() []string {
 	return []string{"BTC-USD", "ETH-USD", "SOL-USD", "LINK-USD"}
 }
 
+// Capabilities returns the features of the client. Spot trading cannot open
+// shorts or use margin, and has neither stop market nor reduce-only orders;
+// perpetuals can be shorted on margin.
+func (c *Client) Capabilities() exchanges.Capabilities {
+	return exchanges.Capabilities{
+		Shorts:     c.perpetuals,
+		StopOrders: true,
+		PostOnly:   true,
+		Margin:     c.perpetuals,
+	}
+}
+
+// SetLeverage records the leverage orders on the perpetual of symbol are
+// sent with, checked against its limit. Spot accepts only 1x.
+func (c *Client) SetLeverage(ctx context.Context, symbol string, leverage decimal.Decimal) error {
+	if !c.perpetuals {
+		if !leverage.Equal(decimal.NewFromInt(1)) {
+			return fmt.Errorf("%w: coinbase spot trading has no leverage", exchanges.ErrNotSupported)
+		}
+		return nil
+	}
+
+	if !leverage.IsPositive() {
+		return fmt.Errorf("%w: leverage must be positive, got %s", exchanges.ErrInvalidOrder, leverage)
+	}
+	details, err := c.getPerpetualDetails(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	if limit, err := decimal.NewFromString(details.MaxLeverage); err == nil && leverage.GreaterThan(limit) {
+		return fmt.Errorf("%w: %s allows at most %sx leverage, got %sx",
+			exchanges.ErrInvalidOrder, symbol, limit, leverage)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.leverage == nil {
+		c.leverage = make(map[string]decimal.Decimal)
+	}
+	c.leverage[symbol] = leverage
+	return nil
+}
+
+// SetMarginMode records the margin type orders on the perpetual of symbol
+// are sent with. Spot trading has no margin.
+func (c *Client) SetMarginMode(ctx context.Context, symbol string, mode exchanges.MarginMode) error {
+	if !c.perpetuals {
+		return fmt.Errorf("%w: coinbase spot trading has no margin", exchanges.ErrNotSupported)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.marginModes == nil {
+		c.marginModes = make(map[string]exchanges.MarginMode)
+	}
+	c.marginModes[symbol] = mode
+	return nil
+}
+
+// GetLeverage returns the leverage and margin mode recorded for the
+// perpetual of symbol, 1x cross when none was set, and its limit. Spot
+// trading is always 1x.
+func (c *Client) GetLeverage(ctx context.Context, symbol string) (*exchanges.LeverageSettings, error) {
+	if !c.perpetuals {
+		return &exchanges.LeverageSettings{
+			Symbol:   symbol,
+			Leverage: decimal.NewFromInt(1),
+			Max:      decimal.NewFromInt(1),
+		}, nil
+	}
+
+	details, err := c.getPerpetualDetails(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	limit, _ := decimal.NewFromString(details.MaxLeverage)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	leverage, ok := c.leverage[symbol]
+	if !ok {
+		leverage = decimal.NewFromInt(1)
+	}
+	mode, ok := c.marginModes[symbol]
+	if !ok {
+		mode = exchanges.MarginModeCross
+	}
+	return &exchanges.LeverageSettings{
+		Symbol:     symbol,
+		Leverage:   leverage,
+		Max:        limit,
+		MarginMode: mode,
+	}, nil
+}
+
 // SubscribeCandles subscribes to candle updates (using periodic REST API calls)
 func (c *Client) SubscribeCandles(ctx context.Context, symbol string, interval string, callback func(*exchanges.Candle)) error {
 	// Coinbase doesn't provide real-time candle streams via WebSocket
@@ -2,7 +2,11 @@ package coinbase
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/shopspring/decimal"
@@ -155,6 +159,24 @@ func TestPlaceOrder(t *testing.T) {
 	}
 }
 
+func TestPlaceOrder_InvalidTimeInForce(t *testing.T) {
+	client := NewClient("", "")
+
+	order := &exchanges.Order{
+		Symbol:      "BTC-USD",
+		Side:        exchanges.OrderSideBuy,
+		Type:        exchanges.OrderTypeLimit,
+		Price:       decimal.NewFromFloat(50000),
+		Amount:      decimal.NewFromFloat(0.01),
+		TimeInForce: exchanges.TimeInForceIOC,
+		PostOnly:    true,
+	}
+
+	if _, err := client.PlaceOrder(context.Background(), order); err == nil {
+		t.Error("expected post-only IOC orders to be rejected before submission")
+	}
+}
+
 func TestGetOpenOrders(t *testing.T) {
 	// Skip this test as it requires real API credentials
 	t.Skip("GetOpenOrders requires real API credentials and will make actual API calls")
@@ -178,3 +200,111 @@ func TestGetOpenOrders(t *testing.T) {
 		}
 	}
 }
+
+func TestSyncClock(t *testing.T) {
+	serverTime := time.Now().Add(5 * time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/brokerage/time" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("expected the time request to be unauthenticated")
+		}
+		fmt.Fprintf(w, `{"epochMillis":"%d"}`, serverTime.UnixMilli())
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL("test_key", "test_private_key_pem", server.URL, "")
+	offset, err := client.SyncClock(context.Background())
+	if err != nil {
+		t.Fatalf("SyncClock failed: %v", err)
+	}
+	if offset < 4*time.Second || offset > 6*time.Second {
+		t.Errorf("expected an offset of about 5s, got %s", offset)
+	}
+	if client.httpClient.clock.Offset() != offset {
+		t.Error("expected JWTs to be issued at the corrected time")
+	}
+}
+
+func TestPlaceOrderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   exchanges.ErrorCode
+	}{
+		{
+			name:   "refused order",
+			status: http.StatusOK,
+			body:   `{"success":false,"error_response":{"error":"INSUFFICIENT_FUND","message":"Insufficient balance in source account"}}`,
+			want:   exchanges.ErrorCodeInsufficientFunds,
+		},
+		{
+			name:   "error response",
+			status: http.StatusBadRequest,
+			body:   `{"error":"INVALID_ARGUMENT","message":"Invalid product_id"}`,
+			want:   exchanges.ErrorCodeInvalidOrder,
+		},
+		{
+			name:   "unauthorized",
+			status: http.StatusUnauthorized,
+			body:   `{"error":"UNAUTHENTICATED"}`,
+			want:   exchanges.ErrorCodeAuth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := NewClientWithURL("", "", server.URL, "")
+			_, err := client.PlaceOrder(context.Background(), &exchanges.Order{
+				Symbol: "BTC-USD",
+				Side:   exchanges.OrderSideBuy,
+				Type:   exchanges.OrderTypeLimit,
+				Price:  decimal.NewFromInt(50000),
+				Amount: decimal.NewFromFloat(0.1),
+			})
+			if code := exchanges.CodeOf(err); code != tt.want {
+				t.Errorf("expected code %q, got %q (%v)", tt.want, code, err)
+			}
+		})
+	}
+}
+
+func TestConvertCoinbaseFill(t *testing.T) {
+	fill, ok := convertCoinbaseFill(CoinbaseFill{
+		EntryID:            "entry-1",
+		TradeID:            "trade-1",
+		OrderID:            "order-1",
+		TradeTime:          "2024-01-02T03:04:05Z",
+		Price:              "50000",
+		Size:               "1000",
+		Commission:         "6",
+		ProductID:          "BTC-USD",
+		Side:               "BUY",
+		LiquidityIndicator: "TAKER",
+		SizeInQuote:        true,
+	})
+	if !ok {
+		t.Fatal("valid fill was rejected")
+	}
+	if fill.ID != "entry-1" || fill.OrderID != "order-1" {
+		t.Errorf("unexpected identifiers: %+v", fill)
+	}
+	if !fill.Amount.Equal(decimal.NewFromFloat(0.02)) {
+		t.Errorf("expected a size in quote converted to 0.02, got %s", fill.Amount)
+	}
+	if !fill.Fee.Equal(decimal.NewFromInt(6)) || fill.Maker || fill.Side != exchanges.OrderSideBuy {
+		t.Errorf("unexpected fee, liquidity or side: %+v", fill)
+	}
+
+	if _, ok := convertCoinbaseFill(CoinbaseFill{Price: "0", Size: "1"}); ok {
+		t.Error("fill without a price should be rejected")
+	}
+}
@@ -0,0 +1,46 @@
+package coinbase
+
+import (
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+func init() {
+	exchanges.RegisterConnector("coinbase", exchanges.Connector{
+		LoadConfig: loadConfig,
+		New:        newFromConfig,
+	})
+}
+
+// loadConfig reads the Coinbase settings from the environment
+func loadConfig(getenv func(string) string) (exchanges.Config, error) {
+	cfg := exchanges.Config{
+		Enabled:     getenv("ENABLE_COINBASE") == "true",
+		APIKey:      getenv("COINBASE_API_KEY"),
+		APISecret:   getenv("COINBASE_API_SECRET"),
+		PortfolioID: getenv("COINBASE_PORTFOLIO_ID"),
+		Perpetuals:  getenv("COINBASE_PERPETUALS") == "true",
+	}
+	if !cfg.Enabled {
+		return cfg, nil
+	}
+	if cfg.APIKey == "" || cfg.APISecret == "" {
+		return cfg, fmt.Errorf("coinbase enabled but API key or secret is missing")
+	}
+	if cfg.Perpetuals && cfg.PortfolioID == "" {
+		return cfg, fmt.Errorf("coinbase perpetuals enabled but COINBASE_PORTFOLIO_ID, the INTX portfolio, is missing")
+	}
+	return cfg, nil
+}
+
+// newFromConfig creates the spot or perpetuals client of the configuration
+func newFromConfig(cfg exchanges.Config) (exchanges.Exchange, error) {
+	if cfg.Perpetuals {
+		return NewPerpetualsClient(cfg.APIKey, cfg.APISecret, cfg.PortfolioID), nil
+	}
+	if cfg.PortfolioID != "" {
+		return NewClientWithPortfolio(cfg.APIKey, cfg.APISecret, cfg.PortfolioID), nil
+	}
+	return NewClient(cfg.APIKey, cfg.APISecret), nil
+}
@@ -0,0 +1,34 @@
+package coinbase
+
+import (
+	"encoding/json"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// errorResponse describes a refused request, either as the body of an HTTP
+// error or in the error_response of an order that failed
+type errorResponse struct {
+	Error                string `json:"error"` // e.g. INSUFFICIENT_FUND
+	Message              string `json:"message"`
+	PreviewFailureReason string `json:"preview_failure_reason"`
+}
+
+// code classifies the error from its identifiers and message
+func (r errorResponse) code() exchanges.ErrorCode {
+	return exchanges.ClassifyMessage(r.Error+" "+r.PreviewFailureReason+" "+r.Message, exchanges.ErrorCodeRejected)
+}
+
+// classifyError classifies the body of a Coinbase error response
+func classifyError(body string) exchanges.ErrorCode {
+	var resp errorResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return exchanges.ClassifyMessage(body, exchanges.ErrorCodeRejected)
+	}
+	return resp.code()
+}
+
+// apiError classifies an error of a request to the Coinbase API
+func apiError(err error) error {
+	return exchanges.HTTPError("coinbase", err, classifyError)
+}
@@ -0,0 +1,98 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// CoinbaseFill is a fill of the account, as listed by the historical fills
+// endpoint
+type CoinbaseFill struct {
+	EntryID            string `json:"entry_id"`
+	TradeID            string `json:"trade_id"`
+	OrderID            string `json:"order_id"`
+	TradeTime          string `json:"trade_time"`
+	Price              string `json:"price"`
+	Size               string `json:"size"`
+	Commission         string `json:"commission"`
+	ProductID          string `json:"product_id"`
+	Side               string `json:"side"`
+	LiquidityIndicator string `json:"liquidity_indicator"` // MAKER or TAKER
+	SizeInQuote        bool   `json:"size_in_quote"`
+}
+
+// CoinbaseFillsResponse represents the response of the historical fills
+// endpoint
+type CoinbaseFillsResponse struct {
+	Fills  []CoinbaseFill `json:"fills"`
+	Cursor string         `json:"cursor"`
+}
+
+// GetFills returns the fills of the account since the given time
+func (c *Client) GetFills(ctx context.Context, symbol string, since time.Time) ([]exchanges.Trade, error) {
+	query := url.Values{}
+	query.Set("start_sequence_timestamp", since.UTC().Format(time.RFC3339))
+	if symbol != "" {
+		query.Set("product_id", c.productID(symbol))
+	}
+
+	var fills []exchanges.Trade
+	for {
+		var response CoinbaseFillsResponse
+		if err := c.httpClient.doRequest(ctx, "GET", "/brokerage/orders/historical/fills?"+query.Encode(), nil, &response); err != nil {
+			return nil, fmt.Errorf("failed to get fills: %w", err)
+		}
+		for _, f := range response.Fills {
+			if fill, ok := convertCoinbaseFill(f); ok {
+				fill.Symbol = c.symbolOf(fill.Symbol)
+				fills = append(fills, fill)
+			}
+		}
+		if response.Cursor == "" || len(response.Fills) == 0 {
+			return fills, nil
+		}
+		query.Set("cursor", response.Cursor)
+	}
+}
+
+// convertCoinbaseFill converts a fill, reporting false when its price or
+// size is invalid
+func convertCoinbaseFill(f CoinbaseFill) (exchanges.Trade, bool) {
+	price, err := decimal.NewFromString(f.Price)
+	if err != nil || !price.IsPositive() {
+		return exchanges.Trade{}, false
+	}
+	size, err := decimal.NewFromString(f.Size)
+	if err != nil {
+		return exchanges.Trade{}, false
+	}
+	if f.SizeInQuote {
+		size = size.Div(price)
+	}
+	fee, _ := decimal.NewFromString(f.Commission)
+
+	side := exchanges.OrderSideSell
+	if f.Side == "BUY" {
+		side = exchanges.OrderSideBuy
+	}
+	id := f.EntryID
+	if id == "" {
+		id = f.TradeID
+	}
+	return exchanges.Trade{
+		ID:        id,
+		OrderID:   f.OrderID,
+		Symbol:    f.ProductID,
+		Side:      side,
+		Price:     price,
+		Amount:    size,
+		Fee:       fee,
+		Timestamp: parseTimeString(f.TradeTime),
+		Maker:     f.LiquidityIndicator == "MAKER",
+	}, true
+}
@@ -0,0 +1,213 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// Coinbase International Exchange (INTX) lists perpetual futures, traded
+// through the same Advanced Trade endpoints as spot from an INTX portfolio.
+// A perpetuals client trades BTC-PERP-INTX for BTC-USD, so the rest of the
+// bot keeps its symbols, and reports the portfolio's real positions,
+// leverage and funding instead of deriving positions from balances.
+
+// intxProductSuffix turns the base of a symbol into its perpetual product
+const intxProductSuffix = "-PERP-INTX"
+
+// NewPerpetualsClient creates a Coinbase client trading the perpetual
+// futures of the INTX portfolio portfolioID
+func NewPerpetualsClient(apiKey, privateKeyPEM, portfolioID string) *Client {
+	c := NewClientWithPortfolio(apiKey, privateKeyPEM, portfolioID)
+	c.perpetuals = true
+	return c
+}
+
+// NewPerpetualsClientWithURL creates a Coinbase perpetuals client with
+// custom URLs
+func NewPerpetualsClientWithURL(apiKey, privateKeyPEM, portfolioID, baseURL, wsURL string) *Client {
+	c := NewClientWithPortfolioAndURL(apiKey, privateKeyPEM, portfolioID, baseURL, wsURL)
+	c.perpetuals = true
+	return c
+}
+
+// productID returns the product traded for symbol: its perpetual on a
+// perpetuals client, symbol itself on spot
+func (c *Client) productID(symbol string) string {
+	if !c.perpetuals || strings.HasSuffix(symbol, intxProductSuffix) {
+		return symbol
+	}
+	base, _, _ := strings.Cut(symbol, "-")
+	return base + intxProductSuffix
+}
+
+// symbolOf returns the symbol of a product, the inverse of productID
+func (c *Client) symbolOf(productID string) string {
+	if base, ok := strings.CutSuffix(productID, intxProductSuffix); ok {
+		return base + "-USD"
+	}
+	return productID
+}
+
+// amount is a monetary amount as Coinbase reports it
+type amount struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+func (a amount) decimal() decimal.Decimal {
+	value, _ := decimal.NewFromString(a.Value)
+	return value
+}
+
+// intxPosition is a perpetual position of an INTX portfolio
+type intxPosition struct {
+	ProductID        string `json:"product_id"`
+	PositionSide     string `json:"position_side"` // POSITION_SIDE_LONG or POSITION_SIDE_SHORT
+	NetSize          string `json:"net_size"`
+	Leverage         string `json:"leverage"`
+	EntryVWAP        amount `json:"entry_vwap"`
+	MarkPrice        amount `json:"mark_price"`
+	UnrealizedPnL    amount `json:"unrealized_pnl"`
+	LiquidationPrice amount `json:"liquidation_price"`
+	MarginType       string `json:"margin_type"` // MARGIN_TYPE_CROSS or MARGIN_TYPE_ISOLATED
+}
+
+// position converts p, reporting false when it is flat
+func (c *Client) position(p intxPosition) (exchanges.Position, bool) {
+	size, err := decimal.NewFromString(p.NetSize)
+	if err != nil || size.IsZero() {
+		return exchanges.Position{}, false
+	}
+	side := exchanges.OrderSideBuy
+	if p.PositionSide == "POSITION_SIDE_SHORT" || size.IsNegative() {
+		side = exchanges.OrderSideSell
+	}
+	leverage, _ := decimal.NewFromString(p.Leverage)
+	return exchanges.Position{
+		Symbol:           c.symbolOf(p.ProductID),
+		Side:             side,
+		Size:             size.Abs(),
+		EntryPrice:       p.EntryVWAP.decimal(),
+		MarkPrice:        p.MarkPrice.decimal(),
+		Leverage:         leverage,
+		UnrealizedPnL:    p.UnrealizedPnL.decimal(),
+		LiquidationPrice: p.LiquidationPrice.decimal(),
+	}, true
+}
+
+// getPerpetualPositions returns the open positions of the INTX portfolio
+func (c *Client) getPerpetualPositions(ctx context.Context) ([]exchanges.Position, error) {
+	var response struct {
+		Positions []intxPosition `json:"positions"`
+	}
+	if err := c.httpClient.doRequest(ctx, "GET", "/brokerage/intx/positions/"+c.portfolioID, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get positions: %w", err)
+	}
+
+	positions := make([]exchanges.Position, 0, len(response.Positions))
+	for _, p := range response.Positions {
+		if position, ok := c.position(p); ok {
+			positions = append(positions, position)
+		}
+	}
+	return positions, nil
+}
+
+// GetAccountMargin returns the collateral and margin usage of the INTX
+// portfolio. Spot accounts have no margin, so theirs is estimated from
+// balances.
+func (c *Client) GetAccountMargin(ctx context.Context) (*exchanges.AccountMargin, error) {
+	if !c.perpetuals {
+		balances, err := c.GetBalance(ctx)
+		if err != nil {
+			return nil, err
+		}
+		margin := exchanges.EstimateAccountMargin(balances, nil)
+		return &margin, nil
+	}
+
+	var response struct {
+		Summary struct {
+			TotalBalance        amount `json:"total_balance"`
+			BuyingPower         amount `json:"buying_power"`
+			MaxWithdrawalAmount amount `json:"max_withdrawal_amount"`
+		} `json:"summary"`
+		Portfolios []struct {
+			InitialMarginNotional amount `json:"portfolio_im_notional"`
+			PositionNotional      amount `json:"position_notional"`
+		} `json:"portfolios"`
+	}
+	if err := c.httpClient.doRequest(ctx, "GET", "/brokerage/intx/portfolio/"+c.portfolioID, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+
+	margin := &exchanges.AccountMargin{
+		Equity:         response.Summary.TotalBalance.decimal(),
+		FreeCollateral: response.Summary.BuyingPower.decimal(),
+		Withdrawable:   response.Summary.MaxWithdrawalAmount.decimal(),
+		UpdatedAt:      time.Now(),
+	}
+	notional := decimal.Zero
+	for _, portfolio := range response.Portfolios {
+		margin.MarginUsed = margin.MarginUsed.Add(portfolio.InitialMarginNotional.decimal())
+		notional = notional.Add(portfolio.PositionNotional.decimal())
+	}
+	if margin.Equity.IsPositive() {
+		margin.Leverage = notional.Div(margin.Equity)
+	}
+	return margin, nil
+}
+
+// perpetualDetails are the contract details of a perpetual product
+type perpetualDetails struct {
+	FundingRate  string `json:"funding_rate"`
+	FundingTime  string `json:"funding_time"`
+	MaxLeverage  string `json:"max_leverage"`
+	OpenInterest string `json:"open_interest"`
+}
+
+// getPerpetualDetails returns the contract details of the perpetual of symbol
+func (c *Client) getPerpetualDetails(ctx context.Context, symbol string) (*perpetualDetails, error) {
+	if !c.perpetuals {
+		return nil, fmt.Errorf("%w: coinbase spot products have no funding", exchanges.ErrNotSupported)
+	}
+	var product struct {
+		FutureProductDetails struct {
+			PerpetualDetails perpetualDetails `json:"perpetual_details"`
+		} `json:"future_product_details"`
+	}
+	if err := c.httpClient.doRequest(ctx, "GET", "/brokerage/products/"+c.productID(symbol), nil, &product); err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	return &product.FutureProductDetails.PerpetualDetails, nil
+}
+
+// GetFundingRate returns the funding rate of the perpetual of symbol
+func (c *Client) GetFundingRate(ctx context.Context, symbol string) (*exchanges.FundingRate, error) {
+	details, err := c.getPerpetualDetails(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := decimal.NewFromString(details.FundingRate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse funding rate %q: %w", details.FundingRate, err)
+	}
+	funding := &exchanges.FundingRate{Symbol: symbol, Rate: rate}
+	if details.FundingTime != "" {
+		funding.NextFundingAt = parseTimeString(details.FundingTime)
+	}
+	return funding, nil
+}
+
+// marginType returns the margin type of an INTX order in mode
+func marginType(mode exchanges.MarginMode) string {
+	if mode == exchanges.MarginModeIsolated {
+		return "ISOLATED"
+	}
+	return "CROSS"
+}
@@ -0,0 +1,116 @@
+package coinbase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func TestPerpetuals(t *testing.T) {
+	var placed CoinbaseOrderRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/brokerage/intx/positions/portfolio-1":
+			w.Write([]byte(`{"positions":[
+				{"product_id":"BTC-PERP-INTX","position_side":"POSITION_SIDE_SHORT","net_size":"-0.5","leverage":"3",
+				 "entry_vwap":{"value":"50000","currency":"USDC"},"mark_price":{"value":"49000","currency":"USDC"},
+				 "unrealized_pnl":{"value":"500","currency":"USDC"},"liquidation_price":{"value":"60000","currency":"USDC"}},
+				{"product_id":"ETH-PERP-INTX","position_side":"POSITION_SIDE_LONG","net_size":"0"}
+			]}`))
+		case "/brokerage/intx/portfolio/portfolio-1":
+			w.Write([]byte(`{"summary":{"total_balance":{"value":"10000"},"buying_power":{"value":"8000"},"max_withdrawal_amount":{"value":"7000"}},
+				"portfolios":[{"portfolio_im_notional":{"value":"2000"},"position_notional":{"value":"24500"}}]}`))
+		case "/brokerage/products/BTC-PERP-INTX":
+			w.Write([]byte(`{"product_id":"BTC-PERP-INTX","future_product_details":{"perpetual_details":
+				{"funding_rate":"0.0001","funding_time":"2024-01-01T01:00:00Z","max_leverage":"10","open_interest":"1200"}}}`))
+		case "/brokerage/orders":
+			json.NewDecoder(r.Body).Decode(&placed)
+			w.Write([]byte(`{"success":true,"order_id":"order-1"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := NewPerpetualsClientWithURL("", "", "portfolio-1", server.URL, "")
+	ctx := context.Background()
+	if caps := client.Capabilities(); !caps.Shorts || !caps.Margin {
+		t.Errorf("expected perpetuals to be shortable on margin, got %+v", caps)
+	}
+
+	positions, err := client.GetPositions(ctx)
+	if err != nil {
+		t.Fatalf("GetPositions failed: %v", err)
+	}
+	if len(positions) != 1 {
+		t.Fatalf("expected the open position only, got %+v", positions)
+	}
+	position := positions[0]
+	if position.Symbol != "BTC-USD" || position.Side != exchanges.OrderSideSell || !position.Size.Equal(decimal.NewFromFloat(0.5)) ||
+		!position.Leverage.Equal(decimal.NewFromInt(3)) || !position.MarkPrice.Equal(decimal.NewFromInt(49000)) {
+		t.Errorf("unexpected position %+v", position)
+	}
+
+	margin, err := client.GetAccountMargin(ctx)
+	if err != nil {
+		t.Fatalf("GetAccountMargin failed: %v", err)
+	}
+	if !margin.Equity.Equal(decimal.NewFromInt(10000)) || !margin.MarginUsed.Equal(decimal.NewFromInt(2000)) ||
+		!margin.Leverage.Equal(decimal.NewFromFloat(2.45)) {
+		t.Errorf("unexpected margin %+v", margin)
+	}
+
+	funding, err := client.GetFundingRate(ctx, "BTC-USD")
+	if err != nil {
+		t.Fatalf("GetFundingRate failed: %v", err)
+	}
+	if !funding.Rate.Equal(decimal.NewFromFloat(0.0001)) || funding.NextFundingAt.IsZero() {
+		t.Errorf("unexpected funding %+v", funding)
+	}
+
+	if err := client.SetLeverage(ctx, "BTC-USD", decimal.NewFromInt(20)); !errors.Is(err, exchanges.ErrInvalidOrder) {
+		t.Errorf("expected leverage above the limit to be rejected, got %v", err)
+	}
+	if err := client.SetLeverage(ctx, "BTC-USD", decimal.NewFromInt(5)); err != nil {
+		t.Fatalf("SetLeverage failed: %v", err)
+	}
+	if err := client.SetMarginMode(ctx, "BTC-USD", exchanges.MarginModeIsolated); err != nil {
+		t.Fatalf("SetMarginMode failed: %v", err)
+	}
+	settings, err := client.GetLeverage(ctx, "BTC-USD")
+	if err != nil {
+		t.Fatalf("GetLeverage failed: %v", err)
+	}
+	if !settings.Leverage.Equal(decimal.NewFromInt(5)) || !settings.Max.Equal(decimal.NewFromInt(10)) || settings.MarginMode != exchanges.MarginModeIsolated {
+		t.Errorf("unexpected leverage settings %+v", settings)
+	}
+
+	_, err = client.PlaceOrder(ctx, &exchanges.Order{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideSell,
+		Type:   exchanges.OrderTypeMarket,
+		Amount: decimal.NewFromFloat(0.1),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if placed.ProductID != "BTC-PERP-INTX" || placed.Leverage != "5" || placed.MarginType != "ISOLATED" {
+		t.Errorf("unexpected order %+v", placed)
+	}
+}
+
+func TestSpotHasNoFunding(t *testing.T) {
+	client := NewClientWithURL("", "", "http://localhost", "")
+	if _, err := client.GetFundingRate(context.Background(), "BTC-USD"); !errors.Is(err, exchanges.ErrNotSupported) {
+		t.Errorf("expected spot products to have no funding, got %v", err)
+	}
+	if client.productID("BTC-USD") != "BTC-USD" {
+		t.Errorf("expected spot symbols to be traded as is")
+	}
+}
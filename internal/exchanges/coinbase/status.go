@@ -0,0 +1,87 @@
+package coinbase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// GetMarketStatus returns whether trading in symbol is halted. Products that
+// are offline, delisted, disabled or restricted to canceling orders do not
+// take new orders.
+func (c *Client) GetMarketStatus(ctx context.Context, symbol string) (*exchanges.MarketStatus, error) {
+	var product struct {
+		Status          string `json:"status"`
+		TradingDisabled bool   `json:"trading_disabled"`
+		IsDisabled      bool   `json:"is_disabled"`
+		CancelOnly      bool   `json:"cancel_only"`
+	}
+	if err := c.httpClient.doRequest(ctx, "GET", "/brokerage/products/"+c.productID(symbol), nil, &product); err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	switch {
+	case product.TradingDisabled || product.IsDisabled:
+		return &exchanges.MarketStatus{Halted: true, Reason: "trading disabled"}, nil
+	case product.CancelOnly:
+		return &exchanges.MarketStatus{Halted: true, Reason: "cancel only"}, nil
+	case product.Status != "" && product.Status != "online":
+		return &exchanges.MarketStatus{Halted: true, Reason: product.Status}, nil
+	}
+	return &exchanges.MarketStatus{}, nil
+}
+
+// ListMarkets returns the spot products open for trading, or the perpetuals
+// on a perpetuals client. Their 24h volume is converted from the base
+// currency when Coinbase leaves out the quote one.
+func (c *Client) ListMarkets(ctx context.Context) ([]exchanges.Market, error) {
+	var response struct {
+		Products []struct {
+			ProductID       string `json:"product_id"`
+			QuoteCurrencyID string `json:"quote_currency_id"`
+			Price           string `json:"price"`
+			Volume24h       string `json:"volume_24h"`
+			QuoteVolume24h  string `json:"approximate_quote_24h_volume"`
+			Status          string `json:"status"`
+			TradingDisabled bool   `json:"trading_disabled"`
+			IsDisabled      bool   `json:"is_disabled"`
+			CancelOnly      bool   `json:"cancel_only"`
+
+			FutureProductDetails struct {
+				PerpetualDetails perpetualDetails `json:"perpetual_details"`
+			} `json:"future_product_details"`
+		} `json:"products"`
+	}
+	path := "/brokerage/products?product_type=SPOT"
+	if c.perpetuals {
+		path = "/brokerage/products?product_type=FUTURE&contract_expiry_type=PERPETUAL"
+	}
+	if err := c.httpClient.doRequest(ctx, "GET", path, nil, &response); err != nil {
+		return nil, fmt.Errorf("failed to list products: %w", err)
+	}
+
+	markets := make([]exchanges.Market, 0, len(response.Products))
+	for _, product := range response.Products {
+		if product.TradingDisabled || product.IsDisabled || product.CancelOnly ||
+			(product.Status != "" && product.Status != "online") {
+			continue
+		}
+		price, _ := decimal.NewFromString(product.Price)
+		volume, err := decimal.NewFromString(product.QuoteVolume24h)
+		if err != nil {
+			base, _ := decimal.NewFromString(product.Volume24h)
+			volume = base.Mul(price)
+		}
+		openInterest, _ := decimal.NewFromString(product.FutureProductDetails.PerpetualDetails.OpenInterest)
+		markets = append(markets, exchanges.Market{
+			Symbol:       c.symbolOf(product.ProductID),
+			Quote:        product.QuoteCurrencyID,
+			Price:        price,
+			Volume24h:    volume,
+			OpenInterest: openInterest,
+		})
+	}
+	return markets, nil
+}
@@ -0,0 +1,66 @@
+package coinbase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestGetMarketStatus(t *testing.T) {
+	body := `{"product_id":"BTC-USD","status":"online","trading_disabled":false,"cancel_only":false}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/brokerage/products/BTC-USD" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL("", "", server.URL, "")
+	status, err := client.GetMarketStatus(context.Background(), "BTC-USD")
+	if err != nil {
+		t.Fatalf("GetMarketStatus failed: %v", err)
+	}
+	if status.Halted {
+		t.Error("expected an online product not to be halted")
+	}
+
+	body = `{"product_id":"BTC-USD","status":"online","trading_disabled":false,"cancel_only":true}`
+	status, _ = client.GetMarketStatus(context.Background(), "BTC-USD")
+	if !status.Halted || status.Reason != "cancel only" {
+		t.Errorf("expected a cancel-only product to be halted, got %+v", status)
+	}
+}
+
+func TestListMarkets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/brokerage/products" || r.URL.Query().Get("product_type") != "SPOT" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"products":[
+			{"product_id":"BTC-USD","quote_currency_id":"USD","price":"50000","volume_24h":"100","approximate_quote_24h_volume":"5000000","status":"online"},
+			{"product_id":"ETH-EUR","quote_currency_id":"EUR","price":"2000","volume_24h":"10","approximate_quote_24h_volume":"","status":"online"},
+			{"product_id":"OLD-USD","quote_currency_id":"USD","price":"1","volume_24h":"1","status":"delisted"},
+			{"product_id":"CXL-USD","quote_currency_id":"USD","price":"1","volume_24h":"1","status":"online","cancel_only":true}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL("", "", server.URL, "")
+	markets, err := client.ListMarkets(context.Background())
+	if err != nil {
+		t.Fatalf("ListMarkets failed: %v", err)
+	}
+	if len(markets) != 2 {
+		t.Fatalf("expected the 2 tradable products, got %+v", markets)
+	}
+	if markets[0].Symbol != "BTC-USD" || markets[0].Quote != "USD" || !markets[0].Volume24h.Equal(decimal.NewFromInt(5000000)) {
+		t.Errorf("unexpected market %+v", markets[0])
+	}
+	if !markets[1].Volume24h.Equal(decimal.NewFromInt(20000)) {
+		t.Errorf("expected the volume to be converted from the base currency, got %s", markets[1].Volume24h)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -26,6 +27,10 @@ type WebSocketClient struct {
 	tradeCallbacks     map[string]func(*exchanges.Trade)
 
 	done chan struct{}
+
+	// subscriptions are the subscription messages sent, replayed by Reconnect
+	subscriptions map[string]any
+	lastMessage   atomic.Int64 // Unix nanoseconds of the last message received
 }
 
 // NewWebSocketClient creates a new WebSocket client
@@ -94,13 +99,18 @@ func (ws *WebSocketClient) Close() error {
 
 // handleMessages processes incoming WebSocket messages
 func (ws *WebSocketClient) handleMessages(done <-chan struct{}) {
+	ws.mu.RLock()
+	conn := ws.conn
+	ws.mu.RUnlock()
+
 	defer func() {
 		ws.mu.Lock()
-		if ws.conn != nil {
-			ws.conn.Close()
+		// Reconnect may have replaced the connection meanwhile
+		if ws.conn == conn {
 			ws.conn = nil
 		}
 		ws.mu.Unlock()
+		conn.Close()
 	}()
 
 	backoff := time.Second
@@ -113,7 +123,7 @@ func (ws *WebSocketClient) handleMessages(done <-chan struct{}) {
 		case <-done:
 			return
 		default:
-			_, message, err := ws.conn.ReadMessage()
+			_, message, err := conn.ReadMessage()
 			if err != nil {
 				retries++
 				if retries >= maxRetries {
@@ -137,6 +147,7 @@ func (ws *WebSocketClient) handleMessages(done <-chan struct{}) {
 			// Reset backoff on successful read
 			backoff = time.Second
 			retries = 0
+			ws.lastMessage.Store(time.Now().UnixNano())
 			ws.processMessage(message)
 		}
 	}
@@ -360,7 +371,7 @@ func (ws *WebSocketClient) SubscribeTicker(ctx context.Context, symbol string, c
 		"channel":     "ticker",
 	}
 
-	return ws.sendMessage(sub)
+	return ws.subscribe(sub)
 }
 
 // SubscribeOrderBook subscribes to order book updates
@@ -376,7 +387,7 @@ func (ws *WebSocketClient) SubscribeOrderBook(ctx context.Context, symbol string
 		"channel":     "level2",
 	}
 
-	return ws.sendMessage(sub)
+	return ws.subscribe(sub)
 }
 
 // SubscribeTrades subscribes to trade updates
@@ -392,7 +403,7 @@ func (ws *WebSocketClient) SubscribeTrades(ctx context.Context, symbol string, c
 		"channel":     "market_trades",
 	}
 
-	return ws.sendMessage(sub)
+	return ws.subscribe(sub)
 }
 
 // sendMessage sends a message through the WebSocket
@@ -411,3 +422,58 @@ func (ws *WebSocketClient) sendMessage(msg any) error {
 
 	return ws.conn.WriteMessage(websocket.TextMessage, data)
 }
+
+// subscribe sends a subscription message and records it for Reconnect
+func (ws *WebSocketClient) subscribe(sub any) error {
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	if ws.subscriptions == nil {
+		ws.subscriptions = make(map[string]any)
+	}
+	ws.subscriptions[string(data)] = sub
+	ws.mu.Unlock()
+
+	return ws.sendMessage(sub)
+}
+
+// HasSubscriptions reports whether any channel has been subscribed to
+func (ws *WebSocketClient) HasSubscriptions() bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return len(ws.subscriptions) > 0
+}
+
+// LastMessageAt returns when the last message was received, zero before the
+// first one
+func (ws *WebSocketClient) LastMessageAt() time.Time {
+	if nanos := ws.lastMessage.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+	return time.Time{}
+}
+
+// Reconnect replaces the connection and renews every subscription on it
+func (ws *WebSocketClient) Reconnect(ctx context.Context) error {
+	ws.Close()
+	if err := ws.Connect(ctx); err != nil {
+		return err
+	}
+
+	ws.mu.RLock()
+	subs := make([]any, 0, len(ws.subscriptions))
+	for _, sub := range ws.subscriptions {
+		subs = append(subs, sub)
+	}
+	ws.mu.RUnlock()
+
+	for _, sub := range subs {
+		if err := ws.sendMessage(sub); err != nil {
+			return fmt.Errorf("failed to resubscribe: %w", err)
+		}
+	}
+	telemetry.RecordWebSocketReconnect("coinbase")
+	return nil
+}
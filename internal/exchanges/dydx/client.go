@@ -56,6 +56,11 @@ type Client struct {
 	pythonClient *PythonClient // For order placement via Python client
 	network      string        // "testnet" or "mainnet"
 	marketCache  *marketCache  // Cached market data
+
+	// Leverage recorded by SetLeverage, by market
+	leverage map[string]decimal.Decimal
+	// Market quality weights, the defaults when nil
+	quality *QualityConfig
 }
 
 // NewClient creates a new dYdX client
@@ -370,6 +375,28 @@ func (c *Client) SubscribeTrades(ctx context.Context, symbol string, callback fu
 	return c.ws.SubscribeTrades(ctx, symbol, callback)
 }
 
+// Streaming reports whether the websocket carries any subscription
+func (c *Client) Streaming() bool {
+	return c.ws != nil && c.ws.HasSubscriptions()
+}
+
+// LastMessageAt returns when the websocket last received a message, zero when
+// it is not connected or has received nothing yet
+func (c *Client) LastMessageAt() time.Time {
+	if c.ws == nil {
+		return time.Time{}
+	}
+	return c.ws.LastMessageAt()
+}
+
+// ReconnectStream reconnects the websocket and renews its subscriptions
+func (c *Client) ReconnectStream(ctx context.Context) error {
+	if c.ws == nil {
+		return fmt.Errorf("websocket not connected")
+	}
+	return c.ws.Reconnect(ctx)
+}
+
 // SubscribeCandles subscribes to candle updates (using periodic REST API calls)
 func (c *Client) SubscribeCandles(ctx context.Context, symbol string, interval string, callback func(*exchanges.Candle)) error {
 	// dYdX v4 doesn't provide real-time candle streams via WebSocket
@@ -429,8 +456,13 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 		return nil, fmt.Errorf("Python client not initialized - please use NewClientWithMnemonic")
 	}
 
+	placement, err := c.orderPlacement(ctx, order, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	// Place order via Python client
-	result, err := pythonClient.PlaceOrder(ctx, order)
+	result, err := pythonClient.PlaceOrder(ctx, order, placement)
 	if err != nil {
 		telemetry.RecordError("PlaceOrderFailed")
 		return nil, fmt.Errorf("failed to place order: %w", err)
@@ -468,7 +500,7 @@ func (c *Client) CancelOrder(ctx context.Context, orderID string) error {
 // GetOrder retrieves order details
 // ⚠️ WARNING: NOT IMPLEMENTED - dYdX v4 indexer API may not provide individual order queries
 func (c *Client) GetOrder(ctx context.Context, orderID string) (*exchanges.Order, error) {
-	return nil, fmt.Errorf("GetOrder not implemented for dYdX v4 - indexer API limitations")
+	return nil, fmt.Errorf("%w: GetOrder not implemented for dYdX v4 - indexer API limitations", exchanges.ErrNotSupported)
 }
 
 // GetOpenOrders retrieves all open orders
@@ -489,62 +521,96 @@ func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]exchanges.
 
 	orders := make([]exchanges.Order, 0, len(ordersData))
 	for _, orderData := range ordersData {
-		var side exchanges.OrderSide
-		if orderData.Side == "BUY" {
-			side = exchanges.OrderSideBuy
-		} else {
-			side = exchanges.OrderSideSell
-		}
+		orders = append(orders, convertOrderData(orderData))
+	}
 
-		var orderType exchanges.OrderType
-		switch orderData.Type {
-		case "LIMIT":
-			orderType = exchanges.OrderTypeLimit
-		case "MARKET":
-			orderType = exchanges.OrderTypeMarket
-		case "STOP_LIMIT":
-			orderType = exchanges.OrderTypeStopLimit
-		default:
-			orderType = exchanges.OrderTypeLimit // default
-		}
+	return orders, nil
+}
 
-		var status exchanges.OrderStatus
-		switch orderData.Status {
-		case "OPEN":
-			status = exchanges.OrderStatusOpen
-		case "FILLED":
-			status = exchanges.OrderStatusFilled
-		case "CANCELLED":
-			status = exchanges.OrderStatusCanceled
-		case "PENDING":
-			status = exchanges.OrderStatusOpen // treat pending as open
-		default:
-			status = exchanges.OrderStatusOpen // default to open
-		}
+// convertOrderData converts an indexer order into an exchange order
+func convertOrderData(orderData OrderData) exchanges.Order {
+	var side exchanges.OrderSide
+	if orderData.Side == "BUY" {
+		side = exchanges.OrderSideBuy
+	} else {
+		side = exchanges.OrderSideSell
+	}
+
+	var orderType exchanges.OrderType
+	switch orderData.Type {
+	case "LIMIT":
+		orderType = exchanges.OrderTypeLimit
+	case "MARKET":
+		orderType = exchanges.OrderTypeMarket
+	case "STOP_LIMIT":
+		orderType = exchanges.OrderTypeStopLimit
+	case "STOP_MARKET":
+		orderType = exchanges.OrderTypeStopMarket
+	default:
+		orderType = exchanges.OrderTypeLimit // default
+	}
+
+	var status exchanges.OrderStatus
+	switch orderData.Status {
+	case "OPEN":
+		status = exchanges.OrderStatusOpen
+	case "FILLED":
+		status = exchanges.OrderStatusFilled
+	case "CANCELLED":
+		status = exchanges.OrderStatusCanceled
+	case "PENDING":
+		status = exchanges.OrderStatusOpen // treat pending as open
+	default:
+		status = exchanges.OrderStatusOpen // default to open
+	}
+
+	return exchanges.Order{
+		ID:            orderData.ID,
+		Symbol:        orderData.Market,
+		Side:          side,
+		Type:          orderType,
+		Status:        status,
+		Price:         orderData.Price,
+		Amount:        orderData.Size,
+		Filled:        orderData.Size.Sub(orderData.RemainingSize),
+		Remaining:     orderData.RemainingSize,
+		CreatedAt:     orderData.CreatedAt,
+		ClientOrderID: orderData.ClientID,
+		StopPrice:     orderData.TriggerPrice,
+		ReduceOnly:    orderData.ReduceOnly,
+	}
+}
 
-		orders = append(orders, exchanges.Order{
-			ID:            orderData.ID,
-			Symbol:        orderData.Market,
-			Side:          side,
-			Type:          orderType,
-			Status:        status,
-			Price:         orderData.Price,
-			Amount:        orderData.Size,
-			Filled:        orderData.Size.Sub(orderData.RemainingSize),
-			Remaining:     orderData.RemainingSize,
-			CreatedAt:     orderData.CreatedAt,
-			ClientOrderID: orderData.ClientID,
-			StopPrice:     orderData.TriggerPrice,
-		})
+// GetOrderByClientID finds a recent order by the client order ID it was placed with
+func (c *Client) GetOrderByClientID(ctx context.Context, symbol string, clientOrderID string) (*exchanges.Order, error) {
+	if c.wallet == nil {
+		return nil, fmt.Errorf("wallet not initialized - provide mnemonic to access account data")
 	}
 
-	return orders, nil
+	// Without a status filter the indexer returns recent orders in every state
+	var ordersData []OrderData
+	path := fmt.Sprintf("/v4/orders?address=%s&subaccountNumber=%d", c.wallet.Address, c.wallet.SubAccountNumber)
+	if symbol != "" {
+		path += fmt.Sprintf("&market=%s", symbol)
+	}
+	if err := c.httpClient.get(ctx, path, &ordersData); err != nil {
+		return nil, fmt.Errorf("failed to get orders: %w", err)
+	}
+
+	for _, orderData := range ordersData {
+		if orderData.ClientID == clientOrderID {
+			order := convertOrderData(orderData)
+			return &order, nil
+		}
+	}
+
+	return nil, exchanges.ErrOrderNotFound
 }
 
 // GetOrderHistory retrieves order history
 // ⚠️ WARNING: NOT IMPLEMENTED - dYdX v4 indexer API may not provide order history queries
 func (c *Client) GetOrderHistory(ctx context.Context, symbol string, limit int) ([]exchanges.Order, error) {
-	return nil, fmt.Errorf("GetOrderHistory not implemented for dYdX v4 - indexer API limitations")
+	return nil, fmt.Errorf("%w: GetOrderHistory not implemented for dYdX v4 - indexer API limitations", exchanges.ErrNotSupported)
 }
 
 // GetBalance retrieves account balance
@@ -648,7 +714,7 @@ func (c *Client) GetPositions(ctx context.Context) ([]exchanges.Position, error)
 // GetPosition retrieves a specific position
 func (c *Client) GetPosition(ctx context.Context, symbol string) (*exchanges.Position, error) {
 	// Note: dYdX v4 requires address/subaccount parameters
-	return nil, fmt.Errorf("not implemented - requires subaccount address")
+	return nil, fmt.Errorf("%w: not implemented - requires subaccount address", exchanges.ErrNotSupported)
 }
 
 // SupportedSymbols returns list of supported trading symbols
@@ -656,6 +722,16 @@ func (c *Client) SupportedSymbols() []string {
 	return []string{"BTC-USD", "ETH-USD", "SOL-USD", "AVAX-USD"}
 }
 
+// Capabilities returns the features of the client. Only limit and market
+// orders are supported, so stops have to be emulated.
+func (c *Client) Capabilities() exchanges.Capabilities {
+	return exchanges.Capabilities{
+		Shorts:   true,
+		PostOnly: true,
+		Margin:   true,
+	}
+}
+
 // Name returns the exchange name
 func (c *Client) Name() string {
 	return "dYdX"
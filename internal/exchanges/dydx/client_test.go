@@ -323,3 +323,40 @@ func contains(str, substr string) bool {
 	}
 	return false
 }
+
+func TestConvertFillData(t *testing.T) {
+	fill := convertFillData(FillData{
+		ID:        "fill-1",
+		Side:      "SELL",
+		Liquidity: "MAKER",
+		Market:    "BTC-USD",
+		Price:     decimal.NewFromInt(50000),
+		Size:      decimal.NewFromFloat(0.1),
+		Fee:       decimal.NewFromFloat(-0.5),
+		OrderID:   "order-1",
+	})
+
+	if fill.ID != "fill-1" || fill.OrderID != "order-1" || fill.Symbol != "BTC-USD" {
+		t.Errorf("unexpected identifiers: %+v", fill)
+	}
+	if fill.Side != exchanges.OrderSideSell || !fill.Maker {
+		t.Errorf("expected a maker sell, got %s maker=%v", fill.Side, fill.Maker)
+	}
+	if !fill.Fee.Equal(decimal.NewFromFloat(-0.5)) {
+		t.Errorf("expected the rebate as a negative fee, got %s", fill.Fee)
+	}
+	if fill.Liquidation != "" {
+		t.Errorf("order fill should not be a liquidation, got %q", fill.Liquidation)
+	}
+
+	for fillType, kind := range map[string]exchanges.LiquidationKind{
+		"LIMIT":       "",
+		"LIQUIDATION": "", // Our order filled against a liquidated position
+		"LIQUIDATED":  exchanges.LiquidationKindLiquidated,
+		"DELEVERAGED": exchanges.LiquidationKindDeleveraged,
+	} {
+		if got := convertFillData(FillData{Type: fillType}).Liquidation; got != kind {
+			t.Errorf("%s fill: expected %q, got %q", fillType, kind, got)
+		}
+	}
+}
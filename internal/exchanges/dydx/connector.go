@@ -0,0 +1,74 @@
+package dydx
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// dYdX testnet indexer
+const (
+	testnetURL   = "https://indexer.v4testnet.dydx.exchange"
+	testnetWSURL = "wss://indexer.v4testnet.dydx.exchange/v4/ws"
+)
+
+func init() {
+	exchanges.RegisterConnector("dydx", exchanges.Connector{
+		LoadConfig: loadConfig,
+		New:        newFromConfig,
+		Testnet:    newTestnetFromConfig,
+	})
+}
+
+// loadConfig reads the dYdX settings from the environment
+func loadConfig(getenv func(string) string) (exchanges.Config, error) {
+	cfg := exchanges.Config{
+		Enabled:   getenv("ENABLE_DYDX") == "true",
+		APIKey:    getenv("DYDX_API_KEY"),
+		APISecret: getenv("DYDX_API_SECRET"),
+		Mnemonic:  getenv("DYDX_MNEMONIC"),
+	}
+	if value := getenv("DYDX_SUB_ACCOUNT_NUMBER"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			cfg.SubAccountNumber = parsed
+		}
+	}
+	if cfg.Enabled && cfg.Mnemonic == "" && cfg.APISecret == "" {
+		return cfg, fmt.Errorf("dYdX enabled but no authentication method provided - set DYDX_MNEMONIC or DYDX_API_KEY/DYDX_API_SECRET")
+	}
+	return cfg, nil
+}
+
+// newFromConfig creates a client authenticated by the mnemonic, preferred,
+// or the API key of the configuration
+func newFromConfig(cfg exchanges.Config) (exchanges.Exchange, error) {
+	if cfg.Mnemonic != "" {
+		client, err := NewClientWithMnemonic(cfg.Mnemonic, cfg.SubAccountNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dYdX client with mnemonic: %w", err)
+		}
+		return client, nil
+	}
+	if cfg.APISecret != "" {
+		client, err := NewClient(cfg.APIKey, cfg.APISecret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create dYdX client: %w", err)
+		}
+		return client, nil
+	}
+	return nil, fmt.Errorf("dYdX enabled but no authentication method provided - set DYDX_MNEMONIC or DYDX_API_KEY/DYDX_API_SECRET")
+}
+
+// newTestnetFromConfig creates a client on the testnet, which requires the
+// mnemonic
+func newTestnetFromConfig(cfg exchanges.Config) (exchanges.Exchange, error) {
+	if cfg.Mnemonic == "" {
+		return nil, fmt.Errorf("the dYdX testnet requires DYDX_MNEMONIC")
+	}
+	client, err := NewClientWithMnemonicAndURL(cfg.Mnemonic, cfg.SubAccountNumber, testnetURL, testnetWSURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dYdX testnet client: %w", err)
+	}
+	return client, nil
+}
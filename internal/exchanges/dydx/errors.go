@@ -0,0 +1,21 @@
+package dydx
+
+import (
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// classifyError classifies the body of a dYdX indexer error response
+func classifyError(body string) exchanges.ErrorCode {
+	return exchanges.ClassifyMessage(body, exchanges.ErrorCodeRejected)
+}
+
+// apiError classifies an error of a request to the dYdX indexer API
+func apiError(err error) error {
+	return exchanges.HTTPError("dydx", err, classifyError)
+}
+
+// chainError classifies an order or cancellation the chain refused, e.g.
+// "insufficient funds" or an undercollateralized subaccount
+func chainError(action, message string) error {
+	return exchanges.NewError("dydx", classifyError(message), action+": "+message, nil)
+}
@@ -0,0 +1,84 @@
+package dydx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// FillData is a fill of the subaccount, as listed by the indexer
+type FillData struct {
+	ID        string          `json:"id"`
+	Side      string          `json:"side"`
+	Liquidity string          `json:"liquidity"` // TAKER or MAKER
+	Market    string          `json:"market"`
+	Price     decimal.Decimal `json:"price"`
+	Size      decimal.Decimal `json:"size"`
+	Fee       decimal.Decimal `json:"fee"`
+	CreatedAt time.Time       `json:"createdAt"`
+	OrderID   string          `json:"orderId"`
+	// Type is LIMIT or MARKET for the fills of orders, LIQUIDATED and
+	// DELEVERAGED for those of positions the protocol closed
+	Type string `json:"type"`
+}
+
+// FillsResponse is the response of the indexer fills endpoint
+type FillsResponse struct {
+	Fills []FillData `json:"fills"`
+}
+
+// GetFills returns the fills of the subaccount since the given time. The
+// indexer lists the most recent fills first.
+func (c *Client) GetFills(ctx context.Context, symbol string, since time.Time) ([]exchanges.Trade, error) {
+	if c.wallet == nil {
+		return nil, fmt.Errorf("wallet not initialized - provide mnemonic to access account data")
+	}
+
+	path := fmt.Sprintf("/v4/fills?address=%s&subaccountNumber=%d", c.wallet.Address, c.wallet.SubAccountNumber)
+	if symbol != "" {
+		path += "&marketType=PERPETUAL&market=" + url.QueryEscape(symbol)
+	}
+	var response FillsResponse
+	if err := c.httpClient.get(ctx, path, &response); err != nil {
+		return nil, fmt.Errorf("failed to get fills: %w", err)
+	}
+
+	fills := make([]exchanges.Trade, 0, len(response.Fills))
+	for _, f := range response.Fills {
+		if f.CreatedAt.Before(since) {
+			continue
+		}
+		fills = append(fills, convertFillData(f))
+	}
+	return fills, nil
+}
+
+// convertFillData converts an indexer fill into a trade
+func convertFillData(f FillData) exchanges.Trade {
+	side := exchanges.OrderSideSell
+	if f.Side == "BUY" {
+		side = exchanges.OrderSideBuy
+	}
+	trade := exchanges.Trade{
+		ID:        f.ID,
+		OrderID:   f.OrderID,
+		Symbol:    f.Market,
+		Side:      side,
+		Price:     f.Price,
+		Amount:    f.Size,
+		Fee:       f.Fee,
+		Timestamp: f.CreatedAt,
+		Maker:     f.Liquidity == "MAKER",
+	}
+	switch f.Type {
+	case "LIQUIDATED":
+		trade.Liquidation = exchanges.LiquidationKindLiquidated
+	case "DELEVERAGED":
+		trade.Liquidation = exchanges.LiquidationKindDeleveraged
+	}
+	return trade
+}
@@ -1,16 +1,12 @@
 package dydx
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
-	"time"
 
-	"github.com/guyghost/constantine/internal/ratelimit"
-	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/guyghost/constantine/internal/httpclient"
 )
 
 const (
@@ -22,93 +18,60 @@ const (
 
 // HTTPClient handles REST API requests to dYdX
 type HTTPClient struct {
-	baseURL     string
-	apiKey      string
-	apiSecret   string
-	httpClient  *http.Client
-	rateLimiter ratelimit.Limiter
+	apiKey    string
+	apiSecret string
+	client    *httpclient.Client
 }
 
 // NewHTTPClient creates a new HTTP client for dYdX
 func NewHTTPClient(baseURL, apiKey, apiSecret string) *HTTPClient {
-	// Create rate limiter with burst capability
-	limiter := ratelimit.NewTokenBucket(dydxRateLimit, int(dydxRateLimit*2))
-
 	return &HTTPClient{
-		baseURL:     baseURL,
-		apiKey:      apiKey,
-		apiSecret:   apiSecret,
-		rateLimiter: limiter,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		apiKey:    apiKey,
+		apiSecret: apiSecret,
+		client:    httpclient.New(httpclient.DefaultConfig("dydx", baseURL, dydxRateLimit)),
 	}
 }
 
-// doRequest performs an HTTP request
+// doRequest performs an HTTP request. GET requests are retried on
+// transient failures.
 func (c *HTTPClient) doRequest(ctx context.Context, method, path string, body any, result any) error {
-	// Apply rate limiting before making the request
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limit wait failed: %w", err)
-	}
-
-	start := time.Now()
-
-	var reqBody io.Reader
+	var reqBody []byte
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		reqBody = jsonData
 	}
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Accept", "application/json")
 
 	// Add API key if provided
 	if c.apiKey != "" {
-		req.Header.Set("X-API-KEY", c.apiKey)
+		header.Set("X-API-KEY", c.apiKey)
 	}
 
-	// Execute request
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.client.Do(ctx, &httpclient.Request{
+		Method:     method,
+		Path:       path,
+		Body:       reqBody,
+		Header:     header,
+		Idempotent: method == http.MethodGet,
+	})
 	if err != nil {
-		telemetry.RecordAPIRequest("dydx", path, time.Since(start))
-		return fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read response
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		telemetry.RecordAPIRequest("dydx", path, time.Since(start))
-		return fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		telemetry.RecordAPIRequest("dydx", path, time.Since(start))
-		return fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(respBody))
+		return apiError(err)
 	}
 
 	// Parse response
 	if result != nil {
-		if err := json.Unmarshal(respBody, result); err != nil {
-			telemetry.RecordAPIRequest("dydx", path, time.Since(start))
+		if err := json.Unmarshal(resp.Body, result); err != nil {
 			return fmt.Errorf("failed to parse response: %w", err)
 		}
 	}
 
-	// Record successful request
-	telemetry.RecordAPIRequest("dydx", path, time.Since(start))
-
 	return nil
 }
 
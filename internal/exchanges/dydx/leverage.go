@@ -0,0 +1,99 @@
+package dydx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// isolatedSubaccountStart is the first subaccount number holding isolated
+// positions; lower subaccounts are cross margined
+const isolatedSubaccountStart = 128
+
+// dYdX v4 has no leverage setting: a position can use up to the inverse of
+// its market's initial margin fraction, and its margin mode follows the
+// subaccount that holds it.
+
+// SetLeverage checks leverage against the market's limit and records it as
+// the leverage of the market reported by GetLeverage
+func (c *Client) SetLeverage(ctx context.Context, symbol string, leverage decimal.Decimal) error {
+	if !leverage.IsPositive() {
+		return fmt.Errorf("%w: leverage must be positive, got %s", exchanges.ErrInvalidOrder, leverage)
+	}
+	limit, err := c.maxLeverage(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	if leverage.GreaterThan(limit) {
+		return fmt.Errorf("%w: %s allows at most %sx leverage, got %sx",
+			exchanges.ErrInvalidOrder, symbol, limit.StringFixed(2), leverage)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.leverage == nil {
+		c.leverage = make(map[string]decimal.Decimal)
+	}
+	c.leverage[symbol] = leverage
+	return nil
+}
+
+// SetMarginMode succeeds only if mode is the margin mode of the client's
+// subaccount, which cannot be changed
+func (c *Client) SetMarginMode(ctx context.Context, symbol string, mode exchanges.MarginMode) error {
+	if current := c.marginMode(); mode != current {
+		return fmt.Errorf("%w: dYdX margin mode follows the subaccount, which is %s (isolated subaccounts start at %d)",
+			exchanges.ErrNotSupported, current, isolatedSubaccountStart)
+	}
+	return nil
+}
+
+// GetLeverage returns the leverage recorded for symbol, or the market's
+// limit if none was set, and the margin mode of the subaccount
+func (c *Client) GetLeverage(ctx context.Context, symbol string) (*exchanges.LeverageSettings, error) {
+	limit, err := c.maxLeverage(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	leverage, ok := c.leverage[symbol]
+	c.mu.RUnlock()
+	if !ok {
+		leverage = limit
+	}
+
+	return &exchanges.LeverageSettings{
+		Symbol:     symbol,
+		Leverage:   leverage,
+		Max:        limit,
+		MarginMode: c.marginMode(),
+	}, nil
+}
+
+// marginMode returns the margin mode of the client's subaccount
+func (c *Client) marginMode() exchanges.MarginMode {
+	if c.wallet != nil && c.wallet.SubAccountNumber >= isolatedSubaccountStart {
+		return exchanges.MarginModeIsolated
+	}
+	return exchanges.MarginModeCross
+}
+
+// maxLeverage returns the highest leverage of symbol, the inverse of its
+// initial margin fraction
+func (c *Client) maxLeverage(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	markets, err := c.GetAllMarkets(ctx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	market, ok := markets[symbol]
+	if !ok {
+		return decimal.Zero, fmt.Errorf("market %s not found", symbol)
+	}
+	if !market.InitialMarginFraction.IsPositive() {
+		return decimal.Zero, fmt.Errorf("market %s has no initial margin fraction", symbol)
+	}
+	return decimal.NewFromInt(1).Div(market.InitialMarginFraction), nil
+}
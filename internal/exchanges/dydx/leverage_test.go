@@ -0,0 +1,50 @@
+package dydx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func TestClient_Leverage(t *testing.T) {
+	client := &Client{
+		wallet: &Wallet{SubAccountNumber: 0},
+		marketCache: &marketCache{
+			markets: map[string]MarketData{
+				"BTC-USD": {Ticker: "BTC-USD", InitialMarginFraction: decimal.NewFromFloat(0.05)},
+			},
+			timestamp: time.Now(),
+		},
+	}
+	ctx := context.Background()
+
+	settings, err := client.GetLeverage(ctx, "BTC-USD")
+	if err != nil {
+		t.Fatalf("GetLeverage failed: %v", err)
+	}
+	if !settings.Max.Equal(decimal.NewFromInt(20)) || !settings.Leverage.Equal(settings.Max) {
+		t.Errorf("Expected the market limit of 20x before any leverage is set, got %+v", settings)
+	}
+
+	if err := client.SetLeverage(ctx, "BTC-USD", decimal.NewFromInt(5)); err != nil {
+		t.Fatalf("SetLeverage failed: %v", err)
+	}
+	settings, _ = client.GetLeverage(ctx, "BTC-USD")
+	if !settings.Leverage.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected 5x, got %s", settings.Leverage)
+	}
+	if err := client.SetLeverage(ctx, "BTC-USD", decimal.NewFromInt(25)); !errors.Is(err, exchanges.ErrInvalidOrder) {
+		t.Errorf("Expected leverage above the market limit to be rejected, got %v", err)
+	}
+
+	if err := client.SetMarginMode(ctx, "BTC-USD", exchanges.MarginModeCross); err != nil {
+		t.Errorf("Expected cross margin on subaccount 0, got %v", err)
+	}
+	if err := client.SetMarginMode(ctx, "BTC-USD", exchanges.MarginModeIsolated); !errors.Is(err, exchanges.ErrNotSupported) {
+		t.Errorf("Expected isolated margin to be refused on a cross subaccount, got %v", err)
+	}
+}
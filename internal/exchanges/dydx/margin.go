@@ -0,0 +1,50 @@
+package dydx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// GetAccountMargin returns the margin usage of the subaccount. The indexer
+// reports equity and free collateral; the notional behind the leverage is
+// taken at entry prices.
+func (c *Client) GetAccountMargin(ctx context.Context) (*exchanges.AccountMargin, error) {
+	if c.wallet == nil {
+		return nil, fmt.Errorf("wallet not initialized - provide mnemonic to access account data")
+	}
+
+	var resp AccountResponse
+	path := fmt.Sprintf("/v4/addresses/%s", c.wallet.Address)
+	if err := c.httpClient.get(ctx, path, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+
+	for _, subAccount := range resp.SubAccounts {
+		if subAccount.SubAccountNumber != c.wallet.SubAccountNumber {
+			continue
+		}
+
+		notional := decimal.Zero
+		for _, position := range subAccount.OpenPerpetualPositions {
+			notional = notional.Add(position.Size.Abs().Mul(position.EntryPrice))
+		}
+
+		margin := &exchanges.AccountMargin{
+			Equity:         subAccount.Equity,
+			MarginUsed:     decimal.Max(subAccount.Equity.Sub(subAccount.FreeCollateral), decimal.Zero),
+			FreeCollateral: subAccount.FreeCollateral,
+			// Collateral backing open positions cannot be withdrawn
+			Withdrawable: subAccount.FreeCollateral,
+			UpdatedAt:    time.Now(),
+		}
+		if subAccount.Equity.IsPositive() {
+			margin.Leverage = notional.Div(subAccount.Equity)
+		}
+		return margin, nil
+	}
+	return nil, fmt.Errorf("subaccount %d not found", c.wallet.SubAccountNumber)
+}
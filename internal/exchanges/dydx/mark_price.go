@@ -0,0 +1,87 @@
+package dydx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// SubscribeMarkPrice streams the oracle price of symbol, which dYdX marks
+// positions and computes margin at
+func (c *Client) SubscribeMarkPrice(ctx context.Context, symbol string, callback func(*exchanges.MarkPrice)) error {
+	if c.ws == nil {
+		return fmt.Errorf("websocket not connected")
+	}
+	return c.ws.SubscribeMarkPrice(ctx, symbol, callback)
+}
+
+// SubscribeMarkPrice subscribes to the markets channel for the oracle price
+// of symbol. The channel is shared with the ticker, so it is subscribed to
+// only once.
+func (ws *WebSocketClient) SubscribeMarkPrice(ctx context.Context, symbol string, callback func(*exchanges.MarkPrice)) error {
+	ws.mu.Lock()
+	if ws.markCallbacks == nil {
+		ws.markCallbacks = make(map[string]func(*exchanges.MarkPrice))
+	}
+	ws.markCallbacks[symbol] = callback
+	_, subscribed := ws.tickerCallbacks[symbol]
+	ws.mu.Unlock()
+
+	if subscribed {
+		return nil
+	}
+	return ws.subscribe(map[string]interface{}{
+		"type":    "subscribe",
+		"channel": "v4_markets",
+		"id":      symbol,
+	})
+}
+
+// marketsMarkPrices returns the oracle prices a markets channel message
+// carries: by market under oraclePrices, trading or markets (the snapshot
+// sent on subscription), or for the market of the message id
+func marketsMarkPrices(msg map[string]interface{}, now time.Time) []exchanges.MarkPrice {
+	contents, ok := msg["contents"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var marks []exchanges.MarkPrice
+	add := func(symbol string, market map[string]interface{}) {
+		value, _ := market["oraclePrice"].(string)
+		price, err := decimal.NewFromString(value)
+		if err != nil || !price.IsPositive() {
+			return
+		}
+		marks = append(marks, exchanges.MarkPrice{Symbol: symbol, Mark: price, Index: price, Timestamp: now})
+	}
+	for _, key := range []string{"oraclePrices", "trading", "markets"} {
+		markets, _ := contents[key].(map[string]interface{})
+		for symbol, data := range markets {
+			if market, ok := data.(map[string]interface{}); ok {
+				add(symbol, market)
+			}
+		}
+	}
+	if id, ok := msg["id"].(string); ok && len(marks) == 0 {
+		add(id, contents)
+	}
+	return marks
+}
+
+// handleMarkPriceMessage hands the oracle prices of a markets channel
+// message to their callbacks
+func (ws *WebSocketClient) handleMarkPriceMessage(msg map[string]interface{}) {
+	marks := marketsMarkPrices(msg, time.Now())
+
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	for i := range marks {
+		if callback, exists := ws.markCallbacks[marks[i].Symbol]; exists {
+			callback(&marks[i])
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package dydx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func TestWebSocketClient_MarkPrices(t *testing.T) {
+	ws := NewWebSocketClient("wss://unused", "", "")
+	marks := make(map[string]decimal.Decimal)
+	for _, symbol := range []string{"BTC-USD", "ETH-USD"} {
+		// Not connected: the callback is registered all the same
+		_ = ws.SubscribeMarkPrice(context.Background(), symbol, func(mark *exchanges.MarkPrice) {
+			marks[mark.Symbol] = mark.Mark
+		})
+	}
+
+	ws.processMessage([]byte(`{"type":"subscribed","channel":"v4_markets","contents":{"markets":{"BTC-USD":{"oraclePrice":"50000"},"SOL-USD":{"oraclePrice":"100"}}}}`))
+	ws.processMessage([]byte(`{"type":"channel_data","channel":"v4_markets","contents":{"oraclePrices":{"ETH-USD":{"oraclePrice":"2000.5","effectiveAt":"2024-01-01T00:00:00Z"}}}}`))
+	ws.processMessage([]byte(`{"type":"channel_data","channel":"v4_markets","id":"BTC-USD","contents":{"oraclePrice":"50100"}}`))
+
+	if !marks["BTC-USD"].Equal(decimal.NewFromInt(50100)) || !marks["ETH-USD"].Equal(decimal.NewFromFloat(2000.5)) {
+		t.Errorf("unexpected mark prices: %v", marks)
+	}
+	if _, ok := marks["SOL-USD"]; ok {
+		t.Error("markets not subscribed to should be skipped")
+	}
+}
@@ -15,11 +15,6 @@ const (
 	// Cache duration for market data
 	marketCacheDuration = 5 * time.Minute
 
-	// Quality score weights
-	volumeWeight     = 0.35 // 35% volume
-	liquidityWeight  = 0.35 // 35% liquidity
-	volatilityWeight = 0.30 // 30% volatility (penalizing extreme values)
-
 	// Minimum volume threshold (in USD)
 	minVolumeUSD = 1000000.0 // $1M minimum
 
@@ -27,6 +22,72 @@ const (
 	maxVolatility = 0.8 // 80% maximum volatility
 )
 
+// QualityConfig weighs the market quality score. Weights are expected to be
+// validated by the caller and sum to 1.
+type QualityConfig struct {
+	VolumeWeight     float64
+	LiquidityWeight  float64
+	VolatilityWeight float64 // Volatility lowers the score
+	// MinVolumeUSD is the 24h volume below which a market gets the lowest
+	// volume score
+	MinVolumeUSD float64
+}
+
+// DefaultQualityConfig returns the default quality weights: 35% volume, 35%
+// liquidity and 30% volatility
+func DefaultQualityConfig() QualityConfig {
+	return QualityConfig{
+		VolumeWeight:     0.35,
+		LiquidityWeight:  0.35,
+		VolatilityWeight: 0.30,
+		MinVolumeUSD:     100000,
+	}
+}
+
+// SetQualityConfig sets the weights markets are scored with
+func (c *Client) SetQualityConfig(cfg QualityConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.quality = &cfg
+}
+
+// qualityConfig returns the weights markets are scored with
+func (c *Client) qualityConfig() QualityConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.quality == nil {
+		return DefaultQualityConfig()
+	}
+	return *c.quality
+}
+
+// scoreMarket evaluates the quality of a market from its ticker
+func (c *Client) scoreMarket(symbol string, ticker MarketTicker, cfg QualityConfig) MarketQuality {
+	// Calculate volatility and liquidity from ticker data (fast)
+	volatility := c.estimateVolatilityFromSpread(ticker)
+	liquidity := c.estimateLiquidityFromTicker(ticker)
+
+	// Calculate volume score (normalized)
+	volumeUSD, _ := ticker.Volume24h.Float64()
+	volumeScore := c.normalizeVolume(volumeUSD, cfg.MinVolumeUSD)
+
+	// Calculate composite quality score
+	// Volatility is a penalty (higher volatility = lower score)
+	volatilityScore := 1.0 - math.Min(volatility, 1.0)
+
+	qualityScore := (volumeScore * cfg.VolumeWeight) +
+		(liquidity * cfg.LiquidityWeight) +
+		(volatilityScore * cfg.VolatilityWeight)
+
+	return MarketQuality{
+		Symbol:       symbol,
+		Volume24h:    ticker.Volume24h,
+		Volatility:   volatility,
+		Liquidity:    liquidity,
+		QualityScore: qualityScore,
+	}
+}
+
 // marketCache stores cached market data
 type marketCache struct {
 	markets   map[string]MarketData
@@ -64,6 +125,30 @@ func (c *Client) GetAllMarkets(ctx context.Context) (map[string]MarketData, erro
 	return marketsResp.Markets, nil
 }
 
+// ListMarkets returns the ACTIVE perpetual markets, all quoted in USD
+func (c *Client) ListMarkets(ctx context.Context) ([]exchanges.Market, error) {
+	markets, err := c.GetAllMarkets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make([]exchanges.Market, 0, len(markets))
+	for symbol, market := range markets {
+		if market.Status != "ACTIVE" {
+			continue
+		}
+		listed = append(listed, exchanges.Market{
+			Symbol:       symbol,
+			Quote:        "USD",
+			Price:        market.OraclePrice,
+			Volume24h:    market.Volume24H,
+			OpenInterest: market.OpenInterest,
+		})
+	}
+	sort.Slice(listed, func(i, j int) bool { return listed[i].Symbol < listed[j].Symbol })
+	return listed, nil
+}
+
 // EvaluateMarketQuality evaluates the quality metrics of a market
 func (c *Client) EvaluateMarketQuality(ctx context.Context, symbol string) (*MarketQuality, error) {
 	// Get market data
@@ -88,31 +173,8 @@ func (c *Client) EvaluateMarketQuality(ctx context.Context, symbol string) (*Mar
 		return nil, fmt.Errorf("ticker data for %s not found", symbol)
 	}
 
-	// Calculate volatility from ticker data (fast)
-	volatility := c.estimateVolatilityFromSpread(tickerData)
-
-	// Calculate liquidity from ticker data (fast)
-	liquidity := c.estimateLiquidityFromTicker(tickerData)
-
-	// Calculate volume score (normalized)
-	volumeUSD, _ := tickerData.Volume24h.Float64()
-	volumeScore := c.normalizeVolume(volumeUSD)
-
-	// Calculate composite quality score
-	// Volatility is a penalty (higher volatility = lower score)
-	volatilityScore := 1.0 - math.Min(volatility, 1.0)
-
-	qualityScore := (volumeScore * volumeWeight) +
-		(liquidity * liquidityWeight) +
-		(volatilityScore * volatilityWeight)
-
-	return &MarketQuality{
-		Symbol:       symbol,
-		Volume24h:    tickerData.Volume24h,
-		Volatility:   volatility,
-		Liquidity:    liquidity,
-		QualityScore: qualityScore,
-	}, nil
+	quality := c.scoreMarket(symbol, tickerData, c.qualityConfig())
+	return &quality, nil
 }
 
 // FilterMarketsByQuality filters markets based on minimum quality threshold
@@ -128,6 +190,7 @@ func (c *Client) FilterMarketsByQuality(ctx context.Context, minQuality float64)
 		return nil, fmt.Errorf("failed to get ticker data: %w", err)
 	}
 
+	cfg := c.qualityConfig()
 	filtered := make(map[string]MarketQuality)
 	filteredMu := sync.Mutex{}
 
@@ -147,31 +210,10 @@ func (c *Client) FilterMarketsByQuality(ctx context.Context, minQuality float64)
 				return
 			}
 
-			// Calculate volatility from ticker data (fast)
-			volatility := c.estimateVolatilityFromSpread(tickerData)
-
-			// Calculate liquidity from ticker data (fast)
-			liquidity := c.estimateLiquidityFromTicker(tickerData)
-
-			// Get volume score
-			volumeUSD, _ := tickerData.Volume24h.Float64()
-			volumeScore := c.normalizeVolume(volumeUSD)
-
-			// Calculate composite quality score
-			volatilityScore := 1.0 - math.Min(volatility, 1.0)
-			qualityScore := (volumeScore * volumeWeight) +
-				(liquidity * liquidityWeight) +
-				(volatilityScore * volatilityWeight)
-
-			if qualityScore >= minQuality {
+			quality := c.scoreMarket(sym, tickerData, cfg)
+			if quality.QualityScore >= minQuality {
 				filteredMu.Lock()
-				filtered[sym] = MarketQuality{
-					Symbol:       sym,
-					Volume24h:    tickerData.Volume24h,
-					Volatility:   volatility,
-					Liquidity:    liquidity,
-					QualityScore: qualityScore,
-				}
+				filtered[sym] = quality
 				filteredMu.Unlock()
 			}
 		}(symbol)
@@ -253,16 +295,17 @@ func (c *Client) calculateVolatility(candles []exchanges.Candle, ticker MarketTi
 	return math.Sqrt(variance)
 }
 
-// normalizeVolume normalizes 24h volume to [0, 1] score
-func (c *Client) normalizeVolume(volumeUSD float64) float64 {
-	if volumeUSD < 100000 { // $100K minimum
+// normalizeVolume normalizes 24h volume to [0, 1] score, minVolumeUSD
+// scoring the lowest
+func (c *Client) normalizeVolume(volumeUSD, minVolumeUSD float64) float64 {
+	if volumeUSD < minVolumeUSD {
 		return 0.1
 	}
 
 	// Log-based normalization: better distribution
-	// At $100K = 0.1, at $1M = 0.35, at $10M = 0.60, at $100M+ = 0.85+
+	// With a $100K minimum: at $100K = 0.1, at $1M = 0.3, at $10M = 0.5, at $100M = 0.7
 	logVolume := math.Log10(volumeUSD)
-	baseLog := math.Log10(100000)          // $100K baseline
+	baseLog := math.Log10(minVolumeUSD)
 	score := 0.1 + (logVolume-baseLog)*0.2 // Scale factor
 	return math.Min(math.Max(score, 0.1), 1.0)
 }
@@ -2,6 +2,7 @@ package dydx
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/shopspring/decimal"
@@ -310,3 +311,44 @@ func TestMarketSelectionConsistency(t *testing.T) {
 		}
 	}
 }
+
+// TestScoreMarket_QualityConfig tests that market scores follow the configured weights
+func TestScoreMarket_QualityConfig(t *testing.T) {
+	client, err := NewClient("", "")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// A 2% spread on a market trading $10M a day
+	ticker := MarketTicker{
+		Bid:          decimal.NewFromInt(99),
+		Ask:          decimal.NewFromInt(101),
+		Volume24h:    decimal.NewFromInt(10_000_000),
+		OpenInterest: decimal.NewFromInt(5000),
+	}
+
+	volumeOnly := client.scoreMarket("BTC-USD", ticker, QualityConfig{VolumeWeight: 1, MinVolumeUSD: 100000})
+	if math.Abs(volumeOnly.QualityScore-0.5) > 1e-9 {
+		t.Errorf("Expected the volume score alone, got %.3f", volumeOnly.QualityScore)
+	}
+	volatilityOnly := client.scoreMarket("BTC-USD", ticker, QualityConfig{VolatilityWeight: 1, MinVolumeUSD: 100000})
+	if math.Abs(volatilityOnly.QualityScore-(1-volatilityOnly.Volatility)) > 1e-9 {
+		t.Errorf("Expected the volatility score alone, got %.3f", volatilityOnly.QualityScore)
+	}
+	mixed := client.scoreMarket("BTC-USD", ticker, DefaultQualityConfig())
+	want := 0.35*volumeOnly.QualityScore + 0.35*mixed.Liquidity + 0.30*volatilityOnly.QualityScore
+	if math.Abs(mixed.QualityScore-want) > 1e-9 {
+		t.Errorf("Expected the default weights to blend the scores to %.3f, got %.3f", want, mixed.QualityScore)
+	}
+
+	// Raising the volume floor above the market's volume gives it the lowest volume score
+	floored := client.scoreMarket("BTC-USD", ticker, QualityConfig{VolumeWeight: 1, MinVolumeUSD: 100_000_000})
+	if floored.QualityScore != 0.1 {
+		t.Errorf("Expected the lowest volume score below the floor, got %.3f", floored.QualityScore)
+	}
+
+	client.SetQualityConfig(QualityConfig{VolumeWeight: 1, MinVolumeUSD: 100000})
+	if got := client.qualityConfig(); got.VolumeWeight != 1 {
+		t.Errorf("Expected the configured weights to be used, got %+v", got)
+	}
+}
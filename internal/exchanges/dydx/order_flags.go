@@ -0,0 +1,101 @@
+package dydx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// dYdX keeps short-term orders in the memory of the validators only: they
+// cost no gas and suit scalping, but expire after a few blocks. Orders meant
+// to rest, and stops, are stateful: stored on chain until canceled or their
+// good til time.
+
+const (
+	orderFlagsShortTerm   = 0
+	orderFlagsConditional = 32
+	orderFlagsLongTerm    = 64
+
+	// shortTermBlockWindow is how many blocks past the current height a
+	// short-term order is good til (the protocol allows up to 20)
+	shortTermBlockWindow = 20
+	// shortTermMaxLifetime is the longest an order may live and still be
+	// sent short-term, safely within the block window
+	shortTermMaxLifetime = 15 * time.Second
+	// maxStatefulLifetime is how far ahead stateful orders without an expiry
+	// are good til (the protocol allows up to 95 days)
+	maxStatefulLifetime = 90 * 24 * time.Hour
+)
+
+// orderPlacement is how an order is stored by the chain
+type orderPlacement struct {
+	Flags        int
+	GoodTilBlock int64     // Short-term orders
+	GoodTilTime  time.Time // Stateful orders
+}
+
+// orderLongevity picks short-term orders for those that execute at once or
+// expire within the block window, and stateful ones for those meant to
+// rest. An explicit longevity in the options wins; stops are always stateful.
+func orderLongevity(order *exchanges.Order, now time.Time) exchanges.OrderLongevity {
+	if order.Type.IsStop() {
+		return exchanges.OrderLongevityLongTerm
+	}
+	if order.Options != nil && order.Options.Longevity != exchanges.OrderLongevityAuto {
+		return order.Options.Longevity
+	}
+	switch {
+	case order.Type == exchanges.OrderTypeMarket,
+		order.TimeInForce == exchanges.TimeInForceIOC,
+		order.TimeInForce == exchanges.TimeInForceFOK:
+		return exchanges.OrderLongevityShortTerm
+	case order.TimeInForce == exchanges.TimeInForceGTD && !order.ExpiresAt.IsZero() && order.ExpiresAt.Sub(now) <= shortTermMaxLifetime:
+		return exchanges.OrderLongevityShortTerm
+	}
+	return exchanges.OrderLongevityLongTerm
+}
+
+// orderPlacement returns the flags and good til block or time of order
+func (c *Client) orderPlacement(ctx context.Context, order *exchanges.Order, now time.Time) (orderPlacement, error) {
+	expiry := now.Add(maxStatefulLifetime)
+	if order.TimeInForce == exchanges.TimeInForceGTD && !order.ExpiresAt.IsZero() {
+		expiry = order.ExpiresAt
+	}
+
+	switch orderLongevity(order, now) {
+	case exchanges.OrderLongevityShortTerm:
+		if order.TimeInForce == exchanges.TimeInForceGTD && order.ExpiresAt.Sub(now) > shortTermMaxLifetime {
+			return orderPlacement{}, fmt.Errorf("%w: short-term orders expire within %s",
+				exchanges.ErrInvalidOrder, shortTermMaxLifetime)
+		}
+		height, err := c.blockHeight(ctx)
+		if err != nil {
+			return orderPlacement{}, err
+		}
+		return orderPlacement{Flags: orderFlagsShortTerm, GoodTilBlock: height + shortTermBlockWindow}, nil
+	case exchanges.OrderLongevityLongTerm:
+		if order.Type.IsStop() {
+			return orderPlacement{Flags: orderFlagsConditional, GoodTilTime: expiry}, nil
+		}
+		return orderPlacement{Flags: orderFlagsLongTerm, GoodTilTime: expiry}, nil
+	}
+	return orderPlacement{}, fmt.Errorf("%w: unknown order longevity %q", exchanges.ErrInvalidOrder, order.Options.Longevity)
+}
+
+// blockHeight returns the latest block height seen by the indexer
+func (c *Client) blockHeight(ctx context.Context) (int64, error) {
+	var resp struct {
+		Height string `json:"height"`
+	}
+	if err := c.httpClient.get(ctx, "/v4/height", &resp); err != nil {
+		return 0, fmt.Errorf("failed to get block height: %w", err)
+	}
+	height, err := strconv.ParseInt(resp.Height, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse block height %q: %w", resp.Height, err)
+	}
+	return height, nil
+}
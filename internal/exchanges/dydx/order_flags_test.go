@@ -0,0 +1,97 @@
+package dydx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+func TestClient_OrderPlacement(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v4/height" {
+			t.Errorf("Unexpected request %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"height":"1000","time":"2024-01-01T00:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL("", "", server.URL, "")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	expiry := now.Add(time.Hour)
+
+	tests := []struct {
+		name  string
+		order exchanges.Order
+		want  orderPlacement
+	}{
+		{
+			name:  "market orders are short-term",
+			order: exchanges.Order{Type: exchanges.OrderTypeMarket},
+			want:  orderPlacement{Flags: orderFlagsShortTerm, GoodTilBlock: 1000 + shortTermBlockWindow},
+		},
+		{
+			name:  "ioc limits are short-term",
+			order: exchanges.Order{Type: exchanges.OrderTypeLimit, TimeInForce: exchanges.TimeInForceIOC},
+			want:  orderPlacement{Flags: orderFlagsShortTerm, GoodTilBlock: 1000 + shortTermBlockWindow},
+		},
+		{
+			name:  "limits expiring within the block window are short-term",
+			order: exchanges.Order{Type: exchanges.OrderTypeLimit, TimeInForce: exchanges.TimeInForceGTD, ExpiresAt: now.Add(10 * time.Second)},
+			want:  orderPlacement{Flags: orderFlagsShortTerm, GoodTilBlock: 1000 + shortTermBlockWindow},
+		},
+		{
+			name:  "resting limits are long-term",
+			order: exchanges.Order{Type: exchanges.OrderTypeLimit},
+			want:  orderPlacement{Flags: orderFlagsLongTerm, GoodTilTime: now.Add(maxStatefulLifetime)},
+		},
+		{
+			name:  "gtd limits are good til their expiry",
+			order: exchanges.Order{Type: exchanges.OrderTypeLimit, TimeInForce: exchanges.TimeInForceGTD, ExpiresAt: expiry},
+			want:  orderPlacement{Flags: orderFlagsLongTerm, GoodTilTime: expiry},
+		},
+		{
+			name:  "stops are conditional",
+			order: exchanges.Order{Type: exchanges.OrderTypeStopMarket},
+			want:  orderPlacement{Flags: orderFlagsConditional, GoodTilTime: now.Add(maxStatefulLifetime)},
+		},
+		{
+			name: "longevity can be chosen",
+			order: exchanges.Order{Type: exchanges.OrderTypeLimit,
+				Options: &exchanges.OrderOptions{Longevity: exchanges.OrderLongevityShortTerm}},
+			want: orderPlacement{Flags: orderFlagsShortTerm, GoodTilBlock: 1000 + shortTermBlockWindow},
+		},
+		{
+			name: "stops stay stateful when short-term is asked",
+			order: exchanges.Order{Type: exchanges.OrderTypeStopLimit,
+				Options: &exchanges.OrderOptions{Longevity: exchanges.OrderLongevityShortTerm}},
+			want: orderPlacement{Flags: orderFlagsConditional, GoodTilTime: now.Add(maxStatefulLifetime)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.orderPlacement(context.Background(), &tt.order, now)
+			if err != nil {
+				t.Fatalf("orderPlacement failed: %v", err)
+			}
+			if got.Flags != tt.want.Flags || got.GoodTilBlock != tt.want.GoodTilBlock || !got.GoodTilTime.Equal(tt.want.GoodTilTime) {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	order := exchanges.Order{
+		Type:        exchanges.OrderTypeLimit,
+		TimeInForce: exchanges.TimeInForceGTD,
+		ExpiresAt:   expiry,
+		Options:     &exchanges.OrderOptions{Longevity: exchanges.OrderLongevityShortTerm},
+	}
+	if _, err := client.orderPlacement(context.Background(), &order, now); !errors.Is(err, exchanges.ErrInvalidOrder) {
+		t.Errorf("Expected short-term orders outliving the block window to be rejected, got %v", err)
+	}
+}
@@ -94,10 +94,16 @@ type PythonOrderRequest struct {
 	Type        string  `json:"type"`
 	Size        float64 `json:"size"`
 	Price       float64 `json:"price"`
+	Trigger     float64 `json:"triggerPrice,omitempty"` // Stop price of conditional orders
 	TimeInForce string  `json:"timeInForce,omitempty"`
 	ReduceOnly  bool    `json:"reduceOnly,omitempty"`
 	PostOnly    bool    `json:"postOnly,omitempty"`
 	ClientID    string  `json:"clientId,omitempty"`
+
+	// Short-term orders are good til a block, stateful ones til a time
+	OrderFlags   int   `json:"orderFlags"`
+	GoodTilBlock int64 `json:"goodTilBlock,omitempty"`
+	GoodTilTime  int64 `json:"goodTilBlockTime,omitempty"` // Unix seconds
 }
 
 // PythonOrderResponse represents the response from Python client
@@ -109,8 +115,9 @@ type PythonOrderResponse struct {
 	TxHash   string `json:"txHash,omitempty"`
 }
 
-// PlaceOrder places an order using the Python client
-func (c *PythonClient) PlaceOrder(ctx context.Context, order *exchanges.Order) (*exchanges.Order, error) {
+// PlaceOrder places an order using the Python client, short-term or
+// stateful as placement says
+func (c *PythonClient) PlaceOrder(ctx context.Context, order *exchanges.Order, placement orderPlacement) (*exchanges.Order, error) {
 	// Convert order to Python request format
 	side := "BUY"
 	if order.Side == "sell" || order.Side == "SELL" {
@@ -118,20 +125,53 @@ func (c *PythonClient) PlaceOrder(ctx context.Context, order *exchanges.Order) (
 	}
 
 	orderType := "LIMIT"
-	if order.Type == "market" || order.Type == "MARKET" {
+	switch order.Type {
+	case exchanges.OrderTypeMarket, "MARKET":
 		orderType = "MARKET"
+	case exchanges.OrderTypeStopLimit:
+		orderType = "STOP_LIMIT"
+	case exchanges.OrderTypeStopMarket:
+		orderType = "STOP_MARKET"
 	}
 
 	size, _ := order.Amount.Float64()
 	price, _ := order.Price.Float64()
 
+	clientID := order.ClientOrderID
+	if clientID == "" {
+		clientID = order.ID
+	}
+
+	if err := exchanges.ValidateTimeInForce(order.TimeInForce, order.PostOnly, order.ExpiresAt); err != nil {
+		return nil, err
+	}
+
 	pyRequest := PythonOrderRequest{
 		Market:   order.Symbol,
 		Side:     side,
 		Type:     orderType,
 		Size:     size,
 		Price:    price,
-		ClientID: order.ID,
+		PostOnly: order.PostOnly,
+		ClientID: clientID,
+
+		OrderFlags:   placement.Flags,
+		GoodTilBlock: placement.GoodTilBlock,
+	}
+	if !placement.GoodTilTime.IsZero() {
+		pyRequest.GoodTilTime = placement.GoodTilTime.Unix()
+	}
+	pyRequest.ReduceOnly = order.ReduceOnly
+	if order.Type.IsStop() {
+		pyRequest.Trigger = order.StopPrice.InexactFloat64()
+	}
+	switch order.TimeInForce {
+	case exchanges.TimeInForceIOC:
+		pyRequest.TimeInForce = "IOC"
+	case exchanges.TimeInForceFOK:
+		pyRequest.TimeInForce = "FOK"
+	case exchanges.TimeInForceGTD:
+		pyRequest.TimeInForce = "GTT"
 	}
 
 	// Execute Python script
@@ -146,7 +186,7 @@ func (c *PythonClient) PlaceOrder(ctx context.Context, order *exchanges.Order) (
 	}
 
 	if !pyResponse.Success {
-		return nil, fmt.Errorf("order placement failed: %s", pyResponse.Error)
+		return nil, chainError("order placement failed", pyResponse.Error)
 	}
 
 	// Update order with response data
@@ -177,7 +217,7 @@ func (c *PythonClient) CancelOrder(ctx context.Context, orderID string) error {
 	}
 
 	if !pyResponse.Success {
-		return fmt.Errorf("order cancellation failed: %s", pyResponse.Error)
+		return chainError("order cancellation failed", pyResponse.Error)
 	}
 
 	return nil
@@ -99,7 +99,18 @@ class DydxClientWrapper:
             order_type = data.get("type", "LIMIT").upper()
             size = float(data.get("size", 0))
             price = float(data.get("price", 0))
+            trigger_price = float(data.get("triggerPrice", 0))
+            reduce_only = bool(data.get("reduceOnly", False))
             client_id = data.get("clientId", "")
+            # Short-term orders (flags 0) are good til a block; long-term (64)
+            # and conditional (32) orders are stateful, good til a time
+            order_flags = int(data.get("orderFlags", 0))
+            good_til_block = int(data.get("goodTilBlock", 0))
+            good_til_block_time = int(data.get("goodTilBlockTime", 0))
+            if order_flags == 0 and good_til_block <= 0:
+                raise ValueError("short-term orders require goodTilBlock")
+            if order_flags != 0 and good_til_block_time <= 0:
+                raise ValueError("stateful orders require goodTilBlockTime")
 
             # NOTE: Full dYdX v4 order placement requires complex protobuf construction
             # For now, we return a placeholder response indicating the order would be placed
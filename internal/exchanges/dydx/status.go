@@ -0,0 +1,29 @@
+package dydx
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// GetMarketStatus returns whether trading in symbol is halted. Markets that
+// are not ACTIVE, such as PAUSED, CANCEL_ONLY, POST_ONLY or FINAL_SETTLEMENT,
+// do not take new positions.
+func (c *Client) GetMarketStatus(ctx context.Context, symbol string) (*exchanges.MarketStatus, error) {
+	var resp MarketsResponse
+	path := "/v4/perpetualMarkets?ticker=" + url.QueryEscape(symbol)
+	if err := c.httpClient.get(ctx, path, &resp); err != nil {
+		return nil, fmt.Errorf("failed to get market status: %w", err)
+	}
+
+	market, ok := resp.Markets[symbol]
+	if !ok {
+		return nil, fmt.Errorf("market %s not found", symbol)
+	}
+	if market.Status != "ACTIVE" {
+		return &exchanges.MarketStatus{Halted: true, Reason: market.Status}, nil
+	}
+	return &exchanges.MarketStatus{}, nil
+}
@@ -0,0 +1,63 @@
+package dydx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestClient_GetMarketStatus(t *testing.T) {
+	status := "ACTIVE"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("ticker") == "" {
+			t.Errorf("Expected the market to be queried by ticker, got %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"markets":{"BTC-USD":{"ticker":"BTC-USD","status":"` + status + `"}}}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL("", "", server.URL, "")
+	market, err := client.GetMarketStatus(context.Background(), "BTC-USD")
+	if err != nil {
+		t.Fatalf("GetMarketStatus failed: %v", err)
+	}
+	if market.Halted {
+		t.Error("Expected an active market not to be halted")
+	}
+
+	status = "PAUSED"
+	market, _ = client.GetMarketStatus(context.Background(), "BTC-USD")
+	if !market.Halted || market.Reason != "PAUSED" {
+		t.Errorf("Expected a paused market to be halted, got %+v", market)
+	}
+
+	if _, err := client.GetMarketStatus(context.Background(), "ETH-USD"); err == nil {
+		t.Error("Expected an error for an unknown market")
+	}
+}
+
+func TestClient_ListMarkets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"markets":{
+			"ETH-USD":{"ticker":"ETH-USD","status":"ACTIVE","oraclePrice":"2000","volume24H":"300000000","openInterest":"90000"},
+			"BTC-USD":{"ticker":"BTC-USD","status":"ACTIVE","oraclePrice":"50000","volume24H":"900000000","openInterest":"1200"},
+			"LUNA-USD":{"ticker":"LUNA-USD","status":"FINAL_SETTLEMENT","oraclePrice":"0.1","volume24H":"0"}
+		}}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL("", "", server.URL, "")
+	markets, err := client.ListMarkets(context.Background())
+	if err != nil {
+		t.Fatalf("ListMarkets failed: %v", err)
+	}
+	if len(markets) != 2 || markets[0].Symbol != "BTC-USD" || markets[1].Symbol != "ETH-USD" {
+		t.Fatalf("Expected the active markets sorted by symbol, got %+v", markets)
+	}
+	if !markets[0].Volume24h.Equal(decimal.NewFromInt(900000000)) || markets[0].Quote != "USD" {
+		t.Errorf("Unexpected market %+v", markets[0])
+	}
+}
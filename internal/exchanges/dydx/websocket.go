@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -25,8 +26,14 @@ type WebSocketClient struct {
 	tickerCallbacks    map[string]func(*exchanges.Ticker)
 	orderbookCallbacks map[string]func(*exchanges.OrderBook)
 	tradeCallbacks     map[string]func(*exchanges.Trade)
+	// markCallbacks receive the oracle prices, by market
+	markCallbacks map[string]func(*exchanges.MarkPrice)
 
 	done chan struct{}
+
+	// subscriptions are the subscription messages sent, replayed by Reconnect
+	subscriptions map[string]interface{}
+	lastMessage   atomic.Int64 // Unix nanoseconds of the last message received
 }
 
 // NewWebSocketClient creates a new WebSocket client
@@ -98,13 +105,18 @@ func (ws *WebSocketClient) Close() error {
 
 // handleMessages processes incoming WebSocket messages
 func (ws *WebSocketClient) handleMessages(done <-chan struct{}) {
+	ws.mu.RLock()
+	conn := ws.conn
+	ws.mu.RUnlock()
+
 	defer func() {
 		ws.mu.Lock()
-		if ws.conn != nil {
-			ws.conn.Close()
+		// Reconnect may have replaced the connection meanwhile
+		if ws.conn == conn {
 			ws.conn = nil
 		}
 		ws.mu.Unlock()
+		conn.Close()
 	}()
 
 	for {
@@ -112,7 +124,7 @@ func (ws *WebSocketClient) handleMessages(done <-chan struct{}) {
 		case <-done:
 			return
 		default:
-			_, message, err := ws.conn.ReadMessage()
+			_, message, err := conn.ReadMessage()
 			if err != nil {
 				// Log error and attempt reconnect
 				telemetry.RecordWebSocketReconnect("dydx")
@@ -120,6 +132,7 @@ func (ws *WebSocketClient) handleMessages(done <-chan struct{}) {
 				continue
 			}
 
+			ws.lastMessage.Store(time.Now().UnixNano())
 			ws.processMessage(message)
 		}
 	}
@@ -140,7 +153,10 @@ func (ws *WebSocketClient) processMessage(message []byte) {
 
 	switch msgType {
 	case "subscribed":
-		// Handle subscription confirmation
+		// The markets snapshot carries the oracle prices
+		if channel, _ := msg["channel"].(string); channel == "v4_markets" {
+			ws.handleMarkPriceMessage(msg)
+		}
 		return
 	case "channel_data":
 		channel, ok := msg["channel"].(string)
@@ -151,6 +167,7 @@ func (ws *WebSocketClient) processMessage(message []byte) {
 		switch channel {
 		case "v4_markets":
 			ws.handleTickerMessage(msg)
+			ws.handleMarkPriceMessage(msg)
 		case "v4_orderbook":
 			ws.handleOrderBookMessage(msg)
 		case "v4_trades":
@@ -351,7 +368,7 @@ func (ws *WebSocketClient) SubscribeTicker(ctx context.Context, symbol string, c
 		"id":      symbol,
 	}
 
-	return ws.sendMessage(sub)
+	return ws.subscribe(sub)
 }
 
 // SubscribeOrderBook subscribes to order book updates
@@ -367,7 +384,7 @@ func (ws *WebSocketClient) SubscribeOrderBook(ctx context.Context, symbol string
 		"id":      symbol,
 	}
 
-	return ws.sendMessage(sub)
+	return ws.subscribe(sub)
 }
 
 // SubscribeTrades subscribes to trade updates
@@ -383,7 +400,7 @@ func (ws *WebSocketClient) SubscribeTrades(ctx context.Context, symbol string, c
 		"id":      symbol,
 	}
 
-	return ws.sendMessage(sub)
+	return ws.subscribe(sub)
 }
 
 // sendMessage sends a message through the WebSocket
@@ -402,3 +419,58 @@ func (ws *WebSocketClient) sendMessage(msg interface{}) error {
 
 	return ws.conn.WriteMessage(websocket.TextMessage, data)
 }
+
+// subscribe sends a subscription message and records it for Reconnect
+func (ws *WebSocketClient) subscribe(sub interface{}) error {
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	if ws.subscriptions == nil {
+		ws.subscriptions = make(map[string]interface{})
+	}
+	ws.subscriptions[string(data)] = sub
+	ws.mu.Unlock()
+
+	return ws.sendMessage(sub)
+}
+
+// HasSubscriptions reports whether any channel has been subscribed to
+func (ws *WebSocketClient) HasSubscriptions() bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return len(ws.subscriptions) > 0
+}
+
+// LastMessageAt returns when the last message was received, zero before the
+// first one
+func (ws *WebSocketClient) LastMessageAt() time.Time {
+	if nanos := ws.lastMessage.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+	return time.Time{}
+}
+
+// Reconnect replaces the connection and renews every subscription on it
+func (ws *WebSocketClient) Reconnect(ctx context.Context) error {
+	ws.Close()
+	if err := ws.Connect(ctx); err != nil {
+		return err
+	}
+
+	ws.mu.RLock()
+	subs := make([]interface{}, 0, len(ws.subscriptions))
+	for _, sub := range ws.subscriptions {
+		subs = append(subs, sub)
+	}
+	ws.mu.RUnlock()
+
+	for _, sub := range subs {
+		if err := ws.sendMessage(sub); err != nil {
+			return fmt.Errorf("failed to resubscribe: %w", err)
+		}
+	}
+	telemetry.RecordWebSocketReconnect("dydx")
+	return nil
+}
@@ -0,0 +1,156 @@
+package exchanges
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/guyghost/constantine/internal/httpclient"
+)
+
+// ErrorCode classifies exchange errors so callers can tell whether to retry,
+// give up or alert an operator
+type ErrorCode string
+
+const (
+	ErrorCodeAuth              ErrorCode = "auth"               // Credentials or signature rejected
+	ErrorCodeInsufficientFunds ErrorCode = "insufficient_funds" // Not enough balance or margin
+	ErrorCodeInvalidOrder      ErrorCode = "invalid_order"      // Order parameters rejected
+	ErrorCodeRateLimited       ErrorCode = "rate_limited"       // Too many requests
+	ErrorCodeNotSupported      ErrorCode = "not_supported"      // Feature unavailable on the exchange
+	ErrorCodeRejected          ErrorCode = "rejected"           // Request refused for another reason
+	ErrorCodeNetwork           ErrorCode = "network"            // Exchange unreachable or failing
+	ErrorCodeUnknown           ErrorCode = "unknown"
+)
+
+// Retryable reports whether a request failing with the code may succeed when
+// sent again
+func (c ErrorCode) Retryable() bool {
+	switch c {
+	case ErrorCodeRateLimited, ErrorCodeNetwork, ErrorCodeUnknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// Error is an error returned by an exchange, classified by code
+type Error struct {
+	Exchange string
+	Code     ErrorCode
+	Message  string // Exchange's description of the error
+	Err      error  // Underlying error
+}
+
+// NewError creates an exchange error
+func NewError(exchange string, code ErrorCode, message string, err error) *Error {
+	return &Error{Exchange: exchange, Code: code, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Message != "" && e.Err != nil:
+		return fmt.Sprintf("%s: %s: %v", e.Exchange, e.Message, e.Err)
+	case e.Message != "":
+		return fmt.Sprintf("%s: %s", e.Exchange, e.Message)
+	default:
+		return fmt.Sprintf("%s: %v", e.Exchange, e.Err)
+	}
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches the sentinel errors of the error's code, so errors.Is(err,
+// ErrInvalidOrder) holds for exchange errors with ErrorCodeInvalidOrder
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrInvalidOrder:
+		return e.Code == ErrorCodeInvalidOrder
+	case ErrNotSupported:
+		return e.Code == ErrorCodeNotSupported
+	default:
+		return false
+	}
+}
+
+// CodeOf returns the code of err. Errors that are not exchange errors are
+// classified from the sentinel errors they wrap.
+func CodeOf(err error) ErrorCode {
+	var exchangeErr *Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &exchangeErr):
+		return exchangeErr.Code
+	case errors.Is(err, ErrInvalidOrder):
+		return ErrorCodeInvalidOrder
+	case errors.Is(err, ErrNotSupported):
+		return ErrorCodeNotSupported
+	case errors.Is(err, ErrNotConnected):
+		return ErrorCodeNetwork
+	default:
+		return ErrorCodeUnknown
+	}
+}
+
+// messageCodes maps phrases exchanges use in error messages to codes, most
+// specific first
+var messageCodes = []struct {
+	phrase string
+	code   ErrorCode
+}{
+	{"insufficient", ErrorCodeInsufficientFunds},
+	{"undercollateralized", ErrorCodeInsufficientFunds},
+	{"not enough", ErrorCodeInsufficientFunds},
+	{"rate limit", ErrorCodeRateLimited},
+	{"too many requests", ErrorCodeRateLimited},
+	{"unauthorized", ErrorCodeAuth},
+	{"signature", ErrorCodeAuth},
+	{"api key", ErrorCodeAuth},
+	{"permission", ErrorCodeAuth},
+	{"unauthenticated", ErrorCodeAuth},
+	{"api wallet", ErrorCodeAuth},
+	{"not supported", ErrorCodeNotSupported},
+	{"unsupported", ErrorCodeNotSupported},
+	{"post only", ErrorCodeInvalidOrder},
+	{"minimum", ErrorCodeInvalidOrder},
+	{"precision", ErrorCodeInvalidOrder},
+	{"invalid", ErrorCodeInvalidOrder},
+}
+
+// ClassifyMessage returns the code of an error message in free text, or
+// fallback when no known phrase appears in it
+func ClassifyMessage(message string, fallback ErrorCode) ErrorCode {
+	message = strings.ToLower(strings.ReplaceAll(message, "_", " "))
+	for _, mc := range messageCodes {
+		if strings.Contains(message, mc.phrase) {
+			return mc.code
+		}
+	}
+	return fallback
+}
+
+// HTTPError classifies err, an error of a request to the REST API of
+// exchange. The body of responses refusing the request is classified by
+// classify, which returns ErrorCodeRejected when it does not recognise it.
+func HTTPError(exchange string, err error, classify func(body string) ErrorCode) error {
+	var reqErr *httpclient.Error
+	if !errors.As(err, &reqErr) {
+		return err
+	}
+
+	var code ErrorCode
+	switch reqErr.Kind {
+	case httpclient.KindRateLimited:
+		code = ErrorCodeRateLimited
+	case httpclient.KindAuth:
+		code = ErrorCodeAuth
+	case httpclient.KindTransient:
+		code = ErrorCodeNetwork
+	default:
+		code = classify(reqErr.Body)
+	}
+	return NewError(exchange, code, "", err)
+}
@@ -0,0 +1,100 @@
+package exchanges
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/httpclient"
+)
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{name: "nil", err: nil, want: ""},
+		{name: "plain", err: errors.New("boom"), want: ErrorCodeUnknown},
+		{name: "sentinel", err: fmt.Errorf("%w: bad tif", ErrInvalidOrder), want: ErrorCodeInvalidOrder},
+		{name: "not connected", err: ErrNotConnected, want: ErrorCodeNetwork},
+		{name: "wrapped exchange error", err: fmt.Errorf("failed to place order: %w",
+			NewError("test", ErrorCodeInsufficientFunds, "no funds", nil)), want: ErrorCodeInsufficientFunds},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CodeOf(tt.err); got != tt.want {
+				t.Errorf("CodeOf() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestError_IsSentinel(t *testing.T) {
+	err := NewError("test", ErrorCodeInvalidOrder, "order must have minimum value", nil)
+	if !errors.Is(err, ErrInvalidOrder) {
+		t.Error("expected an invalid order error to match ErrInvalidOrder")
+	}
+	if errors.Is(err, ErrNotSupported) {
+		t.Error("expected an invalid order error not to match ErrNotSupported")
+	}
+	if err.Error() != "test: order must have minimum value" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
+
+func TestClassifyMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		want    ErrorCode
+	}{
+		{"INSUFFICIENT_FUND", ErrorCodeInsufficientFunds},
+		{"Insufficient margin to place order.", ErrorCodeInsufficientFunds},
+		{"subaccount would be undercollateralized", ErrorCodeInsufficientFunds},
+		{"PERMISSION_DENIED", ErrorCodeAuth},
+		{"User or API Wallet 0x12 does not exist.", ErrorCodeAuth},
+		{"UNSUPPORTED_ORDER_CONFIGURATION", ErrorCodeNotSupported},
+		{"Post only order would have immediately matched", ErrorCodeInvalidOrder},
+		{"Order must have minimum value of $10.", ErrorCodeInvalidOrder},
+		{"something else", ErrorCodeRejected},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyMessage(tt.message, ErrorCodeRejected); got != tt.want {
+			t.Errorf("ClassifyMessage(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	classify := func(body string) ErrorCode {
+		return ClassifyMessage(body, ErrorCodeRejected)
+	}
+	tests := []struct {
+		err  *httpclient.Error
+		want ErrorCode
+	}{
+		{&httpclient.Error{Kind: httpclient.KindRateLimited, StatusCode: 429}, ErrorCodeRateLimited},
+		{&httpclient.Error{Kind: httpclient.KindAuth, StatusCode: 401}, ErrorCodeAuth},
+		{&httpclient.Error{Kind: httpclient.KindTransient, Err: errors.New("timeout")}, ErrorCodeNetwork},
+		{&httpclient.Error{Kind: httpclient.KindFatal, StatusCode: 400, Body: "insufficient balance"}, ErrorCodeInsufficientFunds},
+		{&httpclient.Error{Kind: httpclient.KindFatal, StatusCode: 404, Body: "not found"}, ErrorCodeRejected},
+	}
+
+	for _, tt := range tests {
+		err := HTTPError("test", tt.err, classify)
+		if got := CodeOf(err); got != tt.want {
+			t.Errorf("%v: code %q, want %q", tt.err, got, tt.want)
+		}
+		var reqErr *httpclient.Error
+		if !errors.As(err, &reqErr) {
+			t.Errorf("%v: expected the request error to be wrapped", tt.err)
+		}
+	}
+
+	plain := errors.New("boom")
+	if HTTPError("test", plain, classify) != plain {
+		t.Error("expected errors other than request errors to be returned unchanged")
+	}
+}
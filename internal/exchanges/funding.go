@@ -0,0 +1,22 @@
+package exchanges
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FundingRate is the rate perpetual positions pay or receive at the next
+// funding, longs paying shorts when it is positive
+type FundingRate struct {
+	Symbol        string
+	Rate          decimal.Decimal // Fraction of the position notional
+	NextFundingAt time.Time       // Zero when the venue does not report it
+}
+
+// FundingReporter is implemented by exchanges trading perpetuals that report
+// their funding rate
+type FundingReporter interface {
+	GetFundingRate(ctx context.Context, symbol string) (*FundingRate, error)
+}
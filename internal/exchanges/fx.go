@@ -0,0 +1,224 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultFXRateTTL is how long a conversion rate is used before it is
+// fetched again
+const DefaultFXRateTTL = time.Minute
+
+// FXConverter converts amounts into a reporting currency using the tickers of
+// an exchange's fiat and stablecoin pairs. Rates are cached: Convert only
+// reads the cache, and currencies it misses are fetched by the next Refresh.
+type FXConverter struct {
+	exchange Exchange
+	currency string
+	ttl      time.Duration
+
+	// pegs maps a currency to the one it is treated as equal to, so pairs
+	// quoted in either can be used
+	pegs map[string]string
+
+	mu    sync.RWMutex
+	rates map[string]fxRate
+	seen  map[string]bool // Every currency asked for, refreshed together
+}
+
+type fxRate struct {
+	rate decimal.Decimal
+	at   time.Time
+}
+
+// NewFXConverter creates a converter into currency using the tickers of
+// exchange. USDC is pegged to USD.
+func NewFXConverter(exchange Exchange, currency string) *FXConverter {
+	return &FXConverter{
+		exchange: exchange,
+		currency: strings.ToUpper(currency),
+		ttl:      DefaultFXRateTTL,
+		pegs:     map[string]string{"USDC": "USD"},
+		rates:    make(map[string]fxRate),
+		seen:     make(map[string]bool),
+	}
+}
+
+// Currency returns the reporting currency
+func (c *FXConverter) Currency() string {
+	return c.currency
+}
+
+// SetPeg treats currency as equal to target, e.g. a stablecoin to its fiat
+func (c *FXConverter) SetPeg(currency, target string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pegs[strings.ToUpper(currency)] = strings.ToUpper(target)
+}
+
+// Convert converts amount from currency with the cached rate. It reports
+// false when the rate has not been fetched yet.
+func (c *FXConverter) Convert(amount decimal.Decimal, from string) (decimal.Decimal, bool) {
+	from = strings.ToUpper(from)
+	if c.same(from, c.currency) {
+		return amount, true
+	}
+
+	c.mu.RLock()
+	rate, ok := c.rates[from]
+	c.mu.RUnlock()
+	if !ok {
+		c.mu.Lock()
+		c.seen[from] = true
+		c.mu.Unlock()
+		return decimal.Zero, false
+	}
+	return amount.Mul(rate.rate), true
+}
+
+// ConvertContext converts amount from currency, fetching the rate when it is
+// missing or older than the TTL
+func (c *FXConverter) ConvertContext(ctx context.Context, amount decimal.Decimal, from string) (decimal.Decimal, error) {
+	rate, err := c.Rate(ctx, from)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return amount.Mul(rate), nil
+}
+
+// Rate returns the rate converting currency into the reporting currency. A
+// rate that cannot be refreshed is kept until it is fetched again.
+func (c *FXConverter) Rate(ctx context.Context, from string) (decimal.Decimal, error) {
+	from = strings.ToUpper(from)
+	if c.same(from, c.currency) {
+		return decimal.NewFromInt(1), nil
+	}
+
+	c.mu.Lock()
+	c.seen[from] = true
+	cached, ok := c.rates[from]
+	c.mu.Unlock()
+	if ok && time.Since(cached.at) < c.ttl {
+		return cached.rate, nil
+	}
+
+	rate, err := c.fetch(ctx, from)
+	if err != nil {
+		if ok {
+			logger.Component("fx").Warn("using stale conversion rate",
+				"currency", from, "age", time.Since(cached.at).Round(time.Second), "error", err)
+			return cached.rate, nil
+		}
+		return decimal.Zero, err
+	}
+
+	c.mu.Lock()
+	c.rates[from] = fxRate{rate: rate, at: time.Now()}
+	c.mu.Unlock()
+	return rate, nil
+}
+
+// Refresh fetches the rates of currencies and of every currency asked for
+// before, when they are older than the TTL
+func (c *FXConverter) Refresh(ctx context.Context, currencies ...string) {
+	c.mu.Lock()
+	for _, currency := range currencies {
+		c.seen[strings.ToUpper(currency)] = true
+	}
+	pending := make([]string, 0, len(c.seen))
+	for currency := range c.seen {
+		pending = append(pending, currency)
+	}
+	c.mu.Unlock()
+
+	sort.Strings(pending)
+	for _, currency := range pending {
+		if _, err := c.Rate(ctx, currency); err != nil {
+			logger.Component("fx").Warn("failed to fetch conversion rate",
+				"currency", currency, "reporting_currency", c.currency, "error", err)
+		}
+	}
+}
+
+// fetch reads the rate of from in the reporting currency from the ticker of
+// a direct or inverse pair, in any currency pegged to either
+func (c *FXConverter) fetch(ctx context.Context, from string) (decimal.Decimal, error) {
+	for _, base := range c.aliases(from) {
+		for _, quote := range c.aliases(c.currency) {
+			if price, err := c.price(ctx, base+"-"+quote); err == nil {
+				return price, nil
+			}
+			if price, err := c.price(ctx, quote+"-"+base); err == nil {
+				return decimal.NewFromInt(1).Div(price), nil
+			}
+		}
+	}
+	return decimal.Zero, fmt.Errorf("no %s/%s pair on %s", from, c.currency, c.exchange.Name())
+}
+
+// price returns the mid price of symbol, or its last price without a quote
+func (c *FXConverter) price(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	ticker, err := c.exchange.GetTicker(ctx, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	price := ticker.Last
+	if ticker.Bid.IsPositive() && ticker.Ask.IsPositive() {
+		price = ticker.Bid.Add(ticker.Ask).Div(decimal.NewFromInt(2))
+	}
+	if !price.IsPositive() {
+		return decimal.Zero, fmt.Errorf("no price for %s", symbol)
+	}
+	return price, nil
+}
+
+// canonical returns the currency currency is pegged to, or itself
+func (c *FXConverter) canonical(currency string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if target, ok := c.pegs[currency]; ok {
+		return target
+	}
+	return currency
+}
+
+func (c *FXConverter) same(a, b string) bool {
+	return c.canonical(a) == c.canonical(b)
+}
+
+// aliases returns currency followed by the currencies equal to it through
+// pegs
+func (c *FXConverter) aliases(currency string) []string {
+	target := c.canonical(currency)
+	aliases := []string{currency}
+	if target != currency {
+		aliases = append(aliases, target)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var pegged []string
+	for from, to := range c.pegs {
+		if to == target && from != currency {
+			pegged = append(pegged, from)
+		}
+	}
+	sort.Strings(pegged)
+	return append(aliases, pegged...)
+}
+
+// QuoteCurrency returns the quote currency of a symbol such as BTC-USD or
+// ETH/EUR, USD when the symbol has none
+func QuoteCurrency(symbol string) string {
+	if i := strings.LastIndexAny(symbol, "-/"); i >= 0 && i < len(symbol)-1 {
+		return strings.ToUpper(symbol[i+1:])
+	}
+	return "USD"
+}
@@ -0,0 +1,142 @@
+package exchanges
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// fxExchange quotes the pairs in its tickers map
+type fxExchange struct {
+	*MockExchange
+	tickers map[string]decimal.Decimal
+	calls   int
+}
+
+func (e *fxExchange) GetTicker(ctx context.Context, symbol string) (*Ticker, error) {
+	e.calls++
+	price, ok := e.tickers[symbol]
+	if !ok {
+		return nil, fmt.Errorf("unknown symbol %s", symbol)
+	}
+	return &Ticker{Symbol: symbol, Bid: price, Ask: price, Last: price}, nil
+}
+
+func TestFXConverter_Rates(t *testing.T) {
+	exchange := &fxExchange{
+		MockExchange: NewMockExchange("fx"),
+		tickers:      map[string]decimal.Decimal{"USDC-EUR": decimal.NewFromFloat(0.8)},
+	}
+	ctx := context.Background()
+
+	// USD converts through the USDC pair it is pegged to
+	toEUR := NewFXConverter(exchange, "eur")
+	if _, ok := toEUR.Convert(decimal.NewFromInt(100), "USD"); ok {
+		t.Error("expected no rate before it is fetched")
+	}
+	toEUR.Refresh(ctx)
+	amount, ok := toEUR.Convert(decimal.NewFromInt(100), "USD")
+	if !ok || !amount.Equal(decimal.NewFromInt(80)) {
+		t.Errorf("expected 100 USD to be 80 EUR, got %s (%v)", amount, ok)
+	}
+
+	// The inverse pair converts the other way
+	toUSD := NewFXConverter(exchange, "USD")
+	amount, err := toUSD.ConvertContext(ctx, decimal.NewFromInt(80), "EUR")
+	if err != nil || !amount.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("expected 80 EUR to be 100 USD, got %s (%v)", amount, err)
+	}
+
+	// Cached rates are not fetched again within the TTL
+	calls := exchange.calls
+	toUSD.ConvertContext(ctx, decimal.NewFromInt(1), "EUR")
+	if exchange.calls != calls {
+		t.Errorf("expected the cached rate to be used, got %d more calls", exchange.calls-calls)
+	}
+
+	if amount, ok := toUSD.Convert(decimal.NewFromInt(5), "USDC"); !ok || !amount.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("expected USDC to convert at par, got %s", amount)
+	}
+	if _, err := toUSD.ConvertContext(ctx, decimal.NewFromInt(1), "GBP"); err == nil {
+		t.Error("expected an error without a GBP pair")
+	}
+}
+
+func TestExchangeMultiplexer_RefreshDataConvertsTotals(t *testing.T) {
+	exchange := &fxExchange{
+		MockExchange: NewMockExchange("coinbase"),
+		tickers: map[string]decimal.Decimal{
+			"EUR-USD": decimal.NewFromFloat(1.1),
+			"BTC-USD": decimal.NewFromInt(50000),
+		},
+	}
+	exchange.balances = []Balance{
+		{Asset: "USD", Total: decimal.NewFromInt(1000)},
+		{Asset: "EUR", Total: decimal.NewFromInt(1000)},
+		{Asset: "BTC", Total: decimal.NewFromFloat(0.01)},
+		{Asset: "XYZ", Total: decimal.NewFromInt(5)}, // No rate: left out
+	}
+	exchange.positions = []Position{
+		{Symbol: "BTC-EUR", Size: decimal.NewFromFloat(0.01), EntryPrice: decimal.NewFromInt(40000), UnrealizedPnL: decimal.NewFromInt(100)},
+	}
+
+	multiplexer := NewExchangeMultiplexer()
+	multiplexer.AddExchange("coinbase", exchange)
+	multiplexer.SetFXConverter(NewFXConverter(exchange, "USD"))
+	if err := multiplexer.RefreshData(context.Background()); err != nil {
+		t.Fatalf("RefreshData failed: %v", err)
+	}
+
+	data := multiplexer.GetAggregatedData()
+	if data.Currency != "USD" {
+		t.Errorf("expected the reporting currency to be USD, got %q", data.Currency)
+	}
+	if !data.TotalBalance.Equal(decimal.NewFromInt(2600)) {
+		t.Errorf("expected a total balance of 2600 USD, got %s", data.TotalBalance)
+	}
+	if !data.TotalPnL.Equal(decimal.NewFromInt(110)) {
+		t.Errorf("expected the EUR PnL to be 110 USD, got %s", data.TotalPnL)
+	}
+	if len(data.Unconverted) != 1 || data.Unconverted[0] != "XYZ" {
+		t.Errorf("expected XYZ to be reported as unconverted, got %v", data.Unconverted)
+	}
+	if data.Exchanges["coinbase"].Error == nil {
+		t.Error("expected the exchange with an unconverted balance to report an error")
+	}
+}
+
+func TestQuoteCurrency(t *testing.T) {
+	for symbol, quote := range map[string]string{"BTC-USD": "USD", "ETH/eur": "EUR", "BTC": "USD"} {
+		if got := QuoteCurrency(symbol); got != quote {
+			t.Errorf("%s: expected %s, got %s", symbol, quote, got)
+		}
+	}
+}
+
+func TestExchangeMultiplexer_RefreshDataMarginBaseCurrency(t *testing.T) {
+	exchange := &fxExchange{
+		MockExchange: NewMockExchange("eu"),
+		tickers:      map[string]decimal.Decimal{"EUR-USD": decimal.NewFromFloat(1.1)},
+	}
+	multiplexer := NewExchangeMultiplexer()
+	multiplexer.AddExchange("eu", &marginReportingExchange{
+		MockExchange: exchange.MockExchange,
+		margin: AccountMargin{
+			Equity:         decimal.NewFromInt(1000),
+			MarginUsed:     decimal.NewFromInt(200),
+			FreeCollateral: decimal.NewFromInt(800),
+		},
+	})
+	multiplexer.Configure("eu", ExchangeSettings{BaseCurrency: "EUR"})
+	multiplexer.SetFXConverter(NewFXConverter(exchange, "USD"))
+	if err := multiplexer.RefreshData(context.Background()); err != nil {
+		t.Fatalf("RefreshData failed: %v", err)
+	}
+
+	data := multiplexer.GetAggregatedData()
+	if !data.TotalEquity.Equal(decimal.NewFromInt(1100)) || !data.TotalFreeCollateral.Equal(decimal.NewFromInt(880)) {
+		t.Errorf("expected the EUR margin converted to USD, got equity %s, free %s", data.TotalEquity, data.TotalFreeCollateral)
+	}
+}
@@ -1,7 +1,6 @@
 package hyperliquid
 
 import (
-	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/hmac"
@@ -9,7 +8,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"io"
 	"math/big"
 	"net/http"
 	"sort"
@@ -25,7 +23,7 @@ import (
 	"github.com/vmihailenco/msgpack/v5"
 
 	"github.com/guyghost/constantine/internal/exchanges"
-	"github.com/guyghost/constantine/internal/ratelimit"
+	"github.com/guyghost/constantine/internal/httpclient"
 	"github.com/guyghost/constantine/internal/telemetry"
 )
 
@@ -148,6 +146,13 @@ func floatToWire(x float64) string {
 	return fmt.Sprintf("%.8f", x)
 }
 
+// toCloid derives a Hyperliquid client order ID (128-bit hex) from an
+// arbitrary client order ID so the same request always maps to the same cloid
+func toCloid(clientOrderID string) string {
+	sum := sha256.Sum256([]byte(clientOrderID))
+	return "0x" + hex.EncodeToString(sum[:16])
+}
+
 // extractCoinFromSymbol extracts the coin name from a symbol (e.g., "BTC-USD" -> "BTC")
 func extractCoinFromSymbol(symbol string) string {
 	// Simple implementation - split on "-" and take first part
@@ -160,26 +165,18 @@ func extractCoinFromSymbol(symbol string) string {
 
 // HTTPClient handles REST API requests to Hyperliquid
 type HTTPClient struct {
-	baseURL     string
-	apiKey      string
-	apiSecret   string
-	httpClient  *http.Client
-	rateLimiter ratelimit.Limiter
+	apiKey    string
+	apiSecret string
+	client    *httpclient.Client
+	clock     exchanges.Clock // Server time signatures and nonces are stamped with
 }
 
 // NewHTTPClient creates a new HTTP client for Hyperliquid
 func NewHTTPClient(baseURL, apiKey, apiSecret string) *HTTPClient {
-	// Create rate limiter with burst capability
-	limiter := ratelimit.NewTokenBucket(hyperliquidRateLimit, int(hyperliquidRateLimit*2))
-
 	return &HTTPClient{
-		baseURL:     baseURL,
-		apiKey:      apiKey,
-		apiSecret:   apiSecret,
-		rateLimiter: limiter,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		apiKey:    apiKey,
+		apiSecret: apiSecret,
+		client:    httpclient.New(httpclient.DefaultConfig("hyperliquid", baseURL, hyperliquidRateLimit)),
 	}
 }
 
@@ -204,7 +201,7 @@ func (c *HTTPClient) createAuthHeaders(method, path string, body []byte) map[str
 	}
 
 	// Create timestamp
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	timestamp := strconv.FormatInt(c.clock.Now().UnixMilli(), 10)
 
 	// Create message to sign: method + path + body + timestamp
 	message := method + path + string(body) + timestamp
@@ -220,66 +217,86 @@ func (c *HTTPClient) createAuthHeaders(method, path string, body []byte) map[str
 	return headers
 }
 
-// doRequest performs an HTTP request
+// doRequest performs an HTTP request. Info requests are read-only and
+// retried on transient failures.
 func (c *HTTPClient) doRequest(ctx context.Context, method, path string, body any, result any) error {
-	// Apply rate limiting before making the request
-	if err := c.rateLimiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limit wait failed: %w", err)
-	}
-
-	start := time.Now()
-
 	var reqBody []byte
-	var reqBodyReader io.Reader
-
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
 			return fmt.Errorf("failed to marshal request body: %w", err)
 		}
 		reqBody = jsonData
-		reqBodyReader = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBodyReader)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(ctx, &httpclient.Request{
+		Method:     method,
+		Path:       path,
+		Body:       reqBody,
+		Header:     header,
+		Idempotent: path == "/info",
+		Sign: func(req *http.Request) error {
+			// Add authentication headers for exchange endpoints
+			if strings.Contains(path, "/exchange") && c.apiKey != "" && c.apiSecret != "" {
+				for key, value := range c.createAuthHeaders(method, path, reqBody) {
+					req.Header.Set(key, value)
+				}
+			}
+			return nil
+		},
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return apiError(err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	// Add authentication headers for exchange endpoints
-	if strings.Contains(path, "/exchange") && c.apiKey != "" && c.apiSecret != "" {
-		authHeaders := c.createAuthHeaders(method, path, reqBody)
-		for key, value := range authHeaders {
-			req.Header.Set(key, value)
+	if result != nil {
+		if err := json.Unmarshal(resp.Body, result); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
 		}
 	}
 
-	resp, err := c.httpClient.Do(req)
+	return nil
+}
+
+// syncClock measures the offset to the Hyperliquid server time. The API has
+// no time endpoint, and the Date header only has a resolution of one second,
+// as coarse as the skew tolerated. The time of an order book snapshot, the
+// last block, is in milliseconds instead.
+func (c *HTTPClient) syncClock(ctx context.Context) (time.Duration, error) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(ctx, &httpclient.Request{
+		Method:     http.MethodPost,
+		Path:       "/info",
+		Body:       []byte(`{"type":"l2Book","coin":"BTC"}`),
+		Header:     header,
+		Idempotent: true,
+	})
 	if err != nil {
-		telemetry.RecordAPIRequest("hyperliquid", path, time.Since(start))
-		return fmt.Errorf("failed to execute request: %w", err)
+		return 0, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		telemetry.RecordAPIRequest("hyperliquid", path, time.Since(start))
-		return fmt.Errorf("API error: status=%d", resp.StatusCode)
+	var result struct {
+		Time int64 `json:"time"` // Unix milliseconds
 	}
-
-	if result != nil {
-		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
-			telemetry.RecordAPIRequest("hyperliquid", path, time.Since(start))
-			return fmt.Errorf("failed to decode response: %w", err)
-		}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
 	}
+	if result.Time <= 0 {
+		return 0, fmt.Errorf("missing server time in order book response")
+	}
+	return c.clock.Measure(resp.Sent, resp.Received, time.UnixMilli(result.Time)), nil
+}
 
-	// Record successful request
-	telemetry.RecordAPIRequest("hyperliquid", path, time.Since(start))
-
-	return nil
+// SyncClock corrects the timestamps and nonces of signed requests to the
+// Hyperliquid server time
+func (c *Client) SyncClock(ctx context.Context) (time.Duration, error) {
+	return c.httpClient.syncClock(ctx)
 }
 
 // Client implements the exchanges.Exchange interface for Hyperliquid
@@ -293,6 +310,7 @@ type Client struct {
 	mu         sync.RWMutex
 	httpClient *HTTPClient
 	privateKey *ecdsa.PrivateKey
+	twaps      map[string]*twapOrder // Native TWAPs placed, by order ID
 }
 
 // NewClient creates a new Hyperliquid client
@@ -636,6 +654,28 @@ func (c *Client) SubscribeTrades(ctx context.Context, symbol string, callback fu
 	return c.ws.SubscribeTrades(ctx, symbol, callback)
 }
 
+// Streaming reports whether the websocket carries any subscription
+func (c *Client) Streaming() bool {
+	return c.ws != nil && c.ws.HasSubscriptions()
+}
+
+// LastMessageAt returns when the websocket last received a message, zero when
+// it is not connected or has received nothing yet
+func (c *Client) LastMessageAt() time.Time {
+	if c.ws == nil {
+		return time.Time{}
+	}
+	return c.ws.LastMessageAt()
+}
+
+// ReconnectStream reconnects the websocket and renews its subscriptions
+func (c *Client) ReconnectStream(ctx context.Context) error {
+	if c.ws == nil {
+		return fmt.Errorf("websocket not connected")
+	}
+	return c.ws.Reconnect(ctx)
+}
+
 // HyperliquidOrderRequest represents the request body for placing orders
 type HyperliquidOrderRequest struct {
 	Type   string `json:"type"`
@@ -646,7 +686,7 @@ type HyperliquidOrderRequest struct {
 		Size      string `json:"sz"`
 		OrderType struct {
 			Limit struct {
-				Tif string `json:"tif"` // Time in force: "Gtc" (Good till cancel)
+				Tif string `json:"tif"` // Time in force: "Gtc", "Ioc" or "Alo" (post-only)
 			} `json:"limit"`
 		} `json:"orderType"`
 	} `json:"orders"`
@@ -657,6 +697,9 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 	if c.privateKey == nil {
 		return nil, fmt.Errorf("hyperliquid requires a private key to place orders")
 	}
+	if order.Options != nil && order.Options.TWAP != nil {
+		return c.placeTWAP(ctx, order)
+	}
 
 	// Extract coin from symbol
 	coin := extractCoinFromSymbol(order.Symbol)
@@ -671,6 +714,11 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 		isBuy = true
 	}
 
+	orderType, err := hyperliquidOrderType(order)
+	if err != nil {
+		return nil, err
+	}
+
 	// Convert price and size to wire format
 	priceStr := floatToWire(order.Price.InexactFloat64())
 	sizeStr := floatToWire(order.Amount.InexactFloat64())
@@ -681,12 +729,11 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 		"b": isBuy,
 		"p": priceStr,
 		"s": sizeStr,
-		"r": false, // reduceOnly - set to false for now
-		"t": map[string]interface{}{
-			"limit": map[string]interface{}{
-				"tif": "Gtc", // Time in force: Good till cancel
-			},
-		},
+		"r": order.ReduceOnly,
+		"t": orderType,
+	}
+	if order.ClientOrderID != "" {
+		orderWire["c"] = toCloid(order.ClientOrderID)
 	}
 
 	// Create order action
@@ -695,9 +742,12 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 		"orders":   []interface{}{orderWire},
 		"grouping": "na",
 	}
+	if builder := builderWire(order.Options); builder != nil {
+		orderAction["builder"] = builder
+	}
 
 	// Get timestamp for nonce
-	timestamp := time.Now().UnixMilli()
+	timestamp := c.httpClient.clock.Now().UnixMilli()
 
 	// Sign the action
 	signature, err := signL1Action(c.privateKey, orderAction, nil, timestamp, nil, c.baseURL == hyperliquidAPIURL)
@@ -718,12 +768,19 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 	if err != nil {
 		return nil, fmt.Errorf("failed to place order: %w", err)
 	}
+	if err := responseError("order rejected", response); err != nil {
+		return nil, err
+	}
 
 	// Check response
 	if status, ok := response["status"].(string); ok && status == "ok" {
 		if respData, ok := response["response"].(map[string]interface{}); ok {
 			if data, ok := respData["data"].(map[string]interface{}); ok {
 				if statuses, ok := data["statuses"].([]interface{}); ok && len(statuses) > 0 {
+					// Trigger orders may be acknowledged without their order ID
+					if status, ok := statuses[0].(string); ok && status == "waitingForTrigger" {
+						return c.triggerOrderPlaced(ctx, order)
+					}
 					if statusData, ok := statuses[0].(map[string]interface{}); ok {
 						if resting, ok := statusData["resting"].(map[string]interface{}); ok {
 							if oid, ok := resting["oid"].(float64); ok {
@@ -734,6 +791,31 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 								return order, nil
 							}
 						}
+						// Immediate or cancel orders fill without resting
+						if filled, ok := statusData["filled"].(map[string]interface{}); ok {
+							if oid, ok := filled["oid"].(float64); ok {
+								order.ID = fmt.Sprintf("%d", int64(oid))
+								order.Status = exchanges.OrderStatusFilled
+								if size, ok := filled["totalSz"].(string); ok {
+									order.FilledAmount, _ = decimal.NewFromString(size)
+									order.Filled = order.FilledAmount
+									order.Remaining = order.Amount.Sub(order.FilledAmount)
+									if order.Remaining.IsPositive() {
+										order.Status = exchanges.OrderStatusPartially
+									}
+								}
+								if price, ok := filled["avgPx"].(string); ok {
+									order.AveragePrice, _ = decimal.NewFromString(price)
+								}
+								order.CreatedAt = time.Now()
+								order.UpdatedAt = time.Now()
+								return order, nil
+							}
+						}
+						// Post-only orders that would cross and unfilled IOC orders are rejected
+						if message, ok := statusData["error"].(string); ok {
+							return nil, actionError("order rejected", message)
+						}
 					}
 				}
 			}
@@ -743,11 +825,86 @@ func (c *Client) PlaceOrder(ctx context.Context, order *exchanges.Order) (*excha
 	return nil, fmt.Errorf("failed to parse order response")
 }
 
+// hyperliquidOrderType returns the wire order type of order: a trigger for
+// stop orders, a limit with its time in force otherwise
+func hyperliquidOrderType(order *exchanges.Order) (map[string]interface{}, error) {
+	if order.Type.IsStop() {
+		if order.PostOnly {
+			return nil, fmt.Errorf("%w: stop orders cannot be post-only", exchanges.ErrInvalidOrder)
+		}
+		triggerPrice := order.StopPrice
+		if triggerPrice.IsZero() {
+			triggerPrice = order.Price
+		}
+		if !triggerPrice.IsPositive() {
+			return nil, fmt.Errorf("%w: stop price required for stop orders", exchanges.ErrInvalidOrder)
+		}
+		return map[string]interface{}{
+			"trigger": map[string]interface{}{
+				"isMarket":  order.Type == exchanges.OrderTypeStopMarket,
+				"triggerPx": floatToWire(triggerPrice.InexactFloat64()),
+				"tpsl":      "sl",
+			},
+		}, nil
+	}
+
+	tif, err := hyperliquidTIF(order)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"limit": map[string]interface{}{
+			"tif": tif,
+		},
+	}, nil
+}
+
+// triggerOrderPlaced completes a trigger order acknowledged without its order
+// ID by looking it up by its client order ID
+func (c *Client) triggerOrderPlaced(ctx context.Context, order *exchanges.Order) (*exchanges.Order, error) {
+	if order.ClientOrderID == "" {
+		return nil, fmt.Errorf("failed to parse order response: trigger order placed without an order ID")
+	}
+	placed, err := c.GetOrderByClientID(ctx, order.Symbol, order.ClientOrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up trigger order: %w", err)
+	}
+	order.ID = placed.ID
+	order.Status = exchanges.OrderStatusOpen
+	order.CreatedAt = time.Now()
+	order.UpdatedAt = time.Now()
+	return order, nil
+}
+
+// hyperliquidTIF maps the time in force of an order to Hyperliquid, which
+// supports good till canceled, immediate or cancel and add liquidity only
+func hyperliquidTIF(order *exchanges.Order) (string, error) {
+	if err := exchanges.ValidateTimeInForce(order.TimeInForce, order.PostOnly, order.ExpiresAt); err != nil {
+		return "", err
+	}
+	if order.PostOnly {
+		if order.TimeInForce == exchanges.TimeInForceGTD {
+			return "", fmt.Errorf("%w: hyperliquid does not support good till date orders", exchanges.ErrInvalidOrder)
+		}
+		return "Alo", nil
+	}
+	switch order.TimeInForce {
+	case "", exchanges.TimeInForceGTC:
+		return "Gtc", nil
+	case exchanges.TimeInForceIOC:
+		return "Ioc", nil
+	}
+	return "", fmt.Errorf("%w: hyperliquid does not support %s orders", exchanges.ErrInvalidOrder, order.TimeInForce)
+}
+
 // CancelOrder cancels an existing order
 func (c *Client) CancelOrder(ctx context.Context, orderID string) error {
 	if c.privateKey == nil {
 		return fmt.Errorf("hyperliquid requires a private key to cancel orders")
 	}
+	if strings.HasPrefix(orderID, twapOrderPrefix) {
+		return c.cancelTWAP(ctx, orderID)
+	}
 
 	// Parse order ID to int64
 	oid, err := strconv.ParseInt(orderID, 10, 64)
@@ -766,7 +923,7 @@ func (c *Client) CancelOrder(ctx context.Context, orderID string) error {
 	}
 
 	// Get timestamp for nonce
-	timestamp := time.Now().UnixMilli()
+	timestamp := c.httpClient.clock.Now().UnixMilli()
 
 	// Sign the action
 	signature, err := signL1Action(c.privateKey, cancelAction, nil, timestamp, nil, c.baseURL == hyperliquidAPIURL)
@@ -787,6 +944,9 @@ func (c *Client) CancelOrder(ctx context.Context, orderID string) error {
 	if err != nil {
 		return fmt.Errorf("failed to cancel order: %w", err)
 	}
+	if err := responseError("cancel rejected", response); err != nil {
+		return err
+	}
 
 	// Check response
 	if status, ok := response["status"].(string); ok && status == "ok" {
@@ -839,6 +999,9 @@ func (c *Client) GetOrder(ctx context.Context, orderID string) (*exchanges.Order
 	if c.apiKey == "" {
 		return nil, fmt.Errorf("hyperliquid requires an ethereum address (set as API key) to query order status")
 	}
+	if strings.HasPrefix(orderID, twapOrderPrefix) {
+		return c.getTWAP(ctx, orderID)
+	}
 
 	// Parse order ID to int64
 	oid, err := strconv.ParseInt(orderID, 10, 64)
@@ -915,6 +1078,84 @@ func (c *Client) GetOrder(ctx context.Context, orderID string) (*exchanges.Order
 	return order, nil
 }
 
+// hyperliquidOrderQueryResponse represents an orderStatus lookup by cloid
+type hyperliquidOrderQueryResponse struct {
+	Status string `json:"status"` // "order" or "unknownOid"
+	Order  struct {
+		Order struct {
+			Coin      string `json:"coin"`
+			Side      string `json:"side"`
+			LimitPx   string `json:"limitPx"`
+			Sz        string `json:"sz"`
+			OrigSz    string `json:"origSz"`
+			Oid       int64  `json:"oid"`
+			Timestamp int64  `json:"timestamp"`
+		} `json:"order"`
+		Status string `json:"status"`
+	} `json:"order"`
+}
+
+// GetOrderByClientID finds an order by the client order ID it was placed with
+func (c *Client) GetOrderByClientID(ctx context.Context, symbol string, clientOrderID string) (*exchanges.Order, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("hyperliquid requires an ethereum address (set as API key) to query order status")
+	}
+
+	request := map[string]any{
+		"type": "orderStatus",
+		"user": c.apiKey,
+		"oid":  toCloid(clientOrderID),
+	}
+
+	var response hyperliquidOrderQueryResponse
+	if err := c.httpClient.doRequest(ctx, "POST", "/info", request, &response); err != nil {
+		return nil, fmt.Errorf("failed to get order by client id: %w", err)
+	}
+	if response.Status != "order" {
+		return nil, exchanges.ErrOrderNotFound
+	}
+
+	info := response.Order.Order
+	order := &exchanges.Order{
+		ID:            fmt.Sprintf("%d", info.Oid),
+		ClientOrderID: clientOrderID,
+		Symbol:        info.Coin + "-USD",
+		Type:          exchanges.OrderTypeLimit,
+		CreatedAt:     time.UnixMilli(info.Timestamp),
+		UpdatedAt:     time.Now(),
+	}
+	if info.Side == "B" || info.Side == "buy" {
+		order.Side = exchanges.OrderSideBuy
+	} else {
+		order.Side = exchanges.OrderSideSell
+	}
+	if price, err := decimal.NewFromString(info.LimitPx); err == nil {
+		order.Price = price
+	}
+	if size, err := decimal.NewFromString(info.OrigSz); err == nil {
+		order.Amount = size
+	} else if size, err := decimal.NewFromString(info.Sz); err == nil {
+		order.Amount = size
+	}
+	if remaining, err := decimal.NewFromString(info.Sz); err == nil {
+		order.Remaining = remaining
+		order.Filled = order.Amount.Sub(remaining)
+	}
+
+	switch response.Order.Status {
+	case "filled":
+		order.Status = exchanges.OrderStatusFilled
+	case "canceled", "cancelled", "marginCanceled":
+		order.Status = exchanges.OrderStatusCanceled
+	case "rejected":
+		order.Status = exchanges.OrderStatusRejected
+	default:
+		order.Status = exchanges.OrderStatusOpen
+	}
+
+	return order, nil
+}
+
 // GetOpenOrders retrieves all open orders
 func (c *Client) GetOpenOrders(ctx context.Context, symbol string) ([]exchanges.Order, error) {
 	if c.apiKey == "" {
@@ -1277,22 +1518,31 @@ func (c *Client) GetPositions(ctx context.Context) ([]exchanges.Position, error)
 			leverage = decimal.NewFromInt(int64(pos.Leverage.Value))
 		}
 
-		// Get current mark price (we'll fetch separately or approximate)
-		// For now, calculate from unrealized PnL
-		markPrice := entryPrice // Default to entry price
+		// The mark price is implied by the position's notional value
+		markPrice := entryPrice
+		if value, err := decimal.NewFromString(pos.PositionValue); err == nil && value.IsPositive() {
+			markPrice = value.Div(size)
+		}
+
+		// Null when the position cannot be liquidated, e.g. fully collateralized
+		liquidationPrice, err := decimal.NewFromString(pos.LiquidationPx)
+		if err != nil {
+			liquidationPrice = decimal.Zero
+		}
 
 		// Construct symbol (coin + "-USD")
 		symbol := pos.Coin + "-USD"
 
 		position := exchanges.Position{
-			Symbol:        symbol,
-			Side:          side,
-			Size:          size,
-			EntryPrice:    entryPrice,
-			MarkPrice:     markPrice,
-			Leverage:      leverage,
-			UnrealizedPnL: unrealizedPnL,
-			RealizedPnL:   decimal.Zero, // Not provided in this response
+			Symbol:           symbol,
+			Side:             side,
+			Size:             size,
+			EntryPrice:       entryPrice,
+			MarkPrice:        markPrice,
+			Leverage:         leverage,
+			UnrealizedPnL:    unrealizedPnL,
+			RealizedPnL:      decimal.Zero, // Not provided in this response
+			LiquidationPrice: liquidationPrice,
 		}
 
 		positions = append(positions, position)
@@ -1332,6 +1582,20 @@ func (c *Client) SupportedSymbols() []string {
 	return []string{"BTC-USD", "ETH-USD", "SOL-USD", "ARB-USD"}
 }
 
+// Capabilities returns the features of the client. Stops are sent as trigger
+// orders.
+func (c *Client) Capabilities() exchanges.Capabilities {
+	return exchanges.Capabilities{
+		Shorts:     true,
+		StopOrders: true,
+		StopMarket: true,
+		PostOnly:   true,
+		ReduceOnly: true,
+		Margin:     true,
+		NativeTWAP: true,
+	}
+}
+
 // SubscribeCandles subscribes to candle updates (using periodic REST API calls)
 func (c *Client) SubscribeCandles(ctx context.Context, symbol string, interval string, callback func(*exchanges.Candle)) error {
 	// Hyperliquid doesn't provide real-time candle streams via WebSocket
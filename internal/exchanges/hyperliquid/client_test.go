@@ -1,8 +1,14 @@
 package hyperliquid
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/shopspring/decimal"
@@ -50,6 +56,66 @@ func TestExtractCoinFromSymbol(t *testing.T) {
 	}
 }
 
+func TestHyperliquidTIF(t *testing.T) {
+	tests := []struct {
+		name     string
+		tif      exchanges.TimeInForce
+		postOnly bool
+		expected string
+		wantErr  bool
+	}{
+		{name: "default", expected: "Gtc"},
+		{name: "gtc", tif: exchanges.TimeInForceGTC, expected: "Gtc"},
+		{name: "ioc", tif: exchanges.TimeInForceIOC, expected: "Ioc"},
+		{name: "post-only", postOnly: true, expected: "Alo"},
+		{name: "fok unsupported", tif: exchanges.TimeInForceFOK, wantErr: true},
+		{name: "post-only ioc", tif: exchanges.TimeInForceIOC, postOnly: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tif, err := hyperliquidTIF(&exchanges.Order{TimeInForce: tt.tif, PostOnly: tt.postOnly})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("hyperliquidTIF() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tif != tt.expected {
+				t.Errorf("hyperliquidTIF() = %s, want %s", tif, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHyperliquidOrderType(t *testing.T) {
+	stop := &exchanges.Order{Type: exchanges.OrderTypeStopMarket, StopPrice: decimal.NewFromInt(45000)}
+	orderType, err := hyperliquidOrderType(stop)
+	if err != nil {
+		t.Fatalf("hyperliquidOrderType() error = %v", err)
+	}
+	trigger, ok := orderType["trigger"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a trigger order, got %v", orderType)
+	}
+	if trigger["isMarket"] != true || trigger["triggerPx"] != floatToWire(45000) || trigger["tpsl"] != "sl" {
+		t.Errorf("unexpected trigger %v", trigger)
+	}
+
+	stop.Type = exchanges.OrderTypeStopLimit
+	orderType, _ = hyperliquidOrderType(stop)
+	if orderType["trigger"].(map[string]interface{})["isMarket"] != false {
+		t.Error("stop limit orders should not trigger at market")
+	}
+
+	stop.PostOnly = true
+	if _, err := hyperliquidOrderType(stop); err == nil {
+		t.Error("expected post-only stops to be rejected")
+	}
+
+	orderType, err = hyperliquidOrderType(&exchanges.Order{Type: exchanges.OrderTypeLimit, PostOnly: true})
+	if err != nil || orderType["limit"].(map[string]interface{})["tif"] != "Alo" {
+		t.Errorf("hyperliquidOrderType() = %v, %v; expected a post-only limit", orderType, err)
+	}
+}
+
 func TestSupportedSymbols(t *testing.T) {
 	client := NewClient("", "")
 
@@ -469,3 +535,75 @@ func TestGetOrderHistory(t *testing.T) {
 func contains(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
+
+func TestHyperliquidFillTrade(t *testing.T) {
+	fill, ok := hyperliquidFill{
+		Coin:    "ETH",
+		Px:      "2000.5",
+		Sz:      "0.3",
+		Side:    "A",
+		Time:    1700000000000,
+		Oid:     42,
+		Tid:     7,
+		Fee:     "-0.01",
+		Crossed: false,
+	}.trade()
+	if !ok {
+		t.Fatal("valid fill was rejected")
+	}
+	if fill.ID != "7" || fill.OrderID != "42" || fill.Symbol != "ETH-USD" {
+		t.Errorf("unexpected identifiers: %+v", fill)
+	}
+	if fill.Side != exchanges.OrderSideSell || !fill.Maker {
+		t.Errorf("expected a maker sell, got %s maker=%v", fill.Side, fill.Maker)
+	}
+	if !fill.Price.Equal(decimal.NewFromFloat(2000.5)) || !fill.Amount.Equal(decimal.NewFromFloat(0.3)) {
+		t.Errorf("unexpected price or size: %s %s", fill.Price, fill.Amount)
+	}
+	if !fill.Fee.Equal(decimal.NewFromFloat(-0.01)) {
+		t.Errorf("expected the rebate as a negative fee, got %s", fill.Fee)
+	}
+
+	if _, ok := (hyperliquidFill{Px: "bad", Sz: "1"}).trade(); ok {
+		t.Error("fill with an invalid price should be rejected")
+	}
+}
+
+func TestHyperliquidFillLiquidation(t *testing.T) {
+	var fill hyperliquidFill
+	payload := `{"coin":"ETH","px":"1900","sz":"1","side":"A","dir":"Close Long","liquidation":{"liquidatedUser":"0xABC","markPx":"1899.5","method":"market"}}`
+	if err := json.Unmarshal([]byte(payload), &fill); err != nil {
+		t.Fatalf("failed to decode fill: %v", err)
+	}
+	if kind := fill.liquidation("0xabc"); kind != exchanges.LiquidationKindLiquidated {
+		t.Errorf("expected our position liquidated, got %q", kind)
+	}
+	if kind := fill.liquidation("0xdef"); kind != "" {
+		t.Errorf("liquidation of another user should be an ordinary fill, got %q", kind)
+	}
+	if kind := (hyperliquidFill{Dir: "Auto-Deleveraging"}).liquidation("0xabc"); kind != exchanges.LiquidationKindDeleveraged {
+		t.Errorf("expected an auto-deleveraging, got %q", kind)
+	}
+	if kind := (hyperliquidFill{Dir: "Open Long"}).liquidation("0xabc"); kind != "" {
+		t.Errorf("expected an ordinary fill, got %q", kind)
+	}
+}
+
+func TestSyncClock(t *testing.T) {
+	ahead := 1750 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A Date header a second behind would be read as a smaller skew
+		w.Header().Set("Date", time.Now().Add(-time.Second).UTC().Format(http.TimeFormat))
+		fmt.Fprintf(w, `{"coin":"BTC","time":%d,"levels":[[],[]]}`, time.Now().Add(ahead).UnixMilli())
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL("0xabc", "", server.URL, "")
+	offset, err := client.SyncClock(context.Background())
+	if err != nil {
+		t.Fatalf("SyncClock failed: %v", err)
+	}
+	if diff := offset - ahead; diff < -50*time.Millisecond || diff > 50*time.Millisecond {
+		t.Errorf("expected an offset of %s to the millisecond, got %s", ahead, offset)
+	}
+}
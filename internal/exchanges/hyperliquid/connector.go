@@ -0,0 +1,32 @@
+package hyperliquid
+
+import (
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+func init() {
+	exchanges.RegisterConnector("hyperliquid", exchanges.Connector{
+		LoadConfig: loadConfig,
+		New:        newFromConfig,
+	})
+}
+
+// loadConfig reads the Hyperliquid settings from the environment
+func loadConfig(getenv func(string) string) (exchanges.Config, error) {
+	cfg := exchanges.Config{
+		Enabled:   getenv("ENABLE_HYPERLIQUID") == "true",
+		APIKey:    getenv("HYPERLIQUID_API_KEY"),
+		APISecret: getenv("HYPERLIQUID_API_SECRET"),
+	}
+	if cfg.Enabled && (cfg.APIKey == "" || cfg.APISecret == "") {
+		return cfg, fmt.Errorf("hyperliquid enabled but API key or secret is missing")
+	}
+	return cfg, nil
+}
+
+// newFromConfig creates a client signing with the key of the configuration
+func newFromConfig(cfg exchanges.Config) (exchanges.Exchange, error) {
+	return NewClient(cfg.APIKey, cfg.APISecret), nil
+}
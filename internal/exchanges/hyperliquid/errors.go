@@ -0,0 +1,32 @@
+package hyperliquid
+
+import (
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// classifyError classifies the body of a Hyperliquid error response, which
+// is a plain message
+func classifyError(body string) exchanges.ErrorCode {
+	return exchanges.ClassifyMessage(body, exchanges.ErrorCodeRejected)
+}
+
+// apiError classifies an error of a request to the Hyperliquid API
+func apiError(err error) error {
+	return exchanges.HTTPError("hyperliquid", err, classifyError)
+}
+
+// actionError classifies an action the exchange refused in a successful
+// response, either as {"status":"err","response":"<message>"} or as the
+// error of an order status, e.g. "Insufficient margin to place order."
+func actionError(action, message string) error {
+	return exchanges.NewError("hyperliquid", classifyError(message), action+": "+message, nil)
+}
+
+// responseError returns the error of an action response with status "err"
+func responseError(action string, response map[string]interface{}) error {
+	if status, _ := response["status"].(string); status != "err" {
+		return nil
+	}
+	message, _ := response["response"].(string)
+	return actionError(action, message)
+}
@@ -0,0 +1,165 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/shopspring/decimal"
+)
+
+// hyperliquidFill is a fill of the account, as listed by userFillsByTime and
+// streamed on the userFills channel
+type hyperliquidFill struct {
+	Coin    string `json:"coin"`
+	Px      string `json:"px"`
+	Sz      string `json:"sz"`
+	Side    string `json:"side"` // "B" for buys, "A" for sells
+	Time    int64  `json:"time"` // Unix milliseconds
+	Oid     int64  `json:"oid"`
+	Tid     int64  `json:"tid"`
+	Fee     string `json:"fee"`
+	Crossed bool   `json:"crossed"` // Took liquidity
+	Dir     string `json:"dir"`     // e.g. "Open Long", or "Auto-Deleveraging"
+	// Liquidation is set on the fills of a liquidation, on both the side of
+	// the liquidated user and that of the liquidator
+	Liquidation *struct {
+		LiquidatedUser string `json:"liquidatedUser"`
+		MarkPx         string `json:"markPx"`
+		Method         string `json:"method"` // market or backstop
+	} `json:"liquidation"`
+}
+
+// liquidation returns how the fill closed a position of user without an order
+// of theirs, empty for an ordinary fill
+func (f hyperliquidFill) liquidation(user string) exchanges.LiquidationKind {
+	switch {
+	case f.Dir == "Auto-Deleveraging":
+		return exchanges.LiquidationKindDeleveraged
+	case f.Liquidation != nil && (f.Liquidation.LiquidatedUser == "" || strings.EqualFold(f.Liquidation.LiquidatedUser, user)):
+		return exchanges.LiquidationKindLiquidated
+	}
+	return ""
+}
+
+// trade converts the fill, reporting false when its price or size is invalid
+func (f hyperliquidFill) trade() (exchanges.Trade, bool) {
+	price, err := decimal.NewFromString(f.Px)
+	if err != nil {
+		return exchanges.Trade{}, false
+	}
+	size, err := decimal.NewFromString(f.Sz)
+	if err != nil {
+		return exchanges.Trade{}, false
+	}
+	fee, _ := decimal.NewFromString(f.Fee)
+
+	side := exchanges.OrderSideSell
+	if f.Side == "B" {
+		side = exchanges.OrderSideBuy
+	}
+	return exchanges.Trade{
+		ID:        strconv.FormatInt(f.Tid, 10),
+		OrderID:   strconv.FormatInt(f.Oid, 10),
+		Symbol:    f.Coin + "-USD",
+		Side:      side,
+		Price:     price,
+		Amount:    size,
+		Fee:       fee,
+		Timestamp: time.UnixMilli(f.Time),
+		Maker:     !f.Crossed,
+	}, true
+}
+
+// GetFills returns the fills of the account since the given time
+func (c *Client) GetFills(ctx context.Context, symbol string, since time.Time) ([]exchanges.Trade, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("hyperliquid requires an ethereum address (set as API key) to list fills")
+	}
+
+	request := map[string]any{
+		"type":      "userFillsByTime",
+		"user":      c.apiKey,
+		"startTime": since.UnixMilli(),
+	}
+
+	var response []hyperliquidFill
+	if err := c.httpClient.doRequest(ctx, "POST", "/info", request, &response); err != nil {
+		return nil, fmt.Errorf("failed to get fills: %w", err)
+	}
+
+	fills := make([]exchanges.Trade, 0, len(response))
+	for _, f := range response {
+		fill, ok := f.trade()
+		if !ok || (symbol != "" && fill.Symbol != symbol) {
+			continue
+		}
+		fill.Liquidation = f.liquidation(c.apiKey)
+		fills = append(fills, fill)
+	}
+	c.attributeTWAPFills(ctx, fills)
+	return fills, nil
+}
+
+// SubscribeFills streams the fills of the account
+func (c *Client) SubscribeFills(ctx context.Context, callback func(*exchanges.Trade)) error {
+	if c.ws == nil {
+		return fmt.Errorf("websocket not connected")
+	}
+	if c.apiKey == "" {
+		return fmt.Errorf("hyperliquid requires an ethereum address (set as API key) to stream fills")
+	}
+	return c.ws.SubscribeFills(ctx, c.apiKey, callback)
+}
+
+// SubscribeFills subscribes to the fills of user
+func (ws *WebSocketClient) SubscribeFills(ctx context.Context, user string, callback func(*exchanges.Trade)) error {
+	ws.mu.Lock()
+	ws.fillCallback = callback
+	ws.fillUser = user
+	ws.mu.Unlock()
+
+	sub := map[string]any{
+		"method": "subscribe",
+		"subscription": map[string]any{
+			"type": "userFills",
+			"user": user,
+		},
+	}
+
+	logger.Exchange("hyperliquid").Debug("subscribing to fills")
+	return ws.subscribe(sub)
+}
+
+// handleUserFillsMessage handles fills of the account. The snapshot sent on
+// subscription holds past fills, which are left to GetFills.
+func (ws *WebSocketClient) handleUserFillsMessage(message []byte) {
+	var msg struct {
+		Data struct {
+			IsSnapshot bool              `json:"isSnapshot"`
+			Fills      []hyperliquidFill `json:"fills"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Data.IsSnapshot {
+		return
+	}
+
+	ws.mu.RLock()
+	callback := ws.fillCallback
+	user := ws.fillUser
+	ws.mu.RUnlock()
+	if callback == nil {
+		return
+	}
+	for _, f := range msg.Data.Fills {
+		if fill, ok := f.trade(); ok {
+			fill.Liquidation = f.liquidation(user)
+			callback(&fill)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package hyperliquid
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// Hyperliquid sets the leverage and the margin mode of an asset together, so
+// each setter reads the other value back before updating both
+
+// SetLeverage sets the leverage of new positions on symbol, keeping its
+// margin mode. Hyperliquid only accepts whole leverages.
+func (c *Client) SetLeverage(ctx context.Context, symbol string, leverage decimal.Decimal) error {
+	current, err := c.GetLeverage(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	return c.updateLeverage(ctx, symbol, leverage, current.MarginMode)
+}
+
+// SetMarginMode sets the margin mode of symbol, keeping its leverage
+func (c *Client) SetMarginMode(ctx context.Context, symbol string, mode exchanges.MarginMode) error {
+	current, err := c.GetLeverage(ctx, symbol)
+	if err != nil {
+		return err
+	}
+	return c.updateLeverage(ctx, symbol, current.Leverage, mode)
+}
+
+// GetLeverage returns the leverage and margin mode of the account on symbol
+// and the maximum leverage of its asset
+func (c *Client) GetLeverage(ctx context.Context, symbol string) (*exchanges.LeverageSettings, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("hyperliquid requires the account address to read leverage")
+	}
+	coin := extractCoinFromSymbol(symbol)
+
+	var active struct {
+		Leverage struct {
+			Type  string `json:"type"`
+			Value int64  `json:"value"`
+		} `json:"leverage"`
+	}
+	request := map[string]any{
+		"type": "activeAssetData",
+		"user": c.apiKey,
+		"coin": coin,
+	}
+	if err := c.httpClient.doRequest(ctx, "POST", "/info", request, &active); err != nil {
+		return nil, fmt.Errorf("failed to get leverage: %w", err)
+	}
+
+	var meta struct {
+		Universe []struct {
+			Name        string `json:"name"`
+			MaxLeverage int64  `json:"maxLeverage"`
+		} `json:"universe"`
+	}
+	if err := c.httpClient.doRequest(ctx, "POST", "/info", map[string]any{"type": "meta"}, &meta); err != nil {
+		return nil, fmt.Errorf("failed to get asset metadata: %w", err)
+	}
+
+	settings := &exchanges.LeverageSettings{
+		Symbol:     symbol,
+		Leverage:   decimal.NewFromInt(active.Leverage.Value),
+		MarginMode: exchanges.MarginModeCross,
+	}
+	if active.Leverage.Type == "isolated" {
+		settings.MarginMode = exchanges.MarginModeIsolated
+	}
+	for _, asset := range meta.Universe {
+		if asset.Name == coin {
+			settings.Max = decimal.NewFromInt(asset.MaxLeverage)
+			break
+		}
+	}
+	return settings, nil
+}
+
+// updateLeverage sends the updateLeverage action for symbol
+func (c *Client) updateLeverage(ctx context.Context, symbol string, leverage decimal.Decimal, mode exchanges.MarginMode) error {
+	if c.privateKey == nil {
+		return fmt.Errorf("hyperliquid requires a private key to set leverage")
+	}
+	if !leverage.IsInteger() || leverage.LessThan(decimal.NewFromInt(1)) {
+		return fmt.Errorf("%w: hyperliquid leverage must be a whole number of at least 1, got %s", exchanges.ErrInvalidOrder, leverage)
+	}
+	if mode != exchanges.MarginModeCross && mode != exchanges.MarginModeIsolated {
+		return fmt.Errorf("%w: unknown margin mode %q", exchanges.ErrInvalidOrder, mode)
+	}
+
+	// For now, use coin name as asset, as PlaceOrder does
+	leverageAction := map[string]interface{}{
+		"type":     "updateLeverage",
+		"asset":    extractCoinFromSymbol(symbol),
+		"isCross":  mode == exchanges.MarginModeCross,
+		"leverage": leverage.IntPart(),
+	}
+
+	timestamp := c.httpClient.clock.Now().UnixMilli()
+	signature, err := signL1Action(c.privateKey, leverageAction, nil, timestamp, nil, c.baseURL == hyperliquidAPIURL)
+	if err != nil {
+		return fmt.Errorf("failed to sign leverage update: %w", err)
+	}
+
+	payload := map[string]interface{}{
+		"action":    leverageAction,
+		"nonce":     timestamp,
+		"signature": signature,
+	}
+
+	var response map[string]interface{}
+	if err := c.httpClient.doRequest(ctx, "POST", "/exchange", payload, &response); err != nil {
+		return fmt.Errorf("failed to set leverage: %w", err)
+	}
+	if err := responseError("leverage update rejected", response); err != nil {
+		return err
+	}
+	if status, _ := response["status"].(string); status != "ok" {
+		return fmt.Errorf("failed to set leverage: invalid response")
+	}
+	return nil
+}
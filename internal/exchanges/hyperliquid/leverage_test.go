@@ -0,0 +1,63 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func TestSetLeverage(t *testing.T) {
+	var action map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch {
+		case r.URL.Path == "/exchange":
+			action, _ = body["action"].(map[string]interface{})
+			w.Write([]byte(`{"status":"ok","response":{"type":"default"}}`))
+		case body["type"] == "activeAssetData":
+			w.Write([]byte(`{"user":"0xabc","coin":"ETH","leverage":{"type":"isolated","value":3}}`))
+		case body["type"] == "meta":
+			w.Write([]byte(`{"universe":[{"name":"BTC","maxLeverage":40},{"name":"ETH","maxLeverage":25}]}`))
+		}
+	}))
+	defer server.Close()
+
+	dummyPrivateKey := "1234567890123456789012345678901234567890123456789012345678901234"
+	client := NewClientWithURL("0xabc", dummyPrivateKey, server.URL, "")
+	ctx := context.Background()
+
+	settings, err := client.GetLeverage(ctx, "ETH-USD")
+	if err != nil {
+		t.Fatalf("GetLeverage failed: %v", err)
+	}
+	if !settings.Leverage.Equal(decimal.NewFromInt(3)) || !settings.Max.Equal(decimal.NewFromInt(25)) ||
+		settings.MarginMode != exchanges.MarginModeIsolated {
+		t.Errorf("Unexpected settings %+v", settings)
+	}
+
+	// The margin mode read back is kept when only the leverage changes
+	if err := client.SetLeverage(ctx, "ETH-USD", decimal.NewFromInt(5)); err != nil {
+		t.Fatalf("SetLeverage failed: %v", err)
+	}
+	if action["type"] != "updateLeverage" || action["asset"] != "ETH" || action["isCross"] != false || action["leverage"] != float64(5) {
+		t.Errorf("Unexpected action %v", action)
+	}
+
+	if err := client.SetMarginMode(ctx, "ETH-USD", exchanges.MarginModeCross); err != nil {
+		t.Fatalf("SetMarginMode failed: %v", err)
+	}
+	if action["isCross"] != true || action["leverage"] != float64(3) {
+		t.Errorf("Unexpected action %v", action)
+	}
+
+	if err := client.SetLeverage(ctx, "ETH-USD", decimal.NewFromFloat(2.5)); err == nil {
+		t.Error("Expected fractional leverage to be rejected")
+	}
+}
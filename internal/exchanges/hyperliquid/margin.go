@@ -0,0 +1,63 @@
+package hyperliquid
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// GetAccountMargin returns the margin summary of the account across cross
+// and isolated positions
+func (c *Client) GetAccountMargin(ctx context.Context) (*exchanges.AccountMargin, error) {
+	if c.apiKey == "" {
+		return nil, fmt.Errorf("hyperliquid requires the account address to read margin")
+	}
+
+	var response struct {
+		MarginSummary struct {
+			AccountValue    string `json:"accountValue"`
+			TotalMarginUsed string `json:"totalMarginUsed"`
+			TotalNtlPos     string `json:"totalNtlPos"`
+		} `json:"marginSummary"`
+		Withdrawable string `json:"withdrawable"`
+	}
+	request := map[string]any{
+		"type": "clearinghouseState",
+		"user": c.apiKey,
+	}
+	if err := c.httpClient.doRequest(ctx, "POST", "/info", request, &response); err != nil {
+		return nil, fmt.Errorf("failed to get margin: %w", err)
+	}
+
+	equity, err := decimal.NewFromString(response.MarginSummary.AccountValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse account value: %w", err)
+	}
+	marginUsed, err := decimal.NewFromString(response.MarginSummary.TotalMarginUsed)
+	if err != nil {
+		marginUsed = decimal.Zero
+	}
+	notional, err := decimal.NewFromString(response.MarginSummary.TotalNtlPos)
+	if err != nil {
+		notional = decimal.Zero
+	}
+	withdrawable, err := decimal.NewFromString(response.Withdrawable)
+	if err != nil {
+		withdrawable = decimal.Zero
+	}
+
+	margin := &exchanges.AccountMargin{
+		Equity:         equity,
+		MarginUsed:     marginUsed,
+		FreeCollateral: decimal.Max(equity.Sub(marginUsed), decimal.Zero),
+		Withdrawable:   withdrawable,
+		UpdatedAt:      time.Now(),
+	}
+	if equity.IsPositive() {
+		margin.Leverage = notional.Div(equity)
+	}
+	return margin, nil
+}
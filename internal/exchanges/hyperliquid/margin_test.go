@@ -0,0 +1,40 @@
+package hyperliquid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestGetAccountMargin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"marginSummary":{"accountValue":"10000.0","totalMarginUsed":"2500.0","totalNtlPos":"25000.0","totalRawUsd":"-15000.0"},
+			"withdrawable":"7000.0",
+			"assetPositions":[]
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL("0xabc", "", server.URL, "")
+	margin, err := client.GetAccountMargin(context.Background())
+	if err != nil {
+		t.Fatalf("GetAccountMargin failed: %v", err)
+	}
+	if !margin.Equity.Equal(decimal.NewFromInt(10000)) || !margin.MarginUsed.Equal(decimal.NewFromInt(2500)) {
+		t.Errorf("Unexpected equity %s or margin used %s", margin.Equity, margin.MarginUsed)
+	}
+	if !margin.FreeCollateral.Equal(decimal.NewFromInt(7500)) || !margin.Withdrawable.Equal(decimal.NewFromInt(7000)) {
+		t.Errorf("Unexpected free collateral %s or withdrawable %s", margin.FreeCollateral, margin.Withdrawable)
+	}
+	if !margin.Leverage.Equal(decimal.NewFromFloat(2.5)) {
+		t.Errorf("Expected leverage 2.5, got %s", margin.Leverage)
+	}
+
+	if _, err := NewClientWithURL("", "", server.URL, "").GetAccountMargin(context.Background()); err == nil {
+		t.Error("Expected an error without the account address")
+	}
+}
@@ -0,0 +1,88 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/shopspring/decimal"
+)
+
+// activeAssetCtx is the context of a perpetual streamed on the
+// activeAssetCtx channel
+type activeAssetCtx struct {
+	Coin string `json:"coin"`
+	Ctx  struct {
+		MarkPx   string `json:"markPx"`
+		OraclePx string `json:"oraclePx"` // Index the mark derives from
+	} `json:"ctx"`
+}
+
+// markPrice converts the context, reporting false when it carries no mark
+func (a activeAssetCtx) markPrice(now time.Time) (exchanges.MarkPrice, bool) {
+	mark, err := decimal.NewFromString(a.Ctx.MarkPx)
+	if err != nil || !mark.IsPositive() {
+		return exchanges.MarkPrice{}, false
+	}
+	index, _ := decimal.NewFromString(a.Ctx.OraclePx)
+	return exchanges.MarkPrice{
+		Symbol:    a.Coin + "-USD",
+		Mark:      mark,
+		Index:     index,
+		Timestamp: now,
+	}, true
+}
+
+// SubscribeMarkPrice streams the mark and oracle prices of symbol
+func (c *Client) SubscribeMarkPrice(ctx context.Context, symbol string, callback func(*exchanges.MarkPrice)) error {
+	if c.ws == nil {
+		return fmt.Errorf("websocket not connected")
+	}
+	return c.ws.SubscribeMarkPrice(ctx, symbol, callback)
+}
+
+// SubscribeMarkPrice subscribes to the asset context of symbol
+func (ws *WebSocketClient) SubscribeMarkPrice(ctx context.Context, symbol string, callback func(*exchanges.MarkPrice)) error {
+	ws.mu.Lock()
+	coin := strings.Split(symbol, "-")[0]
+	if ws.markCallbacks == nil {
+		ws.markCallbacks = make(map[string]func(*exchanges.MarkPrice))
+	}
+	ws.markCallbacks[coin] = callback
+	ws.mu.Unlock()
+
+	sub := map[string]any{
+		"method": "subscribe",
+		"subscription": map[string]any{
+			"type": "activeAssetCtx",
+			"coin": coin,
+		},
+	}
+
+	logger.Exchange("hyperliquid").Debug("subscribing to mark price", "symbol", symbol)
+	return ws.subscribe(sub)
+}
+
+// handleActiveAssetCtxMessage handles asset context updates
+func (ws *WebSocketClient) handleActiveAssetCtxMessage(message []byte) {
+	var msg struct {
+		Data activeAssetCtx `json:"data"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return
+	}
+
+	ws.mu.RLock()
+	callback := ws.markCallbacks[msg.Data.Coin]
+	ws.mu.RUnlock()
+	if callback == nil {
+		return
+	}
+	if mark, ok := msg.Data.markPrice(time.Now()); ok {
+		callback(&mark)
+	}
+}
@@ -0,0 +1,29 @@
+package hyperliquid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func TestHandleActiveAssetCtxMessage(t *testing.T) {
+	ws := NewWebSocketClient("ws://unused", "", "")
+	var marks []*exchanges.MarkPrice
+	// Not connected: the callback is registered all the same
+	_ = ws.SubscribeMarkPrice(context.Background(), "BTC-USD", func(mark *exchanges.MarkPrice) {
+		marks = append(marks, mark)
+	})
+
+	ws.processMessage([]byte(`{"channel":"activeAssetCtx","data":{"coin":"BTC","ctx":{"markPx":"50010.5","oraclePx":"50000","midPx":"50012"}}}`))
+	ws.processMessage([]byte(`{"channel":"activeAssetCtx","data":{"coin":"ETH","ctx":{"markPx":"2000","oraclePx":"2001"}}}`))
+	ws.processMessage([]byte(`{"channel":"activeAssetCtx","data":{"coin":"BTC","ctx":{"oraclePx":"50000"}}}`))
+
+	if len(marks) != 1 {
+		t.Fatalf("expected only the BTC context with a mark, got %d marks", len(marks))
+	}
+	if marks[0].Symbol != "BTC-USD" || !marks[0].Mark.Equal(decimal.NewFromFloat(50010.5)) || !marks[0].Index.Equal(decimal.NewFromInt(50000)) {
+		t.Errorf("unexpected mark price: %+v", marks[0])
+	}
+}
@@ -0,0 +1,81 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// GetMarketStatus returns whether trading in symbol is halted. Hyperliquid
+// flags delisted assets in its metadata; they only accept closing orders.
+func (c *Client) GetMarketStatus(ctx context.Context, symbol string) (*exchanges.MarketStatus, error) {
+	var meta struct {
+		Universe []struct {
+			Name       string `json:"name"`
+			IsDelisted bool   `json:"isDelisted"`
+		} `json:"universe"`
+	}
+	if err := c.httpClient.doRequest(ctx, "POST", "/info", map[string]any{"type": "meta"}, &meta); err != nil {
+		return nil, fmt.Errorf("failed to get asset metadata: %w", err)
+	}
+
+	coin := extractCoinFromSymbol(symbol)
+	for _, asset := range meta.Universe {
+		if asset.Name != coin {
+			continue
+		}
+		if asset.IsDelisted {
+			return &exchanges.MarketStatus{Halted: true, Reason: "delisted"}, nil
+		}
+		return &exchanges.MarketStatus{}, nil
+	}
+	return nil, fmt.Errorf("asset %s not found", coin)
+}
+
+// ListMarkets returns the perpetual markets that are not delisted, named
+// after their coin against USD like the other symbols of the client
+func (c *Client) ListMarkets(ctx context.Context) ([]exchanges.Market, error) {
+	var response []json.RawMessage
+	if err := c.httpClient.doRequest(ctx, "POST", "/info", map[string]any{"type": "metaAndAssetCtxs"}, &response); err != nil {
+		return nil, fmt.Errorf("failed to get asset contexts: %w", err)
+	}
+	if len(response) != 2 {
+		return nil, fmt.Errorf("unexpected asset contexts response of %d elements", len(response))
+	}
+
+	var meta struct {
+		Universe []struct {
+			Name       string `json:"name"`
+			IsDelisted bool   `json:"isDelisted"`
+		} `json:"universe"`
+	}
+	var contexts []struct {
+		DayNtlVlm    string `json:"dayNtlVlm"`
+		MarkPx       string `json:"markPx"`
+		OpenInterest string `json:"openInterest"`
+	}
+	if err := json.Unmarshal(response[0], &meta); err != nil {
+		return nil, fmt.Errorf("failed to decode asset metadata: %w", err)
+	}
+	if err := json.Unmarshal(response[1], &contexts); err != nil {
+		return nil, fmt.Errorf("failed to decode asset contexts: %w", err)
+	}
+
+	markets := make([]exchanges.Market, 0, len(meta.Universe))
+	for i, asset := range meta.Universe {
+		// Contexts are listed in the order of the universe
+		if asset.IsDelisted || i >= len(contexts) {
+			continue
+		}
+		markets = append(markets, exchanges.Market{
+			Symbol:       asset.Name + "-USD",
+			Quote:        "USD",
+			Price:        exchanges.ParseDecimalOrZero(contexts[i].MarkPx),
+			Volume24h:    exchanges.ParseDecimalOrZero(contexts[i].DayNtlVlm),
+			OpenInterest: exchanges.ParseDecimalOrZero(contexts[i].OpenInterest),
+		})
+	}
+	return markets, nil
+}
@@ -0,0 +1,62 @@
+package hyperliquid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestGetMarketStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"universe":[{"name":"BTC","szDecimals":5},{"name":"FTT","szDecimals":1,"isDelisted":true}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL("", "", server.URL, "")
+	ctx := context.Background()
+
+	status, err := client.GetMarketStatus(ctx, "BTC-USD")
+	if err != nil {
+		t.Fatalf("GetMarketStatus failed: %v", err)
+	}
+	if status.Halted {
+		t.Error("Expected a listed asset not to be halted")
+	}
+
+	status, _ = client.GetMarketStatus(ctx, "FTT-USD")
+	if !status.Halted || status.Reason != "delisted" {
+		t.Errorf("Expected a delisted asset to be halted, got %+v", status)
+	}
+
+	if _, err := client.GetMarketStatus(ctx, "XYZ-USD"); err == nil {
+		t.Error("Expected an error for an unknown asset")
+	}
+}
+
+func TestListMarkets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"universe":[{"name":"BTC","szDecimals":5},{"name":"FTT","szDecimals":1,"isDelisted":true},{"name":"ETH","szDecimals":4}]},
+			[{"dayNtlVlm":"900000000","markPx":"50000","openInterest":"12000"},{"dayNtlVlm":"0","markPx":"1"},{"dayNtlVlm":"300000000","markPx":"2000","openInterest":"90000"}]
+		]`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithURL("", "", server.URL, "")
+	markets, err := client.ListMarkets(context.Background())
+	if err != nil {
+		t.Fatalf("ListMarkets failed: %v", err)
+	}
+	if len(markets) != 2 {
+		t.Fatalf("Expected the 2 listed assets, got %+v", markets)
+	}
+	if markets[0].Symbol != "BTC-USD" || !markets[0].Volume24h.Equal(decimal.NewFromInt(900000000)) {
+		t.Errorf("Unexpected market %+v", markets[0])
+	}
+	if markets[1].Symbol != "ETH-USD" || !markets[1].OpenInterest.Equal(decimal.NewFromInt(90000)) {
+		t.Errorf("Expected ETH to keep its own context, got %+v", markets[1])
+	}
+}
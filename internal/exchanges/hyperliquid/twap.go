@@ -0,0 +1,271 @@
+package hyperliquid
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// Hyperliquid works TWAP orders itself, in market slices every 30 seconds.
+// A TWAP has an ID of its own rather than an order ID, so the client tracks
+// the TWAPs it placed to report their progress from the fills of their
+// slices.
+
+const (
+	// twapOrderPrefix marks the order IDs of TWAPs
+	twapOrderPrefix = "twap-"
+	// minTWAPMinutes and maxTWAPMinutes bound the duration of a TWAP
+	minTWAPMinutes = 5
+	maxTWAPMinutes = 1440
+	// twapSettleDelay is how long after its end a TWAP may still report the
+	// fills of its last slices before it is considered expired
+	twapSettleDelay = time.Minute
+)
+
+// twapOrder is a TWAP placed by the client
+type twapOrder struct {
+	order    exchanges.Order
+	coin     string
+	twapID   int64
+	ends     time.Time
+	canceled bool
+}
+
+// twapSliceFill is a fill of a TWAP slice, as listed by userTwapSliceFills
+type twapSliceFill struct {
+	Fill   hyperliquidFill `json:"fill"`
+	TwapID int64           `json:"twapId"`
+}
+
+// twapMinutes returns the duration of a TWAP in whole minutes, rounded up
+func twapMinutes(duration time.Duration) (int64, error) {
+	minutes := int64((duration + time.Minute - 1) / time.Minute)
+	if minutes < minTWAPMinutes || minutes > maxTWAPMinutes {
+		return 0, fmt.Errorf("%w: hyperliquid TWAPs last %d to %d minutes, got %s",
+			exchanges.ErrInvalidOrder, minTWAPMinutes, maxTWAPMinutes, duration)
+	}
+	return minutes, nil
+}
+
+// builderWire returns the builder of an order in wire format, nil without one
+func builderWire(options *exchanges.OrderOptions) map[string]interface{} {
+	if options == nil || options.BuilderFee == nil || options.BuilderFee.Address == "" {
+		return nil
+	}
+	return map[string]interface{}{
+		"b": strings.ToLower(options.BuilderFee.Address),
+		"f": options.BuilderFee.TenthsBps,
+	}
+}
+
+// sendAction signs and sends an exchange action, returning its response
+func (c *Client) sendAction(ctx context.Context, action map[string]interface{}, name string) (map[string]interface{}, error) {
+	timestamp := c.httpClient.clock.Now().UnixMilli()
+	signature, err := signL1Action(c.privateKey, action, nil, timestamp, nil, c.baseURL == hyperliquidAPIURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign %s: %w", name, err)
+	}
+
+	payload := map[string]interface{}{
+		"action":    action,
+		"nonce":     timestamp,
+		"signature": signature,
+	}
+
+	var response map[string]interface{}
+	if err := c.httpClient.doRequest(ctx, "POST", "/exchange", payload, &response); err != nil {
+		return nil, fmt.Errorf("failed to send %s: %w", name, err)
+	}
+	if err := responseError(name+" rejected", response); err != nil {
+		return nil, err
+	}
+	if status, _ := response["status"].(string); status != "ok" {
+		return nil, fmt.Errorf("failed to send %s: invalid response", name)
+	}
+	return response, nil
+}
+
+// placeTWAP places order as a native TWAP
+func (c *Client) placeTWAP(ctx context.Context, order *exchanges.Order) (*exchanges.Order, error) {
+	if order.Type != exchanges.OrderTypeMarket {
+		return nil, fmt.Errorf("%w: TWAP orders are market orders", exchanges.ErrInvalidOrder)
+	}
+	minutes, err := twapMinutes(order.Options.TWAP.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	coin := extractCoinFromSymbol(order.Symbol)
+	twapAction := map[string]interface{}{
+		"type": "twapOrder",
+		"twap": map[string]interface{}{
+			"a": coin, // As PlaceOrder, the coin stands for the asset
+			"b": order.Side == exchanges.OrderSideBuy,
+			"s": floatToWire(order.Amount.InexactFloat64()),
+			"r": order.ReduceOnly,
+			"m": minutes,
+			"t": order.Options.TWAP.Randomize,
+		},
+	}
+
+	response, err := c.sendAction(ctx, twapAction, "twap order")
+	if err != nil {
+		return nil, err
+	}
+	var status map[string]interface{}
+	if respData, ok := response["response"].(map[string]interface{}); ok {
+		if data, ok := respData["data"].(map[string]interface{}); ok {
+			status, _ = data["status"].(map[string]interface{})
+		}
+	}
+	if message, ok := status["error"].(string); ok {
+		return nil, actionError("twap order rejected", message)
+	}
+	running, _ := status["running"].(map[string]interface{})
+	twapID, ok := running["twapId"].(float64)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse twap response: no TWAP ID")
+	}
+
+	now := time.Now()
+	order.ID = twapOrderPrefix + strconv.FormatInt(int64(twapID), 10)
+	order.Status = exchanges.OrderStatusOpen
+	order.CreatedAt = now
+	order.UpdatedAt = now
+
+	c.mu.Lock()
+	if c.twaps == nil {
+		c.twaps = make(map[string]*twapOrder)
+	}
+	c.twaps[order.ID] = &twapOrder{
+		order:  *order,
+		coin:   coin,
+		twapID: int64(twapID),
+		ends:   now.Add(time.Duration(minutes) * time.Minute),
+	}
+	c.mu.Unlock()
+	return order, nil
+}
+
+// trackedTWAP returns the TWAP placed under orderID
+func (c *Client) trackedTWAP(orderID string) (*twapOrder, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	twap, ok := c.twaps[orderID]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown TWAP %s", exchanges.ErrOrderNotFound, orderID)
+	}
+	return twap, nil
+}
+
+// cancelTWAP stops a running TWAP; the slices already filled are kept
+func (c *Client) cancelTWAP(ctx context.Context, orderID string) error {
+	twap, err := c.trackedTWAP(orderID)
+	if err != nil {
+		return err
+	}
+	cancelAction := map[string]interface{}{
+		"type": "twapCancel",
+		"a":    twap.coin,
+		"t":    twap.twapID,
+	}
+	if _, err := c.sendAction(ctx, cancelAction, "twap cancel"); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	twap.canceled = true
+	c.mu.Unlock()
+	return nil
+}
+
+// twapSliceFills lists the fills of the TWAP slices of the account
+func (c *Client) twapSliceFills(ctx context.Context) ([]twapSliceFill, error) {
+	request := map[string]any{
+		"type": "userTwapSliceFills",
+		"user": c.apiKey,
+	}
+	var fills []twapSliceFill
+	if err := c.httpClient.doRequest(ctx, "POST", "/info", request, &fills); err != nil {
+		return nil, fmt.Errorf("failed to get twap fills: %w", err)
+	}
+	return fills, nil
+}
+
+// getTWAP returns the progress of a TWAP: filled once its slices filled its
+// size, canceled or expired once stopped short of it
+func (c *Client) getTWAP(ctx context.Context, orderID string) (*exchanges.Order, error) {
+	twap, err := c.trackedTWAP(orderID)
+	if err != nil {
+		return nil, err
+	}
+	fills, err := c.twapSliceFills(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	order := twap.order
+	canceled := twap.canceled
+	c.mu.RUnlock()
+
+	filled, notional := decimal.Zero, decimal.Zero
+	for _, f := range fills {
+		if f.TwapID != twap.twapID {
+			continue
+		}
+		if fill, ok := f.Fill.trade(); ok {
+			filled = filled.Add(fill.Amount)
+			notional = notional.Add(fill.Amount.Mul(fill.Price))
+		}
+	}
+
+	order.Filled = filled
+	order.FilledAmount = filled
+	order.Remaining = decimal.Max(order.Amount.Sub(filled), decimal.Zero)
+	if filled.IsPositive() {
+		order.AveragePrice = notional.Div(filled)
+	}
+	order.UpdatedAt = time.Now()
+	switch {
+	case !order.Remaining.IsPositive():
+		order.Status = exchanges.OrderStatusFilled
+	case canceled:
+		order.Status = exchanges.OrderStatusCanceled
+	case time.Now().After(twap.ends.Add(twapSettleDelay)):
+		order.Status = exchanges.OrderStatusExpired
+	case filled.IsPositive():
+		order.Status = exchanges.OrderStatusPartially
+	}
+	return &order, nil
+}
+
+// attributeTWAPFills reports the fills of the slices of the TWAPs placed by
+// the client as fills of the TWAP rather than of their slice
+func (c *Client) attributeTWAPFills(ctx context.Context, fills []exchanges.Trade) {
+	c.mu.RLock()
+	tracked := len(c.twaps) > 0
+	c.mu.RUnlock()
+	if !tracked || len(fills) == 0 {
+		return
+	}
+
+	slices, err := c.twapSliceFills(ctx)
+	if err != nil {
+		return
+	}
+	twapOf := make(map[string]string, len(slices))
+	for _, f := range slices {
+		twapOf[strconv.FormatInt(f.Fill.Tid, 10)] = twapOrderPrefix + strconv.FormatInt(f.TwapID, 10)
+	}
+	for i := range fills {
+		if orderID, ok := twapOf[fills[i].ID]; ok {
+			fills[i].OrderID = orderID
+		}
+	}
+}
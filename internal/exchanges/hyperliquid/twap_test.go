@@ -0,0 +1,108 @@
+package hyperliquid
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func TestNativeTWAP(t *testing.T) {
+	var actions []map[string]interface{}
+	sliceFills := `[]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch {
+		case r.URL.Path == "/exchange":
+			action, _ := body["action"].(map[string]interface{})
+			actions = append(actions, action)
+			if action["type"] == "twapOrder" {
+				w.Write([]byte(`{"status":"ok","response":{"type":"twapOrder","data":{"status":{"running":{"twapId":77}}}}}`))
+				return
+			}
+			w.Write([]byte(`{"status":"ok","response":{"type":"default"}}`))
+		case body["type"] == "userTwapSliceFills":
+			w.Write([]byte(sliceFills))
+		case body["type"] == "userFillsByTime":
+			w.Write([]byte(`[{"coin":"ETH","px":"2000","sz":"0.5","side":"B","time":1700000000000,"oid":901,"tid":11,"fee":"0.2","crossed":true}]`))
+		}
+	}))
+	defer server.Close()
+
+	dummyPrivateKey := "1234567890123456789012345678901234567890123456789012345678901234"
+	client := NewClientWithURL("0xabc", dummyPrivateKey, server.URL, "")
+	ctx := context.Background()
+
+	order := &exchanges.Order{
+		Symbol: "ETH-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeMarket,
+		Amount: decimal.NewFromInt(2),
+		Options: &exchanges.OrderOptions{
+			TWAP: &exchanges.TWAPOptions{Duration: 10 * time.Minute, Randomize: true},
+		},
+	}
+	placed, err := client.PlaceOrder(ctx, order)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if placed.ID != "twap-77" || placed.Status != exchanges.OrderStatusOpen {
+		t.Errorf("Unexpected TWAP order %s %s", placed.ID, placed.Status)
+	}
+	twap, _ := actions[0]["twap"].(map[string]interface{})
+	if twap["a"] != "ETH" || twap["b"] != true || twap["s"] != floatToWire(2) || twap["m"] != float64(10) || twap["t"] != true {
+		t.Errorf("Unexpected TWAP action %v", actions[0])
+	}
+
+	// Slice fills report the progress of the TWAP and are attributed to it
+	sliceFills = `[{"fill":{"coin":"ETH","px":"2000","sz":"0.5","side":"B","time":1700000000000,"oid":901,"tid":11,"fee":"0.2","crossed":true},"twapId":77},
+		{"fill":{"coin":"ETH","px":"2010","sz":"0.5","side":"B","time":1700000030000,"oid":902,"tid":12,"fee":"0.2","crossed":true},"twapId":77},
+		{"fill":{"coin":"BTC","px":"50000","sz":"1","side":"A","time":1700000030000,"oid":903,"tid":13,"fee":"1","crossed":true},"twapId":5}]`
+	status, err := client.GetOrder(ctx, "twap-77")
+	if err != nil {
+		t.Fatalf("GetOrder failed: %v", err)
+	}
+	if status.Status != exchanges.OrderStatusPartially || !status.FilledAmount.Equal(decimal.NewFromInt(1)) || !status.AveragePrice.Equal(decimal.NewFromInt(2005)) {
+		t.Errorf("Unexpected TWAP progress %s %s at %s", status.Status, status.FilledAmount, status.AveragePrice)
+	}
+	fills, err := client.GetFills(ctx, "", time.Time{})
+	if err != nil || len(fills) != 1 || fills[0].OrderID != "twap-77" {
+		t.Errorf("Expected the slice fill to be attributed to the TWAP, got %+v (%v)", fills, err)
+	}
+
+	if err := client.CancelOrder(ctx, "twap-77"); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	if actions[1]["type"] != "twapCancel" || actions[1]["a"] != "ETH" || actions[1]["t"] != float64(77) {
+		t.Errorf("Unexpected cancel action %v", actions[1])
+	}
+	if status, _ := client.GetOrder(ctx, "twap-77"); status.Status != exchanges.OrderStatusCanceled {
+		t.Errorf("Expected the TWAP to be canceled, got %s", status.Status)
+	}
+
+	if _, err := client.GetOrder(ctx, "twap-78"); !errors.Is(err, exchanges.ErrOrderNotFound) {
+		t.Errorf("Expected unknown TWAPs not to be found, got %v", err)
+	}
+	order.Options.TWAP.Duration = time.Minute
+	if _, err := client.PlaceOrder(ctx, order); !errors.Is(err, exchanges.ErrInvalidOrder) {
+		t.Errorf("Expected TWAPs shorter than 5 minutes to be rejected, got %v", err)
+	}
+}
+
+func TestBuilderWire(t *testing.T) {
+	if builderWire(nil) != nil || builderWire(&exchanges.OrderOptions{}) != nil {
+		t.Error("Orders without a builder fee should not carry a builder")
+	}
+	builder := builderWire(&exchanges.OrderOptions{BuilderFee: &exchanges.BuilderFee{Address: "0xABC", TenthsBps: 10}})
+	if builder["b"] != "0xabc" || builder["f"] != 10 {
+		t.Errorf("Unexpected builder %v", builder)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -27,7 +28,17 @@ type WebSocketClient struct {
 	orderbookCallbacks map[string]func(*exchanges.OrderBook)
 	tradeCallbacks     map[string]func(*exchanges.Trade)
 
+	// fillCallback receives the fills of the account of fillUser
+	fillCallback func(*exchanges.Trade)
+	fillUser     string
+	// markCallbacks receive the mark prices, by coin
+	markCallbacks map[string]func(*exchanges.MarkPrice)
+
 	done chan struct{}
+
+	// subscriptions are the subscription messages sent, replayed by Reconnect
+	subscriptions map[string]any
+	lastMessage   atomic.Int64 // Unix nanoseconds of the last message received
 }
 
 // NewWebSocketClient creates a new WebSocket client
@@ -99,13 +110,18 @@ func (ws *WebSocketClient) Close() error {
 
 // handleMessages processes incoming WebSocket messages
 func (ws *WebSocketClient) handleMessages(done <-chan struct{}) {
+	ws.mu.RLock()
+	conn := ws.conn
+	ws.mu.RUnlock()
+
 	defer func() {
 		ws.mu.Lock()
-		if ws.conn != nil {
-			ws.conn.Close()
+		// Reconnect may have replaced the connection meanwhile
+		if ws.conn == conn {
 			ws.conn = nil
 		}
 		ws.mu.Unlock()
+		conn.Close()
 	}()
 
 	for {
@@ -113,7 +129,7 @@ func (ws *WebSocketClient) handleMessages(done <-chan struct{}) {
 		case <-done:
 			return
 		default:
-			_, message, err := ws.conn.ReadMessage()
+			_, message, err := conn.ReadMessage()
 			if err != nil {
 				// Log error and attempt reconnect
 				telemetry.RecordWebSocketReconnect("hyperliquid")
@@ -121,6 +137,7 @@ func (ws *WebSocketClient) handleMessages(done <-chan struct{}) {
 				continue
 			}
 
+			ws.lastMessage.Store(time.Now().UnixNano())
 			ws.processMessage(message)
 		}
 	}
@@ -145,6 +162,10 @@ func (ws *WebSocketClient) processMessage(message []byte) {
 			ws.handleOrderBookMessage(msg)
 		case "trades":
 			ws.handleTradeMessage(msg)
+		case "userFills":
+			ws.handleUserFillsMessage(message)
+		case "activeAssetCtx":
+			ws.handleActiveAssetCtxMessage(message)
 		}
 	}
 }
@@ -327,7 +348,7 @@ func (ws *WebSocketClient) SubscribeTicker(ctx context.Context, symbol string, c
 	}
 
 	logger.Exchange("hyperliquid").Debug("subscribing to ticker", "symbol", symbol)
-	return ws.sendMessage(sub)
+	return ws.subscribe(sub)
 }
 
 // SubscribeOrderBook subscribes to order book updates
@@ -344,7 +365,7 @@ func (ws *WebSocketClient) SubscribeOrderBook(ctx context.Context, symbol string
 	}
 
 	logger.Exchange("hyperliquid").Debug("subscribing to orderbook", "symbol", symbol)
-	return ws.sendMessage(sub)
+	return ws.subscribe(sub)
 }
 
 // SubscribeTrades subscribes to trade updates
@@ -360,7 +381,7 @@ func (ws *WebSocketClient) SubscribeTrades(ctx context.Context, symbol string, c
 		"params": []string{fmt.Sprintf("trades.%s", coin)},
 	}
 
-	return ws.sendMessage(sub)
+	return ws.subscribe(sub)
 }
 
 // sendMessage sends a message through the WebSocket
@@ -379,3 +400,58 @@ func (ws *WebSocketClient) sendMessage(msg any) error {
 
 	return ws.conn.WriteMessage(websocket.TextMessage, data)
 }
+
+// subscribe sends a subscription message and records it for Reconnect
+func (ws *WebSocketClient) subscribe(sub any) error {
+	data, err := json.Marshal(sub)
+	if err != nil {
+		return err
+	}
+	ws.mu.Lock()
+	if ws.subscriptions == nil {
+		ws.subscriptions = make(map[string]any)
+	}
+	ws.subscriptions[string(data)] = sub
+	ws.mu.Unlock()
+
+	return ws.sendMessage(sub)
+}
+
+// HasSubscriptions reports whether any channel has been subscribed to
+func (ws *WebSocketClient) HasSubscriptions() bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return len(ws.subscriptions) > 0
+}
+
+// LastMessageAt returns when the last message was received, zero before the
+// first one
+func (ws *WebSocketClient) LastMessageAt() time.Time {
+	if nanos := ws.lastMessage.Load(); nanos != 0 {
+		return time.Unix(0, nanos)
+	}
+	return time.Time{}
+}
+
+// Reconnect replaces the connection and renews every subscription on it
+func (ws *WebSocketClient) Reconnect(ctx context.Context) error {
+	ws.Close()
+	if err := ws.Connect(ctx); err != nil {
+		return err
+	}
+
+	ws.mu.RLock()
+	subs := make([]any, 0, len(ws.subscriptions))
+	for _, sub := range ws.subscriptions {
+		subs = append(subs, sub)
+	}
+	ws.mu.RUnlock()
+
+	for _, sub := range subs {
+		if err := ws.sendMessage(sub); err != nil {
+			return fmt.Errorf("failed to resubscribe: %w", err)
+		}
+	}
+	telemetry.RecordWebSocketReconnect("hyperliquid")
+	return nil
+}
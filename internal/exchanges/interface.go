@@ -3,6 +3,7 @@ package exchanges
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -20,11 +21,47 @@ const (
 type OrderType string
 
 const (
-	OrderTypeLimit     OrderType = "limit"
-	OrderTypeMarket    OrderType = "market"
-	OrderTypeStopLimit OrderType = "stop_limit"
+	OrderTypeLimit      OrderType = "limit"
+	OrderTypeMarket     OrderType = "market"
+	OrderTypeStopLimit  OrderType = "stop_limit"
+	OrderTypeStopMarket OrderType = "stop_market" // Market order sent once StopPrice trades
 )
 
+// IsStop reports whether orders of the type wait for their stop price to
+// trade before they are sent
+func (t OrderType) IsStop() bool {
+	return t == OrderTypeStopLimit || t == OrderTypeStopMarket
+}
+
+// TimeInForce controls how long an order stays on the book
+type TimeInForce string
+
+const (
+	TimeInForceGTC TimeInForce = "gtc" // Good till canceled (default)
+	TimeInForceIOC TimeInForce = "ioc" // Immediate or cancel: fill what is possible, cancel the rest
+	TimeInForceFOK TimeInForce = "fok" // Fill or kill: fill entirely or cancel
+	TimeInForceGTD TimeInForce = "gtd" // Good till ExpiresAt
+)
+
+// ValidateTimeInForce checks the time in force options of an order: post-only
+// orders must rest on the book, and good till date orders need a future expiry
+func ValidateTimeInForce(tif TimeInForce, postOnly bool, expiresAt time.Time) error {
+	switch tif {
+	case "", TimeInForceGTC:
+	case TimeInForceIOC, TimeInForceFOK:
+		if postOnly {
+			return fmt.Errorf("%w: post-only orders cannot be %s", ErrInvalidOrder, tif)
+		}
+	case TimeInForceGTD:
+		if expiresAt.IsZero() {
+			return fmt.Errorf("%w: good till date orders require an expiry", ErrInvalidOrder)
+		}
+	default:
+		return fmt.Errorf("%w: unknown time in force %q", ErrInvalidOrder, tif)
+	}
+	return nil
+}
+
 // OrderStatus represents the status of an order
 type OrderStatus string
 
@@ -37,12 +74,22 @@ const (
 	OrderStatusRejected  OrderStatus = "rejected"
 )
 
+// MarginMode is how collateral backs leveraged positions
+type MarginMode string
+
+const (
+	MarginModeCross    MarginMode = "cross"    // Positions share the account's collateral
+	MarginModeIsolated MarginMode = "isolated" // Each position is backed by its own collateral
+)
+
 // Common errors
 var (
 	ErrOrderNotFound    = errors.New("order not found")
 	ErrPositionNotFound = errors.New("position not found")
 	ErrNotConnected     = errors.New("exchange not connected")
 	ErrInvalidOrder     = errors.New("invalid order")
+	ErrNotSupported     = errors.New("not supported")
+	ErrSymbolNotAllowed = errors.New("symbol not allowed")
 )
 
 // Ticker represents market ticker data
@@ -87,9 +134,17 @@ type Order struct {
 	StopPrice    decimal.Decimal
 	FilledAmount decimal.Decimal
 	AveragePrice decimal.Decimal
+	// Time in force of limit orders; empty is good till canceled
+	TimeInForce TimeInForce
+	PostOnly    bool      // Rejected instead of taking liquidity
+	ExpiresAt   time.Time // Expiry of good till date orders
+	ReduceOnly  bool      // Only reduces the position, never increases or flips it
+	// Options are the venue-specific options of the order, nil for none
+	Options *OrderOptions
 }
 
-// Trade represents a completed trade
+// Trade represents a completed trade: a print on the market, or a fill of
+// one of the account's orders
 type Trade struct {
 	ID        string
 	OrderID   string
@@ -97,8 +152,12 @@ type Trade struct {
 	Side      OrderSide
 	Price     decimal.Decimal
 	Amount    decimal.Decimal
-	Fee       decimal.Decimal
+	Fee       decimal.Decimal // Paid in the quote currency; negative for rebates
 	Timestamp time.Time
+	Maker     bool // The fill added liquidity
+	// Liquidation is set on the fills of the account made by the venue
+	// liquidating or auto-deleveraging a position, on exchanges reporting it
+	Liquidation LiquidationKind
 }
 
 // Position represents an open position
@@ -134,6 +193,14 @@ type Candle struct {
 	Volume    decimal.Decimal
 }
 
+// LeverageSettings is the leverage configuration of a symbol on an exchange
+type LeverageSettings struct {
+	Symbol     string
+	Leverage   decimal.Decimal // Leverage applied to new positions
+	Max        decimal.Decimal // Highest leverage the exchange allows, zero if unknown
+	MarginMode MarginMode
+}
+
 // Exchange defines the interface all exchanges must implement
 type Exchange interface {
 	// Connection management
@@ -162,7 +229,49 @@ type Exchange interface {
 	GetPositions(ctx context.Context) ([]Position, error)
 	GetPosition(ctx context.Context, symbol string) (*Position, error)
 
+	// Margin
+	SetLeverage(ctx context.Context, symbol string, leverage decimal.Decimal) error
+	SetMarginMode(ctx context.Context, symbol string, mode MarginMode) error
+	GetLeverage(ctx context.Context, symbol string) (*LeverageSettings, error)
+
 	// Metadata
 	Name() string
 	SupportedSymbols() []string
+	Capabilities() Capabilities
+}
+
+// Capabilities describes the features an exchange supports, so callers can
+// work around the missing ones instead of failing at runtime
+type Capabilities struct {
+	Shorts         bool // Opening short positions
+	StopOrders     bool // Native stop limit orders
+	StopMarket     bool // Native stop market orders
+	ReduceOnly     bool // Orders the exchange keeps from increasing a position
+	OCO            bool // One-cancels-the-other order pairs
+	PostOnly       bool // Orders rejected instead of taking liquidity
+	WebSocketFills bool // Fills are streamed over the websocket rather than polled
+	Margin         bool // Leveraged positions on margin
+	NativeTWAP     bool // Market orders worked in slices by the exchange
+	Hedge          bool // Long and short positions held at once on the same symbol
+}
+
+// ClientOrderLookup is implemented by exchanges that can find an order by the
+// client order ID it was submitted with. It lets callers check whether a
+// submission that failed in transit actually reached the exchange before
+// retrying it.
+type ClientOrderLookup interface {
+	GetOrderByClientID(ctx context.Context, symbol string, clientOrderID string) (*Order, error)
+}
+
+// FillHistory is implemented by exchanges that list the fills of the
+// account, with their actual prices and fees. An empty symbol lists the
+// fills of every symbol.
+type FillHistory interface {
+	GetFills(ctx context.Context, symbol string, since time.Time) ([]Trade, error)
+}
+
+// FillStream is implemented by exchanges that stream the fills of the
+// account as they happen
+type FillStream interface {
+	SubscribeFills(ctx context.Context, callback func(*Trade)) error
 }
@@ -0,0 +1,39 @@
+package exchanges
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateTimeInForce(t *testing.T) {
+	expiry := time.Now().Add(time.Minute)
+	tests := []struct {
+		name      string
+		tif       TimeInForce
+		postOnly  bool
+		expiresAt time.Time
+		wantErr   bool
+	}{
+		{name: "default", tif: ""},
+		{name: "gtc post-only", tif: TimeInForceGTC, postOnly: true},
+		{name: "ioc", tif: TimeInForceIOC},
+		{name: "ioc post-only", tif: TimeInForceIOC, postOnly: true, wantErr: true},
+		{name: "fok post-only", tif: TimeInForceFOK, postOnly: true, wantErr: true},
+		{name: "gtd with expiry", tif: TimeInForceGTD, postOnly: true, expiresAt: expiry},
+		{name: "gtd without expiry", tif: TimeInForceGTD, wantErr: true},
+		{name: "unknown", tif: "day", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTimeInForce(tt.tif, tt.postOnly, tt.expiresAt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateTimeInForce() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidOrder) {
+				t.Errorf("expected ErrInvalidOrder, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package exchanges
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrVenueTooSlow is returned for orders sent to a venue whose recent order
+// latency exceeds its limit
+var ErrVenueTooSlow = errors.New("venue too slow")
+
+const (
+	// Latencies kept per venue, whatever the window
+	maxLatencySamples = 200
+	// Recent latencies needed before a venue may be found slow
+	minLatencySamples = 5
+)
+
+// LatencyTracker tracks the round-trip latency of the orders sent to each
+// venue over a sliding window. Scalping fills on a slow venue are often worse
+// than not trading, so venues whose p95 exceeds the limit are avoided. A venue
+// without enough recent samples is never slow, so one avoided for a whole
+// window is tried again.
+type LatencyTracker struct {
+	mu      sync.Mutex
+	maxP95  time.Duration
+	window  time.Duration
+	samples map[string][]latencySample // By venue, oldest first
+	now     func() time.Time
+}
+
+type latencySample struct {
+	at      time.Time
+	latency time.Duration
+}
+
+// NewLatencyTracker creates a tracker finding venues slow when the p95 of the
+// latencies recorded over window exceeds maxP95
+func NewLatencyTracker(maxP95, window time.Duration) *LatencyTracker {
+	return &LatencyTracker{
+		maxP95:  maxP95,
+		window:  window,
+		samples: make(map[string][]latencySample),
+		now:     time.Now,
+	}
+}
+
+// MaxP95 returns the p95 latency above which a venue is slow
+func (t *LatencyTracker) MaxP95() time.Duration {
+	return t.maxP95
+}
+
+// Record records the round-trip latency of an order sent to venue
+func (t *LatencyTracker) Record(venue string, latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	samples := append(t.recent(venue), latencySample{at: t.now(), latency: latency})
+	if len(samples) > maxLatencySamples {
+		samples = samples[len(samples)-maxLatencySamples:]
+	}
+	t.samples[venue] = samples
+}
+
+// P95 returns the p95 of the recent latencies of venue, and false without
+// enough of them
+func (t *LatencyTracker) P95(venue string) (time.Duration, bool) {
+	t.mu.Lock()
+	samples := t.recent(venue)
+	t.samples[venue] = samples
+	t.mu.Unlock()
+
+	if len(samples) < minLatencySamples {
+		return 0, false
+	}
+	latencies := make([]time.Duration, len(samples))
+	for i, sample := range samples {
+		latencies[i] = sample.latency
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	// Nearest rank
+	return latencies[(len(latencies)*95+99)/100-1], true
+}
+
+// Slow reports whether the recent p95 latency of venue exceeds the limit
+func (t *LatencyTracker) Slow(venue string) bool {
+	p95, ok := t.P95(venue)
+	return ok && p95 > t.maxP95
+}
+
+// recent returns the samples of venue within the window. Must be called with
+// the lock held.
+func (t *LatencyTracker) recent(venue string) []latencySample {
+	samples := t.samples[venue]
+	cutoff := t.now().Add(-t.window)
+	first := 0
+	for first < len(samples) && samples[first].at.Before(cutoff) {
+		first++
+	}
+	return samples[first:]
+}
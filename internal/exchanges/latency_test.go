@@ -0,0 +1,45 @@
+package exchanges
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tracker := NewLatencyTracker(200*time.Millisecond, time.Minute)
+	tracker.now = func() time.Time { return now }
+
+	for i := 0; i < minLatencySamples-1; i++ {
+		tracker.Record("a", time.Second)
+	}
+	if _, ok := tracker.P95("a"); ok || tracker.Slow("a") {
+		t.Fatal("expected no p95 without enough samples")
+	}
+
+	// 95 fast round trips and 5 slow ones: the p95 is still fast
+	tracker = NewLatencyTracker(200*time.Millisecond, time.Minute)
+	tracker.now = func() time.Time { return now }
+	for i := 0; i < 95; i++ {
+		tracker.Record("a", 50*time.Millisecond)
+	}
+	for i := 0; i < 5; i++ {
+		tracker.Record("a", time.Second)
+	}
+	if p95, ok := tracker.P95("a"); !ok || p95 != 50*time.Millisecond || tracker.Slow("a") {
+		t.Errorf("expected a p95 of 50ms, got %s (%v)", p95, ok)
+	}
+
+	for i := 0; i < 10; i++ {
+		tracker.Record("a", time.Second)
+	}
+	if !tracker.Slow("a") {
+		t.Error("expected the venue to be slow once its p95 exceeds the limit")
+	}
+
+	// Samples leave the window, so a venue avoided is tried again
+	now = now.Add(2 * time.Minute)
+	if _, ok := tracker.P95("a"); ok || tracker.Slow("a") {
+		t.Error("expected the samples to expire with the window")
+	}
+}
@@ -0,0 +1,14 @@
+package exchanges
+
+// LiquidationKind is how the venue itself closed part of a position of the
+// account, reported on the fills it made
+type LiquidationKind string
+
+const (
+	// LiquidationKindLiquidated is a position closed for falling below its
+	// maintenance margin
+	LiquidationKindLiquidated LiquidationKind = "liquidated"
+	// LiquidationKindDeleveraged is a position auto-deleveraged (ADL): closed
+	// against a liquidated counterparty the insurance fund could not absorb
+	LiquidationKindDeleveraged LiquidationKind = "deleveraged"
+)
@@ -0,0 +1,91 @@
+package exchanges
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountMargin is a snapshot of an account's equity and margin usage
+type AccountMargin struct {
+	Equity         decimal.Decimal // Collateral plus unrealized PnL
+	MarginUsed     decimal.Decimal // Initial margin held by open positions
+	FreeCollateral decimal.Decimal // Margin available for new positions
+	Leverage       decimal.Decimal // Open notional divided by equity
+	Withdrawable   decimal.Decimal // Collateral that can leave the account
+	UpdatedAt      time.Time
+}
+
+// MarginReporter is implemented by exchanges that report their account's
+// margin usage directly. Other exchanges are estimated from their balances
+// and positions.
+type MarginReporter interface {
+	GetAccountMargin(ctx context.Context) (*AccountMargin, error)
+}
+
+// EstimateAccountMargin derives the margin usage of an account from its
+// balances and positions. Equity is the USD and USDC collateral, and each
+// position holds its notional divided by its leverage, 1x when unknown.
+func EstimateAccountMargin(balances []Balance, positions []Position) AccountMargin {
+	margin := AccountMargin{UpdatedAt: time.Now()}
+	for _, balance := range balances {
+		if balance.Asset == "USD" || balance.Asset == "USDC" {
+			margin.Equity = margin.Equity.Add(balance.Total)
+			margin.Withdrawable = margin.Withdrawable.Add(balance.Free)
+		}
+	}
+
+	notional := decimal.Zero
+	for _, position := range positions {
+		price := position.MarkPrice
+		if !price.IsPositive() {
+			price = position.EntryPrice
+		}
+		value := position.Size.Abs().Mul(price)
+		notional = notional.Add(value)
+
+		leverage := position.Leverage
+		if leverage.LessThan(decimal.NewFromInt(1)) {
+			leverage = decimal.NewFromInt(1)
+		}
+		margin.MarginUsed = margin.MarginUsed.Add(value.Div(leverage))
+	}
+
+	margin.FreeCollateral = decimal.Max(margin.Equity.Sub(margin.MarginUsed), decimal.Zero)
+	margin.Withdrawable = decimal.Min(margin.Withdrawable, margin.FreeCollateral)
+	if margin.Equity.IsPositive() {
+		margin.Leverage = notional.Div(margin.Equity)
+	}
+	return margin
+}
+
+// marginTotals sums the margin usage of several exchanges
+type marginTotals struct {
+	equity, used, free decimal.Decimal
+}
+
+func (t *marginTotals) add(margin AccountMargin) {
+	t.equity = t.equity.Add(margin.Equity)
+	t.used = t.used.Add(margin.MarginUsed)
+	t.free = t.free.Add(margin.FreeCollateral)
+}
+
+func (t *marginTotals) apply(data *AggregatedData) {
+	data.TotalEquity = t.equity
+	data.TotalMarginUsed = t.used
+	data.TotalFreeCollateral = t.free
+}
+
+// accountMargin returns the margin usage of exchange, reported by the
+// exchange when it can or estimated from balances and positions
+func accountMargin(ctx context.Context, exchange Exchange, balances []Balance, positions []Position) (AccountMargin, error) {
+	if reporter, ok := exchange.(MarginReporter); ok {
+		margin, err := reporter.GetAccountMargin(ctx)
+		if err != nil {
+			return AccountMargin{}, err
+		}
+		return *margin, nil
+	}
+	return EstimateAccountMargin(balances, positions), nil
+}
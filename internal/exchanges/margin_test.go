@@ -0,0 +1,84 @@
+package exchanges
+
+import (
+	"context"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+// marginReportingExchange reports a fixed margin summary
+type marginReportingExchange struct {
+	*MockExchange
+	margin AccountMargin
+}
+
+func (e *marginReportingExchange) GetAccountMargin(ctx context.Context) (*AccountMargin, error) {
+	return &e.margin, nil
+}
+
+func TestEstimateAccountMargin(t *testing.T) {
+	balances := []Balance{
+		{Asset: "USD", Free: decimal.NewFromInt(9000), Total: decimal.NewFromInt(10000)},
+		{Asset: "BTC", Free: decimal.NewFromInt(1), Total: decimal.NewFromInt(1)},
+	}
+	positions := []Position{
+		{Symbol: "BTC-USD", Size: decimal.NewFromFloat(0.2), MarkPrice: decimal.NewFromInt(50000), Leverage: decimal.NewFromInt(5)},
+		{Symbol: "ETH-USD", Size: decimal.NewFromInt(-2), EntryPrice: decimal.NewFromInt(2500)},
+	}
+
+	margin := EstimateAccountMargin(balances, positions)
+	if !margin.Equity.Equal(decimal.NewFromInt(10000)) {
+		t.Errorf("expected equity from the USD collateral only, got %s", margin.Equity)
+	}
+	// 10000 notional at 5x and 5000 at 1x
+	if !margin.MarginUsed.Equal(decimal.NewFromInt(7000)) {
+		t.Errorf("expected margin used 7000, got %s", margin.MarginUsed)
+	}
+	if !margin.FreeCollateral.Equal(decimal.NewFromInt(3000)) {
+		t.Errorf("expected free collateral 3000, got %s", margin.FreeCollateral)
+	}
+	if !margin.Withdrawable.Equal(decimal.NewFromInt(3000)) {
+		t.Errorf("expected withdrawable capped by free collateral, got %s", margin.Withdrawable)
+	}
+	if !margin.Leverage.Equal(decimal.NewFromFloat(1.5)) {
+		t.Errorf("expected account leverage 1.5, got %s", margin.Leverage)
+	}
+}
+
+func TestExchangeMultiplexer_RefreshDataMargin(t *testing.T) {
+	multiplexer := NewExchangeMultiplexer()
+	multiplexer.AddExchange("reported", &marginReportingExchange{
+		MockExchange: NewMockExchange("reported"),
+		margin: AccountMargin{
+			Equity:         decimal.NewFromInt(5000),
+			MarginUsed:     decimal.NewFromInt(1000),
+			FreeCollateral: decimal.NewFromInt(4000),
+			Withdrawable:   decimal.NewFromInt(3500),
+			Leverage:       decimal.NewFromInt(2),
+		},
+	})
+	multiplexer.AddExchange("estimated", NewMockExchange("estimated"))
+
+	if err := multiplexer.RefreshData(context.Background()); err != nil {
+		t.Fatalf("RefreshData failed: %v", err)
+	}
+	data := multiplexer.GetAggregatedData()
+
+	reported := data.Exchanges["reported"].Margin
+	if !reported.Withdrawable.Equal(decimal.NewFromInt(3500)) || !reported.Leverage.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("expected the reported margin to be used, got %+v", reported)
+	}
+	// The mock holds 5100 of BTC at 1x against 1100 of collateral
+	estimated := data.Exchanges["estimated"].Margin
+	if !estimated.MarginUsed.Equal(decimal.NewFromInt(5100)) || !estimated.FreeCollateral.IsZero() {
+		t.Errorf("expected an estimated margin, got %+v", estimated)
+	}
+
+	if !data.TotalEquity.Equal(decimal.NewFromInt(6100)) ||
+		!data.TotalMarginUsed.Equal(decimal.NewFromInt(6100)) ||
+		!data.TotalFreeCollateral.Equal(decimal.NewFromInt(4000)) {
+		t.Errorf("unexpected totals: equity %s, used %s, free %s",
+			data.TotalEquity, data.TotalMarginUsed, data.TotalFreeCollateral)
+	}
+}
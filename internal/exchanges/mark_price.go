@@ -0,0 +1,24 @@
+package exchanges
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MarkPrice is the price a venue marks positions at, and the index price of
+// the underlying it derives from. Unrealized PnL, margin and liquidations
+// follow the mark rather than the last trade, which a thin book lets swing.
+type MarkPrice struct {
+	Symbol    string
+	Mark      decimal.Decimal
+	Index     decimal.Decimal // Zero when the venue does not report it
+	Timestamp time.Time
+}
+
+// MarkPriceStream is implemented by exchanges that stream the mark and index
+// prices of a market
+type MarkPriceStream interface {
+	SubscribeMarkPrice(ctx context.Context, symbol string, callback func(*MarkPrice)) error
+}
@@ -0,0 +1,16 @@
+package exchanges
+
+import "context"
+
+// MarketStatus is the trading status of a market
+type MarketStatus struct {
+	Halted bool
+	Reason string // Status reported by the venue when halted, e.g. "PAUSED"
+}
+
+// MarketStatusReporter is implemented by exchanges whose market metadata
+// tells whether trading in a market is halted, for maintenance, a delisting
+// or a circuit breaker
+type MarketStatusReporter interface {
+	GetMarketStatus(ctx context.Context, symbol string) (*MarketStatus, error)
+}
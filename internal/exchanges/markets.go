@@ -0,0 +1,24 @@
+package exchanges
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// Market is a market listed by an exchange
+type Market struct {
+	Symbol       string
+	Quote        string          // Quote currency, e.g. "USD"
+	Price        decimal.Decimal // Last or mark price
+	Volume24h    decimal.Decimal // Traded over the last 24h, in the quote currency
+	OpenInterest decimal.Decimal // In base units, zero for spot markets
+}
+
+// MarketLister is implemented by exchanges that can list the markets they
+// trade, so the traded symbols can be discovered instead of configured
+type MarketLister interface {
+	// ListMarkets returns the markets open for trading, leaving out the
+	// halted, delisted or cancel only ones
+	ListMarkets(ctx context.Context) ([]Market, error)
+}
@@ -19,6 +19,9 @@ type MockExchange struct {
 	balanceError  error
 	positionError error
 	orderError    error
+	lastPrice     decimal.Decimal
+	capabilities  Capabilities
+	leverage      map[string]LeverageSettings
 }
 
 func NewMockExchange(name string) *MockExchange {
@@ -57,6 +60,17 @@ func NewMockExchange(name string) *MockExchange {
 				CreatedAt: time.Now(),
 			},
 		},
+		capabilities: Capabilities{
+			Shorts:         true,
+			StopOrders:     true,
+			OCO:            true,
+			PostOnly:       true,
+			ReduceOnly:     true,
+			WebSocketFills: true,
+			Margin:         true,
+			Hedge:          true,
+		},
+		leverage: make(map[string]LeverageSettings),
 	}
 }
 
@@ -131,11 +145,15 @@ func (m *MockExchange) GetPosition(ctx context.Context, symbol string) (*Positio
 }
 
 func (m *MockExchange) GetTicker(ctx context.Context, symbol string) (*Ticker, error) {
+	last := decimal.NewFromFloat(50050)
+	if !m.lastPrice.IsZero() {
+		last = m.lastPrice
+	}
 	return &Ticker{
 		Symbol: symbol,
 		Bid:    decimal.NewFromFloat(50000),
 		Ask:    decimal.NewFromFloat(50100),
-		Last:   decimal.NewFromFloat(50050),
+		Last:   last,
 	}, nil
 }
 
@@ -188,6 +206,36 @@ func (m *MockExchange) SupportedSymbols() []string {
 	return []string{"BTC-USD", "ETH-USD"}
 }
 
+func (m *MockExchange) Capabilities() Capabilities {
+	return m.capabilities
+}
+
+func (m *MockExchange) SetLeverage(ctx context.Context, symbol string, leverage decimal.Decimal) error {
+	settings := m.leverageSettings(symbol)
+	settings.Leverage = leverage
+	m.leverage[symbol] = settings
+	return nil
+}
+
+func (m *MockExchange) SetMarginMode(ctx context.Context, symbol string, mode MarginMode) error {
+	settings := m.leverageSettings(symbol)
+	settings.MarginMode = mode
+	m.leverage[symbol] = settings
+	return nil
+}
+
+func (m *MockExchange) GetLeverage(ctx context.Context, symbol string) (*LeverageSettings, error) {
+	settings := m.leverageSettings(symbol)
+	return &settings, nil
+}
+
+func (m *MockExchange) leverageSettings(symbol string) LeverageSettings {
+	if settings, ok := m.leverage[symbol]; ok {
+		return settings
+	}
+	return LeverageSettings{Symbol: symbol, Leverage: decimal.NewFromInt(1), MarginMode: MarginModeCross}
+}
+
 // SetConnectError sets the error to return on Connect
 func (m *MockExchange) SetConnectError(err error) {
 	m.connectError = err
@@ -207,3 +255,13 @@ func (m *MockExchange) SetPositionError(err error) {
 func (m *MockExchange) SetOrderError(err error) {
 	m.orderError = err
 }
+
+// SetLastPrice sets the last price returned by GetTicker
+func (m *MockExchange) SetLastPrice(price decimal.Decimal) {
+	m.lastPrice = price
+}
+
+// SetCapabilities sets the capabilities returned by Capabilities
+func (m *MockExchange) SetCapabilities(capabilities Capabilities) {
+	m.capabilities = capabilities
+}
@@ -3,6 +3,8 @@ package exchanges
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,6 +17,28 @@ type ExchangeMultiplexer struct {
 	exchanges map[string]Exchange // exchange name -> exchange
 	symbolMap map[string]string   // symbol -> exchange name
 	data      *AggregatedData
+	fx        *FXConverter // Normalizes totals into a reporting currency when set
+
+	// Base currency and tradable symbols, by exchange name
+	settings map[string]ExchangeSettings
+
+	// Order latency of each exchange, routing entries away from slow ones
+	latency *LatencyTracker
+}
+
+// ExchangeSettings are the settings of an exchange in the multiplexer
+type ExchangeSettings struct {
+	BaseCurrency string       // Currency its collateral is held in, USD when empty
+	Symbols      SymbolFilter // Symbols it may trade
+}
+
+// baseCurrency returns the currency the collateral of the settings' exchange
+// is held in
+func (s ExchangeSettings) baseCurrency() string {
+	if s.BaseCurrency == "" {
+		return "USD"
+	}
+	return s.BaseCurrency
 }
 
 // NewExchangeMultiplexer creates a new exchange multiplexer
@@ -22,6 +46,7 @@ func NewExchangeMultiplexer() *ExchangeMultiplexer {
 	return &ExchangeMultiplexer{
 		exchanges: make(map[string]Exchange),
 		symbolMap: make(map[string]string),
+		settings:  make(map[string]ExchangeSettings),
 		data: &AggregatedData{
 			Exchanges:    make(map[string]*ExchangeData),
 			TotalBalance: decimal.Zero,
@@ -72,14 +97,25 @@ func (em *ExchangeMultiplexer) RefreshData(ctx context.Context) error {
 	for k, v := range em.exchanges {
 		exchanges[k] = v
 	}
+	fx := em.fx
+	settings := make(map[string]ExchangeSettings, len(em.settings))
+	for k, v := range em.settings {
+		settings[k] = v
+	}
 	em.mu.RUnlock()
 
+	if fx != nil {
+		fx.Refresh(ctx)
+	}
+
 	aggregated := &AggregatedData{
 		Exchanges:    make(map[string]*ExchangeData),
 		TotalBalance: decimal.Zero,
 		TotalPnL:     decimal.Zero,
 		LastUpdate:   time.Now().Unix(),
 	}
+	var totals marginTotals
+	unconverted := make(map[string]bool)
 
 	for name, exchange := range exchanges {
 		exchangeData := &ExchangeData{
@@ -87,6 +123,25 @@ func (em *ExchangeMultiplexer) RefreshData(ctx context.Context) error {
 			Connected: exchange.IsConnected(),
 		}
 
+		// value returns amount in the reporting currency, or as is without a
+		// converter. Amounts that cannot be converted are left out of the
+		// totals and reported as the exchange's error, so the snapshot is not
+		// taken for complete.
+		value := func(amount decimal.Decimal, currency string) decimal.Decimal {
+			if fx == nil || amount.IsZero() {
+				return amount
+			}
+			converted, err := fx.ConvertContext(ctx, amount, currency)
+			if err != nil {
+				unconverted[strings.ToUpper(currency)] = true
+				if exchangeData.Error == nil {
+					exchangeData.Error = fmt.Errorf("failed to convert %s to %s: %w", currency, fx.Currency(), err)
+				}
+				return decimal.Zero
+			}
+			return converted
+		}
+
 		// Get balances
 		balances, err := exchange.GetBalance(ctx)
 		if err != nil {
@@ -95,7 +150,7 @@ func (em *ExchangeMultiplexer) RefreshData(ctx context.Context) error {
 			exchangeData.Balances = balances
 			// Aggregate total balance (sum of all assets)
 			for _, balance := range balances {
-				aggregated.TotalBalance = aggregated.TotalBalance.Add(balance.Total)
+				aggregated.TotalBalance = aggregated.TotalBalance.Add(value(balance.Total, balance.Asset))
 			}
 		}
 
@@ -109,10 +164,28 @@ func (em *ExchangeMultiplexer) RefreshData(ctx context.Context) error {
 			exchangeData.Positions = positions
 			// Aggregate PnL
 			for _, pos := range positions {
-				aggregated.TotalPnL = aggregated.TotalPnL.Add(pos.UnrealizedPnL).Add(pos.RealizedPnL)
+				pnl := pos.UnrealizedPnL.Add(pos.RealizedPnL)
+				aggregated.TotalPnL = aggregated.TotalPnL.Add(value(pnl, QuoteCurrency(pos.Symbol)))
+				aggregated.TotalUnrealizedPnL = aggregated.TotalUnrealizedPnL.Add(value(pos.UnrealizedPnL, QuoteCurrency(pos.Symbol)))
 			}
 		}
 
+		// Get margin usage, estimated when the exchange cannot report it
+		if margin, err := accountMargin(ctx, exchange, exchangeData.Balances, exchangeData.Positions); err != nil {
+			if exchangeData.Error == nil {
+				exchangeData.Error = err
+			}
+		} else {
+			exchangeData.Margin = margin
+			// Margin is held in the exchange's base currency
+			base := settings[name].baseCurrency()
+			totals.add(AccountMargin{
+				Equity:         value(margin.Equity, base),
+				MarginUsed:     value(margin.MarginUsed, base),
+				FreeCollateral: value(margin.FreeCollateral, base),
+			})
+		}
+
 		// Get open orders
 		orders, err := exchange.GetOpenOrders(ctx, "")
 		if err != nil {
@@ -126,6 +199,15 @@ func (em *ExchangeMultiplexer) RefreshData(ctx context.Context) error {
 		aggregated.Exchanges[name] = exchangeData
 	}
 
+	totals.apply(aggregated)
+	if fx != nil {
+		aggregated.Currency = fx.Currency()
+	}
+	for currency := range unconverted {
+		aggregated.Unconverted = append(aggregated.Unconverted, currency)
+	}
+	sort.Strings(aggregated.Unconverted)
+
 	em.mu.Lock()
 	em.data = aggregated
 	em.mu.Unlock()
@@ -140,6 +222,23 @@ func (em *ExchangeMultiplexer) GetAggregatedData() *AggregatedData {
 	return em.data
 }
 
+// SetFXConverter normalizes the totals of the aggregated data into the
+// converter's reporting currency
+func (em *ExchangeMultiplexer) SetFXConverter(fx *FXConverter) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.fx = fx
+}
+
+// SetLatencyTracker records the latency of the orders placed through the
+// multiplexer in tracker and routes entries away from the exchanges it finds
+// slow
+func (em *ExchangeMultiplexer) SetLatencyTracker(tracker *LatencyTracker) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.latency = tracker
+}
+
 // AddExchange adds an exchange to the multiplexer
 func (em *ExchangeMultiplexer) AddExchange(name string, exchange Exchange) {
 	em.mu.Lock()
@@ -147,7 +246,32 @@ func (em *ExchangeMultiplexer) AddExchange(name string, exchange Exchange) {
 	em.exchanges[name] = exchange
 }
 
-// MapSymbol maps a symbol to a specific exchange
+// Configure sets the base currency and tradable symbols of an exchange
+func (em *ExchangeMultiplexer) Configure(exchangeName string, settings ExchangeSettings) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.settings[exchangeName] = settings
+}
+
+// AllowsSymbol reports whether symbol may be traded: by the exchange it is
+// mapped to, or by any exchange while it is not mapped
+func (em *ExchangeMultiplexer) AllowsSymbol(symbol string) bool {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	if exchangeName, mapped := em.symbolMap[symbol]; mapped {
+		return em.settings[exchangeName].Symbols.Allows(symbol)
+	}
+	for name := range em.exchanges {
+		if em.settings[name].Symbols.Allows(symbol) {
+			return true
+		}
+	}
+	return false
+}
+
+// MapSymbol maps a symbol to a specific exchange, which must be allowed to
+// trade it
 func (em *ExchangeMultiplexer) MapSymbol(symbol, exchangeName string) error {
 	em.mu.Lock()
 	defer em.mu.Unlock()
@@ -155,6 +279,9 @@ func (em *ExchangeMultiplexer) MapSymbol(symbol, exchangeName string) error {
 	if _, exists := em.exchanges[exchangeName]; !exists {
 		return fmt.Errorf("exchange %s not found", exchangeName)
 	}
+	if !em.settings[exchangeName].Symbols.Allows(symbol) {
+		return fmt.Errorf("%w: %s on %s", ErrSymbolNotAllowed, symbol, exchangeName)
+	}
 
 	em.symbolMap[symbol] = exchangeName
 	return nil
@@ -180,12 +307,63 @@ func (em *ExchangeMultiplexer) GetExchangeForSymbol(symbol string) (Exchange, er
 
 // PlaceOrder places an order on the appropriate exchange for the symbol
 func (em *ExchangeMultiplexer) PlaceOrder(ctx context.Context, order *Order) (*Order, error) {
-	exchange, err := em.GetExchangeForSymbol(order.Symbol)
+	name, exchange, err := em.routeOrder(order)
 	if err != nil {
 		return nil, err
 	}
 
-	return exchange.PlaceOrder(ctx, order)
+	em.mu.RLock()
+	tracker := em.latency
+	em.mu.RUnlock()
+	start := time.Now()
+	placed, err := exchange.PlaceOrder(ctx, order)
+	if tracker != nil {
+		// Failed orders count too: a timeout is the slowest round trip
+		tracker.Record(name, time.Since(start))
+	}
+	return placed, err
+}
+
+// routeOrder returns the exchange the symbol of order is mapped to or, for
+// entries while it is slow, the fastest other exchange allowed to trade the
+// symbol. Reduce-only orders always go to the mapped exchange, which holds
+// the position.
+func (em *ExchangeMultiplexer) routeOrder(order *Order) (string, Exchange, error) {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	name, exists := em.symbolMap[order.Symbol]
+	if !exists {
+		return "", nil, fmt.Errorf("no exchange mapped for symbol %s", order.Symbol)
+	}
+	exchange, exists := em.exchanges[name]
+	if !exists {
+		return "", nil, fmt.Errorf("exchange %s not found", name)
+	}
+	if em.latency == nil || order.ReduceOnly || !em.latency.Slow(name) {
+		return name, exchange, nil
+	}
+
+	// Venues without recent latencies rank after measured ones
+	best, bestP95, bestMeasured := "", time.Duration(0), false
+	for candidate := range em.exchanges {
+		if candidate == name || !em.settings[candidate].Symbols.Allows(order.Symbol) || em.latency.Slow(candidate) {
+			continue
+		}
+		p95, measured := em.latency.P95(candidate)
+		switch {
+		case best == "",
+			measured && (!bestMeasured || p95 < bestP95),
+			measured == bestMeasured && p95 == bestP95 && candidate < best:
+			best, bestP95, bestMeasured = candidate, p95, measured
+		}
+	}
+	if best == "" {
+		p95, _ := em.latency.P95(name)
+		return "", nil, fmt.Errorf("%w: %s p95 %s exceeds %s and no other exchange trades %s",
+			ErrVenueTooSlow, name, p95, em.latency.MaxP95(), order.Symbol)
+	}
+	return best, em.exchanges[best], nil
 }
 
 // GetPositions aggregates positions from all exchanges
@@ -249,6 +427,66 @@ func (em *ExchangeMultiplexer) GetBalance(ctx context.Context) ([]Balance, error
 	return aggregated, nil
 }
 
+// CrossExchangePrice returns the median last price of symbol across the
+// exchanges quoting it, used to tell a bad tick on one venue from a real
+// move. It fails unless at least two exchanges quote the symbol.
+func (em *ExchangeMultiplexer) CrossExchangePrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	prices := em.lastPrices(ctx, symbol, "")
+	if len(prices) < 2 {
+		return decimal.Zero, fmt.Errorf("%s is quoted by %d exchanges, need at least 2", symbol, len(prices))
+	}
+	return medianPrice(prices), nil
+}
+
+// AlternatePrice returns the median last price of symbol across the
+// exchanges other than the one it is traded on, used to confirm a price seen
+// on the trading venue before acting on it. It fails unless another exchange
+// quotes the symbol.
+func (em *ExchangeMultiplexer) AlternatePrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	em.mu.RLock()
+	venue := em.symbolMap[symbol]
+	em.mu.RUnlock()
+
+	prices := em.lastPrices(ctx, symbol, venue)
+	if len(prices) == 0 {
+		return decimal.Zero, fmt.Errorf("%s is not quoted by another exchange", symbol)
+	}
+	return medianPrice(prices), nil
+}
+
+// lastPrices returns the last price of symbol on every exchange quoting it,
+// except the exchange named exclude
+func (em *ExchangeMultiplexer) lastPrices(ctx context.Context, symbol, exclude string) []decimal.Decimal {
+	em.mu.RLock()
+	exchanges := make([]Exchange, 0, len(em.exchanges))
+	for name, exchange := range em.exchanges {
+		if name != exclude {
+			exchanges = append(exchanges, exchange)
+		}
+	}
+	em.mu.RUnlock()
+
+	prices := make([]decimal.Decimal, 0, len(exchanges))
+	for _, exchange := range exchanges {
+		ticker, err := exchange.GetTicker(ctx, symbol)
+		if err != nil || ticker == nil || !ticker.Last.IsPositive() {
+			continue
+		}
+		prices = append(prices, ticker.Last)
+	}
+	return prices
+}
+
+// medianPrice returns the median of prices, which must not be empty
+func medianPrice(prices []decimal.Decimal) decimal.Decimal {
+	sort.Slice(prices, func(i, j int) bool { return prices[i].LessThan(prices[j]) })
+	mid := len(prices) / 2
+	if len(prices)%2 == 0 {
+		return prices[mid-1].Add(prices[mid]).Div(decimal.NewFromInt(2))
+	}
+	return prices[mid]
+}
+
 // GetExchanges returns all registered exchanges
 func (em *ExchangeMultiplexer) GetExchanges() map[string]Exchange {
 	em.mu.RLock()
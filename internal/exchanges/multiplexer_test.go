@@ -0,0 +1,146 @@
+package exchanges
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestExchangeMultiplexer_CrossExchangePrice(t *testing.T) {
+	multiplexer := NewExchangeMultiplexer()
+	prices := map[string]float64{"a": 50000, "b": 50100, "c": 60000}
+	for name, price := range prices {
+		exchange := NewMockExchange(name)
+		exchange.SetLastPrice(decimal.NewFromFloat(price))
+		multiplexer.AddExchange(name, exchange)
+	}
+
+	median, err := multiplexer.CrossExchangePrice(context.Background(), "BTC-USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !median.Equal(decimal.NewFromFloat(50100)) {
+		t.Errorf("expected the outlier to be ignored by the median, got %s", median)
+	}
+
+	multiplexer.AddExchange("d", NewMockExchange("d")) // Quotes 50050
+	median, err = multiplexer.CrossExchangePrice(context.Background(), "BTC-USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !median.Equal(decimal.NewFromFloat(50075)) {
+		t.Errorf("expected the mean of the middle prices, got %s", median)
+	}
+
+	single := NewExchangeMultiplexer()
+	single.AddExchange("a", NewMockExchange("a"))
+	if _, err := single.CrossExchangePrice(context.Background(), "BTC-USD"); err == nil {
+		t.Error("expected an error with a single exchange")
+	}
+}
+
+func TestExchangeMultiplexer_AlternatePrice(t *testing.T) {
+	multiplexer := NewExchangeMultiplexer()
+	prices := map[string]float64{"a": 40000, "b": 50000}
+	for name, price := range prices {
+		exchange := NewMockExchange(name)
+		exchange.SetLastPrice(decimal.NewFromFloat(price))
+		multiplexer.AddExchange(name, exchange)
+	}
+	if err := multiplexer.MapSymbol("BTC-USD", "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	price, err := multiplexer.AlternatePrice(context.Background(), "BTC-USD")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !price.Equal(decimal.NewFromFloat(50000)) {
+		t.Errorf("expected the price of the other exchange, got %s", price)
+	}
+
+	single := NewExchangeMultiplexer()
+	single.AddExchange("a", NewMockExchange("a"))
+	if err := single.MapSymbol("BTC-USD", "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := single.AlternatePrice(context.Background(), "BTC-USD"); err == nil {
+		t.Error("expected an error without another exchange")
+	}
+}
+
+func TestExchangeMultiplexer_SymbolFilter(t *testing.T) {
+	multiplexer := NewExchangeMultiplexer()
+	multiplexer.AddExchange("a", NewMockExchange("a"))
+	multiplexer.AddExchange("b", NewMockExchange("b"))
+	onlyUSD, err := NewSymbolFilter([]string{"*-USD"}, []string{"PEPE-*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	multiplexer.Configure("a", ExchangeSettings{Symbols: onlyUSD})
+	noDoge, err := NewSymbolFilter(nil, []string{"DOGE-*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	multiplexer.Configure("b", ExchangeSettings{Symbols: noDoge})
+
+	for _, symbol := range []string{"BTC-EUR", "PEPE-USD"} {
+		if err := multiplexer.MapSymbol(symbol, "a"); !errors.Is(err, ErrSymbolNotAllowed) {
+			t.Errorf("MapSymbol(%s) = %v, want ErrSymbolNotAllowed", symbol, err)
+		}
+	}
+	if err := multiplexer.MapSymbol("BTC-USD", "a"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Unmapped symbols are allowed when any exchange may trade them
+	for symbol, want := range map[string]bool{"BTC-USD": true, "PEPE-USD": true, "DOGE-USD": true, "DOGE-EUR": false} {
+		if got := multiplexer.AllowsSymbol(symbol); got != want {
+			t.Errorf("AllowsSymbol(%s) = %v, want %v", symbol, got, want)
+		}
+	}
+	if err := multiplexer.MapSymbol("PEPE-USD", "b"); err != nil || !multiplexer.AllowsSymbol("PEPE-USD") {
+		t.Errorf("expected PEPE-USD to be tradable on b, got %v", err)
+	}
+}
+
+func TestExchangeMultiplexer_RoutesAwayFromSlowVenue(t *testing.T) {
+	multiplexer := NewExchangeMultiplexer()
+	for _, name := range []string{"slow", "fast", "faster"} {
+		multiplexer.AddExchange(name, NewMockExchange(name))
+	}
+	onlyETH, err := NewSymbolFilter([]string{"ETH-*"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	multiplexer.Configure("faster", ExchangeSettings{Symbols: onlyETH})
+	if err := multiplexer.MapSymbol("BTC-USD", "slow"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tracker := NewLatencyTracker(100*time.Millisecond, time.Minute)
+	multiplexer.SetLatencyTracker(tracker)
+	for i := 0; i < minLatencySamples; i++ {
+		tracker.Record("slow", time.Second)
+		tracker.Record("faster", time.Millisecond)
+	}
+
+	entry := &Order{Symbol: "BTC-USD", Side: OrderSideBuy, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1)}
+	name, _, err := multiplexer.routeOrder(entry)
+	if err != nil || name != "fast" {
+		t.Errorf("expected the entry routed to the other exchange trading BTC, got %q (%v)", name, err)
+	}
+	exit := &Order{Symbol: "BTC-USD", Side: OrderSideSell, Type: OrderTypeMarket, Amount: decimal.NewFromInt(1), ReduceOnly: true}
+	if name, _, err := multiplexer.routeOrder(exit); err != nil || name != "slow" {
+		t.Errorf("expected the exit sent to the exchange holding the position, got %q (%v)", name, err)
+	}
+
+	for i := 0; i < minLatencySamples; i++ {
+		tracker.Record("fast", time.Second)
+	}
+	if _, err := multiplexer.PlaceOrder(context.Background(), entry); !errors.Is(err, ErrVenueTooSlow) {
+		t.Errorf("expected the entry refused when every exchange is slow, got %v", err)
+	}
+}
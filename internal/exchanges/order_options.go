@@ -0,0 +1,35 @@
+package exchanges
+
+import "time"
+
+// OrderOptions are the venue-specific options of an order. Exchanges ignore
+// the options they do not support, except TWAP, which changes how the order
+// executes and is only sent to exchanges with NativeTWAP.
+type OrderOptions struct {
+	TWAP       *TWAPOptions
+	BuilderFee *BuilderFee
+	Longevity  OrderLongevity
+}
+
+// OrderLongevity chooses between the short-lived orders a venue only keeps
+// in memory and the stateful orders it stores, on venues that have both
+type OrderLongevity string
+
+const (
+	OrderLongevityAuto      OrderLongevity = ""           // Picked from the type and time in force
+	OrderLongevityShortTerm OrderLongevity = "short_term" // Cheap, expires within seconds
+	OrderLongevityLongTerm  OrderLongevity = "long_term"  // Rests until canceled or its expiry
+)
+
+// TWAPOptions has the exchange work a market order in slices over Duration
+type TWAPOptions struct {
+	Duration  time.Duration
+	Randomize bool // Randomize the timing of the slices
+}
+
+// BuilderFee is the fee paid on top of the exchange fees to the builder
+// whose address routed the order, in tenths of a basis point
+type BuilderFee struct {
+	Address   string
+	TenthsBps int
+}
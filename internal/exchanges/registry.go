@@ -0,0 +1,113 @@
+package exchanges
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Config holds the configuration of a connector
+type Config struct {
+	Enabled          bool
+	APIKey           string
+	APISecret        string
+	PortfolioID      string // For Coinbase
+	Perpetuals       bool   // For Coinbase, INTX perpetuals of the portfolio instead of spot
+	Mnemonic         string // For dYdX
+	SubAccountNumber int    // For dYdX
+
+	// Currency the exchange holds collateral in, and the symbols it may
+	// trade as glob patterns ("*-USD", "PEPE-*")
+	BaseCurrency string
+	AllowSymbols []string
+	DenySymbols  []string
+}
+
+// Connector is an exchange the bot can load. It owns its configuration:
+// LoadConfig reads the settings of the connector with getenv and rejects
+// them when the connector is enabled without what it needs to run.
+type Connector struct {
+	LoadConfig func(getenv func(string) string) (Config, error)
+	New        func(cfg Config) (Exchange, error)
+	Testnet    func(cfg Config) (Exchange, error) // Nil without a testnet
+}
+
+var (
+	connectorsMu sync.RWMutex
+	connectors   = make(map[string]Connector)
+)
+
+// RegisterConnector adds a connector to the registry, usually from the init
+// function of its package. It panics if name is already registered, as two
+// connectors cannot share a configuration.
+func RegisterConnector(name string, connector Connector) {
+	connectorsMu.Lock()
+	defer connectorsMu.Unlock()
+	if _, exists := connectors[name]; exists {
+		panic(fmt.Sprintf("exchange %q is already registered", name))
+	}
+	connectors[name] = connector
+}
+
+// unregisterConnector removes a connector from the registry, for tests
+func unregisterConnector(name string) {
+	connectorsMu.Lock()
+	defer connectorsMu.Unlock()
+	delete(connectors, name)
+}
+
+// Connectors returns the names of the registered connectors, sorted
+func Connectors() []string {
+	connectorsMu.RLock()
+	defer connectorsMu.RUnlock()
+	names := make([]string, 0, len(connectors))
+	for name := range connectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupConnector returns the connector registered under name
+func LookupConnector(name string) (Connector, bool) {
+	connectorsMu.RLock()
+	defer connectorsMu.RUnlock()
+	connector, ok := connectors[name]
+	return connector, ok
+}
+
+// LoadConfigs reads the configuration of every registered connector with
+// getenv, by connector name
+func LoadConfigs(getenv func(string) string) (map[string]Config, error) {
+	configs := make(map[string]Config)
+	for _, name := range Connectors() {
+		connector, _ := LookupConnector(name)
+		cfg, err := connector.LoadConfig(getenv)
+		if err != nil {
+			return nil, err
+		}
+		configs[name] = cfg
+	}
+	return configs, nil
+}
+
+// NewExchange creates the client of exchange name from its configuration
+func NewExchange(name string, cfg Config) (Exchange, error) {
+	connector, ok := LookupConnector(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange %q", name)
+	}
+	return connector.New(cfg)
+}
+
+// NewTestnetExchange creates the client of exchange name on its testnet
+func NewTestnetExchange(name string, cfg Config) (Exchange, error) {
+	connector, ok := LookupConnector(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown exchange %q", name)
+	}
+	if connector.Testnet == nil {
+		return nil, fmt.Errorf("%s has no testnet", name)
+	}
+	return connector.Testnet(cfg)
+}
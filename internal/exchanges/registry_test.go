@@ -0,0 +1,54 @@
+package exchanges
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConnectorRegistry(t *testing.T) {
+	fake := NewMockExchange("fake")
+	RegisterConnector("fake", Connector{
+		LoadConfig: func(getenv func(string) string) (Config, error) {
+			cfg := Config{Enabled: getenv("ENABLE_FAKE") == "true", APIKey: getenv("FAKE_API_KEY")}
+			if cfg.Enabled && cfg.APIKey == "" {
+				return cfg, errors.New("fake enabled but API key is missing")
+			}
+			return cfg, nil
+		},
+		New: func(cfg Config) (Exchange, error) {
+			if cfg.APIKey != "key" {
+				t.Errorf("expected the connector's configuration, got %+v", cfg)
+			}
+			return fake, nil
+		},
+	})
+	defer unregisterConnector("fake")
+
+	env := map[string]string{"ENABLE_FAKE": "true", "FAKE_API_KEY": "key"}
+	configs, err := LoadConfigs(func(key string) string { return env[key] })
+	if err != nil || !configs["fake"].Enabled {
+		t.Fatalf("expected the connector to read its configuration, got %+v (%v)", configs, err)
+	}
+	delete(env, "FAKE_API_KEY")
+	if _, err := LoadConfigs(func(key string) string { return env[key] }); err == nil {
+		t.Error("expected the connector's validation error")
+	}
+
+	exchange, err := NewExchange("fake", configs["fake"])
+	if err != nil || exchange != fake {
+		t.Errorf("expected the registered connector, got %v (%v)", exchange, err)
+	}
+	if _, err := NewExchange("missing", Config{}); err == nil {
+		t.Error("expected an error for an unregistered exchange")
+	}
+	if _, err := NewTestnetExchange("fake", configs["fake"]); err == nil {
+		t.Error("expected an error for a connector without testnet")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a name twice to panic")
+		}
+	}()
+	RegisterConnector("fake", Connector{})
+}
@@ -0,0 +1,19 @@
+package exchanges
+
+import (
+	"context"
+	"time"
+)
+
+// StreamMonitor is implemented by exchanges streaming market data over a
+// websocket, so a stream that stopped delivering can be detected and renewed
+type StreamMonitor interface {
+	// Streaming reports whether the stream carries any subscription; an
+	// idle stream is not expected to receive messages
+	Streaming() bool
+	// LastMessageAt returns when the stream last received a message, zero
+	// before the first one
+	LastMessageAt() time.Time
+	// ReconnectStream reconnects the stream and renews its subscriptions
+	ReconnectStream(ctx context.Context) error
+}
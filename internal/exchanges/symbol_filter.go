@@ -0,0 +1,50 @@
+package exchanges
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// SymbolFilter restricts the symbols an exchange may trade with glob
+// patterns such as "*-USD" or "PEPE-*". A symbol is allowed when it matches
+// one of the Allow patterns, or there are none, and none of the Deny ones.
+type SymbolFilter struct {
+	Allow []string
+	Deny  []string
+}
+
+// NewSymbolFilter creates a filter, rejecting malformed patterns
+func NewSymbolFilter(allow, deny []string) (SymbolFilter, error) {
+	filter := SymbolFilter{}
+	for _, pattern := range allow {
+		filter.Allow = append(filter.Allow, strings.ToUpper(pattern))
+	}
+	for _, pattern := range deny {
+		filter.Deny = append(filter.Deny, strings.ToUpper(pattern))
+	}
+	for _, pattern := range append(append([]string(nil), filter.Allow...), filter.Deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return SymbolFilter{}, fmt.Errorf("invalid symbol pattern %q: %w", pattern, err)
+		}
+	}
+	return filter, nil
+}
+
+// Allows reports whether symbol may be traded
+func (f SymbolFilter) Allows(symbol string) bool {
+	symbol = strings.ToUpper(symbol)
+	if len(f.Allow) > 0 && !matchesAny(f.Allow, symbol) {
+		return false
+	}
+	return !matchesAny(f.Deny, symbol)
+}
+
+func matchesAny(patterns []string, symbol string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, symbol); matched {
+			return true
+		}
+	}
+	return false
+}
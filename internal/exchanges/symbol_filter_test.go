@@ -0,0 +1,29 @@
+package exchanges
+
+import "testing"
+
+func TestSymbolFilter(t *testing.T) {
+	filter, err := NewSymbolFilter([]string{"*-usd", "*-USDC"}, []string{"PEPE-*"})
+	if err != nil {
+		t.Fatalf("NewSymbolFilter failed: %v", err)
+	}
+
+	tests := map[string]bool{
+		"BTC-USD":  true,
+		"eth-usdc": true,
+		"BTC-EUR":  false, // Not allowed
+		"PEPE-USD": false, // Denied
+	}
+	for symbol, want := range tests {
+		if got := filter.Allows(symbol); got != want {
+			t.Errorf("Allows(%s) = %v, want %v", symbol, got, want)
+		}
+	}
+
+	if !(SymbolFilter{}).Allows("ANY-THING") {
+		t.Error("expected an empty filter to allow every symbol")
+	}
+	if _, err := NewSymbolFilter(nil, []string{"[BTC"}); err == nil {
+		t.Error("expected a malformed pattern to be rejected")
+	}
+}
@@ -0,0 +1,447 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// AlgoType selects how entries too large for the book are worked
+type AlgoType string
+
+const (
+	AlgoNone    AlgoType = "none"
+	AlgoTWAP    AlgoType = "twap"    // Equal slices spread evenly over a duration
+	AlgoIceberg AlgoType = "iceberg" // Only one slice shown at a time, replaced once filled
+	AlgoChase   AlgoType = "chase"   // Pegged to the touch and repriced as the book moves
+	AlgoMaker   AlgoType = "maker"   // Posted at the touch, then crossed or abandoned
+)
+
+// AlgoStatus is the state of a parent order worked by an algorithm
+type AlgoStatus string
+
+const (
+	AlgoStatusRunning   AlgoStatus = "running"
+	AlgoStatusCompleted AlgoStatus = "completed"
+	AlgoStatusCanceled  AlgoStatus = "canceled"
+	AlgoStatusFailed    AlgoStatus = "failed"
+)
+
+// algoRetention is how long finished algorithms stay visible in AlgoOrders
+const algoRetention = time.Hour
+
+// AlgoOrder tracks a parent order split into child orders
+type AlgoOrder struct {
+	ID        string
+	Type      AlgoType
+	Symbol    string
+	Side      exchanges.OrderSide
+	Total     decimal.Decimal
+	Submitted decimal.Decimal // Amount sent as child orders so far
+	Slices    int
+	Children  []string // Child order IDs
+	Status    AlgoStatus
+	Error     string
+	StartedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Progress returns the fraction of the parent submitted, from 0 to 1
+func (a AlgoOrder) Progress() float64 {
+	if !a.Total.IsPositive() {
+		return 0
+	}
+	return a.Submitted.Div(a.Total).InexactFloat64()
+}
+
+// OrderCanceler is implemented by order managers that can cancel orders; it
+// is used to pull the resting slice of a canceled iceberg
+type OrderCanceler interface {
+	CancelOrder(ctx context.Context, orderID string) error
+}
+
+// OrderLookup is implemented by order managers that keep the state of
+// finished orders; chases need it to learn what a canceled order filled
+type OrderLookup interface {
+	GetOrder(orderID string) *exchanges.Order
+}
+
+// CapabilityReporter is implemented by order managers that report the
+// features of their exchange; native TWAPs are only used when it has them
+type CapabilityReporter interface {
+	Capabilities() exchanges.Capabilities
+}
+
+type algoRun struct {
+	order  AlgoOrder
+	cancel context.CancelFunc
+}
+
+// algoEnabled reports whether large entries are worked by an algorithm
+func (e *ExecutionAgent) algoEnabled() bool {
+	return (e.config.Algo == AlgoTWAP || e.config.Algo == AlgoIceberg) && e.config.AlgoDepthFraction > 0
+}
+
+// useAlgo reports whether req is too large to place at once: larger than
+// AlgoDepthFraction of the depth visible on the side it trades against
+func (e *ExecutionAgent) useAlgo(req *order.OrderRequest, book *exchanges.OrderBook) bool {
+	if book == nil || !e.algoEnabled() {
+		return false
+	}
+	threshold := visibleDepth(book, req.Side).Mul(decimal.NewFromFloat(e.config.AlgoDepthFraction))
+	return req.Amount.GreaterThan(threshold)
+}
+
+// planSlices splits amount into the child order sizes of the configured algorithm
+func (e *ExecutionAgent) planSlices(amount decimal.Decimal) []decimal.Decimal {
+	var slice decimal.Decimal
+	switch e.config.Algo {
+	case AlgoTWAP:
+		if e.config.TWAPSlices > 1 {
+			slice = amount.Div(decimal.NewFromInt(int64(e.config.TWAPSlices))).Truncate(8)
+		}
+	case AlgoIceberg:
+		slice = amount.Mul(e.config.IcebergDisplayFraction).Truncate(8)
+	}
+	if !slice.IsPositive() || slice.GreaterThanOrEqual(amount) {
+		return []decimal.Decimal{amount}
+	}
+
+	// Every slice but the last has the same size; the last takes the remainder
+	var slices []decimal.Decimal
+	remaining := amount
+	for remaining.GreaterThan(slice) && (e.config.Algo != AlgoTWAP || len(slices) < e.config.TWAPSlices-1) {
+		slices = append(slices, slice)
+		remaining = remaining.Sub(slice)
+	}
+	return append(slices, remaining)
+}
+
+// startAlgo places the first slice of req and works the remaining slices in
+// the background. The algorithm is registered before the first slice is
+// placed, so every child order belongs to a tracked algorithm. Errors placing
+// the first slice are returned; every error fails the algorithm.
+func (e *ExecutionAgent) startAlgo(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+	if e.nativeTWAP() {
+		return e.startNativeTWAP(ctx, req)
+	}
+
+	slices := e.planSlices(req.Amount)
+	run := e.newAlgoRun(e.config.Algo, req, len(slices))
+	runCtx := e.registerAlgo(ctx, run)
+
+	first, err := e.placeSlice(ctx, run, req, slices[0])
+	if err != nil {
+		run.cancel()
+		e.finishAlgo(run, AlgoStatusFailed, err)
+		return nil, err
+	}
+
+	go e.workAlgo(runCtx, run, req, slices[1:], first)
+	return first, nil
+}
+
+// nativeTWAP reports whether TWAPs are worked by the exchange rather than
+// sliced here
+func (e *ExecutionAgent) nativeTWAP() bool {
+	if e.config.Algo != AlgoTWAP || !e.config.NativeTWAP {
+		return false
+	}
+	reporter, ok := e.orderManager.(CapabilityReporter)
+	return ok && reporter.Capabilities().NativeTWAP
+}
+
+// startNativeTWAP places req as a single market order the exchange works
+// over the TWAP duration. The algorithm runs until the order leaves the
+// book; canceling it cancels the order.
+func (e *ExecutionAgent) startNativeTWAP(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+	twap := *req
+	twap.Type = exchanges.OrderTypeMarket
+	twap.TimeInForce = ""
+	twap.ExpiresAt = time.Time{}
+	twap.PostOnly = false
+	options := exchanges.OrderOptions{}
+	if req.Options != nil {
+		options = *req.Options
+	}
+	options.TWAP = &exchanges.TWAPOptions{Duration: e.config.TWAPDuration}
+	twap.Options = &options
+
+	run := e.newAlgoRun(AlgoTWAP, req, 1)
+	runCtx := e.registerAlgo(ctx, run)
+	placed, err := e.placeSlice(ctx, run, &twap, req.Amount)
+	if err != nil {
+		run.cancel()
+		e.finishAlgo(run, AlgoStatusFailed, err)
+		return nil, err
+	}
+
+	go func() {
+		if err := e.waitForOrder(runCtx, placed); err != nil {
+			e.finishAlgo(run, AlgoStatusCanceled, nil)
+			return
+		}
+		e.finishAlgo(run, AlgoStatusCompleted, nil)
+	}()
+	return placed, nil
+}
+
+// newAlgoRun creates the tracking of an algorithm working req
+func (e *ExecutionAgent) newAlgoRun(algo AlgoType, req *order.OrderRequest, slices int) *algoRun {
+	now := e.clock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.algoSeq++
+	return &algoRun{order: AlgoOrder{
+		ID:        fmt.Sprintf("algo-%d", e.algoSeq),
+		Type:      algo,
+		Symbol:    req.Symbol,
+		Side:      req.Side,
+		Total:     req.Amount,
+		Submitted: decimal.Zero,
+		Slices:    slices,
+		Status:    AlgoStatusRunning,
+		StartedAt: now,
+		UpdatedAt: now,
+	}}
+}
+
+// registerAlgo records a started algorithm and returns the context it runs
+// in, which outlives ctx, the context of the signal, and is canceled by
+// CancelAlgo or, on shutdown, CancelAlgos
+func (e *ExecutionAgent) registerAlgo(ctx context.Context, run *algoRun) context.Context {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	now := e.clock()
+
+	e.mu.Lock()
+	run.cancel = cancel
+	if e.algos == nil {
+		e.algos = make(map[string]*algoRun)
+	}
+	for id, finished := range e.algos {
+		if finished.order.Status != AlgoStatusRunning && now.Sub(finished.order.UpdatedAt) > algoRetention {
+			delete(e.algos, id)
+		}
+	}
+	e.algos[run.order.ID] = run
+	progress := run.order
+	e.mu.Unlock()
+
+	logger.Component("execution").Info("execution algorithm started",
+		"algo", progress.ID,
+		"type", progress.Type,
+		"symbol", progress.Symbol,
+		"amount", progress.Total.String(),
+		"slices", progress.Slices)
+	return runCtx
+}
+
+// workAlgo places the remaining slices of an algorithm until done or canceled
+func (e *ExecutionAgent) workAlgo(ctx context.Context, run *algoRun, req *order.OrderRequest, slices []decimal.Decimal, previous *exchanges.Order) {
+	defer run.cancel()
+
+	for _, amount := range slices {
+		if err := e.waitForSlice(ctx, previous); err != nil {
+			e.finishAlgo(run, AlgoStatusCanceled, nil)
+			return
+		}
+		placed, err := e.placeSlice(ctx, run, req, amount)
+		if err != nil {
+			if ctx.Err() != nil {
+				e.finishAlgo(run, AlgoStatusCanceled, nil)
+			} else {
+				e.finishAlgo(run, AlgoStatusFailed, err)
+			}
+			return
+		}
+		previous = placed
+	}
+	e.finishAlgo(run, AlgoStatusCompleted, nil)
+}
+
+// waitForSlice waits until the next slice is due: after the TWAP interval,
+// or once the previous iceberg slice has left the book. The resting iceberg
+// slice is canceled if the algorithm is.
+func (e *ExecutionAgent) waitForSlice(ctx context.Context, previous *exchanges.Order) error {
+	if e.config.Algo == AlgoTWAP {
+		interval := e.config.TWAPDuration / time.Duration(max(e.config.TWAPSlices, 1))
+		timer := time.NewTimer(interval)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return nil
+		}
+	}
+	return e.waitForOrder(ctx, previous)
+}
+
+// waitForOrder waits until placed has left the book, canceling it if ctx is
+// canceled first
+func (e *ExecutionAgent) waitForOrder(ctx context.Context, placed *exchanges.Order) error {
+	poll := e.config.AlgoPollInterval
+	if poll <= 0 {
+		poll = time.Second
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for e.isOpen(placed.ID) {
+		select {
+		case <-ctx.Done():
+			if canceler, ok := e.orderManager.(OrderCanceler); ok {
+				_ = canceler.CancelOrder(context.WithoutCancel(ctx), placed.ID)
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+func (e *ExecutionAgent) isOpen(orderID string) bool {
+	return e.openOrder(orderID) != nil
+}
+
+// openOrder returns the open order with orderID, if it is still open
+func (e *ExecutionAgent) openOrder(orderID string) *exchanges.Order {
+	for _, open := range e.orderManager.GetOpenOrders() {
+		if open.ID == orderID {
+			return open
+		}
+	}
+	return nil
+}
+
+// placeSlice places one child order of an algorithm
+func (e *ExecutionAgent) placeSlice(ctx context.Context, run *algoRun, req *order.OrderRequest, amount decimal.Decimal) (*exchanges.Order, error) {
+	child := *req
+	child.Amount = amount
+	child.ClientOrderID = ""
+	if child.TimeInForce == exchanges.TimeInForceGTD {
+		child.ExpiresAt = e.clock().Add(e.config.EntryOrderTTL)
+	}
+
+	if err := e.reserveOrders(child.Symbol, protectedOrderCount(&child)); err != nil {
+		return nil, err
+	}
+	placed, err := e.orderManager.PlaceOrder(ctx, &child)
+	if err != nil {
+		return nil, exchangeError(ExecutionErrorTypeOrderPlacementFailed, err)
+	}
+
+	e.mu.Lock()
+	run.order.Submitted = run.order.Submitted.Add(amount)
+	run.order.Children = append(run.order.Children, placed.ID)
+	run.order.UpdatedAt = e.clock()
+	e.mu.Unlock()
+	return placed, nil
+}
+
+func (e *ExecutionAgent) finishAlgo(run *algoRun, status AlgoStatus, err error) {
+	e.mu.Lock()
+	run.order.Status = status
+	run.order.UpdatedAt = e.clock()
+	if err != nil {
+		run.order.Error = err.Error()
+	}
+	progress := run.order
+	e.mu.Unlock()
+
+	log := logger.Component("execution")
+	if err != nil {
+		log.Error("execution algorithm failed", "algo", progress.ID, "symbol", progress.Symbol,
+			"submitted", progress.Submitted.String(), "total", progress.Total.String(), "error", err)
+		return
+	}
+	log.Info("execution algorithm finished", "algo", progress.ID, "symbol", progress.Symbol,
+		"status", status, "submitted", progress.Submitted.String(), "total", progress.Total.String())
+}
+
+// AlgoOrders returns the running algorithms and those finished within the
+// last hour, oldest first
+func (e *ExecutionAgent) AlgoOrders() []AlgoOrder {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	orders := make([]AlgoOrder, 0, len(e.algos))
+	for _, run := range e.algos {
+		progress := run.order
+		progress.Children = append([]string(nil), run.order.Children...)
+		orders = append(orders, progress)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].StartedAt.Before(orders[j].StartedAt)
+	})
+	return orders
+}
+
+// CancelAlgo stops a running algorithm. Child orders already filled are kept.
+func (e *ExecutionAgent) CancelAlgo(id string) error {
+	e.mu.RLock()
+	run, ok := e.algos[id]
+	running := ok && run.order.Status == AlgoStatusRunning
+	e.mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("execution algorithm %s not found", id)
+	}
+	if running {
+		run.cancel()
+	}
+	return nil
+}
+
+// CancelAlgos stops every running algorithm, on shutdown or when trading
+// must stop, and returns how many were running. Child orders already filled
+// are kept.
+func (e *ExecutionAgent) CancelAlgos() int {
+	e.mu.RLock()
+	var running []*algoRun
+	for _, run := range e.algos {
+		if run.order.Status == AlgoStatusRunning {
+			running = append(running, run)
+		}
+	}
+	e.mu.RUnlock()
+
+	for _, run := range running {
+		run.cancel()
+	}
+	return len(running)
+}
+
+// algoRunning reports whether an algorithm is still building a position on symbol
+func (e *ExecutionAgent) algoRunning(symbol string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, run := range e.algos {
+		if run.order.Symbol == symbol && run.order.Status == AlgoStatusRunning {
+			return true
+		}
+	}
+	return false
+}
+
+// cancelSymbolAlgos stops the algorithms running on symbol
+func (e *ExecutionAgent) cancelSymbolAlgos(symbol string) {
+	e.mu.RLock()
+	var cancels []context.CancelFunc
+	for _, run := range e.algos {
+		if run.order.Symbol == symbol && run.order.Status == AlgoStatusRunning {
+			cancels = append(cancels, run.cancel)
+		}
+	}
+	e.mu.RUnlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
@@ -0,0 +1,336 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// algoOrderManager records child orders and keeps them open until filled
+type algoOrderManager struct {
+	mockOrderManager
+
+	mu       sync.Mutex
+	placed   []decimal.Decimal
+	open     map[string]*exchanges.Order
+	orders   map[string]*exchanges.Order
+	canceled []string
+	placeErr error
+	// fillOnCancel is filled on the orders it cancels, as if the fill
+	// raced the cancel
+	fillOnCancel decimal.Decimal
+}
+
+func newAlgoOrderManager() *algoOrderManager {
+	return &algoOrderManager{open: make(map[string]*exchanges.Order), orders: make(map[string]*exchanges.Order)}
+}
+
+func (m *algoOrderManager) PlaceOrder(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.placeErr != nil {
+		return nil, m.placeErr
+	}
+	m.placed = append(m.placed, req.Amount)
+	placed := &exchanges.Order{ID: fmt.Sprintf("child-%d", len(m.placed)), Symbol: req.Symbol, Price: req.Price, Amount: req.Amount, Status: exchanges.OrderStatusOpen}
+	m.open[placed.ID] = placed
+	m.orders[placed.ID] = placed
+	return placed, nil
+}
+
+func (m *algoOrderManager) GetOrder(orderID string) *exchanges.Order {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if o, ok := m.orders[orderID]; ok {
+		copied := *o
+		return &copied
+	}
+	return nil
+}
+
+func (m *algoOrderManager) GetOpenOrders() []*exchanges.Order {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	orders := make([]*exchanges.Order, 0, len(m.open))
+	for _, o := range m.open {
+		orders = append(orders, o)
+	}
+	return orders
+}
+
+func (m *algoOrderManager) CancelOrder(ctx context.Context, orderID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.canceled = append(m.canceled, orderID)
+	delete(m.open, orderID)
+	if o, ok := m.orders[orderID]; ok {
+		o.Status = exchanges.OrderStatusCanceled
+		o.Filled = m.fillOnCancel
+	}
+	return nil
+}
+
+func (m *algoOrderManager) fill(orderID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.open, orderID)
+	if o, ok := m.orders[orderID]; ok {
+		o.Status = exchanges.OrderStatusFilled
+		o.Filled = o.Amount
+	}
+}
+
+func (m *algoOrderManager) placedAmounts() []decimal.Decimal {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]decimal.Decimal(nil), m.placed...)
+}
+
+func newAlgoAgent(orders *algoOrderManager, config Config) *ExecutionAgent {
+	config.AutoExecute = true
+	config.StopLossPercent = decimal.NewFromFloat(0.01)
+	config.AlgoDepthFraction = 0.5
+	config.AlgoPollInterval = time.Millisecond
+	agent := &ExecutionAgent{
+		orderManager: orders,
+		riskManager: &mockRiskManager{
+			calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+				return decimal.NewFromInt(10)
+			},
+		},
+		config: config,
+	}
+	agent.SetOrderBookSource(func(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error) {
+		return liquidityTestBook(), nil // 13 on each side, so entries above 6.5 are worked
+	})
+	return agent
+}
+
+func algoTestSignal() *strategy.Signal {
+	return &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Strength: 1,
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Symbol:   "BTC-USD",
+	}
+}
+
+func waitForAlgo(t *testing.T, agent *ExecutionAgent, status AlgoStatus) AlgoOrder {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		algos := agent.AlgoOrders()
+		if len(algos) == 1 && algos[0].Status == status {
+			return algos[0]
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("algorithm did not reach status %s: %+v", status, agent.AlgoOrders())
+	return AlgoOrder{}
+}
+
+func TestPlanSlices(t *testing.T) {
+	agent := &ExecutionAgent{config: Config{Algo: AlgoTWAP, TWAPSlices: 3}}
+	slices := agent.planSlices(decimal.NewFromInt(10))
+	require.Len(t, slices, 3)
+	total := decimal.Zero
+	for _, slice := range slices {
+		total = total.Add(slice)
+	}
+	assert.True(t, decimal.NewFromInt(10).Equal(total))
+
+	agent.config = Config{Algo: AlgoIceberg, IcebergDisplayFraction: decimal.NewFromFloat(0.4)}
+	slices = agent.planSlices(decimal.NewFromInt(10))
+	require.Len(t, slices, 3)
+	assert.True(t, decimal.NewFromInt(4).Equal(slices[0]))
+	assert.True(t, decimal.NewFromInt(2).Equal(slices[2]))
+
+	agent.config = Config{Algo: AlgoIceberg, IcebergDisplayFraction: decimal.NewFromInt(1)}
+	assert.Len(t, agent.planSlices(decimal.NewFromInt(10)), 1)
+}
+
+func TestHandleSignal_SmallEntryPlacedDirectly(t *testing.T) {
+	orders := newAlgoOrderManager()
+	agent := newAlgoAgent(orders, Config{Algo: AlgoTWAP, TWAPSlices: 5, TWAPDuration: time.Millisecond})
+	agent.config.AlgoDepthFraction = 0.9 // 10 is below 90% of the 13 visible
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+	assert.Len(t, orders.placedAmounts(), 1)
+	assert.Empty(t, agent.AlgoOrders())
+}
+
+func TestHandleSignal_LargeEntryWorkedByTWAP(t *testing.T) {
+	orders := newAlgoOrderManager()
+	agent := newAlgoAgent(orders, Config{Algo: AlgoTWAP, TWAPSlices: 5, TWAPDuration: 5 * time.Millisecond})
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+
+	progress := waitForAlgo(t, agent, AlgoStatusCompleted)
+	assert.Len(t, orders.placedAmounts(), 5)
+	assert.Len(t, progress.Children, 5)
+	assert.True(t, decimal.NewFromInt(10).Equal(progress.Submitted))
+	assert.Equal(t, 1.0, progress.Progress())
+}
+
+func TestHandleSignal_LargeEntryWorkedByIceberg(t *testing.T) {
+	orders := newAlgoOrderManager()
+	agent := newAlgoAgent(orders, Config{Algo: AlgoIceberg, IcebergDisplayFraction: decimal.NewFromFloat(0.5)})
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+
+	// The next slice is only shown once the visible one fills
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, orders.placedAmounts(), 1)
+	assert.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()), "entries wait for the running algorithm")
+	assert.Len(t, orders.placedAmounts(), 1)
+
+	orders.fill("child-1")
+	progress := waitForAlgo(t, agent, AlgoStatusCompleted)
+	assert.Len(t, orders.placedAmounts(), 2)
+	assert.True(t, decimal.NewFromInt(10).Equal(progress.Submitted))
+}
+
+func TestHandleSignal_SlippageCheckedPerSlice(t *testing.T) {
+	// 3 are offered within 10 bps: too little for the entry of 10 at once,
+	// enough for each of its TWAP slices of 2
+	orders := newAlgoOrderManager()
+	agent := newAlgoAgent(orders, Config{Algo: AlgoTWAP, TWAPSlices: 5, TWAPDuration: 5 * time.Millisecond, MaxSlippageBps: 10})
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+	waitForAlgo(t, agent, AlgoStatusCompleted)
+	assert.Len(t, orders.placedAmounts(), 5)
+
+	// Iceberg slices of 5 still cannot be absorbed
+	orders = newAlgoOrderManager()
+	agent = newAlgoAgent(orders, Config{Algo: AlgoIceberg, IcebergDisplayFraction: decimal.NewFromFloat(0.5), MaxSlippageBps: 10})
+	var execErr *ExecutionError
+	if assert.ErrorAs(t, agent.HandleSignal(context.Background(), algoTestSignal()), &execErr) {
+		assert.Equal(t, ExecutionErrorTypeInsufficientLiquidity, execErr.Type)
+	}
+	assert.Empty(t, orders.placedAmounts())
+}
+
+func TestHandleSignal_AlgoRegisteredBeforeFirstSlice(t *testing.T) {
+	orders := newAlgoOrderManager()
+	agent := newAlgoAgent(orders, Config{Algo: AlgoTWAP, TWAPSlices: 5, TWAPDuration: time.Hour})
+	var running []AlgoOrder
+	orders.mockOrderManager.placeOrderFunc = func(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+		running = agent.AlgoOrders()
+		return nil, errors.New("exchange unavailable")
+	}
+	agent.orderManager = &orders.mockOrderManager
+
+	require.Error(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+	require.Len(t, running, 1, "the algorithm is tracked while its first slice is placed")
+	progress := waitForAlgo(t, agent, AlgoStatusFailed)
+	assert.Contains(t, progress.Error, "exchange unavailable")
+}
+
+func TestCancelAlgo(t *testing.T) {
+	orders := newAlgoOrderManager()
+	agent := newAlgoAgent(orders, Config{Algo: AlgoIceberg, IcebergDisplayFraction: decimal.NewFromFloat(0.25)})
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+	algos := agent.AlgoOrders()
+	require.Len(t, algos, 1)
+
+	require.NoError(t, agent.CancelAlgo(algos[0].ID))
+	progress := waitForAlgo(t, agent, AlgoStatusCanceled)
+	assert.Equal(t, 0.25, progress.Progress())
+
+	orders.mu.Lock()
+	assert.Equal(t, []string{"child-1"}, orders.canceled)
+	orders.mu.Unlock()
+
+	assert.Error(t, agent.CancelAlgo("missing"))
+}
+
+func TestCancelAlgos(t *testing.T) {
+	orders := newAlgoOrderManager()
+	agent := newAlgoAgent(orders, Config{Algo: AlgoIceberg, IcebergDisplayFraction: decimal.NewFromFloat(0.25)})
+
+	// The algorithm outlives the context of its signal
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, agent.HandleSignal(ctx, algoTestSignal()))
+	cancel()
+	require.Equal(t, AlgoStatusRunning, agent.AlgoOrders()[0].Status)
+
+	assert.Equal(t, 1, agent.CancelAlgos())
+	waitForAlgo(t, agent, AlgoStatusCanceled)
+	assert.Equal(t, 0, agent.CancelAlgos(), "finished algorithms are left alone")
+}
+
+// nativeTWAPOrderManager is an algoOrderManager on an exchange that works
+// TWAPs itself
+type nativeTWAPOrderManager struct {
+	*algoOrderManager
+	requests []order.OrderRequest
+}
+
+func (m *nativeTWAPOrderManager) Capabilities() exchanges.Capabilities {
+	return exchanges.Capabilities{NativeTWAP: true}
+}
+
+func (m *nativeTWAPOrderManager) PlaceOrder(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+	m.mu.Lock()
+	m.requests = append(m.requests, *req)
+	m.mu.Unlock()
+	return m.algoOrderManager.PlaceOrder(ctx, req)
+}
+
+func TestHandleSignal_LargeEntryWorkedByNativeTWAP(t *testing.T) {
+	orders := &nativeTWAPOrderManager{algoOrderManager: newAlgoOrderManager()}
+	agent := newAlgoAgent(orders.algoOrderManager, Config{
+		Algo:                AlgoTWAP,
+		TWAPSlices:          5,
+		TWAPDuration:        10 * time.Minute,
+		NativeTWAP:          true,
+		EntryPostOnly:       true,
+		BuilderAddress:      "0xbuilder",
+		BuilderFeeTenthsBps: 10,
+	})
+	agent.orderManager = orders
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+
+	// The whole entry is sent at once, as a market order the exchange works
+	require.Len(t, orders.requests, 1)
+	req := orders.requests[0]
+	assert.True(t, decimal.NewFromInt(10).Equal(req.Amount))
+	assert.Equal(t, exchanges.OrderTypeMarket, req.Type)
+	assert.False(t, req.PostOnly)
+	require.NotNil(t, req.Options)
+	require.NotNil(t, req.Options.TWAP)
+	assert.Equal(t, 10*time.Minute, req.Options.TWAP.Duration)
+	require.NotNil(t, req.Options.BuilderFee)
+	assert.Equal(t, "0xbuilder", req.Options.BuilderFee.Address)
+
+	// The algorithm runs until the exchange is done with the order
+	algos := agent.AlgoOrders()
+	require.Len(t, algos, 1)
+	assert.Equal(t, AlgoStatusRunning, algos[0].Status)
+	orders.fill("child-1")
+	progress := waitForAlgo(t, agent, AlgoStatusCompleted)
+	assert.Equal(t, 1, progress.Slices)
+	assert.Equal(t, []string{"child-1"}, progress.Children)
+}
+
+func TestHandleSignal_NativeTWAPNeedsExchangeSupport(t *testing.T) {
+	orders := newAlgoOrderManager()
+	agent := newAlgoAgent(orders, Config{Algo: AlgoTWAP, TWAPSlices: 5, TWAPDuration: 5 * time.Millisecond, NativeTWAP: true})
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+
+	waitForAlgo(t, agent, AlgoStatusCompleted)
+	assert.Len(t, orders.placedAmounts(), 5, "TWAPs are sliced here when the exchange cannot work them")
+}
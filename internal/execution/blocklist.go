@@ -0,0 +1,44 @@
+package execution
+
+import (
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// Blocklist reports the markets that must never be traded
+type Blocklist interface {
+	// Blocked reports whether symbol is blocked on exchange, and why
+	Blocked(exchange, symbol string) (string, bool)
+}
+
+// SetBlocklist refuses entries on the markets blocklist blocks. Exits are
+// still executed so positions on them can be closed.
+func (e *ExecutionAgent) SetBlocklist(blocklist Blocklist) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.blocklist = blocklist
+}
+
+// checkBlocklist returns why entries on symbol are refused by the blocklist
+func (e *ExecutionAgent) checkBlocklist(symbol string) (string, bool) {
+	e.mu.RLock()
+	blocklist := e.blocklist
+	resolver := e.resolveExchange
+	e.mu.RUnlock()
+	if blocklist == nil {
+		return "", false
+	}
+
+	exchangeName := ""
+	if resolver != nil {
+		exchangeName = resolver(symbol)
+	}
+	reason, blocked := blocklist.Blocked(exchangeName, symbol)
+	if !blocked {
+		return "", false
+	}
+	telemetry.RecordSignalBlocked(symbol, "blocklist")
+	if reason == "" {
+		return "market blocklisted", true
+	}
+	return "market blocklisted: " + reason, true
+}
@@ -0,0 +1,40 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/blocklist"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSignal_Blocklist(t *testing.T) {
+	var placed []*order.OrderRequest
+	agent := newScalingAgent(nil, &placed, Config{})
+	agent.SetExchangeResolver(func(symbol string) string { return "dydx" })
+	var decisions []Decision
+	agent.SetDecisionCallback(func(decision Decision) { decisions = append(decisions, decision) })
+
+	list := blocklist.New()
+	require.NoError(t, list.Block(blocklist.Entry{Symbol: "LUNA-USD", Reason: "delisted"}))
+	require.NoError(t, list.Block(blocklist.Entry{Exchange: "hyperliquid", Symbol: "ETH-USD"}))
+	agent.SetBlocklist(list)
+
+	entry := func(symbol string) *strategy.Signal {
+		return &strategy.Signal{Type: strategy.SignalTypeEntry, Side: exchanges.OrderSideBuy, Price: decimal.NewFromInt(100), Symbol: symbol}
+	}
+
+	require.NoError(t, agent.HandleSignal(context.Background(), entry("LUNA-USD")))
+	assert.Empty(t, placed)
+	assert.Equal(t, DecisionSkipped, decisions[0].Outcome)
+	assert.Equal(t, "market blocklisted: delisted", decisions[0].Reason)
+
+	// Blocked on another exchange only
+	require.NoError(t, agent.HandleSignal(context.Background(), entry("ETH-USD")))
+	assert.Len(t, placed, 1)
+}
@@ -0,0 +1,167 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// StopMover is implemented by order managers that can move the stop loss of
+// a position, replacing its order
+type StopMover interface {
+	MoveStopLoss(ctx context.Context, symbol string, side order.PositionSide, price decimal.Decimal) (bool, error)
+}
+
+// StopMove is a stop loss moved by the execution agent
+type StopMove struct {
+	Time     time.Time
+	Symbol   string
+	Side     order.PositionSide
+	Strategy string
+	Reason   string
+	Entry    decimal.Decimal // Entry price of the position
+	Mark     decimal.Decimal // Price the move was decided at
+	From     decimal.Decimal // Stop level before the move
+	To       decimal.Decimal // Stop level after the move
+}
+
+// breakEvenState is what the break-even rule knows of an open position
+type breakEvenState struct {
+	risk  decimal.Decimal // Distance from the entry price to the initial stop
+	moved bool
+}
+
+// SetStopMoveCallback sets the callback notified of each stop loss moved
+func (e *ExecutionAgent) SetStopMoveCallback(callback func(StopMove)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onStopMove = callback
+}
+
+// HasBreakEven reports whether stops are moved to break-even
+func (e *ExecutionAgent) HasBreakEven() bool {
+	return e.config.BreakEvenTriggerR > 0
+}
+
+// breakEvenStop returns the level the stop of position moves to once in
+// profit: its entry price, moved BreakEvenBufferPercent further in the
+// direction of profit
+func (c Config) breakEvenStop(position *order.ManagedPosition) decimal.Decimal {
+	buffer := position.EntryPrice.Mul(c.BreakEvenBufferPercent)
+	if position.Side == order.PositionSideShort {
+		return position.EntryPrice.Sub(buffer)
+	}
+	return position.EntryPrice.Add(buffer)
+}
+
+// EnforceBreakEven moves the stop loss of the open positions whose
+// unrealized profit reached BreakEvenTriggerR times their initial risk, the
+// distance from their entry price to the stop they were opened with, to
+// break-even. Each position has its stop moved once.
+func (e *ExecutionAgent) EnforceBreakEven(ctx context.Context) error {
+	mover, ok := e.orderManager.(StopMover)
+	if !ok || !e.HasBreakEven() {
+		return nil
+	}
+
+	trigger := decimal.NewFromFloat(e.config.BreakEvenTriggerR)
+	open := make(map[string]bool)
+	var errs []error
+	for _, position := range e.orderManager.GetPositions() {
+		if position.Status != order.PositionStatusOpen || !position.EntryPrice.IsPositive() {
+			continue
+		}
+		open[position.ID] = true
+
+		e.mu.Lock()
+		if e.breakEven == nil {
+			e.breakEven = make(map[string]*breakEvenState)
+		}
+		state, tracked := e.breakEven[position.ID]
+		if !tracked && !position.StopLoss.IsZero() {
+			state = &breakEvenState{risk: position.EntryPrice.Sub(position.StopLoss).Abs()}
+			e.breakEven[position.ID] = state
+		}
+		e.mu.Unlock()
+		if state == nil || state.moved || !state.risk.IsPositive() {
+			continue
+		}
+
+		profit := position.CurrentPrice.Sub(position.EntryPrice)
+		if position.Side == order.PositionSideShort {
+			profit = profit.Neg()
+		}
+		if profit.LessThan(state.risk.Mul(trigger)) {
+			continue
+		}
+
+		from := position.StopLoss
+		to := e.config.breakEvenStop(position)
+		moved, err := mover.MoveStopLoss(ctx, position.Symbol, position.Side, to)
+		if err != nil {
+			telemetry.RecordError("break_even_failed")
+			errs = append(errs, fmt.Errorf("move stop of %s: %w", position.Symbol, err))
+			continue
+		}
+		e.mu.Lock()
+		state.moved = true
+		callback := e.onStopMove
+		e.mu.Unlock()
+		if !moved {
+			// The stop is already at or past break-even
+			continue
+		}
+
+		logger.Component("execution").Info("stop moved to break-even",
+			"symbol", position.Symbol,
+			"side", position.Side,
+			"from", from.String(),
+			"to", to.String(),
+			"mark", position.CurrentPrice.String())
+		if callback != nil {
+			callback(StopMove{
+				Time:     e.clock(),
+				Symbol:   position.Symbol,
+				Side:     position.Side,
+				Strategy: position.Strategy,
+				Reason:   "break_even",
+				Entry:    position.EntryPrice,
+				Mark:     position.CurrentPrice,
+				From:     from,
+				To:       to,
+			})
+		}
+	}
+
+	// Forget the positions closed since
+	e.mu.Lock()
+	for id := range e.breakEven {
+		if !open[id] {
+			delete(e.breakEven, id)
+		}
+	}
+	e.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// RunBreakEven moves stops to break-even every interval until ctx is done
+func (e *ExecutionAgent) RunBreakEven(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.EnforceBreakEven(ctx); err != nil {
+				logger.Component("execution").Warn("failed to move stops to break-even", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stopMovingOrderManager records the stops moved and applies them to its
+// positions
+type stopMovingOrderManager struct {
+	mockOrderManager
+	positions []*order.ManagedPosition
+	moves     []decimal.Decimal
+	err       error
+}
+
+func (m *stopMovingOrderManager) GetPositions() []*order.ManagedPosition {
+	return m.positions
+}
+
+func (m *stopMovingOrderManager) MoveStopLoss(ctx context.Context, symbol string, side order.PositionSide, price decimal.Decimal) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	m.moves = append(m.moves, price)
+	for _, position := range m.positions {
+		if position.Symbol == symbol && position.Side == side {
+			position.StopLoss = price
+		}
+	}
+	return true, nil
+}
+
+func TestEnforceBreakEven(t *testing.T) {
+	long := &order.ManagedPosition{
+		ID: "pos-1", Symbol: "BTC-USD", Side: order.PositionSideLong, Status: order.PositionStatusOpen, Strategy: "fast",
+		EntryPrice: decimal.NewFromInt(100), StopLoss: decimal.NewFromInt(98), CurrentPrice: decimal.NewFromInt(101),
+	}
+	short := &order.ManagedPosition{
+		ID: "pos-2", Symbol: "ETH-USD", Side: order.PositionSideShort, Status: order.PositionStatusOpen,
+		EntryPrice: decimal.NewFromInt(50), StopLoss: decimal.NewFromInt(51), CurrentPrice: decimal.NewFromFloat(48.5),
+	}
+	orders := &stopMovingOrderManager{positions: []*order.ManagedPosition{long, short}}
+	agent := &ExecutionAgent{
+		orderManager: orders,
+		config:       Config{BreakEvenTriggerR: 1.5, BreakEvenBufferPercent: decimal.NewFromFloat(0.001)},
+	}
+	var moves []StopMove
+	agent.SetStopMoveCallback(func(move StopMove) { moves = append(moves, move) })
+
+	// The long is 0.5R in profit, the short 1.5R
+	require.NoError(t, agent.EnforceBreakEven(context.Background()))
+	require.Len(t, moves, 1)
+	assert.Equal(t, "ETH-USD", moves[0].Symbol)
+	assert.Equal(t, "break_even", moves[0].Reason)
+	assert.True(t, moves[0].From.Equal(decimal.NewFromInt(51)))
+	assert.True(t, moves[0].To.Equal(decimal.NewFromFloat(49.95)), "short stop %s", moves[0].To)
+
+	// The initial risk is kept once the stop moved
+	long.CurrentPrice = decimal.NewFromInt(103)
+	require.NoError(t, agent.EnforceBreakEven(context.Background()))
+	require.Len(t, moves, 2)
+	assert.Equal(t, "BTC-USD", moves[1].Symbol)
+	assert.Equal(t, "fast", moves[1].Strategy)
+	assert.True(t, moves[1].To.Equal(decimal.NewFromFloat(100.1)), "long stop %s", moves[1].To)
+
+	// Each position has its stop moved once
+	require.NoError(t, agent.EnforceBreakEven(context.Background()))
+	assert.Len(t, orders.moves, 2)
+
+	// Closed positions are forgotten
+	orders.positions = nil
+	require.NoError(t, agent.EnforceBreakEven(context.Background()))
+	assert.Empty(t, agent.breakEven)
+}
+
+func TestEnforceBreakEven_RetriesFailedMoves(t *testing.T) {
+	position := &order.ManagedPosition{
+		ID: "pos-1", Symbol: "BTC-USD", Side: order.PositionSideLong, Status: order.PositionStatusOpen,
+		EntryPrice: decimal.NewFromInt(100), StopLoss: decimal.NewFromInt(99), CurrentPrice: decimal.NewFromInt(102),
+	}
+	orders := &stopMovingOrderManager{positions: []*order.ManagedPosition{position}, err: errors.New("exchange down")}
+	agent := &ExecutionAgent{
+		orderManager: orders,
+		config:       Config{BreakEvenTriggerR: 1},
+		now:          func() time.Time { return time.Unix(0, 0) },
+	}
+
+	assert.Error(t, agent.EnforceBreakEven(context.Background()))
+	orders.err = nil
+	require.NoError(t, agent.EnforceBreakEven(context.Background()))
+	require.Len(t, orders.moves, 1)
+	assert.True(t, orders.moves[0].Equal(decimal.NewFromInt(100)))
+}
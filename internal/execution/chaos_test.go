@@ -0,0 +1,120 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/exchanges/chaos"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHandleSignal_ChaosConsistency feeds random entry and exit signals to an
+// agent trading through the real order manager on an exchange injecting
+// failures. Every position the manager holds must match the fills of the
+// orders it knows about, and no order may be placed twice.
+func TestHandleSignal_ChaosConsistency(t *testing.T) {
+	symbols := []string{"BTC-USD", "ETH-USD"}
+	for seed := int64(1); seed <= 3; seed++ {
+		t.Run(fmt.Sprintf("seed-%d", seed), func(t *testing.T) {
+			ctx := context.Background()
+			rng := rand.New(rand.NewSource(seed))
+
+			market := testutils.NewMatchingExchange("chaos", decimal.NewFromInt(100))
+			exchange := chaos.New(market, chaos.Config{
+				Seed:            seed,
+				TimeoutRate:     0.15,
+				ServerErrorRate: 0.1,
+				DuplicateRate:   0.1,
+				OutOfOrderRate:  0.2,
+				StalePriceRate:  0.2,
+			})
+			manager := order.NewManager(exchange)
+			manager.SetSubmitRetryPolicy(2, 0)
+
+			var mu sync.Mutex
+			known := make(map[string]bool)
+			manager.SetOrderUpdateCallback(func(update *order.OrderUpdate) {
+				mu.Lock()
+				defer mu.Unlock()
+				known[update.Order.ID] = true
+			})
+
+			agent := NewExecutionAgent(manager, &mockRiskManager{
+				calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+					return decimal.NewFromFloat(0.1)
+				},
+				getCurrentBalanceFunc: func() decimal.Decimal {
+					return decimal.NewFromInt(10000)
+				},
+			}, Config{
+				AutoExecute: true,
+				// Protective orders rest far from the market and never fill
+				StopLossPercent:   decimal.NewFromFloat(0.5),
+				TakeProfitPercent: decimal.NewFromFloat(0.5),
+				ExitFraction:      decimal.NewFromInt(1),
+				EntryTimeInForce:  exchanges.TimeInForceGTC,
+			})
+
+			for step := 0; step < 200; step++ {
+				symbol := symbols[rng.Intn(len(symbols))]
+				switch op := rng.Intn(10); {
+				case op < 4:
+					_ = agent.HandleSignal(ctx, &strategy.Signal{
+						Type:     strategy.SignalTypeEntry,
+						Side:     exchanges.OrderSideBuy,
+						Symbol:   symbol,
+						Price:    market.Price(),
+						Strength: 1,
+					})
+				case op < 6:
+					_ = agent.HandleSignal(ctx, &strategy.Signal{
+						Type:     strategy.SignalTypeExit,
+						Side:     exchanges.OrderSideSell,
+						Symbol:   symbol,
+						Price:    market.Price(),
+						Strength: 1,
+					})
+				default:
+					move := decimal.NewFromFloat((rng.Float64() - 0.5) / 50)
+					market.SetPrice(market.Price().Mul(decimal.NewFromInt(1).Add(move)).Round(2))
+				}
+			}
+
+			exchange.SetConfig(chaos.Config{})
+
+			clientIDs := make(map[string]bool)
+			expected := make(map[string]decimal.Decimal)
+			mu.Lock()
+			for _, placed := range market.Orders() {
+				require.False(t, clientIDs[placed.ClientOrderID], "client order ID %s placed twice", placed.ClientOrderID)
+				clientIDs[placed.ClientOrderID] = true
+				if !known[placed.ID] {
+					continue
+				}
+				if placed.Side == exchanges.OrderSideBuy {
+					expected[placed.Symbol] = expected[placed.Symbol].Add(placed.Filled)
+				} else {
+					expected[placed.Symbol] = expected[placed.Symbol].Sub(placed.Filled)
+				}
+			}
+			mu.Unlock()
+
+			for _, symbol := range symbols {
+				held := decimal.Zero
+				if position := manager.GetPosition(symbol); position != nil {
+					held = position.Amount
+				}
+				assert.True(t, held.Equal(expected[symbol]), "%s: manager holds %s, known orders filled %s", symbol, held, expected[symbol])
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// chaseEnabled reports whether entries are pegged to the touch
+func (e *ExecutionAgent) chaseEnabled() bool {
+	return e.config.ChaseEntries
+}
+
+// chaseLimit returns the worst price a chased entry may be repriced to:
+// ChaseMaxSlippageBps away from the signal price
+func (e *ExecutionAgent) chaseLimit(signalPrice decimal.Decimal, side exchanges.OrderSide) decimal.Decimal {
+	offset := signalPrice.Mul(decimal.NewFromFloat(e.config.ChaseMaxSlippageBps / 10000))
+	if side == exchanges.OrderSideSell {
+		return signalPrice.Sub(offset)
+	}
+	return signalPrice.Add(offset)
+}
+
+// chasePrice returns the price joining the best bid for buys or the best ask
+// for sells, never worse than limit. Joining the own side of the book keeps
+// the order from crossing the spread.
+func chasePrice(book *exchanges.OrderBook, side exchanges.OrderSide, limit decimal.Decimal) decimal.Decimal {
+	if side == exchanges.OrderSideSell {
+		return decimal.Max(book.Asks[0].Price, limit)
+	}
+	return decimal.Min(book.Bids[0].Price, limit)
+}
+
+// startChase places req at the touch and keeps repricing it as the book moves
+func (e *ExecutionAgent) startChase(ctx context.Context, req *order.OrderRequest, book *exchanges.OrderBook) (*exchanges.Order, error) {
+	limit := e.chaseLimit(req.Price, req.Side)
+
+	peg := *req
+	peg.Price = chasePrice(book, req.Side, limit)
+	peg.PostOnly = true
+	if peg.TimeInForce == exchanges.TimeInForceIOC || peg.TimeInForce == exchanges.TimeInForceFOK {
+		peg.TimeInForce = exchanges.TimeInForceGTC
+	}
+
+	run := e.newAlgoRun(AlgoChase, &peg, 1)
+	runCtx := e.registerAlgo(ctx, run)
+	placed, err := e.placeSlice(ctx, run, &peg, peg.Amount)
+	if err != nil {
+		run.cancel()
+		e.finishAlgo(run, AlgoStatusFailed, err)
+		return nil, err
+	}
+
+	go e.workChase(runCtx, run, &peg, limit, placed)
+	return placed, nil
+}
+
+// workChase reprices the resting order to the touch every ChaseInterval
+// until it leaves the book, the amendments run out or the chase is canceled,
+// which also pulls the resting order. Repricing cancels the order and places
+// what it left unfilled at the new price, once the order manager has its
+// final state.
+func (e *ExecutionAgent) workChase(ctx context.Context, run *algoRun, peg *order.OrderRequest, limit decimal.Decimal, current *exchanges.Order) {
+	defer run.cancel()
+
+	interval := e.config.ChaseInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	canceler, canCancel := e.orderManager.(OrderCanceler)
+	lookup, canLookup := e.orderManager.(OrderLookup)
+	amendments := 0
+	for {
+		select {
+		case <-ctx.Done():
+			if canCancel && e.isOpen(current.ID) {
+				_ = canceler.CancelOrder(context.WithoutCancel(ctx), current.ID)
+			}
+			e.finishAlgo(run, AlgoStatusCanceled, nil)
+			return
+		case <-ticker.C:
+		}
+
+		open := e.openOrder(current.ID)
+		if open == nil || !canCancel || !canLookup || amendments >= e.config.ChaseMaxAmendments {
+			// Filled, canceled elsewhere, or left resting at its last price
+			e.finishAlgo(run, AlgoStatusCompleted, nil)
+			return
+		}
+
+		book, err := e.fetchBook(ctx, peg.Symbol)
+		if err != nil {
+			continue
+		}
+		price := chasePrice(book, peg.Side, limit)
+		if price.Equal(open.Price) {
+			continue
+		}
+
+		if err := canceler.CancelOrder(ctx, current.ID); err != nil {
+			// Most likely filled in the meantime; the next tick will tell
+			continue
+		}
+		// The order may have filled until the cancel went through
+		final, err := e.finalOrderState(ctx, lookup, current.ID, interval)
+		if err != nil {
+			if ctx.Err() != nil {
+				e.finishAlgo(run, AlgoStatusCanceled, nil)
+			} else {
+				e.finishAlgo(run, AlgoStatusFailed, err)
+			}
+			return
+		}
+		remaining := final.Amount.Sub(decimal.Max(final.Filled, final.FilledAmount))
+		if final.Status == exchanges.OrderStatusFilled {
+			remaining = decimal.Zero
+		}
+		if !remaining.IsPositive() {
+			e.finishAlgo(run, AlgoStatusCompleted, nil)
+			return
+		}
+		e.mu.Lock()
+		run.order.Submitted = run.order.Submitted.Sub(remaining)
+		e.mu.Unlock()
+
+		next := *peg
+		next.Price = price
+		placed, err := e.placeSlice(ctx, run, &next, remaining)
+		if err != nil {
+			e.finishAlgo(run, AlgoStatusFailed, err)
+			return
+		}
+		current = placed
+		amendments++
+	}
+}
+
+// finalOrderState waits, polling every interval, until the order with
+// orderID leaves the open orders and returns its final state
+func (e *ExecutionAgent) finalOrderState(ctx context.Context, lookup OrderLookup, orderID string, interval time.Duration) (*exchanges.Order, error) {
+	for e.isOpen(orderID) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+	final := lookup.GetOrder(orderID)
+	if final == nil {
+		return nil, fmt.Errorf("order %s is no longer tracked", orderID)
+	}
+	return final, nil
+}
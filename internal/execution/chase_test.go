@@ -0,0 +1,145 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chaseTestBook(bid, ask float64) *exchanges.OrderBook {
+	return &exchanges.OrderBook{
+		Symbol: "BTC-USD",
+		Bids:   []exchanges.Level{{Price: decimal.NewFromFloat(bid), Amount: decimal.NewFromInt(100)}},
+		Asks:   []exchanges.Level{{Price: decimal.NewFromFloat(ask), Amount: decimal.NewFromInt(100)}},
+	}
+}
+
+func TestChasePrice(t *testing.T) {
+	book := chaseTestBook(99.9, 100.1)
+
+	assert.True(t, decimal.NewFromFloat(99.9).Equal(chasePrice(book, exchanges.OrderSideBuy, decimal.NewFromInt(101))))
+	assert.True(t, decimal.NewFromFloat(99.5).Equal(chasePrice(book, exchanges.OrderSideBuy, decimal.NewFromFloat(99.5))))
+	assert.True(t, decimal.NewFromFloat(100.1).Equal(chasePrice(book, exchanges.OrderSideSell, decimal.NewFromInt(99))))
+	assert.True(t, decimal.NewFromFloat(100.5).Equal(chasePrice(book, exchanges.OrderSideSell, decimal.NewFromFloat(100.5))))
+}
+
+func TestChaseLimit(t *testing.T) {
+	agent := &ExecutionAgent{config: Config{ChaseMaxSlippageBps: 10}}
+
+	assert.True(t, decimal.NewFromFloat(100.1).Equal(agent.chaseLimit(decimal.NewFromInt(100), exchanges.OrderSideBuy)))
+	assert.True(t, decimal.NewFromFloat(99.9).Equal(agent.chaseLimit(decimal.NewFromInt(100), exchanges.OrderSideSell)))
+}
+
+// newChaseAgent returns an agent chasing entries against a book whose bid
+// the test moves
+func newChaseAgent(orders *algoOrderManager, maxAmendments int) (*ExecutionAgent, func(bid float64)) {
+	var mu sync.Mutex
+	bid := 99.9
+
+	agent := &ExecutionAgent{
+		orderManager: orders,
+		riskManager: &mockRiskManager{
+			calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+				return decimal.NewFromInt(1)
+			},
+		},
+		config: Config{
+			AutoExecute:         true,
+			StopLossPercent:     decimal.NewFromFloat(0.01),
+			ChaseEntries:        true,
+			ChaseMaxAmendments:  maxAmendments,
+			ChaseMaxSlippageBps: 20,
+			ChaseInterval:       time.Millisecond,
+		},
+	}
+	agent.SetOrderBookSource(func(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return chaseTestBook(bid, bid+0.2), nil
+	})
+	return agent, func(price float64) {
+		mu.Lock()
+		defer mu.Unlock()
+		bid = price
+	}
+}
+
+func TestHandleSignal_ChaseRepricesToTouch(t *testing.T) {
+	orders := newAlgoOrderManager()
+	agent, moveBid := newChaseAgent(orders, 5)
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+	require.Len(t, orders.placedAmounts(), 1)
+
+	moveBid(100.05)
+	require.Eventually(t, func() bool { return len(orders.placedAmounts()) >= 2 }, time.Second, time.Millisecond)
+
+	orders.mu.Lock()
+	assert.Equal(t, []string{"child-1"}, orders.canceled)
+	orders.mu.Unlock()
+
+	// The bid moving past the slippage limit pegs the order at the limit
+	moveBid(101)
+	require.Eventually(t, func() bool {
+		for _, open := range orders.GetOpenOrders() {
+			if open.Price.Equal(decimal.NewFromFloat(100.2)) {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+
+	for _, open := range orders.GetOpenOrders() {
+		orders.fill(open.ID)
+	}
+	progress := waitForAlgo(t, agent, AlgoStatusCompleted)
+	assert.Equal(t, AlgoChase, progress.Type)
+	assert.True(t, decimal.NewFromInt(1).Equal(progress.Submitted))
+}
+
+func TestHandleSignal_ChaseStopsAfterMaxAmendments(t *testing.T) {
+	orders := newAlgoOrderManager()
+	agent, moveBid := newChaseAgent(orders, 1)
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+	moveBid(100)
+	waitForAlgo(t, agent, AlgoStatusCompleted)
+	moveBid(100.1)
+	time.Sleep(10 * time.Millisecond)
+
+	assert.Len(t, orders.placedAmounts(), 2)
+	assert.Len(t, orders.GetOpenOrders(), 1, "the last order is left resting")
+}
+
+func TestHandleSignal_ChaseReplacesOnlyWhatTheCancelLeft(t *testing.T) {
+	orders := newAlgoOrderManager()
+	orders.fillOnCancel = decimal.NewFromFloat(0.4)
+	agent, moveBid := newChaseAgent(orders, 1)
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+	moveBid(100)
+	progress := waitForAlgo(t, agent, AlgoStatusCompleted)
+
+	placed := orders.placedAmounts()
+	require.Len(t, placed, 2)
+	assert.True(t, decimal.NewFromFloat(0.6).Equal(placed[1]), "the fill racing the cancel must not be placed again, got %s", placed[1])
+	assert.True(t, decimal.NewFromInt(1).Equal(progress.Submitted))
+}
+
+func TestHandleSignal_ChaseRecordsFailedPlacement(t *testing.T) {
+	orders := newAlgoOrderManager()
+	orders.placeErr = errors.New("exchange down")
+	agent, _ := newChaseAgent(orders, 1)
+
+	assert.Error(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+	algos := agent.AlgoOrders()
+	require.Len(t, algos, 1)
+	assert.Equal(t, AlgoStatusFailed, algos[0].Status)
+}
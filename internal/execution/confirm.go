@@ -0,0 +1,247 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultConfirmTimeout is how long a proposed entry waits for approval
+const DefaultConfirmTimeout = time.Minute
+
+var (
+	// ErrProposalRejected is returned for an entry an operator rejected
+	ErrProposalRejected = errors.New("entry rejected by operator")
+	// ErrProposalExpired is returned for an entry not approved in time
+	ErrProposalExpired = errors.New("entry not approved in time")
+	// ErrProposalPending is returned for an entry on a symbol and side that
+	// already awaits approval
+	ErrProposalPending = errors.New("entry already awaiting approval")
+)
+
+// Proposal is an entry awaiting approval
+type Proposal struct {
+	ID         string              `json:"id"`
+	Symbol     string              `json:"symbol"`
+	Side       exchanges.OrderSide `json:"side"`
+	Price      decimal.Decimal     `json:"price"`
+	Amount     decimal.Decimal     `json:"amount"`
+	StopLoss   decimal.Decimal     `json:"stop_loss"`
+	TakeProfit decimal.Decimal     `json:"take_profit"`
+	Strategy   string              `json:"strategy,omitempty"`
+	Strength   float64             `json:"strength"`
+	Reason     string              `json:"reason,omitempty"`
+	ProposedAt time.Time           `json:"proposed_at"`
+	ExpiresAt  time.Time           `json:"expires_at"`
+}
+
+// Approver decides whether a validated entry may be placed. Propose queues
+// the entry and returns its proposal ID without waiting: decided is called
+// once, from another goroutine, with nil when the entry is approved or the
+// reason it was refused.
+type Approver interface {
+	Propose(proposal Proposal, decided func(error)) (string, error)
+}
+
+// SetApprover requires approval from approver before each entry is placed
+func (e *ExecutionAgent) SetApprover(approver Approver) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.approver = approver
+}
+
+// proposeEntry proposes req to the approver, if any, and reports whether it
+// did. The signal's decision is then proposed, and place is called to place
+// the entry once approved; the decision made on the entry is recorded once
+// the operator decides it or the proposal expires.
+func (e *ExecutionAgent) proposeEntry(ctx context.Context, signal *strategy.Signal, req *order.OrderRequest, decision *Decision, place func(context.Context, *Decision) (*exchanges.Order, error)) (bool, error) {
+	e.mu.RLock()
+	approver := e.approver
+	e.mu.RUnlock()
+	if approver == nil {
+		return false, nil
+	}
+
+	final := *decision
+	id, err := approver.Propose(Proposal{
+		Symbol:     req.Symbol,
+		Side:       req.Side,
+		Price:      req.Price,
+		Amount:     req.Amount,
+		StopLoss:   req.StopLoss,
+		TakeProfit: req.TakeProfit,
+		Strategy:   req.Strategy,
+		Strength:   signal.Strength,
+		Reason:     signal.Reason,
+		ProposedAt: e.clock(),
+	}, func(err error) {
+		final.Time = e.clock()
+		var placed *exchanges.Order
+		switch {
+		case err != nil:
+			err = &ExecutionError{
+				Type:    ExecutionErrorTypeNotApproved,
+				Message: err.Error(),
+				Err:     err,
+			}
+		case ctx.Err() != nil:
+			err = ctx.Err()
+		default:
+			placed, err = place(ctx, &final)
+		}
+		final.complete(placed, err)
+		e.recordDecision(&final)
+		if err != nil {
+			logger.Component("execution").Warn("proposed entry not placed",
+				"symbol", req.Symbol,
+				"side", req.Side,
+				"error", err)
+		}
+	})
+	if err != nil {
+		return true, &ExecutionError{
+			Type:    ExecutionErrorTypeNotApproved,
+			Message: err.Error(),
+			Err:     err,
+		}
+	}
+	decision.propose("awaiting approval of proposal " + id)
+	return true, nil
+}
+
+type pendingProposal struct {
+	proposal Proposal
+	seq      int
+	decided  func(error)
+	expiry   *time.Timer
+}
+
+// ConfirmationQueue holds proposed entries until an operator approves or
+// rejects them, or they expire. Only one entry per symbol and side is
+// proposed at a time.
+type ConfirmationQueue struct {
+	timeout time.Duration
+
+	mu         sync.Mutex
+	pending    map[string]*pendingProposal
+	seq        int
+	onProposal func(Proposal)
+	now        func() time.Time
+}
+
+// NewConfirmationQueue creates a queue whose proposals expire after
+// timeout, DefaultConfirmTimeout when zero
+func NewConfirmationQueue(timeout time.Duration) *ConfirmationQueue {
+	if timeout <= 0 {
+		timeout = DefaultConfirmTimeout
+	}
+	return &ConfirmationQueue{
+		timeout: timeout,
+		pending: make(map[string]*pendingProposal),
+		now:     time.Now,
+	}
+}
+
+// SetProposalCallback sets the callback notified of every new proposal
+func (q *ConfirmationQueue) SetProposalCallback(callback func(Proposal)) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.onProposal = callback
+}
+
+// Propose queues an entry until it is approved, rejected or expires, and
+// returns its ID. decided is called once, in its own goroutine, with the
+// outcome.
+func (q *ConfirmationQueue) Propose(proposal Proposal, decided func(error)) (string, error) {
+	q.mu.Lock()
+	for _, p := range q.pending {
+		if p.proposal.Symbol == proposal.Symbol && p.proposal.Side == proposal.Side {
+			q.mu.Unlock()
+			return "", fmt.Errorf("%w: %s %s", ErrProposalPending, proposal.Side, proposal.Symbol)
+		}
+	}
+	q.seq++
+	proposal.ID = strconv.Itoa(q.seq)
+	if proposal.ProposedAt.IsZero() {
+		proposal.ProposedAt = q.now()
+	}
+	proposal.ExpiresAt = proposal.ProposedAt.Add(q.timeout)
+	q.pending[proposal.ID] = &pendingProposal{
+		proposal: proposal,
+		seq:      q.seq,
+		decided:  decided,
+		expiry: time.AfterFunc(q.timeout, func() {
+			q.settle(proposal.ID, ErrProposalExpired)
+		}),
+	}
+	callback := q.onProposal
+	q.mu.Unlock()
+
+	if callback != nil {
+		callback(proposal)
+	}
+	return proposal.ID, nil
+}
+
+// Approve approves the proposal with id
+func (q *ConfirmationQueue) Approve(id string) error {
+	if !q.settle(id, nil) {
+		return fmt.Errorf("no pending proposal %s", id)
+	}
+	return nil
+}
+
+// Reject rejects the proposal with id
+func (q *ConfirmationQueue) Reject(id string) error {
+	if !q.settle(id, ErrProposalRejected) {
+		return fmt.Errorf("no pending proposal %s", id)
+	}
+	return nil
+}
+
+// settle removes the proposal with id and reports err as its outcome,
+// returning false when it is no longer pending
+func (q *ConfirmationQueue) settle(id string, err error) bool {
+	q.mu.Lock()
+	pending, ok := q.pending[id]
+	if ok {
+		delete(q.pending, id)
+		pending.expiry.Stop()
+	}
+	q.mu.Unlock()
+	if !ok {
+		return false
+	}
+	if pending.decided != nil {
+		go pending.decided(err)
+	}
+	return true
+}
+
+// Pending returns the proposals awaiting approval, oldest first
+func (q *ConfirmationQueue) Pending() []Proposal {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := make([]*pendingProposal, 0, len(q.pending))
+	for _, p := range q.pending {
+		pending = append(pending, p)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].seq < pending[j].seq })
+
+	proposals := make([]Proposal, len(pending))
+	for i, p := range pending {
+		proposals[i] = p.proposal
+	}
+	return proposals
+}
@@ -0,0 +1,135 @@
+package execution
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForProposal returns the first pending proposal once it is queued
+func waitForProposal(t *testing.T, q *ConfirmationQueue) Proposal {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if pending := q.Pending(); len(pending) > 0 {
+			return pending[0]
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("no proposal queued")
+	return Proposal{}
+}
+
+// waitForDecision returns the outcome reported to decided
+func waitForDecision(t *testing.T, decided <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-decided:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("proposal was not decided")
+		return nil
+	}
+}
+
+func TestConfirmationQueue_ApproveAndReject(t *testing.T) {
+	q := NewConfirmationQueue(time.Second)
+	var notified []Proposal
+	q.SetProposalCallback(func(p Proposal) { notified = append(notified, p) })
+
+	proposal := Proposal{Symbol: "BTC-USD", Side: exchanges.OrderSideBuy, Amount: decimal.NewFromFloat(0.1)}
+	decided := make(chan error, 1)
+
+	id, err := q.Propose(proposal, func(err error) { decided <- err })
+	require.NoError(t, err)
+	pending := waitForProposal(t, q)
+	assert.Equal(t, id, pending.ID)
+	assert.False(t, pending.ExpiresAt.IsZero())
+
+	// A second entry on the same symbol and side is refused while pending
+	_, err = q.Propose(proposal, func(error) {})
+	assert.ErrorIs(t, err, ErrProposalPending)
+
+	require.NoError(t, q.Approve(pending.ID))
+	assert.NoError(t, waitForDecision(t, decided))
+	assert.Error(t, q.Approve(pending.ID), "a decided proposal is no longer pending")
+	assert.Empty(t, q.Pending())
+
+	_, err = q.Propose(proposal, func(err error) { decided <- err })
+	require.NoError(t, err)
+	require.NoError(t, q.Reject(waitForProposal(t, q).ID))
+	assert.ErrorIs(t, waitForDecision(t, decided), ErrProposalRejected)
+	assert.Len(t, notified, 2)
+}
+
+func TestConfirmationQueue_Expires(t *testing.T) {
+	q := NewConfirmationQueue(10 * time.Millisecond)
+	decided := make(chan error, 1)
+	_, err := q.Propose(Proposal{Symbol: "BTC-USD", Side: exchanges.OrderSideBuy}, func(err error) { decided <- err })
+	require.NoError(t, err)
+	assert.ErrorIs(t, waitForDecision(t, decided), ErrProposalExpired)
+	assert.Empty(t, q.Pending())
+}
+
+func TestHandleSignal_EntryAwaitsApproval(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		placed []*order.OrderRequest
+	)
+	agent := newScalingAgent(nil, &placed, Config{})
+	placeOrder := agent.orderManager.(*mockOrderManager).placeOrderFunc
+	agent.orderManager.(*mockOrderManager).placeOrderFunc = func(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return placeOrder(ctx, req)
+	}
+	placedCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(placed)
+	}
+	decisions := make(chan Decision, 4)
+	agent.SetDecisionCallback(func(d Decision) { decisions <- d })
+	q := NewConfirmationQueue(time.Second)
+	agent.SetApprover(q)
+
+	signal := &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Symbol:   "BTC-USD",
+		Strength: 1,
+	}
+
+	// The signal is handled without waiting for the operator
+	require.NoError(t, agent.HandleSignal(context.Background(), signal))
+	assert.Equal(t, DecisionProposed, (<-decisions).Outcome)
+	pending := waitForProposal(t, q)
+	assert.Equal(t, "BTC-USD", pending.Symbol)
+	assert.True(t, pending.Amount.Equal(decimal.NewFromInt(1)))
+	assert.Equal(t, 0, placedCount(), "entries must not be placed before approval")
+
+	// Exits are handled while the entry awaits approval
+	require.NoError(t, agent.HandleSignal(context.Background(), &strategy.Signal{Type: strategy.SignalTypeExit, Symbol: "ETH-USD", Strength: 1}))
+	<-decisions
+
+	require.NoError(t, q.Reject(pending.ID))
+	rejected := <-decisions
+	assert.Equal(t, DecisionRejected, rejected.Outcome)
+	assert.Equal(t, ExecutionErrorTypeNotApproved.String(), rejected.Error)
+	assert.Equal(t, 0, placedCount())
+
+	require.NoError(t, agent.HandleSignal(context.Background(), signal))
+	<-decisions
+	require.NoError(t, q.Approve(waitForProposal(t, q).ID))
+	assert.Equal(t, DecisionExecuted, (<-decisions).Outcome)
+	assert.Equal(t, 1, placedCount())
+}
@@ -0,0 +1,131 @@
+package execution
+
+import (
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/ringbuf"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// CostPolicy is what is done with entries whose take profit does not clear
+// their expected round-trip cost
+type CostPolicy string
+
+const (
+	CostPolicyNone   CostPolicy = "none"
+	CostPolicyWiden  CostPolicy = "widen"  // Move the take profit out to cover the costs
+	CostPolicyReject CostPolicy = "reject" // Skip the entry
+)
+
+// fillCost is what a recent fill on a symbol cost
+type fillCost struct {
+	slippageBps float64
+	measured    bool // Whether the fill had a reference price to measure slippage against
+	maker       bool
+}
+
+// RecordExecution records the cost of a fill, which the expected round-trip
+// cost of the next entries on its symbol is estimated from
+func (e *ExecutionAgent) RecordExecution(execution order.Execution) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.fillCosts == nil {
+		e.fillCosts = make(map[string]*ringbuf.Buffer[fillCost])
+	}
+	fills, ok := e.fillCosts[execution.Symbol]
+	if !ok {
+		fills = ringbuf.New[fillCost](max(e.config.CostWindow, 1))
+		e.fillCosts[execution.Symbol] = fills
+	}
+	fills.Push(fillCost{
+		slippageBps: execution.SlippageBps.InexactFloat64(),
+		measured:    execution.Measured(),
+		maker:       execution.Liquidity == order.LiquidityMaker,
+	})
+}
+
+// RoundTripCostBps returns the expected cost of entering and exiting symbol,
+// in basis points: the fees of both fills at the maker share of its recent
+// fills, their mean slippage both ways and, when book is given, the spread.
+// Symbols without fills yet are assumed to take liquidity without slippage.
+func (e *ExecutionAgent) RoundTripCostBps(symbol string, book *exchanges.OrderBook) float64 {
+	e.mu.RLock()
+	var fills []fillCost
+	if buffer, ok := e.fillCosts[symbol]; ok {
+		fills = buffer.Values()
+	}
+	e.mu.RUnlock()
+
+	makers, measured := 0, 0
+	slippage := 0.0
+	for _, fill := range fills {
+		if fill.maker {
+			makers++
+		}
+		if fill.measured {
+			slippage += fill.slippageBps
+			measured++
+		}
+	}
+
+	makerShare := 0.0
+	if len(fills) > 0 {
+		makerShare = float64(makers) / float64(len(fills))
+	}
+	fee := makerShare*e.config.MakerFeeBps + (1-makerShare)*e.config.TakerFeeBps
+	cost := 2 * fee
+	// Fills better than their reference do not make the trade cheaper
+	if measured > 0 && slippage > 0 {
+		cost += 2 * slippage / float64(measured)
+	}
+	if book != nil && len(book.Bids) > 0 && len(book.Asks) > 0 {
+		cost += spreadBps(book)
+	}
+	return cost
+}
+
+// applyCostPolicy checks that the take profit of req clears its expected
+// round-trip cost by MinNetProfitBps, widening it up to CostMaxWidenFactor
+// times its distance under the widen policy. It returns the reason the entry
+// is skipped, if it is.
+func (e *ExecutionAgent) applyCostPolicy(req *order.OrderRequest, book *exchanges.OrderBook, decision *Decision) (string, bool) {
+	policy := e.config.CostPolicy
+	if policy != CostPolicyWiden && policy != CostPolicyReject {
+		return "", false
+	}
+	if req.TakeProfit.IsZero() || !req.Price.IsPositive() {
+		return "", false
+	}
+
+	cost := e.RoundTripCostBps(req.Symbol, book)
+	decision.CostBps = cost
+	target := req.TakeProfit.Sub(req.Price).Abs().Div(req.Price).InexactFloat64() * 10000
+	required := cost + e.config.MinNetProfitBps
+	if target > required {
+		return "", false
+	}
+
+	if policy == CostPolicyWiden && required <= target*e.config.CostMaxWidenFactor {
+		distance := req.Price.Mul(decimal.NewFromFloat(required / 10000))
+		if req.Side == exchanges.OrderSideBuy {
+			req.TakeProfit = req.Price.Add(distance)
+		} else {
+			req.TakeProfit = req.Price.Sub(distance)
+		}
+		widenLadder(req)
+		logger.Component("execution").Info("take profit widened to cover costs",
+			"symbol", req.Symbol,
+			"cost_bps", cost,
+			"target_bps", target,
+			"take_profit", req.TakeProfit.String())
+		return "", false
+	}
+
+	telemetry.RecordSignalBlocked(req.Symbol, "costs")
+	return fmt.Sprintf("take profit %.1f bps does not clear %.1f bps of costs", target, cost), true
+}
@@ -0,0 +1,80 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutionAgent_RoundTripCostBps(t *testing.T) {
+	agent := &ExecutionAgent{config: Config{MakerFeeBps: 2, TakerFeeBps: 5, CostWindow: 3}}
+
+	// Taking liquidity both ways without fills to measure
+	assert.InDelta(t, 10, agent.RoundTripCostBps("BTC-USD", nil), 1e-9)
+
+	// The oldest fill falls out of the window: one maker fill out of three,
+	// 4 bps of mean slippage on the measured ones
+	for _, execution := range []order.Execution{
+		{Symbol: "BTC-USD", Liquidity: order.LiquidityMaker},
+		{Symbol: "BTC-USD", Liquidity: order.LiquidityMaker},
+		{Symbol: "BTC-USD", Liquidity: order.LiquidityTaker, ReferencePrice: decimal.NewFromInt(100), FillPrice: decimal.NewFromFloat(100.06), SlippageBps: decimal.NewFromInt(6)},
+		{Symbol: "BTC-USD", Liquidity: order.LiquidityTaker, ReferencePrice: decimal.NewFromInt(100), FillPrice: decimal.NewFromFloat(100.02), SlippageBps: decimal.NewFromInt(2)},
+	} {
+		agent.RecordExecution(execution)
+	}
+	book := &exchanges.OrderBook{
+		Bids: []exchanges.Level{{Price: decimal.NewFromFloat(99.95), Amount: decimal.NewFromInt(1)}},
+		Asks: []exchanges.Level{{Price: decimal.NewFromFloat(100.05), Amount: decimal.NewFromInt(1)}},
+	}
+	// 2 x 4 bps of fees, 2 x 4 bps of slippage and a 10 bps spread
+	assert.InDelta(t, 26, agent.RoundTripCostBps("BTC-USD", book), 1e-9)
+}
+
+func TestHandleSignal_CostPolicy(t *testing.T) {
+	entry := &strategy.Signal{Type: strategy.SignalTypeEntry, Side: exchanges.OrderSideSell, Price: decimal.NewFromInt(100), Symbol: "DOGE-USD", Strength: 1}
+	slippage := order.Execution{Symbol: "DOGE-USD", Liquidity: order.LiquidityTaker, ReferencePrice: decimal.NewFromInt(100), FillPrice: decimal.NewFromFloat(99.3), SlippageBps: decimal.NewFromInt(70)}
+
+	tests := []struct {
+		name       string
+		policy     CostPolicy
+		minNetBps  float64
+		takeProfit float64 // Expected take profit, 0 when the entry is skipped
+	}{
+		// The 2% take profit clears 2 x 5 bps of fees and 2 x 70 of slippage
+		{"covered", CostPolicyReject, 0, 98},
+		// 150 bps of costs and 100 of net profit need 2.5%
+		{"widened", CostPolicyWiden, 100, 97.5},
+		{"rejected", CostPolicyReject, 100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var placed []*order.OrderRequest
+			agent := newScalingAgent(nil, &placed, Config{
+				CostPolicy:         tt.policy,
+				TakerFeeBps:        5,
+				CostWindow:         10,
+				CostMaxWidenFactor: 2,
+				MinNetProfitBps:    tt.minNetBps,
+			})
+			var decisions []Decision
+			agent.SetDecisionCallback(func(decision Decision) { decisions = append(decisions, decision) })
+			agent.RecordExecution(slippage)
+
+			require.NoError(t, agent.HandleSignal(context.Background(), entry))
+			assert.InDelta(t, 150, decisions[0].CostBps, 1e-9)
+			if tt.takeProfit == 0 {
+				assert.Empty(t, placed)
+				assert.Equal(t, DecisionSkipped, decisions[0].Outcome)
+				return
+			}
+			require.Len(t, placed, 1)
+			assert.True(t, placed[0].TakeProfit.Equal(decimal.NewFromFloat(tt.takeProfit)), "take profit %s", placed[0].TakeProfit)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package execution
+
+import (
+	"errors"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+)
+
+// DecisionOutcome is what the execution agent did with a signal
+type DecisionOutcome string
+
+const (
+	DecisionExecuted  DecisionOutcome = "executed"
+	DecisionSizedDown DecisionOutcome = "sized_down" // Executed smaller than the risk-based size
+	DecisionRejected  DecisionOutcome = "rejected"   // A check or the exchange refused it
+	DecisionSkipped   DecisionOutcome = "skipped"    // Ignored by design, e.g. a weak signal
+	DecisionProposed  DecisionOutcome = "proposed"   // Awaiting operator approval, decided in a later decision
+)
+
+// Decision records how a signal was handled and why
+type Decision struct {
+	Time     time.Time           `json:"time"`
+	Symbol   string              `json:"symbol"`
+	Type     strategy.SignalType `json:"type"`
+	Side     exchanges.OrderSide `json:"side"`
+	Strategy string              `json:"strategy,omitempty"`
+	Price    decimal.Decimal     `json:"price"`
+	Strength float64             `json:"strength"`
+
+	Outcome DecisionOutcome `json:"outcome"`
+	Reason  string          `json:"reason,omitempty"`
+	// Error is the type of the execution error of a rejected signal
+	Error string `json:"error,omitempty"`
+
+	// RequestedAmount is the risk-based size, Amount the size ordered
+	RequestedAmount decimal.Decimal `json:"requested_amount"`
+	Amount          decimal.Decimal `json:"amount"`
+	OrderID         string          `json:"order_id,omitempty"`
+	// SizeMultiplier is the share of the risk-based size the strength of the
+	// signal was worth
+	SizeMultiplier float64 `json:"size_multiplier,omitempty"`
+	// CostBps is the expected round-trip cost the take profit was checked
+	// against, in basis points
+	CostBps float64 `json:"cost_bps,omitempty"`
+}
+
+// newDecision starts the decision for signal
+func newDecision(signal *strategy.Signal, at time.Time) *Decision {
+	return &Decision{
+		Time:     at,
+		Symbol:   signal.Symbol,
+		Type:     signal.Type,
+		Side:     signal.Side,
+		Strategy: signal.Strategy,
+		Price:    signal.Price,
+		Strength: signal.Strength,
+	}
+}
+
+// skip marks the signal as ignored for reason
+func (d *Decision) skip(reason string) {
+	d.Outcome = DecisionSkipped
+	d.Reason = reason
+}
+
+// propose marks the signal as awaiting approval for reason
+func (d *Decision) propose(reason string) {
+	d.Outcome = DecisionProposed
+	d.Reason = reason
+}
+
+// sized records the risk-based size and the size ordered. reason explains
+// why the order is smaller.
+func (d *Decision) sized(requested, amount decimal.Decimal, reason string) {
+	d.RequestedAmount = requested
+	d.Amount = amount
+	if amount.LessThan(requested) && d.Reason == "" {
+		d.Reason = reason
+	}
+}
+
+// complete sets the outcome from the error the signal was handled with
+func (d *Decision) complete(placed *exchanges.Order, err error) {
+	if err != nil {
+		d.Outcome = DecisionRejected
+		d.Reason = err.Error()
+		var execErr *ExecutionError
+		if errors.As(err, &execErr) {
+			d.Error = execErr.Type.String()
+		}
+		return
+	}
+	if d.Outcome != "" {
+		return
+	}
+	if placed != nil {
+		d.OrderID = placed.ID
+	}
+	d.Outcome = DecisionExecuted
+	if d.Amount.IsPositive() && d.Amount.LessThan(d.RequestedAmount) {
+		d.Outcome = DecisionSizedDown
+	}
+}
+
+// SetDecisionCallback sets the callback invoked with the decision made on
+// every signal handled, executed or not
+func (e *ExecutionAgent) SetDecisionCallback(callback func(Decision)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onDecision = callback
+}
+
+// recordDecision reports decision to the decision callback
+func (e *ExecutionAgent) recordDecision(decision *Decision) {
+	e.mu.RLock()
+	onDecision := e.onDecision
+	e.mu.RUnlock()
+	if onDecision != nil {
+		onDecision(*decision)
+	}
+}
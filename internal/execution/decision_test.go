@@ -0,0 +1,75 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSignal_RecordsDecisions(t *testing.T) {
+	var placed []*order.OrderRequest
+	positions := []*order.ManagedPosition{
+		{Symbol: "BTC-USD", Side: order.PositionSideLong, Status: order.PositionStatusOpen, EntryPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1)},
+	}
+	agent := newScalingAgent(positions, &placed, Config{
+		MinSignalStrength:     0.3,
+		MaxAddOns:             1,
+		AddOnSizeFactor:       decimal.NewFromFloat(0.5),
+		AddOnMinProfitPercent: decimal.NewFromFloat(0.05),
+	})
+	risk := agent.riskManager.(*mockRiskManager)
+
+	var decisions []Decision
+	agent.SetDecisionCallback(func(decision Decision) {
+		decisions = append(decisions, decision)
+	})
+
+	entry := func(symbol string, price int64, strength float64) *strategy.Signal {
+		return &strategy.Signal{
+			Type:     strategy.SignalTypeEntry,
+			Side:     exchanges.OrderSideBuy,
+			Price:    decimal.NewFromInt(price),
+			Symbol:   symbol,
+			Strategy: "main",
+			Strength: strength,
+		}
+	}
+
+	// Weak signal
+	require.NoError(t, agent.HandleSignal(context.Background(), entry("ETH-USD", 100, 0.1)))
+	// New position at the risk-based size
+	require.NoError(t, agent.HandleSignal(context.Background(), entry("ETH-USD", 100, 0.8)))
+	// Add-on scaled down from the risk-based size
+	require.NoError(t, agent.HandleSignal(context.Background(), entry("BTC-USD", 110, 0.8)))
+	// Refused by the risk manager
+	risk.validateOrderFunc = func(req *order.OrderRequest, openPositions []*order.ManagedPosition) error {
+		return errors.New("max open positions reached")
+	}
+	require.Error(t, agent.HandleSignal(context.Background(), entry("SOL-USD", 100, 0.8)))
+
+	require.Len(t, decisions, 4)
+
+	assert.Equal(t, DecisionSkipped, decisions[0].Outcome)
+	assert.Contains(t, decisions[0].Reason, "below minimum")
+
+	assert.Equal(t, DecisionExecuted, decisions[1].Outcome)
+	assert.Equal(t, "order", decisions[1].OrderID)
+	assert.True(t, decisions[1].Amount.Equal(decimal.NewFromInt(1)))
+	assert.Equal(t, "main", decisions[1].Strategy)
+
+	assert.Equal(t, DecisionSizedDown, decisions[2].Outcome)
+	assert.True(t, decisions[2].RequestedAmount.Equal(decimal.NewFromInt(1)))
+	assert.True(t, decisions[2].Amount.Equal(decimal.NewFromFloat(0.5)))
+	assert.Equal(t, "add-on scaled down", decisions[2].Reason)
+
+	assert.Equal(t, DecisionRejected, decisions[3].Outcome)
+	assert.Equal(t, "max open positions reached", decisions[3].Reason)
+	assert.Equal(t, "risk_validation_failed", decisions[3].Error)
+}
@@ -2,18 +2,29 @@ package execution
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/ringbuf"
 	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/telemetry"
 	"github.com/shopspring/decimal"
 )
 
 // OrderManager defines the minimal behavior required from an order manager.
 type OrderManager interface {
 	GetPositions() []*order.ManagedPosition
+	GetOpenOrders() []*exchanges.Order
 	PlaceOrder(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error)
 	ClosePosition(ctx context.Context, symbol string) error
+	ReducePosition(ctx context.Context, symbol string, fraction decimal.Decimal) (*exchanges.Order, error)
 }
 
 // RiskManager defines the minimal behavior required from a risk manager.
@@ -24,11 +35,67 @@ type RiskManager interface {
 	GetCurrentBalance() decimal.Decimal
 }
 
+// EventPolicy is implemented by risk managers that restrict entries around
+// scheduled economic or news events
+type EventPolicy interface {
+	ApplyEventPolicy(req *order.OrderRequest) error
+}
+
+// EntryRecorder is implemented by risk managers that limit how often a symbol
+// is entered
+type EntryRecorder interface {
+	RecordEntry(symbol string, at time.Time)
+}
+
+// ExitPriceConfirmer is implemented by order managers that check the price
+// triggering an exit against another venue
+type ExitPriceConfirmer interface {
+	ConfirmExitPrice(ctx context.Context, symbol string, price decimal.Decimal) error
+}
+
+// CapitalAllocator splits the account balance across strategy instances
+type CapitalAllocator interface {
+	Capital(strategy string) decimal.Decimal
+	Owner(symbol string) (string, bool)
+	AssignPosition(symbol, strategy string)
+}
+
+// PerformanceGuard throttles or disables the entries of strategies whose
+// live performance degrades
+type PerformanceGuard interface {
+	// SizeFactor returns the factor the entries of strategy on symbol are
+	// sized by, 0 when they are disabled, and the reason when below 1
+	SizeFactor(strategy, symbol string) (float64, string)
+}
+
 // ExecutionAgent handles automated order placement based on trading signals
 type ExecutionAgent struct {
 	orderManager OrderManager
 	riskManager  RiskManager
 	config       Config
+	throttle     *OrderThrottle
+
+	mu              sync.RWMutex
+	resolveExchange func(symbol string) string
+	addOns          map[string]int // Add-ons made to the open position per symbol and side
+	onEntry         func(signal *strategy.Signal, placed *exchanges.Order, decision *Decision)
+	onDecision      func(Decision)
+	onStopMove      func(StopMove)
+	approver        Approver
+	schedule        *TradingSchedule
+	orderBooks      OrderBookSource
+	allocator       CapitalAllocator
+	guard           PerformanceGuard
+	blocklist       Blocklist
+	operator        OperatorPresence
+	algos           map[string]*algoRun
+	fillCosts       map[string]*ringbuf.Buffer[fillCost] // Recent fills per symbol
+	breakEven       map[string]*breakEvenState           // Open positions by ID
+	holdingExits    map[string]holdingExit               // Limit exits of positions held too long, by position ID
+	connectivity    ConnectivityCheck
+	parked          []ParkedSignal // Signals waiting for their exchange, oldest first
+	algoSeq         int
+	now             func() time.Time
 }
 
 // Config holds configuration for the execution agent
@@ -37,11 +104,104 @@ type Config struct {
 	StopLossPercent   decimal.Decimal // e.g., 0.005 for 0.5%
 	TakeProfitPercent decimal.Decimal // e.g., 0.01 for 1%
 
+	// Take profits scaled out of in rungs instead of a single one, the rest
+	// of the position riding with a stop moved to break-even once the first
+	// rung fills, then trailing the best price by TrailingStopPercent
+	TakeProfitLadder    []TakeProfitRung
+	TrailingStopPercent decimal.Decimal // e.g., 0.005 for 0.5% (0 leaves the stop at break-even)
+
+	// Stop moved to the entry price once the unrealized profit reaches
+	// BreakEvenTriggerR times the initial risk (0 disables)
+	BreakEvenTriggerR      float64
+	BreakEvenBufferPercent decimal.Decimal // Beyond the entry price, in the direction of profit when positive
+
+	// Positions exited once held longer than their strategy intends, when
+	// they neither hit their take profit nor their stop loss (0 disables)
+	MaxHoldingDuration   time.Duration
+	MaxHoldingStrategies map[string]time.Duration // Overrides by strategy
+	HoldingExit          HoldingExit              // market or mid
+	HoldingExitTimeout   time.Duration            // How long a mid exit rests before closing at market
+
+	// Signals parked while the exchange of their symbol is unreachable,
+	// executed once it is back if they are still valid (0 disables)
+	ParkSignalTTL   time.Duration // How long an entry may stay parked
+	ParkMaxDriftBps float64       // Furthest the price may move from a parked entry's price (0 disables)
+	ParkMaxSignals  int           // Signals parked at once, the oldest entry dropped first
+
 	// Signal thresholds
 	MinSignalStrength float64 // Minimum signal strength to execute (0.0-1.0)
 
+	// Entry size scaled with signal strength, from the min multiplier at
+	// MinSignalStrength to the max multiplier at full strength
+	StrengthSizing        StrengthSizing // none, linear or step
+	StrengthSizingSteps   int            // Size levels of the step curve
+	StrengthMinMultiplier float64        // Fraction of the risk-based size for the weakest signals
+	StrengthMaxMultiplier float64        // Fraction of the risk-based size for the strongest signals
+
 	// Execution settings
 	AutoExecute bool // Whether to automatically execute orders
+
+	// Order submission limits, independent of HTTP rate limiting (0 disables)
+	MaxOrdersPerSecond       int // Per exchange
+	MaxOrdersPerMinute       int // Per exchange
+	GlobalMaxOrdersPerSecond int // Across all exchanges
+	GlobalMaxOrdersPerMinute int // Across all exchanges
+	MaxOpenOrders            int // Including stop loss and take profit orders
+
+	// Scaling in and out of positions
+	MaxAddOns             int             // Entries added to an open position (0 disables pyramiding)
+	AddOnSizeFactor       decimal.Decimal // Add-on n is sized factor^n times an initial entry, e.g. 0.5 then 0.25
+	AddOnMinProfitPercent decimal.Decimal // Favorable move required before adding, e.g. 0.005 for 0.5%
+	ExitFraction          decimal.Decimal // Fraction of the position closed per exit signal (0 closes fully)
+
+	// Maker/taker behaviour of entry orders
+	EntryTimeInForce exchanges.TimeInForce // gtc, ioc, fok or gtd (empty is gtc)
+	EntryPostOnly    bool                  // Only rest on the book, never take liquidity
+	EntryOrderTTL    time.Duration         // Lifetime of gtd entries
+
+	// Execution algorithms for entries too large for the book
+	Algo                   AlgoType        // none, twap or iceberg
+	AlgoDepthFraction      float64         // Entries above this fraction of the visible depth are worked (0 disables)
+	TWAPSlices             int             // Number of TWAP slices
+	TWAPDuration           time.Duration   // Time over which TWAP slices are spread
+	NativeTWAP             bool            // Have TWAPs worked by the exchange when it can
+	IcebergDisplayFraction decimal.Decimal // Fraction of the order shown per iceberg slice
+	AlgoPollInterval       time.Duration   // How often resting iceberg slices are checked
+
+	// Entries pegged to the touch and repriced as the book moves
+	ChaseEntries        bool          // Chase entries that are not worked by an algorithm
+	ChaseMaxAmendments  int           // Reprices before the order is left resting
+	ChaseMaxSlippageBps float64       // Furthest the price may move from the signal price
+	ChaseInterval       time.Duration // How often the touch is checked
+
+	// Entries posted at the touch first, then crossed with a market order or
+	// abandoned when they do not fill in time or the price moves away
+	MakerFirst           MakerFirst            // off, market or abandon
+	MakerFirstStrategies map[string]MakerFirst // Overrides by strategy
+	MakerTimeout         time.Duration         // How long the post-only order may rest
+	MakerMaxDriftBps     float64               // Furthest the touch may move away from it (0 disables)
+
+	// Spread and liquidity guard on entries (0 disables a check)
+	MaxSpreadBps   float64 // Maximum bid/ask spread in basis points of the mid price
+	MaxSlippageBps float64 // Depth within this distance of the best price must fill the order
+	BookDepth      int     // Order book levels fetched for the checks
+
+	// Entries proposed to an operator and only placed once approved
+	ConfirmEntries bool
+	ConfirmTimeout time.Duration // How long a proposal waits for approval
+
+	// Take profits checked against the expected round-trip cost of the
+	// entry: fees, the spread and the slippage of recent fills
+	CostPolicy         CostPolicy // none, widen or reject
+	MakerFeeBps        float64
+	TakerFeeBps        float64
+	CostWindow         int     // Recent fills of a symbol its costs are estimated from
+	MinNetProfitBps    float64 // Take profit left after costs an entry must keep
+	CostMaxWidenFactor float64 // Furthest the widen policy moves the take profit, as a multiple of its distance
+
+	// Builder fee attached to entries, on exchanges that pay builders
+	BuilderAddress      string
+	BuilderFeeTenthsBps int
 }
 
 // DefaultConfig returns default execution configuration
@@ -51,7 +211,316 @@ func DefaultConfig() Config {
 		TakeProfitPercent: decimal.NewFromFloat(0.01),  // 1%
 		MinSignalStrength: 0.3,                         // 30% - Reduced to allow more signals while still filtering weak ones
 		AutoExecute:       true,
+
+		StrengthSizing:        StrengthSizingNone,
+		StrengthSizingSteps:   3,
+		StrengthMinMultiplier: 0.5,
+		StrengthMaxMultiplier: 1,
+
+		MaxOrdersPerSecond:       5,
+		MaxOrdersPerMinute:       60,
+		GlobalMaxOrdersPerSecond: 10,
+		GlobalMaxOrdersPerMinute: 120,
+		MaxOpenOrders:            50,
+
+		MaxAddOns:             0,
+		AddOnSizeFactor:       decimal.NewFromFloat(0.5),
+		AddOnMinProfitPercent: decimal.NewFromFloat(0.005), // 0.5%
+		ExitFraction:          decimal.NewFromInt(1),
+
+		EntryTimeInForce: exchanges.TimeInForceGTC,
+		EntryOrderTTL:    time.Minute,
+
+		Algo:                   AlgoNone,
+		AlgoDepthFraction:      0.5,
+		TWAPSlices:             5,
+		TWAPDuration:           time.Minute,
+		IcebergDisplayFraction: decimal.NewFromFloat(0.2),
+		AlgoPollInterval:       time.Second,
+
+		ChaseMaxAmendments:  5,
+		ChaseMaxSlippageBps: 10,
+		ChaseInterval:       2 * time.Second,
+
+		MakerFirst:       MakerFirstOff,
+		MakerTimeout:     10 * time.Second,
+		MakerMaxDriftBps: 10,
+
+		BookDepth: 20,
+
+		ConfirmTimeout: DefaultConfirmTimeout,
+
+		CostPolicy:         CostPolicyNone,
+		MakerFeeBps:        2,
+		TakerFeeBps:        5,
+		CostWindow:         50,
+		CostMaxWidenFactor: 2,
+
+		HoldingExit:        HoldingExitMarket,
+		HoldingExitTimeout: 30 * time.Second,
+
+		ParkMaxDriftBps: 20,
+		ParkMaxSignals:  50,
+	}
+}
+
+// LoadConfig loads execution configuration from environment variables,
+// keeping the DefaultConfig value of every variable unset
+func LoadConfig() Config {
+	config := DefaultConfig()
+
+	if val := os.Getenv("EXECUTION_AUTO_TRADE"); val != "" {
+		if parsed, err := strconv.ParseBool(val); err == nil {
+			config.AutoExecute = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_MIN_SIGNAL_STRENGTH"); val != "" {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil {
+			config.MinSignalStrength = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_STRENGTH_SIZING"); val != "" {
+		switch sizing := StrengthSizing(strings.ToLower(val)); sizing {
+		case StrengthSizingNone, StrengthSizingLinear, StrengthSizingStep:
+			config.StrengthSizing = sizing
+		}
+	}
+	for key, target := range map[string]*float64{
+		"EXECUTION_STRENGTH_MIN_MULTIPLIER": &config.StrengthMinMultiplier,
+		"EXECUTION_STRENGTH_MAX_MULTIPLIER": &config.StrengthMaxMultiplier,
+	} {
+		if val := os.Getenv(key); val != "" {
+			// Strength only sizes entries down from the risk-based size
+			if parsed, err := strconv.ParseFloat(val, 64); err == nil && parsed > 0 {
+				*target = math.Min(parsed, 1)
+			}
+		}
+	}
+	if config.StrengthMinMultiplier > config.StrengthMaxMultiplier {
+		config.StrengthMinMultiplier = config.StrengthMaxMultiplier
+	}
+	if val := os.Getenv("EXECUTION_STOP_LOSS_PERCENT"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil {
+			config.StopLossPercent = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_TAKE_PROFIT_PERCENT"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil {
+			config.TakeProfitPercent = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_TAKE_PROFIT_LADDER"); val != "" {
+		if rungs, ok := parseTakeProfitLadder(val); ok {
+			config.TakeProfitLadder = rungs
+		}
+	}
+	if val := os.Getenv("EXECUTION_TRAILING_STOP_PERCENT"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && !parsed.IsNegative() && parsed.LessThan(decimal.NewFromInt(1)) {
+			config.TrailingStopPercent = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_BREAK_EVEN_TRIGGER_R"); val != "" {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil && parsed >= 0 {
+			config.BreakEvenTriggerR = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_BREAK_EVEN_BUFFER_PERCENT"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil {
+			config.BreakEvenBufferPercent = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_MAX_HOLDING_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+			config.MaxHoldingDuration = time.Duration(parsed) * time.Second
+		}
+	}
+	if val := os.Getenv("EXECUTION_MAX_HOLDING_STRATEGIES"); val != "" {
+		config.MaxHoldingStrategies = parseHoldingDurations(val)
+	}
+	if val := os.Getenv("EXECUTION_HOLDING_EXIT"); val != "" {
+		switch exit := HoldingExit(strings.ToLower(val)); exit {
+		case HoldingExitMarket, HoldingExitMid:
+			config.HoldingExit = exit
+		}
+	}
+	if val := os.Getenv("EXECUTION_HOLDING_EXIT_TIMEOUT_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed > 0 {
+			config.HoldingExitTimeout = time.Duration(parsed) * time.Second
+		}
+	}
+	if val := os.Getenv("EXECUTION_PARK_SIGNALS_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+			config.ParkSignalTTL = time.Duration(parsed) * time.Second
+		}
+	}
+	if val := os.Getenv("EXECUTION_PARK_MAX_DRIFT_BPS"); val != "" {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil && parsed >= 0 {
+			config.ParkMaxDriftBps = parsed
+		}
 	}
+	if val := os.Getenv("EXECUTION_ADD_ON_SIZE_FACTOR"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && parsed.IsPositive() {
+			config.AddOnSizeFactor = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_ADD_ON_MIN_PROFIT_PERCENT"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && !parsed.IsNegative() {
+			config.AddOnMinProfitPercent = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_EXIT_FRACTION"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && parsed.IsPositive() && parsed.LessThanOrEqual(decimal.NewFromInt(1)) {
+			config.ExitFraction = parsed
+		}
+	}
+
+	if val := os.Getenv("EXECUTION_TIME_IN_FORCE"); val != "" {
+		tif := exchanges.TimeInForce(strings.ToLower(val))
+		if exchanges.ValidateTimeInForce(tif, false, time.Now().Add(time.Minute)) == nil {
+			config.EntryTimeInForce = tif
+		}
+	}
+	if val := os.Getenv("EXECUTION_POST_ONLY"); val != "" {
+		if parsed, err := strconv.ParseBool(val); err == nil {
+			config.EntryPostOnly = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_ORDER_TTL_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed > 0 {
+			config.EntryOrderTTL = time.Duration(parsed) * time.Second
+		}
+	}
+	if val := os.Getenv("EXECUTION_ALGO"); val != "" {
+		switch algo := AlgoType(strings.ToLower(val)); algo {
+		case AlgoNone, AlgoTWAP, AlgoIceberg:
+			config.Algo = algo
+		}
+	}
+	if val := os.Getenv("EXECUTION_ALGO_DEPTH_FRACTION"); val != "" {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil && parsed >= 0 {
+			config.AlgoDepthFraction = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_TWAP_DURATION_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed > 0 {
+			config.TWAPDuration = time.Duration(parsed) * time.Second
+		}
+	}
+	if val := os.Getenv("EXECUTION_NATIVE_TWAP"); val != "" {
+		if parsed, err := strconv.ParseBool(val); err == nil {
+			config.NativeTWAP = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_BUILDER_ADDRESS"); val != "" {
+		config.BuilderAddress = val
+	}
+	if val := os.Getenv("EXECUTION_BUILDER_FEE_TENTHS_BPS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+			config.BuilderFeeTenthsBps = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_ICEBERG_DISPLAY_FRACTION"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && parsed.IsPositive() && parsed.LessThanOrEqual(decimal.NewFromInt(1)) {
+			config.IcebergDisplayFraction = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_CHASE"); val != "" {
+		if parsed, err := strconv.ParseBool(val); err == nil {
+			config.ChaseEntries = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_CHASE_MAX_SLIPPAGE_BPS"); val != "" {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil && parsed >= 0 {
+			config.ChaseMaxSlippageBps = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_CHASE_INTERVAL_MS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed > 0 {
+			config.ChaseInterval = time.Duration(parsed) * time.Millisecond
+		}
+	}
+	if val := os.Getenv("EXECUTION_MAKER_FIRST"); val != "" {
+		if mode, ok := parseMakerFirst(val); ok {
+			config.MakerFirst = mode
+		}
+	}
+	if val := os.Getenv("EXECUTION_MAKER_FIRST_STRATEGIES"); val != "" {
+		config.MakerFirstStrategies = parseMakerFirstStrategies(val)
+	}
+	if val := os.Getenv("EXECUTION_MAKER_TIMEOUT_MS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed > 0 {
+			config.MakerTimeout = time.Duration(parsed) * time.Millisecond
+		}
+	}
+	if val := os.Getenv("EXECUTION_MAKER_MAX_DRIFT_BPS"); val != "" {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil && parsed >= 0 {
+			config.MakerMaxDriftBps = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_MAX_SPREAD_BPS"); val != "" {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil && parsed >= 0 {
+			config.MaxSpreadBps = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_MAX_SLIPPAGE_BPS"); val != "" {
+		if parsed, err := strconv.ParseFloat(val, 64); err == nil && parsed >= 0 {
+			config.MaxSlippageBps = parsed
+		}
+	}
+
+	if val := os.Getenv("EXECUTION_COST_POLICY"); val != "" {
+		switch policy := CostPolicy(strings.ToLower(val)); policy {
+		case CostPolicyNone, CostPolicyWiden, CostPolicyReject:
+			config.CostPolicy = policy
+		}
+	}
+	for key, target := range map[string]*float64{
+		"EXECUTION_MAKER_FEE_BPS":      &config.MakerFeeBps,
+		"EXECUTION_TAKER_FEE_BPS":      &config.TakerFeeBps,
+		"EXECUTION_MIN_NET_PROFIT_BPS": &config.MinNetProfitBps,
+		"EXECUTION_COST_MAX_WIDEN":     &config.CostMaxWidenFactor,
+	} {
+		if val := os.Getenv(key); val != "" {
+			if parsed, err := strconv.ParseFloat(val, 64); err == nil && parsed >= 0 {
+				*target = parsed
+			}
+		}
+	}
+
+	if val := os.Getenv("EXECUTION_CONFIRM_ENTRIES"); val != "" {
+		if parsed, err := strconv.ParseBool(val); err == nil {
+			config.ConfirmEntries = parsed
+		}
+	}
+	if val := os.Getenv("EXECUTION_CONFIRM_TIMEOUT_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed > 0 {
+			config.ConfirmTimeout = time.Duration(parsed) * time.Second
+		}
+	}
+
+	intOverrides := map[string]*int{
+		"EXECUTION_MAX_ORDERS_PER_SECOND":        &config.MaxOrdersPerSecond,
+		"EXECUTION_MAX_ORDERS_PER_MINUTE":        &config.MaxOrdersPerMinute,
+		"EXECUTION_GLOBAL_MAX_ORDERS_PER_SECOND": &config.GlobalMaxOrdersPerSecond,
+		"EXECUTION_GLOBAL_MAX_ORDERS_PER_MINUTE": &config.GlobalMaxOrdersPerMinute,
+		"EXECUTION_MAX_OPEN_ORDERS":              &config.MaxOpenOrders,
+		"EXECUTION_MAX_ADD_ONS":                  &config.MaxAddOns,
+		"EXECUTION_BOOK_DEPTH":                   &config.BookDepth,
+		"EXECUTION_TWAP_SLICES":                  &config.TWAPSlices,
+		"EXECUTION_CHASE_MAX_AMENDMENTS":         &config.ChaseMaxAmendments,
+		"EXECUTION_STRENGTH_SIZING_STEPS":        &config.StrengthSizingSteps,
+		"EXECUTION_COST_WINDOW":                  &config.CostWindow,
+		"EXECUTION_PARK_MAX_SIGNALS":             &config.ParkMaxSignals,
+	}
+	for key, target := range intOverrides {
+		if val := os.Getenv(key); val != "" {
+			if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+				*target = parsed
+			}
+		}
+	}
+
+	return config
 }
 
 // NewExecutionAgent creates a new execution agent
@@ -60,51 +529,261 @@ func NewExecutionAgent(orderManager OrderManager, riskManager RiskManager, confi
 		orderManager: orderManager,
 		riskManager:  riskManager,
 		config:       config,
+		throttle:     NewOrderThrottle(config),
+		addOns:       make(map[string]int),
+	}
+}
+
+// SetExchangeResolver sets the function used to map a symbol to the exchange
+// it trades on, so order limits can be enforced per exchange
+func (e *ExecutionAgent) SetExchangeResolver(resolver func(symbol string) string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resolveExchange = resolver
+}
+
+// SetEntryCallback sets the callback invoked after an entry order is placed
+// for a signal, with the decision sizing it
+func (e *ExecutionAgent) SetEntryCallback(callback func(signal *strategy.Signal, placed *exchanges.Order, decision *Decision)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.onEntry = callback
+}
+
+// SetSchedule restricts entries to the trading windows of schedule
+func (e *ExecutionAgent) SetSchedule(schedule *TradingSchedule) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.schedule = schedule
+}
+
+// SetCapitalAllocator sizes entries from the capital allocated to the
+// strategy of each signal instead of the whole balance
+func (e *ExecutionAgent) SetCapitalAllocator(allocator CapitalAllocator) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.allocator = allocator
+}
+
+// SetPerformanceGuard scales down or refuses the entries of strategies the
+// guard finds degraded
+func (e *ExecutionAgent) SetPerformanceGuard(guard PerformanceGuard) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.guard = guard
+}
+
+// clock returns the current time
+func (e *ExecutionAgent) clock() time.Time {
+	if e.now != nil {
+		return e.now()
+	}
+	return time.Now()
+}
+
+// checkSchedule rejects entries outside the trading windows of the symbol,
+// and while the positions of the strategy on it must be flat
+func (e *ExecutionAgent) checkSchedule(symbol, strategyName string) error {
+	e.mu.RLock()
+	schedule := e.schedule
+	e.mu.RUnlock()
+
+	now := e.clock()
+	if allowed, reason := schedule.Allowed(symbol, now); !allowed {
+		telemetry.RecordSignalBlocked(symbol, "schedule")
+		return &ExecutionError{
+			Type:    ExecutionErrorTypeTradingWindowClosed,
+			Message: fmt.Sprintf("%s: %s", symbol, reason),
+		}
 	}
+	if flat, reason := schedule.Flat(symbol, strategyName, now); flat {
+		telemetry.RecordSignalBlocked(symbol, "flat_by")
+		return &ExecutionError{
+			Type:    ExecutionErrorTypeTradingWindowClosed,
+			Message: fmt.Sprintf("%s: %s", symbol, reason),
+		}
+	}
+	return nil
 }
 
-// HandleSignal processes a trading signal and executes orders if conditions are met
+// throttleExchange returns the exchange whose order limits apply to symbol
+func (e *ExecutionAgent) throttleExchange(symbol string) string {
+	e.mu.RLock()
+	resolver := e.resolveExchange
+	e.mu.RUnlock()
+
+	if resolver == nil {
+		return ""
+	}
+	return resolver(symbol)
+}
+
+// reserveOrders checks the order submission limits for count new orders on symbol
+func (e *ExecutionAgent) reserveOrders(symbol string, count int) error {
+	if e.throttle == nil {
+		return nil
+	}
+
+	if err := e.throttle.Reserve(e.throttleExchange(symbol), count, len(e.orderManager.GetOpenOrders())); err != nil {
+		telemetry.RecordError("order_rate_limited")
+		return &ExecutionError{
+			Type:    ExecutionErrorTypeRateLimited,
+			Message: err.Error(),
+		}
+	}
+	return nil
+}
+
+// HandleSignal processes a trading signal and executes orders if conditions
+// are met. The decision made is reported to the decision callback.
 func (e *ExecutionAgent) HandleSignal(ctx context.Context, signal *strategy.Signal) error {
+	decision := newDecision(signal, e.clock())
+	placed, err := e.handleSignal(ctx, signal, decision)
+	decision.complete(placed, err)
+	e.recordDecision(decision)
+	return err
+}
+
+// handleSignal executes signal, filling decision with what was done
+func (e *ExecutionAgent) handleSignal(ctx context.Context, signal *strategy.Signal, decision *Decision) (*exchanges.Order, error) {
 	// Check if auto-execution is enabled
 	if !e.config.AutoExecute {
-		return nil
+		decision.skip("auto-execution disabled")
+		return nil, nil
 	}
 
 	// Check signal strength threshold
 	if signal.Strength < e.config.MinSignalStrength {
-		return nil
+		decision.skip(fmt.Sprintf("strength %.2f below minimum %.2f", signal.Strength, e.config.MinSignalStrength))
+		return nil, nil
+	}
+
+	if e.parkSignal(signal, decision) {
+		return nil, nil
 	}
 
 	switch signal.Type {
 	case strategy.SignalTypeEntry:
+		if reason, blocked := e.checkBlocklist(signal.Symbol); blocked {
+			decision.skip(reason)
+			return nil, nil
+		}
+		if reason, absent := e.checkOperator(signal.Symbol); absent {
+			decision.skip(reason)
+			return nil, nil
+		}
+		if err := e.checkSchedule(signal.Symbol, signal.Strategy); err != nil {
+			return nil, err
+		}
 		canTrade, reason := e.riskManager.CanTrade()
 		if !canTrade {
-			return &ExecutionError{
+			return nil, &ExecutionError{
 				Type:    ExecutionErrorTypeRiskCheckFailed,
 				Message: reason,
 			}
 		}
-		return e.handleEntrySignal(ctx, signal)
+		return e.handleEntrySignal(ctx, signal, decision)
 	case strategy.SignalTypeExit:
-		return e.handleExitSignal(ctx, signal)
+		return e.handleExitSignal(ctx, signal, decision)
 	default:
-		return &ExecutionError{
+		return nil, &ExecutionError{
 			Type:    ExecutionErrorTypeInvalidSignal,
 			Message: "Unknown signal type",
 		}
 	}
 }
 
+// protectedOrderCount returns the orders submitted for req: the entry and
+// its stop loss and take profits
+func protectedOrderCount(req *order.OrderRequest) int {
+	count := 1
+	if !req.StopLoss.IsZero() {
+		count++
+	}
+	if len(req.TakeProfitLadder) > 0 {
+		count += len(req.TakeProfitLadder)
+	} else if !req.TakeProfit.IsZero() {
+		count++
+	}
+	return count
+}
+
 // handleEntrySignal handles entry signals by placing orders
-func (e *ExecutionAgent) handleEntrySignal(ctx context.Context, signal *strategy.Signal) error {
+func (e *ExecutionAgent) handleEntrySignal(ctx context.Context, signal *strategy.Signal, decision *Decision) (*exchanges.Order, error) {
+	// An algorithm is still building the position
+	if e.algoRunning(signal.Symbol) {
+		decision.skip("execution algorithm running")
+		return nil, nil
+	}
+
 	// Calculate stop loss price
 	stopLoss := e.calculateStopLoss(signal)
 
-	// Get current balance for position sizing
-	balance := e.riskManager.GetCurrentBalance()
+	positions := e.orderManager.GetPositions()
+
+	// Size from the capital of the signal's strategy when the balance is
+	// shared, leaving symbols held by another strategy to it
+	e.mu.RLock()
+	allocator := e.allocator
+	guard := e.guard
+	e.mu.RUnlock()
+	strategyName := signal.Strategy
+	if strategyName == "" {
+		strategyName = strategy.MainInstance
+	}
+	var balance decimal.Decimal
+	if allocator != nil {
+		if owner, ok := allocator.Owner(signal.Symbol); ok && owner != strategyName && hasOpenPosition(positions, signal.Symbol) {
+			telemetry.RecordSignalBlocked(signal.Symbol, "strategy")
+			decision.skip("position held by strategy " + owner)
+			return nil, nil
+		}
+		balance = allocator.Capital(strategyName)
+	} else {
+		balance = e.riskManager.GetCurrentBalance()
+	}
 
 	// Calculate position size based on risk management
 	positionSize := e.riskManager.CalculatePositionSize(signal.Price, stopLoss, balance)
+	riskSize := positionSize
+	sizeReason := ""
+
+	// Weaker signals take a smaller share of the risk-based size
+	multiplier := e.config.strengthMultiplier(signal.Strength)
+	decision.SizeMultiplier = multiplier
+	if multiplier != 1 {
+		positionSize = positionSize.Mul(decimal.NewFromFloat(multiplier))
+		sizeReason = fmt.Sprintf("strength %.2f sized at %.2fx", signal.Strength, multiplier)
+	}
+
+	// Strategies performing below expectations trade smaller, or not at all
+	if guard != nil {
+		factor, reason := guard.SizeFactor(strategyName, signal.Symbol)
+		if factor <= 0 {
+			telemetry.RecordSignalBlocked(signal.Symbol, "degraded")
+			decision.skip(reason)
+			return nil, nil
+		}
+		if factor < 1 {
+			positionSize = positionSize.Mul(decimal.NewFromFloat(factor))
+			sizeReason = reason
+		}
+	}
+
+	// Entries on the side of an open position scale into it
+	addOnKey := signal.Symbol + "|" + string(positionSideFor(signal.Side))
+	existing := findOpenPosition(positions, signal.Symbol, positionSideFor(signal.Side))
+	if existing != nil {
+		addOnSize, ok := e.planAddOn(existing, addOnKey, signal, positionSize)
+		if !ok {
+			decision.skip("add-on not allowed")
+			return nil, nil
+		}
+		positionSize = addOnSize
+		sizeReason = "add-on scaled down"
+	} else {
+		e.resetAddOns(addOnKey)
+	}
 
 	// Calculate take profit price
 	takeProfit := e.calculateTakeProfit(signal)
@@ -118,77 +797,365 @@ func (e *ExecutionAgent) handleEntrySignal(ctx context.Context, signal *strategy
 		Amount:     positionSize,
 		StopLoss:   stopLoss,
 		TakeProfit: takeProfit,
+
+		TimeInForce:    e.config.EntryTimeInForce,
+		PostOnly:       e.config.EntryPostOnly,
+		Strategy:       strategyName,
+		ReferencePrice: signal.Price,
+	}
+	if req.TimeInForce == exchanges.TimeInForceGTD {
+		req.ExpiresAt = e.clock().Add(e.config.EntryOrderTTL)
+	}
+	// The nearest rung of a ladder stands for the take profit in the checks
+	if ladder := e.config.takeProfitLadder(signal.Side, signal.Price); len(ladder) > 0 {
+		req.TakeProfitLadder = ladder
+		req.TakeProfit = ladder[0].Price
+		req.TrailingStop = e.config.TrailingStopPercent
+	}
+	if e.config.BuilderAddress != "" {
+		req.Options = &exchanges.OrderOptions{BuilderFee: &exchanges.BuilderFee{
+			Address:   e.config.BuilderAddress,
+			TenthsBps: e.config.BuilderFeeTenthsBps,
+		}}
 	}
 
+	// Pause or adjust entries around scheduled events
+	if policy, ok := e.riskManager.(EventPolicy); ok {
+		amount := req.Amount
+		if err := policy.ApplyEventPolicy(req); err != nil {
+			telemetry.RecordSignalBlocked(req.Symbol, "event")
+			return nil, &ExecutionError{
+				Type:    ExecutionErrorTypeEventPause,
+				Message: err.Error(),
+			}
+		}
+		if req.Amount.LessThan(amount) {
+			sizeReason = "reduced by event policy"
+		}
+	}
+	decision.sized(riskSize, req.Amount, sizeReason)
+
 	// Validate order with risk manager
-	positions := e.orderManager.GetPositions()
 	if err := e.riskManager.ValidateOrder(req, positions); err != nil {
-		return &ExecutionError{
+		return nil, &ExecutionError{
 			Type:    ExecutionErrorTypeRiskValidationFailed,
 			Message: err.Error(),
 		}
 	}
 
-	// Place the order
-	placedOrder, err := e.orderManager.PlaceOrder(ctx, req)
+	// Check the book can take the order at an acceptable price
+	book, err := e.checkLiquidity(ctx, req)
 	if err != nil {
-		return &ExecutionError{
-			Type:    ExecutionErrorTypeOrderPlacementFailed,
-			Message: err.Error(),
+		return nil, err
+	}
+
+	// Scalp only where the take profit clears the fees, spread and slippage
+	if reason, skipped := e.applyCostPolicy(req, book, decision); skipped {
+		decision.skip(reason)
+		return nil, nil
+	}
+
+	// In confirmation mode the entry is placed once an operator approves it,
+	// without holding up the signals handled meanwhile
+	proposed, err := e.proposeEntry(ctx, signal, req, decision, func(ctx context.Context, decision *Decision) (*exchanges.Order, error) {
+		// The book may have moved while the entry awaited approval
+		book, err := e.checkLiquidity(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return e.placeEntry(ctx, signal, req, book, existing != nil, addOnKey, decision)
+	})
+	if proposed || err != nil {
+		return nil, err
+	}
+	return e.placeEntry(ctx, signal, req, book, existing != nil, addOnKey, decision)
+}
+
+// placeEntry places the validated entry req, or works it in slices when it
+// is large for book. addOn is set when it adds to the position counted under
+// addOnKey.
+func (e *ExecutionAgent) placeEntry(ctx context.Context, signal *strategy.Signal, req *order.OrderRequest, book *exchanges.OrderBook, addOn bool, addOnKey string, decision *Decision) (*exchanges.Order, error) {
+	e.mu.RLock()
+	allocator := e.allocator
+	e.mu.RUnlock()
+	strategyName := req.Strategy
+
+	var (
+		placedOrder *exchanges.Order
+		err         error
+	)
+	if e.useAlgo(req, book) {
+		placedOrder, err = e.startAlgo(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	} else if mode := e.config.makerFirstFor(strategyName); book != nil && (mode == MakerFirstMarket || mode == MakerFirstAbandon) {
+		placedOrder, err = e.startMakerFirst(ctx, req, book, mode)
+		if err != nil {
+			return nil, err
+		}
+	} else if book != nil && e.chaseEnabled() {
+		placedOrder, err = e.startChase(ctx, req, book)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if err := e.reserveOrders(req.Symbol, protectedOrderCount(req)); err != nil {
+			return nil, err
+		}
+		placedOrder, err = e.orderManager.PlaceOrder(ctx, req)
+		if err != nil {
+			return nil, exchangeError(ExecutionErrorTypeOrderPlacementFailed, err)
 		}
 	}
 
-	// Log successful order placement
-	// (Logging will be handled by the order manager callbacks)
+	if recorder, ok := e.riskManager.(EntryRecorder); ok {
+		recorder.RecordEntry(req.Symbol, e.clock())
+	}
+	if allocator != nil {
+		allocator.AssignPosition(req.Symbol, strategyName)
+	}
 
-	_ = placedOrder // Use the placed order if needed for logging
+	e.mu.Lock()
+	if addOn {
+		if e.addOns == nil {
+			e.addOns = make(map[string]int)
+		}
+		e.addOns[addOnKey]++
+	}
+	onEntry := e.onEntry
+	e.mu.Unlock()
 
+	if onEntry != nil {
+		onEntry(signal, placedOrder, decision)
+	}
+
+	return placedOrder, nil
+}
+
+// planAddOn applies the pyramiding rules to an entry on the side of an open
+// position and returns the add-on size. The nth add-on is AddOnSizeFactor^n
+// times baseSize, the size of an initial entry, rather than a factor of the
+// previous add-on's fill, and is only allowed once the position has moved
+// AddOnMinProfitPercent in its favor.
+func (e *ExecutionAgent) planAddOn(position *order.ManagedPosition, key string, signal *strategy.Signal, baseSize decimal.Decimal) (decimal.Decimal, bool) {
+	if e.config.MaxAddOns <= 0 {
+		return decimal.Zero, false
+	}
+
+	e.mu.RLock()
+	made := e.addOns[key]
+	e.mu.RUnlock()
+	if made >= e.config.MaxAddOns {
+		return decimal.Zero, false
+	}
+
+	if position.EntryPrice.IsPositive() {
+		move := signal.Price.Sub(position.EntryPrice).Div(position.EntryPrice)
+		if position.Side == order.PositionSideShort {
+			move = move.Neg()
+		}
+		if move.LessThan(e.config.AddOnMinProfitPercent) {
+			return decimal.Zero, false
+		}
+	}
+
+	size := baseSize
+	factor := e.config.AddOnSizeFactor
+	if factor.IsPositive() {
+		for i := 0; i <= made; i++ {
+			size = size.Mul(factor)
+		}
+	}
+	if !size.IsPositive() {
+		return decimal.Zero, false
+	}
+	return size, true
+}
+
+// resetAddOns forgets the add-ons made to a position that has been closed
+func (e *ExecutionAgent) resetAddOns(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.addOns, key)
+}
+
+// findOpenPosition returns the open position on one side of symbol, if any
+func findOpenPosition(positions []*order.ManagedPosition, symbol string, side order.PositionSide) *order.ManagedPosition {
+	for _, position := range positions {
+		if position.Symbol == symbol && position.Side == side && position.Status == order.PositionStatusOpen {
+			return position
+		}
+	}
 	return nil
 }
 
+// hasOpenPosition reports whether symbol has an open position on either side
+func hasOpenPosition(positions []*order.ManagedPosition, symbol string) bool {
+	return findOpenPosition(positions, symbol, order.PositionSideLong) != nil ||
+		findOpenPosition(positions, symbol, order.PositionSideShort) != nil
+}
+
+// positionSideFor returns the position side opened by an order side
+func positionSideFor(side exchanges.OrderSide) order.PositionSide {
+	if side == exchanges.OrderSideBuy {
+		return order.PositionSideLong
+	}
+	return order.PositionSideShort
+}
+
 // handleExitSignal handles exit signals by closing positions
-func (e *ExecutionAgent) handleExitSignal(ctx context.Context, signal *strategy.Signal) error {
+func (e *ExecutionAgent) handleExitSignal(ctx context.Context, signal *strategy.Signal, decision *Decision) (*exchanges.Order, error) {
+	// Only the strategy holding the position, the lead of an ensemble entry
+	// included, exits it
+	e.mu.RLock()
+	allocator := e.allocator
+	e.mu.RUnlock()
+	if allocator != nil {
+		strategyName := signal.Strategy
+		if strategyName == "" {
+			strategyName = strategy.MainInstance
+		}
+		owner, ok := allocator.Owner(signal.Symbol)
+		if ok && owner != strategyName && hasOpenPosition(e.orderManager.GetPositions(), signal.Symbol) {
+			telemetry.RecordSignalBlocked(signal.Symbol, "strategy")
+			decision.skip("position held by strategy " + owner)
+			return nil, nil
+		}
+	}
+
+	// A price seen on a single venue, like a wick, does not close positions;
+	// the strategy signals again while the exit condition holds
+	if confirmer, ok := e.orderManager.(ExitPriceConfirmer); ok {
+		if err := confirmer.ConfirmExitPrice(ctx, signal.Symbol, signal.Price); err != nil {
+			decision.skip(err.Error())
+			return nil, nil
+		}
+	}
+
+	// Stop adding to a position that is being exited
+	e.cancelSymbolAlgos(signal.Symbol)
+
+	// Exits reduce risk, so the order limits never hold them back
+	if e.throttle != nil {
+		e.throttle.Record(e.throttleExchange(signal.Symbol), 1)
+	}
+
+	// Scale out of the position when configured, otherwise close it
+	fraction := e.config.ExitFraction
+	if fraction.IsPositive() && fraction.LessThan(decimal.NewFromInt(1)) {
+		placed, err := e.orderManager.ReducePosition(ctx, signal.Symbol, fraction)
+		if err != nil {
+			return nil, exchangeError(ExecutionErrorTypePositionCloseFailed, err)
+		}
+		decision.Reason = "position reduced by " + fraction.String()
+		return placed, nil
+	}
+
 	// Close position for the symbol
 	if err := e.orderManager.ClosePosition(ctx, signal.Symbol); err != nil {
-		return &ExecutionError{
-			Type:    ExecutionErrorTypePositionCloseFailed,
-			Message: err.Error(),
-		}
+		return nil, exchangeError(ExecutionErrorTypePositionCloseFailed, err)
 	}
+	e.resetAddOns(signal.Symbol + "|" + string(order.PositionSideLong))
+	e.resetAddOns(signal.Symbol + "|" + string(order.PositionSideShort))
 
-	return nil
+	decision.Reason = "position closed"
+	return nil, nil
 }
 
 // calculateStopLoss calculates the stop loss price based on signal side
 func (e *ExecutionAgent) calculateStopLoss(signal *strategy.Signal) decimal.Decimal {
+	stopLoss := e.config.StopLossPercent
+	if signal.StopLossPercent > 0 {
+		stopLoss = decimal.NewFromFloat(signal.StopLossPercent / 100)
+	}
 	if signal.Side == exchanges.OrderSideBuy {
 		// For buy orders, stop loss is below entry price
-		return signal.Price.Mul(decimal.NewFromInt(1).Sub(e.config.StopLossPercent))
+		return signal.Price.Mul(decimal.NewFromInt(1).Sub(stopLoss))
 	}
 	// For sell orders, stop loss is above entry price
-	return signal.Price.Mul(decimal.NewFromInt(1).Add(e.config.StopLossPercent))
+	return signal.Price.Mul(decimal.NewFromInt(1).Add(stopLoss))
 }
 
 // calculateTakeProfit calculates the take profit price based on signal side
 func (e *ExecutionAgent) calculateTakeProfit(signal *strategy.Signal) decimal.Decimal {
+	takeProfit := e.config.TakeProfitPercent
+	if signal.TakeProfitPercent > 0 {
+		takeProfit = decimal.NewFromFloat(signal.TakeProfitPercent / 100)
+	}
 	if signal.Side == exchanges.OrderSideBuy {
 		// For buy orders, take profit is above entry price
-		return signal.Price.Mul(decimal.NewFromInt(1).Add(e.config.TakeProfitPercent))
+		return signal.Price.Mul(decimal.NewFromInt(1).Add(takeProfit))
 	}
 	// For sell orders, take profit is below entry price
-	return signal.Price.Mul(decimal.NewFromInt(1).Sub(e.config.TakeProfitPercent))
+	return signal.Price.Mul(decimal.NewFromInt(1).Sub(takeProfit))
 }
 
 // ExecutionError represents an error that occurred during order execution
 type ExecutionError struct {
 	Type    ExecutionErrorType
 	Message string
+	Err     error // Error of the exchange, if it caused the failure
 }
 
 func (e *ExecutionError) Error() string {
 	return e.Message
 }
 
+func (e *ExecutionError) Unwrap() error {
+	return e.Err
+}
+
+// Action returns how the error should be handled
+func (e *ExecutionError) Action() ErrorAction {
+	switch e.Type {
+	case ExecutionErrorTypeRateLimited:
+		return ErrorActionRetry
+	case ExecutionErrorTypeInsufficientFunds, ExecutionErrorTypeAuthFailed:
+		return ErrorActionAlert
+	case ExecutionErrorTypeOrderPlacementFailed:
+		if exchanges.CodeOf(e.Err).Retryable() {
+			return ErrorActionRetry
+		}
+		return ErrorActionAbort
+	case ExecutionErrorTypePositionCloseFailed:
+		// A position that cannot be closed needs attention
+		if exchanges.CodeOf(e.Err).Retryable() {
+			return ErrorActionRetry
+		}
+		return ErrorActionAlert
+	default:
+		return ErrorActionAbort
+	}
+}
+
+// ErrorAction is how an execution error should be handled
+type ErrorAction string
+
+const (
+	ErrorActionRetry ErrorAction = "retry" // Transient: a later signal may succeed
+	ErrorActionAbort ErrorAction = "abort" // The signal cannot be executed as is
+	ErrorActionAlert ErrorAction = "alert" // Needs an operator, e.g. to fix credentials or add funds
+)
+
+// exchangeError wraps an error of the order manager, typing failures the
+// exchange classified as rate limiting, missing funds or rejected credentials
+func exchangeError(errType ExecutionErrorType, err error) *ExecutionError {
+	switch exchanges.CodeOf(err) {
+	case exchanges.ErrorCodeRateLimited:
+		errType = ExecutionErrorTypeRateLimited
+	case exchanges.ErrorCodeInsufficientFunds:
+		errType = ExecutionErrorTypeInsufficientFunds
+	case exchanges.ErrorCodeAuth:
+		errType = ExecutionErrorTypeAuthFailed
+	}
+	return &ExecutionError{
+		Type:    errType,
+		Message: err.Error(),
+		Err:     err,
+	}
+}
+
 // ExecutionErrorType defines the type of execution error
 type ExecutionErrorType int
 
@@ -198,4 +1165,42 @@ const (
 	ExecutionErrorTypeRiskValidationFailed
 	ExecutionErrorTypeOrderPlacementFailed
 	ExecutionErrorTypePositionCloseFailed
+	ExecutionErrorTypeRateLimited
+	ExecutionErrorTypeTradingWindowClosed
+	ExecutionErrorTypeEventPause
+	ExecutionErrorTypeInsufficientLiquidity
+	ExecutionErrorTypeInsufficientFunds
+	ExecutionErrorTypeAuthFailed
+	ExecutionErrorTypeNotApproved
 )
+
+func (t ExecutionErrorType) String() string {
+	switch t {
+	case ExecutionErrorTypeRiskCheckFailed:
+		return "risk_check_failed"
+	case ExecutionErrorTypeInvalidSignal:
+		return "invalid_signal"
+	case ExecutionErrorTypeRiskValidationFailed:
+		return "risk_validation_failed"
+	case ExecutionErrorTypeOrderPlacementFailed:
+		return "order_placement_failed"
+	case ExecutionErrorTypePositionCloseFailed:
+		return "position_close_failed"
+	case ExecutionErrorTypeRateLimited:
+		return "rate_limited"
+	case ExecutionErrorTypeTradingWindowClosed:
+		return "trading_window_closed"
+	case ExecutionErrorTypeEventPause:
+		return "event_pause"
+	case ExecutionErrorTypeInsufficientLiquidity:
+		return "insufficient_liquidity"
+	case ExecutionErrorTypeInsufficientFunds:
+		return "insufficient_funds"
+	case ExecutionErrorTypeAuthFailed:
+		return "auth_failed"
+	case ExecutionErrorTypeNotApproved:
+		return "not_approved"
+	default:
+		return "unknown"
+	}
+}
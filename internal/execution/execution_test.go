@@ -3,19 +3,25 @@ package execution
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/portfolio"
 	"github.com/guyghost/constantine/internal/strategy"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type mockOrderManager struct {
-	getPositionsFunc  func() []*order.ManagedPosition
-	placeOrderFunc    func(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error)
-	closePositionFunc func(ctx context.Context, symbol string) error
+	getPositionsFunc   func() []*order.ManagedPosition
+	openOrders         []*exchanges.Order
+	placeOrderFunc     func(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error)
+	closePositionFunc  func(ctx context.Context, symbol string) error
+	reducePositionFunc func(ctx context.Context, symbol string, fraction decimal.Decimal) (*exchanges.Order, error)
 }
 
 func (m *mockOrderManager) GetPositions() []*order.ManagedPosition {
@@ -25,6 +31,10 @@ func (m *mockOrderManager) GetPositions() []*order.ManagedPosition {
 	return nil
 }
 
+func (m *mockOrderManager) GetOpenOrders() []*exchanges.Order {
+	return m.openOrders
+}
+
 func (m *mockOrderManager) PlaceOrder(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
 	if m.placeOrderFunc != nil {
 		return m.placeOrderFunc(ctx, req)
@@ -39,6 +49,13 @@ func (m *mockOrderManager) ClosePosition(ctx context.Context, symbol string) err
 	return nil
 }
 
+func (m *mockOrderManager) ReducePosition(ctx context.Context, symbol string, fraction decimal.Decimal) (*exchanges.Order, error) {
+	if m.reducePositionFunc != nil {
+		return m.reducePositionFunc(ctx, symbol, fraction)
+	}
+	return nil, nil
+}
+
 type mockRiskManager struct {
 	canTradeFunc              func() (bool, string)
 	validateOrderFunc         func(req *order.OrderRequest, openPositions []*order.ManagedPosition) error
@@ -143,6 +160,21 @@ func TestCalculateTakeProfit_SellOrder(t *testing.T) {
 	assert.Equal(t, expected, takeProfit)
 }
 
+func TestCalculateProtection_TunedSignal(t *testing.T) {
+	agent := &ExecutionAgent{
+		config: DefaultConfig(),
+	}
+	signal := &strategy.Signal{
+		Side:              exchanges.OrderSideBuy,
+		Price:             decimal.NewFromFloat(50000),
+		StopLossPercent:   2,
+		TakeProfitPercent: 3,
+	}
+
+	assert.True(t, decimal.NewFromFloat(49000).Equal(agent.calculateStopLoss(signal)))
+	assert.True(t, decimal.NewFromFloat(51500).Equal(agent.calculateTakeProfit(signal)))
+}
+
 func TestExecutionError_Error(t *testing.T) {
 	err := &ExecutionError{
 		Type:    ExecutionErrorTypeOrderPlacementFailed,
@@ -158,6 +190,79 @@ func TestExecutionErrorTypes(t *testing.T) {
 	assert.Equal(t, ExecutionErrorType(2), ExecutionErrorTypeRiskValidationFailed)
 	assert.Equal(t, ExecutionErrorType(3), ExecutionErrorTypeOrderPlacementFailed)
 	assert.Equal(t, ExecutionErrorType(4), ExecutionErrorTypePositionCloseFailed)
+	assert.Equal(t, ExecutionErrorType(5), ExecutionErrorTypeRateLimited)
+	assert.Equal(t, ExecutionErrorType(6), ExecutionErrorTypeTradingWindowClosed)
+	assert.Equal(t, ExecutionErrorType(7), ExecutionErrorTypeEventPause)
+	assert.Equal(t, ExecutionErrorType(8), ExecutionErrorTypeInsufficientLiquidity)
+	assert.Equal(t, ExecutionErrorType(9), ExecutionErrorTypeInsufficientFunds)
+	assert.Equal(t, ExecutionErrorType(10), ExecutionErrorTypeAuthFailed)
+}
+
+func TestExecutionError_Action(t *testing.T) {
+	tests := []struct {
+		name     string
+		errType  ExecutionErrorType
+		err      error
+		want     ErrorAction
+		wantType ExecutionErrorType
+	}{
+		{"rate limited", ExecutionErrorTypeOrderPlacementFailed,
+			exchanges.NewError("test", exchanges.ErrorCodeRateLimited, "slow down", nil),
+			ErrorActionRetry, ExecutionErrorTypeRateLimited},
+		{"insufficient funds", ExecutionErrorTypeOrderPlacementFailed,
+			exchanges.NewError("test", exchanges.ErrorCodeInsufficientFunds, "no funds", nil),
+			ErrorActionAlert, ExecutionErrorTypeInsufficientFunds},
+		{"auth", ExecutionErrorTypeOrderPlacementFailed,
+			exchanges.NewError("test", exchanges.ErrorCodeAuth, "bad key", nil),
+			ErrorActionAlert, ExecutionErrorTypeAuthFailed},
+		{"invalid order", ExecutionErrorTypeOrderPlacementFailed,
+			exchanges.NewError("test", exchanges.ErrorCodeInvalidOrder, "too small", nil),
+			ErrorActionAbort, ExecutionErrorTypeOrderPlacementFailed},
+		{"network", ExecutionErrorTypeOrderPlacementFailed,
+			exchanges.NewError("test", exchanges.ErrorCodeNetwork, "timeout", nil),
+			ErrorActionRetry, ExecutionErrorTypeOrderPlacementFailed},
+		{"close refused", ExecutionErrorTypePositionCloseFailed,
+			exchanges.NewError("test", exchanges.ErrorCodeRejected, "refused", nil),
+			ErrorActionAlert, ExecutionErrorTypePositionCloseFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execErr := exchangeError(tt.errType, fmt.Errorf("failed to place order: %w", tt.err))
+			assert.Equal(t, tt.wantType, execErr.Type)
+			assert.Equal(t, tt.want, execErr.Action())
+			assert.ErrorIs(t, execErr, tt.err)
+		})
+	}
+}
+
+func TestHandleSignal_EntryInsufficientFunds(t *testing.T) {
+	agent := &ExecutionAgent{
+		orderManager: &mockOrderManager{
+			placeOrderFunc: func(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+				return nil, exchanges.NewError("test", exchanges.ErrorCodeInsufficientFunds, "insufficient balance", nil)
+			},
+		},
+		riskManager: &mockRiskManager{},
+		config: Config{
+			AutoExecute:       true,
+			StopLossPercent:   decimal.NewFromFloat(0.01),
+			TakeProfitPercent: decimal.NewFromFloat(0.02),
+		},
+	}
+
+	err := agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Side:     exchanges.OrderSideBuy,
+		Strength: 1,
+		Symbol:   "BTC-USD",
+		Price:    decimal.NewFromInt(50000),
+	})
+
+	var execErr *ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, ExecutionErrorTypeInsufficientFunds, execErr.Type)
+	assert.Equal(t, ErrorActionAlert, execErr.Action())
 }
 
 func TestHandleSignal_EntryRiskCheckFailure(t *testing.T) {
@@ -228,9 +333,20 @@ func TestHandleSignal_EntrySuccess(t *testing.T) {
 		Symbol:   "BTC-USD",
 	}
 
+	var journaledSignal *strategy.Signal
+	var journaledOrder *exchanges.Order
+	agent.SetEntryCallback(func(signal *strategy.Signal, placed *exchanges.Order, _ *Decision) {
+		journaledSignal = signal
+		journaledOrder = placed
+	})
+
 	err := agent.HandleSignal(context.Background(), signal)
 
 	assert.NoError(t, err)
+	assert.Same(t, signal, journaledSignal)
+	if assert.NotNil(t, journaledOrder) {
+		assert.Equal(t, "order-1", journaledOrder.ID)
+	}
 	if assert.NotNil(t, capturedRequest) {
 		assert.Equal(t, signal.Symbol, capturedRequest.Symbol)
 		assert.Equal(t, signal.Side, capturedRequest.Side)
@@ -241,6 +357,89 @@ func TestHandleSignal_EntrySuccess(t *testing.T) {
 	}
 }
 
+type mockRecordingRiskManager struct {
+	mockRiskManager
+	entries []string
+}
+
+func (m *mockRecordingRiskManager) RecordEntry(symbol string, at time.Time) {
+	m.entries = append(m.entries, symbol)
+}
+
+func TestHandleSignal_EntryRecordedWithRiskManager(t *testing.T) {
+	riskManager := &mockRecordingRiskManager{
+		mockRiskManager: mockRiskManager{
+			calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+				return decimal.NewFromFloat(0.1)
+			},
+		},
+	}
+	agent := &ExecutionAgent{
+		orderManager: &mockOrderManager{
+			placeOrderFunc: func(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+				return &exchanges.Order{ID: "order-1"}, nil
+			},
+		},
+		riskManager: riskManager,
+		config: Config{
+			AutoExecute:     true,
+			StopLossPercent: decimal.NewFromFloat(0.01),
+		},
+	}
+
+	signal := &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Strength: 1,
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Symbol:   "BTC-USD",
+	}
+
+	assert.NoError(t, agent.HandleSignal(context.Background(), signal))
+	assert.Equal(t, []string{"BTC-USD"}, riskManager.entries)
+}
+
+func TestHandleSignal_EntryTimeInForce(t *testing.T) {
+	now := time.Date(2024, 3, 12, 12, 0, 0, 0, time.UTC)
+	var capturedRequest *order.OrderRequest
+	agent := &ExecutionAgent{
+		orderManager: &mockOrderManager{
+			placeOrderFunc: func(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+				capturedRequest = req
+				return &exchanges.Order{ID: "order-1"}, nil
+			},
+		},
+		riskManager: &mockRiskManager{
+			calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+				return decimal.NewFromFloat(0.1)
+			},
+		},
+		config: Config{
+			AutoExecute:      true,
+			StopLossPercent:  decimal.NewFromFloat(0.01),
+			EntryTimeInForce: exchanges.TimeInForceGTD,
+			EntryPostOnly:    true,
+			EntryOrderTTL:    30 * time.Second,
+		},
+		now: func() time.Time { return now },
+	}
+
+	err := agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Strength: 1,
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Symbol:   "BTC-USD",
+	})
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, capturedRequest) {
+		assert.Equal(t, exchanges.TimeInForceGTD, capturedRequest.TimeInForce)
+		assert.True(t, capturedRequest.PostOnly)
+		assert.Equal(t, now.Add(30*time.Second), capturedRequest.ExpiresAt)
+	}
+}
+
 func TestHandleSignal_EntryValidationFailure(t *testing.T) {
 	validationErr := errors.New("validation failed")
 	agent := &ExecutionAgent{
@@ -286,6 +485,55 @@ func TestHandleSignal_EntryValidationFailure(t *testing.T) {
 	assert.Equal(t, validationErr.Error(), execErr.Message)
 }
 
+type mockEventRiskManager struct {
+	mockRiskManager
+	applyEventPolicyFunc func(req *order.OrderRequest) error
+}
+
+func (m *mockEventRiskManager) ApplyEventPolicy(req *order.OrderRequest) error {
+	return m.applyEventPolicyFunc(req)
+}
+
+func TestHandleSignal_EntryPausedByEvent(t *testing.T) {
+	validated := false
+	agent := &ExecutionAgent{
+		orderManager: &mockOrderManager{},
+		riskManager: &mockEventRiskManager{
+			mockRiskManager: mockRiskManager{
+				validateOrderFunc: func(req *order.OrderRequest, openPositions []*order.ManagedPosition) error {
+					validated = true
+					return nil
+				},
+				calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+					return decimal.NewFromFloat(0.1)
+				},
+			},
+			applyEventPolicyFunc: func(req *order.OrderRequest) error {
+				return errors.New("entries on BTC-USD paused around CPI")
+			},
+		},
+		config: Config{
+			AutoExecute:     true,
+			StopLossPercent: decimal.NewFromFloat(0.01),
+		},
+	}
+
+	signal := &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Strength: 1,
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Symbol:   "BTC-USD",
+	}
+
+	err := agent.HandleSignal(context.Background(), signal)
+
+	var execErr *ExecutionError
+	assert.ErrorAs(t, err, &execErr)
+	assert.Equal(t, ExecutionErrorTypeEventPause, execErr.Type)
+	assert.False(t, validated, "paused entries must not reach order validation")
+}
+
 func TestHandleSignal_ExitBypassesRiskCheck(t *testing.T) {
 	closed := false
 	agent := &ExecutionAgent{
@@ -318,6 +566,52 @@ func TestHandleSignal_ExitBypassesRiskCheck(t *testing.T) {
 	assert.True(t, closed)
 }
 
+// confirmingOrderManager confirms exit prices with confirm
+type confirmingOrderManager struct {
+	*mockOrderManager
+	confirm func(price decimal.Decimal) error
+}
+
+func (m *confirmingOrderManager) ConfirmExitPrice(ctx context.Context, symbol string, price decimal.Decimal) error {
+	return m.confirm(price)
+}
+
+func TestHandleSignal_ExitAwaitsPriceConfirmation(t *testing.T) {
+	closed := false
+	agent := &ExecutionAgent{
+		orderManager: &confirmingOrderManager{
+			mockOrderManager: &mockOrderManager{
+				closePositionFunc: func(ctx context.Context, symbol string) error {
+					closed = true
+					return nil
+				},
+			},
+			confirm: func(price decimal.Decimal) error {
+				if price.LessThan(decimal.NewFromInt(99)) {
+					return order.ErrExitUnconfirmed
+				}
+				return nil
+			},
+		},
+		riskManager: &mockRiskManager{},
+		config:      Config{AutoExecute: true},
+	}
+	var decisions []Decision
+	agent.SetDecisionCallback(func(decision Decision) { decisions = append(decisions, decision) })
+
+	exit := func(price int64) *strategy.Signal {
+		return &strategy.Signal{Type: strategy.SignalTypeExit, Strength: 1, Symbol: "BTC-USD", Price: decimal.NewFromInt(price)}
+	}
+
+	require.NoError(t, agent.HandleSignal(context.Background(), exit(90)))
+	assert.False(t, closed, "an unconfirmed exit should not close the position")
+	require.Len(t, decisions, 1)
+	assert.Equal(t, DecisionSkipped, decisions[0].Outcome)
+
+	require.NoError(t, agent.HandleSignal(context.Background(), exit(100)))
+	assert.True(t, closed)
+}
+
 func TestHandleSignal_ExitCloseError(t *testing.T) {
 	agent := &ExecutionAgent{
 		orderManager: &mockOrderManager{
@@ -343,3 +637,235 @@ func TestHandleSignal_ExitCloseError(t *testing.T) {
 	assert.Equal(t, ExecutionErrorTypePositionCloseFailed, execErr.Type)
 	assert.Equal(t, "close failed", execErr.Message)
 }
+
+func newScalingAgent(positions []*order.ManagedPosition, placed *[]*order.OrderRequest, config Config) *ExecutionAgent {
+	config.AutoExecute = true
+	config.StopLossPercent = decimal.NewFromFloat(0.01)
+	config.TakeProfitPercent = decimal.NewFromFloat(0.02)
+	return &ExecutionAgent{
+		orderManager: &mockOrderManager{
+			getPositionsFunc: func() []*order.ManagedPosition {
+				return positions
+			},
+			placeOrderFunc: func(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+				*placed = append(*placed, req)
+				return &exchanges.Order{ID: "order"}, nil
+			},
+		},
+		riskManager: &mockRiskManager{
+			calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+				return decimal.NewFromInt(1)
+			},
+		},
+		config: config,
+	}
+}
+
+func TestHandleSignal_AddOnDisabled(t *testing.T) {
+	var placed []*order.OrderRequest
+	positions := []*order.ManagedPosition{
+		{Symbol: "BTC-USD", Side: order.PositionSideLong, Status: order.PositionStatusOpen, EntryPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1)},
+	}
+	agent := newScalingAgent(positions, &placed, Config{})
+
+	err := agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type:   strategy.SignalTypeEntry,
+		Side:   exchanges.OrderSideBuy,
+		Price:  decimal.NewFromInt(110),
+		Symbol: "BTC-USD",
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, placed)
+}
+
+func TestHandleSignal_AddOnPyramiding(t *testing.T) {
+	var placed []*order.OrderRequest
+	positions := []*order.ManagedPosition{
+		{Symbol: "BTC-USD", Side: order.PositionSideLong, Status: order.PositionStatusOpen, EntryPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1)},
+	}
+	agent := newScalingAgent(positions, &placed, Config{
+		MaxAddOns:             2,
+		AddOnSizeFactor:       decimal.NewFromFloat(0.5),
+		AddOnMinProfitPercent: decimal.NewFromFloat(0.05),
+	})
+	signal := func(price int64) *strategy.Signal {
+		return &strategy.Signal{
+			Type:   strategy.SignalTypeEntry,
+			Side:   exchanges.OrderSideBuy,
+			Price:  decimal.NewFromInt(price),
+			Symbol: "BTC-USD",
+		}
+	}
+
+	// Not yet far enough in profit
+	assert.NoError(t, agent.HandleSignal(context.Background(), signal(102)))
+	assert.Empty(t, placed)
+
+	assert.NoError(t, agent.HandleSignal(context.Background(), signal(110)))
+	assert.NoError(t, agent.HandleSignal(context.Background(), signal(120)))
+	assert.NoError(t, agent.HandleSignal(context.Background(), signal(130)))
+
+	if assert.Len(t, placed, 2, "add-ons should stop at MaxAddOns") {
+		assert.True(t, placed[0].Amount.Equal(decimal.NewFromFloat(0.5)))
+		assert.True(t, placed[1].Amount.Equal(decimal.NewFromFloat(0.25)))
+	}
+}
+
+func TestHandleSignal_ExitScalesOut(t *testing.T) {
+	var reducedBy decimal.Decimal
+	agent := &ExecutionAgent{
+		orderManager: &mockOrderManager{
+			closePositionFunc: func(ctx context.Context, symbol string) error {
+				t.Fatalf("position should be reduced, not closed")
+				return nil
+			},
+			reducePositionFunc: func(ctx context.Context, symbol string, fraction decimal.Decimal) (*exchanges.Order, error) {
+				reducedBy = fraction
+				return &exchanges.Order{ID: "reduce"}, nil
+			},
+		},
+		riskManager: &mockRiskManager{},
+		config: Config{
+			AutoExecute:  true,
+			ExitFraction: decimal.NewFromFloat(0.5),
+		},
+	}
+
+	err := agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type:   strategy.SignalTypeExit,
+		Symbol: "BTC-USD",
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, reducedBy.Equal(decimal.NewFromFloat(0.5)))
+}
+
+func TestHandleSignal_EntrySizedFromStrategyCapital(t *testing.T) {
+	allocator, err := portfolio.NewAllocator(portfolio.AllocationFixed, map[string]float64{"main": 3, "breakout": 1})
+	require.NoError(t, err)
+	allocator.Rebalance(decimal.NewFromInt(1000))
+
+	var placed []*order.OrderRequest
+	var sizedFrom decimal.Decimal
+	agent := newScalingAgent(nil, &placed, Config{})
+	agent.riskManager = &mockRiskManager{
+		calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+			sizedFrom = accountBalance
+			return decimal.NewFromInt(1)
+		},
+	}
+	agent.SetCapitalAllocator(allocator)
+
+	err = agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Symbol:   "BTC-USD",
+		Strategy: "breakout",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, placed, 1)
+	assert.True(t, sizedFrom.Equal(decimal.NewFromInt(250)), "sized from %s", sizedFrom)
+	assert.Equal(t, "breakout", placed[0].Strategy)
+	owner, ok := allocator.Owner("BTC-USD")
+	assert.True(t, ok)
+	assert.Equal(t, "breakout", owner)
+}
+
+func TestHandleSignal_EntrySkipsSymbolHeldByAnotherStrategy(t *testing.T) {
+	allocator, err := portfolio.NewAllocator(portfolio.AllocationFixed, map[string]float64{"main": 1, "breakout": 1})
+	require.NoError(t, err)
+	allocator.Rebalance(decimal.NewFromInt(1000))
+	allocator.AssignPosition("BTC-USD", "breakout")
+
+	var placed []*order.OrderRequest
+	positions := []*order.ManagedPosition{
+		{Symbol: "BTC-USD", Side: order.PositionSideLong, Status: order.PositionStatusOpen, EntryPrice: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1)},
+	}
+	agent := newScalingAgent(positions, &placed, Config{
+		MaxAddOns:             1,
+		AddOnSizeFactor:       decimal.NewFromFloat(0.5),
+		AddOnMinProfitPercent: decimal.NewFromFloat(0.05),
+	})
+	agent.SetCapitalAllocator(allocator)
+
+	err = agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type:   strategy.SignalTypeEntry,
+		Side:   exchanges.OrderSideBuy,
+		Price:  decimal.NewFromInt(110),
+		Symbol: "BTC-USD",
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, placed, "the main strategy must not add to a position held by another strategy")
+}
+
+func TestHandleSignal_ExitSkipsPositionHeldByAnotherStrategy(t *testing.T) {
+	allocator, err := portfolio.NewAllocator(portfolio.AllocationFixed, map[string]float64{"main": 1, "breakout": 1})
+	require.NoError(t, err)
+	allocator.Rebalance(decimal.NewFromInt(1000))
+	allocator.AssignPosition("BTC-USD", "breakout")
+
+	var closed []string
+	agent := &ExecutionAgent{
+		orderManager: &mockOrderManager{
+			getPositionsFunc: func() []*order.ManagedPosition {
+				return []*order.ManagedPosition{
+					{Symbol: "BTC-USD", Side: order.PositionSideLong, Status: order.PositionStatusOpen, Amount: decimal.NewFromInt(1)},
+				}
+			},
+			closePositionFunc: func(ctx context.Context, symbol string) error {
+				closed = append(closed, symbol)
+				return nil
+			},
+		},
+		riskManager: &mockRiskManager{},
+		config:      Config{AutoExecute: true},
+	}
+	agent.SetCapitalAllocator(allocator)
+
+	err = agent.HandleSignal(context.Background(), &strategy.Signal{Type: strategy.SignalTypeExit, Symbol: "BTC-USD"})
+	assert.NoError(t, err)
+	assert.Empty(t, closed, "the main strategy must not exit a position held by another strategy")
+
+	err = agent.HandleSignal(context.Background(), &strategy.Signal{Type: strategy.SignalTypeExit, Symbol: "BTC-USD", Strategy: "breakout"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BTC-USD"}, closed, "the holding strategy should exit its position")
+}
+
+type stubGuard map[string]float64
+
+func (g stubGuard) SizeFactor(strategy, symbol string) (float64, string) {
+	factor, ok := g[strategy+"|"+symbol]
+	if !ok {
+		return 1, ""
+	}
+	return factor, "strategy " + strategy + " degraded"
+}
+
+func TestHandleSignal_PerformanceGuard(t *testing.T) {
+	var placed []*order.OrderRequest
+	agent := newScalingAgent(nil, &placed, Config{})
+	agent.SetPerformanceGuard(stubGuard{"main|BTC-USD": 0.5, "main|ETH-USD": 0})
+	var decisions []Decision
+	agent.SetDecisionCallback(func(decision Decision) { decisions = append(decisions, decision) })
+
+	entry := func(symbol string) *strategy.Signal {
+		return &strategy.Signal{Type: strategy.SignalTypeEntry, Side: exchanges.OrderSideBuy, Price: decimal.NewFromInt(100), Symbol: symbol}
+	}
+
+	require.NoError(t, agent.HandleSignal(context.Background(), entry("BTC-USD")))
+	require.Len(t, placed, 1)
+	assert.True(t, placed[0].Amount.Equal(decimal.NewFromFloat(0.5)), "throttled entries are sized down, got %s", placed[0].Amount)
+	assert.Equal(t, "strategy main degraded", decisions[0].Reason)
+
+	require.NoError(t, agent.HandleSignal(context.Background(), entry("ETH-USD")))
+	assert.Len(t, placed, 1, "disabled strategies place no entries")
+	assert.Equal(t, DecisionSkipped, decisions[1].Outcome)
+
+	require.NoError(t, agent.HandleSignal(context.Background(), entry("SOL-USD")))
+	require.Len(t, placed, 2)
+	assert.True(t, placed[1].Amount.Equal(decimal.NewFromInt(1)), "healthy strategies trade at full size")
+}
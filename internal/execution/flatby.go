@@ -0,0 +1,118 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// positionOwner returns the strategy holding the position on symbol, empty
+// for the main instance
+func (e *ExecutionAgent) positionOwner(symbol string) string {
+	e.mu.RLock()
+	allocator := e.allocator
+	e.mu.RUnlock()
+	if allocator == nil {
+		return ""
+	}
+	owner, _ := allocator.Owner(symbol)
+	return owner
+}
+
+// HasFlatBy reports whether the trading schedule flattens positions at some
+// time of day
+func (e *ExecutionAgent) HasFlatBy() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.schedule.HasFlatBy()
+}
+
+// FlatBy returns when the position on symbol is next flattened by the
+// trading schedule, or when it was if it is being flattened
+func (e *ExecutionAgent) FlatBy(symbol string) (time.Time, bool) {
+	e.mu.RLock()
+	schedule := e.schedule
+	e.mu.RUnlock()
+	return schedule.FlatBy(symbol, e.positionOwner(symbol), e.clock())
+}
+
+// EnforceFlatBy closes the positions that must be flat by the trading
+// schedule. The algorithms and resting entries on their symbols are canceled
+// first so nothing reopens them, then the positions are closed, their stop
+// loss and take profit orders being canceled once the close is placed.
+func (e *ExecutionAgent) EnforceFlatBy(ctx context.Context) error {
+	e.mu.RLock()
+	schedule := e.schedule
+	e.mu.RUnlock()
+	if !schedule.HasFlatBy() {
+		return nil
+	}
+
+	now := e.clock()
+	flat := make(map[string]string)
+	isFlat := func(symbol string) bool {
+		if _, checked := flat[symbol]; !checked {
+			_, reason := schedule.Flat(symbol, e.positionOwner(symbol), now)
+			flat[symbol] = reason
+		}
+		return flat[symbol] != ""
+	}
+
+	for _, algo := range e.AlgoOrders() {
+		if algo.Status == AlgoStatusRunning && isFlat(algo.Symbol) {
+			e.cancelSymbolAlgos(algo.Symbol)
+		}
+	}
+
+	var errs []error
+	if canceler, ok := e.orderManager.(OrderCanceler); ok {
+		for _, resting := range e.orderManager.GetOpenOrders() {
+			if resting.ReduceOnly || !isFlat(resting.Symbol) {
+				continue
+			}
+			if err := canceler.CancelOrder(ctx, resting.ID); err != nil {
+				errs = append(errs, fmt.Errorf("cancel %s: %w", resting.ID, err))
+			}
+		}
+	}
+
+	closed := make(map[string]bool)
+	for _, position := range e.orderManager.GetPositions() {
+		symbol := position.Symbol
+		if position.Status != order.PositionStatusOpen || closed[symbol] || !isFlat(symbol) {
+			continue
+		}
+		closed[symbol] = true
+		if err := e.orderManager.ClosePosition(ctx, symbol); err != nil {
+			telemetry.RecordError("flat_by_failed")
+			errs = append(errs, fmt.Errorf("close %s: %w", symbol, err))
+			continue
+		}
+		e.resetAddOns(symbol + "|" + string(order.PositionSideLong))
+		e.resetAddOns(symbol + "|" + string(order.PositionSideShort))
+		logger.Component("execution").Info("position flattened", "symbol", symbol, "reason", flat[symbol])
+	}
+	return errors.Join(errs...)
+}
+
+// RunFlatBy enforces the flat-by times of the trading schedule every
+// interval until ctx is done
+func (e *ExecutionAgent) RunFlatBy(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.EnforceFlatBy(ctx); err != nil {
+				logger.Component("execution").Warn("failed to flatten positions", "error", err)
+			}
+		}
+	}
+}
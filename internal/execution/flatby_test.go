@@ -0,0 +1,75 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnforceFlatBy(t *testing.T) {
+	orders := newAlgoOrderManager()
+	orders.open["entry-1"] = &exchanges.Order{ID: "entry-1", Symbol: "BTC-USD", Side: exchanges.OrderSideBuy}
+	orders.open["stop-1"] = &exchanges.Order{ID: "stop-1", Symbol: "BTC-USD", Side: exchanges.OrderSideSell, ReduceOnly: true}
+	orders.open["entry-2"] = &exchanges.Order{ID: "entry-2", Symbol: "EUR-USD", Side: exchanges.OrderSideBuy}
+	var closed []string
+	orders.getPositionsFunc = func() []*order.ManagedPosition {
+		return []*order.ManagedPosition{
+			{Symbol: "BTC-USD", Side: order.PositionSideLong, Amount: decimal.NewFromInt(1), Status: order.PositionStatusOpen},
+			{Symbol: "EUR-USD", Side: order.PositionSideLong, Amount: decimal.NewFromInt(1), Status: order.PositionStatusOpen},
+		}
+	}
+	orders.closePositionFunc = func(ctx context.Context, symbol string) error {
+		closed = append(closed, symbol)
+		return nil
+	}
+
+	now := time.Date(2024, 1, 3, 21, 0, 0, 0, time.UTC)
+	agent := &ExecutionAgent{
+		orderManager: orders,
+		riskManager:  &mockRiskManager{},
+		config:       Config{AutoExecute: true, MinSignalStrength: 0.1},
+		addOns:       map[string]int{"BTC-USD|long": 2},
+		now:          func() time.Time { return now },
+	}
+	flatBy := TimeOfDay(21*time.Hour + 55*time.Minute)
+	agent.SetSchedule(&TradingSchedule{
+		Default: SessionSchedule{FlatBy: &flatBy},
+		Symbols: map[string]SessionSchedule{"EUR-USD": {}},
+	})
+
+	require.NoError(t, agent.EnforceFlatBy(context.Background()))
+	assert.Empty(t, closed, "positions are kept until the flat-by time")
+	at, ok := agent.FlatBy("BTC-USD")
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(55*time.Minute), at)
+
+	now = now.Add(time.Hour)
+	require.NoError(t, agent.EnforceFlatBy(context.Background()))
+	assert.Equal(t, []string{"BTC-USD"}, closed)
+	assert.Equal(t, []string{"entry-1"}, orders.canceled, "resting entries should be canceled, exits kept")
+	assert.Empty(t, agent.addOns)
+
+	// Entries are refused until the end of the flat window
+	err := agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Symbol:   "BTC-USD",
+		Strength: 1,
+	})
+	var execErr *ExecutionError
+	require.ErrorAs(t, err, &execErr)
+	assert.Equal(t, ExecutionErrorTypeTradingWindowClosed, execErr.Type)
+	assert.Contains(t, execErr.Message, "flat by 21:55 UTC")
+
+	orders.closePositionFunc = func(ctx context.Context, symbol string) error {
+		return errors.New("exchange unavailable")
+	}
+	assert.Error(t, agent.EnforceFlatBy(context.Background()), "failed closes should be reported")
+}
@@ -0,0 +1,227 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// HoldingExit is how positions held past their maximum holding time exit
+type HoldingExit string
+
+const (
+	HoldingExitMarket HoldingExit = "market" // Close the position at market
+	// Post a reduce-only limit at the mid price, closing at market if it
+	// has not filled after HoldingExitTimeout
+	HoldingExitMid HoldingExit = "mid"
+)
+
+// holdingExit is a limit exit resting for a position held too long
+type holdingExit struct {
+	orderID  string
+	placedAt time.Time
+}
+
+// parseHoldingDurations parses maximum holding times by strategy written as
+// "name:seconds,name:seconds", skipping invalid entries
+func parseHoldingDurations(value string) map[string]time.Duration {
+	durations := make(map[string]time.Duration)
+	for _, entry := range strings.Split(value, ",") {
+		name, text, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		if seconds, err := strconv.Atoi(strings.TrimSpace(text)); err == nil && seconds >= 0 {
+			durations[name] = time.Duration(seconds) * time.Second
+		}
+	}
+	return durations
+}
+
+// maxHoldingFor returns how long the positions of strategy may be held, 0
+// when they are not limited
+func (c Config) maxHoldingFor(name string) time.Duration {
+	if name == "" {
+		name = strategy.MainInstance
+	}
+	if duration, ok := c.MaxHoldingStrategies[name]; ok {
+		return duration
+	}
+	return c.MaxHoldingDuration
+}
+
+// HasMaxHolding reports whether positions are exited after a maximum
+// holding time
+func (e *ExecutionAgent) HasMaxHolding() bool {
+	if e.config.MaxHoldingDuration > 0 {
+		return true
+	}
+	for _, duration := range e.config.MaxHoldingStrategies {
+		if duration > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// EnforceMaxHolding exits the open positions held longer than the maximum
+// holding time of their strategy, at market or with a limit at the mid price
+// as HoldingExit sets. A limit exit not filled within HoldingExitTimeout, or
+// that could not be priced, is replaced by a market close. Each exit is
+// recorded as a decision on an exit signal.
+func (e *ExecutionAgent) EnforceMaxHolding(ctx context.Context) error {
+	if !e.HasMaxHolding() {
+		return nil
+	}
+
+	now := e.clock()
+	resting := make(map[string]bool)
+	for _, open := range e.orderManager.GetOpenOrders() {
+		resting[open.ID] = true
+	}
+
+	open := make(map[string]bool)
+	var errs []error
+	for _, position := range e.orderManager.GetPositions() {
+		if position.Status != order.PositionStatusOpen || !position.Amount.IsPositive() {
+			continue
+		}
+		open[position.ID] = true
+		limit := e.config.maxHoldingFor(position.Strategy)
+		if limit <= 0 || now.Sub(position.EntryTime) < limit {
+			continue
+		}
+
+		e.mu.RLock()
+		pending, exiting := e.holdingExits[position.ID]
+		e.mu.RUnlock()
+		if exiting && resting[pending.orderID] && now.Sub(pending.placedAt) < e.config.HoldingExitTimeout {
+			continue
+		}
+
+		signal := &strategy.Signal{
+			Type:     strategy.SignalTypeExit,
+			Side:     exitSideOf(position.Side),
+			Symbol:   position.Symbol,
+			Price:    position.CurrentPrice,
+			Strategy: position.Strategy,
+			Strength: 1,
+			Reason:   fmt.Sprintf("held longer than %s", limit),
+		}
+		decision := newDecision(signal, now)
+		placed, err := e.exitHeldPosition(ctx, position, exiting, pending, decision)
+		decision.complete(placed, err)
+		e.recordDecision(decision)
+		if err != nil {
+			telemetry.RecordError("max_holding_failed")
+			errs = append(errs, fmt.Errorf("exit %s: %w", position.Symbol, err))
+			continue
+		}
+		logger.Component("execution").Info("position held too long",
+			"symbol", position.Symbol,
+			"strategy", position.Strategy,
+			"held", now.Sub(position.EntryTime).Round(time.Second).String(),
+			"exit", decision.Reason)
+	}
+
+	// Forget the positions closed since
+	e.mu.Lock()
+	for id := range e.holdingExits {
+		if !open[id] {
+			delete(e.holdingExits, id)
+		}
+	}
+	e.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// exitHeldPosition exits position, with a limit at the mid price the first
+// time when HoldingExit is mid, otherwise at market after canceling the
+// limit exit left by a previous attempt
+func (e *ExecutionAgent) exitHeldPosition(ctx context.Context, position *order.ManagedPosition, exiting bool, pending holdingExit, decision *Decision) (*exchanges.Order, error) {
+	if e.config.HoldingExit == HoldingExitMid && !exiting {
+		book, err := e.fetchBook(ctx, position.Symbol)
+		if err == nil {
+			mid := book.Bids[0].Price.Add(book.Asks[0].Price).Div(decimal.NewFromInt(2))
+			placed, err := e.orderManager.PlaceOrder(ctx, &order.OrderRequest{
+				Symbol:         position.Symbol,
+				Side:           exitSideOf(position.Side),
+				Type:           exchanges.OrderTypeLimit,
+				Price:          mid,
+				Amount:         position.Amount,
+				ReduceOnly:     true,
+				Strategy:       position.Strategy,
+				ReferencePrice: mid,
+			})
+			if err != nil {
+				return nil, exchangeError(ExecutionErrorTypeOrderPlacementFailed, err)
+			}
+			e.mu.Lock()
+			if e.holdingExits == nil {
+				e.holdingExits = make(map[string]holdingExit)
+			}
+			e.holdingExits[position.ID] = holdingExit{orderID: placed.ID, placedAt: e.clock()}
+			e.mu.Unlock()
+			decision.Price = mid
+			decision.Amount = position.Amount
+			decision.Reason = "limit exit at mid"
+			return placed, nil
+		}
+		// Without a book to price the exit at, it is closed at market
+		logger.Component("execution").Warn("no mid price for exit, closing at market", "symbol", position.Symbol, "error", err)
+	}
+
+	if exiting {
+		if canceler, ok := e.orderManager.(OrderCanceler); ok {
+			if err := canceler.CancelOrder(ctx, pending.orderID); err != nil && !errors.Is(err, exchanges.ErrOrderNotFound) {
+				return nil, exchangeError(ExecutionErrorTypePositionCloseFailed, err)
+			}
+		}
+	}
+	if err := e.orderManager.ClosePosition(ctx, position.Symbol); err != nil {
+		return nil, exchangeError(ExecutionErrorTypePositionCloseFailed, err)
+	}
+	e.resetAddOns(position.Symbol + "|" + string(position.Side))
+	e.mu.Lock()
+	delete(e.holdingExits, position.ID)
+	e.mu.Unlock()
+	decision.Amount = position.Amount
+	decision.Reason = "position closed at market"
+	return nil, nil
+}
+
+// exitSideOf returns the side of the order that closes a position on side
+func exitSideOf(side order.PositionSide) exchanges.OrderSide {
+	if side == order.PositionSideShort {
+		return exchanges.OrderSideBuy
+	}
+	return exchanges.OrderSideSell
+}
+
+// RunMaxHolding exits the positions held too long every interval until ctx
+// is done
+func (e *ExecutionAgent) RunMaxHolding(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.EnforceMaxHolding(ctx); err != nil {
+				logger.Component("execution").Warn("failed to exit positions held too long", "error", err)
+			}
+		}
+	}
+}
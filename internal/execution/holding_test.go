@@ -0,0 +1,114 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHoldingDurations(t *testing.T) {
+	durations := parseHoldingDurations("fast:300, slow : 3600,bad:x,:5,bare")
+
+	assert.Equal(t, map[string]time.Duration{"fast": 5 * time.Minute, "slow": time.Hour}, durations)
+	config := Config{MaxHoldingDuration: time.Minute, MaxHoldingStrategies: durations}
+	assert.Equal(t, 5*time.Minute, config.maxHoldingFor("fast"))
+	assert.Equal(t, time.Minute, config.maxHoldingFor(""))
+}
+
+func newHoldingAgent(orders *algoOrderManager, config Config, now *time.Time) *ExecutionAgent {
+	return &ExecutionAgent{
+		orderManager: orders,
+		riskManager:  &mockRiskManager{},
+		config:       config,
+		now:          func() time.Time { return *now },
+	}
+}
+
+func TestEnforceMaxHolding_Market(t *testing.T) {
+	opened := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	now := opened.Add(4 * time.Minute)
+	orders := newAlgoOrderManager()
+	orders.getPositionsFunc = func() []*order.ManagedPosition {
+		return []*order.ManagedPosition{
+			{ID: "pos-1", Symbol: "BTC-USD", Side: order.PositionSideLong, Strategy: "fast", Amount: decimal.NewFromInt(1), EntryTime: opened, Status: order.PositionStatusOpen},
+			{ID: "pos-2", Symbol: "ETH-USD", Side: order.PositionSideLong, Strategy: "slow", Amount: decimal.NewFromInt(1), EntryTime: opened, Status: order.PositionStatusOpen},
+		}
+	}
+	var closed []string
+	orders.closePositionFunc = func(ctx context.Context, symbol string) error {
+		closed = append(closed, symbol)
+		return nil
+	}
+	agent := newHoldingAgent(orders, Config{
+		MaxHoldingStrategies: map[string]time.Duration{"fast": 5 * time.Minute},
+		HoldingExit:          HoldingExitMarket,
+	}, &now)
+	var decisions []Decision
+	agent.SetDecisionCallback(func(decision Decision) { decisions = append(decisions, decision) })
+
+	require.NoError(t, agent.EnforceMaxHolding(context.Background()))
+	assert.Empty(t, closed, "positions are kept within their holding time")
+
+	now = opened.Add(5 * time.Minute)
+	require.NoError(t, agent.EnforceMaxHolding(context.Background()))
+	assert.Equal(t, []string{"BTC-USD"}, closed, "only strategies with a holding time are exited")
+	require.Len(t, decisions, 1)
+	assert.Equal(t, strategy.SignalTypeExit, decisions[0].Type)
+	assert.Equal(t, exchanges.OrderSideSell, decisions[0].Side)
+	assert.Equal(t, DecisionExecuted, decisions[0].Outcome)
+	assert.Equal(t, "position closed at market", decisions[0].Reason)
+}
+
+func TestEnforceMaxHolding_MidThenMarket(t *testing.T) {
+	opened := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	now := opened.Add(time.Hour)
+	orders := newAlgoOrderManager()
+	position := &order.ManagedPosition{ID: "pos-1", Symbol: "BTC-USD", Side: order.PositionSideShort, Amount: decimal.NewFromInt(2), EntryTime: opened, Status: order.PositionStatusOpen}
+	orders.getPositionsFunc = func() []*order.ManagedPosition { return []*order.ManagedPosition{position} }
+	var closed []string
+	orders.closePositionFunc = func(ctx context.Context, symbol string) error {
+		closed = append(closed, symbol)
+		return nil
+	}
+	agent := newHoldingAgent(orders, Config{
+		MaxHoldingDuration: time.Minute,
+		HoldingExit:        HoldingExitMid,
+		HoldingExitTimeout: 30 * time.Second,
+	}, &now)
+	agent.SetOrderBookSource(func(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error) {
+		return &exchanges.OrderBook{
+			Bids: []exchanges.Level{{Price: decimal.NewFromInt(99), Amount: decimal.NewFromInt(5)}},
+			Asks: []exchanges.Level{{Price: decimal.NewFromInt(101), Amount: decimal.NewFromInt(5)}},
+		}, nil
+	})
+
+	require.NoError(t, agent.EnforceMaxHolding(context.Background()))
+	require.Len(t, orders.open, 1)
+	exit := orders.open["child-1"]
+	require.NotNil(t, exit)
+	assert.True(t, exit.Price.Equal(decimal.NewFromInt(100)), "exit should rest at the mid price")
+	assert.True(t, exit.Amount.Equal(decimal.NewFromInt(2)), "exit should cover the position")
+	assert.Empty(t, closed)
+
+	// The limit exit rests until its timeout
+	now = now.Add(10 * time.Second)
+	require.NoError(t, agent.EnforceMaxHolding(context.Background()))
+	assert.Equal(t, []decimal.Decimal{decimal.NewFromInt(2)}, orders.placedAmounts(), "exit should not be placed twice")
+
+	now = now.Add(30 * time.Second)
+	require.NoError(t, agent.EnforceMaxHolding(context.Background()))
+	assert.Equal(t, []string{"child-1"}, orders.canceled, "unfilled exit should be canceled")
+	assert.Equal(t, []string{"BTC-USD"}, closed, "position should be closed at market")
+
+	// Positions closed since are forgotten
+	orders.getPositionsFunc = func() []*order.ManagedPosition { return nil }
+	require.NoError(t, agent.EnforceMaxHolding(context.Background()))
+	assert.Empty(t, agent.holdingExits)
+}
@@ -0,0 +1,78 @@
+package execution
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// TakeProfitRung is a level of the take profit ladder of entries: Fraction of
+// the position is taken Percent away from the entry price
+type TakeProfitRung struct {
+	Percent  decimal.Decimal // e.g., 0.005 for 0.5%
+	Fraction decimal.Decimal // e.g., 0.5 for half the position
+}
+
+// parseTakeProfitLadder parses take profit rungs written as
+// "percent:fraction,percent:fraction", nearest first. It returns false when
+// an entry is invalid or the fractions take more than the whole position.
+func parseTakeProfitLadder(value string) ([]TakeProfitRung, bool) {
+	var rungs []TakeProfitRung
+	total := decimal.Zero
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		percentText, fractionText, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, false
+		}
+		percent, err := decimal.NewFromString(strings.TrimSpace(percentText))
+		if err != nil || !percent.IsPositive() {
+			return nil, false
+		}
+		fraction, err := decimal.NewFromString(strings.TrimSpace(fractionText))
+		if err != nil || !fraction.IsPositive() {
+			return nil, false
+		}
+		total = total.Add(fraction)
+		rungs = append(rungs, TakeProfitRung{Percent: percent, Fraction: fraction})
+	}
+	if len(rungs) == 0 || total.GreaterThan(decimal.NewFromInt(1)) {
+		return nil, false
+	}
+	sort.SliceStable(rungs, func(i, j int) bool { return rungs[i].Percent.LessThan(rungs[j].Percent) })
+	return rungs, true
+}
+
+// takeProfitLadder returns the take profit levels of an entry at price on
+// side, nearest first, or nil when no ladder is configured
+func (c Config) takeProfitLadder(side exchanges.OrderSide, price decimal.Decimal) []order.TakeProfitLevel {
+	if len(c.TakeProfitLadder) == 0 {
+		return nil
+	}
+	levels := make([]order.TakeProfitLevel, len(c.TakeProfitLadder))
+	one := decimal.NewFromInt(1)
+	for i, rung := range c.TakeProfitLadder {
+		target := price.Mul(one.Add(rung.Percent))
+		if side == exchanges.OrderSideSell {
+			target = price.Mul(one.Sub(rung.Percent))
+		}
+		levels[i] = order.TakeProfitLevel{Price: target, Fraction: rung.Fraction}
+	}
+	return levels
+}
+
+// widenLadder moves the levels of the ladder of req nearer than its take
+// profit out to it
+func widenLadder(req *order.OrderRequest) {
+	for i, level := range req.TakeProfitLadder {
+		if req.Side == exchanges.OrderSideBuy && level.Price.LessThan(req.TakeProfit) ||
+			req.Side == exchanges.OrderSideSell && level.Price.GreaterThan(req.TakeProfit) {
+			req.TakeProfitLadder[i].Price = req.TakeProfit
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTakeProfitLadder(t *testing.T) {
+	rungs, ok := parseTakeProfitLadder("0.01:0.3, 0.005 : 0.5")
+	require.True(t, ok)
+	require.Len(t, rungs, 2)
+	assert.True(t, rungs[0].Percent.Equal(decimal.NewFromFloat(0.005)), "nearest rung first")
+	assert.True(t, rungs[0].Fraction.Equal(decimal.NewFromFloat(0.5)))
+	assert.True(t, rungs[1].Percent.Equal(decimal.NewFromFloat(0.01)))
+
+	for _, value := range []string{"", "0.005", "0.005:0.7,0.01:0.5", "-0.01:0.5", "0.01:zero"} {
+		_, ok := parseTakeProfitLadder(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestHandleSignal_TakeProfitLadder(t *testing.T) {
+	var placed []*order.OrderRequest
+	agent := newScalingAgent(nil, &placed, Config{
+		TakeProfitLadder: []TakeProfitRung{
+			{Percent: decimal.NewFromFloat(0.005), Fraction: decimal.NewFromFloat(0.5)},
+			{Percent: decimal.NewFromFloat(0.01), Fraction: decimal.NewFromFloat(0.3)},
+		},
+		TrailingStopPercent: decimal.NewFromFloat(0.004),
+	})
+
+	require.NoError(t, agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type: strategy.SignalTypeEntry, Side: exchanges.OrderSideSell, Price: decimal.NewFromInt(100), Symbol: "BTC-USD", Strength: 1,
+	}))
+	require.Len(t, placed, 1)
+	req := placed[0]
+	require.Len(t, req.TakeProfitLadder, 2)
+	assert.True(t, req.TakeProfitLadder[0].Price.Equal(decimal.NewFromFloat(99.5)), "first rung %s", req.TakeProfitLadder[0].Price)
+	assert.True(t, req.TakeProfitLadder[1].Price.Equal(decimal.NewFromInt(99)), "second rung %s", req.TakeProfitLadder[1].Price)
+	assert.True(t, req.TakeProfit.Equal(decimal.NewFromFloat(99.5)), "nearest rung stands for the take profit")
+	assert.True(t, req.TrailingStop.Equal(decimal.NewFromFloat(0.004)))
+	assert.Equal(t, 4, protectedOrderCount(req))
+}
+
+func TestHandleSignal_CostPolicyWidensLadder(t *testing.T) {
+	var placed []*order.OrderRequest
+	agent := newScalingAgent(nil, &placed, Config{
+		TakeProfitLadder: []TakeProfitRung{
+			{Percent: decimal.NewFromFloat(0.001), Fraction: decimal.NewFromFloat(0.5)},
+			{Percent: decimal.NewFromFloat(0.01), Fraction: decimal.NewFromFloat(0.5)},
+		},
+		CostPolicy:         CostPolicyWiden,
+		TakerFeeBps:        5,
+		MinNetProfitBps:    5,
+		CostMaxWidenFactor: 2,
+	})
+
+	require.NoError(t, agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type: strategy.SignalTypeEntry, Side: exchanges.OrderSideBuy, Price: decimal.NewFromInt(100), Symbol: "BTC-USD", Strength: 1,
+	}))
+	require.Len(t, placed, 1)
+	// 10 bps of fees and 5 of net profit push the first rung out, the
+	// second clears them
+	ladder := placed[0].TakeProfitLadder
+	assert.True(t, ladder[0].Price.Equal(decimal.NewFromFloat(100.15)), "first rung %s", ladder[0].Price)
+	assert.True(t, ladder[1].Price.Equal(decimal.NewFromInt(101)), "second rung %s", ladder[1].Price)
+}
@@ -0,0 +1,145 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// OrderBookSource fetches the order book of a symbol
+type OrderBookSource func(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error)
+
+// SetOrderBookSource enables the spread and liquidity guard on entries using
+// the order books fetched from source
+func (e *ExecutionAgent) SetOrderBookSource(source OrderBookSource) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.orderBooks = source
+}
+
+// checkLiquidity rejects entries when the spread is wider than MaxSpreadBps
+// or the visible depth within MaxSlippageBps of the best price cannot fill
+// the order, or its largest slice when an execution algorithm works it. It
+// returns the order book it checked, if one was fetched, for
+// the execution algorithms to size against.
+func (e *ExecutionAgent) checkLiquidity(ctx context.Context, req *order.OrderRequest) (*exchanges.OrderBook, error) {
+	if e.config.MaxSpreadBps <= 0 && e.config.MaxSlippageBps <= 0 && !e.algoEnabled() && !e.chaseEnabled() && !e.makerFirstEnabled() {
+		return nil, nil
+	}
+
+	e.mu.RLock()
+	source := e.orderBooks
+	e.mu.RUnlock()
+	if source == nil {
+		return nil, nil
+	}
+
+	book, err := e.fetchBook(ctx, req.Symbol)
+	if err != nil {
+		return nil, e.liquidityError(req.Symbol, err.Error())
+	}
+
+	if e.config.MaxSpreadBps > 0 {
+		if spread := spreadBps(book); spread > e.config.MaxSpreadBps {
+			return nil, e.liquidityError(req.Symbol, fmt.Sprintf("%s spread %.1f bps exceeds %.1f bps", req.Symbol, spread, e.config.MaxSpreadBps))
+		}
+	}
+
+	if e.config.MaxSlippageBps > 0 {
+		// A sliced order only takes one slice from the book at a time
+		amount := req.Amount
+		if e.useAlgo(req, book) {
+			slices := e.planSlices(req.Amount)
+			amount = decimal.Max(slices[0], slices[1:]...)
+		}
+		available := depthWithin(book, req.Side, e.config.MaxSlippageBps)
+		if available.LessThan(amount) {
+			return nil, e.liquidityError(req.Symbol, fmt.Sprintf("%s depth %s within %.1f bps cannot absorb %s",
+				req.Symbol, available.String(), e.config.MaxSlippageBps, amount.String()))
+		}
+	}
+	return book, nil
+}
+
+// fetchBook fetches the order book of symbol, failing when it has no bids or asks
+func (e *ExecutionAgent) fetchBook(ctx context.Context, symbol string) (*exchanges.OrderBook, error) {
+	e.mu.RLock()
+	source := e.orderBooks
+	e.mu.RUnlock()
+	if source == nil {
+		return nil, fmt.Errorf("no order book source for %s", symbol)
+	}
+
+	book, err := source(ctx, symbol, e.config.BookDepth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch order book for %s: %w", symbol, err)
+	}
+	if book == nil || len(book.Bids) == 0 || len(book.Asks) == 0 {
+		return nil, fmt.Errorf("order book for %s is empty", symbol)
+	}
+	return book, nil
+}
+
+func (e *ExecutionAgent) liquidityError(symbol, message string) error {
+	telemetry.RecordSignalBlocked(symbol, "liquidity")
+	return &ExecutionError{
+		Type:    ExecutionErrorTypeInsufficientLiquidity,
+		Message: message,
+	}
+}
+
+// visibleDepth returns the amount resting on the side of the book an order
+// on side trades against
+func visibleDepth(book *exchanges.OrderBook, side exchanges.OrderSide) decimal.Decimal {
+	levels := book.Asks
+	if side == exchanges.OrderSideSell {
+		levels = book.Bids
+	}
+	depth := decimal.Zero
+	for _, level := range levels {
+		depth = depth.Add(level.Amount)
+	}
+	return depth
+}
+
+// spreadBps returns the spread between the best bid and ask in basis points
+// of the mid price
+func spreadBps(book *exchanges.OrderBook) float64 {
+	bid := book.Bids[0].Price
+	ask := book.Asks[0].Price
+	mid := bid.Add(ask).Div(decimal.NewFromInt(2))
+	if !mid.IsPositive() {
+		return 0
+	}
+	return ask.Sub(bid).Div(mid).InexactFloat64() * 10000
+}
+
+// depthWithin returns the amount an order on side can take from the book
+// without trading further than maxBps from the best opposite price
+func depthWithin(book *exchanges.OrderBook, side exchanges.OrderSide, maxBps float64) decimal.Decimal {
+	levels := book.Asks
+	if side == exchanges.OrderSideSell {
+		levels = book.Bids
+	}
+
+	best := levels[0].Price
+	offset := best.Mul(decimal.NewFromFloat(maxBps / 10000))
+	limit := best.Add(offset)
+	if side == exchanges.OrderSideSell {
+		limit = best.Sub(offset)
+	}
+
+	depth := decimal.Zero
+	for _, level := range levels {
+		if (side == exchanges.OrderSideSell && level.Price.LessThan(limit)) ||
+			(side != exchanges.OrderSideSell && level.Price.GreaterThan(limit)) {
+			break
+		}
+		depth = depth.Add(level.Amount)
+	}
+	return depth
+}
@@ -0,0 +1,122 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func liquidityTestBook() *exchanges.OrderBook {
+	level := func(price, amount float64) exchanges.Level {
+		return exchanges.Level{Price: decimal.NewFromFloat(price), Amount: decimal.NewFromFloat(amount)}
+	}
+	return &exchanges.OrderBook{
+		Symbol: "BTC-USD",
+		Bids:   []exchanges.Level{level(99.99, 1), level(99.95, 2), level(99.5, 10)},
+		Asks:   []exchanges.Level{level(100.01, 1), level(100.05, 2), level(100.5, 10)},
+	}
+}
+
+func TestSpreadBps(t *testing.T) {
+	assert.InDelta(t, 2.0, spreadBps(liquidityTestBook()), 0.01)
+}
+
+func TestDepthWithin(t *testing.T) {
+	book := liquidityTestBook()
+
+	assert.True(t, decimal.NewFromInt(3).Equal(depthWithin(book, exchanges.OrderSideBuy, 10)))
+	assert.True(t, decimal.NewFromInt(13).Equal(depthWithin(book, exchanges.OrderSideBuy, 100)))
+	assert.True(t, decimal.NewFromInt(3).Equal(depthWithin(book, exchanges.OrderSideSell, 10)))
+	assert.True(t, decimal.NewFromInt(1).Equal(depthWithin(book, exchanges.OrderSideSell, 1)))
+}
+
+func TestCheckLiquidity(t *testing.T) {
+	tests := []struct {
+		name      string
+		spread    float64
+		slippage  float64
+		amount    float64
+		bookErr   error
+		wantError bool
+	}{
+		{name: "disabled", amount: 100},
+		{name: "spread within limit", spread: 5, amount: 1},
+		{name: "spread too wide", spread: 1, amount: 1, wantError: true},
+		{name: "depth absorbs order", slippage: 10, amount: 3},
+		{name: "depth too thin", slippage: 10, amount: 5, wantError: true},
+		{name: "book unavailable", spread: 5, amount: 1, bookErr: errors.New("timeout"), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &ExecutionAgent{
+				config: Config{MaxSpreadBps: tt.spread, MaxSlippageBps: tt.slippage, BookDepth: 20},
+			}
+			agent.SetOrderBookSource(func(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error) {
+				if tt.bookErr != nil {
+					return nil, tt.bookErr
+				}
+				return liquidityTestBook(), nil
+			})
+
+			_, err := agent.checkLiquidity(context.Background(), &order.OrderRequest{
+				Symbol: "BTC-USD",
+				Side:   exchanges.OrderSideBuy,
+				Amount: decimal.NewFromFloat(tt.amount),
+			})
+			if !tt.wantError {
+				assert.NoError(t, err)
+				return
+			}
+			var execErr *ExecutionError
+			if assert.ErrorAs(t, err, &execErr) {
+				assert.Equal(t, ExecutionErrorTypeInsufficientLiquidity, execErr.Type)
+			}
+		})
+	}
+}
+
+func TestHandleSignal_EntryRejectedOnThinBook(t *testing.T) {
+	placed := false
+	agent := &ExecutionAgent{
+		orderManager: &mockOrderManager{
+			placeOrderFunc: func(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+				placed = true
+				return &exchanges.Order{ID: "order-1"}, nil
+			},
+		},
+		riskManager: &mockRiskManager{
+			calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+				return decimal.NewFromInt(50)
+			},
+		},
+		config: Config{
+			AutoExecute:     true,
+			StopLossPercent: decimal.NewFromFloat(0.01),
+			MaxSlippageBps:  20,
+		},
+	}
+	agent.SetOrderBookSource(func(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error) {
+		return liquidityTestBook(), nil
+	})
+
+	err := agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Strength: 1,
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Symbol:   "BTC-USD",
+	})
+
+	var execErr *ExecutionError
+	if assert.ErrorAs(t, err, &execErr) {
+		assert.Equal(t, ExecutionErrorTypeInsufficientLiquidity, execErr.Type)
+	}
+	assert.False(t, placed)
+}
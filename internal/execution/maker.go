@@ -0,0 +1,191 @@
+package execution
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// MakerFirst selects whether entries are first posted at the touch, and what
+// becomes of those that do not fill there
+type MakerFirst string
+
+const (
+	MakerFirstOff     MakerFirst = "off"
+	MakerFirstMarket  MakerFirst = "market"  // Cross the spread with a market order
+	MakerFirstAbandon MakerFirst = "abandon" // Give the entry up
+)
+
+// parseMakerFirst parses a maker-first mode, case-insensitively
+func parseMakerFirst(value string) (MakerFirst, bool) {
+	switch mode := MakerFirst(strings.ToLower(strings.TrimSpace(value))); mode {
+	case MakerFirstOff, MakerFirstMarket, MakerFirstAbandon:
+		return mode, true
+	}
+	return "", false
+}
+
+// parseMakerFirstStrategies parses maker-first modes by strategy written as
+// "name:mode,name:mode", skipping invalid entries
+func parseMakerFirstStrategies(value string) map[string]MakerFirst {
+	modes := make(map[string]MakerFirst)
+	for _, entry := range strings.Split(value, ",") {
+		name, text, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+		if mode, ok := parseMakerFirst(text); ok {
+			modes[name] = mode
+		}
+	}
+	return modes
+}
+
+// makerFirstFor returns the maker-first mode of the entries of strategy
+func (c Config) makerFirstFor(strategy string) MakerFirst {
+	if mode, ok := c.MakerFirstStrategies[strategy]; ok {
+		return mode
+	}
+	return c.MakerFirst
+}
+
+// makerFirstEnabled reports whether the entries of any strategy are posted
+// at the touch first
+func (e *ExecutionAgent) makerFirstEnabled() bool {
+	if e.config.MakerFirst == MakerFirstMarket || e.config.MakerFirst == MakerFirstAbandon {
+		return true
+	}
+	for _, mode := range e.config.MakerFirstStrategies {
+		if mode == MakerFirstMarket || mode == MakerFirstAbandon {
+			return true
+		}
+	}
+	return false
+}
+
+// touchPrice returns the best price on the own side of the book: the bid for
+// buys, the ask for sells
+func touchPrice(book *exchanges.OrderBook, side exchanges.OrderSide) decimal.Decimal {
+	if side == exchanges.OrderSideSell {
+		return book.Asks[0].Price
+	}
+	return book.Bids[0].Price
+}
+
+// driftBps returns how far, in basis points, the touch has moved away from
+// an order on side resting at price; negative when it moved toward it
+func driftBps(book *exchanges.OrderBook, side exchanges.OrderSide, price decimal.Decimal) float64 {
+	diff := touchPrice(book, side).Sub(price)
+	if side == exchanges.OrderSideSell {
+		diff = diff.Neg()
+	}
+	return diff.Div(price).InexactFloat64() * 10000
+}
+
+// startMakerFirst posts req at the touch and falls back to mode once it has
+// not filled within MakerTimeout or the touch moves MakerMaxDriftBps away
+func (e *ExecutionAgent) startMakerFirst(ctx context.Context, req *order.OrderRequest, book *exchanges.OrderBook, mode MakerFirst) (*exchanges.Order, error) {
+	post := *req
+	post.Price = touchPrice(book, req.Side)
+	post.PostOnly = true
+	if post.TimeInForce == exchanges.TimeInForceIOC || post.TimeInForce == exchanges.TimeInForceFOK {
+		post.TimeInForce = exchanges.TimeInForceGTC
+	}
+
+	run := e.newAlgoRun(AlgoMaker, &post, 1)
+	placed, err := e.placeSlice(ctx, run, &post, post.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx := e.registerAlgo(ctx, run)
+	go e.workMakerFirst(runCtx, run, &post, mode, placed)
+	return placed, nil
+}
+
+// workMakerFirst watches the posted entry until it fills, times out or the
+// touch moves away, then cancels its remainder and crosses the spread with
+// it or abandons it. Canceling the run pulls the posted order.
+func (e *ExecutionAgent) workMakerFirst(ctx context.Context, run *algoRun, post *order.OrderRequest, mode MakerFirst, current *exchanges.Order) {
+	defer run.cancel()
+
+	poll := e.config.AlgoPollInterval
+	if poll <= 0 {
+		poll = time.Second
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	timeout := time.NewTimer(e.config.MakerTimeout)
+	defer timeout.Stop()
+
+	canceler, canCancel := e.orderManager.(OrderCanceler)
+	reason := ""
+	for reason == "" {
+		select {
+		case <-ctx.Done():
+			if canCancel && e.isOpen(current.ID) {
+				_ = canceler.CancelOrder(context.WithoutCancel(ctx), current.ID)
+			}
+			e.finishAlgo(run, AlgoStatusCanceled, nil)
+			return
+		case <-timeout.C:
+			reason = "timeout"
+		case <-ticker.C:
+			if !e.isOpen(current.ID) {
+				e.finishAlgo(run, AlgoStatusCompleted, nil)
+				return
+			}
+			if e.config.MakerMaxDriftBps <= 0 {
+				continue
+			}
+			book, err := e.fetchBook(ctx, post.Symbol)
+			if err == nil && driftBps(book, post.Side, post.Price) > e.config.MakerMaxDriftBps {
+				reason = "price moved away"
+			}
+		}
+	}
+
+	open := e.openOrder(current.ID)
+	if open == nil || !canCancel {
+		// Filled in the meantime, or left resting
+		e.finishAlgo(run, AlgoStatusCompleted, nil)
+		return
+	}
+	if err := canceler.CancelOrder(ctx, current.ID); err != nil {
+		// Most likely filled in the meantime
+		e.finishAlgo(run, AlgoStatusCompleted, nil)
+		return
+	}
+	remaining := open.Amount.Sub(decimal.Max(open.Filled, open.FilledAmount))
+	e.mu.Lock()
+	run.order.Submitted = run.order.Submitted.Sub(remaining)
+	e.mu.Unlock()
+
+	logger.Component("execution").Info("maker entry not filled",
+		"algo", run.order.ID,
+		"symbol", post.Symbol,
+		"reason", reason,
+		"fallback", mode,
+		"remaining", remaining.String())
+	if mode != MakerFirstMarket || !remaining.IsPositive() {
+		e.finishAlgo(run, AlgoStatusCanceled, nil)
+		return
+	}
+
+	cross := *post
+	cross.Type = exchanges.OrderTypeMarket
+	cross.Price = decimal.Zero
+	cross.PostOnly = false
+	cross.TimeInForce = ""
+	if _, err := e.placeSlice(ctx, run, &cross, remaining); err != nil {
+		e.finishAlgo(run, AlgoStatusFailed, err)
+		return
+	}
+	e.finishAlgo(run, AlgoStatusCompleted, nil)
+}
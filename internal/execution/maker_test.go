@@ -0,0 +1,153 @@
+package execution
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makerOrderManager records the requests of the orders placed
+type makerOrderManager struct {
+	*algoOrderManager
+
+	requests []order.OrderRequest
+}
+
+func (m *makerOrderManager) PlaceOrder(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+	m.mu.Lock()
+	m.requests = append(m.requests, *req)
+	m.mu.Unlock()
+	return m.algoOrderManager.PlaceOrder(ctx, req)
+}
+
+func (m *makerOrderManager) placedRequests() []order.OrderRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]order.OrderRequest(nil), m.requests...)
+}
+
+// newMakerAgent returns an agent posting entries at the touch of a book
+// whose bid the test moves
+func newMakerAgent(orders *makerOrderManager, config Config) (*ExecutionAgent, func(bid float64)) {
+	var mu sync.Mutex
+	bid := 99.9
+
+	config.AutoExecute = true
+	config.StopLossPercent = decimal.NewFromFloat(0.01)
+	config.AlgoPollInterval = time.Millisecond
+	agent := &ExecutionAgent{
+		orderManager: orders,
+		riskManager: &mockRiskManager{
+			calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+				return decimal.NewFromInt(1)
+			},
+		},
+		config: config,
+	}
+	agent.SetOrderBookSource(func(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return chaseTestBook(bid, bid+0.2), nil
+	})
+	return agent, func(price float64) {
+		mu.Lock()
+		defer mu.Unlock()
+		bid = price
+	}
+}
+
+func TestParseMakerFirstStrategies(t *testing.T) {
+	modes := parseMakerFirstStrategies("fast:abandon, main : MARKET,slow:maybe,:off,bare")
+
+	assert.Equal(t, map[string]MakerFirst{"fast": MakerFirstAbandon, "main": MakerFirstMarket}, modes)
+	assert.Equal(t, MakerFirstAbandon, Config{MakerFirst: MakerFirstMarket, MakerFirstStrategies: modes}.makerFirstFor("fast"))
+	assert.Equal(t, MakerFirstOff, Config{MakerFirst: MakerFirstOff, MakerFirstStrategies: modes}.makerFirstFor("slow"))
+}
+
+func TestHandleSignal_MakerFirstCrossesAfterTimeout(t *testing.T) {
+	orders := &makerOrderManager{algoOrderManager: newAlgoOrderManager()}
+	agent, _ := newMakerAgent(orders, Config{
+		MakerFirst:   MakerFirstMarket,
+		MakerTimeout: 20 * time.Millisecond,
+	})
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+	progress := waitForAlgo(t, agent, AlgoStatusCompleted)
+	assert.Equal(t, AlgoMaker, progress.Type)
+	assert.True(t, decimal.NewFromInt(1).Equal(progress.Submitted))
+
+	requests := orders.placedRequests()
+	require.Len(t, requests, 2)
+	assert.True(t, requests[0].PostOnly)
+	assert.True(t, decimal.NewFromFloat(99.9).Equal(requests[0].Price), "posted at the bid")
+	assert.Equal(t, exchanges.OrderTypeMarket, requests[1].Type)
+	assert.False(t, requests[1].PostOnly)
+	assert.True(t, decimal.NewFromInt(1).Equal(requests[1].Amount))
+
+	orders.mu.Lock()
+	assert.Equal(t, []string{"child-1"}, orders.canceled)
+	orders.mu.Unlock()
+}
+
+func TestHandleSignal_MakerFirstAbandonsWhenPriceMovesAway(t *testing.T) {
+	orders := &makerOrderManager{algoOrderManager: newAlgoOrderManager()}
+	agent, moveBid := newMakerAgent(orders, Config{
+		MakerFirst:           MakerFirstOff,
+		MakerFirstStrategies: map[string]MakerFirst{"fast": MakerFirstAbandon},
+		MakerTimeout:         time.Minute,
+		MakerMaxDriftBps:     10,
+	})
+
+	signal := algoTestSignal()
+	signal.Strategy = "fast"
+	require.NoError(t, agent.HandleSignal(context.Background(), signal))
+
+	// A bid 5 bps up is within the drift allowed
+	moveBid(99.95)
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, orders.GetOpenOrders(), 1)
+
+	moveBid(100.2)
+	waitForAlgo(t, agent, AlgoStatusCanceled)
+	assert.Len(t, orders.placedRequests(), 1, "nothing crosses the spread")
+	assert.Empty(t, orders.GetOpenOrders())
+}
+
+func TestHandleSignal_MakerFirstFilled(t *testing.T) {
+	orders := &makerOrderManager{algoOrderManager: newAlgoOrderManager()}
+	agent, _ := newMakerAgent(orders, Config{
+		MakerFirst:   MakerFirstMarket,
+		MakerTimeout: time.Minute,
+	})
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+	orders.fill("child-1")
+	waitForAlgo(t, agent, AlgoStatusCompleted)
+
+	assert.Len(t, orders.placedRequests(), 1)
+	orders.mu.Lock()
+	assert.Empty(t, orders.canceled)
+	orders.mu.Unlock()
+}
+
+func TestHandleSignal_MakerFirstOffForOtherStrategies(t *testing.T) {
+	orders := &makerOrderManager{algoOrderManager: newAlgoOrderManager()}
+	agent, _ := newMakerAgent(orders, Config{
+		MakerFirstStrategies: map[string]MakerFirst{"fast": MakerFirstAbandon},
+		MakerTimeout:         time.Minute,
+	})
+
+	require.NoError(t, agent.HandleSignal(context.Background(), algoTestSignal()))
+
+	requests := orders.placedRequests()
+	require.Len(t, requests, 1)
+	assert.False(t, requests[0].PostOnly)
+	assert.Empty(t, agent.AlgoOrders())
+}
@@ -0,0 +1,36 @@
+package execution
+
+import (
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// OperatorPresence reports whether an operator is watching the bot
+type OperatorPresence interface {
+	// Absent reports whether the operator has been away too long, and why
+	Absent() (string, bool)
+}
+
+// SetOperatorPresence refuses entries while presence reports the operator
+// absent. Exits are still executed.
+func (e *ExecutionAgent) SetOperatorPresence(presence OperatorPresence) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.operator = presence
+}
+
+// checkOperator returns why entries on symbol are refused for lack of an
+// operator
+func (e *ExecutionAgent) checkOperator(symbol string) (string, bool) {
+	e.mu.RLock()
+	presence := e.operator
+	e.mu.RUnlock()
+	if presence == nil {
+		return "", false
+	}
+
+	reason, absent := presence.Absent()
+	if absent {
+		telemetry.RecordSignalBlocked(symbol, "operator_absent")
+	}
+	return reason, absent
+}
@@ -0,0 +1,41 @@
+package execution
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// operatorPresence reports the operator absent while away is set
+type operatorPresence struct {
+	away bool
+}
+
+func (p *operatorPresence) Absent() (string, bool) {
+	return "deadman switch tripped", p.away
+}
+
+func TestHandleSignal_OperatorAbsent(t *testing.T) {
+	var placed []*order.OrderRequest
+	agent := newScalingAgent(nil, &placed, Config{})
+	var decisions []Decision
+	agent.SetDecisionCallback(func(decision Decision) { decisions = append(decisions, decision) })
+	presence := &operatorPresence{away: true}
+	agent.SetOperatorPresence(presence)
+
+	entry := &strategy.Signal{Type: strategy.SignalTypeEntry, Side: exchanges.OrderSideBuy, Price: decimal.NewFromInt(100), Symbol: "BTC-USD"}
+	require.NoError(t, agent.HandleSignal(context.Background(), entry))
+	assert.Empty(t, placed)
+	assert.Equal(t, DecisionSkipped, decisions[0].Outcome)
+	assert.Equal(t, "deadman switch tripped", decisions[0].Reason)
+
+	presence.away = false
+	require.NoError(t, agent.HandleSignal(context.Background(), entry))
+	assert.Len(t, placed, 1)
+}
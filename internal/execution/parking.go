@@ -0,0 +1,201 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// ConnectivityCheck reports whether the exchange symbol trades on is
+// reachable
+type ConnectivityCheck func(symbol string) bool
+
+// ParkedSignal is a signal held while the exchange of its symbol is
+// unreachable
+type ParkedSignal struct {
+	Signal   *strategy.Signal `json:"signal"`
+	ParkedAt time.Time        `json:"parked_at"`
+}
+
+// SetConnectivityCheck parks the signals on symbols check reports
+// unreachable when ParkSignalTTL is set, instead of losing them to orders
+// the exchange cannot take
+func (e *ExecutionAgent) SetConnectivityCheck(check ConnectivityCheck) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.connectivity = check
+}
+
+// HasParking reports whether signals are parked while their exchange is
+// unreachable
+func (e *ExecutionAgent) HasParking() bool {
+	return e.config.ParkSignalTTL > 0
+}
+
+// ParkedSignals returns the signals waiting for their exchange, oldest first
+func (e *ExecutionAgent) ParkedSignals() []ParkedSignal {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]ParkedSignal(nil), e.parked...)
+}
+
+// parkSignal parks signal when the exchange of its symbol is unreachable,
+// and reports whether it did. The signal supersedes the one of the same type
+// parked for the same symbol and strategy, and the oldest entry is dropped
+// once ParkMaxSignals are parked. Exits are never dropped: the position they
+// close stays open until they execute.
+func (e *ExecutionAgent) parkSignal(signal *strategy.Signal, decision *Decision) bool {
+	if e.config.ParkSignalTTL <= 0 {
+		return false
+	}
+	e.mu.RLock()
+	check := e.connectivity
+	e.mu.RUnlock()
+	if check == nil || check(signal.Symbol) {
+		return false
+	}
+
+	var dropped []ParkedSignal
+	e.mu.Lock()
+	parked := make([]ParkedSignal, 0, len(e.parked)+1)
+	for _, p := range e.parked {
+		if p.Signal.Symbol != signal.Symbol || p.Signal.Strategy != signal.Strategy || p.Signal.Type != signal.Type {
+			parked = append(parked, p)
+		}
+	}
+	parked = append(parked, ParkedSignal{Signal: signal, ParkedAt: e.clock()})
+	if limit := e.config.ParkMaxSignals; limit > 0 && len(parked) > limit {
+		excess := len(parked) - limit
+		kept := parked[:0]
+		for _, p := range parked {
+			if excess > 0 && p.Signal.Type == strategy.SignalTypeEntry {
+				dropped = append(dropped, p)
+				excess--
+				continue
+			}
+			kept = append(kept, p)
+		}
+		parked = kept
+	}
+	e.parked = parked
+	e.mu.Unlock()
+
+	telemetry.RecordSignalBlocked(signal.Symbol, "exchange_unreachable")
+	decision.skip("exchange unreachable, signal parked")
+	for _, p := range dropped {
+		e.dropParked(p, "parking queue full")
+	}
+	return true
+}
+
+// unpark removes p from the parked signals, and reports whether it was still
+// parked
+func (e *ExecutionAgent) unpark(p ParkedSignal) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i, parked := range e.parked {
+		if parked.Signal == p.Signal {
+			e.parked = append(e.parked[:i], e.parked[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// dropParked records the decision not to execute the parked signal p
+func (e *ExecutionAgent) dropParked(p ParkedSignal, reason string) {
+	telemetry.RecordSignalBlocked(p.Signal.Symbol, "parked_dropped")
+	decision := newDecision(p.Signal, e.clock())
+	decision.skip(reason)
+	e.recordDecision(decision)
+}
+
+// parkedDrift returns how far, in basis points, the mid price has moved from
+// the price of the parked entry signal. Exits are not checked: the position
+// they close is still open whatever the price.
+func (e *ExecutionAgent) parkedDrift(ctx context.Context, signal *strategy.Signal) (float64, error) {
+	e.mu.RLock()
+	source := e.orderBooks
+	e.mu.RUnlock()
+	if signal.Type != strategy.SignalTypeEntry || e.config.ParkMaxDriftBps <= 0 || source == nil || !signal.Price.IsPositive() {
+		return 0, nil
+	}
+
+	book, err := e.fetchBook(ctx, signal.Symbol)
+	if err != nil {
+		return 0, err
+	}
+	mid := book.Bids[0].Price.Add(book.Asks[0].Price).Div(decimal.NewFromInt(2))
+	return mid.Sub(signal.Price).Abs().Div(signal.Price).InexactFloat64() * 10000, nil
+}
+
+// ReleaseParked re-validates the parked signals whose exchange is reachable
+// again and executes those still valid: parked less than ParkSignalTTL ago
+// and, for entries, within ParkMaxDriftBps of the mid price. Executed
+// signals go through every check of HandleSignal again. Entries whose
+// exchange stays unreachable are dropped once they expire, exits wait for it
+// however long it takes.
+func (e *ExecutionAgent) ReleaseParked(ctx context.Context) error {
+	e.mu.RLock()
+	check := e.connectivity
+	parked := append([]ParkedSignal(nil), e.parked...)
+	e.mu.RUnlock()
+
+	now := e.clock()
+	var errs []error
+	for _, p := range parked {
+		expired := p.Signal.Type == strategy.SignalTypeEntry && now.Sub(p.ParkedAt) > e.config.ParkSignalTTL
+		if !expired && check != nil && !check(p.Signal.Symbol) {
+			continue
+		}
+		drift := 0.0
+		if !expired {
+			var err error
+			if drift, err = e.parkedDrift(ctx, p.Signal); err != nil {
+				// The exchange is not answering yet
+				continue
+			}
+		}
+		if !e.unpark(p) {
+			continue
+		}
+
+		switch {
+		case expired:
+			e.dropParked(p, fmt.Sprintf("parked signal expired after %s", e.config.ParkSignalTTL))
+		case drift > e.config.ParkMaxDriftBps:
+			e.dropParked(p, fmt.Sprintf("price drifted %.1f bps while parked, above %.1f bps", drift, e.config.ParkMaxDriftBps))
+		default:
+			logger.Component("execution").Info("releasing parked signal",
+				"symbol", p.Signal.Symbol,
+				"type", p.Signal.Type,
+				"parked", now.Sub(p.ParkedAt).Round(time.Millisecond).String())
+			if err := e.HandleSignal(ctx, p.Signal); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", p.Signal.Symbol, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunParking releases the parked signals every interval until ctx is done
+func (e *ExecutionAgent) RunParking(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.ReleaseParked(ctx); err != nil {
+				logger.Component("execution").Warn("failed to execute parked signals", "error", err)
+			}
+		}
+	}
+}
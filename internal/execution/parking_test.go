@@ -0,0 +1,169 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newParkingAgent(placed *[]*order.OrderRequest, now *time.Time, connected map[string]bool) *ExecutionAgent {
+	agent := newScalingAgent(nil, placed, Config{
+		ParkSignalTTL:   time.Minute,
+		ParkMaxDriftBps: 20,
+		ParkMaxSignals:  2,
+	})
+	agent.now = func() time.Time { return *now }
+	agent.SetConnectivityCheck(func(symbol string) bool { return connected[symbol] })
+	return agent
+}
+
+func entrySignal(symbol string, price int64) *strategy.Signal {
+	return &strategy.Signal{
+		Type: strategy.SignalTypeEntry, Side: exchanges.OrderSideBuy, Symbol: symbol, Price: decimal.NewFromInt(price), Strength: 1,
+	}
+}
+
+func TestHandleSignal_ParksWhileUnreachable(t *testing.T) {
+	var placed []*order.OrderRequest
+	now := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	connected := map[string]bool{}
+	agent := newParkingAgent(&placed, &now, connected)
+	var decisions []Decision
+	agent.SetDecisionCallback(func(decision Decision) { decisions = append(decisions, decision) })
+
+	require.NoError(t, agent.HandleSignal(context.Background(), entrySignal("BTC-USD", 100)))
+	assert.Empty(t, placed, "no order is sent to an unreachable exchange")
+	require.Len(t, decisions, 1)
+	assert.Equal(t, DecisionSkipped, decisions[0].Outcome)
+	assert.Equal(t, "exchange unreachable, signal parked", decisions[0].Reason)
+
+	// A newer signal of the strategy supersedes the parked one
+	latest := entrySignal("BTC-USD", 101)
+	require.NoError(t, agent.HandleSignal(context.Background(), latest))
+	parked := agent.ParkedSignals()
+	require.Len(t, parked, 1)
+	assert.Same(t, latest, parked[0].Signal)
+
+	// The oldest signal is dropped once the queue is full
+	require.NoError(t, agent.HandleSignal(context.Background(), entrySignal("ETH-USD", 10)))
+	require.NoError(t, agent.HandleSignal(context.Background(), entrySignal("SOL-USD", 1)))
+	parked = agent.ParkedSignals()
+	require.Len(t, parked, 2)
+	assert.Equal(t, "ETH-USD", parked[0].Signal.Symbol)
+	dropped := decisions[len(decisions)-2]
+	assert.Equal(t, "BTC-USD", dropped.Symbol)
+	assert.Equal(t, "parking queue full", dropped.Reason)
+
+	// Nothing is released while the exchange stays unreachable
+	require.NoError(t, agent.ReleaseParked(context.Background()))
+	assert.Len(t, agent.ParkedSignals(), 2)
+	assert.Empty(t, placed)
+}
+
+func TestReleaseParked(t *testing.T) {
+	var placed []*order.OrderRequest
+	now := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	connected := map[string]bool{}
+	agent := newParkingAgent(&placed, &now, connected)
+	agent.config.ParkMaxSignals = 10
+	agent.SetOrderBookSource(func(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error) {
+		return &exchanges.OrderBook{
+			Bids: []exchanges.Level{{Price: decimal.NewFromFloat(99.9), Amount: decimal.NewFromInt(5)}},
+			Asks: []exchanges.Level{{Price: decimal.NewFromFloat(100.1), Amount: decimal.NewFromInt(5)}},
+		}, nil
+	})
+	var decisions []Decision
+	agent.SetDecisionCallback(func(decision Decision) { decisions = append(decisions, decision) })
+
+	ctx := context.Background()
+	require.NoError(t, agent.HandleSignal(ctx, entrySignal("BTC-USD", 100)))
+	require.NoError(t, agent.HandleSignal(ctx, entrySignal("ETH-USD", 105)))
+	require.NoError(t, agent.HandleSignal(ctx, entrySignal("SOL-USD", 100)))
+	decisions = nil
+
+	// Back within the TTL: the entry still priced at the market executes,
+	// the one the price moved away from is dropped
+	now = now.Add(30 * time.Second)
+	connected["BTC-USD"] = true
+	connected["ETH-USD"] = true
+	require.NoError(t, agent.ReleaseParked(ctx))
+	require.Len(t, placed, 1)
+	assert.Equal(t, "BTC-USD", placed[0].Symbol)
+	require.Len(t, decisions, 2)
+	for _, decision := range decisions {
+		switch decision.Symbol {
+		case "BTC-USD":
+			assert.Equal(t, DecisionExecuted, decision.Outcome)
+		case "ETH-USD":
+			assert.Equal(t, DecisionSkipped, decision.Outcome)
+			assert.Contains(t, decision.Reason, "price drifted")
+		}
+	}
+
+	// The signal whose exchange never came back expires
+	decisions = nil
+	now = now.Add(time.Minute)
+	require.NoError(t, agent.ReleaseParked(ctx))
+	assert.Len(t, placed, 1)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, "SOL-USD", decisions[0].Symbol)
+	assert.Contains(t, decisions[0].Reason, "expired")
+	assert.Empty(t, agent.ParkedSignals())
+}
+
+func TestReleaseParked_KeepsExitsAcrossOutage(t *testing.T) {
+	var placed []*order.OrderRequest
+	now := time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)
+	connected := map[string]bool{}
+	agent := newParkingAgent(&placed, &now, connected)
+	var closed []string
+	agent.orderManager.(*mockOrderManager).closePositionFunc = func(ctx context.Context, symbol string) error {
+		closed = append(closed, symbol)
+		return nil
+	}
+
+	ctx := context.Background()
+	exit := &strategy.Signal{Type: strategy.SignalTypeExit, Side: exchanges.OrderSideSell, Symbol: "BTC-USD", Price: decimal.NewFromInt(100), Strength: 1}
+	require.NoError(t, agent.HandleSignal(ctx, exit))
+
+	// A later entry of the strategy does not supersede the exit, and entries
+	// overflowing the queue are dropped before it
+	require.NoError(t, agent.HandleSignal(ctx, entrySignal("BTC-USD", 101)))
+	require.NoError(t, agent.HandleSignal(ctx, entrySignal("ETH-USD", 10)))
+	require.NoError(t, agent.HandleSignal(ctx, entrySignal("SOL-USD", 1)))
+	parked := agent.ParkedSignals()
+	require.Len(t, parked, 2)
+	assert.Same(t, exit, parked[0].Signal)
+	assert.Equal(t, "SOL-USD", parked[1].Signal.Symbol)
+
+	// The exit outlives the TTL while the exchange is down
+	now = now.Add(10 * time.Minute)
+	require.NoError(t, agent.ReleaseParked(ctx))
+	parked = agent.ParkedSignals()
+	require.Len(t, parked, 1)
+	assert.Same(t, exit, parked[0].Signal)
+	assert.Empty(t, closed)
+
+	// and closes the position once the exchange is back
+	connected["BTC-USD"] = true
+	require.NoError(t, agent.ReleaseParked(ctx))
+	assert.Equal(t, []string{"BTC-USD"}, closed)
+	assert.Empty(t, agent.ParkedSignals())
+}
+
+func TestHandleSignal_ParkingDisabled(t *testing.T) {
+	var placed []*order.OrderRequest
+	agent := newScalingAgent(nil, &placed, Config{})
+	agent.SetConnectivityCheck(func(string) bool { return false })
+
+	require.NoError(t, agent.HandleSignal(context.Background(), entrySignal("BTC-USD", 100)))
+	assert.Len(t, placed, 1, "signals are tried as before without a parking TTL")
+	assert.Empty(t, agent.ParkedSignals())
+}
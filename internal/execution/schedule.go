@@ -0,0 +1,370 @@
+package execution
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/calendar"
+)
+
+// TimeRange is a daily window in UTC, as offsets from midnight. A range whose
+// end is before its start wraps past midnight.
+type TimeRange struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseTimeRange parses a range such as "13:00-21:00"
+func ParseTimeRange(value string) (TimeRange, error) {
+	startText, endText, ok := strings.Cut(strings.TrimSpace(value), "-")
+	if !ok {
+		return TimeRange{}, fmt.Errorf("invalid time range %q: expected HH:MM-HH:MM", value)
+	}
+	start, err := parseClock(startText)
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("invalid time range %q: %w", value, err)
+	}
+	end, err := parseClock(endText)
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("invalid time range %q: %w", value, err)
+	}
+	return TimeRange{Start: start, End: end}, nil
+}
+
+// TimeOfDay is a time of day in UTC, as an offset from midnight
+type TimeOfDay time.Duration
+
+// ParseTimeOfDay parses a time such as "21:55"
+func ParseTimeOfDay(value string) (TimeOfDay, error) {
+	offset, err := parseClock(value)
+	if err != nil {
+		return 0, err
+	}
+	return TimeOfDay(offset), nil
+}
+
+func (t TimeOfDay) String() string {
+	return formatClock(time.Duration(t))
+}
+
+// UnmarshalJSON parses a time written as "HH:MM"
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseTimeOfDay(value)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// clockOffset returns the time of day of t in UTC
+func clockOffset(t time.Time) time.Duration {
+	t = t.UTC()
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+}
+
+// Contains reports whether the time of day of t falls within the range
+func (r TimeRange) Contains(t time.Time) bool {
+	return r.containsOffset(clockOffset(t))
+}
+
+func (r TimeRange) containsOffset(offset time.Duration) bool {
+	if r.Start <= r.End {
+		return offset >= r.Start && offset < r.End
+	}
+	return offset >= r.Start || offset < r.End
+}
+
+func (r TimeRange) String() string {
+	return formatClock(r.Start) + "-" + formatClock(r.End)
+}
+
+// UnmarshalJSON parses a range written as "HH:MM-HH:MM"
+func (r *TimeRange) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseTimeRange(value)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// SessionSchedule restricts entries to trading hours and weekdays
+type SessionSchedule struct {
+	Hours        []TimeRange `json:"hours,omitempty"` // No hours means all day
+	SkipWeekends bool        `json:"skip_weekends,omitempty"`
+	// FlatBy is when positions are closed ahead of low-liquidity hours. They
+	// stay flat until the end of the trading hours it falls in, or until
+	// midnight UTC.
+	FlatBy *TimeOfDay `json:"flat_by,omitempty"`
+}
+
+// flatWindow returns the daily window positions must be flat in, if any
+func (s SessionSchedule) flatWindow() (TimeRange, bool) {
+	if s.FlatBy == nil {
+		return TimeRange{}, false
+	}
+	window := TimeRange{Start: time.Duration(*s.FlatBy), End: 24 * time.Hour}
+	for _, r := range s.Hours {
+		if r.containsOffset(window.Start) {
+			window.End = r.End
+			break
+		}
+	}
+	return window, true
+}
+
+// allows reports whether entries are allowed at t, with the reason if not
+func (s SessionSchedule) allows(t time.Time) (bool, string) {
+	t = t.UTC()
+	if s.SkipWeekends && (t.Weekday() == time.Saturday || t.Weekday() == time.Sunday) {
+		return false, "weekend trading disabled"
+	}
+	if len(s.Hours) == 0 {
+		return true, ""
+	}
+	for _, r := range s.Hours {
+		if r.Contains(t) {
+			return true, ""
+		}
+	}
+	hours := make([]string, len(s.Hours))
+	for i, r := range s.Hours {
+		hours[i] = r.String()
+	}
+	return false, "outside trading hours " + strings.Join(hours, ", ") + " UTC"
+}
+
+// Blackout is a period, typically around an economic release, during which
+// no new entries are taken
+type Blackout struct {
+	Name    string
+	Start   time.Time
+	End     time.Time
+	Symbols []string // Empty applies to every symbol
+}
+
+func (b Blackout) applies(symbol string, t time.Time) bool {
+	if t.Before(b.Start) || !t.Before(b.End) {
+		return false
+	}
+	if len(b.Symbols) == 0 {
+		return true
+	}
+	for _, s := range b.Symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// TradingSchedule decides when the execution agent may open positions. Exits
+// are never restricted by the schedule.
+type TradingSchedule struct {
+	Default   SessionSchedule            `json:"default"`
+	Symbols   map[string]SessionSchedule `json:"symbols,omitempty"` // Overrides the default per symbol
+	Blackouts []Blackout                 `json:"-"`
+	// Overrides the flat-by time of the default per strategy; the flat-by
+	// time of a symbol override still applies first
+	StrategyFlatBy map[string]TimeOfDay `json:"strategy_flat_by,omitempty"`
+}
+
+// Allowed reports whether an entry on symbol is allowed at t, with the reason
+// if not
+func (s *TradingSchedule) Allowed(symbol string, t time.Time) (bool, string) {
+	if s == nil {
+		return true, ""
+	}
+	for _, blackout := range s.Blackouts {
+		if blackout.applies(symbol, t) {
+			return false, fmt.Sprintf("blackout %q until %s", blackout.Name, blackout.End.UTC().Format("2006-01-02 15:04 UTC"))
+		}
+	}
+	session, ok := s.Symbols[symbol]
+	if !ok {
+		session = s.Default
+	}
+	return session.allows(t)
+}
+
+// flatWindow returns the daily window the positions on symbol held by
+// strategy must be flat in, if any
+func (s *TradingSchedule) flatWindow(symbol, strategy string) (TimeRange, bool) {
+	if s == nil {
+		return TimeRange{}, false
+	}
+	session, override := s.Symbols[symbol]
+	if !override {
+		session = s.Default
+	}
+	if flatBy, ok := s.StrategyFlatBy[strategy]; ok && (!override || session.FlatBy == nil) {
+		session.FlatBy = &flatBy
+	}
+	return session.flatWindow()
+}
+
+// HasFlatBy reports whether positions are flattened at some time of day
+func (s *TradingSchedule) HasFlatBy() bool {
+	if s == nil {
+		return false
+	}
+	if s.Default.FlatBy != nil || len(s.StrategyFlatBy) > 0 {
+		return true
+	}
+	for _, session := range s.Symbols {
+		if session.FlatBy != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Flat reports whether the positions on symbol held by strategy must be
+// flat at t, with the reason if so. Entries are refused meanwhile.
+func (s *TradingSchedule) Flat(symbol, strategy string, t time.Time) (bool, string) {
+	window, ok := s.flatWindow(symbol, strategy)
+	if !ok || !window.Contains(t) {
+		return false, ""
+	}
+	return true, fmt.Sprintf("flat by %s UTC until %s UTC", formatClock(window.Start), formatClock(window.End))
+}
+
+// FlatBy returns when the positions on symbol held by strategy are next
+// flattened after t, or when they were if they must be flat at t
+func (s *TradingSchedule) FlatBy(symbol, strategy string, t time.Time) (time.Time, bool) {
+	window, ok := s.flatWindow(symbol, strategy)
+	if !ok {
+		return time.Time{}, false
+	}
+	t = t.UTC()
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	at := midnight.Add(window.Start)
+	switch {
+	case window.Contains(t) && at.After(t):
+		// The window started the day before and wraps past midnight
+		at = at.AddDate(0, 0, -1)
+	case !window.Contains(t) && !at.After(t):
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, true
+}
+
+// LoadScheduleFile loads a trading schedule from a JSON file
+func LoadScheduleFile(path string) (*TradingSchedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trading schedule: %w", err)
+	}
+	var schedule TradingSchedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, fmt.Errorf("failed to parse trading schedule %s: %w", path, err)
+	}
+	return &schedule, nil
+}
+
+// LoadCalendarFile loads blackouts from a calendar file (see
+// calendar.LoadFile), the format of the event feed of the risk manager.
+// Every line needs an end; the impact is not used.
+func LoadCalendarFile(path string) ([]Blackout, error) {
+	entries, err := calendar.LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	blackouts := make([]Blackout, 0, len(entries))
+	for _, entry := range entries {
+		if entry.End.IsZero() {
+			return nil, fmt.Errorf("calendar %s line %d: a blackout needs an end", path, entry.Line)
+		}
+		blackouts = append(blackouts, Blackout{Name: entry.Name, Start: entry.Start, End: entry.End, Symbols: entry.Symbols})
+	}
+	return blackouts, nil
+}
+
+// LoadSchedule builds the trading schedule from environment variables:
+// EXECUTION_SCHEDULE_FILE, or EXECUTION_TRADING_HOURS, EXECUTION_SKIP_WEEKENDS
+// and EXECUTION_FLAT_BY for a schedule shared by every symbol, plus
+// blackouts from EXECUTION_CALENDAR_FILE. It returns nil when trading is
+// not restricted.
+func LoadSchedule() (*TradingSchedule, error) {
+	var schedule *TradingSchedule
+
+	if path := os.Getenv("EXECUTION_SCHEDULE_FILE"); path != "" {
+		loaded, err := LoadScheduleFile(path)
+		if err != nil {
+			return nil, err
+		}
+		schedule = loaded
+	}
+
+	if hours := os.Getenv("EXECUTION_TRADING_HOURS"); hours != "" {
+		if schedule == nil {
+			schedule = &TradingSchedule{}
+		}
+		schedule.Default.Hours = nil
+		for _, value := range strings.Split(hours, ",") {
+			r, err := ParseTimeRange(value)
+			if err != nil {
+				return nil, err
+			}
+			schedule.Default.Hours = append(schedule.Default.Hours, r)
+		}
+	}
+	if os.Getenv("EXECUTION_SKIP_WEEKENDS") == "true" {
+		if schedule == nil {
+			schedule = &TradingSchedule{}
+		}
+		schedule.Default.SkipWeekends = true
+	}
+	if value := os.Getenv("EXECUTION_FLAT_BY"); value != "" {
+		flatBy, err := ParseTimeOfDay(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EXECUTION_FLAT_BY: %w", err)
+		}
+		if schedule == nil {
+			schedule = &TradingSchedule{}
+		}
+		schedule.Default.FlatBy = &flatBy
+	}
+
+	if path := os.Getenv("EXECUTION_CALENDAR_FILE"); path != "" {
+		blackouts, err := LoadCalendarFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if schedule == nil {
+			schedule = &TradingSchedule{}
+		}
+		schedule.Blackouts = append(schedule.Blackouts, blackouts...)
+	}
+
+	return schedule, nil
+}
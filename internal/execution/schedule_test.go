@@ -0,0 +1,220 @@
+package execution
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeRange_Contains(t *testing.T) {
+	day, err := ParseTimeRange("13:00-21:00")
+	require.NoError(t, err)
+	assert.True(t, day.Contains(time.Date(2024, 1, 3, 13, 0, 0, 0, time.UTC)))
+	assert.False(t, day.Contains(time.Date(2024, 1, 3, 21, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "13:00-21:00", day.String())
+
+	overnight, err := ParseTimeRange("22:00-02:00")
+	require.NoError(t, err)
+	assert.True(t, overnight.Contains(time.Date(2024, 1, 3, 23, 30, 0, 0, time.UTC)))
+	assert.True(t, overnight.Contains(time.Date(2024, 1, 3, 1, 0, 0, 0, time.UTC)))
+	assert.False(t, overnight.Contains(time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)))
+
+	_, err = ParseTimeRange("13h-21h")
+	assert.Error(t, err)
+}
+
+func TestTradingSchedule_Allowed(t *testing.T) {
+	fomc := time.Date(2024, 1, 3, 19, 0, 0, 0, time.UTC)
+	schedule := &TradingSchedule{
+		Default: SessionSchedule{Hours: []TimeRange{{Start: 13 * time.Hour, End: 21 * time.Hour}}},
+		Symbols: map[string]SessionSchedule{
+			"EUR-USD": {SkipWeekends: true},
+		},
+		Blackouts: []Blackout{{Name: "FOMC", Start: fomc.Add(-15 * time.Minute), End: fomc.Add(30 * time.Minute), Symbols: []string{"BTC-USD"}}},
+	}
+
+	wednesday := time.Date(2024, 1, 3, 15, 0, 0, 0, time.UTC)
+	allowed, _ := schedule.Allowed("BTC-USD", wednesday)
+	assert.True(t, allowed)
+
+	allowed, reason := schedule.Allowed("BTC-USD", wednesday.Add(-3*time.Hour))
+	assert.False(t, allowed)
+	assert.Contains(t, reason, "outside trading hours")
+
+	allowed, reason = schedule.Allowed("BTC-USD", fomc)
+	assert.False(t, allowed)
+	assert.Contains(t, reason, "FOMC")
+
+	// The blackout only covers the listed symbols
+	allowed, _ = schedule.Allowed("ETH-USD", fomc)
+	assert.True(t, allowed)
+
+	// Symbol schedules override the default
+	allowed, _ = schedule.Allowed("EUR-USD", wednesday.Add(-10*time.Hour))
+	assert.True(t, allowed)
+	allowed, reason = schedule.Allowed("EUR-USD", time.Date(2024, 1, 6, 15, 0, 0, 0, time.UTC))
+	assert.False(t, allowed)
+	assert.Contains(t, reason, "weekend")
+
+	var unrestricted *TradingSchedule
+	allowed, _ = unrestricted.Allowed("BTC-USD", wednesday)
+	assert.True(t, allowed)
+}
+
+func TestLoadSchedule(t *testing.T) {
+	dir := t.TempDir()
+	schedulePath := filepath.Join(dir, "schedule.json")
+	require.NoError(t, os.WriteFile(schedulePath, []byte(`{
+		"default": {"hours": ["13:00-21:00"]},
+		"symbols": {"EUR-USD": {"skip_weekends": true}}
+	}`), 0o644))
+	calendarPath := filepath.Join(dir, "calendar.csv")
+	require.NoError(t, os.WriteFile(calendarPath, []byte(
+		"start,end,name,symbols\n"+
+			"# US releases\n"+
+			"2024-01-05T13:15:00Z,2024-01-05T14:00:00Z,NFP\n"+
+			"2024-01-31T18:45:00Z,2024-01-31T19:30:00Z,FOMC,BTC-USD ETH-USD\n"), 0o644))
+
+	t.Setenv("EXECUTION_SCHEDULE_FILE", schedulePath)
+	t.Setenv("EXECUTION_CALENDAR_FILE", calendarPath)
+	t.Setenv("EXECUTION_SKIP_WEEKENDS", "true")
+
+	schedule, err := LoadSchedule()
+	require.NoError(t, err)
+	require.NotNil(t, schedule)
+	assert.Equal(t, []TimeRange{{Start: 13 * time.Hour, End: 21 * time.Hour}}, schedule.Default.Hours)
+	assert.True(t, schedule.Default.SkipWeekends)
+	assert.True(t, schedule.Symbols["EUR-USD"].SkipWeekends)
+	require.Len(t, schedule.Blackouts, 2)
+	assert.Equal(t, "NFP", schedule.Blackouts[0].Name)
+	assert.Equal(t, []string{"BTC-USD", "ETH-USD"}, schedule.Blackouts[1].Symbols)
+
+	t.Setenv("EXECUTION_TRADING_HOURS", "9-17")
+	_, err = LoadSchedule()
+	assert.Error(t, err)
+}
+
+func TestLoadSchedule_Unrestricted(t *testing.T) {
+	schedule, err := LoadSchedule()
+	assert.NoError(t, err)
+	assert.Nil(t, schedule)
+}
+
+func TestHandleSignal_EntryOutsideTradingWindow(t *testing.T) {
+	placed := false
+	agent := &ExecutionAgent{
+		orderManager: &mockOrderManager{},
+		riskManager: &mockRiskManager{
+			canTradeFunc: func() (bool, string) {
+				placed = true
+				return true, ""
+			},
+		},
+		config: Config{AutoExecute: true, MinSignalStrength: 0.1},
+		now:    func() time.Time { return time.Date(2024, 1, 3, 8, 0, 0, 0, time.UTC) },
+	}
+	agent.SetSchedule(&TradingSchedule{
+		Default: SessionSchedule{Hours: []TimeRange{{Start: 13 * time.Hour, End: 21 * time.Hour}}},
+	})
+
+	err := agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Symbol:   "BTC-USD",
+		Strength: 1,
+	})
+
+	var execErr *ExecutionError
+	assert.ErrorAs(t, err, &execErr)
+	assert.Equal(t, ExecutionErrorTypeTradingWindowClosed, execErr.Type)
+	assert.False(t, placed, "entry should be rejected before any risk check")
+
+	// Exits are never restricted
+	err = agent.HandleSignal(context.Background(), &strategy.Signal{
+		Type:     strategy.SignalTypeExit,
+		Symbol:   "BTC-USD",
+		Strength: 1,
+	})
+	assert.NoError(t, err)
+}
+
+func TestTradingSchedule_Flat(t *testing.T) {
+	flatBy := TimeOfDay(21*time.Hour + 55*time.Minute)
+	overnight := TimeOfDay(1 * time.Hour)
+	asia := TimeOfDay(7 * time.Hour)
+	schedule := &TradingSchedule{
+		Default: SessionSchedule{FlatBy: &flatBy},
+		Symbols: map[string]SessionSchedule{
+			"EUR-USD": {Hours: []TimeRange{{Start: 22 * time.Hour, End: 2 * time.Hour}}, FlatBy: &overnight},
+			"SOL-USD": {Hours: []TimeRange{{Start: 13 * time.Hour, End: 21 * time.Hour}}},
+		},
+		StrategyFlatBy: map[string]TimeOfDay{"asia": asia},
+	}
+	assert.True(t, schedule.HasFlatBy())
+
+	day := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	flat, _ := schedule.Flat("BTC-USD", "", day.Add(21*time.Hour))
+	assert.False(t, flat)
+	flat, reason := schedule.Flat("BTC-USD", "", day.Add(22*time.Hour))
+	assert.True(t, flat)
+	assert.Contains(t, reason, "flat by 21:55 UTC until 24:00 UTC")
+	flat, _ = schedule.Flat("BTC-USD", "", day.Add(24*time.Hour))
+	assert.False(t, flat, "positions may be reopened the next day")
+
+	at, ok := schedule.FlatBy("BTC-USD", "", day.Add(12*time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, day.Add(21*time.Hour+55*time.Minute), at)
+	at, _ = schedule.FlatBy("BTC-USD", "", day.Add(23*time.Hour))
+	assert.Equal(t, day.Add(21*time.Hour+55*time.Minute), at, "flat-by time being enforced")
+
+	// The flat window ends with the trading hours the flat-by time falls in
+	flat, reason = schedule.Flat("EUR-USD", "", day.Add(90*time.Minute))
+	assert.True(t, flat)
+	assert.Contains(t, reason, "until 02:00 UTC")
+	flat, _ = schedule.Flat("EUR-USD", "", day.Add(22*time.Hour))
+	assert.False(t, flat)
+
+	// Strategies override the default, but not the symbols
+	flat, _ = schedule.Flat("BTC-USD", "asia", day.Add(8*time.Hour))
+	assert.True(t, flat)
+	flat, _ = schedule.Flat("EUR-USD", "asia", day.Add(8*time.Hour))
+	assert.False(t, flat)
+	flat, _ = schedule.Flat("SOL-USD", "asia", day.Add(8*time.Hour))
+	assert.True(t, flat, "symbol overrides without a flat-by time follow the strategy")
+
+	// Symbol overrides without a flat-by time are never flattened otherwise
+	_, ok = schedule.FlatBy("SOL-USD", "", day)
+	assert.False(t, ok)
+
+	var unrestricted *TradingSchedule
+	assert.False(t, unrestricted.HasFlatBy())
+	flat, _ = unrestricted.Flat("BTC-USD", "", day)
+	assert.False(t, flat)
+}
+
+func TestLoadSchedule_FlatBy(t *testing.T) {
+	dir := t.TempDir()
+	schedulePath := filepath.Join(dir, "schedule.json")
+	require.NoError(t, os.WriteFile(schedulePath, []byte(`{
+		"symbols": {"EUR-USD": {"flat_by": "20:00"}},
+		"strategy_flat_by": {"asia": "07:00"}
+	}`), 0o644))
+	t.Setenv("EXECUTION_SCHEDULE_FILE", schedulePath)
+	t.Setenv("EXECUTION_FLAT_BY", "21:55")
+
+	schedule, err := LoadSchedule()
+	require.NoError(t, err)
+	require.NotNil(t, schedule.Default.FlatBy)
+	assert.Equal(t, "21:55", schedule.Default.FlatBy.String())
+	assert.Equal(t, "20:00", schedule.Symbols["EUR-USD"].FlatBy.String())
+	assert.Equal(t, TimeOfDay(7*time.Hour), schedule.StrategyFlatBy["asia"])
+
+	t.Setenv("EXECUTION_FLAT_BY", "late")
+	_, err = LoadSchedule()
+	assert.Error(t, err)
+}
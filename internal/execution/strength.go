@@ -0,0 +1,39 @@
+package execution
+
+import "math"
+
+// StrengthSizing selects how signal strength scales the size of entries
+type StrengthSizing string
+
+const (
+	StrengthSizingNone   StrengthSizing = "none"   // Every entry at the risk-based size
+	StrengthSizingLinear StrengthSizing = "linear" // From the min to the max multiplier as strength rises
+	StrengthSizingStep   StrengthSizing = "step"   // Linear, rounded down to StrengthSizingSteps levels
+)
+
+// strengthMultiplier returns the fraction of the risk-based size an entry of
+// strength is placed at. The curve starts at MinSignalStrength, the weakest
+// signal executed, and reaches the max multiplier at a strength of 1. The
+// multiplier never exceeds 1, so strength cannot size beyond the risk limits.
+func (c Config) strengthMultiplier(strength float64) float64 {
+	if c.StrengthSizing != StrengthSizingLinear && c.StrengthSizing != StrengthSizingStep {
+		return 1
+	}
+
+	position := 1.0
+	if c.MinSignalStrength < 1 {
+		position = (strength - c.MinSignalStrength) / (1 - c.MinSignalStrength)
+	}
+	position = math.Max(0, math.Min(1, position))
+
+	if c.StrengthSizing == StrengthSizingStep {
+		if c.StrengthSizingSteps < 2 {
+			position = 1
+		} else {
+			steps := float64(c.StrengthSizingSteps)
+			level := math.Min(math.Floor(position*steps), steps-1)
+			position = level / (steps - 1)
+		}
+	}
+	return math.Min(1, c.StrengthMinMultiplier+(c.StrengthMaxMultiplier-c.StrengthMinMultiplier)*position)
+}
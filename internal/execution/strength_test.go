@@ -0,0 +1,80 @@
+package execution
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_StrengthMultiplier(t *testing.T) {
+	config := Config{MinSignalStrength: 0.2, StrengthMinMultiplier: 0.5, StrengthMaxMultiplier: 1, StrengthSizingSteps: 3}
+
+	tests := []struct {
+		sizing   StrengthSizing
+		strength float64
+		want     float64
+	}{
+		{StrengthSizingNone, 0.2, 1},
+		{StrengthSizingLinear, 0.2, 0.5},
+		{StrengthSizingLinear, 0.6, 0.75},
+		{StrengthSizingLinear, 1, 1},
+		{StrengthSizingLinear, 0.1, 0.5}, // Below the curve
+		{StrengthSizingStep, 0.4, 0.5},
+		{StrengthSizingStep, 0.6, 0.75},
+		{StrengthSizingStep, 0.9, 1},
+		{StrengthSizingStep, 1, 1},
+	}
+	for _, tt := range tests {
+		config.StrengthSizing = tt.sizing
+		got := config.strengthMultiplier(tt.strength)
+		assert.True(t, math.Abs(got-tt.want) < 1e-9, "%s at %.2f: expected %v, got %v", tt.sizing, tt.strength, tt.want, got)
+	}
+}
+
+func TestLoadConfig_StrengthMultipliersNeverExceedOne(t *testing.T) {
+	t.Setenv("EXECUTION_STRENGTH_MIN_MULTIPLIER", "1.5")
+	t.Setenv("EXECUTION_STRENGTH_MAX_MULTIPLIER", "3")
+
+	config := LoadConfig()
+	assert.Equal(t, 1.0, config.StrengthMinMultiplier)
+	assert.Equal(t, 1.0, config.StrengthMaxMultiplier)
+
+	config = Config{StrengthSizing: StrengthSizingLinear, StrengthMinMultiplier: 0.5, StrengthMaxMultiplier: 2}
+	assert.Equal(t, 1.0, config.strengthMultiplier(1), "a multiplier set in code is capped too")
+}
+
+func TestHandleSignal_StrengthSizing(t *testing.T) {
+	var placed []*order.OrderRequest
+	agent := newScalingAgent(nil, &placed, Config{
+		StrengthSizing:        StrengthSizingLinear,
+		StrengthMinMultiplier: 0.5,
+		StrengthMaxMultiplier: 1,
+	})
+	var decisions []Decision
+	agent.SetDecisionCallback(func(decision Decision) { decisions = append(decisions, decision) })
+	var journaled []float64
+	agent.SetEntryCallback(func(_ *strategy.Signal, _ *exchanges.Order, decision *Decision) {
+		journaled = append(journaled, decision.SizeMultiplier)
+	})
+
+	entry := func(symbol string, strength float64) *strategy.Signal {
+		return &strategy.Signal{Type: strategy.SignalTypeEntry, Side: exchanges.OrderSideBuy, Price: decimal.NewFromInt(100), Symbol: symbol, Strength: strength}
+	}
+
+	require.NoError(t, agent.HandleSignal(context.Background(), entry("BTC-USD", 0.5)))
+	require.NoError(t, agent.HandleSignal(context.Background(), entry("ETH-USD", 1)))
+	require.Len(t, placed, 2)
+	assert.True(t, placed[0].Amount.Equal(decimal.NewFromFloat(0.75)), "weaker signals are sized down, got %s", placed[0].Amount)
+	assert.True(t, placed[1].Amount.Equal(decimal.NewFromInt(1)), "full strength signals trade the risk-based size")
+
+	assert.Equal(t, DecisionSizedDown, decisions[0].Outcome)
+	assert.Equal(t, "strength 0.50 sized at 0.75x", decisions[0].Reason)
+	assert.Equal(t, []float64{0.75, 1}, journaled)
+}
@@ -0,0 +1,123 @@
+package execution
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// globalThrottleKey is the bucket shared by every exchange
+const globalThrottleKey = "*"
+
+// OrderThrottle enforces order submission limits per exchange and globally.
+// It counts orders the bot submits, independently of any HTTP-level rate
+// limiting done by the exchange clients, so a runaway signal loop is stopped
+// before it reaches the network layer.
+type OrderThrottle struct {
+	mu          sync.Mutex
+	config      Config
+	submissions map[string][]time.Time
+	now         func() time.Time
+}
+
+// NewOrderThrottle creates a new order throttle from the execution config
+func NewOrderThrottle(config Config) *OrderThrottle {
+	return &OrderThrottle{
+		config:      config,
+		submissions: make(map[string][]time.Time),
+		now:         time.Now,
+	}
+}
+
+// Reserve checks whether count orders may be submitted to exchange and, if so,
+// records them. openOrders is the number of orders currently resting.
+func (t *OrderThrottle) Reserve(exchange string, count int, openOrders int) error {
+	if count <= 0 {
+		return nil
+	}
+	if exchange == "" {
+		exchange = "default"
+	}
+
+	if t.config.MaxOpenOrders > 0 && openOrders+count > t.config.MaxOpenOrders {
+		return fmt.Errorf("max open orders reached (%d/%d)", openOrders, t.config.MaxOpenOrders)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	exchangeWindow := t.prune(exchange, now)
+	globalWindow := t.prune(globalThrottleKey, now)
+
+	if err := checkWindow(exchangeWindow, now, count, t.config.MaxOrdersPerSecond, t.config.MaxOrdersPerMinute); err != nil {
+		return fmt.Errorf("%s order rate limit: %w", exchange, err)
+	}
+	if err := checkWindow(globalWindow, now, count, t.config.GlobalMaxOrdersPerSecond, t.config.GlobalMaxOrdersPerMinute); err != nil {
+		return fmt.Errorf("global order rate limit: %w", err)
+	}
+
+	for i := 0; i < count; i++ {
+		exchangeWindow = append(exchangeWindow, now)
+		globalWindow = append(globalWindow, now)
+	}
+	t.submissions[exchange] = exchangeWindow
+	t.submissions[globalThrottleKey] = globalWindow
+	return nil
+}
+
+// Record counts count orders submitted to exchange without checking any
+// limit. Reduce-only exits are recorded so entries see them in the rate
+// windows, but a position close is never refused.
+func (t *OrderThrottle) Record(exchange string, count int) {
+	if count <= 0 {
+		return
+	}
+	if exchange == "" {
+		exchange = "default"
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	exchangeWindow := t.prune(exchange, now)
+	globalWindow := t.prune(globalThrottleKey, now)
+	for i := 0; i < count; i++ {
+		exchangeWindow = append(exchangeWindow, now)
+		globalWindow = append(globalWindow, now)
+	}
+	t.submissions[exchange] = exchangeWindow
+	t.submissions[globalThrottleKey] = globalWindow
+}
+
+// prune drops submissions older than one minute and returns the remainder
+func (t *OrderThrottle) prune(key string, now time.Time) []time.Time {
+	window := t.submissions[key]
+	cutoff := now.Add(-time.Minute)
+	i := 0
+	for i < len(window) && !window[i].After(cutoff) {
+		i++
+	}
+	if i > 0 {
+		window = append(window[:0], window[i:]...)
+	}
+	return window
+}
+
+func checkWindow(window []time.Time, now time.Time, count, perSecond, perMinute int) error {
+	if perMinute > 0 && len(window)+count > perMinute {
+		return fmt.Errorf("%d orders in the last minute (limit %d)", len(window), perMinute)
+	}
+	if perSecond > 0 {
+		cutoff := now.Add(-time.Second)
+		recent := 0
+		for i := len(window) - 1; i >= 0 && window[i].After(cutoff); i-- {
+			recent++
+		}
+		if recent+count > perSecond {
+			return fmt.Errorf("%d orders in the last second (limit %d)", recent, perSecond)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package execution
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestThrottle(config Config) (*OrderThrottle, *time.Time) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	throttle := NewOrderThrottle(config)
+	throttle.now = func() time.Time { return now }
+	return throttle, &now
+}
+
+func TestOrderThrottle_PerSecondLimit(t *testing.T) {
+	throttle, now := newTestThrottle(Config{MaxOrdersPerSecond: 3})
+
+	assert.NoError(t, throttle.Reserve("dydx", 3, 0))
+	assert.Error(t, throttle.Reserve("dydx", 1, 0))
+
+	// Other exchanges have their own bucket
+	assert.NoError(t, throttle.Reserve("coinbase", 1, 0))
+
+	*now = now.Add(1100 * time.Millisecond)
+	assert.NoError(t, throttle.Reserve("dydx", 1, 0))
+}
+
+func TestOrderThrottle_PerMinuteLimit(t *testing.T) {
+	throttle, now := newTestThrottle(Config{MaxOrdersPerMinute: 5})
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, throttle.Reserve("dydx", 1, 0))
+		*now = now.Add(2 * time.Second)
+	}
+	assert.Error(t, throttle.Reserve("dydx", 1, 0))
+
+	*now = now.Add(time.Minute)
+	assert.NoError(t, throttle.Reserve("dydx", 1, 0))
+}
+
+func TestOrderThrottle_GlobalLimit(t *testing.T) {
+	throttle, _ := newTestThrottle(Config{GlobalMaxOrdersPerSecond: 2})
+
+	assert.NoError(t, throttle.Reserve("dydx", 1, 0))
+	assert.NoError(t, throttle.Reserve("coinbase", 1, 0))
+	err := throttle.Reserve("hyperliquid", 1, 0)
+	assert.ErrorContains(t, err, "global")
+}
+
+func TestOrderThrottle_MaxOpenOrders(t *testing.T) {
+	throttle, _ := newTestThrottle(Config{MaxOpenOrders: 4})
+
+	assert.NoError(t, throttle.Reserve("dydx", 3, 1))
+	assert.Error(t, throttle.Reserve("dydx", 3, 2))
+}
+
+func TestOrderThrottle_RejectedOrdersAreNotCounted(t *testing.T) {
+	throttle, _ := newTestThrottle(Config{MaxOrdersPerSecond: 3})
+
+	assert.Error(t, throttle.Reserve("dydx", 4, 0))
+	assert.NoError(t, throttle.Reserve("dydx", 3, 0))
+}
+
+func TestHandleSignal_RateLimited(t *testing.T) {
+	placed := 0
+	config := DefaultConfig()
+	config.MinSignalStrength = 0
+	config.MaxOrdersPerSecond = 3
+
+	agent := NewExecutionAgent(
+		&mockOrderManager{
+			placeOrderFunc: func(ctx context.Context, req *order.OrderRequest) (*exchanges.Order, error) {
+				placed++
+				return &exchanges.Order{ID: "order"}, nil
+			},
+		},
+		&mockRiskManager{
+			calculatePositionSizeFunc: func(entryPrice, stopLoss, accountBalance decimal.Decimal) decimal.Decimal {
+				return decimal.NewFromFloat(0.1)
+			},
+		},
+		config,
+	)
+	agent.SetExchangeResolver(func(symbol string) string { return "dydx" })
+
+	signal := &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Strength: 1,
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Symbol:   "BTC-USD",
+	}
+
+	assert.NoError(t, agent.HandleSignal(context.Background(), signal))
+
+	err := agent.HandleSignal(context.Background(), signal)
+	var execErr *ExecutionError
+	assert.ErrorAs(t, err, &execErr)
+	assert.Equal(t, ExecutionErrorTypeRateLimited, execErr.Type)
+	assert.Equal(t, 1, placed)
+}
+
+func TestHandleSignal_ExitsAreNotRateLimited(t *testing.T) {
+	closed := 0
+	config := DefaultConfig()
+	config.MinSignalStrength = 0
+	config.MaxOrdersPerSecond = 1
+	config.MaxOpenOrders = 2
+
+	agent := NewExecutionAgent(
+		&mockOrderManager{
+			openOrders: []*exchanges.Order{{ID: "stop"}, {ID: "take-profit"}},
+			getPositionsFunc: func() []*order.ManagedPosition {
+				return []*order.ManagedPosition{{Symbol: "BTC-USD", Side: order.PositionSideLong, Amount: decimal.NewFromInt(1), Status: order.PositionStatusOpen}}
+			},
+			closePositionFunc: func(ctx context.Context, symbol string) error {
+				closed++
+				return nil
+			},
+		},
+		&mockRiskManager{},
+		config,
+	)
+	agent.SetExchangeResolver(func(symbol string) string { return "dydx" })
+
+	exit := &strategy.Signal{Type: strategy.SignalTypeExit, Strength: 1, Symbol: "BTC-USD", Price: decimal.NewFromInt(100)}
+	assert.NoError(t, agent.HandleSignal(context.Background(), exit))
+	assert.NoError(t, agent.HandleSignal(context.Background(), exit))
+	assert.Equal(t, 2, closed, "exits should close positions past the order limits")
+
+	// Exits still count against the entries
+	assert.Error(t, agent.throttle.Reserve("dydx", 1, 0))
+}
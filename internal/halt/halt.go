@@ -0,0 +1,143 @@
+// Package halt tracks the symbols whose trading is halted, by the exchange
+// or manually by an operator
+package halt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// Source is who halted a symbol
+type Source string
+
+const (
+	SourceExchange Source = "exchange" // Market status reported by the venue
+	SourceManual   Source = "manual"   // Set by an operator
+)
+
+// Halt is a symbol halted by one source
+type Halt struct {
+	Symbol string    `json:"symbol"`
+	Source Source    `json:"source"`
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+// Registry holds the halted symbols. A symbol stays halted until every
+// source that halted it resumes it.
+type Registry struct {
+	mu       sync.RWMutex
+	halts    map[string]map[Source]Halt
+	onChange func(halt Halt, halted bool)
+	now      func() time.Time
+}
+
+// NewRegistry creates a registry without halts
+func NewRegistry() *Registry {
+	return &Registry{
+		halts: make(map[string]map[Source]Halt),
+		now:   time.Now,
+	}
+}
+
+// SetChangeCallback sets the callback notified when a source halts or
+// resumes a symbol
+func (r *Registry) SetChangeCallback(callback func(halt Halt, halted bool)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onChange = callback
+}
+
+// Halt halts symbol on behalf of source. Halting a symbol source already
+// halted keeps the original halt.
+func (r *Registry) Halt(symbol string, source Source, reason string) {
+	r.mu.Lock()
+	if _, exists := r.halts[symbol][source]; exists {
+		r.mu.Unlock()
+		return
+	}
+	if r.halts[symbol] == nil {
+		r.halts[symbol] = make(map[Source]Halt)
+	}
+	halt := Halt{Symbol: symbol, Source: source, Reason: reason, Since: r.now()}
+	r.halts[symbol][source] = halt
+	callback := r.onChange
+	r.mu.Unlock()
+
+	if callback != nil {
+		callback(halt, true)
+	}
+}
+
+// Resume lifts the halt source put on symbol
+func (r *Registry) Resume(symbol string, source Source) {
+	r.mu.Lock()
+	halt, exists := r.halts[symbol][source]
+	if !exists {
+		r.mu.Unlock()
+		return
+	}
+	delete(r.halts[symbol], source)
+	if len(r.halts[symbol]) == 0 {
+		delete(r.halts, symbol)
+	}
+	callback := r.onChange
+	r.mu.Unlock()
+
+	if callback != nil {
+		callback(halt, false)
+	}
+}
+
+// IsHalted reports whether any source halted symbol
+func (r *Registry) IsHalted(symbol string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.halts[symbol]) > 0
+}
+
+// Halts returns the current halts sorted by symbol and source
+func (r *Registry) Halts() []Halt {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	halts := make([]Halt, 0, len(r.halts))
+	for _, sources := range r.halts {
+		for _, halt := range sources {
+			halts = append(halts, halt)
+		}
+	}
+	sort.Slice(halts, func(i, j int) bool {
+		if halts[i].Symbol != halts[j].Symbol {
+			return halts[i].Symbol < halts[j].Symbol
+		}
+		return halts[i].Source < halts[j].Source
+	})
+	return halts
+}
+
+// Refresh halts or resumes symbols on behalf of the exchange from the market
+// status reporter reports. Symbols whose status cannot be fetched keep their
+// current state.
+func (r *Registry) Refresh(ctx context.Context, reporter exchanges.MarketStatusReporter, symbols []string) error {
+	var errs []error
+	for _, symbol := range symbols {
+		status, err := reporter.GetMarketStatus(ctx, symbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			continue
+		}
+		if status.Halted {
+			r.Halt(symbol, SourceExchange, status.Reason)
+		} else {
+			r.Resume(symbol, SourceExchange)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package halt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+type statusReporter map[string]*exchanges.MarketStatus
+
+func (s statusReporter) GetMarketStatus(ctx context.Context, symbol string) (*exchanges.MarketStatus, error) {
+	status, ok := s[symbol]
+	if !ok {
+		return nil, errors.New("unknown market")
+	}
+	return status, nil
+}
+
+func TestRegistry_SourcesHaltIndependently(t *testing.T) {
+	r := NewRegistry()
+	var changes []bool
+	r.SetChangeCallback(func(halt Halt, halted bool) { changes = append(changes, halted) })
+
+	r.Halt("BTC-USD", SourceManual, "maintenance")
+	r.Halt("BTC-USD", SourceManual, "again")
+	r.Halt("BTC-USD", SourceExchange, "PAUSED")
+	if !r.IsHalted("BTC-USD") || r.IsHalted("ETH-USD") {
+		t.Fatal("only BTC-USD should be halted")
+	}
+	halts := r.Halts()
+	if len(halts) != 2 || halts[0].Source != SourceExchange || halts[1].Reason != "maintenance" {
+		t.Errorf("expected one halt per source keeping the first reason, got %+v", halts)
+	}
+
+	r.Resume("BTC-USD", SourceManual)
+	if !r.IsHalted("BTC-USD") {
+		t.Error("the exchange halt should hold after the manual one is lifted")
+	}
+	r.Resume("BTC-USD", SourceExchange)
+	r.Resume("BTC-USD", SourceExchange)
+	if r.IsHalted("BTC-USD") || len(r.Halts()) != 0 {
+		t.Error("BTC-USD should trade once every source resumed it")
+	}
+	if len(changes) != 4 {
+		t.Errorf("expected 4 changes, got %d", len(changes))
+	}
+}
+
+func TestRegistry_Refresh(t *testing.T) {
+	r := NewRegistry()
+	reporter := statusReporter{
+		"BTC-USD": {Halted: true, Reason: "PAUSED"},
+		"ETH-USD": {},
+	}
+	r.Halt("ETH-USD", SourceExchange, "CANCEL_ONLY")
+
+	err := r.Refresh(context.Background(), reporter, []string{"BTC-USD", "ETH-USD", "SOL-USD"})
+	if err == nil {
+		t.Error("expected the unknown market to be reported")
+	}
+	if !r.IsHalted("BTC-USD") {
+		t.Error("BTC-USD should be halted by the exchange")
+	}
+	if r.IsHalted("ETH-USD") {
+		t.Error("ETH-USD should resume once the exchange reopens it")
+	}
+}
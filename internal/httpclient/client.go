@@ -0,0 +1,259 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/circuitbreaker"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/ratelimit"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// Config holds the configuration of an exchange's HTTP client
+type Config struct {
+	Name    string // Exchange name used in metrics and logs
+	BaseURL string
+	Timeout time.Duration
+
+	// RateLimit is the maximum requests per second, adapted down on 429s and
+	// low quota headers
+	RateLimit float64
+	Burst     int
+
+	// MaxRetries is the number of retries of a failed request. Backoff
+	// doubles from BaseBackoff up to MaxBackoff, with jitter.
+	MaxRetries  int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+
+	// Breaker configures the circuit breaker of each endpoint
+	Breaker *circuitbreaker.Config
+}
+
+// DefaultConfig returns the default configuration for an exchange allowing
+// rateLimit requests per second
+func DefaultConfig(name, baseURL string, rateLimit float64) *Config {
+	return &Config{
+		Name:        name,
+		BaseURL:     baseURL,
+		Timeout:     30 * time.Second,
+		RateLimit:   rateLimit,
+		Burst:       int(rateLimit * 2),
+		MaxRetries:  3,
+		BaseBackoff: 200 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+		Breaker: &circuitbreaker.Config{
+			MaxFailures:         5,
+			Timeout:             30 * time.Second,
+			MaxHalfOpenRequests: 1,
+		},
+	}
+}
+
+// Request is a request to an exchange endpoint
+type Request struct {
+	Method string
+	Path   string
+	Body   []byte
+	Header http.Header
+
+	// Endpoint names the route the circuit breaker and the logs are keyed
+	// on, like GET /orders/{id}, when Path carries identifiers. It defaults
+	// to the method and the path without its query.
+	Endpoint string
+
+	// Idempotent requests are retried after transient failures. Rate limited
+	// requests are always retried since the exchange did not process them.
+	Idempotent bool
+
+	// Sign authenticates each attempt, so signatures and timestamps are fresh
+	// on retries
+	Sign func(req *http.Request) error
+}
+
+// Response is a successful response
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+
+	// Sent and Received bracket the attempt that got the response
+	Sent     time.Time
+	Received time.Time
+}
+
+// Client sends requests to an exchange with rate limiting, retries and a
+// circuit breaker per endpoint
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+	limiter    *ratelimit.AdaptiveLimiter
+	log        *logger.Logger
+
+	mu       sync.Mutex
+	breakers map[string]*circuitbreaker.CircuitBreaker
+}
+
+// New creates a client
+func New(config *Config) *Client {
+	return &Client{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout},
+		limiter:    ratelimit.NewAdaptiveLimiter(config.RateLimit, config.Burst),
+		log:        logger.Component("httpclient").WithField("exchange", config.Name),
+		breakers:   make(map[string]*circuitbreaker.CircuitBreaker),
+	}
+}
+
+// Do sends r, retrying it while it fails with a retryable error. Failures
+// are returned as *Error.
+func (c *Client) Do(ctx context.Context, r *Request) (*Response, error) {
+	endpoint := r.Endpoint
+	if endpoint == "" {
+		endpoint = r.Method + " " + strings.SplitN(r.Path, "?", 2)[0]
+	}
+	breaker := c.breaker(endpoint)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.attempt(ctx, breaker, endpoint, r)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= c.config.MaxRetries || !c.retryable(ctx, r, err) {
+			return nil, err
+		}
+
+		delay := c.backoff(attempt)
+		c.log.Debug("retrying request",
+			"endpoint", endpoint,
+			"attempt", attempt+1,
+			"delay", delay,
+			"error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends r once through the endpoint's circuit breaker. Only
+// transient failures count against the endpoint: a rejected order says
+// nothing about the exchange's health.
+func (c *Client) attempt(ctx context.Context, breaker *circuitbreaker.CircuitBreaker, endpoint string, r *Request) (*Response, error) {
+	var resp *Response
+	var reqErr error
+	err := breaker.Execute(ctx, func() error {
+		resp, reqErr = c.send(ctx, endpoint, r)
+		if kind, _ := KindOf(reqErr); reqErr != nil && kind == KindTransient && ctx.Err() == nil {
+			return reqErr
+		}
+		return nil
+	})
+	if err != nil && reqErr == nil {
+		return nil, &Error{Kind: KindTransient, Endpoint: endpoint, Err: err}
+	}
+	return resp, reqErr
+}
+
+func (c *Client) send(ctx context.Context, endpoint string, r *Request) (*Response, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, &Error{Kind: KindTransient, Endpoint: endpoint, Err: fmt.Errorf("rate limit wait failed: %w", err)}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, c.config.BaseURL+r.Path, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, &Error{Kind: KindFatal, Endpoint: endpoint, Err: fmt.Errorf("failed to create request: %w", err)}
+	}
+	for key, values := range r.Header {
+		req.Header[key] = values
+	}
+	if r.Sign != nil {
+		if err := r.Sign(req); err != nil {
+			return nil, &Error{Kind: KindAuth, Endpoint: endpoint, Err: err}
+		}
+	}
+
+	sent := time.Now()
+	httpResp, err := c.httpClient.Do(req)
+	telemetry.RecordAPIRequest(c.config.Name, r.Path, time.Since(sent))
+	if err != nil {
+		return nil, &Error{Kind: KindTransient, Endpoint: endpoint, Err: fmt.Errorf("failed to execute request: %w", err)}
+	}
+	defer httpResp.Body.Close()
+
+	feedback := c.limiter.ObserveResponse(httpResp)
+	telemetry.RecordRateLimit(c.config.Name, c.limiter.Rate(), feedback.Remaining, feedback.Throttled)
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, &Error{Kind: KindTransient, Endpoint: endpoint, Err: fmt.Errorf("failed to read response: %w", err)}
+	}
+
+	if httpResp.StatusCode < 200 || httpResp.StatusCode >= 300 {
+		return nil, &Error{
+			Kind:       statusKind(httpResp.StatusCode),
+			Endpoint:   endpoint,
+			StatusCode: httpResp.StatusCode,
+			Body:       string(body),
+		}
+	}
+
+	return &Response{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		Body:       body,
+		Sent:       sent,
+		Received:   time.Now(),
+	}, nil
+}
+
+// retryable reports whether a request that failed with err should be sent
+// again
+func (c *Client) retryable(ctx context.Context, r *Request, err error) bool {
+	switch kind, _ := KindOf(err); kind {
+	case KindRateLimited:
+		return ctx.Err() == nil
+	case KindTransient:
+		return r.Idempotent && ctx.Err() == nil &&
+			!errors.Is(err, circuitbreaker.ErrCircuitOpen) &&
+			!errors.Is(err, circuitbreaker.ErrTooManyRequests)
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before retry attempt+1: exponential with jitter
+// between half and the full delay, so clients do not retry in lockstep
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.config.BaseBackoff << attempt
+	if delay <= 0 || delay > c.config.MaxBackoff {
+		delay = c.config.MaxBackoff
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// breaker returns the circuit breaker of endpoint
+func (c *Client) breaker(endpoint string) *circuitbreaker.CircuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	breaker, ok := c.breakers[endpoint]
+	if !ok {
+		breaker = circuitbreaker.New(c.config.Name+" "+endpoint, c.config.Breaker)
+		c.breakers[endpoint] = breaker
+	}
+	return breaker
+}
@@ -0,0 +1,183 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/circuitbreaker"
+)
+
+func testClient(url string) *Client {
+	config := DefaultConfig("test", url, 1000)
+	config.BaseBackoff = time.Millisecond
+	config.MaxBackoff = 5 * time.Millisecond
+	config.Breaker.MaxFailures = 3
+	return New(config)
+}
+
+// statusServer answers with the given statuses in turn, then 200
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(calls.Add(1))
+		if call <= len(statuses) {
+			if statuses[call-1] == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "0.01")
+			}
+			w.WriteHeader(statuses[call-1])
+			w.Write([]byte(`{"error":"failed"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	server, calls := statusServer(t, http.StatusBadGateway, http.StatusServiceUnavailable)
+	client := testClient(server.URL)
+
+	var signatures int
+	resp, err := client.Do(context.Background(), &Request{
+		Method:     http.MethodGet,
+		Path:       "/markets",
+		Idempotent: true,
+		Sign: func(req *http.Request) error {
+			signatures++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected the request to succeed after retries, got %v", err)
+	}
+	if string(resp.Body) != `{"ok":true}` {
+		t.Errorf("Unexpected body %s", resp.Body)
+	}
+	if calls.Load() != 3 || signatures != 3 {
+		t.Errorf("Expected 3 signed attempts, got %d calls and %d signatures", calls.Load(), signatures)
+	}
+}
+
+func TestClient_DoesNotRetryNonIdempotentRequests(t *testing.T) {
+	server, calls := statusServer(t, http.StatusServiceUnavailable)
+	client := testClient(server.URL)
+
+	_, err := client.Do(context.Background(), &Request{Method: http.MethodPost, Path: "/orders"})
+	if kind, ok := KindOf(err); !ok || kind != KindTransient {
+		t.Errorf("Expected a transient error, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls.Load())
+	}
+}
+
+func TestClient_RetriesRateLimitedRequests(t *testing.T) {
+	server, calls := statusServer(t, http.StatusTooManyRequests)
+	client := testClient(server.URL)
+
+	if _, err := client.Do(context.Background(), &Request{Method: http.MethodPost, Path: "/orders"}); err != nil {
+		t.Fatalf("Expected the rate limited request to be retried, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestClient_ErrorKinds(t *testing.T) {
+	tests := []struct {
+		status int
+		kind   Kind
+	}{
+		{http.StatusUnauthorized, KindAuth},
+		{http.StatusForbidden, KindAuth},
+		{http.StatusBadRequest, KindFatal},
+		{http.StatusNotFound, KindFatal},
+	}
+
+	for _, tt := range tests {
+		server, calls := statusServer(t, tt.status)
+		client := testClient(server.URL)
+
+		_, err := client.Do(context.Background(), &Request{Method: http.MethodGet, Path: "/accounts", Idempotent: true})
+		var reqErr *Error
+		if !errors.As(err, &reqErr) {
+			t.Fatalf("Status %d: expected *Error, got %v", tt.status, err)
+		}
+		if reqErr.Kind != tt.kind || reqErr.StatusCode != tt.status {
+			t.Errorf("Status %d: expected kind %s, got %s (%d)", tt.status, tt.kind, reqErr.Kind, reqErr.StatusCode)
+		}
+		if calls.Load() != 1 {
+			t.Errorf("Status %d: expected no retry, got %d attempts", tt.status, calls.Load())
+		}
+	}
+}
+
+func TestClient_CircuitBreakerPerRoute(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig("test", server.URL, 1000)
+	config.MaxRetries = 0
+	config.Breaker.MaxFailures = 2
+	client := New(config)
+	ctx := context.Background()
+
+	// Requests for different orders share the breaker of their route
+	for _, id := range []string{"order-1", "order-2"} {
+		client.Do(ctx, &Request{Method: http.MethodGet, Path: "/orders/" + id, Endpoint: "GET /orders/{id}"})
+	}
+	_, err := client.Do(ctx, &Request{Method: http.MethodGet, Path: "/orders/order-3", Endpoint: "GET /orders/{id}"})
+	if !errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+		t.Errorf("Expected the circuit of the route to be open, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected the open circuit to fail fast, got %d calls", calls.Load())
+	}
+	if len(client.breakers) != 1 {
+		t.Errorf("Expected a single breaker for the route, got %d", len(client.breakers))
+	}
+}
+
+func TestClient_CircuitBreakerPerEndpoint(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig("test", server.URL, 1000)
+	config.MaxRetries = 0
+	config.Breaker.MaxFailures = 2
+	client := New(config)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		client.Do(ctx, &Request{Method: http.MethodGet, Path: "/down?attempt=1"})
+	}
+	_, err := client.Do(ctx, &Request{Method: http.MethodGet, Path: "/down?attempt=2"})
+	if !errors.Is(err, circuitbreaker.ErrCircuitOpen) {
+		t.Errorf("Expected the circuit of the endpoint to be open, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected the open circuit to fail fast, got %d calls", calls.Load())
+	}
+
+	if _, err := client.Do(ctx, &Request{Method: http.MethodGet, Path: "/up"}); err != nil {
+		t.Errorf("Expected other endpoints to be unaffected, got %v", err)
+	}
+}
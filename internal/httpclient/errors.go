@@ -0,0 +1,75 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Kind classifies why a request failed, which decides whether it is retried
+type Kind int
+
+const (
+	KindTransient   Kind = iota // Network errors, timeouts and 5xx: may succeed when retried
+	KindRateLimited             // 429: the exchange did not process the request
+	KindAuth                    // 401 and 403: the credentials or signature were rejected
+	KindFatal                   // Other 4xx and malformed requests: retrying will not help
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindTransient:
+		return "transient"
+	case KindRateLimited:
+		return "rate_limited"
+	case KindAuth:
+		return "auth"
+	case KindFatal:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// Error is a failed request
+type Error struct {
+	Kind       Kind
+	Endpoint   string // Method and path of the request
+	StatusCode int    // HTTP status, 0 when no response was received
+	Body       string // Response body of an HTTP error
+	Err        error  // Underlying error when no response was received
+}
+
+func (e *Error) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s: API error (%d): %s", e.Endpoint, e.StatusCode, e.Body)
+	}
+	return fmt.Sprintf("%s: %v", e.Endpoint, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of err and whether err is a request error
+func KindOf(err error) (Kind, bool) {
+	var reqErr *Error
+	if errors.As(err, &reqErr) {
+		return reqErr.Kind, true
+	}
+	return 0, false
+}
+
+// statusKind classifies an HTTP error status
+func statusKind(status int) Kind {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return KindRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return KindAuth
+	case status == http.StatusRequestTimeout || status >= 500:
+		return KindTransient
+	default:
+		return KindFatal
+	}
+}
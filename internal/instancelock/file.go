@@ -0,0 +1,95 @@
+package instancelock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// errLocked is returned by lockFile when another process holds the lock
+var errLocked = errors.New("file locked")
+
+// FileLock is an advisory lock on a file, held until released or until the
+// process exits, crashes included
+type FileLock struct {
+	mu    sync.Mutex
+	path  string
+	owner string
+	file  *os.File
+}
+
+// NewFileLock creates a lock on the file named after key in dir
+func NewFileLock(dir, key, owner string) *FileLock {
+	return &FileLock{
+		path:  filepath.Join(dir, key+".lock"),
+		owner: owner,
+	}
+}
+
+// Path returns the path of the lock file
+func (l *FileLock) Path() string {
+	return l.path
+}
+
+// Acquire takes the lock and writes the owner in the file
+func (l *FileLock) Acquire(context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open lock file: %w", err)
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		if errors.Is(err, errLocked) {
+			holder, _ := os.ReadFile(l.path)
+			return fmt.Errorf("%w: %s (%s)", ErrHeld, strings.TrimSpace(string(holder)), l.path)
+		}
+		return fmt.Errorf("failed to lock %s: %w", l.path, err)
+	}
+
+	// The owner is informative, the lock itself is what excludes others
+	if err := file.Truncate(0); err == nil {
+		_, _ = file.WriteAt([]byte(l.owner+"\n"), 0)
+	}
+	l.file = file
+	return nil
+}
+
+// Renew checks the lock is still held. A file lock never expires.
+func (l *FileLock) Renew(context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return ErrLost
+	}
+	return nil
+}
+
+// Release unlocks the file. The file is kept, so an instance waiting on it
+// never locks a file about to be removed.
+func (l *FileLock) Release(context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	_ = l.file.Truncate(0)
+	err := unlockFile(l.file)
+	if cerr := l.file.Close(); err == nil {
+		err = cerr
+	}
+	l.file = nil
+	return err
+}
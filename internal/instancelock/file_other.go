@@ -0,0 +1,19 @@
+//go:build !unix && !windows
+
+package instancelock
+
+import (
+	"errors"
+	"os"
+)
+
+// lockFile fails, file locks are only supported on Unix and Windows; use a
+// Redis lease
+func lockFile(*os.File) error {
+	return errors.New("file locks are not supported on this platform, use a Redis lease")
+}
+
+// unlockFile does nothing, no file is ever locked
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package instancelock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockedRange locates the byte locked, past the owner written at the start of
+// the file: Windows locks are mandatory, and the holder must stay readable
+func lockedRange() *windows.Overlapped {
+	return &windows.Overlapped{OffsetHigh: 1}
+}
+
+// lockFile takes an exclusive lock on file without waiting
+func lockFile(file *os.File) error {
+	err := windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, lockedRange())
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errLocked
+	}
+	return err
+}
+
+// unlockFile releases the lock on file
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, lockedRange())
+}
@@ -0,0 +1,87 @@
+// Package instancelock keeps a single bot instance trading an account. Two
+// instances against the same account double every trade, so each one takes a
+// lock keyed on the account before trading: a file lock between instances on
+// the same host, or a Redis lease between hosts.
+package instancelock
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrHeld is returned when acquiring a lock another instance holds
+	ErrHeld = errors.New("instance lock held by another instance")
+	// ErrLost is returned when renewing a lock that expired or was taken over
+	ErrLost = errors.New("instance lock lost")
+)
+
+// Lock is held by at most one instance at a time
+type Lock interface {
+	// Acquire takes the lock, failing with an error wrapping ErrHeld when
+	// another instance holds it
+	Acquire(ctx context.Context) error
+	// Renew extends the lock, failing with an error wrapping ErrLost when it
+	// is no longer held
+	Renew(ctx context.Context) error
+	// Release lets another instance take the lock
+	Release(ctx context.Context) error
+}
+
+// AccountKey returns the lock key of the account identified by identities,
+// such as its exchange name, API key and subaccount. Identities are hashed, so
+// the key reveals none of them, and their order does not matter. Each account
+// takes its own lock, see Locks.
+func AccountKey(identities ...string) string {
+	sorted := append([]string(nil), identities...)
+	sort.Strings(sorted)
+	hash := sha256.Sum256([]byte(strings.Join(sorted, "\x00")))
+	return "constantine-" + hex.EncodeToString(hash[:8])
+}
+
+// Owner identifies this process in the locks it holds
+func Owner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// Keep renews lock every interval until ctx is done. When the lock is lost, or
+// could not be renewed for ttl since another instance may have taken it since,
+// lost is called once and renewal stops.
+func Keep(ctx context.Context, lock Lock, interval, ttl time.Duration, lost func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	renewed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := lock.Renew(ctx)
+		switch {
+		case err == nil:
+			renewed = time.Now()
+		case errors.Is(err, ErrLost):
+			lost(err)
+			return
+		case ctx.Err() != nil:
+			return
+		case time.Since(renewed) >= ttl:
+			lost(fmt.Errorf("%w: not renewed for %s: %v", ErrLost, time.Since(renewed).Round(time.Second), err))
+			return
+		}
+	}
+}
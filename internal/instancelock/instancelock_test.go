@@ -0,0 +1,287 @@
+package instancelock
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/testutils"
+)
+
+func TestAccountKey(t *testing.T) {
+	key := AccountKey("hyperliquid", "0xabc")
+	testutils.AssertEqual(t, key, AccountKey("0xabc", "hyperliquid"), "identity order should not matter")
+	testutils.AssertTrue(t, key != AccountKey("hyperliquid", "0xdef"), "accounts should have their own key")
+	testutils.AssertFalse(t, strings.Contains(key, "0xabc"), "key should not reveal identities")
+}
+
+func TestFileLock(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	first := NewFileLock(dir, "account", "first")
+	second := NewFileLock(dir, "account", "second")
+
+	testutils.AssertNoError(t, first.Acquire(ctx), "first instance should take the lock")
+	err := second.Acquire(ctx)
+	testutils.AssertTrue(t, errors.Is(err, ErrHeld), "second instance should be refused")
+	testutils.AssertTrue(t, strings.Contains(err.Error(), "first"), "error should name the holder")
+	testutils.AssertNoError(t, first.Renew(ctx), "file lock should stay held")
+
+	testutils.AssertNoError(t, NewFileLock(dir, "other-account", "second").Acquire(ctx), "other accounts should not be locked")
+
+	testutils.AssertNoError(t, first.Release(ctx), "Release should not return error")
+	testutils.AssertTrue(t, errors.Is(first.Renew(ctx), ErrLost), "released lock should be lost")
+	testutils.AssertNoError(t, second.Acquire(ctx), "lock should be taken once released")
+	testutils.AssertNoError(t, second.Release(ctx), "Release should not return error")
+}
+
+func TestLocks_SharedAccount(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	hyperliquid := AccountKey("hyperliquid", "0xabc")
+	coinbase := AccountKey("coinbase", "key")
+
+	// An instance trading two accounts excludes one trading either of them
+	both := Locks{NewFileLock(dir, coinbase, "both"), NewFileLock(dir, hyperliquid, "both")}
+	testutils.AssertNoError(t, both.Acquire(ctx), "first instance should take both locks")
+	single := Locks{NewFileLock(dir, hyperliquid, "single")}
+	testutils.AssertTrue(t, errors.Is(single.Acquire(ctx), ErrHeld), "shared account should be refused")
+	testutils.AssertNoError(t, both.Release(ctx), "Release should not return error")
+
+	// A refused instance keeps none of the locks it took
+	testutils.AssertNoError(t, single.Acquire(ctx), "single instance should take its lock")
+	err := both.Acquire(ctx)
+	testutils.AssertTrue(t, errors.Is(err, ErrHeld), "shared account should be refused")
+	other := NewFileLock(dir, coinbase, "other")
+	testutils.AssertNoError(t, other.Acquire(ctx), "locks taken by a refused instance should be released")
+	testutils.AssertNoError(t, other.Release(ctx), "Release should not return error")
+	testutils.AssertNoError(t, single.Release(ctx), "Release should not return error")
+}
+
+func TestRedisLock(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	ctx := context.Background()
+	url := fmt.Sprintf("redis://:secret@%s/2", server.addr)
+
+	first, err := NewRedisLock(url, "account", "first", time.Minute)
+	testutils.AssertNoError(t, err, "NewRedisLock should not return error")
+	second, err := NewRedisLock(url, "account", "second", time.Minute)
+	testutils.AssertNoError(t, err, "NewRedisLock should not return error")
+
+	testutils.AssertNoError(t, first.Acquire(ctx), "first instance should take the lease")
+	err = second.Acquire(ctx)
+	testutils.AssertTrue(t, errors.Is(err, ErrHeld), "second instance should be refused")
+	testutils.AssertTrue(t, strings.Contains(err.Error(), "first"), "error should name the holder")
+	testutils.AssertNoError(t, first.Renew(ctx), "holder should renew the lease")
+	testutils.AssertTrue(t, errors.Is(second.Renew(ctx), ErrLost), "others cannot renew the lease")
+
+	// Expired leases are taken over, and the previous holder loses them
+	server.expire("account")
+	testutils.AssertNoError(t, second.Acquire(ctx), "expired lease should be taken over")
+	testutils.AssertTrue(t, errors.Is(first.Renew(ctx), ErrLost), "previous holder should lose the lease")
+	testutils.AssertNoError(t, first.Release(ctx), "releasing a lost lease should not fail")
+	testutils.AssertEqual(t, "second", server.get("account"), "release should keep the lease of the new holder")
+
+	testutils.AssertNoError(t, second.Release(ctx), "Release should not return error")
+	testutils.AssertEqual(t, "", server.get("account"), "release should delete the lease")
+
+	wrong, err := NewRedisLock(fmt.Sprintf("redis://:wrong@%s", server.addr), "account", "third", time.Minute)
+	testutils.AssertNoError(t, err, "NewRedisLock should not return error")
+	testutils.AssertError(t, wrong.Acquire(ctx), "wrong password should fail")
+
+	_, err = NewRedisLock("http://localhost", "account", "first", time.Minute)
+	testutils.AssertError(t, err, "non Redis URLs should be refused")
+}
+
+func TestRedisLock_ACLUser(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	server.username = "bot"
+	ctx := context.Background()
+
+	lock, err := NewRedisLock(fmt.Sprintf("redis://bot:secret@%s", server.addr), "account", "first", time.Minute)
+	testutils.AssertNoError(t, err, "NewRedisLock should not return error")
+	testutils.AssertNoError(t, lock.Acquire(ctx), "ACL user should take the lease")
+	testutils.AssertEqual(t, "first", server.get("account"), "lease should be set")
+
+	other, err := NewRedisLock(fmt.Sprintf("redis://:secret@%s", server.addr), "account", "second", time.Minute)
+	testutils.AssertNoError(t, err, "NewRedisLock should not return error")
+	testutils.AssertError(t, other.Acquire(ctx), "default user should be refused")
+}
+
+// flakyLock fails renewals with the errors queued
+type flakyLock struct {
+	mu     sync.Mutex
+	errors []error
+}
+
+func (f *flakyLock) Acquire(context.Context) error { return nil }
+func (f *flakyLock) Release(context.Context) error { return nil }
+func (f *flakyLock) Renew(context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.errors) == 0 {
+		return nil
+	}
+	err := f.errors[0]
+	f.errors = f.errors[1:]
+	return err
+}
+
+func TestKeep(t *testing.T) {
+	lost := make(chan error, 1)
+	lock := &flakyLock{errors: []error{nil, errors.New("connection refused"), ErrLost}}
+	go Keep(context.Background(), lock, time.Millisecond, time.Hour, func(err error) { lost <- err })
+	select {
+	case err := <-lost:
+		testutils.AssertTrue(t, errors.Is(err, ErrLost), "lost lease should be reported")
+	case <-time.After(time.Second):
+		t.Fatal("lost lease was not reported")
+	}
+
+	// Renewals failing for the whole TTL may have let another instance in
+	failing := make([]error, 100)
+	for i := range failing {
+		failing[i] = errors.New("connection refused")
+	}
+	go Keep(context.Background(), &flakyLock{errors: failing}, time.Millisecond, 10*time.Millisecond, func(err error) { lost <- err })
+	select {
+	case err := <-lost:
+		testutils.AssertTrue(t, errors.Is(err, ErrLost), "unrenewed lease should be reported lost")
+	case <-time.After(time.Second):
+		t.Fatal("unrenewed lease was not reported")
+	}
+}
+
+// fakeRedis serves the commands used by the lease
+type fakeRedis struct {
+	addr     string
+	username string // ACL user, default when empty
+	password string
+	mu       sync.Mutex
+	values   map[string]string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	testutils.AssertNoError(t, err, "fake Redis should listen")
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeRedis{addr: listener.Addr().String(), password: password, values: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (s *fakeRedis) expire(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+func (s *fakeRedis) get(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.values[key]
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := s.password == ""
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		if strings.ToUpper(args[0]) == "AUTH" {
+			// AUTH password authenticates the default user
+			username, password := "", args[len(args)-1]
+			if len(args) == 3 {
+				username = args[1]
+			}
+			if username != s.username || password != s.password {
+				io.WriteString(conn, "-WRONGPASS invalid password\r\n")
+				continue
+			}
+			authenticated = true
+			io.WriteString(conn, "+OK\r\n")
+			continue
+		}
+		if !authenticated {
+			io.WriteString(conn, "-NOAUTH Authentication required\r\n")
+			continue
+		}
+		io.WriteString(conn, s.apply(args))
+	}
+}
+
+func (s *fakeRedis) apply(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "SELECT":
+		return "+OK\r\n"
+	case "SET":
+		if _, held := s.values[args[1]]; held {
+			return "$-1\r\n"
+		}
+		s.values[args[1]] = args[2]
+		return "+OK\r\n"
+	case "GET":
+		value, ok := s.values[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+	case "EVAL":
+		key, owner := args[3], args[4]
+		if s.values[key] != owner {
+			return ":0\r\n"
+		}
+		if args[1] == releaseScript {
+			delete(s.values, key)
+		}
+		return ":1\r\n"
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, count)
+	for i := range args {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
@@ -0,0 +1,49 @@
+package instancelock
+
+import (
+	"context"
+	"errors"
+)
+
+// Locks holds several locks as one, such as the locks of every account an
+// instance trades. Two instances sharing any account exclude each other even
+// when they trade different sets of accounts.
+type Locks []Lock
+
+// Acquire takes every lock in order. When one cannot be taken the locks
+// already taken are released and its error is returned.
+func (l Locks) Acquire(ctx context.Context) error {
+	for i, lock := range l {
+		if err := lock.Acquire(ctx); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				_ = l[j].Release(context.WithoutCancel(ctx))
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Renew extends every lock, failing with an error wrapping ErrLost as soon as
+// one of them is lost
+func (l Locks) Renew(ctx context.Context) error {
+	var errs []error
+	for _, lock := range l {
+		if err := lock.Renew(ctx); err != nil {
+			if errors.Is(err, ErrLost) {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Release lets another instance take every lock
+func (l Locks) Release(ctx context.Context) error {
+	var errs []error
+	for _, lock := range l {
+		errs = append(errs, lock.Release(ctx))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,188 @@
+package instancelock
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Extends the lease only while this instance still owns it
+	renewScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) else return 0 end`
+	// Deletes the lease only while this instance still owns it
+	releaseScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+)
+
+// RedisLock is a lease on a Redis key expiring after its TTL unless renewed,
+// so the lock of a crashed or partitioned instance is freed by itself
+type RedisLock struct {
+	addr     string
+	username string
+	password string
+	db       int
+	key      string
+	owner    string
+	ttl      time.Duration
+	dialer   net.Dialer
+}
+
+// NewRedisLock creates a lease on key for the Redis server at rawURL
+// (redis://[[username]:password@]host:port[/db]). The username of a Redis 6
+// ACL user is optional, the default user is used without it.
+func NewRedisLock(rawURL, key, owner string, ttl time.Duration) (*RedisLock, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	if parsed.Scheme != "redis" || parsed.Host == "" {
+		return nil, errors.New("invalid Redis URL, expected redis://[[username]:password@]host:port[/db]")
+	}
+	lock := &RedisLock{
+		addr:  parsed.Host,
+		key:   key,
+		owner: owner,
+		ttl:   ttl,
+	}
+	if parsed.Port() == "" {
+		lock.addr = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+	if password, ok := parsed.User.Password(); ok {
+		lock.username = parsed.User.Username()
+		lock.password = password
+	}
+	if db := strings.TrimPrefix(parsed.Path, "/"); db != "" {
+		if lock.db, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid Redis database %q", db)
+		}
+	}
+	return lock, nil
+}
+
+// Acquire sets the key to the owner unless another instance holds it
+func (l *RedisLock) Acquire(ctx context.Context) error {
+	reply, err := l.do(ctx, "SET", l.key, l.owner, "NX", "PX", l.ttlMillis())
+	if err != nil {
+		return err
+	}
+	if reply != nil {
+		return nil
+	}
+
+	holder, err := l.do(ctx, "GET", l.key)
+	if err != nil || holder == nil {
+		return fmt.Errorf("%w: %s", ErrHeld, l.key)
+	}
+	return fmt.Errorf("%w: %v (%s)", ErrHeld, holder, l.key)
+}
+
+// Renew extends the lease by its TTL
+func (l *RedisLock) Renew(ctx context.Context) error {
+	reply, err := l.do(ctx, "EVAL", renewScript, "1", l.key, l.owner, l.ttlMillis())
+	if err != nil {
+		return err
+	}
+	if reply != int64(1) {
+		return fmt.Errorf("%w: %s expired or taken over", ErrLost, l.key)
+	}
+	return nil
+}
+
+// Release deletes the key if this instance still holds it
+func (l *RedisLock) Release(ctx context.Context) error {
+	_, err := l.do(ctx, "EVAL", releaseScript, "1", l.key, l.owner)
+	return err
+}
+
+func (l *RedisLock) ttlMillis() string {
+	return strconv.FormatInt(l.ttl.Milliseconds(), 10)
+}
+
+// do runs a command on a new connection, authenticated and on the database
+// of the URL. Leases are renewed seldom enough that a connection per command
+// costs nothing, and none is left broken by a network failure.
+func (l *RedisLock) do(ctx context.Context, args ...string) (any, error) {
+	conn, err := l.dialer.DialContext(ctx, "tcp", l.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	defer conn.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	reader := bufio.NewReader(conn)
+	if l.password != "" {
+		auth := []string{"AUTH", l.password}
+		if l.username != "" {
+			auth = []string{"AUTH", l.username, l.password}
+		}
+		if _, err := command(conn, reader, auth...); err != nil {
+			return nil, err
+		}
+	}
+	if l.db != 0 {
+		if _, err := command(conn, reader, "SELECT", strconv.Itoa(l.db)); err != nil {
+			return nil, err
+		}
+	}
+	return command(conn, reader, args...)
+}
+
+// command writes a command in the Redis protocol and reads its reply: a
+// string, an int64, nil, or an error for error replies
+func command(w io.Writer, r *bufio.Reader, args ...string) (any, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return nil, fmt.Errorf("failed to send Redis command: %w", err)
+	}
+	return readReply(r)
+}
+
+// readReply reads a single reply. Arrays are not used by the lease.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Redis reply: %w", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("empty Redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid Redis reply %q", line)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("failed to read Redis reply: %w", err)
+		}
+		return string(data[:size]), nil
+	default:
+		return nil, fmt.Errorf("unsupported Redis reply %q", line)
+	}
+}
@@ -0,0 +1,201 @@
+// Package journal keeps an append-only record of the trades the bot enters,
+// together with the signal and indicator snapshot that triggered them, of
+// how their orders filled and of the stops moved while they were open.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/statefile"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/shopspring/decimal"
+)
+
+// Entry is a single journaled trade
+type Entry struct {
+	Time     time.Time           `json:"time"`
+	Symbol   string              `json:"symbol"`
+	Side     exchanges.OrderSide `json:"side"`
+	Strategy string              `json:"strategy,omitempty"`
+	OrderID  string              `json:"order_id,omitempty"`
+	Price    decimal.Decimal     `json:"price"`
+	Amount   decimal.Decimal     `json:"amount"`
+	Strength float64             `json:"strength"`
+	// SizeMultiplier is the share of the risk-based size the entry was
+	// placed at for its strength
+	SizeMultiplier float64                    `json:"size_multiplier,omitempty"`
+	Reason         string                     `json:"reason"`
+	Components     *strategy.SignalComponents `json:"components,omitempty"`
+	// Execution is set on the entries recording a filled order
+	Execution *order.Execution `json:"execution,omitempty"`
+	// StopMove is set on the entries recording a stop loss moved
+	StopMove *StopMove `json:"stop_move,omitempty"`
+}
+
+// StopMove is a stop loss moved while a position was open
+type StopMove struct {
+	From decimal.Decimal `json:"from"`
+	To   decimal.Decimal `json:"to"`
+	Mark decimal.Decimal `json:"mark"` // Price the move was decided at
+}
+
+// NewEntry builds the journal entry for an order placed on signal
+func NewEntry(signal *strategy.Signal, placed *exchanges.Order) Entry {
+	entry := Entry{
+		Symbol:     signal.Symbol,
+		Side:       signal.Side,
+		Strategy:   signal.Strategy,
+		Price:      signal.Price,
+		Strength:   signal.Strength,
+		Reason:     signal.Reason,
+		Components: signal.Components,
+	}
+	if placed != nil {
+		entry.OrderID = placed.ID
+		entry.Amount = placed.Amount
+		if placed.Price.IsPositive() {
+			entry.Price = placed.Price
+		}
+	}
+	return entry
+}
+
+// NewFill builds the journal entry for a filled order
+func NewFill(execution order.Execution) Entry {
+	return Entry{
+		Time:      execution.Time,
+		Symbol:    execution.Symbol,
+		Side:      execution.Side,
+		Strategy:  execution.Strategy,
+		OrderID:   execution.OrderID,
+		Price:     execution.FillPrice,
+		Amount:    execution.Amount,
+		Reason:    "fill",
+		Execution: &execution,
+	}
+}
+
+// NewStopMove builds the journal entry for the stop loss of the position on
+// symbol and side moved, priced at its new level
+func NewStopMove(symbol string, side order.PositionSide, strategy, reason string, move StopMove) Entry {
+	entrySide := exchanges.OrderSideBuy
+	if side == order.PositionSideShort {
+		entrySide = exchanges.OrderSideSell
+	}
+	return Entry{
+		Symbol:   symbol,
+		Side:     entrySide,
+		Strategy: strategy,
+		Price:    move.To,
+		Reason:   reason,
+		StopMove: &move,
+	}
+}
+
+// Executions returns the fills recorded in entries
+func Executions(entries []Entry) []order.Execution {
+	var executions []order.Execution
+	for _, entry := range entries {
+		if entry.Execution != nil {
+			executions = append(executions, *entry.Execution)
+		}
+	}
+	return executions
+}
+
+// Journal writes trade entries to a JSON lines stream. Every entry is flushed
+// as it is written so the journal survives a crash.
+type Journal struct {
+	mu      sync.Mutex
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	closer  io.Closer
+	now     func() time.Time
+}
+
+// New creates a journal that writes to w
+func New(w io.Writer) *Journal {
+	buffered := bufio.NewWriter(w)
+	j := &Journal{
+		writer:  buffered,
+		encoder: json.NewEncoder(statefile.NewLineWriter(buffered)),
+		now:     time.Now,
+	}
+	if c, ok := w.(io.Closer); ok {
+		j.closer = c
+	}
+	return j
+}
+
+// NewFile creates a journal appending to the file at path
+func NewFile(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trade journal: %w", err)
+	}
+	return New(file), nil
+}
+
+// Record writes a single entry, stamping it with the current time if unset
+func (j *Journal) Record(entry Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.encoder == nil {
+		return fmt.Errorf("journal closed")
+	}
+	if entry.Time.IsZero() {
+		entry.Time = j.now()
+	}
+	if err := j.encoder.Encode(&entry); err != nil {
+		return fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	return j.writer.Flush()
+}
+
+// Close flushes and closes the underlying writer if possible
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.writer == nil {
+		return nil
+	}
+	err := j.writer.Flush()
+	j.writer = nil
+	j.encoder = nil
+	if j.closer != nil {
+		if cerr := j.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Load reads every entry from a journal file
+func Load(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trade journal: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	decoder := json.NewDecoder(statefile.NewLineReader(file))
+	for decoder.More() {
+		var entry Entry
+		if err := decoder.Decode(&entry); err != nil {
+			return nil, fmt.Errorf("failed to read journal entry %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
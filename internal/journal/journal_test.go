@@ -0,0 +1,108 @@
+package journal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestJournal_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := NewFile(path)
+	testutils.AssertNoError(t, err, "NewFile should not return error")
+
+	signal := &strategy.Signal{
+		Type:     strategy.SignalTypeEntry,
+		Side:     exchanges.OrderSideBuy,
+		Symbol:   "BTC-USD",
+		Price:    decimal.NewFromFloat(100),
+		Strength: 0.8,
+		Reason:   "EMA crossover + RSI oversold",
+		Components: &strategy.SignalComponents{
+			ShortEMA:   decimal.NewFromFloat(101),
+			LongEMA:    decimal.NewFromFloat(99),
+			RSI:        decimal.NewFromFloat(28),
+			BBPosition: 0.2,
+			Weights:    strategy.IndicatorWeights{EMA: 0.4, RSI: 0.6},
+		},
+	}
+	placed := &exchanges.Order{ID: "order-1", Price: decimal.NewFromFloat(100.5), Amount: decimal.NewFromFloat(0.1)}
+
+	testutils.AssertNoError(t, j.Record(NewEntry(signal, placed)), "Record should not return error")
+	// Entries are flushed as they are written
+	entries, err := Load(path)
+	testutils.AssertNoError(t, err, "Load should not return error")
+	testutils.AssertEqual(t, 1, len(entries), "journal should hold one entry")
+	testutils.AssertNoError(t, j.Close(), "Close should not return error")
+
+	entry := entries[0]
+	testutils.AssertEqual(t, "order-1", entry.OrderID, "entry should reference the placed order")
+	testutils.AssertTrue(t, entry.Price.Equal(decimal.NewFromFloat(100.5)), "entry should use the order price")
+	testutils.AssertTrue(t, entry.Amount.Equal(decimal.NewFromFloat(0.1)), "entry should use the order amount")
+	testutils.AssertFalse(t, entry.Time.IsZero(), "entry should be timestamped")
+	testutils.AssertNotNil(t, entry.Components, "entry should keep the signal components")
+	testutils.AssertTrue(t, entry.Components.RSI.Equal(decimal.NewFromFloat(28)), "RSI should round trip")
+	testutils.AssertEqual(t, 0.6, entry.Components.Weights.RSI, "weights should round trip")
+
+	testutils.AssertError(t, j.Record(entry), "Record after Close should fail")
+}
+
+func TestJournal_Fills(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := NewFile(path)
+	testutils.AssertNoError(t, err, "NewFile should not return error")
+
+	signal := &strategy.Signal{Side: exchanges.OrderSideBuy, Symbol: "BTC-USD", Price: decimal.NewFromFloat(100)}
+	testutils.AssertNoError(t, j.Record(NewEntry(signal, nil)), "Record should not return error")
+	testutils.AssertNoError(t, j.Record(NewFill(order.Execution{
+		Exchange:       "coinbase",
+		Symbol:         "BTC-USD",
+		Side:           exchanges.OrderSideBuy,
+		OrderID:        "order-1",
+		ReferencePrice: decimal.NewFromFloat(100),
+		FillPrice:      decimal.NewFromFloat(100.1),
+		Amount:         decimal.NewFromFloat(0.1),
+		SlippageBps:    decimal.NewFromInt(10),
+		Liquidity:      order.LiquidityTaker,
+	})), "Record should not return error")
+	testutils.AssertNoError(t, j.Close(), "Close should not return error")
+
+	entries, err := Load(path)
+	testutils.AssertNoError(t, err, "Load should not return error")
+	testutils.AssertEqual(t, 2, len(entries), "journal should hold the entry and the fill")
+
+	executions := Executions(entries)
+	testutils.AssertEqual(t, 1, len(executions), "only fills should be executions")
+	testutils.AssertTrue(t, entries[1].Price.Equal(decimal.NewFromFloat(100.1)), "fill entry should use the fill price")
+	testutils.AssertTrue(t, executions[0].SlippageBps.Equal(decimal.NewFromInt(10)), "slippage should round trip")
+	testutils.AssertEqual(t, order.LiquidityTaker, executions[0].Liquidity, "liquidity should round trip")
+}
+
+func TestJournal_StopMoves(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := NewFile(path)
+	testutils.AssertNoError(t, err, "NewFile should not return error")
+
+	testutils.AssertNoError(t, j.Record(NewStopMove("ETH-USD", order.PositionSideShort, "fast", "break_even", StopMove{
+		From: decimal.NewFromFloat(105),
+		To:   decimal.NewFromFloat(99.9),
+		Mark: decimal.NewFromFloat(98),
+	})), "Record should not return error")
+	testutils.AssertNoError(t, j.Close(), "Close should not return error")
+
+	entries, err := Load(path)
+	testutils.AssertNoError(t, err, "Load should not return error")
+	testutils.AssertEqual(t, 1, len(entries), "journal should hold the stop move")
+	entry := entries[0]
+	testutils.AssertEqual(t, exchanges.OrderSideSell, entry.Side, "short positions should be journaled as sells")
+	testutils.AssertEqual(t, "break_even", entry.Reason, "reason should round trip")
+	testutils.AssertNotNil(t, entry.StopMove, "stop move should round trip")
+	testutils.AssertTrue(t, entry.StopMove.From.Equal(decimal.NewFromFloat(105)), "previous level should round trip")
+	testutils.AssertTrue(t, entry.Price.Equal(decimal.NewFromFloat(99.9)), "entry should be priced at the new level")
+	testutils.AssertEqual(t, 0, len(Executions(entries)), "stop moves should not be executions")
+}
@@ -0,0 +1,195 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+)
+
+// DefaultStopTimeout bounds how long a single component may take to stop
+const DefaultStopTimeout = 10 * time.Second
+
+// Component is a subsystem started with the bot and stopped on shutdown
+type Component interface {
+	Name() string
+	// Start starts the component. ctx is the component's lifetime: it is
+	// canceled when the bot shuts down.
+	Start(ctx context.Context) error
+	// Stop stops the component. ctx bounds how long it may take.
+	Stop(ctx context.Context) error
+}
+
+// funcComponent adapts start and stop functions to a Component
+type funcComponent struct {
+	name  string
+	start func(ctx context.Context) error
+	stop  func() error
+}
+
+// NewComponent creates a component from the Start and Stop methods of a
+// subsystem that manages its own goroutines. stop may be nil.
+func NewComponent(name string, start func(ctx context.Context) error, stop func() error) Component {
+	return &funcComponent{name: name, start: start, stop: stop}
+}
+
+func (c *funcComponent) Name() string { return c.name }
+
+func (c *funcComponent) Start(ctx context.Context) error { return c.start(ctx) }
+
+func (c *funcComponent) Stop(context.Context) error {
+	if c.stop == nil {
+		return nil
+	}
+	return c.stop()
+}
+
+// service runs a loop in its own goroutine until it is stopped
+type service struct {
+	name string
+	run  func(ctx context.Context)
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewService creates a component running run in a goroutine. run must
+// return once its context is canceled; Stop cancels it and waits for it to
+// return.
+func NewService(name string, run func(ctx context.Context)) Component {
+	return &service{name: name, run: run}
+}
+
+func (s *service) Name() string { return s.name }
+
+func (s *service) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("%s already running", s.name)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	s.cancel, s.done = cancel, done
+	go func() {
+		defer close(done)
+		s.run(runCtx)
+	}()
+	return nil
+}
+
+func (s *service) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.cancel, s.done = nil, nil
+	s.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Manager starts components in the order they were registered and stops
+// them in reverse order, so a component can rely on the ones registered
+// before it for its whole lifetime
+type Manager struct {
+	stopTimeout time.Duration
+
+	mu         sync.Mutex
+	components []Component
+	started    []Component
+}
+
+// NewManager creates a manager giving each component stopTimeout to stop,
+// DefaultStopTimeout when zero
+func NewManager(stopTimeout time.Duration) *Manager {
+	if stopTimeout <= 0 {
+		stopTimeout = DefaultStopTimeout
+	}
+	return &Manager{stopTimeout: stopTimeout}
+}
+
+// Register adds components, started by the next StartAll
+func (m *Manager) Register(components ...Component) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, components...)
+}
+
+// StartAll starts the components not started yet, in registration order.
+// If one fails, the components started by this call are stopped again and
+// the error is returned.
+func (m *Manager) StartAll(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	log := logger.Component("lifecycle")
+	before := len(m.started)
+	for _, component := range m.components[before:] {
+		if err := component.Start(ctx); err != nil {
+			for i := len(m.started) - 1; i >= before; i-- {
+				if stopErr := m.stop(m.started[i]); stopErr != nil {
+					log.Error("failed to stop component", "component", m.started[i].Name(), "error", stopErr)
+				}
+			}
+			m.started = m.started[:before]
+			return fmt.Errorf("failed to start %s: %w", component.Name(), err)
+		}
+		m.started = append(m.started, component)
+		log.Debug("component started", "component", component.Name())
+	}
+	return nil
+}
+
+// StopAll stops the started components in reverse registration order. A
+// component that fails or exceeds the stop timeout is reported and the
+// next one is stopped anyway. Stopped components are started again by the
+// next StartAll.
+func (m *Manager) StopAll() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	log := logger.Component("lifecycle")
+	var errs []error
+	for i := len(m.started) - 1; i >= 0; i-- {
+		component := m.started[i]
+		if err := m.stop(component); err != nil {
+			log.Error("failed to stop component", "component", component.Name(), "error", err)
+			errs = append(errs, fmt.Errorf("failed to stop %s: %w", component.Name(), err))
+			continue
+		}
+		log.Debug("component stopped", "component", component.Name())
+	}
+	m.started = nil
+	return errors.Join(errs...)
+}
+
+// stop stops component within the stop timeout. A Stop ignoring its
+// context is abandoned once the timeout expires.
+func (m *Manager) stop(component Component) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.stopTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- component.Stop(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %s", m.stopTimeout)
+	}
+}
@@ -0,0 +1,121 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) component(name string, startErr error) Component {
+	return NewComponent(name,
+		func(context.Context) error {
+			if startErr != nil {
+				return startErr
+			}
+			r.events = append(r.events, "start "+name)
+			return nil
+		},
+		func() error {
+			r.events = append(r.events, "stop "+name)
+			return nil
+		})
+}
+
+func TestManager_StartsInOrderAndStopsInReverse(t *testing.T) {
+	r := &recorder{}
+	m := NewManager(time.Second)
+	m.Register(r.component("orders", nil), r.component("engine", nil))
+	m.Register(r.component("strategy", nil))
+
+	if err := m.StartAll(context.Background()); err != nil {
+		t.Fatalf("StartAll failed: %v", err)
+	}
+	if err := m.StopAll(); err != nil {
+		t.Fatalf("StopAll failed: %v", err)
+	}
+
+	want := []string{"start orders", "start engine", "start strategy", "stop strategy", "stop engine", "stop orders"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Errorf("expected %v, got %v", want, r.events)
+	}
+
+	// Stopping again is a no-op
+	if err := m.StopAll(); err != nil || len(r.events) != len(want) {
+		t.Errorf("expected a second StopAll to do nothing, got %v and %v", err, r.events)
+	}
+}
+
+func TestManager_StartFailureStopsStartedComponents(t *testing.T) {
+	r := &recorder{}
+	m := NewManager(time.Second)
+	m.Register(r.component("orders", nil), r.component("engine", nil), r.component("strategy", errors.New("no candles")))
+
+	err := m.StartAll(context.Background())
+	if err == nil {
+		t.Fatal("expected StartAll to fail")
+	}
+
+	want := []string{"start orders", "start engine", "stop engine", "stop orders"}
+	if !reflect.DeepEqual(r.events, want) {
+		t.Errorf("expected %v, got %v", want, r.events)
+	}
+	if err := m.StopAll(); err != nil || len(r.events) != len(want) {
+		t.Errorf("expected nothing left to stop, got %v and %v", err, r.events)
+	}
+}
+
+func TestManager_StopTimeout(t *testing.T) {
+	r := &recorder{}
+	release := make(chan struct{})
+	defer close(release)
+
+	m := NewManager(20 * time.Millisecond)
+	m.Register(
+		r.component("orders", nil),
+		NewComponent("stuck", func(context.Context) error { return nil }, func() error {
+			<-release
+			return nil
+		}),
+	)
+	if err := m.StartAll(context.Background()); err != nil {
+		t.Fatalf("StartAll failed: %v", err)
+	}
+
+	if err := m.StopAll(); err == nil {
+		t.Fatal("expected the stuck component to time out")
+	}
+	// The components registered before it are stopped anyway
+	if want := []string{"start orders", "stop orders"}; !reflect.DeepEqual(r.events, want) {
+		t.Errorf("expected %v, got %v", want, r.events)
+	}
+}
+
+func TestService_StopWaitsForLoop(t *testing.T) {
+	exited := false
+	service := NewService("loop", func(ctx context.Context) {
+		<-ctx.Done()
+		exited = true
+	})
+
+	m := NewManager(time.Second)
+	m.Register(service)
+	if err := m.StartAll(context.Background()); err != nil {
+		t.Fatalf("StartAll failed: %v", err)
+	}
+	if err := service.Start(context.Background()); err == nil {
+		t.Error("expected starting a running service to fail")
+	}
+
+	if err := m.StopAll(); err != nil {
+		t.Fatalf("StopAll failed: %v", err)
+	}
+	if !exited {
+		t.Error("expected the loop to have returned")
+	}
+}
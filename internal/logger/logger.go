@@ -17,6 +17,9 @@ type Config struct {
 	Format     string // "json" or "text"
 	AddSource  bool
 	OutputPath string // empty means stdout
+	// Redact masks secrets, addresses, recovery phrase fragments and order
+	// IDs in every attribute
+	Redact bool
 }
 
 // DefaultConfig returns default logger configuration
@@ -25,6 +28,7 @@ func DefaultConfig() *Config {
 		Level:     slog.LevelInfo,
 		Format:    "json",
 		AddSource: false,
+		Redact:    true,
 	}
 }
 
@@ -38,6 +42,9 @@ func New(config *Config) *Logger {
 		Level:     config.Level,
 		AddSource: config.AddSource,
 	}
+	if config.Redact {
+		opts.ReplaceAttr = redactAttr
+	}
 
 	var handler slog.Handler
 	output := os.Stdout
@@ -161,6 +168,13 @@ func Default() *Logger {
 	return defaultLogger
 }
 
+// DebugEnabled reports whether the default logger writes debug messages.
+// Hot paths check it before building debug attributes, which allocate even
+// when the message is dropped.
+func DebugEnabled() bool {
+	return defaultLogger.Enabled(context.Background(), slog.LevelDebug)
+}
+
 // Convenience functions using default logger
 
 // Debug logs a debug message
@@ -266,6 +266,20 @@ func TestLogLevels(t *testing.T) {
 	}
 }
 
+func TestDebugEnabled(t *testing.T) {
+	previous := Default()
+	defer SetDefault(previous)
+
+	SetDefault(New(&Config{Level: slog.LevelInfo}))
+	if DebugEnabled() {
+		t.Error("Debug should be disabled at info level")
+	}
+	SetDefault(New(&Config{Level: slog.LevelDebug}))
+	if !DebugEnabled() {
+		t.Error("Debug should be enabled at debug level")
+	}
+}
+
 // Helper type for testing
 type testError struct {
 	msg string
@@ -274,3 +288,41 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.msg
 }
+
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := &Logger{
+		Logger: slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: redactAttr})),
+	}
+
+	mnemonic := "abandon ability able about above absent absorb abstract absurd abuse access accident"
+	address := "0x1234567890abcdef1234567890abcdef12345678"
+	logger.WithField("wallet", address).Info("order placed",
+		"client_order_id", "7f3a9c1e-0001",
+		"api_key", "organizations/abc/apiKeys/def",
+		"error", &testError{msg: "signer " + address + " rejected"},
+		"note", "restored from "+mnemonic,
+		"symbol", "BTC-USD",
+	)
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to parse log line: %v", err)
+	}
+	for key, want := range map[string]string{
+		"wallet":          "…5678",
+		"client_order_id": "…0001",
+		"api_key":         "[REDACTED]",
+		"error":           "signer …5678 rejected",
+		"note":            "restored from [REDACTED]",
+		"symbol":          "BTC-USD",
+		"msg":             "order placed",
+	} {
+		if entry[key] != want {
+			t.Errorf("%s: expected %q, got %q", key, want, entry[key])
+		}
+	}
+	if strings.Contains(buf.String(), "abandon ability") {
+		t.Error("expected the recovery phrase to be redacted")
+	}
+}
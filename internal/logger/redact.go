@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	redacted = "[REDACTED]"
+	// Consecutive recovery phrase words taken for a mnemonic fragment
+	minMnemonicWords = 6
+)
+
+var (
+	// Keys whose values are secrets, hidden entirely
+	secretKeys = []string{"mnemonic", "private_key", "privatekey", "secret", "passphrase", "password", "api_key", "apikey"}
+	// Keys whose values identify the account or its orders, masked but for
+	// their last characters so log lines can still be correlated
+	identifierKeys = []string{"address", "wallet", "order_id", "orderid", "cloid", "oid", "portfolio_id", "subaccount"}
+
+	// Ethereum (Hyperliquid) and dYdX addresses appearing in any value
+	addressPattern = regexp.MustCompile(`0x[0-9a-fA-F]{40}|dydx1[02-9ac-hj-np-z]{38}`)
+)
+
+// redactAttr masks the sensitive values of an attribute: secrets, account
+// and order identifiers, and addresses or recovery phrase fragments found in
+// strings and errors
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) == 0 {
+		switch attr.Key {
+		case slog.TimeKey, slog.LevelKey, slog.SourceKey, slog.MessageKey:
+			return attr
+		}
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		return attr
+	}
+	key := strings.ToLower(attr.Key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return slog.String(attr.Key, redacted)
+		}
+	}
+	for _, identifier := range identifierKeys {
+		if key == identifier || key == identifier+"s" || strings.HasSuffix(key, "_"+identifier) || strings.HasSuffix(key, "_"+identifier+"s") {
+			return slog.String(attr.Key, maskIdentifier(attr.Value.Resolve().String()))
+		}
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		if value := redactString(attr.Value.String()); value != attr.Value.String() {
+			return slog.String(attr.Key, value)
+		}
+	case slog.KindAny:
+		if err, ok := attr.Value.Any().(error); ok && err != nil {
+			if value := redactString(err.Error()); value != err.Error() {
+				return slog.String(attr.Key, value)
+			}
+		}
+	}
+	return attr
+}
+
+// maskIdentifier keeps the last 4 characters of an identifier
+func maskIdentifier(value string) string {
+	if len(value) <= 4 {
+		return redacted
+	}
+	return "…" + value[len(value)-4:]
+}
+
+// redactString masks the addresses and recovery phrase fragments in value
+func redactString(value string) string {
+	value = addressPattern.ReplaceAllStringFunc(value, maskIdentifier)
+	return redactMnemonics(value)
+}
+
+// redactMnemonics replaces runs of recovery phrase words, which ordinary
+// messages do not chain, with a placeholder
+func redactMnemonics(value string) string {
+	words := strings.Fields(value)
+	if len(words) < minMnemonicWords {
+		return value
+	}
+
+	var result []string
+	run := 0
+	flush := func(end int) {
+		if run >= minMnemonicWords {
+			result = append(result, redacted)
+		} else {
+			result = append(result, words[end-run:end]...)
+		}
+		run = 0
+	}
+	changed := false
+	for i, word := range words {
+		if _, ok := bip39.GetWordIndex(word); ok {
+			run++
+			continue
+		}
+		changed = changed || run >= minMnemonicWords
+		flush(i)
+		result = append(result, word)
+	}
+	changed = changed || run >= minMnemonicWords
+	flush(len(words))
+	if !changed {
+		return value
+	}
+	return strings.Join(result, " ")
+}
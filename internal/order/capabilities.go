@@ -0,0 +1,104 @@
+package order
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	ordererrors "github.com/guyghost/constantine/internal/order/errors"
+	"github.com/shopspring/decimal"
+)
+
+// Capabilities returns the features of the manager's exchange
+func (m *Manager) Capabilities() exchanges.Capabilities {
+	return m.capabilities
+}
+
+// checkCapabilities rejects requests the exchange cannot execute before they
+// are sent. Requests asking for a feature that only improves execution, like
+// post-only, are degraded by PlaceOrder instead.
+func (m *Manager) checkCapabilities(req *OrderRequest) error {
+	if req.Type == exchanges.OrderTypeStopLimit && !m.capabilities.StopOrders {
+		return ordererrors.New(ordererrors.OperationValidate, req.Symbol,
+			fmt.Errorf("%w: %s has no native stop orders", exchanges.ErrNotSupported, m.exchange.Name()))
+	}
+	if req.Type == exchanges.OrderTypeStopMarket && !m.capabilities.StopMarket {
+		return ordererrors.New(ordererrors.OperationValidate, req.Symbol,
+			fmt.Errorf("%w: %s has no native stop market orders", exchanges.ErrNotSupported, m.exchange.Name()))
+	}
+	if req.Options != nil && req.Options.TWAP != nil {
+		if !m.capabilities.NativeTWAP {
+			return ordererrors.New(ordererrors.OperationValidate, req.Symbol,
+				fmt.Errorf("%w: %s has no native TWAP orders", exchanges.ErrNotSupported, m.exchange.Name()))
+		}
+		if req.Type != exchanges.OrderTypeMarket {
+			return ordererrors.New(ordererrors.OperationValidate, req.Symbol,
+				fmt.Errorf("%w: TWAP orders are market orders", exchanges.ErrInvalidOrder))
+		}
+	}
+	if !m.capabilities.Shorts && m.opensShort(req) {
+		return ordererrors.New(ordererrors.OperationValidate, req.Symbol,
+			fmt.Errorf("%w: %s cannot open short positions", exchanges.ErrNotSupported, m.exchange.Name()))
+	}
+	return nil
+}
+
+// reduceOnlyAmount returns the amount a reduce-only request is sent with. The
+// exchange enforces reduce-only when it can; otherwise the request is cut down
+// to the position it reduces, and rejected when there is none, so an exit
+// never opens or increases a position.
+func (m *Manager) reduceOnlyAmount(ctx context.Context, req *OrderRequest) (decimal.Decimal, error) {
+	if !req.ReduceOnly || m.capabilities.ReduceOnly {
+		return req.Amount, nil
+	}
+
+	reduced := positionSideFor(exitSide(req.Side))
+	held := decimal.Zero
+	m.mu.RLock()
+	position, managed := m.orderBook.Positions[m.positionKey(req.Symbol, reduced)]
+	if managed && position.Side == reduced && position.Status == PositionStatusOpen {
+		held = position.Amount
+	}
+	m.mu.RUnlock()
+
+	if !managed {
+		callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
+		defer cancel()
+		positions, err := m.exchange.GetPositions(callCtx)
+		if err != nil {
+			return decimal.Zero, ordererrors.New(ordererrors.OperationValidate, req.Symbol,
+				fmt.Errorf("failed to check the position a reduce-only order reduces: %w", err))
+		}
+		for _, position := range positions {
+			if position.Symbol == req.Symbol && position.Side == exitSide(req.Side) {
+				held = position.Size.Abs()
+			}
+		}
+	}
+
+	if !held.IsPositive() {
+		return decimal.Zero, ordererrors.New(ordererrors.OperationValidate, req.Symbol,
+			fmt.Errorf("%w: reduce-only order has no position to reduce", exchanges.ErrInvalidOrder))
+	}
+	return decimal.Min(req.Amount, held), nil
+}
+
+// opensShort reports whether req would open or add to a short position: a
+// sell that is not reduce-only and is larger than the long held on the symbol
+func (m *Manager) opensShort(req *OrderRequest) bool {
+	if req.Side != exchanges.OrderSideSell || req.ReduceOnly {
+		return false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.hedgeMode {
+		return true
+	}
+	position, exists := m.orderBook.Positions[req.Symbol]
+	if !exists || position.Side != PositionSideLong || position.Status != PositionStatusOpen {
+		return true
+	}
+	return req.Amount.GreaterThan(position.Amount)
+}
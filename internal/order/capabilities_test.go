@@ -0,0 +1,164 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_RejectsUnsupportedOrders(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.CapabilitiesValue = exchanges.Capabilities{}
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeStopLimit,
+		Price:  decimal.NewFromFloat(90),
+		Amount: decimal.NewFromFloat(1),
+	})
+	testutils.AssertTrue(t, errors.Is(err, exchanges.ErrNotSupported), "stop orders should be rejected without native stops")
+
+	_, err = manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideSell,
+		Type:   exchanges.OrderTypeMarket,
+		Amount: decimal.NewFromFloat(1),
+	})
+	testutils.AssertTrue(t, errors.Is(err, exchanges.ErrNotSupported), "opening a short should be rejected without shorts")
+	testutils.AssertEqual(t, 0, exchange.placeCalls, "rejected orders should not reach the exchange")
+
+	// Selling a long that is held is not a short
+	exchange.fillOnPlace = true
+	_, err = manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeMarket,
+		Amount: decimal.NewFromFloat(1),
+	})
+	testutils.AssertNoError(t, err, "entry should succeed")
+	_, err = manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideSell,
+		Type:   exchanges.OrderTypeMarket,
+		Amount: decimal.NewFromFloat(1),
+	})
+	testutils.AssertNoError(t, err, "selling the held long should succeed")
+}
+
+func TestManager_DropsUnsupportedPostOnly(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.CapabilitiesValue.PostOnly = false
+	manager := NewManager(exchange)
+
+	req := idempotencyRequest()
+	req.PostOnly = true
+	placed, err := manager.PlaceOrder(context.Background(), req)
+	testutils.AssertNoError(t, err, "post-only order should be placed without the flag")
+	testutils.AssertFalse(t, placed.PostOnly, "post-only should be dropped on venues without it")
+}
+
+func TestManager_ReduceOnlyWithoutNativeSupport(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.CapabilitiesValue.ReduceOnly = false
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	// The exchange holds a 0.5 BTC-USD long: a reduce-only buy would add to it
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideBuy,
+		Type:       exchanges.OrderTypeMarket,
+		Amount:     decimal.NewFromFloat(1),
+		ReduceOnly: true,
+	})
+	testutils.AssertTrue(t, errors.Is(err, exchanges.ErrInvalidOrder), "a reduce-only order without a position to reduce should be rejected")
+	testutils.AssertEqual(t, 0, exchange.placeCalls, "rejected orders should not reach the exchange")
+
+	placed, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideSell,
+		Type:       exchanges.OrderTypeMarket,
+		Amount:     decimal.NewFromFloat(2),
+		ReduceOnly: true,
+	})
+	testutils.AssertNoError(t, err, "selling the held long should succeed")
+	testutils.AssertTrue(t, placed.Amount.Equal(decimal.NewFromFloat(0.5)), "the exit should be cut down to the position")
+	testutils.AssertTrue(t, placed.ReduceOnly, "the exit should be sent reduce-only")
+}
+
+func TestManager_StopMarket(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	stop := &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideSell,
+		Type:       exchanges.OrderTypeStopMarket,
+		Price:      decimal.NewFromFloat(45000),
+		Amount:     decimal.NewFromFloat(0.5),
+		ReduceOnly: true,
+	}
+	_, err := manager.PlaceOrder(ctx, stop)
+	testutils.AssertTrue(t, errors.Is(err, exchanges.ErrNotSupported), "stop market orders should be rejected without native support")
+
+	exchange.CapabilitiesValue.StopMarket = true
+	manager = NewManager(exchange)
+	placed, err := manager.PlaceOrder(ctx, stop)
+	testutils.AssertNoError(t, err, "stop market order should be placed")
+	testutils.AssertTrue(t, placed.StopPrice.Equal(decimal.NewFromFloat(45000)), "the price should be the stop price")
+
+	// Stop losses exit at market once triggered
+	entry := &exchanges.Order{ID: "entry", ClientOrderID: "entry", Symbol: "BTC-USD", Side: exchanges.OrderSideBuy}
+	stopLoss, err := manager.placeStopLoss(ctx, entry, decimal.NewFromFloat(45000), decimal.NewFromFloat(0.5), "sl")
+	testutils.AssertNoError(t, err, "stop loss should be placed")
+	testutils.AssertEqual(t, exchanges.OrderTypeStopMarket, stopLoss.Type, "stop losses should be stop market orders")
+	testutils.AssertTrue(t, stopLoss.ReduceOnly, "stop losses should be reduce-only")
+}
+
+func TestManager_NativeTWAP(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	manager := NewManager(exchange)
+	ctx := context.Background()
+	twap := &exchanges.OrderOptions{TWAP: &exchanges.TWAPOptions{Duration: 10 * time.Minute}}
+
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:  "BTC-USD",
+		Side:    exchanges.OrderSideBuy,
+		Type:    exchanges.OrderTypeMarket,
+		Amount:  decimal.NewFromFloat(1),
+		Options: twap,
+	})
+	testutils.AssertTrue(t, errors.Is(err, exchanges.ErrNotSupported), "TWAP orders should be rejected without native TWAP")
+
+	exchange.CapabilitiesValue.NativeTWAP = true
+	manager = NewManager(exchange)
+	_, err = manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:  "BTC-USD",
+		Side:    exchanges.OrderSideBuy,
+		Type:    exchanges.OrderTypeLimit,
+		Price:   decimal.NewFromFloat(100),
+		Amount:  decimal.NewFromFloat(1),
+		Options: twap,
+	})
+	testutils.AssertTrue(t, errors.Is(err, exchanges.ErrInvalidOrder), "TWAP orders should be market orders")
+	testutils.AssertEqual(t, 0, exchange.placeCalls, "rejected orders should not reach the exchange")
+
+	placed, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:  "BTC-USD",
+		Side:    exchanges.OrderSideBuy,
+		Type:    exchanges.OrderTypeMarket,
+		Amount:  decimal.NewFromFloat(1),
+		Options: twap,
+	})
+	testutils.AssertNoError(t, err, "TWAP order should be placed")
+	testutils.AssertTrue(t, placed.Options == twap, "the options should reach the exchange")
+}
@@ -0,0 +1,199 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/exchanges/chaos"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+var stressChaos = chaos.Config{
+	TimeoutRate:     0.15,
+	ServerErrorRate: 0.1,
+	DuplicateRate:   0.1,
+	OutOfOrderRate:  0.2,
+	StalePriceRate:  0.2,
+}
+
+// TestManager_ChaosConsistency drives the manager with random orders, cancels
+// and price moves through an exchange injecting failures, resubmitting
+// failed requests under the same client order ID as callers do. Once the
+// faults stop, the manager must agree with the exchange on the position and
+// no request may have been placed twice.
+func TestManager_ChaosConsistency(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		t.Run(fmt.Sprintf("seed-%d", seed), func(t *testing.T) {
+			runChaosScenario(t, seed, 300)
+		})
+	}
+}
+
+func runChaosScenario(t *testing.T, seed int64, steps int) {
+	const symbol = "BTC-USD"
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(seed))
+
+	market := testutils.NewMatchingExchange("chaos", decimal.NewFromInt(100))
+	market.SetPartialFills(true)
+	config := stressChaos
+	config.Seed = seed
+	exchange := chaos.New(market, config)
+
+	manager := NewManager(exchange)
+	manager.SetSubmitRetryPolicy(2, 0)
+
+	var (
+		pending  []*OrderRequest // requests that failed, resubmitted as is
+		requests int
+	)
+	submit := func(req *OrderRequest) {
+		if _, err := manager.PlaceOrder(ctx, req); err != nil {
+			pending = append(pending, req)
+		}
+	}
+	// sellOutstanding reports whether a reducing order may still fill, so
+	// reducing orders never add up to more than the position
+	sellOutstanding := func() bool {
+		for _, req := range pending {
+			if req.ReduceOnly {
+				return true
+			}
+		}
+		for _, order := range manager.GetOpenOrders() {
+			if order.Side == exchanges.OrderSideSell {
+				return true
+			}
+		}
+		return false
+	}
+	newRequest := func(side exchanges.OrderSide, amount decimal.Decimal) *OrderRequest {
+		requests++
+		req := &OrderRequest{
+			ClientOrderID: fmt.Sprintf("chaos-%d-%d", seed, requests),
+			Symbol:        symbol,
+			Side:          side,
+			Type:          exchanges.OrderTypeMarket,
+			Amount:        amount,
+			ReduceOnly:    side == exchanges.OrderSideSell,
+		}
+		if rng.Intn(2) == 0 {
+			// Rest a limit within 1% of the market
+			offset := decimal.NewFromFloat(rng.Float64() / 100)
+			if side == exchanges.OrderSideBuy {
+				offset = offset.Neg()
+			}
+			req.Type = exchanges.OrderTypeLimit
+			req.Price = market.Price().Mul(decimal.NewFromInt(1).Add(offset)).Round(2)
+		}
+		return req
+	}
+
+	for step := 0; step < steps; step++ {
+		if len(pending) > 0 && rng.Intn(3) == 0 {
+			req := pending[0]
+			pending = pending[1:]
+			submit(req)
+		}
+
+		switch op := rng.Intn(10); {
+		case op < 4:
+			amount := decimal.NewFromInt(int64(1 + rng.Intn(10))).Div(decimal.NewFromInt(100))
+			submit(newRequest(exchanges.OrderSideBuy, amount))
+		case op < 6:
+			position := manager.GetPosition(symbol)
+			if position == nil || sellOutstanding() {
+				continue
+			}
+			amount := position.Amount.Mul(decimal.NewFromFloat(0.25 + rng.Float64()*0.75)).Round(4)
+			if amount.IsPositive() {
+				submit(newRequest(exchanges.OrderSideSell, amount))
+			}
+		case op < 7:
+			if orders := manager.GetOpenOrders(); len(orders) > 0 {
+				_ = manager.CancelOrder(ctx, orders[rng.Intn(len(orders))].ID)
+			}
+		default:
+			move := decimal.NewFromFloat((rng.Float64() - 0.5) / 50)
+			market.SetPrice(market.Price().Mul(decimal.NewFromInt(1).Add(move)).Round(2))
+		}
+		manager.updateOrders(ctx)
+	}
+
+	faults := exchange.Faults()
+	if faults[chaos.FaultTimeout] == 0 || faults[chaos.FaultOutOfOrder] == 0 {
+		t.Fatalf("expected timeouts and out of order updates to be injected, got %v", faults)
+	}
+
+	// Let every request settle without faults
+	exchange.SetConfig(chaos.Config{})
+	for _, req := range pending {
+		if _, err := manager.PlaceOrder(ctx, req); err != nil {
+			t.Fatalf("resubmitting %s without faults: %v", req.ClientOrderID, err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		manager.updateOrders(ctx)
+	}
+
+	clientIDs := make(map[string]string)
+	for _, order := range market.Orders() {
+		if first, exists := clientIDs[order.ClientOrderID]; exists {
+			t.Fatalf("client order ID %s placed twice, as %s and %s", order.ClientOrderID, first, order.ID)
+		}
+		clientIDs[order.ClientOrderID] = order.ID
+	}
+	if len(clientIDs) != requests {
+		t.Fatalf("expected %d orders on the exchange, got %d", requests, len(clientIDs))
+	}
+
+	managed := decimal.Zero
+	if position := manager.GetPosition(symbol); position != nil {
+		managed = position.Amount
+	}
+	if net := market.NetPosition(symbol); !managed.Equal(net) {
+		t.Fatalf("manager holds %s but the exchange filled %s (faults %v)", managed, net, faults)
+	}
+	for _, order := range manager.GetOpenOrders() {
+		actual, err := market.GetOrder(ctx, order.ID)
+		testutils.AssertNoError(t, err, "Open order should exist on the exchange")
+		testutils.AssertTrue(t, filledQuantity(order).Equal(filledQuantity(actual)), "Open order fills should match the exchange")
+	}
+}
+
+func TestManager_StaleSnapshotIgnored(t *testing.T) {
+	market := testutils.NewMatchingExchange("chaos", decimal.NewFromInt(100))
+	market.SetPartialFills(true)
+	exchange := chaos.New(market, chaos.Config{Seed: 1})
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeLimit,
+		Price:  decimal.NewFromInt(99),
+		Amount: decimal.NewFromInt(4),
+	})
+	testutils.AssertNoError(t, err, "PlaceOrder should not return error")
+
+	market.SetPrice(decimal.NewFromInt(98)) // fills 2
+	manager.updateOrders(ctx)
+	market.SetPrice(decimal.NewFromInt(97)) // fills 1 more
+	manager.updateOrders(ctx)
+
+	// Every query now answers with an earlier state
+	exchange.SetConfig(chaos.Config{OutOfOrderRate: 1})
+	manager.updateOrders(ctx)
+	exchange.SetConfig(chaos.Config{})
+	manager.updateOrders(ctx)
+
+	position := manager.GetPosition("BTC-USD")
+	testutils.AssertNotNil(t, position, "Position should exist")
+	testutils.AssertTrue(t, position.Amount.Equal(decimal.NewFromInt(3)), "Stale snapshot should not apply fills twice")
+	testutils.AssertTrue(t, filledQuantity(manager.GetOpenOrders()[0]).Equal(decimal.NewFromInt(3)), "Open order should keep its latest fills")
+}
@@ -0,0 +1,170 @@
+package order
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// fillsLookback is how far before the last poll fills are listed again, so
+// fills reported late or for orders not registered yet are not missed
+const fillsLookback = time.Minute
+
+// fillsDrivePositions reports whether positions follow the fills the
+// exchange reports, at their actual prices and fees, rather than the fill
+// progress of order snapshots
+func (m *Manager) fillsDrivePositions() bool {
+	return m.fillHistory != nil
+}
+
+// HandleFill applies a fill reported by the exchange to the position of its
+// order. Fills are deduplicated by ID, so the same fill may come from the
+// stream and from polling; fills of orders the manager did not place are
+// ignored, except those of the venue liquidating or auto-deleveraging a
+// position.
+func (m *Manager) HandleFill(ctx context.Context, fill *exchanges.Trade) {
+	if fill.Liquidation != "" && m.fillsDrivePositions() {
+		m.handleLiquidation(ctx, fill)
+		return
+	}
+	position, applied := m.applyExchangeFill(fill)
+	if !applied {
+		return
+	}
+	if position != nil {
+		m.emitPositionUpdate(position)
+	}
+	if err := m.syncSymbolProtection(ctx, fill.Symbol); err != nil {
+		m.emitError(err)
+	}
+}
+
+// applyExchangeFill applies fill to positions, charging its fee to their
+// realized PnL, and reports whether it was applied
+func (m *Manager) applyExchangeFill(fill *exchanges.Trade) (*ManagedPosition, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.fillsDrivePositions() || fill.ID == "" || !fill.Amount.IsPositive() {
+		return nil, false
+	}
+	if _, seen := m.seenFills[fill.ID]; seen {
+		return nil, false
+	}
+	order := m.orderBook.OpenOrders[fill.OrderID]
+	if order == nil {
+		order = m.awaitingFills[fill.OrderID]
+	}
+	if order == nil {
+		// Not placed by the manager, or not registered yet: left unseen so
+		// the next poll tries it again
+		return nil, false
+	}
+	m.seenFills[fill.ID] = fill.Timestamp
+
+	if tracked, ok := m.trackedOrders[order.ID]; ok {
+		tracked.fee = tracked.fee.Add(fill.Fee)
+		tracked.fills++
+		if !fill.Maker {
+			tracked.takerFills++
+		}
+	}
+
+	position := m.applyFill(order, fill.Amount, fill.Price)
+	if position != nil {
+		position.Fees = position.Fees.Add(fill.Fee)
+		position.RealizedPnL = position.RealizedPnL.Sub(fill.Fee)
+	}
+
+	applied := m.fillsApplied[order.ID].Add(fill.Amount)
+	m.fillsApplied[order.ID] = applied
+	if waiting, ok := m.awaitingFills[order.ID]; ok && !applied.LessThan(filledQuantity(waiting)) {
+		m.forgetOrder(order.ID)
+	}
+	return position, true
+}
+
+// awaitFills reports whether the finished order still has fills to apply,
+// keeping it until they are. Must be called with the lock held.
+func (m *Manager) awaitFills(order *exchanges.Order) bool {
+	if !m.fillsDrivePositions() || !m.fillsApplied[order.ID].LessThan(filledQuantity(order)) {
+		delete(m.fillsApplied, order.ID)
+		return false
+	}
+	m.awaitingFills[order.ID] = order
+	return true
+}
+
+// forgetOrder drops what is kept about a finished order to apply its fills.
+// Must be called with the lock held.
+func (m *Manager) forgetOrder(orderID string) {
+	delete(m.awaitingFills, orderID)
+	delete(m.fillsApplied, orderID)
+	delete(m.reducingOrders, orderID)
+	delete(m.orderStrategies, orderID)
+}
+
+// pollFills applies the fills listed by the exchange since the last poll
+func (m *Manager) pollFills(ctx context.Context) {
+	if !m.fillsDrivePositions() {
+		return
+	}
+
+	m.mu.RLock()
+	since := m.fillsPolled.Add(-fillsLookback)
+	m.mu.RUnlock()
+
+	polledAt := time.Now()
+	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
+	fills, err := m.fillHistory.GetFills(callCtx, "", since)
+	cancel()
+	if err != nil {
+		return
+	}
+
+	sort.SliceStable(fills, func(i, j int) bool {
+		return fills[i].Timestamp.Before(fills[j].Timestamp)
+	})
+	for i := range fills {
+		m.HandleFill(ctx, &fills[i])
+	}
+
+	m.mu.Lock()
+	m.fillsPolled = polledAt
+	for id, at := range m.seenFills {
+		if at.Before(since.Add(-fillsLookback)) {
+			delete(m.seenFills, id)
+		}
+	}
+	m.mu.Unlock()
+}
+
+// streamFills subscribes to the fills of exchanges that stream them
+func (m *Manager) streamFills(ctx context.Context) {
+	stream, ok := m.exchange.(exchanges.FillStream)
+	if !ok || !m.fillsDrivePositions() {
+		return
+	}
+	err := stream.SubscribeFills(ctx, func(fill *exchanges.Trade) {
+		m.HandleFill(ctx, fill)
+	})
+	if err != nil {
+		// Polling still picks the fills up
+		m.emitError(err)
+	}
+}
+
+// reportedLiquidity returns the fees of the fills of a tracked order and the
+// liquidity they reported, empty when no fill was reported
+func (t *trackedOrder) reportedLiquidity() (decimal.Decimal, Liquidity) {
+	if t.fills == 0 {
+		return t.fee, ""
+	}
+	if t.takerFills > 0 {
+		return t.fee, LiquidityTaker
+	}
+	return t.fee, LiquidityMaker
+}
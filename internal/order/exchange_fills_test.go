@@ -0,0 +1,172 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+// fillReportingExchange lists the fills set by the test
+type fillReportingExchange struct {
+	*flakyExchange
+	fills []exchanges.Trade
+}
+
+func (f *fillReportingExchange) GetFills(ctx context.Context, symbol string, since time.Time) ([]exchanges.Trade, error) {
+	return f.fills, nil
+}
+
+func newFillReportingManager() (*Manager, *fillReportingExchange) {
+	exchange := &fillReportingExchange{flakyExchange: newFlakyExchange(0, false)}
+	return NewManager(exchange), exchange
+}
+
+func testFill(id string, order *exchanges.Order, amount, price, fee float64) exchanges.Trade {
+	return exchanges.Trade{
+		ID:        id,
+		OrderID:   order.ID,
+		Symbol:    order.Symbol,
+		Side:      order.Side,
+		Price:     decimal.NewFromFloat(price),
+		Amount:    decimal.NewFromFloat(amount),
+		Fee:       decimal.NewFromFloat(fee),
+		Timestamp: time.Now(),
+		Maker:     true,
+	}
+}
+
+// withStatus returns the snapshot of order reporting amount filled
+func withStatus(order *exchanges.Order, status exchanges.OrderStatus, amount, price float64) exchanges.Order {
+	snapshot := *order
+	snapshot.Status = status
+	snapshot.FilledAmount = decimal.NewFromFloat(amount)
+	snapshot.AveragePrice = decimal.NewFromFloat(price)
+	return snapshot
+}
+
+func TestManager_FillsDrivePnLNetOfFees(t *testing.T) {
+	manager, exchange := newFillReportingManager()
+	ctx := context.Background()
+
+	var closed *ManagedPosition
+	manager.SetPositionUpdateCallback(func(position *ManagedPosition) {
+		if position.Status == PositionStatusClosed {
+			closed = position
+		}
+	})
+
+	entry, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeLimit,
+		Price:  decimal.NewFromFloat(101),
+		Amount: decimal.NewFromFloat(1),
+	})
+	testutils.AssertNoError(t, err, "entry should be placed")
+
+	// The snapshot reports a partial fill at its limit price, the fills the
+	// prices actually paid
+	exchange.OrdersValue = []exchanges.Order{withStatus(entry, exchanges.OrderStatusPartially, 0.4, 101)}
+	exchange.fills = []exchanges.Trade{testFill("f1", entry, 0.4, 100, 0.04)}
+	manager.pollFills(ctx)
+	manager.updateOrders(ctx)
+
+	position := manager.GetPosition("BTC-USD")
+	testutils.AssertNotNil(t, position, "the fill should open a position")
+	testutils.AssertTrue(t, position.Amount.Equal(decimal.NewFromFloat(0.4)), "snapshots should not apply fills again")
+	testutils.AssertTrue(t, position.EntryPrice.Equal(decimal.NewFromFloat(100)), "entry should be at the fill price")
+
+	// Fills listed again are not applied twice
+	exchange.fills = append(exchange.fills, testFill("f2", entry, 0.6, 101, 0.06))
+	exchange.OrdersValue = []exchanges.Order{withStatus(entry, exchanges.OrderStatusFilled, 1, 100.6)}
+	manager.pollFills(ctx)
+	manager.pollFills(ctx)
+	manager.updateOrders(ctx)
+
+	position = manager.GetPosition("BTC-USD")
+	testutils.AssertTrue(t, position.Amount.Equal(decimal.NewFromFloat(1)), "each fill should apply once")
+	testutils.AssertTrue(t, position.EntryPrice.Equal(decimal.NewFromFloat(100.6)), "entry should be volume weighted")
+
+	exit, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideSell,
+		Type:       exchanges.OrderTypeLimit,
+		Price:      decimal.NewFromFloat(110),
+		Amount:     decimal.NewFromFloat(1),
+		ReduceOnly: true,
+	})
+	testutils.AssertNoError(t, err, "exit should be placed")
+	exchange.fills = append(exchange.fills, testFill("f3", exit, 1, 110.5, 0.11))
+	manager.pollFills(ctx)
+
+	testutils.AssertNotNil(t, closed, "the exit fill should close the position")
+	testutils.AssertTrue(t, closed.Fees.Equal(decimal.NewFromFloat(0.21)), "fees of all fills should be charged")
+	testutils.AssertTrue(t, closed.RealizedPnL.Equal(decimal.NewFromFloat(9.69)), "PnL should be realized at fill prices net of fees")
+}
+
+func TestManager_LateFillOfFinishedExit(t *testing.T) {
+	manager, exchange := newFillReportingManager()
+	ctx := context.Background()
+
+	executions := make(map[string]Execution)
+	manager.SetExecutionCallback(func(execution Execution) {
+		executions[execution.OrderID] = execution
+	})
+
+	entry, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "ETH-USD",
+		Side:   exchanges.OrderSideSell,
+		Type:   exchanges.OrderTypeLimit,
+		Price:  decimal.NewFromFloat(2000),
+		Amount: decimal.NewFromFloat(2),
+	})
+	testutils.AssertNoError(t, err, "entry should be placed")
+	exchange.fills = []exchanges.Trade{testFill("e1", entry, 2, 2000, 0.4)}
+	manager.pollFills(ctx)
+
+	exit, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:     "ETH-USD",
+		Side:       exchanges.OrderSideBuy,
+		Type:       exchanges.OrderTypeMarket,
+		Amount:     decimal.NewFromFloat(2),
+		ReduceOnly: true,
+	})
+	testutils.AssertNoError(t, err, "exit should be placed")
+
+	// The exit is reported done before its fill
+	exchange.OrdersValue = []exchanges.Order{
+		withStatus(entry, exchanges.OrderStatusFilled, 2, 2000),
+		withStatus(exit, exchanges.OrderStatusFilled, 2, 1990),
+	}
+	manager.updateOrders(ctx)
+	testutils.AssertNotNil(t, manager.GetPosition("ETH-USD"), "the position should wait for the exit fill")
+	testutils.AssertEqual(t, 0, len(manager.GetOpenOrders()), "both orders should no longer be open")
+
+	exchange.fills = append(exchange.fills, testFill("x1", exit, 2, 1990, 0.6))
+	manager.pollFills(ctx)
+	testutils.AssertTrue(t, manager.GetPosition("ETH-USD") == nil, "the late fill should close the short")
+
+	manager.mu.RLock()
+	testutils.AssertEqual(t, 0, len(manager.awaitingFills), "the exit should be forgotten once applied")
+	testutils.AssertEqual(t, 0, len(manager.reducingOrders), "the exit should be forgotten once applied")
+	manager.mu.RUnlock()
+
+	testutils.AssertEqual(t, 2, len(executions), "both executions should be reported")
+	testutils.AssertEqual(t, LiquidityMaker, executions[entry.ID].Liquidity, "the entry should report the liquidity of its fills")
+	testutils.AssertTrue(t, executions[entry.ID].Fee.Equal(decimal.NewFromFloat(0.4)), "the entry should report the fee of its fills")
+	testutils.AssertEqual(t, LiquidityTaker, executions[exit.ID].Liquidity, "market exits without fills yet are estimated to take liquidity")
+}
+
+func TestManager_IgnoresFillsOfOtherOrders(t *testing.T) {
+	manager, exchange := newFillReportingManager()
+	ctx := context.Background()
+
+	exchange.fills = []exchanges.Trade{testFill("m1", &exchanges.Order{ID: "manual", Symbol: "BTC-USD", Side: exchanges.OrderSideBuy}, 1, 100, 0.1)}
+	manager.pollFills(ctx)
+
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD") == nil, "fills of orders placed elsewhere should be ignored")
+}
@@ -0,0 +1,58 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// ErrExitUnconfirmed is returned for exits whose trigger price is not
+// confirmed by the independent price source
+var ErrExitUnconfirmed = errors.New("exit price not confirmed")
+
+// PriceSource returns a price of a symbol independent of the manager's
+// exchange, such as the price quoted by other venues
+type PriceSource func(ctx context.Context, symbol string) (decimal.Decimal, error)
+
+// SetExitPriceCheck requires the price that triggers a synthetic stop or an
+// exit to be within tolerance percent of the price from source, so a wick or
+// bad print on a single venue does not close positions. A nil source
+// disables the check.
+func (m *Manager) SetExitPriceCheck(source PriceSource, tolerance decimal.Decimal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.exitPriceSource = source
+	m.exitPriceTolerance = tolerance
+}
+
+// ConfirmExitPrice checks price, which triggered an exit on symbol, against
+// the independent price source. It returns ErrExitUnconfirmed when they are
+// further apart than the tolerance. Exits are never held back when the
+// source has no price: failing to exit is worse than a false exit.
+func (m *Manager) ConfirmExitPrice(ctx context.Context, symbol string, price decimal.Decimal) error {
+	m.mu.RLock()
+	source := m.exitPriceSource
+	tolerance := m.exitPriceTolerance
+	m.mu.RUnlock()
+	if source == nil || !price.IsPositive() {
+		return nil
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
+	defer cancel()
+	reference, err := source(callCtx, symbol)
+	if err != nil || !reference.IsPositive() {
+		return nil
+	}
+
+	deviation := price.Sub(reference).Abs().Div(reference).Mul(decimal.NewFromInt(100))
+	if deviation.GreaterThan(tolerance) {
+		telemetry.RecordExitUnconfirmed(symbol)
+		return fmt.Errorf("%w: %s at %s is %s%% from %s on other venues",
+			ErrExitUnconfirmed, symbol, price, deviation.StringFixed(2), reference)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_SyntheticStopWaitsForConfirmation(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.fillOnPlace = true
+	exchange.CapabilitiesValue.StopOrders = false
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	reference := decimal.NewFromFloat(100)
+	var sourceErr error
+	manager.SetExitPriceCheck(func(ctx context.Context, symbol string) (decimal.Decimal, error) {
+		return reference, sourceErr
+	}, decimal.NewFromFloat(1))
+
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:   "BTC-USD",
+		Side:     exchanges.OrderSideBuy,
+		Type:     exchanges.OrderTypeMarket,
+		Amount:   decimal.NewFromFloat(1),
+		StopLoss: decimal.NewFromFloat(90),
+	})
+	testutils.AssertNoError(t, err, "entry should succeed")
+
+	// A wick to 89 on the venue while other venues quote 100
+	manager.CheckSyntheticStops(ctx, "BTC-USD", decimal.NewFromFloat(89))
+	testutils.AssertNotNil(t, manager.GetPosition("BTC-USD"), "unconfirmed stop should not close the position")
+	testutils.AssertEqual(t, 1, len(manager.SyntheticStops()), "unconfirmed stop should stay armed")
+
+	// Without a reference price the stop fires
+	sourceErr = errors.New("no other venue")
+	manager.CheckSyntheticStops(ctx, "BTC-USD", decimal.NewFromFloat(89))
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD") == nil, "stop should fire without a reference price")
+}
+
+func TestManager_ConfirmExitPrice(t *testing.T) {
+	manager := NewManager(newFlakyExchange(0, false))
+	ctx := context.Background()
+	testutils.AssertNoError(t, manager.ConfirmExitPrice(ctx, "BTC-USD", decimal.NewFromFloat(50)), "exits should pass without a price source")
+
+	manager.SetExitPriceCheck(func(ctx context.Context, symbol string) (decimal.Decimal, error) {
+		return decimal.NewFromFloat(100), nil
+	}, decimal.NewFromFloat(1))
+	testutils.AssertNoError(t, manager.ConfirmExitPrice(ctx, "BTC-USD", decimal.NewFromFloat(99.5)), "price within tolerance should be confirmed")
+	err := manager.ConfirmExitPrice(ctx, "BTC-USD", decimal.NewFromFloat(98))
+	testutils.AssertTrue(t, errors.Is(err, ErrExitUnconfirmed), "price beyond tolerance should not be confirmed")
+}
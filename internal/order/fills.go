@@ -0,0 +1,337 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	ordererrors "github.com/guyghost/constantine/internal/order/errors"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// protectionLevels are the stop loss and take profit requested by an entry
+type protectionLevels struct {
+	stopLoss      decimal.Decimal
+	takeProfit    decimal.Decimal
+	ladder        []TakeProfitLevel
+	trailingStop  decimal.Decimal
+	clientOrderID string
+}
+
+// takeProfitTarget is the price and quantity of a take profit order
+type takeProfitTarget struct {
+	price  decimal.Decimal
+	amount decimal.Decimal
+}
+
+// positionProtection tracks the protective orders resting for a position and
+// the quantity and levels they were placed for
+type positionProtection struct {
+	levels    protectionLevels
+	armed     decimal.Decimal // Quantity the stop loss was placed for
+	armedStop decimal.Decimal
+	seq       int
+	entry     *exchanges.Order
+	// Stop loss and take profit orders resting, one take profit per level
+	// of the ladder, and the targets the take profits were placed for
+	stopLossOrderID    string
+	takeProfitOrderIDs []string
+	armedTargets       []takeProfitTarget
+	ladder             ladderState
+}
+
+// filledQuantity returns the cumulative filled quantity reported for an order.
+// Exchange clients populate either Filled or FilledAmount; a filled order
+// without either is assumed to be filled in full.
+func filledQuantity(order *exchanges.Order) decimal.Decimal {
+	if order == nil {
+		return decimal.Zero
+	}
+	if order.Filled.IsPositive() {
+		return order.Filled
+	}
+	if order.FilledAmount.IsPositive() {
+		return order.FilledAmount
+	}
+	if order.Status == exchanges.OrderStatusFilled {
+		return order.Amount
+	}
+	return decimal.Zero
+}
+
+// fillDelta returns the quantity filled between two snapshots of the same order
+// and the average price of that increment
+func fillDelta(newOrder, oldOrder *exchanges.Order) (decimal.Decimal, decimal.Decimal) {
+	newFilled := filledQuantity(newOrder)
+	oldFilled := filledQuantity(oldOrder)
+	qty := newFilled.Sub(oldFilled)
+	if !qty.IsPositive() {
+		return decimal.Zero, decimal.Zero
+	}
+
+	price := newOrder.Price
+	if newOrder.AveragePrice.IsPositive() {
+		price = newOrder.AveragePrice
+		if oldFilled.IsPositive() && oldOrder.AveragePrice.IsPositive() {
+			// Back out the increment from the two cumulative averages
+			notional := newOrder.AveragePrice.Mul(newFilled).Sub(oldOrder.AveragePrice.Mul(oldFilled))
+			if incremental := notional.Div(qty); incremental.IsPositive() {
+				price = incremental
+			}
+		}
+	}
+	return qty, price
+}
+
+// isTerminalStatus reports whether an order can no longer be filled
+func isTerminalStatus(status exchanges.OrderStatus) bool {
+	switch status {
+	case exchanges.OrderStatusFilled, exchanges.OrderStatusCanceled, exchanges.OrderStatusRejected:
+		return true
+	}
+	return false
+}
+
+// adoptProtection makes the levels requested by entry the protection of its
+// position once the entry has filled on the position's side. Must be called
+// with the lock held.
+func (m *Manager) adoptProtection(entry *exchanges.Order) {
+	levels, pending := m.pendingProtection[entry.ID]
+	if !pending {
+		return
+	}
+	if isTerminalStatus(entry.Status) {
+		delete(m.pendingProtection, entry.ID)
+	}
+
+	key := m.positionKey(entry.Symbol, positionSideFor(entry.Side))
+	position, exists := m.orderBook.Positions[key]
+	if !exists || position.Side != positionSideFor(entry.Side) {
+		// The entry reduced an opposite position; its levels do not apply
+		return
+	}
+
+	protection, exists := m.protections[key]
+	if !exists {
+		protection = &positionProtection{}
+		m.protections[key] = protection
+	}
+	if protection.levels.clientOrderID == levels.clientOrderID {
+		// A stop moved since the entry was placed stays where it is
+		levels.stopLoss = protection.levels.stopLoss
+	} else {
+		protection.seq = 0
+		protection.ladder = ladderState{}
+	}
+	protection.levels = levels
+	protection.entry = entry
+}
+
+// syncSymbolProtection synchronizes the protection of every leg of symbol
+func (m *Manager) syncSymbolProtection(ctx context.Context, symbol string) error {
+	m.mu.RLock()
+	keys := m.legKeys(symbol)
+	m.mu.RUnlock()
+
+	var errs []error
+	for _, key := range keys {
+		if err := m.syncProtection(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MoveStopLoss moves the stop loss of the position on symbol and side to
+// price, cancelling and replacing its order, if that brings it closer to the
+// market. It reports whether the stop moved.
+func (m *Manager) MoveStopLoss(ctx context.Context, symbol string, side PositionSide, price decimal.Decimal) (bool, error) {
+	if err := m.checkObserveOnly(); err != nil {
+		return false, err
+	}
+	m.mu.Lock()
+	key := m.positionKey(symbol, side)
+	protection, exists := m.protections[key]
+	moved := exists && protection.tightenStop(price)
+	m.mu.Unlock()
+	if !moved {
+		return false, nil
+	}
+	if err := m.syncProtection(ctx, key); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// syncProtection keeps the stop loss and take profits of the position stored
+// under key sized to its current quantity. The stop loss is replaced when the
+// position is scaled in or out or its level moves, each take profit when its
+// price or quantity changes, and all are canceled once the position is
+// closed.
+func (m *Manager) syncProtection(ctx context.Context, key string) error {
+	m.mu.Lock()
+	protection, exists := m.protections[key]
+	if !exists {
+		m.mu.Unlock()
+		return nil
+	}
+
+	target := decimal.Zero
+	position, hasPosition := m.orderBook.Positions[key]
+	if hasPosition && position.Status == PositionStatusOpen {
+		target = position.Amount
+	}
+	targets := protection.takeProfitTargets(target)
+	stopStale := !target.Equal(protection.armed) || !protection.levels.stopLoss.Equal(protection.armedStop)
+	replace := make([]bool, len(targets))
+	targetsStale := len(targets) != len(protection.armedTargets)
+	for i, t := range targets {
+		replace[i] = i >= len(protection.armedTargets) || !t.equal(protection.armedTargets[i])
+		targetsStale = targetsStale || replace[i]
+	}
+	if !stopStale && !targetsStale {
+		m.mu.Unlock()
+		return nil
+	}
+
+	// Orders no longer open have already filled or been canceled
+	var stale []string
+	isOpen := func(orderID string) bool {
+		_, open := m.orderBook.OpenOrders[orderID]
+		return orderID != "" && open
+	}
+	if stopStale && isOpen(protection.stopLossOrderID) {
+		stale = append(stale, protection.stopLossOrderID)
+	}
+	for i, orderID := range protection.takeProfitOrderIDs {
+		if (i >= len(targets) || replace[i]) && isOpen(orderID) {
+			stale = append(stale, orderID)
+		}
+	}
+	levels := protection.levels
+	entry := protection.entry
+	symbol := entry.Symbol
+	m.mu.Unlock()
+
+	// Cancel before replacing so the old and new orders can never both
+	// execute against the same position
+	var cancelErrs []error
+	for _, orderID := range stale {
+		if err := m.CancelOrder(ctx, orderID); err != nil {
+			cancelErrs = append(cancelErrs, err)
+		}
+	}
+	if err := errors.Join(cancelErrs...); err != nil {
+		return ordererrors.New(ordererrors.OperationCancel, symbol, err)
+	}
+
+	m.mu.Lock()
+	if stopStale {
+		protection.armed = decimal.Zero
+		protection.armedStop = decimal.Zero
+		protection.stopLossOrderID = ""
+	}
+	// Take profits left in place keep their orders; those without quantity
+	// have nothing to place
+	orderIDs := make([]string, len(targets))
+	armedTargets := make([]takeProfitTarget, len(targets))
+	for i, t := range targets {
+		switch {
+		case !replace[i]:
+			orderIDs[i] = protection.takeProfitOrderIDs[i]
+			armedTargets[i] = protection.armedTargets[i]
+		case !t.amount.IsPositive():
+			armedTargets[i] = t
+		}
+	}
+	protection.takeProfitOrderIDs = orderIDs
+	protection.armedTargets = armedTargets
+	if !target.IsPositive() {
+		delete(m.protections, key)
+		m.mu.Unlock()
+		m.disarmSyntheticStop(key)
+		return nil
+	}
+	// Each replacement gets its own client order ID so it is not
+	// deduplicated against the order it replaces
+	protection.seq++
+	suffix := ""
+	if protection.seq > 1 {
+		suffix = fmt.Sprintf("-%d", protection.seq)
+	}
+	m.mu.Unlock()
+
+	if stopStale {
+		switch {
+		case levels.stopLoss.IsZero():
+			m.disarmSyntheticStop(key)
+		case !m.capabilities.StopOrders:
+			// The venue has no native stops: the manager fires the stop itself
+			m.armSyntheticStop(&SyntheticStop{
+				Key:           key,
+				Symbol:        symbol,
+				Side:          exitSide(entry.Side),
+				StopPrice:     levels.stopLoss,
+				Amount:        target,
+				ClientOrderID: childClientOrderID(entry, "sl"+suffix),
+				CreatedAt:     time.Now(),
+			})
+			telemetry.RecordStopLossPlaced(symbol)
+		default:
+			placed, err := m.placeStopLoss(ctx, entry, levels.stopLoss, target, "sl"+suffix)
+			if err != nil {
+				return ordererrors.New(ordererrors.OperationPlaceStopLoss, symbol, err)
+			}
+			m.mu.Lock()
+			protection.stopLossOrderID = placed.ID
+			m.mu.Unlock()
+		}
+		m.mu.Lock()
+		protection.armed = target
+		protection.armedStop = levels.stopLoss
+		if position, exists := m.orderBook.Positions[key]; exists {
+			position.StopLoss = levels.stopLoss
+		}
+		m.mu.Unlock()
+	}
+
+	for i, t := range targets {
+		if !replace[i] || !t.amount.IsPositive() {
+			continue
+		}
+		kind := "tp" + suffix
+		if len(levels.ladder) > 0 {
+			kind = fmt.Sprintf("tp%d%s", i+1, suffix)
+		}
+		placed, err := m.placeTakeProfit(ctx, entry, t.price, t.amount, kind)
+		if err != nil {
+			return ordererrors.New(ordererrors.OperationPlaceTakeProfit, symbol, err)
+		}
+		m.mu.Lock()
+		if i < len(protection.takeProfitOrderIDs) {
+			protection.takeProfitOrderIDs[i] = placed.ID
+			protection.armedTargets[i] = t
+		}
+		m.mu.Unlock()
+	}
+	return nil
+}
+
+// exitSide returns the side of the order that exits a position entered on side
+func exitSide(side exchanges.OrderSide) exchanges.OrderSide {
+	if side == exchanges.OrderSideBuy {
+		return exchanges.OrderSideSell
+	}
+	return exchanges.OrderSideBuy
+}
+
+// positionSideFor returns the position side opened by an order side
+func positionSideFor(side exchanges.OrderSide) PositionSide {
+	if side == exchanges.OrderSideBuy {
+		return PositionSideLong
+	}
+	return PositionSideShort
+}
@@ -0,0 +1,208 @@
+package order
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestFillDelta(t *testing.T) {
+	oldOrder := &exchanges.Order{
+		Price:        decimal.NewFromFloat(100),
+		FilledAmount: decimal.NewFromFloat(0.5),
+		AveragePrice: decimal.NewFromFloat(100),
+	}
+	newOrder := &exchanges.Order{
+		Price:        decimal.NewFromFloat(100),
+		FilledAmount: decimal.NewFromFloat(1),
+		AveragePrice: decimal.NewFromFloat(101),
+	}
+
+	qty, price := fillDelta(newOrder, oldOrder)
+	testutils.AssertTrue(t, qty.Equal(decimal.NewFromFloat(0.5)), "delta should be the newly filled quantity")
+	testutils.AssertTrue(t, price.Equal(decimal.NewFromFloat(102)), "delta price should be backed out of the averages")
+
+	qty, _ = fillDelta(newOrder, newOrder)
+	testutils.AssertTrue(t, qty.IsZero(), "unchanged order should have no delta")
+}
+
+func TestManager_PartialFillsAverageEntryAndArmProtection(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	req := &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideBuy,
+		Type:       exchanges.OrderTypeLimit,
+		Price:      decimal.NewFromFloat(100),
+		Amount:     decimal.NewFromFloat(1),
+		StopLoss:   decimal.NewFromFloat(90),
+		TakeProfit: decimal.NewFromFloat(120),
+	}
+	entry, err := manager.PlaceOrder(ctx, req)
+	testutils.AssertNoError(t, err, "PlaceOrder should not return error")
+	testutils.AssertEqual(t, 1, len(manager.GetOpenOrders()), "protection should wait for a fill")
+
+	// First partial fill
+	partial := *entry
+	partial.Status = exchanges.OrderStatusPartially
+	partial.FilledAmount = decimal.NewFromFloat(0.4)
+	partial.AveragePrice = decimal.NewFromFloat(100)
+	exchange.OrdersValue = []exchanges.Order{partial}
+	manager.updateOrders(ctx)
+
+	position := manager.GetPosition("BTC-USD")
+	testutils.AssertNotNil(t, position, "partial fill should open a position")
+	testutils.AssertTrue(t, position.Amount.Equal(decimal.NewFromFloat(0.4)), "position should hold the filled quantity")
+	testutils.AssertTrue(t, position.EntryPrice.Equal(decimal.NewFromFloat(100)), "entry price should match the fill")
+	assertProtectionAmount(t, manager, entry.ID, decimal.NewFromFloat(0.4))
+
+	// Remaining quantity fills at a higher price
+	filled := *entry
+	filled.Status = exchanges.OrderStatusFilled
+	filled.FilledAmount = decimal.NewFromFloat(1)
+	filled.AveragePrice = decimal.NewFromFloat(101)
+	exchange.OrdersValue = []exchanges.Order{filled}
+	manager.updateOrders(ctx)
+
+	position = manager.GetPosition("BTC-USD")
+	testutils.AssertTrue(t, position.Amount.Equal(decimal.NewFromFloat(1)), "position should hold the full quantity")
+	testutils.AssertTrue(t, position.EntryPrice.Round(8).Equal(decimal.NewFromFloat(101)), "entry price should be volume weighted")
+	assertProtectionAmount(t, manager, entry.ID, decimal.NewFromFloat(1))
+}
+
+// assertProtectionAmount checks that exactly one stop loss and one take profit
+// are resting, both sized to amount
+func assertProtectionAmount(t *testing.T, manager *Manager, entryID string, amount decimal.Decimal) {
+	t.Helper()
+	var stops, targets int
+	for _, order := range manager.GetOpenOrders() {
+		if order.ID == entryID {
+			continue
+		}
+		testutils.AssertTrue(t, order.Amount.Equal(amount), "protective order should cover "+amount.String())
+		if order.Type == exchanges.OrderTypeStopLimit {
+			stops++
+		} else {
+			targets++
+		}
+	}
+	testutils.AssertEqual(t, 1, stops, "should have one stop loss")
+	testutils.AssertEqual(t, 1, targets, "should have one take profit")
+}
+
+func TestManager_ApplyFillReducesPosition(t *testing.T) {
+	manager := NewManager(testutils.NewTestExchange("test-exchange"))
+
+	buy := &exchanges.Order{ID: "buy", Symbol: "BTC-USD", Side: exchanges.OrderSideBuy}
+	sell := &exchanges.Order{ID: "sell", Symbol: "BTC-USD", Side: exchanges.OrderSideSell}
+
+	manager.applyFill(buy, decimal.NewFromFloat(1), decimal.NewFromFloat(100))
+	position := manager.applyFill(sell, decimal.NewFromFloat(0.4), decimal.NewFromFloat(110))
+	testutils.AssertEqual(t, PositionStatusOpen, position.Status, "partial exit should keep the position open")
+	testutils.AssertTrue(t, position.Amount.Equal(decimal.NewFromFloat(0.6)), "position should be reduced")
+	testutils.AssertTrue(t, position.RealizedPnL.Equal(decimal.NewFromFloat(4)), "PnL should be realized on the reduced quantity")
+
+	position = manager.applyFill(sell, decimal.NewFromFloat(0.6), decimal.NewFromFloat(90))
+	testutils.AssertEqual(t, PositionStatusClosed, position.Status, "full exit should close the position")
+	testutils.AssertTrue(t, position.RealizedPnL.Equal(decimal.NewFromFloat(-2)), "PnL should accumulate across exits")
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD") == nil, "closed position should be removed")
+}
+
+func TestManager_ReducePositionResizesProtection(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.fillOnPlace = true
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideBuy,
+		Type:       exchanges.OrderTypeMarket,
+		Price:      decimal.NewFromFloat(100),
+		Amount:     decimal.NewFromFloat(1),
+		StopLoss:   decimal.NewFromFloat(90),
+		TakeProfit: decimal.NewFromFloat(120),
+	})
+	testutils.AssertNoError(t, err, "entry should succeed")
+	assertProtectionAmount(t, manager, "", decimal.NewFromFloat(1))
+
+	_, err = manager.ReducePosition(ctx, "BTC-USD", decimal.NewFromFloat(0.25))
+	testutils.AssertNoError(t, err, "reduce should succeed")
+
+	position := manager.GetPosition("BTC-USD")
+	testutils.AssertTrue(t, position.Amount.Equal(decimal.NewFromFloat(0.75)), "position should be reduced by the fraction")
+	assertProtectionAmount(t, manager, "", decimal.NewFromFloat(0.75))
+
+	_, err = manager.ReducePosition(ctx, "BTC-USD", decimal.NewFromFloat(1.5))
+	testutils.AssertError(t, err, "fraction above one should be rejected")
+}
+
+func TestManager_ScaleInResizesProtection(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.fillOnPlace = true
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	for _, price := range []float64{100, 110} {
+		_, err := manager.PlaceOrder(ctx, &OrderRequest{
+			Symbol:     "BTC-USD",
+			Side:       exchanges.OrderSideBuy,
+			Type:       exchanges.OrderTypeMarket,
+			Price:      decimal.NewFromFloat(price),
+			Amount:     decimal.NewFromFloat(1),
+			StopLoss:   decimal.NewFromFloat(price * 0.9),
+			TakeProfit: decimal.NewFromFloat(price * 1.2),
+		})
+		testutils.AssertNoError(t, err, "entry should succeed")
+	}
+
+	position := manager.GetPosition("BTC-USD")
+	testutils.AssertTrue(t, position.Amount.Equal(decimal.NewFromFloat(2)), "add-on should increase the position")
+	testutils.AssertTrue(t, position.EntryPrice.Equal(decimal.NewFromFloat(105)), "entry price should be averaged")
+	assertProtectionAmount(t, manager, "", decimal.NewFromFloat(2))
+}
+
+func TestManager_MoveStopLoss(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.fillOnPlace = true
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideBuy,
+		Type:       exchanges.OrderTypeMarket,
+		Price:      decimal.NewFromFloat(100),
+		Amount:     decimal.NewFromFloat(1),
+		StopLoss:   decimal.NewFromFloat(90),
+		TakeProfit: decimal.NewFromFloat(120),
+	})
+	testutils.AssertNoError(t, err, "entry should succeed")
+	exchange.fillOnPlace = false
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD").StopLoss.Equal(decimal.NewFromFloat(90)), "position should report its stop")
+
+	moved, err := manager.MoveStopLoss(ctx, "BTC-USD", PositionSideLong, decimal.NewFromFloat(100.1))
+	testutils.AssertNoError(t, err, "MoveStopLoss should not return error")
+	testutils.AssertTrue(t, moved, "stop should move closer to the market")
+	position := manager.GetPosition("BTC-USD")
+	testutils.AssertTrue(t, position.StopLoss.Equal(decimal.NewFromFloat(100.1)), "position should report the moved stop")
+	stops := 0
+	for _, order := range manager.GetOpenOrders() {
+		if order.Type.IsStop() {
+			stops++
+			testutils.AssertTrue(t, order.StopPrice.Equal(decimal.NewFromFloat(100.1)), "stop order should be replaced at the new level")
+			testutils.AssertEqual(t, position.StopLossOrderID, order.ID, "replacement should be linked to the position")
+		}
+	}
+	testutils.AssertEqual(t, 1, stops, "old stop should be canceled")
+	assertProtectionAmount(t, manager, "", decimal.NewFromFloat(1))
+
+	moved, err = manager.MoveStopLoss(ctx, "BTC-USD", PositionSideLong, decimal.NewFromFloat(95))
+	testutils.AssertNoError(t, err, "MoveStopLoss should not return error")
+	testutils.AssertFalse(t, moved, "stop should never move away from the market")
+}
@@ -0,0 +1,118 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	ordererrors "github.com/guyghost/constantine/internal/order/errors"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// ErrSanityCheck is returned for orders rejected by the sanity guard
+var ErrSanityCheck = errors.New("order failed sanity check")
+
+// SanityLimits are hard limits checked on every order right before it is
+// submitted, independently of the risk manager, to catch sizing bugs. A zero
+// limit is not checked.
+type SanityLimits struct {
+	MaxNotional       decimal.Decimal // Largest order value, in quote currency
+	MaxAmount         decimal.Decimal // Largest order size, in base units
+	MaxPriceDeviation decimal.Decimal // Furthest a limit price may be from the last ticker, in percent
+}
+
+// Enabled reports whether any limit is set
+func (l SanityLimits) Enabled() bool {
+	return l.MaxNotional.IsPositive() || l.MaxAmount.IsPositive() || l.MaxPriceDeviation.IsPositive()
+}
+
+// SetSanityLimits sets the limits checked before each order is submitted
+func (m *Manager) SetSanityLimits(limits SanityLimits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sanityLimits = limits
+}
+
+// SanityLimits returns the limits checked before each order is submitted
+func (m *Manager) SanityLimits() SanityLimits {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sanityLimits
+}
+
+// checkSanity rejects orders breaking the sanity limits. Reduce-only orders
+// are never rejected so the guard cannot keep the bot from getting out of a
+// position.
+func (m *Manager) checkSanity(ctx context.Context, req *OrderRequest) error {
+	limits := m.SanityLimits()
+	if req.ReduceOnly || !limits.Enabled() {
+		return nil
+	}
+
+	err := m.sanityError(ctx, req, limits)
+	if err != nil {
+		telemetry.RecordError("order_sanity_check")
+		return ordererrors.New(ordererrors.OperationValidate, req.Symbol, err)
+	}
+	return nil
+}
+
+func (m *Manager) sanityError(ctx context.Context, req *OrderRequest, limits SanityLimits) error {
+	if limits.MaxAmount.IsPositive() && req.Amount.GreaterThan(limits.MaxAmount) {
+		return fmt.Errorf("%w: amount %s exceeds limit %s", ErrSanityCheck, req.Amount, limits.MaxAmount)
+	}
+
+	// Market orders are valued at the last price, as are limit orders when
+	// their price is checked against it
+	hasPrice := req.Type != exchanges.OrderTypeMarket && req.Price.IsPositive()
+	var reference decimal.Decimal
+	if limits.MaxPriceDeviation.IsPositive() || (limits.MaxNotional.IsPositive() && !hasPrice) {
+		var err error
+		if reference, err = m.referencePrice(ctx, req.Symbol); err != nil {
+			return fmt.Errorf("%w: no reference price: %v", ErrSanityCheck, err)
+		}
+	}
+
+	if hasPrice && limits.MaxPriceDeviation.IsPositive() {
+		deviation := req.Price.Sub(reference).Abs().Div(reference).Mul(decimal.NewFromInt(100))
+		if deviation.GreaterThan(limits.MaxPriceDeviation) {
+			return fmt.Errorf("%w: price %s is %s%% from last price %s, limit %s%%",
+				ErrSanityCheck, req.Price, deviation.StringFixed(2), reference, limits.MaxPriceDeviation)
+		}
+	}
+
+	if limits.MaxNotional.IsPositive() {
+		price := reference
+		if hasPrice {
+			price = req.Price
+		}
+		if notional := req.Amount.Mul(price); notional.GreaterThan(limits.MaxNotional) {
+			return fmt.Errorf("%w: notional %s exceeds limit %s", ErrSanityCheck, notional.StringFixed(2), limits.MaxNotional)
+		}
+	}
+	return nil
+}
+
+// referencePrice returns the last traded price of symbol, or the mid when the
+// ticker has no last price
+func (m *Manager) referencePrice(ctx context.Context, symbol string) (decimal.Decimal, error) {
+	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
+	defer cancel()
+
+	ticker, err := m.exchange.GetTicker(callCtx, symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if ticker == nil {
+		return decimal.Zero, fmt.Errorf("no ticker for %s", symbol)
+	}
+	if ticker.Last.IsPositive() {
+		return ticker.Last, nil
+	}
+	if ticker.Bid.IsPositive() && ticker.Ask.IsPositive() {
+		return ticker.Bid.Add(ticker.Ask).Div(decimal.NewFromInt(2)), nil
+	}
+	return decimal.Zero, fmt.Errorf("ticker for %s has no price", symbol)
+}
@@ -0,0 +1,63 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_SanityGuard(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	manager := NewManager(exchange)
+	manager.SetSanityLimits(SanityLimits{
+		MaxNotional:       decimal.NewFromInt(10000),
+		MaxAmount:         decimal.NewFromInt(1),
+		MaxPriceDeviation: decimal.NewFromInt(5),
+	})
+	ctx := context.Background()
+
+	limit := func(price, amount float64) *OrderRequest {
+		return &OrderRequest{
+			Symbol: "BTC-USD",
+			Side:   exchanges.OrderSideBuy,
+			Type:   exchanges.OrderTypeLimit,
+			Price:  decimal.NewFromFloat(price),
+			Amount: decimal.NewFromFloat(amount),
+		}
+	}
+
+	// The test ticker last traded at 50000
+	_, err := manager.PlaceOrder(ctx, limit(50000, 2))
+	testutils.AssertTrue(t, errors.Is(err, ErrSanityCheck), "amount above the limit should be rejected")
+
+	_, err = manager.PlaceOrder(ctx, limit(40000, 0.1))
+	testutils.AssertTrue(t, errors.Is(err, ErrSanityCheck), "price 20% off the last price should be rejected")
+
+	_, err = manager.PlaceOrder(ctx, limit(50000, 0.5))
+	testutils.AssertTrue(t, errors.Is(err, ErrSanityCheck), "notional above the limit should be rejected")
+
+	market := limit(0, 0.5)
+	market.Type = exchanges.OrderTypeMarket
+	market.Price = decimal.Zero
+	_, err = manager.PlaceOrder(ctx, market)
+	testutils.AssertTrue(t, errors.Is(err, ErrSanityCheck), "market orders should be valued at the last price")
+	testutils.AssertEqual(t, 0, exchange.placeCalls, "rejected orders should not reach the exchange")
+
+	_, err = manager.PlaceOrder(ctx, limit(51000, 0.1))
+	testutils.AssertNoError(t, err, "order within the limits should be placed")
+
+	exit := limit(50000, 2)
+	exit.Side = exchanges.OrderSideSell
+	exit.ReduceOnly = true
+	_, err = manager.PlaceOrder(ctx, exit)
+	testutils.AssertNoError(t, err, "reduce-only orders should never be blocked")
+
+	// Without a reference price, orders that need one are refused
+	exchange.TickerValue = nil
+	_, err = manager.PlaceOrder(ctx, limit(50000, 0.1))
+	testutils.AssertTrue(t, errors.Is(err, ErrSanityCheck), "orders should be refused without a reference price")
+}
@@ -0,0 +1,39 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+
+	ordererrors "github.com/guyghost/constantine/internal/order/errors"
+)
+
+// ErrSymbolHalted is returned for orders opening or adding to a position on
+// a halted symbol
+var ErrSymbolHalted = errors.New("trading halted")
+
+// SetHaltCheck pauses the monitoring of the symbols check reports halted and
+// refuses new entries on them. Reduce-only orders are still sent.
+func (m *Manager) SetHaltCheck(check func(symbol string) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.haltCheck = check
+}
+
+// halted reports whether trading in symbol is halted. Must be called without
+// the lock held.
+func (m *Manager) halted(symbol string) bool {
+	m.mu.RLock()
+	check := m.haltCheck
+	m.mu.RUnlock()
+	return check != nil && check(symbol)
+}
+
+// checkHalt rejects requests opening or adding to a position on a halted
+// symbol
+func (m *Manager) checkHalt(req *OrderRequest) error {
+	if req.ReduceOnly || !m.halted(req.Symbol) {
+		return nil
+	}
+	return ordererrors.New(ordererrors.OperationValidate, req.Symbol,
+		fmt.Errorf("%w: %s", ErrSymbolHalted, req.Symbol))
+}
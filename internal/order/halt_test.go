@@ -0,0 +1,47 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_HaltedSymbol(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.fillOnPlace = true
+	exchange.CapabilitiesValue.StopOrders = false
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	halted := false
+	manager.SetHaltCheck(func(symbol string) bool { return halted && symbol == "BTC-USD" })
+
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:   "BTC-USD",
+		Side:     exchanges.OrderSideBuy,
+		Type:     exchanges.OrderTypeMarket,
+		Amount:   decimal.NewFromFloat(1),
+		StopLoss: decimal.NewFromFloat(90),
+	})
+	testutils.AssertNoError(t, err, "entry should succeed before the halt")
+
+	halted = true
+	_, err = manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeMarket,
+		Amount: decimal.NewFromFloat(1),
+	})
+	testutils.AssertTrue(t, errors.Is(err, ErrSymbolHalted), "entries on a halted symbol should be refused")
+
+	manager.CheckSyntheticStops(ctx, "BTC-USD", decimal.NewFromFloat(89))
+	testutils.AssertEqual(t, 1, len(manager.SyntheticStops()), "stops should wait for trading to resume")
+
+	halted = false
+	manager.CheckSyntheticStops(ctx, "BTC-USD", decimal.NewFromFloat(89))
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD") == nil, "stop should fire once trading resumes")
+}
@@ -0,0 +1,153 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// SetHedgeMode enables hedge mode, where a long and a short position on the
+// same symbol are tracked as independent legs. Enable it before any order is
+// placed. It is refused on exchanges without hedged positions, where an
+// order on the opposite side nets the position instead of opening a leg.
+//
+// In hedge mode an order opens or adds to the leg on its side unless it is
+// reduce-only, in which case it reduces the opposite leg.
+func (m *Manager) SetHedgeMode(enabled bool) error {
+	if enabled && !m.capabilities.Hedge {
+		return fmt.Errorf("%w: %s has no hedged positions", exchanges.ErrNotSupported, m.exchange.Name())
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hedgeMode = enabled
+	return nil
+}
+
+// HedgeMode reports whether hedge mode is enabled
+func (m *Manager) HedgeMode() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.hedgeMode
+}
+
+// GetPositionSide returns the position on one side of a symbol
+func (m *Manager) GetPositionSide(symbol string, side PositionSide) *ManagedPosition {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	position, exists := m.orderBook.Positions[m.positionKey(symbol, side)]
+	if !exists || position.Side != side {
+		return nil
+	}
+	return position
+}
+
+// ClosePositionSide closes the position on one side of a symbol
+func (m *Manager) ClosePositionSide(ctx context.Context, symbol string, side PositionSide) error {
+	m.mu.RLock()
+	key := m.positionKey(symbol, side)
+	position, exists := m.orderBook.Positions[key]
+	m.mu.RUnlock()
+
+	if !exists || position.Side != side {
+		return fmt.Errorf("%s position not found: %s", side, symbol)
+	}
+	return m.closePosition(ctx, key)
+}
+
+// ReducePositionSide closes fraction of the position on one side of a symbol
+func (m *Manager) ReducePositionSide(ctx context.Context, symbol string, side PositionSide, fraction decimal.Decimal) (*exchanges.Order, error) {
+	m.mu.RLock()
+	key := m.positionKey(symbol, side)
+	position, exists := m.orderBook.Positions[key]
+	m.mu.RUnlock()
+
+	if !exists || position.Side != side {
+		return nil, fmt.Errorf("%s position not found: %s", side, symbol)
+	}
+	return m.reducePosition(ctx, key, fraction)
+}
+
+// positionKey returns the order book key of a position. Must be called with
+// the lock held.
+func (m *Manager) positionKey(symbol string, side PositionSide) string {
+	if !m.hedgeMode {
+		return symbol
+	}
+	return symbol + "|" + string(side)
+}
+
+// positionKeys returns the keys of every position held on symbol. Must be
+// called with the lock held.
+func (m *Manager) positionKeys(symbol string) []string {
+	var keys []string
+	for _, key := range m.legKeys(symbol) {
+		if _, exists := m.orderBook.Positions[key]; exists {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// legKeys returns every key a position on symbol may be stored under. Must be
+// called with the lock held.
+func (m *Manager) legKeys(symbol string) []string {
+	if !m.hedgeMode {
+		return []string{symbol}
+	}
+	return []string{m.positionKey(symbol, PositionSideLong), m.positionKey(symbol, PositionSideShort)}
+}
+
+// fillTarget resolves the position an order fill applies to and whether the
+// fill reduces it. Must be called with the lock held.
+func (m *Manager) fillTarget(order *exchanges.Order) (string, PositionSide, bool) {
+	side := positionSideFor(order.Side)
+
+	if m.hedgeMode {
+		if m.reducingOrders[order.ID] {
+			side = oppositeSide(side)
+			return m.positionKey(order.Symbol, side), side, true
+		}
+		return m.positionKey(order.Symbol, side), side, false
+	}
+
+	position, exists := m.orderBook.Positions[order.Symbol]
+	if !exists {
+		// A reduce-only order never opens a position
+		return order.Symbol, side, m.reducingOrders[order.ID]
+	}
+	if position.Status == PositionStatusClosed && position.Side == side {
+		return order.Symbol, side, false
+	}
+	return order.Symbol, position.Side, position.Side != side
+}
+
+// closePositions closes every leg held on symbol
+func (m *Manager) closePositions(ctx context.Context, symbol string) error {
+	m.mu.RLock()
+	keys := m.positionKeys(symbol)
+	m.mu.RUnlock()
+
+	if len(keys) == 0 {
+		return fmt.Errorf("position not found: %s", symbol)
+	}
+
+	var errs []error
+	for _, key := range keys {
+		if err := m.closePosition(ctx, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// oppositeSide returns the other side of a position
+func oppositeSide(side PositionSide) PositionSide {
+	if side == PositionSideLong {
+		return PositionSideShort
+	}
+	return PositionSideLong
+}
@@ -0,0 +1,89 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func placeFilled(t *testing.T, manager *Manager, side exchanges.OrderSide, price, amount float64, reduceOnly bool) {
+	t.Helper()
+	_, err := manager.PlaceOrder(context.Background(), &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       side,
+		Type:       exchanges.OrderTypeMarket,
+		Price:      decimal.NewFromFloat(price),
+		Amount:     decimal.NewFromFloat(amount),
+		ReduceOnly: reduceOnly,
+	})
+	testutils.AssertNoError(t, err, "order should succeed")
+}
+
+func newHedgeTestManager(t *testing.T, hedge bool) *Manager {
+	t.Helper()
+	exchange := newFlakyExchange(0, false)
+	exchange.fillOnPlace = true
+	manager := NewManager(exchange)
+	testutils.AssertNoError(t, manager.SetHedgeMode(hedge), "test exchange should support hedge mode")
+	return manager
+}
+
+func TestManager_HedgeModeKeepsIndependentLegs(t *testing.T) {
+	manager := newHedgeTestManager(t, true)
+
+	placeFilled(t, manager, exchanges.OrderSideBuy, 100, 1, false)
+	placeFilled(t, manager, exchanges.OrderSideSell, 100, 0.5, false)
+
+	long := manager.GetPositionSide("BTC-USD", PositionSideLong)
+	short := manager.GetPositionSide("BTC-USD", PositionSideShort)
+	testutils.AssertNotNil(t, long, "long leg should exist")
+	testutils.AssertNotNil(t, short, "short leg should exist")
+	testutils.AssertTrue(t, long.Amount.Equal(decimal.NewFromFloat(1)), "long leg should be unchanged by the short entry")
+	testutils.AssertTrue(t, short.Amount.Equal(decimal.NewFromFloat(0.5)), "short leg should hold its own quantity")
+	testutils.AssertEqual(t, 2, len(manager.GetPositions()), "both legs should be reported")
+
+	// Reduce-only buy reduces the short leg only
+	placeFilled(t, manager, exchanges.OrderSideBuy, 90, 0.5, true)
+	testutils.AssertTrue(t, manager.GetPositionSide("BTC-USD", PositionSideShort) == nil, "short leg should be closed")
+	testutils.AssertTrue(t, short.RealizedPnL.Equal(decimal.NewFromFloat(5)), "short leg PnL should be tracked independently")
+	testutils.AssertTrue(t, long.Amount.Equal(decimal.NewFromFloat(1)), "long leg should be untouched")
+	testutils.AssertTrue(t, long.RealizedPnL.IsZero(), "long leg should have no realized PnL")
+}
+
+func TestManager_HedgeModeClosePositionClosesAllLegs(t *testing.T) {
+	manager := newHedgeTestManager(t, true)
+
+	placeFilled(t, manager, exchanges.OrderSideBuy, 100, 1, false)
+	placeFilled(t, manager, exchanges.OrderSideSell, 100, 1, false)
+
+	err := manager.ClosePosition(context.Background(), "BTC-USD")
+	testutils.AssertNoError(t, err, "closing all legs should succeed")
+	testutils.AssertEqual(t, 0, len(manager.GetPositions()), "both legs should be closed")
+}
+
+func TestManager_OneWayModeNetsOppositeFills(t *testing.T) {
+	manager := newHedgeTestManager(t, false)
+
+	placeFilled(t, manager, exchanges.OrderSideBuy, 100, 1, false)
+	placeFilled(t, manager, exchanges.OrderSideSell, 110, 0.5, false)
+
+	positions := manager.GetPositions()
+	testutils.AssertEqual(t, 1, len(positions), "opposite fill should net against the position")
+	testutils.AssertTrue(t, positions[0].Amount.Equal(decimal.NewFromFloat(0.5)), "position should be reduced")
+	testutils.AssertTrue(t, positions[0].RealizedPnL.Equal(decimal.NewFromFloat(5)), "reduction should realize PnL")
+}
+
+func TestManager_SetHedgeModeRequiresHedgedPositions(t *testing.T) {
+	exchange := testutils.NewTestExchange("one-way")
+	exchange.CapabilitiesValue.Hedge = false
+	manager := NewManager(exchange)
+
+	err := manager.SetHedgeMode(true)
+	testutils.AssertTrue(t, errors.Is(err, exchanges.ErrNotSupported), "hedge mode should be refused without hedged positions")
+	testutils.AssertFalse(t, manager.HedgeMode(), "hedge mode should stay disabled")
+	testutils.AssertNoError(t, manager.SetHedgeMode(false), "disabling hedge mode is always allowed")
+}
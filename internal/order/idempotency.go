@@ -0,0 +1,196 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/ringbuf"
+)
+
+const (
+	// maxClientOrderRegistrySize bounds the number of client order IDs remembered
+	maxClientOrderRegistrySize = 1000
+
+	defaultSubmitRetries      = 2
+	defaultSubmitRetryBackoff = 250 * time.Millisecond
+)
+
+// clientOrderRegistry remembers which client order IDs were accepted by the
+// exchange so the same logical order is never submitted twice
+type clientOrderRegistry struct {
+	mu            sync.Mutex
+	orders        map[string]*exchanges.Order
+	fifo          *ringbuf.Buffer[string]
+	attempted     map[string]bool
+	attemptedFIFO *ringbuf.Buffer[string]
+}
+
+func newClientOrderRegistry() *clientOrderRegistry {
+	return &clientOrderRegistry{
+		orders:        make(map[string]*exchanges.Order),
+		fifo:          ringbuf.New[string](maxClientOrderRegistrySize),
+		attempted:     make(map[string]bool),
+		attemptedFIFO: ringbuf.New[string](maxClientOrderRegistrySize),
+	}
+}
+
+// attempt records a submission of clientOrderID and reports whether it was
+// submitted before
+func (r *clientOrderRegistry) attempt(clientOrderID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.attempted[clientOrderID] {
+		return true
+	}
+	r.attempted[clientOrderID] = true
+	if oldest, evicted := r.attemptedFIFO.Push(clientOrderID); evicted {
+		delete(r.attempted, oldest)
+	}
+	return false
+}
+
+func (r *clientOrderRegistry) get(clientOrderID string) (*exchanges.Order, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.orders[clientOrderID]
+	return order, ok
+}
+
+func (r *clientOrderRegistry) store(clientOrderID string, order *exchanges.Order) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.orders[clientOrderID]; !exists {
+		if oldest, evicted := r.fifo.Push(clientOrderID); evicted {
+			delete(r.orders, oldest)
+		}
+	}
+	r.orders[clientOrderID] = order
+}
+
+// SetSubmitRetryPolicy configures how many times a failed submission is retried
+// and the base delay between attempts. Retries only happen when the exchange
+// can look orders up by client order ID, so a submission that reached the
+// exchange despite the error is never duplicated.
+func (m *Manager) SetSubmitRetryPolicy(maxRetries int, backoff time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	m.submitRetries = maxRetries
+	m.submitBackoff = backoff
+}
+
+// newClientOrderID returns a new client order ID, unique even for orders
+// created within the same clock tick
+func newClientOrderID() string {
+	return "order-" + uuid.NewString()
+}
+
+// childClientOrderID derives the client order ID of a protective order from
+// its parent so retries of the parent never duplicate its stop or target
+func childClientOrderID(parent *exchanges.Order, kind string) string {
+	if parent.ClientOrderID == "" {
+		return ""
+	}
+	return parent.ClientOrderID + "-" + kind
+}
+
+// submitOrder places order on the exchange, deduplicating on its client order
+// ID. The returned bool reports whether an earlier submission was reused.
+func (m *Manager) submitOrder(ctx context.Context, order *exchanges.Order) (*exchanges.Order, bool, error) {
+	if err := m.checkObserveOnly(); err != nil {
+		return nil, false, err
+	}
+	if order.ClientOrderID == "" {
+		order.ClientOrderID = newClientOrderID()
+	}
+	if existing, ok := m.clientOrders.get(order.ClientOrderID); ok {
+		return existing, true, nil
+	}
+
+	m.mu.RLock()
+	retries := m.submitRetries
+	backoff := m.submitBackoff
+	m.mu.RUnlock()
+
+	lookup, canLookup := m.exchange.(exchanges.ClientOrderLookup)
+	// A caller resubmitting after an error may have placed the order already
+	resubmitted := m.clientOrders.attempt(order.ClientOrderID)
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			if err := sleepWithContext(ctx, backoff*time.Duration(attempt)); err != nil {
+				return nil, false, lastErr
+			}
+		}
+		if attempt > 0 || (resubmitted && canLookup) {
+			// The previous attempt may have reached the exchange before failing
+			callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
+			found, err := lookup.GetOrderByClientID(callCtx, order.Symbol, order.ClientOrderID)
+			cancel()
+			if err == nil && found != nil {
+				m.clientOrders.store(order.ClientOrderID, found)
+				return found, false, nil
+			}
+			if err != nil && !errors.Is(err, exchanges.ErrOrderNotFound) {
+				// Without a definitive answer resubmitting could double the order
+				if lastErr == nil {
+					return nil, false, fmt.Errorf("previous submission status unknown: %w", err)
+				}
+				return nil, false, fmt.Errorf("%w (status unknown: %v)", lastErr, err)
+			}
+		}
+
+		attemptOrder := *order
+		callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
+		start := time.Now()
+		placed, err := m.exchange.PlaceOrder(callCtx, &attemptOrder)
+		m.recordLatency(start)
+		cancel()
+		if err == nil {
+			if placed.ClientOrderID == "" {
+				placed.ClientOrderID = order.ClientOrderID
+			}
+			m.clientOrders.store(order.ClientOrderID, placed)
+			return placed, false, nil
+		}
+
+		lastErr = err
+		if !canLookup || !isRetryableSubmitError(ctx, err) {
+			break
+		}
+	}
+
+	return nil, false, lastErr
+}
+
+// isRetryableSubmitError reports whether a failed submission may be retried.
+// Orders the exchange refused, for lack of funds or invalid parameters, fail
+// the same way when resubmitted.
+func isRetryableSubmitError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	return exchanges.CodeOf(err).Retryable() && !errors.Is(err, context.Canceled)
+}
+
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
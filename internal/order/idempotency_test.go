@@ -0,0 +1,173 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+// flakyExchange fails the first placements and optionally records the order
+// anyway, simulating a request that timed out after reaching the exchange
+type flakyExchange struct {
+	*testutils.TestExchange
+	failures     int
+	landOnFailed bool
+	placed       map[string]*exchanges.Order
+	placeCalls   int
+	lookupCalls  int
+	fillOnPlace  bool
+}
+
+func newFlakyExchange(failures int, landOnFailed bool) *flakyExchange {
+	return &flakyExchange{
+		TestExchange: testutils.NewTestExchange("flaky"),
+		failures:     failures,
+		landOnFailed: landOnFailed,
+		placed:       make(map[string]*exchanges.Order),
+	}
+}
+
+func (f *flakyExchange) PlaceOrder(ctx context.Context, order *exchanges.Order) (*exchanges.Order, error) {
+	f.placeCalls++
+	placed := *order
+	placed.ID = "id-" + order.ClientOrderID
+	placed.Status = exchanges.OrderStatusOpen
+	if f.fillOnPlace {
+		placed.Status = exchanges.OrderStatusFilled
+		placed.Filled = order.Amount
+	}
+	if f.placeCalls <= f.failures {
+		if f.landOnFailed {
+			f.placed[order.ClientOrderID] = &placed
+		}
+		return nil, errors.New("request timeout")
+	}
+	f.placed[order.ClientOrderID] = &placed
+	return &placed, nil
+}
+
+func (f *flakyExchange) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*exchanges.Order, error) {
+	f.lookupCalls++
+	if order, ok := f.placed[clientOrderID]; ok {
+		return order, nil
+	}
+	return nil, exchanges.ErrOrderNotFound
+}
+
+func idempotencyRequest() *OrderRequest {
+	return &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeLimit,
+		Price:  decimal.NewFromFloat(50000),
+		Amount: decimal.NewFromFloat(0.1),
+	}
+}
+
+func TestManager_PlaceOrderRetriesAfterTransientFailure(t *testing.T) {
+	exchange := newFlakyExchange(1, false)
+	manager := NewManager(exchange)
+	manager.SetSubmitRetryPolicy(2, time.Millisecond)
+
+	order, err := manager.PlaceOrder(context.Background(), idempotencyRequest())
+	testutils.AssertNoError(t, err, "order should succeed after retry")
+	testutils.AssertEqual(t, 2, exchange.placeCalls, "order should be submitted twice")
+	testutils.AssertEqual(t, 1, exchange.lookupCalls, "exchange should be queried before resubmitting")
+	testutils.AssertEqual(t, 1, len(exchange.placed), "only one order should exist")
+	testutils.AssertNotNil(t, order, "order should be returned")
+}
+
+func TestManager_PlaceOrderDoesNotDuplicateLandedOrder(t *testing.T) {
+	exchange := newFlakyExchange(1, true)
+	manager := NewManager(exchange)
+	manager.SetSubmitRetryPolicy(2, time.Millisecond)
+
+	order, err := manager.PlaceOrder(context.Background(), idempotencyRequest())
+	testutils.AssertNoError(t, err, "landed order should be recovered")
+	testutils.AssertEqual(t, 1, exchange.placeCalls, "order must not be resubmitted")
+	testutils.AssertEqual(t, "id-"+order.ClientOrderID, order.ID, "recovered order should be returned")
+}
+
+func TestManager_PlaceOrderGivesUpAfterRetries(t *testing.T) {
+	exchange := newFlakyExchange(10, false)
+	manager := NewManager(exchange)
+	manager.SetSubmitRetryPolicy(2, time.Millisecond)
+
+	_, err := manager.PlaceOrder(context.Background(), idempotencyRequest())
+	testutils.AssertError(t, err, "order should fail after exhausting retries")
+	testutils.AssertEqual(t, 3, exchange.placeCalls, "initial attempt plus two retries")
+}
+
+func TestManager_PlaceOrderDedupsClientOrderID(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	manager := NewManager(exchange)
+
+	req := idempotencyRequest()
+	req.ClientOrderID = "signal-42"
+
+	first, err := manager.PlaceOrder(context.Background(), req)
+	testutils.AssertNoError(t, err, "first submission should succeed")
+	second, err := manager.PlaceOrder(context.Background(), req)
+	testutils.AssertNoError(t, err, "duplicate submission should succeed")
+
+	testutils.AssertEqual(t, 1, exchange.placeCalls, "duplicate should not reach the exchange")
+	testutils.AssertEqual(t, first.ID, second.ID, "duplicate should return the original order")
+}
+
+func TestNewClientOrderID_UniqueWithinClockTick(t *testing.T) {
+	const goroutines, perGoroutine = 8, 500
+	ids := make(chan string, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perGoroutine {
+				ids <- newClientOrderID()
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[string]bool, goroutines*perGoroutine)
+	for id := range ids {
+		testutils.AssertFalse(t, seen[id], "client order IDs created concurrently should not collide")
+		seen[id] = true
+	}
+}
+
+func TestManager_PlaceOrderNoRetryWithoutLookup(t *testing.T) {
+	exchange := testutils.NewTestExchange("plain")
+	exchange.PlaceOrderError = errors.New("request timeout")
+	manager := NewManager(exchange)
+	manager.SetSubmitRetryPolicy(2, time.Millisecond)
+
+	_, err := manager.PlaceOrder(context.Background(), idempotencyRequest())
+	testutils.AssertError(t, err, "order should fail without retry")
+}
+
+func TestManager_ProtectiveOrdersUseDerivedClientIDs(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.fillOnPlace = true
+	manager := NewManager(exchange)
+
+	req := idempotencyRequest()
+	req.ClientOrderID = "entry-1"
+	req.StopLoss = decimal.NewFromFloat(49000)
+	req.TakeProfit = decimal.NewFromFloat(51000)
+
+	_, err := manager.PlaceOrder(context.Background(), req)
+	testutils.AssertNoError(t, err, "order with protection should succeed")
+
+	for _, id := range []string{"entry-1", "entry-1-sl", "entry-1-tp"} {
+		_, ok := exchange.placed[id]
+		testutils.AssertTrue(t, ok, "expected order "+id)
+	}
+}
@@ -0,0 +1,144 @@
+package order
+
+import (
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// trailingStopStep is the share of the trailing distance the best price must
+// move by before the stop follows, so stops are not replaced on every tick
+var trailingStopStep = decimal.NewFromFloat(0.25)
+
+// ladderState tracks the levels of a take profit ladder filled so far
+type ladderState struct {
+	base  decimal.Decimal   // Quantity the fractions apply to, fixed once a level fills
+	taken []decimal.Decimal // Quantity filled per level
+	best  decimal.Decimal   // Best price since the first fill, followed by the trailing stop
+}
+
+// hit reports whether a level of the ladder has filled
+func (l ladderState) hit() bool {
+	for _, taken := range l.taken {
+		if taken.IsPositive() {
+			return true
+		}
+	}
+	return false
+}
+
+func (t takeProfitTarget) equal(other takeProfitTarget) bool {
+	return t.price.Equal(other.price) && t.amount.Equal(other.amount)
+}
+
+// takeProfitTargets returns the take profits to rest for a position of
+// amount: a single one covering it, or one per level of the ladder for its
+// fraction of the position less what it already took, the rest riding as a
+// runner. Must be called with the lock held.
+func (p *positionProtection) takeProfitTargets(amount decimal.Decimal) []takeProfitTarget {
+	levels := p.levels.ladder
+	if len(levels) == 0 {
+		if p.levels.takeProfit.IsZero() {
+			return nil
+		}
+		return []takeProfitTarget{{price: p.levels.takeProfit, amount: amount}}
+	}
+
+	if len(p.ladder.taken) != len(levels) {
+		p.ladder.taken = make([]decimal.Decimal, len(levels))
+	}
+	if !p.ladder.hit() {
+		p.ladder.base = amount
+	}
+	targets := make([]takeProfitTarget, len(levels))
+	remaining := amount
+	for i, level := range levels {
+		size := p.ladder.base.Mul(level.Fraction).Truncate(8).Sub(p.ladder.taken[i])
+		size = decimal.Max(decimal.Zero, decimal.Min(size, remaining))
+		targets[i] = takeProfitTarget{price: level.Price, amount: size}
+		remaining = remaining.Sub(size)
+	}
+	return targets
+}
+
+// recordTakeProfitFill records qty filled by orderID if it is a level of a
+// take profit ladder. The first fill moves the stop loss to the entry price
+// of the position. Must be called with the lock held.
+func (m *Manager) recordTakeProfitFill(orderID string, qty decimal.Decimal) {
+	for key, protection := range m.protections {
+		if len(protection.levels.ladder) == 0 || len(protection.ladder.taken) != len(protection.levels.ladder) {
+			continue
+		}
+		for i, id := range protection.takeProfitOrderIDs {
+			if id != orderID || i >= len(protection.ladder.taken) {
+				continue
+			}
+			first := !protection.ladder.hit()
+			protection.ladder.taken[i] = protection.ladder.taken[i].Add(qty)
+			// What is left of the order is still resting as placed
+			protection.armedTargets[i].amount = protection.armedTargets[i].amount.Sub(qty)
+			if position, exists := m.orderBook.Positions[key]; exists && first {
+				protection.tightenStop(position.EntryPrice)
+				protection.ladder.best = position.EntryPrice
+			}
+			return
+		}
+	}
+}
+
+// tightenStop moves the stop loss to price if that is closer to the market,
+// and reports whether it moved
+func (p *positionProtection) tightenStop(price decimal.Decimal) bool {
+	if !price.IsPositive() || p.entry == nil {
+		return false
+	}
+	current := p.levels.stopLoss
+	long := positionSideFor(p.entry.Side) == PositionSideLong
+	if !current.IsZero() && (long && !price.GreaterThan(current) || !long && !price.LessThan(current)) {
+		return false
+	}
+	p.levels.stopLoss = price
+	return true
+}
+
+// trail follows price with the stop of the runner once the ladder has
+// started filling, and reports whether the stop moved
+func (p *positionProtection) trail(price decimal.Decimal) bool {
+	trailing := p.levels.trailingStop
+	if !trailing.IsPositive() || !p.ladder.hit() || !price.IsPositive() {
+		return false
+	}
+
+	long := positionSideFor(p.entry.Side) == PositionSideLong
+	if p.ladder.best.IsZero() || long && price.GreaterThan(p.ladder.best) || !long && price.LessThan(p.ladder.best) {
+		p.ladder.best = price
+	}
+	distance := p.ladder.best.Mul(trailing)
+	stop := p.ladder.best.Sub(distance)
+	if !long {
+		stop = p.ladder.best.Add(distance)
+	}
+	if !p.levels.stopLoss.IsZero() && stop.Sub(p.levels.stopLoss).Abs().LessThan(distance.Mul(trailingStopStep)) {
+		return false
+	}
+	return p.tightenStop(stop)
+}
+
+// trailStops moves the trailing stops of the runners on symbol after price,
+// replacing the stop orders moved
+func (m *Manager) trailStops(ctx context.Context, symbol string, price decimal.Decimal) {
+	m.mu.Lock()
+	moved := false
+	for _, protection := range m.protections {
+		if protection.entry != nil && protection.entry.Symbol == symbol && protection.trail(price) {
+			moved = true
+		}
+	}
+	m.mu.Unlock()
+
+	if moved {
+		if err := m.syncSymbolProtection(ctx, symbol); err != nil {
+			m.emitError(err)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package order
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+// restingExits returns the stop and the take profits resting, by price
+func restingExits(manager *Manager) (*exchanges.Order, map[string]*exchanges.Order) {
+	var stop *exchanges.Order
+	targets := make(map[string]*exchanges.Order)
+	for _, order := range manager.GetOpenOrders() {
+		if order.Type.IsStop() {
+			stop = order
+		} else {
+			targets[order.Price.String()] = order
+		}
+	}
+	return stop, targets
+}
+
+func TestManager_TakeProfitLadder(t *testing.T) {
+	exchange := testutils.NewMatchingExchange("test-exchange", decimal.NewFromInt(100))
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:   "BTC-USD",
+		Side:     exchanges.OrderSideBuy,
+		Type:     exchanges.OrderTypeMarket,
+		Price:    decimal.NewFromFloat(100),
+		Amount:   decimal.NewFromFloat(1),
+		StopLoss: decimal.NewFromFloat(90),
+		TakeProfitLadder: []TakeProfitLevel{
+			{Price: decimal.NewFromFloat(110), Fraction: decimal.NewFromFloat(0.5)},
+			{Price: decimal.NewFromFloat(120), Fraction: decimal.NewFromFloat(0.3)},
+		},
+		TrailingStop: decimal.NewFromFloat(0.05),
+	})
+	testutils.AssertNoError(t, err, "entry should succeed")
+
+	stop, targets := restingExits(manager)
+	testutils.AssertNotNil(t, stop, "stop should rest")
+	testutils.AssertTrue(t, stop.Amount.Equal(decimal.NewFromFloat(1)), "stop should cover the position")
+	testutils.AssertEqual(t, 2, len(targets), "one take profit should rest per level")
+	testutils.AssertTrue(t, targets["110"].Amount.Equal(decimal.NewFromFloat(0.5)), "first level should take half")
+	testutils.AssertTrue(t, targets["120"].Amount.Equal(decimal.NewFromFloat(0.3)), "second level should take 30%")
+	second := targets["120"].ID
+
+	// The first level fills: the stop moves to the entry price for the rest
+	exchange.SetPrice(decimal.NewFromInt(111))
+	manager.updateOrders(ctx)
+
+	position := manager.GetPosition("BTC-USD")
+	testutils.AssertTrue(t, position.Amount.Equal(decimal.NewFromFloat(0.5)), "first level should reduce the position")
+	stop, targets = restingExits(manager)
+	testutils.AssertTrue(t, stop.StopPrice.Equal(decimal.NewFromFloat(100)), "stop should move to break-even")
+	testutils.AssertTrue(t, stop.Amount.Equal(decimal.NewFromFloat(0.5)), "stop should cover the rest of the position")
+	testutils.AssertEqual(t, 1, len(targets), "filled level should not be replaced")
+	testutils.AssertEqual(t, second, targets["120"].ID, "second level should keep resting")
+
+	// The stop of the runner trails the best price
+	manager.HandleMarkPrice(ctx, &exchanges.MarkPrice{Symbol: "BTC-USD", Mark: decimal.NewFromFloat(130)})
+	stop, _ = restingExits(manager)
+	testutils.AssertTrue(t, stop.StopPrice.Equal(decimal.NewFromFloat(123.5)), "stop should trail the best price")
+	trailed := stop.ID
+
+	manager.HandleMarkPrice(ctx, &exchanges.MarkPrice{Symbol: "BTC-USD", Mark: decimal.NewFromFloat(131)})
+	manager.HandleMarkPrice(ctx, &exchanges.MarkPrice{Symbol: "BTC-USD", Mark: decimal.NewFromFloat(125)})
+	stop, _ = restingExits(manager)
+	testutils.AssertEqual(t, trailed, stop.ID, "small moves and pullbacks should leave the stop")
+}
+
+func TestValidateOrderRequest_TakeProfitLadder(t *testing.T) {
+	req := &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeMarket,
+		Amount: decimal.NewFromFloat(1),
+		TakeProfitLadder: []TakeProfitLevel{
+			{Price: decimal.NewFromFloat(110), Fraction: decimal.NewFromFloat(0.7)},
+			{Price: decimal.NewFromFloat(120), Fraction: decimal.NewFromFloat(0.7)},
+		},
+	}
+	testutils.AssertError(t, validateOrderRequest(req), "levels taking more than the position should be refused")
+
+	req.TakeProfitLadder[1].Fraction = decimal.NewFromFloat(0.3)
+	testutils.AssertNoError(t, validateOrderRequest(req), "levels taking the whole position should be accepted")
+
+	req.TrailingStop = decimal.NewFromFloat(-0.01)
+	testutils.AssertError(t, validateOrderRequest(req), "negative trailing stop should be refused")
+}
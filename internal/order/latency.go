@@ -0,0 +1,46 @@
+package order
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	ordererrors "github.com/guyghost/constantine/internal/order/errors"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// SetLatencyTracker records the round-trip latency of every order submitted
+// to the exchange in tracker, and refuses new entries while tracker finds the
+// exchange slow. Reduce-only orders are still sent.
+func (m *Manager) SetLatencyTracker(tracker *exchanges.LatencyTracker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.latency = tracker
+}
+
+// latencyTracker returns the tracker set with SetLatencyTracker, if any
+func (m *Manager) latencyTracker() *exchanges.LatencyTracker {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.latency
+}
+
+// recordLatency records the round trip of an order submitted at start
+func (m *Manager) recordLatency(start time.Time) {
+	if tracker := m.latencyTracker(); tracker != nil {
+		tracker.Record(m.exchange.Name(), time.Since(start))
+	}
+}
+
+// checkLatency rejects requests opening or adding to a position while the
+// exchange is slow
+func (m *Manager) checkLatency(req *OrderRequest) error {
+	tracker := m.latencyTracker()
+	if req.ReduceOnly || tracker == nil || !tracker.Slow(m.exchange.Name()) {
+		return nil
+	}
+	telemetry.RecordError("order_venue_latency")
+	p95, _ := tracker.P95(m.exchange.Name())
+	return ordererrors.New(ordererrors.OperationValidate, req.Symbol,
+		fmt.Errorf("%w: %s p95 %s exceeds %s", exchanges.ErrVenueTooSlow, m.exchange.Name(), p95, tracker.MaxP95()))
+}
@@ -0,0 +1,49 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_SlowVenue(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.fillOnPlace = true
+	manager := NewManager(exchange)
+	tracker := exchanges.NewLatencyTracker(time.Second, time.Minute)
+	manager.SetLatencyTracker(tracker)
+	ctx := context.Background()
+
+	entry := &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeMarket,
+		Amount: decimal.NewFromFloat(1),
+	}
+	for i := 0; i < 5; i++ {
+		_, err := manager.PlaceOrder(ctx, entry)
+		testutils.AssertNoError(t, err, "entries should succeed on a fast venue")
+	}
+	_, measured := tracker.P95(exchange.Name())
+	testutils.AssertTrue(t, measured, "the latency of submitted orders should be recorded")
+
+	for i := 0; i < 100; i++ {
+		tracker.Record(exchange.Name(), 2*time.Second)
+	}
+	_, err := manager.PlaceOrder(ctx, entry)
+	testutils.AssertTrue(t, errors.Is(err, exchanges.ErrVenueTooSlow), "entries should be refused on a slow venue")
+
+	_, err = manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideSell,
+		Type:       exchanges.OrderTypeMarket,
+		Amount:     decimal.NewFromFloat(1),
+		ReduceOnly: true,
+	})
+	testutils.AssertNoError(t, err, "exits should still be sent on a slow venue")
+}
@@ -0,0 +1,102 @@
+package order
+
+import (
+	"context"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// LiquidationEvent is a position of the account the venue liquidated or
+// auto-deleveraged, as reported by one of its fills
+type LiquidationEvent struct {
+	Kind      exchanges.LiquidationKind
+	Symbol    string
+	Side      PositionSide // Side of the position closed
+	Price     decimal.Decimal
+	Amount    decimal.Decimal
+	Fee       decimal.Decimal
+	Timestamp time.Time
+	// Position is the managed position after the fill, nil when the
+	// manager did not track it
+	Position *ManagedPosition
+}
+
+// SetLiquidationCallback sets the callback notified when the venue
+// liquidates or auto-deleverages a position of the account
+func (m *Manager) SetLiquidationCallback(callback func(*LiquidationEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onLiquidation = callback
+}
+
+// handleLiquidation reconciles the position closed by a liquidation or
+// auto-deleveraging fill: it is reduced by the fill, its stop loss and take
+// profit resized or canceled, and the liquidation callback notified
+func (m *Manager) handleLiquidation(ctx context.Context, fill *exchanges.Trade) {
+	event, applied := m.applyLiquidationFill(fill)
+	if !applied {
+		return
+	}
+	if event.Position != nil {
+		m.emitPositionUpdate(event.Position)
+		if err := m.syncSymbolProtection(ctx, fill.Symbol); err != nil {
+			m.emitError(err)
+		}
+	}
+
+	m.mu.RLock()
+	callback := m.onLiquidation
+	m.mu.RUnlock()
+	if callback != nil {
+		safeInvoke(func() { callback(event) })
+	}
+}
+
+// applyLiquidationFill applies fill to the position it closed, marking the
+// position with the kind of liquidation, and reports whether the fill was
+// new
+func (m *Manager) applyLiquidationFill(fill *exchanges.Trade) (*LiquidationEvent, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if fill.ID == "" || !fill.Amount.IsPositive() {
+		return nil, false
+	}
+	if _, seen := m.seenFills[fill.ID]; seen {
+		return nil, false
+	}
+	m.seenFills[fill.ID] = fill.Timestamp
+
+	side := oppositeSide(positionSideFor(fill.Side))
+	event := &LiquidationEvent{
+		Kind:      fill.Liquidation,
+		Symbol:    fill.Symbol,
+		Side:      side,
+		Price:     fill.Price,
+		Amount:    fill.Amount,
+		Fee:       fill.Fee,
+		Timestamp: fill.Timestamp,
+	}
+	key := m.positionKey(fill.Symbol, side)
+	if position, exists := m.orderBook.Positions[key]; !exists || position.Side != side || position.Status != PositionStatusOpen {
+		return event, true
+	}
+
+	// The venue closed the position with an order of its own
+	exit := &exchanges.Order{ID: fill.OrderID, Symbol: fill.Symbol, Side: fill.Side}
+	if exit.ID == "" {
+		exit.ID = "liquidation-" + fill.ID
+	}
+	m.reducingOrders[exit.ID] = true
+	position := m.applyFill(exit, fill.Amount, fill.Price)
+	delete(m.reducingOrders, exit.ID)
+	if position != nil {
+		position.Fees = position.Fees.Add(fill.Fee)
+		position.RealizedPnL = position.RealizedPnL.Sub(fill.Fee)
+		position.Liquidation = fill.Liquidation
+		event.Position = position
+	}
+	return event, true
+}
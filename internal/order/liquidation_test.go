@@ -0,0 +1,70 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_LiquidationReconcilesPosition(t *testing.T) {
+	manager, exchange := newFillReportingManager()
+	exchange.CapabilitiesValue.StopOrders = true
+	ctx := context.Background()
+
+	var events []*LiquidationEvent
+	manager.SetLiquidationCallback(func(event *LiquidationEvent) { events = append(events, event) })
+
+	entry, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:   "BTC-USD",
+		Side:     exchanges.OrderSideBuy,
+		Type:     exchanges.OrderTypeLimit,
+		Price:    decimal.NewFromFloat(100),
+		Amount:   decimal.NewFromFloat(1),
+		StopLoss: decimal.NewFromFloat(90),
+	})
+	testutils.AssertNoError(t, err, "entry should be placed")
+	exchange.OrdersValue = []exchanges.Order{withStatus(entry, exchanges.OrderStatusFilled, 1, 100)}
+	exchange.fills = []exchanges.Trade{testFill("f1", entry, 1, 100, 0)}
+	manager.pollFills(ctx)
+	manager.updateOrders(ctx)
+	testutils.AssertEqual(t, 1, len(manager.GetOpenOrders()), "stop should protect the position")
+
+	// The venue closes part, then the rest of the position with its own orders
+	liquidation := exchanges.Trade{
+		ID: "liq-1", OrderID: "venue-1", Symbol: "BTC-USD", Side: exchanges.OrderSideSell,
+		Price: decimal.NewFromFloat(85), Amount: decimal.NewFromFloat(0.4), Fee: decimal.NewFromFloat(0.5),
+		Timestamp: time.Now(), Liquidation: exchanges.LiquidationKindLiquidated,
+	}
+	rest := liquidation
+	rest.ID = "liq-2"
+	rest.Amount = decimal.NewFromFloat(0.6)
+	exchange.fills = append(exchange.fills, liquidation, rest)
+	manager.pollFills(ctx)
+	manager.pollFills(ctx)
+
+	testutils.AssertEqual(t, 2, len(events), "each liquidation fill should be notified once")
+	event := events[1]
+	testutils.AssertEqual(t, exchanges.LiquidationKindLiquidated, event.Kind, "kind should be reported")
+	testutils.AssertEqual(t, PositionSideLong, event.Side, "a sell closes the long")
+	testutils.AssertNotNil(t, event.Position, "tracked position should be reconciled")
+	testutils.AssertEqual(t, PositionStatusClosed, event.Position.Status, "position should be closed")
+	testutils.AssertEqual(t, exchanges.LiquidationKindLiquidated, event.Position.Liquidation, "position should be marked liquidated")
+	testutils.AssertTrue(t, event.Position.RealizedPnL.Equal(decimal.NewFromFloat(-16)), "loss should be realized at the liquidation price net of fees")
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD") == nil, "position should no longer be managed")
+	testutils.AssertEqual(t, 0, len(manager.GetOpenOrders()), "stop should be canceled")
+
+	// Positions the manager does not track are still notified
+	exchange.fills = append(exchange.fills, exchanges.Trade{
+		ID: "adl-1", OrderID: "venue-2", Symbol: "ETH-USD", Side: exchanges.OrderSideBuy,
+		Price: decimal.NewFromFloat(50), Amount: decimal.NewFromFloat(2),
+		Timestamp: time.Now(), Liquidation: exchanges.LiquidationKindDeleveraged,
+	})
+	manager.pollFills(ctx)
+	testutils.AssertEqual(t, 3, len(events), "untracked liquidation should be notified")
+	testutils.AssertEqual(t, PositionSideShort, events[2].Side, "a buy closes the short")
+	testutils.AssertTrue(t, events[2].Position == nil, "untracked position should not be reported")
+}
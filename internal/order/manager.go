@@ -22,27 +22,120 @@ const (
 
 // Manager manages orders and positions
 type Manager struct {
-	exchange  exchanges.Exchange
-	orderBook *OrderBook
-	mu        sync.RWMutex
+	exchange     exchanges.Exchange
+	capabilities exchanges.Capabilities
+	orderBook    *OrderBook
+	mu           sync.RWMutex
 
 	// Callbacks
 	onOrderUpdate    func(*OrderUpdate)
 	onPositionUpdate func(*ManagedPosition)
 	onError          func(error)
 
+	// Idempotent submission
+	clientOrders  *clientOrderRegistry
+	submitRetries int
+	submitBackoff time.Duration
+
+	// Protection requested by entries awaiting fills, keyed by entry order ID,
+	// and protection resting for positions, keyed by symbol
+	pendingProtection map[string]protectionLevels
+	protections       map[string]*positionProtection
+
+	// Stops fired by the manager on venues without native stops, keyed by
+	// position key, and where they are persisted
+	syntheticStops map[string]*SyntheticStop
+	stopStore      SyntheticStopStore
+	// Client order IDs of the exits of fired synthetic stops being placed
+	syntheticExits map[string]bool
+
+	// Hedge mode tracks long and short legs per symbol independently
+	hedgeMode      bool
+	reducingOrders map[string]bool // Reduce-only orders, by order ID
+
+	// Strategy that placed each open order, by order ID
+	orderStrategies map[string]string
+
+	// Hard limits checked right before submission
+	sanityLimits SanityLimits
+
+	// Independent price confirming the prices that trigger exits
+	exitPriceSource    PriceSource
+	exitPriceTolerance decimal.Decimal
+
+	// Reports the symbols whose trading is halted
+	haltCheck func(symbol string) bool
+
+	// Order round-trip latency of the exchange, refusing entries while slow
+	latency *exchanges.LatencyTracker
+
+	// Another instance trades the account: orders are neither placed nor
+	// canceled
+	observeOnly bool
+
+	// Orders whose execution quality is measured once done, by order ID
+	trackedOrders map[string]*trackedOrder
+	onExecution   func(Execution)
+
+	// Fills listed by exchanges that report them drive positions: fills
+	// applied by ID, quantity applied by order ID, and finished orders whose
+	// fills are not all applied yet
+	fillHistory   exchanges.FillHistory
+	seenFills     map[string]time.Time
+	fillsApplied  map[string]decimal.Decimal
+	awaitingFills map[string]*exchanges.Order
+	fillsPolled   time.Time
+	onLiquidation func(*LiquidationEvent)
+
+	// Mark prices streamed by exchanges that stream them, by symbol, and the
+	// symbols subscribed to
+	markStream     exchanges.MarkPriceStream
+	marks          map[string]exchanges.MarkPrice
+	markSubscribed map[string]bool
+
+	// Max age of resting entries, and how many were swept
+	orderAgeLimits OrderAgeLimits
+	staleCanceled  int
+	staleRepriced  int
+
 	// Control
 	running bool
 	done    chan struct{}
+	// lastLoop is when the monitor loop last completed, for liveness checks
+	lastLoop time.Time
 }
 
 // NewManager creates a new order manager
 func NewManager(exchange exchanges.Exchange) *Manager {
-	return &Manager{
-		exchange:  exchange,
-		orderBook: NewOrderBook(),
-		done:      make(chan struct{}),
+	m := &Manager{
+		exchange:          exchange,
+		capabilities:      exchange.Capabilities(),
+		orderBook:         NewOrderBook(),
+		clientOrders:      newClientOrderRegistry(),
+		submitRetries:     defaultSubmitRetries,
+		submitBackoff:     defaultSubmitRetryBackoff,
+		pendingProtection: make(map[string]protectionLevels),
+		protections:       make(map[string]*positionProtection),
+		syntheticStops:    make(map[string]*SyntheticStop),
+		syntheticExits:    make(map[string]bool),
+		reducingOrders:    make(map[string]bool),
+		orderStrategies:   make(map[string]string),
+		trackedOrders:     make(map[string]*trackedOrder),
+		done:              make(chan struct{}),
+	}
+	if history, ok := exchange.(exchanges.FillHistory); ok {
+		m.fillHistory = history
+		m.seenFills = make(map[string]time.Time)
+		m.fillsApplied = make(map[string]decimal.Decimal)
+		m.awaitingFills = make(map[string]*exchanges.Order)
+		m.fillsPolled = time.Now()
+	}
+	if stream, ok := exchange.(exchanges.MarkPriceStream); ok {
+		m.markStream = stream
+		m.marks = make(map[string]exchanges.MarkPrice)
+		m.markSubscribed = make(map[string]bool)
 	}
+	return m
 }
 
 // SetOrderUpdateCallback sets the callback for order updates
@@ -88,6 +181,7 @@ func (m *Manager) Start(ctx context.Context) error {
 
 	// Start monitoring loop
 	go m.monitor(ctx, doneCh)
+	m.streamFills(ctx)
 
 	return nil
 }
@@ -118,30 +212,62 @@ func (m *Manager) PlaceOrder(ctx context.Context, req *OrderRequest) (*exchanges
 	if err := validateOrderRequest(req); err != nil {
 		return nil, err
 	}
+	if err := m.checkCapabilities(req); err != nil {
+		return nil, err
+	}
+	amount, err := m.reduceOnlyAmount(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkHalt(req); err != nil {
+		return nil, err
+	}
+	if err := m.checkLatency(req); err != nil {
+		return nil, err
+	}
+	if err := m.checkSanity(ctx, req); err != nil {
+		return nil, err
+	}
 
-	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
-	defer cancel()
-
-	// Create order
+	// Create order; post-only is dropped on venues without it
 	order := &exchanges.Order{
-		ClientOrderID: fmt.Sprintf("order-%d", time.Now().UnixNano()),
+		ClientOrderID: req.ClientOrderID,
 		Symbol:        req.Symbol,
 		Side:          req.Side,
 		Type:          req.Type,
 		Price:         req.Price,
-		Amount:        req.Amount,
+		Amount:        amount,
+		TimeInForce:   req.TimeInForce,
+		PostOnly:      req.PostOnly && m.capabilities.PostOnly,
+		ExpiresAt:     req.ExpiresAt,
+		ReduceOnly:    req.ReduceOnly,
+		Options:       req.Options,
+	}
+	if req.Type.IsStop() {
+		order.StopPrice = req.Price
 	}
 
 	// Place order on exchange
-	placedOrder, err := m.exchange.PlaceOrder(callCtx, order)
+	placedOrder, reused, err := m.submitOrder(ctx, order)
 	if err != nil {
 		m.emitError(ordererrors.New(ordererrors.OperationPlace, order.Symbol, err))
 		return nil, err
 	}
+	if reused {
+		// Already submitted under this client order ID; nothing more to do
+		return placedOrder, nil
+	}
 
 	// Store order
 	m.mu.Lock()
 	m.orderBook.OpenOrders[placedOrder.ID] = placedOrder
+	if req.ReduceOnly {
+		m.reducingOrders[placedOrder.ID] = true
+	}
+	if req.Strategy != "" {
+		m.orderStrategies[placedOrder.ID] = req.Strategy
+	}
+	m.trackExecution(order, placedOrder, req.ReferencePrice, req.Strategy)
 	m.mu.Unlock()
 
 	// Emit order update
@@ -151,17 +277,29 @@ func (m *Manager) PlaceOrder(ctx context.Context, req *OrderRequest) (*exchanges
 		Timestamp: time.Now(),
 	})
 
-	// Place stop loss and take profit if specified
-	if !req.StopLoss.IsZero() {
-		if _, err := m.placeStopLoss(ctx, placedOrder, req.StopLoss); err != nil {
-			_ = m.CancelOrder(context.WithoutCancel(ctx), placedOrder.ID)
-			return nil, ordererrors.New(ordererrors.OperationPlaceStopLoss, placedOrder.Symbol, err)
+	// Stop loss and take profit are armed once the entry fills, sized to the
+	// filled quantity of the position
+	if !req.ReduceOnly && (!req.StopLoss.IsZero() || !req.TakeProfit.IsZero() || len(req.TakeProfitLadder) > 0) {
+		m.mu.Lock()
+		m.pendingProtection[placedOrder.ID] = protectionLevels{
+			stopLoss:      req.StopLoss,
+			takeProfit:    req.TakeProfit,
+			ladder:        req.TakeProfitLadder,
+			trailingStop:  req.TrailingStop,
+			clientOrderID: placedOrder.ClientOrderID,
 		}
+		m.mu.Unlock()
 	}
-	if !req.TakeProfit.IsZero() {
-		if _, err := m.placeTakeProfit(ctx, placedOrder, req.TakeProfit); err != nil {
-			_ = m.CancelOrder(context.WithoutCancel(ctx), placedOrder.ID)
-			return nil, ordererrors.New(ordererrors.OperationPlaceTakeProfit, placedOrder.Symbol, err)
+
+	// Market orders may come back already (partially) filled
+	if filledQuantity(placedOrder).IsPositive() {
+		m.handleOrderStatusChange(placedOrder, &exchanges.Order{ID: placedOrder.ID, Status: exchanges.OrderStatusOpen})
+		if err := m.syncSymbolProtection(ctx, placedOrder.Symbol); err != nil {
+			if !req.ReduceOnly {
+				_ = m.CancelOrder(context.WithoutCancel(ctx), placedOrder.ID)
+				return nil, err
+			}
+			m.emitError(err)
 		}
 	}
 
@@ -171,6 +309,10 @@ func (m *Manager) PlaceOrder(ctx context.Context, req *OrderRequest) (*exchanges
 
 // CancelOrder cancels an existing order
 func (m *Manager) CancelOrder(ctx context.Context, orderID string) error {
+	if err := m.checkObserveOnly(); err != nil {
+		m.emitError(ordererrors.New(ordererrors.OperationCancel, orderID, err))
+		return err
+	}
 	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
 	defer cancel()
 
@@ -178,15 +320,26 @@ func (m *Manager) CancelOrder(ctx context.Context, orderID string) error {
 		m.emitError(ordererrors.New(ordererrors.OperationCancel, orderID, err))
 		return err
 	}
+	if m.settleCanceledOrder(callCtx, orderID) {
+		return nil
+	}
 
 	// Update order book
 	m.mu.Lock()
-	if order, exists := m.orderBook.OpenOrders[orderID]; exists {
+	order, exists := m.orderBook.OpenOrders[orderID]
+	if exists {
 		order.Status = exchanges.OrderStatusCanceled
 		delete(m.orderBook.OpenOrders, orderID)
 		m.addFilledOrder(order)
 	}
+	delete(m.pendingProtection, orderID)
+	if !exists || !m.awaitFills(order) {
+		delete(m.reducingOrders, orderID)
+		delete(m.orderStrategies, orderID)
+	}
+	execution := m.completeExecution(&exchanges.Order{ID: orderID, Status: exchanges.OrderStatusCanceled})
 	m.mu.Unlock()
+	m.emitExecution(execution)
 
 	// Emit order update
 	m.emitOrderUpdate(&OrderUpdate{
@@ -201,6 +354,33 @@ func (m *Manager) CancelOrder(ctx context.Context, orderID string) error {
 	return nil
 }
 
+// settleCanceledOrder applies the fills a canceled order got since the last
+// poll. It reports whether the order was settled or left to the monitor loop
+// because the exchange did not give its final state; false means the order
+// can be dropped as is.
+func (m *Manager) settleCanceledOrder(ctx context.Context, orderID string) bool {
+	m.mu.RLock()
+	stored := m.orderBook.OpenOrders[orderID]
+	m.mu.RUnlock()
+	if stored == nil {
+		return false
+	}
+
+	final, err := m.exchange.GetOrder(ctx, orderID)
+	if errors.Is(err, exchanges.ErrOrderNotFound) {
+		return false
+	}
+	if err != nil || final == nil || !isTerminalStatus(final.Status) || filledQuantity(final).LessThan(filledQuantity(stored)) {
+		// No final state yet, the monitor loop picks the cancel up
+		return true
+	}
+	if !filledQuantity(final).GreaterThan(filledQuantity(stored)) {
+		return false
+	}
+	m.handleOrderStatusChange(final, stored)
+	return true
+}
+
 // GetOpenOrders returns all open orders
 func (m *Manager) GetOpenOrders() []*exchanges.Order {
 	m.mu.RLock()
@@ -213,6 +393,39 @@ func (m *Manager) GetOpenOrders() []*exchanges.Order {
 	return orders
 }
 
+// GetOrder returns the last known state of an order, open or among the
+// latest finished ones, or nil if the manager does not know it
+func (m *Manager) GetOrder(orderID string) *exchanges.Order {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if order, ok := m.orderBook.OpenOrders[orderID]; ok {
+		return order
+	}
+	var found *exchanges.Order
+	for order := range m.orderBook.FilledOrders.All() {
+		if order.ID == orderID {
+			found = order
+		}
+	}
+	return found
+}
+
+// GetOpenEntryOrders returns the open orders that may open or grow a
+// position, leaving out the reduce-only exits
+func (m *Manager) GetOpenEntryOrders() []*exchanges.Order {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	orders := make([]*exchanges.Order, 0, len(m.orderBook.OpenOrders))
+	for id, order := range m.orderBook.OpenOrders {
+		if !m.reducingOrders[id] {
+			orders = append(orders, order)
+		}
+	}
+	return orders
+}
+
 // GetPositions returns all open positions
 func (m *Manager) GetPositions() []*ManagedPosition {
 	m.mu.RLock()
@@ -225,23 +438,36 @@ func (m *Manager) GetPositions() []*ManagedPosition {
 	return positions
 }
 
-// GetPosition returns a specific position
+// GetPosition returns a specific position. In hedge mode it returns the long
+// leg if one is held, otherwise the short leg; use GetPositionSide to select.
 func (m *Manager) GetPosition(symbol string) *ManagedPosition {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return m.orderBook.Positions[symbol]
+	for _, key := range m.legKeys(symbol) {
+		if position, exists := m.orderBook.Positions[key]; exists {
+			return position
+		}
+	}
+	return nil
 }
 
-// ClosePosition closes a position
+// ClosePosition closes a position. In hedge mode every leg held on the
+// symbol is closed.
 func (m *Manager) ClosePosition(ctx context.Context, symbol string) error {
+	return m.closePositions(ctx, symbol)
+}
+
+// closePosition closes the position stored under key
+func (m *Manager) closePosition(ctx context.Context, key string) error {
 	m.mu.RLock()
-	position, exists := m.orderBook.Positions[symbol]
+	position, exists := m.orderBook.Positions[key]
 	m.mu.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("position not found: %s", symbol)
+		return fmt.Errorf("position not found: %s", key)
 	}
+	symbol := position.Symbol
 
 	// Determine order side (opposite of position side)
 	var orderSide exchanges.OrderSide
@@ -253,11 +479,12 @@ func (m *Manager) ClosePosition(ctx context.Context, symbol string) error {
 
 	// Place market order to close position
 	req := &OrderRequest{
-		Symbol:     symbol,
-		Side:       orderSide,
-		Type:       exchanges.OrderTypeMarket,
-		Amount:     position.Amount,
-		ReduceOnly: true,
+		Symbol:         symbol,
+		Side:           orderSide,
+		Type:           exchanges.OrderTypeMarket,
+		Amount:         position.Amount,
+		ReduceOnly:     true,
+		ReferencePrice: position.CurrentPrice,
 	}
 
 	order, err := m.PlaceOrder(ctx, req)
@@ -273,12 +500,93 @@ func (m *Manager) ClosePosition(ctx context.Context, symbol string) error {
 	position.ExitOrderID = order.ID
 	m.mu.Unlock()
 
+	// Cancel the protective orders of the closed position
+	if err := m.syncProtection(ctx, key); err != nil {
+		m.emitError(err)
+	}
+
 	// Emit position update
 	m.emitPositionUpdate(position)
 
 	return nil
 }
 
+// ReducePosition closes fraction (0 < fraction <= 1) of a position with a
+// reduce-only market order. Stop loss and take profit orders are resized to
+// the remaining quantity once the reduction fills. In hedge mode every leg is
+// reduced and the order of the last one is returned.
+func (m *Manager) ReducePosition(ctx context.Context, symbol string, fraction decimal.Decimal) (*exchanges.Order, error) {
+	m.mu.RLock()
+	keys := m.positionKeys(symbol)
+	m.mu.RUnlock()
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("position not found: %s", symbol)
+	}
+
+	var (
+		order *exchanges.Order
+		errs  []error
+	)
+	for _, key := range keys {
+		placed, err := m.reducePosition(ctx, key, fraction)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		order = placed
+	}
+	return order, errors.Join(errs...)
+}
+
+// reducePosition closes fraction of the position stored under key
+func (m *Manager) reducePosition(ctx context.Context, key string, fraction decimal.Decimal) (*exchanges.Order, error) {
+	if !fraction.IsPositive() || fraction.GreaterThan(decimal.NewFromInt(1)) {
+		return nil, fmt.Errorf("reduce fraction must be in (0, 1], got %s", fraction)
+	}
+
+	m.mu.RLock()
+	position, exists := m.orderBook.Positions[key]
+	var (
+		symbol string
+		side   PositionSide
+		amount decimal.Decimal
+		mark   decimal.Decimal
+	)
+	if exists {
+		exists = position.Status == PositionStatusOpen
+		symbol = position.Symbol
+		side = position.Side
+		amount = position.Amount
+		mark = position.CurrentPrice
+	}
+	m.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("position not found: %s", key)
+	}
+
+	orderSide := exchanges.OrderSideSell
+	if side == PositionSideShort {
+		orderSide = exchanges.OrderSideBuy
+	}
+
+	req := &OrderRequest{
+		Symbol:         symbol,
+		Side:           orderSide,
+		Type:           exchanges.OrderTypeMarket,
+		Amount:         amount.Mul(fraction),
+		ReduceOnly:     true,
+		ReferencePrice: mark,
+	}
+
+	order, err := m.PlaceOrder(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reduce position: %w", err)
+	}
+	return order, nil
+}
+
 // monitor monitors orders and positions
 func (m *Manager) monitor(ctx context.Context, done <-chan struct{}) {
 	ticker := time.NewTicker(1 * time.Second)
@@ -291,22 +599,41 @@ func (m *Manager) monitor(ctx context.Context, done <-chan struct{}) {
 		case <-done:
 			return
 		case <-ticker.C:
+			m.pollFills(ctx)
 			m.updateOrders(ctx)
+			m.sweepStaleOrders(ctx)
+			m.streamMarkPrices(ctx)
 			m.updatePositions(ctx)
+			m.checkSyntheticStops(ctx)
+
+			m.mu.Lock()
+			m.lastLoop = time.Now()
+			m.mu.Unlock()
 		}
 	}
 }
 
-// updateOrders updates the status of open orders
+// LastLoopAt returns when the monitor loop last completed, zero before the
+// first pass
+func (m *Manager) LastLoopAt() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastLoop
+}
+
+// updateOrders updates the status of open orders, except on halted symbols
 func (m *Manager) updateOrders(ctx context.Context) {
 	m.mu.RLock()
-	orderIDs := make([]string, 0, len(m.orderBook.OpenOrders))
-	for id := range m.orderBook.OpenOrders {
-		orderIDs = append(orderIDs, id)
+	orderSymbols := make(map[string]string, len(m.orderBook.OpenOrders))
+	for id, order := range m.orderBook.OpenOrders {
+		orderSymbols[id] = order.Symbol
 	}
 	m.mu.RUnlock()
 
-	for _, orderID := range orderIDs {
+	for orderID, symbol := range orderSymbols {
+		if m.halted(symbol) {
+			continue
+		}
 		callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
 		order, err := m.exchange.GetOrder(callCtx, orderID)
 		cancel()
@@ -318,9 +645,18 @@ func (m *Manager) updateOrders(ctx context.Context) {
 		oldOrder := m.orderBook.OpenOrders[orderID]
 		m.mu.Unlock()
 
-		// Check if status changed
-		if oldOrder != nil && order.Status != oldOrder.Status {
+		// A snapshot older than the one stored, e.g. served by a lagging
+		// replica, would make the next one apply its fills twice
+		if oldOrder != nil && filledQuantity(order).LessThan(filledQuantity(oldOrder)) {
+			continue
+		}
+
+		// Check if status or filled quantity changed
+		if oldOrder != nil && (order.Status != oldOrder.Status || !filledQuantity(order).Equal(filledQuantity(oldOrder))) {
 			m.handleOrderStatusChange(order, oldOrder)
+			if err := m.syncSymbolProtection(ctx, order.Symbol); err != nil {
+				m.emitError(err)
+			}
 		}
 	}
 }
@@ -335,18 +671,34 @@ func (m *Manager) handleOrderStatusChange(newOrder, oldOrder *exchanges.Order) {
 		shouldEmitPosition bool
 	)
 
+	// Apply only the quantity filled since the last snapshot so partial
+	// fills update the position incrementally, unless the fills the exchange
+	// reports do
+	if qty, price := fillDelta(newOrder, oldOrder); qty.IsPositive() && !m.fillsDrivePositions() {
+		if position := m.applyFill(newOrder, qty, price); position != nil {
+			positionToNotify = position
+			shouldEmitPosition = true
+		}
+	}
+	if _, pending := m.pendingProtection[newOrder.ID]; pending {
+		if filledQuantity(newOrder).IsPositive() {
+			m.adoptProtection(newOrder)
+		} else if isTerminalStatus(newOrder.Status) {
+			delete(m.pendingProtection, newOrder.ID)
+		}
+	}
+	execution := m.completeExecution(newOrder)
+	if isTerminalStatus(newOrder.Status) && !m.awaitFills(newOrder) {
+		delete(m.reducingOrders, newOrder.ID)
+		delete(m.orderStrategies, newOrder.ID)
+	}
+
 	switch newOrder.Status {
 	case exchanges.OrderStatusFilled:
 		event = OrderEventFilled
 		delete(m.orderBook.OpenOrders, newOrder.ID)
 		m.addFilledOrder(newOrder)
 
-		// Update or create position
-		if position := m.handleFilledOrder(newOrder); position != nil {
-			positionToNotify = position
-			shouldEmitPosition = true
-		}
-
 	case exchanges.OrderStatusPartially:
 		event = OrderEventPartiallyFilled
 		m.orderBook.OpenOrders[newOrder.ID] = newOrder
@@ -354,6 +706,11 @@ func (m *Manager) handleOrderStatusChange(newOrder, oldOrder *exchanges.Order) {
 	case exchanges.OrderStatusCanceled:
 		event = OrderEventCanceled
 		delete(m.orderBook.OpenOrders, newOrder.ID)
+
+	default:
+		// Fill progress without a status change
+		event = OrderEventPartiallyFilled
+		m.orderBook.OpenOrders[newOrder.ID] = newOrder
 	}
 
 	m.mu.Unlock()
@@ -368,60 +725,91 @@ func (m *Manager) handleOrderStatusChange(newOrder, oldOrder *exchanges.Order) {
 		Event:     event,
 		Timestamp: time.Now(),
 	})
+	m.emitExecution(execution)
 }
 
-// handleFilledOrder handles a filled order and updates positions
+// handleFilledOrder applies the full filled quantity of an order to positions
 func (m *Manager) handleFilledOrder(order *exchanges.Order) *ManagedPosition {
-	position, exists := m.orderBook.Positions[order.Symbol]
+	price := order.Price
+	if order.AveragePrice.IsPositive() {
+		price = order.AveragePrice
+	}
+	return m.applyFill(order, filledQuantity(order), price)
+}
 
+// applyFill updates positions with qty filled at price. Fills on the side of
+// the position increase it at a volume-weighted average entry price; opposite
+// fills reduce it and realize PnL on the reduced quantity.
+func (m *Manager) applyFill(order *exchanges.Order, qty, price decimal.Decimal) *ManagedPosition {
+	if !qty.IsPositive() {
+		return nil
+	}
+	m.recordTakeProfitFill(order.ID, qty)
+
+	key, side, reduce := m.fillTarget(order)
+	position, exists := m.orderBook.Positions[key]
+	if exists && position.Status == PositionStatusClosed && !reduce {
+		// A closed position awaiting its exit fill does not absorb new entries
+		exists = false
+	}
 	if !exists {
-		// Create new position
-		var side PositionSide
-		if order.Side == exchanges.OrderSideBuy {
-			side = PositionSideLong
-		} else {
-			side = PositionSideShort
+		if reduce {
+			// Nothing left to reduce
+			return nil
 		}
-
 		position = &ManagedPosition{
 			ID:            fmt.Sprintf("pos-%d", time.Now().UnixNano()),
 			Symbol:        order.Symbol,
 			Side:          side,
-			EntryPrice:    order.Price,
-			CurrentPrice:  order.Price,
-			Amount:        order.Filled,
+			EntryPrice:    price,
+			CurrentPrice:  price,
+			Amount:        qty,
 			Leverage:      decimal.NewFromInt(1),
 			UnrealizedPnL: decimal.Zero,
 			RealizedPnL:   decimal.Zero,
 			EntryTime:     time.Now(),
 			Status:        PositionStatusOpen,
 			EntryOrderID:  order.ID,
+			Strategy:      m.orderStrategies[order.ID],
 		}
 
-		m.orderBook.Positions[order.Symbol] = position
+		m.orderBook.Positions[key] = position
 		return position
-	} else {
-		// Update existing position or close it
-		if (position.Side == PositionSideLong && order.Side == exchanges.OrderSideSell) ||
-			(position.Side == PositionSideShort && order.Side == exchanges.OrderSideBuy) {
-			// Closing position
-			pnl := m.calculatePnL(position, order.Price)
-			position.RealizedPnL = position.RealizedPnL.Add(pnl)
-			position.Status = PositionStatusClosed
-			exitTime := time.Now()
-			position.ExitTime = &exitTime
-			position.ExitOrderID = order.ID
-
-			delete(m.orderBook.Positions, order.Symbol)
-			return position
-		}
 	}
 
-	return nil
+	if !reduce {
+		// Increasing position
+		total := position.Amount.Add(qty)
+		position.EntryPrice = position.EntryPrice.Mul(position.Amount).Add(price.Mul(qty)).Div(total)
+		position.Amount = total
+		return position
+	}
+
+	// Reducing or closing position; any excess over the position is ignored
+	closed := decimal.Min(qty, position.Amount)
+	position.RealizedPnL = position.RealizedPnL.Add(m.calculateFillPnL(position, price, closed))
+	position.Amount = position.Amount.Sub(closed)
+	if position.Amount.IsPositive() {
+		return position
+	}
+
+	position.Status = PositionStatusClosed
+	exitTime := time.Now()
+	position.ExitTime = &exitTime
+	position.ExitOrderID = order.ID
+	position.StopOut = order.ID == position.StopLossOrderID || m.syntheticExits[order.ClientOrderID]
+
+	delete(m.orderBook.Positions, key)
+	return position
 }
 
 // calculatePnL calculates profit/loss for a position
 func (m *Manager) calculatePnL(position *ManagedPosition, exitPrice decimal.Decimal) decimal.Decimal {
+	return m.calculateFillPnL(position, exitPrice, position.Amount)
+}
+
+// calculateFillPnL calculates profit/loss for closing amount of a position
+func (m *Manager) calculateFillPnL(position *ManagedPosition, exitPrice, amount decimal.Decimal) decimal.Decimal {
 	priceDiff := exitPrice.Sub(position.EntryPrice)
 	if position.Side == PositionSideShort {
 		priceDiff = priceDiff.Neg()
@@ -430,10 +818,10 @@ func (m *Manager) calculatePnL(position *ManagedPosition, exitPrice decimal.Deci
 	if leverage.IsZero() {
 		leverage = decimal.NewFromInt(1)
 	}
-	return priceDiff.Mul(position.Amount).Mul(leverage)
+	return priceDiff.Mul(amount).Mul(leverage)
 }
 
-// updatePositions updates position information
+// updatePositions updates position information, except on halted symbols
 func (m *Manager) updatePositions(ctx context.Context) {
 	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
 	defer cancel()
@@ -444,18 +832,32 @@ func (m *Manager) updatePositions(ctx context.Context) {
 	}
 
 	for _, exchangePos := range positions {
+		if m.halted(exchangePos.Symbol) {
+			continue
+		}
 		m.mu.Lock()
-		managedPos, exists := m.orderBook.Positions[exchangePos.Symbol]
+		managedPos, exists := m.orderBook.Positions[m.positionKey(exchangePos.Symbol, positionSideFor(exchangePos.Side))]
+		price := decimal.Zero
 		if exists {
 			managedPos.CurrentPrice = exchangePos.MarkPrice
+			if mark, streamed := m.marks[exchangePos.Symbol]; streamed && !exchangePos.MarkPrice.IsPositive() {
+				// The exchange reports positions without their mark
+				managedPos.CurrentPrice = mark.Mark
+			}
 			managedPos.UnrealizedPnL = exchangePos.UnrealizedPnL
+			managedPos.LiquidationPrice = exchangePos.LiquidationPrice
+			price = managedPos.CurrentPrice
 		}
 		m.mu.Unlock()
+
+		if price.IsPositive() {
+			m.trailStops(ctx, exchangePos.Symbol, price)
+		}
 	}
 }
 
-// placeStopLoss places a stop loss order
-func (m *Manager) placeStopLoss(ctx context.Context, order *exchanges.Order, stopLoss decimal.Decimal) (*exchanges.Order, error) {
+// placeStopLoss places a stop loss order for amount of the entry order
+func (m *Manager) placeStopLoss(ctx context.Context, order *exchanges.Order, stopLoss, amount decimal.Decimal, kind string) (*exchanges.Order, error) {
 	if stopLoss.IsZero() {
 		return nil, nil
 	}
@@ -464,30 +866,34 @@ func (m *Manager) placeStopLoss(ctx context.Context, order *exchanges.Order, sto
 		return nil, errors.New("stop loss price must be positive")
 	}
 
-	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
-	defer cancel()
-
 	// Determine stop loss side (opposite of entry order)
 	stopSide := exchanges.OrderSideSell
 	if order.Side == exchanges.OrderSideSell {
 		stopSide = exchanges.OrderSideBuy
 	}
 
-	// Create stop loss order
+	// Create stop loss order, exiting at market once triggered where the
+	// venue allows it
+	stopType := exchanges.OrderTypeStopLimit
+	if m.capabilities.StopMarket {
+		stopType = exchanges.OrderTypeStopMarket
+	}
 	stopOrder := &exchanges.Order{
-		Symbol:    order.Symbol,
-		Side:      stopSide,
-		Type:      exchanges.OrderTypeStopLimit,
-		Amount:    order.Amount,
-		Price:     stopLoss,
-		StopPrice: stopLoss,
-		Status:    exchanges.OrderStatusOpen,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ClientOrderID: childClientOrderID(order, kind),
+		Symbol:        order.Symbol,
+		Side:          stopSide,
+		Type:          stopType,
+		Amount:        amount,
+		Price:         stopLoss,
+		StopPrice:     stopLoss,
+		Status:        exchanges.OrderStatusOpen,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+		ReduceOnly:    true,
 	}
 
 	// Place the stop loss order
-	placedOrder, err := m.exchange.PlaceOrder(callCtx, stopOrder)
+	placedOrder, _, err := m.submitOrder(ctx, stopOrder)
 	if err != nil {
 		m.emitError(ordererrors.New(ordererrors.OperationPlaceStopLoss, order.Symbol, err))
 		return nil, err
@@ -496,14 +902,15 @@ func (m *Manager) placeStopLoss(ctx context.Context, order *exchanges.Order, sto
 	// Update order book
 	m.mu.Lock()
 	m.orderBook.OpenOrders[placedOrder.ID] = placedOrder
+	m.reducingOrders[placedOrder.ID] = true
 
-	// Link to the original position if exists
-	for _, pos := range m.orderBook.Positions {
-		if pos.Symbol == order.Symbol && pos.EntryOrderID == order.ID {
-			pos.StopLossOrderID = placedOrder.ID
-			break
-		}
+	// Link to the position if exists
+	strategy := m.orderStrategies[order.ID]
+	if pos, exists := m.orderBook.Positions[m.positionKey(order.Symbol, positionSideFor(order.Side))]; exists {
+		pos.StopLossOrderID = placedOrder.ID
+		strategy = pos.Strategy
 	}
+	m.trackExecution(stopOrder, placedOrder, stopLoss, strategy)
 	m.mu.Unlock()
 
 	// Emit order update
@@ -517,8 +924,8 @@ func (m *Manager) placeStopLoss(ctx context.Context, order *exchanges.Order, sto
 	return placedOrder, nil
 }
 
-// placeTakeProfit places a take profit order
-func (m *Manager) placeTakeProfit(ctx context.Context, order *exchanges.Order, takeProfit decimal.Decimal) (*exchanges.Order, error) {
+// placeTakeProfit places a take profit order for amount of the entry order
+func (m *Manager) placeTakeProfit(ctx context.Context, order *exchanges.Order, takeProfit, amount decimal.Decimal, kind string) (*exchanges.Order, error) {
 	if takeProfit.IsZero() {
 		return nil, nil
 	}
@@ -527,9 +934,6 @@ func (m *Manager) placeTakeProfit(ctx context.Context, order *exchanges.Order, t
 		return nil, errors.New("take profit price must be positive")
 	}
 
-	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
-	defer cancel()
-
 	// Determine take profit side (opposite of entry order)
 	takeProfitSide := exchanges.OrderSideSell
 	if order.Side == exchanges.OrderSideSell {
@@ -538,18 +942,20 @@ func (m *Manager) placeTakeProfit(ctx context.Context, order *exchanges.Order, t
 
 	// Create take profit order as limit order
 	takeProfitOrder := &exchanges.Order{
-		Symbol:    order.Symbol,
-		Side:      takeProfitSide,
-		Type:      exchanges.OrderTypeLimit,
-		Amount:    order.Amount,
-		Price:     takeProfit,
-		Status:    exchanges.OrderStatusOpen,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ClientOrderID: childClientOrderID(order, kind),
+		Symbol:        order.Symbol,
+		Side:          takeProfitSide,
+		Type:          exchanges.OrderTypeLimit,
+		Amount:        amount,
+		Price:         takeProfit,
+		Status:        exchanges.OrderStatusOpen,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+		ReduceOnly:    true,
 	}
 
 	// Place the take profit order
-	placedOrder, err := m.exchange.PlaceOrder(callCtx, takeProfitOrder)
+	placedOrder, _, err := m.submitOrder(ctx, takeProfitOrder)
 	if err != nil {
 		m.emitError(ordererrors.New(ordererrors.OperationPlaceTakeProfit, order.Symbol, err))
 		return nil, err
@@ -558,14 +964,15 @@ func (m *Manager) placeTakeProfit(ctx context.Context, order *exchanges.Order, t
 	// Update order book
 	m.mu.Lock()
 	m.orderBook.OpenOrders[placedOrder.ID] = placedOrder
+	m.reducingOrders[placedOrder.ID] = true
 
-	// Link to the original position if exists
-	for _, pos := range m.orderBook.Positions {
-		if pos.Symbol == order.Symbol && pos.EntryOrderID == order.ID {
-			pos.TakeProfitOrderID = placedOrder.ID
-			break
-		}
+	// Link to the position if exists
+	strategy := m.orderStrategies[order.ID]
+	if pos, exists := m.orderBook.Positions[m.positionKey(order.Symbol, positionSideFor(order.Side))]; exists {
+		pos.TakeProfitOrderID = placedOrder.ID
+		strategy = pos.Strategy
 	}
+	m.trackExecution(takeProfitOrder, placedOrder, takeProfit, strategy)
 	m.mu.Unlock()
 
 	// Emit order update
@@ -579,14 +986,10 @@ func (m *Manager) placeTakeProfit(ctx context.Context, order *exchanges.Order, t
 	return placedOrder, nil
 }
 
-// addFilledOrder adds an order to the filled orders list with size limit
+// addFilledOrder adds an order to the filled orders, evicting the oldest
+// once MaxFilledOrdersHistory are kept
 func (m *Manager) addFilledOrder(order *exchanges.Order) {
-	if len(m.orderBook.FilledOrders) >= MaxFilledOrdersHistory {
-		trimmed := make([]*exchanges.Order, MaxFilledOrdersHistory-1)
-		copy(trimmed, m.orderBook.FilledOrders[len(m.orderBook.FilledOrders)-(MaxFilledOrdersHistory-1):])
-		m.orderBook.FilledOrders = trimmed
-	}
-	m.orderBook.FilledOrders = append(m.orderBook.FilledOrders, order)
+	m.orderBook.FilledOrders.Push(order)
 }
 
 // emitOrderUpdate emits an order update
@@ -642,11 +1045,27 @@ func validateOrderRequest(req *OrderRequest) error {
 		return ordererrors.New(ordererrors.OperationValidate, req.Symbol, errors.New("amount must be positive"))
 	}
 	switch req.Type {
-	case exchanges.OrderTypeLimit, exchanges.OrderTypeStopLimit:
+	case exchanges.OrderTypeLimit, exchanges.OrderTypeStopLimit, exchanges.OrderTypeStopMarket:
 		if req.Price.LessThanOrEqual(decimal.Zero) {
 			return ordererrors.New(ordererrors.OperationValidate, req.Symbol, errors.New("price must be positive for limit orders"))
 		}
 	}
+	if err := exchanges.ValidateTimeInForce(req.TimeInForce, req.PostOnly, req.ExpiresAt); err != nil {
+		return ordererrors.New(ordererrors.OperationValidate, req.Symbol, err)
+	}
+	total := decimal.Zero
+	for _, level := range req.TakeProfitLadder {
+		if !level.Price.IsPositive() || !level.Fraction.IsPositive() {
+			return ordererrors.New(ordererrors.OperationValidate, req.Symbol, errors.New("take profit levels must have a positive price and fraction"))
+		}
+		total = total.Add(level.Fraction)
+	}
+	if total.GreaterThan(decimal.NewFromInt(1)) {
+		return ordererrors.New(ordererrors.OperationValidate, req.Symbol, errors.New("take profit levels cannot take more than the position"))
+	}
+	if req.TrailingStop.IsNegative() || req.TrailingStop.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return ordererrors.New(ordererrors.OperationValidate, req.Symbol, errors.New("trailing stop must be a fraction of the price"))
+	}
 	return nil
 }
 
@@ -656,13 +1075,15 @@ func (m *Manager) GetStats() *OrderStats {
 	defer m.mu.RUnlock()
 
 	stats := &OrderStats{
-		TotalOrders:  len(m.orderBook.FilledOrders) + len(m.orderBook.OpenOrders),
-		FilledOrders: len(m.orderBook.FilledOrders),
+		TotalOrders:  m.orderBook.FilledOrders.Len() + len(m.orderBook.OpenOrders),
+		FilledOrders: m.orderBook.FilledOrders.Len(),
 		TotalVolume:  decimal.Zero,
 		TotalFees:    decimal.Zero,
 	}
+	stats.StaleCanceled = m.staleCanceled
+	stats.StaleRepriced = m.staleRepriced
 
-	for _, order := range m.orderBook.FilledOrders {
+	for order := range m.orderBook.FilledOrders.All() {
 		stats.TotalVolume = stats.TotalVolume.Add(order.Filled.Mul(order.Price))
 		if order.Status == exchanges.OrderStatusCanceled {
 			stats.CanceledOrders++
@@ -2,6 +2,7 @@ package order
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -66,6 +67,11 @@ func TestManager_CancelOrder(t *testing.T) {
 	// Cancel the order
 	err = manager.CancelOrder(ctx, placedOrder.ID)
 	testutils.AssertNoError(t, err, "CancelOrder should not return error")
+
+	canceled := manager.GetOrder(placedOrder.ID)
+	testutils.AssertNotNil(t, canceled, "canceled orders should still be known")
+	testutils.AssertEqual(t, exchanges.OrderStatusCanceled, canceled.Status, "order should be canceled")
+	testutils.AssertTrue(t, manager.GetOrder("unknown") == nil, "unknown orders should not be found")
 }
 
 func TestManager_GetOpenOrders(t *testing.T) {
@@ -96,6 +102,36 @@ func TestManager_GetOpenOrders(t *testing.T) {
 	testutils.AssertEqual(t, "BTC-USD", orders[0].Symbol, "Order symbol should match")
 }
 
+func TestManager_GetOpenEntryOrders(t *testing.T) {
+	exchange := testutils.NewMatchingExchange("test-exchange", decimal.NewFromInt(100))
+	manager := NewManager(exchange)
+
+	ctx, cancel := testutils.CreateTestContext()
+	defer cancel()
+	entry, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeLimit,
+		Price:  decimal.NewFromFloat(90),
+		Amount: decimal.NewFromFloat(0.1),
+	})
+	testutils.AssertNoError(t, err, "PlaceOrder should not return error")
+	_, err = manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideSell,
+		Type:       exchanges.OrderTypeLimit,
+		Price:      decimal.NewFromFloat(110),
+		Amount:     decimal.NewFromFloat(1),
+		ReduceOnly: true,
+	})
+	testutils.AssertNoError(t, err, "PlaceOrder should not return error")
+
+	orders := manager.GetOpenEntryOrders()
+	testutils.AssertEqual(t, 2, len(manager.GetOpenOrders()), "Should have 2 open orders")
+	testutils.AssertEqual(t, 1, len(orders), "Reduce-only orders are not entries")
+	testutils.AssertEqual(t, entry.ID, orders[0].ID, "The entry should be listed")
+}
+
 func TestManager_GetPositions(t *testing.T) {
 	exchange := testutils.NewTestExchange("test-exchange")
 	manager := NewManager(exchange)
@@ -211,7 +247,7 @@ func TestManager_OrderStatusChanges(t *testing.T) {
 
 	// Check that order was moved to filled orders
 	testutils.AssertEqual(t, 0, len(manager.GetOpenOrders()), "Should have no open orders after fill")
-	testutils.AssertEqual(t, 1, len(manager.orderBook.FilledOrders), "Should have 1 filled order")
+	testutils.AssertEqual(t, 1, manager.orderBook.FilledOrders.Len(), "Should have 1 filled order")
 
 	// Check that position was created
 	positions := manager.GetPositions()
@@ -221,6 +257,35 @@ func TestManager_OrderStatusChanges(t *testing.T) {
 	testutils.AssertTrue(t, positions[0].EntryPrice.Equal(order.Price), "Position entry price should match order price")
 }
 
+func TestManager_PositionCarriesStrategy(t *testing.T) {
+	exchange := testutils.NewTestExchange("test-exchange")
+	manager := NewManager(exchange)
+
+	req := &OrderRequest{
+		Symbol:   "BTC-USD",
+		Side:     exchanges.OrderSideBuy,
+		Type:     exchanges.OrderTypeLimit,
+		Price:    decimal.NewFromFloat(50000),
+		Amount:   decimal.NewFromFloat(0.1),
+		Strategy: "breakout",
+	}
+
+	ctx, cancel := testutils.CreateTestContext()
+	defer cancel()
+	order, err := manager.PlaceOrder(ctx, req)
+	testutils.AssertNoError(t, err, "PlaceOrder should not return error")
+
+	filledOrder := *order
+	filledOrder.Status = exchanges.OrderStatusFilled
+	filledOrder.Filled = order.Amount
+	manager.handleOrderStatusChange(&filledOrder, order)
+
+	position := manager.GetPosition("BTC-USD")
+	testutils.AssertNotNil(t, position, "fill should open a position")
+	testutils.AssertEqual(t, "breakout", position.Strategy, "position should be attributed to the strategy of its entry")
+	testutils.AssertEqual(t, 0, len(manager.orderStrategies), "filled orders should not stay tracked")
+}
+
 func TestManager_CalculatePnL(t *testing.T) {
 	exchange := testutils.NewTestExchange("test-exchange")
 	manager := NewManager(exchange)
@@ -369,7 +434,8 @@ func TestManager_GetStats(t *testing.T) {
 		Status: exchanges.OrderStatusCanceled,
 	}
 
-	manager.orderBook.FilledOrders = append(manager.orderBook.FilledOrders, filledOrder, cancelledOrder)
+	manager.orderBook.FilledOrders.Push(filledOrder)
+	manager.orderBook.FilledOrders.Push(cancelledOrder)
 
 	stats = manager.GetStats()
 	testutils.AssertEqual(t, 2, stats.TotalOrders, "Total orders should be 2")
@@ -377,3 +443,17 @@ func TestManager_GetStats(t *testing.T) {
 	testutils.AssertEqual(t, 1, stats.CanceledOrders, "Cancelled orders should be 1")
 	testutils.AssertEqual(t, 1.0, stats.SuccessRate, "Success rate should be 1.0")
 }
+
+func TestManager_FilledOrdersBounded(t *testing.T) {
+	manager := NewManager(testutils.NewTestExchange("test-exchange"))
+
+	for i := 0; i < MaxFilledOrdersHistory+10; i++ {
+		manager.addFilledOrder(&exchanges.Order{ID: fmt.Sprintf("order-%d", i), Status: exchanges.OrderStatusFilled})
+	}
+
+	filled := manager.orderBook.FilledOrders
+	testutils.AssertEqual(t, MaxFilledOrdersHistory, filled.Len(), "Filled orders should be capped")
+	testutils.AssertEqual(t, "order-10", filled.At(0).ID, "Oldest filled orders should be evicted first")
+	last, _ := filled.Last()
+	testutils.AssertEqual(t, fmt.Sprintf("order-%d", MaxFilledOrdersHistory+9), last.ID, "Latest filled order should be kept")
+}
@@ -0,0 +1,75 @@
+package order
+
+import (
+	"context"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// HandleMarkPrice marks the positions on the symbol of mark at the
+// exchange's mark price, rather than the last trade, checks their synthetic
+// stops against it and moves their trailing stops after it
+func (m *Manager) HandleMarkPrice(ctx context.Context, mark *exchanges.MarkPrice) {
+	if mark == nil || !mark.Mark.IsPositive() {
+		return
+	}
+
+	m.mu.Lock()
+	if m.marks == nil {
+		m.marks = make(map[string]exchanges.MarkPrice)
+	}
+	m.marks[mark.Symbol] = *mark
+	for _, position := range m.orderBook.Positions {
+		if position.Symbol == mark.Symbol && position.Status == PositionStatusOpen {
+			position.CurrentPrice = mark.Mark
+			position.UnrealizedPnL = m.calculatePnL(position, mark.Mark)
+		}
+	}
+	m.mu.Unlock()
+
+	m.CheckSyntheticStops(ctx, mark.Symbol, mark.Mark)
+	m.trailStops(ctx, mark.Symbol, mark.Mark)
+}
+
+// MarkPrice returns the latest mark price streamed for symbol, false when
+// none was
+func (m *Manager) MarkPrice(symbol string) (exchanges.MarkPrice, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	mark, ok := m.marks[symbol]
+	return mark, ok
+}
+
+// streamMarkPrices subscribes to the mark price of the symbols with a
+// position or a synthetic stop, once per symbol. A failed subscription is
+// not retried: the positions polled still carry their mark.
+func (m *Manager) streamMarkPrices(ctx context.Context) {
+	if m.markStream == nil {
+		return
+	}
+
+	m.mu.Lock()
+	var symbols []string
+	add := func(symbol string) {
+		if !m.markSubscribed[symbol] {
+			m.markSubscribed[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	for _, position := range m.orderBook.Positions {
+		add(position.Symbol)
+	}
+	for _, stop := range m.syntheticStops {
+		add(stop.Symbol)
+	}
+	m.mu.Unlock()
+
+	for _, symbol := range symbols {
+		err := m.markStream.SubscribeMarkPrice(ctx, symbol, func(mark *exchanges.MarkPrice) {
+			m.HandleMarkPrice(ctx, mark)
+		})
+		if err != nil {
+			m.emitError(err)
+		}
+	}
+}
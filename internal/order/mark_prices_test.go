@@ -0,0 +1,68 @@
+package order
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+// markStreamingExchange keeps the mark price callbacks subscribed, by symbol
+type markStreamingExchange struct {
+	*flakyExchange
+	subscriptions map[string]int
+	callbacks     map[string]func(*exchanges.MarkPrice)
+}
+
+func (m *markStreamingExchange) SubscribeMarkPrice(ctx context.Context, symbol string, callback func(*exchanges.MarkPrice)) error {
+	m.subscriptions[symbol]++
+	m.callbacks[symbol] = callback
+	return nil
+}
+
+func (m *markStreamingExchange) mark(symbol string, price float64) {
+	m.callbacks[symbol](&exchanges.MarkPrice{Symbol: symbol, Mark: decimal.NewFromFloat(price)})
+}
+
+func TestManager_MarksPositionsAtStreamedMark(t *testing.T) {
+	exchange := &markStreamingExchange{
+		flakyExchange: newFlakyExchange(0, false),
+		subscriptions: make(map[string]int),
+		callbacks:     make(map[string]func(*exchanges.MarkPrice)),
+	}
+	exchange.fillOnPlace = true
+	exchange.CapabilitiesValue.StopOrders = false
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:   "BTC-USD",
+		Side:     exchanges.OrderSideBuy,
+		Type:     exchanges.OrderTypeLimit,
+		Price:    decimal.NewFromFloat(100),
+		Amount:   decimal.NewFromFloat(2),
+		StopLoss: decimal.NewFromFloat(90),
+	})
+	testutils.AssertNoError(t, err, "entry should be placed")
+
+	manager.streamMarkPrices(ctx)
+	manager.streamMarkPrices(ctx)
+	testutils.AssertEqual(t, 1, exchange.subscriptions["BTC-USD"], "each symbol should be subscribed to once")
+
+	exchange.mark("BTC-USD", 104)
+	position := manager.GetPosition("BTC-USD")
+	testutils.AssertTrue(t, position.CurrentPrice.Equal(decimal.NewFromFloat(104)), "the position should be marked at the exchange's mark")
+	testutils.AssertTrue(t, position.UnrealizedPnL.Equal(decimal.NewFromFloat(8)), "unrealized PnL should follow the mark")
+
+	// Positions polled without their mark keep the streamed one
+	exchange.PositionsValue = []exchanges.Position{{Symbol: "BTC-USD", Side: exchanges.OrderSideBuy, Size: decimal.NewFromFloat(2)}}
+	manager.updatePositions(ctx)
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD").CurrentPrice.Equal(decimal.NewFromFloat(104)), "a missing mark should not wipe the streamed one")
+
+	exchange.mark("BTC-USD", 89.5)
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD") == nil, "the synthetic stop should fire on the mark")
+	mark, ok := manager.MarkPrice("BTC-USD")
+	testutils.AssertTrue(t, ok && mark.Mark.Equal(decimal.NewFromFloat(89.5)), "the latest mark should be kept")
+}
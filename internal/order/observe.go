@@ -0,0 +1,35 @@
+package order
+
+import (
+	"errors"
+
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// ErrObserveOnly is returned for orders placed or canceled while another
+// instance trades the account
+var ErrObserveOnly = errors.New("observe-only: another instance trades the account")
+
+// SetObserveOnly stops placing and canceling any order, protective orders
+// included, while observe is set. Positions and orders are still tracked.
+func (m *Manager) SetObserveOnly(observe bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observeOnly = observe
+}
+
+// ObserveOnly reports whether orders are neither placed nor canceled
+func (m *Manager) ObserveOnly() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.observeOnly
+}
+
+// checkObserveOnly rejects any order change while observing
+func (m *Manager) checkObserveOnly() error {
+	if !m.ObserveOnly() {
+		return nil
+	}
+	telemetry.RecordError("order_observe_only")
+	return ErrObserveOnly
+}
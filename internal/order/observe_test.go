@@ -0,0 +1,44 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_ObserveOnly(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	manager.SetObserveOnly(true)
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeLimit,
+		Price:  decimal.NewFromFloat(100),
+		Amount: decimal.NewFromFloat(1),
+	})
+	testutils.AssertTrue(t, errors.Is(err, ErrObserveOnly), "entries should be refused while observing")
+	testutils.AssertEqual(t, 0, len(exchange.placed), "nothing should reach the exchange")
+	testutils.AssertTrue(t, errors.Is(manager.CancelOrder(ctx, "id-1"), ErrObserveOnly), "cancels should be refused while observing")
+
+	manager.SetObserveOnly(false)
+	placed, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol: "BTC-USD",
+		Side:   exchanges.OrderSideBuy,
+		Type:   exchanges.OrderTypeLimit,
+		Price:  decimal.NewFromFloat(100),
+		Amount: decimal.NewFromFloat(1),
+	})
+	testutils.AssertNoError(t, err, "orders should be placed once trading")
+
+	// Orders of the instance trading are left to it
+	manager.SetObserveOnly(true)
+	testutils.AssertTrue(t, errors.Is(manager.CancelOrder(ctx, placed.ID), ErrObserveOnly), "resting orders should not be canceled while observing")
+	testutils.AssertEqual(t, 1, len(manager.GetOpenOrders()), "order should still be tracked")
+}
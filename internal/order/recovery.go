@@ -0,0 +1,242 @@
+package order
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	ordererrors "github.com/guyghost/constantine/internal/order/errors"
+	"github.com/shopspring/decimal"
+)
+
+// recoveryFillsLookback is how far back fills are listed to infer the entry
+// price of the positions recovered
+const recoveryFillsLookback = 7 * 24 * time.Hour
+
+// RecoveryReport is what Recover adopted from the exchange
+type RecoveryReport struct {
+	Positions []string // Keys of the positions adopted
+	Orders    int      // Open orders adopted
+	Protected int      // Positions whose resting stop or target was adopted
+	// Positions whose entry price could not be inferred from fills, taken
+	// as reported by the exchange instead
+	Uninferred int
+}
+
+// Recover adopts the positions and open orders found on the exchange that
+// the manager does not track yet, such as those left by a previous session,
+// so they are managed instead of stacked upon. Entry prices are inferred from
+// the fills that built the positions where the exchange lists them, and
+// resting reduce-only orders become the stop loss and take profit of the
+// positions they close, resized if they do not cover them. Must be called
+// before Start.
+func (m *Manager) Recover(ctx context.Context) (RecoveryReport, error) {
+	var report RecoveryReport
+
+	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
+	positions, err := m.exchange.GetPositions(callCtx)
+	cancel()
+	if err != nil {
+		return report, ordererrors.New(ordererrors.OperationValidate, "", err)
+	}
+	callCtx, cancel = context.WithTimeout(ctx, defaultAPICallTimeout)
+	orders, err := m.exchange.GetOpenOrders(callCtx, "")
+	cancel()
+	if err != nil {
+		return report, ordererrors.New(ordererrors.OperationValidate, "", err)
+	}
+
+	now := time.Now()
+	var fills []exchanges.Trade
+	if m.fillHistory != nil {
+		callCtx, cancel = context.WithTimeout(ctx, defaultAPICallTimeout)
+		fills, err = m.fillHistory.GetFills(callCtx, "", now.Add(-recoveryFillsLookback))
+		cancel()
+		if err != nil {
+			// Entry prices fall back to those the exchange reports
+			m.emitError(ordererrors.New(ordererrors.OperationValidate, "", err))
+		}
+		sort.SliceStable(fills, func(i, j int) bool {
+			return fills[i].Timestamp.Before(fills[j].Timestamp)
+		})
+	}
+
+	m.mu.Lock()
+	var adopted []*ManagedPosition
+	for _, exchangePos := range positions {
+		if !exchangePos.Size.IsPositive() {
+			continue
+		}
+		side := positionSideFor(exchangePos.Side)
+		key := m.positionKey(exchangePos.Symbol, side)
+		if _, exists := m.orderBook.Positions[key]; exists {
+			continue
+		}
+
+		entryPrice, openedAt, inferred := inferEntry(fills, exchangePos.Symbol, exchangePos.Side, exchangePos.Size)
+		if !inferred {
+			report.Uninferred++
+			entryPrice = exchangePos.EntryPrice
+			if !entryPrice.IsPositive() {
+				entryPrice = exchangePos.MarkPrice
+			}
+			openedAt = now
+		}
+		position := &ManagedPosition{
+			ID:               "recovered-" + key,
+			Symbol:           exchangePos.Symbol,
+			Side:             side,
+			EntryPrice:       entryPrice,
+			CurrentPrice:     exchangePos.MarkPrice,
+			LiquidationPrice: exchangePos.LiquidationPrice,
+			Amount:           exchangePos.Size,
+			Leverage:         exchangePos.Leverage,
+			UnrealizedPnL:    exchangePos.UnrealizedPnL,
+			EntryTime:        openedAt,
+			Status:           PositionStatusOpen,
+		}
+		m.orderBook.Positions[key] = position
+		adopted = append(adopted, position)
+		report.Positions = append(report.Positions, key)
+	}
+
+	for i := range orders {
+		order := orders[i]
+		if _, exists := m.orderBook.OpenOrders[order.ID]; exists || order.ID == "" || isTerminalStatus(order.Status) {
+			continue
+		}
+		m.orderBook.OpenOrders[order.ID] = &order
+		if order.ReduceOnly {
+			m.reducingOrders[order.ID] = true
+		}
+		if m.fillsDrivePositions() {
+			// The positions reported already hold what the order filled
+			if filled := filledQuantity(&order); filled.IsPositive() {
+				m.fillsApplied[order.ID] = filled
+			}
+		}
+		report.Orders++
+	}
+
+	for _, position := range adopted {
+		if m.adoptRestingProtection(position) {
+			report.Protected++
+		}
+	}
+
+	if m.fillsDrivePositions() {
+		// Fills listed already built the positions reported
+		for _, fill := range fills {
+			if fill.ID != "" {
+				m.seenFills[fill.ID] = fill.Timestamp
+			}
+		}
+		m.fillsPolled = now
+	}
+	m.mu.Unlock()
+
+	for _, position := range adopted {
+		m.emitPositionUpdate(position)
+	}
+	if !m.ObserveOnly() {
+		// Stops and targets not covering their whole position are resized
+		for _, position := range adopted {
+			if err := m.syncSymbolProtection(ctx, position.Symbol); err != nil {
+				m.emitError(err)
+			}
+		}
+	}
+	return report, nil
+}
+
+// inferEntry returns the average price and opening time of the position of
+// amount on the side of entrySide, from the fills that built it since it was
+// last flat. Reductions do not change the average entry price, so it is the
+// average of the entries since then. The returned bool is false when fills
+// do not go back far enough.
+func inferEntry(fills []exchanges.Trade, symbol string, entrySide exchanges.OrderSide, amount decimal.Decimal) (decimal.Decimal, time.Time, bool) {
+	remaining := amount
+	notional := decimal.Zero
+	entered := decimal.Zero
+	// Walk back from the latest fill to the one the position opened with
+	for i := len(fills) - 1; i >= 0; i-- {
+		fill := fills[i]
+		if fill.Symbol != symbol || !fill.Amount.IsPositive() {
+			continue
+		}
+		if fill.Side != entrySide {
+			// The position was larger before this reduction
+			remaining = remaining.Add(fill.Amount)
+			continue
+		}
+		// A fill flipping the position only opened its part above flat
+		taken := decimal.Min(fill.Amount, remaining)
+		notional = notional.Add(taken.Mul(fill.Price))
+		entered = entered.Add(taken)
+		remaining = remaining.Sub(fill.Amount)
+		if !remaining.IsPositive() {
+			return notional.Div(entered), fill.Timestamp, true
+		}
+	}
+	return decimal.Zero, time.Time{}, false
+}
+
+// adoptRestingProtection makes the reduce-only orders resting against
+// position its stop loss and take profit, kept sized to the position from
+// then on. Must be called with the lock held.
+func (m *Manager) adoptRestingProtection(position *ManagedPosition) bool {
+	entrySide := exchanges.OrderSideBuy
+	if position.Side == PositionSideShort {
+		entrySide = exchanges.OrderSideSell
+	}
+	key := m.positionKey(position.Symbol, position.Side)
+
+	protection := &positionProtection{}
+	for id, order := range m.orderBook.OpenOrders {
+		if order.Symbol != position.Symbol || order.Side != exitSide(entrySide) || !m.reducingOrders[id] {
+			continue
+		}
+		// Orders not covering the position are resized by the next sync
+		resting := order.Amount.Sub(filledQuantity(order))
+		switch {
+		case order.Type.IsStop() && protection.stopLossOrderID == "":
+			protection.stopLossOrderID = id
+			protection.levels.stopLoss = stopLevel(order)
+			protection.armed = resting
+			protection.armedStop = protection.levels.stopLoss
+			position.StopLoss = protection.levels.stopLoss
+			position.StopLossOrderID = id
+		case order.Type == exchanges.OrderTypeLimit && len(protection.takeProfitOrderIDs) == 0:
+			protection.takeProfitOrderIDs = []string{id}
+			protection.levels.takeProfit = order.Price
+			protection.armedTargets = []takeProfitTarget{{price: order.Price, amount: resting}}
+			position.TakeProfit = order.Price
+			position.TakeProfitOrderID = id
+		}
+	}
+	if protection.stopLossOrderID == "" && len(protection.takeProfitOrderIDs) == 0 {
+		return false
+	}
+
+	// Replacements get client order IDs of their own
+	protection.levels.clientOrderID = newClientOrderID()
+	protection.entry = &exchanges.Order{
+		ID:            position.ID,
+		ClientOrderID: protection.levels.clientOrderID,
+		Symbol:        position.Symbol,
+		Side:          entrySide,
+		Amount:        position.Amount,
+		Status:        exchanges.OrderStatusFilled,
+	}
+	m.protections[key] = protection
+	return true
+}
+
+// stopLevel returns the trigger price of a stop order
+func stopLevel(order *exchanges.Order) decimal.Decimal {
+	if order.StopPrice.IsPositive() {
+		return order.StopPrice
+	}
+	return order.Price
+}
@@ -0,0 +1,125 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_RecoverAdoptsPositionsAndOrders(t *testing.T) {
+	manager, exchange := newFillReportingManager()
+	ctx := context.Background()
+	start := time.Now().Add(-time.Hour)
+
+	exchange.PositionsValue = []exchanges.Position{{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideBuy,
+		Size:       decimal.NewFromFloat(1),
+		EntryPrice: decimal.NewFromFloat(999),
+		MarkPrice:  decimal.NewFromFloat(110),
+	}}
+	stop := exchanges.Order{
+		ID: "stop-1", Symbol: "BTC-USD", Side: exchanges.OrderSideSell, Type: exchanges.OrderTypeStopMarket,
+		Amount: decimal.NewFromFloat(1), StopPrice: decimal.NewFromFloat(90), Status: exchanges.OrderStatusOpen, ReduceOnly: true,
+	}
+	entry := exchanges.Order{
+		ID: "entry-1", Symbol: "ETH-USD", Side: exchanges.OrderSideBuy, Type: exchanges.OrderTypeLimit,
+		Price: decimal.NewFromFloat(10), Amount: decimal.NewFromFloat(2), Status: exchanges.OrderStatusOpen,
+	}
+	exchange.OrdersValue = []exchanges.Order{stop, entry}
+	// Bought 0.5 at 100 and 1 at 130, sold 0.5: the average entry is 120
+	exchange.fills = []exchanges.Trade{
+		{ID: "f1", OrderID: "old-1", Symbol: "BTC-USD", Side: exchanges.OrderSideBuy, Price: decimal.NewFromFloat(100), Amount: decimal.NewFromFloat(0.5), Timestamp: start},
+		{ID: "f2", OrderID: "old-2", Symbol: "BTC-USD", Side: exchanges.OrderSideBuy, Price: decimal.NewFromFloat(130), Amount: decimal.NewFromFloat(1), Timestamp: start.Add(time.Minute)},
+		{ID: "f3", OrderID: "old-3", Symbol: "BTC-USD", Side: exchanges.OrderSideSell, Price: decimal.NewFromFloat(140), Amount: decimal.NewFromFloat(0.5), Timestamp: start.Add(2 * time.Minute)},
+	}
+
+	report, err := manager.Recover(ctx)
+	testutils.AssertNoError(t, err, "Recover should not return error")
+	testutils.AssertEqual(t, 1, len(report.Positions), "position should be adopted")
+	testutils.AssertEqual(t, "BTC-USD", report.Positions[0], "position should be adopted by key")
+	testutils.AssertEqual(t, 2, report.Orders, "open orders should be adopted")
+	testutils.AssertEqual(t, 1, report.Protected, "resting stop should protect the position")
+	testutils.AssertEqual(t, 0, report.Uninferred, "entry price should be inferred from fills")
+
+	position := manager.GetPosition("BTC-USD")
+	testutils.AssertNotNil(t, position, "position should be managed")
+	testutils.AssertTrue(t, position.EntryPrice.Equal(decimal.NewFromFloat(120)), "entry price should average the entries since flat")
+	testutils.AssertEqual(t, start, position.EntryTime, "entry time should be the opening fill")
+	testutils.AssertEqual(t, "stop-1", position.StopLossOrderID, "stop should be linked to the position")
+	testutils.AssertTrue(t, position.StopLoss.Equal(decimal.NewFromFloat(90)), "stop level should be adopted")
+	testutils.AssertEqual(t, 0, len(exchange.placed), "a stop covering the position is kept")
+	testutils.AssertEqual(t, 2, len(manager.GetOpenOrders()), "open orders should be tracked")
+
+	// Fills listed at recovery already built the position
+	manager.pollFills(ctx)
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD").Amount.Equal(decimal.NewFromFloat(1)), "fills should not be applied twice")
+
+	// Recovering again adopts nothing new
+	report, err = manager.Recover(ctx)
+	testutils.AssertNoError(t, err, "Recover should not return error")
+	testutils.AssertEqual(t, 0, len(report.Positions), "tracked positions should not be adopted twice")
+	testutils.AssertEqual(t, 0, report.Orders, "tracked orders should not be adopted twice")
+}
+
+func TestManager_RecoverWithoutFills(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.CapabilitiesValue.StopOrders = true
+	manager := NewManager(exchange)
+	ctx := context.Background()
+
+	exchange.PositionsValue = []exchanges.Position{{
+		Symbol:     "ETH-USD",
+		Side:       exchanges.OrderSideSell,
+		Size:       decimal.NewFromFloat(2),
+		EntryPrice: decimal.NewFromFloat(50),
+	}}
+	// The stop only covers half the position
+	exchange.OrdersValue = []exchanges.Order{{
+		ID: "stop-1", Symbol: "ETH-USD", Side: exchanges.OrderSideBuy, Type: exchanges.OrderTypeStopMarket,
+		Amount: decimal.NewFromFloat(1), StopPrice: decimal.NewFromFloat(55), Status: exchanges.OrderStatusOpen, ReduceOnly: true,
+	}}
+
+	report, err := manager.Recover(ctx)
+	testutils.AssertNoError(t, err, "Recover should not return error")
+	testutils.AssertEqual(t, 1, report.Uninferred, "entry price should come from the exchange")
+
+	position := manager.GetPosition("ETH-USD")
+	testutils.AssertEqual(t, PositionSideShort, position.Side, "side should be adopted")
+	testutils.AssertTrue(t, position.EntryPrice.Equal(decimal.NewFromFloat(50)), "reported entry price should be used")
+
+	testutils.AssertEqual(t, 1, len(exchange.placed), "stop should be replaced to cover the position")
+	for _, placed := range exchange.placed {
+		testutils.AssertTrue(t, placed.Amount.Equal(decimal.NewFromFloat(2)), "replacement should cover the whole position")
+		testutils.AssertTrue(t, placed.StopPrice.Equal(decimal.NewFromFloat(55)), "replacement should keep the stop level")
+		testutils.AssertTrue(t, placed.ReduceOnly, "replacement should be reduce-only")
+	}
+}
+
+func TestInferEntry(t *testing.T) {
+	at := time.Now()
+	fill := func(side exchanges.OrderSide, amount, price float64) exchanges.Trade {
+		at = at.Add(time.Minute)
+		return exchanges.Trade{Symbol: "BTC-USD", Side: side, Amount: decimal.NewFromFloat(amount), Price: decimal.NewFromFloat(price), Timestamp: at}
+	}
+	// Short 1, then a buy of 3 flipped it to a long of 2 at 100, then bought 2 at 130
+	fills := []exchanges.Trade{
+		fill(exchanges.OrderSideSell, 1, 90),
+		fill(exchanges.OrderSideBuy, 3, 100),
+		fill(exchanges.OrderSideBuy, 2, 130),
+	}
+
+	price, openedAt, ok := inferEntry(fills, "BTC-USD", exchanges.OrderSideBuy, decimal.NewFromFloat(4))
+	testutils.AssertTrue(t, ok, "fills should cover the position")
+	testutils.AssertTrue(t, price.Equal(decimal.NewFromFloat(115)), "only the part of the flip above flat should count")
+	testutils.AssertEqual(t, fills[1].Timestamp, openedAt, "position should open with the flip")
+
+	_, _, ok = inferEntry(fills, "BTC-USD", exchanges.OrderSideBuy, decimal.NewFromFloat(10))
+	testutils.AssertFalse(t, ok, "fills not covering the position should not be used")
+	_, _, ok = inferEntry(fills, "ETH-USD", exchanges.OrderSideBuy, decimal.NewFromFloat(1))
+	testutils.AssertFalse(t, ok, "fills of other symbols should be ignored")
+}
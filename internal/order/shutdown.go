@@ -0,0 +1,127 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// ShutdownPolicy controls what happens to resting orders and open positions
+// when the bot shuts down
+type ShutdownPolicy string
+
+const (
+	// ShutdownPolicyCancelAll cancels every resting entry placed by the
+	// manager. The reduce-only stop loss and take profit orders are left in
+	// place so the open positions stay protected while the bot is down.
+	ShutdownPolicyCancelAll ShutdownPolicy = "cancel-all"
+	// ShutdownPolicyFlattenAll closes every position, then cancels the open
+	// orders
+	ShutdownPolicyFlattenAll ShutdownPolicy = "flatten-all"
+	// ShutdownPolicyLeave leaves orders and positions untouched
+	ShutdownPolicyLeave ShutdownPolicy = "leave"
+)
+
+// ParseShutdownPolicy parses a shutdown policy, defaulting to cancel-all
+func ParseShutdownPolicy(value string) (ShutdownPolicy, error) {
+	switch ShutdownPolicy(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ShutdownPolicyCancelAll:
+		return ShutdownPolicyCancelAll, nil
+	case ShutdownPolicyFlattenAll:
+		return ShutdownPolicyFlattenAll, nil
+	case ShutdownPolicyLeave:
+		return ShutdownPolicyLeave, nil
+	default:
+		return "", fmt.Errorf("invalid shutdown policy %q (expected cancel-all, flatten-all or leave)", value)
+	}
+}
+
+// CancelAllOrders cancels every open order tracked by the manager.
+// It attempts all cancellations and returns the combined errors.
+func (m *Manager) CancelAllOrders(ctx context.Context) error {
+	return m.cancelOrders(ctx, m.GetOpenOrders())
+}
+
+// CancelEntryOrders cancels the open orders that may open or grow a position,
+// leaving the reduce-only exits in place. It attempts all cancellations and
+// returns the combined errors.
+func (m *Manager) CancelEntryOrders(ctx context.Context) error {
+	return m.cancelOrders(ctx, m.GetOpenEntryOrders())
+}
+
+// cancelOrders cancels orders and returns the combined errors
+func (m *Manager) cancelOrders(ctx context.Context, orders []*exchanges.Order) error {
+	var errs []error
+	for _, order := range orders {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := m.CancelOrder(ctx, order.ID); err != nil {
+			errs = append(errs, fmt.Errorf("cancel %s: %w", order.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cancelOrphanedExits cancels the reduce-only orders left on symbols without
+// an open position
+func (m *Manager) cancelOrphanedExits(ctx context.Context) error {
+	m.mu.RLock()
+	open := make(map[string]bool, len(m.orderBook.Positions))
+	for _, position := range m.orderBook.Positions {
+		if position.Status == PositionStatusOpen {
+			open[position.Symbol] = true
+		}
+	}
+	var orphaned []*exchanges.Order
+	for id, order := range m.orderBook.OpenOrders {
+		if m.reducingOrders[id] && !open[order.Symbol] {
+			orphaned = append(orphaned, order)
+		}
+	}
+	m.mu.RUnlock()
+	return m.cancelOrders(ctx, orphaned)
+}
+
+// CloseAllPositions closes every open position with a reduce-only market order
+func (m *Manager) CloseAllPositions(ctx context.Context) error {
+	var errs []error
+	for _, position := range m.GetPositions() {
+		if position.Status != PositionStatusOpen {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := m.ClosePositionSide(ctx, position.Symbol, position.Side); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", position.Symbol, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Shutdown applies the shutdown policy. Callers should bound ctx with a
+// timeout so a slow exchange cannot block process exit.
+func (m *Manager) Shutdown(ctx context.Context, policy ShutdownPolicy) error {
+	switch policy {
+	case ShutdownPolicyLeave:
+		return nil
+	case ShutdownPolicyCancelAll:
+		return m.CancelEntryOrders(ctx)
+	case ShutdownPolicyFlattenAll:
+		// Entries are canceled first so nothing reopens a position. Stop
+		// loss and take profit orders stay in place until the close of their
+		// position is placed, so a close that fails leaves it protected.
+		entryErr := m.CancelEntryOrders(ctx)
+		closeErr := m.CloseAllPositions(ctx)
+		exitErr := m.cancelOrphanedExits(ctx)
+		return errors.Join(entryErr, closeErr, exitErr)
+	default:
+		return fmt.Errorf("unknown shutdown policy %q", policy)
+	}
+}
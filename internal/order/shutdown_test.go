@@ -0,0 +1,97 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestParseShutdownPolicy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ShutdownPolicy
+		wantErr  bool
+	}{
+		{"", ShutdownPolicyCancelAll, false},
+		{"cancel-all", ShutdownPolicyCancelAll, false},
+		{"FLATTEN-ALL", ShutdownPolicyFlattenAll, false},
+		{" leave ", ShutdownPolicyLeave, false},
+		{"panic", "", true},
+	}
+
+	for _, tt := range tests {
+		policy, err := ParseShutdownPolicy(tt.input)
+		if tt.wantErr {
+			testutils.AssertError(t, err, "expected error for "+tt.input)
+			continue
+		}
+		testutils.AssertNoError(t, err, "unexpected error for "+tt.input)
+		testutils.AssertEqual(t, tt.expected, policy, "policy mismatch for "+tt.input)
+	}
+}
+
+func newShutdownTestManager() (*Manager, *testutils.TestExchange) {
+	exchange := testutils.NewTestExchange("test-exchange")
+	manager := NewManager(exchange)
+	manager.orderBook.OpenOrders["entry-1"] = &exchanges.Order{ID: "entry-1", Symbol: "ETH-USD", Status: exchanges.OrderStatusOpen}
+	manager.orderBook.OpenOrders["stop-1"] = &exchanges.Order{ID: "stop-1", Symbol: "BTC-USD", Status: exchanges.OrderStatusOpen, ReduceOnly: true}
+	manager.reducingOrders["stop-1"] = true
+	manager.orderBook.Positions["BTC-USD"] = &ManagedPosition{
+		Symbol: "BTC-USD",
+		Side:   PositionSideLong,
+		Amount: decimal.NewFromFloat(0.1),
+		Status: PositionStatusOpen,
+	}
+	return manager, exchange
+}
+
+func TestManager_ShutdownLeave(t *testing.T) {
+	manager, _ := newShutdownTestManager()
+
+	err := manager.Shutdown(context.Background(), ShutdownPolicyLeave)
+	testutils.AssertNoError(t, err, "leave should not fail")
+	testutils.AssertEqual(t, 2, len(manager.GetOpenOrders()), "orders should be left resting")
+}
+
+func TestManager_ShutdownCancelAll(t *testing.T) {
+	manager, _ := newShutdownTestManager()
+
+	err := manager.Shutdown(context.Background(), ShutdownPolicyCancelAll)
+	testutils.AssertNoError(t, err, "cancel-all should not fail")
+	testutils.AssertEqual(t, 0, len(manager.GetOpenEntryOrders()), "entries should be canceled")
+	testutils.AssertEqual(t, 1, len(manager.GetOpenOrders()), "stop loss should keep protecting the position")
+	testutils.AssertEqual(t, PositionStatusOpen, manager.GetPosition("BTC-USD").Status, "position should stay open")
+}
+
+func TestManager_ShutdownFlattenAll(t *testing.T) {
+	manager, _ := newShutdownTestManager()
+
+	err := manager.Shutdown(context.Background(), ShutdownPolicyFlattenAll)
+	testutils.AssertNoError(t, err, "flatten-all should not fail")
+	testutils.AssertEqual(t, PositionStatusClosed, manager.GetPosition("BTC-USD").Status, "position should be closed")
+	testutils.AssertEqual(t, 0, len(manager.GetOpenOrders()), "entries and protection should be canceled once closed")
+}
+
+func TestManager_ShutdownFlattenAllKeepsProtectionWhenCloseFails(t *testing.T) {
+	manager, exchange := newShutdownTestManager()
+	exchange.PlaceOrderError = errors.New("exchange unavailable")
+
+	err := manager.Shutdown(context.Background(), ShutdownPolicyFlattenAll)
+	testutils.AssertError(t, err, "close errors should be reported")
+	testutils.AssertEqual(t, PositionStatusOpen, manager.GetPosition("BTC-USD").Status, "position should stay open")
+	testutils.AssertEqual(t, 0, len(manager.GetOpenEntryOrders()), "entries should be canceled")
+	testutils.AssertEqual(t, 1, len(manager.GetOpenOrders()), "stop loss should keep protecting the open position")
+}
+
+func TestManager_ShutdownReportsCancelErrors(t *testing.T) {
+	manager, exchange := newShutdownTestManager()
+	exchange.CancelOrderError = errors.New("exchange unavailable")
+
+	err := manager.Shutdown(context.Background(), ShutdownPolicyCancelAll)
+	testutils.AssertError(t, err, "cancel errors should be reported")
+	testutils.AssertEqual(t, 1, len(manager.GetOpenEntryOrders()), "failed cancels should remain tracked")
+}
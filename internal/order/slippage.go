@@ -0,0 +1,241 @@
+package order
+
+import (
+	"sort"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// Liquidity tells whether a fill added liquidity to the book or took it
+type Liquidity string
+
+const (
+	LiquidityMaker Liquidity = "maker"
+	LiquidityTaker Liquidity = "taker"
+)
+
+// Execution measures how an order filled against the price it was placed on
+type Execution struct {
+	Time     time.Time           `json:"time"`
+	Exchange string              `json:"exchange"`
+	Symbol   string              `json:"symbol"`
+	Side     exchanges.OrderSide `json:"side"`
+	Type     exchanges.OrderType `json:"type"`
+	Strategy string              `json:"strategy,omitempty"`
+	OrderID  string              `json:"order_id"`
+	// ReferencePrice is the signal or mark price the order was placed on,
+	// SubmittedPrice its limit price, zero for market orders
+	ReferencePrice decimal.Decimal `json:"reference_price"`
+	SubmittedPrice decimal.Decimal `json:"submitted_price"`
+	FillPrice      decimal.Decimal `json:"fill_price"`
+	Amount         decimal.Decimal `json:"amount"`
+	// SlippageBps is how much worse the fill price is than the reference
+	// price, in basis points; negative when it is better. Zero without a
+	// reference price.
+	SlippageBps decimal.Decimal `json:"slippage_bps"`
+	Liquidity   Liquidity       `json:"liquidity"`
+	// Fee is what the fills reported by the exchange cost, in the quote
+	// currency; zero when the exchange does not report fills
+	Fee decimal.Decimal `json:"fee"`
+}
+
+// Measured reports whether the execution has a reference price to measure
+// slippage against
+func (e Execution) Measured() bool {
+	return e.ReferencePrice.IsPositive() && e.FillPrice.IsPositive()
+}
+
+// trackedOrder is an order whose execution is measured once it is done
+type trackedOrder struct {
+	submitted *exchanges.Order
+	reference decimal.Decimal
+	strategy  string
+	immediate bool // Filled, at least partly, when placed
+	placedAt  time.Time
+
+	// Fees and liquidity of the fills the exchange reported
+	fee        decimal.Decimal
+	fills      int
+	takerFills int
+}
+
+// slippageBps returns how much worse fill is than reference for side, in
+// basis points
+func slippageBps(side exchanges.OrderSide, reference, fill decimal.Decimal) decimal.Decimal {
+	if !reference.IsPositive() || !fill.IsPositive() {
+		return decimal.Zero
+	}
+	diff := fill.Sub(reference)
+	if side == exchanges.OrderSideSell {
+		diff = diff.Neg()
+	}
+	return diff.Div(reference).Mul(decimal.NewFromInt(10000)).Round(2)
+}
+
+// SetExecutionCallback sets the callback notified of the execution of every
+// order placed through the manager once it is done filling
+func (m *Manager) SetExecutionCallback(callback func(Execution)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.onExecution = callback
+}
+
+// trackExecution starts measuring the execution of placed, submitted as
+// submitted on reference price. Must be called with the lock held.
+func (m *Manager) trackExecution(submitted, placed *exchanges.Order, reference decimal.Decimal, strategy string) {
+	m.trackedOrders[placed.ID] = &trackedOrder{
+		submitted: submitted,
+		reference: reference,
+		strategy:  strategy,
+		immediate: filledQuantity(placed).IsPositive(),
+		placedAt:  time.Now(),
+	}
+}
+
+// completeExecution returns the execution of order once it can no longer
+// fill, nil if it is not tracked, still working or never filled. Must be
+// called with the lock held.
+func (m *Manager) completeExecution(order *exchanges.Order) *Execution {
+	tracked, exists := m.trackedOrders[order.ID]
+	if !exists || !isTerminalStatus(order.Status) {
+		return nil
+	}
+	delete(m.trackedOrders, order.ID)
+
+	filled := filledQuantity(order)
+	if !filled.IsPositive() {
+		return nil
+	}
+
+	fillPrice := order.AveragePrice
+	if !fillPrice.IsPositive() {
+		fillPrice = order.Price
+	}
+	// Without fills reported by the venue, liquidity is estimated: market
+	// orders, triggered stops and orders crossing the book on arrival take
+	// liquidity; the rest rested on the book until filled
+	submitted := tracked.submitted
+	fee, liquidity := tracked.reportedLiquidity()
+	if liquidity == "" {
+		liquidity = LiquidityMaker
+		if submitted.Type != exchanges.OrderTypeLimit || (tracked.immediate && !submitted.PostOnly) {
+			liquidity = LiquidityTaker
+		}
+	}
+
+	var submittedPrice decimal.Decimal
+	if submitted.Type != exchanges.OrderTypeMarket {
+		submittedPrice = submitted.Price
+	}
+	return &Execution{
+		Time:           time.Now(),
+		Exchange:       m.exchange.Name(),
+		Symbol:         submitted.Symbol,
+		Side:           submitted.Side,
+		Type:           submitted.Type,
+		Strategy:       tracked.strategy,
+		OrderID:        order.ID,
+		ReferencePrice: tracked.reference,
+		SubmittedPrice: submittedPrice,
+		FillPrice:      fillPrice,
+		Amount:         filled,
+		SlippageBps:    slippageBps(submitted.Side, tracked.reference, fillPrice),
+		Liquidity:      liquidity,
+		Fee:            fee,
+	}
+}
+
+// emitExecution records execution in telemetry and notifies the callback
+func (m *Manager) emitExecution(execution *Execution) {
+	if execution == nil {
+		return
+	}
+	telemetry.RecordExecution(execution.Exchange, execution.Symbol, string(execution.Liquidity),
+		execution.SlippageBps.InexactFloat64(), execution.Measured())
+
+	m.mu.RLock()
+	callback := m.onExecution
+	m.mu.RUnlock()
+
+	if callback != nil {
+		safeInvoke(func() { callback(*execution) })
+	}
+}
+
+// ExecutionSummary is the execution quality of the orders filled on a
+// symbol of an exchange
+type ExecutionSummary struct {
+	Exchange string `json:"exchange"`
+	Symbol   string `json:"symbol"`
+	Fills    int    `json:"fills"`
+	Maker    int    `json:"maker"`
+	Taker    int    `json:"taker"`
+	// Slippage of the fills with a reference price: mean, weighted by
+	// notional, and worst
+	Measured         int             `json:"measured"`
+	AvgSlippageBps   decimal.Decimal `json:"avg_slippage_bps"`
+	WorstSlippageBps decimal.Decimal `json:"worst_slippage_bps"`
+	Notional         decimal.Decimal `json:"notional"`
+}
+
+// MakerShare returns the share of fills that added liquidity, from 0 to 1
+func (s ExecutionSummary) MakerShare() float64 {
+	if s.Fills == 0 {
+		return 0
+	}
+	return float64(s.Maker) / float64(s.Fills)
+}
+
+// SummarizeExecutions aggregates executions per exchange and symbol, sorted
+// by exchange and symbol
+func SummarizeExecutions(executions []Execution) []ExecutionSummary {
+	type key struct{ exchange, symbol string }
+	summaries := make(map[key]*ExecutionSummary)
+	weighted := make(map[key]decimal.Decimal)
+	measuredNotional := make(map[key]decimal.Decimal)
+
+	for _, e := range executions {
+		k := key{e.Exchange, e.Symbol}
+		s, exists := summaries[k]
+		if !exists {
+			s = &ExecutionSummary{Exchange: e.Exchange, Symbol: e.Symbol}
+			summaries[k] = s
+		}
+		s.Fills++
+		if e.Liquidity == LiquidityMaker {
+			s.Maker++
+		} else {
+			s.Taker++
+		}
+		notional := e.FillPrice.Mul(e.Amount)
+		s.Notional = s.Notional.Add(notional)
+
+		if !e.Measured() {
+			continue
+		}
+		if s.Measured == 0 || e.SlippageBps.GreaterThan(s.WorstSlippageBps) {
+			s.WorstSlippageBps = e.SlippageBps
+		}
+		s.Measured++
+		weighted[k] = weighted[k].Add(e.SlippageBps.Mul(notional))
+		measuredNotional[k] = measuredNotional[k].Add(notional)
+	}
+
+	result := make([]ExecutionSummary, 0, len(summaries))
+	for k, s := range summaries {
+		if measuredNotional[k].IsPositive() {
+			s.AvgSlippageBps = weighted[k].Div(measuredNotional[k]).Round(2)
+		}
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Exchange != result[j].Exchange {
+			return result[i].Exchange < result[j].Exchange
+		}
+		return result[i].Symbol < result[j].Symbol
+	})
+	return result
+}
@@ -0,0 +1,105 @@
+package order
+
+import (
+	"context"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_MeasuresExecutions(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	manager := NewManager(exchange)
+	var executions []Execution
+	manager.SetExecutionCallback(func(execution Execution) {
+		executions = append(executions, execution)
+	})
+	ctx := context.Background()
+
+	limit := func(side exchanges.OrderSide, price float64) *OrderRequest {
+		return &OrderRequest{
+			Symbol:         "BTC-USD",
+			Side:           side,
+			Type:           exchanges.OrderTypeLimit,
+			Price:          decimal.NewFromFloat(price),
+			Amount:         decimal.NewFromFloat(0.1),
+			ReferencePrice: decimal.NewFromInt(100),
+			Strategy:       "scalping",
+		}
+	}
+
+	// A limit order crossing the book fills on placement and takes liquidity
+	exchange.fillOnPlace = true
+	_, err := manager.PlaceOrder(ctx, limit(exchanges.OrderSideBuy, 101))
+	testutils.AssertNoError(t, err, "PlaceOrder should not return error")
+	testutils.AssertEqual(t, 1, len(executions), "filled order should be reported")
+	taker := executions[0]
+	testutils.AssertEqual(t, LiquidityTaker, taker.Liquidity, "order filled on placement should be taker")
+	testutils.AssertTrue(t, taker.SlippageBps.Equal(decimal.NewFromInt(100)), "buying 1% above the signal is 100 bps of slippage")
+	testutils.AssertTrue(t, taker.SubmittedPrice.Equal(decimal.NewFromInt(101)), "submitted price should be the limit price")
+	testutils.AssertEqual(t, "scalping", taker.Strategy, "execution should carry the strategy")
+	testutils.AssertEqual(t, "flaky", taker.Exchange, "execution should carry the exchange")
+
+	// A resting order filled later made liquidity; a better fill is negative slippage
+	exchange.fillOnPlace = false
+	placed, err := manager.PlaceOrder(ctx, limit(exchanges.OrderSideSell, 100))
+	testutils.AssertNoError(t, err, "PlaceOrder should not return error")
+	testutils.AssertEqual(t, 1, len(executions), "open order should not be reported")
+
+	filled := *placed
+	filled.Status = exchanges.OrderStatusFilled
+	filled.FilledAmount = placed.Amount
+	filled.AveragePrice = decimal.NewFromFloat(100.5)
+	manager.handleOrderStatusChange(&filled, placed)
+	testutils.AssertEqual(t, 2, len(executions), "filled order should be reported")
+	maker := executions[1]
+	testutils.AssertEqual(t, LiquidityMaker, maker.Liquidity, "resting order should be maker")
+	testutils.AssertTrue(t, maker.FillPrice.Equal(decimal.NewFromFloat(100.5)), "fill price should be the average price")
+	testutils.AssertTrue(t, maker.SlippageBps.Equal(decimal.NewFromInt(-50)), "selling above the signal is negative slippage")
+
+	// Orders canceled without a fill are not executions
+	placed, err = manager.PlaceOrder(ctx, limit(exchanges.OrderSideBuy, 99))
+	testutils.AssertNoError(t, err, "PlaceOrder should not return error")
+	testutils.AssertNoError(t, manager.CancelOrder(ctx, placed.ID), "CancelOrder should not return error")
+	testutils.AssertEqual(t, 2, len(executions), "canceled order should not be reported")
+	testutils.AssertEqual(t, 0, len(manager.trackedOrders), "done orders should no longer be tracked")
+}
+
+func TestSummarizeExecutions(t *testing.T) {
+	execution := func(exchange, symbol string, liquidity Liquidity, reference, fill float64) Execution {
+		return Execution{
+			Exchange:       exchange,
+			Symbol:         symbol,
+			Side:           exchanges.OrderSideBuy,
+			ReferencePrice: decimal.NewFromFloat(reference),
+			FillPrice:      decimal.NewFromFloat(fill),
+			Amount:         decimal.NewFromInt(1),
+			SlippageBps:    slippageBps(exchanges.OrderSideBuy, decimal.NewFromFloat(reference), decimal.NewFromFloat(fill)),
+			Liquidity:      liquidity,
+		}
+	}
+
+	summaries := SummarizeExecutions([]Execution{
+		execution("dydx", "ETH-USD", LiquidityTaker, 100, 100.2),
+		execution("coinbase", "BTC-USD", LiquidityMaker, 100, 100),
+		execution("coinbase", "BTC-USD", LiquidityTaker, 100, 101),
+		execution("coinbase", "BTC-USD", LiquidityTaker, 0, 100),
+	})
+	testutils.AssertEqual(t, 2, len(summaries), "executions should be grouped by exchange and symbol")
+
+	btc := summaries[0]
+	testutils.AssertEqual(t, "coinbase", btc.Exchange, "summaries should be sorted by exchange")
+	testutils.AssertEqual(t, 3, btc.Fills, "every fill should be counted")
+	testutils.AssertEqual(t, 1, btc.Maker, "maker fills should be counted")
+	testutils.AssertEqual(t, 2, btc.Taker, "taker fills should be counted")
+	testutils.AssertEqual(t, 2, btc.Measured, "fills without a reference price should not be measured")
+	testutils.AssertTrue(t, btc.WorstSlippageBps.Equal(decimal.NewFromInt(100)), "worst slippage should be kept")
+	// 0 bps on 100 and 100 bps on 101 of notional
+	testutils.AssertTrue(t, btc.AvgSlippageBps.Equal(decimal.NewFromFloat(50.25)), "average slippage should be weighted by notional")
+	testutils.AssertTrue(t, btc.MakerShare() > 0.33 && btc.MakerShare() < 0.34, "maker share should be a third")
+
+	eth := summaries[1]
+	testutils.AssertTrue(t, eth.AvgSlippageBps.Equal(decimal.NewFromInt(20)), "single fill average is its slippage")
+}
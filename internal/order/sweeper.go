@@ -0,0 +1,222 @@
+package order
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// StaleAction is what the sweeper does with entries resting longer than
+// their max age
+type StaleAction string
+
+const (
+	StaleActionCancel  StaleAction = "cancel"
+	StaleActionReprice StaleAction = "reprice" // Replace the remainder at the touch
+)
+
+// ParseStaleAction parses a stale action, case-insensitively
+func ParseStaleAction(value string) (StaleAction, bool) {
+	switch action := StaleAction(strings.ToLower(strings.TrimSpace(value))); action {
+	case StaleActionCancel, StaleActionReprice:
+		return action, true
+	}
+	return "", false
+}
+
+// OrderAgeLimit is how long the limit entries of a strategy may rest before
+// the sweeper acts on them. A zero MaxAge lets them rest indefinitely.
+type OrderAgeLimit struct {
+	MaxAge time.Duration
+	Action StaleAction
+}
+
+// OrderAgeLimits are the age limits of resting entries: Default for the
+// strategies without their own
+type OrderAgeLimits struct {
+	Default    OrderAgeLimit
+	Strategies map[string]OrderAgeLimit
+}
+
+// ParseOrderAgeLimits parses age limits by strategy written as
+// "name:seconds:action,name:seconds", the action defaulting to cancel.
+// Invalid entries are skipped.
+func ParseOrderAgeLimits(value string) map[string]OrderAgeLimit {
+	limits := make(map[string]OrderAgeLimit)
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.Split(entry, ":")
+		name := strings.TrimSpace(parts[0])
+		if name == "" || len(parts) < 2 || len(parts) > 3 {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || seconds < 0 {
+			continue
+		}
+		limit := OrderAgeLimit{MaxAge: time.Duration(seconds) * time.Second, Action: StaleActionCancel}
+		if len(parts) == 3 {
+			action, ok := ParseStaleAction(parts[2])
+			if !ok {
+				continue
+			}
+			limit.Action = action
+		}
+		limits[name] = limit
+	}
+	return limits
+}
+
+// For returns the age limit of the entries of strategy
+func (l OrderAgeLimits) For(strategy string) OrderAgeLimit {
+	if limit, ok := l.Strategies[strategy]; ok {
+		return limit
+	}
+	return l.Default
+}
+
+// Enabled reports whether the entries of any strategy have a max age
+func (l OrderAgeLimits) Enabled() bool {
+	if l.Default.MaxAge > 0 {
+		return true
+	}
+	for _, limit := range l.Strategies {
+		if limit.MaxAge > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// SetOrderAgeLimits sets how long limit entries may rest before they are
+// canceled or repriced
+func (m *Manager) SetOrderAgeLimits(limits OrderAgeLimits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.orderAgeLimits = limits
+}
+
+// staleOrder is a resting entry past its max age
+type staleOrder struct {
+	order     *exchanges.Order
+	limit     OrderAgeLimit
+	strategy  string
+	reference decimal.Decimal
+	levels    protectionLevels
+}
+
+// orderPlacedAt returns when order was placed, zero when unknown. Must be
+// called with the lock held.
+func (m *Manager) orderPlacedAt(order *exchanges.Order) time.Time {
+	if tracked, ok := m.trackedOrders[order.ID]; ok && !tracked.placedAt.IsZero() {
+		return tracked.placedAt
+	}
+	return order.CreatedAt
+}
+
+// sweepStaleOrders cancels or reprices the limit entries resting longer than
+// the max age of their strategy. Exits and protection are never swept, so
+// the sweeper cannot leave a position without its way out.
+func (m *Manager) sweepStaleOrders(ctx context.Context) {
+	m.mu.RLock()
+	limits := m.orderAgeLimits
+	if !limits.Enabled() {
+		m.mu.RUnlock()
+		return
+	}
+	now := time.Now()
+	var stale []staleOrder
+	for id, order := range m.orderBook.OpenOrders {
+		if order.Type != exchanges.OrderTypeLimit || m.reducingOrders[id] || order.ReduceOnly {
+			continue
+		}
+		strategy := m.orderStrategies[id]
+		limit := limits.For(strategy)
+		placedAt := m.orderPlacedAt(order)
+		if limit.MaxAge <= 0 || placedAt.IsZero() || now.Sub(placedAt) < limit.MaxAge {
+			continue
+		}
+		entry := staleOrder{order: order, limit: limit, strategy: strategy, levels: m.pendingProtection[id]}
+		if tracked, ok := m.trackedOrders[id]; ok {
+			entry.reference = tracked.reference
+		}
+		stale = append(stale, entry)
+	}
+	m.mu.RUnlock()
+
+	for _, entry := range stale {
+		if m.halted(entry.order.Symbol) {
+			continue
+		}
+		m.sweepOrder(ctx, entry)
+	}
+}
+
+// sweepOrder cancels a stale entry and, under the reprice action, places its
+// unfilled remainder again at the touch with the same protection
+func (m *Manager) sweepOrder(ctx context.Context, stale staleOrder) {
+	order := stale.order
+	if err := m.CancelOrder(ctx, order.ID); err != nil {
+		// Most likely filled or canceled in the meantime; the monitor loop
+		// picks its final state up
+		return
+	}
+
+	repriced := stale.limit.Action == StaleActionReprice && m.repriceOrder(ctx, stale)
+
+	m.mu.Lock()
+	if repriced {
+		m.staleRepriced++
+	} else {
+		m.staleCanceled++
+	}
+	m.mu.Unlock()
+}
+
+// repriceOrder places the remainder of a canceled stale entry at the touch,
+// reporting whether it was placed
+func (m *Manager) repriceOrder(ctx context.Context, stale staleOrder) bool {
+	order := stale.order
+	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
+	final, err := m.exchange.GetOrder(callCtx, order.ID)
+	if err == nil && final != nil && final.Amount.IsPositive() {
+		order = final
+	}
+	ticker, err := m.exchange.GetTicker(callCtx, order.Symbol)
+	cancel()
+	if err != nil {
+		m.emitError(err)
+		return false
+	}
+
+	remaining := order.Amount.Sub(filledQuantity(order))
+	price := ticker.Bid
+	if order.Side == exchanges.OrderSideSell {
+		price = ticker.Ask
+	}
+	if !remaining.IsPositive() || !price.IsPositive() {
+		return false
+	}
+
+	_, err = m.PlaceOrder(ctx, &OrderRequest{
+		Symbol:           order.Symbol,
+		Side:             order.Side,
+		Type:             exchanges.OrderTypeLimit,
+		Price:            price,
+		Amount:           remaining,
+		StopLoss:         stale.levels.stopLoss,
+		TakeProfit:       stale.levels.takeProfit,
+		TakeProfitLadder: stale.levels.ladder,
+		TrailingStop:     stale.levels.trailingStop,
+		TimeInForce:      order.TimeInForce,
+		PostOnly:         order.PostOnly,
+		ExpiresAt:        order.ExpiresAt,
+		Strategy:         stale.strategy,
+		ReferencePrice:   stale.reference,
+	})
+	// PlaceOrder reports its own errors
+	return err == nil
+}
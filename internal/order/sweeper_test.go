@@ -0,0 +1,124 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestParseOrderAgeLimits(t *testing.T) {
+	limits := ParseOrderAgeLimits("fast:30:REPRICE, swing : 600,bad:x,neg:-1,odd:10:maybe,:5,bare")
+
+	testutils.AssertEqual(t, 2, len(limits), "invalid entries should be skipped")
+	testutils.AssertEqual(t, OrderAgeLimit{MaxAge: 30 * time.Second, Action: StaleActionReprice}, limits["fast"], "fast should be parsed")
+	testutils.AssertEqual(t, OrderAgeLimit{MaxAge: 10 * time.Minute, Action: StaleActionCancel}, limits["swing"], "the action should default to cancel")
+}
+
+// placeAgedOrder places req and backdates it by age
+func placeAgedOrder(t *testing.T, manager *Manager, req *OrderRequest, age time.Duration) *exchanges.Order {
+	t.Helper()
+	placed, err := manager.PlaceOrder(context.Background(), req)
+	testutils.AssertNoError(t, err, "order should be placed")
+	manager.mu.Lock()
+	manager.trackedOrders[placed.ID].placedAt = time.Now().Add(-age)
+	manager.mu.Unlock()
+	return placed
+}
+
+func TestManager_SweepCancelsStaleEntries(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	manager := NewManager(exchange)
+	manager.SetOrderAgeLimits(OrderAgeLimits{
+		Strategies: map[string]OrderAgeLimit{"fast": {MaxAge: time.Minute, Action: StaleActionCancel}},
+	})
+
+	entry := func(strategy string) *OrderRequest {
+		return &OrderRequest{
+			Symbol:   "BTC-USD",
+			Side:     exchanges.OrderSideBuy,
+			Type:     exchanges.OrderTypeLimit,
+			Price:    decimal.NewFromFloat(100),
+			Amount:   decimal.NewFromFloat(1),
+			Strategy: strategy,
+		}
+	}
+	stale := placeAgedOrder(t, manager, entry("fast"), 2*time.Minute)
+	fresh := placeAgedOrder(t, manager, entry("fast"), 30*time.Second)
+	unlimited := placeAgedOrder(t, manager, entry("swing"), time.Hour)
+	exit := placeAgedOrder(t, manager, &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideSell,
+		Type:       exchanges.OrderTypeLimit,
+		Price:      decimal.NewFromFloat(110),
+		Amount:     decimal.NewFromFloat(1),
+		ReduceOnly: true,
+		Strategy:   "fast",
+	}, time.Hour)
+
+	manager.sweepStaleOrders(context.Background())
+
+	open := make(map[string]bool)
+	for _, order := range manager.GetOpenOrders() {
+		open[order.ID] = true
+	}
+	testutils.AssertFalse(t, open[stale.ID], "the stale entry should be canceled")
+	testutils.AssertTrue(t, open[fresh.ID], "entries within their max age should rest")
+	testutils.AssertTrue(t, open[unlimited.ID], "entries of strategies without a max age should rest")
+	testutils.AssertTrue(t, open[exit.ID], "exits should never be swept")
+
+	stats := manager.GetStats()
+	testutils.AssertEqual(t, 1, stats.StaleCanceled, "the cancel should be counted")
+	testutils.AssertEqual(t, 0, stats.StaleRepriced, "nothing should be repriced")
+}
+
+func TestManager_SweepRepricesStaleEntries(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.TickerValue = &exchanges.Ticker{
+		Symbol: "BTC-USD",
+		Bid:    decimal.NewFromFloat(104),
+		Ask:    decimal.NewFromFloat(104.5),
+	}
+	manager := NewManager(exchange)
+	manager.SetOrderAgeLimits(OrderAgeLimits{
+		Default: OrderAgeLimit{MaxAge: time.Minute, Action: StaleActionReprice},
+	})
+
+	stale := placeAgedOrder(t, manager, &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideBuy,
+		Type:       exchanges.OrderTypeLimit,
+		Price:      decimal.NewFromFloat(100),
+		Amount:     decimal.NewFromFloat(1),
+		StopLoss:   decimal.NewFromFloat(95),
+		TakeProfit: decimal.NewFromFloat(110),
+		PostOnly:   true,
+		Strategy:   "main",
+	}, 2*time.Minute)
+
+	// Partly filled before it went stale
+	exchange.OrdersValue = []exchanges.Order{withStatus(stale, exchanges.OrderStatusPartially, 0.25, 100)}
+	manager.updateOrders(context.Background())
+	exchange.OrdersValue = nil
+
+	manager.sweepStaleOrders(context.Background())
+
+	entries := manager.GetOpenEntryOrders()
+	testutils.AssertEqual(t, 1, len(entries), "the remainder should be placed again")
+	repriced := entries[0]
+	testutils.AssertTrue(t, repriced.ID != stale.ID, "the stale entry should be replaced")
+	testutils.AssertTrue(t, repriced.Price.Equal(decimal.NewFromFloat(104)), "the remainder should rest at the bid")
+	testutils.AssertTrue(t, repriced.Amount.Equal(decimal.NewFromFloat(0.75)), "only the remainder should be placed again")
+	testutils.AssertTrue(t, repriced.PostOnly, "the remainder should keep its flags")
+
+	manager.mu.RLock()
+	levels := manager.pendingProtection[repriced.ID]
+	manager.mu.RUnlock()
+	testutils.AssertTrue(t, levels.stopLoss.Equal(decimal.NewFromFloat(95)), "the remainder should keep its stop loss")
+	testutils.AssertTrue(t, levels.takeProfit.Equal(decimal.NewFromFloat(110)), "the remainder should keep its take profit")
+
+	testutils.AssertEqual(t, 1, manager.GetStats().StaleRepriced, "the reprice should be counted")
+}
@@ -0,0 +1,317 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	ordererrors "github.com/guyghost/constantine/internal/order/errors"
+	"github.com/guyghost/constantine/internal/statefile"
+	"github.com/shopspring/decimal"
+)
+
+// SyntheticStop is a stop loss held by the manager for a venue without
+// native stop orders. It is fired as a reduce-only market order once the
+// mark price trades through StopPrice.
+type SyntheticStop struct {
+	Key           string              `json:"key"` // Position key: the symbol, or symbol and side in hedge mode
+	Symbol        string              `json:"symbol"`
+	Side          exchanges.OrderSide `json:"side"` // Side of the exit order
+	StopPrice     decimal.Decimal     `json:"stop_price"`
+	Amount        decimal.Decimal     `json:"amount"`
+	ClientOrderID string              `json:"client_order_id,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+// triggered reports whether price has traded through the stop
+func (s *SyntheticStop) triggered(price decimal.Decimal) bool {
+	if !price.IsPositive() {
+		return false
+	}
+	if s.Side == exchanges.OrderSideSell {
+		return price.LessThanOrEqual(s.StopPrice)
+	}
+	return price.GreaterThanOrEqual(s.StopPrice)
+}
+
+// SyntheticStopStore persists synthetic stops so they survive restarts
+type SyntheticStopStore interface {
+	Load() ([]SyntheticStop, error)
+	Save(stops []SyntheticStop) error
+}
+
+// FileSyntheticStopStore keeps synthetic stops in a JSON file, replaced
+// atomically on every save
+type FileSyntheticStopStore struct {
+	path string
+}
+
+// NewFileSyntheticStopStore creates a store backed by the file at path
+func NewFileSyntheticStopStore(path string) *FileSyntheticStopStore {
+	return &FileSyntheticStopStore{path: path}
+}
+
+// Load returns the stored stops, or none if the file does not exist yet
+func (s *FileSyntheticStopStore) Load() ([]SyntheticStop, error) {
+	data, err := statefile.Read(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read synthetic stops: %w", err)
+	}
+
+	var stops []SyntheticStop
+	if err := json.Unmarshal(data, &stops); err != nil {
+		return nil, fmt.Errorf("failed to parse synthetic stops: %w", err)
+	}
+	return stops, nil
+}
+
+// Save replaces the stored stops
+func (s *FileSyntheticStopStore) Save(stops []SyntheticStop) error {
+	data, err := json.MarshalIndent(stops, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode synthetic stops: %w", err)
+	}
+
+	if err := statefile.Write(s.path, data); err != nil {
+		return fmt.Errorf("failed to save synthetic stops: %w", err)
+	}
+	return nil
+}
+
+// SetSyntheticStopStore persists synthetic stops to store and restores the
+// stops it holds. Restored stops keep watching their positions until they
+// fire or the position is found closed.
+func (m *Manager) SetSyntheticStopStore(store SyntheticStopStore) error {
+	stops, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stopStore = store
+	for i := range stops {
+		stop := stops[i]
+		if _, exists := m.syntheticStops[stop.Key]; !exists {
+			m.syntheticStops[stop.Key] = &stop
+		}
+	}
+	return nil
+}
+
+// SyntheticStops returns the synthetic stops currently armed
+func (m *Manager) SyntheticStops() []SyntheticStop {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.syntheticStopList()
+}
+
+// syntheticStopList returns the armed stops sorted by key. Must be called
+// with the lock held.
+func (m *Manager) syntheticStopList() []SyntheticStop {
+	stops := make([]SyntheticStop, 0, len(m.syntheticStops))
+	for _, stop := range m.syntheticStops {
+		stops = append(stops, *stop)
+	}
+	sort.Slice(stops, func(i, j int) bool { return stops[i].Key < stops[j].Key })
+	return stops
+}
+
+// armSyntheticStop arms stop in place of a native stop loss
+func (m *Manager) armSyntheticStop(stop *SyntheticStop) {
+	m.mu.Lock()
+	m.syntheticStops[stop.Key] = stop
+	m.mu.Unlock()
+	m.saveSyntheticStops()
+}
+
+// disarmSyntheticStop removes the stop of the position stored under key
+func (m *Manager) disarmSyntheticStop(key string) {
+	m.mu.Lock()
+	_, exists := m.syntheticStops[key]
+	delete(m.syntheticStops, key)
+	m.mu.Unlock()
+	if exists {
+		m.saveSyntheticStops()
+	}
+}
+
+// saveSyntheticStops persists the armed stops, reporting failures without
+// disarming anything: the stops still fire while the process runs
+func (m *Manager) saveSyntheticStops() {
+	m.mu.RLock()
+	store := m.stopStore
+	stops := m.syntheticStopList()
+	m.mu.RUnlock()
+
+	if store == nil {
+		return
+	}
+	if err := store.Save(stops); err != nil {
+		m.emitError(ordererrors.New(ordererrors.OperationPlaceStopLoss, "", err))
+	}
+}
+
+// CheckSyntheticStops fires the synthetic stops on symbol that price has
+// traded through. The monitor loop checks every stop against the mark price;
+// callers with a faster price feed can call it on every tick. Stops on a
+// halted symbol wait for trading to resume.
+func (m *Manager) CheckSyntheticStops(ctx context.Context, symbol string, price decimal.Decimal) {
+	if m.halted(symbol) {
+		return
+	}
+	m.mu.RLock()
+	triggered := false
+	for _, stop := range m.syntheticStops {
+		if stop.Symbol == symbol && stop.triggered(price) {
+			triggered = true
+			break
+		}
+	}
+	m.mu.RUnlock()
+	if !triggered {
+		return
+	}
+	// Stops stay armed while another venue does not confirm the price
+	if err := m.ConfirmExitPrice(ctx, symbol, price); err != nil {
+		return
+	}
+
+	m.mu.Lock()
+	var fired []*SyntheticStop
+	for key, stop := range m.syntheticStops {
+		if stop.Symbol == symbol && stop.triggered(price) {
+			fired = append(fired, stop)
+			delete(m.syntheticStops, key)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, stop := range fired {
+		if err := m.fireSyntheticStop(ctx, stop); err != nil {
+			// Re-arm so the next check tries again, unless a new stop was
+			// armed for the position meanwhile
+			m.mu.Lock()
+			if _, exists := m.syntheticStops[stop.Key]; !exists {
+				m.syntheticStops[stop.Key] = stop
+			}
+			m.mu.Unlock()
+			m.emitError(ordererrors.New(ordererrors.OperationPlaceStopLoss, stop.Symbol, err))
+		}
+	}
+	if len(fired) > 0 {
+		m.saveSyntheticStops()
+	}
+}
+
+// checkSyntheticStops checks every armed stop against the mark price of its
+// position, or the streamed mark of its symbol, or the last traded price when
+// neither is known
+func (m *Manager) checkSyntheticStops(ctx context.Context) {
+	m.mu.RLock()
+	prices := make(map[string]decimal.Decimal)
+	for key, stop := range m.syntheticStops {
+		if position, exists := m.orderBook.Positions[key]; exists && position.CurrentPrice.IsPositive() {
+			prices[stop.Symbol] = position.CurrentPrice
+		} else if mark, streamed := m.marks[stop.Symbol]; streamed {
+			prices[stop.Symbol] = mark.Mark
+		} else if _, seen := prices[stop.Symbol]; !seen {
+			prices[stop.Symbol] = decimal.Zero
+		}
+	}
+	m.mu.RUnlock()
+
+	for symbol, price := range prices {
+		if m.halted(symbol) {
+			continue
+		}
+		if !price.IsPositive() {
+			callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
+			ticker, err := m.exchange.GetTicker(callCtx, symbol)
+			cancel()
+			if err != nil || ticker == nil {
+				continue
+			}
+			price = ticker.Last
+		}
+		m.CheckSyntheticStops(ctx, symbol, price)
+	}
+}
+
+// fireSyntheticStop closes the position protected by stop with a reduce-only
+// market order. A stop restored after a restart has no managed position, so
+// the exchange is asked whether the position is still open first.
+func (m *Manager) fireSyntheticStop(ctx context.Context, stop *SyntheticStop) error {
+	m.mu.RLock()
+	position, managed := m.orderBook.Positions[stop.Key]
+	m.mu.RUnlock()
+
+	amount := stop.Amount
+	if managed {
+		amount = position.Amount
+	} else {
+		held, err := m.heldPosition(ctx, stop)
+		if err != nil {
+			return fmt.Errorf("failed to check position: %w", err)
+		}
+		if !held.IsPositive() {
+			return nil
+		}
+		amount = decimal.Min(amount, held)
+	}
+
+	// The exit may fill before it is placed: it is known by its client
+	// order ID until then, and by its order ID once placed
+	clientOrderID := stop.ClientOrderID
+	if clientOrderID == "" {
+		clientOrderID = newClientOrderID()
+	}
+	m.mu.Lock()
+	m.syntheticExits[clientOrderID] = true
+	m.mu.Unlock()
+
+	placed, err := m.PlaceOrder(ctx, &OrderRequest{
+		Symbol:         stop.Symbol,
+		Side:           stop.Side,
+		Type:           exchanges.OrderTypeMarket,
+		Amount:         amount,
+		ReduceOnly:     true,
+		ClientOrderID:  clientOrderID,
+		ReferencePrice: stop.StopPrice,
+	})
+
+	m.mu.Lock()
+	delete(m.syntheticExits, clientOrderID)
+	if err == nil && managed && position.Status == PositionStatusOpen {
+		position.StopLossOrderID = placed.ID
+	}
+	m.mu.Unlock()
+	return err
+}
+
+// heldPosition returns the size of the position on the exchange that stop
+// exits, zero if there is none. Positions are listed rather than looked up
+// by symbol, which not every exchange supports.
+func (m *Manager) heldPosition(ctx context.Context, stop *SyntheticStop) (decimal.Decimal, error) {
+	callCtx, cancel := context.WithTimeout(ctx, defaultAPICallTimeout)
+	defer cancel()
+
+	positions, err := m.exchange.GetPositions(callCtx)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	for _, position := range positions {
+		if position.Symbol == stop.Symbol && position.Side == exitSide(stop.Side) {
+			return position.Size.Abs(), nil
+		}
+	}
+	return decimal.Zero, nil
+}
@@ -0,0 +1,101 @@
+package order
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_SyntheticStopFiresOnMarkPrice(t *testing.T) {
+	exchange := newFlakyExchange(0, false)
+	exchange.fillOnPlace = true
+	exchange.CapabilitiesValue.StopOrders = false
+	manager := NewManager(exchange)
+	store := NewFileSyntheticStopStore(filepath.Join(t.TempDir(), "stops.json"))
+	testutils.AssertNoError(t, manager.SetSyntheticStopStore(store), "store should load")
+	var closed *ManagedPosition
+	manager.SetPositionUpdateCallback(func(position *ManagedPosition) {
+		if position.Status == PositionStatusClosed {
+			closed = position
+		}
+	})
+	ctx := context.Background()
+
+	_, err := manager.PlaceOrder(ctx, &OrderRequest{
+		Symbol:     "BTC-USD",
+		Side:       exchanges.OrderSideBuy,
+		Type:       exchanges.OrderTypeMarket,
+		Amount:     decimal.NewFromFloat(1),
+		StopLoss:   decimal.NewFromFloat(90),
+		TakeProfit: decimal.NewFromFloat(120),
+	})
+	testutils.AssertNoError(t, err, "entry should not fail for lack of native stops")
+
+	open := manager.GetOpenOrders()
+	testutils.AssertEqual(t, 1, len(open), "only the take profit should rest")
+	testutils.AssertEqual(t, exchanges.OrderTypeLimit, open[0].Type, "resting order should be the take profit")
+
+	stops := manager.SyntheticStops()
+	testutils.AssertEqual(t, 1, len(stops), "stop loss should be armed synthetically")
+	testutils.AssertEqual(t, exchanges.OrderSideSell, stops[0].Side, "stop should exit the long")
+	testutils.AssertTrue(t, stops[0].Amount.Equal(decimal.NewFromFloat(1)), "stop should cover the position")
+	stored, err := store.Load()
+	testutils.AssertNoError(t, err, "stored stops should load")
+	testutils.AssertEqual(t, 1, len(stored), "armed stop should be persisted")
+
+	manager.CheckSyntheticStops(ctx, "BTC-USD", decimal.NewFromFloat(95))
+	testutils.AssertEqual(t, 1, len(manager.SyntheticStops()), "stop should not fire above its level")
+
+	manager.CheckSyntheticStops(ctx, "BTC-USD", decimal.NewFromFloat(89))
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD") == nil, "stop should close the position")
+	testutils.AssertTrue(t, closed != nil && closed.StopOut, "position closed by the synthetic stop should be a stop-out")
+	testutils.AssertEqual(t, 0, len(manager.GetOpenOrders()), "take profit should be canceled with the position")
+	testutils.AssertEqual(t, 0, len(manager.SyntheticStops()), "fired stop should be disarmed")
+	stored, _ = store.Load()
+	testutils.AssertEqual(t, 0, len(stored), "fired stop should be removed from the store")
+}
+
+func TestManager_SyntheticStopSurvivesRestart(t *testing.T) {
+	store := NewFileSyntheticStopStore(filepath.Join(t.TempDir(), "stops.json"))
+	err := store.Save([]SyntheticStop{
+		{
+			Key:           "BTC-USD",
+			Symbol:        "BTC-USD",
+			Side:          exchanges.OrderSideSell,
+			StopPrice:     decimal.NewFromFloat(45000),
+			Amount:        decimal.NewFromFloat(1),
+			ClientOrderID: "order-1-sl",
+		},
+		{
+			Key:       "ETH-USD",
+			Symbol:    "ETH-USD",
+			Side:      exchanges.OrderSideSell,
+			StopPrice: decimal.NewFromFloat(2000),
+			Amount:    decimal.NewFromFloat(1),
+		},
+	})
+	testutils.AssertNoError(t, err, "stops should save")
+
+	exchange := newFlakyExchange(0, false)
+	exchange.fillOnPlace = true
+	manager := NewManager(exchange)
+	testutils.AssertNoError(t, manager.SetSyntheticStopStore(store), "store should load")
+	testutils.AssertEqual(t, 2, len(manager.SyntheticStops()), "stops should be restored")
+
+	// The exchange still holds 0.5 BTC, and no ETH position
+	ctx := context.Background()
+	manager.CheckSyntheticStops(ctx, "BTC-USD", decimal.NewFromFloat(44000))
+	manager.CheckSyntheticStops(ctx, "ETH-USD", decimal.NewFromFloat(1900))
+
+	testutils.AssertEqual(t, 1, exchange.placeCalls, "only the held position should be closed")
+	exit, ok := exchange.placed["order-1-sl"]
+	testutils.AssertTrue(t, ok, "exit should reuse the stop's client order ID")
+	testutils.AssertEqual(t, exchanges.OrderTypeMarket, exit.Type, "exit should be a market order")
+	testutils.AssertTrue(t, exit.Amount.Equal(decimal.NewFromFloat(0.5)), "exit should not exceed the held position")
+	testutils.AssertTrue(t, manager.GetPosition("BTC-USD") == nil, "reduce-only exit should not open a position")
+	testutils.AssertEqual(t, 0, len(manager.SyntheticStops()), "both stops should be disarmed")
+}
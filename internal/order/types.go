@@ -4,20 +4,45 @@ import (
 	"time"
 
 	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/ringbuf"
 	"github.com/shopspring/decimal"
 )
 
 // OrderRequest represents a request to place an order
 type OrderRequest struct {
-	Symbol      string
-	Side        exchanges.OrderSide
-	Type        exchanges.OrderType
-	Price       decimal.Decimal
-	Amount      decimal.Decimal
-	StopLoss    decimal.Decimal
-	TakeProfit  decimal.Decimal
-	TimeInForce string
-	ReduceOnly  bool
+	Symbol     string
+	Side       exchanges.OrderSide
+	Type       exchanges.OrderType
+	Price      decimal.Decimal
+	Amount     decimal.Decimal
+	StopLoss   decimal.Decimal
+	TakeProfit decimal.Decimal
+	// TakeProfitLadder replaces TakeProfit with partial exits, each taking
+	// its fraction of the position; what the levels leave rides as a runner.
+	// The stop loss moves to the entry price once a level fills, then trails
+	// the best price by TrailingStop, a fraction of the price (0 disables).
+	TakeProfitLadder []TakeProfitLevel
+	TrailingStop     decimal.Decimal
+	TimeInForce      exchanges.TimeInForce
+	PostOnly         bool      // Only add liquidity; rejected if it would cross the book
+	ExpiresAt        time.Time // Expiry of good till date orders
+	ReduceOnly       bool
+	// ClientOrderID makes the request idempotent; generated when empty
+	ClientOrderID string
+	// Strategy is the strategy instance placing the order, carried over to
+	// the position it opens for PnL attribution
+	Strategy string
+	// ReferencePrice is the signal or mark price the order is placed on,
+	// against which its slippage is measured
+	ReferencePrice decimal.Decimal
+	// Options are venue-specific options, like a native TWAP or a builder fee
+	Options *exchanges.OrderOptions
+}
+
+// TakeProfitLevel is a level of a take profit ladder
+type TakeProfitLevel struct {
+	Price    decimal.Decimal
+	Fraction decimal.Decimal // Of the position, e.g. 0.5 for half
 }
 
 // OrderUpdate represents an order status update
@@ -49,6 +74,9 @@ type OrderStats struct {
 	TotalFees       decimal.Decimal
 	AverageFillTime time.Duration
 	SuccessRate     float64
+	// Entries swept for resting longer than their max age
+	StaleCanceled int
+	StaleRepriced int
 }
 
 // PositionSide represents the side of a position
@@ -74,12 +102,14 @@ type ManagedPosition struct {
 	Side              PositionSide
 	EntryPrice        decimal.Decimal
 	CurrentPrice      decimal.Decimal
+	LiquidationPrice  decimal.Decimal // Zero when the exchange does not report one
 	Amount            decimal.Decimal
 	Leverage          decimal.Decimal
 	StopLoss          decimal.Decimal
 	TakeProfit        decimal.Decimal
 	UnrealizedPnL     decimal.Decimal
-	RealizedPnL       decimal.Decimal
+	RealizedPnL       decimal.Decimal // Net of Fees
+	Fees              decimal.Decimal // Paid on the fills reported by the exchange
 	EntryTime         time.Time
 	ExitTime          *time.Time
 	Status            PositionStatus
@@ -87,12 +117,19 @@ type ManagedPosition struct {
 	ExitOrderID       string
 	StopLossOrderID   string
 	TakeProfitOrderID string
+	Strategy          string // Strategy instance that opened the position
+	// StopOut is set once the stop loss, native or synthetic, closed the
+	// position
+	StopOut bool
+	// Liquidation is set once the venue liquidated or auto-deleveraged the
+	// position
+	Liquidation exchanges.LiquidationKind
 }
 
 // OrderBook represents the current state of orders
 type OrderBook struct {
 	OpenOrders    map[string]*exchanges.Order
-	FilledOrders  []*exchanges.Order
+	FilledOrders  *ringbuf.Buffer[*exchanges.Order] // Latest MaxFilledOrdersHistory finished orders
 	Positions     map[string]*ManagedPosition
 	PendingOrders map[string]*OrderRequest
 }
@@ -101,7 +138,7 @@ type OrderBook struct {
 func NewOrderBook() *OrderBook {
 	return &OrderBook{
 		OpenOrders:    make(map[string]*exchanges.Order),
-		FilledOrders:  make([]*exchanges.Order, 0),
+		FilledOrders:  ringbuf.New[*exchanges.Order](MaxFilledOrdersHistory),
 		Positions:     make(map[string]*ManagedPosition),
 		PendingOrders: make(map[string]*OrderRequest),
 	}
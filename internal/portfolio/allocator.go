@@ -0,0 +1,218 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/shopspring/decimal"
+)
+
+// AllocationMode decides how the balance is split across strategies
+type AllocationMode string
+
+const (
+	// AllocationFixed splits the balance by the configured weights
+	AllocationFixed AllocationMode = "fixed"
+	// AllocationPerformance scales the configured weights by the return of
+	// each strategy since the previous rebalance
+	AllocationPerformance AllocationMode = "performance"
+)
+
+// Performance allocation moves a strategy's weight by at most these factors
+// per rebalance so one lucky or unlucky period cannot starve a strategy
+const (
+	minPerformanceFactor = 0.5
+	maxPerformanceFactor = 1.5
+)
+
+// StrategyAllocation is the capital and performance of one strategy
+type StrategyAllocation struct {
+	Strategy string
+	Weight   float64         // Configured weight
+	Share    float64         // Current fraction of the balance
+	Capital  decimal.Decimal // Capital allocated at the last rebalance
+	PnL      decimal.Decimal // Realized PnL since start
+	// PeriodPnL is the realized PnL since the last rebalance
+	PeriodPnL decimal.Decimal
+	Trades    int
+}
+
+// Allocator splits the account balance across strategy instances sharing
+// it, attributes realized PnL to the strategy that opened each position and
+// rebalances the allocations periodically
+type Allocator struct {
+	mu         sync.RWMutex
+	mode       AllocationMode
+	strategies map[string]*StrategyAllocation
+	owners     map[string]string // Strategy holding the position of each symbol
+}
+
+// NewAllocator creates an allocator for strategies with the given weights
+func NewAllocator(mode AllocationMode, weights map[string]float64) (*Allocator, error) {
+	switch mode {
+	case "":
+		mode = AllocationFixed
+	case AllocationFixed, AllocationPerformance:
+	default:
+		return nil, fmt.Errorf("invalid allocation mode %q: expected fixed or performance", mode)
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("allocator needs at least one strategy")
+	}
+
+	total := 0.0
+	strategies := make(map[string]*StrategyAllocation, len(weights))
+	for name, weight := range weights {
+		if weight < 0 {
+			return nil, fmt.Errorf("strategy %q: weight must not be negative", name)
+		}
+		total += weight
+		strategies[name] = &StrategyAllocation{Strategy: name, Weight: weight}
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("strategy weights must not all be zero")
+	}
+	for _, s := range strategies {
+		s.Share = s.Weight / total
+	}
+
+	return &Allocator{
+		mode:       mode,
+		strategies: strategies,
+		owners:     make(map[string]string),
+	}, nil
+}
+
+// Rebalance splits balance across the strategies. In performance mode the
+// shares move toward the strategies that made money since the last
+// rebalance.
+func (a *Allocator) Rebalance(balance decimal.Decimal) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	scores := make(map[string]float64, len(a.strategies))
+	total := 0.0
+	for name, s := range a.strategies {
+		score := s.Weight
+		if a.mode == AllocationPerformance {
+			score = s.Share
+			if s.Capital.IsPositive() {
+				ret := s.PeriodPnL.Div(s.Capital).InexactFloat64()
+				score *= math.Min(math.Max(1+ret, minPerformanceFactor), maxPerformanceFactor)
+			}
+		}
+		scores[name] = score
+		total += score
+	}
+
+	for name, s := range a.strategies {
+		if total > 0 {
+			s.Share = scores[name] / total
+		}
+		s.Capital = balance.Mul(decimal.NewFromFloat(s.Share))
+		s.PeriodPnL = decimal.Zero
+	}
+
+	logger.Component("portfolio").Info("capital rebalanced",
+		"mode", a.mode,
+		"balance", balance.StringFixed(2),
+		"allocations", a.summaryLocked())
+}
+
+// Capital returns the capital strategy may size positions from: its
+// allocation plus what it realized since the last rebalance. Unknown
+// strategies have no capital.
+func (a *Allocator) Capital(strategy string) decimal.Decimal {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	s, ok := a.strategies[strategy]
+	if !ok {
+		return decimal.Zero
+	}
+	return decimal.Max(s.Capital.Add(s.PeriodPnL), decimal.Zero)
+}
+
+// Owner returns the strategy holding the position on symbol
+func (a *Allocator) Owner(symbol string) (string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	owner, ok := a.owners[symbol]
+	return owner, ok
+}
+
+// AssignPosition attributes the position on symbol to strategy
+func (a *Allocator) AssignPosition(symbol, strategy string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.owners[symbol] = strategy
+}
+
+// RecordTrade attributes the realized PnL of a closed position on symbol to
+// the strategy that opened it and releases the symbol. Trades on symbols no
+// strategy holds are ignored.
+func (a *Allocator) RecordTrade(symbol string, pnl decimal.Decimal) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	owner, ok := a.owners[symbol]
+	if !ok {
+		return
+	}
+	delete(a.owners, symbol)
+
+	s, ok := a.strategies[owner]
+	if !ok {
+		return
+	}
+	s.PnL = s.PnL.Add(pnl)
+	s.PeriodPnL = s.PeriodPnL.Add(pnl)
+	s.Trades++
+}
+
+// Allocations returns the allocation of every strategy sorted by name
+func (a *Allocator) Allocations() []StrategyAllocation {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.allocationsLocked()
+}
+
+func (a *Allocator) allocationsLocked() []StrategyAllocation {
+	allocations := make([]StrategyAllocation, 0, len(a.strategies))
+	for _, s := range a.strategies {
+		allocations = append(allocations, *s)
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		return allocations[i].Strategy < allocations[j].Strategy
+	})
+	return allocations
+}
+
+func (a *Allocator) summaryLocked() map[string]string {
+	summary := make(map[string]string, len(a.strategies))
+	for _, s := range a.allocationsLocked() {
+		summary[s.Strategy] = fmt.Sprintf("%.1f%% (%s)", s.Share*100, s.Capital.StringFixed(2))
+	}
+	return summary
+}
+
+// Run rebalances the allocations from balance every interval until ctx is
+// canceled
+func (a *Allocator) Run(ctx context.Context, interval time.Duration, balance func() decimal.Decimal) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.Rebalance(balance())
+		}
+	}
+}
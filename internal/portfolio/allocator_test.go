@@ -0,0 +1,98 @@
+package portfolio
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestNewAllocator_Invalid(t *testing.T) {
+	if _, err := NewAllocator("equal", map[string]float64{"main": 1}); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+	if _, err := NewAllocator(AllocationFixed, nil); err == nil {
+		t.Error("expected an error without strategies")
+	}
+	if _, err := NewAllocator(AllocationFixed, map[string]float64{"main": 0}); err == nil {
+		t.Error("expected an error when every weight is zero")
+	}
+}
+
+func TestAllocator_FixedSplitsByWeight(t *testing.T) {
+	a, err := NewAllocator(AllocationFixed, map[string]float64{"main": 3, "fast": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Rebalance(decimal.NewFromInt(1000))
+
+	if got := a.Capital("main"); !got.Equal(decimal.NewFromInt(750)) {
+		t.Errorf("main capital = %s, want 750", got)
+	}
+	if got := a.Capital("fast"); !got.Equal(decimal.NewFromInt(250)) {
+		t.Errorf("fast capital = %s, want 250", got)
+	}
+	if got := a.Capital("unknown"); !got.IsZero() {
+		t.Errorf("unknown strategies should have no capital, got %s", got)
+	}
+}
+
+func TestAllocator_AttributesTrades(t *testing.T) {
+	a, err := NewAllocator(AllocationFixed, map[string]float64{"main": 1, "fast": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Rebalance(decimal.NewFromInt(1000))
+
+	a.AssignPosition("BTC-USD", "fast")
+	a.RecordTrade("BTC-USD", decimal.NewFromInt(50))
+	// No strategy holds ETH-USD
+	a.RecordTrade("ETH-USD", decimal.NewFromInt(20))
+
+	if _, held := a.Owner("BTC-USD"); held {
+		t.Error("closing the position should release the symbol")
+	}
+	if got := a.Capital("fast"); !got.Equal(decimal.NewFromInt(550)) {
+		t.Errorf("fast capital = %s, want 550 including realized PnL", got)
+	}
+
+	allocations := a.Allocations()
+	if len(allocations) != 2 || allocations[0].Strategy != "fast" {
+		t.Fatalf("allocations should be sorted by strategy: %+v", allocations)
+	}
+	if allocations[0].Trades != 1 || !allocations[0].PnL.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("unexpected fast allocation: %+v", allocations[0])
+	}
+	if allocations[1].Trades != 0 || !allocations[1].PnL.IsZero() {
+		t.Errorf("unexpected main allocation: %+v", allocations[1])
+	}
+}
+
+func TestAllocator_PerformanceRebalance(t *testing.T) {
+	a, err := NewAllocator(AllocationPerformance, map[string]float64{"main": 1, "fast": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Rebalance(decimal.NewFromInt(1000))
+
+	// fast makes 20% on its capital, main loses 20%
+	a.AssignPosition("BTC-USD", "fast")
+	a.RecordTrade("BTC-USD", decimal.NewFromInt(100))
+	a.AssignPosition("ETH-USD", "main")
+	a.RecordTrade("ETH-USD", decimal.NewFromInt(-100))
+
+	a.Rebalance(decimal.NewFromInt(1000))
+
+	// 0.5*1.2 and 0.5*0.8 normalize to 60% and 40%
+	if got := a.Capital("fast"); !got.Equal(decimal.NewFromInt(600)) {
+		t.Errorf("fast capital = %s, want 600", got)
+	}
+	if got := a.Capital("main"); !got.Equal(decimal.NewFromInt(400)) {
+		t.Errorf("main capital = %s, want 400", got)
+	}
+
+	// A rebalance without trades keeps the shares
+	a.Rebalance(decimal.NewFromInt(2000))
+	if got := a.Capital("fast"); !got.Equal(decimal.NewFromInt(1200)) {
+		t.Errorf("fast capital = %s, want 1200", got)
+	}
+}
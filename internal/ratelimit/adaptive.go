@@ -0,0 +1,194 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRetryAfter is the pause after a 429 without a Retry-After header
+	defaultRetryAfter = time.Second
+	// throttleFactor shrinks the rate on every 429
+	throttleFactor = 0.5
+	// recoverySteps is the number of successful responses over which the
+	// rate grows back from its minimum to its maximum
+	recoverySteps = 20
+	// minRateFraction bounds how far the rate can shrink
+	minRateFraction = 0.05
+	// lowQuotaFraction is the share of the quota left at which the rate is
+	// paced to spread the remaining requests until the window resets
+	lowQuotaFraction = 0.2
+)
+
+// Feedback is the rate limiting information of an exchange response
+type Feedback struct {
+	Throttled  bool          // The request was rejected with 429 Too Many Requests
+	RetryAfter time.Duration // How long the exchange asked to wait, 0 if it did not
+	Remaining  int           // Requests left in the current window, -1 if unknown
+	Limit      int           // Requests allowed per window, -1 if unknown
+	Reset      time.Duration // Time until the window resets, 0 if unknown
+}
+
+// ParseFeedback reads the status and the rate limit headers of resp. It
+// understands Retry-After and the X-RateLimit-* and RateLimit-* headers.
+func ParseFeedback(resp *http.Response) Feedback {
+	return parseFeedback(resp.StatusCode, resp.Header, time.Now())
+}
+
+func parseFeedback(status int, header http.Header, now time.Time) Feedback {
+	feedback := Feedback{
+		Throttled: status == http.StatusTooManyRequests,
+		Remaining: headerInt(header, "X-RateLimit-Remaining", "RateLimit-Remaining"),
+		Limit:     headerInt(header, "X-RateLimit-Limit", "RateLimit-Limit"),
+	}
+
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+			feedback.RetryAfter = time.Duration(seconds * float64(time.Second))
+		} else if at, err := http.ParseTime(value); err == nil && at.After(now) {
+			feedback.RetryAfter = at.Sub(now)
+		}
+	}
+
+	if value := headerValue(header, "X-RateLimit-Reset", "RateLimit-Reset"); value != "" {
+		if reset, err := strconv.ParseFloat(value, 64); err == nil && reset > 0 {
+			switch {
+			case reset > 1e12: // Unix milliseconds
+				feedback.Reset = time.UnixMilli(int64(reset)).Sub(now)
+			case reset > 1e9: // Unix seconds
+				feedback.Reset = time.Unix(int64(reset), 0).Sub(now)
+			default: // Seconds until the reset
+				feedback.Reset = time.Duration(reset * float64(time.Second))
+			}
+			feedback.Reset = max(feedback.Reset, 0)
+		}
+	}
+	return feedback
+}
+
+func headerValue(header http.Header, names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(header.Get(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func headerInt(header http.Header, names ...string) int {
+	value, err := strconv.Atoi(headerValue(header, names...))
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+// AdaptiveLimiter is a token bucket whose rate follows the exchange's
+// responses: it backs off on 429s, honours Retry-After, paces the requests
+// left when the quota runs low and grows back to its configured rate while
+// requests succeed
+type AdaptiveLimiter struct {
+	bucket  *TokenBucket
+	maxRate float64
+	minRate float64
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+	remaining   int
+}
+
+// NewAdaptiveLimiter creates an adaptive limiter allowing at most rate
+// requests per second with the given burst
+func NewAdaptiveLimiter(rate float64, burst int) *AdaptiveLimiter {
+	return &AdaptiveLimiter{
+		bucket:    NewTokenBucket(rate, burst),
+		maxRate:   rate,
+		minRate:   rate * minRateFraction,
+		remaining: -1,
+	}
+}
+
+// Wait blocks until the exchange's pause is over and a token is available
+func (a *AdaptiveLimiter) Wait(ctx context.Context) error {
+	if pause := time.Until(a.resumeAt()); pause > 0 {
+		timer := time.NewTimer(pause)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return a.bucket.Wait(ctx)
+}
+
+// Allow returns true if a request can be sent immediately
+func (a *AdaptiveLimiter) Allow() bool {
+	if time.Now().Before(a.resumeAt()) {
+		return false
+	}
+	return a.bucket.Allow()
+}
+
+// Reserve reserves a token and returns the time to wait for it
+func (a *AdaptiveLimiter) Reserve() time.Duration {
+	return max(a.bucket.Reserve(), time.Until(a.resumeAt()))
+}
+
+func (a *AdaptiveLimiter) resumeAt() time.Time {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.pausedUntil
+}
+
+// Rate returns the current allowance in requests per second
+func (a *AdaptiveLimiter) Rate() float64 {
+	return a.bucket.Rate()
+}
+
+// Remaining returns the requests left in the exchange's window as last
+// reported, or -1 if the exchange does not report it
+func (a *AdaptiveLimiter) Remaining() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.remaining
+}
+
+// Observe adjusts the rate to the feedback of a response
+func (a *AdaptiveLimiter) Observe(feedback Feedback) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.remaining = feedback.Remaining
+	rate := a.bucket.Rate()
+
+	switch {
+	case feedback.Throttled:
+		pause := feedback.RetryAfter
+		if pause <= 0 {
+			pause = defaultRetryAfter
+		}
+		a.pausedUntil = time.Now().Add(pause)
+		rate *= throttleFactor
+	case feedback.Remaining >= 0 && feedback.Limit > 0 && feedback.Reset > 0 &&
+		float64(feedback.Remaining) < float64(feedback.Limit)*lowQuotaFraction:
+		// Spread what is left of the quota over the rest of the window
+		rate = float64(feedback.Remaining) / feedback.Reset.Seconds()
+	default:
+		rate += a.maxRate / recoverySteps
+	}
+
+	a.bucket.SetRate(math.Min(math.Max(rate, a.minRate), a.maxRate))
+}
+
+// ObserveResponse adjusts the rate to resp and returns its feedback
+func (a *AdaptiveLimiter) ObserveResponse(resp *http.Response) Feedback {
+	feedback := ParseFeedback(resp)
+	a.Observe(feedback)
+	return feedback
+}
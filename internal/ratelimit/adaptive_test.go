@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseFeedback(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// 429 with Retry-After in seconds
+	header := http.Header{}
+	header.Set("Retry-After", "3")
+	feedback := parseFeedback(http.StatusTooManyRequests, header, now)
+	if !feedback.Throttled {
+		t.Error("429 should be throttled")
+	}
+	if feedback.RetryAfter != 3*time.Second {
+		t.Errorf("Expected Retry-After of 3s, got %v", feedback.RetryAfter)
+	}
+	if feedback.Remaining != -1 || feedback.Limit != -1 {
+		t.Errorf("Expected unknown quota, got remaining=%d limit=%d", feedback.Remaining, feedback.Limit)
+	}
+
+	// Retry-After as an HTTP date
+	header = http.Header{}
+	header.Set("Retry-After", now.Add(5*time.Second).Format(http.TimeFormat))
+	feedback = parseFeedback(http.StatusTooManyRequests, header, now)
+	if feedback.RetryAfter != 5*time.Second {
+		t.Errorf("Expected Retry-After of 5s, got %v", feedback.RetryAfter)
+	}
+
+	// Quota headers with the reset as seconds, Unix seconds and Unix milliseconds
+	for _, reset := range []string{"10", "1704110410", "1704110410000"} {
+		header = http.Header{}
+		header.Set("X-RateLimit-Remaining", "7")
+		header.Set("X-RateLimit-Limit", "100")
+		header.Set("X-RateLimit-Reset", reset)
+		feedback = parseFeedback(http.StatusOK, header, now)
+		if feedback.Throttled {
+			t.Error("200 should not be throttled")
+		}
+		if feedback.Remaining != 7 || feedback.Limit != 100 {
+			t.Errorf("Expected remaining=7 limit=100, got remaining=%d limit=%d", feedback.Remaining, feedback.Limit)
+		}
+		if feedback.Reset != 10*time.Second {
+			t.Errorf("Reset %s: expected 10s, got %v", reset, feedback.Reset)
+		}
+	}
+
+	// IETF RateLimit-* headers
+	header = http.Header{}
+	header.Set("RateLimit-Remaining", "0")
+	feedback = parseFeedback(http.StatusOK, header, now)
+	if feedback.Remaining != 0 {
+		t.Errorf("Expected remaining=0, got %d", feedback.Remaining)
+	}
+}
+
+func TestAdaptiveLimiter_Observe(t *testing.T) {
+	limiter := NewAdaptiveLimiter(10, 10)
+
+	limiter.Observe(Feedback{Throttled: true, RetryAfter: time.Millisecond, Remaining: -1, Limit: -1})
+	if rate := limiter.Rate(); rate != 5 {
+		t.Errorf("Expected rate to halve to 5 after a 429, got %v", rate)
+	}
+
+	// Repeated 429s never shrink the rate below its floor
+	for i := 0; i < 20; i++ {
+		limiter.Observe(Feedback{Throttled: true, RetryAfter: time.Millisecond, Remaining: -1, Limit: -1})
+	}
+	if rate := limiter.Rate(); rate != 10*minRateFraction {
+		t.Errorf("Expected rate to be floored at %v, got %v", 10*minRateFraction, rate)
+	}
+
+	// Successful responses grow the rate back to, but not beyond, its maximum
+	for i := 0; i < recoverySteps+5; i++ {
+		limiter.Observe(Feedback{Remaining: -1, Limit: -1})
+	}
+	if rate := limiter.Rate(); rate != 10 {
+		t.Errorf("Expected rate to recover to 10, got %v", rate)
+	}
+
+	// A low quota spreads the requests left until the window resets
+	limiter.Observe(Feedback{Remaining: 4, Limit: 100, Reset: 2 * time.Second})
+	if rate := limiter.Rate(); rate != 2 {
+		t.Errorf("Expected rate of 2 for 4 requests over 2s, got %v", rate)
+	}
+	if remaining := limiter.Remaining(); remaining != 4 {
+		t.Errorf("Expected 4 requests remaining, got %d", remaining)
+	}
+}
+
+func TestAdaptiveLimiter_WaitHonoursRetryAfter(t *testing.T) {
+	limiter := NewAdaptiveLimiter(100, 10)
+	limiter.Observe(Feedback{Throttled: true, RetryAfter: 100 * time.Millisecond, Remaining: -1, Limit: -1})
+
+	if limiter.Allow() {
+		t.Error("Request should be denied while the exchange asked to wait")
+	}
+
+	start := time.Now()
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Wait should honour Retry-After, returned after %v", elapsed)
+	}
+
+	limiter.Observe(Feedback{Throttled: true, RetryAfter: time.Second, Remaining: -1, Limit: -1})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); err == nil {
+		t.Error("Wait should fail when the context ends during the pause")
+	}
+}
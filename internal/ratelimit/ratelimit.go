@@ -121,6 +121,21 @@ func (tb *TokenBucket) Reserve() time.Duration {
 	return waitDuration
 }
 
+// Rate returns the current rate in tokens per second
+func (tb *TokenBucket) Rate() float64 {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	return tb.rate
+}
+
+// SetRate changes the rate, keeping the tokens accumulated at the old one
+func (tb *TokenBucket) SetRate(rate float64) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	tb.refill()
+	tb.rate = rate
+}
+
 // MultiLimiter manages multiple rate limiters for different endpoints
 type MultiLimiter struct {
 	limiters map[string]Limiter
@@ -0,0 +1,541 @@
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// maxReplayedCandles bounds the candle history kept per symbol for GetCandles
+const maxReplayedCandles = 1000
+
+// Config holds replay driver configuration
+type Config struct {
+	// Speed scales the time between recorded events; 1 replays in real time,
+	// 10 replays ten times faster and 0 replays as fast as possible
+	Speed float64
+	// StartDelay gives consumers time to subscribe before the first event
+	StartDelay time.Duration
+	// InitialBalance is the quote balance reported by GetBalance
+	InitialBalance decimal.Decimal
+	// Name is the exchange name reported by the player
+	Name string
+}
+
+// DefaultConfig returns the default replay configuration
+func DefaultConfig() Config {
+	return Config{
+		Speed:          1,
+		StartDelay:     2 * time.Second,
+		InitialBalance: decimal.NewFromInt(10000),
+		Name:           "replay",
+	}
+}
+
+type candleSubscription struct {
+	interval string
+	callback func(*exchanges.Candle)
+}
+
+// Player implements exchanges.Exchange by replaying recorded market data.
+// Events are dispatched synchronously and in file order, so a given recording
+// always produces the same sequence of callbacks.
+type Player struct {
+	config Config
+	events []Event
+
+	mu         sync.RWMutex
+	connected  bool
+	tickerSubs map[string][]func(*exchanges.Ticker)
+	bookSubs   map[string][]func(*exchanges.OrderBook)
+	tradeSubs  map[string][]func(*exchanges.Trade)
+	candleSubs map[string][]candleSubscription
+	tickers    map[string]*exchanges.Ticker
+	books      map[string]*exchanges.OrderBook
+	candles    map[string][]exchanges.Candle
+	orders     map[string]*exchanges.Order
+	leverage   map[string]exchanges.LeverageSettings
+	replayed   int
+	done       chan struct{}
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// NewPlayer creates a player for the given events
+func NewPlayer(events []Event, config Config) *Player {
+	if config.Name == "" {
+		config.Name = "replay"
+	}
+	return &Player{
+		config:     config,
+		events:     events,
+		tickerSubs: make(map[string][]func(*exchanges.Ticker)),
+		bookSubs:   make(map[string][]func(*exchanges.OrderBook)),
+		tradeSubs:  make(map[string][]func(*exchanges.Trade)),
+		candleSubs: make(map[string][]candleSubscription),
+		tickers:    make(map[string]*exchanges.Ticker),
+		books:      make(map[string]*exchanges.OrderBook),
+		candles:    make(map[string][]exchanges.Candle),
+		orders:     make(map[string]*exchanges.Order),
+		leverage:   make(map[string]exchanges.LeverageSettings),
+		done:       make(chan struct{}),
+		sleep:      sleepContext,
+	}
+}
+
+// LoadEvents reads JSON lines events from r
+func LoadEvents(r io.Reader) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(text), &event); err != nil {
+			return nil, fmt.Errorf("invalid event at line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+
+	// Concurrent subscriptions may interleave slightly out of order on disk
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events, nil
+}
+
+// LoadFile reads a recording from path
+func LoadFile(path string) ([]Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+	return LoadEvents(file)
+}
+
+// Run dispatches all recorded events to subscribers, honouring the configured
+// speed. It returns when the recording is exhausted or ctx is canceled.
+func (p *Player) Run(ctx context.Context) error {
+	defer p.markDone()
+
+	if p.config.StartDelay > 0 {
+		if err := p.sleep(ctx, p.config.StartDelay); err != nil {
+			return err
+		}
+	}
+
+	var previous time.Time
+	for i := range p.events {
+		event := &p.events[i]
+		if p.config.Speed > 0 && !previous.IsZero() && event.Time.After(previous) {
+			wait := time.Duration(float64(event.Time.Sub(previous)) / p.config.Speed)
+			if err := p.sleep(ctx, wait); err != nil {
+				return err
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		previous = event.Time
+		p.dispatch(event)
+	}
+	return nil
+}
+
+// Done returns a channel closed once Run has finished
+func (p *Player) Done() <-chan struct{} {
+	return p.done
+}
+
+// Replayed returns the number of events dispatched so far
+func (p *Player) Replayed() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.replayed
+}
+
+// Publish dispatches event to subscribers right away, for drivers that
+// generate market data as they go instead of replaying a recording
+func (p *Player) Publish(event Event) {
+	p.dispatch(&event)
+}
+
+func (p *Player) markDone() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	select {
+	case <-p.done:
+	default:
+		close(p.done)
+	}
+}
+
+func (p *Player) dispatch(event *Event) {
+	p.mu.Lock()
+	p.replayed++
+	var (
+		tickerCallbacks []func(*exchanges.Ticker)
+		bookCallbacks   []func(*exchanges.OrderBook)
+		tradeCallbacks  []func(*exchanges.Trade)
+		candleCallbacks []func(*exchanges.Candle)
+	)
+	switch event.Kind {
+	case EventTicker:
+		if event.Ticker != nil {
+			p.tickers[event.Symbol] = event.Ticker
+			tickerCallbacks = append(tickerCallbacks, p.tickerSubs[event.Symbol]...)
+		}
+	case EventOrderBook:
+		if event.OrderBook != nil {
+			p.books[event.Symbol] = event.OrderBook
+			bookCallbacks = append(bookCallbacks, p.bookSubs[event.Symbol]...)
+		}
+	case EventTrade:
+		if event.Trade != nil {
+			tradeCallbacks = append(tradeCallbacks, p.tradeSubs[event.Symbol]...)
+		}
+	case EventCandle:
+		if event.Candle != nil {
+			p.appendCandle(event.Symbol, *event.Candle)
+			for _, sub := range p.candleSubs[event.Symbol] {
+				if sub.interval == "" || event.Interval == "" || sub.interval == event.Interval {
+					candleCallbacks = append(candleCallbacks, sub.callback)
+				}
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	for _, cb := range tickerCallbacks {
+		ticker := *event.Ticker
+		cb(&ticker)
+	}
+	for _, cb := range bookCallbacks {
+		book := *event.OrderBook
+		cb(&book)
+	}
+	for _, cb := range tradeCallbacks {
+		trade := *event.Trade
+		cb(&trade)
+	}
+	for _, cb := range candleCallbacks {
+		candle := *event.Candle
+		cb(&candle)
+	}
+}
+
+func (p *Player) appendCandle(symbol string, candle exchanges.Candle) {
+	history := p.candles[symbol]
+	if n := len(history); n > 0 && history[n-1].Timestamp.Equal(candle.Timestamp) {
+		history[n-1] = candle
+	} else {
+		history = append(history, candle)
+	}
+	if len(history) > maxReplayedCandles {
+		history = history[len(history)-maxReplayedCandles:]
+	}
+	p.candles[symbol] = history
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Connect marks the player as connected
+func (p *Player) Connect(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.connected = true
+	return nil
+}
+
+// Disconnect marks the player as disconnected
+func (p *Player) Disconnect() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.connected = false
+	return nil
+}
+
+// IsConnected returns whether the player is connected
+func (p *Player) IsConnected() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.connected
+}
+
+// GetTicker returns the last replayed ticker for symbol
+func (p *Player) GetTicker(ctx context.Context, symbol string) (*exchanges.Ticker, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	ticker, ok := p.tickers[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no replayed ticker for %s", symbol)
+	}
+	copied := *ticker
+	return &copied, nil
+}
+
+// GetOrderBook returns the last replayed order book for symbol
+func (p *Player) GetOrderBook(ctx context.Context, symbol string, depth int) (*exchanges.OrderBook, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	book, ok := p.books[symbol]
+	if !ok {
+		return nil, fmt.Errorf("no replayed order book for %s", symbol)
+	}
+	copied := *book
+	if depth > 0 && len(copied.Bids) > depth {
+		copied.Bids = copied.Bids[:depth]
+	}
+	if depth > 0 && len(copied.Asks) > depth {
+		copied.Asks = copied.Asks[:depth]
+	}
+	return &copied, nil
+}
+
+// GetCandles returns the candles replayed so far for symbol
+func (p *Player) GetCandles(ctx context.Context, symbol string, interval string, limit int) ([]exchanges.Candle, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	history := p.candles[symbol]
+	if limit > 0 && len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	result := make([]exchanges.Candle, len(history))
+	copy(result, history)
+	return result, nil
+}
+
+// SubscribeTicker registers a ticker callback for symbol
+func (p *Player) SubscribeTicker(ctx context.Context, symbol string, callback func(*exchanges.Ticker)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tickerSubs[symbol] = append(p.tickerSubs[symbol], callback)
+	return nil
+}
+
+// SubscribeOrderBook registers an order book callback for symbol
+func (p *Player) SubscribeOrderBook(ctx context.Context, symbol string, callback func(*exchanges.OrderBook)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bookSubs[symbol] = append(p.bookSubs[symbol], callback)
+	return nil
+}
+
+// SubscribeTrades registers a trade callback for symbol
+func (p *Player) SubscribeTrades(ctx context.Context, symbol string, callback func(*exchanges.Trade)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.tradeSubs[symbol] = append(p.tradeSubs[symbol], callback)
+	return nil
+}
+
+// SubscribeCandles registers a candle callback for symbol and interval
+func (p *Player) SubscribeCandles(ctx context.Context, symbol string, interval string, callback func(*exchanges.Candle)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.candleSubs[symbol] = append(p.candleSubs[symbol], candleSubscription{interval: interval, callback: callback})
+	return nil
+}
+
+// PlaceOrder accepts an order without matching it; replay is for reproducing
+// the bot's decisions, not for simulating fills
+func (p *Player) PlaceOrder(ctx context.Context, order *exchanges.Order) (*exchanges.Order, error) {
+	if order == nil {
+		return nil, exchanges.ErrInvalidOrder
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	placed := *order
+	placed.ID = uuid.NewString()
+	placed.Status = exchanges.OrderStatusOpen
+	placed.Remaining = placed.Amount
+	placed.CreatedAt = time.Now()
+	placed.UpdatedAt = placed.CreatedAt
+	p.orders[placed.ID] = &placed
+
+	result := placed
+	return &result, nil
+}
+
+// CancelOrder cancels a previously placed order
+func (p *Player) CancelOrder(ctx context.Context, orderID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	order, ok := p.orders[orderID]
+	if !ok {
+		return exchanges.ErrOrderNotFound
+	}
+	order.Status = exchanges.OrderStatusCanceled
+	order.UpdatedAt = time.Now()
+	return nil
+}
+
+// GetOrder returns a previously placed order
+func (p *Player) GetOrder(ctx context.Context, orderID string) (*exchanges.Order, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	order, ok := p.orders[orderID]
+	if !ok {
+		return nil, exchanges.ErrOrderNotFound
+	}
+	copied := *order
+	return &copied, nil
+}
+
+// GetOpenOrders returns open orders, optionally filtered by symbol
+func (p *Player) GetOpenOrders(ctx context.Context, symbol string) ([]exchanges.Order, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var orders []exchanges.Order
+	for _, order := range p.orders {
+		if order.Status != exchanges.OrderStatusOpen {
+			continue
+		}
+		if symbol != "" && order.Symbol != symbol {
+			continue
+		}
+		orders = append(orders, *order)
+	}
+	return orders, nil
+}
+
+// GetOrderHistory returns all orders placed during the replay
+func (p *Player) GetOrderHistory(ctx context.Context, symbol string, limit int) ([]exchanges.Order, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var orders []exchanges.Order
+	for _, order := range p.orders {
+		if symbol != "" && order.Symbol != symbol {
+			continue
+		}
+		orders = append(orders, *order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+	if limit > 0 && len(orders) > limit {
+		orders = orders[len(orders)-limit:]
+	}
+	return orders, nil
+}
+
+// GetBalance returns the configured initial balance
+func (p *Player) GetBalance(ctx context.Context) ([]exchanges.Balance, error) {
+	return []exchanges.Balance{
+		{
+			Asset:     "USD",
+			Free:      p.config.InitialBalance,
+			Total:     p.config.InitialBalance,
+			UpdatedAt: time.Now(),
+		},
+	}, nil
+}
+
+// GetPositions returns no positions; orders are never filled during replay
+func (p *Player) GetPositions(ctx context.Context) ([]exchanges.Position, error) {
+	return []exchanges.Position{}, nil
+}
+
+// GetPosition always returns ErrPositionNotFound
+func (p *Player) GetPosition(ctx context.Context, symbol string) (*exchanges.Position, error) {
+	return nil, exchanges.ErrPositionNotFound
+}
+
+// Name returns the exchange name
+func (p *Player) Name() string {
+	return p.config.Name
+}
+
+// SupportedSymbols returns the symbols present in the recording
+func (p *Player) SupportedSymbols() []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, event := range p.events {
+		if event.Symbol != "" && !seen[event.Symbol] {
+			seen[event.Symbol] = true
+			symbols = append(symbols, event.Symbol)
+		}
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Capabilities returns the features of the player, which accepts any single
+// order but neither links orders nor streams fills
+func (p *Player) Capabilities() exchanges.Capabilities {
+	return exchanges.Capabilities{
+		Shorts:     true,
+		StopOrders: true,
+		PostOnly:   true,
+		Margin:     true,
+	}
+}
+
+// SetLeverage records the leverage of symbol
+func (p *Player) SetLeverage(ctx context.Context, symbol string, leverage decimal.Decimal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	settings := p.leverageSettings(symbol)
+	settings.Leverage = leverage
+	p.leverage[symbol] = settings
+	return nil
+}
+
+// SetMarginMode records the margin mode of symbol
+func (p *Player) SetMarginMode(ctx context.Context, symbol string, mode exchanges.MarginMode) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	settings := p.leverageSettings(symbol)
+	settings.MarginMode = mode
+	p.leverage[symbol] = settings
+	return nil
+}
+
+// GetLeverage returns the recorded leverage of symbol, 1x cross by default
+func (p *Player) GetLeverage(ctx context.Context, symbol string) (*exchanges.LeverageSettings, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	settings := p.leverageSettings(symbol)
+	return &settings, nil
+}
+
+// leverageSettings must be called with the lock held
+func (p *Player) leverageSettings(symbol string) exchanges.LeverageSettings {
+	if settings, ok := p.leverage[symbol]; ok {
+		return settings
+	}
+	return exchanges.LeverageSettings{
+		Symbol:     symbol,
+		Leverage:   decimal.NewFromInt(1),
+		MarginMode: exchanges.MarginModeCross,
+	}
+}
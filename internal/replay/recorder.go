@@ -0,0 +1,192 @@
+package replay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// EventKind identifies the type of market data stored in an Event
+type EventKind string
+
+const (
+	EventTicker    EventKind = "ticker"
+	EventOrderBook EventKind = "orderbook"
+	EventCandle    EventKind = "candle"
+	EventTrade     EventKind = "trade"
+)
+
+// Event is a single recorded market data update
+type Event struct {
+	Time      time.Time            `json:"time"`
+	Kind      EventKind            `json:"kind"`
+	Exchange  string               `json:"exchange,omitempty"`
+	Symbol    string               `json:"symbol"`
+	Interval  string               `json:"interval,omitempty"`
+	Ticker    *exchanges.Ticker    `json:"ticker,omitempty"`
+	OrderBook *exchanges.OrderBook `json:"orderbook,omitempty"`
+	Candle    *exchanges.Candle    `json:"candle,omitempty"`
+	Trade     *exchanges.Trade     `json:"trade,omitempty"`
+}
+
+// Recorder writes market data events to a JSON lines stream
+type Recorder struct {
+	mu      sync.Mutex
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	closer  io.Closer
+	now     func() time.Time
+	count   int
+}
+
+// NewRecorder creates a recorder that writes to w
+func NewRecorder(w io.Writer) *Recorder {
+	buffered := bufio.NewWriter(w)
+	r := &Recorder{
+		writer:  buffered,
+		encoder: json.NewEncoder(buffered),
+		now:     time.Now,
+	}
+	if c, ok := w.(io.Closer); ok {
+		r.closer = c
+	}
+	return r
+}
+
+// NewFileRecorder creates a recorder appending to the file at path
+func NewFileRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording file: %w", err)
+	}
+	return NewRecorder(file), nil
+}
+
+// Record writes a single event, stamping it with the receive time if unset
+func (r *Recorder) Record(event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.encoder == nil {
+		return fmt.Errorf("recorder closed")
+	}
+	if event.Time.IsZero() {
+		event.Time = r.now()
+	}
+	if err := r.encoder.Encode(&event); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	r.count++
+	return nil
+}
+
+// Count returns the number of events recorded so far
+func (r *Recorder) Count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.count
+}
+
+// Flush writes any buffered events to the underlying writer
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.writer == nil {
+		return nil
+	}
+	return r.writer.Flush()
+}
+
+// Close flushes buffered events and closes the underlying writer if possible
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.writer == nil {
+		return nil
+	}
+	err := r.writer.Flush()
+	r.writer = nil
+	r.encoder = nil
+	if r.closer != nil {
+		if cerr := r.closer.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// RecordingExchange wraps an exchange and records every market data update
+// delivered through its subscriptions before forwarding it to the caller
+type RecordingExchange struct {
+	exchanges.Exchange
+	recorder *Recorder
+	onError  func(error)
+}
+
+// NewRecordingExchange creates a recording wrapper around inner
+func NewRecordingExchange(inner exchanges.Exchange, recorder *Recorder) *RecordingExchange {
+	return &RecordingExchange{
+		Exchange: inner,
+		recorder: recorder,
+	}
+}
+
+// SetErrorCallback sets the callback invoked when an event cannot be recorded
+func (e *RecordingExchange) SetErrorCallback(callback func(error)) {
+	e.onError = callback
+}
+
+func (e *RecordingExchange) record(event Event) {
+	event.Exchange = e.Exchange.Name()
+	if err := e.recorder.Record(event); err != nil && e.onError != nil {
+		e.onError(err)
+	}
+}
+
+// SubscribeTicker records ticker updates and forwards them to callback
+func (e *RecordingExchange) SubscribeTicker(ctx context.Context, symbol string, callback func(*exchanges.Ticker)) error {
+	return e.Exchange.SubscribeTicker(ctx, symbol, func(ticker *exchanges.Ticker) {
+		if ticker != nil {
+			e.record(Event{Kind: EventTicker, Symbol: symbol, Ticker: ticker})
+		}
+		callback(ticker)
+	})
+}
+
+// SubscribeOrderBook records order book updates and forwards them to callback
+func (e *RecordingExchange) SubscribeOrderBook(ctx context.Context, symbol string, callback func(*exchanges.OrderBook)) error {
+	return e.Exchange.SubscribeOrderBook(ctx, symbol, func(ob *exchanges.OrderBook) {
+		if ob != nil {
+			e.record(Event{Kind: EventOrderBook, Symbol: symbol, OrderBook: ob})
+		}
+		callback(ob)
+	})
+}
+
+// SubscribeTrades records trades and forwards them to callback
+func (e *RecordingExchange) SubscribeTrades(ctx context.Context, symbol string, callback func(*exchanges.Trade)) error {
+	return e.Exchange.SubscribeTrades(ctx, symbol, func(trade *exchanges.Trade) {
+		if trade != nil {
+			e.record(Event{Kind: EventTrade, Symbol: symbol, Trade: trade})
+		}
+		callback(trade)
+	})
+}
+
+// SubscribeCandles records candle updates and forwards them to callback
+func (e *RecordingExchange) SubscribeCandles(ctx context.Context, symbol string, interval string, callback func(*exchanges.Candle)) error {
+	return e.Exchange.SubscribeCandles(ctx, symbol, interval, func(candle *exchanges.Candle) {
+		if candle != nil {
+			e.record(Event{Kind: EventCandle, Symbol: symbol, Interval: interval, Candle: candle})
+		}
+		callback(candle)
+	})
+}
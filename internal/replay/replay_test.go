@@ -0,0 +1,155 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tickerExchange struct {
+	*exchanges.MockExchange
+	callback func(*exchanges.Ticker)
+}
+
+func (e *tickerExchange) SubscribeTicker(ctx context.Context, symbol string, callback func(*exchanges.Ticker)) error {
+	e.callback = callback
+	return nil
+}
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(&buf)
+
+	inner := &tickerExchange{MockExchange: exchanges.NewMockExchange("mock")}
+	recording := NewRecordingExchange(inner, recorder)
+
+	var forwarded int
+	require.NoError(t, recording.SubscribeTicker(context.Background(), "BTC-USD", func(*exchanges.Ticker) {
+		forwarded++
+	}))
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		recorder.now = func() time.Time { return base.Add(time.Duration(i) * time.Second) }
+		inner.callback(&exchanges.Ticker{Symbol: "BTC-USD", Last: decimal.NewFromInt(int64(100 + i))})
+	}
+	require.NoError(t, recorder.Flush())
+
+	assert.Equal(t, 3, forwarded)
+	assert.Equal(t, 3, recorder.Count())
+
+	events, err := LoadEvents(&buf)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, EventTicker, events[0].Kind)
+	assert.Equal(t, "mock", events[0].Exchange)
+	assert.True(t, events[2].Ticker.Last.Equal(decimal.NewFromInt(102)))
+}
+
+func TestPlayer_DispatchesInOrder(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Time: base, Kind: EventCandle, Symbol: "BTC-USD", Interval: "1m", Candle: &exchanges.Candle{Symbol: "BTC-USD", Timestamp: base, Close: decimal.NewFromInt(100)}},
+		{Time: base.Add(time.Second), Kind: EventTicker, Symbol: "BTC-USD", Ticker: &exchanges.Ticker{Symbol: "BTC-USD", Last: decimal.NewFromInt(101)}},
+		{Time: base.Add(2 * time.Second), Kind: EventCandle, Symbol: "BTC-USD", Interval: "5m", Candle: &exchanges.Candle{Symbol: "BTC-USD", Timestamp: base, Close: decimal.NewFromInt(99)}},
+		{Time: base.Add(3 * time.Second), Kind: EventTicker, Symbol: "ETH-USD", Ticker: &exchanges.Ticker{Symbol: "ETH-USD", Last: decimal.NewFromInt(10)}},
+	}
+
+	config := DefaultConfig()
+	config.StartDelay = 0
+	config.Speed = 2
+	player := NewPlayer(events, config)
+
+	var waits []time.Duration
+	player.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	var received []string
+	ctx := context.Background()
+	require.NoError(t, player.SubscribeCandles(ctx, "BTC-USD", "1m", func(c *exchanges.Candle) {
+		received = append(received, "candle:"+c.Close.String())
+	}))
+	require.NoError(t, player.SubscribeTicker(ctx, "BTC-USD", func(tk *exchanges.Ticker) {
+		received = append(received, "ticker:"+tk.Last.String())
+	}))
+
+	require.NoError(t, player.Run(ctx))
+
+	assert.Equal(t, []string{"candle:100", "ticker:101"}, received)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond}, waits)
+	assert.Equal(t, 4, player.Replayed())
+	assert.Equal(t, []string{"BTC-USD", "ETH-USD"}, player.SupportedSymbols())
+
+	ticker, err := player.GetTicker(ctx, "ETH-USD")
+	require.NoError(t, err)
+	assert.True(t, ticker.Last.Equal(decimal.NewFromInt(10)))
+
+	select {
+	case <-player.Done():
+	default:
+		t.Fatal("expected player to be done")
+	}
+}
+
+func TestPlayer_StopsOnCancel(t *testing.T) {
+	base := time.Now()
+	events := []Event{
+		{Time: base, Kind: EventTicker, Symbol: "BTC-USD", Ticker: &exchanges.Ticker{Symbol: "BTC-USD"}},
+		{Time: base.Add(time.Hour), Kind: EventTicker, Symbol: "BTC-USD", Ticker: &exchanges.Ticker{Symbol: "BTC-USD"}},
+	}
+	config := DefaultConfig()
+	config.StartDelay = 0
+	player := NewPlayer(events, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := player.Run(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, player.Replayed())
+}
+
+func TestPlayer_Orders(t *testing.T) {
+	player := NewPlayer(nil, DefaultConfig())
+	ctx := context.Background()
+
+	placed, err := player.PlaceOrder(ctx, &exchanges.Order{Symbol: "BTC-USD", Side: exchanges.OrderSideBuy, Amount: decimal.NewFromInt(1)})
+	require.NoError(t, err)
+	assert.NotEmpty(t, placed.ID)
+
+	open, err := player.GetOpenOrders(ctx, "BTC-USD")
+	require.NoError(t, err)
+	assert.Len(t, open, 1)
+
+	require.NoError(t, player.CancelOrder(ctx, placed.ID))
+	open, err = player.GetOpenOrders(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, open)
+
+	assert.ErrorIs(t, player.CancelOrder(ctx, "missing"), exchanges.ErrOrderNotFound)
+}
+
+func TestPlayer_Publish(t *testing.T) {
+	player := NewPlayer(nil, DefaultConfig())
+	ctx := context.Background()
+
+	var received []string
+	require.NoError(t, player.SubscribeTicker(ctx, "BTC-USD", func(tk *exchanges.Ticker) {
+		received = append(received, tk.Last.String())
+	}))
+
+	player.Publish(Event{Time: time.Now(), Kind: EventTicker, Symbol: "BTC-USD", Ticker: &exchanges.Ticker{Symbol: "BTC-USD", Last: decimal.NewFromInt(100)}})
+
+	assert.Equal(t, []string{"100"}, received)
+	ticker, err := player.GetTicker(ctx, "BTC-USD")
+	require.NoError(t, err)
+	assert.True(t, ticker.Last.Equal(decimal.NewFromInt(100)))
+}
@@ -0,0 +1,99 @@
+// Package ringbuf provides a fixed capacity buffer keeping the latest values
+// pushed. Once full, each push overwrites the oldest value in place, so a
+// bounded history never reallocates nor pins the backing arrays that
+// re-slicing leaves behind.
+package ringbuf
+
+import "iter"
+
+// Buffer holds up to its capacity of the latest values pushed, oldest first.
+// It is not safe for concurrent use.
+type Buffer[T any] struct {
+	items []T
+	start int // index of the oldest value
+	size  int
+}
+
+// New creates a buffer holding up to capacity values
+func New[T any](capacity int) *Buffer[T] {
+	if capacity < 1 {
+		panic("ringbuf: capacity must be positive")
+	}
+	return &Buffer[T]{items: make([]T, capacity)}
+}
+
+// Push appends v, returning the oldest value and true when it was evicted to
+// make room
+func (b *Buffer[T]) Push(v T) (evicted T, ok bool) {
+	if b.size < len(b.items) {
+		b.items[(b.start+b.size)%len(b.items)] = v
+		b.size++
+		return evicted, false
+	}
+	evicted = b.items[b.start]
+	b.items[b.start] = v
+	b.start = (b.start + 1) % len(b.items)
+	return evicted, true
+}
+
+// Len returns the number of values held
+func (b *Buffer[T]) Len() int {
+	return b.size
+}
+
+// Cap returns the number of values the buffer holds once full
+func (b *Buffer[T]) Cap() int {
+	return len(b.items)
+}
+
+// At returns the i-th value, 0 being the oldest. It panics when i is out of
+// range.
+func (b *Buffer[T]) At(i int) T {
+	if i < 0 || i >= b.size {
+		panic("ringbuf: index out of range")
+	}
+	return b.items[(b.start+i)%len(b.items)]
+}
+
+// Last returns the latest value, or false when the buffer is empty
+func (b *Buffer[T]) Last() (T, bool) {
+	if b.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return b.At(b.size - 1), true
+}
+
+// AppendTo appends the values to dst, oldest first, and returns the extended
+// slice. Passing a reused slice avoids allocating on every copy.
+func (b *Buffer[T]) AppendTo(dst []T) []T {
+	end := b.start + b.size
+	if end <= len(b.items) {
+		return append(dst, b.items[b.start:end]...)
+	}
+	dst = append(dst, b.items[b.start:]...)
+	return append(dst, b.items[:end-len(b.items)]...)
+}
+
+// Values returns a copy of the values, oldest first
+func (b *Buffer[T]) Values() []T {
+	return b.AppendTo(make([]T, 0, b.size))
+}
+
+// All iterates over the values, oldest first
+func (b *Buffer[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < b.size; i++ {
+			if !yield(b.items[(b.start+i)%len(b.items)]) {
+				return
+			}
+		}
+	}
+}
+
+// Reset empties the buffer, releasing the values it referenced
+func (b *Buffer[T]) Reset() {
+	clear(b.items)
+	b.start = 0
+	b.size = 0
+}
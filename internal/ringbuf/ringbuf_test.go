@@ -0,0 +1,158 @@
+package ringbuf
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBuffer_Push(t *testing.T) {
+	b := New[int](3)
+	for i := 1; i <= 3; i++ {
+		if _, ok := b.Push(i); ok {
+			t.Fatalf("Push(%d) should not evict before the buffer is full", i)
+		}
+	}
+	if b.Len() != 3 || b.Cap() != 3 {
+		t.Fatalf("Expected len 3 and cap 3, got %d and %d", b.Len(), b.Cap())
+	}
+
+	evicted, ok := b.Push(4)
+	if !ok || evicted != 1 {
+		t.Fatalf("Expected 1 to be evicted, got %d (%t)", evicted, ok)
+	}
+	if got := b.Values(); !slices.Equal(got, []int{2, 3, 4}) {
+		t.Errorf("Expected [2 3 4], got %v", got)
+	}
+	if b.At(0) != 2 || b.At(2) != 4 {
+		t.Errorf("Expected At(0)=2 and At(2)=4, got %d and %d", b.At(0), b.At(2))
+	}
+	if last, ok := b.Last(); !ok || last != 4 {
+		t.Errorf("Expected last 4, got %d (%t)", last, ok)
+	}
+}
+
+func TestBuffer_Wraparound(t *testing.T) {
+	b := New[int](4)
+	for i := 0; i < 11; i++ {
+		b.Push(i)
+	}
+	want := []int{7, 8, 9, 10}
+	if got := b.Values(); !slices.Equal(got, want) {
+		t.Errorf("Values: expected %v, got %v", want, got)
+	}
+	if got := b.AppendTo([]int{-1}); !slices.Equal(got, []int{-1, 7, 8, 9, 10}) {
+		t.Errorf("AppendTo should keep dst, got %v", got)
+	}
+	if got := slices.Collect(b.All()); !slices.Equal(got, want) {
+		t.Errorf("All: expected %v, got %v", want, got)
+	}
+	for v := range b.All() {
+		if v != 7 {
+			t.Errorf("Expected iteration to start at 7, got %d", v)
+		}
+		break
+	}
+}
+
+func TestBuffer_EmptyAndReset(t *testing.T) {
+	b := New[*int](2)
+	if _, ok := b.Last(); ok {
+		t.Error("Last should report an empty buffer")
+	}
+	if got := b.Values(); len(got) != 0 {
+		t.Errorf("Expected no values, got %v", got)
+	}
+
+	v := 1
+	b.Push(&v)
+	b.Push(&v)
+	b.Reset()
+	if b.Len() != 0 {
+		t.Errorf("Expected empty buffer after Reset, got %d values", b.Len())
+	}
+	for _, item := range b.items {
+		if item != nil {
+			t.Error("Reset should release the values held")
+		}
+	}
+	b.Push(&v)
+	if b.Len() != 1 || b.At(0) != &v {
+		t.Error("Buffer should be usable after Reset")
+	}
+}
+
+func TestBuffer_AtOutOfRange(t *testing.T) {
+	b := New[int](2)
+	b.Push(1)
+	defer func() {
+		if recover() == nil {
+			t.Error("At should panic out of range")
+		}
+	}()
+	b.At(1)
+}
+
+func TestNew_InvalidCapacity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("New should panic on a zero capacity")
+		}
+	}()
+	New[int](0)
+}
+
+func BenchmarkBuffer_Push(b *testing.B) {
+	buf := New[int](100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Push(i)
+	}
+}
+
+// BenchmarkSlice_Reslice is the append and re-slice trimming the buffer
+// replaces, for comparison
+func BenchmarkSlice_Reslice(b *testing.B) {
+	values := make([]int, 0, 100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		values = append(values, i)
+		if len(values) > 100 {
+			values = values[1:]
+		}
+	}
+}
+
+func BenchmarkBuffer_AppendTo(b *testing.B) {
+	buf := New[int](100)
+	for i := 0; i < 150; i++ {
+		buf.Push(i)
+	}
+	dst := make([]int, 0, 100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst = buf.AppendTo(dst[:0])
+	}
+}
+
+func BenchmarkBuffer_All(b *testing.B) {
+	buf := New[int](100)
+	for i := 0; i < 150; i++ {
+		buf.Push(i)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sum := 0
+		for v := range buf.All() {
+			sum += v
+		}
+		_ = sum
+	}
+}
@@ -0,0 +1,103 @@
+package ringbuf
+
+// Tiered keeps the latest values at full resolution and older ones
+// downsampled: the values evicted from a tier are merged, factor at a time,
+// into single values of the next tier. Each tier holds up to the same
+// capacity, so the span covered grows geometrically with the tiers while the
+// memory used only grows linearly. It is not safe for concurrent use.
+type Tiered[T any] struct {
+	tiers  []*Buffer[T] // Finest first
+	merged []int        // Values merged into the latest value of each tier
+	factor int
+	merge  func(acc, v T) T
+}
+
+// NewTiered creates a buffer holding up to capacity values at full
+// resolution and as many in each of levels downsampled tiers. merge folds a
+// value into the one standing for the values before it in the same bucket,
+// e.g. keeping the latest price or summing volumes.
+func NewTiered[T any](capacity, levels, factor int, merge func(acc, v T) T) *Tiered[T] {
+	if levels < 0 || (levels > 0 && factor < 2) {
+		panic("ringbuf: downsampled tiers need a factor of at least 2")
+	}
+	t := &Tiered[T]{
+		tiers:  make([]*Buffer[T], levels+1),
+		merged: make([]int, levels+1),
+		factor: factor,
+		merge:  merge,
+	}
+	for i := range t.tiers {
+		t.tiers[i] = New[T](capacity)
+	}
+	return t
+}
+
+// Push appends v, cascading the value it evicts into the coarser tiers
+func (t *Tiered[T]) Push(v T) {
+	for i, tier := range t.tiers {
+		if i > 0 && t.merged[i] > 0 && t.merged[i] < t.factor {
+			// The latest value of the tier still has room in its bucket
+			last := (tier.start + tier.size - 1) % len(tier.items)
+			tier.items[last] = t.merge(tier.items[last], v)
+			t.merged[i]++
+			return
+		}
+		evicted, ok := tier.Push(v)
+		t.merged[i] = 1
+		if !ok {
+			return
+		}
+		v = evicted
+	}
+}
+
+// Len returns the number of values held across the tiers
+func (t *Tiered[T]) Len() int {
+	n := 0
+	for _, tier := range t.tiers {
+		n += tier.Len()
+	}
+	return n
+}
+
+// Cap returns the number of values held once every tier is full
+func (t *Tiered[T]) Cap() int {
+	return len(t.tiers) * t.tiers[0].Cap()
+}
+
+// Span returns the number of values pushed the tiers cover once full
+func (t *Tiered[T]) Span() int {
+	span, resolution := 0, 1
+	for _, tier := range t.tiers {
+		span += tier.Cap() * resolution
+		resolution *= t.factor
+	}
+	return span
+}
+
+// Last returns the latest value, or false when the buffer is empty
+func (t *Tiered[T]) Last() (T, bool) {
+	return t.tiers[0].Last()
+}
+
+// AppendTo appends the values to dst, oldest and coarsest first, and returns
+// the extended slice
+func (t *Tiered[T]) AppendTo(dst []T) []T {
+	for i := len(t.tiers) - 1; i >= 0; i-- {
+		dst = t.tiers[i].AppendTo(dst)
+	}
+	return dst
+}
+
+// Values returns a copy of the values, oldest and coarsest first
+func (t *Tiered[T]) Values() []T {
+	return t.AppendTo(make([]T, 0, t.Len()))
+}
+
+// Reset empties the buffer, releasing the values it referenced
+func (t *Tiered[T]) Reset() {
+	for i, tier := range t.tiers {
+		tier.Reset()
+		t.merged[i] = 0
+	}
+}
@@ -0,0 +1,65 @@
+package ringbuf
+
+import (
+	"slices"
+	"testing"
+)
+
+func sum(acc, v int) int { return acc + v }
+
+func TestTiered_Downsamples(t *testing.T) {
+	b := NewTiered(3, 1, 2, sum)
+	for i := 1; i <= 9; i++ {
+		b.Push(i)
+	}
+	// 1 to 6 were evicted from the full resolution tier and merged in pairs
+	if got := b.Values(); !slices.Equal(got, []int{3, 7, 11, 7, 8, 9}) {
+		t.Fatalf("Expected [3 7 11 7 8 9], got %v", got)
+	}
+
+	// The next eviction opens a bucket, dropping the oldest downsampled value
+	b.Push(10)
+	if got := b.Values(); !slices.Equal(got, []int{7, 11, 7, 8, 9, 10}) {
+		t.Errorf("Expected [7 11 7 8 9 10], got %v", got)
+	}
+	b.Push(11)
+	if got := b.Values(); !slices.Equal(got, []int{7, 11, 15, 9, 10, 11}) {
+		t.Errorf("Expected [7 11 15 9 10 11], got %v", got)
+	}
+	if last, ok := b.Last(); !ok || last != 11 {
+		t.Errorf("Expected last 11, got %d (%t)", last, ok)
+	}
+	if b.Len() != 6 || b.Cap() != 6 {
+		t.Errorf("Expected len 6 and cap 6, got %d and %d", b.Len(), b.Cap())
+	}
+}
+
+func TestTiered_Span(t *testing.T) {
+	b := NewTiered(100, 2, 5, sum)
+	if b.Cap() != 300 || b.Span() != 100+500+2500 {
+		t.Errorf("Expected cap 300 and span 3100, got %d and %d", b.Cap(), b.Span())
+	}
+
+	// Without downsampled tiers it is a plain buffer
+	plain := NewTiered(3, 0, 0, sum)
+	for i := 0; i < 5; i++ {
+		plain.Push(i)
+	}
+	if got := plain.Values(); !slices.Equal(got, []int{2, 3, 4}) || plain.Span() != 3 {
+		t.Errorf("Expected [2 3 4] over a span of 3, got %v over %d", got, plain.Span())
+	}
+
+	plain.Reset()
+	if plain.Len() != 0 {
+		t.Errorf("Expected empty buffer after Reset, got %d values", plain.Len())
+	}
+}
+
+func TestNewTiered_InvalidFactor(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTiered should panic on downsampled tiers without a factor")
+		}
+	}()
+	NewTiered(3, 1, 1, sum)
+}
@@ -0,0 +1,86 @@
+package risk
+
+import (
+	"fmt"
+	"time"
+)
+
+// symbolActivity tracks the recent entries and stop-outs of a symbol
+type symbolActivity struct {
+	entries       []time.Time // Entry times within the last hour
+	blackoutUntil time.Time   // No re-entry before this time after a stop-out
+	deleveragedAt time.Time   // Last partial close by the pre-liquidation defense
+}
+
+func (m *Manager) activity(symbol string) *symbolActivity {
+	if m.symbols == nil {
+		m.symbols = make(map[string]*symbolActivity)
+	}
+	activity, ok := m.symbols[symbol]
+	if !ok {
+		activity = &symbolActivity{}
+		m.symbols[symbol] = activity
+	}
+	return activity
+}
+
+// RecordEntry records an entry placed on symbol at the given time, for the
+// minimum entry interval and hourly trade limits
+func (m *Manager) RecordEntry(symbol string, at time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	activity := m.activity(symbol)
+	activity.entries = append(pruneEntries(activity.entries, at), at)
+}
+
+// recordStopOut starts the re-entry blackout of the symbol of a stopped-out
+// trade. The caller must hold the lock.
+func (m *Manager) recordStopOut(result TradeResult) {
+	if m.config.StopOutBlackout <= 0 {
+		return
+	}
+	at := result.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	activity := m.activity(result.Symbol)
+	if until := at.Add(m.config.StopOutBlackout); until.After(activity.blackoutUntil) {
+		activity.blackoutUntil = until
+	}
+}
+
+// validateSymbolActivity rejects an entry on symbol at now that would churn
+// it. The caller must hold the lock.
+func (m *Manager) validateSymbolActivity(symbol string, now time.Time) error {
+	activity, ok := m.symbols[symbol]
+	if !ok {
+		return nil
+	}
+
+	if now.Before(activity.blackoutUntil) {
+		return fmt.Errorf("%s stopped out, re-entry blocked for %v", symbol, activity.blackoutUntil.Sub(now).Round(time.Second))
+	}
+
+	entries := pruneEntries(activity.entries, now)
+	if m.config.MinEntryInterval > 0 && len(entries) > 0 {
+		if elapsed := now.Sub(entries[len(entries)-1]); elapsed < m.config.MinEntryInterval {
+			return fmt.Errorf("last entry on %s was %v ago, minimum interval is %v",
+				symbol, elapsed.Round(time.Second), m.config.MinEntryInterval)
+		}
+	}
+	if m.config.MaxSymbolTradesPerHour > 0 && len(entries) >= m.config.MaxSymbolTradesPerHour {
+		return fmt.Errorf("%s reached %d entries in the last hour", symbol, m.config.MaxSymbolTradesPerHour)
+	}
+	return nil
+}
+
+// pruneEntries drops the entries more than an hour older than now
+func pruneEntries(entries []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-time.Hour)
+	i := 0
+	for i < len(entries) && !entries[i].After(cutoff) {
+		i++
+	}
+	return entries[i:]
+}
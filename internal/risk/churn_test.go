@@ -0,0 +1,110 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+func churnTestRequest(symbol string) *order.OrderRequest {
+	return &order.OrderRequest{
+		Symbol:   symbol,
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Amount:   decimal.NewFromFloat(0.1),
+		StopLoss: decimal.NewFromInt(99),
+	}
+}
+
+func TestValidateOrder_MinEntryInterval(t *testing.T) {
+	config := DefaultConfig()
+	config.MinEntryInterval = time.Minute
+	manager := NewManager(config, decimal.NewFromInt(10000))
+
+	manager.RecordEntry("BTC-USD", time.Now().Add(-30*time.Second))
+	if err := manager.ValidateOrder(churnTestRequest("BTC-USD"), nil); err == nil {
+		t.Error("expected an entry within the minimum interval to be rejected")
+	}
+	if err := manager.ValidateOrder(churnTestRequest("ETH-USD"), nil); err != nil {
+		t.Errorf("expected other symbols to be unaffected, got %v", err)
+	}
+
+	manager = NewManager(config, decimal.NewFromInt(10000))
+	manager.RecordEntry("BTC-USD", time.Now().Add(-2*time.Minute))
+	if err := manager.ValidateOrder(churnTestRequest("BTC-USD"), nil); err != nil {
+		t.Errorf("expected an entry after the minimum interval to pass, got %v", err)
+	}
+}
+
+func TestValidateOrder_MaxSymbolTradesPerHour(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxSymbolTradesPerHour = 2
+	manager := NewManager(config, decimal.NewFromInt(10000))
+
+	now := time.Now()
+	manager.RecordEntry("BTC-USD", now.Add(-90*time.Minute)) // Outside the hour
+	manager.RecordEntry("BTC-USD", now.Add(-40*time.Minute))
+	if err := manager.ValidateOrder(churnTestRequest("BTC-USD"), nil); err != nil {
+		t.Errorf("expected one entry in the last hour to pass, got %v", err)
+	}
+
+	manager.RecordEntry("BTC-USD", now.Add(-10*time.Minute))
+	if err := manager.ValidateOrder(churnTestRequest("BTC-USD"), nil); err == nil {
+		t.Error("expected the hourly limit to reject the entry")
+	}
+}
+
+func TestValidateOrder_StopOutBlackout(t *testing.T) {
+	config := DefaultConfig()
+	config.StopOutBlackout = 30 * time.Minute
+	manager := NewManager(config, decimal.NewFromInt(10000))
+
+	manager.RecordTrade(TradeResult{
+		Timestamp: time.Now().Add(-10 * time.Minute),
+		Symbol:    "BTC-USD",
+		PnL:       decimal.NewFromInt(-5),
+		StopOut:   true,
+	})
+	if err := manager.ValidateOrder(churnTestRequest("BTC-USD"), nil); err == nil {
+		t.Error("expected re-entry after a stop-out to be blocked")
+	}
+
+	manager.RecordTrade(TradeResult{
+		Timestamp: time.Now().Add(-time.Hour),
+		Symbol:    "ETH-USD",
+		PnL:       decimal.NewFromInt(-5),
+		StopOut:   true,
+	})
+	if err := manager.ValidateOrder(churnTestRequest("ETH-USD"), nil); err != nil {
+		t.Errorf("expected the blackout to have expired, got %v", err)
+	}
+
+	manager.RecordTrade(TradeResult{
+		Timestamp: time.Now(),
+		Symbol:    "SOL-USD",
+		PnL:       decimal.NewFromInt(-5),
+	})
+	if err := manager.ValidateOrder(churnTestRequest("SOL-USD"), nil); err != nil {
+		t.Errorf("expected losses that are not stop-outs to allow re-entry, got %v", err)
+	}
+}
+
+func TestLoadConfig_AntiChurn(t *testing.T) {
+	t.Setenv("RISK_MIN_ENTRY_INTERVAL_SECONDS", "45")
+	t.Setenv("RISK_MAX_SYMBOL_TRADES_PER_HOUR", "6")
+	t.Setenv("RISK_STOP_OUT_BLACKOUT_MINUTES", "20")
+
+	config := LoadConfig()
+	if config.MinEntryInterval != 45*time.Second {
+		t.Errorf("expected MinEntryInterval 45s, got %v", config.MinEntryInterval)
+	}
+	if config.MaxSymbolTradesPerHour != 6 {
+		t.Errorf("expected MaxSymbolTradesPerHour 6, got %d", config.MaxSymbolTradesPerHour)
+	}
+	if config.StopOutBlackout != 20*time.Minute {
+		t.Errorf("expected StopOutBlackout 20m, got %v", config.StopOutBlackout)
+	}
+}
@@ -0,0 +1,288 @@
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/calendar"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// EventImpact is the expected market impact of a scheduled event
+type EventImpact string
+
+const (
+	EventImpactLow    EventImpact = "low"
+	EventImpactMedium EventImpact = "medium"
+	EventImpactHigh   EventImpact = "high"
+)
+
+func (i EventImpact) rank() int {
+	switch EventImpact(strings.ToLower(string(i))) {
+	case EventImpactLow:
+		return 1
+	case EventImpactMedium:
+		return 2
+	case EventImpactHigh:
+		return 3
+	}
+	return 0
+}
+
+// EventAction is what the risk manager does with entries around an event
+type EventAction string
+
+const (
+	EventActionPause EventAction = "pause" // Reject new entries
+	EventActionWiden EventAction = "widen" // Widen stops and shrink size to keep the same risk
+)
+
+// Event is a scheduled economic or news event
+type Event struct {
+	Time   time.Time   `json:"time"`
+	Name   string      `json:"name"`
+	Impact EventImpact `json:"impact"`
+	// Symbols or currencies affected; empty affects every symbol. A currency
+	// such as USD matches every symbol quoted or based in it.
+	Symbols []string `json:"symbols,omitempty"`
+}
+
+func (e Event) affects(symbol string) bool {
+	if len(e.Symbols) == 0 {
+		return true
+	}
+	parts := strings.FieldsFunc(symbol, func(r rune) bool { return r == '-' || r == '/' })
+	for _, s := range e.Symbols {
+		if strings.EqualFold(s, symbol) {
+			return true
+		}
+		for _, part := range parts {
+			if strings.EqualFold(s, part) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// EventFeed lists scheduled events
+type EventFeed interface {
+	Events(ctx context.Context) ([]Event, error)
+}
+
+// CSVEventFeed reads events from a calendar file (see calendar.LoadFile),
+// the format of the blackouts of the execution agent. Every line needs an
+// impact; the event is at its start, and its end is not used.
+type CSVEventFeed struct {
+	Path string
+}
+
+// Events implements EventFeed
+func (f CSVEventFeed) Events(_ context.Context) ([]Event, error) {
+	entries, err := calendar.LoadFile(f.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(entries))
+	for _, entry := range entries {
+		event := Event{Time: entry.Start, Name: entry.Name, Impact: EventImpact(entry.Impact), Symbols: entry.Symbols}
+		if event.Impact.rank() == 0 {
+			return nil, fmt.Errorf("event feed %s line %d: invalid impact %q, expected low, medium or high", f.Path, entry.Line, entry.Impact)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// HTTPEventFeed fetches events as a JSON array from an external API
+type HTTPEventFeed struct {
+	URL    string
+	Client *http.Client
+}
+
+// Events implements EventFeed
+func (f HTTPEventFeed) Events(ctx context.Context) ([]Event, error) {
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event feed request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch event feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("event feed returned status %d", resp.StatusCode)
+	}
+	var events []Event
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		return nil, fmt.Errorf("failed to decode event feed: %w", err)
+	}
+	return events, nil
+}
+
+// EventCalendar caches the events of a feed and refreshes them periodically
+type EventCalendar struct {
+	feed     EventFeed
+	interval time.Duration
+
+	mu     sync.RWMutex
+	events []Event
+	now    func() time.Time
+}
+
+// NewEventCalendar creates a calendar refreshing feed every interval
+func NewEventCalendar(feed EventFeed, interval time.Duration) *EventCalendar {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &EventCalendar{
+		feed:     feed,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// NewEventCalendarFromConfig creates the calendar for the configured feed, or
+// returns nil when no feed is configured
+func NewEventCalendarFromConfig(config *Config) *EventCalendar {
+	switch {
+	case config.EventFeedPath != "":
+		return NewEventCalendar(CSVEventFeed{Path: config.EventFeedPath}, config.EventRefreshInterval)
+	case config.EventFeedURL != "":
+		return NewEventCalendar(HTTPEventFeed{URL: config.EventFeedURL}, config.EventRefreshInterval)
+	}
+	return nil
+}
+
+// Refresh reloads the events from the feed. The previous events are kept if
+// the feed fails.
+func (c *EventCalendar) Refresh(ctx context.Context) error {
+	events, err := c.feed.Events(ctx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.events = events
+	c.mu.Unlock()
+	return nil
+}
+
+// Run refreshes the calendar until ctx is canceled
+func (c *EventCalendar) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil && ctx.Err() == nil {
+				logger.Component("risk").Warn("failed to refresh event calendar", "error", err)
+			}
+		}
+	}
+}
+
+// Upcoming returns the events at or after the current time
+func (c *EventCalendar) Upcoming() []Event {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	var upcoming []Event
+	for _, event := range c.events {
+		if !event.Time.Before(now) {
+			upcoming = append(upcoming, event)
+		}
+	}
+	return upcoming
+}
+
+// Active returns the most impactful event affecting symbol whose window,
+// from before the event until after it, contains the current time
+func (c *EventCalendar) Active(symbol string, before, after time.Duration, minImpact EventImpact) (Event, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := c.now()
+	var active Event
+	found := false
+	for _, event := range c.events {
+		if event.Impact.rank() < minImpact.rank() || !event.affects(symbol) {
+			continue
+		}
+		if now.Before(event.Time.Add(-before)) || now.After(event.Time.Add(after)) {
+			continue
+		}
+		if !found || event.Impact.rank() > active.Impact.rank() {
+			active = event
+			found = true
+		}
+	}
+	return active, found
+}
+
+// SetEventCalendar enables the event policy using the events of calendar
+func (m *Manager) SetEventCalendar(calendar *EventCalendar) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = calendar
+}
+
+// EventCalendar returns the event calendar, if any
+func (m *Manager) EventCalendar() *EventCalendar {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.events
+}
+
+// ApplyEventPolicy applies the configured event action to an entry request
+// placed around a scheduled event: the entry is rejected when paused, or its
+// stop is moved EventStopWidenFactor times further away and its size reduced
+// by the same factor so the amount at risk is unchanged.
+func (m *Manager) ApplyEventPolicy(req *order.OrderRequest) error {
+	m.mu.RLock()
+	calendar := m.events
+	m.mu.RUnlock()
+	if calendar == nil {
+		return nil
+	}
+
+	event, active := calendar.Active(req.Symbol, m.config.EventPauseBefore, m.config.EventPauseAfter, m.config.EventMinImpact)
+	if !active {
+		return nil
+	}
+
+	if m.config.EventAction != EventActionWiden {
+		return fmt.Errorf("entries on %s paused around %s at %s", req.Symbol, event.Name, event.Time.UTC().Format("15:04 UTC"))
+	}
+
+	factor := m.config.EventStopWidenFactor
+	if factor.LessThanOrEqual(decimal.NewFromInt(1)) || req.StopLoss.IsZero() {
+		return nil
+	}
+	req.StopLoss = req.Price.Sub(req.Price.Sub(req.StopLoss).Mul(factor))
+	req.Amount = req.Amount.Div(factor)
+	logger.Component("risk").Info("stop widened around event",
+		"symbol", req.Symbol,
+		"event", event.Name,
+		"stop_loss", req.StopLoss.String(),
+		"amount", req.Amount.String())
+	return nil
+}
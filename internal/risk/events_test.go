@@ -0,0 +1,166 @@
+package risk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+type staticEventFeed []Event
+
+func (f staticEventFeed) Events(context.Context) ([]Event, error) {
+	return f, nil
+}
+
+func newTestCalendar(t *testing.T, now time.Time, events ...Event) *EventCalendar {
+	t.Helper()
+	calendar := NewEventCalendar(staticEventFeed(events), time.Hour)
+	calendar.now = func() time.Time { return now }
+	if err := calendar.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	return calendar
+}
+
+func TestCSVEventFeed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.csv")
+	content := "start,end,name,symbols,impact\n" +
+		"# comment\n" +
+		"2024-03-12T12:30:00Z,,CPI,USD,high\n" +
+		"2024-03-20T18:00:00Z,2024-03-20T19:00:00Z,FOMC,,HIGH\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := CSVEventFeed{Path: path}.Events(context.Background())
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	if events[0].Name != "CPI" || events[0].Impact != EventImpactHigh || len(events[0].Symbols) != 1 {
+		t.Errorf("unexpected first event: %+v", events[0])
+	}
+	if events[1].Impact != EventImpactHigh || len(events[1].Symbols) != 0 {
+		t.Errorf("unexpected second event: %+v", events[1])
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.csv")
+	if err := os.WriteFile(bad, []byte("2024-03-12T12:30:00Z,,CPI,,extreme\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (CSVEventFeed{Path: bad}).Events(context.Background()); err == nil {
+		t.Error("expected an error for an invalid impact")
+	}
+}
+
+func TestHTTPEventFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"time":"2024-03-12T12:30:00Z","name":"CPI","impact":"high","symbols":["USD"]}]`))
+	}))
+	defer server.Close()
+
+	events, err := HTTPEventFeed{URL: server.URL}.Events(context.Background())
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != "CPI" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
+
+func TestEventCalendar_Active(t *testing.T) {
+	at := time.Date(2024, 3, 12, 12, 30, 0, 0, time.UTC)
+	events := []Event{
+		{Time: at, Name: "CPI", Impact: EventImpactHigh, Symbols: []string{"USD"}},
+		{Time: at, Name: "ECB speech", Impact: EventImpactLow, Symbols: []string{"EUR"}},
+	}
+
+	tests := []struct {
+		name   string
+		now    time.Time
+		symbol string
+		min    EventImpact
+		want   bool
+	}{
+		{"inside window before", at.Add(-10 * time.Minute), "BTC-USD", EventImpactHigh, true},
+		{"inside window after", at.Add(10 * time.Minute), "BTC-USD", EventImpactHigh, true},
+		{"before window", at.Add(-20 * time.Minute), "BTC-USD", EventImpactHigh, false},
+		{"after window", at.Add(20 * time.Minute), "BTC-USD", EventImpactHigh, false},
+		{"other currency", at, "BTC-EUR", EventImpactHigh, false},
+		{"low impact included", at, "BTC-EUR", EventImpactLow, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := newTestCalendar(t, tt.now, events...)
+			_, active := calendar.Active(tt.symbol, 15*time.Minute, 15*time.Minute, tt.min)
+			if active != tt.want {
+				t.Errorf("expected active=%v, got %v", tt.want, active)
+			}
+		})
+	}
+}
+
+func TestApplyEventPolicy_Pause(t *testing.T) {
+	at := time.Date(2024, 3, 12, 12, 30, 0, 0, time.UTC)
+	manager := NewManager(DefaultConfig(), decimal.NewFromInt(10000))
+
+	req := &order.OrderRequest{Symbol: "BTC-USD", Price: decimal.NewFromInt(100), StopLoss: decimal.NewFromInt(99)}
+	if err := manager.ApplyEventPolicy(req); err != nil {
+		t.Errorf("expected no error without a calendar, got %v", err)
+	}
+
+	manager.SetEventCalendar(newTestCalendar(t, at.Add(-5*time.Minute),
+		Event{Time: at, Name: "CPI", Impact: EventImpactHigh}))
+	if err := manager.ApplyEventPolicy(req); err == nil {
+		t.Error("expected entries to be paused around the event")
+	}
+}
+
+func TestApplyEventPolicy_Widen(t *testing.T) {
+	at := time.Date(2024, 3, 12, 12, 30, 0, 0, time.UTC)
+	config := DefaultConfig()
+	config.EventAction = EventActionWiden
+	config.EventStopWidenFactor = decimal.NewFromInt(2)
+	manager := NewManager(config, decimal.NewFromInt(10000))
+	manager.SetEventCalendar(newTestCalendar(t, at,
+		Event{Time: at, Name: "CPI", Impact: EventImpactHigh}))
+
+	buy := &order.OrderRequest{
+		Symbol:   "BTC-USD",
+		Price:    decimal.NewFromInt(100),
+		Amount:   decimal.NewFromInt(2),
+		StopLoss: decimal.NewFromInt(99),
+	}
+	if err := manager.ApplyEventPolicy(buy); err != nil {
+		t.Fatalf("expected the entry to be allowed, got %v", err)
+	}
+	if !buy.StopLoss.Equal(decimal.NewFromInt(98)) {
+		t.Errorf("expected buy stop 98, got %s", buy.StopLoss)
+	}
+	if !buy.Amount.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("expected buy amount 1, got %s", buy.Amount)
+	}
+
+	sell := &order.OrderRequest{
+		Symbol:   "BTC-USD",
+		Price:    decimal.NewFromInt(100),
+		Amount:   decimal.NewFromInt(2),
+		StopLoss: decimal.NewFromInt(101),
+	}
+	if err := manager.ApplyEventPolicy(sell); err != nil {
+		t.Fatalf("expected the entry to be allowed, got %v", err)
+	}
+	if !sell.StopLoss.Equal(decimal.NewFromInt(102)) {
+		t.Errorf("expected sell stop 102, got %s", sell.StopLoss)
+	}
+}
@@ -0,0 +1,80 @@
+package risk
+
+import (
+	"sort"
+
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// Utilization is how much of a risk limit is used
+type Utilization struct {
+	Name  string
+	Used  decimal.Decimal
+	Limit decimal.Decimal // Zero when the limit is disabled
+}
+
+// Fraction returns the share of the limit used, 0 without a limit
+func (u Utilization) Fraction() float64 {
+	if !u.Limit.IsPositive() {
+		return 0
+	}
+	return u.Used.Div(u.Limit).InexactFloat64()
+}
+
+// SymbolExposures returns the exposure of each symbol with open positions
+// against MaxExposurePerSymbol, most used first. As when validating orders,
+// the exposure of a symbol is that of its larger leg.
+func (m *Manager) SymbolExposures(positions []*order.ManagedPosition) []Utilization {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	legs := make(map[string]map[order.PositionSide]decimal.Decimal)
+	for _, pos := range positions {
+		if pos.Status != order.PositionStatusOpen {
+			continue
+		}
+		if legs[pos.Symbol] == nil {
+			legs[pos.Symbol] = make(map[order.PositionSide]decimal.Decimal)
+		}
+		legs[pos.Symbol][pos.Side] = legs[pos.Symbol][pos.Side].Add(pos.Amount.Mul(pos.EntryPrice))
+	}
+
+	limit := m.currentBalance.Mul(m.config.MaxExposurePerSymbol).Div(decimal.NewFromInt(100))
+	exposures := make([]Utilization, 0, len(legs))
+	for symbol, sides := range legs {
+		exposure := decimal.Max(sides[order.PositionSideLong], sides[order.PositionSideShort])
+		if converted, err := m.toReporting(exposure, symbol); err == nil {
+			exposure = converted
+		}
+		exposures = append(exposures, Utilization{Name: symbol, Used: exposure, Limit: limit})
+	}
+	sort.Slice(exposures, func(i, j int) bool {
+		if exposures[i].Fraction() != exposures[j].Fraction() {
+			return exposures[i].Fraction() > exposures[j].Fraction()
+		}
+		return exposures[i].Name < exposures[j].Name
+	})
+	return exposures
+}
+
+// LossBudgets returns the loss taken in the current daily session, and in
+// the weekly one when MaxWeeklyLoss is set, against their limits
+func (m *Manager) LossBudgets() []Utilization {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	budgets := []Utilization{{
+		Name:  "daily",
+		Used:  decimal.Max(m.dailyPnL.Neg(), decimal.Zero),
+		Limit: m.config.MaxDailyLoss,
+	}}
+	if m.config.MaxWeeklyLoss.IsPositive() {
+		budgets = append(budgets, Utilization{
+			Name:  "weekly",
+			Used:  decimal.Max(m.weeklyPnL.Neg(), decimal.Zero),
+			Limit: m.config.MaxWeeklyLoss,
+		})
+	}
+	return budgets
+}
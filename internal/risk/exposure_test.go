@@ -0,0 +1,53 @@
+package risk
+
+import (
+	"math"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_SymbolExposures(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxExposurePerSymbol = decimal.NewFromInt(30)
+	manager := NewManager(config, decimal.NewFromInt(10000))
+
+	positions := []*order.ManagedPosition{
+		{Symbol: "BTC-USD", Side: order.PositionSideLong, Status: order.PositionStatusOpen, Amount: decimal.NewFromFloat(0.02), EntryPrice: decimal.NewFromInt(50000)},
+		// A hedged short smaller than the long does not add to it
+		{Symbol: "BTC-USD", Side: order.PositionSideShort, Status: order.PositionStatusOpen, Amount: decimal.NewFromFloat(0.01), EntryPrice: decimal.NewFromInt(50000)},
+		{Symbol: "ETH-USD", Side: order.PositionSideLong, Status: order.PositionStatusOpen, Amount: decimal.NewFromInt(1), EntryPrice: decimal.NewFromInt(2400)},
+		{Symbol: "SOL-USD", Side: order.PositionSideLong, Status: order.PositionStatusClosed, Amount: decimal.NewFromInt(10), EntryPrice: decimal.NewFromInt(100)},
+	}
+
+	exposures := manager.SymbolExposures(positions)
+	if len(exposures) != 2 {
+		t.Fatalf("expected the two symbols with open positions, got %+v", exposures)
+	}
+	if exposures[0].Name != "ETH-USD" || math.Abs(exposures[0].Fraction()-0.8) > 1e-9 {
+		t.Errorf("expected ETH-USD first at 80%% of its limit, got %+v", exposures[0])
+	}
+	if exposures[1].Name != "BTC-USD" || !exposures[1].Used.Equal(decimal.NewFromInt(1000)) || !exposures[1].Limit.Equal(decimal.NewFromInt(3000)) {
+		t.Errorf("expected 1000 of BTC-USD exposure against 3000, got %+v", exposures[1])
+	}
+}
+
+func TestManager_LossBudgets(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxDailyLoss = decimal.NewFromInt(100)
+	manager := NewManager(config, decimal.NewFromInt(10000))
+	manager.RecordTrade(TradeResult{Symbol: "BTC-USD", PnL: decimal.NewFromInt(-25)})
+
+	budgets := manager.LossBudgets()
+	if len(budgets) != 1 || budgets[0].Name != "daily" || budgets[0].Fraction() != 0.25 {
+		t.Fatalf("expected a quarter of the daily budget used, got %+v", budgets)
+	}
+
+	config.MaxWeeklyLoss = decimal.NewFromInt(500)
+	manager.RecordTrade(TradeResult{Symbol: "BTC-USD", PnL: decimal.NewFromInt(50)})
+	budgets = manager.LossBudgets()
+	if len(budgets) != 2 || !budgets[0].Used.IsZero() || budgets[1].Name != "weekly" {
+		t.Errorf("expected no loss used once in profit and a weekly budget, got %+v", budgets)
+	}
+}
@@ -0,0 +1,37 @@
+package risk
+
+import (
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// CurrencyConverter converts amounts into the reporting currency the limits
+// and balances are expressed in. It reports false when it has no rate.
+type CurrencyConverter interface {
+	Convert(amount decimal.Decimal, from string) (decimal.Decimal, bool)
+}
+
+// SetCurrencyConverter converts the notional, risk and PnL of symbols quoted
+// in other currencies before they are checked against the limits. Without a
+// converter every symbol is assumed to be quoted in the reporting currency.
+func (m *Manager) SetCurrencyConverter(converter CurrencyConverter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fx = converter
+}
+
+// toReporting converts amount from the quote currency of symbol. Must be
+// called with the lock held.
+func (m *Manager) toReporting(amount decimal.Decimal, symbol string) (decimal.Decimal, error) {
+	if m.fx == nil {
+		return amount, nil
+	}
+	quote := exchanges.QuoteCurrency(symbol)
+	converted, ok := m.fx.Convert(amount, quote)
+	if !ok {
+		return decimal.Zero, fmt.Errorf("no conversion rate for %s yet", quote)
+	}
+	return converted, nil
+}
@@ -0,0 +1,60 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// fixedRates converts with fixed rates into USD
+type fixedRates map[string]decimal.Decimal
+
+func (r fixedRates) Convert(amount decimal.Decimal, from string) (decimal.Decimal, bool) {
+	if from == "USD" {
+		return amount, true
+	}
+	rate, ok := r[from]
+	return amount.Mul(rate), ok
+}
+
+func TestValidateOrder_ConvertsQuoteCurrency(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxPositionSize = decimal.NewFromInt(1000)
+	manager := NewManager(config, decimal.NewFromInt(10000))
+	manager.SetCurrencyConverter(fixedRates{"EUR": decimal.NewFromFloat(1.2)})
+
+	// 900 EUR is 1080 USD, above the 1000 USD limit
+	req := &order.OrderRequest{
+		Symbol:   "ETH-EUR",
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(900),
+		Amount:   decimal.NewFromInt(1),
+		StopLoss: decimal.NewFromInt(890),
+	}
+	if err := manager.ValidateOrder(req, nil); err == nil {
+		t.Error("expected the converted position size to exceed the limit")
+	}
+
+	req.Price = decimal.NewFromInt(800)
+	req.StopLoss = decimal.NewFromInt(790)
+	if err := manager.ValidateOrder(req, nil); err != nil {
+		t.Errorf("expected 960 USD to pass, got %v", err)
+	}
+
+	req.Symbol = "ETH-GBP"
+	if err := manager.ValidateOrder(req, nil); err == nil {
+		t.Error("expected an order without a conversion rate to be rejected")
+	}
+}
+
+func TestRecordTrade_ConvertsPnL(t *testing.T) {
+	manager := NewManager(DefaultConfig(), decimal.NewFromInt(10000))
+	manager.SetCurrencyConverter(fixedRates{"EUR": decimal.NewFromFloat(1.2)})
+
+	manager.RecordTrade(TradeResult{Symbol: "BTC-EUR", PnL: decimal.NewFromInt(100), IsWin: true})
+	if pnl := manager.GetDailyPnL(); !pnl.Equal(decimal.NewFromInt(120)) {
+		t.Errorf("expected a daily PnL of 120 USD, got %s", pnl)
+	}
+}
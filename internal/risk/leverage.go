@@ -0,0 +1,54 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+// ErrLeverageMismatch is returned when the leverage set on the venue differs
+// from the configured MaxLeverage
+var ErrLeverageMismatch = errors.New("leverage mismatch")
+
+// ApplyLeverage sets the configured margin mode and MaxLeverage on exchange
+// for every symbol, then reads them back so the risk limits match what the
+// venue actually applies. It does nothing when no margin mode is configured
+// or the exchange does not trade on margin.
+func ApplyLeverage(ctx context.Context, exchange exchanges.Exchange, config *Config, symbols []string) error {
+	if config.MarginMode == "" || !exchange.Capabilities().Margin {
+		return nil
+	}
+
+	var errs []error
+	for _, symbol := range symbols {
+		if err := applySymbolLeverage(ctx, exchange, config, symbol); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func applySymbolLeverage(ctx context.Context, exchange exchanges.Exchange, config *Config, symbol string) error {
+	if err := exchange.SetMarginMode(ctx, symbol, config.MarginMode); err != nil {
+		return fmt.Errorf("failed to set margin mode: %w", err)
+	}
+	if err := exchange.SetLeverage(ctx, symbol, config.MaxLeverage); err != nil {
+		return fmt.Errorf("failed to set leverage: %w", err)
+	}
+
+	settings, err := exchange.GetLeverage(ctx, symbol)
+	if err != nil {
+		return fmt.Errorf("failed to read leverage: %w", err)
+	}
+	if !settings.Leverage.Equal(config.MaxLeverage) {
+		return fmt.Errorf("%w: venue applies %sx, MaxLeverage is %sx",
+			ErrLeverageMismatch, settings.Leverage, config.MaxLeverage)
+	}
+	if settings.MarginMode != config.MarginMode {
+		return fmt.Errorf("%w: venue uses %s margin, configured %s",
+			ErrLeverageMismatch, settings.MarginMode, config.MarginMode)
+	}
+	return nil
+}
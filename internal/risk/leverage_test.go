@@ -0,0 +1,58 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+// cappedExchange silently caps the leverage it applies, like a venue
+// lowering the leverage of a market
+type cappedExchange struct {
+	*testutils.TestExchange
+	limit decimal.Decimal
+}
+
+func (c *cappedExchange) SetLeverage(ctx context.Context, symbol string, leverage decimal.Decimal) error {
+	return c.TestExchange.SetLeverage(ctx, symbol, decimal.Min(leverage, c.limit))
+}
+
+func TestApplyLeverage(t *testing.T) {
+	config := DefaultConfig()
+	config.MarginMode = exchanges.MarginModeIsolated
+	symbols := []string{"BTC-USD", "ETH-USD"}
+	ctx := context.Background()
+
+	exchange := testutils.NewTestExchange("venue")
+	if err := ApplyLeverage(ctx, exchange, config, symbols); err != nil {
+		t.Fatalf("ApplyLeverage failed: %v", err)
+	}
+	for _, symbol := range symbols {
+		settings, _ := exchange.GetLeverage(ctx, symbol)
+		if !settings.Leverage.Equal(config.MaxLeverage) || settings.MarginMode != exchanges.MarginModeIsolated {
+			t.Errorf("%s: expected %sx isolated, got %sx %s", symbol, config.MaxLeverage, settings.Leverage, settings.MarginMode)
+		}
+	}
+
+	capped := &cappedExchange{TestExchange: testutils.NewTestExchange("capped"), limit: decimal.NewFromInt(3)}
+	err := ApplyLeverage(ctx, capped, config, symbols)
+	if !errors.Is(err, ErrLeverageMismatch) {
+		t.Errorf("Expected a leverage mismatch when the venue caps leverage, got %v", err)
+	}
+
+	// Venues without margin and an unset margin mode are left alone
+	spot := testutils.NewTestExchange("spot")
+	spot.CapabilitiesValue.Margin = false
+	spot.LeverageError = errors.New("should not be called")
+	if err := ApplyLeverage(ctx, spot, config, symbols); err != nil {
+		t.Errorf("Expected venues without margin to be skipped, got %v", err)
+	}
+	config.MarginMode = ""
+	if err := ApplyLeverage(ctx, capped, config, symbols); err != nil {
+		t.Errorf("Expected leverage to be unmanaged without a margin mode, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package risk
+
+import (
+	"context"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// LiquidationRisk is how close a position is to being liquidated
+type LiquidationRisk struct {
+	Symbol           string
+	Side             order.PositionSide
+	LiquidationPrice decimal.Decimal
+	Distance         decimal.Decimal // Distance from the mark to the liquidation price, in percent of the mark
+	// BufferUsed is the share of the distance from entry to liquidation
+	// lost: 0 at or above the entry price of a long, 1 at the liquidation
+	// price. It is a price distance, not the margin ratio of the account.
+	BufferUsed decimal.Decimal
+	Deleverage bool // The position should be partially closed
+}
+
+// LiquidationDistance returns the distance from mark to liquidation in
+// percent of mark
+func LiquidationDistance(mark, liquidation decimal.Decimal) decimal.Decimal {
+	if !mark.IsPositive() {
+		return decimal.Zero
+	}
+	return mark.Sub(liquidation).Abs().Div(mark).Mul(decimal.NewFromInt(100))
+}
+
+// bufferUsed returns the adverse move from entry to mark relative to the
+// distance from entry to liquidation
+func bufferUsed(position *order.ManagedPosition) decimal.Decimal {
+	buffer := position.EntryPrice.Sub(position.LiquidationPrice)
+	loss := position.EntryPrice.Sub(position.CurrentPrice)
+	if position.Side == order.PositionSideShort {
+		buffer = buffer.Neg()
+		loss = loss.Neg()
+	}
+	if !buffer.IsPositive() || loss.IsNegative() {
+		return decimal.Zero
+	}
+	return loss.Div(buffer)
+}
+
+// CheckLiquidation returns the liquidation risk of position, or nil when the
+// exchange reports no liquidation price or the mark price is unknown. The
+// position should be deleveraged once the share of its buffer used reaches
+// DeleverageBufferUsed, at most once per DeleverageCooldown.
+func (m *Manager) CheckLiquidation(position *order.ManagedPosition, now time.Time) *LiquidationRisk {
+	if !position.LiquidationPrice.IsPositive() || !position.CurrentPrice.IsPositive() {
+		return nil
+	}
+
+	risk := &LiquidationRisk{
+		Symbol:           position.Symbol,
+		Side:             position.Side,
+		LiquidationPrice: position.LiquidationPrice,
+		Distance:         LiquidationDistance(position.CurrentPrice, position.LiquidationPrice),
+		BufferUsed:       bufferUsed(position),
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	threshold := m.config.DeleverageBufferUsed
+	if !threshold.IsPositive() || risk.BufferUsed.LessThan(threshold) {
+		return risk
+	}
+	if activity, ok := m.symbols[position.Symbol]; ok && now.Sub(activity.deleveragedAt) < m.config.DeleverageCooldown {
+		return risk
+	}
+	risk.Deleverage = true
+	return risk
+}
+
+// DefendLiquidations checks every open position of orders and partially
+// closes the ones that used up more of their buffer than the deleverage
+// threshold. It
+// returns the positions at risk it found.
+func (m *Manager) DefendLiquidations(ctx context.Context, orders *order.Manager) []LiquidationRisk {
+	log := logger.Component("risk")
+	now := time.Now()
+
+	m.mu.RLock()
+	fraction := m.config.DeleverageFraction
+	m.mu.RUnlock()
+
+	var risks []LiquidationRisk
+	for _, position := range orders.GetPositions() {
+		risk := m.CheckLiquidation(position, now)
+		if risk == nil {
+			continue
+		}
+		distance, _ := risk.Distance.Float64()
+		used, _ := risk.BufferUsed.Float64()
+		telemetry.RecordPositionUpdate(position.Symbol, "liquidation_distance", distance)
+		telemetry.RecordPositionUpdate(position.Symbol, "liquidation_buffer_used", used)
+
+		if risk.Deleverage {
+			if _, err := orders.ReducePositionSide(ctx, position.Symbol, position.Side, fraction); err != nil {
+				log.Error("failed to deleverage position",
+					"symbol", position.Symbol,
+					"side", position.Side,
+					"buffer_used", risk.BufferUsed.StringFixed(2),
+					"error", err)
+				risk.Deleverage = false
+			} else {
+				m.mu.Lock()
+				m.activity(position.Symbol).deleveragedAt = now
+				m.mu.Unlock()
+			}
+		}
+		risks = append(risks, *risk)
+	}
+	return risks
+}
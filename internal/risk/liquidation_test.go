@@ -0,0 +1,85 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+func TestCheckLiquidation(t *testing.T) {
+	manager := NewManager(DefaultConfig(), decimal.NewFromInt(10000))
+	now := time.Now()
+
+	long := &order.ManagedPosition{
+		Symbol:           "BTC-USD",
+		Side:             order.PositionSideLong,
+		EntryPrice:       decimal.NewFromInt(100),
+		CurrentPrice:     decimal.NewFromInt(95),
+		LiquidationPrice: decimal.NewFromInt(90),
+	}
+	risk := manager.CheckLiquidation(long, now)
+	if risk == nil {
+		t.Fatal("expected a liquidation risk for a position with a liquidation price")
+	}
+	if !risk.BufferUsed.Equal(decimal.NewFromFloat(0.5)) {
+		t.Errorf("expected half the buffer used, got %s", risk.BufferUsed)
+	}
+	if !risk.Distance.Round(4).Equal(decimal.NewFromFloat(5.2632)) {
+		t.Errorf("expected a distance of 5.26%%, got %s", risk.Distance)
+	}
+	if risk.Deleverage {
+		t.Error("expected no deleverage below the threshold")
+	}
+
+	short := &order.ManagedPosition{
+		Symbol:           "ETH-USD",
+		Side:             order.PositionSideShort,
+		EntryPrice:       decimal.NewFromInt(100),
+		CurrentPrice:     decimal.NewFromInt(109),
+		LiquidationPrice: decimal.NewFromInt(110),
+	}
+	risk = manager.CheckLiquidation(short, now)
+	if !risk.BufferUsed.Equal(decimal.NewFromFloat(0.9)) || !risk.Deleverage {
+		t.Errorf("expected a short with 0.9 of its buffer used to be deleveraged, got %s", risk.BufferUsed)
+	}
+
+	// A position in profit uses none of its margin buffer
+	short.CurrentPrice = decimal.NewFromInt(95)
+	if risk = manager.CheckLiquidation(short, now); !risk.BufferUsed.IsZero() || risk.Deleverage {
+		t.Errorf("expected a winning position to use none of its buffer, got %s", risk.BufferUsed)
+	}
+
+	long.LiquidationPrice = decimal.Zero
+	if manager.CheckLiquidation(long, now) != nil {
+		t.Error("expected no liquidation risk without a liquidation price")
+	}
+}
+
+func TestCheckLiquidation_Cooldown(t *testing.T) {
+	config := DefaultConfig()
+	config.DeleverageCooldown = time.Minute
+	manager := NewManager(config, decimal.NewFromInt(10000))
+	now := time.Now()
+
+	position := &order.ManagedPosition{
+		Symbol:           "BTC-USD",
+		Side:             order.PositionSideLong,
+		EntryPrice:       decimal.NewFromInt(100),
+		CurrentPrice:     decimal.NewFromInt(91),
+		LiquidationPrice: decimal.NewFromInt(90),
+	}
+	manager.activity("BTC-USD").deleveragedAt = now.Add(-30 * time.Second)
+	if manager.CheckLiquidation(position, now).Deleverage {
+		t.Error("expected no deleverage during the cooldown")
+	}
+	if !manager.CheckLiquidation(position, now.Add(time.Minute)).Deleverage {
+		t.Error("expected a deleverage after the cooldown")
+	}
+
+	manager.config.DeleverageBufferUsed = decimal.Zero
+	if manager.CheckLiquidation(position, now.Add(time.Minute)).Deleverage {
+		t.Error("expected a zero threshold to disable deleveraging")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -18,15 +19,54 @@ type Config struct {
 	MaxPositions         int             // Maximum number of concurrent positions
 	MaxLeverage          decimal.Decimal // Maximum leverage allowed
 	MaxDailyLoss         decimal.Decimal // Maximum daily loss (in base currency)
+	MaxWeeklyLoss        decimal.Decimal // Maximum loss over the weekly session (0 disables)
 	MaxDrawdown          decimal.Decimal // Maximum drawdown percentage
 	RiskPerTrade         decimal.Decimal // Risk per trade as percentage of capital
 	MinAccountBalance    decimal.Decimal // Minimum account balance to trade
 	DailyTradingLimit    int             // Maximum trades per day
 	CooldownPeriod       time.Duration   // Cooldown period after consecutive losses
 	ConsecutiveLossLimit int             // Number of consecutive losses to trigger cooldown
+	// Daily sessions start at SessionResetTime in SessionTimezone, weekly
+	// sessions at the start of the Monday session
+	SessionTimezone  *time.Location // (default: UTC)
+	SessionResetTime time.Duration  // Time of day, e.g. 17h for 17:00 (default: midnight)
 	// Position correlation limits
 	MaxExposurePerSymbol   decimal.Decimal // Maximum exposure per symbol as percentage of balance (default: 30%)
 	MaxSameSymbolPositions int             // Maximum number of positions for the same symbol (default: 2)
+	// Anti-churn limits per symbol (0 disables a limit)
+	MinEntryInterval       time.Duration // Minimum time between entries on a symbol
+	MaxSymbolTradesPerHour int           // Maximum entries on a symbol over the last hour
+	StopOutBlackout        time.Duration // No re-entry on a symbol for this long after a stop-out
+	// Scheduled economic and news events
+	EventFeedPath        string          // CSV file listing events
+	EventFeedURL         string          // API returning events as JSON, used when no file is set
+	EventRefreshInterval time.Duration   // How often the feed is reloaded (default: 1h)
+	EventPauseBefore     time.Duration   // Window before an event (default: 15m)
+	EventPauseAfter      time.Duration   // Window after an event (default: 15m)
+	EventMinImpact       EventImpact     // Least impactful events acted on (default: high)
+	EventAction          EventAction     // pause or widen (default: pause)
+	EventStopWidenFactor decimal.Decimal // Stop distance multiplier for the widen action (default: 2)
+	// Pre-liquidation defense: positions are partially closed once their
+	// share of buffer used reaches DeleverageBufferUsed (0 disables)
+	DeleverageBufferUsed decimal.Decimal // Share of the distance from entry to liquidation lost, from 0 at entry to 1 at liquidation (default: 0.8)
+	DeleverageFraction   decimal.Decimal // Fraction of the position closed each time (default: 0.5)
+	DeleverageCooldown   time.Duration   // Minimum time between two deleverages of a position (default: 1m)
+	// Unexplained balance changes of at least TransferThreshold percent of the
+	// balance are deposits or withdrawals; smaller ones are funding and fees
+	TransferThreshold decimal.Decimal // (default: 1%)
+	// A change is only classified once it stayed unexplained for
+	// TransferSettleDelay, so that the PnL of trades the venue settled
+	// before the bot recorded them nets it out
+	TransferSettleDelay time.Duration // (default: 2m)
+	// RiskPerTrade is multiplied by DrawdownThrottleFactor for every
+	// DrawdownThrottleStep percent of drawdown, down to
+	// DrawdownThrottleMinScale, and restored as the drawdown recovers
+	DrawdownThrottleStep     decimal.Decimal // (0 disables)
+	DrawdownThrottleFactor   decimal.Decimal // (default: 0.5)
+	DrawdownThrottleMinScale decimal.Decimal // (default: 0.25)
+	// MarginMode is set on the venue with MaxLeverage for every traded symbol
+	// at startup; empty leaves the venue's settings unmanaged
+	MarginMode exchanges.MarginMode
 }
 
 // DefaultConfig returns default risk management configuration
@@ -42,8 +82,23 @@ func DefaultConfig() *Config {
 		DailyTradingLimit:      50,
 		CooldownPeriod:         15 * time.Minute,
 		ConsecutiveLossLimit:   3,
+		SessionTimezone:        time.UTC,
 		MaxExposurePerSymbol:   decimal.NewFromFloat(30), // 30% max exposure per symbol
 		MaxSameSymbolPositions: 2,                        // Max 2 positions per symbol
+		EventRefreshInterval:   time.Hour,
+		EventPauseBefore:       15 * time.Minute,
+		EventPauseAfter:        15 * time.Minute,
+		EventMinImpact:         EventImpactHigh,
+		EventAction:            EventActionPause,
+		EventStopWidenFactor:   decimal.NewFromInt(2),
+		DeleverageBufferUsed:   decimal.NewFromFloat(0.8),
+		DeleverageFraction:     decimal.NewFromFloat(0.5),
+		DeleverageCooldown:     time.Minute,
+		TransferThreshold:      decimal.NewFromFloat(1),
+		TransferSettleDelay:    2 * time.Minute,
+
+		DrawdownThrottleFactor:   decimal.NewFromFloat(0.5),
+		DrawdownThrottleMinScale: decimal.NewFromFloat(0.25),
 	}
 }
 
@@ -76,6 +131,24 @@ func LoadConfig() *Config {
 		}
 	}
 
+	if val := os.Getenv("RISK_MAX_WEEKLY_LOSS"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && !parsed.IsNegative() {
+			config.MaxWeeklyLoss = parsed
+		}
+	}
+
+	if val := os.Getenv("RISK_SESSION_TIMEZONE"); val != "" {
+		if loc, err := time.LoadLocation(val); err == nil {
+			config.SessionTimezone = loc
+		}
+	}
+
+	if val := os.Getenv("RISK_SESSION_RESET_TIME"); val != "" {
+		if parsed, err := parseSessionTime(val); err == nil {
+			config.SessionResetTime = parsed
+		}
+	}
+
 	if val := os.Getenv("RISK_MAX_DRAWDOWN"); val != "" {
 		if parsed, err := decimal.NewFromString(val); err == nil {
 			config.MaxDrawdown = parsed
@@ -88,6 +161,24 @@ func LoadConfig() *Config {
 		}
 	}
 
+	if val := os.Getenv("RISK_DRAWDOWN_THROTTLE_STEP"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && !parsed.IsNegative() {
+			config.DrawdownThrottleStep = parsed
+		}
+	}
+
+	if val := os.Getenv("RISK_DRAWDOWN_THROTTLE_FACTOR"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && parsed.IsPositive() && parsed.LessThan(decimal.NewFromInt(1)) {
+			config.DrawdownThrottleFactor = parsed
+		}
+	}
+
+	if val := os.Getenv("RISK_DRAWDOWN_THROTTLE_MIN_SCALE"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && parsed.IsPositive() && parsed.LessThanOrEqual(decimal.NewFromInt(1)) {
+			config.DrawdownThrottleMinScale = parsed
+		}
+	}
+
 	if val := os.Getenv("RISK_MIN_ACCOUNT_BALANCE"); val != "" {
 		if parsed, err := decimal.NewFromString(val); err == nil {
 			config.MinAccountBalance = parsed
@@ -124,6 +215,96 @@ func LoadConfig() *Config {
 		}
 	}
 
+	if val := os.Getenv("RISK_MIN_ENTRY_INTERVAL_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+			config.MinEntryInterval = time.Duration(parsed) * time.Second
+		}
+	}
+
+	if val := os.Getenv("RISK_MAX_SYMBOL_TRADES_PER_HOUR"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+			config.MaxSymbolTradesPerHour = parsed
+		}
+	}
+
+	if val := os.Getenv("RISK_STOP_OUT_BLACKOUT_MINUTES"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+			config.StopOutBlackout = time.Duration(parsed) * time.Minute
+		}
+	}
+
+	config.EventFeedPath = os.Getenv("RISK_EVENT_FEED_FILE")
+	config.EventFeedURL = os.Getenv("RISK_EVENT_FEED_URL")
+
+	minuteOverrides := map[string]*time.Duration{
+		"RISK_EVENT_REFRESH_MINUTES":      &config.EventRefreshInterval,
+		"RISK_EVENT_PAUSE_BEFORE_MINUTES": &config.EventPauseBefore,
+		"RISK_EVENT_PAUSE_AFTER_MINUTES":  &config.EventPauseAfter,
+	}
+	for key, target := range minuteOverrides {
+		if val := os.Getenv(key); val != "" {
+			if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+				*target = time.Duration(parsed) * time.Minute
+			}
+		}
+	}
+
+	if val := os.Getenv("RISK_EVENT_MIN_IMPACT"); val != "" {
+		if impact := EventImpact(strings.ToLower(val)); impact.rank() > 0 {
+			config.EventMinImpact = impact
+		}
+	}
+
+	if val := os.Getenv("RISK_EVENT_ACTION"); val != "" {
+		switch action := EventAction(strings.ToLower(val)); action {
+		case EventActionPause, EventActionWiden:
+			config.EventAction = action
+		}
+	}
+
+	if val := os.Getenv("RISK_DELEVERAGE_BUFFER_USED"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && !parsed.IsNegative() && parsed.LessThan(decimal.NewFromInt(1)) {
+			config.DeleverageBufferUsed = parsed
+		}
+	}
+
+	if val := os.Getenv("RISK_DELEVERAGE_FRACTION"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && parsed.IsPositive() && parsed.LessThanOrEqual(decimal.NewFromInt(1)) {
+			config.DeleverageFraction = parsed
+		}
+	}
+
+	if val := os.Getenv("RISK_DELEVERAGE_COOLDOWN_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+			config.DeleverageCooldown = time.Duration(parsed) * time.Second
+		}
+	}
+
+	if val := os.Getenv("RISK_TRANSFER_THRESHOLD_PERCENT"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && !parsed.IsNegative() {
+			config.TransferThreshold = parsed
+		}
+	}
+
+	if val := os.Getenv("RISK_TRANSFER_SETTLE_SECONDS"); val != "" {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed >= 0 {
+			config.TransferSettleDelay = time.Duration(parsed) * time.Second
+		}
+	}
+
+	if val := os.Getenv("RISK_MARGIN_MODE"); val != "" {
+		switch mode := exchanges.MarginMode(strings.ToLower(val)); mode {
+		case exchanges.MarginModeCross, exchanges.MarginModeIsolated:
+			config.MarginMode = mode
+		}
+	}
+
+	if val := os.Getenv("RISK_EVENT_STOP_WIDEN_FACTOR"); val != "" {
+		if parsed, err := decimal.NewFromString(val); err == nil && parsed.GreaterThan(decimal.NewFromInt(1)) {
+			config.EventStopWidenFactor = parsed
+		}
+	}
+
 	return config
 }
 
@@ -142,7 +323,29 @@ type Manager struct {
 	currentBalance      decimal.Decimal
 	peakBalance         decimal.Decimal
 	tradeHistory        []TradeResult
-	lastResetDate       time.Time
+	lastResetDate       time.Time // Start of the daily session
+	weeklyPnL           decimal.Decimal
+	weekStart           time.Time
+	sessionStore        SessionStore
+	appliedRiskScale    decimal.Decimal // Drawdown scale of the risk per trade last reported
+
+	// freeCollateral is the margin left for new positions on the exchanges,
+	// unchecked until the first UpdateFreeCollateral
+	freeCollateral      decimal.Decimal
+	freeCollateralKnown bool
+
+	symbols    map[string]*symbolActivity // Recent entries and stop-outs per symbol
+	events     *EventCalendar
+	fx         CurrencyConverter
+	openOrders OpenOrderSource // Resting entries whose margin is reserved
+
+	// Balance reconciliation against trade PnL
+	snapshot         *balanceSnapshot
+	pnlSinceSnapshot decimal.Decimal
+	unsettled        decimal.Decimal // Change unexplained for less than TransferSettleDelay
+	unsettledSince   time.Time
+	flows            []BalanceFlow
+	netDeposits      decimal.Decimal
 }
 
 // TradeResult represents the result of a trade
@@ -155,6 +358,11 @@ type TradeResult struct {
 	Amount     decimal.Decimal
 	PnL        decimal.Decimal
 	IsWin      bool
+	StopOut    bool   // Closed by the stop loss order
+	Strategy   string // Strategy instance that opened the position
+	// Liquidation is set when the venue liquidated or auto-deleveraged the
+	// position
+	Liquidation exchanges.LiquidationKind
 }
 
 // NewManager creates a new risk manager
@@ -167,13 +375,18 @@ func NewManager(config *Config, initialBalance decimal.Decimal) *Manager {
 		currentBalance:  initialBalance,
 		peakBalance:     initialBalance,
 		tradeHistory:    make([]TradeResult, 0),
-		lastResetDate:   now,
+		lastResetDate:   config.sessionStart(now),
+		weekStart:       config.weekStart(now),
 		lastTradeTime:   now,
+
+		appliedRiskScale: decimal.NewFromInt(1),
 	}
 }
 
 // CanTrade checks if trading is allowed based on risk parameters
 func (m *Manager) CanTrade() (bool, string) {
+	m.rollSession(time.Now())
+	reserved := m.ReservedCapital()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -188,6 +401,11 @@ func (m *Manager) CanTrade() (bool, string) {
 		return false, "daily loss limit reached"
 	}
 
+	// Check weekly loss limit
+	if m.config.MaxWeeklyLoss.IsPositive() && m.weeklyPnL.LessThan(m.config.MaxWeeklyLoss.Neg()) {
+		return false, "weekly loss limit reached"
+	}
+
 	// Check daily trade limit
 	if m.tradesExecutedToday >= m.config.DailyTradingLimit {
 		return false, "daily trade limit reached"
@@ -198,6 +416,11 @@ func (m *Manager) CanTrade() (bool, string) {
 		return false, "account balance below minimum"
 	}
 
+	// Check the balance left once the resting entries are reserved
+	if reserved.IsPositive() && m.currentBalance.Sub(reserved).LessThan(m.config.MinAccountBalance) {
+		return false, fmt.Sprintf("capital reserved by open orders (%s) leaves the balance below minimum", reserved.StringFixed(2))
+	}
+
 	// Check maximum drawdown
 	drawdown := m.calculateDrawdown()
 	if drawdown.GreaterThan(m.config.MaxDrawdown) {
@@ -210,16 +433,23 @@ func (m *Manager) CanTrade() (bool, string) {
 
 // ValidateOrder validates an order against risk parameters
 func (m *Manager) ValidateOrder(req *order.OrderRequest, openPositions []*order.ManagedPosition) error {
+	reserved := m.ReservedCapital()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	// Adding to an existing position does not open a new one
+	addOn := isAddOn(req, openPositions)
+
 	// Check max positions
-	if len(openPositions) >= m.config.MaxPositions {
+	if !addOn && len(openPositions) >= m.config.MaxPositions {
 		return fmt.Errorf("maximum number of positions (%d) reached", m.config.MaxPositions)
 	}
 
-	// Check position size
-	positionSize := req.Amount.Mul(req.Price)
+	// Check position size, in the reporting currency
+	positionSize, err := m.toReporting(req.Amount.Mul(req.Price), req.Symbol)
+	if err != nil {
+		return err
+	}
 	if positionSize.GreaterThan(m.config.MaxPositionSize) {
 		positionSizeFloat, _ := positionSize.Float64()
 		maxSizeFloat, _ := m.config.MaxPositionSize.Float64()
@@ -227,8 +457,21 @@ func (m *Manager) ValidateOrder(req *order.OrderRequest, openPositions []*order.
 			positionSizeFloat, maxSizeFloat)
 	}
 
+	// Check the margin the order holds against the buying power left
+	if err := m.validateMargin(positionSize); err != nil {
+		return err
+	}
+	if err := m.validateReservation(positionSize, reserved); err != nil {
+		return err
+	}
+
 	// Check symbol correlation limits
-	if err := m.validateSymbolExposure(req, openPositions); err != nil {
+	if err := m.validateSymbolExposure(req, openPositions, addOn); err != nil {
+		return err
+	}
+
+	// Check per-symbol anti-churn limits
+	if err := m.validateSymbolActivity(req.Symbol, time.Now()); err != nil {
 		return err
 	}
 
@@ -243,8 +486,11 @@ func (m *Manager) ValidateOrder(req *order.OrderRequest, openPositions []*order.
 		return fmt.Errorf("stop loss must differ from entry price")
 	}
 
-	maxRisk := m.currentBalance.Mul(m.config.RiskPerTrade).Div(decimal.NewFromInt(100))
-	potentialLoss := priceDiff.Mul(req.Amount)
+	maxRisk := m.currentBalance.Mul(m.riskPerTrade()).Div(decimal.NewFromInt(100))
+	potentialLoss, err := m.toReporting(priceDiff.Mul(req.Amount), req.Symbol)
+	if err != nil {
+		return err
+	}
 	if potentialLoss.GreaterThan(maxRisk) {
 		potentialLossFloat, _ := potentialLoss.Float64()
 		maxRiskFloat, _ := maxRisk.Float64()
@@ -255,15 +501,54 @@ func (m *Manager) ValidateOrder(req *order.OrderRequest, openPositions []*order.
 	return nil
 }
 
+// validateMargin checks that the free collateral covers the initial margin
+// of an order of the given notional at MaxLeverage
+func (m *Manager) validateMargin(notional decimal.Decimal) error {
+	if !m.freeCollateralKnown {
+		return nil
+	}
+	required := notional.Div(m.leverage())
+	if required.GreaterThan(m.freeCollateral) {
+		return fmt.Errorf("order margin %s exceeds free collateral %s",
+			required.StringFixed(2), m.freeCollateral.StringFixed(2))
+	}
+	return nil
+}
+
+// isAddOn reports whether req adds to an open position on the same symbol and side
+func isAddOn(req *order.OrderRequest, openPositions []*order.ManagedPosition) bool {
+	side := positionSideFor(req.Side)
+	for _, pos := range openPositions {
+		if pos.Symbol == req.Symbol && pos.Side == side && pos.Status == order.PositionStatusOpen {
+			return true
+		}
+	}
+	return false
+}
+
+// positionSideFor returns the position side grown by an order side
+func positionSideFor(side exchanges.OrderSide) order.PositionSide {
+	if side == exchanges.OrderSideBuy {
+		return order.PositionSideLong
+	}
+	return order.PositionSideShort
+}
+
 // validateSymbolExposure checks if adding a new position would exceed symbol exposure limits
-func (m *Manager) validateSymbolExposure(req *order.OrderRequest, openPositions []*order.ManagedPosition) error {
-	// Count positions for the same symbol
+func (m *Manager) validateSymbolExposure(req *order.OrderRequest, openPositions []*order.ManagedPosition, addOn bool) error {
+	// Count positions for the same symbol. Exposure is measured per leg: only
+	// positions on the side of the order grow with it, so a hedged long and
+	// short are checked independently.
 	sameSymbolCount := 0
 	totalExposure := decimal.Zero
+	side := positionSideFor(req.Side)
 
 	for _, pos := range openPositions {
 		if pos.Symbol == req.Symbol {
 			sameSymbolCount++
+			if pos.Side != side {
+				continue
+			}
 			// Calculate position value
 			posValue := pos.Amount.Mul(pos.EntryPrice)
 			totalExposure = totalExposure.Add(posValue)
@@ -271,14 +556,17 @@ func (m *Manager) validateSymbolExposure(req *order.OrderRequest, openPositions
 	}
 
 	// Check max positions per symbol
-	if sameSymbolCount >= m.config.MaxSameSymbolPositions {
+	if !addOn && sameSymbolCount >= m.config.MaxSameSymbolPositions {
 		return fmt.Errorf("maximum positions for symbol %s (%d) reached",
 			req.Symbol, m.config.MaxSameSymbolPositions)
 	}
 
 	// Check total exposure for this symbol
 	newPositionValue := req.Amount.Mul(req.Price)
-	totalExposureWithNew := totalExposure.Add(newPositionValue)
+	totalExposureWithNew, err := m.toReporting(totalExposure.Add(newPositionValue), req.Symbol)
+	if err != nil {
+		return err
+	}
 
 	maxExposure := m.currentBalance.Mul(m.config.MaxExposurePerSymbol).Div(decimal.NewFromInt(100))
 	if totalExposureWithNew.GreaterThan(maxExposure) {
@@ -298,11 +586,20 @@ func (m *Manager) CalculatePositionSize(
 	stopLoss decimal.Decimal,
 	accountBalance decimal.Decimal,
 ) decimal.Decimal {
+	reserved := m.ReservedCapital()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	// Size from the capital not already reserved by resting entries
+	if reserved.IsPositive() {
+		available := decimal.Max(m.currentBalance.Sub(reserved), decimal.Zero)
+		if available.LessThan(accountBalance) {
+			accountBalance = available
+		}
+	}
+
 	// Calculate risk amount
-	riskAmount := accountBalance.Mul(m.config.RiskPerTrade).Div(decimal.NewFromInt(100))
+	riskAmount := accountBalance.Mul(m.riskPerTrade()).Div(decimal.NewFromInt(100))
 
 	// Calculate price difference
 	priceDiff := entryPrice.Sub(stopLoss).Abs()
@@ -324,14 +621,26 @@ func (m *Manager) CalculatePositionSize(
 
 // RecordTrade records a trade result and updates statistics
 func (m *Manager) RecordTrade(result TradeResult) {
+	defer m.saveSession()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// A trade closed in a new session counts towards it
+	m.checkSessionReset(time.Now())
+
+	// Account for the PnL in the reporting currency, as is when no rate is
+	// known yet
+	if pnl, err := m.toReporting(result.PnL, result.Symbol); err == nil {
+		result.PnL = pnl
+	}
+
 	// Add to trade history
 	m.tradeHistory = append(m.tradeHistory, result)
 
 	// Update daily PnL
 	m.dailyPnL = m.dailyPnL.Add(result.PnL)
+	m.weeklyPnL = m.weeklyPnL.Add(result.PnL)
+	m.pnlSinceSnapshot = m.pnlSinceSnapshot.Add(result.PnL)
 
 	// Update balance
 	m.currentBalance = m.currentBalance.Add(result.PnL)
@@ -340,6 +649,7 @@ func (m *Manager) RecordTrade(result TradeResult) {
 	if m.currentBalance.GreaterThan(m.peakBalance) {
 		m.peakBalance = m.currentBalance
 	}
+	m.updateRiskScale()
 
 	// Update consecutive losses
 	if result.IsWin {
@@ -354,24 +664,49 @@ func (m *Manager) RecordTrade(result TradeResult) {
 		}
 	}
 
+	if result.StopOut {
+		m.recordStopOut(result)
+	}
+
 	// Update trade count
 	m.tradesExecutedToday++
 	m.lastTradeTime = time.Now()
-
-	// Check if we need to reset daily statistics
-	m.checkDailyReset()
 }
 
 // UpdateBalance updates the current account balance
 func (m *Manager) UpdateBalance(balance decimal.Decimal) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.setBalance(balance)
+}
 
+// setBalance updates the current balance and its peak. Must be called with
+// the lock held.
+func (m *Manager) setBalance(balance decimal.Decimal) {
 	m.currentBalance = balance
 
 	if balance.GreaterThan(m.peakBalance) {
 		m.peakBalance = balance
 	}
+	m.updateRiskScale()
+}
+
+// UpdateFreeCollateral updates the margin available for new positions across
+// the exchanges. Orders needing more initial margin are rejected.
+func (m *Manager) UpdateFreeCollateral(free decimal.Decimal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.freeCollateral = free
+	m.freeCollateralKnown = true
+}
+
+// GetFreeCollateral returns the margin available for new positions, and
+// whether it is known
+func (m *Manager) GetFreeCollateral() (decimal.Decimal, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.freeCollateral, m.freeCollateralKnown
 }
 
 // GetCurrentBalance returns the current account balance
@@ -437,20 +772,6 @@ func (m *Manager) GetDrawdown() decimal.Decimal {
 	return m.calculateDrawdown()
 }
 
-// checkDailyReset checks if daily statistics should be reset
-func (m *Manager) checkDailyReset() {
-	now := time.Now()
-	// Truncate to day boundaries for accurate comparison
-	lastResetDay := m.lastResetDate.Truncate(24 * time.Hour)
-	currentDay := now.Truncate(24 * time.Hour)
-
-	if currentDay.After(lastResetDay) {
-		m.dailyPnL = decimal.Zero
-		m.tradesExecutedToday = 0
-		m.lastResetDate = now
-	}
-}
-
 // GetStats returns risk management statistics
 func (m *Manager) GetStats() *Stats {
 	m.mu.RLock()
@@ -493,12 +814,15 @@ func (m *Manager) GetStats() *Stats {
 		NetPnL:              totalProfit.Add(totalLoss),
 		ProfitFactor:        profitFactor,
 		CurrentDrawdown:     m.calculateDrawdown(),
+		RiskScale:           m.riskScale(),
 		ConsecutiveLosses:   m.consecutiveLosses,
 		DailyPnL:            m.dailyPnL,
+		WeeklyPnL:           m.weeklyPnL,
 		TradesExecutedToday: m.tradesExecutedToday,
 		CurrentBalance:      m.currentBalance,
 		StartingBalance:     m.startingBalance,
 		PeakBalance:         m.peakBalance,
+		NetDeposits:         m.netDeposits,
 	}
 }
 
@@ -513,10 +837,13 @@ type Stats struct {
 	NetPnL              decimal.Decimal
 	ProfitFactor        float64
 	CurrentDrawdown     decimal.Decimal
+	RiskScale           decimal.Decimal // Fraction of RiskPerTrade taken at the current drawdown
 	ConsecutiveLosses   int
 	DailyPnL            decimal.Decimal
+	WeeklyPnL           decimal.Decimal
 	TradesExecutedToday int
 	CurrentBalance      decimal.Decimal
 	StartingBalance     decimal.Decimal
 	PeakBalance         decimal.Decimal
+	NetDeposits         decimal.Decimal // Deposits less withdrawals detected since startup
 }
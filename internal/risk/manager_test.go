@@ -153,6 +153,34 @@ func TestManager_ValidateOrder(t *testing.T) {
 	}
 }
 
+func TestManager_ValidateOrderAddOn(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxPositions = 1
+	config.MaxSameSymbolPositions = 1
+	manager := NewManager(config, decimal.NewFromFloat(10000))
+
+	req := &order.OrderRequest{
+		Symbol:   "BTC-USD",
+		Side:     exchanges.OrderSideBuy,
+		Type:     exchanges.OrderTypeLimit,
+		Price:    decimal.NewFromFloat(50000),
+		Amount:   decimal.NewFromFloat(0.005),
+		StopLoss: decimal.NewFromFloat(49500),
+	}
+	openPositions := []*order.ManagedPosition{
+		{Symbol: "BTC-USD", Side: order.PositionSideLong, Status: order.PositionStatusOpen, Amount: decimal.NewFromFloat(0.005), EntryPrice: decimal.NewFromFloat(49000)},
+	}
+
+	if err := manager.ValidateOrder(req, openPositions); err != nil {
+		t.Errorf("adding to an open position should not count as a new position, got: %v", err)
+	}
+
+	req.Side = exchanges.OrderSideSell
+	if err := manager.ValidateOrder(req, openPositions); err == nil {
+		t.Error("opposite side order should count as a new position")
+	}
+}
+
 func TestManager_RecordTrade(t *testing.T) {
 	config := DefaultConfig()
 	initialBalance := decimal.NewFromFloat(10000)
@@ -340,3 +368,33 @@ func TestLoadConfig(t *testing.T) {
 	os.Unsetenv("RISK_MAX_POSITIONS")
 	os.Unsetenv("RISK_MAX_POSITION_SIZE")
 }
+
+func TestValidateOrder_FreeCollateral(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxLeverage = decimal.NewFromInt(2)
+	config.MaxPositionSize = decimal.NewFromInt(100000)
+	config.MaxExposurePerSymbol = decimal.NewFromInt(100)
+	config.RiskPerTrade = decimal.NewFromInt(10)
+	manager := NewManager(config, decimal.NewFromInt(10000))
+
+	req := &order.OrderRequest{
+		Symbol:   "BTC-USD",
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Amount:   decimal.NewFromInt(50), // 5000 notional, 2500 margin at 2x
+		StopLoss: decimal.NewFromInt(99),
+	}
+	if err := manager.ValidateOrder(req, nil); err != nil {
+		t.Fatalf("expected no margin check before the free collateral is known, got %v", err)
+	}
+
+	manager.UpdateFreeCollateral(decimal.NewFromInt(2000))
+	if err := manager.ValidateOrder(req, nil); err == nil {
+		t.Error("expected an order needing more margin than the free collateral to be rejected")
+	}
+
+	manager.UpdateFreeCollateral(decimal.NewFromInt(3000))
+	if err := manager.ValidateOrder(req, nil); err != nil {
+		t.Errorf("expected the order to fit the free collateral, got %v", err)
+	}
+}
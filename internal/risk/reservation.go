@@ -0,0 +1,86 @@
+package risk
+
+import (
+	"fmt"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// OpenOrderSource lists the entry orders resting on the exchanges, leaving
+// out the reduce-only stop loss and take profit orders
+type OpenOrderSource interface {
+	GetOpenEntryOrders() []*exchanges.Order
+}
+
+// SetOpenOrderSource reserves the initial margin of the resting entry orders
+// of source: it is deducted from the balance new entries are checked and
+// sized against, so that orders filling together stay within the account
+func (m *Manager) SetOpenOrderSource(source OpenOrderSource) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.openOrders = source
+}
+
+// ReservedCapital returns the initial margin, at MaxLeverage, held by the
+// unfilled part of the resting entry orders
+func (m *Manager) ReservedCapital() decimal.Decimal {
+	m.mu.RLock()
+	source := m.openOrders
+	m.mu.RUnlock()
+	if source == nil {
+		return decimal.Zero
+	}
+	// Listed without the lock: the source has its own
+	orders := source.GetOpenEntryOrders()
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.reservedMargin(orders)
+}
+
+// reservedMargin sums the margin held by orders. Must be called with the lock
+// held.
+func (m *Manager) reservedMargin(orders []*exchanges.Order) decimal.Decimal {
+	reserved := decimal.Zero
+	for _, o := range orders {
+		remaining := o.Amount.Sub(o.Filled)
+		if o.Remaining.IsPositive() {
+			remaining = o.Remaining
+		}
+		if !remaining.IsPositive() || !o.Price.IsPositive() {
+			continue
+		}
+		// Orders without a conversion rate yet are reserved as quoted
+		notional := remaining.Mul(o.Price)
+		if converted, err := m.toReporting(notional, o.Symbol); err == nil {
+			notional = converted
+		}
+		reserved = reserved.Add(notional)
+	}
+	return reserved.Div(m.leverage())
+}
+
+// leverage returns MaxLeverage, at least 1. Must be called with the lock held.
+func (m *Manager) leverage() decimal.Decimal {
+	if m.config.MaxLeverage.LessThan(decimal.NewFromInt(1)) {
+		return decimal.NewFromInt(1)
+	}
+	return m.config.MaxLeverage
+}
+
+// validateReservation checks that the initial margin of an order of the given
+// notional fits in the balance not reserved by resting entries. Must be called
+// with the lock held.
+func (m *Manager) validateReservation(notional, reserved decimal.Decimal) error {
+	if !reserved.IsPositive() {
+		return nil
+	}
+	available := m.currentBalance.Sub(reserved)
+	required := notional.Div(m.leverage())
+	if required.GreaterThan(available) {
+		return fmt.Errorf("order margin %s exceeds capital not reserved by open orders %s",
+			required.StringFixed(2), available.StringFixed(2))
+	}
+	return nil
+}
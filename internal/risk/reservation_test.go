@@ -0,0 +1,67 @@
+package risk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// restingOrders is a fixed set of resting entry orders
+type restingOrders []*exchanges.Order
+
+func (o restingOrders) GetOpenEntryOrders() []*exchanges.Order {
+	return o
+}
+
+func TestManager_ReservedCapital(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxLeverage = decimal.NewFromInt(2)
+	config.MaxExposurePerSymbol = decimal.NewFromInt(100)
+	manager := NewManager(config, decimal.NewFromInt(1000))
+
+	if !manager.ReservedCapital().IsZero() {
+		t.Fatal("expected nothing reserved without an order source")
+	}
+
+	// 400 of notional left on the partially filled BTC entry and 1000 on
+	// the ETH entry hold 700 of margin at 2x
+	manager.SetOpenOrderSource(restingOrders{
+		{Symbol: "BTC-USD", Side: exchanges.OrderSideBuy, Price: decimal.NewFromInt(50000), Amount: decimal.NewFromFloat(0.01), Filled: decimal.NewFromFloat(0.002)},
+		{Symbol: "ETH-USD", Side: exchanges.OrderSideSell, Price: decimal.NewFromInt(1000), Amount: decimal.NewFromInt(1)},
+	})
+	if reserved := manager.ReservedCapital(); !reserved.Equal(decimal.NewFromInt(700)) {
+		t.Fatalf("expected 700 reserved, got %s", reserved)
+	}
+
+	// Sized from the 300 left: 1% risk over a 1 stop distance
+	size := manager.CalculatePositionSize(decimal.NewFromInt(100), decimal.NewFromInt(99), decimal.NewFromInt(1000))
+	if !size.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("expected a size of 3 from the unreserved capital, got %s", size)
+	}
+
+	req := &order.OrderRequest{
+		Symbol:   "SOL-USD",
+		Side:     exchanges.OrderSideBuy,
+		Price:    decimal.NewFromInt(100),
+		Amount:   decimal.NewFromInt(10),
+		StopLoss: decimal.NewFromInt(99),
+	}
+	if err := manager.ValidateOrder(req, nil); err == nil || !strings.Contains(err.Error(), "reserved") {
+		t.Errorf("expected 500 of margin to exceed the 300 unreserved, got %v", err)
+	}
+	req.Amount = decimal.NewFromInt(5)
+	if err := manager.ValidateOrder(req, nil); err != nil {
+		t.Errorf("expected an order within the unreserved capital to pass, got %v", err)
+	}
+
+	if ok, _ := manager.CanTrade(); !ok {
+		t.Error("expected trading allowed with 300 unreserved")
+	}
+	config.MinAccountBalance = decimal.NewFromInt(400)
+	if ok, reason := manager.CanTrade(); ok || !strings.Contains(reason, "reserved") {
+		t.Errorf("expected trading stopped below the minimum once orders are reserved, got %q", reason)
+	}
+}
@@ -0,0 +1,211 @@
+package risk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/statefile"
+	"github.com/shopspring/decimal"
+)
+
+// SessionState holds the counters of the current daily and weekly sessions,
+// persisted so a restart does not hand back the loss budget
+type SessionState struct {
+	SessionStart      time.Time       `json:"session_start"`
+	WeekStart         time.Time       `json:"week_start"`
+	DailyPnL          decimal.Decimal `json:"daily_pnl"`
+	WeeklyPnL         decimal.Decimal `json:"weekly_pnl"`
+	TradesToday       int             `json:"trades_today"`
+	ConsecutiveLosses int             `json:"consecutive_losses"`
+	CooldownUntil     time.Time       `json:"cooldown_until,omitempty"`
+}
+
+// SessionStore persists the session counters
+type SessionStore interface {
+	Load() (*SessionState, error)
+	Save(state SessionState) error
+}
+
+// FileSessionStore keeps the session counters in a JSON file, replaced
+// atomically on every save
+type FileSessionStore struct {
+	path string
+}
+
+// NewFileSessionStore creates a store backed by the file at path
+func NewFileSessionStore(path string) *FileSessionStore {
+	return &FileSessionStore{path: path}
+}
+
+// Load returns the stored counters, or nil if the file does not exist yet
+func (s *FileSessionStore) Load() (*SessionState, error) {
+	data, err := statefile.Read(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read risk session: %w", err)
+	}
+
+	var state SessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse risk session: %w", err)
+	}
+	return &state, nil
+}
+
+// Save replaces the stored counters
+func (s *FileSessionStore) Save(state SessionState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode risk session: %w", err)
+	}
+
+	if err := statefile.Write(s.path, data); err != nil {
+		return fmt.Errorf("failed to save risk session: %w", err)
+	}
+	return nil
+}
+
+// parseSessionTime parses the HH:MM time of day sessions start at
+func parseSessionTime(value string) (time.Duration, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid session time %q, expected HH:MM", value)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil || h < 0 || h > 23 {
+		return 0, fmt.Errorf("invalid session hour in %q", value)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 || m > 59 {
+		return 0, fmt.Errorf("invalid session minute in %q", value)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// sessionStart returns the start of the daily session containing t: the
+// last SessionResetTime at or before t in SessionTimezone
+func (c *Config) sessionStart(t time.Time) time.Time {
+	loc := c.SessionTimezone
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := t.In(loc)
+	hour := int(c.SessionResetTime / time.Hour)
+	minute := int(c.SessionResetTime % time.Hour / time.Minute)
+	start := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if start.After(local) {
+		start = time.Date(local.Year(), local.Month(), local.Day()-1, hour, minute, 0, 0, loc)
+	}
+	return start
+}
+
+// weekStart returns the start of the weekly session containing t: the
+// daily session starting on the Monday
+func (c *Config) weekStart(t time.Time) time.Time {
+	start := c.sessionStart(t)
+	daysSinceMonday := (int(start.Weekday()) + 6) % 7
+	return time.Date(start.Year(), start.Month(), start.Day()-daysSinceMonday,
+		start.Hour(), start.Minute(), 0, 0, start.Location())
+}
+
+// checkSessionReset resets the daily counters once a new daily session has
+// started, and the weekly PnL once a new week has. It reports whether
+// anything was reset. Must be called with the lock held.
+func (m *Manager) checkSessionReset(now time.Time) bool {
+	reset := false
+	if start := m.config.sessionStart(now); start.After(m.lastResetDate) {
+		m.dailyPnL = decimal.Zero
+		m.tradesExecutedToday = 0
+		m.lastResetDate = start
+		reset = true
+	}
+	if start := m.config.weekStart(now); start.After(m.weekStart) {
+		m.weeklyPnL = decimal.Zero
+		m.weekStart = start
+		reset = true
+	}
+	return reset
+}
+
+// rollSession starts the sessions due at now and persists the reset counters
+func (m *Manager) rollSession(now time.Time) {
+	m.mu.Lock()
+	reset := m.checkSessionReset(now)
+	m.mu.Unlock()
+	if reset {
+		m.saveSession()
+	}
+}
+
+// SetSessionStore persists the session counters to store and restores those
+// it holds. The daily and weekly counters are only restored while their
+// session is still running; the loss streak and cooldown always are.
+func (m *Manager) SetSessionStore(store SessionStore) error {
+	state, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.sessionStore = store
+	if state != nil {
+		if state.SessionStart.Equal(m.lastResetDate) {
+			m.dailyPnL = state.DailyPnL
+			m.tradesExecutedToday = state.TradesToday
+		}
+		if state.WeekStart.Equal(m.weekStart) {
+			m.weeklyPnL = state.WeeklyPnL
+		}
+		m.consecutiveLosses = state.ConsecutiveLosses
+		m.cooldownUntil = state.CooldownUntil
+	}
+	m.mu.Unlock()
+
+	m.saveSession()
+	return nil
+}
+
+// sessionState returns the counters to persist. Must be called with the lock
+// held.
+func (m *Manager) sessionState() SessionState {
+	return SessionState{
+		SessionStart:      m.lastResetDate,
+		WeekStart:         m.weekStart,
+		DailyPnL:          m.dailyPnL,
+		WeeklyPnL:         m.weeklyPnL,
+		TradesToday:       m.tradesExecutedToday,
+		ConsecutiveLosses: m.consecutiveLosses,
+		CooldownUntil:     m.cooldownUntil,
+	}
+}
+
+// saveSession persists the session counters, logging failures: the limits
+// still apply while the process runs
+func (m *Manager) saveSession() {
+	m.mu.RLock()
+	store := m.sessionStore
+	state := m.sessionState()
+	m.mu.RUnlock()
+
+	if store == nil {
+		return
+	}
+	if err := store.Save(state); err != nil {
+		logger.Component("risk").Error("failed to save risk session", "error", err)
+	}
+}
+
+// GetWeeklyPnL returns the profit/loss of the current weekly session
+func (m *Manager) GetWeeklyPnL() decimal.Decimal {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.weeklyPnL
+}
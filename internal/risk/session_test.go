@@ -0,0 +1,147 @@
+package risk
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestConfig_SessionStart(t *testing.T) {
+	newYork := time.FixedZone("EST", -5*60*60)
+	config := DefaultConfig()
+	config.SessionTimezone = newYork
+	config.SessionResetTime = 17 * time.Hour
+
+	tests := []struct {
+		at      time.Time
+		session time.Time
+		week    time.Time
+	}{
+		// Wednesday before the 17:00 reset: Tuesday's session
+		{time.Date(2024, 3, 6, 15, 0, 0, 0, newYork), time.Date(2024, 3, 5, 17, 0, 0, 0, newYork), time.Date(2024, 3, 4, 17, 0, 0, 0, newYork)},
+		{time.Date(2024, 3, 6, 18, 0, 0, 0, newYork), time.Date(2024, 3, 6, 17, 0, 0, 0, newYork), time.Date(2024, 3, 4, 17, 0, 0, 0, newYork)},
+		// Monday before the reset still belongs to the previous week
+		{time.Date(2024, 3, 11, 9, 0, 0, 0, newYork), time.Date(2024, 3, 10, 17, 0, 0, 0, newYork), time.Date(2024, 3, 4, 17, 0, 0, 0, newYork)},
+		// The boundary is in New York time whatever the zone of the input
+		{time.Date(2024, 3, 11, 22, 30, 0, 0, time.UTC), time.Date(2024, 3, 11, 17, 0, 0, 0, newYork), time.Date(2024, 3, 11, 17, 0, 0, 0, newYork)},
+	}
+	for _, tt := range tests {
+		if got := config.sessionStart(tt.at); !got.Equal(tt.session) {
+			t.Errorf("session of %s: expected %s, got %s", tt.at, tt.session, got)
+		}
+		if got := config.weekStart(tt.at); !got.Equal(tt.week) {
+			t.Errorf("week of %s: expected %s, got %s", tt.at, tt.week, got)
+		}
+	}
+}
+
+func TestParseSessionTime(t *testing.T) {
+	if got, err := parseSessionTime("17:30"); err != nil || got != 17*time.Hour+30*time.Minute {
+		t.Errorf("expected 17h30m, got %s (%v)", got, err)
+	}
+	for _, value := range []string{"1730", "24:00", "12:60", "ab:00"} {
+		if _, err := parseSessionTime(value); err == nil {
+			t.Errorf("expected an error for %q", value)
+		}
+	}
+}
+
+func TestManager_SessionReset(t *testing.T) {
+	manager := NewManager(DefaultConfig(), decimal.NewFromInt(10000))
+	tuesday := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	manager.lastResetDate = manager.config.sessionStart(tuesday)
+	manager.weekStart = manager.config.weekStart(tuesday)
+	manager.dailyPnL = decimal.NewFromInt(-50)
+	manager.weeklyPnL = decimal.NewFromInt(-50)
+	manager.tradesExecutedToday = 4
+
+	if manager.checkSessionReset(tuesday.Add(time.Hour)) {
+		t.Fatal("expected no reset within the session")
+	}
+
+	if !manager.checkSessionReset(tuesday.AddDate(0, 0, 1)) {
+		t.Fatal("expected the daily session to reset")
+	}
+	if !manager.dailyPnL.IsZero() || manager.tradesExecutedToday != 0 {
+		t.Errorf("expected the daily counters reset, got %s and %d trades", manager.dailyPnL, manager.tradesExecutedToday)
+	}
+	if !manager.weeklyPnL.Equal(decimal.NewFromInt(-50)) {
+		t.Errorf("expected the weekly PnL kept within the week, got %s", manager.weeklyPnL)
+	}
+
+	manager.checkSessionReset(time.Date(2024, 3, 11, 0, 30, 0, 0, time.UTC))
+	if !manager.weeklyPnL.IsZero() {
+		t.Errorf("expected the weekly PnL reset on Monday, got %s", manager.weeklyPnL)
+	}
+}
+
+func TestManager_WeeklyLossLimit(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxWeeklyLoss = decimal.NewFromInt(150)
+	manager := NewManager(config, decimal.NewFromInt(10000))
+
+	manager.RecordTrade(TradeResult{Symbol: "BTC-USD", PnL: decimal.NewFromInt(-90)})
+	if ok, reason := manager.CanTrade(); !ok {
+		t.Fatalf("expected trading allowed, got %q", reason)
+	}
+
+	// Losses of earlier days of the week still count
+	manager.mu.Lock()
+	manager.dailyPnL = decimal.Zero
+	manager.mu.Unlock()
+	manager.RecordTrade(TradeResult{Symbol: "BTC-USD", PnL: decimal.NewFromInt(-90)})
+	if ok, reason := manager.CanTrade(); ok || reason != "weekly loss limit reached" {
+		t.Errorf("expected the weekly loss limit, got %v (%q)", ok, reason)
+	}
+	if !manager.GetWeeklyPnL().Equal(decimal.NewFromInt(-180)) {
+		t.Errorf("expected a weekly PnL of -180, got %s", manager.GetWeeklyPnL())
+	}
+}
+
+func TestManager_SessionStore(t *testing.T) {
+	store := NewFileSessionStore(filepath.Join(t.TempDir(), "session.json"))
+	config := DefaultConfig()
+	config.ConsecutiveLossLimit = 10
+
+	manager := NewManager(config, decimal.NewFromInt(10000))
+	if err := manager.SetSessionStore(store); err != nil {
+		t.Fatal(err)
+	}
+	manager.RecordTrade(TradeResult{Symbol: "BTC-USD", PnL: decimal.NewFromInt(-60)})
+	manager.RecordTrade(TradeResult{Symbol: "ETH-USD", PnL: decimal.NewFromInt(-20)})
+
+	// A restart picks the session up where it was
+	restarted := NewManager(config, decimal.NewFromInt(10000))
+	if err := restarted.SetSessionStore(store); err != nil {
+		t.Fatal(err)
+	}
+	if !restarted.GetDailyPnL().Equal(decimal.NewFromInt(-80)) || !restarted.GetWeeklyPnL().Equal(decimal.NewFromInt(-80)) {
+		t.Errorf("expected the session PnL restored, got %s daily and %s weekly", restarted.GetDailyPnL(), restarted.GetWeeklyPnL())
+	}
+	if restarted.GetDailyTradeCount() != 2 || restarted.GetConsecutiveLosses() != 2 {
+		t.Errorf("expected 2 trades and 2 losses restored, got %d and %d", restarted.GetDailyTradeCount(), restarted.GetConsecutiveLosses())
+	}
+
+	// Counters of a session that has ended are not
+	state, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.SessionStart = state.SessionStart.AddDate(0, 0, -1)
+	state.WeekStart = state.WeekStart.AddDate(0, 0, -7)
+	if err := store.Save(*state); err != nil {
+		t.Fatal(err)
+	}
+	stale := NewManager(config, decimal.NewFromInt(10000))
+	if err := stale.SetSessionStore(store); err != nil {
+		t.Fatal(err)
+	}
+	if !stale.GetDailyPnL().IsZero() || !stale.GetWeeklyPnL().IsZero() || stale.GetDailyTradeCount() != 0 {
+		t.Errorf("expected the counters of ended sessions dropped, got %+v", stale.GetStats())
+	}
+	if stale.GetConsecutiveLosses() != 2 {
+		t.Errorf("expected the loss streak kept, got %d", stale.GetConsecutiveLosses())
+	}
+}
@@ -0,0 +1,65 @@
+package risk
+
+import (
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// riskScale returns the fraction of RiskPerTrade taken at the current
+// drawdown: DrawdownThrottleFactor per DrawdownThrottleStep of drawdown, down
+// to DrawdownThrottleMinScale. Must be called with the lock held.
+func (m *Manager) riskScale() decimal.Decimal {
+	one := decimal.NewFromInt(1)
+	step := m.config.DrawdownThrottleStep
+	factor := m.config.DrawdownThrottleFactor
+	if !step.IsPositive() || !factor.IsPositive() || factor.GreaterThanOrEqual(one) {
+		return one
+	}
+
+	scale := one
+	for steps := m.calculateDrawdown().Div(step).IntPart(); steps > 0; steps-- {
+		scale = scale.Mul(factor)
+		if scale.LessThanOrEqual(m.config.DrawdownThrottleMinScale) {
+			return decimal.Min(m.config.DrawdownThrottleMinScale, one)
+		}
+	}
+	return scale
+}
+
+// riskPerTrade returns RiskPerTrade scaled for the current drawdown. Must be
+// called with the lock held.
+func (m *Manager) riskPerTrade() decimal.Decimal {
+	return m.config.RiskPerTrade.Mul(m.riskScale())
+}
+
+// RiskScale returns the fraction of RiskPerTrade currently taken per trade
+func (m *Manager) RiskScale() decimal.Decimal {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.riskScale()
+}
+
+// updateRiskScale reports the scale of the risk per trade after a balance
+// change, logging when the drawdown moves it. Must be called with the lock
+// held.
+func (m *Manager) updateRiskScale() {
+	scale := m.riskScale()
+	drawdown := m.calculateDrawdown()
+	telemetry.RecordRiskScale(drawdown.InexactFloat64(), scale.InexactFloat64())
+	if scale.Equal(m.appliedRiskScale) {
+		return
+	}
+
+	log := logger.Component("risk")
+	if scale.LessThan(m.appliedRiskScale) {
+		log.Warn("risk per trade scaled down on drawdown",
+			"drawdown_pct", drawdown.StringFixed(2),
+			"scale", scale.String())
+	} else {
+		log.Info("risk per trade scaled back up",
+			"drawdown_pct", drawdown.StringFixed(2),
+			"scale", scale.String())
+	}
+	m.appliedRiskScale = scale
+}
@@ -0,0 +1,50 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestManager_DrawdownThrottle(t *testing.T) {
+	config := DefaultConfig()
+	config.DrawdownThrottleStep = decimal.NewFromInt(5)
+	config.MaxDrawdown = decimal.NewFromInt(50)
+	manager := NewManager(config, decimal.NewFromInt(10000))
+
+	tests := []struct {
+		balance int64
+		scale   float64
+	}{
+		{10000, 1},
+		{9600, 1},    // 4% drawdown
+		{9500, 0.5},  // 5%
+		{8900, 0.25}, // 11%
+		{7000, 0.25}, // 30%, held at the minimum scale
+		{9700, 1},    // Recovered to 3%
+	}
+	for _, tt := range tests {
+		manager.UpdateBalance(decimal.NewFromInt(tt.balance))
+		if scale := manager.RiskScale(); !scale.Equal(decimal.NewFromFloat(tt.scale)) {
+			t.Errorf("balance %d: expected a risk scale of %v, got %s", tt.balance, tt.scale, scale)
+		}
+	}
+
+	// Sizing takes the scaled risk: 0.5% of 10000 over a 10 stop distance
+	manager.UpdateBalance(decimal.NewFromInt(9500))
+	size := manager.CalculatePositionSize(decimal.NewFromInt(100), decimal.NewFromInt(90), decimal.NewFromInt(10000))
+	if !size.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("expected a size of 5 at half risk, got %s", size)
+	}
+	if stats := manager.GetStats(); !stats.RiskScale.Equal(decimal.NewFromFloat(0.5)) {
+		t.Errorf("expected the risk scale in the stats, got %s", stats.RiskScale)
+	}
+}
+
+func TestManager_DrawdownThrottleDisabled(t *testing.T) {
+	manager := NewManager(DefaultConfig(), decimal.NewFromInt(10000))
+	manager.UpdateBalance(decimal.NewFromInt(9000))
+	if scale := manager.RiskScale(); !scale.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("expected the full risk without a throttle step, got %s", scale)
+	}
+}
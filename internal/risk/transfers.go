@@ -0,0 +1,120 @@
+package risk
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxFlowHistory bounds the balance flows kept for inspection
+const maxFlowHistory = 100
+
+// FlowKind classifies a balance change not explained by trade PnL
+type FlowKind string
+
+const (
+	FlowDeposit    FlowKind = "deposit"
+	FlowWithdrawal FlowKind = "withdrawal"
+	FlowFunding    FlowKind = "funding" // Funding payments, fees and other small adjustments
+)
+
+// BalanceFlow is a balance change not explained by trade PnL
+type BalanceFlow struct {
+	Kind   FlowKind
+	Amount decimal.Decimal // Signed: positive flows added to the balance
+	At     time.Time
+}
+
+// balanceSnapshot is the balance last reconciled
+type balanceSnapshot struct {
+	balance    decimal.Decimal
+	unrealized decimal.Decimal
+}
+
+// ReconcileBalance updates the balance from an exchange snapshot and
+// explains its change since the previous snapshot. unrealizedPnL is the
+// unrealized PnL included in balance, zero for venues reporting cash only.
+//
+// The change of the realized balance not accounted for by the trades
+// recorded meanwhile is a flow. Deposits and withdrawals shift the starting
+// and peak balances by the same amount, so topping up or withdrawing does
+// not move the drawdown; funding and fees count as PnL.
+//
+// A trade closed on the venue shows in its balance before the bot records
+// it, so a change is held back until it stayed unexplained for
+// TransferSettleDelay, trades recorded meanwhile netting it out. The balance
+// leaves it out until then. Only snapshots of every exchange, with every
+// amount converted into the reporting currency, may be reconciled.
+func (m *Manager) ReconcileBalance(balance, unrealizedPnL decimal.Decimal, at time.Time) *BalanceFlow {
+	flow := m.reconcileBalance(balance, unrealizedPnL, at)
+	if flow != nil && flow.Kind == FlowFunding {
+		m.saveSession()
+	}
+	return flow
+}
+
+// reconcileBalance updates the balance and returns the flow explaining its
+// change, if any
+func (m *Manager) reconcileBalance(balance, unrealizedPnL decimal.Decimal, at time.Time) *BalanceFlow {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	previous := m.snapshot
+	expected := m.pnlSinceSnapshot
+	m.snapshot = &balanceSnapshot{balance: balance, unrealized: unrealizedPnL}
+	m.pnlSinceSnapshot = decimal.Zero
+
+	if previous == nil {
+		m.setBalance(balance)
+		return nil
+	}
+
+	realized := balance.Sub(unrealizedPnL)
+	previousRealized := previous.balance.Sub(previous.unrealized)
+	unexplained := realized.Sub(previousRealized).Sub(expected).Add(m.unsettled)
+	if unexplained.IsZero() {
+		m.unsettled = decimal.Zero
+		m.setBalance(balance)
+		return nil
+	}
+	if delay := m.config.TransferSettleDelay; delay > 0 {
+		if m.unsettled.IsZero() {
+			m.unsettledSince = at
+		}
+		if at.Sub(m.unsettledSince) < delay {
+			m.unsettled = unexplained
+			m.setBalance(balance.Sub(unexplained))
+			return nil
+		}
+	}
+	m.unsettled = decimal.Zero
+
+	flow := BalanceFlow{Kind: FlowFunding, Amount: unexplained, At: at}
+	threshold := previousRealized.Abs().Mul(m.config.TransferThreshold).Div(decimal.NewFromInt(100))
+	if m.config.TransferThreshold.IsPositive() && unexplained.Abs().GreaterThanOrEqual(threshold) {
+		flow.Kind = FlowDeposit
+		if unexplained.IsNegative() {
+			flow.Kind = FlowWithdrawal
+		}
+		m.netDeposits = m.netDeposits.Add(unexplained)
+		m.startingBalance = m.startingBalance.Add(unexplained)
+		m.peakBalance = decimal.Max(m.peakBalance.Add(unexplained), decimal.Zero)
+	} else {
+		m.dailyPnL = m.dailyPnL.Add(unexplained)
+		m.weeklyPnL = m.weeklyPnL.Add(unexplained)
+	}
+	m.setBalance(balance)
+
+	m.flows = append(m.flows, flow)
+	if len(m.flows) > maxFlowHistory {
+		m.flows = m.flows[len(m.flows)-maxFlowHistory:]
+	}
+	return &flow
+}
+
+// GetBalanceFlows returns the most recent balance flows, oldest first
+func (m *Manager) GetBalanceFlows() []BalanceFlow {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]BalanceFlow(nil), m.flows...)
+}
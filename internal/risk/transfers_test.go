@@ -0,0 +1,110 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// newTransferTestManager returns a manager classifying balance changes as
+// soon as they are seen
+func newTransferTestManager() *Manager {
+	config := DefaultConfig()
+	config.TransferSettleDelay = 0
+	return NewManager(config, decimal.NewFromInt(10000))
+}
+
+func TestReconcileBalance_DepositKeepsDrawdown(t *testing.T) {
+	manager := newTransferTestManager()
+	now := time.Now()
+
+	if flow := manager.ReconcileBalance(decimal.NewFromInt(10000), decimal.Zero, now); flow != nil {
+		t.Fatalf("expected the first snapshot to explain nothing, got %+v", flow)
+	}
+
+	// A losing trade accounts for the whole change
+	manager.RecordTrade(TradeResult{Symbol: "BTC-USD", PnL: decimal.NewFromInt(-500)})
+	if flow := manager.ReconcileBalance(decimal.NewFromInt(9500), decimal.Zero, now); flow != nil {
+		t.Errorf("expected the trade to explain the change, got %+v", flow)
+	}
+
+	// Unrealized PnL moves the balance of margin accounts without a flow
+	if flow := manager.ReconcileBalance(decimal.NewFromInt(9300), decimal.NewFromInt(-200), now); flow != nil {
+		t.Errorf("expected unrealized PnL to explain the change, got %+v", flow)
+	}
+
+	flow := manager.ReconcileBalance(decimal.NewFromInt(14500), decimal.Zero, now)
+	if flow == nil || flow.Kind != FlowDeposit || !flow.Amount.Equal(decimal.NewFromInt(5000)) {
+		t.Fatalf("expected a 5000 deposit, got %+v", flow)
+	}
+	// The 500 lost below the peak is still lost after the deposit
+	stats := manager.GetStats()
+	if gap := stats.PeakBalance.Sub(stats.CurrentBalance); !gap.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("expected the deposit to keep the 500 drawdown, got %s", gap)
+	}
+	if !stats.NetDeposits.Equal(decimal.NewFromInt(5000)) {
+		t.Errorf("expected net deposits of 5000, got %s", stats.NetDeposits)
+	}
+
+	// A withdrawal does not register as a loss
+	flow = manager.ReconcileBalance(decimal.NewFromInt(4500), decimal.Zero, now)
+	if flow == nil || flow.Kind != FlowWithdrawal {
+		t.Fatalf("expected a withdrawal, got %+v", flow)
+	}
+	stats = manager.GetStats()
+	if gap := stats.PeakBalance.Sub(stats.CurrentBalance); !gap.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("expected the withdrawal to keep the 500 drawdown, got %s", gap)
+	}
+}
+
+func TestReconcileBalance_FundingCountsAsPnL(t *testing.T) {
+	manager := newTransferTestManager()
+	now := time.Now()
+
+	manager.ReconcileBalance(decimal.NewFromInt(10000), decimal.Zero, now)
+	flow := manager.ReconcileBalance(decimal.NewFromInt(9990), decimal.Zero, now)
+	if flow == nil || flow.Kind != FlowFunding {
+		t.Fatalf("expected a small change to be funding, got %+v", flow)
+	}
+	if pnl := manager.GetDailyPnL(); !pnl.Equal(decimal.NewFromInt(-10)) {
+		t.Errorf("expected funding in the daily PnL, got %s", pnl)
+	}
+	if flows := manager.GetBalanceFlows(); len(flows) != 1 {
+		t.Errorf("expected 1 flow recorded, got %d", len(flows))
+	}
+}
+
+func TestReconcileBalance_SettlesTradesRecordedLate(t *testing.T) {
+	manager := NewManager(DefaultConfig(), decimal.NewFromInt(10000))
+	now := time.Now()
+	manager.ReconcileBalance(decimal.NewFromInt(10000), decimal.Zero, now)
+
+	// The venue settles a 300 win before the bot records the trade
+	if flow := manager.ReconcileBalance(decimal.NewFromInt(10300), decimal.Zero, now.Add(time.Second)); flow != nil {
+		t.Fatalf("expected the change to wait for the trade, got %+v", flow)
+	}
+	if balance := manager.GetCurrentBalance(); !balance.Equal(decimal.NewFromInt(10000)) {
+		t.Errorf("expected the unsettled change left out of the balance, got %s", balance)
+	}
+	manager.RecordTrade(TradeResult{Symbol: "BTC-USD", PnL: decimal.NewFromInt(300), IsWin: true})
+	if flow := manager.ReconcileBalance(decimal.NewFromInt(10300), decimal.Zero, now.Add(3*time.Minute)); flow != nil {
+		t.Errorf("expected the recorded trade to explain the change, got %+v", flow)
+	}
+	if deposits := manager.GetStats().NetDeposits; !deposits.IsZero() {
+		t.Errorf("expected no deposit, got %s", deposits)
+	}
+
+	// A change still unexplained after the settle delay is a transfer
+	if flow := manager.ReconcileBalance(decimal.NewFromInt(15300), decimal.Zero, now.Add(4*time.Minute)); flow != nil {
+		t.Fatalf("expected the deposit to wait for the settle delay, got %+v", flow)
+	}
+	flow := manager.ReconcileBalance(decimal.NewFromInt(15300), decimal.Zero, now.Add(7*time.Minute))
+	if flow == nil || flow.Kind != FlowDeposit || !flow.Amount.Equal(decimal.NewFromInt(5000)) {
+		t.Fatalf("expected a 5000 deposit, got %+v", flow)
+	}
+	stats := manager.GetStats()
+	if !stats.PeakBalance.Equal(decimal.NewFromInt(15300)) || !stats.CurrentBalance.Equal(decimal.NewFromInt(15300)) {
+		t.Errorf("expected the deposit to move the balance and its peak once, got %s and %s", stats.CurrentBalance, stats.PeakBalance)
+	}
+}
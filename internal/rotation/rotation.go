@@ -0,0 +1,330 @@
+// Package rotation takes symbols out of trading when the symbol selection
+// drops them, without leaving their open positions unmanaged: a symbol still
+// holding a position is either kept until it is flat or has its position
+// closed gradually.
+package rotation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/guyghost/constantine/internal/ringbuf"
+	"github.com/shopspring/decimal"
+)
+
+// keptEvents is the number of recent events kept for display
+const keptEvents = 50
+
+// Policy is how a dropped symbol holding a position leaves trading
+type Policy string
+
+const (
+	// PolicyBlock keeps trading the symbol until its position is closed by
+	// the strategy, a stop or a take profit
+	PolicyBlock Policy = "block"
+	// PolicyClose stops trading the symbol at once and closes its position in
+	// equal steps over the close period
+	PolicyClose Policy = "close"
+)
+
+// ParsePolicy parses a policy name, block when empty
+func ParsePolicy(name string) (Policy, error) {
+	switch Policy(name) {
+	case "", PolicyBlock:
+		return PolicyBlock, nil
+	case PolicyClose:
+		return PolicyClose, nil
+	}
+	return "", fmt.Errorf("unknown rotation policy %q", name)
+}
+
+// Positions is the part of the order manager rotations act on
+type Positions interface {
+	GetPosition(symbol string) *order.ManagedPosition
+	ReducePosition(ctx context.Context, symbol string, fraction decimal.Decimal) (*exchanges.Order, error)
+	ClosePosition(ctx context.Context, symbol string) error
+}
+
+// EventKind is what happened to a rotating symbol
+type EventKind string
+
+const (
+	EventDropped EventKind = "dropped" // Symbol left trading
+	EventBlocked EventKind = "blocked" // Drop postponed until the position is closed
+	EventClosing EventKind = "closing" // Position being closed before the drop
+	EventReduced EventKind = "reduced" // Part of the position closed
+	EventClosed  EventKind = "closed"  // Position fully closed
+	EventKept    EventKind = "kept"    // Symbol selected again before leaving
+	EventFailed  EventKind = "failed"  // Drop or close attempt failed
+)
+
+// Event is a step of a symbol rotation
+type Event struct {
+	Time   time.Time
+	Symbol string
+	Kind   EventKind
+	Detail string
+}
+
+// Transition is a dropped symbol waiting for its position to be closed
+type Transition struct {
+	Symbol   string
+	Policy   Policy
+	Since    time.Time
+	Deadline time.Time       // Position fully closed by then, PolicyClose only
+	Amount   decimal.Decimal // Position left at the last check
+
+	reducedAt time.Time // Last step of a PolicyClose transition
+}
+
+// Rotator drops the symbols the selection no longer wants according to its
+// policy. Dropping is delegated to the drop function, which stops the
+// strategy of the symbol.
+type Rotator struct {
+	policy    Policy
+	closeOver time.Duration
+	positions Positions
+	drop      func(symbol string) error
+
+	mu          sync.Mutex
+	transitions map[string]*Transition
+	events      *ringbuf.Buffer[Event]
+	onEvent     func(Event)
+	now         func() time.Time
+}
+
+// NewRotator creates a rotator applying policy, closing positions over
+// closeOver with PolicyClose
+func NewRotator(policy Policy, closeOver time.Duration, positions Positions, drop func(symbol string) error) *Rotator {
+	return &Rotator{
+		policy:      policy,
+		closeOver:   closeOver,
+		positions:   positions,
+		drop:        drop,
+		transitions: make(map[string]*Transition),
+		events:      ringbuf.New[Event](keptEvents),
+		now:         time.Now,
+	}
+}
+
+// SetEventCallback sets the callback notified of every event
+func (r *Rotator) SetEventCallback(callback func(Event)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onEvent = callback
+}
+
+// Drop takes symbol out of trading: at once when it holds no position,
+// otherwise according to the policy
+func (r *Rotator) Drop(ctx context.Context, symbol string) {
+	r.mu.Lock()
+	_, pending := r.transitions[symbol]
+	r.mu.Unlock()
+	if pending {
+		return
+	}
+
+	amount := r.openAmount(symbol)
+	if amount.IsZero() {
+		r.dropNow(symbol, "no open position")
+		return
+	}
+
+	now := r.now()
+	transition := &Transition{Symbol: symbol, Policy: r.policy, Since: now, Amount: amount, reducedAt: now}
+	switch r.policy {
+	case PolicyClose:
+		transition.Deadline = now.Add(r.closeOver)
+		if err := r.drop(symbol); err != nil {
+			r.record(symbol, EventFailed, err.Error())
+			return
+		}
+		r.store(transition)
+		r.record(symbol, EventClosing, fmt.Sprintf("closing %s over %s", amount, r.closeOver))
+	default:
+		r.store(transition)
+		r.record(symbol, EventBlocked, fmt.Sprintf("kept until %s is closed", amount))
+	}
+}
+
+// Keep cancels the rotation of symbol when the selection picks it again. It
+// returns whether its strategy is still running, which is the case unless it
+// was being closed.
+func (r *Rotator) Keep(symbol string) bool {
+	r.mu.Lock()
+	transition, pending := r.transitions[symbol]
+	delete(r.transitions, symbol)
+	r.mu.Unlock()
+	if !pending {
+		return false
+	}
+	r.record(symbol, EventKept, "selected again")
+	return transition.Policy == PolicyBlock
+}
+
+// Check moves every transition forward: blocked symbols that are flat are
+// dropped, and closing positions are reduced or closed at their deadline
+func (r *Rotator) Check(ctx context.Context) {
+	for _, transition := range r.Transitions() {
+		r.step(ctx, &transition)
+	}
+}
+
+// Run checks the transitions every interval until ctx is canceled
+func (r *Rotator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Check(ctx)
+		}
+	}
+}
+
+// Transitions returns the pending transitions sorted by symbol
+func (r *Rotator) Transitions() []Transition {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	transitions := make([]Transition, 0, len(r.transitions))
+	for _, transition := range r.transitions {
+		transitions = append(transitions, *transition)
+	}
+	sort.Slice(transitions, func(i, j int) bool { return transitions[i].Symbol < transitions[j].Symbol })
+	return transitions
+}
+
+// Events returns the latest events, oldest first
+func (r *Rotator) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events.Values()
+}
+
+// step moves one transition forward
+func (r *Rotator) step(ctx context.Context, transition *Transition) {
+	symbol := transition.Symbol
+	amount := r.openAmount(symbol)
+
+	if transition.Policy == PolicyBlock {
+		if amount.IsZero() {
+			if r.dropNow(symbol, "position closed") {
+				r.forget(symbol)
+			}
+			return
+		}
+		r.update(symbol, amount)
+		return
+	}
+
+	if amount.IsZero() {
+		if r.forget(symbol) {
+			r.record(symbol, EventClosed, "position closed")
+		}
+		return
+	}
+	left := transition.Deadline.Sub(r.now())
+	if left <= 0 {
+		if err := r.positions.ClosePosition(ctx, symbol); err != nil {
+			r.record(symbol, EventFailed, err.Error())
+			return
+		}
+		r.forget(symbol)
+		r.record(symbol, EventClosed, fmt.Sprintf("closed %s at the deadline", amount))
+		return
+	}
+
+	// Close the share of the position the time since the last step is of the
+	// time left, unwinding it evenly until the deadline
+	elapsed := r.now().Sub(transition.reducedAt)
+	if elapsed <= 0 {
+		return
+	}
+	fraction := decimal.NewFromFloat(float64(elapsed) / float64(elapsed+left))
+	if _, err := r.positions.ReducePosition(ctx, symbol, fraction); err != nil {
+		r.record(symbol, EventFailed, err.Error())
+		return
+	}
+	r.mu.Lock()
+	if stored, ok := r.transitions[symbol]; ok {
+		stored.reducedAt = r.now()
+	}
+	r.mu.Unlock()
+	r.record(symbol, EventReduced, fmt.Sprintf("%s%% of %s, %s left", fraction.Mul(decimal.NewFromInt(100)).StringFixed(0), amount, left.Round(time.Second)))
+}
+
+// dropNow drops symbol through the drop function, returning whether it
+// succeeded
+func (r *Rotator) dropNow(symbol, reason string) bool {
+	if err := r.drop(symbol); err != nil {
+		r.record(symbol, EventFailed, err.Error())
+		return false
+	}
+	r.record(symbol, EventDropped, reason)
+	return true
+}
+
+// openAmount returns the amount of the open position held on symbol, zero
+// when flat
+func (r *Rotator) openAmount(symbol string) decimal.Decimal {
+	position := r.positions.GetPosition(symbol)
+	if position == nil || position.Status == order.PositionStatusClosed {
+		return decimal.Zero
+	}
+	return position.Amount
+}
+
+// store starts tracking transition
+func (r *Rotator) store(transition *Transition) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.transitions[transition.Symbol] = transition
+}
+
+// update records the position left on a transition
+func (r *Rotator) update(symbol string, amount decimal.Decimal) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if transition, ok := r.transitions[symbol]; ok {
+		transition.Amount = amount
+	}
+}
+
+// forget stops tracking the transition of symbol, returning whether it was
+// still tracked
+func (r *Rotator) forget(symbol string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.transitions[symbol]
+	delete(r.transitions, symbol)
+	return ok
+}
+
+// record logs an event, keeps it for display and notifies the callback
+func (r *Rotator) record(symbol string, kind EventKind, detail string) {
+	event := Event{Time: r.now(), Symbol: symbol, Kind: kind, Detail: detail}
+	r.mu.Lock()
+	r.events.Push(event)
+	callback := r.onEvent
+	r.mu.Unlock()
+
+	log := logger.Component("rotation")
+	if kind == EventFailed {
+		log.Warn("symbol rotation step failed", "symbol", symbol, "error", detail)
+	} else {
+		log.Info("symbol rotation", "symbol", symbol, "event", kind, "detail", detail)
+	}
+	if callback != nil {
+		callback(event)
+	}
+}
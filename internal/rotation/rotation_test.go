@@ -0,0 +1,189 @@
+package rotation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/order"
+	"github.com/shopspring/decimal"
+)
+
+// fakePositions holds one position per symbol, reduced and closed at once
+type fakePositions struct {
+	amounts   map[string]decimal.Decimal
+	fractions []decimal.Decimal
+	closeErr  error
+}
+
+func (f *fakePositions) GetPosition(symbol string) *order.ManagedPosition {
+	amount, ok := f.amounts[symbol]
+	if !ok || amount.IsZero() {
+		return nil
+	}
+	return &order.ManagedPosition{Symbol: symbol, Amount: amount, Status: order.PositionStatusOpen}
+}
+
+func (f *fakePositions) ReducePosition(ctx context.Context, symbol string, fraction decimal.Decimal) (*exchanges.Order, error) {
+	f.fractions = append(f.fractions, fraction)
+	f.amounts[symbol] = f.amounts[symbol].Mul(decimal.NewFromInt(1).Sub(fraction))
+	return &exchanges.Order{Symbol: symbol}, nil
+}
+
+func (f *fakePositions) ClosePosition(ctx context.Context, symbol string) error {
+	if f.closeErr != nil {
+		return f.closeErr
+	}
+	delete(f.amounts, symbol)
+	return nil
+}
+
+func newTestRotator(policy Policy, positions *fakePositions, now *time.Time) (*Rotator, *[]string) {
+	var dropped []string
+	r := NewRotator(policy, 10*time.Minute, positions, func(symbol string) error {
+		dropped = append(dropped, symbol)
+		return nil
+	})
+	r.now = func() time.Time { return *now }
+	return r, &dropped
+}
+
+func kinds(events []Event) []EventKind {
+	result := make([]EventKind, len(events))
+	for i, event := range events {
+		result[i] = event.Kind
+	}
+	return result
+}
+
+func TestRotator_DropsFlatSymbolsAtOnce(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	positions := &fakePositions{amounts: map[string]decimal.Decimal{}}
+	r, dropped := newTestRotator(PolicyBlock, positions, &now)
+
+	r.Drop(context.Background(), "ETH-USD")
+	if len(*dropped) != 1 || (*dropped)[0] != "ETH-USD" {
+		t.Errorf("Expected ETH-USD to be dropped at once, got %v", *dropped)
+	}
+	if len(r.Transitions()) != 0 {
+		t.Errorf("Expected no transition, got %+v", r.Transitions())
+	}
+}
+
+func TestRotator_BlockPolicy(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	positions := &fakePositions{amounts: map[string]decimal.Decimal{"ETH-USD": decimal.NewFromInt(2)}}
+	r, dropped := newTestRotator(PolicyBlock, positions, &now)
+	ctx := context.Background()
+
+	r.Drop(ctx, "ETH-USD")
+	r.Check(ctx)
+	if len(*dropped) != 0 {
+		t.Fatalf("Expected the drop to be blocked while the position is open, got %v", *dropped)
+	}
+	if transitions := r.Transitions(); len(transitions) != 1 || transitions[0].Policy != PolicyBlock {
+		t.Fatalf("Expected a blocked transition, got %+v", transitions)
+	}
+	if len(positions.fractions) != 0 {
+		t.Error("Block policy should not touch the position")
+	}
+
+	delete(positions.amounts, "ETH-USD")
+	r.Check(ctx)
+	if len(*dropped) != 1 {
+		t.Errorf("Expected the symbol to be dropped once flat, got %v", *dropped)
+	}
+	if len(r.Transitions()) != 0 {
+		t.Error("Expected the transition to complete")
+	}
+	got := kinds(r.Events())
+	if len(got) != 2 || got[0] != EventBlocked || got[1] != EventDropped {
+		t.Errorf("Expected blocked then dropped events, got %v", got)
+	}
+}
+
+func TestRotator_ClosePolicy(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	positions := &fakePositions{amounts: map[string]decimal.Decimal{"ETH-USD": decimal.NewFromInt(10)}}
+	r, dropped := newTestRotator(PolicyClose, positions, &now)
+	ctx := context.Background()
+
+	r.Drop(ctx, "ETH-USD")
+	if len(*dropped) != 1 {
+		t.Fatalf("Expected the strategy to be dropped at once, got %v", *dropped)
+	}
+	transitions := r.Transitions()
+	if len(transitions) != 1 || !transitions[0].Deadline.Equal(start.Add(10*time.Minute)) {
+		t.Fatalf("Expected a closing transition due in 10 minutes, got %+v", transitions)
+	}
+
+	// Evenly unwound: a tenth of the initial position each minute
+	for minute := 1; minute <= 5; minute++ {
+		now = start.Add(time.Duration(minute) * time.Minute)
+		r.Check(ctx)
+	}
+	left := positions.amounts["ETH-USD"]
+	if !left.Round(6).Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Expected half the position left halfway, got %s", left)
+	}
+
+	// Whatever is left is closed at the deadline
+	positions.closeErr = errors.New("rejected")
+	now = start.Add(11 * time.Minute)
+	r.Check(ctx)
+	if len(r.Transitions()) != 1 {
+		t.Fatal("Expected a failed close to be retried")
+	}
+	positions.closeErr = nil
+	r.Check(ctx)
+	if _, open := positions.amounts["ETH-USD"]; open || len(r.Transitions()) != 0 {
+		t.Errorf("Expected the position closed at the deadline, %v left", positions.amounts)
+	}
+	events := kinds(r.Events())
+	if events[0] != EventClosing || events[len(events)-1] != EventClosed {
+		t.Errorf("Expected closing first and closed last, got %v", events)
+	}
+}
+
+func TestRotator_Keep(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	positions := &fakePositions{amounts: map[string]decimal.Decimal{
+		"ETH-USD": decimal.NewFromInt(1),
+		"SOL-USD": decimal.NewFromInt(1),
+	}}
+	blocker, _ := newTestRotator(PolicyBlock, positions, &now)
+	closer, _ := newTestRotator(PolicyClose, positions, &now)
+	ctx := context.Background()
+
+	blocker.Drop(ctx, "ETH-USD")
+	if !blocker.Keep("ETH-USD") {
+		t.Error("A blocked symbol should still be trading when kept")
+	}
+	closer.Drop(ctx, "SOL-USD")
+	if closer.Keep("SOL-USD") {
+		t.Error("A closing symbol should need restarting when kept")
+	}
+	if blocker.Keep("BTC-USD") {
+		t.Error("A symbol never dropped has no transition to cancel")
+	}
+
+	now = now.Add(time.Minute)
+	closer.Check(ctx)
+	if len(positions.fractions) != 0 {
+		t.Error("A kept symbol should no longer be reduced")
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	for name, want := range map[string]Policy{"": PolicyBlock, "block": PolicyBlock, "close": PolicyClose} {
+		if got, err := ParsePolicy(name); err != nil || got != want {
+			t.Errorf("ParsePolicy(%q) = %s, %v, want %s", name, got, err, want)
+		}
+	}
+	if _, err := ParsePolicy("flatten"); err == nil {
+		t.Error("Expected an error for an unknown policy")
+	}
+}
@@ -0,0 +1,119 @@
+package statefile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Append-only logs, such as the trade journal, grow a line at a time and are
+// never rewritten, so each of their lines is sealed on its own: lineMagic
+// followed by the base64 salt, nonce and sealed content of the line.
+var lineMagic = []byte("CONSTANTINE-LINE-1:")
+
+// SetPassphraseFromEnv sets the passphrase from STATE_PASSPHRASE, or the
+// content of STATE_KEY_FILE, and reports whether one is set
+func SetPassphraseFromEnv() (bool, error) {
+	value, err := LoadPassphrase(os.Getenv("STATE_PASSPHRASE"), os.Getenv("STATE_KEY_FILE"))
+	if err != nil || value == "" {
+		return false, err
+	}
+	return true, SetPassphrase(value)
+}
+
+// EncryptLine seals line, which must not contain a newline, with the key of
+// this process, or returns it as is without a passphrase
+func EncryptLine(line []byte) ([]byte, error) {
+	sealed, err := encrypt(line)
+	if err != nil || !Encrypted(sealed) {
+		return sealed, err
+	}
+	encoded := make([]byte, len(lineMagic)+base64.StdEncoding.EncodedLen(len(sealed)-len(magic)))
+	copy(encoded, lineMagic)
+	base64.StdEncoding.Encode(encoded[len(lineMagic):], sealed[len(magic):])
+	return encoded, nil
+}
+
+// DecryptLine returns the content of a line sealed by EncryptLine. Plain
+// lines are returned as is.
+func DecryptLine(line []byte) ([]byte, error) {
+	if !bytes.HasPrefix(line, lineMagic) {
+		return line, nil
+	}
+	sealed := make([]byte, len(magic)+base64.StdEncoding.DecodedLen(len(line)-len(lineMagic)))
+	copy(sealed, magic)
+	n, err := base64.StdEncoding.Decode(sealed[len(magic):], line[len(lineMagic):])
+	if err != nil {
+		return nil, fmt.Errorf("encrypted line is corrupted: %w", err)
+	}
+	return decrypt(sealed[:len(magic)+n])
+}
+
+// lineWriter seals every complete line written to it
+type lineWriter struct {
+	w       io.Writer
+	pending []byte
+}
+
+// NewLineWriter returns a writer sealing each line written to it with
+// EncryptLine before writing it to w. A line is written once its newline is.
+func NewLineWriter(w io.Writer) io.Writer {
+	return &lineWriter{w: w}
+}
+
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.pending = append(lw.pending, p...)
+	for {
+		end := bytes.IndexByte(lw.pending, '\n')
+		if end < 0 {
+			return len(p), nil
+		}
+		line, err := EncryptLine(lw.pending[:end])
+		if err != nil {
+			return 0, err
+		}
+		if _, err := lw.w.Write(append(line, '\n')); err != nil {
+			return 0, err
+		}
+		lw.pending = lw.pending[end+1:]
+	}
+}
+
+// lineReader opens the lines read from a log
+type lineReader struct {
+	r       *bufio.Reader
+	pending []byte
+	err     error
+}
+
+// NewLineReader returns a reader of the lines of r opened with DecryptLine,
+// so logs holding both plain and sealed lines read as plain text
+func NewLineReader(r io.Reader) io.Reader {
+	return &lineReader{r: bufio.NewReader(r)}
+}
+
+func (lr *lineReader) Read(p []byte) (int, error) {
+	for len(lr.pending) == 0 {
+		if lr.err != nil {
+			return 0, lr.err
+		}
+		line, err := lr.r.ReadBytes('\n')
+		lr.err = err
+		newline := bytes.HasSuffix(line, []byte{'\n'})
+		line = bytes.TrimSuffix(line, []byte{'\n'})
+		if len(line) == 0 && !newline {
+			continue
+		}
+		plain, err := DecryptLine(line)
+		if err != nil {
+			return 0, err
+		}
+		lr.pending = append(plain, '\n')
+	}
+	n := copy(p, lr.pending)
+	lr.pending = lr.pending[n:]
+	return n, nil
+}
@@ -0,0 +1,45 @@
+package statefile
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLineWriterReader(t *testing.T) {
+	defer SetPassphrase("")
+	var log bytes.Buffer
+
+	// A log started before encryption was enabled keeps its plain lines
+	w := NewLineWriter(&log)
+	if _, err := w.Write([]byte("{\"pnl\":\"1\"}\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := SetPassphrase("correct horse"); err != nil {
+		t.Fatalf("SetPassphrase failed: %v", err)
+	}
+	// Lines are sealed once complete, however they are written
+	if _, err := w.Write([]byte("{\"pnl\":")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := w.Write([]byte("\"2\"}\n{\"pnl\":\"3\"}\n")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	raw := log.Bytes()
+	if bytes.Count(raw, []byte("\n")) != 3 || bytes.Count(raw, lineMagic) != 2 || bytes.Contains(raw, []byte("\"2\"")) {
+		t.Fatalf("expected the last two lines sealed at rest, got %q", raw)
+	}
+
+	want := "{\"pnl\":\"1\"}\n{\"pnl\":\"2\"}\n{\"pnl\":\"3\"}\n"
+	got, err := io.ReadAll(NewLineReader(bytes.NewReader(raw)))
+	if err != nil || string(got) != want {
+		t.Fatalf("expected the lines opened, got %q (%v)", got, err)
+	}
+
+	SetPassphrase("")
+	if _, err := io.ReadAll(NewLineReader(bytes.NewReader(raw))); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("expected ErrEncrypted without a passphrase, got %v", err)
+	}
+}
@@ -0,0 +1,197 @@
+// Package statefile reads and writes the state files the bot persists, such
+// as the risk session, synthetic stops and blocklist. Files are replaced
+// atomically, and encrypted at rest with AES-256-GCM once a passphrase is
+// set. Plain files are still read, so state written before encryption was
+// enabled is migrated on its next save. Append-only logs, such as the trade
+// journal, are encrypted line by line.
+package statefile
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrEncrypted is returned when reading an encrypted file without the
+// passphrase it was encrypted with
+var ErrEncrypted = errors.New("state file is encrypted")
+
+const (
+	saltSize = 16
+	keySize  = 32
+	// scrypt cost, the recommended interactive parameters
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// magic starts every encrypted file, followed by the salt the key was
+// derived with, the nonce and the sealed content
+var magic = []byte("CONSTANTINE-STATE-1\n")
+
+var (
+	mu         sync.Mutex
+	passphrase string
+	writeSalt  []byte            // Salt of the files written by this process
+	keys       map[string][]byte // Keys derived from passphrase, by salt
+)
+
+// SetPassphrase encrypts the files written from now on with a key derived
+// from passphrase, and decrypts those read with it. An empty passphrase
+// writes plain files.
+func SetPassphrase(value string) error {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	passphrase = value
+	writeSalt = salt
+	keys = make(map[string][]byte)
+	return nil
+}
+
+// LoadPassphrase returns the passphrase given directly or, when it is empty,
+// the content of keyFile without surrounding whitespace. Both empty
+// disables encryption.
+func LoadPassphrase(value, keyFile string) (string, error) {
+	if value != "" || keyFile == "" {
+		return value, nil
+	}
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read key file: %w", err)
+	}
+	value = strings.TrimSpace(string(data))
+	if value == "" {
+		return "", fmt.Errorf("key file %s is empty", keyFile)
+	}
+	return value, nil
+}
+
+// Encrypted reports whether data is the content of an encrypted file
+func Encrypted(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// Read returns the content of the file at path, decrypted if it is
+// encrypted. A missing file fails with an error wrapping os.ErrNotExist.
+func Read(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !Encrypted(data) {
+		return data, err
+	}
+	return decrypt(data)
+}
+
+// Write replaces the file at path with data, encrypted once a passphrase is
+// set. The file is written next to path and renamed over it, so a crash never
+// leaves it half written.
+func Write(path string, data []byte) error {
+	data, err := encrypt(data)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// encrypt seals data with the key of this process, or returns it as is
+// without a passphrase
+func encrypt(data []byte) ([]byte, error) {
+	mu.Lock()
+	salt := writeSalt
+	enabled := passphrase != ""
+	mu.Unlock()
+	if !enabled {
+		return data, nil
+	}
+
+	aead, err := newAEAD(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := make([]byte, 0, len(magic)+len(salt)+len(nonce)+len(data)+aead.Overhead())
+	sealed = append(sealed, magic...)
+	sealed = append(sealed, salt...)
+	sealed = append(sealed, nonce...)
+	return aead.Seal(sealed, nonce, data, magic), nil
+}
+
+// decrypt opens the content of an encrypted file
+func decrypt(data []byte) ([]byte, error) {
+	mu.Lock()
+	enabled := passphrase != ""
+	mu.Unlock()
+	if !enabled {
+		return nil, ErrEncrypted
+	}
+
+	data = data[len(magic):]
+	if len(data) < saltSize {
+		return nil, errors.New("encrypted state file is truncated")
+	}
+	aead, err := newAEAD(data[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	data = data[saltSize:]
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("encrypted state file is truncated")
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], magic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt state file, wrong passphrase? %w", err)
+	}
+	return plain, nil
+}
+
+// newAEAD returns the cipher keyed from the passphrase and salt. Keys are
+// cached since deriving one is deliberately slow.
+func newAEAD(salt []byte) (cipher.AEAD, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	key, ok := keys[string(salt)]
+	if !ok {
+		var err error
+		key, err = scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keySize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+		keys[string(salt)] = key
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,86 @@
+package statefile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteRead(t *testing.T) {
+	defer SetPassphrase("")
+	path := filepath.Join(t.TempDir(), "state.json")
+	content := []byte(`{"daily_pnl":"-12.5"}`)
+
+	if _, err := Read(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected a missing file to fail with os.ErrNotExist, got %v", err)
+	}
+
+	// Plain files are read once encryption is enabled
+	if err := Write(path, content); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := SetPassphrase("correct horse"); err != nil {
+		t.Fatalf("SetPassphrase failed: %v", err)
+	}
+	got, err := Read(path)
+	if err != nil || !bytes.Equal(got, content) {
+		t.Fatalf("expected the plain file to be read, got %q (%v)", got, err)
+	}
+
+	if err := Write(path, content); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	raw, _ := os.ReadFile(path)
+	if !Encrypted(raw) || bytes.Contains(raw, []byte("daily_pnl")) {
+		t.Fatalf("expected the file to be encrypted at rest, got %q", raw)
+	}
+	got, err = Read(path)
+	if err != nil || !bytes.Equal(got, content) {
+		t.Fatalf("expected the file to be decrypted, got %q (%v)", got, err)
+	}
+
+	// Another process with the same passphrase derives the key from the salt
+	// of the file
+	if err := SetPassphrase("correct horse"); err != nil {
+		t.Fatalf("SetPassphrase failed: %v", err)
+	}
+	if got, err := Read(path); err != nil || !bytes.Equal(got, content) {
+		t.Errorf("expected the file to be decrypted after a restart, got %q (%v)", got, err)
+	}
+
+	if err := SetPassphrase("wrong"); err != nil {
+		t.Fatalf("SetPassphrase failed: %v", err)
+	}
+	if _, err := Read(path); err == nil {
+		t.Error("expected a wrong passphrase to fail")
+	}
+	SetPassphrase("")
+	if _, err := Read(path); !errors.Is(err, ErrEncrypted) {
+		t.Errorf("expected ErrEncrypted without a passphrase, got %v", err)
+	}
+}
+
+func TestLoadPassphrase(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "state.key")
+	if err := os.WriteFile(keyFile, []byte("from file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		value, keyFile, want string
+	}{
+		{"direct", keyFile, "direct"},
+		{"", keyFile, "from file"},
+		{"", "", ""},
+	} {
+		got, err := LoadPassphrase(tt.value, tt.keyFile)
+		if err != nil || got != tt.want {
+			t.Errorf("LoadPassphrase(%q, %q) = %q (%v), want %q", tt.value, tt.keyFile, got, err, tt.want)
+		}
+	}
+	if _, err := LoadPassphrase("", filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing key file")
+	}
+}
@@ -0,0 +1,126 @@
+package strategy
+
+import (
+	"log/slog"
+	"math"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	componentsBBPeriod     = 20
+	componentsBBStdDev     = 2.0
+	componentsVolumeWindow = 20
+)
+
+// SignalComponents is the indicator snapshot behind a signal
+type SignalComponents struct {
+	ShortEMA decimal.Decimal `json:"short_ema"`
+	LongEMA  decimal.Decimal `json:"long_ema"`
+	RSI      decimal.Decimal `json:"rsi"`
+	// BBPosition locates the price within the Bollinger Bands: 0 at the lower
+	// band, 1 at the upper band, outside [0, 1] beyond them
+	BBPosition float64 `json:"bb_position"`
+	// VolumeZScore is the latest volume in standard deviations from the
+	// average of the preceding volumes
+	VolumeZScore       float64          `json:"volume_zscore"`
+	OrderBookImbalance float64          `json:"orderbook_imbalance"`
+	SpreadBps          float64          `json:"spread_bps"`
+	Weights            IndicatorWeights `json:"weights"`
+	Regime             MarketRegime     `json:"regime"`
+	ADX                float64          `json:"adx"`
+	// ModelProbability is set when a scoring model rated the signal
+	ModelProbability float64 `json:"model_probability,omitempty"`
+}
+
+// LogValue renders the components as a log group
+func (c *SignalComponents) LogValue() slog.Value {
+	if c == nil {
+		return slog.GroupValue()
+	}
+	return slog.GroupValue(
+		slog.String("short_ema", c.ShortEMA.StringFixed(4)),
+		slog.String("long_ema", c.LongEMA.StringFixed(4)),
+		slog.String("rsi", c.RSI.StringFixed(2)),
+		slog.Float64("bb_position", c.BBPosition),
+		slog.Float64("volume_zscore", c.VolumeZScore),
+		slog.Float64("orderbook_imbalance", c.OrderBookImbalance),
+		slog.Float64("spread_bps", c.SpreadBps),
+		slog.String("regime", string(c.Regime)),
+		slog.Float64("adx", c.ADX),
+		slog.Float64("ema_weight", c.Weights.EMA),
+		slog.Float64("rsi_weight", c.Weights.RSI),
+		slog.Float64("volume_weight", c.Weights.Volume),
+		slog.Float64("bb_weight", c.Weights.BB),
+		slog.Float64("orderbook_weight", c.Weights.OrderBook),
+		slog.Float64("model_probability", c.ModelProbability),
+	)
+}
+
+// buildComponents snapshots the indicators and weights used for the current signal
+func (sg *SignalGenerator) buildComponents(
+	prices, volumes []decimal.Decimal,
+	shortEMA, longEMA, rsi decimal.Decimal,
+) *SignalComponents {
+	components := &SignalComponents{
+		ShortEMA:     shortEMA,
+		LongEMA:      longEMA,
+		RSI:          rsi,
+		BBPosition:   bollingerPosition(prices, componentsBBPeriod, componentsBBStdDev),
+		VolumeZScore: volumeZScore(volumes, componentsVolumeWindow),
+		Weights:      sg.indicatorWeights,
+	}
+	regime := sg.Regime()
+	components.Regime = regime.Regime
+	components.ADX = regime.ADX
+	if sg.bookFeatures.Valid {
+		components.OrderBookImbalance = sg.bookFeatures.Imbalance
+		components.SpreadBps = sg.bookFeatures.SpreadBps
+	}
+	return components
+}
+
+// bollingerPosition returns where the latest price sits within the Bollinger
+// Bands, or 0.5 when the bands cannot be computed or have no width
+func bollingerPosition(prices []decimal.Decimal, period int, stdDev float64) float64 {
+	upper, _, lower := BollingerBands(prices, period, stdDev)
+	if len(upper) == 0 {
+		return 0.5
+	}
+	top := upper[len(upper)-1]
+	bottom := lower[len(lower)-1]
+	width := top.Sub(bottom)
+	if !width.IsPositive() {
+		return 0.5
+	}
+	return prices[len(prices)-1].Sub(bottom).Div(width).InexactFloat64()
+}
+
+// volumeZScore returns the z-score of the latest volume against the window
+// of volumes preceding it
+func volumeZScore(volumes []decimal.Decimal, window int) float64 {
+	if len(volumes) < 3 {
+		return 0
+	}
+	history := volumes[:len(volumes)-1]
+	if len(history) > window {
+		history = history[len(history)-window:]
+	}
+
+	mean := 0.0
+	for _, v := range history {
+		mean += v.InexactFloat64()
+	}
+	mean /= float64(len(history))
+
+	variance := 0.0
+	for _, v := range history {
+		diff := v.InexactFloat64() - mean
+		variance += diff * diff
+	}
+	std := math.Sqrt(variance / float64(len(history)))
+	if std == 0 {
+		return 0
+	}
+	return (volumes[len(volumes)-1].InexactFloat64() - mean) / std
+}
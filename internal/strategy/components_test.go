@@ -0,0 +1,85 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/shopspring/decimal"
+)
+
+func TestGenerateSignal_AttachesComponents(t *testing.T) {
+	sg := NewSignalGenerator(config.DefaultConfig())
+
+	prices := make([]decimal.Decimal, 30)
+	volumes := make([]decimal.Decimal, 30)
+	for i := range prices {
+		prices[i] = decimal.NewFromFloat(100.0 + float64(i)*0.8)
+		volumes[i] = decimal.NewFromFloat(1000.0 + float64(i%3)*10)
+	}
+
+	// Uptrend with a bid-heavy book triggers a buy
+	signal := sg.GenerateSignal("BTC-USD", prices, volumes, testOrderBook([]float64{3, 3, 3}, []float64{1, 1, 1}))
+	if signal.Type != SignalTypeEntry {
+		t.Fatalf("expected an entry signal, got %s (%s)", signal.Type, signal.Reason)
+	}
+
+	components := signal.Components
+	if components == nil {
+		t.Fatal("entry signal should carry its components")
+	}
+	if !components.ShortEMA.GreaterThan(components.LongEMA) {
+		t.Errorf("buy signal should have short EMA %s above long EMA %s", components.ShortEMA, components.LongEMA)
+	}
+	if components.Weights != sg.indicatorWeights {
+		t.Errorf("components should record the applied weights, got %+v", components.Weights)
+	}
+	if math.Abs(components.OrderBookImbalance-0.5) > 1e-9 {
+		t.Errorf("expected order book imbalance 0.5, got %f", components.OrderBookImbalance)
+	}
+	if components.BBPosition <= 0.5 {
+		t.Errorf("price at the top of an uptrend should sit in the upper band, got %f", components.BBPosition)
+	}
+
+	if none := sg.GenerateSignal("BTC-USD", prices[:5], volumes[:5], nil); none.Components != nil {
+		t.Error("signals without conditions met should not carry components")
+	}
+}
+
+func TestBollingerPosition(t *testing.T) {
+	flat := make([]decimal.Decimal, 20)
+	for i := range flat {
+		flat[i] = decimal.NewFromInt(100)
+	}
+	if pos := bollingerPosition(flat, 20, 2); pos != 0.5 {
+		t.Errorf("flat prices should sit mid-band, got %f", pos)
+	}
+	if pos := bollingerPosition(flat[:5], 20, 2); pos != 0.5 {
+		t.Errorf("insufficient data should default to mid-band, got %f", pos)
+	}
+
+	rising := make([]decimal.Decimal, 20)
+	for i := range rising {
+		rising[i] = decimal.NewFromInt(int64(100 + i))
+	}
+	if pos := bollingerPosition(rising, 20, 2); pos <= 0.5 || pos > 1 {
+		t.Errorf("latest price of a rising series should be in the upper band, got %f", pos)
+	}
+}
+
+func TestVolumeZScore(t *testing.T) {
+	volumes := []decimal.Decimal{
+		decimal.NewFromInt(90),
+		decimal.NewFromInt(110),
+		decimal.NewFromInt(90),
+		decimal.NewFromInt(110),
+		decimal.NewFromInt(130),
+	}
+	// Preceding volumes average 100 with a standard deviation of 10
+	if z := volumeZScore(volumes, 20); math.Abs(z-3) > 1e-9 {
+		t.Errorf("expected z-score 3, got %f", z)
+	}
+	if z := volumeZScore(volumes[:2], 20); z != 0 {
+		t.Errorf("too few volumes should score 0, got %f", z)
+	}
+}
@@ -0,0 +1,36 @@
+package strategy
+
+import (
+	"math"
+	"time"
+
+	"github.com/guyghost/constantine/internal/config"
+)
+
+// debounced reports whether signal is held back after last, emitted at
+// lastAt. A signal repeating the type and side of the last one is only
+// emitted once the price or the strength moved enough since, and no signal
+// but an exit follows another within SignalMinInterval.
+func debounced(cfg *config.Config, signal, last *Signal, lastAt, now time.Time) bool {
+	if last == nil {
+		return false
+	}
+	tooSoon := now.Sub(lastAt) < cfg.SignalMinInterval
+	if signal.Type != last.Type || signal.Side != last.Side {
+		// Exits are never held back
+		return tooSoon && signal.Type != SignalTypeExit
+	}
+	return tooSoon || !signalMoved(cfg, signal, last)
+}
+
+// signalMoved reports whether the price or the strength of signal moved
+// from last by the configured deltas; never without them
+func signalMoved(cfg *config.Config, signal, last *Signal) bool {
+	if cfg.SignalMinPriceDeltaPercent > 0 && last.Price.IsPositive() {
+		move := signal.Price.Sub(last.Price).Abs().Div(last.Price).InexactFloat64() * 100
+		if move >= cfg.SignalMinPriceDeltaPercent {
+			return true
+		}
+	}
+	return cfg.SignalMinStrengthDelta > 0 && math.Abs(signal.Strength-last.Strength) >= cfg.SignalMinStrengthDelta
+}
@@ -0,0 +1,55 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func TestDebounced(t *testing.T) {
+	config := DefaultConfig()
+	config.SignalMinInterval = 30 * time.Second
+	config.SignalMinPriceDeltaPercent = 1
+	config.SignalMinStrengthDelta = 0.2
+
+	at := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+	last := &Signal{Type: SignalTypeEntry, Side: exchanges.OrderSideBuy, Price: decimal.NewFromInt(100), Strength: 0.5}
+	signal := func(signalType SignalType, side exchanges.OrderSide, price, strength float64) *Signal {
+		return &Signal{Type: signalType, Side: side, Price: decimal.NewFromFloat(price), Strength: strength}
+	}
+
+	tests := []struct {
+		name   string
+		signal *Signal
+		after  time.Duration
+		want   bool
+	}{
+		{"same signal", signal(SignalTypeEntry, exchanges.OrderSideBuy, 100.5, 0.6), time.Minute, true},
+		{"price moved", signal(SignalTypeEntry, exchanges.OrderSideBuy, 98, 0.5), time.Minute, false},
+		{"strength moved", signal(SignalTypeEntry, exchanges.OrderSideBuy, 100, 0.8), time.Minute, false},
+		{"moved too soon", signal(SignalTypeEntry, exchanges.OrderSideBuy, 98, 0.9), 10 * time.Second, true},
+		{"side flip", signal(SignalTypeEntry, exchanges.OrderSideSell, 100, 0.5), time.Minute, false},
+		{"side flip too soon", signal(SignalTypeEntry, exchanges.OrderSideSell, 100, 0.5), 10 * time.Second, true},
+		{"exit too soon", signal(SignalTypeExit, exchanges.OrderSideSell, 100, 0.5), time.Second, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := debounced(config, tt.signal, last, at, at.Add(tt.after)); got != tt.want {
+				t.Errorf("debounced() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if debounced(config, last, nil, time.Time{}, at) {
+		t.Error("the first signal should be emitted")
+	}
+
+	// Without deltas, a repeated signal is never emitted again
+	config.SignalMinPriceDeltaPercent = 0
+	config.SignalMinStrengthDelta = 0
+	if !debounced(config, signal(SignalTypeEntry, exchanges.OrderSideBuy, 50, 1), last, at, at.Add(time.Hour)) {
+		t.Error("repeated signals should be suppressed without deltas")
+	}
+}
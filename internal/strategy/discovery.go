@@ -0,0 +1,188 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// universeKeepBand widens the rank a symbol already traded must hold to stay
+// in the universe, so symbols ranked around the cutoff do not churn on every
+// refresh
+const universeKeepBand = 1.25
+
+// DiscoveryConfig selects which of the markets listed by the exchanges make
+// up the trading universe
+type DiscoveryConfig struct {
+	MaxSymbols   int             // Size of the universe, 0 for no limit
+	MinVolume24h decimal.Decimal // Markets trading less over 24h are left out
+	Quote        string          // Quote currency markets must trade in, empty for any
+	Pinned       []string        // Symbols always traded, counted in MaxSymbols
+	// Preferred is the exchange a symbol it lists is traded on, whatever its
+	// volume elsewhere. Others are traded where their volume is highest.
+	Preferred string
+}
+
+// DiscoveredMarket is a market listed by one of the exchanges
+type DiscoveredMarket struct {
+	exchanges.Market
+	Exchange string // Exchange the symbol is traded on
+}
+
+// UniverseChange lists the symbols a refresh added to and removed from the
+// trading universe
+type UniverseChange struct {
+	Added   []DiscoveredMarket
+	Removed []string
+}
+
+// discoverMarkets lists the markets of the connected exchanges that can list
+// them, most traded first. A symbol listed on several exchanges is kept once,
+// on the preferred one or else the one trading it most. Exchanges failing to
+// list their markets are returned with their error.
+func discoverMarkets(ctx context.Context, sources map[string]exchanges.Exchange, cfg DiscoveryConfig) ([]DiscoveredMarket, map[string]error) {
+	failed := make(map[string]error)
+	bySymbol := make(map[string]DiscoveredMarket)
+	for name, exchange := range sources {
+		lister, ok := exchange.(exchanges.MarketLister)
+		if !ok || !exchange.IsConnected() {
+			continue
+		}
+		markets, err := lister.ListMarkets(ctx)
+		if err != nil {
+			failed[name] = err
+			continue
+		}
+		for _, market := range markets {
+			if cfg.Quote != "" && !strings.EqualFold(market.Quote, cfg.Quote) {
+				continue
+			}
+			if market.Volume24h.LessThan(cfg.MinVolume24h) {
+				continue
+			}
+			if listed, exists := bySymbol[market.Symbol]; exists && !replacesListing(listed, market, name, cfg.Preferred) {
+				continue
+			}
+			bySymbol[market.Symbol] = DiscoveredMarket{Market: market, Exchange: name}
+		}
+	}
+
+	ranked := make([]DiscoveredMarket, 0, len(bySymbol))
+	for _, market := range bySymbol {
+		ranked = append(ranked, market)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if c := ranked[i].Volume24h.Cmp(ranked[j].Volume24h); c != 0 {
+			return c > 0
+		}
+		return ranked[i].Symbol < ranked[j].Symbol
+	})
+	return ranked, failed
+}
+
+// replacesListing reports whether market, listed by exchange, should replace the
+// listing of the same symbol found so far
+func replacesListing(listed DiscoveredMarket, market exchanges.Market, exchange, preferred string) bool {
+	switch preferred {
+	case listed.Exchange:
+		return false
+	case exchange:
+		return true
+	}
+	return market.Volume24h.GreaterThan(listed.Volume24h)
+}
+
+// selectUniverse picks the next universe, by symbol to the exchange it is
+// traded on, out of the ranked markets. Pinned symbols come first, then the
+// current symbols still ranked within the keep band or listed on an exchange
+// that failed to answer, then the best ranked newcomers.
+func selectUniverse(ranked []DiscoveredMarket, current map[string]string, failed map[string]error, cfg DiscoveryConfig) map[string]string {
+	next := make(map[string]string)
+	full := func() bool {
+		return cfg.MaxSymbols > 0 && len(next) >= cfg.MaxSymbols
+	}
+	exchangeOf := make(map[string]string, len(ranked))
+	for _, market := range ranked {
+		exchangeOf[market.Symbol] = market.Exchange
+	}
+
+	for _, symbol := range cfg.Pinned {
+		if name, listed := exchangeOf[symbol]; listed {
+			next[symbol] = name
+		} else {
+			next[symbol] = current[symbol]
+		}
+	}
+
+	band := len(ranked)
+	if cfg.MaxSymbols > 0 {
+		band = min(band, int(math.Ceil(float64(cfg.MaxSymbols)*universeKeepBand)))
+	}
+	for _, market := range ranked[:band] {
+		if _, traded := current[market.Symbol]; traded && !full() {
+			next[market.Symbol] = market.Exchange
+		}
+	}
+
+	var unanswered []string
+	for symbol, name := range current {
+		if _, down := failed[name]; down {
+			unanswered = append(unanswered, symbol)
+		}
+	}
+	sort.Strings(unanswered)
+	for _, symbol := range unanswered {
+		if _, kept := next[symbol]; !kept && !full() {
+			next[symbol] = current[symbol]
+		}
+	}
+
+	for _, market := range ranked {
+		if full() {
+			break
+		}
+		if _, kept := next[market.Symbol]; !kept {
+			next[market.Symbol] = market.Exchange
+		}
+	}
+	return next
+}
+
+// diffUniverse returns the change from the current universe to next, the
+// added markets in rank order
+func diffUniverse(ranked []DiscoveredMarket, current, next map[string]string) UniverseChange {
+	var change UniverseChange
+	listed := make(map[string]bool, len(ranked))
+	for _, market := range ranked {
+		listed[market.Symbol] = true
+		if _, traded := current[market.Symbol]; traded {
+			continue
+		}
+		if _, added := next[market.Symbol]; added {
+			change.Added = append(change.Added, market)
+		}
+	}
+	// Pinned symbols the exchanges did not list
+	var unlisted []string
+	for symbol := range next {
+		if _, traded := current[symbol]; !traded && !listed[symbol] {
+			unlisted = append(unlisted, symbol)
+		}
+	}
+	sort.Strings(unlisted)
+	for _, symbol := range unlisted {
+		change.Added = append(change.Added, DiscoveredMarket{Market: exchanges.Market{Symbol: symbol}, Exchange: next[symbol]})
+	}
+
+	for symbol := range current {
+		if _, kept := next[symbol]; !kept {
+			change.Removed = append(change.Removed, symbol)
+		}
+	}
+	sort.Strings(change.Removed)
+	return change
+}
@@ -0,0 +1,223 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/symbolmanager"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+// listingExchange is a test exchange listing fixed markets
+type listingExchange struct {
+	*testutils.TestExchange
+	markets []exchanges.Market
+	err     error
+}
+
+func (e *listingExchange) ListMarkets(ctx context.Context) ([]exchanges.Market, error) {
+	return e.markets, e.err
+}
+
+func newListingExchange(name string, markets ...exchanges.Market) *listingExchange {
+	return &listingExchange{TestExchange: testutils.NewTestExchange(name), markets: markets}
+}
+
+func usdMarket(symbol string, volume int64) exchanges.Market {
+	return exchanges.Market{Symbol: symbol, Quote: "USD", Volume24h: decimal.NewFromInt(volume)}
+}
+
+func TestDiscoverMarkets(t *testing.T) {
+	dydx := newListingExchange("dydx", usdMarket("BTC-USD", 900), usdMarket("ETH-USD", 300), usdMarket("DOGE-USD", 5))
+	coinbase := newListingExchange("coinbase", usdMarket("ETH-USD", 500), usdMarket("SOL-USD", 200),
+		exchanges.Market{Symbol: "BTC-EUR", Quote: "EUR", Volume24h: decimal.NewFromInt(1000)})
+	offline := newListingExchange("offline", usdMarket("XRP-USD", 10000))
+	offline.ConnectedValue = false
+	broken := newListingExchange("broken")
+	broken.err = errors.New("unavailable")
+	sources := map[string]exchanges.Exchange{
+		"dydx":     dydx,
+		"coinbase": coinbase,
+		"offline":  offline,
+		"broken":   broken,
+		"plain":    testutils.NewTestExchange("plain"),
+	}
+
+	ranked, failed := discoverMarkets(context.Background(), sources, DiscoveryConfig{
+		MinVolume24h: decimal.NewFromInt(10),
+		Quote:        "usd",
+	})
+
+	var got []string
+	for _, market := range ranked {
+		got = append(got, market.Symbol+"@"+market.Exchange)
+	}
+	want := []string{"BTC-USD@dydx", "ETH-USD@coinbase", "SOL-USD@coinbase"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if _, ok := failed["broken"]; !ok || len(failed) != 1 {
+		t.Errorf("Expected only the broken exchange to fail, got %v", failed)
+	}
+
+	ranked, _ = discoverMarkets(context.Background(), sources, DiscoveryConfig{Quote: "USD", Preferred: "dydx"})
+	for _, market := range ranked {
+		if market.Symbol == "ETH-USD" && market.Exchange != "dydx" {
+			t.Errorf("Expected ETH-USD on the preferred exchange, got %s", market.Exchange)
+		}
+	}
+}
+
+func TestSelectUniverse(t *testing.T) {
+	ranked := []DiscoveredMarket{
+		{Market: usdMarket("BTC-USD", 900), Exchange: "dydx"},
+		{Market: usdMarket("ETH-USD", 800), Exchange: "dydx"},
+		{Market: usdMarket("SOL-USD", 700), Exchange: "dydx"},
+		{Market: usdMarket("AVAX-USD", 600), Exchange: "dydx"},
+		{Market: usdMarket("LINK-USD", 500), Exchange: "dydx"},
+		{Market: usdMarket("ARB-USD", 400), Exchange: "dydx"},
+	}
+
+	tests := []struct {
+		name    string
+		current map[string]string
+		failed  map[string]error
+		pinned  []string
+		want    []string
+	}{
+		{
+			name: "best ranked from scratch",
+			want: []string{"BTC-USD", "ETH-USD", "SOL-USD", "AVAX-USD"},
+		},
+		{
+			name:    "symbols within the keep band stay",
+			current: map[string]string{"LINK-USD": "dydx", "ARB-USD": "dydx"},
+			want:    []string{"LINK-USD", "BTC-USD", "ETH-USD", "SOL-USD"},
+		},
+		{
+			name:    "unlisted symbols leave",
+			current: map[string]string{"XRP-USD": "dydx"},
+			want:    []string{"BTC-USD", "ETH-USD", "SOL-USD", "AVAX-USD"},
+		},
+		{
+			name:    "symbols of exchanges failing to answer stay",
+			current: map[string]string{"XRP-USD": "coinbase"},
+			failed:  map[string]error{"coinbase": errors.New("timeout")},
+			want:    []string{"XRP-USD", "BTC-USD", "ETH-USD", "SOL-USD"},
+		},
+		{
+			name:   "pinned symbols come first",
+			pinned: []string{"ARB-USD", "PEPE-USD"},
+			want:   []string{"ARB-USD", "PEPE-USD", "BTC-USD", "ETH-USD"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := selectUniverse(ranked, tt.current, tt.failed, DiscoveryConfig{MaxSymbols: 4, Pinned: tt.pinned})
+			got := make([]string, 0, len(next))
+			for symbol := range next {
+				got = append(got, symbol)
+			}
+			sort.Strings(got)
+			want := slices.Clone(tt.want)
+			sort.Strings(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("Expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestIntegratedStrategyEngine_Discovery(t *testing.T) {
+	dydx := newListingExchange("dydx", usdMarket("BTC-USD", 900), usdMarket("ETH-USD", 800), usdMarket("SOL-USD", 700))
+	engine := NewIntegratedStrategyEngine(config.DefaultConfig(), []string{"BTC-USD", "DOGE-USD"}, dydx, time.Minute)
+	engine.EnableDiscovery(func() map[string]exchanges.Exchange {
+		return map[string]exchanges.Exchange{"dydx": dydx}
+	}, DiscoveryConfig{MaxSymbols: 2})
+
+	var changes []UniverseChange
+	engine.SetUniverseCallback(func(change UniverseChange) {
+		changes = append(changes, change)
+	})
+
+	engine.updateSymbolSelection(context.Background())
+	if got := engine.GetTradingSymbols(); !slices.Equal(got, []string{"BTC-USD", "ETH-USD"}) {
+		t.Errorf("Expected the 2 most traded symbols, got %v", got)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("Expected one universe change, got %d", len(changes))
+	}
+	if len(changes[0].Added) != 1 || changes[0].Added[0].Symbol != "ETH-USD" || changes[0].Added[0].Exchange != "dydx" {
+		t.Errorf("Expected ETH-USD to be added from dydx, got %+v", changes[0].Added)
+	}
+	if !slices.Equal(changes[0].Removed, []string{"DOGE-USD"}) {
+		t.Errorf("Expected DOGE-USD to be removed, got %v", changes[0].Removed)
+	}
+
+	// Nothing changes while the listing does not
+	engine.updateSymbolSelection(context.Background())
+	if len(changes) != 1 {
+		t.Errorf("Expected no change on an identical listing, got %+v", changes[1:])
+	}
+
+	// A failed listing keeps the universe
+	dydx.err = errors.New("unavailable")
+	engine.updateSymbolSelection(context.Background())
+	if got := engine.GetTradingSymbols(); len(changes) != 1 || !slices.Equal(got, []string{"BTC-USD", "ETH-USD"}) {
+		t.Errorf("Expected the universe to be kept when listing fails, got %v", got)
+	}
+}
+
+func TestStrategyOrchestrator_AddRemoveSymbol(t *testing.T) {
+	manager := symbolmanager.NewSymbolManager()
+	primary := testutils.NewTestExchange("primary")
+	other := testutils.NewTestExchange("other")
+	orchestrator := NewStrategyOrchestrator(manager, primary)
+	orchestrator.SetExchangeResolver(func(symbol string) (exchanges.Exchange, error) {
+		if symbol == "SOL-USD" {
+			return other, nil
+		}
+		return nil, errors.New("not mapped")
+	})
+
+	cfg := config.DefaultConfig()
+	cfg.Symbol = "SOL-USD"
+	strategy, err := orchestrator.AddSymbol(context.Background(), symbolmanager.SymbolConfig{
+		Symbol:         "SOL-USD",
+		StrategyConfig: cfg,
+		Enabled:        true,
+	})
+	if err != nil {
+		t.Fatalf("AddSymbol failed: %v", err)
+	}
+	if strategy.exchange != other {
+		t.Error("Expected the strategy to trade on the resolved exchange")
+	}
+	if !manager.IsSymbolActive("SOL-USD") {
+		t.Error("Expected the symbol to be added to the symbol manager")
+	}
+
+	if err := strategy.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start strategy: %v", err)
+	}
+	if err := orchestrator.RemoveSymbol("SOL-USD"); err != nil {
+		t.Fatalf("RemoveSymbol failed: %v", err)
+	}
+	if strategy.IsRunning() {
+		t.Error("Expected the removed strategy to be stopped")
+	}
+	if _, err := orchestrator.GetSymbolStrategy("SOL-USD"); err == nil {
+		t.Error("Expected the strategy to be removed")
+	}
+	if len(manager.GetAllSymbols()) != 0 {
+		t.Errorf("Expected the symbol to leave the symbol manager, got %v", manager.GetAllSymbols())
+	}
+}
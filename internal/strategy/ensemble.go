@@ -0,0 +1,247 @@
+package strategy
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/ringbuf"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// EnsembleMode selects how the votes of strategies are weighted
+type EnsembleMode string
+
+const (
+	// EnsembleMajority gives every strategy one vote
+	EnsembleMajority EnsembleMode = "majority"
+	// EnsembleWeighted weights each vote by the recent accuracy of its
+	// strategy
+	EnsembleWeighted EnsembleMode = "weighted"
+)
+
+// EnsembleConfig controls how the entry signals of several strategies on a
+// symbol are combined
+type EnsembleConfig struct {
+	Mode EnsembleMode
+	// Window is how long votes on a symbol are gathered for, from the first
+	Window time.Duration
+	// MinVotes is the number of strategies that must vote on a symbol
+	MinVotes int
+	// Quorum is the share of the vote weight the winning side must exceed
+	Quorum float64
+	// Vetoes are the strategies whose vote against a side blocks it
+	Vetoes []string
+	// AccuracyWindow is the number of recent outcomes the accuracy of a
+	// strategy is measured over
+	AccuracyWindow int
+}
+
+// DefaultEnsembleConfig returns the default ensemble configuration
+func DefaultEnsembleConfig() EnsembleConfig {
+	return EnsembleConfig{
+		Mode:           EnsembleMajority,
+		Window:         5 * time.Second,
+		MinVotes:       2,
+		Quorum:         0.5,
+		AccuracyWindow: 20,
+	}
+}
+
+// vote is the latest entry signal of a strategy on a symbol
+type vote struct {
+	strategy string
+	signal   *Signal
+}
+
+// Ensemble combines the entry signals several strategies emit on the same
+// symbol into a single one before execution. Exit signals are passed through
+// as they come.
+type Ensemble struct {
+	cfg  EnsembleConfig
+	emit func(*Signal)
+
+	mu       sync.Mutex
+	pending  map[string][]vote                // Votes gathered on each symbol
+	open     map[string]map[string]bool       // Whether each strategy voted with the entry open on a symbol
+	outcomes map[string]*ringbuf.Buffer[bool] // Recent outcomes each strategy called right
+}
+
+// NewEnsemble creates an ensemble passing the combined signals to emit
+func NewEnsemble(cfg EnsembleConfig, emit func(*Signal)) *Ensemble {
+	return &Ensemble{
+		cfg:      cfg,
+		emit:     emit,
+		pending:  make(map[string][]vote),
+		open:     make(map[string]map[string]bool),
+		outcomes: make(map[string]*ringbuf.Buffer[bool]),
+	}
+}
+
+// Submit adds the vote of signal's strategy on its symbol, replacing an
+// earlier one of the same strategy. Votes are resolved once the window
+// started by the first of them elapses.
+func (e *Ensemble) Submit(signal *Signal) {
+	if signal.Type != SignalTypeEntry {
+		e.emit(signal)
+		return
+	}
+
+	name := signal.Strategy
+	if name == "" {
+		name = MainInstance
+	}
+
+	e.mu.Lock()
+	votes := e.pending[signal.Symbol]
+	first := len(votes) == 0
+	votes = slices.DeleteFunc(votes, func(v vote) bool { return v.strategy == name })
+	e.pending[signal.Symbol] = append(votes, vote{strategy: name, signal: signal})
+	e.mu.Unlock()
+
+	if first {
+		time.AfterFunc(e.cfg.Window, func() { e.resolve(signal.Symbol) })
+	}
+}
+
+// resolve combines the votes gathered on symbol and emits the entry of the
+// winning side, unless it lacks votes, quorum or is vetoed
+func (e *Ensemble) resolve(symbol string) {
+	e.mu.Lock()
+	votes := e.pending[symbol]
+	delete(e.pending, symbol)
+	if len(votes) == 0 {
+		e.mu.Unlock()
+		return
+	}
+
+	weights := make(map[exchanges.OrderSide]float64, 2)
+	total := 0.0
+	for _, v := range votes {
+		weight := e.weight(v.strategy)
+		weights[v.signal.Side] += weight
+		total += weight
+	}
+	side := exchanges.OrderSideBuy
+	if weights[exchanges.OrderSideSell] > weights[exchanges.OrderSideBuy] {
+		side = exchanges.OrderSideSell
+	}
+	share := 0.0
+	if total > 0 {
+		share = weights[side] / total
+	}
+
+	reason := ""
+	switch {
+	case len(votes) < e.cfg.MinVotes:
+		reason = "ensemble_votes"
+	case share <= e.cfg.Quorum:
+		reason = "ensemble_quorum"
+	default:
+		for _, v := range votes {
+			if v.signal.Side != side && slices.Contains(e.cfg.Vetoes, v.strategy) {
+				reason = "ensemble_veto"
+			}
+		}
+	}
+	if reason != "" {
+		e.mu.Unlock()
+		logger.Component("strategy").Info("ensemble entry blocked",
+			"symbol", symbol,
+			"reason", reason,
+			"votes", len(votes),
+			"side", side,
+			"share", share)
+		telemetry.RecordSignalBlocked(symbol, reason)
+		return
+	}
+
+	// The strongest agreeing signal carries the entry, at the mean strength
+	// of its side
+	var lead *Signal
+	strength := 0.0
+	agreed := 0
+	sides := make(map[string]bool, len(votes))
+	for _, v := range votes {
+		sides[v.strategy] = v.signal.Side == side
+		if v.signal.Side != side {
+			continue
+		}
+		strength += v.signal.Strength
+		agreed++
+		if lead == nil || v.signal.Strength > lead.Strength {
+			lead = v.signal
+		}
+	}
+	e.open[symbol] = sides
+	e.mu.Unlock()
+
+	combined := *lead
+	combined.Strength = strength / float64(agreed)
+	combined.Reason = fmt.Sprintf("ensemble %d/%d votes (%.0f%%): %s", agreed, len(votes), share*100, lead.Reason)
+	e.emit(&combined)
+}
+
+// weight returns the vote weight of strategy. Must be called with the lock
+// held.
+func (e *Ensemble) weight(strategy string) float64 {
+	if e.cfg.Mode != EnsembleWeighted {
+		return 1
+	}
+	return e.accuracy(strategy)
+}
+
+// accuracy returns the share of recent outcomes strategy called right, with
+// one right and one wrong call assumed so new strategies start at one half.
+// Must be called with the lock held.
+func (e *Ensemble) accuracy(strategy string) float64 {
+	right, total := 1.0, 2.0
+	if outcomes, ok := e.outcomes[strategy]; ok {
+		for correct := range outcomes.All() {
+			if correct {
+				right++
+			}
+			total++
+		}
+	}
+	return right / total
+}
+
+// RecordTrade credits the strategies that voted on the entry of symbol with
+// the realized PnL of its trade: those that voted with a winning entry, or
+// against a losing one, called it right
+func (e *Ensemble) RecordTrade(symbol string, pnl decimal.Decimal) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	sides, ok := e.open[symbol]
+	if !ok {
+		return
+	}
+	delete(e.open, symbol)
+	for strategy, with := range sides {
+		outcomes, ok := e.outcomes[strategy]
+		if !ok {
+			outcomes = ringbuf.New[bool](max(e.cfg.AccuracyWindow, 1))
+			e.outcomes[strategy] = outcomes
+		}
+		outcomes.Push(with == pnl.IsPositive())
+	}
+}
+
+// Accuracies returns the recent accuracy of each strategy that has had an
+// outcome recorded
+func (e *Ensemble) Accuracies() map[string]float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	accuracies := make(map[string]float64, len(e.outcomes))
+	for strategy := range e.outcomes {
+		accuracies[strategy] = e.accuracy(strategy)
+	}
+	return accuracies
+}
@@ -0,0 +1,122 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func ensembleVote(strategy string, side exchanges.OrderSide, strength float64) *Signal {
+	return &Signal{
+		Type:     SignalTypeEntry,
+		Side:     side,
+		Symbol:   "BTC-USD",
+		Price:    decimal.NewFromInt(100),
+		Strength: strength,
+		Reason:   strategy,
+		Strategy: strategy,
+	}
+}
+
+// newTestEnsemble returns an ensemble whose votes are only resolved by the
+// test, and the signals it emitted
+func newTestEnsemble(cfg EnsembleConfig) (*Ensemble, *[]*Signal) {
+	cfg.Window = time.Hour
+	var emitted []*Signal
+	return NewEnsemble(cfg, func(signal *Signal) { emitted = append(emitted, signal) }), &emitted
+}
+
+func TestEnsemble_MajorityVote(t *testing.T) {
+	ensemble, emitted := newTestEnsemble(DefaultEnsembleConfig())
+
+	ensemble.Submit(ensembleVote("", exchanges.OrderSideBuy, 0.6))
+	ensemble.Submit(ensembleVote("fast", exchanges.OrderSideBuy, 0.9))
+	ensemble.Submit(ensembleVote("slow", exchanges.OrderSideSell, 0.7))
+	ensemble.resolve("BTC-USD")
+
+	if len(*emitted) != 1 {
+		t.Fatalf("expected one combined entry, got %d", len(*emitted))
+	}
+	signal := (*emitted)[0]
+	if signal.Side != exchanges.OrderSideBuy || signal.Strategy != "fast" {
+		t.Errorf("expected the strongest buy to carry the entry, got %s from %q", signal.Side, signal.Strategy)
+	}
+	if signal.Strength != 0.75 {
+		t.Errorf("expected the mean strength of the buys, got %v", signal.Strength)
+	}
+
+	// Exits are not voted on
+	ensemble.Submit(&Signal{Type: SignalTypeExit, Symbol: "BTC-USD"})
+	if len(*emitted) != 2 {
+		t.Error("expected the exit passed through")
+	}
+}
+
+func TestEnsemble_Blocked(t *testing.T) {
+	cfg := DefaultEnsembleConfig()
+	cfg.Vetoes = []string{"slow"}
+
+	tests := []struct {
+		name  string
+		votes []*Signal
+	}{
+		{"too few votes", []*Signal{ensembleVote("fast", exchanges.OrderSideBuy, 0.8)}},
+		{"split vote", []*Signal{
+			ensembleVote("fast", exchanges.OrderSideBuy, 0.8),
+			ensembleVote("main", exchanges.OrderSideSell, 0.8),
+		}},
+		{"vetoed", []*Signal{
+			ensembleVote("fast", exchanges.OrderSideBuy, 0.8),
+			ensembleVote("main", exchanges.OrderSideBuy, 0.8),
+			ensembleVote("slow", exchanges.OrderSideSell, 0.8),
+		}},
+		{"vote replaced by the later one", []*Signal{
+			ensembleVote("fast", exchanges.OrderSideBuy, 0.8),
+			ensembleVote("fast", exchanges.OrderSideBuy, 0.9),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ensemble, emitted := newTestEnsemble(cfg)
+			for _, signal := range tt.votes {
+				ensemble.Submit(signal)
+			}
+			ensemble.resolve("BTC-USD")
+			if len(*emitted) != 0 {
+				t.Errorf("expected the entry blocked, got %+v", (*emitted)[0])
+			}
+		})
+	}
+}
+
+func TestEnsemble_WeightedByAccuracy(t *testing.T) {
+	cfg := DefaultEnsembleConfig()
+	cfg.Mode = EnsembleWeighted
+	ensemble, emitted := newTestEnsemble(cfg)
+
+	// "slow" voted against the winning entries of "main" and "fast"
+	for range 3 {
+		ensemble.Submit(ensembleVote("main", exchanges.OrderSideSell, 0.8))
+		ensemble.Submit(ensembleVote("fast", exchanges.OrderSideSell, 0.8))
+		ensemble.Submit(ensembleVote("slow", exchanges.OrderSideBuy, 0.8))
+		ensemble.resolve("BTC-USD")
+		ensemble.RecordTrade("BTC-USD", decimal.NewFromInt(10))
+	}
+	accuracies := ensemble.Accuracies()
+	if accuracies["fast"] != 0.8 || accuracies["slow"] != 0.2 {
+		t.Fatalf("expected accuracies of 0.8 and 0.2, got %v", accuracies)
+	}
+
+	// With a new strategy at one half, the two buys now weigh less than the
+	// accurate sell
+	*emitted = nil
+	ensemble.Submit(ensembleVote("slow", exchanges.OrderSideBuy, 0.8))
+	ensemble.Submit(ensembleVote("new", exchanges.OrderSideBuy, 0.8))
+	ensemble.Submit(ensembleVote("fast", exchanges.OrderSideSell, 0.8))
+	ensemble.resolve("BTC-USD")
+	if len(*emitted) != 1 || (*emitted)[0].Side != exchanges.OrderSideSell {
+		t.Errorf("expected the accurate strategy's sell to win, got %v", *emitted)
+	}
+}
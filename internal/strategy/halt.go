@@ -0,0 +1,44 @@
+package strategy
+
+import (
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// HaltCheck reports whether trading in symbol is halted
+type HaltCheck func(symbol string) bool
+
+// SetHaltCheck stops entries from being signaled while check reports the
+// strategy's symbol halted. Exits are still signaled.
+func (s *ScalpingStrategy) SetHaltCheck(check HaltCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.haltCheck = check
+}
+
+// entryHalted reports whether signal is an entry on a halted symbol
+func (s *ScalpingStrategy) entryHalted(signal *Signal) bool {
+	s.mu.RLock()
+	check := s.haltCheck
+	s.mu.RUnlock()
+	if check == nil || signal.Type != SignalTypeEntry || !check(signal.Symbol) {
+		return false
+	}
+
+	logger.Component("strategy").Debug("entry dropped: trading halted",
+		"symbol", signal.Symbol,
+		"side", signal.Side)
+	telemetry.RecordSignalBlocked(signal.Symbol, "halted")
+	return true
+}
+
+// SetHaltCheck sets the halt check of every strategy, current and started
+// later
+func (so *StrategyOrchestrator) SetHaltCheck(check HaltCheck) {
+	so.mu.Lock()
+	defer so.mu.Unlock()
+	so.haltCheck = check
+	for _, strategy := range so.strategies {
+		strategy.SetHaltCheck(check)
+	}
+}
@@ -0,0 +1,31 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+)
+
+func TestScalpingStrategy_HaltDropsEntries(t *testing.T) {
+	strategy := NewScalpingStrategy(DefaultConfig(), &MockExchangeForStrategy{})
+	entry := &Signal{Type: SignalTypeEntry, Side: exchanges.OrderSideBuy, Symbol: "BTC-USD"}
+	exit := &Signal{Type: SignalTypeExit, Side: exchanges.OrderSideBuy, Symbol: "BTC-USD"}
+
+	if strategy.entryHalted(entry) {
+		t.Error("entries should pass without a halt check")
+	}
+
+	halted := map[string]bool{"BTC-USD": true}
+	strategy.SetHaltCheck(func(symbol string) bool { return halted[symbol] })
+	if !strategy.entryHalted(entry) {
+		t.Error("entries on a halted symbol should be dropped")
+	}
+	if strategy.entryHalted(exit) {
+		t.Error("exits should pass while halted")
+	}
+
+	halted["BTC-USD"] = false
+	if strategy.entryHalted(entry) {
+		t.Error("entries should pass once trading resumes")
+	}
+}
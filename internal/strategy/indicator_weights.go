@@ -7,15 +7,18 @@ import (
 
 	"github.com/guyghost/constantine/internal/config"
 	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/ringbuf"
 	"github.com/shopspring/decimal"
 )
 
 // IndicatorWeights represents the dynamic weights for technical indicators
 type IndicatorWeights struct {
-	EMA    float64 // Weight for EMA (crossover trend)
-	RSI    float64 // Weight for RSI (momentum)
-	Volume float64 // Weight for volume
-	BB     float64 // Weight for Bollinger Bands
+	EMA    float64 `json:"ema"`    // Weight for EMA (crossover trend)
+	RSI    float64 `json:"rsi"`    // Weight for RSI (momentum)
+	Volume float64 `json:"volume"` // Weight for volume
+	BB     float64 `json:"bb"`     // Weight for Bollinger Bands
+	// OrderBook weights order book microstructure; zero when no usable book
+	OrderBook float64 `json:"orderbook"`
 }
 
 // MarketCondition represents market conditions at a specific timestamp
@@ -29,18 +32,16 @@ type MarketCondition struct {
 
 // WeightCalculator manages dynamic weight calculations based on market conditions
 type WeightCalculator struct {
-	config         *config.Config
-	history        []MarketCondition
-	maxHistorySize int
-	mu             sync.RWMutex
+	config  *config.Config
+	history *ringbuf.Buffer[MarketCondition]
+	mu      sync.RWMutex
 }
 
 // NewWeightCalculator creates a new WeightCalculator instance
 func NewWeightCalculator(cfg *config.Config) *WeightCalculator {
 	return &WeightCalculator{
-		config:         cfg,
-		history:        make([]MarketCondition, 0),
-		maxHistorySize: 50,
+		config:  cfg,
+		history: ringbuf.New[MarketCondition](50),
 	}
 }
 
@@ -136,6 +137,39 @@ func (wc *WeightCalculator) CalculateDynamicWeights(
 	return normalized
 }
 
+// ApplyOrderBookWeight gives order book features their own weight, scaled by
+// how informative the book is: a tight spread lets the book weigh in, a strong
+// imbalance increases its say, and a spread at or beyond maxSpreadBps removes
+// it. The other weights are scaled down so the total still sums to 1.
+func (wc *WeightCalculator) ApplyOrderBookWeight(weights IndicatorWeights, features OrderBookFeatures, maxSpreadBps float64) IndicatorWeights {
+	weights.OrderBook = 0
+	if !features.Valid || maxSpreadBps <= 0 {
+		return weights
+	}
+
+	quality := 1 - features.SpreadBps/maxSpreadBps
+	if quality <= 0 {
+		return weights
+	}
+	if quality > 1 {
+		quality = 1
+	}
+
+	obWeight := 0.15 * quality
+	if math.Abs(features.Imbalance) > 0.5 {
+		obWeight += 0.1 * quality
+	}
+
+	scale := 1 - obWeight
+	return IndicatorWeights{
+		EMA:       weights.EMA * scale,
+		RSI:       weights.RSI * scale,
+		Volume:    weights.Volume * scale,
+		BB:        weights.BB * scale,
+		OrderBook: obWeight,
+	}
+}
+
 // CalculateVolatility calculates normalized volatility [0, 1] using Bollinger Bands width
 func (wc *WeightCalculator) CalculateVolatility(prices []decimal.Decimal) decimal.Decimal {
 	if len(prices) < 20 {
@@ -285,10 +319,11 @@ func (wc *WeightCalculator) calculateEMA(prices []decimal.Decimal, period int) d
 	}
 
 	multiplier := decimal.NewFromFloat(2.0 / (float64(period) + 1.0))
+	complement := decimal.NewFromInt(1).Sub(multiplier)
 	ema := prices[0]
 
 	for i := 1; i < len(prices); i++ {
-		ema = prices[i].Mul(multiplier).Add(ema.Mul(decimal.NewFromInt(1).Sub(multiplier)))
+		ema = prices[i].Mul(multiplier).Add(ema.Mul(complement)).Round(int32(decimal.DivisionPrecision))
 	}
 
 	return ema
@@ -299,10 +334,7 @@ func (wc *WeightCalculator) addToHistory(condition MarketCondition) {
 	wc.mu.Lock()
 	defer wc.mu.Unlock()
 
-	wc.history = append(wc.history, condition)
-	if len(wc.history) > wc.maxHistorySize {
-		wc.history = wc.history[1:]
-	}
+	wc.history.Push(condition)
 }
 
 // GetHistory returns the market condition history (thread-safe)
@@ -310,7 +342,5 @@ func (wc *WeightCalculator) GetHistory() []MarketCondition {
 	wc.mu.RLock()
 	defer wc.mu.RUnlock()
 
-	history := make([]MarketCondition, len(wc.history))
-	copy(history, wc.history)
-	return history
+	return wc.history.Values()
 }
@@ -22,10 +22,11 @@ func EMA(prices []decimal.Decimal, period int) []decimal.Decimal {
 	}
 	result[period-1] = sum.Div(decimal.NewFromInt(int64(period)))
 
-	// Calculate EMA
+	// Calculate EMA, rounding each step: the digits of the multiplier would
+	// otherwise pile up at every step and make long histories very slow
 	for i := period; i < len(prices); i++ {
 		ema := prices[i].Sub(result[i-1]).Mul(multiplier).Add(result[i-1])
-		result[i] = ema
+		result[i] = ema.Round(int32(decimal.DivisionPrecision))
 	}
 
 	return result[period-1:]
@@ -186,6 +187,116 @@ func ATR(high, low, close []decimal.Decimal, period int) []decimal.Decimal {
 	return SMA(trueRanges, period)
 }
 
+// ADX calculates Wilder's Average Directional Index along with the positive
+// and negative directional indicators it is derived from
+func ADX(high, low, close []decimal.Decimal, period int) (adx, plusDI, minusDI []decimal.Decimal) {
+	n := len(close)
+	if period <= 0 || len(high) != n || len(low) != n || n < 2*period+1 {
+		return []decimal.Decimal{}, []decimal.Decimal{}, []decimal.Decimal{}
+	}
+
+	tr := make([]float64, n)
+	plusDM := make([]float64, n)
+	minusDM := make([]float64, n)
+	for i := 1; i < n; i++ {
+		h, l := high[i].InexactFloat64(), low[i].InexactFloat64()
+		prevClose := close[i-1].InexactFloat64()
+		tr[i] = math.Max(h-l, math.Max(math.Abs(h-prevClose), math.Abs(l-prevClose)))
+
+		up := h - high[i-1].InexactFloat64()
+		down := low[i-1].InexactFloat64() - l
+		if up > down && up > 0 {
+			plusDM[i] = up
+		}
+		if down > up && down > 0 {
+			minusDM[i] = down
+		}
+	}
+
+	// Wilder smoothing, seeded with the sum of the first period
+	var smoothTR, smoothPlus, smoothMinus float64
+	for i := 1; i <= period; i++ {
+		smoothTR += tr[i]
+		smoothPlus += plusDM[i]
+		smoothMinus += minusDM[i]
+	}
+
+	p := float64(period)
+	dx := make([]float64, 0, n-period)
+	plus := make([]float64, 0, n-period)
+	minus := make([]float64, 0, n-period)
+	for i := period; i < n; i++ {
+		if i > period {
+			smoothTR = smoothTR - smoothTR/p + tr[i]
+			smoothPlus = smoothPlus - smoothPlus/p + plusDM[i]
+			smoothMinus = smoothMinus - smoothMinus/p + minusDM[i]
+		}
+		pdi, mdi := 0.0, 0.0
+		if smoothTR > 0 {
+			pdi = 100 * smoothPlus / smoothTR
+			mdi = 100 * smoothMinus / smoothTR
+		}
+		value := 0.0
+		if pdi+mdi > 0 {
+			value = 100 * math.Abs(pdi-mdi) / (pdi + mdi)
+		}
+		dx = append(dx, value)
+		plus = append(plus, pdi)
+		minus = append(minus, mdi)
+	}
+
+	current := 0.0
+	for i := 0; i < period; i++ {
+		current += dx[i]
+	}
+	current /= p
+
+	adx = make([]decimal.Decimal, 0, len(dx)-period+1)
+	plusDI = make([]decimal.Decimal, 0, len(dx)-period+1)
+	minusDI = make([]decimal.Decimal, 0, len(dx)-period+1)
+	for i := period - 1; i < len(dx); i++ {
+		if i > period-1 {
+			current = (current*(p-1) + dx[i]) / p
+		}
+		adx = append(adx, decimal.NewFromFloat(current))
+		plusDI = append(plusDI, decimal.NewFromFloat(plus[i]))
+		minusDI = append(minusDI, decimal.NewFromFloat(minus[i]))
+	}
+
+	return adx, plusDI, minusDI
+}
+
+// RealizedVolatility calculates the standard deviation of log returns over
+// the last period prices
+func RealizedVolatility(prices []decimal.Decimal, period int) float64 {
+	if period < 2 || len(prices) < period+1 {
+		return 0
+	}
+
+	window := prices[len(prices)-period-1:]
+	returns := make([]float64, 0, period)
+	for i := 1; i < len(window); i++ {
+		prev := window[i-1].InexactFloat64()
+		curr := window[i].InexactFloat64()
+		if prev <= 0 || curr <= 0 {
+			return 0
+		}
+		returns = append(returns, math.Log(curr/prev))
+	}
+
+	mean := 0.0
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	variance := 0.0
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	return math.Sqrt(variance / float64(len(returns)-1))
+}
+
 // VWAP calculates the Volume Weighted Average Price
 func VWAP(prices, volumes []decimal.Decimal) decimal.Decimal {
 	if len(prices) == 0 || len(volumes) == 0 || len(prices) != len(volumes) {
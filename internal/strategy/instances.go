@@ -0,0 +1,106 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/guyghost/constantine/internal/config"
+)
+
+// MainInstance is the name of the strategy instance built from the base
+// configuration
+const MainInstance = "main"
+
+// InstanceTypeScalping is the only strategy type available to instances
+const InstanceTypeScalping = "scalping"
+
+// InstanceConfig describes an additional strategy instance sharing the account
+type InstanceConfig struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"` // Defaults to scalping
+	// Weight is the share of capital the instance is allocated relative to the
+	// other instances; defaults to 1
+	Weight float64 `json:"weight,omitempty"`
+	// Config overrides fields of the base configuration, keyed by their Go
+	// field names, e.g. {"ShortEMAPeriod": 5}
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// Instance is a strategy instance resolved from its InstanceConfig
+type Instance struct {
+	Name   string
+	Type   string
+	Weight float64
+	Config *config.Config
+}
+
+// LoadInstances loads strategy instances from a JSON array in path, applying
+// the overrides of each instance to a copy of base. An entry named main sets
+// the weight of the instance running the base configuration, which is always
+// returned first.
+func LoadInstances(path string, base *config.Config) ([]Instance, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read strategy instances: %w", err)
+	}
+	var configs []InstanceConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("failed to parse strategy instances %s: %w", path, err)
+	}
+
+	instances := []Instance{{Name: MainInstance, Type: InstanceTypeScalping, Weight: 1, Config: base}}
+	seen := map[string]bool{}
+	for i, ic := range configs {
+		if ic.Name == "" {
+			return nil, fmt.Errorf("strategy instance %d has no name", i+1)
+		}
+		if seen[ic.Name] {
+			return nil, fmt.Errorf("duplicate strategy instance %q", ic.Name)
+		}
+		seen[ic.Name] = true
+
+		if ic.Type == "" {
+			ic.Type = InstanceTypeScalping
+		}
+		if ic.Type != InstanceTypeScalping {
+			return nil, fmt.Errorf("strategy instance %q: unsupported type %q", ic.Name, ic.Type)
+		}
+		if ic.Weight < 0 {
+			return nil, fmt.Errorf("strategy instance %q: weight must not be negative", ic.Name)
+		}
+		if ic.Weight == 0 {
+			ic.Weight = 1
+		}
+
+		if ic.Name == MainInstance {
+			if len(ic.Config) > 0 {
+				return nil, fmt.Errorf("strategy instance %q runs the base configuration and takes no overrides", ic.Name)
+			}
+			instances[0].Weight = ic.Weight
+			continue
+		}
+
+		cfg := *base
+		if len(ic.Config) > 0 {
+			if err := json.Unmarshal(ic.Config, &cfg); err != nil {
+				return nil, fmt.Errorf("strategy instance %q: invalid config: %w", ic.Name, err)
+			}
+		}
+		instances = append(instances, Instance{Name: ic.Name, Type: ic.Type, Weight: ic.Weight, Config: &cfg})
+	}
+	return instances, nil
+}
+
+// NewInstanceEngine creates an engine trading the same symbols on the same
+// exchange and with the same price reference and halt check as ise, with a
+// different configuration
+func (ise *IntegratedStrategyEngine) NewInstanceEngine(cfg *config.Config) *IntegratedStrategyEngine {
+	engine := NewIntegratedStrategyEngine(cfg, ise.GetTradingSymbols(), ise.exchange, ise.refreshInterval)
+
+	ise.scalingStrategy.mu.RLock()
+	engine.scalingStrategy.priceReference = ise.scalingStrategy.priceReference
+	engine.scalingStrategy.haltCheck = ise.scalingStrategy.haltCheck
+	ise.scalingStrategy.mu.RUnlock()
+	return engine
+}
@@ -0,0 +1,77 @@
+package strategy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/config"
+)
+
+func writeInstances(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "strategies.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadInstances(t *testing.T) {
+	base := config.DefaultConfig()
+	path := writeInstances(t, `[
+		{"name": "main", "weight": 3},
+		{"name": "fast", "config": {"ShortEMAPeriod": 3, "LongEMAPeriod": 8}},
+		{"name": "slow", "type": "scalping", "weight": 0.5, "config": {"RSIPeriod": 21}}
+	]`)
+
+	instances, err := LoadInstances(path, base)
+	if err != nil {
+		t.Fatalf("LoadInstances failed: %v", err)
+	}
+	if len(instances) != 3 {
+		t.Fatalf("expected 3 instances, got %d", len(instances))
+	}
+
+	main := instances[0]
+	if main.Name != MainInstance || main.Weight != 3 || main.Config != base {
+		t.Errorf("main instance should run the base config with weight 3, got %+v", main)
+	}
+
+	fast := instances[1]
+	if fast.Weight != 1 {
+		t.Errorf("weight should default to 1, got %v", fast.Weight)
+	}
+	if fast.Config.ShortEMAPeriod != 3 || fast.Config.LongEMAPeriod != 8 {
+		t.Errorf("overrides not applied: %+v", fast.Config)
+	}
+	if fast.Config.RSIPeriod != base.RSIPeriod {
+		t.Errorf("fields without overrides should keep the base value")
+	}
+
+	slow := instances[2]
+	if slow.Config.RSIPeriod != 21 || slow.Weight != 0.5 {
+		t.Errorf("unexpected slow instance: %+v", slow)
+	}
+	if base.RSIPeriod == 21 || base.ShortEMAPeriod == 3 {
+		t.Errorf("overrides must not modify the base config")
+	}
+}
+
+func TestLoadInstances_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing name":   `[{"weight": 1}]`,
+		"duplicate":      `[{"name": "a"}, {"name": "a"}]`,
+		"unknown type":   `[{"name": "a", "type": "grid"}]`,
+		"negative":       `[{"name": "a", "weight": -1}]`,
+		"main overrides": `[{"name": "main", "config": {"RSIPeriod": 5}}]`,
+		"bad config":     `[{"name": "a", "config": {"RSIPeriod": "fast"}}]`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadInstances(writeInstances(t, content), config.DefaultConfig()); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -2,12 +2,15 @@ package strategy
 
 import (
 	"context"
+	"maps"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/guyghost/constantine/internal/config"
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/telemetry"
 	"github.com/shopspring/decimal"
 )
 
@@ -23,9 +26,16 @@ type IntegratedStrategyEngine struct {
 
 	// State
 	selectedSymbols map[string]RankedSymbol
+	dynamicWeights  map[string]IndicatorWeights // Weights for the market data of each symbol
 	marketData      map[string]SymbolData
 	refreshInterval time.Duration
 
+	// Discovery
+	discovery        *DiscoveryConfig
+	marketSources    func() map[string]exchanges.Exchange
+	universe         map[string]string // Traded symbols to the exchange they were discovered on
+	onUniverseChange func(UniverseChange)
+
 	// Control
 	mu      sync.RWMutex
 	running bool
@@ -46,9 +56,10 @@ func NewIntegratedStrategyEngine(
 		symbolSelector:   NewSymbolSelector(cfg),
 		weightCalculator: NewWeightCalculator(cfg),
 		signalGenerator:  NewSignalGenerator(cfg),
-		scalingStrategy:  NewScalpingStrategy(cfg, exchange),
+		scalingStrategy:  NewScalpingStrategy(cfg.ForSymbol(cfg.Symbol), exchange),
 		exchange:         exchange,
 		selectedSymbols:  make(map[string]RankedSymbol),
+		dynamicWeights:   make(map[string]IndicatorWeights),
 		marketData:       make(map[string]SymbolData),
 		refreshInterval:  refreshInterval,
 		done:             make(chan struct{}),
@@ -135,10 +146,106 @@ func (ise *IntegratedStrategyEngine) refreshSymbolSelection(ctx context.Context,
 	}
 }
 
+// EnableDiscovery makes every symbol selection refresh first rebuild the
+// trading symbols from the markets listed by the exchanges sources returns,
+// as selected by cfg. The trading symbols configured so far are the initial
+// universe.
+func (ise *IntegratedStrategyEngine) EnableDiscovery(sources func() map[string]exchanges.Exchange, cfg DiscoveryConfig) {
+	ise.mu.Lock()
+	defer ise.mu.Unlock()
+	ise.discovery = &cfg
+	ise.marketSources = sources
+	ise.universe = make(map[string]string, len(ise.tradingSymbols))
+	for _, symbol := range ise.tradingSymbols {
+		ise.universe[symbol] = ""
+	}
+}
+
+// SetUniverseCallback sets the callback for the symbols discovery adds to and
+// removes from the trading symbols
+func (ise *IntegratedStrategyEngine) SetUniverseCallback(callback func(UniverseChange)) {
+	ise.mu.Lock()
+	defer ise.mu.Unlock()
+	ise.onUniverseChange = callback
+}
+
+// GetTradingSymbols returns the symbols symbol selection evaluates
+func (ise *IntegratedStrategyEngine) GetTradingSymbols() []string {
+	ise.mu.RLock()
+	defer ise.mu.RUnlock()
+	return append([]string(nil), ise.tradingSymbols...)
+}
+
+// refreshUniverse rebuilds the trading symbols from the markets the exchanges
+// list. The universe is kept as is when no exchange could list its markets.
+func (ise *IntegratedStrategyEngine) refreshUniverse(ctx context.Context) {
+	ise.mu.RLock()
+	cfg := *ise.discovery
+	sources := ise.marketSources
+	ise.mu.RUnlock()
+
+	ranked, failed := discoverMarkets(ctx, sources(), cfg)
+	for name, err := range failed {
+		logger.Component("strategy").Warn("failed to list markets", "exchange", name, "error", err)
+	}
+	if len(ranked) == 0 {
+		return
+	}
+
+	ise.mu.Lock()
+	next := selectUniverse(ranked, ise.universe, failed, cfg)
+	change := diffUniverse(ranked, ise.universe, next)
+	ise.universe = next
+	ise.tradingSymbols = make([]string, 0, len(next))
+	for symbol := range next {
+		ise.tradingSymbols = append(ise.tradingSymbols, symbol)
+	}
+	sort.Strings(ise.tradingSymbols)
+	callback := ise.onUniverseChange
+	ise.mu.Unlock()
+
+	if len(change.Added) == 0 && len(change.Removed) == 0 {
+		return
+	}
+	added := make([]string, len(change.Added))
+	for i, market := range change.Added {
+		added[i] = market.Symbol
+	}
+	logger.Component("strategy").Info("trading universe updated",
+		"added", added,
+		"removed", change.Removed,
+		"symbols", len(next))
+	if callback != nil {
+		callback(change)
+	}
+}
+
+// marketExchange returns the exchange symbol was discovered on, or the engine
+// exchange
+func (ise *IntegratedStrategyEngine) marketExchange(symbol string) exchanges.Exchange {
+	ise.mu.RLock()
+	name := ise.universe[symbol]
+	sources := ise.marketSources
+	ise.mu.RUnlock()
+	if name != "" && sources != nil {
+		if exchange, ok := sources()[name]; ok {
+			return exchange
+		}
+	}
+	return ise.exchange
+}
+
 // updateSymbolSelection updates the selected trading symbols
 func (ise *IntegratedStrategyEngine) updateSymbolSelection(ctx context.Context) {
+	ise.mu.RLock()
+	discovery := ise.discovery != nil
+	ise.mu.RUnlock()
+	if discovery {
+		ise.refreshUniverse(ctx)
+	}
+
 	// Get list of symbols to evaluate
-	symbols := ise.tradingSymbols
+	symbols := ise.GetTradingSymbols()
 	if len(symbols) == 0 {
 		logger.Component("strategy").Warn("no trading symbols configured")
 		return
@@ -149,13 +256,13 @@ func (ise *IntegratedStrategyEngine) updateSymbolSelection(ctx context.Context)
 	successCount := 0
 
 	for _, symbol := range symbols {
-		prices, err := ise.fetchPriceData(ctx, symbol, 30)
+		prices, err := ise.fetchPriceData(ctx, symbol, ise.config.Selection.Lookback)
 		if err != nil {
 			logger.Component("strategy").Debug("failed to fetch price data", "symbol", symbol, "error", err)
 			continue
 		}
 
-		volumes, err := ise.fetchVolumeData(ctx, symbol, 30)
+		volumes, err := ise.fetchVolumeData(ctx, symbol, ise.config.Selection.Lookback)
 		if err != nil {
 			logger.Component("strategy").Debug("failed to fetch volume data", "symbol", symbol, "error", err)
 			volumes = make([]decimal.Decimal, len(prices))
@@ -164,7 +271,7 @@ func (ise *IntegratedStrategyEngine) updateSymbolSelection(ctx context.Context)
 			}
 		}
 
-		if len(prices) >= 20 { // Minimum data requirement
+		if len(prices) >= ise.config.Selection.MinHistory {
 			symbolData[symbol] = SymbolData{
 				Prices:  prices,
 				Volumes: volumes,
@@ -199,6 +306,14 @@ func (ise *IntegratedStrategyEngine) updateSymbolSelection(ctx context.Context)
 	}
 
 	selected := ise.symbolSelector.SelectBestSymbols(symbols, symbolData, selectedCount)
+	weights := make(map[string]IndicatorWeights, len(symbolData))
+	for symbol, data := range symbolData {
+		rsi := decimal.NewFromInt(50)
+		if values := RSI(data.Prices, ise.config.RSIPeriod); len(values) > 0 {
+			rsi = values[len(values)-1]
+		}
+		weights[symbol] = ise.weightCalculator.CalculateDynamicWeights(data.Prices, data.Volumes, rsi)
+	}
 
 	// Update state
 	ise.mu.Lock()
@@ -206,9 +321,12 @@ func (ise *IntegratedStrategyEngine) updateSymbolSelection(ctx context.Context)
 	for _, rs := range selected {
 		ise.selectedSymbols[rs.Symbol] = rs
 	}
+	ise.dynamicWeights = weights
 	ise.marketData = symbolData
 	ise.mu.Unlock()
 
+	publishSelection(ise.symbolSelector.RankSymbols(symbols, symbolData), selected, weights)
+
 	// Log selection
 	logger.Component("strategy").Info("symbol selection updated",
 		"total_symbols", len(symbols),
@@ -217,10 +335,44 @@ func (ise *IntegratedStrategyEngine) updateSymbolSelection(ctx context.Context)
 		"symbols", formatSelectedSymbols(selected))
 }
 
+// publishSelection publishes the assessment of every ranked symbol and its
+// indicator weights to telemetry
+func publishSelection(ranked, selected []RankedSymbol, weights map[string]IndicatorWeights) {
+	picked := make(map[string]RankedSymbol, len(selected))
+	for _, rs := range selected {
+		picked[rs.Symbol] = rs
+	}
+	scores := make([]telemetry.SymbolScore, 0, len(ranked))
+	for _, rs := range ranked {
+		score := telemetry.SymbolScore{
+			Symbol:    rs.Symbol,
+			Score:     rs.Score,
+			Potential: rs.Potential.InexactFloat64(),
+			Risk:      rs.Risk.InexactFloat64(),
+			Sharpe:    rs.SharpeRatio.InexactFloat64(),
+		}
+		if selection, ok := picked[rs.Symbol]; ok {
+			score.Selected = true
+			score.Correlation = selection.Correlation
+		}
+		if w, ok := weights[rs.Symbol]; ok {
+			score.Weights = map[string]float64{
+				"ema":       w.EMA,
+				"rsi":       w.RSI,
+				"volume":    w.Volume,
+				"bb":        w.BB,
+				"orderbook": w.OrderBook,
+			}
+		}
+		scores = append(scores, score)
+	}
+	telemetry.RecordSymbolSelection(scores)
+}
+
 // fetchPriceData fetches recent price data for a symbol
 func (ise *IntegratedStrategyEngine) fetchPriceData(ctx context.Context, symbol string, count int) ([]decimal.Decimal, error) {
 	// Try to get candles from exchange
-	candles, err := ise.exchange.GetCandles(ctx, symbol, "1m", count)
+	candles, err := ise.marketExchange(symbol).GetCandles(ctx, symbol, ise.config.ForSymbol(symbol).CandleIntervalName(), count)
 	if err != nil || len(candles) == 0 {
 		// If exchange fails or returns no data, generate synthetic data for symbol selection to work
 		logger.Component("strategy").Debug("generating synthetic candle data", "symbol", symbol, "error", err)
@@ -244,7 +396,7 @@ func (ise *IntegratedStrategyEngine) fetchPriceData(ctx context.Context, symbol
 
 // fetchVolumeData fetches recent volume data for a symbol
 func (ise *IntegratedStrategyEngine) fetchVolumeData(ctx context.Context, symbol string, count int) ([]decimal.Decimal, error) {
-	candles, err := ise.exchange.GetCandles(ctx, symbol, "1m", count)
+	candles, err := ise.marketExchange(symbol).GetCandles(ctx, symbol, ise.config.ForSymbol(symbol).CandleIntervalName(), count)
 	if err != nil || len(candles) == 0 {
 		// If exchange fails or returns no data, generate synthetic volume data
 		logger.Component("strategy").Debug("generating synthetic volume data", "symbol", symbol, "error", err)
@@ -277,6 +429,19 @@ func (ise *IntegratedStrategyEngine) GetSelectedSymbols() map[string]RankedSymbo
 	return result
 }
 
+// GetDynamicWeights returns the indicator weights computed for each symbol on
+// the last symbol selection refresh
+func (ise *IntegratedStrategyEngine) GetDynamicWeights() map[string]IndicatorWeights {
+	ise.mu.RLock()
+	defer ise.mu.RUnlock()
+	return maps.Clone(ise.dynamicWeights)
+}
+
+// GetConfig returns the strategy configuration of the engine
+func (ise *IntegratedStrategyEngine) GetConfig() *config.Config {
+	return ise.config
+}
+
 // GetSignalGenerator returns the signal generator for custom usage
 func (ise *IntegratedStrategyEngine) GetSignalGenerator() *SignalGenerator {
 	return ise.signalGenerator
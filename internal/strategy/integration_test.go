@@ -2,6 +2,7 @@ package strategy
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -111,6 +112,19 @@ func TestIntegratedStrategyEngineSymbolSelection(t *testing.T) {
 			t.Errorf("Selected symbol not in config: %s", symbol)
 		}
 	}
+
+	// Every evaluated symbol has its indicator weights
+	weights := engine.GetDynamicWeights()
+	for _, symbol := range tradingSymbols {
+		w, ok := weights[symbol]
+		if !ok {
+			t.Errorf("Expected dynamic weights for %s", symbol)
+			continue
+		}
+		if total := w.EMA + w.RSI + w.Volume + w.BB + w.OrderBook; math.Abs(total-1) > 1e-6 {
+			t.Errorf("Expected the weights of %s to sum to 1, got %f", symbol, total)
+		}
+	}
 }
 
 // TestIntegratedStrategyEngineWeightCalculation tests weight integration
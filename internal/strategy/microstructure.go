@@ -0,0 +1,121 @@
+package strategy
+
+import (
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// OrderBookFeatures holds microstructure features derived from an order book snapshot
+type OrderBookFeatures struct {
+	Valid       bool
+	Mid         decimal.Decimal
+	WeightedMid decimal.Decimal // Mid weighted by top-of-book sizes (micro-price)
+	SpreadBps   float64
+	// Imbalance of resting volume in the top levels, from -1 (all asks) to 1 (all bids)
+	Imbalance float64
+	// Volume resting within DepthBps of the mid on each side
+	BidDepth       decimal.Decimal
+	AskDepth       decimal.Decimal
+	DepthImbalance float64
+	DepthBps       float64
+}
+
+// ComputeOrderBookFeatures derives microstructure features from the top levels
+// of an order book and the volume resting within depthBps of the mid price
+func ComputeOrderBookFeatures(orderbook *exchanges.OrderBook, levels int, depthBps float64) OrderBookFeatures {
+	features := OrderBookFeatures{DepthBps: depthBps}
+	if orderbook == nil || len(orderbook.Bids) == 0 || len(orderbook.Asks) == 0 {
+		return features
+	}
+
+	bestBid := orderbook.Bids[0]
+	bestAsk := orderbook.Asks[0]
+	if !bestBid.Price.IsPositive() || !bestAsk.Price.IsPositive() || bestAsk.Price.LessThan(bestBid.Price) {
+		return features
+	}
+
+	two := decimal.NewFromInt(2)
+	features.Mid = bestBid.Price.Add(bestAsk.Price).Div(two)
+	features.SpreadBps = bestAsk.Price.Sub(bestBid.Price).Div(features.Mid).Mul(decimal.NewFromInt(10000)).InexactFloat64()
+
+	// Micro-price: leans toward the side with less size, where the next trade
+	// is more likely to move the price
+	topSize := bestBid.Amount.Add(bestAsk.Amount)
+	if topSize.IsPositive() {
+		features.WeightedMid = bestBid.Price.Mul(bestAsk.Amount).Add(bestAsk.Price.Mul(bestBid.Amount)).Div(topSize)
+	} else {
+		features.WeightedMid = features.Mid
+	}
+
+	if levels <= 0 {
+		levels = 5
+	}
+	bidVolume := sumLevels(orderbook.Bids, levels)
+	askVolume := sumLevels(orderbook.Asks, levels)
+	features.Imbalance = imbalance(bidVolume, askVolume)
+
+	if depthBps > 0 {
+		band := features.Mid.Mul(decimal.NewFromFloat(depthBps / 10000))
+		lower := features.Mid.Sub(band)
+		upper := features.Mid.Add(band)
+		for _, level := range orderbook.Bids {
+			if level.Price.LessThan(lower) {
+				break
+			}
+			features.BidDepth = features.BidDepth.Add(level.Amount)
+		}
+		for _, level := range orderbook.Asks {
+			if level.Price.GreaterThan(upper) {
+				break
+			}
+			features.AskDepth = features.AskDepth.Add(level.Amount)
+		}
+		features.DepthImbalance = imbalance(features.BidDepth, features.AskDepth)
+	}
+
+	features.Valid = true
+	return features
+}
+
+// DirectionalScore scores how strongly the book supports a trade direction,
+// from 0 (against or neutral) to 1, combining volume imbalance and the
+// micro-price skew inside the spread
+func (f OrderBookFeatures) DirectionalScore(isBuy bool) float64 {
+	if !f.Valid {
+		return 0
+	}
+
+	skew := 0.0
+	halfSpread := f.Mid.Mul(decimal.NewFromFloat(f.SpreadBps / 20000))
+	if halfSpread.IsPositive() {
+		skew = f.WeightedMid.Sub(f.Mid).Div(halfSpread).InexactFloat64()
+	}
+
+	score := 0.5*f.Imbalance + 0.5*skew
+	if !isBuy {
+		score = -score
+	}
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
+
+func sumLevels(levels []exchanges.Level, count int) decimal.Decimal {
+	total := decimal.Zero
+	for i := 0; i < count && i < len(levels); i++ {
+		total = total.Add(levels[i].Amount)
+	}
+	return total
+}
+
+func imbalance(bid, ask decimal.Decimal) float64 {
+	total := bid.Add(ask)
+	if !total.IsPositive() {
+		return 0
+	}
+	return bid.Sub(ask).Div(total).InexactFloat64()
+}
@@ -0,0 +1,100 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func testOrderBook(bidSizes, askSizes []float64) *exchanges.OrderBook {
+	book := &exchanges.OrderBook{Symbol: "BTC-USD"}
+	for i, size := range bidSizes {
+		book.Bids = append(book.Bids, exchanges.Level{
+			Price:  decimal.RequireFromString("99.99").Sub(decimal.New(int64(i), -2)),
+			Amount: decimal.NewFromFloat(size),
+		})
+	}
+	for i, size := range askSizes {
+		book.Asks = append(book.Asks, exchanges.Level{
+			Price:  decimal.RequireFromString("100.01").Add(decimal.New(int64(i), -2)),
+			Amount: decimal.NewFromFloat(size),
+		})
+	}
+	return book
+}
+
+func TestComputeOrderBookFeatures(t *testing.T) {
+	book := testOrderBook([]float64{3, 3, 3}, []float64{1, 1, 1})
+
+	features := ComputeOrderBookFeatures(book, 5, 2)
+	if !features.Valid {
+		t.Fatal("features should be valid")
+	}
+	if !features.Mid.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("expected mid 100, got %s", features.Mid)
+	}
+	if math.Abs(features.SpreadBps-2) > 1e-9 {
+		t.Errorf("expected spread of 2 bps, got %f", features.SpreadBps)
+	}
+	if math.Abs(features.Imbalance-0.5) > 1e-9 {
+		t.Errorf("expected imbalance 0.5, got %f", features.Imbalance)
+	}
+	// Heavier bids push the micro-price toward the ask
+	if !features.WeightedMid.GreaterThan(features.Mid) {
+		t.Errorf("weighted mid %s should be above mid %s", features.WeightedMid, features.Mid)
+	}
+	// A 2 bps band around 100 reaches 99.98 and 100.02
+	if !features.BidDepth.Equal(decimal.NewFromInt(6)) || !features.AskDepth.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("unexpected depth: bids %s asks %s", features.BidDepth, features.AskDepth)
+	}
+}
+
+func TestComputeOrderBookFeatures_Invalid(t *testing.T) {
+	if ComputeOrderBookFeatures(nil, 5, 10).Valid {
+		t.Error("nil book should not be valid")
+	}
+	crossed := &exchanges.OrderBook{
+		Bids: []exchanges.Level{{Price: decimal.NewFromInt(101), Amount: decimal.NewFromInt(1)}},
+		Asks: []exchanges.Level{{Price: decimal.NewFromInt(100), Amount: decimal.NewFromInt(1)}},
+	}
+	if ComputeOrderBookFeatures(crossed, 5, 10).Valid {
+		t.Error("crossed book should not be valid")
+	}
+}
+
+func TestOrderBookFeatures_DirectionalScore(t *testing.T) {
+	features := ComputeOrderBookFeatures(testOrderBook([]float64{3, 3}, []float64{1, 1}), 5, 10)
+
+	if score := features.DirectionalScore(true); score <= 0 {
+		t.Errorf("bid-heavy book should support buys, got %f", score)
+	}
+	if score := features.DirectionalScore(false); score != 0 {
+		t.Errorf("bid-heavy book should not support sells, got %f", score)
+	}
+}
+
+func TestApplyOrderBookWeight(t *testing.T) {
+	wc := NewWeightCalculator(config.DefaultConfig())
+	base := IndicatorWeights{EMA: 0.35, RSI: 0.35, Volume: 0.15, BB: 0.15}
+
+	features := ComputeOrderBookFeatures(testOrderBook([]float64{3, 3}, []float64{1, 1}), 5, 10)
+	weights := wc.ApplyOrderBookWeight(base, features, 25)
+	if weights.OrderBook <= 0 {
+		t.Fatalf("tight book should receive a weight, got %f", weights.OrderBook)
+	}
+	sum := weights.EMA + weights.RSI + weights.Volume + weights.BB + weights.OrderBook
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("weights should sum to 1, got %f", sum)
+	}
+
+	// Spread beyond the limit removes the order book weight
+	if weights := wc.ApplyOrderBookWeight(base, features, 1); weights.OrderBook != 0 {
+		t.Errorf("wide spread should disable the order book weight, got %f", weights.OrderBook)
+	}
+	if weights := wc.ApplyOrderBookWeight(base, OrderBookFeatures{}, 25); weights != base {
+		t.Errorf("missing book should leave weights unchanged, got %+v", weights)
+	}
+}
@@ -3,6 +3,7 @@ package strategy
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/guyghost/constantine/internal/symbolmanager"
@@ -15,11 +16,22 @@ type SymbolManagerInterface interface {
 	IsSymbolActive(symbol string) bool
 }
 
+// SymbolRegistry is implemented by symbol managers that symbols can be added
+// to and removed from while trading
+type SymbolRegistry interface {
+	AddSymbol(symbol string, config symbolmanager.SymbolConfig) error
+	RemoveSymbol(symbol string) error
+}
+
 // StrategyOrchestrator manages multiple strategy instances for different symbols
 type StrategyOrchestrator struct {
-	strategies    map[string]*ScalpingStrategy
-	symbolManager SymbolManagerInterface
-	exchange      exchanges.Exchange
+	mu               sync.RWMutex
+	strategies       map[string]*ScalpingStrategy
+	symbolManager    SymbolManagerInterface
+	exchange         exchanges.Exchange
+	exchangeResolver func(symbol string) (exchanges.Exchange, error)
+	haltCheck        HaltCheck
+	scheduler        *Scheduler
 }
 
 // NewStrategyOrchestrator creates a new strategy orchestrator
@@ -38,38 +50,85 @@ func (so *StrategyOrchestrator) StartSymbol(ctx context.Context, symbol string)
 		return fmt.Errorf("symbol %s is not active", symbol)
 	}
 
+	so.mu.Lock()
+	defer so.mu.Unlock()
+
 	// Check if strategy already exists
 	if _, exists := so.strategies[symbol]; exists {
 		return fmt.Errorf("strategy for symbol %s already exists", symbol)
 	}
 
-	// Get symbol configuration
-	symbolConfig, err := so.symbolManager.GetSymbolConfig(symbol)
-	if err != nil {
-		return fmt.Errorf("failed to get config for symbol %s: %w", symbol, err)
-	}
-
-	// Create strategy instance with the provided exchange
-	strategy := NewScalpingStrategy(symbolConfig.StrategyConfig, so.exchange)
-
-	so.strategies[symbol] = strategy
-
-	return nil
+	return so.startSymbolLocked(ctx, symbol)
 }
 
 // StopSymbol stops and removes the strategy for a specific symbol
 func (so *StrategyOrchestrator) StopSymbol(symbol string) error {
-	if _, exists := so.strategies[symbol]; !exists {
+	so.mu.Lock()
+	strategy, exists := so.strategies[symbol]
+	delete(so.strategies, symbol)
+	so.mu.Unlock()
+
+	if !exists {
 		return fmt.Errorf("strategy for symbol %s not found", symbol)
 	}
+	return strategy.Stop()
+}
 
-	delete(so.strategies, symbol)
+// AddSymbol adds a symbol to the symbol manager, which must be a
+// SymbolRegistry, and creates its strategy. The strategy is returned for the
+// caller to set up and start.
+func (so *StrategyOrchestrator) AddSymbol(ctx context.Context, config symbolmanager.SymbolConfig) (*ScalpingStrategy, error) {
+	registry, ok := so.symbolManager.(SymbolRegistry)
+	if !ok {
+		return nil, fmt.Errorf("symbol manager does not support adding symbols")
+	}
+	if err := registry.AddSymbol(config.Symbol, config); err != nil {
+		return nil, err
+	}
+	if err := so.StartSymbol(ctx, config.Symbol); err != nil {
+		registry.RemoveSymbol(config.Symbol)
+		return nil, err
+	}
+	return so.GetSymbolStrategy(config.Symbol)
+}
 
-	return nil
+// RemoveSymbol stops the strategy of a symbol and removes the symbol from the
+// symbol manager, which must be a SymbolRegistry
+func (so *StrategyOrchestrator) RemoveSymbol(symbol string) error {
+	registry, ok := so.symbolManager.(SymbolRegistry)
+	if !ok {
+		return fmt.Errorf("symbol manager does not support removing symbols")
+	}
+	if err := so.StopSymbol(symbol); err != nil {
+		return err
+	}
+	return registry.RemoveSymbol(symbol)
+}
+
+// SetExchangeResolver sets how the exchange a symbol trades on is found.
+// Strategies created afterwards use it, falling back to the orchestrator
+// exchange when it fails.
+func (so *StrategyOrchestrator) SetExchangeResolver(resolver func(symbol string) (exchanges.Exchange, error)) {
+	so.mu.Lock()
+	defer so.mu.Unlock()
+	so.exchangeResolver = resolver
+}
+
+// SetScheduler runs the updates of every strategy, current and started
+// later, on scheduler. Strategies already running switch on their next Start.
+func (so *StrategyOrchestrator) SetScheduler(scheduler *Scheduler) {
+	so.mu.Lock()
+	defer so.mu.Unlock()
+	so.scheduler = scheduler
+	for _, strategy := range so.strategies {
+		strategy.SetScheduler(scheduler)
+	}
 }
 
 // GetSymbolStrategy returns the strategy instance for a specific symbol
 func (so *StrategyOrchestrator) GetSymbolStrategy(symbol string) (*ScalpingStrategy, error) {
+	so.mu.RLock()
+	defer so.mu.RUnlock()
 	strategy, exists := so.strategies[symbol]
 	if !exists {
 		return nil, fmt.Errorf("strategy for symbol %s not found", symbol)
@@ -80,6 +139,8 @@ func (so *StrategyOrchestrator) GetSymbolStrategy(symbol string) (*ScalpingStrat
 
 // GetActiveStrategies returns all currently active strategy instances
 func (so *StrategyOrchestrator) GetActiveStrategies() map[string]*ScalpingStrategy {
+	so.mu.RLock()
+	defer so.mu.RUnlock()
 	active := make(map[string]*ScalpingStrategy)
 	for symbol, strategy := range so.strategies {
 		active[symbol] = strategy
@@ -90,13 +151,11 @@ func (so *StrategyOrchestrator) GetActiveStrategies() map[string]*ScalpingStrate
 
 // ProcessMarketData processes market data for all active symbols
 func (so *StrategyOrchestrator) ProcessMarketData(ctx context.Context, symbol string, candle exchanges.Candle) error {
-	if _, exists := so.strategies[symbol]; !exists {
-		// Symbol not active, skip
-		return nil
-	}
-
+	so.mu.RLock()
 	strategy, exists := so.strategies[symbol]
+	so.mu.RUnlock()
 	if !exists {
+		// Symbol not active, skip
 		return nil
 	}
 
@@ -108,6 +167,8 @@ func (so *StrategyOrchestrator) ProcessMarketData(ctx context.Context, symbol st
 
 // GenerateSignals generates trading signals for all active symbols
 func (so *StrategyOrchestrator) GenerateSignals(ctx context.Context) map[string]*Signal {
+	so.mu.RLock()
+	defer so.mu.RUnlock()
 	signals := make(map[string]*Signal)
 
 	for symbol := range so.strategies {
@@ -125,6 +186,9 @@ func (so *StrategyOrchestrator) GenerateSignals(ctx context.Context) map[string]
 func (so *StrategyOrchestrator) UpdateActiveSymbols(ctx context.Context) error {
 	activeSymbols := so.symbolManager.GetActiveSymbols()
 
+	so.mu.Lock()
+	defer so.mu.Unlock()
+
 	// Start strategies for new active symbols
 	for _, symbol := range activeSymbols {
 		if _, exists := so.strategies[symbol]; !exists {
@@ -162,8 +226,20 @@ func (so *StrategyOrchestrator) startSymbolLocked(ctx context.Context, symbol st
 		return fmt.Errorf("failed to get config for symbol %s: %w", symbol, err)
 	}
 
-	// Create strategy instance with the provided exchange
-	strategy := NewScalpingStrategy(symbolConfig.StrategyConfig, so.exchange)
+	// Create strategy instance with the exchange of the symbol
+	exchange := so.exchange
+	if so.exchangeResolver != nil {
+		if resolved, err := so.exchangeResolver(symbol); err == nil {
+			exchange = resolved
+		}
+	}
+	strategy := NewScalpingStrategy(symbolConfig.StrategyConfig, exchange)
+	if so.haltCheck != nil {
+		strategy.SetHaltCheck(so.haltCheck)
+	}
+	if so.scheduler != nil {
+		strategy.SetScheduler(so.scheduler)
+	}
 
 	so.strategies[symbol] = strategy
 
@@ -172,6 +248,8 @@ func (so *StrategyOrchestrator) startSymbolLocked(ctx context.Context, symbol st
 
 // GetStrategyMetrics returns performance metrics for all strategies
 func (so *StrategyOrchestrator) GetStrategyMetrics() map[string]StrategyMetrics {
+	so.mu.RLock()
+	defer so.mu.RUnlock()
 	metrics := make(map[string]StrategyMetrics)
 
 	for symbol := range so.strategies {
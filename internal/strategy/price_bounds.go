@@ -0,0 +1,78 @@
+package strategy
+
+import (
+	"math"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/shopspring/decimal"
+)
+
+// priceBounds is the reference price the sanity bounds of a symbol are
+// calibrated around
+type priceBounds struct {
+	reference decimal.Decimal // Zero until the first price calibrates it
+	updated   time.Time
+}
+
+// withinCalibratedBounds reports whether price is within PriceBoundsPercent
+// of the calibrated reference. Prices are until the first one calibrates it.
+func (s *ScalpingStrategy) withinCalibratedBounds(price decimal.Decimal) bool {
+	low, high, ok := s.PriceBounds()
+	if !ok {
+		return true
+	}
+	return !price.LessThan(low) && !price.GreaterThan(high)
+}
+
+// PriceBounds returns the bounds calibrated around the price of the symbol,
+// and false while calibration is disabled or no price was seen yet
+func (s *ScalpingStrategy) PriceBounds() (decimal.Decimal, decimal.Decimal, bool) {
+	if s.config.PriceBoundsPercent <= 0 {
+		return decimal.Zero, decimal.Zero, false
+	}
+	s.boundsMu.Lock()
+	reference := s.bounds.reference
+	s.boundsMu.Unlock()
+	if reference.IsZero() {
+		return decimal.Zero, decimal.Zero, false
+	}
+
+	band := reference.Mul(decimal.NewFromFloat(s.config.PriceBoundsPercent / 100))
+	return reference.Sub(band), reference.Add(band), true
+}
+
+// calibrateBounds moves the reference of the bounds toward a price accepted
+// into the history at the given time, by the share of the distance the
+// half-life gives the time elapsed since the last move. The first price
+// sets it.
+func (s *ScalpingStrategy) calibrateBounds(price decimal.Decimal, at time.Time) {
+	if s.config.PriceBoundsPercent <= 0 || !price.IsPositive() {
+		return
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	s.boundsMu.Lock()
+	defer s.boundsMu.Unlock()
+
+	if s.bounds.reference.IsZero() {
+		s.bounds = priceBounds{reference: price, updated: at}
+		logger.Component("strategy").Info("price bounds calibrated",
+			"symbol", s.config.Symbol,
+			"reference", price.String(),
+			"band_percent", s.config.PriceBoundsPercent)
+		return
+	}
+
+	halfLife := s.config.PriceBoundsHalfLife
+	elapsed := at.Sub(s.bounds.updated)
+	if halfLife <= 0 || elapsed <= 0 {
+		return
+	}
+	weight := 1 - math.Pow(0.5, elapsed.Seconds()/halfLife.Seconds())
+	move := price.Sub(s.bounds.reference).Mul(decimal.NewFromFloat(weight))
+	s.bounds.reference = s.bounds.reference.Add(move)
+	s.bounds.updated = at
+}
@@ -0,0 +1,62 @@
+package strategy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestScalpingStrategy_CalibratedPriceBounds(t *testing.T) {
+	config := DefaultConfig()
+	config.PriceBoundsPercent = 50
+	config.PriceBoundsHalfLife = time.Hour
+	start := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+	// One configuration covers symbols at very different prices
+	for _, price := range []float64{0.05, 100000} {
+		strategy := NewScalpingStrategy(config, &MockExchangeForStrategy{})
+		if !strategy.validatePrice(decimal.NewFromFloat(price * 3)) {
+			t.Errorf("expected any price accepted before calibration at %v", price)
+		}
+		strategy.calibrateBounds(decimal.NewFromFloat(price), start)
+
+		tests := []struct {
+			factor float64
+			valid  bool
+		}{
+			{1, true},
+			{1.4, true},
+			{0.6, true},
+			{1.6, false},
+			{0.4, false},
+		}
+		for _, tt := range tests {
+			if got := strategy.validatePrice(decimal.NewFromFloat(price * tt.factor)); got != tt.valid {
+				t.Errorf("price %v x %v: expected valid=%v, got %v", price, tt.factor, tt.valid, got)
+			}
+		}
+	}
+}
+
+func TestScalpingStrategy_PriceBoundsFollowPrice(t *testing.T) {
+	config := DefaultConfig()
+	config.PriceBoundsPercent = 50
+	config.PriceBoundsHalfLife = time.Hour
+	strategy := NewScalpingStrategy(config, &MockExchangeForStrategy{})
+	start := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+	strategy.calibrateBounds(decimal.NewFromInt(100), start)
+	// Halfway to 140 after one half-life: bounds of 60 to 180
+	strategy.calibrateBounds(decimal.NewFromInt(140), start.Add(time.Hour))
+	low, high, ok := strategy.PriceBounds()
+	if !ok || !low.Equal(decimal.NewFromInt(60)) || !high.Equal(decimal.NewFromInt(180)) {
+		t.Errorf("expected bounds of 60 to 180, got %s to %s", low, high)
+	}
+
+	// Prices at the same time do not move it
+	strategy.calibrateBounds(decimal.NewFromInt(1000), start.Add(time.Hour))
+	if _, high, _ := strategy.PriceBounds(); !high.Equal(decimal.NewFromInt(180)) {
+		t.Errorf("expected the reference unchanged, got a high of %s", high)
+	}
+}
@@ -0,0 +1,126 @@
+package strategy
+
+import (
+	"context"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/telemetry"
+	"github.com/shopspring/decimal"
+)
+
+// PriceReference returns an independent price of symbol used to confirm or
+// reject a suspicious tick, such as the median price across exchanges
+type PriceReference func(ctx context.Context, symbol string) (decimal.Decimal, error)
+
+// SetPriceReference sets the reference suspicious ticks are checked against.
+// Without one, they are quarantined until enough ticks confirm the new level.
+func (s *ScalpingStrategy) SetPriceReference(reference PriceReference) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.priceReference = reference
+}
+
+// repeatsLastPrice reports whether price equals the latest price of the
+// history. Like any price close to it, it ends a quarantine.
+func (s *ScalpingStrategy) repeatsLastPrice(price decimal.Decimal) bool {
+	s.mu.RLock()
+	last, ok := s.prices.Last()
+	quarantined := len(s.quarantine) > 0
+	s.mu.RUnlock()
+
+	if !ok || !price.Equal(last) {
+		return false
+	}
+	if quarantined {
+		s.mu.Lock()
+		s.quarantine = nil
+		s.mu.Unlock()
+	}
+	return true
+}
+
+// screenPrice reports whether price may enter the price history. Prices
+// moving more than MaxPriceChangePercent from the last one are accepted when
+// the reference price agrees with them, dropped when it does not, and
+// quarantined when there is no reference until SpikeConfirmations ticks
+// confirm the new level. It must be called without holding s.mu.
+func (s *ScalpingStrategy) screenPrice(price decimal.Decimal) bool {
+	s.mu.RLock()
+	last, _ := s.prices.Last()
+	reference := s.priceReference
+	quarantined := len(s.quarantine) > 0
+	s.mu.RUnlock()
+
+	if s.validatePriceChange(last, price) {
+		if quarantined {
+			s.mu.Lock()
+			s.quarantine = nil
+			s.mu.Unlock()
+		}
+		return true
+	}
+
+	if reference != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), strategyAPITimeout)
+		median, err := reference(ctx, s.config.Symbol)
+		cancel()
+		if err == nil {
+			if !s.nearPrice(price, median) {
+				logger.Component("strategy").Warn("tick rejected by cross-exchange check",
+					"symbol", s.config.Symbol,
+					"price", price.String(),
+					"last", last.String(),
+					"median", median.String())
+				telemetry.RecordTickFiltered(s.config.Symbol, "cross_check")
+				return false
+			}
+			logger.Component("strategy").Info("price move confirmed across exchanges",
+				"symbol", s.config.Symbol,
+				"price", price.String(),
+				"last", last.String(),
+				"median", median.String())
+			s.mu.Lock()
+			s.quarantine = nil
+			s.mu.Unlock()
+			return true
+		}
+		logger.Component("strategy").Debug("cross-exchange price unavailable, quarantining tick",
+			"symbol", s.config.Symbol,
+			"error", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A tick away from the quarantined level starts a new quarantine
+	if len(s.quarantine) > 0 && !s.nearPrice(price, s.quarantine[0]) {
+		s.quarantine = s.quarantine[:0]
+	}
+	s.quarantine = append(s.quarantine, price)
+	if len(s.quarantine) >= s.config.SpikeConfirmations {
+		logger.Component("strategy").Info("price level confirmed after quarantine",
+			"symbol", s.config.Symbol,
+			"price", price.String(),
+			"last", last.String(),
+			"ticks", len(s.quarantine))
+		s.quarantine = nil
+		return true
+	}
+
+	logger.Component("strategy").Debug("tick quarantined",
+		"symbol", s.config.Symbol,
+		"price", price.String(),
+		"last", last.String(),
+		"ticks", len(s.quarantine))
+	telemetry.RecordTickFiltered(s.config.Symbol, "spike")
+	return false
+}
+
+// nearPrice reports whether price is within PriceDeviationPercent of target
+func (s *ScalpingStrategy) nearPrice(price, target decimal.Decimal) bool {
+	if !target.IsPositive() {
+		return false
+	}
+	deviation := price.Sub(target).Div(target).Abs().Mul(decimal.NewFromInt(100))
+	return deviation.LessThanOrEqual(decimal.NewFromFloat(s.config.PriceDeviationPercent))
+}
@@ -0,0 +1,80 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func newPriceFilterStrategy(prices ...float64) *ScalpingStrategy {
+	strategy := NewScalpingStrategy(DefaultConfig(), &MockExchangeForStrategy{})
+	for _, price := range prices {
+		strategy.prices.Push(decimal.NewFromFloat(price))
+	}
+	return strategy
+}
+
+func tick(price float64) *exchanges.Ticker {
+	return &exchanges.Ticker{Symbol: "BTC-USD", Last: decimal.NewFromFloat(price)}
+}
+
+func TestScreenPrice_QuarantinesSpikesWithoutErrors(t *testing.T) {
+	strategy := newPriceFilterStrategy(50000)
+	errs := 0
+	strategy.SetErrorCallback(func(error) { errs++ })
+
+	// A single bad tick is dropped and the next normal tick accepted
+	strategy.handleTicker(tick(60000))
+	strategy.handleTicker(tick(50100))
+	prices := strategy.GetCurrentPrices()
+	if len(prices) != 2 || !prices[1].Equal(decimal.NewFromFloat(50100)) {
+		t.Fatalf("expected the spike to be dropped, got %v", prices)
+	}
+
+	// A new level confirmed by SpikeConfirmations ticks is accepted
+	for i := 0; i < strategy.config.SpikeConfirmations; i++ {
+		strategy.handleTicker(tick(56000 + float64(i)*10))
+	}
+	prices = strategy.GetCurrentPrices()
+	if len(prices) != 3 || !prices[2].Equal(decimal.NewFromFloat(56020)) {
+		t.Fatalf("expected the confirmed level to be accepted, got %v", prices)
+	}
+	if errs != 0 {
+		t.Errorf("expected quarantined ticks not to reach the error callback, got %d errors", errs)
+	}
+}
+
+func TestScreenPrice_CrossExchangeCheck(t *testing.T) {
+	strategy := newPriceFilterStrategy(50000)
+	median := decimal.NewFromFloat(56000)
+	var referenceErr error
+	strategy.SetPriceReference(func(ctx context.Context, symbol string) (decimal.Decimal, error) {
+		return median, referenceErr
+	})
+
+	// Other exchanges moved too: the jump is real
+	if !strategy.screenPrice(decimal.NewFromFloat(56100)) {
+		t.Error("expected a move matching the median to be accepted")
+	}
+
+	// Other exchanges did not move: the tick is bad
+	median = decimal.NewFromFloat(50000)
+	if strategy.screenPrice(decimal.NewFromFloat(56100)) {
+		t.Error("expected a move away from the median to be rejected")
+	}
+	if len(strategy.quarantine) != 0 {
+		t.Error("expected rejected ticks not to be quarantined")
+	}
+
+	// Without a median the tick falls back to the quarantine
+	referenceErr = errors.New("unavailable")
+	if strategy.screenPrice(decimal.NewFromFloat(56100)) {
+		t.Error("expected the tick to be quarantined")
+	}
+	if len(strategy.quarantine) != 1 {
+		t.Errorf("expected 1 quarantined tick, got %d", len(strategy.quarantine))
+	}
+}
@@ -0,0 +1,146 @@
+package strategy
+
+import (
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// MarketRegime is the broad behaviour of a market
+type MarketRegime string
+
+const (
+	RegimeUnknown        MarketRegime = "unknown"
+	RegimeTrending       MarketRegime = "trending"
+	RegimeRanging        MarketRegime = "ranging"
+	RegimeHighVolatility MarketRegime = "high_volatility"
+)
+
+// RegimeState is a market regime classification and the measures behind it
+type RegimeState struct {
+	Regime  MarketRegime
+	ADX     float64
+	PlusDI  float64
+	MinusDI float64
+	// ATRPercent is the average true range as a percentage of the price
+	ATRPercent float64
+	// RealizedVol is the short-term realized volatility, and VolRatio its
+	// ratio to the realized volatility over the whole history
+	RealizedVol float64
+	VolRatio    float64
+}
+
+// TrendSide returns the order side that trades with the trend, or an empty
+// side when the market is not trending
+func (r RegimeState) TrendSide() exchanges.OrderSide {
+	if r.Regime != RegimeTrending || r.PlusDI == r.MinusDI {
+		return ""
+	}
+	if r.PlusDI > r.MinusDI {
+		return exchanges.OrderSideBuy
+	}
+	return exchanges.OrderSideSell
+}
+
+// ClassifyRegime classifies the market from ATR, ADX and realized volatility.
+// High volatility takes precedence over a trend, and a market that is neither
+// is ranging. The regime is unknown until there is enough history for the ADX.
+func ClassifyRegime(high, low, close []decimal.Decimal, cfg *config.Config) RegimeState {
+	state := RegimeState{Regime: RegimeUnknown}
+
+	adx, plusDI, minusDI := ADX(high, low, close, cfg.RegimeADXPeriod)
+	if len(adx) == 0 {
+		return state
+	}
+	state.ADX = adx[len(adx)-1].InexactFloat64()
+	state.PlusDI = plusDI[len(plusDI)-1].InexactFloat64()
+	state.MinusDI = minusDI[len(minusDI)-1].InexactFloat64()
+
+	price := close[len(close)-1]
+	if atr := ATR(high, low, close, cfg.RegimeADXPeriod); len(atr) > 0 && price.IsPositive() {
+		state.ATRPercent = atr[len(atr)-1].Div(price).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	}
+
+	state.RealizedVol = RealizedVolatility(close, cfg.RegimeVolWindow)
+	if longVol := RealizedVolatility(close, len(close)-1); longVol > 0 {
+		state.VolRatio = state.RealizedVol / longVol
+	}
+
+	switch {
+	case (cfg.RegimeHighVolRatio > 0 && state.VolRatio >= cfg.RegimeHighVolRatio) ||
+		(cfg.RegimeHighVolATRPercent > 0 && state.ATRPercent >= cfg.RegimeHighVolATRPercent):
+		state.Regime = RegimeHighVolatility
+	case state.ADX >= cfg.RegimeTrendADX:
+		state.Regime = RegimeTrending
+	default:
+		state.Regime = RegimeRanging
+	}
+	return state
+}
+
+// ClassifyRegimeFromPrices classifies the market from a close price series,
+// using close-to-close moves as the true range
+func ClassifyRegimeFromPrices(prices []decimal.Decimal, cfg *config.Config) RegimeState {
+	return ClassifyRegime(prices, prices, prices, cfg)
+}
+
+// Regime returns the regime classified on the last signal generation
+func (sg *SignalGenerator) Regime() RegimeState {
+	sg.mu.RLock()
+	defer sg.mu.RUnlock()
+	if sg.regime.Regime == "" {
+		return RegimeState{Regime: RegimeUnknown}
+	}
+	return sg.regime
+}
+
+func (sg *SignalGenerator) setRegime(regime RegimeState) {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	sg.regime = regime
+}
+
+// blocksCounterTrend reports whether an entry on side fades a trending
+// market, which mean-reversion entries are not allowed to do
+func (sg *SignalGenerator) blocksCounterTrend(regime RegimeState, side exchanges.OrderSide) bool {
+	if !sg.config.RegimeBlockCounterTrend {
+		return false
+	}
+	trend := regime.TrendSide()
+	return trend != "" && trend != side
+}
+
+// ApplyRegimeWeight shifts weight toward the indicators suited to the
+// regime: trend following in trends, mean reversion in ranges and band and
+// volume confirmation when volatility is high. The adjusted weights keep
+// the same total.
+func (wc *WeightCalculator) ApplyRegimeWeight(weights IndicatorWeights, regime RegimeState) IndicatorWeights {
+	adjusted := weights
+	switch regime.Regime {
+	case RegimeTrending:
+		adjusted.EMA *= 1.4
+		adjusted.RSI *= 0.6
+	case RegimeRanging:
+		adjusted.EMA *= 0.7
+		adjusted.RSI *= 1.3
+		adjusted.BB *= 1.3
+	case RegimeHighVolatility:
+		adjusted.EMA *= 0.8
+		adjusted.Volume *= 1.2
+		adjusted.BB *= 1.3
+	default:
+		return weights
+	}
+
+	before := weights.EMA + weights.RSI + weights.Volume + weights.BB
+	after := adjusted.EMA + adjusted.RSI + adjusted.Volume + adjusted.BB
+	if after == 0 {
+		return weights
+	}
+	scale := before / after
+	adjusted.EMA *= scale
+	adjusted.RSI *= scale
+	adjusted.Volume *= scale
+	adjusted.BB *= scale
+	return adjusted
+}
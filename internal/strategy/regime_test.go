@@ -0,0 +1,157 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+func seriesFrom(values []float64) []decimal.Decimal {
+	prices := make([]decimal.Decimal, len(values))
+	for i, v := range values {
+		prices[i] = decimal.NewFromFloat(v)
+	}
+	return prices
+}
+
+func trendingSeries(n int, step float64) []decimal.Decimal {
+	values := make([]float64, n)
+	for i := range values {
+		// Small pullbacks keep the directional movement realistic
+		values[i] = 100 + float64(i)*step
+		if i%4 == 3 {
+			values[i] -= step / 2
+		}
+	}
+	return seriesFrom(values)
+}
+
+func rangingSeries(n int) []decimal.Decimal {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = 100 + 0.5*math.Sin(float64(i))
+	}
+	return seriesFrom(values)
+}
+
+func TestADX(t *testing.T) {
+	trend := trendingSeries(60, 0.5)
+	adx, plusDI, minusDI := ADX(trend, trend, trend, 14)
+	if len(adx) != 60-2*14+1 {
+		t.Fatalf("unexpected ADX length %d", len(adx))
+	}
+	if adx[len(adx)-1].InexactFloat64() < 25 {
+		t.Errorf("steady uptrend should have a strong ADX, got %s", adx[len(adx)-1])
+	}
+	if !plusDI[len(plusDI)-1].GreaterThan(minusDI[len(minusDI)-1]) {
+		t.Error("uptrend should have +DI above -DI")
+	}
+
+	if adx, _, _ := ADX(trend[:20], trend[:20], trend[:20], 14); len(adx) != 0 {
+		t.Error("ADX should need twice the period of data")
+	}
+}
+
+func TestClassifyRegime(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	trend := ClassifyRegimeFromPrices(trendingSeries(60, 0.5), cfg)
+	if trend.Regime != RegimeTrending {
+		t.Errorf("expected trending regime, got %s (ADX %.1f)", trend.Regime, trend.ADX)
+	}
+	if trend.TrendSide() != exchanges.OrderSideBuy {
+		t.Errorf("uptrend should trade with buys, got %q", trend.TrendSide())
+	}
+
+	ranging := ClassifyRegimeFromPrices(rangingSeries(60), cfg)
+	if ranging.Regime != RegimeRanging {
+		t.Errorf("expected ranging regime, got %s (ADX %.1f, vol ratio %.2f)", ranging.Regime, ranging.ADX, ranging.VolRatio)
+	}
+	if ranging.TrendSide() != "" {
+		t.Error("ranging market should have no trend side")
+	}
+
+	// A quiet range followed by violent swings
+	values := make([]float64, 60)
+	for i := range values {
+		values[i] = 100 + 0.1*math.Sin(float64(i))
+		if i >= 50 {
+			values[i] = 100 + 5*math.Sin(float64(i))
+		}
+	}
+	volatile := ClassifyRegimeFromPrices(seriesFrom(values), cfg)
+	if volatile.Regime != RegimeHighVolatility {
+		t.Errorf("expected high volatility regime, got %s (vol ratio %.2f)", volatile.Regime, volatile.VolRatio)
+	}
+
+	if unknown := ClassifyRegimeFromPrices(trendingSeries(20, 0.5), cfg); unknown.Regime != RegimeUnknown {
+		t.Errorf("short history should leave the regime unknown, got %s", unknown.Regime)
+	}
+}
+
+func TestApplyRegimeWeight(t *testing.T) {
+	wc := NewWeightCalculator(config.DefaultConfig())
+	base := IndicatorWeights{EMA: 0.35, RSI: 0.35, Volume: 0.15, BB: 0.15}
+
+	trending := wc.ApplyRegimeWeight(base, RegimeState{Regime: RegimeTrending})
+	if trending.EMA <= base.EMA || trending.RSI >= base.RSI {
+		t.Errorf("trend should favour EMA over RSI, got %+v", trending)
+	}
+	ranging := wc.ApplyRegimeWeight(base, RegimeState{Regime: RegimeRanging})
+	if ranging.RSI <= base.RSI || ranging.EMA >= base.EMA {
+		t.Errorf("range should favour RSI over EMA, got %+v", ranging)
+	}
+	for _, weights := range []IndicatorWeights{trending, ranging} {
+		if sum := weights.EMA + weights.RSI + weights.Volume + weights.BB; math.Abs(sum-1) > 1e-9 {
+			t.Errorf("weights should keep their total, got %f", sum)
+		}
+	}
+	if weights := wc.ApplyRegimeWeight(base, RegimeState{Regime: RegimeUnknown}); weights != base {
+		t.Errorf("unknown regime should leave weights unchanged, got %+v", weights)
+	}
+}
+
+func TestSignalGenerator_BlocksCounterTrendEntries(t *testing.T) {
+	cfg := config.DefaultConfig()
+	sg := NewSignalGenerator(cfg)
+	uptrend := RegimeState{Regime: RegimeTrending, ADX: 40, PlusDI: 35, MinusDI: 10}
+
+	if !sg.blocksCounterTrend(uptrend, exchanges.OrderSideSell) {
+		t.Error("sell against an uptrend should be blocked")
+	}
+	if sg.blocksCounterTrend(uptrend, exchanges.OrderSideBuy) {
+		t.Error("buy with the uptrend should be allowed")
+	}
+	if sg.blocksCounterTrend(RegimeState{Regime: RegimeRanging, PlusDI: 35, MinusDI: 10}, exchanges.OrderSideSell) {
+		t.Error("ranging market should not block entries")
+	}
+
+	cfg.RegimeBlockCounterTrend = false
+	if sg.blocksCounterTrend(uptrend, exchanges.OrderSideSell) {
+		t.Error("blocking should be configurable")
+	}
+}
+
+func TestGenerateSignal_ExposesRegime(t *testing.T) {
+	sg := NewSignalGenerator(config.DefaultConfig())
+	if sg.Regime().Regime != RegimeUnknown {
+		t.Errorf("regime should be unknown before any signal, got %s", sg.Regime().Regime)
+	}
+
+	prices := trendingSeries(60, 0.5)
+	volumes := make([]decimal.Decimal, len(prices))
+	for i := range volumes {
+		volumes[i] = decimal.NewFromInt(1000)
+	}
+	signal := sg.GenerateSignal("BTC-USD", prices, volumes, testOrderBook([]float64{3, 3, 3}, []float64{1, 1, 1}))
+
+	if sg.Regime().Regime != RegimeTrending {
+		t.Errorf("generator should expose the trending regime, got %s", sg.Regime().Regime)
+	}
+	if signal.Components == nil || signal.Components.Regime != RegimeTrending {
+		t.Errorf("signal components should record the regime, got %+v", signal.Components)
+	}
+}
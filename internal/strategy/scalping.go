@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -11,12 +12,17 @@ import (
 	"github.com/guyghost/constantine/internal/config"
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/ringbuf"
 	"github.com/guyghost/constantine/internal/telemetry"
 	"github.com/shopspring/decimal"
 )
 
 const strategyAPITimeout = 5 * time.Second
 
+// maxPreloadCandles bounds the candles preloaded for a long history, whose
+// oldest tiers otherwise fill up as the strategy runs
+const maxPreloadCandles = 1000
+
 // DefaultConfig returns default scalping strategy configuration
 func DefaultConfig() *config.Config {
 	return config.DefaultConfig()
@@ -51,11 +57,38 @@ type ScalpingStrategy struct {
 	signalGenerator *SignalGenerator
 	mu              sync.RWMutex
 
-	// Market data
-	prices     []decimal.Decimal
-	volumes    []decimal.Decimal
-	orderbook  *exchanges.OrderBook
-	lastSignal *Signal
+	// Market data, older prices and volumes downsampled into the candles
+	// of a longer interval
+	prices  *ringbuf.Tiered[decimal.Decimal]
+	volumes *ringbuf.Tiered[decimal.Decimal]
+	// updateMu serializes updates, which copy the histories into scratch
+	// slices reused from one update to the next instead of allocating them
+	updateMu       sync.Mutex
+	scratchPrices  []decimal.Decimal
+	scratchVolumes []decimal.Decimal
+	orderbook      *exchanges.OrderBook
+	lastSignal     *Signal
+	// lastSignalAt is when lastSignal was emitted
+	lastSignalAt time.Time
+	// lastCandle is the timestamp of the latest candle, used to detect
+	// candles missed by the subscription
+	lastCandle time.Time
+	// lastProcessed is when the latest streamed candle was processed
+	lastProcessed time.Time
+	// quarantine holds the recent ticks that jumped away from the price
+	// history, waiting for confirmation of the new level
+	quarantine     []decimal.Decimal
+	priceReference PriceReference
+	haltCheck      HaltCheck
+	// bounds are the price sanity bounds calibrated on the symbol's price,
+	// guarded by boundsMu as prices are validated with and without mu held
+	boundsMu sync.Mutex
+	bounds   priceBounds
+	// scheduler runs the updates when set, in place of the strategy's own loop
+	scheduler *Scheduler
+	// tuned is set once the parameters are tuned online, from when entry
+	// signals carry their stop loss and take profit
+	tuned bool
 
 	// Callbacks
 	onSignal   func(*Signal)
@@ -74,12 +107,17 @@ func NewScalpingStrategy(config *config.Config, exchange exchanges.Exchange) *Sc
 		config:          config,
 		exchange:        exchange,
 		signalGenerator: NewSignalGenerator(config),
-		prices:          make([]decimal.Decimal, 0, 100),
-		volumes:         make([]decimal.Decimal, 0, 100),
+		prices:          ringbuf.NewTiered(config.History(), config.HistoryTiers, config.Downsample(), lastPrice),
+		volumes:         ringbuf.NewTiered(config.History(), config.HistoryTiers, config.Downsample(), decimal.Decimal.Add),
 		done:            make(chan struct{}),
 	}
 }
 
+// lastPrice downsamples prices as candles do, closing at the latest one
+func lastPrice(_, price decimal.Decimal) decimal.Decimal {
+	return price
+}
+
 // SetSignalCallback sets the callback for signals
 func (s *ScalpingStrategy) SetSignalCallback(callback func(*Signal)) {
 	s.mu.Lock()
@@ -141,14 +179,27 @@ func (s *ScalpingStrategy) Start(ctx context.Context) error {
 		return fmt.Errorf("strategy already running")
 	}
 	s.running = true
+	scheduler := s.scheduler
 	s.mu.Unlock()
 
 	// Start strategy loop
-	go s.run(strategyCtx, doneCh)
+	if scheduler != nil {
+		scheduler.add(strategyCtx, s, s.config.UpdateInterval, s.update)
+	} else {
+		go s.run(strategyCtx, doneCh)
+	}
 
 	return nil
 }
 
+// SetScheduler makes scheduler run the updates of the strategy instead of a
+// loop of its own. It takes effect on the next Start.
+func (s *ScalpingStrategy) SetScheduler(scheduler *Scheduler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scheduler = scheduler
+}
+
 // Stop stops the scalping strategy
 func (s *ScalpingStrategy) Stop() error {
 	s.mu.Lock()
@@ -162,6 +213,9 @@ func (s *ScalpingStrategy) Stop() error {
 		s.cancel()
 		s.cancel = nil
 	}
+	if s.scheduler != nil {
+		s.scheduler.remove(s)
+	}
 	if s.done != nil {
 		select {
 		case <-s.done:
@@ -181,6 +235,14 @@ func (s *ScalpingStrategy) IsRunning() bool {
 	return s.running
 }
 
+// LastCandleAt returns when the strategy last processed a streamed candle,
+// zero before the first one
+func (s *ScalpingStrategy) LastCandleAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastProcessed
+}
+
 // GetConfig returns the strategy configuration
 // This method provides access to the strategy's configuration parameters
 // for use by other components like the backtesting engine
@@ -193,6 +255,11 @@ func (s *ScalpingStrategy) GetSignalGenerator() *SignalGenerator {
 	return s.signalGenerator
 }
 
+// GetRegime returns the market regime classified on the last update
+func (s *ScalpingStrategy) GetRegime() RegimeState {
+	return s.signalGenerator.Regime()
+}
+
 // subscribeMarketData subscribes to market data streams
 func (s *ScalpingStrategy) subscribeMarketData(ctx context.Context) error {
 	logger.Component("strategy").Debug("subscribing to market data", "symbol", s.config.Symbol)
@@ -204,7 +271,7 @@ func (s *ScalpingStrategy) subscribeMarketData(ctx context.Context) error {
 
 	// Subscribe to candles for OHLCV data (primary data source)
 	candleCtx, cancel := context.WithTimeout(ctx, strategyAPITimeout)
-	if err := s.exchange.SubscribeCandles(candleCtx, s.config.Symbol, "1m", s.handleCandle); err != nil {
+	if err := s.exchange.SubscribeCandles(candleCtx, s.config.Symbol, s.config.CandleIntervalName(), s.handleCandle); err != nil {
 		cancel()
 		return err
 	}
@@ -247,9 +314,12 @@ func (s *ScalpingStrategy) preloadHistoricalCandles(ctx context.Context) error {
 
 	// Calculate how many candles to load
 	// We need at least 2x the longest period to ensure smooth indicator calculations
+	s.mu.RLock()
 	maxPeriod := max(s.config.ShortEMAPeriod, s.config.LongEMAPeriod, s.config.RSIPeriod, 20) // 20 for Bollinger Bands
+	s.mu.RUnlock()
 	minCandles := maxPeriod * 2
-	candlesToLoad := max(minCandles, 100) // Load at least 100 candles
+	// Fill the history, its downsampled tiers as far as exchanges serve
+	candlesToLoad := max(minCandles, min(s.config.HistorySpan(), maxPreloadCandles))
 
 	logger.Component("strategy").Debug("calculated candles to load",
 		"symbol", s.config.Symbol,
@@ -260,7 +330,7 @@ func (s *ScalpingStrategy) preloadHistoricalCandles(ctx context.Context) error {
 	loadCtx, cancel := context.WithTimeout(ctx, strategyAPITimeout*2) // Longer timeout for historical data
 	defer cancel()
 
-	candles, err := s.exchange.GetCandles(loadCtx, s.config.Symbol, "1m", candlesToLoad)
+	candles, err := s.exchange.GetCandles(loadCtx, s.config.Symbol, s.config.CandleIntervalName(), candlesToLoad)
 	if err != nil {
 		return fmt.Errorf("failed to load historical candles: %w", err)
 	}
@@ -288,20 +358,18 @@ func (s *ScalpingStrategy) preloadHistoricalCandles(ctx context.Context) error {
 		}
 
 		// Add to price and volume history
-		s.prices = append(s.prices, candle.Close)
-		s.volumes = append(s.volumes, candle.Volume)
-
-		// Keep only last 100 entries to prevent memory issues
-		if len(s.prices) > 100 {
-			s.prices = s.prices[1:]
-			s.volumes = s.volumes[1:]
+		s.prices.Push(candle.Close)
+		s.volumes.Push(candle.Volume)
+		s.calibrateBounds(candle.Close, candle.Timestamp)
+		if candle.Timestamp.After(s.lastCandle) {
+			s.lastCandle = candle.Timestamp
 		}
 	}
 
 	logger.Component("strategy").Debug("historical candles processed",
 		"symbol", s.config.Symbol,
-		"prices_count", len(s.prices),
-		"volumes_count", len(s.volumes))
+		"prices_count", s.prices.Len(),
+		"volumes_count", s.volumes.Len())
 
 	return nil
 }
@@ -322,48 +390,47 @@ func max(values ...int) int {
 
 // handleTicker handles ticker updates
 func (s *ScalpingStrategy) handleTicker(ticker *exchanges.Ticker) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	logger.Component("strategy").Debug("received ticker",
-		"symbol", ticker.Symbol,
-		"price", ticker.Last.String(),
-		"bid", ticker.Bid.String(),
-		"ask", ticker.Ask.String())
-
-	// Price sanity checks
-	if !s.validatePrice(ticker.Last) {
-		s.emitError(fmt.Errorf("price validation failed for %s: price=%s", s.config.Symbol, ticker.Last))
-		return
-	}
+	if logger.DebugEnabled() {
+		logger.Component("strategy").Debug("received ticker",
+			"symbol", ticker.Symbol,
+			"price", ticker.Last.String(),
+			"bid", ticker.Bid.String(),
+			"ask", ticker.Ask.String())
+	}
+
+	// A repeated price passed the checks when it entered the history, and
+	// skipping them spares their decimal arithmetic on most ticks
+	if !s.repeatsLastPrice(ticker.Last) {
+		// Price sanity checks
+		if !s.validatePrice(ticker.Last) {
+			s.emitError(fmt.Errorf("price validation failed for %s: price=%s", s.config.Symbol, ticker.Last))
+			return
+		}
 
-	// Check for abnormal price movements
-	if len(s.prices) > 0 {
-		lastPrice := s.prices[len(s.prices)-1]
-		if !s.validatePriceChange(lastPrice, ticker.Last) {
-			s.emitError(fmt.Errorf("abnormal price movement detected for %s: %s -> %s",
-				s.config.Symbol, lastPrice, ticker.Last))
+		// Filter abnormal price movements
+		if !s.screenPrice(ticker.Last) {
 			return
 		}
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// Update price history
-	s.prices = append(s.prices, ticker.Last)
+	s.prices.Push(ticker.Last)
+	s.calibrateBounds(ticker.Last, ticker.Timestamp)
 
-	// Keep only last 100 prices
-	if len(s.prices) > 100 {
-		s.prices = s.prices[1:]
+	if logger.DebugEnabled() {
+		logger.Component("strategy").Debug("price history updated",
+			"symbol", s.config.Symbol,
+			"prices_count", s.prices.Len())
 	}
-
-	logger.Component("strategy").Debug("price history updated",
-		"symbol", s.config.Symbol,
-		"prices_count", len(s.prices))
 }
 
 // validatePrice checks if a price is within acceptable ranges
 func (s *ScalpingStrategy) validatePrice(price decimal.Decimal) bool {
 	// Price must be positive
-	if price.LessThanOrEqual(decimal.Zero) {
+	if price.Sign() <= 0 {
 		return false
 	}
 
@@ -375,7 +442,7 @@ func (s *ScalpingStrategy) validatePrice(price decimal.Decimal) bool {
 		return false
 	}
 
-	return true
+	return s.withinCalibratedBounds(price)
 }
 
 // validatePriceChange checks if price movement is within acceptable limits
@@ -401,19 +468,37 @@ func (s *ScalpingStrategy) handleOrderBook(orderbook *exchanges.OrderBook) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	logger.Component("strategy").Debug("received orderbook",
-		"symbol", orderbook.Symbol,
-		"bids_count", len(orderbook.Bids),
-		"asks_count", len(orderbook.Asks))
+	if logger.DebugEnabled() {
+		logger.Component("strategy").Debug("received orderbook",
+			"symbol", orderbook.Symbol,
+			"bids_count", len(orderbook.Bids),
+			"asks_count", len(orderbook.Asks))
+	}
 
 	s.orderbook = orderbook
 }
 
 // handleCandle handles candle updates
 func (s *ScalpingStrategy) handleCandle(candle *exchanges.Candle) {
+	missed := s.backfillCandles(candle.Timestamp)
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	for _, c := range missed {
+		if !c.Timestamp.After(s.lastCandle) || !s.validatePrice(c.Close) {
+			continue
+		}
+		s.prices.Push(c.Close)
+		s.volumes.Push(c.Volume)
+		s.calibrateBounds(c.Close, c.Timestamp)
+		s.lastCandle = c.Timestamp
+	}
+	if candle.Timestamp.After(s.lastCandle) {
+		s.lastCandle = candle.Timestamp
+	}
+	s.lastProcessed = time.Now()
+
 	logger.Component("strategy").Info("📊 candle received",
 		"symbol", candle.Symbol,
 		"timestamp", candle.Timestamp.Format("15:04:05"),
@@ -424,24 +509,65 @@ func (s *ScalpingStrategy) handleCandle(candle *exchanges.Candle) {
 		"volume", candle.Volume.StringFixed(4))
 
 	// Use close price for price history (most relevant for indicators)
-	s.prices = append(s.prices, candle.Close)
+	s.prices.Push(candle.Close)
 
 	// Update volume history
-	s.volumes = append(s.volumes, candle.Volume)
+	s.volumes.Push(candle.Volume)
 
-	// Keep only last 100 entries
-	if len(s.prices) > 100 {
-		s.prices = s.prices[1:]
+	logger.Component("strategy").Debug("candle processed",
+		"symbol", s.config.Symbol,
+		"prices_count", s.prices.Len(),
+		"volumes_count", s.volumes.Len(),
+		"ready_for_signals", s.prices.Len() >= s.config.LongEMAPeriod)
+}
+
+// backfillCandles fetches the candles missed between the latest candle and
+// a candle received at timestamp, oldest first, so indicators are not
+// computed over a gapped history after a disconnect
+func (s *ScalpingStrategy) backfillCandles(timestamp time.Time) []exchanges.Candle {
+	s.mu.RLock()
+	last := s.lastCandle
+	s.mu.RUnlock()
+
+	if last.IsZero() {
+		return nil
 	}
-	if len(s.volumes) > 100 {
-		s.volumes = s.volumes[1:]
+	missing := int(timestamp.Sub(last)/s.config.Candles()) - 1
+	if missing < 1 {
+		return nil
 	}
+	// Older candles would be trimmed from the history anyway
+	limit := min(missing, s.config.History()) + 1
 
-	logger.Component("strategy").Debug("candle processed",
+	ctx, cancel := context.WithTimeout(context.Background(), strategyAPITimeout)
+	defer cancel()
+	candles, err := s.exchange.GetCandles(ctx, s.config.Symbol, s.config.CandleIntervalName(), limit)
+	if err != nil {
+		logger.Component("strategy").Warn("failed to backfill missed candles",
+			"symbol", s.config.Symbol,
+			"missing", missing,
+			"error", err)
+		return nil
+	}
+
+	missed := make([]exchanges.Candle, 0, len(candles))
+	for _, c := range candles {
+		if c.Timestamp.After(last) && c.Timestamp.Before(timestamp) {
+			missed = append(missed, c)
+		}
+	}
+	sort.Slice(missed, func(i, j int) bool {
+		return missed[i].Timestamp.Before(missed[j].Timestamp)
+	})
+
+	logger.Component("strategy").Info("candle gap healed",
 		"symbol", s.config.Symbol,
-		"prices_count", len(s.prices),
-		"volumes_count", len(s.volumes),
-		"ready_for_signals", len(s.prices) >= s.config.LongEMAPeriod)
+		"from", last.Format("15:04:05"),
+		"to", timestamp.Format("15:04:05"),
+		"missing", missing,
+		"backfilled", len(missed))
+	telemetry.RecordCandleGapHealed(s.config.Symbol, len(missed))
+	return missed
 }
 
 // handleTrade handles trade updates
@@ -456,16 +582,11 @@ func (s *ScalpingStrategy) handleTrade(trade *exchanges.Trade) {
 		"amount", trade.Amount.String())
 
 	// Update volume history (additional to candles)
-	s.volumes = append(s.volumes, trade.Amount)
-
-	// Keep only last 100 volumes
-	if len(s.volumes) > 100 {
-		s.volumes = s.volumes[1:]
-	}
+	s.volumes.Push(trade.Amount)
 
 	logger.Component("strategy").Debug("volume history updated",
 		"symbol", s.config.Symbol,
-		"volumes_count", len(s.volumes))
+		"volumes_count", s.volumes.Len())
 }
 
 // run is the main strategy loop
@@ -487,19 +608,25 @@ func (s *ScalpingStrategy) run(ctx context.Context, done <-chan struct{}) {
 
 // update performs strategy analysis and generates signals
 func (s *ScalpingStrategy) update(ctx context.Context) {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	// The snapshot is only read during this update, so nothing may keep
+	// prices or volumes once it returns
 	s.mu.RLock()
-	prices := make([]decimal.Decimal, len(s.prices))
-	copy(prices, s.prices)
-	volumes := make([]decimal.Decimal, len(s.volumes))
-	copy(volumes, s.volumes)
+	s.scratchPrices = s.prices.AppendTo(s.scratchPrices[:0])
+	s.scratchVolumes = s.volumes.AppendTo(s.scratchVolumes[:0])
 	orderbook := s.orderbook
 	s.mu.RUnlock()
+	prices, volumes := s.scratchPrices, s.scratchVolumes
 
-	logger.Component("strategy").Debug("strategy update",
-		"symbol", s.config.Symbol,
-		"prices_count", len(prices),
-		"volumes_count", len(volumes),
-		"has_orderbook", orderbook != nil)
+	if logger.DebugEnabled() {
+		logger.Component("strategy").Debug("strategy update",
+			"symbol", s.config.Symbol,
+			"prices_count", len(prices),
+			"volumes_count", len(volumes),
+			"has_orderbook", orderbook != nil)
+	}
 
 	// Need enough data for analysis
 	if len(prices) < s.config.LongEMAPeriod {
@@ -522,6 +649,14 @@ func (s *ScalpingStrategy) update(ctx context.Context) {
 	if signal.Type == SignalTypeNone {
 		return
 	}
+	if s.tuned && signal.Type == SignalTypeEntry {
+		signal.StopLossPercent = s.config.StopLossPercent
+		signal.TakeProfitPercent = s.config.TakeProfitPercent
+	}
+	if s.entryHalted(signal) {
+		s.checkExitConditions(ctx, prices)
+		return
+	}
 
 	logger.Component("strategy").Debug("generated signal",
 		"symbol", s.config.Symbol,
@@ -542,13 +677,21 @@ func (s *ScalpingStrategy) update(ctx context.Context) {
 	}
 
 	// Check if we should emit this signal
+	now := time.Now()
 	s.mu.Lock()
-	shouldEmit := s.lastSignal == nil || signal.Type != s.lastSignal.Type || signal.Side != s.lastSignal.Side
+	shouldEmit := !debounced(s.config, signal, s.lastSignal, s.lastSignalAt, now)
 	if shouldEmit {
 		s.lastSignal = signal
+		s.lastSignalAt = now
 	}
 	callback := s.onSignal
 	s.mu.Unlock()
+	if !shouldEmit {
+		logger.Component("strategy").Debug("signal debounced",
+			"symbol", s.config.Symbol,
+			"type", signal.Type,
+			"side", signal.Side)
+	}
 
 	// Emit signal
 	if shouldEmit && callback != nil {
@@ -627,9 +770,6 @@ func (s *ScalpingStrategy) emitError(err error) {
 
 // ProcessCandle processes a new candle for the strategy
 func (s *ScalpingStrategy) ProcessCandle(candle exchanges.Candle) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	logger.Component("strategy").Debug("processing candle",
 		"symbol", candle.Symbol,
 		"timestamp", candle.Timestamp,
@@ -645,34 +785,25 @@ func (s *ScalpingStrategy) ProcessCandle(candle exchanges.Candle) {
 		return
 	}
 
-	// Check for abnormal price movements
-	if len(s.prices) > 0 {
-		lastPrice := s.prices[len(s.prices)-1]
-		if !s.validatePriceChange(lastPrice, candle.Close) {
-			s.emitError(fmt.Errorf("abnormal price movement detected for %s: %s -> %s",
-				s.config.Symbol, lastPrice, candle.Close))
-			return
-		}
+	// Filter abnormal price movements
+	if !s.screenPrice(candle.Close) {
+		return
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	// Update price history with close price
-	s.prices = append(s.prices, candle.Close)
+	s.prices.Push(candle.Close)
+	s.calibrateBounds(candle.Close, candle.Timestamp)
 
 	// Update volume history
-	s.volumes = append(s.volumes, candle.Volume)
-
-	// Keep only last 100 entries
-	if len(s.prices) > 100 {
-		s.prices = s.prices[1:]
-	}
-	if len(s.volumes) > 100 {
-		s.volumes = s.volumes[1:]
-	}
+	s.volumes.Push(candle.Volume)
 
 	logger.Component("strategy").Debug("candle processed",
 		"symbol", s.config.Symbol,
-		"prices_count", len(s.prices),
-		"volumes_count", len(s.volumes))
+		"prices_count", s.prices.Len(),
+		"volumes_count", s.volumes.Len())
 }
 
 // GetLastSignal returns the last generated signal
@@ -687,9 +818,7 @@ func (s *ScalpingStrategy) GetCurrentPrices() []decimal.Decimal {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	prices := make([]decimal.Decimal, len(s.prices))
-	copy(prices, s.prices)
-	return prices
+	return s.prices.Values()
 }
 
 // GetOrderBook returns the current order book
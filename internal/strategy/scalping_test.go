@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/testutils"
 	"github.com/shopspring/decimal"
 )
 
@@ -86,6 +87,18 @@ func (m *MockExchangeForStrategy) SubscribeCandles(ctx context.Context, symbol s
 }
 func (m *MockExchangeForStrategy) Name() string               { return "mock" }
 func (m *MockExchangeForStrategy) SupportedSymbols() []string { return []string{"BTC-USD"} }
+func (m *MockExchangeForStrategy) Capabilities() exchanges.Capabilities {
+	return exchanges.Capabilities{}
+}
+func (m *MockExchangeForStrategy) SetLeverage(ctx context.Context, symbol string, leverage decimal.Decimal) error {
+	return nil
+}
+func (m *MockExchangeForStrategy) SetMarginMode(ctx context.Context, symbol string, mode exchanges.MarginMode) error {
+	return nil
+}
+func (m *MockExchangeForStrategy) GetLeverage(ctx context.Context, symbol string) (*exchanges.LeverageSettings, error) {
+	return &exchanges.LeverageSettings{Symbol: symbol}, nil
+}
 
 func TestDefaultConfig(t *testing.T) {
 	config := DefaultConfig()
@@ -440,3 +453,197 @@ func TestScalpingStrategy_Preloading(t *testing.T) {
 		t.Error("Strategy should not be running after stop")
 	}
 }
+
+func TestScalpingStrategy_HandleCandleBackfillsGap(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	candle := func(minute int) *exchanges.Candle {
+		return &exchanges.Candle{
+			Symbol:    "BTC-USD",
+			Timestamp: start.Add(time.Duration(minute) * time.Minute),
+			Close:     decimal.NewFromInt(int64(50000 + minute)),
+			Volume:    decimal.NewFromInt(1),
+		}
+	}
+
+	exchange := testutils.NewTestExchange("test")
+	exchange.CandlesValue = nil
+	for minute := 0; minute <= 6; minute++ {
+		exchange.CandlesValue = append(exchange.CandlesValue, *candle(minute))
+	}
+	strategy := NewScalpingStrategy(DefaultConfig(), exchange)
+
+	// Consecutive candles need no backfill
+	strategy.handleCandle(candle(0))
+	strategy.handleCandle(candle(1))
+	if got := len(strategy.GetCurrentPrices()); got != 2 {
+		t.Fatalf("expected 2 prices without a gap, got %d", got)
+	}
+
+	// A candle arriving five minutes later heals the four missed candles
+	strategy.handleCandle(candle(6))
+	prices := strategy.GetCurrentPrices()
+	if len(prices) != 7 {
+		t.Fatalf("expected 7 prices after healing the gap, got %d", len(prices))
+	}
+	for i, price := range prices {
+		if !price.Equal(candle(i).Close) {
+			t.Errorf("price %d: expected %s, got %s", i, candle(i).Close, price)
+		}
+	}
+}
+
+func TestSignalGenerator_ShouldExitWithoutEntryPrice(t *testing.T) {
+	sg := NewSignalGenerator(DefaultConfig())
+	position := &exchanges.Position{Symbol: "BTC-USD", Side: exchanges.OrderSideBuy, Size: decimal.NewFromInt(1)}
+
+	testutils.AssertFalse(t, sg.ShouldExit(position, decimal.NewFromInt(100), decimal.NewFromInt(50)), "Position without entry price should not exit")
+}
+
+// newHotPathStrategy returns a strategy with a full price and volume history
+func newHotPathStrategy() *ScalpingStrategy {
+	strategy := NewScalpingStrategy(DefaultConfig(), &MockExchangeForStrategy{})
+	for i := 0; i < strategy.config.History(); i++ {
+		strategy.prices.Push(decimal.NewFromInt(50000 + int64(i%7)))
+		strategy.volumes.Push(decimal.NewFromInt(10 + int64(i%3)))
+	}
+	return strategy
+}
+
+func TestScalpingStrategy_HotPathAllocations(t *testing.T) {
+	strategy := newHotPathStrategy()
+	orderbook := &exchanges.OrderBook{Symbol: "BTC-USD"}
+	unchanged := &exchanges.Ticker{Symbol: "BTC-USD", Last: strategy.GetCurrentPrices()[strategy.config.History()-1]}
+
+	if allocs := testing.AllocsPerRun(100, func() { strategy.handleOrderBook(orderbook) }); allocs != 0 {
+		t.Errorf("handleOrderBook should not allocate, got %.0f allocations", allocs)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { strategy.handleTicker(unchanged) }); allocs != 0 {
+		t.Errorf("handleTicker should not allocate for an unchanged price, got %.0f allocations", allocs)
+	}
+}
+
+func TestScalpingStrategy_UpdateReusesSnapshot(t *testing.T) {
+	strategy := newHotPathStrategy()
+	ctx := context.Background()
+
+	strategy.update(ctx)
+	first := &strategy.scratchPrices[0]
+	strategy.handleTicker(&exchanges.Ticker{Symbol: "BTC-USD", Last: decimal.NewFromInt(50010)})
+	strategy.update(ctx)
+
+	testutils.AssertTrue(t, first == &strategy.scratchPrices[0], "Update should copy the history into the same buffer")
+	testutils.AssertTrue(t, strategy.scratchPrices[strategy.config.History()-1].Equal(decimal.NewFromInt(50010)), "Update should see the latest price")
+}
+
+func BenchmarkScalpingStrategy_HandleTicker(b *testing.B) {
+	strategy := newHotPathStrategy()
+	// Mostly repeated prices, as between trades, with a move every 10 ticks
+	tickers := []*exchanges.Ticker{
+		{Symbol: "BTC-USD", Last: decimal.NewFromInt(50003)},
+		{Symbol: "BTC-USD", Last: decimal.NewFromInt(50004)},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strategy.handleTicker(tickers[(i/10)%2])
+	}
+}
+
+func BenchmarkScalpingStrategy_HandleOrderBook(b *testing.B) {
+	strategy := newHotPathStrategy()
+	orderbook := &exchanges.OrderBook{Symbol: "BTC-USD"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strategy.handleOrderBook(orderbook)
+	}
+}
+
+func BenchmarkScalpingStrategy_Update(b *testing.B) {
+	strategy := newHotPathStrategy()
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		strategy.update(ctx)
+	}
+}
+
+func TestScalpingStrategy_CandleIntervalAndHistory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	config := DefaultConfig()
+	config.CandleInterval = 5 * time.Minute
+	config.HistorySize = 30
+
+	// Served as missed if one-minute candles were expected
+	exchange := testutils.NewTestExchange("test")
+	exchange.CandlesValue = []exchanges.Candle{{
+		Symbol:    "BTC-USD",
+		Timestamp: start.Add(37*5*time.Minute + 2*time.Minute),
+		Close:     decimal.NewFromInt(1),
+	}}
+	strategy := NewScalpingStrategy(config, exchange)
+
+	// Candles five minutes apart are consecutive
+	for i := 0; i < 40; i++ {
+		strategy.handleCandle(&exchanges.Candle{
+			Symbol:    "BTC-USD",
+			Timestamp: start.Add(time.Duration(i) * 5 * time.Minute),
+			Close:     decimal.NewFromInt(int64(50000 + i)),
+			Volume:    decimal.NewFromInt(1),
+		})
+	}
+
+	prices := strategy.GetCurrentPrices()
+	if len(prices) != 30 {
+		t.Fatalf("expected the history to keep 30 prices, got %d", len(prices))
+	}
+	for i, price := range prices {
+		if !price.Equal(decimal.NewFromInt(int64(50010 + i))) {
+			t.Fatalf("price %d: expected the latest candles without backfill, got %s", i, price)
+		}
+	}
+}
+
+func TestScalpingStrategy_DownsampledHistory(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	config := DefaultConfig()
+	config.HistorySize = 30
+	config.HistoryTiers = 2
+	config.HistoryDownsample = 5
+	config.LongEMAPeriod = 60
+
+	strategy := NewScalpingStrategy(config, testutils.NewTestExchange("test"))
+	for i := 0; i < 400; i++ {
+		strategy.handleCandle(&exchanges.Candle{
+			Symbol:    "BTC-USD",
+			Timestamp: start.Add(time.Duration(i) * time.Minute),
+			Close:     decimal.NewFromInt(int64(50000 + i)),
+			Volume:    decimal.NewFromInt(1),
+		})
+	}
+
+	// The 30 latest candles at full resolution, the 150 before in 5-minute
+	// candles and the 220 before in 9 of 25 minutes, the latest still open
+	prices := strategy.GetCurrentPrices()
+	if len(prices) != 9+30+30 {
+		t.Fatalf("expected 69 prices across the tiers, got %d", len(prices))
+	}
+	if prices[39].IntPart() != 50370 || prices[68].IntPart() != 50399 {
+		t.Errorf("the latest prices should be at full resolution, got %s to %s", prices[39], prices[68])
+	}
+	if prices[9].IntPart() != 50224 || prices[38].IntPart() != 50369 || prices[0].IntPart() != 50024 {
+		t.Errorf("downsampled prices should close on their latest candle, got %s, %s and %s", prices[0], prices[9], prices[38])
+	}
+
+	volumes := strategy.volumes.Values()
+	if !volumes[0].Equal(decimal.NewFromInt(25)) || !volumes[9].Equal(decimal.NewFromInt(5)) {
+		t.Errorf("downsampled volumes should add up, got %s and %s", volumes[0], volumes[9])
+	}
+	if len(prices) < config.LongEMAPeriod {
+		t.Error("the history should hold a long EMA over more periods than a tier")
+	}
+}
@@ -0,0 +1,273 @@
+package strategy
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+)
+
+// staggerStep spreads the first updates of successive strategies over their
+// interval. Multiples of the golden ratio stay evenly spread however many
+// strategies are added.
+const staggerStep = 0.6180339887498949
+
+// Scheduler runs the updates of many strategies on a fixed pool of workers
+// instead of a loop per strategy. Updates falling due together are dispatched
+// as a batch, at most one per worker at a time, and the first update of each
+// strategy is offset within its interval so their load spreads evenly.
+type Scheduler struct {
+	workers int
+
+	mu      sync.Mutex
+	entries map[any]*scheduledUpdate // by the key it was added with
+	queue   updateQueue
+	added   int // strategies added so far, used to stagger them
+	stats   SchedulerStats
+	wake    chan struct{}
+	cancel  context.CancelFunc
+	done    sync.WaitGroup
+}
+
+// SchedulerStats counts the work of a scheduler
+type SchedulerStats struct {
+	Strategies int    // Strategies scheduled
+	InFlight   int    // Updates running
+	Updates    uint64 // Updates completed
+	// Overruns counts updates skipped because the previous one of the same
+	// strategy was still running or waiting for a worker
+	Overruns uint64
+}
+
+// scheduledUpdate is a strategy waiting for its next update
+type scheduledUpdate struct {
+	key      any
+	ctx      context.Context
+	update   func(ctx context.Context)
+	interval time.Duration
+	due      time.Time
+	index    int // position in the queue, -1 while running
+	removed  bool
+}
+
+// NewScheduler creates a scheduler running up to workers updates at a time
+func NewScheduler(workers int) *Scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Scheduler{
+		workers: workers,
+		entries: make(map[any]*scheduledUpdate),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Start starts the dispatcher and the workers until ctx is done or Stop is
+// called. Strategies may be added before or after.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return nil
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	jobs := make(chan *scheduledUpdate)
+	s.done.Add(s.workers + 1)
+	go s.dispatch(runCtx, jobs)
+	for i := 0; i < s.workers; i++ {
+		go s.work(runCtx, jobs)
+	}
+	logger.Component("strategy").Info("strategy scheduler started", "workers", s.workers)
+	return nil
+}
+
+// Stop stops the scheduler and waits for the running updates
+func (s *Scheduler) Stop() error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	s.done.Wait()
+	return nil
+}
+
+// Stats returns the current counters
+func (s *Scheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	stats.Strategies = len(s.entries)
+	return stats
+}
+
+// add schedules update every interval with ctx under key, the first call
+// staggered within the interval. It stops when ctx is done or key is removed.
+func (s *Scheduler) add(ctx context.Context, key any, interval time.Duration, update func(ctx context.Context)) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.entries[key]; exists {
+		return
+	}
+	_, fraction := math.Modf(float64(s.added) * staggerStep)
+	s.added++
+	entry := &scheduledUpdate{
+		key:      key,
+		ctx:      ctx,
+		update:   update,
+		interval: interval,
+		due:      time.Now().Add(time.Duration(fraction * float64(interval))),
+	}
+	s.entries[key] = entry
+	heap.Push(&s.queue, entry)
+	s.notify()
+}
+
+// remove stops scheduling the updates added under key. An update already
+// running completes.
+func (s *Scheduler) remove(key any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, exists := s.entries[key]
+	if !exists {
+		return
+	}
+	delete(s.entries, key)
+	entry.removed = true
+	if entry.index >= 0 {
+		heap.Remove(&s.queue, entry.index)
+	}
+}
+
+// notify wakes the dispatcher up. Must be called with the lock held.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// dispatch hands the due updates to the workers, sleeping until the next one
+// falls due
+func (s *Scheduler) dispatch(ctx context.Context, jobs chan<- *scheduledUpdate) {
+	defer s.done.Done()
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		now := time.Now()
+		s.mu.Lock()
+		var batch []*scheduledUpdate
+		for s.queue.Len() > 0 && !s.queue[0].due.After(now) {
+			batch = append(batch, heap.Pop(&s.queue).(*scheduledUpdate))
+		}
+		wait := time.Hour
+		if s.queue.Len() > 0 {
+			wait = s.queue[0].due.Sub(now)
+		}
+		s.mu.Unlock()
+
+		for _, entry := range batch {
+			select {
+			case jobs <- entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if len(batch) > 0 {
+			// Handing the batch out may have taken a while
+			continue
+		}
+
+		timer.Reset(wait)
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// work runs updates until ctx is done
+func (s *Scheduler) work(ctx context.Context, jobs <-chan *scheduledUpdate) {
+	defer s.done.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case entry := <-jobs:
+			s.run(entry)
+		}
+	}
+}
+
+// run calls the update of entry and schedules the next one
+func (s *Scheduler) run(entry *scheduledUpdate) {
+	s.mu.Lock()
+	s.stats.InFlight++
+	s.mu.Unlock()
+
+	if entry.ctx.Err() == nil {
+		entry.update(entry.ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.InFlight--
+	s.stats.Updates++
+	if entry.removed {
+		return
+	}
+	if entry.ctx.Err() != nil {
+		delete(s.entries, entry.key)
+		return
+	}
+	// Keep the phase of the strategy, skipping the updates it overran
+	entry.due = entry.due.Add(entry.interval)
+	if late := time.Since(entry.due); late >= 0 {
+		missed := late/entry.interval + 1
+		entry.due = entry.due.Add(missed * entry.interval)
+		s.stats.Overruns += uint64(missed)
+	}
+	heap.Push(&s.queue, entry)
+	s.notify()
+}
+
+// updateQueue is a min-heap of updates by due time
+type updateQueue []*scheduledUpdate
+
+func (q updateQueue) Len() int           { return len(q) }
+func (q updateQueue) Less(i, j int) bool { return q[i].due.Before(q[j].due) }
+func (q updateQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *updateQueue) Push(x any) {
+	entry := x.(*scheduledUpdate)
+	entry.index = len(*q)
+	*q = append(*q, entry)
+}
+
+func (q *updateQueue) Pop() any {
+	old := *q
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	entry.index = -1
+	*q = old[:n-1]
+	return entry
+}
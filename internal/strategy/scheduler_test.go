@@ -0,0 +1,182 @@
+package strategy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_BoundsConcurrency(t *testing.T) {
+	scheduler := NewScheduler(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var running, peak atomic.Int32
+	counts := make([]atomic.Int32, 10)
+	for i := range counts {
+		scheduler.add(ctx, i, 10*time.Millisecond, func(context.Context) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(2 * time.Millisecond)
+			running.Add(-1)
+			counts[i].Add(1)
+		})
+	}
+
+	if err := scheduler.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if err := scheduler.Stop(); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+
+	if p := peak.Load(); p > 2 {
+		t.Errorf("Expected at most 2 updates at a time, got %d", p)
+	}
+	for i := range counts {
+		if counts[i].Load() == 0 {
+			t.Errorf("Strategy %d was never updated", i)
+		}
+	}
+	stats := scheduler.Stats()
+	if stats.Strategies != 10 || stats.InFlight != 0 || stats.Updates == 0 {
+		t.Errorf("Unexpected stats after stop: %+v", stats)
+	}
+}
+
+func TestScheduler_StaggersFirstUpdates(t *testing.T) {
+	scheduler := NewScheduler(1)
+	interval := time.Second
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		scheduler.add(context.Background(), i, interval, func(context.Context) {})
+	}
+
+	offsets := make([]time.Duration, 0, len(scheduler.queue))
+	for _, entry := range scheduler.queue {
+		offset := entry.due.Sub(start)
+		if offset < 0 || offset >= interval+10*time.Millisecond {
+			t.Fatalf("First update should fall within the interval, got %s", offset)
+		}
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	for i := 1; i < len(offsets); i++ {
+		if gap := offsets[i] - offsets[i-1]; gap < interval/10 {
+			t.Errorf("First updates should be spread over the interval, got %v", offsets)
+			break
+		}
+	}
+}
+
+func TestScheduler_Remove(t *testing.T) {
+	scheduler := NewScheduler(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	for _, key := range []string{"kept", "removed"} {
+		scheduler.add(ctx, key, 5*time.Millisecond, func(context.Context) {
+			mu.Lock()
+			calls[key]++
+			mu.Unlock()
+		})
+	}
+	if err := scheduler.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer scheduler.Stop()
+
+	time.Sleep(30 * time.Millisecond)
+	scheduler.remove("removed")
+	mu.Lock()
+	removedCalls := calls["removed"]
+	mu.Unlock()
+	time.Sleep(30 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls["removed"] > removedCalls+1 {
+		t.Errorf("Removed strategy kept being updated: %d calls, then %d", removedCalls, calls["removed"])
+	}
+	if calls["kept"] <= removedCalls {
+		t.Errorf("Kept strategy should keep being updated, got %d calls", calls["kept"])
+	}
+	if n := scheduler.Stats().Strategies; n != 1 {
+		t.Errorf("Expected 1 strategy scheduled, got %d", n)
+	}
+}
+
+func TestScheduler_DropsCanceledStrategies(t *testing.T) {
+	scheduler := NewScheduler(1)
+	if err := scheduler.Start(context.Background()); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer scheduler.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls atomic.Int32
+	scheduler.add(ctx, "symbol", 5*time.Millisecond, func(context.Context) { calls.Add(1) })
+	cancel()
+
+	deadline := time.Now().Add(time.Second)
+	for scheduler.Stats().Strategies != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Canceled strategy should be dropped from the schedule")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if n := calls.Load(); n != 0 {
+		t.Errorf("Canceled strategy should not be updated, got %d calls", n)
+	}
+}
+
+func TestScalpingStrategy_Scheduled(t *testing.T) {
+	scheduler := NewScheduler(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := scheduler.Start(ctx); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer scheduler.Stop()
+
+	strategies := make([]*ScalpingStrategy, 3)
+	for i := range strategies {
+		config := DefaultConfig()
+		config.Symbol = fmt.Sprintf("SYM%d-USD", i)
+		config.UpdateInterval = 5 * time.Millisecond
+		strategies[i] = NewScalpingStrategy(config, &MockExchangeForStrategy{})
+		strategies[i].SetScheduler(scheduler)
+		if err := strategies[i].Start(ctx); err != nil {
+			t.Fatalf("failed to start strategy: %v", err)
+		}
+	}
+	if n := scheduler.Stats().Strategies; n != 3 {
+		t.Fatalf("Expected 3 strategies scheduled, got %d", n)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if scheduler.Stats().Updates == 0 {
+		t.Error("Scheduler should have run strategy updates")
+	}
+
+	for _, s := range strategies {
+		if err := s.Stop(); err != nil {
+			t.Fatalf("failed to stop strategy: %v", err)
+		}
+	}
+	if n := scheduler.Stats().Strategies; n != 0 {
+		t.Errorf("Stopped strategies should leave the schedule, %d left", n)
+	}
+}
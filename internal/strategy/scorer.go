@@ -0,0 +1,276 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/logger"
+)
+
+// SignalScorer scores entry signals with an externally trained model
+type SignalScorer interface {
+	// Score returns the probability, from 0 to 1, that the signal is profitable
+	Score(signal *Signal) (float64, error)
+}
+
+// Features the scoring models are trained on, derived from the signal and its components
+const (
+	FeatureStrength           = "strength"
+	FeatureSide               = "side" // 1 for buys, -1 for sells
+	FeatureRSI                = "rsi"
+	FeatureEMASpread          = "ema_spread" // (short EMA - long EMA) / long EMA
+	FeatureBBPosition         = "bb_position"
+	FeatureVolumeZScore       = "volume_zscore"
+	FeatureOrderBookImbalance = "orderbook_imbalance"
+	FeatureSpreadBps          = "spread_bps"
+)
+
+var scorerFeatures = map[string]bool{
+	FeatureStrength:           true,
+	FeatureSide:               true,
+	FeatureRSI:                true,
+	FeatureEMASpread:          true,
+	FeatureBBPosition:         true,
+	FeatureVolumeZScore:       true,
+	FeatureOrderBookImbalance: true,
+	FeatureSpreadBps:          true,
+}
+
+// SignalFeatures extracts the model features of a signal. Signals without
+// components have no features.
+func SignalFeatures(signal *Signal) (map[string]float64, bool) {
+	if signal == nil || signal.Components == nil {
+		return nil, false
+	}
+	c := signal.Components
+
+	side := 1.0
+	if signal.Side == exchanges.OrderSideSell {
+		side = -1.0
+	}
+	emaSpread := 0.0
+	if c.LongEMA.IsPositive() {
+		emaSpread = c.ShortEMA.Sub(c.LongEMA).Div(c.LongEMA).InexactFloat64()
+	}
+
+	return map[string]float64{
+		FeatureStrength:           signal.Strength,
+		FeatureSide:               side,
+		FeatureRSI:                c.RSI.InexactFloat64(),
+		FeatureEMASpread:          emaSpread,
+		FeatureBBPosition:         c.BBPosition,
+		FeatureVolumeZScore:       c.VolumeZScore,
+		FeatureOrderBookImbalance: c.OrderBookImbalance,
+		FeatureSpreadBps:          c.SpreadBps,
+	}, true
+}
+
+// LogisticScorer scores signals with a logistic regression. Features are
+// standardized with Means and Scales when provided.
+type LogisticScorer struct {
+	Intercept    float64            `json:"intercept"`
+	Coefficients map[string]float64 `json:"coefficients"`
+	Means        map[string]float64 `json:"means,omitempty"`
+	Scales       map[string]float64 `json:"scales,omitempty"`
+}
+
+// Score implements SignalScorer
+func (s *LogisticScorer) Score(signal *Signal) (float64, error) {
+	features, ok := SignalFeatures(signal)
+	if !ok {
+		return 0, fmt.Errorf("signal has no components to score")
+	}
+
+	z := s.Intercept
+	for name, coefficient := range s.Coefficients {
+		value := features[name] - s.Means[name]
+		if scale := s.Scales[name]; scale != 0 {
+			value /= scale
+		}
+		z += coefficient * value
+	}
+	return 1 / (1 + math.Exp(-z)), nil
+}
+
+// validate rejects coefficients for features the bot does not produce, which
+// would otherwise silently score as zero
+func (s *LogisticScorer) validate() error {
+	if len(s.Coefficients) == 0 {
+		return fmt.Errorf("model has no coefficients")
+	}
+	var unknown []string
+	for name := range s.Coefficients {
+		if !scorerFeatures[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown features: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// LoadLogisticScorer loads logistic regression coefficients from a JSON file
+func LoadLogisticScorer(path string) (*LogisticScorer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read model: %w", err)
+	}
+	var scorer LogisticScorer
+	if err := json.Unmarshal(data, &scorer); err != nil {
+		return nil, fmt.Errorf("failed to parse model %s: %w", path, err)
+	}
+	if err := scorer.validate(); err != nil {
+		return nil, fmt.Errorf("invalid model %s: %w", path, err)
+	}
+	return &scorer, nil
+}
+
+type loadedScorer struct {
+	scorer SignalScorer
+	err    error
+}
+
+// scorerCache shares models between the signal generators of every symbol
+var scorerCache = struct {
+	sync.Mutex
+	models map[string]loadedScorer
+}{models: make(map[string]loadedScorer)}
+
+// LoadScorer loads the model at path, picking the format from the file
+// extension. Models are loaded once and shared.
+func LoadScorer(path string) (SignalScorer, error) {
+	scorerCache.Lock()
+	defer scorerCache.Unlock()
+
+	if loaded, ok := scorerCache.models[path]; ok {
+		return loaded.scorer, loaded.err
+	}
+
+	var loaded loadedScorer
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		scorer, err := LoadLogisticScorer(path)
+		if err != nil {
+			loaded.err = err
+		} else {
+			loaded.scorer = scorer
+		}
+	case ".onnx":
+		loaded.err = fmt.Errorf("ONNX models are not supported by this build, export %s as logistic regression coefficients (.json)", path)
+	default:
+		loaded.err = fmt.Errorf("unsupported model format: %s", path)
+	}
+	scorerCache.models[path] = loaded
+	return loaded.scorer, loaded.err
+}
+
+// LoadScorers loads every model referenced by cfg so configuration errors
+// surface at startup rather than on the first signal
+func LoadScorers(cfg *config.Config) error {
+	paths := make([]string, 0, len(cfg.ScorerModels)+1)
+	if cfg.ScorerModelPath != "" {
+		paths = append(paths, cfg.ScorerModelPath)
+	}
+	for _, path := range cfg.ScorerModels {
+		paths = append(paths, path)
+	}
+	for _, path := range paths {
+		if _, err := LoadScorer(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetScorer sets the scorer for symbol, overriding the configured model. An
+// empty symbol sets the scorer used for every symbol without its own.
+func (sg *SignalGenerator) SetScorer(symbol string, scorer SignalScorer) {
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	if sg.scorers == nil {
+		sg.scorers = make(map[string]SignalScorer)
+	}
+	sg.scorers[symbol] = scorer
+}
+
+// scorerFor returns the scorer applying to symbol, if any
+func (sg *SignalGenerator) scorerFor(symbol string) SignalScorer {
+	sg.mu.RLock()
+	scorer, ok := sg.scorers[symbol]
+	if !ok {
+		scorer, ok = sg.scorers[""]
+	}
+	sg.mu.RUnlock()
+	if ok {
+		return scorer
+	}
+
+	path := sg.config.ScorerModels[symbol]
+	if path == "" {
+		path = sg.config.ScorerModelPath
+	}
+	if path == "" {
+		return nil
+	}
+	scorer, err := LoadScorer(path)
+	if err != nil {
+		// Cache the failure on this generator so it is only reported once
+		logger.Component("strategy").Error("failed to load signal scoring model",
+			"symbol", symbol, "path", path, "error", err)
+		sg.SetScorer(symbol, nil)
+		return nil
+	}
+	return scorer
+}
+
+// scoreSignal blends the model probability into the strength of an entry
+// signal and vetoes entries the model scores below the veto threshold
+func (sg *SignalGenerator) scoreSignal(signal *Signal) *Signal {
+	scorer := sg.scorerFor(signal.Symbol)
+	if scorer == nil || signal.Type != SignalTypeEntry {
+		return signal
+	}
+
+	probability, err := scorer.Score(signal)
+	if err != nil {
+		logger.Component("strategy").Warn("signal scoring failed, keeping unscored signal",
+			"symbol", signal.Symbol, "error", err)
+		return signal
+	}
+
+	if probability < sg.config.ScorerVetoThreshold {
+		logger.Component("strategy").Info("entry vetoed by scoring model",
+			"symbol", signal.Symbol,
+			"side", signal.Side,
+			"probability", probability,
+			"threshold", sg.config.ScorerVetoThreshold)
+		return &Signal{
+			Type:   SignalTypeNone,
+			Symbol: signal.Symbol,
+			Reason: fmt.Sprintf("Vetoed by scoring model (p=%.2f)", probability),
+		}
+	}
+
+	weight := sg.config.ScorerWeight
+	strength := (1-weight)*signal.Strength + weight*probability
+	logger.Component("strategy").Debug("signal scored",
+		"symbol", signal.Symbol,
+		"probability", probability,
+		"strength", signal.Strength,
+		"scored_strength", strength)
+	signal.Strength = strength
+	if signal.Components != nil {
+		signal.Components.ModelProbability = probability
+	}
+	return signal
+}
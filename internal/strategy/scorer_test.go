@@ -0,0 +1,122 @@
+package strategy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+type stubScorer struct {
+	probability float64
+	err         error
+}
+
+func (s stubScorer) Score(*Signal) (float64, error) {
+	return s.probability, s.err
+}
+
+func scoredEntry() *Signal {
+	return &Signal{
+		Type:     SignalTypeEntry,
+		Side:     exchanges.OrderSideBuy,
+		Symbol:   "BTC-USD",
+		Strength: 0.6,
+		Components: &SignalComponents{
+			ShortEMA: decimal.NewFromInt(101),
+			LongEMA:  decimal.NewFromInt(100),
+			RSI:      decimal.NewFromInt(25),
+		},
+	}
+}
+
+func TestScoreSignal_BlendsAndVetoes(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ScorerVetoThreshold = 0.3
+	sg := NewSignalGenerator(cfg)
+
+	if signal := sg.scoreSignal(scoredEntry()); signal.Strength != 0.6 {
+		t.Errorf("signal should be unchanged without a scorer, got %f", signal.Strength)
+	}
+
+	sg.SetScorer("", stubScorer{probability: 0.8})
+	signal := sg.scoreSignal(scoredEntry())
+	if signal.Strength != 0.7 {
+		t.Errorf("expected strength blended to 0.7, got %f", signal.Strength)
+	}
+	if signal.Components.ModelProbability != 0.8 {
+		t.Errorf("components should record the model probability, got %f", signal.Components.ModelProbability)
+	}
+
+	// Per-symbol scorer overrides the default
+	sg.SetScorer("BTC-USD", stubScorer{probability: 0.1})
+	if signal := sg.scoreSignal(scoredEntry()); signal.Type != SignalTypeNone {
+		t.Errorf("low probability entry should be vetoed, got %s", signal.Type)
+	}
+
+	// Scoring failures keep the signal as generated
+	sg.SetScorer("BTC-USD", stubScorer{err: errors.New("boom")})
+	if signal := sg.scoreSignal(scoredEntry()); signal.Type != SignalTypeEntry || signal.Strength != 0.6 {
+		t.Errorf("failed scoring should keep the signal, got %s %f", signal.Type, signal.Strength)
+	}
+}
+
+func TestLogisticScorer(t *testing.T) {
+	scorer := &LogisticScorer{
+		Coefficients: map[string]float64{FeatureRSI: -1},
+		Means:        map[string]float64{FeatureRSI: 25},
+		Scales:       map[string]float64{FeatureRSI: 10},
+	}
+	probability, err := scorer.Score(scoredEntry())
+	if err != nil {
+		t.Fatalf("Score returned error: %v", err)
+	}
+	// RSI at its mean standardizes to zero
+	if probability != 0.5 {
+		t.Errorf("expected probability 0.5, got %f", probability)
+	}
+
+	if _, err := scorer.Score(&Signal{Type: SignalTypeEntry}); err == nil {
+		t.Error("signal without components should not be scored")
+	}
+}
+
+func TestLoadScorer(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "model.json")
+	if err := os.WriteFile(valid, []byte(`{"intercept": 0.1, "coefficients": {"rsi": -0.02, "side": 0.3}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unknown := filepath.Join(dir, "unknown.json")
+	if err := os.WriteFile(unknown, []byte(`{"coefficients": {"macd": 1}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	scorer, err := LoadScorer(valid)
+	if err != nil {
+		t.Fatalf("LoadScorer returned error: %v", err)
+	}
+	if again, _ := LoadScorer(valid); again != scorer {
+		t.Error("models should be loaded once and shared")
+	}
+	if _, err := LoadScorer(unknown); err == nil {
+		t.Error("unknown features should be rejected")
+	}
+	if _, err := LoadScorer(filepath.Join(dir, "model.onnx")); err == nil {
+		t.Error("ONNX models should be reported as unsupported")
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.ScorerModels = map[string]string{"BTC-USD": valid}
+	if err := LoadScorers(cfg); err != nil {
+		t.Errorf("LoadScorers returned error: %v", err)
+	}
+	cfg.ScorerModelPath = unknown
+	if err := LoadScorers(cfg); err == nil {
+		t.Error("LoadScorers should surface invalid models")
+	}
+}
@@ -2,6 +2,7 @@ package strategy
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/guyghost/constantine/internal/config"
 	"github.com/guyghost/constantine/internal/exchanges"
@@ -18,6 +19,16 @@ type Signal struct {
 	Strength  float64 // 0.0 to 1.0
 	Reason    string
 	Timestamp int64
+	// Components is the indicator snapshot the signal was generated from; set
+	// on entry signals so trades can be audited after the fact
+	Components *SignalComponents
+	// Strategy is the name of the strategy instance that generated the
+	// signal; empty for the main instance
+	Strategy string
+	// StopLossPercent and TakeProfitPercent, in percent, replace the
+	// execution defaults for the protective orders of an entry when set
+	StopLossPercent   float64
+	TakeProfitPercent float64
 }
 
 // SignalType represents the type of signal
@@ -36,6 +47,11 @@ type SignalGenerator struct {
 	config           *config.Config
 	weightCalculator *WeightCalculator
 	indicatorWeights IndicatorWeights
+	bookFeatures     OrderBookFeatures
+
+	mu      sync.RWMutex
+	scorers map[string]SignalScorer // Per-symbol overrides of the configured model
+	regime  RegimeState
 }
 
 // NewSignalGenerator creates a new signal generator
@@ -80,6 +96,32 @@ func (sg *SignalGenerator) GenerateSignal(
 	// Calculate dynamic indicator weights based on current market conditions
 	sg.indicatorWeights = sg.weightCalculator.CalculateDynamicWeights(prices, volumes, currentRSI)
 
+	// Classify the market regime and favour the indicators suited to it
+	regime := ClassifyRegimeFromPrices(prices, sg.config)
+	sg.setRegime(regime)
+	sg.indicatorWeights = sg.weightCalculator.ApplyRegimeWeight(sg.indicatorWeights, regime)
+	logger.Component("strategy").Debug("market regime",
+		"symbol", symbol,
+		"regime", regime.Regime,
+		"adx", regime.ADX,
+		"atr_percent", regime.ATRPercent,
+		"realized_vol", regime.RealizedVol,
+		"vol_ratio", regime.VolRatio)
+
+	// Derive order book features and let them weigh in when the book is usable
+	sg.bookFeatures = ComputeOrderBookFeatures(orderbook, sg.config.OrderBookLevels, sg.config.OrderBookDepthBps)
+	sg.indicatorWeights = sg.weightCalculator.ApplyOrderBookWeight(sg.indicatorWeights, sg.bookFeatures, sg.config.OrderBookMaxSpreadBps)
+	if sg.bookFeatures.Valid {
+		logger.Component("strategy").Debug("order book features",
+			"symbol", symbol,
+			"imbalance", sg.bookFeatures.Imbalance,
+			"weighted_mid", sg.bookFeatures.WeightedMid.StringFixed(4),
+			"spread_bps", sg.bookFeatures.SpreadBps,
+			"bid_depth", sg.bookFeatures.BidDepth.String(),
+			"ask_depth", sg.bookFeatures.AskDepth.String(),
+			"orderbook_weight", sg.indicatorWeights.OrderBook)
+	}
+
 	// Log indicator calculations
 	logger.Component("strategy").Debug("signal calculation",
 		"symbol", symbol,
@@ -96,6 +138,9 @@ func (sg *SignalGenerator) GenerateSignal(
 
 	// Check for buy signal
 	if sg.isBuySignal(currentShortEMA, currentLongEMA, currentRSI, orderbook) {
+		if sg.blocksCounterTrend(regime, exchanges.OrderSideBuy) {
+			return &Signal{Type: SignalTypeNone, Symbol: symbol, Reason: "Counter-trend entry blocked in trending regime"}
+		}
 		strength := sg.calculateSignalStrength(currentShortEMA, currentLongEMA, currentRSI, true)
 		logger.Component("strategy").Debug("buy signal generated",
 			"symbol", symbol,
@@ -103,18 +148,22 @@ func (sg *SignalGenerator) GenerateSignal(
 			"strength", strength,
 			"ema_crossover", currentShortEMA.GreaterThan(currentLongEMA),
 			"rsi_oversold", currentRSI.LessThan(decimal.NewFromFloat(sg.config.RSIOversold)))
-		return &Signal{
-			Type:     SignalTypeEntry,
-			Side:     exchanges.OrderSideBuy,
-			Symbol:   symbol,
-			Price:    currentPrice,
-			Strength: strength,
-			Reason:   "EMA crossover + RSI oversold",
-		}
+		return sg.scoreSignal(&Signal{
+			Type:       SignalTypeEntry,
+			Side:       exchanges.OrderSideBuy,
+			Symbol:     symbol,
+			Price:      currentPrice,
+			Strength:   strength,
+			Reason:     "EMA crossover + RSI oversold",
+			Components: sg.buildComponents(prices, volumes, currentShortEMA, currentLongEMA, currentRSI),
+		})
 	}
 
 	// Check for sell signal
 	if sg.isSellSignal(currentShortEMA, currentLongEMA, currentRSI, orderbook) {
+		if sg.blocksCounterTrend(regime, exchanges.OrderSideSell) {
+			return &Signal{Type: SignalTypeNone, Symbol: symbol, Reason: "Counter-trend entry blocked in trending regime"}
+		}
 		strength := sg.calculateSignalStrength(currentShortEMA, currentLongEMA, currentRSI, false)
 		logger.Component("strategy").Debug("sell signal generated",
 			"symbol", symbol,
@@ -122,14 +171,15 @@ func (sg *SignalGenerator) GenerateSignal(
 			"strength", strength,
 			"ema_crossover", currentShortEMA.LessThan(currentLongEMA),
 			"rsi_overbought", currentRSI.GreaterThan(decimal.NewFromFloat(sg.config.RSIOverbought)))
-		return &Signal{
-			Type:     SignalTypeEntry,
-			Side:     exchanges.OrderSideSell,
-			Symbol:   symbol,
-			Price:    currentPrice,
-			Strength: strength,
-			Reason:   "EMA crossover + RSI overbought",
-		}
+		return sg.scoreSignal(&Signal{
+			Type:       SignalTypeEntry,
+			Side:       exchanges.OrderSideSell,
+			Symbol:     symbol,
+			Price:      currentPrice,
+			Strength:   strength,
+			Reason:     "EMA crossover + RSI overbought",
+			Components: sg.buildComponents(prices, volumes, currentShortEMA, currentLongEMA, currentRSI),
+		})
 	}
 
 	logger.Component("strategy").Debug("no signal generated",
@@ -322,6 +372,10 @@ func (sg *SignalGenerator) calculateSignalStrength(
 
 	strength += rsiStrength
 
+	// Order book strength - weighted by dynamic order book weight
+	bookStrength := sg.bookFeatures.DirectionalScore(isBuy) * sg.indicatorWeights.OrderBook
+	strength += bookStrength
+
 	// Ensure minimum strength when signal passes validation
 	// This handles cases where one indicator is strong but weighted low
 	if strength < 0.3 {
@@ -339,9 +393,11 @@ func (sg *SignalGenerator) calculateSignalStrength(
 	logger.Component("strategy").Debug("signal strength calculation",
 		"ema_strength", emaStrength,
 		"rsi_strength", rsiStrength,
+		"orderbook_strength", bookStrength,
 		"total_strength", strength,
 		"ema_weight", sg.indicatorWeights.EMA,
-		"rsi_weight", sg.indicatorWeights.RSI)
+		"rsi_weight", sg.indicatorWeights.RSI,
+		"orderbook_weight", sg.indicatorWeights.OrderBook)
 
 	return strength
 }
@@ -352,7 +408,8 @@ func (sg *SignalGenerator) ShouldExit(
 	currentPrice decimal.Decimal,
 	rsi decimal.Decimal,
 ) bool {
-	if position == nil {
+	if position == nil || !position.EntryPrice.IsPositive() {
+		// Venues that do not report the entry price leave nothing to compare
 		return false
 	}
 
@@ -1,12 +1,14 @@
 package strategy
 
 import (
+	"fmt"
 	"math"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/ringbuf"
 	"github.com/shopspring/decimal"
 )
 
@@ -21,6 +23,9 @@ type RankedSymbol struct {
 	Potential   decimal.Decimal // Gain potential
 	Risk        decimal.Decimal // Risk assessment
 	SharpeRatio decimal.Decimal // Risk-adjusted return
+	// Correlation is the highest return correlation with the symbols selected
+	// before it, set by SelectBestSymbols
+	Correlation float64
 }
 
 type SelectionEvent struct {
@@ -31,38 +36,34 @@ type SelectionEvent struct {
 }
 
 type SymbolSelector struct {
-	config         *config.Config
-	history        []SelectionEvent
-	maxHistorySize int
-	mu             sync.RWMutex
+	config  *config.Config
+	history *ringbuf.Buffer[SelectionEvent]
+	mu      sync.RWMutex
 }
 
 func NewSymbolSelector(cfg *config.Config) *SymbolSelector {
 	return &SymbolSelector{
-		config:         cfg,
-		history:        make([]SelectionEvent, 0),
-		maxHistorySize: 100,
+		config:  cfg,
+		history: ringbuf.New[SelectionEvent](100),
 	}
 }
 
 func (ss *SymbolSelector) CalculateGainPotential(symbol string, prices []decimal.Decimal, volumes []decimal.Decimal) decimal.Decimal {
-	if len(prices) < 21 {
+	scoring := ss.config.Selection
+	if len(prices) < scoring.LongEMA {
 		return decimal.Zero
 	}
 
-	// Calculate EMA 9 and EMA 21
-	ema9 := ss.calculateEMA(prices, 9)
-	ema21 := ss.calculateEMA(prices, 21)
+	// Calculate the short and long EMAs
+	shortEMA := ss.calculateEMA(prices, scoring.ShortEMA)
+	longEMA := ss.calculateEMA(prices, scoring.LongEMA)
 
-	if ema9 == nil || ema21 == nil || len(ema9) == 0 || len(ema21) == 0 {
+	if len(shortEMA) == 0 || len(longEMA) == 0 {
 		return decimal.Zero
 	}
 
-	lastEMA9 := ema9[len(ema9)-1]
-	lastEMA21 := ema21[len(ema21)-1]
-
 	var score decimal.Decimal
-	if lastEMA9.GreaterThan(lastEMA21) {
+	if shortEMA[len(shortEMA)-1].GreaterThan(longEMA[len(longEMA)-1]) {
 		// Uptrend
 		// Find recent ATH
 		ath := decimal.Zero
@@ -95,7 +96,8 @@ func (ss *SymbolSelector) CalculateGainPotential(symbol string, prices []decimal
 }
 
 func (ss *SymbolSelector) CalculateRiskAssessment(symbol string, prices []decimal.Decimal, volumes []decimal.Decimal) decimal.Decimal {
-	if len(prices) < 20 {
+	scoring := ss.config.Selection
+	if len(prices) < scoring.MinHistory {
 		return decimal.NewFromFloat(1.0) // High risk
 	}
 
@@ -113,7 +115,7 @@ func (ss *SymbolSelector) CalculateRiskAssessment(symbol string, prices []decima
 	dd := ss.calculateMaxDrawdown(prices)
 
 	// Volume score
-	volScore := decimal.Zero
+	volScore := 0.0
 	if len(volumes) > 0 {
 		sum := decimal.Zero
 		for _, v := range volumes {
@@ -121,17 +123,14 @@ func (ss *SymbolSelector) CalculateRiskAssessment(symbol string, prices []decima
 		}
 		avgVol := sum.Div(decimal.NewFromInt(int64(len(volumes))))
 		avgVolFloat, _ := avgVol.Float64()
-		if avgVolFloat > 1000 {
-			volScore = decimal.NewFromFloat(1.0)
-		} else if avgVolFloat < 100 {
-			volScore = decimal.NewFromFloat(0.0)
-		} else {
-			volScore = decimal.NewFromFloat((avgVolFloat - 100) / 900)
+		if avgVolFloat > scoring.VolumeHigh {
+			volScore = 1.0
+		} else if avgVolFloat > scoring.VolumeLow {
+			volScore = (avgVolFloat - scoring.VolumeLow) / (scoring.VolumeHigh - scoring.VolumeLow)
 		}
 	}
 
-	volRiskFloat, _ := volScore.Float64()
-	riskVal := vol*0.4 + dd*0.4 + (1-volRiskFloat)*0.2
+	riskVal := vol*scoring.RiskVolatilityWeight + dd*scoring.RiskDrawdownWeight + (1-volScore)*scoring.RiskVolumeWeight
 
 	// Ensure risk is between 0 and 1
 	if riskVal < 0 {
@@ -165,13 +164,11 @@ func (ss *SymbolSelector) CalculateSharpeRatio(symbol string, prices []decimal.D
 
 	stdDev := ss.calculateStdDevFloat(returns)
 
-	riskFreeRate := 0.01 // 1% annual
-
 	if stdDev == 0 {
 		return decimal.Zero
 	}
 
-	sharpe := (meanReturn - riskFreeRate) / stdDev
+	sharpe := (meanReturn - ss.config.Selection.RiskFreeRate) / stdDev
 	return decimal.NewFromFloat(sharpe)
 }
 
@@ -180,6 +177,8 @@ func (ss *SymbolSelector) CalculateOpportunityScore(symbol string, prices []deci
 	risk := ss.CalculateRiskAssessment(symbol, prices, volumes)
 	sharpe := ss.CalculateSharpeRatio(symbol, prices, volumes)
 
+	scoring := ss.config.Selection
+
 	// Volume confirmation
 	volScore := 0.0
 	if len(volumes) > 0 {
@@ -189,7 +188,7 @@ func (ss *SymbolSelector) CalculateOpportunityScore(symbol string, prices []deci
 		}
 		avgVol := sum.Div(decimal.NewFromInt(int64(len(volumes))))
 		avgVolFloat, _ := avgVol.Float64()
-		volScore = avgVolFloat / 1000.0
+		volScore = avgVolFloat / scoring.VolumeHigh
 		if volScore > 1 {
 			volScore = 1
 		}
@@ -199,7 +198,7 @@ func (ss *SymbolSelector) CalculateOpportunityScore(symbol string, prices []deci
 	sharpeFloat, _ := sharpe.Float64()
 	riskFloat, _ := risk.Float64()
 
-	score := gpFloat*0.4 + sharpeFloat*0.35 + volScore*0.15 - riskFloat*0.10
+	score := gpFloat*scoring.GainWeight + sharpeFloat*scoring.SharpeWeight + volScore*scoring.VolumeWeight - riskFloat*scoring.RiskWeight
 
 	if score < 0 {
 		score = 0
@@ -236,20 +235,58 @@ func (ss *SymbolSelector) RankSymbols(symbols []string, symbolData map[string]Sy
 	return ranked
 }
 
+// SelectBestSymbols selects up to maxCount symbols scoring above the dynamic
+// threshold. Symbols are picked one at a time by their score less the
+// correlation penalty times their highest return correlation with the
+// symbols already picked, so the selection is not several symbols moving as
+// one: a symbol scoring up to the penalty below the threshold can take the
+// place of one correlated with the selection.
 func (ss *SymbolSelector) SelectBestSymbols(symbols []string, symbolData map[string]SymbolData, maxCount int) []RankedSymbol {
 	ranked := ss.RankSymbols(symbols, symbolData)
 	threshold := ss.CalculateDynamicThreshold(symbols, symbolData, maxCount)
-	selected := make([]RankedSymbol, 0)
+	candidates := make([]RankedSymbol, 0, len(ranked))
 	for _, r := range ranked {
-		if r.Score >= threshold && len(selected) < maxCount {
-			selected = append(selected, r)
-			ss.addToHistory(SelectionEvent{
-				Timestamp: time.Now().Unix(),
-				Symbol:    r.Symbol,
-				Score:     r.Score,
-				Reason:    "Selected based on opportunity score",
-			})
+		if r.Score >= threshold-ss.config.SelectionCorrelationPenalty {
+			candidates = append(candidates, r)
+		}
+	}
+
+	returns := make(map[string][]float64, len(candidates))
+	if ss.config.SelectionCorrelationPenalty > 0 {
+		for _, c := range candidates {
+			returns[c.Symbol] = logReturns(symbolData[c.Symbol].Prices, ss.config.SelectionCorrelationWindow)
+		}
+	}
+
+	selected := make([]RankedSymbol, 0)
+	// Symbols below the threshold only ever replace correlated ones, never
+	// fill the selection on their own
+	for len(selected) < maxCount && len(candidates) > 0 && candidates[0].Score >= threshold {
+		best, bestScore := 0, math.Inf(-1)
+		for i := range candidates {
+			candidates[i].Correlation = 0
+			for _, s := range selected {
+				candidates[i].Correlation = math.Max(candidates[i].Correlation, returnCorrelation(returns[candidates[i].Symbol], returns[s.Symbol]))
+			}
+			// Candidates are in score order, so ties keep the better ranked
+			if adjusted := candidates[i].Score - ss.config.SelectionCorrelationPenalty*candidates[i].Correlation; adjusted > bestScore {
+				best, bestScore = i, adjusted
+			}
 		}
+
+		r := candidates[best]
+		candidates = append(candidates[:best], candidates[best+1:]...)
+		selected = append(selected, r)
+		reason := "Selected based on opportunity score"
+		if r.Correlation > 0 {
+			reason = fmt.Sprintf("Selected based on opportunity score, %.2f correlated with the selection", r.Correlation)
+		}
+		ss.addToHistory(SelectionEvent{
+			Timestamp: time.Now().Unix(),
+			Symbol:    r.Symbol,
+			Score:     r.Score,
+			Reason:    reason,
+		})
 	}
 	return selected
 }
@@ -263,15 +300,16 @@ func (ss *SymbolSelector) CalculateDynamicThreshold(symbols []string, symbolData
 		return 0.0
 	}
 
-	// If all > 0.6, threshold 0.6
+	// If all are above the configured threshold, use it
+	threshold := ss.config.Selection.Threshold
 	minScore := 1.0
 	for _, r := range ranked {
 		if r.Score < minScore {
 			minScore = r.Score
 		}
 	}
-	if minScore > 0.6 {
-		return 0.6
+	if minScore > threshold {
+		return threshold
 	}
 
 	// Else, find threshold to get at least minSymbols
@@ -302,9 +340,7 @@ func (ss *SymbolSelector) FilterByPriceRange(symbols []string, symbolData map[st
 func (ss *SymbolSelector) GetSelectionHistory() []SelectionEvent {
 	ss.mu.RLock()
 	defer ss.mu.RUnlock()
-	history := make([]SelectionEvent, len(ss.history))
-	copy(history, ss.history)
-	return history
+	return ss.history.Values()
 }
 
 func (ss *SymbolSelector) calculateEMA(prices []decimal.Decimal, period int) []decimal.Decimal {
@@ -365,11 +401,61 @@ func (ss *SymbolSelector) calculateMaxDrawdown(prices []decimal.Decimal) float64
 	return maxDD
 }
 
+// logReturns returns the log returns of the last window+1 prices, all of
+// them when window is not positive
+func logReturns(prices []decimal.Decimal, window int) []float64 {
+	if window > 0 && len(prices) > window+1 {
+		prices = prices[len(prices)-window-1:]
+	}
+	if len(prices) < 2 {
+		return nil
+	}
+	returns := make([]float64, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		prev, curr := prices[i-1].InexactFloat64(), prices[i].InexactFloat64()
+		if prev > 0 && curr > 0 {
+			returns[i-1] = math.Log(curr / prev)
+		}
+	}
+	return returns
+}
+
+// minCorrelationReturns is the number of returns two symbols must share for
+// their correlation to be measured
+const minCorrelationReturns = 10
+
+// returnCorrelation returns the Pearson correlation of the latest returns two
+// symbols share, 0 when there are too few or one of them does not move
+func returnCorrelation(a, b []float64) float64 {
+	n := min(len(a), len(b))
+	if n < minCorrelationReturns {
+		return 0
+	}
+	a, b = a[len(a)-n:], b[len(b)-n:]
+
+	meanA, meanB := 0.0, 0.0
+	for i := range n {
+		meanA += a[i]
+		meanB += b[i]
+	}
+	meanA /= float64(n)
+	meanB /= float64(n)
+
+	cov, varA, varB := 0.0, 0.0, 0.0
+	for i := range n {
+		da, db := a[i]-meanA, b[i]-meanB
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+	if varA == 0 || varB == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varA*varB)
+}
+
 func (ss *SymbolSelector) addToHistory(event SelectionEvent) {
 	ss.mu.Lock()
 	defer ss.mu.Unlock()
-	ss.history = append(ss.history, event)
-	if len(ss.history) > ss.maxHistorySize {
-		ss.history = ss.history[1:]
-	}
+	ss.history.Push(event)
 }
@@ -116,6 +116,36 @@ func TestCalculateOpportunityScore(t *testing.T) {
 	}
 }
 
+// TestCalculateOpportunityScore_Weights tests that the score follows the configured weights
+func TestCalculateOpportunityScore_Weights(t *testing.T) {
+	prices := make([]decimal.Decimal, 30)
+	volumes := make([]decimal.Decimal, 30)
+	for i := range prices {
+		prices[i] = decimal.NewFromFloat(100.0 + float64(i)*0.5)
+		volumes[i] = decimal.NewFromFloat(500)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Selection.GainWeight, cfg.Selection.SharpeWeight, cfg.Selection.VolumeWeight, cfg.Selection.RiskWeight = 1, 0, 0, 0
+	selector := NewSymbolSelector(cfg)
+	gain, _ := selector.CalculateGainPotential("BTC-USD", prices, volumes).Float64()
+	if score := selector.CalculateOpportunityScore("BTC-USD", prices, volumes); math.Abs(score-gain) > 1e-9 {
+		t.Errorf("Expected the gain potential alone, got %f for %f", score, gain)
+	}
+
+	// Average volume of 500 is half way to a VolumeHigh of 1000
+	cfg.Selection.GainWeight, cfg.Selection.VolumeWeight = 0, 1
+	if score := selector.CalculateOpportunityScore("BTC-USD", prices, volumes); math.Abs(score-0.5) > 1e-9 {
+		t.Errorf("Expected the volume score alone, got %f", score)
+	}
+
+	// Too little history for the configured long EMA leaves no gain potential
+	cfg.Selection.LongEMA = 50
+	if gain := selector.CalculateGainPotential("BTC-USD", prices, volumes); !gain.IsZero() {
+		t.Errorf("Expected no gain potential without enough history, got %s", gain)
+	}
+}
+
 // TestRankSymbols tests symbol ranking by gain potential
 func TestRankSymbols(t *testing.T) {
 	cfg := config.DefaultConfig()
@@ -427,3 +457,64 @@ func TestConsistencyAcrossRuns(t *testing.T) {
 		}
 	}
 }
+
+// walk returns n prices following the given per-step returns from 100,
+// repeated as needed
+func walk(n int, steps ...float64) []decimal.Decimal {
+	prices := make([]decimal.Decimal, n)
+	price := 100.0
+	for i := range prices {
+		price *= 1 + steps[i%len(steps)]
+		prices[i] = decimal.NewFromFloat(price)
+	}
+	return prices
+}
+
+// TestSelectBestSymbols_Diversity tests that symbols moving with the
+// selection give way to ones that do not
+func TestSelectBestSymbols_Diversity(t *testing.T) {
+	volumes := make([]decimal.Decimal, 60)
+	for i := range volumes {
+		volumes[i] = decimal.NewFromFloat(1000.0)
+	}
+	leader := walk(60, 0.004, -0.001, 0.003, 0.002, -0.002, 0.001, 0.005)
+	symbolData := map[string]SymbolData{
+		"BTC-USD":  {Prices: leader, Volumes: volumes},
+		"WBTC-USD": {Prices: walk(60, 0.004, -0.001, 0.003, 0.002, -0.002, 0.001, 0.0049), Volumes: volumes},
+		"SOL-USD":  {Prices: walk(60, -0.001, 0.003, 0.002, 0.004, 0.001, -0.002, 0.003), Volumes: volumes},
+	}
+	symbols := []string{"BTC-USD", "WBTC-USD", "SOL-USD"}
+
+	cfg := config.DefaultConfig()
+	cfg.SelectionCorrelationPenalty = 0
+	byScore := NewSymbolSelector(cfg).SelectBestSymbols(symbols, symbolData, 2)
+	if len(byScore) != 2 || byScore[0].Symbol != "BTC-USD" || byScore[1].Symbol != "WBTC-USD" {
+		t.Fatalf("Expected the two best scores without penalty, got %+v", byScore)
+	}
+
+	cfg = config.DefaultConfig()
+	diverse := NewSymbolSelector(cfg).SelectBestSymbols(symbols, symbolData, 2)
+	if len(diverse) != 2 || diverse[0].Symbol != "BTC-USD" || diverse[1].Symbol != "SOL-USD" {
+		t.Fatalf("Expected the correlated symbol to give way, got %+v", diverse)
+	}
+	if diverse[1].Correlation >= 0.9 {
+		t.Errorf("Expected SOL-USD to move apart from BTC-USD, correlation %.2f", diverse[1].Correlation)
+	}
+}
+
+func TestReturnCorrelation(t *testing.T) {
+	a := logReturns(walk(30, 0.01, -0.02, 0.015), 0)
+	if got := returnCorrelation(a, a); math.Abs(got-1) > 1e-9 {
+		t.Errorf("Expected a series to be fully correlated with itself, got %f", got)
+	}
+	inverse := make([]float64, len(a))
+	for i, r := range a {
+		inverse[i] = -r
+	}
+	if got := returnCorrelation(a, inverse); math.Abs(got+1) > 1e-9 {
+		t.Errorf("Expected -1 for opposite returns, got %f", got)
+	}
+	if got := returnCorrelation(a[:5], a[:5]); got != 0 {
+		t.Errorf("Expected no correlation on too few returns, got %f", got)
+	}
+}
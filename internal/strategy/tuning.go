@@ -0,0 +1,276 @@
+package strategy
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/ringbuf"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// tuningRatioStep is the factor the reward ratio moves by when recent
+	// outcomes call for it
+	tuningRatioStep = 1.25
+	// tuningWinMargin is how far above the break-even win rate outcomes must
+	// be before winners are given more room
+	tuningWinMargin = 0.15
+	// tuningMinChange is the relative move of the stop loss or take profit
+	// below which parameters are left as they are
+	tuningMinChange = 0.05
+)
+
+// Parameters are the strategy parameters tuned online for each symbol
+type Parameters struct {
+	ShortEMAPeriod    int
+	LongEMAPeriod     int
+	StopLossPercent   float64
+	TakeProfitPercent float64
+}
+
+// ParameterRange bounds a tuned parameter
+type ParameterRange struct {
+	Min float64
+	Max float64
+}
+
+func (r ParameterRange) clamp(value float64) float64 {
+	return math.Min(math.Max(value, r.Min), r.Max)
+}
+
+// TuningConfig controls how strategy parameters are re-fit from recent
+// volatility and trade outcomes
+type TuningConfig struct {
+	Interval time.Duration // Time between two fits
+	Cooldown time.Duration // Minimum time between two changes of a symbol's parameters
+	// VolatilityWindow is the number of candles volatility and trend
+	// efficiency are measured over
+	VolatilityWindow int
+	// The stop loss is StopLossVolMultiple times the volatility expected over
+	// HoldingCandles
+	HoldingCandles      int
+	StopLossVolMultiple float64
+	MinTrades           int // Recent trades needed before outcomes adjust the take profit
+	MaxTrades           int // Recent trades outcomes are measured over
+	StopLoss            ParameterRange
+	TakeProfit          ParameterRange
+	ShortEMA            ParameterRange
+	LongEMA             ParameterRange
+}
+
+// DefaultTuningConfig returns the default tuning configuration
+func DefaultTuningConfig() TuningConfig {
+	return TuningConfig{
+		Interval:            15 * time.Minute,
+		Cooldown:            time.Hour,
+		VolatilityWindow:    60,
+		HoldingCandles:      15,
+		StopLossVolMultiple: 1.5,
+		MinTrades:           5,
+		MaxTrades:           20,
+		StopLoss:            ParameterRange{Min: 0.3, Max: 3},
+		TakeProfit:          ParameterRange{Min: 0.5, Max: 6},
+		ShortEMA:            ParameterRange{Min: 5, Max: 15},
+		LongEMA:             ParameterRange{Min: 13, Max: 50},
+	}
+}
+
+// Tuner periodically re-fits the stop loss, take profit and EMA periods of
+// each symbol's strategy: the stop loss follows realized volatility, the take
+// profit keeps the configured reward ratio adjusted by the recent win rate,
+// and the EMAs shorten in trending markets and lengthen in choppy ones.
+type Tuner struct {
+	cfg        TuningConfig
+	strategies func() map[string]*ScalpingStrategy
+
+	mu        sync.Mutex
+	base      map[string]Parameters // Parameters configured for each symbol
+	outcomes  map[string]*ringbuf.Buffer[decimal.Decimal]
+	changedAt map[string]time.Time
+	now       func() time.Time
+}
+
+// NewTuner creates a tuner for the strategies returned by strategies
+func NewTuner(cfg TuningConfig, strategies func() map[string]*ScalpingStrategy) *Tuner {
+	return &Tuner{
+		cfg:        cfg,
+		strategies: strategies,
+		base:       make(map[string]Parameters),
+		outcomes:   make(map[string]*ringbuf.Buffer[decimal.Decimal]),
+		changedAt:  make(map[string]time.Time),
+		now:        time.Now,
+	}
+}
+
+// RecordTrade records the realized PnL of a closed trade on symbol
+func (t *Tuner) RecordTrade(symbol string, pnl decimal.Decimal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	outcomes, ok := t.outcomes[symbol]
+	if !ok {
+		outcomes = ringbuf.New[decimal.Decimal](max(t.cfg.MaxTrades, 1))
+		t.outcomes[symbol] = outcomes
+	}
+	outcomes.Push(pnl)
+}
+
+// Run re-fits the parameters every interval until ctx is canceled
+func (t *Tuner) Run(ctx context.Context) {
+	ticker := time.NewTicker(t.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.Tune()
+		}
+	}
+}
+
+// Tune re-fits the parameters of every strategy out of its cooldown
+func (t *Tuner) Tune() {
+	for symbol, strategy := range t.strategies() {
+		current := strategy.Parameters()
+
+		t.mu.Lock()
+		base, known := t.base[symbol]
+		if !known {
+			base = current
+			t.base[symbol] = base
+		}
+		changedAt := t.changedAt[symbol]
+		var outcomes []decimal.Decimal
+		if buffer, ok := t.outcomes[symbol]; ok {
+			outcomes = buffer.Values()
+		}
+		t.mu.Unlock()
+
+		if !changedAt.IsZero() && t.now().Sub(changedAt) < t.cfg.Cooldown {
+			continue
+		}
+		next := fitParameters(strategy.GetCurrentPrices(), outcomes, base, current, t.cfg)
+		if !parametersChanged(current, next) {
+			continue
+		}
+
+		strategy.ApplyParameters(next)
+		t.mu.Lock()
+		t.changedAt[symbol] = t.now()
+		t.mu.Unlock()
+		logger.Component("strategy").Info("strategy parameters tuned",
+			"symbol", symbol,
+			"short_ema", next.ShortEMAPeriod,
+			"long_ema", next.LongEMAPeriod,
+			"stop_loss_percent", math.Round(next.StopLossPercent*100)/100,
+			"take_profit_percent", math.Round(next.TakeProfitPercent*100)/100,
+			"trades", len(outcomes))
+	}
+}
+
+// fitParameters fits the parameters of a symbol to its price history and the
+// PnL of its recent trades. Parameters that cannot be measured yet are kept.
+func fitParameters(prices []decimal.Decimal, outcomes []decimal.Decimal, base, current Parameters, cfg TuningConfig) Parameters {
+	next := current
+
+	vol := RealizedVolatility(prices, cfg.VolatilityWindow)
+	if vol > 0 {
+		holding := vol * math.Sqrt(float64(max(cfg.HoldingCandles, 1))) * 100
+		next.StopLossPercent = cfg.StopLoss.clamp(holding * cfg.StopLossVolMultiple)
+	}
+
+	// Keep the reward ratio in use, taking profits sooner while the win rate
+	// is below break-even and letting winners run while it is comfortably
+	// above
+	ratio := base.TakeProfitPercent / base.StopLossPercent
+	if current.StopLossPercent > 0 {
+		ratio = current.TakeProfitPercent / current.StopLossPercent
+	}
+	if len(outcomes) >= cfg.MinTrades && len(outcomes) > 0 {
+		wins := 0
+		for _, pnl := range outcomes {
+			if pnl.IsPositive() {
+				wins++
+			}
+		}
+		winRate := float64(wins) / float64(len(outcomes))
+		breakEven := 1 / (1 + ratio)
+		switch {
+		case winRate < breakEven:
+			ratio /= tuningRatioStep
+		case winRate > breakEven+tuningWinMargin:
+			ratio *= tuningRatioStep
+		}
+	}
+	next.TakeProfitPercent = cfg.TakeProfit.clamp(next.StopLossPercent * ratio)
+
+	if efficiency, ok := efficiencyRatio(prices, cfg.VolatilityWindow); ok && base.ShortEMAPeriod > 0 {
+		short := cfg.ShortEMA.Max - efficiency*(cfg.ShortEMA.Max-cfg.ShortEMA.Min)
+		long := short * float64(base.LongEMAPeriod) / float64(base.ShortEMAPeriod)
+		next.ShortEMAPeriod = int(math.Round(short))
+		next.LongEMAPeriod = max(int(math.Round(cfg.LongEMA.clamp(long))), next.ShortEMAPeriod+1)
+	}
+	return next
+}
+
+// efficiencyRatio measures how directly prices moved over the last period
+// candles: 1 for a straight line, close to 0 for noise
+func efficiencyRatio(prices []decimal.Decimal, period int) (float64, bool) {
+	if period < 1 || len(prices) < period+1 {
+		return 0, false
+	}
+	window := prices[len(prices)-period-1:]
+	path := 0.0
+	for i := 1; i < len(window); i++ {
+		path += math.Abs(window[i].Sub(window[i-1]).InexactFloat64())
+	}
+	if path == 0 {
+		return 0, false
+	}
+	return math.Abs(window[len(window)-1].Sub(window[0]).InexactFloat64()) / path, true
+}
+
+// parametersChanged reports whether next differs enough from current to be
+// applied
+func parametersChanged(current, next Parameters) bool {
+	if current.ShortEMAPeriod != next.ShortEMAPeriod || current.LongEMAPeriod != next.LongEMAPeriod {
+		return true
+	}
+	moved := func(from, to float64) bool {
+		return from == 0 || math.Abs(to-from)/from > tuningMinChange
+	}
+	return moved(current.StopLossPercent, next.StopLossPercent) || moved(current.TakeProfitPercent, next.TakeProfitPercent)
+}
+
+// Parameters returns the tuned parameters in use
+func (s *ScalpingStrategy) Parameters() Parameters {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Parameters{
+		ShortEMAPeriod:    s.config.ShortEMAPeriod,
+		LongEMAPeriod:     s.config.LongEMAPeriod,
+		StopLossPercent:   s.config.StopLossPercent,
+		TakeProfitPercent: s.config.TakeProfitPercent,
+	}
+}
+
+// ApplyParameters replaces the tuned parameters between two updates. Entry
+// signals then carry the stop loss and take profit so the protective orders
+// follow them.
+func (s *ScalpingStrategy) ApplyParameters(params Parameters) {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config.ShortEMAPeriod = params.ShortEMAPeriod
+	s.config.LongEMAPeriod = params.LongEMAPeriod
+	s.config.StopLossPercent = params.StopLossPercent
+	s.config.TakeProfitPercent = params.TakeProfitPercent
+	s.tuned = true
+}
@@ -0,0 +1,129 @@
+package strategy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/guyghost/constantine/internal/config"
+	"github.com/guyghost/constantine/internal/testutils"
+	"github.com/shopspring/decimal"
+)
+
+// zigzag returns n prices alternating around 100 by step percent
+func zigzag(n int, step float64) []decimal.Decimal {
+	prices := make([]decimal.Decimal, n)
+	for i := range prices {
+		price := 100.0
+		if i%2 == 1 {
+			price *= 1 + step/100
+		}
+		prices[i] = decimal.NewFromFloat(price)
+	}
+	return prices
+}
+
+// trend returns n prices rising steadily from 100
+func trend(n int) []decimal.Decimal {
+	prices := make([]decimal.Decimal, n)
+	for i := range prices {
+		prices[i] = decimal.NewFromFloat(100 * math.Pow(1.001, float64(i)))
+	}
+	return prices
+}
+
+func wins(n, of int) []decimal.Decimal {
+	outcomes := make([]decimal.Decimal, of)
+	for i := range outcomes {
+		if i < n {
+			outcomes[i] = decimal.NewFromInt(10)
+		} else {
+			outcomes[i] = decimal.NewFromInt(-10)
+		}
+	}
+	return outcomes
+}
+
+func TestFitParameters(t *testing.T) {
+	cfg := DefaultTuningConfig()
+	base := Parameters{ShortEMAPeriod: 9, LongEMAPeriod: 21, StopLossPercent: 1, TakeProfitPercent: 2}
+
+	calm := fitParameters(zigzag(100, 0.05), nil, base, base, cfg)
+	wild := fitParameters(zigzag(100, 0.5), nil, base, base, cfg)
+	if calm.StopLossPercent >= wild.StopLossPercent {
+		t.Errorf("Expected a wider stop in volatile markets, got %.2f calm and %.2f volatile", calm.StopLossPercent, wild.StopLossPercent)
+	}
+	if wild.StopLossPercent > cfg.StopLoss.Max || calm.StopLossPercent < cfg.StopLoss.Min {
+		t.Errorf("Expected stops within %+v, got %.2f and %.2f", cfg.StopLoss, calm.StopLossPercent, wild.StopLossPercent)
+	}
+	if ratio := calm.TakeProfitPercent / calm.StopLossPercent; math.Abs(ratio-2) > 1e-9 {
+		t.Errorf("Expected the configured reward ratio without trades, got %.2f", ratio)
+	}
+
+	// Choppy markets lengthen the EMAs, trending ones shorten them
+	if calm.ShortEMAPeriod != int(cfg.ShortEMA.Max) {
+		t.Errorf("Expected the longest short EMA in a choppy market, got %d", calm.ShortEMAPeriod)
+	}
+	trending := fitParameters(trend(100), nil, base, base, cfg)
+	if trending.ShortEMAPeriod != int(cfg.ShortEMA.Min) {
+		t.Errorf("Expected the shortest short EMA in a trending market, got %d", trending.ShortEMAPeriod)
+	}
+	for _, params := range []Parameters{calm, trending} {
+		if params.LongEMAPeriod <= params.ShortEMAPeriod || float64(params.LongEMAPeriod) > cfg.LongEMA.Max {
+			t.Errorf("Expected a long EMA above the short one and within bounds, got %+v", params)
+		}
+	}
+
+	// Losing streaks take profits sooner, winning streaks let them run
+	losing := fitParameters(zigzag(100, 0.05), wins(1, 10), base, calm, cfg)
+	winning := fitParameters(zigzag(100, 0.05), wins(9, 10), base, calm, cfg)
+	if losing.TakeProfitPercent >= calm.TakeProfitPercent || winning.TakeProfitPercent <= calm.TakeProfitPercent {
+		t.Errorf("Expected take profits to follow outcomes, got %.2f losing, %.2f unchanged, %.2f winning",
+			losing.TakeProfitPercent, calm.TakeProfitPercent, winning.TakeProfitPercent)
+	}
+	if few := fitParameters(zigzag(100, 0.05), wins(0, 2), base, calm, cfg); few.TakeProfitPercent != calm.TakeProfitPercent {
+		t.Error("Expected too few trades to leave the take profit alone")
+	}
+
+	// Nothing is measured without enough history
+	if kept := fitParameters(zigzag(10, 0.5), nil, base, base, cfg); kept != base {
+		t.Errorf("Expected the parameters kept without history, got %+v", kept)
+	}
+}
+
+func TestTuner_Tune(t *testing.T) {
+	cfg := config.DefaultConfig()
+	strategy := NewScalpingStrategy(cfg, testutils.NewTestExchange("test"))
+	for _, price := range zigzag(100, 0.5) {
+		strategy.prices.Push(price)
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tuner := NewTuner(DefaultTuningConfig(), func() map[string]*ScalpingStrategy {
+		return map[string]*ScalpingStrategy{"BTC-USD": strategy}
+	})
+	tuner.now = func() time.Time { return now }
+
+	tuner.Tune()
+	tuned := strategy.Parameters()
+	if tuned.StopLossPercent == 1 || tuned.ShortEMAPeriod == 9 {
+		t.Fatalf("Expected the parameters to be tuned, got %+v", tuned)
+	}
+	if !strategy.tuned {
+		t.Error("Expected entry signals to carry the tuned protection")
+	}
+
+	// Outcomes wait for the cooldown
+	for _, pnl := range wins(0, 10) {
+		tuner.RecordTrade("BTC-USD", pnl)
+	}
+	tuner.Tune()
+	if got := strategy.Parameters(); got != tuned {
+		t.Errorf("Expected no change during the cooldown, got %+v", got)
+	}
+	now = now.Add(2 * time.Hour)
+	tuner.Tune()
+	if got := strategy.Parameters(); got.TakeProfitPercent >= tuned.TakeProfitPercent {
+		t.Errorf("Expected losses to bring the take profit in after the cooldown, got %+v", got)
+	}
+}
@@ -29,6 +29,7 @@ type SymbolManager struct {
 	mu            sync.RWMutex
 	symbols       map[string]*SymbolConfig
 	activeSymbols []string
+	allows        func(symbol string) bool // Symbols that may be traded, all when nil
 }
 
 // NewSymbolManager creates a new symbol manager
@@ -39,6 +40,14 @@ func NewSymbolManager() *SymbolManager {
 	}
 }
 
+// SetSymbolFilter restricts the symbols that may be added or enabled to
+// those allows accepts
+func (sm *SymbolManager) SetSymbolFilter(allows func(symbol string) bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.allows = allows
+}
+
 // AddSymbol adds a new symbol with its configuration
 func (sm *SymbolManager) AddSymbol(symbol string, config SymbolConfig) error {
 	sm.mu.Lock()
@@ -47,6 +56,9 @@ func (sm *SymbolManager) AddSymbol(symbol string, config SymbolConfig) error {
 	if _, exists := sm.symbols[symbol]; exists {
 		return fmt.Errorf("symbol %s already exists", symbol)
 	}
+	if sm.allows != nil && !sm.allows(symbol) {
+		return fmt.Errorf("symbol %s is not allowed on its exchange", symbol)
+	}
 
 	config.Symbol = symbol // Ensure symbol is set in config
 	sm.symbols[symbol] = &config
@@ -131,6 +143,9 @@ func (sm *SymbolManager) EnableSymbol(symbol string) error {
 	if config.Enabled {
 		return nil // Already enabled
 	}
+	if sm.allows != nil && !sm.allows(symbol) {
+		return fmt.Errorf("symbol %s is not allowed on its exchange", symbol)
+	}
 
 	config.Enabled = true
 	sm.activeSymbols = append(sm.activeSymbols, symbol)
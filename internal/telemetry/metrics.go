@@ -2,6 +2,8 @@ package telemetry
 
 import (
 	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
@@ -23,12 +25,54 @@ var (
 	positionUpdates     = make(map[string]map[string]float64) // symbol -> field -> value
 	pnlUpdates          = make(map[string]float64)
 	signalCounts        = make(map[string]uint64)                     // signal type counters
+	blockedSignals      = make(map[string]map[string]uint64)          // symbol -> reason -> count
 	errorCounts         = make(map[string]uint64)                     // error type counters
 	websocketReconnects = make(map[string]uint64)                     // exchange -> reconnect count
+	candleGapsHealed    = make(map[string]uint64)                     // symbol -> gaps backfilled
+	candlesBackfilled   = make(map[string]uint64)                     // symbol -> candles backfilled
+	ticksFiltered       = make(map[string]map[string]uint64)          // symbol -> reason -> count
+	clockOffsets        = make(map[string]float64)                    // exchange -> server time offset in seconds
+	clockSkewWarnings   = make(map[string]uint64)                     // exchange -> skew warnings
+	rateLimitRemaining  = make(map[string]float64)                    // exchange -> requests left in the window
+	rateLimitAllowance  = make(map[string]float64)                    // exchange -> allowed requests per second
+	rateLimited         = make(map[string]uint64)                     // exchange -> 429 responses
 	apiRequestCounts    = make(map[string]map[string]uint64)          // exchange -> endpoint -> count
 	apiRequestLatency   = make(map[string]map[string][]time.Duration) // exchange -> endpoint -> latencies
+	watchdogRestarts    = make(map[string]map[string]uint64)          // component -> outcome -> restarts
+	exitsUnconfirmed    = make(map[string]uint64)                     // symbol -> exits held back by a second venue
+	callbackQueueDepth  = make(map[string]float64)                    // queue -> values waiting for their consumer
+	callbackDrops       = make(map[string]uint64)                     // queue -> values dropped from a full queue
+	executions          = make(map[executionKey]*executionStats)      // exchange and symbol -> execution quality
+	symbolScores        = make(map[string]SymbolScore)                // symbol -> latest selection assessment
+	drawdownPercent     float64                                       // drawdown from the peak balance
+	riskScale           = 1.0                                         // fraction of the risk per trade taken
 )
 
+// SymbolScore is the assessment of a symbol by the latest symbol selection
+type SymbolScore struct {
+	Symbol      string
+	Selected    bool
+	Score       float64            // Composite opportunity score
+	Potential   float64            // Gain potential
+	Risk        float64            // Risk assessment
+	Sharpe      float64            // Risk-adjusted return
+	Correlation float64            // Highest return correlation with the symbols selected before it
+	Weights     map[string]float64 // Dynamic indicator weights by indicator
+}
+
+// executionKey identifies the executions of a symbol on an exchange
+type executionKey struct {
+	exchange string
+	symbol   string
+}
+
+// executionStats aggregates the fills of a symbol on an exchange
+type executionStats struct {
+	liquidity   map[string]uint64 // liquidity -> fills
+	slippageSum float64           // bps, over the measured fills
+	measured    uint64
+}
+
 // RecordOrderPlaced increments the order placed counter.
 func RecordOrderPlaced(symbol, side string) {
 	if symbol == "" {
@@ -118,6 +162,23 @@ func RecordSignal(signalType string) {
 	signalCounts[signalType]++
 }
 
+// RecordSignalBlocked records a signal that was not executed, by symbol and reason.
+func RecordSignalBlocked(symbol, reason string) {
+	if symbol == "" {
+		symbol = "unknown"
+	}
+	if reason == "" {
+		reason = "unknown"
+	}
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	if _, exists := blockedSignals[symbol]; !exists {
+		blockedSignals[symbol] = make(map[string]uint64)
+	}
+	blockedSignals[symbol][reason]++
+}
+
 // RecordError records errors by type.
 func RecordError(errorType string) {
 	if errorType == "" {
@@ -138,6 +199,74 @@ func RecordWebSocketReconnect(exchange string) {
 	websocketReconnects[exchange]++
 }
 
+// RecordCandleGapHealed records a gap in the candle stream of symbol that was
+// backfilled with candles fetched over REST.
+func RecordCandleGapHealed(symbol string, candles int) {
+	if symbol == "" {
+		symbol = "unknown"
+	}
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	candleGapsHealed[symbol]++
+	candlesBackfilled[symbol] += uint64(candles)
+}
+
+// RecordTickFiltered records a tick kept out of the price history, by symbol
+// and reason.
+func RecordTickFiltered(symbol, reason string) {
+	if symbol == "" {
+		symbol = "unknown"
+	}
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	if _, exists := ticksFiltered[symbol]; !exists {
+		ticksFiltered[symbol] = make(map[string]uint64)
+	}
+	ticksFiltered[symbol][reason]++
+}
+
+// RecordClockOffset records the offset of an exchange's server time from the
+// local clock.
+func RecordClockOffset(exchange string, offset time.Duration) {
+	if exchange == "" {
+		exchange = "unknown"
+	}
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	clockOffsets[exchange] = offset.Seconds()
+}
+
+// RecordClockSkewWarning records a clock offset to an exchange large enough
+// to get signed requests rejected.
+func RecordClockSkewWarning(exchange string) {
+	if exchange == "" {
+		exchange = "unknown"
+	}
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	clockSkewWarnings[exchange]++
+}
+
+// RecordRateLimit records the rate limiting state of an exchange after a
+// response: the allowance in requests per second, the requests left in the
+// exchange's window when it reports them (remaining >= 0) and whether the
+// request was throttled.
+func RecordRateLimit(exchange string, allowance float64, remaining int, throttled bool) {
+	if exchange == "" {
+		exchange = "unknown"
+	}
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	rateLimitAllowance[exchange] = allowance
+	if remaining >= 0 {
+		rateLimitRemaining[exchange] = float64(remaining)
+	}
+	if throttled {
+		rateLimited[exchange]++
+	}
+}
+
 // RecordAPIRequest records API request metrics.
 func RecordAPIRequest(exchange, endpoint string, latency time.Duration) {
 	if exchange == "" {
@@ -168,10 +297,109 @@ func RecordAPIRequest(exchange, endpoint string, latency time.Duration) {
 	apiRequestLatency[exchange][endpoint] = latencies
 }
 
+// RecordWatchdogRestart records the watchdog restarting a stuck component,
+// with the outcome "ok" or "failed".
+func RecordWatchdogRestart(component string, ok bool) {
+	if component == "" {
+		component = "unknown"
+	}
+	outcome := "ok"
+	if !ok {
+		outcome = "failed"
+	}
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	if _, exists := watchdogRestarts[component]; !exists {
+		watchdogRestarts[component] = make(map[string]uint64)
+	}
+	watchdogRestarts[component][outcome]++
+}
+
+// RecordExitUnconfirmed records a stop or exit held back because a second
+// venue did not confirm its trigger price.
+func RecordExitUnconfirmed(symbol string) {
+	if symbol == "" {
+		symbol = "unknown"
+	}
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	exitsUnconfirmed[symbol]++
+}
+
+// RecordCallbackQueueDepth records the values waiting in a callback queue
+// for their consumer.
+func RecordCallbackQueueDepth(queue string, depth int) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	callbackQueueDepth[queue] = float64(depth)
+}
+
+// RecordCallbackDropped records a value dropped from a full callback queue.
+func RecordCallbackDropped(queue string) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	callbackDrops[queue]++
+}
+
+// RecordExecution records a filled order on exchange and whether it made or
+// took liquidity. Its slippage, in basis points, is aggregated only when
+// measured against a reference price.
+func RecordExecution(exchange, symbol, liquidity string, slippageBps float64, measured bool) {
+	if exchange == "" {
+		exchange = "unknown"
+	}
+	if symbol == "" {
+		symbol = "unknown"
+	}
+	if liquidity == "" {
+		liquidity = "unknown"
+	}
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	key := executionKey{exchange: exchange, symbol: symbol}
+	stats, exists := executions[key]
+	if !exists {
+		stats = &executionStats{liquidity: make(map[string]uint64)}
+		executions[key] = stats
+	}
+	stats.liquidity[liquidity]++
+	if measured {
+		stats.slippageSum += slippageBps
+		stats.measured++
+	}
+}
+
+// RecordRiskScale records the drawdown from the peak balance, in percent, and
+// the fraction of the risk per trade it leaves.
+func RecordRiskScale(drawdownPct, scale float64) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	drawdownPercent = drawdownPct
+	riskScale = scale
+}
+
+// RecordSymbolSelection replaces the symbol selection metrics with those of
+// the latest refresh, so symbols no longer evaluated stop being reported.
+func RecordSymbolSelection(scores []SymbolScore) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	symbolScores = make(map[string]SymbolScore, len(scores))
+	for _, score := range scores {
+		if score.Symbol == "" {
+			continue
+		}
+		symbolScores[score.Symbol] = score
+	}
+}
+
 // Server exposes metrics and health endpoints.
 type Server struct {
 	srv        *http.Server
+	mux        *http.ServeMux
+	handler    atomic.Value // http.Handler, the mux behind its middleware
 	readyState atomic.Bool
+	status     atomic.Value // func() any
 }
 
 // NewServer creates a new telemetry server.
@@ -197,9 +425,15 @@ func NewServer(addr string) *Server {
 		_, _ = w.Write([]byte("not ready"))
 	})
 
+	mux.HandleFunc("/status", server.statusHandler)
+
+	server.mux = mux
+	server.handler.Store(http.Handler(mux))
 	server.srv = &http.Server{
-		Addr:    addr,
-		Handler: mux,
+		Addr: addr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			server.handler.Load().(http.Handler).ServeHTTP(w, r)
+		}),
 	}
 
 	return server
@@ -251,7 +485,6 @@ func (s *Server) metricsHandler(w http.ResponseWriter, _ *http.Request) {
 	for _, symbol := range symbols {
 		fmt.Fprintf(builder, "constantine_take_profit_total{symbol=\"%s\"} %d\n", symbol, takeProfitCounts[symbol])
 	}
-	metricsMu.RUnlock()
 
 	builder.WriteString("# HELP constantine_callback_panics_total Number of recovered panics from callbacks\n")
 	builder.WriteString("# TYPE constantine_callback_panics_total counter\n")
@@ -312,6 +545,25 @@ func (s *Server) metricsHandler(w http.ResponseWriter, _ *http.Request) {
 		fmt.Fprintf(builder, "constantine_signals_total{type=\"%s\"} %d\n", signalType, signalCounts[signalType])
 	}
 
+	// Blocked signal metrics
+	builder.WriteString("# HELP constantine_signals_blocked_total Signals not executed by symbol and reason\n")
+	builder.WriteString("# TYPE constantine_signals_blocked_total counter\n")
+	symbols = symbols[:0]
+	for symbol := range blockedSignals {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		reasons := make([]string, 0, len(blockedSignals[symbol]))
+		for reason := range blockedSignals[symbol] {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			fmt.Fprintf(builder, "constantine_signals_blocked_total{symbol=\"%s\",reason=\"%s\"} %d\n", symbol, reason, blockedSignals[symbol][reason])
+		}
+	}
+
 	// Error metrics
 	builder.WriteString("# HELP constantine_errors_total Total errors by type\n")
 	builder.WriteString("# TYPE constantine_errors_total counter\n")
@@ -336,6 +588,96 @@ func (s *Server) metricsHandler(w http.ResponseWriter, _ *http.Request) {
 		fmt.Fprintf(builder, "constantine_websocket_reconnects_total{exchange=\"%s\"} %d\n", exchange, websocketReconnects[exchange])
 	}
 
+	// Candle gap metrics
+	builder.WriteString("# HELP constantine_candle_gaps_healed_total Gaps in the candle stream backfilled by symbol\n")
+	builder.WriteString("# TYPE constantine_candle_gaps_healed_total counter\n")
+	symbols = symbols[:0]
+	for symbol := range candleGapsHealed {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		fmt.Fprintf(builder, "constantine_candle_gaps_healed_total{symbol=\"%s\"} %d\n", symbol, candleGapsHealed[symbol])
+	}
+	builder.WriteString("# HELP constantine_candles_backfilled_total Candles fetched to heal gaps by symbol\n")
+	builder.WriteString("# TYPE constantine_candles_backfilled_total counter\n")
+	for _, symbol := range symbols {
+		fmt.Fprintf(builder, "constantine_candles_backfilled_total{symbol=\"%s\"} %d\n", symbol, candlesBackfilled[symbol])
+	}
+
+	// Filtered tick metrics
+	builder.WriteString("# HELP constantine_ticks_filtered_total Ticks kept out of the price history by symbol and reason\n")
+	builder.WriteString("# TYPE constantine_ticks_filtered_total counter\n")
+	symbols = symbols[:0]
+	for symbol := range ticksFiltered {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		reasons := make([]string, 0, len(ticksFiltered[symbol]))
+		for reason := range ticksFiltered[symbol] {
+			reasons = append(reasons, reason)
+		}
+		sort.Strings(reasons)
+		for _, reason := range reasons {
+			fmt.Fprintf(builder, "constantine_ticks_filtered_total{symbol=\"%s\",reason=\"%s\"} %d\n", symbol, reason, ticksFiltered[symbol][reason])
+		}
+	}
+
+	// Clock metrics
+	builder.WriteString("# HELP constantine_clock_offset_seconds Offset of the exchange server time from the local clock\n")
+	builder.WriteString("# TYPE constantine_clock_offset_seconds gauge\n")
+	exchanges = exchanges[:0]
+	for exchange := range clockOffsets {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	for _, exchange := range exchanges {
+		fmt.Fprintf(builder, "constantine_clock_offset_seconds{exchange=\"%s\"} %f\n", exchange, clockOffsets[exchange])
+	}
+	builder.WriteString("# HELP constantine_clock_skew_warnings_total Clock checks finding a skew that risks rejected requests by exchange\n")
+	builder.WriteString("# TYPE constantine_clock_skew_warnings_total counter\n")
+	exchanges = exchanges[:0]
+	for exchange := range clockSkewWarnings {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	for _, exchange := range exchanges {
+		fmt.Fprintf(builder, "constantine_clock_skew_warnings_total{exchange=\"%s\"} %d\n", exchange, clockSkewWarnings[exchange])
+	}
+
+	// Rate limit metrics
+	builder.WriteString("# HELP constantine_rate_limit_allowance Requests per second currently allowed by exchange\n")
+	builder.WriteString("# TYPE constantine_rate_limit_allowance gauge\n")
+	exchanges = exchanges[:0]
+	for exchange := range rateLimitAllowance {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	for _, exchange := range exchanges {
+		fmt.Fprintf(builder, "constantine_rate_limit_allowance{exchange=\"%s\"} %f\n", exchange, rateLimitAllowance[exchange])
+	}
+	builder.WriteString("# HELP constantine_rate_limit_remaining Requests left in the exchange rate limit window\n")
+	builder.WriteString("# TYPE constantine_rate_limit_remaining gauge\n")
+	exchanges = exchanges[:0]
+	for exchange := range rateLimitRemaining {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	for _, exchange := range exchanges {
+		fmt.Fprintf(builder, "constantine_rate_limit_remaining{exchange=\"%s\"} %.0f\n", exchange, rateLimitRemaining[exchange])
+	}
+	builder.WriteString("# HELP constantine_rate_limited_total Requests rejected with 429 Too Many Requests by exchange\n")
+	builder.WriteString("# TYPE constantine_rate_limited_total counter\n")
+	exchanges = exchanges[:0]
+	for exchange := range rateLimited {
+		exchanges = append(exchanges, exchange)
+	}
+	sort.Strings(exchanges)
+	for _, exchange := range exchanges {
+		fmt.Fprintf(builder, "constantine_rate_limited_total{exchange=\"%s\"} %d\n", exchange, rateLimited[exchange])
+	}
+
 	// API request metrics
 	builder.WriteString("# HELP constantine_api_requests_total Total API requests by exchange and endpoint\n")
 	builder.WriteString("# TYPE constantine_api_requests_total counter\n")
@@ -382,17 +724,190 @@ func (s *Server) metricsHandler(w http.ResponseWriter, _ *http.Request) {
 		}
 	}
 
+	// Watchdog metrics
+	builder.WriteString("# HELP constantine_watchdog_restarts_total Stuck components restarted by the watchdog by outcome\n")
+	builder.WriteString("# TYPE constantine_watchdog_restarts_total counter\n")
+	components := make([]string, 0, len(watchdogRestarts))
+	for component := range watchdogRestarts {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	for _, component := range components {
+		for _, outcome := range []string{"ok", "failed"} {
+			if count, exists := watchdogRestarts[component][outcome]; exists {
+				fmt.Fprintf(builder, "constantine_watchdog_restarts_total{component=\"%s\",outcome=\"%s\"} %d\n", component, outcome, count)
+			}
+		}
+	}
+
+	// Exit confirmation metrics
+	builder.WriteString("# HELP constantine_exits_unconfirmed_total Stops and exits held back because a second venue did not confirm the price\n")
+	builder.WriteString("# TYPE constantine_exits_unconfirmed_total counter\n")
+	unconfirmedSymbols := make([]string, 0, len(exitsUnconfirmed))
+	for symbol := range exitsUnconfirmed {
+		unconfirmedSymbols = append(unconfirmedSymbols, symbol)
+	}
+	sort.Strings(unconfirmedSymbols)
+	for _, symbol := range unconfirmedSymbols {
+		fmt.Fprintf(builder, "constantine_exits_unconfirmed_total{symbol=\"%s\"} %d\n", symbol, exitsUnconfirmed[symbol])
+	}
+
+	// Callback queue metrics
+	builder.WriteString("# HELP constantine_callback_queue_depth Values waiting in a callback queue for their consumer\n")
+	builder.WriteString("# TYPE constantine_callback_queue_depth gauge\n")
+	queues := make([]string, 0, len(callbackQueueDepth))
+	for queue := range callbackQueueDepth {
+		queues = append(queues, queue)
+	}
+	sort.Strings(queues)
+	for _, queue := range queues {
+		fmt.Fprintf(builder, "constantine_callback_queue_depth{queue=\"%s\"} %f\n", queue, callbackQueueDepth[queue])
+	}
+	builder.WriteString("# HELP constantine_callback_dropped_total Values dropped from a full callback queue\n")
+	builder.WriteString("# TYPE constantine_callback_dropped_total counter\n")
+	droppingQueues := make([]string, 0, len(callbackDrops))
+	for queue := range callbackDrops {
+		droppingQueues = append(droppingQueues, queue)
+	}
+	sort.Strings(droppingQueues)
+	for _, queue := range droppingQueues {
+		fmt.Fprintf(builder, "constantine_callback_dropped_total{queue=\"%s\"} %d\n", queue, callbackDrops[queue])
+	}
+
+	// Execution quality metrics
+	executionKeys := make([]executionKey, 0, len(executions))
+	for key := range executions {
+		executionKeys = append(executionKeys, key)
+	}
+	sort.Slice(executionKeys, func(i, j int) bool {
+		if executionKeys[i].exchange != executionKeys[j].exchange {
+			return executionKeys[i].exchange < executionKeys[j].exchange
+		}
+		return executionKeys[i].symbol < executionKeys[j].symbol
+	})
+	builder.WriteString("# HELP constantine_executions_total Filled orders by exchange, symbol and maker or taker liquidity\n")
+	builder.WriteString("# TYPE constantine_executions_total counter\n")
+	for _, key := range executionKeys {
+		liquidities := make([]string, 0, len(executions[key].liquidity))
+		for liquidity := range executions[key].liquidity {
+			liquidities = append(liquidities, liquidity)
+		}
+		sort.Strings(liquidities)
+		for _, liquidity := range liquidities {
+			fmt.Fprintf(builder, "constantine_executions_total{exchange=\"%s\",symbol=\"%s\",liquidity=\"%s\"} %d\n",
+				key.exchange, key.symbol, liquidity, executions[key].liquidity[liquidity])
+		}
+	}
+	builder.WriteString("# HELP constantine_execution_slippage_bps Slippage of fills against their signal or mark price, in basis points, positive when adverse\n")
+	builder.WriteString("# TYPE constantine_execution_slippage_bps summary\n")
+	for _, key := range executionKeys {
+		stats := executions[key]
+		fmt.Fprintf(builder, "constantine_execution_slippage_bps_sum{exchange=\"%s\",symbol=\"%s\"} %f\n", key.exchange, key.symbol, stats.slippageSum)
+		fmt.Fprintf(builder, "constantine_execution_slippage_bps_count{exchange=\"%s\",symbol=\"%s\"} %d\n", key.exchange, key.symbol, stats.measured)
+	}
+
+	builder.WriteString("# HELP constantine_drawdown_percent Drawdown of the balance from its peak, in percent\n")
+	builder.WriteString("# TYPE constantine_drawdown_percent gauge\n")
+	fmt.Fprintf(builder, "constantine_drawdown_percent %f\n", drawdownPercent)
+	builder.WriteString("# HELP constantine_risk_scale Fraction of the configured risk per trade taken at the current drawdown\n")
+	builder.WriteString("# TYPE constantine_risk_scale gauge\n")
+	fmt.Fprintf(builder, "constantine_risk_scale %f\n", riskScale)
+
+	// Symbol selection metrics
+	scoredSymbols := make([]string, 0, len(symbolScores))
+	for symbol := range symbolScores {
+		scoredSymbols = append(scoredSymbols, symbol)
+	}
+	sort.Strings(scoredSymbols)
+	selectionGauges := []struct {
+		name, help string
+		value      func(SymbolScore) float64
+	}{
+		{"constantine_symbol_selected", "Whether the symbol is in the latest symbol selection", func(s SymbolScore) float64 {
+			if s.Selected {
+				return 1
+			}
+			return 0
+		}},
+		{"constantine_symbol_score", "Composite opportunity score of the symbol in [0, 1]", func(s SymbolScore) float64 { return s.Score }},
+		{"constantine_symbol_potential", "Gain potential of the symbol in [0, 1]", func(s SymbolScore) float64 { return s.Potential }},
+		{"constantine_symbol_risk", "Risk assessment of the symbol in [0, 1]", func(s SymbolScore) float64 { return s.Risk }},
+		{"constantine_symbol_sharpe", "Sharpe ratio of the symbol's recent returns", func(s SymbolScore) float64 { return s.Sharpe }},
+		{"constantine_symbol_correlation", "Highest return correlation of a selected symbol with the symbols selected before it", func(s SymbolScore) float64 { return s.Correlation }},
+	}
+	for _, gauge := range selectionGauges {
+		fmt.Fprintf(builder, "# HELP %s %s\n", gauge.name, gauge.help)
+		fmt.Fprintf(builder, "# TYPE %s gauge\n", gauge.name)
+		for _, symbol := range scoredSymbols {
+			fmt.Fprintf(builder, "%s{symbol=\"%s\"} %f\n", gauge.name, symbol, gauge.value(symbolScores[symbol]))
+		}
+	}
+	builder.WriteString("# HELP constantine_indicator_weight Dynamic weight of each indicator in the signals of the symbol\n")
+	builder.WriteString("# TYPE constantine_indicator_weight gauge\n")
+	for _, symbol := range scoredSymbols {
+		weights := symbolScores[symbol].Weights
+		indicators := make([]string, 0, len(weights))
+		for indicator := range weights {
+			indicators = append(indicators, indicator)
+		}
+		sort.Strings(indicators)
+		for _, indicator := range indicators {
+			fmt.Fprintf(builder, "constantine_indicator_weight{symbol=\"%s\",indicator=\"%s\"} %f\n", symbol, indicator, weights[indicator])
+		}
+	}
+
 	metricsMu.RUnlock()
 
 	_, _ = w.Write([]byte(builder.String()))
 }
 
+// SetStatusSource sets the function whose result is served as JSON on /status
+func (s *Server) SetStatusSource(source func() any) {
+	if s == nil {
+		return
+	}
+	s.status.Store(source)
+}
+
+func (s *Server) statusHandler(w http.ResponseWriter, _ *http.Request) {
+	source, _ := s.status.Load().(func() any)
+	if source == nil {
+		http.Error(w, "status not available", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(source()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// SetMiddleware wraps every endpoint, including those already registered,
+// with middleware.
+func (s *Server) SetMiddleware(middleware func(http.Handler) http.Handler) {
+	if s == nil {
+		return
+	}
+	s.handler.Store(middleware(s.mux))
+}
+
+// SetTLSConfig serves over TLS with config. Must be called before Start.
+func (s *Server) SetTLSConfig(config *tls.Config) {
+	if s == nil || s.srv == nil {
+		return
+	}
+	s.srv.TLSConfig = config
+}
+
 // Start begins serving metrics and health endpoints in a separate goroutine.
 func (s *Server) Start() error {
 	if s == nil || s.srv == nil {
 		return nil
 	}
 	go func() {
+		if s.srv.TLSConfig != nil {
+			_ = s.srv.ListenAndServeTLS("", "")
+			return
+		}
 		_ = s.srv.ListenAndServe()
 	}()
 	return nil
@@ -406,6 +921,14 @@ func (s *Server) Shutdown(ctx context.Context) error {
 	return s.srv.Shutdown(ctx)
 }
 
+// HandleFunc serves an additional endpoint on the telemetry server.
+func (s *Server) HandleFunc(pattern string, handler http.HandlerFunc) {
+	if s == nil {
+		return
+	}
+	s.mux.HandleFunc(pattern, handler)
+}
+
 // SetReady updates the readiness state exposed on /readyz.
 func (s *Server) SetReady(ready bool) {
 	if s == nil {
@@ -12,20 +12,23 @@ import (
 
 // TestExchange is a test implementation of the Exchange interface
 type TestExchange struct {
-	NameValue        string
-	ConnectedValue   bool
-	BalancesValue    []exchanges.Balance
-	PositionsValue   []exchanges.Position
-	OrdersValue      []exchanges.Order
-	TickerValue      *exchanges.Ticker
-	OrderBookValue   *exchanges.OrderBook
-	CandlesValue     []exchanges.Candle
-	ConnectError     error
-	BalanceError     error
-	PositionError    error
-	OrderError       error
-	PlaceOrderError  error
-	CancelOrderError error
+	NameValue         string
+	ConnectedValue    bool
+	BalancesValue     []exchanges.Balance
+	PositionsValue    []exchanges.Position
+	OrdersValue       []exchanges.Order
+	TickerValue       *exchanges.Ticker
+	OrderBookValue    *exchanges.OrderBook
+	CandlesValue      []exchanges.Candle
+	CapabilitiesValue exchanges.Capabilities
+	LeverageValue     map[string]exchanges.LeverageSettings
+	LeverageError     error
+	ConnectError      error
+	BalanceError      error
+	PositionError     error
+	OrderError        error
+	PlaceOrderError   error
+	CancelOrderError  error
 }
 
 func NewTestExchange(name string) *TestExchange {
@@ -95,6 +98,16 @@ func NewTestExchange(name string) *TestExchange {
 				Volume:    decimal.NewFromFloat(100),
 			},
 		},
+		CapabilitiesValue: exchanges.Capabilities{
+			Shorts:         true,
+			StopOrders:     true,
+			OCO:            true,
+			PostOnly:       true,
+			ReduceOnly:     true,
+			WebSocketFills: true,
+			Margin:         true,
+			Hedge:          true,
+		},
 	}
 }
 
@@ -207,6 +220,49 @@ func (t *TestExchange) SupportedSymbols() []string {
 	return []string{"BTC-USD", "ETH-USD"}
 }
 
+func (t *TestExchange) Capabilities() exchanges.Capabilities {
+	return t.CapabilitiesValue
+}
+
+func (t *TestExchange) SetLeverage(ctx context.Context, symbol string, leverage decimal.Decimal) error {
+	if t.LeverageError != nil {
+		return t.LeverageError
+	}
+	settings := t.leverageSettings(symbol)
+	settings.Leverage = leverage
+	t.LeverageValue[symbol] = settings
+	return nil
+}
+
+func (t *TestExchange) SetMarginMode(ctx context.Context, symbol string, mode exchanges.MarginMode) error {
+	if t.LeverageError != nil {
+		return t.LeverageError
+	}
+	settings := t.leverageSettings(symbol)
+	settings.MarginMode = mode
+	t.LeverageValue[symbol] = settings
+	return nil
+}
+
+func (t *TestExchange) GetLeverage(ctx context.Context, symbol string) (*exchanges.LeverageSettings, error) {
+	settings := t.leverageSettings(symbol)
+	return &settings, nil
+}
+
+func (t *TestExchange) leverageSettings(symbol string) exchanges.LeverageSettings {
+	if t.LeverageValue == nil {
+		t.LeverageValue = make(map[string]exchanges.LeverageSettings)
+	}
+	if settings, ok := t.LeverageValue[symbol]; ok {
+		return settings
+	}
+	return exchanges.LeverageSettings{
+		Symbol:     symbol,
+		Leverage:   decimal.NewFromInt(1),
+		MarginMode: exchanges.MarginModeCross,
+	}
+}
+
 // AssertEqual is a helper function for asserting equality in tests
 func AssertEqual(t *testing.T, expected, actual any, message string) {
 	t.Helper()
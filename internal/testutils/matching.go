@@ -0,0 +1,270 @@
+package testutils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/shopspring/decimal"
+)
+
+// MatchingExchange is a test exchange that fills orders against a price set
+// by the test. Market orders fill on placement and limit orders once the
+// price reaches them, half of the remaining amount at a time when partial
+// fills are enabled.
+type MatchingExchange struct {
+	*TestExchange
+
+	mu           sync.Mutex
+	price        decimal.Decimal
+	partialFills bool
+	orders       map[string]*exchanges.Order
+	byClientID   map[string]string
+	positions    map[string]decimal.Decimal // symbol -> signed filled amount
+	nextID       int
+}
+
+// NewMatchingExchange creates a matching exchange trading at price
+func NewMatchingExchange(name string, price decimal.Decimal) *MatchingExchange {
+	e := &MatchingExchange{
+		TestExchange: NewTestExchange(name),
+		price:        price,
+		orders:       make(map[string]*exchanges.Order),
+		byClientID:   make(map[string]string),
+		positions:    make(map[string]decimal.Decimal),
+	}
+	e.TestExchange.PositionsValue = nil
+	e.TestExchange.OrdersValue = nil
+	return e
+}
+
+// SetPartialFills makes limit orders fill half of their remaining amount each
+// time the price reaches them
+func (e *MatchingExchange) SetPartialFills(enabled bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.partialFills = enabled
+}
+
+// SetPrice moves the price and fills the limit orders it reaches
+func (e *MatchingExchange) SetPrice(price decimal.Decimal) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.price = price
+
+	ids := make([]string, 0, len(e.orders))
+	for id := range e.orders {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		e.matchLimit(e.orders[id], e.partialFills)
+	}
+}
+
+// Price returns the current price
+func (e *MatchingExchange) Price() decimal.Decimal {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.price
+}
+
+// NetPosition returns the signed amount filled on symbol, positive when long
+func (e *MatchingExchange) NetPosition(symbol string) decimal.Decimal {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.positions[symbol]
+}
+
+// Orders returns every order placed, in placement order, with its current
+// state
+func (e *MatchingExchange) Orders() []exchanges.Order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	orders := make([]exchanges.Order, 0, len(e.orders))
+	for _, order := range e.orders {
+		orders = append(orders, *order)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].CreatedAt.Before(orders[j].CreatedAt) })
+	return orders
+}
+
+func (e *MatchingExchange) PlaceOrder(ctx context.Context, order *exchanges.Order) (*exchanges.Order, error) {
+	if e.PlaceOrderError != nil {
+		return nil, e.PlaceOrderError
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.nextID++
+	placed := *order
+	placed.ID = fmt.Sprintf("%s-%d", e.NameValue, e.nextID)
+	placed.Status = exchanges.OrderStatusOpen
+	placed.Filled = decimal.Zero
+	placed.FilledAmount = decimal.Zero
+	placed.AveragePrice = decimal.Zero
+	placed.Remaining = placed.Amount
+	placed.CreatedAt = time.Unix(0, int64(e.nextID))
+	e.orders[placed.ID] = &placed
+	// Duplicate client order IDs are accepted, as on venues that do not
+	// enforce them, so tests can catch callers placing an order twice
+	if _, exists := e.byClientID[placed.ClientOrderID]; placed.ClientOrderID != "" && !exists {
+		e.byClientID[placed.ClientOrderID] = placed.ID
+	}
+
+	if placed.Type == exchanges.OrderTypeMarket {
+		e.fill(&placed, placed.Amount)
+	} else {
+		e.matchLimit(&placed, false)
+	}
+	result := placed
+	return &result, nil
+}
+
+// matchLimit fills order if the price reached its limit. Must be called with
+// the lock held.
+func (e *MatchingExchange) matchLimit(order *exchanges.Order, partial bool) {
+	if order.Type != exchanges.OrderTypeLimit || !order.Remaining.IsPositive() ||
+		(order.Status != exchanges.OrderStatusOpen && order.Status != exchanges.OrderStatusPartially) {
+		return
+	}
+	reached := (order.Side == exchanges.OrderSideBuy && e.price.LessThanOrEqual(order.Price)) ||
+		(order.Side == exchanges.OrderSideSell && e.price.GreaterThanOrEqual(order.Price))
+	if !reached {
+		return
+	}
+	qty := order.Remaining
+	if partial {
+		if half := qty.Div(decimal.NewFromInt(2)).Round(8); half.IsPositive() {
+			qty = half
+		}
+	}
+	e.fill(order, qty)
+}
+
+// fill fills qty of order at the current price. Must be called with the lock
+// held.
+func (e *MatchingExchange) fill(order *exchanges.Order, qty decimal.Decimal) {
+	price := e.price
+	if order.Type == exchanges.OrderTypeLimit {
+		price = order.Price
+	}
+	filled := order.Filled.Add(qty)
+	order.AveragePrice = order.AveragePrice.Mul(order.Filled).Add(price.Mul(qty)).Div(filled)
+	order.Filled = filled
+	order.FilledAmount = filled
+	order.Remaining = order.Amount.Sub(filled)
+	order.Status = exchanges.OrderStatusPartially
+	if !order.Remaining.IsPositive() {
+		order.Status = exchanges.OrderStatusFilled
+	}
+	order.UpdatedAt = time.Now()
+
+	if order.Side == exchanges.OrderSideBuy {
+		e.positions[order.Symbol] = e.positions[order.Symbol].Add(qty)
+	} else {
+		e.positions[order.Symbol] = e.positions[order.Symbol].Sub(qty)
+	}
+}
+
+func (e *MatchingExchange) CancelOrder(ctx context.Context, orderID string) error {
+	if e.CancelOrderError != nil {
+		return e.CancelOrderError
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	order, exists := e.orders[orderID]
+	if !exists {
+		return exchanges.ErrOrderNotFound
+	}
+	if order.Status != exchanges.OrderStatusOpen && order.Status != exchanges.OrderStatusPartially {
+		return fmt.Errorf("order %s is %s", orderID, order.Status)
+	}
+	order.Status = exchanges.OrderStatusCanceled
+	order.UpdatedAt = time.Now()
+	return nil
+}
+
+func (e *MatchingExchange) GetOrder(ctx context.Context, orderID string) (*exchanges.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	order, exists := e.orders[orderID]
+	if !exists {
+		return nil, exchanges.ErrOrderNotFound
+	}
+	copied := *order
+	return &copied, nil
+}
+
+// GetOrderByClientID implements exchanges.ClientOrderLookup
+func (e *MatchingExchange) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*exchanges.Order, error) {
+	e.mu.Lock()
+	id, exists := e.byClientID[clientOrderID]
+	e.mu.Unlock()
+	if !exists {
+		return nil, exchanges.ErrOrderNotFound
+	}
+	return e.GetOrder(ctx, id)
+}
+
+func (e *MatchingExchange) GetOpenOrders(ctx context.Context, symbol string) ([]exchanges.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var orders []exchanges.Order
+	for _, order := range e.orders {
+		if order.Status != exchanges.OrderStatusOpen && order.Status != exchanges.OrderStatusPartially {
+			continue
+		}
+		if symbol == "" || order.Symbol == symbol {
+			orders = append(orders, *order)
+		}
+	}
+	return orders, nil
+}
+
+func (e *MatchingExchange) GetTicker(ctx context.Context, symbol string) (*exchanges.Ticker, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return &exchanges.Ticker{
+		Symbol:    symbol,
+		Bid:       e.price,
+		Ask:       e.price,
+		Last:      e.price,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+func (e *MatchingExchange) GetPositions(ctx context.Context) ([]exchanges.Position, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var positions []exchanges.Position
+	for symbol, net := range e.positions {
+		if net.IsZero() {
+			continue
+		}
+		side := exchanges.OrderSideBuy
+		if net.IsNegative() {
+			side = exchanges.OrderSideSell
+		}
+		positions = append(positions, exchanges.Position{
+			Symbol:    symbol,
+			Side:      side,
+			Size:      net.Abs(),
+			MarkPrice: e.price,
+		})
+	}
+	return positions, nil
+}
+
+func (e *MatchingExchange) GetPosition(ctx context.Context, symbol string) (*exchanges.Position, error) {
+	positions, _ := e.GetPositions(ctx)
+	for _, position := range positions {
+		if position.Symbol == symbol {
+			return &position, nil
+		}
+	}
+	return nil, exchanges.ErrPositionNotFound
+}
@@ -313,6 +313,31 @@ func TestRenderPositionSummary(t *testing.T) {
 	}
 }
 
+func TestRenderProgressBar(t *testing.T) {
+	tests := []struct {
+		fraction float64
+		filled   int
+		percent  string
+	}{
+		{0, 0, "  0%"},
+		{0.5, 5, " 50%"},
+		{1.2, 10, "120%"}, // Over the limit: the bar is full, the percentage is not capped
+	}
+
+	for _, tt := range tests {
+		result := RenderProgressBar(tt.fraction, 10)
+		if got := strings.Count(result, "█"); got != tt.filled {
+			t.Errorf("%.1f: expected %d filled cells, got %d", tt.fraction, tt.filled, got)
+		}
+		if got := strings.Count(result, "░"); got != 10-tt.filled {
+			t.Errorf("%.1f: expected %d empty cells, got %d", tt.fraction, 10-tt.filled, got)
+		}
+		if !strings.HasSuffix(result, tt.percent) {
+			t.Errorf("%.1f: expected the bar to end with %q, got %q", tt.fraction, tt.percent, result)
+		}
+	}
+}
+
 func TestRenderOrderBook(t *testing.T) {
 	tests := []struct {
 		name        string
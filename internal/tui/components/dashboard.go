@@ -2,6 +2,7 @@ package components
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -127,3 +128,23 @@ func RenderRiskCard(canTrade bool, reason string, consecutiveLosses int, tradesE
 
 	return boxStyle.Render(content.String())
 }
+
+// RenderProgressBar renders fraction, from 0 to 1, as a bar of width cells
+// followed by its percentage, colored from green to red as it fills up
+func RenderProgressBar(fraction float64, width int) string {
+	if width < 1 {
+		width = 1
+	}
+	filled := int(math.Round(math.Max(0, math.Min(1, fraction)) * float64(width)))
+
+	color := successColor
+	switch {
+	case fraction >= 0.9:
+		color = errorColor
+	case fraction >= 0.6:
+		color = warningColor
+	}
+	bar := lipgloss.NewStyle().Foreground(color).Render(strings.Repeat("█", filled)) +
+		lipgloss.NewStyle().Foreground(mutedColor).Render(strings.Repeat("░", width-filled))
+	return fmt.Sprintf("%s %3.0f%%", bar, fraction*100)
+}
@@ -107,6 +107,9 @@ func RenderPositionDetail(pos *order.ManagedPosition) string {
 	// Size and leverage
 	content.WriteString(fmt.Sprintf("Amount:        %s\n", pos.Amount.StringFixed(4)))
 	content.WriteString(fmt.Sprintf("Leverage:      %sx\n", pos.Leverage.StringFixed(0)))
+	if pos.LiquidationPrice.IsPositive() {
+		content.WriteString(fmt.Sprintf("Liquidation:   $%s\n", pos.LiquidationPrice.StringFixed(2)))
+	}
 	content.WriteString("\n")
 
 	// PnL
@@ -5,9 +5,17 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/guyghost/constantine/internal/accounting"
+	"github.com/guyghost/constantine/internal/blocklist"
+	"github.com/guyghost/constantine/internal/deadman"
+	"github.com/guyghost/constantine/internal/degradation"
+	"github.com/guyghost/constantine/internal/equity"
 	"github.com/guyghost/constantine/internal/exchanges"
+	"github.com/guyghost/constantine/internal/execution"
+	"github.com/guyghost/constantine/internal/halt"
 	"github.com/guyghost/constantine/internal/order"
 	"github.com/guyghost/constantine/internal/risk"
+	"github.com/guyghost/constantine/internal/rotation"
 	"github.com/guyghost/constantine/internal/strategy"
 )
 
@@ -19,6 +27,16 @@ type Model struct {
 	orderManager         *order.Manager
 	riskManager          *risk.Manager
 	integratedEngine     *strategy.IntegratedStrategyEngine
+	ledger               *accounting.Ledger
+	confirmations        *execution.ConfirmationQueue
+	halts                *halt.Registry
+	blocklist            *blocklist.List
+	rotation             *rotation.Rotator
+	equity               *equity.Store
+	degradation          *degradation.Monitor
+	deadman              *deadman.Switch
+	observeOnly          func() bool
+	flatBy               func(symbol string) (time.Time, bool)
 	running              bool
 
 	// UI state
@@ -56,6 +74,7 @@ const (
 	ViewExchanges
 	ViewSettings
 	ViewSymbols
+	ViewRisk
 )
 
 // NewModel creates a new TUI model
@@ -84,6 +103,64 @@ func NewModel(
 	}
 }
 
+// SetLedger shows the PnL attribution of ledger on the dashboard
+func (m *Model) SetLedger(ledger *accounting.Ledger) {
+	m.ledger = ledger
+}
+
+// SetConfirmations shows the entries awaiting approval in confirmation mode
+// and lets them be approved or rejected
+func (m *Model) SetConfirmations(confirmations *execution.ConfirmationQueue) {
+	m.confirmations = confirmations
+}
+
+// SetHalts shows the halted symbols and lets trading in the configured
+// symbols be halted and resumed
+func (m *Model) SetHalts(halts *halt.Registry) {
+	m.halts = halts
+}
+
+// SetBlocklist shows the markets entries are refused on
+func (m *Model) SetBlocklist(list *blocklist.List) {
+	m.blocklist = list
+}
+
+// SetRotation shows the dropped symbols waiting for their positions to be
+// closed and the latest rotation events
+func (m *Model) SetRotation(rotator *rotation.Rotator) {
+	m.rotation = rotator
+}
+
+// SetEquityHistory shows the daily, weekly and monthly PnL of the account
+// recorded in the equity history
+func (m *Model) SetEquityHistory(store *equity.Store) {
+	m.equity = store
+}
+
+// SetDegradation shows the strategies and symbols throttled or disabled for
+// performing below their backtest expectations
+func (m *Model) SetDegradation(monitor *degradation.Monitor) {
+	m.degradation = monitor
+}
+
+// SetDeadman shows the time left before the deadman switch trips, every key
+// pressed counting as an operator heartbeat
+func (m *Model) SetDeadman(deadmanSwitch *deadman.Switch) {
+	m.deadman = deadmanSwitch
+}
+
+// SetObserveOnly flags the header while observing reports that another
+// instance trades the account
+func (m *Model) SetObserveOnly(observing func() bool) {
+	m.observeOnly = observing
+}
+
+// SetFlatBy shows in the positions view when flatBy reports each position is
+// closed ahead of low-liquidity hours
+func (m *Model) SetFlatBy(flatBy func(symbol string) (time.Time, bool)) {
+	m.flatBy = flatBy
+}
+
 // Init initializes the TUI
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/guyghost/constantine/internal/halt"
 )
 
 // Update handles messages and updates the model
@@ -67,6 +68,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKeyPress handles keyboard input
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.deadman != nil {
+		m.deadman.Heartbeat("tui")
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "q":
 		// Quit the application
@@ -102,6 +107,11 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.SetActiveView(ViewSettings)
 		return m, nil
 
+	case "7":
+		// Switch to risk view
+		m.SetActiveView(ViewRisk)
+		return m, nil
+
 	case "s":
 		// Start/stop the bot
 		if m.IsRunning() {
@@ -120,6 +130,49 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case "y", "n":
+		// Approve or reject the oldest entry awaiting approval
+		if m.confirmations == nil {
+			return m, nil
+		}
+		pending := m.confirmations.Pending()
+		if len(pending) == 0 {
+			return m, nil
+		}
+		proposal := pending[0]
+		decide, verb := m.confirmations.Approve, "approved"
+		if msg.String() == "n" {
+			decide, verb = m.confirmations.Reject, "rejected"
+		}
+		if err := decide(proposal.ID); err != nil {
+			m.SetError(err)
+			return m, nil
+		}
+		m.AddMessage(fmt.Sprintf("Entry %s %s %s", verb, proposal.Side, proposal.Symbol))
+		return m, nil
+
+	case "h":
+		// Lift the manual halts, or halt the configured symbols if none
+		if m.halts == nil {
+			return m, nil
+		}
+		resumed := 0
+		for _, h := range m.halts.Halts() {
+			if h.Source == halt.SourceManual {
+				m.halts.Resume(h.Symbol, halt.SourceManual)
+				resumed++
+			}
+		}
+		if resumed > 0 {
+			m.AddMessage(fmt.Sprintf("Trading resumed on %d symbols", resumed))
+			return m, nil
+		}
+		for _, symbol := range m.tradingSymbols {
+			m.halts.Halt(symbol, halt.SourceManual, "halted from the TUI")
+		}
+		m.AddMessage("Trading halted: no new entries")
+		return m, nil
+
 	case "c":
 		// Clear error
 		m.ClearError()
@@ -168,6 +221,9 @@ func (m Model) fetchData() tea.Cmd {
 		if m.integratedEngine != nil {
 			selectedSymbols := m.integratedEngine.GetSelectedSymbols()
 			m.UpdateSelectedSymbols(selectedSymbols)
+			for symbol, weights := range m.integratedEngine.GetDynamicWeights() {
+				m.UpdateDynamicWeights(symbol, weights)
+			}
 		}
 
 		return nil
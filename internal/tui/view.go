@@ -2,12 +2,17 @@ package tui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/guyghost/constantine/internal/accounting"
+	"github.com/guyghost/constantine/internal/degradation"
+	"github.com/guyghost/constantine/internal/equity"
 	"github.com/guyghost/constantine/internal/exchanges"
 	"github.com/guyghost/constantine/internal/strategy"
+	"github.com/guyghost/constantine/internal/tui/components"
 	"github.com/shopspring/decimal"
 )
 
@@ -73,6 +78,8 @@ func (m Model) View() string {
 		content = m.renderSettings()
 	case ViewSymbols:
 		content = m.renderSymbols()
+	case ViewRisk:
+		content = m.renderRisk()
 	}
 
 	// Render header
@@ -121,21 +128,36 @@ func (m Model) renderHeader() string {
 		engineText = successStyle.Render("Engine: Active")
 	}
 
+	parts := []string{title, "  ", statusText, "  ", symbolsText, "  ", engineText}
+	if m.observeOnly != nil && m.observeOnly() {
+		parts = append(parts, "  ", errorStyle.Render("OBSERVE-ONLY: another instance trades"))
+	}
+	if deadmanText := m.renderDeadman(); deadmanText != "" {
+		parts = append(parts, "  ", deadmanText)
+	}
 	return lipgloss.JoinVertical(
 		lipgloss.Left,
-		lipgloss.JoinHorizontal(
-			lipgloss.Top,
-			title,
-			"  ",
-			statusText,
-			"  ",
-			symbolsText,
-			"  ",
-			engineText,
-		),
+		lipgloss.JoinHorizontal(lipgloss.Top, parts...),
 	)
 }
 
+// renderDeadman renders the time left before the deadman switch trips, or
+// nothing without a switch
+func (m Model) renderDeadman() string {
+	if m.deadman == nil {
+		return ""
+	}
+	status := m.deadman.Status()
+	if status.Tripped {
+		return errorStyle.Render("Deadman: TRIPPED, press a key to re-arm")
+	}
+	left := time.Until(status.Deadline).Round(time.Minute)
+	if left < time.Hour {
+		return errorStyle.Render(fmt.Sprintf("Deadman: %s left", left))
+	}
+	return mutedStyle.Render(fmt.Sprintf("Deadman: %s left", left))
+}
+
 // renderStatusBar renders the bottom status bar
 func (m Model) renderStatusBar() string {
 	timestamp := time.Now().Format("15:04:05")
@@ -152,12 +174,18 @@ func (m Model) renderStatusBar() string {
 // renderHelp renders the help text
 func (m Model) renderHelp() string {
 	helps := []string{
-		"[1-6] Switch view",
+		"[1-7] Switch view",
 		"[s] Start/Stop",
 		"[r] Refresh",
 		"[c] Clear error",
 		"[q] Quit",
 	}
+	if m.confirmations != nil {
+		helps = append(helps[:len(helps)-1], "[y/n] Approve/Reject entry", helps[len(helps)-1])
+	}
+	if m.halts != nil {
+		helps = append(helps[:len(helps)-1], "[h] Halt/Resume", helps[len(helps)-1])
+	}
 	return helpStyle.Render(strings.Join(helps, " • "))
 }
 
@@ -178,8 +206,107 @@ func (m Model) renderDashboard() string {
 	// Arrange in grid - 2x3 layout
 	topRow := lipgloss.JoinHorizontal(lipgloss.Top, summary, "  ", selectedSymbolsBox)
 	bottomRow := lipgloss.JoinHorizontal(lipgloss.Top, signalBox, "  ", messagesBox)
+	if proposals := m.renderProposals(); proposals != "" {
+		topRow = lipgloss.JoinVertical(lipgloss.Left, proposals, "", topRow)
+	}
+	if halts := m.renderHalts(); halts != "" {
+		topRow = lipgloss.JoinVertical(lipgloss.Left, halts, "", topRow)
+	}
+	if blocked := m.renderBlocklist(); blocked != "" {
+		topRow = lipgloss.JoinVertical(lipgloss.Left, blocked, "", topRow)
+	}
+	if rotation := m.renderRotation(); rotation != "" {
+		topRow = lipgloss.JoinVertical(lipgloss.Left, rotation, "", topRow)
+	}
+	if degraded := m.renderDegradation(); degraded != "" {
+		topRow = lipgloss.JoinVertical(lipgloss.Left, degraded, "", topRow)
+	}
+
+	rows := []string{topRow, "", bottomRow}
+	var pnlRow []string
+	if m.ledger != nil {
+		// PnL attribution
+		snapshot := m.ledger.Snapshot()
+		pnlRow = append(pnlRow,
+			renderAttribution("P&L by Strategy", snapshot.Strategies), "  ",
+			renderAttribution("P&L by Symbol", snapshot.Symbols))
+	}
+	if m.equity != nil {
+		if len(pnlRow) > 0 {
+			pnlRow = append(pnlRow, "  ")
+		}
+		pnlRow = append(pnlRow, m.renderPnLHistory())
+	}
+	if len(pnlRow) > 0 {
+		rows = append(rows, "", lipgloss.JoinHorizontal(lipgloss.Top, pnlRow...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderPnLHistory renders the PnL of the last days and of the current week
+// and month from the equity history
+func (m Model) renderPnLHistory() string {
+	var content strings.Builder
+
+	content.WriteString(headerStyle.Render("P&L History") + "\n\n")
+
+	daily := m.equity.Rollups(equity.Total, equity.Daily)
+	if len(daily) == 0 {
+		content.WriteString(mutedStyle.Render("No equity snapshots"))
+		return boxStyle.Render(content.String())
+	}
+
+	line := func(label string, rollup equity.Rollup) {
+		pnlStyle := successStyle
+		if rollup.PnL.IsNegative() {
+			pnlStyle = errorStyle
+		}
+		content.WriteString(fmt.Sprintf("%-10s %12s %7.2f%%\n",
+			label, pnlStyle.Render("$"+rollup.PnL.StringFixed(2)), rollup.ReturnPercent))
+	}
+	if len(daily) > 5 {
+		daily = daily[len(daily)-5:]
+	}
+	for _, rollup := range daily {
+		line(rollup.Start.Format("Mon 02/01"), rollup)
+	}
+	content.WriteString("\n")
+	if weekly := m.equity.Rollups(equity.Total, equity.Weekly); len(weekly) > 0 {
+		line("This week", weekly[len(weekly)-1])
+	}
+	if monthly := m.equity.Rollups(equity.Total, equity.Monthly); len(monthly) > 0 {
+		line("This month", monthly[len(monthly)-1])
+	}
+
+	return boxStyle.Render(strings.TrimRight(content.String(), "\n"))
+}
+
+// renderAttribution renders realized performance per strategy or symbol
+func renderAttribution(title string, attributions []accounting.Attribution) string {
+	var content strings.Builder
 
-	return lipgloss.JoinVertical(lipgloss.Left, topRow, "", bottomRow)
+	content.WriteString(headerStyle.Render(title) + "\n\n")
+
+	if len(attributions) == 0 {
+		content.WriteString(mutedStyle.Render("No closed trades"))
+		return boxStyle.Render(content.String())
+	}
+
+	content.WriteString(fmt.Sprintf("%-12s %6s %7s %12s\n", "Name", "Trades", "Win %", "P&L"))
+	for i, a := range attributions {
+		if i == 8 {
+			content.WriteString(mutedStyle.Render(fmt.Sprintf("... %d more", len(attributions)-i)))
+			break
+		}
+		pnlStyle := successStyle
+		if a.PnL.IsNegative() {
+			pnlStyle = errorStyle
+		}
+		content.WriteString(fmt.Sprintf("%-12s %6d %6.1f%% %12s\n",
+			a.Key, a.Trades, a.WinRate(), pnlStyle.Render("$"+a.PnL.StringFixed(2))))
+	}
+
+	return boxStyle.Render(content.String())
 }
 
 // renderSummary renders the summary box
@@ -191,9 +318,18 @@ func (m Model) renderSummary() string {
 	// Get aggregated data
 	data := m.aggregator.GetAggregatedData()
 
+	// Totals are in the reporting currency
+	unit := "$"
+	if data.Currency != "" && data.Currency != "USD" {
+		unit = data.Currency + " "
+	}
+
 	// Total Balance
 	totalBalance := data.TotalBalance.StringFixed(2)
-	content.WriteString(fmt.Sprintf("Total Balance: %s\n", successStyle.Render("$"+totalBalance)))
+	content.WriteString(fmt.Sprintf("Total Balance: %s\n", successStyle.Render(unit+totalBalance)))
+	if len(data.Unconverted) > 0 {
+		content.WriteString(errorStyle.Render(fmt.Sprintf("  Excludes %s: no conversion rate", strings.Join(data.Unconverted, ", "))) + "\n")
+	}
 
 	// Total PnL
 	totalPnL := data.TotalPnL.StringFixed(2)
@@ -201,7 +337,14 @@ func (m Model) renderSummary() string {
 	if data.TotalPnL.IsNegative() {
 		pnlStyle = errorStyle
 	}
-	content.WriteString(fmt.Sprintf("Total P&L:     %s\n", pnlStyle.Render("$"+totalPnL)))
+	content.WriteString(fmt.Sprintf("Total P&L:     %s\n", pnlStyle.Render(unit+totalPnL)))
+
+	// Margin usage and buying power
+	if data.TotalEquity.IsPositive() {
+		marginPct := data.TotalMarginUsed.Div(data.TotalEquity).Mul(decimal.NewFromInt(100))
+		content.WriteString(fmt.Sprintf("Margin Used:   %s%s (%s%%)\n", unit, data.TotalMarginUsed.StringFixed(2), marginPct.StringFixed(0)))
+		content.WriteString(fmt.Sprintf("Free Margin:   %s%s\n", unit, data.TotalFreeCollateral.StringFixed(2)))
+	}
 
 	// Exchange connections
 	connectedCount := 0
@@ -267,6 +410,15 @@ func (m Model) renderCurrentSignal() string {
 				content.WriteString(fmt.Sprintf("  Price: $%s\n", signal.Price.StringFixed(2)))
 				content.WriteString(fmt.Sprintf("  Strength: %.1f%%\n", signal.Strength*100))
 				content.WriteString(fmt.Sprintf("  Reason: %s\n", signal.Reason))
+				if c := signal.Components; c != nil {
+					content.WriteString(mutedStyle.Render(fmt.Sprintf("  EMA %s/%s  RSI %s  BB %.2f  Vol z %.2f",
+						c.ShortEMA.StringFixed(2), c.LongEMA.StringFixed(2), c.RSI.StringFixed(1), c.BBPosition, c.VolumeZScore)) + "\n")
+					content.WriteString(mutedStyle.Render(fmt.Sprintf("  Weights EMA %.2f  RSI %.2f  Vol %.2f  BB %.2f  OB %.2f",
+						c.Weights.EMA, c.Weights.RSI, c.Weights.Volume, c.Weights.BB, c.Weights.OrderBook)) + "\n")
+					if c.Regime != "" {
+						content.WriteString(mutedStyle.Render(fmt.Sprintf("  Regime %s  ADX %.1f", c.Regime, c.ADX)) + "\n")
+					}
+				}
 				content.WriteString("\n")
 			}
 		}
@@ -275,6 +427,153 @@ func (m Model) renderCurrentSignal() string {
 	return boxStyle.Render(content.String())
 }
 
+// renderProposals renders the entries awaiting approval, or nothing
+// outside confirmation mode or when none is pending
+func (m Model) renderProposals() string {
+	if m.confirmations == nil {
+		return ""
+	}
+	pending := m.confirmations.Pending()
+	if len(pending) == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render("Entries Awaiting Approval") + "\n\n")
+	for i, proposal := range pending {
+		line := fmt.Sprintf("%s %s %s @ %s  SL %s  TP %s  strength %.2f  expires in %s",
+			strings.ToUpper(string(proposal.Side)),
+			proposal.Amount.String(),
+			proposal.Symbol,
+			proposal.Price.StringFixed(2),
+			proposal.StopLoss.StringFixed(2),
+			proposal.TakeProfit.StringFixed(2),
+			proposal.Strength,
+			time.Until(proposal.ExpiresAt).Round(time.Second))
+		if i == 0 {
+			content.WriteString(titleStyle.Render("▶ "+line) + "\n")
+			continue
+		}
+		content.WriteString(mutedStyle.Render("  "+line) + "\n")
+	}
+	content.WriteString("\n" + mutedStyle.Render("[y] approve  [n] reject"))
+
+	return boxStyle.Render(content.String())
+}
+
+// renderHalts renders the halted symbols, or nothing when none is halted
+func (m Model) renderHalts() string {
+	if m.halts == nil {
+		return ""
+	}
+	halts := m.halts.Halts()
+	if len(halts) == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render("Trading Halted") + "\n\n")
+	for _, h := range halts {
+		line := fmt.Sprintf("%-12s %-8s since %s", h.Symbol, h.Source, h.Since.Local().Format("15:04:05"))
+		if h.Reason != "" {
+			line += "  " + h.Reason
+		}
+		content.WriteString(titleStyle.Render(line) + "\n")
+	}
+	content.WriteString("\n" + mutedStyle.Render("[h] resume manual halts"))
+
+	return boxStyle.Render(content.String())
+}
+
+// renderBlocklist renders the blocked markets, or nothing when none is
+// blocked
+func (m Model) renderBlocklist() string {
+	if m.blocklist == nil {
+		return ""
+	}
+	entries := m.blocklist.Entries()
+	if len(entries) == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render("Blocked Markets") + "\n\n")
+	for _, entry := range entries {
+		line := fmt.Sprintf("%-24s since %s", entry, entry.Since.Local().Format("2006-01-02 15:04"))
+		if entry.Reason != "" {
+			line += "  " + entry.Reason
+		}
+		content.WriteString(titleStyle.Render(line) + "\n")
+	}
+
+	return boxStyle.Render(content.String())
+}
+
+// renderRotation renders the symbols leaving trading and the latest rotation
+// events, or nothing when no symbol is rotating
+func (m Model) renderRotation() string {
+	if m.rotation == nil {
+		return ""
+	}
+	transitions := m.rotation.Transitions()
+	if len(transitions) == 0 {
+		return ""
+	}
+
+	var content strings.Builder
+	content.WriteString(headerStyle.Render("Symbol Rotation") + "\n\n")
+	for _, t := range transitions {
+		line := fmt.Sprintf("%-12s %-6s %s left", t.Symbol, t.Policy, t.Amount.String())
+		if !t.Deadline.IsZero() {
+			line += "  closed by " + t.Deadline.Local().Format("15:04:05")
+		}
+		content.WriteString(titleStyle.Render(line) + "\n")
+	}
+
+	events := m.rotation.Events()
+	if len(events) > 3 {
+		events = events[len(events)-3:]
+	}
+	content.WriteString("\n")
+	for _, event := range events {
+		content.WriteString(mutedStyle.Render(fmt.Sprintf("%s %-10s %-8s %s",
+			event.Time.Local().Format("15:04:05"), event.Symbol, event.Kind, event.Detail)) + "\n")
+	}
+
+	return boxStyle.Render(strings.TrimRight(content.String(), "\n"))
+}
+
+// renderDegradation renders the strategies and symbols throttled or disabled
+// for performing below expectations, or nothing when all are healthy
+func (m Model) renderDegradation() string {
+	if m.degradation == nil {
+		return ""
+	}
+
+	var content strings.Builder
+	for _, status := range m.degradation.Statuses() {
+		if status.State == degradation.StateHealthy {
+			continue
+		}
+		scope := status.Strategy
+		if status.Symbol != "" {
+			scope += " " + status.Symbol
+		}
+		line := fmt.Sprintf("%-22s %-9s since %s  %s",
+			scope, status.State, status.Since.Local().Format("15:04:05"), status.Reason)
+		if status.State == degradation.StateDisabled {
+			content.WriteString(errorStyle.Render(line) + "\n")
+		} else {
+			content.WriteString(titleStyle.Render(line) + "\n")
+		}
+	}
+	if content.Len() == 0 {
+		return ""
+	}
+
+	return boxStyle.Render(headerStyle.Render("Strategy Degradation") + "\n\n" + strings.TrimRight(content.String(), "\n"))
+}
+
 // renderMessages renders recent messages
 func (m Model) renderMessages() string {
 	var content strings.Builder
@@ -343,8 +642,8 @@ func (m Model) renderSelectedSymbols() string {
 
 			// Show dynamic weights for this symbol if available
 			if weights, ok := m.GetDynamicWeights(rankedSymbol.Symbol); ok {
-				content.WriteString(fmt.Sprintf("  Weights - EMA: %.0f%% RSI: %.0f%% Vol: %.0f%% BB: %.0f%%\n",
-					weights.EMA*100, weights.RSI*100, weights.Volume*100, weights.BB*100))
+				content.WriteString(fmt.Sprintf("  Weights - EMA: %.0f%% RSI: %.0f%% Vol: %.0f%% BB: %.0f%% OB: %.0f%%\n",
+					weights.EMA*100, weights.RSI*100, weights.Volume*100, weights.BB*100, weights.OrderBook*100))
 			}
 
 			content.WriteString("\n")
@@ -486,6 +785,10 @@ func (m Model) renderPositions() string {
 			content.WriteString(fmt.Sprintf("  Entry:  $%s\n", pos.EntryPrice.StringFixed(2)))
 			content.WriteString(fmt.Sprintf("  Size:   %s\n", pos.Size.StringFixed(4)))
 			content.WriteString(fmt.Sprintf("  PnL:    $%s\n", pos.UnrealizedPnL.StringFixed(2)))
+			symbol, _, _ := strings.Cut(pos.Symbol, " (")
+			if flatBy := m.renderFlatBy(symbol); flatBy != "" {
+				content.WriteString("  Flat:   " + flatBy + "\n")
+			}
 			content.WriteString("\n")
 		}
 	}
@@ -493,6 +796,27 @@ func (m Model) renderPositions() string {
 	return boxStyle.Render(content.String())
 }
 
+// renderFlatBy renders when the position on symbol is closed by the trading
+// schedule, or nothing when it is not
+func (m Model) renderFlatBy(symbol string) string {
+	if m.flatBy == nil {
+		return ""
+	}
+	at, ok := m.flatBy(symbol)
+	if !ok {
+		return ""
+	}
+	left := time.Until(at)
+	if left <= 0 {
+		return errorStyle.Render(fmt.Sprintf("by %s UTC, flattening", at.UTC().Format("15:04")))
+	}
+	text := fmt.Sprintf("by %s UTC (in %s)", at.UTC().Format("15:04"), left.Round(time.Minute))
+	if left < time.Hour {
+		return errorStyle.Render(text)
+	}
+	return mutedStyle.Render(text)
+}
+
 // renderOrders renders the orders view
 func (m Model) renderOrders() string {
 	var content strings.Builder
@@ -553,6 +877,13 @@ func (m Model) renderExchanges() string {
 			}
 		}
 
+		// Show margin usage
+		if margin := exchangeData.Margin; margin.Equity.IsPositive() {
+			content.WriteString(fmt.Sprintf("  Margin: $%s used, $%s free, %sx\n",
+				margin.MarginUsed.StringFixed(2), margin.FreeCollateral.StringFixed(2), margin.Leverage.StringFixed(2)))
+			content.WriteString(fmt.Sprintf("  Withdrawable: $%s\n", margin.Withdrawable.StringFixed(2)))
+		}
+
 		// Show positions count
 		posCount := len(exchangeData.Positions)
 		if posCount > 0 {
@@ -571,6 +902,67 @@ func (m Model) renderExchanges() string {
 	return boxStyle.Render(content.String())
 }
 
+// renderRisk renders the headroom left under the risk limits: the loss
+// budgets, the exposure of each symbol against MaxExposurePerSymbol and the
+// margin used on each exchange
+func (m Model) renderRisk() string {
+	const barWidth = 30
+	var content strings.Builder
+
+	content.WriteString(headerStyle.Render("Risk Utilization") + "\n\n")
+	if m.riskManager == nil {
+		content.WriteString(mutedStyle.Render("Risk manager not available"))
+		return boxStyle.Render(content.String())
+	}
+
+	content.WriteString(titleStyle.Render("Loss Budget") + "\n")
+	for _, budget := range m.riskManager.LossBudgets() {
+		if !budget.Limit.IsPositive() {
+			continue
+		}
+		left := decimal.Max(budget.Limit.Sub(budget.Used), decimal.Zero)
+		content.WriteString(fmt.Sprintf("  %-10s %s  $%s of $%s left\n",
+			budget.Name, components.RenderProgressBar(budget.Fraction(), barWidth),
+			left.StringFixed(2), budget.Limit.StringFixed(2)))
+	}
+
+	content.WriteString("\n" + titleStyle.Render("Exposure per Symbol") + "\n")
+	exposures := m.riskManager.SymbolExposures(m.positions)
+	if len(exposures) == 0 {
+		content.WriteString(mutedStyle.Render("  No open positions") + "\n")
+	}
+	for _, exposure := range exposures {
+		content.WriteString(fmt.Sprintf("  %-10s %s  $%s / $%s\n",
+			exposure.Name, components.RenderProgressBar(exposure.Fraction(), barWidth),
+			exposure.Used.StringFixed(2), exposure.Limit.StringFixed(2)))
+	}
+
+	content.WriteString("\n" + titleStyle.Render("Margin per Exchange") + "\n")
+	var exchangeNames []string
+	var data *exchanges.AggregatedData
+	if m.aggregator != nil {
+		data = m.aggregator.GetAggregatedData()
+		for name, exchangeData := range data.Exchanges {
+			if exchangeData.Margin.Equity.IsPositive() {
+				exchangeNames = append(exchangeNames, name)
+			}
+		}
+		sort.Strings(exchangeNames)
+	}
+	if len(exchangeNames) == 0 {
+		content.WriteString(mutedStyle.Render("  No margin reported") + "\n")
+	}
+	for _, name := range exchangeNames {
+		margin := data.Exchanges[name].Margin
+		used := margin.MarginUsed.Div(margin.Equity).InexactFloat64()
+		content.WriteString(fmt.Sprintf("  %-10s %s  $%s of $%s equity\n",
+			name, components.RenderProgressBar(used, barWidth),
+			margin.MarginUsed.StringFixed(2), margin.Equity.StringFixed(2)))
+	}
+
+	return boxStyle.Render(content.String())
+}
+
 // renderSymbols renders the symbols view with detailed information
 func (m Model) renderSymbols() string {
 	var content strings.Builder
@@ -611,6 +1003,7 @@ func (m Model) renderSymbols() string {
 				content.WriteString(fmt.Sprintf("    RSI:            %.1f%%\n", weights.RSI*100))
 				content.WriteString(fmt.Sprintf("    Volume:         %.1f%%\n", weights.Volume*100))
 				content.WriteString(fmt.Sprintf("    Bollinger Bands %.1f%%\n", weights.BB*100))
+				content.WriteString(fmt.Sprintf("    Order Book:     %.1f%%\n", weights.OrderBook*100))
 			}
 
 			// Current signal if available
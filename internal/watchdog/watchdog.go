@@ -0,0 +1,171 @@
+package watchdog
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/guyghost/constantine/internal/logger"
+	"github.com/guyghost/constantine/internal/telemetry"
+)
+
+// restartTimeout bounds a single restart attempt
+const restartTimeout = 30 * time.Second
+
+// Component is a long-running part of the bot whose liveness is supervised
+type Component struct {
+	Name string
+	// Timeout is how long the component may stay silent before it is
+	// considered stuck
+	Timeout time.Duration
+	// LastBeat returns when the component last made progress, zero if it
+	// has not yet
+	LastBeat func() time.Time
+	// Active reports whether the component is expected to make progress,
+	// e.g. false while it is deliberately stopped. Nil means always.
+	Active func() bool
+	// Restart restarts or resubscribes the component. Its context bounds the
+	// restart only and must not become the restarted component's lifetime.
+	Restart func(ctx context.Context) error
+}
+
+// Intervention is a restart of a stuck component
+type Intervention struct {
+	Component string
+	Silence   time.Duration // How long the component had been silent
+	Err       error         // Why the restart failed, nil on success
+	At        time.Time
+}
+
+type supervised struct {
+	component    Component
+	registeredAt time.Time
+	restartedAt  time.Time
+}
+
+// Watchdog restarts components that stopped making progress. A component is
+// stuck once it has been silent for longer than its timeout, counting from
+// its last beat, its last restart or its registration, whichever is latest,
+// so a component is given a full timeout to recover after each restart.
+type Watchdog struct {
+	mu             sync.Mutex
+	components     map[string]*supervised
+	onIntervention func(Intervention)
+	now            func() time.Time
+}
+
+// New creates a watchdog with no components
+func New() *Watchdog {
+	return &Watchdog{
+		components: make(map[string]*supervised),
+		now:        time.Now,
+	}
+}
+
+// Register supervises component, replacing a component of the same name
+func (w *Watchdog) Register(component Component) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.components[component.Name] = &supervised{component: component, registeredAt: w.now()}
+}
+
+// Unregister stops supervising the component called name
+func (w *Watchdog) Unregister(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.components, name)
+}
+
+// SetInterventionCallback sets the callback notified of every restart
+func (w *Watchdog) SetInterventionCallback(callback func(Intervention)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onIntervention = callback
+}
+
+// Check restarts the components that are stuck and returns the
+// interventions made, in component name order
+func (w *Watchdog) Check(ctx context.Context) []Intervention {
+	type restart struct {
+		target       *supervised
+		intervention Intervention
+	}
+
+	w.mu.Lock()
+	now := w.now()
+	var restarts []restart
+	for _, s := range w.components {
+		if !s.active() {
+			continue
+		}
+		if silence := s.silence(now); s.component.Timeout > 0 && silence > s.component.Timeout {
+			restarts = append(restarts, restart{
+				target:       s,
+				intervention: Intervention{Component: s.component.Name, Silence: silence, At: now},
+			})
+		}
+	}
+	callback := w.onIntervention
+	w.mu.Unlock()
+
+	sort.Slice(restarts, func(i, j int) bool {
+		return restarts[i].intervention.Component < restarts[j].intervention.Component
+	})
+
+	log := logger.Component("watchdog")
+	interventions := make([]Intervention, 0, len(restarts))
+	for _, r := range restarts {
+		s, intervention := r.target, r.intervention
+		log.Warn("component stuck, restarting",
+			"component", intervention.Component,
+			"silence", intervention.Silence.Round(time.Second),
+			"timeout", s.component.Timeout)
+
+		restartCtx, cancel := context.WithTimeout(ctx, restartTimeout)
+		intervention.Err = s.component.Restart(restartCtx)
+		cancel()
+
+		w.mu.Lock()
+		s.restartedAt = w.now()
+		w.mu.Unlock()
+
+		telemetry.RecordWatchdogRestart(intervention.Component, intervention.Err == nil)
+		if callback != nil {
+			callback(intervention)
+		}
+		interventions = append(interventions, intervention)
+	}
+	return interventions
+}
+
+// Run checks the components every interval until ctx is canceled
+func (w *Watchdog) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check(ctx)
+		}
+	}
+}
+
+func (s *supervised) active() bool {
+	return s.component.Active == nil || s.component.Active()
+}
+
+// silence returns how long the component has been silent at now
+func (s *supervised) silence(now time.Time) time.Duration {
+	since := s.registeredAt
+	if s.restartedAt.After(since) {
+		since = s.restartedAt
+	}
+	if beat := s.component.LastBeat(); beat.After(since) {
+		since = beat
+	}
+	return now.Sub(since)
+}
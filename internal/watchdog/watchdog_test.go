@@ -0,0 +1,105 @@
+package watchdog
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheck_RestartsStuckComponents(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	w := New()
+	w.now = func() time.Time { return now }
+
+	var lastBeat time.Time
+	restarts := 0
+	w.Register(Component{
+		Name:     "stream",
+		Timeout:  time.Minute,
+		LastBeat: func() time.Time { return lastBeat },
+		Restart: func(ctx context.Context) error {
+			restarts++
+			return nil
+		},
+	})
+	w.Register(Component{
+		Name:     "orders",
+		Timeout:  time.Minute,
+		LastBeat: func() time.Time { return now },
+		Restart: func(ctx context.Context) error {
+			return errors.New("should not restart")
+		},
+	})
+
+	var notified []Intervention
+	w.SetInterventionCallback(func(i Intervention) { notified = append(notified, i) })
+
+	// Silence counts from registration until the first beat
+	now = start.Add(30 * time.Second)
+	if got := w.Check(context.Background()); len(got) != 0 {
+		t.Fatalf("expected no intervention within the timeout, got %v", got)
+	}
+
+	now = start.Add(90 * time.Second)
+	got := w.Check(context.Background())
+	if len(got) != 1 || got[0].Component != "stream" || got[0].Err != nil {
+		t.Fatalf("expected the stream to be restarted, got %v", got)
+	}
+	if got[0].Silence != 90*time.Second {
+		t.Errorf("expected silence 1m30s, got %v", got[0].Silence)
+	}
+	if restarts != 1 || len(notified) != 1 {
+		t.Fatalf("expected 1 restart and 1 notification, got %d and %d", restarts, len(notified))
+	}
+
+	// A restarted component gets a full timeout to recover
+	now = start.Add(120 * time.Second)
+	if got := w.Check(context.Background()); len(got) != 0 {
+		t.Fatalf("expected no intervention right after a restart, got %v", got)
+	}
+
+	// Beats keep the component alive
+	lastBeat = start.Add(140 * time.Second)
+	now = start.Add(190 * time.Second)
+	if got := w.Check(context.Background()); len(got) != 0 {
+		t.Fatalf("expected no intervention after a beat, got %v", got)
+	}
+
+	now = start.Add(201 * time.Second)
+	if got := w.Check(context.Background()); len(got) != 1 {
+		t.Fatalf("expected the silent stream to be restarted again, got %v", got)
+	}
+	if restarts != 2 {
+		t.Errorf("expected 2 restarts, got %d", restarts)
+	}
+}
+
+func TestCheck_ReportsFailedRestarts(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	w := New()
+	w.now = func() time.Time { return now }
+
+	w.Register(Component{
+		Name:     "strategy:BTC-USD",
+		Timeout:  time.Minute,
+		LastBeat: func() time.Time { return time.Time{} },
+		Restart: func(ctx context.Context) error {
+			return errors.New("subscription refused")
+		},
+	})
+
+	now = start.Add(2 * time.Minute)
+	got := w.Check(context.Background())
+	if len(got) != 1 || got[0].Err == nil {
+		t.Fatalf("expected a failed restart, got %v", got)
+	}
+
+	w.Unregister("strategy:BTC-USD")
+	now = start.Add(10 * time.Minute)
+	if got := w.Check(context.Background()); len(got) != 0 {
+		t.Fatalf("expected no intervention after unregistering, got %v", got)
+	}
+}